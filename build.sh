@@ -37,6 +37,8 @@ PATH="$gopath/bin:$PATH" go generate -v ./lib/...
 # make the array a json document and store in the DB row as one item
 # TODO: https://github.com/deepmap/oapi-codegen/issues/859
 sed -i.bak 's/^type LabelDefinitions = /type LabelDefinitions /' lib/openapi/types/types.gen.go
+sed -i.bak 's/^type ApplicationDependencies = /type ApplicationDependencies /' lib/openapi/types/types.gen.go
+sed -i.bak 's/^type UpgradeNodes = /type UpgradeNodes /' lib/openapi/types/types.gen.go
 rm -f lib/openapi/types/types.gen.go.bak
 
 # If ONLYGEN is specified - skip the build
@@ -47,6 +49,9 @@ mod_name=$(grep '^module' "${root_dir}/go.mod" | cut -d' ' -f 2)
 git_version="$(git describe --tags --match 'v*')$([ "$(git diff)" = '' ] || echo '-dirty')"
 version_flags="-X '$mod_name/lib/build.Version=${git_version}' -X '$mod_name/lib/build.Time=$(date -u +%y%m%d.%H%M%S)'"
 BINARY_NAME="aquarium-fish-$git_version"
+CTL_BINARY_NAME="fishctl-$git_version"
+# The binaries to build in format "<name prefix>:<cmd dir>"
+targets="$BINARY_NAME:fish $CTL_BINARY_NAME:fishctl"
 
 # Doing check after generation because generated sources requires additional modules
 ./check.sh
@@ -88,7 +93,8 @@ go tool dist list > /tmp/go_tool_dist_list.txt
 
 for GOOS in $os_list; do
     for GOARCH in $arch_list; do
-        name="$BINARY_NAME.${GOOS}_${GOARCH}"
+      for target in $targets; do
+        name="${target%%:*}.${GOOS}_${GOARCH}"
 
         if ! grep -q "^${GOOS}/${GOARCH}$" /tmp/go_tool_dist_list.txt; then
             echo "Skipping: $name as not supported by go"
@@ -97,8 +103,9 @@ for GOOS in $os_list; do
 
         echo "Building: $name ..."
         rm -f "$name" "$name.log" "$name.zip" "$name.tar.xz"
-        GOOS=$GOOS GOARCH=$GOARCH go build -ldflags="-s -w $version_flags" -o "$name" ./cmd/fish > "$name.log" 2>&1 &
+        GOOS=$GOOS GOARCH=$GOARCH go build -ldflags="-s -w $version_flags" -o "$name" "./cmd/${target##*:}" > "$name.log" 2>&1 &
         pwait $MAXJOBS
+      done
     done
 done
 
@@ -108,7 +115,8 @@ wait
 errorcount=0
 for GOOS in $os_list; do
     for GOARCH in $arch_list; do
-        name="$BINARY_NAME.${GOOS}_${GOARCH}"
+      for target in $targets; do
+        name="${target%%:*}.${GOOS}_${GOARCH}"
         # Log file is not here - build was skipped
         [ -f "$name.log" ] || continue
         # Binary is not here - build error happened
@@ -123,6 +131,7 @@ for GOOS in $os_list; do
             cat "$name.log"
         fi
         rm -f "$name.log"
+      done
     done
 done
 
@@ -135,12 +144,14 @@ if [ "x${RELEASE}" != "x" ]; then
     # Pack the artifact archives
     for GOOS in $os_list; do
         for GOARCH in $arch_list; do
-            name="$BINARY_NAME.${GOOS}_${GOARCH}"
+          for target in $targets; do
+            name="${target%%:*}.${GOOS}_${GOARCH}"
             [ -f "$name" ] || continue
 
             echo "Archiving: $(du -h "$name") ..."
             mkdir "$name.dir"
             bin_name='aquarium-fish'
+            [ "${target##*:}" = 'fish' ] || bin_name="${target##*:}"
             [ "$GOOS" != "windows" ] || bin_name="$bin_name.exe"
 
             cp -a "$name" "$name.dir/$bin_name"
@@ -151,6 +162,7 @@ if [ "x${RELEASE}" != "x" ]; then
                 cd .. && rm -rf "$name.dir"
             ) &
             pwait $MAXJOBS
+          done
         done
     done
 
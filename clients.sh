@@ -0,0 +1,48 @@
+#!/bin/sh -e
+# Copyright 2024 Adobe. All rights reserved.
+# This file is licensed to you under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License. You may obtain a copy
+# of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software distributed under
+# the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+# OF ANY KIND, either express or implied. See the License for the specific language
+# governing permissions and limitations under the License.
+
+# Script to generate Python & TypeScript API clients from the OpenAPI spec, the resulting
+# archives in OUT_DIR could be served by the node with `api_clients_directory` config
+# Requires docker to run openapi-generator, the image version could be set by GENERATOR_VERSION
+
+root_dir=$(cd "$(dirname "$0")"; echo "$PWD")
+echo "ROOT DIR: ${root_dir}"
+cd "${root_dir}"
+
+[ "x$OUT_DIR" != 'x' ] || OUT_DIR="${root_dir}/clients"
+[ "x$GENERATOR_VERSION" != 'x' ] || GENERATOR_VERSION=v7.8.0
+
+git_version="$(git describe --tags --match 'v*')$([ "$(git diff)" = '' ] || echo '-dirty')"
+# Package managers are not happy with the git describe suffixes
+pkg_version="$(echo "${git_version#v}" | sed 's/-\([0-9]*\)-g[0-9a-f]*/.post\1/; s/-dirty$//')"
+
+tmp_dir=$(mktemp -d)
+trap 'rm -rf "$tmp_dir"' EXIT
+mkdir -p "$OUT_DIR"
+
+# The generators are named "<name>:<openapi-generator name>:<additional properties>"
+generators="python:python:packageName=aquarium_fish_client,projectName=aquarium-fish-client,packageVersion=${pkg_version}
+typescript:typescript-fetch:npmName=aquarium-fish-client,npmVersion=${pkg_version},supportsES6=true"
+
+for gen in $generators; do
+    name="${gen%%:*}"
+    rest="${gen#*:}"
+    echo "--- GENERATE ${name} CLIENT ${pkg_version} ---"
+    docker run --rm -u "$(id -u):$(id -g)" -v "${root_dir}/docs:/spec:ro" -v "${tmp_dir}:/out" \
+        "openapitools/openapi-generator-cli:${GENERATOR_VERSION}" generate \
+        -i /spec/openapi.yaml -g "${rest%%:*}" -o "/out/${name}" \
+        --additional-properties="${rest#*:}"
+    tar -C "$tmp_dir" -czf "${OUT_DIR}/aquarium-fish-client-${name}-${git_version}.tar.gz" "${name}"
+done
+
+cp docs/openapi.yaml "${OUT_DIR}/aquarium-fish-openapi-${git_version}.yaml"
+echo "--- CLIENTS ARE IN ${OUT_DIR} ---"
+ls -l "$OUT_DIR"
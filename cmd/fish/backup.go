@@ -0,0 +1,106 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm/logger"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// backupCmd manages the node database backups from the node host, the create works while the node
+// is running and the restore requires the node to be stopped
+func backupCmd() *cobra.Command {
+	var cfgPath, dir, nodeAddress string
+	readConfig := func() (*fish.Config, error) {
+		cfg := &fish.Config{}
+		if err := cfg.ReadConfigFile(cfgPath); err != nil {
+			return nil, fmt.Errorf("Fish: Unable to apply config file %s: %v", cfgPath, err)
+		}
+		if dir != "" {
+			cfg.Directory = dir
+		}
+		if nodeAddress != "" {
+			cfg.NodeAddress = nodeAddress
+		}
+		return cfg, nil
+	}
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backup and restore the node database",
+	}
+	cmd.PersistentFlags().StringVarP(&cfgPath, "cfg", "c", "", "yaml configuration file")
+	cmd.PersistentFlags().StringVarP(&dir, "dir", "D", "", "database and other fish files directory")
+	cmd.PersistentFlags().StringVarP(&nodeAddress, "node", "n", "", "node external endpoint to locate the node database")
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Make the consistent snapshot of the node database, the node could be running",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cfg, err := readConfig()
+			if err != nil {
+				return err
+			}
+			backupDir := fish.BackupDirectory(cfg)
+			if err = os.MkdirAll(backupDir, 0o750); err != nil {
+				return log.Errorf("Fish: Unable to create backup directory: %v", err)
+			}
+			path := filepath.Join(backupDir, fish.BackupName(time.Now()))
+			dbPath := filepath.Join(fish.NodeDirectory(cfg), "sqlite.db")
+			if err = fish.DBSnapshot(dbPath, path, logger.Discard); err != nil {
+				return log.Error("Fish: Unable to backup the database:", err)
+			}
+			log.Info("Fish: Database backup created:", path)
+			return nil
+		},
+	}
+
+	var from, at string
+	restore := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the stopped node database to the point in time",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cfg, err := readConfig()
+			if err != nil {
+				return err
+			}
+			pointInTime := time.Now()
+			if at != "" {
+				if pointInTime, err = time.Parse(time.RFC3339, at); err != nil {
+					return log.Errorf("Fish: Unable to parse the restore time: %v", err)
+				}
+			}
+			name, err := fish.BackupRestore(cfg, from, pointInTime)
+			if err != nil {
+				return log.Error("Fish: Unable to restore the database:", err)
+			}
+			log.Info("Fish: Database restored from backup:", name)
+			return nil
+		},
+	}
+	restore.Flags().StringVar(&from, "from", "", "directory with the backups, backup.directory from config by default")
+	restore.Flags().StringVar(&at, "at", "", "RFC3339 time to restore to, the latest backup made not later is used (latest by default)")
+
+	cmd.AddCommand(create, restore)
+	return cmd
+}
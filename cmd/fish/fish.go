@@ -27,17 +27,31 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"github.com/adobe/aquarium-fish/lib/adminssh"
 	"github.com/adobe/aquarium-fish/lib/build"
 	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi"
 	"github.com/adobe/aquarium-fish/lib/proxysocks"
 	"github.com/adobe/aquarium-fish/lib/proxyssh"
+	"github.com/adobe/aquarium-fish/lib/tracing"
 	"github.com/adobe/aquarium-fish/lib/util"
+
+	// Registering the available gates
+	_ "github.com/adobe/aquarium-fish/lib/gates/buildkite"
+	_ "github.com/adobe/aquarium-fish/lib/gates/github"
+	_ "github.com/adobe/aquarium-fish/lib/gates/jenkins"
+	_ "github.com/adobe/aquarium-fish/lib/gates/terminal"
+	_ "github.com/adobe/aquarium-fish/lib/gates/webhook"
 )
 
 func main() {
+	if err := serviceInit(); err != nil {
+		log.Error("Fish: Unable to init the service:", err)
+		os.Exit(1)
+	}
 	log.Infof("Aquarium Fish %s (%s)", build.Version, build.Time)
 
 	var apiAddress string
@@ -108,7 +122,11 @@ func main() {
 				debug.SetMemoryLimit(int64(cfg.MemTarget.Bytes()))
 			}
 
-			dir := filepath.Join(cfg.Directory, cfg.NodeAddress)
+			if err = tracing.Init(cfg.Tracing, tracing.Attr("host.name", cfg.NodeName)); err != nil {
+				return log.Errorf("Fish: Unable to init tracing: %v", err)
+			}
+
+			dir := fish.NodeDirectory(cfg)
 			if err = os.MkdirAll(dir, 0o750); err != nil {
 				return log.Errorf("Fish: Can't create working directory %s: %v", dir, err)
 			}
@@ -153,6 +171,7 @@ func main() {
 			if err != nil {
 				return err
 			}
+			serviceWatch(fish.Quit)
 
 			log.Info("Fish starting socks5 proxy...")
 			err = proxysocks.Init(fish, cfg.ProxySocksAddress)
@@ -165,13 +184,50 @@ func main() {
 			if !filepath.IsAbs(idRsaPath) {
 				idRsaPath = filepath.Join(cfg.Directory, idRsaPath)
 			}
-			cfg.ProxySSHAddress, err = proxyssh.Init(fish, idRsaPath, cfg.ProxySSHAddress)
+			recordPath := cfg.ProxySSHRecordPath
+			if recordPath != "" && !filepath.IsAbs(recordPath) {
+				recordPath = filepath.Join(cfg.Directory, recordPath)
+			}
+			cfg.ProxySSHAddress, err = proxyssh.Init(fish, idRsaPath, cfg.ProxySSHAddress, recordPath, time.Duration(cfg.ProxySSHRecordRetention), cfg.ProxySSHRecordInput)
 			if err != nil {
 				return err
 			}
 
+			if cfg.AdminSSHAddress != "" {
+				log.Info("Fish starting admin ssh...")
+				hostKeyPath := cfg.AdminSSHHostKey
+				if !filepath.IsAbs(hostKeyPath) {
+					hostKeyPath = filepath.Join(cfg.Directory, hostKeyPath)
+				}
+				caKeyPath := cfg.AdminSSHCAKey
+				if !filepath.IsAbs(caKeyPath) {
+					caKeyPath = filepath.Join(cfg.Directory, caKeyPath)
+				}
+				revokedPath := cfg.AdminSSHRevoked
+				if revokedPath != "" && !filepath.IsAbs(revokedPath) {
+					revokedPath = filepath.Join(cfg.Directory, revokedPath)
+				}
+				auditPath := cfg.AdminSSHAuditPath
+				if auditPath != "" && !filepath.IsAbs(auditPath) {
+					auditPath = filepath.Join(cfg.Directory, auditPath)
+				}
+				if _, err = adminssh.Init(fish, hostKeyPath, cfg.AdminSSHAddress, caKeyPath, revokedPath, cfg.AdminSSHPrincipals, auditPath); err != nil {
+					return err
+				}
+			}
+
 			log.Info("Fish starting API...")
-			srv, err := openapi.Init(fish, cfg.APIAddress, caPath, certPath, keyPath)
+			clientsPath := cfg.APIClientsDirectory
+			if clientsPath != "" && !filepath.IsAbs(clientsPath) {
+				clientsPath = filepath.Join(cfg.Directory, clientsPath)
+			}
+			srv, err := openapi.Init(fish, cfg.APIAddress, caPath, certPath, keyPath, cfg.MetricsAuth, clientsPath)
+			if err != nil {
+				return err
+			}
+
+			log.Info("Fish starting gates...")
+			gateInstances, err := gates.Init(fish, cfg.Gates)
 			if err != nil {
 				return err
 			}
@@ -189,9 +245,12 @@ func main() {
 
 			log.Info("Fish stopping...")
 
+			gates.Close(gateInstances)
 			fish.Close()
+			tracing.Close()
 
 			log.Info("Fish stopped")
+			serviceStop()
 
 			return nil
 		},
@@ -210,6 +269,11 @@ func main() {
 	flags.BoolVar(&logTimestamp, "timestamp", true, "prepend timestamps for each log line")
 	flags.Lookup("timestamp").NoOptDefVal = "false"
 
+	cmd.AddCommand(backupCmd())
+	if svcCmd := serviceCmd(); svcCmd != nil {
+		cmd.AddCommand(svcCmd)
+	}
+
 	if err := cmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// The node is managed by systemd or launchd on the other systems, so nothing to do here
+
+func serviceInit() error { return nil }
+
+func serviceWatch(_ chan<- os.Signal) {}
+
+func serviceStop() {}
+
+func serviceCmd() *cobra.Command { return nil }
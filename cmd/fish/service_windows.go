@@ -0,0 +1,201 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// Name of the Windows service to register the node
+const serviceName = "aquarium-fish"
+
+// service is set when the node is started by the Windows service manager
+var service *serviceHandler
+
+// serviceHandler implements svc.Handler to pass the service manager stop request to the node
+type serviceHandler struct {
+	stop   chan struct{} // Service manager asks to stop the node
+	done   chan struct{} // Node is stopped
+	exited chan struct{} // Service dispatcher is completed
+}
+
+// serviceInit connects to the service manager if the node is started as service, it should be
+// done right away since the service manager waits for it only 30 seconds. The services are started
+// in system32 directory and without console, so the working directory is changed to the executable
+// one and the output is written to the log file there.
+func serviceInit() error {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to locate the service executable: %v", err)
+	}
+	dir := filepath.Dir(exe)
+	if err = os.Chdir(dir); err != nil {
+		return fmt.Errorf("Fish: Unable to change the service working directory: %v", err)
+	}
+	out, err := os.OpenFile(filepath.Join(dir, serviceName+".log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to open the service log file: %v", err)
+	}
+	os.Stdout, os.Stderr = out, out
+	if err = log.InitLoggers(); err != nil {
+		return err
+	}
+
+	service = &serviceHandler{
+		stop:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	go func() {
+		defer close(service.exited)
+		if err := svc.Run(serviceName, service); err != nil {
+			log.Error("Fish: Service dispatcher failed:", err)
+		}
+	}()
+
+	return nil
+}
+
+// serviceWatch passes the service stop request to the node quit channel
+func serviceWatch(quit chan<- os.Signal) {
+	if service == nil {
+		return
+	}
+	go func() {
+		<-service.stop
+		quit <- syscall.SIGTERM
+	}()
+}
+
+// serviceStop reports the service manager the node is stopped
+func serviceStop() {
+	if service == nil {
+		return
+	}
+	close(service.done)
+	select {
+	case <-service.exited:
+	case <-time.After(5 * time.Second):
+	}
+}
+
+// Execute is running while the node is running, the node is reported as started right away since
+// the init could take more time than service manager allows
+func (h *serviceHandler) Execute(_ []string, r <-chan svc.ChangeRequest, s chan<- svc.Status) (bool, uint32) {
+	s <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case <-h.done:
+			return false, 0
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				s <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				s <- svc.Status{State: svc.StopPending}
+				h.stop <- struct{}{}
+				<-h.done
+				return false, 0
+			}
+		}
+	}
+}
+
+// serviceCmd registers the node as Windows service
+func serviceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Register the node as Windows service",
+	}
+
+	install := &cobra.Command{
+		Use:   "install [-- <node flags>]",
+		Short: "Create the service started on boot, the node flags are used to run it (like `-- -c C:\\fish\\config.yml`)",
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			exe, err := os.Executable()
+			if err != nil {
+				return log.Error("Fish: Unable to locate the executable:", err)
+			}
+			m, err := mgr.Connect()
+			if err != nil {
+				return log.Error("Fish: Unable to connect to the service manager:", err)
+			}
+			defer m.Disconnect()
+
+			if s, err := m.OpenService(serviceName); err == nil {
+				s.Close()
+				return log.Errorf("Fish: Service %s already exists", serviceName)
+			}
+			s, err := m.CreateService(serviceName, exe, mgr.Config{
+				DisplayName: "Aquarium Fish",
+				Description: "Part of the Aquarium suite - a distributed resources manager",
+				StartType:   mgr.StartAutomatic,
+			}, args...)
+			if err != nil {
+				return log.Error("Fish: Unable to create the service:", err)
+			}
+			defer s.Close()
+
+			// Restarting the node if it crashes
+			if err = s.SetRecoveryActions([]mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 10 * time.Second}}, 86400); err != nil {
+				log.Warn("Fish: Unable to set the service recovery actions:", err)
+			}
+			log.Infof("Fish: Service %s created, start it with `sc start %s`", serviceName, serviceName)
+			return nil
+		},
+	}
+
+	uninstall := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop and remove the service",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			m, err := mgr.Connect()
+			if err != nil {
+				return log.Error("Fish: Unable to connect to the service manager:", err)
+			}
+			defer m.Disconnect()
+
+			s, err := m.OpenService(serviceName)
+			if err != nil {
+				return log.Errorf("Fish: Service %s is not installed: %v", serviceName, err)
+			}
+			defer s.Close()
+			if _, err = s.Control(svc.Stop); err != nil {
+				log.Debug("Fish: Unable to stop the service, probably it's not running:", err)
+			}
+			if err = s.Delete(); err != nil {
+				return log.Error("Fish: Unable to remove the service:", err)
+			}
+			log.Infof("Fish: Service %s removed", serviceName)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(install, uninstall)
+	return cmd
+}
@@ -0,0 +1,263 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/adobe/aquarium-fish/lib/client"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+func applicationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "application",
+		Aliases: []string{"app"},
+		Short:   "Manage Applications",
+	}
+
+	var filter string
+	var owner, labelUID, status, olderThan, nodeUID string
+	selector := func() (*types.ApplicationSelector, error) {
+		sel := &types.ApplicationSelector{}
+		if owner != "" {
+			sel.OwnerName = &owner
+		}
+		if labelUID != "" {
+			uid, err := uuid.Parse(labelUID)
+			if err != nil {
+				return nil, fmt.Errorf("Wrong Label UID: %v", err)
+			}
+			sel.LabelUID = &uid
+		}
+		if status != "" {
+			s := types.ApplicationStatus(strings.ToUpper(status))
+			sel.Status = &s
+		}
+		if olderThan != "" {
+			sel.OlderThan = &olderThan
+		}
+		if nodeUID != "" {
+			uid, err := uuid.Parse(nodeUID)
+			if err != nil {
+				return nil, fmt.Errorf("Wrong Node UID: %v", err)
+			}
+			sel.NodeUID = &uid
+		}
+		return sel, nil
+	}
+	selectorFlags := func(c *cobra.Command) {
+		c.Flags().StringVar(&owner, "owner", "", "only the Applications of the owner")
+		c.Flags().StringVar(&labelUID, "label-uid", "", "only the Applications of the Label")
+		c.Flags().StringVar(&status, "status", "", "only the Applications with the current status")
+		c.Flags().StringVar(&olderThan, "older-than", "", "only the Applications created earlier than the duration ago")
+		c.Flags().StringVar(&nodeUID, "node-uid", "", "only the Applications allocated by the Node")
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the Applications",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			sel, err := selector()
+			if err != nil {
+				return err
+			}
+			apps, err := cli.ApplicationList(filter, sel)
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(apps))
+			for _, a := range apps {
+				rows = append(rows, []string{a.UID.String(), a.ShortId, a.OwnerName, a.LabelUID.String(), age(a.CreatedAt)})
+			}
+			return printResult(apps, []string{"UID", "SHORT ID", "OWNER", "LABEL", "AGE"}, rows)
+		},
+	}
+	list.Flags().StringVarP(&filter, "filter", "f", "", "SQL WHERE filter like \"owner_name = 'user'\"")
+	selectorFlags(list)
+
+	var labelName, metadata string
+	var await bool
+	var timeout time.Duration
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Request the Resource with the Label",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			if !json.Valid([]byte(metadata)) {
+				return fmt.Errorf("Metadata should be a JSON object")
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			label, err := resolveLabel(cli, labelName)
+			if err != nil {
+				return err
+			}
+			app, err := cli.ApplicationCreate(&types.Application{LabelUID: label.UID, Metadata: util.UnparsedJSON(metadata)})
+			if err != nil {
+				return err
+			}
+			if await {
+				if _, err := cli.ApplicationAwait(app.UID, []types.ApplicationStatus{types.ApplicationStatusALLOCATED}, timeout, 5*time.Second); err != nil {
+					return err
+				}
+			}
+			return printResult(app, []string{"UID", "SHORT ID", "LABEL"},
+				[][]string{{app.UID.String(), app.ShortId, fmt.Sprintf("%s:%d", label.Name, label.Version)}})
+		},
+	}
+	create.Flags().StringVarP(&labelName, "label", "l", "", "Label name, the latest version is used if not set as <name>:<version>")
+	create.Flags().StringVarP(&metadata, "metadata", "m", "{}", "JSON object passed to the Resource")
+	create.Flags().BoolVarP(&await, "await", "w", false, "wait until the Application is allocated")
+	create.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "how long to wait for the allocation")
+	create.MarkFlagRequired("label")
+
+	var statuses []string
+	awaitCmd := &cobra.Command{
+		Use:   "await <uid>",
+		Short: "Wait for the Application to get one of the statuses",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			uid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("Wrong Application UID: %v", err)
+			}
+			want := make([]types.ApplicationStatus, len(statuses))
+			for i, s := range statuses {
+				want[i] = types.ApplicationStatus(strings.ToUpper(s))
+			}
+			state, err := cli.ApplicationAwait(uid, want, timeout, 5*time.Second)
+			if err != nil {
+				return err
+			}
+			return printState(state)
+		},
+	}
+	awaitCmd.Flags().StringSliceVarP(&statuses, "status", "s", []string{"ALLOCATED"}, "statuses to wait for")
+	awaitCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "how long to wait")
+
+	state := &cobra.Command{
+		Use:   "state <uid>",
+		Short: "Show the current state of the Application",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			uid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("Wrong Application UID: %v", err)
+			}
+			state, err := cli.ApplicationStateGet(uid)
+			if err != nil {
+				return err
+			}
+			return printState(state)
+		},
+	}
+
+	var dryRun bool
+	deallocate := &cobra.Command{
+		Use:   "deallocate [<uid>]",
+		Short: "Release the Resource of the Application or all the selected ones",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				sel, err := selector()
+				if err != nil {
+					return err
+				}
+				if *sel == (types.ApplicationSelector{}) {
+					return fmt.Errorf("Application UID or the selector flags are required")
+				}
+				res, err := cli.ApplicationDeallocateBatch(&types.ApplicationDeallocateBatch{Selector: *sel, DryRun: &dryRun})
+				if err != nil {
+					return err
+				}
+				rows := make([][]string, 0, len(res.Deallocated)+len(res.Failed))
+				for _, uid := range res.Deallocated {
+					rows = append(rows, []string{uid.String(), ""})
+				}
+				for uid, reason := range res.Failed {
+					rows = append(rows, []string{uid, reason})
+				}
+				return printResult(res, []string{"UID", "ERROR"}, rows)
+			}
+			uid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("Wrong Application UID: %v", err)
+			}
+			state, err := cli.ApplicationDeallocate(uid)
+			if err != nil {
+				return err
+			}
+			return printState(state)
+		},
+	}
+
+	selectorFlags(deallocate)
+	deallocate.Flags().BoolVar(&dryRun, "dry-run", false, "only show the selected Applications")
+
+	cmd.AddCommand(list, create, awaitCmd, state, deallocate, sshCmd())
+	return cmd
+}
+
+// resolveLabel finds the Label by "<name>" or "<name>:<version>"
+func resolveLabel(cli *client.Client, name string) (*types.Label, error) {
+	n, v, found := strings.Cut(name, ":")
+	if !found {
+		return cli.LabelGetLatest(name)
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("Wrong Label version %q: %v", v, err)
+	}
+	labels, err := cli.LabelList(fmt.Sprintf("name = '%s' AND version = %d", strings.ReplaceAll(n, "'", "''"), version))
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("Label %s not found", name)
+	}
+	return &labels[0], nil
+}
+
+// printState shows the Application state
+func printState(state *types.ApplicationState) error {
+	return printResult(state, []string{"STATUS", "AGE", "DESCRIPTION"},
+		[][]string{{string(state.Status), age(state.CreatedAt), state.Description}})
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Command line client for Fish API
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adobe/aquarium-fish/lib/build"
+	"github.com/adobe/aquarium-fish/lib/client"
+)
+
+// Global options, could be set by flags, env vars or the saved login config
+var (
+	cfgPath string
+	output  string
+	flagCfg client.Config
+)
+
+func main() {
+	cmd := &cobra.Command{
+		Use:           "fishctl",
+		Short:         "Aquarium Fish client",
+		Long:          `Command line client to manage the Aquarium Fish cluster resources`,
+		Version:       fmt.Sprintf("%s (%s)", build.Version, build.Time),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			if output != "table" && output != "json" {
+				return fmt.Errorf("Unknown output format %q, use table or json", output)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&cfgPath, "config", defaultConfigPath(), "path to the login config")
+	flags.StringVarP(&output, "output", "o", "table", "output format (table, json)")
+	flags.StringVar(&flagCfg.URL, "url", "", "Fish API address, FISH_URL env var could be used")
+	flags.StringVarP(&flagCfg.Username, "user", "u", "", "user name, FISH_USER env var could be used")
+	flags.StringVarP(&flagCfg.Password, "password", "p", "", "user password or API token, FISH_PASSWORD env var could be used")
+	flags.StringVar(&flagCfg.CACert, "ca-cert", "", "CA certificate to verify the Fish node")
+	flags.BoolVar(&flagCfg.Insecure, "insecure", false, "do not verify the Fish node certificate")
+
+	cmd.AddCommand(loginCmd(), labelCmd(), applicationCmd(), resourceCmd(), nodeCmd())
+
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		os.Exit(1)
+	}
+}
+
+// defaultConfigPath returns the login config location in the user config dir
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "fishctl.json"
+	}
+	return filepath.Join(dir, "fishctl.json")
+}
+
+// loadConfig merges the saved login config with env vars and flags, the last ones win
+func loadConfig() (cfg client.Config, err error) {
+	if data, err := os.ReadFile(cfgPath); err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("Unable to parse config %s: %v", cfgPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return cfg, fmt.Errorf("Unable to read config %s: %v", cfgPath, err)
+	}
+
+	for _, v := range []struct {
+		env, flag string
+		to        *string
+	}{
+		{"FISH_URL", flagCfg.URL, &cfg.URL},
+		{"FISH_USER", flagCfg.Username, &cfg.Username},
+		{"FISH_PASSWORD", flagCfg.Password, &cfg.Password},
+		{"", flagCfg.CACert, &cfg.CACert},
+	} {
+		if val := os.Getenv(v.env); v.env != "" && val != "" {
+			*v.to = val
+		}
+		if v.flag != "" {
+			*v.to = v.flag
+		}
+	}
+	cfg.Insecure = cfg.Insecure || flagCfg.Insecure
+	return cfg, nil
+}
+
+// newClient creates the API client with the current config
+func newClient() (*client.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg)
+}
@@ -0,0 +1,158 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func labelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "Manage Labels",
+	}
+
+	var filter string
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the Labels",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			labels, err := cli.LabelList(filter)
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(labels))
+			for _, l := range labels {
+				var drivers string
+				for i, def := range l.Definitions {
+					if i > 0 {
+						drivers += ","
+					}
+					drivers += def.Driver
+				}
+				rows = append(rows, []string{l.UID.String(), l.Name, fmt.Sprint(l.Version), drivers})
+			}
+			return printResult(labels, []string{"UID", "NAME", "VERSION", "DRIVERS"}, rows)
+		},
+	}
+	list.Flags().StringVarP(&filter, "filter", "f", "", "SQL WHERE filter like \"name = 'xcode'\"")
+
+	var file string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create the Label from YAML or JSON file",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("Unable to read Label file: %v", err)
+			}
+			var label types.Label
+			// JSON is a subset of YAML, so both are supported
+			if err := yaml.Unmarshal(data, &label); err != nil {
+				return fmt.Errorf("Unable to parse Label file: %v", err)
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			out, err := cli.LabelCreate(&label)
+			if err != nil {
+				return err
+			}
+			return printResult(out, []string{"UID", "NAME", "VERSION"},
+				[][]string{{out.UID.String(), out.Name, fmt.Sprint(out.Version)}})
+		},
+	}
+	create.Flags().StringVarP(&file, "file", "f", "", "path to the Label definition")
+	create.MarkFlagRequired("file")
+
+	var applyFile, rolesFile string
+	var prune, dryRun bool
+	apply := &cobra.Command{
+		Use:   "apply",
+		Short: "Make the cluster Labels match the set from YAML or JSON file",
+		Long: `Creates the missing Label versions and reports the existing ones with different content
+as drifted. With --prune removes the versions missing in the file, the versions used by the
+active Applications are kept. With --roles the custom Roles are applied too (admin only): the
+changed ones are updated and prune keeps the granted ones. Use --dry-run to see the plan.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			data, err := os.ReadFile(applyFile)
+			if err != nil {
+				return fmt.Errorf("Unable to read Labels file: %v", err)
+			}
+			req := &types.LabelApply{Prune: &prune, DryRun: &dryRun}
+			if err := yaml.Unmarshal(data, &req.Labels); err != nil {
+				return fmt.Errorf("Unable to parse Labels file: %v", err)
+			}
+			if rolesFile != "" {
+				if data, err = os.ReadFile(rolesFile); err != nil {
+					return fmt.Errorf("Unable to read Roles file: %v", err)
+				}
+				roles := []types.Role{}
+				if err := yaml.Unmarshal(data, &roles); err != nil {
+					return fmt.Errorf("Unable to parse Roles file: %v", err)
+				}
+				req.Roles = &roles
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			res, err := cli.LabelApply(req)
+			if err != nil {
+				return err
+			}
+			var rows [][]string
+			for _, item := range []struct {
+				action string
+				keys   []string
+			}{{"create", res.Created}, {"unchanged", res.Unchanged}, {"drifted", res.Drifted}, {"remove", res.Removed}, {"in use", res.InUse}} {
+				for _, key := range item.keys {
+					rows = append(rows, []string{key, item.action})
+				}
+			}
+			if res.Roles != nil {
+				for _, item := range []struct {
+					action string
+					names  []string
+				}{{"create", res.Roles.Created}, {"update", res.Roles.Updated}, {"unchanged", res.Roles.Unchanged}, {"remove", res.Roles.Removed}, {"in use", res.Roles.InUse}} {
+					for _, name := range item.names {
+						rows = append(rows, []string{"role/" + name, item.action})
+					}
+				}
+			}
+			return printResult(res, []string{"NAME", "ACTION"}, rows)
+		},
+	}
+	apply.Flags().StringVarP(&applyFile, "file", "f", "", "path to the list of Labels")
+	apply.Flags().StringVar(&rolesFile, "roles", "", "path to the list of custom Roles to apply too")
+	apply.Flags().BoolVar(&prune, "prune", false, "remove the Label versions and Roles missing in the files")
+	apply.Flags().BoolVar(&dryRun, "dry-run", false, "only show the changes")
+	apply.MarkFlagRequired("file")
+
+	cmd.AddCommand(list, create, apply)
+	return cmd
+}
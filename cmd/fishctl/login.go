@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adobe/aquarium-fish/lib/client"
+)
+
+func loginCmd() *cobra.Command {
+	var passwordStdin bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Check the credentials and save them for the next commands",
+		Long: `Verifies the URL and credentials and stores them in the config file readable only by the
+current user. Use API token instead of the password to not keep it on disk.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if passwordStdin {
+				line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("Unable to read password from stdin: %v", err)
+				}
+				cfg.Password = strings.TrimRight(line, "\r\n")
+			}
+			cli, err := client.New(cfg)
+			if err != nil {
+				return err
+			}
+			user, err := cli.UserMe()
+			if err != nil {
+				return fmt.Errorf("Unable to login: %v", err)
+			}
+
+			data, err := json.MarshalIndent(cfg, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+				return fmt.Errorf("Unable to create config dir: %v", err)
+			}
+			if err := os.WriteFile(cfgPath, data, 0o600); err != nil {
+				return fmt.Errorf("Unable to save config: %v", err)
+			}
+			fmt.Printf("Logged in to %s as %s\n", cfg.URL, user.Name)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password or token from stdin")
+	return cmd
+}
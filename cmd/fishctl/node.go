@@ -0,0 +1,61 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func nodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Show the cluster Nodes",
+	}
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show the Nodes status",
+		Args:  cobra.NoArgs,
+		RunE: func(_ /*cmd*/ *cobra.Command, _ /*args*/ []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			nodes, err := cli.NodeList()
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(nodes))
+			for _, n := range nodes {
+				mode := "active"
+				switch {
+				case n.Shutdown:
+					mode = "shutdown"
+				case n.Drain:
+					mode = "drain"
+				case n.Maintenance:
+					mode = "maintenance"
+				}
+				health := "-"
+				if n.Telemetry != nil {
+					health = string(n.Telemetry.Status)
+				}
+				rows = append(rows, []string{n.Name, n.LocationName, n.Address, n.Version, mode, health, age(n.UpdatedAt)})
+			}
+			return printResult(nodes, []string{"NAME", "LOCATION", "ADDRESS", "VERSION", "MODE", "HEALTH", "LAST PING"}, rows)
+		},
+	}
+
+	cmd.AddCommand(status)
+	return cmd
+}
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func resourceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resource",
+		Short: "Access the Resources",
+	}
+
+	var otp string
+	access := &cobra.Command{
+		Use:   "access <uid>",
+		Short: "Request the credentials to access the Resource through the ssh proxy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			uid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("Wrong Resource UID: %v", err)
+			}
+			acc, err := cli.ResourceAccess(uid, otp)
+			if err != nil {
+				return err
+			}
+			return printResult(acc, []string{"ADDRESS", "USERNAME", "PASSWORD", "HOST KEY"},
+				[][]string{{acc.Address, acc.Username, acc.Password, acc.HostKeyFingerprint}})
+		},
+	}
+	access.Flags().StringVar(&otp, "otp", "", "OTP code if the Label requires it")
+
+	cmd.AddCommand(access)
+	return cmd
+}
+
+func sshCmd() *cobra.Command {
+	var otp string
+	cmd := &cobra.Command{
+		Use:   "ssh <uid> [-- <ssh args>]",
+		Short: "Connect to the Application Resource by ssh through the proxy",
+		Long: `Requests the Resource access and runs ssh client with the received key. The proxy host
+key is checked with the fingerprint provided by Fish.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ /*cmd*/ *cobra.Command, args []string) error {
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			uid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("Wrong Application UID: %v", err)
+			}
+			res, err := cli.ApplicationResourceGet(uid)
+			if err != nil {
+				return err
+			}
+			acc, err := cli.ResourceAccess(res.UID, otp)
+			if err != nil {
+				return err
+			}
+			return runSSH(acc, args[1:])
+		},
+	}
+	cmd.Flags().StringVar(&otp, "otp", "", "OTP code if the Label requires it")
+	return cmd
+}
+
+// runSSH executes the ssh client with the temporary key and known_hosts
+func runSSH(acc *types.ResourceAccess, extra []string) error {
+	host, port, err := net.SplitHostPort(acc.Address)
+	if err != nil {
+		return fmt.Errorf("Wrong proxy address %q: %v", acc.Address, err)
+	}
+	tmp, err := os.MkdirTemp("", "fishctl-ssh-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	knownHosts := filepath.Join(tmp, "known_hosts")
+	if err := pinHostKey(acc.Address, acc.HostKeyFingerprint, knownHosts); err != nil {
+		return err
+	}
+	args := []string{"-p", port, "-o", "UserKnownHostsFile=" + knownHosts, "-o", "StrictHostKeyChecking=yes"}
+	if acc.Key != "" {
+		keyPath := filepath.Join(tmp, "id")
+		if err := os.WriteFile(keyPath, []byte(acc.Key), 0o600); err != nil {
+			return err
+		}
+		args = append(args, "-i", keyPath, "-o", "IdentitiesOnly=yes")
+	} else {
+		fmt.Fprintln(os.Stderr, "Password:", acc.Password)
+	}
+	args = append(args, append(extra, acc.Username+"@"+host)...)
+
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// errKeyscan stops the ssh handshake when the host key is received
+var errKeyscan = errors.New("keyscan")
+
+// pinHostKey gets the proxy host key, verifies the fingerprint and writes it to known_hosts
+func pinHostKey(address, fingerprint, path string) error {
+	var hostKey ssh.PublicKey
+	cfg := &ssh.ClientConfig{
+		User: "fishctl",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errKeyscan
+		},
+		Timeout: 10 * time.Second,
+	}
+	if _, err := ssh.Dial("tcp", address, cfg); hostKey == nil {
+		return fmt.Errorf("Unable to get the proxy host key: %v", err)
+	}
+	if fingerprint != "" && ssh.FingerprintSHA256(hostKey) != fingerprint {
+		return fmt.Errorf("Proxy host key fingerprint mismatch: %s != %s", ssh.FingerprintSHA256(hostKey), fingerprint)
+	}
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, hostKey)
+	return os.WriteFile(path, []byte(line+"\n"), 0o600)
+}
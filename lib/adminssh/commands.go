@@ -0,0 +1,239 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package adminssh
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+const helpText = `Available commands:
+  help                          - show this help
+  node                          - show this node info
+  apps                          - list the active Applications
+  app <uid|short_id>            - show Application, its state and Resource
+  state <uid|short_id> <STATUS> [description]
+                                - force the Application state (use with care)
+  sql <SELECT ...>              - run read-only SQL query on the database
+  log [lines]                   - show the last node log lines (100 by default)
+  log -f                        - follow the node log, Ctrl-C to stop
+  maintenance <on|off>          - switch node maintenance mode
+  exit                          - close the session
+`
+
+// execute runs the command line and returns the exit code and if the session should be closed
+func (s *session) execute(line string) (code int, quit bool) {
+	line = strings.TrimSpace(line)
+	s.server.audit(s.conn, "command: "+line)
+
+	cmd, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	var err error
+	switch cmd {
+	case "help":
+		fmt.Fprint(s, helpText)
+	case "exit", "quit":
+		return 0, true
+	case "node":
+		err = s.printJSON(s.server.fish.GetNode())
+	case "apps":
+		err = s.cmdApps()
+	case "app":
+		err = s.cmdApp(args)
+	case "state":
+		err = s.cmdState(args)
+	case "sql":
+		err = s.cmdSQL(args)
+	case "log":
+		err = s.cmdLog(args)
+	case "maintenance":
+		err = s.cmdMaintenance(args)
+	default:
+		err = fmt.Errorf("Unknown command %q, type \"help\" to see the available commands", cmd)
+	}
+
+	// The state changing commands are recorded in the node audit log too
+	switch cmd {
+	case "state":
+		uid, _, _ := strings.Cut(args, " ")
+		s.server.auditRecord(s.conn, line, "ApplicationState", uid, err)
+	case "maintenance":
+		s.server.auditRecord(s.conn, line, "Node", s.server.fish.GetNode().Name, err)
+	}
+
+	if err != nil {
+		fmt.Fprintf(s, "ERROR: %v\n", err)
+		return 1, false
+	}
+	return 0, false
+}
+
+func (s *session) printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s, "%s\n", data)
+	return nil
+}
+
+func (s *session) cmdApps() error {
+	states, err := s.server.fish.ApplicationStateListActive()
+	if err != nil {
+		return err
+	}
+	uids := make([]types.ApplicationUID, len(states))
+	for i, state := range states {
+		uids[i] = state.ApplicationUID
+	}
+	apps, err := s.server.fish.ApplicationListByUIDs(uids)
+	if err != nil {
+		return err
+	}
+	byUID := make(map[types.ApplicationUID]*types.Application, len(apps))
+	for i := range apps {
+		byUID[apps[i].UID] = &apps[i]
+	}
+
+	w := tabwriter.NewWriter(s, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "UID\tSHORT_ID\tOWNER\tSTATUS\tUPDATED")
+	for _, state := range states {
+		app, ok := byUID[state.ApplicationUID]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", app.UID, app.ShortId, app.OwnerName, state.Status, state.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func (s *session) cmdApp(args string) error {
+	appUID, err := s.server.fish.ApplicationResolveUID(args)
+	if err != nil {
+		return err
+	}
+	app, err := s.server.fish.ApplicationGet(appUID)
+	if err != nil {
+		return err
+	}
+	out := map[string]any{"application": app}
+	if state, err := s.server.fish.ApplicationStateGetByApplication(appUID); err == nil {
+		out["state"] = state
+	}
+	if res, err := s.server.fish.ResourceGetByApplication(appUID); err == nil {
+		out["resource"] = res
+	}
+	return s.printJSON(out)
+}
+
+func (s *session) cmdState(args string) error {
+	fields := strings.SplitN(args, " ", 3)
+	if len(fields) < 2 {
+		return fmt.Errorf("Usage: state <uid|short_id> <STATUS> [description]")
+	}
+	appUID, err := s.server.fish.ApplicationResolveUID(fields[0])
+	if err != nil {
+		return err
+	}
+	status := types.ApplicationStatus(strings.ToUpper(fields[1]))
+	switch status {
+	case types.ApplicationStatusPENDINGAPPROVAL, types.ApplicationStatusNEW, types.ApplicationStatusELECTED, types.ApplicationStatusALLOCATED,
+		types.ApplicationStatusHOLD, types.ApplicationStatusDEALLOCATE, types.ApplicationStatusRECALLED,
+		types.ApplicationStatusDEALLOCATED, types.ApplicationStatusERROR:
+	default:
+		return fmt.Errorf("Unknown status %q", fields[1])
+	}
+	description := fmt.Sprintf("Forced by admin %s with certificate %q", s.conn.User(), s.conn.Permissions.Extensions[extKeyID])
+	if len(fields) > 2 {
+		description += ": " + fields[2]
+	}
+
+	as := &types.ApplicationState{ApplicationUID: appUID, Status: status, Description: description}
+	if err := s.server.fish.ApplicationStateCreate(as); err != nil {
+		return err
+	}
+	log.Warnf("ADMINSSH: Application %s state forced to %s by %s", appUID, status, s.conn.User())
+	return s.printJSON(as)
+}
+
+func (s *session) cmdSQL(args string) error {
+	columns, rows, err := s.server.fish.DBQuery(args)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(s, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		values := make([]string, len(row))
+		for i, v := range row {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			values[i] = fmt.Sprint(v)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(s, "(%d rows)\n", len(rows))
+	return nil
+}
+
+func (s *session) cmdLog(args string) error {
+	if args == "-f" {
+		lines, cancel := log.TailSubscribe()
+		defer cancel()
+		interrupt := s.waitInterrupt()
+		for {
+			select {
+			case <-interrupt:
+				return nil
+			case line := <-lines:
+				fmt.Fprintln(s, line)
+			}
+		}
+	}
+
+	n := 100
+	if args != "" {
+		var err error
+		if n, err = strconv.Atoi(args); err != nil || n < 1 {
+			return fmt.Errorf("Wrong number of lines %q", args)
+		}
+	}
+	for _, line := range log.Tail(n) {
+		fmt.Fprintln(s, line)
+	}
+	return nil
+}
+
+func (s *session) cmdMaintenance(args string) error {
+	switch args {
+	case "on":
+		s.server.fish.MaintenanceSet(true)
+	case "off":
+		s.server.fish.MaintenanceSet(false)
+	default:
+		return fmt.Errorf("Usage: maintenance <on|off>")
+	}
+	fmt.Fprintf(s, "Maintenance mode: %s\n", args)
+	return nil
+}
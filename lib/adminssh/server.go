@@ -0,0 +1,272 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package adminssh implements emergency SSH management endpoint of the Fish node
+// It's separated from the API and proxyssh to be available even when they are not healthy, the
+// access is allowed only with the user SSH certificates signed by the cluster CA.
+package adminssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Permissions extensions used to pass the certificate info to the session
+const (
+	extKeyID  = "fish-key-id"
+	extSerial = "fish-serial"
+)
+
+type adminSSH struct {
+	fish         *fish.Fish
+	serverConfig *ssh.ServerConfig
+	principals   []string
+	revokedPath  string
+
+	auditMutex sync.Mutex
+	auditPath  string
+}
+
+// Init starts the admin ssh server
+// -> hostKeyPath - dedicated SSH private key to identify the server, generated if not exists
+// -> caKeyPath - cluster CA public key in authorized_keys format to verify the user certificates
+// -> revokedPath - file with the revoked certificates serials, one per line, empty disables
+// -> principals - which users are allowed to login, the certificate should contain them
+// -> auditPath - where to write the audit log of the executed commands
+func Init(f *fish.Fish, hostKeyPath, address, caKeyPath, revokedPath string, principals []string, auditPath string) (string, error) {
+	// The host key is not shared with ssh proxy to not allow it to impersonate the admin endpoint
+	privateBytes, err := os.ReadFile(hostKeyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Infof("ADMINSSH: Could not load %q, generating...", hostKeyPath)
+		if privateBytes, err = crypt.GenerateSSHKey(); err != nil {
+			return "", fmt.Errorf("ADMINSSH: Could not generate host key: %w", err)
+		}
+		if err = os.WriteFile(hostKeyPath, privateBytes, 0o600); err != nil {
+			return "", fmt.Errorf("ADMINSSH: Could not write %q: %w", hostKeyPath, err)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("ADMINSSH: Unable to read host key %q: %w", hostKeyPath, err)
+	}
+	private, err := ssh.ParsePrivateKey(privateBytes)
+	if err != nil {
+		return "", fmt.Errorf("ADMINSSH: Failed to parse host key: %w", err)
+	}
+
+	caBytes, err := os.ReadFile(caKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("ADMINSSH: Unable to read CA key %q: %w", caKeyPath, err)
+	}
+	caKey, _, _, _, err := ssh.ParseAuthorizedKey(caBytes)
+	if err != nil {
+		return "", fmt.Errorf("ADMINSSH: Failed to parse CA key: %w", err)
+	}
+
+	server := &adminSSH{fish: f, principals: principals, revokedPath: revokedPath, auditPath: auditPath}
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), caKey.Marshal())
+		},
+		IsRevoked: server.isRevoked,
+	}
+	server.serverConfig = &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-AquariumFishAdmin",
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			return server.publicKeyCallback(checker, conn, key)
+		},
+	}
+	server.serverConfig.AddHostKey(private)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", log.Errorf("ADMINSSH: Unable to bind to address %q: %v", address, err)
+	}
+
+	go server.serve(listener)
+
+	log.Info("ADMINSSH listening on:", listener.Addr())
+
+	return listener.Addr().String(), nil
+}
+
+// serve accepts the incoming connections until the listener is closed
+func (s *adminSSH) serve(listener net.Listener) {
+	defer listener.Close()
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			log.Errorf("ADMINSSH: Unable to accept the incoming connection: %v", err)
+			continue
+		}
+		go s.serveConnection(conn)
+	}
+}
+
+// isRevoked checks the certificate serial in the revoked list, the file is read on each check to
+// apply the revocation without restart. If the list can't be read - all the certificates are
+// considered revoked to not allow access by the certificate which could be revoked.
+func (s *adminSSH) isRevoked(cert *ssh.Certificate) bool {
+	if s.revokedPath == "" {
+		return false
+	}
+	data, err := os.ReadFile(s.revokedPath)
+	if err != nil {
+		log.Errorf("ADMINSSH: Unable to read revoked certificates list: %v", err)
+		return true
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		serial, err := strconv.ParseUint(line, 10, 64)
+		if err != nil {
+			log.Warnf("ADMINSSH: Wrong serial in revoked certificates list: %q", line)
+			continue
+		}
+		if serial == cert.Serial {
+			return true
+		}
+	}
+	return false
+}
+
+// publicKeyCallback allows only the valid certificates signed by CA with allowed principal
+func (s *adminSSH) publicKeyCallback(checker *ssh.CertChecker, conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	if !util.Contains(s.principals, conn.User()) {
+		log.Warnf("ADMINSSH: %s: User %q is not allowed", conn.RemoteAddr(), conn.User())
+		return nil, fmt.Errorf("ADMINSSH: User is not allowed")
+	}
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		log.Warnf("ADMINSSH: %s: User %q tried to login without certificate", conn.RemoteAddr(), conn.User())
+		return nil, fmt.Errorf("ADMINSSH: Only certificates are allowed")
+	}
+	// Checks the CA signature, validity period, principals and source-address critical option
+	perms, err := checker.Authenticate(conn, key)
+	if err != nil {
+		log.Warnf("ADMINSSH: %s: Certificate %q of user %q is not valid: %v", conn.RemoteAddr(), cert.KeyId, conn.User(), err)
+		return nil, err
+	}
+	if perms.Extensions == nil {
+		perms.Extensions = make(map[string]string)
+	}
+	perms.Extensions[extKeyID] = cert.KeyId
+	perms.Extensions[extSerial] = strconv.FormatUint(cert.Serial, 10)
+	return perms, nil
+}
+
+func (s *adminSSH) serveConnection(conn net.Conn) {
+	defer conn.Close()
+
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, s.serverConfig)
+	if err != nil {
+		log.Debugf("ADMINSSH: %s: Failed to handshake: %v", conn.RemoteAddr(), err)
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	log.Infof("ADMINSSH: %s: User %q logged in with certificate %q (serial %s)", sshConn.RemoteAddr(), sshConn.User(),
+		sshConn.Permissions.Extensions[extKeyID], sshConn.Permissions.Extensions[extSerial])
+	s.audit(sshConn, "login")
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only session channels are allowed")
+			continue
+		}
+		ch, chReqs, err := newChannel.Accept()
+		if err != nil {
+			log.Errorf("ADMINSSH: %s: Unable to accept channel: %v", sshConn.RemoteAddr(), err)
+			continue
+		}
+		sess := &session{server: s, conn: sshConn, ch: ch}
+		go sess.serve(chReqs)
+	}
+
+	s.audit(sshConn, "logout")
+}
+
+// audit writes the action to the audit log file and to the node log
+func (s *adminSSH) audit(conn *ssh.ServerConn, action string) {
+	log.Infof("ADMINSSH: %s: User %q audit: %s", conn.RemoteAddr(), conn.User(), action)
+	if s.auditPath == "" {
+		return
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"time":   time.Now().UTC().Format(time.RFC3339Nano),
+		"user":   conn.User(),
+		"key_id": conn.Permissions.Extensions[extKeyID],
+		"serial": conn.Permissions.Extensions[extSerial],
+		"remote": conn.RemoteAddr().String(),
+		"action": action,
+	})
+	if err != nil {
+		log.Error("ADMINSSH: Unable to prepare audit record:", err)
+		return
+	}
+
+	s.auditMutex.Lock()
+	defer s.auditMutex.Unlock()
+	file, err := os.OpenFile(s.auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		log.Error("ADMINSSH: Unable to open audit log:", err)
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		log.Error("ADMINSSH: Unable to write audit log:", err)
+	}
+}
+
+// auditRecord writes the state changing command to the node audit log, the status is 200 if the
+// command succeeded and 400 otherwise to be consistent with the API records
+func (s *adminSSH) auditRecord(conn *ssh.ServerConn, command, objType, objID string, cmdErr error) {
+	host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	rec := &types.AuditRecord{
+		UserName:   conn.User(),
+		AuthMethod: fish.AuditAuthSSHCert,
+		SourceIp:   host,
+		Action:     "ssh " + command,
+		ObjectType: objType,
+		ObjectId:   objID,
+		Status:     http.StatusOK,
+	}
+	if cmdErr != nil {
+		rec.Status = http.StatusBadRequest
+	}
+	if err := s.fish.AuditRecordCreate(rec); err != nil {
+		log.Error("ADMINSSH: Unable to write node audit record:", err)
+	}
+}
@@ -0,0 +1,129 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package adminssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testConn implements ssh.ConnMetadata to check the authentication
+type testConn struct {
+	user string
+}
+
+func (c *testConn) User() string        { return c.user }
+func (*testConn) SessionID() []byte     { return nil }
+func (*testConn) ClientVersion() []byte { return nil }
+func (*testConn) ServerVersion() []byte { return nil }
+func (*testConn) RemoteAddr() net.Addr  { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1} }
+func (*testConn) LocalAddr() net.Addr   { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2} }
+
+func testSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Unable to create signer: %v", err)
+	}
+	return signer
+}
+
+func testCert(t *testing.T, ca ssh.Signer, serial uint64, principal string) *ssh.Certificate {
+	cert := &ssh.Certificate{
+		Key:             testSigner(t).PublicKey(),
+		Serial:          serial,
+		CertType:        ssh.UserCert,
+		KeyId:           "test",
+		ValidPrincipals: []string{principal},
+		ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(time.Now().Add(time.Hour).Unix()),
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatalf("Unable to sign certificate: %v", err)
+	}
+	return cert
+}
+
+// Verify only the valid not revoked certificate of allowed principal is accepted
+func Test_public_key_callback(t *testing.T) {
+	ca := testSigner(t)
+	revokedPath := filepath.Join(t.TempDir(), "revoked")
+	if err := os.WriteFile(revokedPath, []byte("# revoked certificates\n2\n"), 0o600); err != nil {
+		t.Fatalf("Unable to write revoked list: %v", err)
+	}
+	s := &adminSSH{principals: []string{"admin"}, revokedPath: revokedPath}
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(ca.PublicKey().Marshal())
+		},
+		IsRevoked: s.isRevoked,
+	}
+	conn := &testConn{user: "admin"}
+
+	perms, err := s.publicKeyCallback(checker, conn, testCert(t, ca, 1, "admin"))
+	if err != nil {
+		t.Fatalf("publicKeyCallback() of valid certificate failed: %v", err)
+	}
+	if perms.Extensions[extSerial] != "1" {
+		t.Fatalf("publicKeyCallback() should pass certificate serial: %v", perms.Extensions)
+	}
+	if _, err := s.publicKeyCallback(checker, conn, testCert(t, ca, 2, "admin")); err == nil {
+		t.Fatalf("publicKeyCallback() of revoked certificate should fail")
+	}
+	if _, err := s.publicKeyCallback(checker, conn, testCert(t, testSigner(t), 1, "admin")); err == nil {
+		t.Fatalf("publicKeyCallback() of certificate signed by unknown CA should fail")
+	}
+	if _, err := s.publicKeyCallback(checker, conn, testSigner(t).PublicKey()); err == nil {
+		t.Fatalf("publicKeyCallback() of plain key should fail")
+	}
+	if _, err := s.publicKeyCallback(checker, &testConn{user: "other"}, testCert(t, ca, 1, "other")); err == nil {
+		t.Fatalf("publicKeyCallback() of not allowed user should fail")
+	}
+
+	// Unreadable list should deny all the certificates
+	s.revokedPath = filepath.Join(t.TempDir(), "missing")
+	if _, err := s.publicKeyCallback(checker, conn, testCert(t, ca, 1, "admin")); err == nil {
+		t.Fatalf("publicKeyCallback() with unreadable revoked list should fail")
+	}
+}
+
+// Verify the accept loop exits when the listener is closed
+func Test_serve_closed_listener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	s := &adminSSH{}
+	done := make(chan struct{})
+	go func() {
+		s.serve(listener)
+		close(done)
+	}()
+	listener.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("serve() did not exit after listener close")
+	}
+}
@@ -0,0 +1,155 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package adminssh
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+const prompt = "fish-admin> "
+
+// session is the admin ssh session channel
+type session struct {
+	server *adminSSH
+	conn   *ssh.ServerConn
+	ch     ssh.Channel
+	pty    bool
+}
+
+func (s *session) serve(reqs <-chan *ssh.Request) {
+	defer s.ch.Close()
+
+	for req := range reqs {
+		switch req.Type {
+		case "pty-req":
+			s.pty = true
+			req.Reply(true, nil)
+		case "env", "window-change":
+			req.Reply(true, nil)
+		case "shell":
+			req.Reply(true, nil)
+			s.shell()
+			s.exit(0)
+			return
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			code, _ := s.execute(payload.Command)
+			s.exit(code)
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// exit sends the command exit status to the client
+func (s *session) exit(code int) {
+	s.ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(code)}))
+}
+
+// Write sends the output to the client, the terminal needs CRLF line endings
+func (s *session) Write(p []byte) (int, error) {
+	if s.pty {
+		if _, err := s.ch.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	return s.ch.Write(p)
+}
+
+// shell runs the interactive commands loop
+func (s *session) shell() {
+	io.WriteString(s, "Aquarium Fish admin console, all the actions are audited. Type \"help\" for commands.\n")
+	for {
+		io.WriteString(s, prompt)
+		line, err := s.readLine()
+		if err != nil {
+			if err != io.EOF {
+				log.Debugf("ADMINSSH: %s: Unable to read input: %v", s.conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, quit := s.execute(line); quit {
+			return
+		}
+	}
+}
+
+// readLine reads the input line, in pty mode it echoes the input and handles simple editing
+func (s *session) readLine() (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		if _, err := s.ch.Read(buf); err != nil {
+			return "", err
+		}
+		switch c := buf[0]; {
+		case c == '\r' || c == '\n':
+			if s.pty {
+				s.ch.Write([]byte("\r\n"))
+			}
+			return string(line), nil
+		case c == 0x7f || c == 0x08: // Backspace
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				if s.pty {
+					s.ch.Write([]byte("\b \b"))
+				}
+			}
+		case c == 0x03: // Ctrl-C drops the line
+			if s.pty {
+				s.ch.Write([]byte("^C\r\n" + prompt))
+			}
+			line = line[:0]
+		case c == 0x04: // Ctrl-D on empty line exits
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case c >= 0x20:
+			line = append(line, c)
+			if s.pty {
+				s.ch.Write(buf)
+			}
+		}
+	}
+}
+
+// waitInterrupt returns channel which is closed when user pressed Ctrl-C or closed the input
+func (s *session) waitInterrupt() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 1)
+		for {
+			if _, err := s.ch.Read(buf); err != nil || buf[0] == 0x03 || buf[0] == 0x04 {
+				return
+			}
+		}
+	}()
+	return done
+}
@@ -0,0 +1,177 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// UserMe returns the current User
+func (c *Client) UserMe() (out *types.User, err error) {
+	out = &types.User{}
+	err = c.Do(http.MethodGet, "api/v1/user/me/", nil, nil, out)
+	return out, err
+}
+
+// LabelList returns the Labels, filter is optional SQL WHERE expression
+func (c *Client) LabelList(filter string) (out []types.Label, err error) {
+	query := url.Values{}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	err = c.Do(http.MethodGet, "api/v1/label/", query, nil, &out)
+	return out, err
+}
+
+// LabelGetLatest returns the latest version of the Label by name
+func (c *Client) LabelGetLatest(name string) (*types.Label, error) {
+	labels, err := c.LabelList(fmt.Sprintf("name = '%s'", strings.ReplaceAll(name, "'", "''")))
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("Client: Label %q not found", name)
+	}
+	latest := slices.MaxFunc(labels, func(a, b types.Label) int { return a.Version - b.Version })
+	return &latest, nil
+}
+
+// LabelCreate creates the Label
+func (c *Client) LabelCreate(label *types.Label) (out *types.Label, err error) {
+	out = &types.Label{}
+	err = c.Do(http.MethodPost, "api/v1/label/", nil, label, out)
+	return out, err
+}
+
+// LabelApply makes the cluster Labels match the provided set
+func (c *Client) LabelApply(req *types.LabelApply) (out *types.LabelApplyResult, err error) {
+	out = &types.LabelApplyResult{}
+	err = c.Do(http.MethodPost, "api/v1/label/apply", nil, req, out)
+	return out, err
+}
+
+// ApplicationList returns the Applications, filter is optional SQL WHERE expression and the
+// selector is optional set of common properties to match
+func (c *Client) ApplicationList(filter string, sel *types.ApplicationSelector) (out []types.Application, err error) {
+	query := url.Values{}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	if sel != nil {
+		if sel.OwnerName != nil {
+			query.Set("owner_name", *sel.OwnerName)
+		}
+		if sel.LabelUID != nil {
+			query.Set("label_uid", sel.LabelUID.String())
+		}
+		if sel.Status != nil {
+			query.Set("status", string(*sel.Status))
+		}
+		if sel.OlderThan != nil {
+			query.Set("older_than", *sel.OlderThan)
+		}
+		if sel.NodeUID != nil {
+			query.Set("node_uid", sel.NodeUID.String())
+		}
+	}
+	err = c.Do(http.MethodGet, "api/v1/application/", query, nil, &out)
+	return out, err
+}
+
+// ApplicationCreate requests the new Application
+func (c *Client) ApplicationCreate(app *types.Application) (out *types.Application, err error) {
+	out = &types.Application{}
+	err = c.Do(http.MethodPost, "api/v1/application/", nil, app, out)
+	return out, err
+}
+
+// ApplicationStateGet returns the current state of the Application
+func (c *Client) ApplicationStateGet(uid types.ApplicationUID) (out *types.ApplicationState, err error) {
+	out = &types.ApplicationState{}
+	err = c.Do(http.MethodGet, "api/v1/application/"+uid.String()+"/state", nil, nil, out)
+	return out, err
+}
+
+// ApplicationFullGet returns the Application with the current state, Resource and Label name
+func (c *Client) ApplicationFullGet(uid types.ApplicationUID) (out *types.ApplicationFull, err error) {
+	out = &types.ApplicationFull{}
+	err = c.Do(http.MethodGet, "api/v1/application/"+uid.String()+"/full", nil, nil, out)
+	return out, err
+}
+
+// ApplicationResourceGet returns the Resource of the allocated Application
+func (c *Client) ApplicationResourceGet(uid types.ApplicationUID) (out *types.Resource, err error) {
+	out = &types.Resource{}
+	err = c.Do(http.MethodGet, "api/v1/application/"+uid.String()+"/resource", nil, nil, out)
+	return out, err
+}
+
+// ApplicationDeallocate requests the Application deallocation
+func (c *Client) ApplicationDeallocate(uid types.ApplicationUID) (out *types.ApplicationState, err error) {
+	out = &types.ApplicationState{}
+	err = c.Do(http.MethodGet, "api/v1/application/"+uid.String()+"/deallocate", nil, nil, out)
+	return out, err
+}
+
+// ApplicationDeallocateBatch releases the Resources of all the Applications matching selector
+func (c *Client) ApplicationDeallocateBatch(req *types.ApplicationDeallocateBatch) (out *types.ApplicationDeallocateBatchResult, err error) {
+	out = &types.ApplicationDeallocateBatchResult{}
+	err = c.Do(http.MethodPost, "api/v1/application/deallocate", nil, req, out)
+	return out, err
+}
+
+// ApplicationAwait polls the Application state until it gets one of the statuses or timeout
+func (c *Client) ApplicationAwait(uid types.ApplicationUID, statuses []types.ApplicationStatus, timeout, interval time.Duration) (*types.ApplicationState, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		state, err := c.ApplicationStateGet(uid)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(statuses, state.Status) {
+			return state, nil
+		}
+		// The Application will not change the state anymore
+		if state.Status == types.ApplicationStatusERROR || state.Status == types.ApplicationStatusDEALLOCATED {
+			return state, fmt.Errorf("Client: Application %s is %s: %s", uid, state.Status, state.Description)
+		}
+		if time.Now().After(deadline) {
+			return state, fmt.Errorf("Client: Timeout waiting for Application %s, current status is %s", uid, state.Status)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// ResourceAccess requests the credentials to access the Resource, otp is optional
+func (c *Client) ResourceAccess(uid types.ResourceUID, otp string) (out *types.ResourceAccess, err error) {
+	query := url.Values{}
+	if otp != "" {
+		query.Set("otp", otp)
+	}
+	out = &types.ResourceAccess{}
+	err = c.Do(http.MethodGet, "api/v1/resource/"+uid.String()+"/access", query, nil, out)
+	return out, err
+}
+
+// NodeList returns the cluster Nodes
+func (c *Client) NodeList() (out []types.Node, err error) {
+	err = c.Do(http.MethodGet, "api/v1/node/", nil, nil, &out)
+	return out, err
+}
@@ -0,0 +1,233 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package client implements the Fish API client used by fishctl and the external tools
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config describes how to connect to the Fish cluster
+type Config struct {
+	URL      string `json:"url"`      // Fish API address like "https://fish.example.com:8001"
+	Username string `json:"username"` // User name
+	Password string `json:"password"` // User password or API token
+	CACert   string `json:"ca_cert"`  // Path to the CA certificate to verify the Fish node, system CAs if empty
+	Insecure bool   `json:"insecure"` // Do not verify the Fish node certificate
+	Retries  int    `json:"retries"`  // How many times to retry the temporary failed requests, 3 by default, negative disables
+}
+
+// Client executes the Fish API requests
+type Client struct {
+	cfg    Config
+	http   *http.Client
+	stream *http.Client // Without timeout to keep the streams open
+}
+
+// Error is returned when Fish responded with not successful status
+type Error struct {
+	Status     int
+	Message    string
+	RetryAfter time.Duration // When the request could be repeated if Fish asked for it
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Fish API returned %d: %s", e.Status, e.Message)
+}
+
+// Temporary returns true if the same request could succeed later
+func (e *Error) Temporary() bool {
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsStatus checks the error is the Fish API error with the status
+func IsStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
+
+// IsNotFound checks the error is about the not existing object
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized checks the error is about the wrong credentials
+func IsUnauthorized(err error) bool {
+	return IsStatus(err, http.StatusUnauthorized)
+}
+
+// IsRateLimited checks the error is about the exceeded rate limit
+func IsRateLimited(err error) bool {
+	return IsStatus(err, http.StatusTooManyRequests)
+}
+
+// New creates the client for the provided config
+func New(cfg Config) (*Client, error) {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("Client: Fish URL is not set")
+	}
+	if cfg.Username == "" {
+		return nil, fmt.Errorf("Client: Username is not set")
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = 3
+	}
+
+	// #nosec G402 - insecure mode is explicitly requested by the user
+	tlsCfg := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("Client: Unable to read CA certificate: %v", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Client: No certificates found in %s", cfg.CACert)
+		}
+	}
+
+	transport := &http.Transport{TLSClientConfig: tlsCfg}
+	return &Client{
+		cfg: cfg,
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		stream: &http.Client{Transport: transport},
+	}, nil
+}
+
+// newRequest prepares the authenticated request to the Fish API
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Request, error) {
+	u := c.cfg.URL + "/" + strings.TrimPrefix(path, "/")
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("Client: Unable to create request: %v", err)
+	}
+	req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// responseError creates the API error from the not successful response
+func responseError(resp *http.Response, data []byte) *Error {
+	apiErr := &Error{Status: resp.StatusCode}
+	var msg struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &msg) == nil && msg.Message != "" {
+		apiErr.Message = msg.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && sec > 0 {
+		apiErr.RetryAfter = time.Duration(sec) * time.Second
+	}
+	return apiErr
+}
+
+// Do executes the request with json body in and parses the json response into out, both could
+// be nil. The query could be nil too. The temporary failures are retried with backoff and every
+// attempt is sent with the same Idempotency-Key, so Fish replies with the result of the already
+// processed request instead of repeating it. The key is sent for GET too, because some of the GET
+// routes are changing the state (like deallocate or maintenance) and Fish ignores it for the rest.
+func (c *Client) Do(method, path string, query url.Values, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("Client: Unable to encode request: %v", err)
+		}
+	}
+	key := uuid.NewString()
+
+	for attempt := 0; ; attempt++ {
+		err := c.do(method, path, query, key, body, out)
+		if err == nil || attempt >= c.cfg.Retries {
+			return err
+		}
+		delay := util.Backoff(attempt, 500*time.Millisecond, 10*time.Second)
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			if !apiErr.Temporary() {
+				return err
+			}
+			if apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+		}
+		time.Sleep(delay)
+	}
+}
+
+// do executes the request once, the key is sent as Idempotency-Key if it's not empty
+func (c *Client) do(method, path string, query url.Values, key string, body []byte, out any) error {
+	req, err := c.newRequest(context.Background(), method, path, query, body)
+	if err != nil {
+		return err
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("Client: Request %s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024*1024))
+	if err != nil {
+		return fmt.Errorf("Client: Unable to read response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(resp, data)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("Client: Unable to parse response: %v", err)
+		}
+	}
+	return nil
+}
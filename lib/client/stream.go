@@ -0,0 +1,160 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// ApplicationStream calls fn for every Application state event till the ctx is done or fn returns
+// error. When the stream is closed the client reconnects with backoff and resumes from the last
+// received event. If the node is not able to resume, the events sent during the reconnect are
+// lost, so fn gets nil event every time the stream is connected from scratch to re-check the
+// state it's waiting for. The filter (could be nil) is passed to the node to receive only the
+// matching events, like `application_uid`, `label_uid`, `owner_name` or `status`.
+func (c *Client) ApplicationStream(ctx context.Context, filter url.Values, fn func(event *types.ApplicationStateEvent) error) error {
+	var lastSeq uint64
+	for attempt := 0; ; attempt++ {
+		connected, err := c.applicationStreamOnce(ctx, filter, &lastSeq, fn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusGone {
+			// Not able to resume, so starting from scratch right away
+			lastSeq = 0
+			continue
+		}
+		if apiErr != nil && !apiErr.Temporary() {
+			// Wrong credentials or permissions will not change with reconnect
+			return err
+		}
+		var fnErr *streamHandlerError
+		if errors.As(err, &fnErr) {
+			return fnErr.err
+		}
+		if connected {
+			attempt = 0
+		}
+		delay := util.Backoff(attempt, 500*time.Millisecond, 30*time.Second)
+		if apiErr != nil && apiErr.RetryAfter > 0 {
+			delay = apiErr.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// streamHandlerError wraps the error returned by the stream handler to stop reconnecting
+type streamHandlerError struct {
+	err error
+}
+
+func (e *streamHandlerError) Error() string {
+	return e.err.Error()
+}
+
+// applicationStreamOnce reads the stream till it's closed, returns true if it was connected. The
+// lastSeq is used to resume and updated with every received event.
+func (c *Client) applicationStreamOnce(ctx context.Context, filter url.Values, lastSeq *uint64, fn func(event *types.ApplicationStateEvent) error) (bool, error) {
+	query := url.Values{}
+	for key, values := range filter {
+		query[key] = values
+	}
+	if *lastSeq > 0 {
+		query.Set("resume_from", strconv.FormatUint(*lastSeq, 10))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "api/v1/application/stream", query, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.stream.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("Client: Unable to connect the Application stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+		return false, responseError(resp, data)
+	}
+
+	if *lastSeq == 0 {
+		if err = fn(nil); err != nil {
+			return true, &streamHandlerError{err}
+		}
+	}
+	dec := json.NewDecoder(resp.Body)
+	for {
+		event := &types.ApplicationStateEvent{}
+		if err = dec.Decode(event); err != nil {
+			return true, fmt.Errorf("Client: Application stream is closed: %v", err)
+		}
+		*lastSeq = event.Seq
+		if err = fn(event); err != nil {
+			return true, &streamHandlerError{err}
+		}
+	}
+}
+
+// ApplicationWait waits for the Application to get one of the statuses using the stream, fails
+// when the Application is completed with the other status or the ctx is done
+func (c *Client) ApplicationWait(ctx context.Context, uid types.ApplicationUID, statuses []types.ApplicationStatus) (*types.ApplicationState, error) {
+	var out *types.ApplicationState
+	found := errors.New("found")
+	check := func(state *types.ApplicationState) error {
+		if slices.Contains(statuses, state.Status) {
+			out = state
+			return found
+		}
+		// The Application will not change the state anymore
+		if state.Status == types.ApplicationStatusERROR || state.Status == types.ApplicationStatusDEALLOCATED {
+			out = state
+			return fmt.Errorf("Client: Application %s is %s: %s", uid, state.Status, state.Description)
+		}
+		return nil
+	}
+	filter := url.Values{"application_uid": {uid.String()}}
+	err := c.ApplicationStream(ctx, filter, func(event *types.ApplicationStateEvent) error {
+		if event == nil {
+			// Just connected, so the state could be changed while the stream was not listening
+			state, err := c.ApplicationStateGet(uid)
+			if err != nil {
+				return err
+			}
+			return check(state)
+		}
+		if event.State.ApplicationUID != uid || event.Annotation != nil || event.Task != nil {
+			return nil
+		}
+		return check(&event.State)
+	})
+	if errors.Is(err, found) {
+		return out, nil
+	}
+	return out, err
+}
@@ -0,0 +1,223 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// Envelope encryption of the sensitive fields stored in the database: the values are encrypted
+// with AES-256-GCM data key, which is stored next to the value wrapped by the node master key.
+// The master key never leaves the file or KMS, the data key is generated once per process start
+// and the unwrapped data keys are cached, so KMS is not called for every value.
+
+// Prefix of the enveloped value: $enc1$<wrapped data key>$<nonce+ciphertext>
+const envelopePrefix = "$enc1$"
+
+// MasterKey wraps and unwraps the envelope data keys
+type MasterKey interface {
+	Wrap(dataKey []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+var envelope struct {
+	sync.RWMutex
+	master  MasterKey
+	key     []byte
+	wrapped string
+	keys    map[string][]byte // Cache of the unwrapped data keys
+}
+
+// EnvelopeInit sets the master key and generates the new data key, nil disables encryption
+func EnvelopeInit(master MasterKey) error {
+	envelope.Lock()
+	defer envelope.Unlock()
+
+	envelope.master = master
+	envelope.keys = make(map[string][]byte)
+	if master == nil {
+		envelope.key, envelope.wrapped = nil, ""
+		return nil
+	}
+
+	key := RandBytes(32)
+	wrapped, err := master.Wrap(key)
+	if err != nil {
+		return fmt.Errorf("Crypt: Unable to wrap the data key: %v", err)
+	}
+	envelope.key = key
+	envelope.wrapped = base64.StdEncoding.EncodeToString(wrapped)
+	envelope.keys[envelope.wrapped] = key
+
+	return nil
+}
+
+// EnvelopeEnabled checks the master key is set and the values are actually encrypted
+func EnvelopeEnabled() bool {
+	envelope.RLock()
+	defer envelope.RUnlock()
+	return envelope.key != nil
+}
+
+// IsEnveloped checks the value is encrypted by EnvelopeEncrypt
+func IsEnveloped(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// EnvelopeEncrypt encrypts the value, returns it as is if it's empty, already encrypted or the
+// encryption is disabled
+func EnvelopeEncrypt(value string) (string, error) {
+	envelope.RLock()
+	key, wrapped := envelope.key, envelope.wrapped
+	envelope.RUnlock()
+	if key == nil || value == "" || IsEnveloped(value) {
+		return value, nil
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := RandBytes(gcm.NonceSize())
+	data := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return envelopePrefix + wrapped + "$" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// EnvelopeDecrypt decrypts the value encrypted by EnvelopeEncrypt, the plaintext is returned as is
+func EnvelopeDecrypt(value string) (string, error) {
+	if !IsEnveloped(value) {
+		return value, nil
+	}
+	wrapped, encoded, ok := strings.Cut(strings.TrimPrefix(value, envelopePrefix), "$")
+	if !ok {
+		return "", fmt.Errorf("Crypt: Wrong format of the encrypted value")
+	}
+
+	key, err := envelopeDataKey(wrapped)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("Crypt: Unable to decode the encrypted value: %v", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("Crypt: Encrypted value is too short")
+	}
+	out, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("Crypt: Unable to decrypt the value: %v", err)
+	}
+
+	return string(out), nil
+}
+
+// envelopeDataKey returns the cached data key or unwraps it with the master key
+func envelopeDataKey(wrapped string) ([]byte, error) {
+	envelope.RLock()
+	key, master := envelope.keys[wrapped], envelope.master
+	envelope.RUnlock()
+	if key != nil {
+		return key, nil
+	}
+	if master == nil {
+		return nil, fmt.Errorf("Crypt: Unable to decrypt the value: master key is not set")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to decode the data key: %v", err)
+	}
+	if key, err = master.Unwrap(data); err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to unwrap the data key: %v", err)
+	}
+
+	envelope.Lock()
+	envelope.keys[wrapped] = key
+	envelope.Unlock()
+
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to create cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// fileMasterKey is the master key stored in the local file
+type fileMasterKey struct {
+	key []byte
+}
+
+// NewFileMasterKey loads the master key from file, the new key is generated if file not exists
+func NewFileMasterKey(path string) (MasterKey, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Info("Crypt: Generating master key:", path)
+		data = []byte(base64.StdEncoding.EncodeToString(RandBytes(32)))
+		if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("Crypt: Unable to create master key directory: %v", err)
+		}
+		if err = os.WriteFile(path, data, 0o600); err != nil {
+			return nil, fmt.Errorf("Crypt: Unable to write master key: %v", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to read master key: %v", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("Crypt: Master key should be base64-encoded 32 bytes: %s", path)
+	}
+
+	return &fileMasterKey{key: key}, nil
+}
+
+// Wrap encrypts the data key with the master key
+func (m *fileMasterKey) Wrap(dataKey []byte) ([]byte, error) {
+	gcm, err := newGCM(m.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := RandBytes(gcm.NonceSize())
+	return gcm.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// Unwrap decrypts the data key with the master key
+func (m *fileMasterKey) Unwrap(wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(m.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("Crypt: Wrapped data key is too short")
+	}
+	return gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_envelope_encrypt_decrypt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "master.key")
+	master, err := NewFileMasterKey(path)
+	if err != nil {
+		t.Fatalf("Unable to create master key: %v", err)
+	}
+	if err = EnvelopeInit(master); err != nil {
+		t.Fatalf("Unable to init envelope: %v", err)
+	}
+	defer EnvelopeInit(nil)
+
+	enc, err := EnvelopeEncrypt("sup3r-secret")
+	if err != nil {
+		t.Fatalf("Unable to encrypt: %v", err)
+	}
+	if !IsEnveloped(enc) || strings.Contains(enc, "sup3r-secret") {
+		t.Fatalf("Value is not encrypted: %q", enc)
+	}
+	if again, _ := EnvelopeEncrypt(enc); again != enc {
+		t.Fatalf("Encrypted value should not be encrypted twice")
+	}
+	if empty, _ := EnvelopeEncrypt(""); empty != "" {
+		t.Fatalf("Empty value should stay empty: %q", empty)
+	}
+	if plain, err := EnvelopeDecrypt("plaintext"); err != nil || plain != "plaintext" {
+		t.Fatalf("Plaintext value should be returned as is: %q, %v", plain, err)
+	}
+
+	// The new process loads the same key from file and unwraps the data key of the previous one
+	master, err = NewFileMasterKey(path)
+	if err != nil {
+		t.Fatalf("Unable to load master key: %v", err)
+	}
+	if err = EnvelopeInit(master); err != nil {
+		t.Fatalf("Unable to init envelope: %v", err)
+	}
+	if dec, err := EnvelopeDecrypt(enc); err != nil || dec != "sup3r-secret" {
+		t.Fatalf("Wrong decrypted value: %q, %v", dec, err)
+	}
+
+	// Other master key can't unwrap the data key
+	other, err := NewFileMasterKey(filepath.Join(t.TempDir(), "other.key"))
+	if err != nil {
+		t.Fatalf("Unable to create master key: %v", err)
+	}
+	EnvelopeInit(other)
+	if _, err := EnvelopeDecrypt(enc); err == nil {
+		t.Fatalf("Value should not be decrypted with other master key")
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Secrets channel to the Resources: the Resource generates the key pair and shares only the
+// public key through Meta API, the clients encrypt the secrets with it and Fish just relays the
+// ciphertext, so the plaintext never gets to the Fish database or logs.
+// It's a NaCl anonymous sealed box (X25519 + XSalsa20-Poly1305), compatible with libsodium
+// `crypto_box_seal`, so the Resource side could be implemented with any available tool.
+
+// SecretKeyGenerate creates the new base64-encoded key pair to receive the secrets
+func SecretKeyGenerate() (publicKey, privateKey string, err error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub[:]), base64.StdEncoding.EncodeToString(priv[:]), nil
+}
+
+// SecretKeyValidate checks the base64-encoded public key is usable
+func SecretKeyValidate(publicKey string) error {
+	_, err := decodeSecretKey(publicKey)
+	return err
+}
+
+// SecretSeal encrypts the data with base64-encoded public key and returns base64 ciphertext
+func SecretSeal(publicKey string, data []byte) (string, error) {
+	pub, err := decodeSecretKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	out, err := box.SealAnonymous(nil, data, pub, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// SecretOpen decrypts the base64 ciphertext with the base64-encoded key pair
+func SecretOpen(publicKey, privateKey, ciphertext string) ([]byte, error) {
+	pub, err := decodeSecretKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := decodeSecretKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to decode ciphertext: %v", err)
+	}
+	out, ok := box.OpenAnonymous(nil, data, pub, priv)
+	if !ok {
+		return nil, fmt.Errorf("Crypt: Unable to decrypt the secret")
+	}
+	return out, nil
+}
+
+func decodeSecretKey(key string) (*[32]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to decode secret key: %v", err)
+	}
+	if len(data) != 32 {
+		return nil, fmt.Errorf("Crypt: Wrong secret key length: %d != 32", len(data))
+	}
+	var out [32]byte
+	copy(out[:], data)
+	return &out, nil
+}
@@ -0,0 +1,49 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"testing"
+)
+
+// Make sure the secret sealed with the public key could be opened only with the right key pair
+func Test_secret_seal_open(t *testing.T) {
+	pub, priv, err := SecretKeyGenerate()
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	if err := SecretKeyValidate(pub); err != nil {
+		t.Fatalf("Generated public key is not valid: %v", err)
+	}
+	if err := SecretKeyValidate("dGVzdA=="); err == nil {
+		t.Fatalf("Short public key should not be valid")
+	}
+
+	ciphertext, err := SecretSeal(pub, []byte("top secret"))
+	if err != nil {
+		t.Fatalf("Unable to seal: %v", err)
+	}
+
+	data, err := SecretOpen(pub, priv, ciphertext)
+	if err != nil {
+		t.Fatalf("Unable to open: %v", err)
+	}
+	if string(data) != "top secret" {
+		t.Fatalf("Wrong decrypted secret: %q", data)
+	}
+
+	pub2, priv2, _ := SecretKeyGenerate()
+	if _, err := SecretOpen(pub2, priv2, ciphertext); err == nil {
+		t.Fatalf("Secret should not be opened with another key")
+	}
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// SignKey is the Ed25519 key to sign the exported objects, so the other cluster could verify
+// they were not changed on the way. Only the public key is shared with the trusting clusters.
+type SignKey struct {
+	key ed25519.PrivateKey
+}
+
+// NewSignKey creates the key from base64-encoded 32 bytes seed
+func NewSignKey(seed string) (*SignKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(seed))
+	if err != nil || len(data) != ed25519.SeedSize {
+		return nil, fmt.Errorf("Crypt: Sign key should be base64-encoded %d bytes", ed25519.SeedSize)
+	}
+	return &SignKey{key: ed25519.NewKeyFromSeed(data)}, nil
+}
+
+// NewFileSignKey loads the sign key from file, the new key is generated if file not exists
+func NewFileSignKey(path string) (*SignKey, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Info("Crypt: Generating sign key:", path)
+		data = []byte(base64.StdEncoding.EncodeToString(RandBytes(ed25519.SeedSize)))
+		if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("Crypt: Unable to create sign key directory: %v", err)
+		}
+		if err = os.WriteFile(path, data, 0o600); err != nil {
+			return nil, fmt.Errorf("Crypt: Unable to write sign key: %v", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("Crypt: Unable to read sign key: %v", err)
+	}
+
+	key, err := NewSignKey(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, path)
+	}
+	return key, nil
+}
+
+// Public returns the base64-encoded public key to verify the signatures
+func (k *SignKey) Public() string {
+	return base64.StdEncoding.EncodeToString(k.key.Public().(ed25519.PublicKey))
+}
+
+// Sign returns the base64-encoded signature of the data
+func (k *SignKey) Sign(data []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(k.key, data))
+}
+
+// SignVerify checks the base64-encoded signature of the data is made by the public key
+func SignVerify(publicKey string, data []byte, signature string) error {
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("Crypt: Public key should be base64-encoded %d bytes", ed25519.PublicKeySize)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("Crypt: Unable to decode signature: %v", err)
+	}
+	if !ed25519.Verify(pub, data, sig) {
+		return fmt.Errorf("Crypt: Signature is not valid")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Make sure the signature is verified only with the right key and the same data
+func Test_sign_verify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sign.key")
+	key, err := NewFileSignKey(path)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	// The second load should read the same key
+	loaded, err := NewFileSignKey(path)
+	if err != nil {
+		t.Fatalf("Unable to load key: %v", err)
+	}
+	if loaded.Public() != key.Public() {
+		t.Fatalf("Loaded key is different: %s != %s", loaded.Public(), key.Public())
+	}
+
+	sig := key.Sign([]byte("bundle"))
+	if err := SignVerify(key.Public(), []byte("bundle"), sig); err != nil {
+		t.Fatalf("Signature is not verified: %v", err)
+	}
+	if err := SignVerify(key.Public(), []byte("changed"), sig); err == nil {
+		t.Fatalf("Signature of the changed data should not be verified")
+	}
+
+	other, _ := NewSignKey("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	if err := SignVerify(other.Public(), []byte("bundle"), sig); err == nil {
+		t.Fatalf("Signature should not be verified with another key")
+	}
+	if _, err := NewSignKey("dGVzdA=="); err == nil {
+		t.Fatalf("Short key should not be valid")
+	}
+}
@@ -18,11 +18,16 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"net"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 )
 
+var errHostKeyReceived = errors.New("host key received")
+
 // GenerateSSHKey creates a private key in pem format
 func GenerateSSHKey() ([]byte, error) {
 	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
@@ -61,3 +66,37 @@ func GetSSHPubKeyFromPem(privPem []byte) ([]byte, error) {
 
 	return ssh.MarshalAuthorizedKey(publicKey), nil
 }
+
+// GetSSHHostKeyFingerprint connects to the SSH server and returns SHA256 fingerprint of its host key
+// The authentication is not happening - connection is closed right after the key exchange
+func GetSSHHostKeyFingerprint(addr string, timeout time.Duration) (string, error) {
+	var fingerprint string
+	config := &ssh.ClientConfig{
+		User: "fingerprint",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintSHA256(key)
+			return errHostKeyReceived
+		},
+		Timeout: timeout,
+	}
+	conn, err := ssh.Dial("tcp", addr, config)
+	if conn != nil {
+		conn.Close()
+	}
+	if fingerprint != "" {
+		return fingerprint, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("Host key was not received")
+	}
+	return "", err
+}
+
+// GetSSHPubKeyFingerprint returns SHA256 fingerprint of the public key in authorized_keys format
+func GetSSHPubKeyFingerprint(pubkey []byte) (string, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey(pubkey)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(key), nil
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package crypt
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Make sure the TOTP codes are matching the RFC 6238 SHA1 test vectors
+func Test_totp_rfc6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for ts, expected := range vectors {
+		code, err := TOTPCode(secret, TOTPCounter(time.Unix(ts, 0)))
+		if err != nil {
+			t.Fatalf("Unable to generate code: %v", err)
+		}
+		if code != expected {
+			t.Fatalf("Wrong code for %d: %s != %s", ts, code, expected)
+		}
+	}
+}
+
+// Make sure the code is valid only in the skew window
+func Test_totp_validate(t *testing.T) {
+	secret, err := TOTPSecretGenerate()
+	if err != nil {
+		t.Fatalf("Unable to generate secret: %v", err)
+	}
+	now := time.Now()
+	code, _ := TOTPCode(secret, TOTPCounter(now))
+
+	if counter, ok := TOTPValidate(secret, code, now); !ok || counter != TOTPCounter(now) {
+		t.Fatalf("Current code should be valid")
+	}
+	if _, ok := TOTPValidate(strings.ToLower(secret), code, now.Add(TOTPPeriod*time.Second)); !ok {
+		t.Fatalf("Previous period code should be valid")
+	}
+	if _, ok := TOTPValidate(secret, code, now.Add(3*TOTPPeriod*time.Second)); ok {
+		t.Fatalf("Old code should not be valid")
+	}
+	if _, ok := TOTPValidate(secret, "12345", now); ok {
+		t.Fatalf("Short code should not be valid")
+	}
+
+	uri := TOTPURI("Aquarium Fish", "user", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Aquarium%20Fish:user?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("Wrong otpauth URI: %s", uri)
+	}
+}
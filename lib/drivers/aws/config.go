@@ -17,10 +17,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"golang.org/x/net/http/httpproxy"
 
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/util"
@@ -35,16 +40,42 @@ type Config struct {
 	// Optional
 	AccountIDs   []string          `json:"account_ids"`   // AWS Trusted account IDs to filter vpc, subnet, sg, images, snapshots...
 	InstanceTags map[string]string `json:"instance_tags"` // AWS Instance tags to use when this node provision them
+	Metadata     MetadataOptions   `json:"metadata"`      // Default instance metadata service options, could be overridden by label
+
+	// Network access to the AWS API
+	Endpoints EndpointsConfig `json:"endpoints"` // Custom endpoints of the AWS services, used only for the driver region
+	Proxy     string          `json:"proxy"`     // HTTP(S) proxy URL for the AWS requests, HTTPS_PROXY/HTTP_PROXY env vars are used if not set
+	NoProxy   []string        `json:"no_proxy"`  // Hosts or domains to reach without proxy, NO_PROXY env var is used if not set
 
 	// Manage the AWS dedicated hosts to keep them busy and deallocate when not needed
 	// Key of the map is name of the pool - will be used for identification of the pool
 	DedicatedPool map[string]DedicatedPoolRecord `json:"dedicated_pool"`
 
+	// On-demand hourly price of the instance types to estimate the Resources cost (example:
+	// {"c6a.4xlarge": 0.612}). The static table is used instead of AWS Price List API, because
+	// it's served only from a few regions and returns the public prices without the discounts.
+	Prices map[string]float64 `json:"prices"`
+
 	// Various options to not hardcode the important numbers
 	SnapshotCreateWait util.Duration `json:"snapshot_create_wait"` // Maximum wait time for snapshot availability (create), default: 2h
 	ImageCreateWait    util.Duration `json:"image_create_wait"`    // Maximum wait time for image availability (create/copy), default: 2h
 }
 
+// EndpointsConfig allows to override the AWS services endpoints, for example to use the VPC
+// interface endpoints (private link) when the node has no access to the public AWS API
+//
+// Example:
+//
+//	endpoints:
+//	  ec2: https://vpce-0123456789abcdef0-abcdefgh.ec2.us-west-2.vpce.amazonaws.com
+//	  sts: https://vpce-0123456789abcdef0-ijklmnop.sts.us-west-2.vpce.amazonaws.com
+type EndpointsConfig struct {
+	EC2           string `json:"ec2"`           // Elastic Compute Cloud endpoint URL
+	STS           string `json:"sts"`           // Security Token Service endpoint URL
+	KMS           string `json:"kms"`           // Key Management Service endpoint URL
+	ServiceQuotas string `json:"servicequotas"` // Service Quotas endpoint URL
+}
+
 // DedicatedPoolRecord stores the configuration of AWS dedicated pool of particular type to manage
 // aws ec2 allocate-hosts --availability-zone "us-west-2c" --auto-placement "on" --host-recovery "off" --host-maintenance "off" --quantity 1 --instance-type "mac2.metal"
 type DedicatedPoolRecord struct {
@@ -80,6 +111,10 @@ type DedicatedPoolRecord struct {
 	// [24 hours]: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ec2-mac-instances.html#mac-instance-considerations
 	// [scrubbing process]: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/mac-instance-stop.html
 	ScrubbingDelay util.Duration `json:"scrubbing_delay"`
+
+	// Expected duration of the scrubbing process to forecast when the host will be available
+	// again, the Applications could wait for it instead of failing. Default: 2h
+	ScrubbingDuration util.Duration `json:"scrubbing_duration"`
 }
 
 // Apply takes json and applies it to the config structure
@@ -108,21 +143,28 @@ func (c *Config) Validate() (err error) {
 		return fmt.Errorf("AWS: Credentials SecretKey is not set")
 	}
 
-	// Verify that connection is possible with those creds and get the account ID
-	conn := sts.NewFromConfig(aws.Config{
-		Region: c.Region,
-		Credentials: aws.CredentialsProviderFunc(func(_ /*ctx*/ context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     c.KeyID,
-				SecretAccessKey: c.SecretKey,
-				Source:          "fish-cfg",
-			}, nil
-		}),
+	for name, endpoint := range map[string]string{"ec2": c.Endpoints.EC2, "sts": c.Endpoints.STS, "kms": c.Endpoints.KMS, "servicequotas": c.Endpoints.ServiceQuotas} {
+		if endpoint == "" {
+			continue
+		}
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("AWS: Invalid %s endpoint URL: %q", name, endpoint)
+		}
+	}
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("AWS: Invalid proxy URL: %q", c.Proxy)
+		}
+	}
+	for instType, price := range c.Prices {
+		if price < 0 {
+			return fmt.Errorf("AWS: Price of %q can't be negative: %v", instType, price)
+		}
+	}
 
-		// Using retries in order to handle the transient errors:
-		// https://docs.aws.amazon.com/prescriptive-guidance/latest/cloud-design-patterns/retry-backoff.html
-		RetryMaxAttempts: 3,
-		RetryMode:        aws.RetryModeStandard,
+	// Verify that connection is possible with those creds and get the account ID
+	conn := sts.NewFromConfig(c.awsConfig(c.Region, 3), func(o *sts.Options) {
+		o.BaseEndpoint = c.endpoint(c.Region, c.Endpoints.STS)
 	})
 	input := &sts.GetCallerIdentityInput{}
 
@@ -163,11 +205,18 @@ func (c *Config) Validate() (err error) {
 	if c.DedicatedPool == nil {
 		c.DedicatedPool = make(map[string]DedicatedPoolRecord)
 	}
+	if err := c.Metadata.Validate(); err != nil {
+		return err
+	}
 	// Make sure the ScrubbingDelay either unset or >= 1min or we will face often update API reqs
 	for name, pool := range c.DedicatedPool {
 		if pool.ScrubbingDelay > 0 && time.Duration(pool.ScrubbingDelay) < 1*time.Minute {
 			return fmt.Errorf("AWS: Scrubbing delay of pool %q is less then 1 minute: %v", name, pool.ScrubbingDelay)
 		}
+		if pool.ScrubbingDuration <= 0 {
+			pool.ScrubbingDuration = util.Duration(2 * time.Hour)
+			c.DedicatedPool[name] = pool
+		}
 	}
 
 	// Set defaults for other variables
@@ -180,3 +229,61 @@ func (c *Config) Validate() (err error) {
 
 	return nil
 }
+
+// awsConfig returns the SDK config with the driver credentials and proxy for the service clients
+func (c *Config) awsConfig(region string, retries int) aws.Config {
+	cfg := aws.Config{
+		Region: region,
+		Credentials: aws.CredentialsProviderFunc(func(_ /*ctx*/ context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     c.KeyID,
+				SecretAccessKey: c.SecretKey,
+				Source:          "fish-cfg",
+			}, nil
+		}),
+
+		// Using retries in order to handle the transient errors:
+		// https://docs.aws.amazon.com/prescriptive-guidance/latest/cloud-design-patterns/retry-backoff.html
+		RetryMaxAttempts: retries,
+		RetryMode:        aws.RetryModeStandard,
+	}
+
+	// By default the SDK transport uses the proxy from the environment variables
+	if c.Proxy != "" || len(c.NoProxy) > 0 {
+		proxy := httpproxy.FromEnvironment()
+		if c.Proxy != "" {
+			proxy.HTTPProxy = c.Proxy
+			proxy.HTTPSProxy = c.Proxy
+		}
+		if len(c.NoProxy) > 0 {
+			proxy.NoProxy = strings.Join(c.NoProxy, ",")
+		}
+		proxyFunc := proxy.ProxyFunc()
+		cfg.HTTPClient = awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			tr.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyFunc(req.URL)
+			}
+		})
+	}
+
+	return cfg
+}
+
+// endpoint returns the custom service endpoint if it's set, VPC endpoints are regional so the
+// other regions are using the default ones
+func (c *Config) endpoint(region, endpoint string) *string {
+	if endpoint == "" || region != c.Region {
+		return nil
+	}
+	return aws.String(endpoint)
+}
+
+// instancePrice returns the price of the first instance type which has it in the price table
+func (c *Config) instancePrice(instTypes []string) (float64, bool) {
+	for _, instType := range instTypes {
+		if price, ok := c.Prices[instType]; ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"testing"
+)
+
+// Verify the custom endpoints are used only for the driver region
+func Test_config_endpoint(t *testing.T) {
+	cfg := Config{Region: "us-west-2", Endpoints: EndpointsConfig{EC2: "https://vpce-ec2.example.com"}}
+
+	if e := cfg.endpoint("us-west-2", cfg.Endpoints.EC2); e == nil || *e != "https://vpce-ec2.example.com" {
+		t.Fatalf("Custom endpoint should be used for the driver region: %v", e)
+	}
+	if e := cfg.endpoint("us-east-1", cfg.Endpoints.EC2); e != nil {
+		t.Fatalf("Custom endpoint should not be used for the other region: %s", *e)
+	}
+	if e := cfg.endpoint("us-west-2", cfg.Endpoints.KMS); e != nil {
+		t.Fatalf("Empty endpoint should keep the default one: %s", *e)
+	}
+}
+
+// Verify the proxy config replaces the SDK default client only when set
+func Test_config_proxy(t *testing.T) {
+	cfg := Config{Region: "us-west-2"}
+	if cfg.awsConfig(cfg.Region, 3).HTTPClient != nil {
+		t.Fatalf("Default SDK client should be used when proxy is not set")
+	}
+
+	cfg.Proxy = "http://proxy.example.com:3128"
+	if cfg.awsConfig(cfg.Region, 3).HTTPClient == nil {
+		t.Fatalf("Custom client should be used when proxy is set")
+	}
+}
+
+// Verify the price is taken from the first instance type in the price table
+func Test_config_instance_price(t *testing.T) {
+	cfg := Config{Prices: map[string]float64{"c6a.4xlarge": 0.612, "c5.4xlarge": 0.68}}
+
+	if price, ok := cfg.instancePrice([]string{"c7a.4xlarge", "c5.4xlarge", "c6a.4xlarge"}); !ok || price != 0.68 {
+		t.Fatalf("Price of the first known type should be used: %v %v", price, ok)
+	}
+	if _, ok := cfg.instancePrice([]string{"mac2.metal"}); ok {
+		t.Fatalf("Unknown type should not have price")
+	}
+}
@@ -26,8 +26,14 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/metrics"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
 )
 
+var metricDedicatedHosts = metrics.NewGauge("fish_aws_dedicated_pool_hosts",
+	"Amount of the dedicated pool hosts by usage (total, used, free)", "pool", "usage")
+
 // HostReserved - custom status to set in the host for simplifying parallel ops in between the updates
 const HostReserved = "reserved"
 
@@ -49,6 +55,9 @@ type dedicatedPoolWorker struct {
 
 	// Hosts to release or scrub at specified time, used by manageHosts process
 	toManageAt map[string]time.Time
+
+	// When the hosts went to scrubbing to forecast the capacity, guarded by activeHostsMu
+	scrubbingSince map[string]time.Time
 }
 
 // Function runs as routine and makes sure identified hosts pool fits the configuration
@@ -58,14 +67,17 @@ func (d *Driver) newDedicatedPoolWorker(name string, record DedicatedPoolRecord)
 		driver: d,
 		record: record,
 
-		activeHosts: make(map[string]ec2types.Host),
-		toManageAt:  make(map[string]time.Time),
+		activeHosts:    make(map[string]ec2types.Host),
+		toManageAt:     make(map[string]time.Time),
+		scrubbingSince: make(map[string]time.Time),
 	}
 
 	// Receiving amount of instances per dedicated host
 	worker.fetchInstancesPerHost()
 
-	go worker.backgroundProcess()
+	// Running the processes supervised to not take down the whole node in case of panic
+	util.GoSupervised(fmt.Sprintf("AWS dedicated %q", name), worker.backgroundProcess)
+	util.GoSupervised(fmt.Sprintf("AWS dedicated %q update", name), func() { worker.updateDedicatedHostsProcess() })
 
 	log.Debugf("AWS: Created dedicated pool: %q", worker.name)
 
@@ -211,9 +223,8 @@ func (w *dedicatedPoolWorker) fetchInstancesPerHost() {
 func (w *dedicatedPoolWorker) backgroundProcess() {
 	defer log.Infof("AWS: dedicated %q: Exited backgroundProcess()", w.name)
 
-	// Updating hosts and start background process for periodic update
+	// Updating hosts before the periodic update process will do that
 	w.updateDedicatedHosts()
-	go w.updateDedicatedHostsProcess()
 
 	// Run main management process until fish stops
 	for {
@@ -364,8 +375,10 @@ func (w *dedicatedPoolWorker) releaseHosts(releaseHosts []string) {
 				continue
 			}
 
-			// Removing the host from the list
-			delete(w.activeHosts, hostID)
+			// Keeping the host in the list as pending to not count it as free slot of the pool
+			host.State = ec2types.AllocationStatePending
+			w.activeHosts[hostID] = host
+			w.scrubbingSince[hostID] = time.Now()
 		}
 	}
 }
@@ -506,6 +519,8 @@ func (w *dedicatedPoolWorker) updateDedicatedHosts() error {
 
 	w.activeHostsUpdated = time.Now()
 	w.activeHosts = currActiveHosts
+	w.updateScrubbing()
+	w.updateMetrics()
 
 	// Printing list for debug purposes
 	if log.GetVerbosity() == 1 {
@@ -518,6 +533,79 @@ func (w *dedicatedPoolWorker) updateDedicatedHosts() error {
 	return nil
 }
 
+// updateScrubbing tracks when the hosts went to scrubbing, the hosts found already pending (for
+// example after restart) are considered started now, activeHostsMu should be locked by the caller
+func (w *dedicatedPoolWorker) updateScrubbing() {
+	for hostID, host := range w.activeHosts {
+		if _, ok := w.scrubbingSince[hostID]; !ok && host.State == ec2types.AllocationStatePending && isHostMac(&host) {
+			w.scrubbingSince[hostID] = time.Now()
+		}
+	}
+	for hostID := range w.scrubbingSince {
+		if host, ok := w.activeHosts[hostID]; !ok || host.State != ec2types.AllocationStatePending {
+			delete(w.scrubbingSince, hostID)
+		}
+	}
+}
+
+// Forecast returns the state of the pool hosts and when the capacity is expected to free up
+func (w *dedicatedPoolWorker) Forecast() types.DriverPoolForecast {
+	out := types.DriverPoolForecast{
+		Name:         w.name,
+		InstanceType: w.record.Type,
+	}
+
+	w.activeHostsMu.RLock()
+	defer w.activeHostsMu.RUnlock()
+
+	var next time.Time
+	for hostID, host := range w.activeHosts {
+		_, toManage := w.toManageAt[hostID]
+		switch {
+		case isHostUsed(&host):
+			out.Used++
+		case host.State == ec2types.AllocationStatePending:
+			out.Scrubbing++
+			at := w.scrubbingSince[hostID].Add(time.Duration(w.record.ScrubbingDuration))
+			if next.IsZero() || at.Before(next) {
+				next = at
+			}
+		case toManage:
+			out.PendingRelease++
+			out.FreeSlots += int64(getHostCapacity(&host))
+		case getHostCapacity(&host) > 0:
+			out.Available++
+			out.FreeSlots += int64(getHostCapacity(&host))
+		}
+	}
+	if int(w.record.Max) > len(w.activeHosts) {
+		out.FreeSlots += int64(int(w.record.Max)-len(w.activeHosts)) * int64(w.instancesPerHost)
+	}
+
+	if out.FreeSlots < 1 && !next.IsZero() {
+		// Scrubbing could take longer than expected, so capacity could appear any moment
+		if next.Before(time.Now()) {
+			next = time.Now()
+		}
+		out.NextCapacityAt = &next
+	}
+
+	return out
+}
+
+// updateMetrics reports the pool utilization, activeHostsMu should be locked by the caller
+func (w *dedicatedPoolWorker) updateMetrics() {
+	used := 0
+	for _, host := range w.activeHosts {
+		if isHostUsed(&host) {
+			used++
+		}
+	}
+	metricDedicatedHosts.Set(float64(len(w.activeHosts)), w.name, "total")
+	metricDedicatedHosts.Set(float64(used), w.name, "used")
+	metricDedicatedHosts.Set(float64(len(w.activeHosts)-used), w.name, "free")
+}
+
 func (w *dedicatedPoolWorker) allocateDedicatedHost() (string, string, error) {
 	log.Infof("AWS: dedicated %q: Allocating dedicated host of type %q", w.name, w.record.Type)
 
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+func testMacHost(id string, state ec2types.AllocationState, capacity int32, instances int) ec2types.Host {
+	host := ec2types.Host{
+		HostId:         aws.String(id),
+		State:          state,
+		HostProperties: &ec2types.HostProperties{InstanceType: aws.String("mac2.metal")},
+		AvailableCapacity: &ec2types.AvailableCapacity{
+			AvailableInstanceCapacity: []ec2types.InstanceCapacity{{AvailableCapacity: aws.Int32(capacity)}},
+		},
+	}
+	for i := 0; i < instances; i++ {
+		host.Instances = append(host.Instances, ec2types.HostInstance{})
+	}
+	return host
+}
+
+// Verify the pool forecast reports the hosts states and the scrubbing end
+func Test_dedicated_pool_forecast(t *testing.T) {
+	scrubbingStart := time.Now().Add(-30 * time.Minute)
+	w := &dedicatedPoolWorker{
+		name:             "macs",
+		record:           DedicatedPoolRecord{Type: "mac2.metal", Max: 3, ScrubbingDuration: util.Duration(time.Hour)},
+		instancesPerHost: 1,
+		activeHosts: map[string]ec2types.Host{
+			"h-used":      testMacHost("h-used", ec2types.AllocationStateAvailable, 0, 1),
+			"h-scrubbing": testMacHost("h-scrubbing", ec2types.AllocationStatePending, 0, 0),
+			"h-release":   testMacHost("h-release", ec2types.AllocationStateAvailable, 1, 0),
+		},
+		toManageAt:     map[string]time.Time{"h-release": time.Now().Add(time.Hour)},
+		scrubbingSince: map[string]time.Time{"h-scrubbing": scrubbingStart},
+	}
+
+	f := w.Forecast()
+	if f.Used != 1 || f.Scrubbing != 1 || f.PendingRelease != 1 || f.Available != 0 || f.FreeSlots != 1 {
+		t.Fatalf("Wrong forecast: %+v", f)
+	}
+	if f.NextCapacityAt != nil {
+		t.Fatalf("Next capacity should not be set when there are free slots: %s", f.NextCapacityAt)
+	}
+
+	// The idle host was taken, so only the scrubbing one could free up
+	w.activeHosts["h-release"] = testMacHost("h-release", ec2types.AllocationStateAvailable, 0, 1)
+	f = w.Forecast()
+	if f.FreeSlots != 0 || f.NextCapacityAt == nil || !f.NextCapacityAt.Equal(scrubbingStart.Add(time.Hour)) {
+		t.Fatalf("Next capacity should be at the scrubbing end: %+v", f)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -35,6 +36,12 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
+// Instance tags to find the owner of the instance
+const (
+	tagNode        = "FishNode"
+	tagApplication = "FishApplication"
+)
+
 // Factory implements drivers.ResourceDriverFactory interface
 type Factory struct{}
 
@@ -64,6 +71,14 @@ type Driver struct {
 	quotasNextUpdate time.Time
 
 	dedicatedPools map[string]*dedicatedPoolWorker
+
+	// Contains the resolved images when fallback sources are used
+	imageCache      map[string]imageCacheEntry
+	imageCacheMutex sync.Mutex
+
+	// Contains the instance types found by requirements
+	instanceTypesCache      map[string]instanceTypesCacheEntry
+	instanceTypesCacheMutex sync.Mutex
 }
 
 // Name returns name of the driver
@@ -89,6 +104,9 @@ func (d *Driver) Prepare(config []byte) error {
 	d.tasksList = append(d.tasksList,
 		&TaskSnapshot{driver: d},
 		&TaskImage{driver: d},
+		&TaskPower{driver: d, action: drivers.TaskSuspend},
+		&TaskPower{driver: d, action: drivers.TaskResume},
+		&TaskPower{driver: d, action: drivers.TaskReboot},
 	)
 
 	d.quotasMutex.Lock()
@@ -110,6 +128,8 @@ func (d *Driver) Prepare(config []byte) error {
 
 	// Run the background dedicated hosts pool management
 	d.dedicatedPools = make(map[string]*dedicatedPoolWorker)
+	d.imageCache = make(map[string]imageCacheEntry)
+	d.instanceTypesCache = make(map[string]instanceTypesCacheEntry)
 	for name, params := range d.cfg.DedicatedPool {
 		d.dedicatedPools[name] = d.newDedicatedPoolWorker(name, params)
 	}
@@ -117,15 +137,26 @@ func (d *Driver) Prepare(config []byte) error {
 	return nil
 }
 
+// Capabilities of the driver, disk type is the EBS volume type with optional IOPS and
+// throughput ("gp3:3000:125") and network is the subnet
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"*"},
+		DiskClone: true,
+		Networks:  []string{"*"},
+	}
+}
+
 // ValidateDefinition checks LabelDefinition is ok
-func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
 	var opts Options
 	if err := opts.Apply(def.Options); err != nil {
 		return err
 	}
 
-	// Check resources (no disk types supported and no net check)
-	if err := def.Resources.Validate([]string{}, false); err != nil {
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
 		return fmt.Errorf("AWS: Resources validation failed: %s", err)
 	}
 
@@ -134,8 +165,6 @@ func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
 
 // AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
 func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.LabelDefinition) int64 {
-	var instCount int64
-
 	var opts Options
 	if err := opts.Apply(def.Options); err != nil {
 		log.Error("AWS: Unable to apply options:", err)
@@ -152,14 +181,50 @@ func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.La
 		}
 		log.Warn("AWS: Unable to locate dedicated pool:", opts.Pool)
 		return -1
-	} else if awsInstTypeAny(opts.InstanceType, "mac") {
+	}
+
+	// Using capacity of the first instance type which is able to run the definition
+	var instCount int64 = -1
+	var instType string
+	for _, instType = range d.instanceTypes(connEc2, &opts, def) {
+		if instCount = d.typeCapacity(connEc2, instType, def); instCount > 0 {
+			break
+		}
+	}
+	if instCount < 1 || awsInstTypeAny(instType, "mac") {
+		return instCount
+	}
+
+	// Make sure we have enough IP's in the selected VPC or subnet
+	var ipCount int64
+	var err error
+	if _, ipCount, err = d.getSubnetID(connEc2, def.Resources.Network, ""); err != nil {
+		log.Error("AWS: Error during requesting subnet:", err)
+		return -1
+	}
+
+	log.Debugf("AWS: AvailableCapacity: Quotas: %d, IP's: %d", instCount, ipCount)
+
+	// Return the most limiting value
+	if ipCount < instCount {
+		return ipCount
+	}
+	return instCount
+}
+
+// typeCapacity returns how much instances of the type could be started according to the quotas or
+// the available mac dedicated hosts
+func (d *Driver) typeCapacity(connEc2 *ec2.Client, instType string, def types.LabelDefinition) int64 {
+	var instCount int64
+
+	if awsInstTypeAny(instType, "mac") {
 		// Ensure we have the available auto-placing dedicated hosts to use as base for resource.
 		// Quotas for hosts are: "Running Dedicated mac1 Hosts" & "Running Dedicated mac2 Hosts"
 		p := ec2.NewDescribeHostsPaginator(connEc2, &ec2.DescribeHostsInput{
 			Filter: []ec2types.Filter{
 				{
 					Name:   aws.String("instance-type"),
-					Values: []string{opts.InstanceType},
+					Values: []string{instType},
 				},
 				{
 					Name:   aws.String("state"),
@@ -178,7 +243,7 @@ func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.La
 			instCount += int64(len(resp.Hosts))
 		}
 
-		log.Debug("AWS: AvailableCapacity for dedicated Mac:", opts.InstanceType, instCount)
+		log.Debug("AWS: AvailableCapacity for dedicated Mac:", instType, instCount)
 
 		return instCount
 	}
@@ -193,44 +258,46 @@ func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.La
 		instTypes := []string{}
 
 		// Check we have enough quotas for specified instance type
-		if awsInstTypeAny(opts.InstanceType, "dl") {
+		if awsInstTypeAny(instType, "dl") {
 			cpuQuota = d.quotas["Running On-Demand DL instances"]
 			instTypes = append(instTypes, "dl")
-		} else if awsInstTypeAny(opts.InstanceType, "u-") {
+		} else if awsInstTypeAny(instType, "u-") {
 			cpuQuota = d.quotas["Running On-Demand High Memory instances"]
 			instTypes = append(instTypes, "u-")
-		} else if awsInstTypeAny(opts.InstanceType, "hpc") {
+		} else if awsInstTypeAny(instType, "hpc") {
 			cpuQuota = d.quotas["Running On-Demand HPC instances"]
 			instTypes = append(instTypes, "hpc")
-		} else if awsInstTypeAny(opts.InstanceType, "inf") {
+		} else if awsInstTypeAny(instType, "inf") {
 			cpuQuota = d.quotas["Running On-Demand Inf instances"]
 			instTypes = append(instTypes, "inf")
-		} else if awsInstTypeAny(opts.InstanceType, "trn") {
+		} else if awsInstTypeAny(instType, "trn") {
 			cpuQuota = d.quotas["Running On-Demand Trn instances"]
 			instTypes = append(instTypes, "trn")
-		} else if awsInstTypeAny(opts.InstanceType, "f") {
+		} else if awsInstTypeAny(instType, "f") {
 			cpuQuota = d.quotas["Running On-Demand F instances"]
 			instTypes = append(instTypes, "f")
-		} else if awsInstTypeAny(opts.InstanceType, "g", "vt") {
+		} else if awsInstTypeAny(instType, "g", "vt") {
 			cpuQuota = d.quotas["Running On-Demand G and VT instances"]
 			instTypes = append(instTypes, "g", "vt")
-		} else if awsInstTypeAny(opts.InstanceType, "p") {
+		} else if awsInstTypeAny(instType, "p") {
 			cpuQuota = d.quotas["Running On-Demand P instances"]
 			instTypes = append(instTypes, "p")
-		} else if awsInstTypeAny(opts.InstanceType, "x") {
+		} else if awsInstTypeAny(instType, "x") {
 			cpuQuota = d.quotas["Running On-Demand X instances"]
 			instTypes = append(instTypes, "x")
-		} else if awsInstTypeAny(opts.InstanceType, "a", "c", "d", "h", "i", "m", "r", "t", "z") {
+		} else if awsInstTypeAny(instType, "a", "c", "d", "h", "i", "m", "r", "t", "z") {
 			cpuQuota = d.quotas["Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances"]
 			instTypes = append(instTypes, "a", "c", "d", "h", "i", "m", "r", "t", "z")
 		} else {
-			log.Error("AWS: Driver does not support instance type:", opts.InstanceType)
+			d.quotasMutex.Unlock()
+			log.Error("AWS: Driver does not support instance type:", instType)
 			return -1
 		}
 
 		// Checking the current usage of CPU's of this project and subtracting it from quota value
 		cpuUsage, err := d.getProjectCPUUsage(connEc2, instTypes)
 		if err != nil {
+			d.quotasMutex.Unlock()
 			return -1
 		}
 
@@ -239,20 +306,6 @@ func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.La
 	}
 	d.quotasMutex.Unlock()
 
-	// Make sure we have enough IP's in the selected VPC or subnet
-	var ipCount int64
-	var err error
-	if _, ipCount, err = d.getSubnetID(connEc2, def.Resources.Network, ""); err != nil {
-		log.Error("AWS: Error during requesting subnet:", err)
-		return -1
-	}
-
-	log.Debugf("AWS: AvailableCapacity: Quotas: %d, IP's: %d", instCount, ipCount)
-
-	// Return the most limiting value
-	if ipCount < instCount {
-		return ipCount
-	}
 	return instCount
 }
 
@@ -275,20 +328,40 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 	// Looking for the AMI
 	vmImage := opts.Image
 	var err error
-	if vmImage, err = d.getImageID(conn, vmImage); err != nil {
+	if vmImage, err = d.resolveImage(conn, &opts); err != nil {
 		return nil, fmt.Errorf("AWS: %s: Unable to get image: %v", iName, err)
 	}
 	log.Infof("AWS: %s: Selected image: %q", iName, vmImage)
 
+	// Dedicated pool hosts are allocated for the specific type, so only it could be used there
+	instTypes := []string{opts.InstanceType}
+	if opts.Pool == "" {
+		instTypes = d.instanceTypes(conn, &opts, def)
+	}
+	if len(instTypes) == 0 {
+		return nil, fmt.Errorf("AWS: %s: Unable to find any instance type for the definition", iName)
+	}
+
 	// Prepare Instance request information
 	input := ec2.RunInstancesInput{
 		ImageId:      aws.String(vmImage),
-		InstanceType: ec2types.InstanceType(opts.InstanceType),
+		InstanceType: ec2types.InstanceType(instTypes[0]),
+
+		MetadataOptions: d.cfg.Metadata.merge(opts.Metadata).request(),
 
 		MinCount: aws.Int32(1),
 		MaxCount: aws.Int32(1),
 	}
 
+	// Checking the instance profile before reserving any dedicated host for the instance
+	if profile := opts.instanceProfile(); profile != nil {
+		if err = d.checkInstanceProfile(conn, profile, vmImage, instTypes[0]); err != nil {
+			return nil, fmt.Errorf("AWS: %s: Unable to use instance profile %q: %v", iName, opts.InstanceProfile, err)
+		}
+		log.Infof("AWS: %s: Selected instance profile: %q", iName, opts.InstanceProfile)
+		input.IamInstanceProfile = profile
+	}
+
 	var netZone string
 	if opts.Pool != "" {
 		// Let's reserve or allocate the host for the new instance
@@ -306,11 +379,6 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 			HostId:  aws.String(hostID),
 		}
 		log.Infof("AWS: %s: Utilizing pool %q host: %s", iName, opts.Pool, hostID)
-	} else if awsInstTypeAny(opts.InstanceType, "mac") {
-		// For mac machines only dedicated hosts are working, so set the tenancy
-		input.Placement = &ec2types.Placement{
-			Tenancy: ec2types.TenancyHost,
-		}
 	}
 
 	// Checking the VPC exists or use default one
@@ -347,6 +415,7 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 		input.NetworkInterfaces[0].Groups = []string{vmSecgroup}
 	}
 
+	tagsOut := []ec2types.Tag{}
 	if len(d.cfg.InstanceTags) > 0 || len(opts.Tags) > 0 {
 		tagsIn := map[string]string{}
 		// Append tags to the map - from opts (low priority) and from cfg (high priority)
@@ -357,25 +426,32 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 			tagsIn[k] = v
 		}
 
-		tagsOut := []ec2types.Tag{}
 		for k, v := range tagsIn {
 			tagsOut = append(tagsOut, ec2types.Tag{
 				Key:   aws.String(k),
 				Value: aws.String(v),
 			})
 		}
-		// Apply name for the instance
-		tagsOut = append(tagsOut, ec2types.Tag{
-			Key:   aws.String("Name"),
-			Value: aws.String(iName),
-		})
-
-		input.TagSpecifications = []ec2types.TagSpecification{
-			{
-				ResourceType: ec2types.ResourceTypeInstance,
-				Tags:         tagsOut,
-			},
-		}
+	}
+	// Apply name for the instance, it's used to find the instances created by the driver
+	tagsOut = append(tagsOut, ec2types.Tag{
+		Key:   aws.String("Name"),
+		Value: aws.String(iName),
+	})
+	// The account could be shared between the nodes, so marking the owner of the instance
+	node, app := drivers.ListTags(metadata)
+	tagsOut = append(tagsOut, ec2types.Tag{
+		Key:   aws.String(tagNode),
+		Value: aws.String(node),
+	}, ec2types.Tag{
+		Key:   aws.String(tagApplication),
+		Value: aws.String(app),
+	})
+	input.TagSpecifications = []ec2types.TagSpecification{
+		{
+			ResourceType: ec2types.ResourceTypeInstance,
+			Tags:         tagsOut,
+		},
 	}
 
 	// Prepare the device mapping
@@ -433,11 +509,37 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 		}
 	}
 
-	// Run the instance
-	result, err := conn.RunInstances(context.TODO(), &input)
-	if err != nil {
+	// Run the instance, trying the instance types in order until one of them will have capacity
+	var result *ec2.RunInstancesOutput
+	for i, instType := range instTypes {
+		if len(instTypes) > 1 && d.typeCapacity(conn, instType, def) < 1 {
+			log.Infof("AWS: %s: No capacity for instance type %q, trying next one", iName, instType)
+			continue
+		}
+		input.InstanceType = ec2types.InstanceType(instType)
+		if opts.Pool == "" {
+			input.Placement = nil
+			if awsInstTypeAny(instType, "mac") {
+				// For mac machines only dedicated hosts are working, so set the tenancy
+				input.Placement = &ec2types.Placement{
+					Tenancy: ec2types.TenancyHost,
+				}
+			}
+		}
+
+		if result, err = conn.RunInstances(context.TODO(), &input); err == nil {
+			log.Infof("AWS: %s: Selected instance type: %q", iName, instType)
+			break
+		}
+		if i+1 < len(instTypes) && isCapacityError(err) {
+			log.Warnf("AWS: %s: Unable to run instance type %q, trying next one: %v", iName, instType, err)
+			continue
+		}
 		return nil, log.Errorf("AWS: %s: Unable to run instance: %v", iName, err)
 	}
+	if result == nil {
+		return nil, log.Errorf("AWS: %s: No capacity for any of the instance types: %q", iName, instTypes)
+	}
 
 	inst := &result.Instances[0]
 
@@ -502,6 +604,7 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 			log.Infof("AWS: %s: Allocate of instance completed: %q, %q", iName, aws.ToString(inst.InstanceId), aws.ToString(inst.PrivateIpAddress))
 			res.Identifier = aws.ToString(inst.InstanceId)
 			res.IpAddr = aws.ToString(inst.PrivateIpAddress)
+			res.DriverInfo = instanceInfo(inst)
 			return res, nil
 		}
 
@@ -524,6 +627,16 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 	return res, log.Errorf("AWS: %s: Unable to locate the instance IP: %q", iName, aws.ToString(inst.InstanceId))
 }
 
+// InfoSchema describes the instance info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "instance_type", Type: types.DriverInfoFieldTypeString, Required: true, Description: "EC2 instance type"},
+		{Name: "availability_zone", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Availability zone of the instance"},
+		{Name: "image_id", Type: types.DriverInfoFieldTypeString, Required: true, Description: "AMI the instance was started from"},
+		{Name: "host_id", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Dedicated host of the instance if any"},
+	}
+}
+
 // Status shows status of the resource
 func (d *Driver) Status(res *types.Resource) (string, error) {
 	if res == nil || res.Identifier == "" {
@@ -561,6 +674,95 @@ func (d *Driver) GetTask(name, options string) drivers.ResourceDriverTask {
 	return t
 }
 
+// List returns the not terminated instances created by the driver, they are tagged by name and owner
+func (d *Driver) List() ([]types.Resource, error) {
+	conn := d.newEC2Conn()
+	req := ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag:Name"),
+				Values: []string{"fish-*"},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"pending", "running", "stopping", "stopped"},
+			},
+		},
+	}
+	p := ec2.NewDescribeInstancesPaginator(conn, &req)
+
+	out := []types.Resource{}
+	for p.HasMorePages() {
+		resp, err := p.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS: Unable to list the instances: %v", err)
+		}
+		for _, res := range resp.Reservations {
+			for _, inst := range res.Instances {
+				var node, app string
+				for _, tag := range inst.Tags {
+					switch aws.ToString(tag.Key) {
+					case tagNode:
+						node = aws.ToString(tag.Value)
+					case tagApplication:
+						app = aws.ToString(tag.Value)
+					}
+				}
+				out = append(out, drivers.ListResource(aws.ToString(inst.InstanceId), node, app))
+			}
+		}
+	}
+	return out, nil
+}
+
+// Forecast reports the state of the dedicated pools and when their capacity will free up
+func (d *Driver) Forecast() types.DriverForecast {
+	out := types.DriverForecast{Pools: []types.DriverPoolForecast{}}
+	names := make([]string, 0, len(d.dedicatedPools))
+	for name := range d.dedicatedPools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Pools = append(out.Pools, d.dedicatedPools[name].Forecast())
+	}
+	return out
+}
+
+// CapacityForecast returns when the dedicated pool of the definition will have the capacity, the
+// regular instances capacity can't be forecasted
+func (d *Driver) CapacityForecast(def types.LabelDefinition) time.Time {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil || opts.Pool == "" {
+		return time.Time{}
+	}
+	p, ok := d.dedicatedPools[opts.Pool]
+	if !ok {
+		return time.Time{}
+	}
+	forecast := p.Forecast()
+	if forecast.FreeSlots > 0 {
+		return time.Now()
+	}
+	if forecast.NextCapacityAt == nil {
+		return time.Time{}
+	}
+	return *forecast.NextCapacityAt
+}
+
+// HourlyCost returns the price of the definition instance type from the config price table
+func (d *Driver) HourlyCost(def types.LabelDefinition) (float64, error) {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return 0, fmt.Errorf("AWS: Unable to apply options: %v", err)
+	}
+	price, ok := d.cfg.instancePrice(opts.listedInstanceTypes())
+	if !ok {
+		return 0, fmt.Errorf("AWS: No price for the instance types: %v", opts.listedInstanceTypes())
+	}
+	return price, nil
+}
+
 // Deallocate the resource
 func (d *Driver) Deallocate(res *types.Resource) error {
 	if res == nil || res.Identifier == "" {
@@ -0,0 +1,184 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+const (
+	// How long to keep the image resolution result
+	imageCacheTTL = 10 * time.Minute
+
+	// Tag to mark the image copies made by fish to find them later
+	imageSourceTag = "fish-source-image"
+)
+
+// imageCacheEntry keeps the resolved image to not lookup the sources for every allocation
+type imageCacheEntry struct {
+	ID      string
+	Expires time.Time
+}
+
+// resolveImage returns the available image ID, trying the fallback sources in order if the main
+// image is missing or deregistered
+func (d *Driver) resolveImage(conn *ec2.Client, opts *Options) (string, error) {
+	if len(opts.ImageFallbacks) == 0 {
+		return d.getImageID(conn, opts.Image)
+	}
+
+	key := opts.imageCacheKey()
+	d.imageCacheMutex.Lock()
+	entry, ok := d.imageCache[key]
+	d.imageCacheMutex.Unlock()
+	if ok && time.Now().Before(entry.Expires) {
+		return entry.ID, nil
+	}
+
+	sources := append([]ImageSource{{Image: opts.Image}}, opts.ImageFallbacks...)
+	id, err := resolveImageSources(sources, func(src ImageSource) (string, error) {
+		return d.getImageIDFromSource(conn, src)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	d.imageCacheMutex.Lock()
+	d.imageCache[key] = imageCacheEntry{ID: id, Expires: time.Now().Add(imageCacheTTL)}
+	d.imageCacheMutex.Unlock()
+	return id, nil
+}
+
+// resolveImageSources returns the image of the first available source in order
+func resolveImageSources(sources []ImageSource, lookup func(ImageSource) (string, error)) (string, error) {
+	var errs []string
+	for i, src := range sources {
+		id, err := lookup(src)
+		if err != nil {
+			log.Warnf("AWS: Image source %d %q is not available: %v", i, src.String(), err)
+			errs = append(errs, err.Error())
+			continue
+		}
+		if i > 0 {
+			log.Warnf("AWS: Using fallback image source %d %q: %s", i, src.String(), id)
+		}
+		return id, nil
+	}
+
+	return "", fmt.Errorf("AWS: Unable to find image in any of the sources: %s", strings.Join(errs, "; "))
+}
+
+// getImageIDFromSource finds the available image described by the source, copies it from the
+// other region if needed
+func (d *Driver) getImageIDFromSource(conn *ec2.Client, src ImageSource) (string, error) {
+	owners := d.cfg.AccountIDs
+	if src.Owner != "" {
+		owners = []string{src.Owner}
+	}
+
+	srcConn := conn
+	if src.Region != "" && src.Region != d.cfg.Region {
+		srcConn = d.newEC2ConnRegion(src.Region)
+	}
+
+	id, err := d.getAvailableImageID(srcConn, src.Image, owners)
+	if err != nil {
+		return "", err
+	}
+	if srcConn == conn {
+		return id, nil
+	}
+	return d.copyImage(conn, src.Region, id)
+}
+
+// getAvailableImageID makes sure the image exists and is available, since ID could be deregistered
+func (d *Driver) getAvailableImageID(conn *ec2.Client, idName string, owners []string) (string, error) {
+	if !strings.HasPrefix(idName, "ami-") {
+		return d.getImageIDByName(conn, idName, owners)
+	}
+
+	resp, err := conn.DescribeImages(context.TODO(), &ec2.DescribeImagesInput{
+		ImageIds: []string{idName},
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []string{"available"},
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("AWS: Unable to describe image %s: %v", idName, err)
+	}
+	if len(resp.Images) == 0 {
+		return "", fmt.Errorf("AWS: Image %s is not available", idName)
+	}
+	return idName, nil
+}
+
+// copyImage returns the copy of the image from another region if it's available, otherwise
+// starts the copy and returns error, so the next sources will be used while it's in progress
+func (d *Driver) copyImage(conn *ec2.Client, srcRegion, srcID string) (string, error) {
+	srcTag := srcRegion + "/" + srcID
+
+	// The copy could be already made or started by the previous allocations
+	resp, err := conn.DescribeImages(context.TODO(), &ec2.DescribeImagesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("tag:" + imageSourceTag),
+				Values: []string{srcTag},
+			},
+		},
+		Owners: []string{"self"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("AWS: Unable to look for the image copy of %s: %v", srcTag, err)
+	}
+	for _, img := range resp.Images {
+		switch img.State {
+		case types.ImageStateAvailable:
+			return aws.ToString(img.ImageId), nil
+		case types.ImageStatePending:
+			return "", fmt.Errorf("AWS: Copy %s of image %s is in progress", aws.ToString(img.ImageId), srcTag)
+		}
+	}
+
+	// The tag is set on creation, so the copy will be found by the next allocations even if fish restarts
+	log.Infof("AWS: Copying image %s to region %s", srcTag, d.cfg.Region)
+	out, err := conn.CopyImage(context.TODO(), &ec2.CopyImageInput{
+		Name:          aws.String(fmt.Sprintf("fish-copy-%s-%s", srcRegion, srcID)),
+		Description:   aws.String("Copy of " + srcTag + " made by Aquarium Fish"),
+		SourceImageId: aws.String(srcID),
+		SourceRegion:  aws.String(srcRegion),
+		TagSpecifications: []types.TagSpecification{{
+			ResourceType: types.ResourceTypeImage,
+			Tags: []types.Tag{{
+				Key:   aws.String(imageSourceTag),
+				Value: aws.String(srcTag),
+			}},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("AWS: Unable to copy image %s: %v", srcTag, err)
+	}
+
+	return "", fmt.Errorf("AWS: Copy %s of image %s is started", aws.ToString(out.ImageId), srcTag)
+}
@@ -0,0 +1,57 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Verify the image sources are checked in order and the first available one is used
+func Test_resolve_image_sources(t *testing.T) {
+	sources := []ImageSource{
+		{Image: "ami-main"},
+		{Image: "my-image-v1"},
+		{Image: "ami-copy", Region: "us-east-1"},
+		{Image: "shared", Owner: "123"},
+	}
+
+	testcases := []struct {
+		available map[string]string
+		want      string
+		checked   string
+	}{
+		{map[string]string{"ami-main": "ami-main", "my-image-v1": "ami-v1"}, "ami-main", "[ami-main]"},
+		{map[string]string{"my-image-v1": "ami-v1", "shared": "ami-shared"}, "ami-v1", "[ami-main my-image-v1]"},
+		{map[string]string{"shared": "ami-shared"}, "ami-shared", "[ami-main my-image-v1 ami-copy shared]"},
+		{map[string]string{}, "", "[ami-main my-image-v1 ami-copy shared]"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.want, func(t *testing.T) {
+			var checked []string
+			id, err := resolveImageSources(sources, func(src ImageSource) (string, error) {
+				checked = append(checked, src.Image)
+				if id, ok := tc.available[src.Image]; ok {
+					return id, nil
+				}
+				return "", fmt.Errorf("image %s is not available", src.Image)
+			})
+			if id != tc.want || (err == nil) != (tc.want != "") {
+				t.Fatalf("resolveImageSources() = %q, %v; want: %q", id, err, tc.want)
+			}
+			if fmt.Sprint(checked) != tc.checked {
+				t.Fatalf("Sources checked: %v; want: %s", checked, tc.checked)
+			}
+		})
+	}
+}
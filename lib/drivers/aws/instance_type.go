@@ -0,0 +1,156 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// How long to keep the instance types found by requirements
+const instanceTypesCacheTTL = 1 * time.Hour
+
+// instanceTypesCacheEntry keeps the instance types found by requirements to not request them on
+// every capacity check
+type instanceTypesCacheEntry struct {
+	Types   []string
+	Expires time.Time
+}
+
+// instanceTypes returns the ordered list of the instance types to try for the definition: the
+// listed ones go first and then the ones matching the requirements
+func (d *Driver) instanceTypes(conn *ec2.Client, opts *Options, def types.LabelDefinition) []string {
+	out := opts.listedInstanceTypes()
+	if opts.InstanceRequirements == nil {
+		return out
+	}
+
+	found, err := d.getTypesByRequirements(conn, opts.InstanceRequirements.withDefaults(def.Resources.Cpu, def.Resources.Ram))
+	if err != nil {
+		log.Warn("AWS: Unable to find instance types by requirements:", err)
+		return out
+	}
+	for _, typ := range found {
+		if !util.Contains(out, typ) {
+			out = append(out, typ)
+		}
+	}
+	return out
+}
+
+// getTypesByRequirements returns the instance types matching the requirements, ordered from the
+// smallest to the largest ones to not waste the money
+func (d *Driver) getTypesByRequirements(conn *ec2.Client, req InstanceRequirements) ([]string, error) {
+	key := req.String()
+	d.instanceTypesCacheMutex.Lock()
+	entry, ok := d.instanceTypesCache[key]
+	d.instanceTypesCacheMutex.Unlock()
+	if ok && time.Now().Before(entry.Expires) {
+		return entry.Types, nil
+	}
+
+	input := ec2.GetInstanceTypesFromInstanceRequirementsInput{
+		ArchitectureTypes:   []ec2types.ArchitectureType{ec2types.ArchitectureType(req.Arch)},
+		VirtualizationTypes: []ec2types.VirtualizationType{ec2types.VirtualizationTypeHvm},
+		InstanceRequirements: &ec2types.InstanceRequirementsRequest{
+			VCpuCount: &ec2types.VCpuCountRangeRequest{Min: aws.Int32(req.MinCPU)},
+			MemoryMiB: &ec2types.MemoryMiBRequest{Min: aws.Int32(req.MinMemory * 1024)},
+		},
+	}
+
+	var names []string
+	p := ec2.NewGetInstanceTypesFromInstanceRequirementsPaginator(conn, &input)
+	for p.HasMorePages() {
+		resp, err := p.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS: Error during requesting instance types: %v", err)
+		}
+		for _, typ := range resp.InstanceTypes {
+			names = append(names, aws.ToString(typ.InstanceType))
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("AWS: No instance types match the requirements: %s", key)
+	}
+
+	// DescribeInstanceTypes is limited to 100 types per request
+	if len(names) > 100 {
+		names = names[:100]
+	}
+	info, err := d.getTypes(conn, names)
+	if err != nil {
+		return nil, err
+	}
+	sortInstanceTypes(names, info)
+	if len(names) > req.Max {
+		names = names[:req.Max]
+	}
+
+	d.instanceTypesCacheMutex.Lock()
+	d.instanceTypesCache[key] = instanceTypesCacheEntry{Types: names, Expires: time.Now().Add(instanceTypesCacheTTL)}
+	d.instanceTypesCacheMutex.Unlock()
+	return names, nil
+}
+
+// sortInstanceTypes orders the types by vCPU, memory and then by name
+func sortInstanceTypes(names []string, info map[string]ec2types.InstanceTypeInfo) {
+	sort.SliceStable(names, func(i, j int) bool {
+		ci, mi := instanceTypeSize(info[names[i]])
+		cj, mj := instanceTypeSize(info[names[j]])
+		if ci != cj {
+			return ci < cj
+		}
+		if mi != mj {
+			return mi < mj
+		}
+		return names[i] < names[j]
+	})
+}
+
+// instanceTypeSize returns amount of vCPU and memory in MiB of the instance type
+func instanceTypeSize(info ec2types.InstanceTypeInfo) (cpu int32, mem int64) {
+	if info.VCpuInfo != nil {
+		cpu = aws.ToInt32(info.VCpuInfo.DefaultVCpus)
+	}
+	if info.MemoryInfo != nil {
+		mem = aws.ToInt64(info.MemoryInfo.SizeInMiB)
+	}
+	return cpu, mem
+}
+
+// isCapacityError checks if the RunInstances error means that another instance type could succeed
+func isCapacityError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return util.Contains([]string{
+		"InsufficientInstanceCapacity",
+		"InsufficientHostCapacity",
+		"InstanceLimitExceeded",
+		"VcpuLimitExceeded",
+		"Unsupported",
+	}, apiErr.ErrorCode())
+}
@@ -16,6 +16,11 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
@@ -26,17 +31,31 @@ import (
 //
 //	image: ami-abcdef123456
 //	instance_type: c6a.4xlarge
+//	instance_type_fallbacks: [c6i.4xlarge, m6a.4xlarge]
 //	security_group: sg-abcdef123456
 //	tags:
 //	  somekey: somevalue
+//	instance_profile: fish-ci-worker
+//	metadata:
+//	  tokens: required
+//	  hop_limit: 1
+//	  tags: disabled
 type Options struct {
-	Image         string            `json:"image"`          // ID/Name of the image you want to use (name that contains * is usually a bad idea for reproducibility)
-	InstanceType  string            `json:"instance_type"`  // Type of the instance from aws available list
+	Image          string        `json:"image"`           // ID/Name of the image you want to use (name that contains * is usually a bad idea for reproducibility)
+	ImageFallbacks []ImageSource `json:"image_fallbacks"` // Where to look for the image in order if the main one is missing or deregistered
+	InstanceType   string        `json:"instance_type"`   // Type of the instance from aws available list
+
+	InstanceTypeFallbacks []string              `json:"instance_type_fallbacks"` // Types to try in order when the previous ones have no quota or capacity
+	InstanceRequirements  *InstanceRequirements `json:"instance_requirements"`   // Attributes to find the suitable types when the listed ones are not available
+
 	SecurityGroup string            `json:"security_group"` // ID/Name of the security group to use for the instance
 	Tags          map[string]string `json:"tags"`           // Tags to add during instance creation
 	EncryptKey    string            `json:"encrypt_key"`    // Use specific encryption key for the new disks
 	Pool          string            `json:"pool"`           // Use machine from dedicated pool, otherwise will try to use one with auto-placement
 
+	InstanceProfile string          `json:"instance_profile"` // Name or ARN of the IAM instance profile to attach to the instance
+	Metadata        MetadataOptions `json:"metadata"`         // Instance metadata service options, overrides the driver config ones
+
 	UserDataFormat string `json:"userdata_format"` // If not empty - will store the resource metadata to userdata in defined format
 	UserDataPrefix string `json:"userdata_prefix"` // Optional if need to add custom prefix to the metadata key during formatting
 
@@ -45,6 +64,168 @@ type Options struct {
 	TaskImageEncryptKey string `json:"task_image_encrypt_key"` // KMS Key ID or Alias in format "alias/<name>" if need to re-encrypt the newly created AMI snapshots
 }
 
+// ImageSource describes the alternative location of the image
+//
+// Example:
+//
+//	image_fallbacks:
+//	  - image: my-image-v2          # Older version of the image
+//	  - image: ami-abcdef123456     # Copy in another region, will be copied to the current one
+//	    region: us-east-1
+//	  - image: my-image             # Image shared by another account
+//	    owner: "123456789012"
+type ImageSource struct {
+	Image  string `json:"image"`  // ID/Name of the image
+	Region string `json:"region"` // Region to look the image in, if differs from the driver one - the image will be copied
+	Owner  string `json:"owner"`  // Account ID which shares the image, the driver account_ids are used by default
+}
+
+// String returns human readable description of the source
+func (s ImageSource) String() string {
+	out := s.Image
+	if s.Region != "" {
+		out += "@" + s.Region
+	}
+	if s.Owner != "" {
+		out += " owned by " + s.Owner
+	}
+	return out
+}
+
+// Default limit of the instance types found by requirements to try
+const instanceRequirementsMax = 10
+
+// InstanceRequirements describes the instance types suitable for the label when the listed ones
+// are not available, the matching types are tried from the smallest to the largest
+//
+// Example:
+//
+//	instance_requirements:
+//	  min_cpu: 16     # By default uses the definition resources cpu
+//	  min_memory: 32  # In GiB, by default uses the definition resources ram
+//	  arch: arm64
+type InstanceRequirements struct {
+	MinCPU    int32  `json:"min_cpu"`    // Minimum amount of vCPUs
+	MinMemory int32  `json:"min_memory"` // Minimum amount of memory in GiB
+	Arch      string `json:"arch"`       // Processor architecture, should match the image one: "x86_64" (default) or "arm64"
+	Max       int    `json:"max"`        // Maximum amount of the matching types to try, default: 10
+}
+
+// String returns the requirements description
+func (r InstanceRequirements) String() string {
+	return fmt.Sprintf("cpu>=%d mem>=%dGiB arch=%s max=%d", r.MinCPU, r.MinMemory, r.Arch, r.Max)
+}
+
+// withDefaults fills the empty requirements from the definition resources cpu & ram
+func (r InstanceRequirements) withDefaults(cpu, ram uint) InstanceRequirements {
+	if r.MinCPU == 0 {
+		r.MinCPU = int32(cpu)
+	}
+	if r.MinMemory == 0 {
+		r.MinMemory = int32(ram)
+	}
+	if r.Arch == "" {
+		r.Arch = string(ec2types.ArchitectureTypeX8664)
+	}
+	if r.Max <= 0 {
+		r.Max = instanceRequirementsMax
+	}
+	return r
+}
+
+// listedInstanceTypes returns the instance type with the fallbacks without duplicates
+func (o *Options) listedInstanceTypes() (out []string) {
+	for _, typ := range append([]string{o.InstanceType}, o.InstanceTypeFallbacks...) {
+		if typ != "" && !util.Contains(out, typ) {
+			out = append(out, typ)
+		}
+	}
+	return out
+}
+
+// MetadataOptions controls how the instance can reach the instance metadata service (IMDS),
+// the empty values are not sent to AWS so its defaults are used
+//
+// Example:
+//
+//	metadata:
+//	  tokens: required  # Allow only IMDSv2 session-oriented requests
+//	  hop_limit: 1      # Containers running on the instance will not be able to reach IMDS
+//	  tags: disabled    # Do not expose the instance tags through IMDS
+type MetadataOptions struct {
+	Tokens   string `json:"tokens"`    // "required" to allow IMDSv2 only or "optional" to allow IMDSv1 too
+	HopLimit int32  `json:"hop_limit"` // Max number of network hops for the token response: 1-64
+	Tags     string `json:"tags"`      // "enabled" or "disabled" access to the instance tags through IMDS
+}
+
+// Validate checks the metadata options values
+func (m MetadataOptions) Validate() error {
+	if !util.Contains([]string{"", string(ec2types.HttpTokensStateRequired), string(ec2types.HttpTokensStateOptional)}, m.Tokens) {
+		return fmt.Errorf("AWS: Unsupported metadata tokens value: %q", m.Tokens)
+	}
+	if m.HopLimit < 0 || m.HopLimit > 64 {
+		return fmt.Errorf("AWS: Metadata hop limit should be in range 1-64: %d", m.HopLimit)
+	}
+	if !util.Contains([]string{"", string(ec2types.InstanceMetadataTagsStateEnabled), string(ec2types.InstanceMetadataTagsStateDisabled)}, m.Tags) {
+		return fmt.Errorf("AWS: Unsupported metadata tags value: %q", m.Tags)
+	}
+	return nil
+}
+
+// merge returns copy of the options overridden by the non-empty values of the other options
+func (m MetadataOptions) merge(other MetadataOptions) MetadataOptions {
+	if other.Tokens != "" {
+		m.Tokens = other.Tokens
+	}
+	if other.HopLimit != 0 {
+		m.HopLimit = other.HopLimit
+	}
+	if other.Tags != "" {
+		m.Tags = other.Tags
+	}
+	return m
+}
+
+// request converts the options to RunInstances metadata request or nil if nothing is set
+func (m MetadataOptions) request() *ec2types.InstanceMetadataOptionsRequest {
+	if m == (MetadataOptions{}) {
+		return nil
+	}
+	req := &ec2types.InstanceMetadataOptionsRequest{
+		HttpEndpoint: ec2types.InstanceMetadataEndpointStateEnabled,
+	}
+	if m.Tokens != "" {
+		req.HttpTokens = ec2types.HttpTokensState(m.Tokens)
+	}
+	if m.HopLimit != 0 {
+		req.HttpPutResponseHopLimit = aws.Int32(m.HopLimit)
+	}
+	if m.Tags != "" {
+		req.InstanceMetadataTags = ec2types.InstanceMetadataTagsState(m.Tags)
+	}
+	return req
+}
+
+// instanceProfile returns the IAM instance profile specification by name or ARN
+func (o *Options) instanceProfile() *ec2types.IamInstanceProfileSpecification {
+	if o.InstanceProfile == "" {
+		return nil
+	}
+	if strings.HasPrefix(o.InstanceProfile, "arn:") {
+		return &ec2types.IamInstanceProfileSpecification{Arn: aws.String(o.InstanceProfile)}
+	}
+	return &ec2types.IamInstanceProfileSpecification{Name: aws.String(o.InstanceProfile)}
+}
+
+// imageCacheKey returns the key to cache the resolved image for the same set of sources
+func (o *Options) imageCacheKey() string {
+	key := o.Image
+	for _, s := range o.ImageFallbacks {
+		key += "|" + s.Image + "@" + s.Region + "#" + s.Owner
+	}
+	return key
+}
+
 // Apply takes json and applies it to the options structure
 func (o *Options) Apply(options util.UnparsedJSON) error {
 	if err := json.Unmarshal([]byte(options), o); err != nil {
@@ -61,14 +242,36 @@ func (o *Options) Validate() error {
 		return fmt.Errorf("AWS: No EC2 image is specified")
 	}
 
+	for i, s := range o.ImageFallbacks {
+		if s.Image == "" {
+			return fmt.Errorf("AWS: No image is specified in fallback %d", i)
+		}
+	}
+
 	// Check instance type
-	if o.InstanceType == "" {
+	if o.InstanceType == "" && len(o.InstanceTypeFallbacks) == 0 && o.InstanceRequirements == nil {
 		return fmt.Errorf("AWS: No EC2 instance type is specified")
 	}
+	if o.InstanceRequirements != nil {
+		if !util.Contains([]string{"", string(ec2types.ArchitectureTypeX8664), string(ec2types.ArchitectureTypeArm64)}, o.InstanceRequirements.Arch) {
+			return fmt.Errorf("AWS: Unsupported instance requirements arch: %q", o.InstanceRequirements.Arch)
+		}
+		if o.InstanceRequirements.MinCPU < 0 || o.InstanceRequirements.MinMemory < 0 {
+			return fmt.Errorf("AWS: Instance requirements can't be negative")
+		}
+	}
+	// The dedicated hosts are allocated for the specific type, so the alternatives can't be used
+	if o.Pool != "" && (o.InstanceType == "" || len(o.InstanceTypeFallbacks) > 0 || o.InstanceRequirements != nil) {
+		return fmt.Errorf("AWS: Dedicated pool requires only instance_type to be specified")
+	}
 
 	if !util.Contains([]string{"", "json", "env", "ps1"}, o.UserDataFormat) {
 		return fmt.Errorf("AWS: Unsupported userdata format: %s", o.UserDataFormat)
 	}
 
+	if err := o.Metadata.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,119 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Verify the image fallbacks are parsed and validated
+func Test_options_image_fallbacks(t *testing.T) {
+	var opts Options
+	err := opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "t3.micro", "image_fallbacks": [
+		{"image": "my-image-v1"}, {"image": "ami-copy", "region": "us-east-1"}, {"image": "shared", "owner": "123"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Unable to apply options: %v", err)
+	}
+	if len(opts.ImageFallbacks) != 3 || opts.ImageFallbacks[1].Region != "us-east-1" || opts.ImageFallbacks[2].Owner != "123" {
+		t.Fatalf("Wrong fallbacks parsed: %v", opts.ImageFallbacks)
+	}
+	if key := opts.imageCacheKey(); key != "ami-main|my-image-v1@#|ami-copy@us-east-1#|shared@#123" {
+		t.Fatalf("imageCacheKey() = `%s`; want: `ami-main|my-image-v1@#|ami-copy@us-east-1#|shared@#123`", key)
+	}
+
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "t3.micro", "image_fallbacks": [{"region": "us-east-1"}]}`))
+	if err == nil {
+		t.Fatalf("Fallback without image should not pass validation")
+	}
+}
+
+// Verify the metadata options are merged with the config ones & converted to request
+func Test_options_metadata(t *testing.T) {
+	var opts Options
+	err := opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "t3.micro",
+		"instance_profile": "fish-worker", "metadata": {"hop_limit": 2, "tags": "enabled"}}`))
+	if err != nil {
+		t.Fatalf("Unable to apply options: %v", err)
+	}
+
+	if req := (MetadataOptions{}).request(); req != nil {
+		t.Fatalf("Empty metadata options should not produce request: %v", req)
+	}
+
+	cfg := MetadataOptions{Tokens: "required", HopLimit: 1, Tags: "disabled"}
+	req := cfg.merge(opts.Metadata).request()
+	if req.HttpTokens != "required" || *req.HttpPutResponseHopLimit != 2 || req.InstanceMetadataTags != "enabled" {
+		t.Fatalf("Wrong metadata request: tokens=%s hop=%d tags=%s", req.HttpTokens, *req.HttpPutResponseHopLimit, req.InstanceMetadataTags)
+	}
+
+	if p := opts.instanceProfile(); p == nil || p.Name == nil || *p.Name != "fish-worker" || p.Arn != nil {
+		t.Fatalf("Instance profile should be set by name: %v", p)
+	}
+	opts.InstanceProfile = "arn:aws:iam::123456789012:instance-profile/fish-worker"
+	if p := opts.instanceProfile(); p == nil || p.Arn == nil || p.Name != nil {
+		t.Fatalf("Instance profile should be set by ARN: %v", p)
+	}
+
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "t3.micro", "metadata": {"hop_limit": 65}}`))
+	if err == nil {
+		t.Fatalf("Hop limit out of range should not pass validation")
+	}
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "t3.micro", "metadata": {"tokens": "v2"}}`))
+	if err == nil {
+		t.Fatalf("Unknown tokens value should not pass validation")
+	}
+}
+
+// Verify the instance type alternatives are parsed and validated
+func Test_options_instance_types(t *testing.T) {
+	var opts Options
+	err := opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "c6a.4xlarge",
+		"instance_type_fallbacks": ["c6i.4xlarge", "c6a.4xlarge", "m6a.4xlarge"], "instance_requirements": {"arch": "arm64"}}`))
+	if err != nil {
+		t.Fatalf("Unable to apply options: %v", err)
+	}
+	if types := opts.listedInstanceTypes(); len(types) != 3 || types[0] != "c6a.4xlarge" || types[2] != "m6a.4xlarge" {
+		t.Fatalf("Wrong listed instance types: %q", types)
+	}
+
+	req := opts.InstanceRequirements.withDefaults(16, 32)
+	if req.MinCPU != 16 || req.MinMemory != 32 || req.Arch != "arm64" || req.Max != instanceRequirementsMax {
+		t.Fatalf("Wrong requirements defaults: %s", req)
+	}
+
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_requirements": {"min_cpu": 4}}`))
+	if err != nil {
+		t.Fatalf("Requirements should be enough to select the instance type: %v", err)
+	}
+	if types := opts.listedInstanceTypes(); len(types) != 0 {
+		t.Fatalf("No instance types should be listed: %q", types)
+	}
+
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_requirements": {"arch": "sparc"}}`))
+	if err == nil {
+		t.Fatalf("Unsupported arch should not pass validation")
+	}
+	opts = Options{}
+	err = opts.Apply(util.UnparsedJSON(`{"image": "ami-main", "instance_type": "mac2.metal", "instance_type_fallbacks": ["mac1.metal"], "pool": "macs"}`))
+	if err == nil {
+		t.Fatalf("Dedicated pool should not allow instance type fallbacks")
+	}
+}
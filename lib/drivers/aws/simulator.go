@@ -0,0 +1,309 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// Defaults of the dedicated pool simulation, the same as the Mac dedicated hosts have
+const (
+	simulatorDefaultMinUsage          = 24 * time.Hour
+	simulatorDefaultScrubbingDuration = 90 * time.Minute
+	simulatorDefaultInitialize        = 7 * time.Minute
+)
+
+// SimulatorWorkload is one Application to run on the simulated pool
+type SimulatorWorkload struct {
+	Start    time.Time     // When the Application was requested
+	Duration time.Duration // How long the Application is running after the instance is initialized
+}
+
+// SimulatorSettings describes the simulated dedicated pool
+type SimulatorSettings struct {
+	Max               uint          // Maximum amount of the dedicated hosts in the pool
+	ScrubbingDelay    time.Duration // Idle time before the scrubbing, 0 disables the optimization
+	ScrubbingDuration time.Duration // How long the host is not available after the instance
+	MinUsage          time.Duration // Minimal time the host is allocated before release
+	Initialize        time.Duration // How long the instance takes to boot
+	HostHourlyPrice   float64       // Cost of the allocated dedicated host per hour
+}
+
+// SimulatorMonth is the simulated usage of the pool in the calendar month
+type SimulatorMonth struct {
+	Month         string // In format "2006-01"
+	HostsMax      uint
+	HostHours     float64 // Billed hours of the hosts, the scrubbing is not billed
+	InstanceHours float64
+	QueueHours    float64 // Sum of the time the workloads waited for the host
+	Cost          float64
+}
+
+// SimulatorResult is the projection of the pool usage
+type SimulatorResult struct {
+	HostsMax     uint
+	InstancesMax uint
+	QueueMax     uint
+	QueueWaitMax time.Duration
+	Unserved     uint // Workloads which were not able to get the host at all
+	Cost         float64
+	Months       []SimulatorMonth
+}
+
+type simHostState int
+
+const (
+	simHostAvailable simHostState = iota
+	simHostBusy
+	simHostScrubbing
+	simHostReleased
+)
+
+type simHost struct {
+	state       simHostState
+	allocatedAt time.Time
+	availableAt time.Time // Start of the billed period
+	idleGen     int       // Increased on each state change to skip the outdated idle events
+}
+
+type simEventKind int
+
+const (
+	simEventArrival simEventKind = iota
+	simEventTerminate
+	simEventAvailable
+	simEventIdle
+)
+
+type simEvent struct {
+	at       time.Time
+	seq      int
+	kind     simEventKind
+	host     *simHost
+	gen      int
+	workload int
+}
+
+type simEvents []*simEvent
+
+func (e simEvents) Len() int { return len(e) }
+func (e simEvents) Less(i, j int) bool {
+	if e[i].at.Equal(e[j].at) {
+		return e[i].seq < e[j].seq
+	}
+	return e[i].at.Before(e[j].at)
+}
+func (e simEvents) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e *simEvents) Push(x any)   { *e = append(*e, x.(*simEvent)) }
+func (e *simEvents) Pop() any {
+	old := *e
+	n := len(old)
+	x := old[n-1]
+	*e = old[:n-1]
+	return x
+}
+
+type simulator struct {
+	settings  SimulatorSettings
+	workloads []SimulatorWorkload
+
+	events simEvents
+	seq    int
+	queue  []int
+	hosts  map[*simHost]struct{}
+	busy   uint
+
+	result SimulatorResult
+	months map[string]*SimulatorMonth
+}
+
+// Simulate runs the workloads on the dedicated pool with the provided settings and returns the
+// projected hosts usage and cost. It's the same model the driver uses for the Mac pools: the host
+// goes to scrubbing after each instance and is released when it's idle after the minimal usage.
+func Simulate(workloads []SimulatorWorkload, settings SimulatorSettings) SimulatorResult {
+	if settings.MinUsage <= 0 {
+		settings.MinUsage = simulatorDefaultMinUsage
+	}
+	if settings.ScrubbingDuration <= 0 {
+		settings.ScrubbingDuration = simulatorDefaultScrubbingDuration
+	}
+	if settings.Initialize <= 0 {
+		settings.Initialize = simulatorDefaultInitialize
+	}
+
+	s := &simulator{
+		settings:  settings,
+		workloads: workloads,
+		hosts:     make(map[*simHost]struct{}),
+		months:    make(map[string]*SimulatorMonth),
+	}
+	for i, wl := range workloads {
+		s.add(wl.Start, simEventArrival, nil, i)
+	}
+
+	for s.events.Len() > 0 {
+		evt := heap.Pop(&s.events).(*simEvent)
+		s.process(evt)
+	}
+	s.result.Unserved = uint(len(s.queue))
+
+	for _, m := range s.months {
+		m.Cost = m.HostHours * settings.HostHourlyPrice
+		s.result.Cost += m.Cost
+		s.result.Months = append(s.result.Months, *m)
+	}
+	sort.Slice(s.result.Months, func(i, j int) bool {
+		return s.result.Months[i].Month < s.result.Months[j].Month
+	})
+
+	return s.result
+}
+
+func (s *simulator) add(at time.Time, kind simEventKind, host *simHost, workload int) {
+	evt := &simEvent{at: at, seq: s.seq, kind: kind, host: host, workload: workload}
+	if host != nil {
+		evt.gen = host.idleGen
+	}
+	s.seq++
+	heap.Push(&s.events, evt)
+}
+
+func (s *simulator) process(evt *simEvent) {
+	now := evt.at
+	switch evt.kind {
+	case simEventArrival:
+		s.queue = append(s.queue, evt.workload)
+	case simEventTerminate:
+		s.busy--
+		// Mac host is going to scrubbing after the instance, unless it's time to release it
+		if !s.release(evt.host, now) {
+			s.scrub(evt.host, now)
+		}
+	case simEventAvailable:
+		if evt.host.state != simHostScrubbing {
+			return
+		}
+		evt.host.state = simHostAvailable
+		evt.host.availableAt = now
+		evt.host.idleGen++
+		// Without the scrubbing optimization the idle host waits for the release time
+		idleAt := evt.host.allocatedAt.Add(s.settings.MinUsage)
+		if s.settings.ScrubbingDelay > 0 || idleAt.Before(now) {
+			idleAt = now.Add(s.settings.ScrubbingDelay)
+		}
+		s.add(idleAt, simEventIdle, evt.host, 0)
+	case simEventIdle:
+		// Host could be busy or scrubbed since the event was created
+		if evt.host.state != simHostAvailable || evt.gen != evt.host.idleGen {
+			return
+		}
+		if !s.release(evt.host, now) && s.settings.ScrubbingDelay > 0 {
+			s.scrub(evt.host, now)
+		}
+	}
+
+	s.dispatch(now)
+	s.result.QueueMax = max(s.result.QueueMax, uint(len(s.queue)))
+}
+
+// dispatch runs the queued workloads on the available or new hosts
+func (s *simulator) dispatch(now time.Time) {
+	for len(s.queue) > 0 {
+		host := s.availableHost(now)
+		if host == nil {
+			break
+		}
+		wl := s.workloads[s.queue[0]]
+		s.queue = s.queue[1:]
+
+		host.state = simHostBusy
+		host.idleGen++
+		s.busy++
+		s.result.InstancesMax = max(s.result.InstancesMax, s.busy)
+
+		wait := now.Sub(wl.Start)
+		s.result.QueueWaitMax = max(s.result.QueueWaitMax, wait)
+		s.addHours(wl.Start, now, func(m *SimulatorMonth, h float64) { m.QueueHours += h })
+		started := now.Add(s.settings.Initialize)
+		s.addHours(started, started.Add(wl.Duration), func(m *SimulatorMonth, h float64) { m.InstanceHours += h })
+
+		s.add(started.Add(wl.Duration), simEventTerminate, host, 0)
+	}
+}
+
+// availableHost returns the idle host or allocates the new one if the pool is not full
+func (s *simulator) availableHost(now time.Time) *simHost {
+	var found *simHost
+	for host := range s.hosts {
+		// Prefer the oldest host to let the new ones to become releasable later together
+		if host.state == simHostAvailable && (found == nil || host.allocatedAt.Before(found.allocatedAt)) {
+			found = host
+		}
+	}
+	if found != nil || uint(len(s.hosts)) >= s.settings.Max {
+		return found
+	}
+
+	host := &simHost{state: simHostAvailable, allocatedAt: now, availableAt: now}
+	s.hosts[host] = struct{}{}
+	s.result.HostsMax = max(s.result.HostsMax, uint(len(s.hosts)))
+	month := s.month(now)
+	month.HostsMax = max(month.HostsMax, uint(len(s.hosts)))
+
+	return host
+}
+
+// release returns true if the host was old enough and released
+func (s *simulator) release(host *simHost, now time.Time) bool {
+	if now.Before(host.allocatedAt.Add(s.settings.MinUsage)) {
+		return false
+	}
+	s.addHours(host.availableAt, now, func(m *SimulatorMonth, h float64) { m.HostHours += h })
+	host.state = simHostReleased
+	host.idleGen++
+	delete(s.hosts, host)
+	return true
+}
+
+// scrub stops the billing of the host until it's available again
+func (s *simulator) scrub(host *simHost, now time.Time) {
+	s.addHours(host.availableAt, now, func(m *SimulatorMonth, h float64) { m.HostHours += h })
+	host.state = simHostScrubbing
+	host.idleGen++
+	s.add(now.Add(s.settings.ScrubbingDuration), simEventAvailable, host, 0)
+}
+
+func (s *simulator) month(t time.Time) *SimulatorMonth {
+	key := t.UTC().Format("2006-01")
+	m, ok := s.months[key]
+	if !ok {
+		m = &SimulatorMonth{Month: key}
+		s.months[key] = m
+	}
+	return m
+}
+
+// addHours splits the period by calendar months and adds the hours to them
+func (s *simulator) addHours(from, to time.Time, fn func(*SimulatorMonth, float64)) {
+	from, to = from.UTC(), to.UTC()
+	for from.Before(to) {
+		next := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if next.After(to) {
+			next = to
+		}
+		fn(s.month(from), next.Sub(from).Hours())
+		from = next
+	}
+}
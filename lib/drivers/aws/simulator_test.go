@@ -0,0 +1,88 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Verify the simulated pool reuses the hosts after scrubbing and queues the workloads
+func Test_simulate(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	workloads := []SimulatorWorkload{
+		{Start: start, Duration: time.Hour},
+		{Start: start, Duration: time.Hour},
+		{Start: start.Add(3 * time.Hour), Duration: time.Hour},
+	}
+	settings := SimulatorSettings{
+		Max:               1,
+		ScrubbingDuration: time.Hour,
+		Initialize:        time.Minute,
+		HostHourlyPrice:   2,
+	}
+
+	t.Run("one host", func(t *testing.T) {
+		res := Simulate(workloads, settings)
+		if res.HostsMax != 1 || res.InstancesMax != 1 || res.Unserved != 0 {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+		if res.QueueMax != 1 {
+			t.Fatalf("Expected 1 workload in queue, got: %d", res.QueueMax)
+		}
+		// Second workload waits for the first one and the scrubbing
+		if res.QueueWaitMax != 2*time.Hour+time.Minute {
+			t.Fatalf("Unexpected max queue wait: %s", res.QueueWaitMax)
+		}
+		// The host is released after 24h of usage and the period goes through the month end
+		if len(res.Months) != 2 || res.Months[0].Month != "2024-01" || res.Months[1].Month != "2024-02" {
+			t.Fatalf("Unexpected months: %+v", res.Months)
+		}
+		var hours float64
+		for _, m := range res.Months {
+			hours += m.HostHours
+		}
+		// 24h total minus 3 scrubbing hours which are not billed
+		if math.Abs(hours-21) > 0.001 || math.Abs(res.Cost-42) > 0.001 {
+			t.Fatalf("Unexpected host hours %f or cost %f", hours, res.Cost)
+		}
+	})
+
+	t.Run("more hosts", func(t *testing.T) {
+		s := settings
+		s.Max = 2
+		res := Simulate(workloads, s)
+		if res.HostsMax != 2 || res.InstancesMax != 2 || res.QueueWaitMax != 0 {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("scrubbing delay saves the idle hours", func(t *testing.T) {
+		s := settings
+		s.ScrubbingDelay = 30 * time.Minute
+		res := Simulate(workloads, s)
+		if res.Cost >= 42 || res.HostsMax != 1 {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("empty pool", func(t *testing.T) {
+		s := settings
+		s.Max = 0
+		res := Simulate(workloads, s)
+		if res.HostsMax != 0 || res.Unserved != 3 || res.Cost != 0 {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+	})
+}
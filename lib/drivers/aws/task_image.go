@@ -33,6 +33,7 @@ type TaskImage struct {
 	*types.ApplicationTask `json:"-"` // Info about the requested task
 	*types.LabelDefinition `json:"-"` // Info about the used label definition
 	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
 
 	Full bool `json:"full"` // Make full (all disks including connected disks), or just the root OS disk image
 }
@@ -67,6 +68,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 		return []byte(`{"error":"internal: invalid resource"}`), log.Errorf("AWS: Invalid resource: %v", t.Resource)
 	}
 	log.Infof("AWS: TaskImage %s: Creating image for Application %s", t.ApplicationTask.UID, t.ApplicationTask.ApplicationUID)
+	t.Output("Creating image for Application %s", t.ApplicationTask.ApplicationUID)
 	conn := t.driver.newEC2Conn()
 
 	var opts Options
@@ -82,6 +84,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 		}
 
 		log.Infof("AWS: TaskImage %s: Stopping instance %q", t.ApplicationTask.UID, t.Resource.Identifier)
+		t.Output("Stopping instance %q", t.Resource.Identifier)
 		result, err := conn.StopInstances(context.TODO(), &input)
 		if err != nil {
 			// Do not fail hard here - it's still possible to take image of the instance
@@ -197,6 +200,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 	if t.ApplicationTask.When == types.ApplicationStatusDEALLOCATE {
 		// Wait for instance stopped before going forward with image creation
 		log.Infof("AWS: TaskImage %s: Wait for instance %q stopping...", t.ApplicationTask.UID, t.Resource.Identifier)
+		t.Output("Wait for instance %q stopping...", t.Resource.Identifier)
 		sw := ec2.NewInstanceStoppedWaiter(conn)
 		maxWait := 10 * time.Minute
 		waitInput := ec2.DescribeInstancesInput{
@@ -220,9 +224,11 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 
 	imageID := aws.ToString(resp.ImageId)
 	log.Infof("AWS: TaskImage %s: Created image %q with id %q...", t.ApplicationTask.UID, aws.ToString(input.Name), imageID)
+	t.Output("Created image %q with id %q...", aws.ToString(input.Name), imageID)
 
 	// Wait for the image to be completed, otherwise if we will start a copy - it will fail...
 	log.Infof("AWS: TaskImage %s: Wait for image %s %q availability...", t.ApplicationTask.UID, imageID, aws.ToString(input.Name))
+	t.Output("Wait for image %s %q availability...", imageID, aws.ToString(input.Name))
 	sw := ec2.NewImageAvailableWaiter(conn)
 	maxWait := time.Duration(t.driver.cfg.ImageCreateWait)
 	waitInput := ec2.DescribeImagesInput{
@@ -253,6 +259,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 			KmsKeyId:      aws.String(opts.TaskImageEncryptKey),
 		}
 		log.Infof("AWS: TaskImage %s: Re-encrypting tmp image to final image %q", t.ApplicationTask.UID, aws.ToString(copyInput.Name))
+		t.Output("Re-encrypting tmp image to final image %q", aws.ToString(copyInput.Name))
 		resp, err := conn.CopyImage(context.TODO(), &copyInput)
 		if err != nil {
 			return []byte(`{"error":"internal: failed to copy image"}`), log.Errorf("AWS: Unable to copy image from tmp image %s: %v", aws.ToString(resp.ImageId), err)
@@ -262,6 +269,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 		}
 		// Wait for the image to be completed, otherwise if we will delete the temp one right away it will fail...
 		log.Infof("AWS: TaskImage %s: Wait for re-encrypted image %s %q availability...", t.ApplicationTask.UID, aws.ToString(resp.ImageId), imageName)
+		t.Output("Wait for re-encrypted image %s %q availability...", aws.ToString(resp.ImageId), imageName)
 		sw := ec2.NewImageAvailableWaiter(conn)
 		maxWait := time.Duration(t.driver.cfg.ImageCreateWait)
 		waitInput := ec2.DescribeImagesInput{
@@ -284,6 +292,7 @@ func (t *TaskImage) Execute() (result []byte, err error) {
 	}
 
 	log.Infof("AWS: Created image for the instance %s: %s %q", t.Resource.Identifier, imageID, imageName)
+	t.Output("Created image for the instance %s: %s %q", t.Resource.Identifier, imageID, imageName)
 
 	return json.Marshal(map[string]string{"image": imageID, "image_name": imageName})
 }
@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// TaskPower stores the suspend, resume or reboot task data
+type TaskPower struct {
+	driver *Driver
+	action string
+
+	*types.ApplicationTask `json:"-"` // Info about the requested task
+	*types.LabelDefinition `json:"-"` // Info about the used label definition
+	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
+
+	Hibernate bool `json:"hibernate"` // Suspend hibernates the instance to keep the memory, it has to be enabled for the instance
+}
+
+// Name returns name of the task
+func (t *TaskPower) Name() string {
+	return t.action
+}
+
+// Clone makes a copy of the initial task to execute
+func (t *TaskPower) Clone() drivers.ResourceDriverTask {
+	n := *t
+	return &n
+}
+
+// SetInfo defines information of the environment
+func (t *TaskPower) SetInfo(task *types.ApplicationTask, def *types.LabelDefinition, res *types.Resource) {
+	t.ApplicationTask = task
+	t.LabelDefinition = def
+	t.Resource = res
+}
+
+// Execute - Power tasks could be executed during ALLOCATED ApplicationStatus
+func (t *TaskPower) Execute() (result []byte, err error) {
+	if t.ApplicationTask == nil {
+		return []byte(`{"error":"internal: invalid application task"}`), log.Error("AWS: Invalid application task:", t.ApplicationTask)
+	}
+	if t.Resource == nil || t.Resource.Identifier == "" {
+		return []byte(`{"error":"internal: invalid resource"}`), log.Error("AWS: Invalid resource:", t.Resource)
+	}
+	log.Infof("AWS: TaskPower %s: Executing %s of instance %q", t.ApplicationTask.UID, t.action, t.Resource.Identifier)
+	t.Output("Executing %s of instance %q...", t.action, t.Resource.Identifier)
+	conn := t.driver.newEC2Conn()
+	ids := []string{t.Resource.Identifier}
+	waitInput := ec2.DescribeInstancesInput{InstanceIds: ids}
+	maxWait := 10 * time.Minute
+
+	status := "running"
+	switch t.action {
+	case drivers.TaskSuspend:
+		status = "suspended"
+		if _, err = conn.StopInstances(context.TODO(), &ec2.StopInstancesInput{InstanceIds: ids, Hibernate: aws.Bool(t.Hibernate)}); err != nil {
+			return []byte(`{"error":"internal: failed to stop the instance"}`), log.Errorf("AWS: Unable to stop instance %s: %v", t.Resource.Identifier, err)
+		}
+		err = ec2.NewInstanceStoppedWaiter(conn).Wait(context.TODO(), &waitInput, maxWait)
+	case drivers.TaskResume:
+		if _, err = conn.StartInstances(context.TODO(), &ec2.StartInstancesInput{InstanceIds: ids}); err != nil {
+			return []byte(`{"error":"internal: failed to start the instance"}`), log.Errorf("AWS: Unable to start instance %s: %v", t.Resource.Identifier, err)
+		}
+		err = ec2.NewInstanceRunningWaiter(conn).Wait(context.TODO(), &waitInput, maxWait)
+	case drivers.TaskReboot:
+		// Reboot is just queued by AWS and the instance stays running
+		if _, err = conn.RebootInstances(context.TODO(), &ec2.RebootInstancesInput{InstanceIds: ids}); err != nil {
+			return []byte(`{"error":"internal: failed to reboot the instance"}`), log.Errorf("AWS: Unable to reboot instance %s: %v", t.Resource.Identifier, err)
+		}
+	}
+	if err != nil {
+		return []byte(`{"error":"internal: instance state wait failed"}`), log.Errorf("AWS: TaskPower %s: Error during wait for instance %s %s: %v", t.ApplicationTask.UID, t.Resource.Identifier, t.action, err)
+	}
+
+	t.Output("Instance %s is %s", t.Resource.Identifier, status)
+	return json.Marshal(map[string]any{"status": status})
+}
@@ -34,6 +34,7 @@ type TaskSnapshot struct {
 	*types.ApplicationTask `json:"-"` // Info about the requested task
 	*types.LabelDefinition `json:"-"` // Info about the used label definition
 	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
 
 	Full bool `json:"full"` // Make full (all disks including OS image), or just the additional disks snapshot
 }
@@ -68,6 +69,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 		return []byte(`{"error":"internal: invalid resource"}`), log.Error("AWS: Invalid resource:", t.Resource)
 	}
 	log.Infof("AWS: TaskSnapshot %s: Creating snapshot for Application %s", t.ApplicationTask.UID, t.ApplicationTask.ApplicationUID)
+	t.Output("Creating snapshot for Application %s", t.ApplicationTask.ApplicationUID)
 	conn := t.driver.newEC2Conn()
 
 	if t.ApplicationTask.When == types.ApplicationStatusDEALLOCATE {
@@ -77,6 +79,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 		}
 
 		log.Infof("AWS: TaskSnapshot %s: Stopping instance %q...", t.ApplicationTask.UID, t.Resource.Identifier)
+		t.Output("Stopping instance %q...", t.Resource.Identifier)
 		result, err := conn.StopInstances(context.TODO(), &input)
 		if err != nil {
 			// Do not fail hard here - it's still possible to take snapshot of the instance
@@ -140,6 +143,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 
 	// Wait for snapshots to be available...
 	log.Infof("AWS: TaskSnapshot %s: Wait for snapshots %s availability...", t.ApplicationTask.UID, snapshots)
+	t.Output("Wait for snapshots %s availability...", snapshots)
 	sw := ec2.NewSnapshotCompletedWaiter(conn)
 	maxWait := time.Duration(t.driver.cfg.SnapshotCreateWait)
 	waitInput := ec2.DescribeSnapshotsInput{
@@ -151,6 +155,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 	}
 
 	log.Infof("AWS: TaskSnapshot %s: Created snapshots for instance %s: %s", t.ApplicationTask.UID, t.Resource.Identifier, strings.Join(snapshots, ", "))
+	t.Output("Created snapshots for instance %s: %s", t.Resource.Identifier, strings.Join(snapshots, ", "))
 
 	return json.Marshal(map[string]any{"snapshots": snapshots})
 }
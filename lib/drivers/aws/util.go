@@ -14,6 +14,7 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,61 +24,32 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/smithy-go"
 
+	"github.com/adobe/aquarium-fish/lib/drivers"
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
 )
 
 func (d *Driver) newEC2Conn() *ec2.Client {
-	return ec2.NewFromConfig(aws.Config{
-		Region: d.cfg.Region,
-		Credentials: aws.CredentialsProviderFunc(func(_ /*ctx*/ context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     d.cfg.KeyID,
-				SecretAccessKey: d.cfg.SecretKey,
-				Source:          "fish-cfg",
-			}, nil
-		}),
-
-		// Using retries in order to handle the transient errors:
-		// https://docs.aws.amazon.com/prescriptive-guidance/latest/cloud-design-patterns/retry-backoff.html
-		RetryMaxAttempts: 5,
-		RetryMode:        aws.RetryModeStandard,
+	return d.newEC2ConnRegion(d.cfg.Region)
+}
+
+func (d *Driver) newEC2ConnRegion(region string) *ec2.Client {
+	return ec2.NewFromConfig(d.cfg.awsConfig(region, 5), func(o *ec2.Options) {
+		o.BaseEndpoint = d.cfg.endpoint(region, d.cfg.Endpoints.EC2)
 	})
 }
 
 func (d *Driver) newKMSConn() *kms.Client {
-	return kms.NewFromConfig(aws.Config{
-		Region: d.cfg.Region,
-		Credentials: aws.CredentialsProviderFunc(func(_ /*ctx*/ context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     d.cfg.KeyID,
-				SecretAccessKey: d.cfg.SecretKey,
-				Source:          "fish-cfg",
-			}, nil
-		}),
-
-		// Using retries in order to handle the transient errors:
-		// https://docs.aws.amazon.com/prescriptive-guidance/latest/cloud-design-patterns/retry-backoff.html
-		RetryMaxAttempts: 5,
-		RetryMode:        aws.RetryModeStandard,
+	return kms.NewFromConfig(d.cfg.awsConfig(d.cfg.Region, 5), func(o *kms.Options) {
+		o.BaseEndpoint = d.cfg.endpoint(d.cfg.Region, d.cfg.Endpoints.KMS)
 	})
 }
 
 func (d *Driver) newServiceQuotasConn() *servicequotas.Client {
-	return servicequotas.NewFromConfig(aws.Config{
-		Region: d.cfg.Region,
-		Credentials: aws.CredentialsProviderFunc(func(_ /*ctx*/ context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     d.cfg.KeyID,
-				SecretAccessKey: d.cfg.SecretKey,
-				Source:          "fish-cfg",
-			}, nil
-		}),
-
-		// Using retries in order to handle the transient errors:
-		// https://docs.aws.amazon.com/prescriptive-guidance/latest/cloud-design-patterns/retry-backoff.html
-		RetryMaxAttempts: 5,
-		RetryMode:        aws.RetryModeStandard,
+	return servicequotas.NewFromConfig(d.cfg.awsConfig(d.cfg.Region, 5), func(o *servicequotas.Options) {
+		o.BaseEndpoint = d.cfg.endpoint(d.cfg.Region, d.cfg.Endpoints.ServiceQuotas)
 	})
 }
 
@@ -225,7 +197,11 @@ func (d *Driver) getImageID(conn *ec2.Client, idName string) (string, error) {
 	if strings.HasPrefix(idName, "ami-") {
 		return idName, nil
 	}
+	return d.getImageIDByName(conn, idName, d.cfg.AccountIDs)
+}
 
+// getImageIDByName looks for the latest available image with the name owned by the accounts
+func (*Driver) getImageIDByName(conn *ec2.Client, idName string, owners []string) (string, error) {
 	log.Debug("AWS: Looking for image name:", idName)
 
 	// Look for image with the defined name
@@ -240,7 +216,7 @@ func (d *Driver) getImageID(conn *ec2.Client, idName string) (string, error) {
 				Values: []string{"available"},
 			},
 		},
-		Owners: d.cfg.AccountIDs,
+		Owners: owners,
 	}
 	p := ec2.NewDescribeImagesPaginator(conn, &req)
 	resp, err := conn.DescribeImages(context.TODO(), &req)
@@ -521,6 +497,39 @@ func (*Driver) getInstance(conn *ec2.Client, instID string) (*types.Instance, er
 	return &resp.Reservations[0].Instances[0], nil
 }
 
+// Makes sure the instance profile exists and could be passed to the instance. There is no IAM
+// client used by the driver, so it's checking through RunInstances dry run which validates the
+// profile before the actual run - any unrelated errors are left for the real request to report
+func (*Driver) checkInstanceProfile(conn *ec2.Client, profile *types.IamInstanceProfileSpecification, image, instanceType string) error {
+	input := ec2.RunInstancesInput{
+		DryRun:             aws.Bool(true),
+		ImageId:            aws.String(image),
+		InstanceType:       types.InstanceType(instanceType),
+		IamInstanceProfile: profile,
+
+		MinCount: aws.Int32(1),
+		MaxCount: aws.Int32(1),
+	}
+
+	_, err := conn.RunInstances(context.TODO(), &input)
+	var apiErr smithy.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.ErrorCode() {
+	case "DryRunOperation":
+		return nil
+	case "InvalidParameterValue":
+		if strings.Contains(strings.ToLower(apiErr.ErrorMessage()), "iaminstanceprofile") {
+			return fmt.Errorf("Instance profile is not found: %s", apiErr.ErrorMessage())
+		}
+	case "UnauthorizedOperation":
+		return fmt.Errorf("Not allowed to run instance with the instance profile (check iam:PassRole): %s", apiErr.ErrorMessage())
+	}
+	log.Debug("AWS: Instance profile dry run returned unrelated error:", err)
+	return nil
+}
+
 // Will get the kms key id based on alias if it's specified
 func (d *Driver) getKeyID(idAlias string) (string, error) {
 	if !strings.HasPrefix(idAlias, "alias/") {
@@ -612,6 +621,21 @@ func awsInstTypeAny(val string, options ...string) bool {
 	return false
 }
 
+// instanceInfo collects the driver info of the Resource described by InfoSchema
+func instanceInfo(inst *types.Instance) util.UnparsedJSON {
+	info := map[string]any{
+		"instance_type": string(inst.InstanceType),
+		"image_id":      aws.ToString(inst.ImageId),
+	}
+	if inst.Placement != nil {
+		info["availability_zone"] = aws.ToString(inst.Placement.AvailabilityZone)
+		if inst.Placement.HostId != nil {
+			info["host_id"] = aws.ToString(inst.Placement.HostId)
+		}
+	}
+	return drivers.InfoJSON(info)
+}
+
 /**
  * Trigger Host Scrubbing process
  *
@@ -49,6 +49,13 @@ type Config struct {
 	CPUOverbook uint `json:"cpu_overbook"` // How much CPUs could be reused by multiple tenants
 	RAMOverbook uint `json:"ram_overbook"` // How much RAM (GB) could be reused by multiple tenants
 
+	// Burst overcommit allows to place more bursting containers on the node, since they rarely
+	// use the whole limit at the same time. The burstable part of the container (burst limit -
+	// guaranteed) is divided by overcommit to account in the node capacity. For example container
+	// with 2 guaranteed and 6 burst CPUs with cpu_burst_overcommit: 4 takes 2 + (6-2)/4 = 3 CPUs.
+	CPUBurstOvercommit float64 `json:"cpu_burst_overcommit"` // 1 (default) accounts the whole CPU burst limit
+	RAMBurstOvercommit float64 `json:"ram_burst_overcommit"` // 1 (default) accounts the whole RAM burst limit
+
 	DownloadUser     string `json:"download_user"`     // The user will be used in download operations
 	DownloadPassword string `json:"download_password"` // The password will be used in download operations
 }
@@ -76,6 +83,16 @@ func (c *Config) Validate() (err error) {
 	if c.ImagesPath == "" {
 		c.ImagesPath = "fish_docker_images"
 	}
+
+	if c.CPUBurstOvercommit == 0 {
+		c.CPUBurstOvercommit = 1
+	}
+	if c.RAMBurstOvercommit == 0 {
+		c.RAMBurstOvercommit = 1
+	}
+	if c.CPUBurstOvercommit < 1 || c.RAMBurstOvercommit < 1 {
+		return log.Errorf("Docker: Burst overcommit can't be less than 1: %v, %v", c.CPUBurstOvercommit, c.RAMBurstOvercommit)
+	}
 	if c.WorkspacePath == "" {
 		c.WorkspacePath = "fish_docker_workspace"
 	}
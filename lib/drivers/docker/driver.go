@@ -31,6 +31,12 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
+// Container labels to find the owner of the container
+const (
+	labelNode        = "aquarium.fish.node"
+	labelApplication = "aquarium.fish.application"
+)
+
 // Factory implements drivers.ResourceDriverFactory interface
 type Factory struct{}
 
@@ -59,6 +65,9 @@ type Driver struct {
 
 	dockerUsageMutex sync.Mutex
 	dockerUsage      types.Resources // Used when the docker is remote
+
+	burstUsageMutex sync.Mutex
+	burstUsage      types.Resources // Accounted burst part of the containers, used when the docker is local
 }
 
 // Name returns name of the driver
@@ -104,25 +113,53 @@ func (d *Driver) Prepare(config []byte) error {
 
 	// Collect the current state of docker containers for validation (for example not controlled
 	// containers) purposes - it will be actively used if docker driver is remote
-	d.dockerUsage, err = d.getInitialUsage()
+	d.dockerUsage, d.burstUsage, err = d.getInitialUsage()
 	if err != nil {
 		return err
 	}
 
+	// Fill up the available tasks to execute
+	d.tasksList = append(d.tasksList,
+		&TaskPower{driver: d, action: drivers.TaskSuspend},
+		&TaskPower{driver: d, action: drivers.TaskResume},
+		&TaskPower{driver: d, action: drivers.TaskReboot},
+	)
+
 	// TODO: Cleanup the image directory in case the images are not good
 	return nil
 }
 
+// Capabilities of the driver, the disks are the directories or the filesystem images
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"dir", "hfs+", "exfat", "fat32"},
+		DiskReuse: true,
+		Networks:  []string{"", "nat"},
+		Tenancy:   true,
+	}
+}
+
 // ValidateDefinition checks LabelDefinition is ok
-func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
 	// Check resources
-	if err := def.Resources.Validate([]string{"dir", "hfs+", "exfat", "fat32"}, true); err != nil {
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
 		return log.Error("Docker: Resources validation failed:", err)
 	}
 
 	// Check options
 	var opts Options
-	return opts.Apply(def.Options)
+	if err := opts.Apply(def.Options); err != nil {
+		return err
+	}
+	if opts.Burst.CPU > 0 && opts.Burst.CPU < def.Resources.Cpu {
+		return fmt.Errorf("Docker: Burst CPU can't be less than the definition cpu: %d < %d", opts.Burst.CPU, def.Resources.Cpu)
+	}
+	if opts.Burst.RAM > 0 && opts.Burst.RAM < def.Resources.Ram {
+		return fmt.Errorf("Docker: Burst RAM can't be less than the definition ram: %d < %d", opts.Burst.RAM, def.Resources.Ram)
+	}
+
+	return nil
 }
 
 // AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
@@ -134,15 +171,30 @@ func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDef
 		d.dockerUsageMutex.Lock()
 		nodeUsage = d.dockerUsage
 		d.dockerUsageMutex.Unlock()
+	} else {
+		// Fish knows only the guaranteed resources of the local containers
+		d.burstUsageMutex.Lock()
+		nodeUsage.Cpu += d.burstUsage.Cpu
+		nodeUsage.Ram += d.burstUsage.Ram
+		d.burstUsageMutex.Unlock()
 	}
 
+	var opts Options
+	if err := opts.Apply(req.Options); err != nil {
+		return -1
+	}
+	// The container takes the guaranteed resources and the overcommitted part of the burst
+	burst := d.burstResources(req.Resources, opts.Burst)
+	reqCPU := req.Resources.Cpu + burst.Cpu
+	reqRAM := req.Resources.Ram + burst.Ram
+
 	availCPU, availRAM := d.getAvailResources()
 
 	// Check if the node has the required resources - otherwise we can't run it anyhow
-	if req.Resources.Cpu > availCPU {
+	if reqCPU > availCPU || opts.Burst.CPU > availCPU {
 		return 0
 	}
-	if req.Resources.Ram > availRAM {
+	if reqRAM > availRAM || opts.Burst.RAM > availRAM {
 		return 0
 	}
 	// TODO: Check disk requirements
@@ -167,8 +219,11 @@ func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDef
 	}
 
 	// Calculate how much of those definitions we could run
-	outCount = int64((availCPU - nodeUsage.Cpu) / req.Resources.Cpu)
-	ramCount := int64((availRAM - nodeUsage.Ram) / req.Resources.Ram)
+	if nodeUsage.Cpu > availCPU || nodeUsage.Ram > availRAM {
+		return 0
+	}
+	outCount = int64((availCPU - nodeUsage.Cpu) / reqCPU)
+	ramCount := int64((availRAM - nodeUsage.Ram) / reqRAM)
 	if outCount > ramCount {
 		outCount = ramCount
 	}
@@ -230,10 +285,12 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 		"--name", cName,
 		"--mac-address", cHwaddr,
 		"--network", "aquarium-" + cNetwork,
-		"--cpus", fmt.Sprintf("%d", def.Resources.Cpu),
-		"--memory", fmt.Sprintf("%dg", def.Resources.Ram),
 		"--pull", "never",
 	}
+	// Tagging the container to find the owner of it after the node crash
+	node, app := drivers.ListTags(metadata)
+	runArgs = append(runArgs, "--label", labelNode+"="+node, "--label", labelApplication+"="+app)
+	runArgs = append(runArgs, burstArgs(def.Resources, opts.Burst)...)
 
 	// Create and connect volumes to container
 	if err := d.disksCreate(cName, &runArgs, def.Resources.Disks); err != nil {
@@ -256,14 +313,40 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 		return nil, log.Error("Docker: Unable to run container", cName, err)
 	}
 
+	burst := d.burstResources(def.Resources, opts.Burst)
 	if d.cfg.IsRemote {
 		// Locked in the beginning of the function
 		d.dockerUsage.Add(def.Resources)
+		d.dockerUsage.Cpu += burst.Cpu
+		d.dockerUsage.Ram += burst.Ram
+	} else {
+		d.burstUsageMutex.Lock()
+		d.burstUsage.Cpu += burst.Cpu
+		d.burstUsage.Ram += burst.Ram
+		d.burstUsageMutex.Unlock()
 	}
 
 	log.Info("Docker: Allocate of Container completed:", cHwaddr, cName)
 
-	return &types.Resource{Identifier: cName, HwAddr: cHwaddr}, nil
+	info := map[string]any{"image": imgNameVersion, "network": cNetwork}
+	// The digest is not critical for the resource, so just skipping it in case of error
+	stdout, _, err := util.RunAndLog("DOCKER", 5*time.Second, nil, d.cfg.DockerPath, "inspect", "--format", "{{ .Image }}", cName)
+	if err != nil {
+		log.Warn("Docker: Unable to get the container image digest:", cName, err)
+	} else {
+		info["image_digest"] = strings.TrimSpace(stdout)
+	}
+
+	return &types.Resource{Identifier: cName, HwAddr: cHwaddr, DriverInfo: drivers.InfoJSON(info)}, nil
+}
+
+// InfoSchema describes the container info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "image", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Image name and version of the container"},
+		{Name: "image_digest", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Digest of the container image"},
+		{Name: "network", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Network the container is connected to"},
+	}
 }
 
 // Status shows status of the resource
@@ -277,6 +360,14 @@ func (d *Driver) Status(res *types.Resource) (string, error) {
 	return drivers.StatusNone, nil
 }
 
+// SelfCheck makes sure the docker daemon is responding
+func (d *Driver) SelfCheck() error {
+	if _, _, err := util.RunAndLog("DOCKER", 5*time.Second, nil, d.cfg.DockerPath, "system", "info", "--format", "{{ .ID }}"); err != nil {
+		return fmt.Errorf("Docker: Daemon is not responding: %v", err)
+	}
+	return nil
+}
+
 // GetTask returns task struct by name
 func (d *Driver) GetTask(name, options string) drivers.ResourceDriverTask {
 	// Look for the specified task name
@@ -298,6 +389,24 @@ func (d *Driver) GetTask(name, options string) drivers.ResourceDriverTask {
 	return t
 }
 
+// List returns the containers created by the driver, they have the standardized names and labels
+func (d *Driver) List() ([]types.Resource, error) {
+	stdout, _, err := util.RunAndLog("DOCKER", 10*time.Second, nil, d.cfg.DockerPath, "ps", "-a", "--filter", "name=fish-",
+		"--format", `{{ .Names }}\t{{ .Label "`+labelNode+`" }}\t{{ .Label "`+labelApplication+`" }}`)
+	if err != nil {
+		return nil, fmt.Errorf("Docker: Unable to list the containers: %v", err)
+	}
+	out := []types.Resource{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := append(strings.Split(line, "\t"), "", "")
+		// The filter matches the substring, so checking the prefix
+		if strings.HasPrefix(fields[0], "fish-") {
+			out = append(out, drivers.ListResource(fields[0], fields[1], fields[2]))
+		}
+	}
+	return out, nil
+}
+
 // Deallocate the resource
 func (d *Driver) Deallocate(res *types.Resource) error {
 	if res == nil || res.Identifier == "" {
@@ -323,14 +432,19 @@ func (d *Driver) Deallocate(res *types.Resource) error {
 	}
 	cVolumes := strings.Split(strings.TrimSpace(stdout), "\n")
 
+	// Get the container CPU/RAM to subtract from the usage
+	cRes, cBurst, err := d.getContainersResources([]string{cID})
+	if err != nil {
+		return log.Error("Docker: Unable to collect the container resources:", cName, err)
+	}
 	if d.cfg.IsRemote {
-		// Get the container CPU/RAM to subtract from the docker_usage
-		res, err := d.getContainersResources([]string{cID})
-		if err != nil {
-			return log.Error("Docker: Unable to collect the container resources:", cName, err)
-		}
 		// Locked in the beginning of the function
-		d.dockerUsage.Subtract(res)
+		d.dockerUsage.Subtract(cRes)
+	} else {
+		d.burstUsageMutex.Lock()
+		d.burstUsage.Cpu -= min(cBurst.Cpu, d.burstUsage.Cpu)
+		d.burstUsage.Ram -= min(cBurst.Ram, d.burstUsage.Ram)
+		d.burstUsageMutex.Unlock()
 	}
 
 	// Stop the container
@@ -31,8 +31,21 @@ import (
 //	    sum: sha256:1234567890abcdef1234567890abcdef2
 //	  - url: https://artifact-storage/aquarium/image/docker/ubuntu2004-python3-ci/ubuntu2004-python3-ci-VERSION.tar.xz
 //	    sum: sha256:1234567890abcdef1234567890abcdef3
+//	burst:
+//	  cpu: 8
+//	  ram: 16
 type Options struct {
 	Images []drivers.Image `json:"images"` // List of image dependencies, last one is running one
+
+	Burst Burst `json:"burst"` // Allows the container to use more than the definition resources
+}
+
+// Burst limits of the container, the definition resources become the guaranteed ones:
+// * CPU: `--cpu-shares` (cgroup v2 cpu.weight) of the guaranteed vCPUs and `--cpus` (cpu.max) limit
+// * RAM: `--memory-reservation` (cgroup v2 memory.low) of the guaranteed RAM and `--memory` (memory.max) limit
+type Burst struct {
+	CPU uint `json:"cpu"` // Max vCPUs the container could use, 0 or the definition cpu disables CPU burst
+	RAM uint `json:"ram"` // Max RAM (GB) the container could use, 0 or the definition ram disables RAM burst
 }
 
 // Apply takes json and applies it to the options structure
@@ -0,0 +1,82 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package docker
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// TaskPower pauses, unpauses or restarts the container
+type TaskPower struct {
+	driver *Driver
+	action string
+
+	*types.ApplicationTask `json:"-"` // Info about the requested task
+	*types.LabelDefinition `json:"-"` // Info about the used label definition
+	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
+}
+
+// Name returns name of the task
+func (t *TaskPower) Name() string {
+	return t.action
+}
+
+// Clone makes a copy of the initial task to execute
+func (t *TaskPower) Clone() drivers.ResourceDriverTask {
+	n := *t
+	return &n
+}
+
+// SetInfo defines information of the environment
+func (t *TaskPower) SetInfo(task *types.ApplicationTask, def *types.LabelDefinition, res *types.Resource) {
+	t.ApplicationTask = task
+	t.LabelDefinition = def
+	t.Resource = res
+}
+
+// Execute - Power tasks could be executed during ALLOCATED ApplicationStatus
+func (t *TaskPower) Execute() (result []byte, err error) {
+	if t.ApplicationTask == nil {
+		return []byte(`{"error":"internal: invalid application task"}`), log.Error("Docker: Invalid application task:", t.ApplicationTask)
+	}
+	if t.Resource == nil || t.Resource.Identifier == "" {
+		return []byte(`{"error":"internal: invalid resource"}`), log.Error("Docker: Invalid resource:", t.Resource)
+	}
+	cID := t.driver.getAllocatedContainerID(t.Resource.Identifier)
+	if len(cID) == 0 {
+		return []byte(`{"error":"internal: container not found"}`), log.Error("Docker: Unable to find container with identifier:", t.Resource.Identifier)
+	}
+
+	// Suspended container keeps the processes in memory, so it could be resumed quickly
+	cmd, status := "restart", "running"
+	switch t.action {
+	case drivers.TaskSuspend:
+		cmd, status = "pause", "suspended"
+	case drivers.TaskResume:
+		cmd = "unpause"
+	}
+	t.Output("Executing %s of container %s...", cmd, t.Resource.Identifier)
+	if _, _, err := util.RunAndLog("DOCKER", 5*time.Minute, nil, t.driver.cfg.DockerPath, cmd, cID); err != nil {
+		return []byte(`{"error":"internal: failed to change the container state"}`), log.Errorf("Docker: Unable to %s container %s: %v", cmd, t.Resource.Identifier, err)
+	}
+
+	t.Output("Container %s is %s", t.Resource.Identifier, status)
+	return json.Marshal(map[string]any{"status": status})
+}
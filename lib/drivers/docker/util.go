@@ -15,6 +15,7 @@ package docker
 import (
 	"fmt"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -28,67 +29,111 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-func (d *Driver) getContainersResources(containerIDs []string) (types.Resources, error) {
-	var out types.Resources
-
-	// Getting current running containers info - will return "<ncpu>,<mem_bytes>\n..." for each one
-	dockerArgs := []string{"inspect", "--format", "{{ .HostConfig.NanoCpus }},{{ .HostConfig.Memory }}"}
+// getContainersResources returns the accounted resources of the containers and the burst part of it
+func (d *Driver) getContainersResources(containerIDs []string) (out, burst types.Resources, err error) {
+	// Getting current running containers info - will return "<ncpu>,<mem_bytes>,<cpu_shares>,<mem_reservation_bytes>\n..." for each one
+	dockerArgs := []string{"inspect", "--format", "{{ .HostConfig.NanoCpus }},{{ .HostConfig.Memory }},{{ .HostConfig.CpuShares }},{{ .HostConfig.MemoryReservation }}"}
 	dockerArgs = append(dockerArgs, containerIDs...)
 	stdout, _, err := util.RunAndLog("DOCKER", 5*time.Second, nil, d.cfg.DockerPath, dockerArgs...)
 	if err != nil {
-		return out, fmt.Errorf("Docker: Unable to inspect the containers to get used resources: %v", err)
+		return out, burst, fmt.Errorf("Docker: Unable to inspect the containers to get used resources: %v", err)
 	}
 
 	resList := strings.Split(strings.TrimSpace(stdout), "\n")
 	for _, res := range resList {
-		cpuMem := strings.Split(res, ",")
-		if len(cpuMem) < 2 {
-			return out, fmt.Errorf("Docker: Not enough info values in return: %q", resList)
-		}
-		resCPU, err := strconv.ParseUint(cpuMem[0], 10, 64)
-		if err != nil {
-			return out, fmt.Errorf("Docker: Unable to parse CPU uint: %v (%q)", err, cpuMem[0])
+		values := strings.Split(res, ",")
+		if len(values) < 4 {
+			return out, burst, fmt.Errorf("Docker: Not enough info values in return: %q", resList)
 		}
-		resRAM, err := strconv.ParseUint(cpuMem[1], 10, 64)
-		if err != nil {
-			return out, fmt.Errorf("Docker: Unable to parse RAM uint: %v (%q)", err, cpuMem[1])
+		var parsed [4]uint64
+		for i := range parsed {
+			if parsed[i], err = strconv.ParseUint(values[i], 10, 64); err != nil {
+				return out, burst, fmt.Errorf("Docker: Unable to parse uint: %v (%q)", err, values[i])
+			}
 		}
+		resCPU, resRAM := uint(parsed[0]/1000000000), uint(parsed[1]/1073741824) // Originallly in NCPU and bytes
 		if resCPU == 0 || resRAM == 0 {
-			return out, fmt.Errorf("Docker: The container is non-Fish controlled zero-cpu/ram ones: %q", containerIDs)
+			return out, burst, fmt.Errorf("Docker: The container is non-Fish controlled zero-cpu/ram ones: %q", containerIDs)
 		}
-		out.Cpu += uint(resCPU / 1000000000) // Originallly in NCPU
-		out.Ram += uint(resRAM / 1073741824) // Get in GB
+
+		// The bursting container has the guaranteed resources set as shares & reservation
+		guarCPU, guarRAM := resCPU, resRAM
+		if parsed[2] > 0 {
+			guarCPU = uint(parsed[2] / 1024)
+		}
+		if parsed[3] > 0 {
+			guarRAM = uint(parsed[3] / 1073741824)
+		}
+		burstCPU := burstAccount(guarCPU, resCPU, d.cfg.CPUBurstOvercommit)
+		burstRAM := burstAccount(guarRAM, resRAM, d.cfg.RAMBurstOvercommit)
+
+		out.Cpu += guarCPU + burstCPU
+		out.Ram += guarRAM + burstRAM
+		burst.Cpu += burstCPU
+		burst.Ram += burstRAM
 		// TODO: Add disks too here
 	}
 
-	return out, nil
+	return out, burst, nil
+}
+
+// burstAccount returns the accounted part of the burst over the guaranteed amount
+func burstAccount(guaranteed, limit uint, overcommit float64) uint {
+	if limit <= guaranteed {
+		return 0
+	}
+	return uint(math.Ceil(float64(limit-guaranteed) / overcommit))
+}
+
+// burstArgs returns the docker run CPU & RAM arguments, the definition resources are guaranteed
+// ones and the burst sets the limit
+func burstArgs(res types.Resources, burst Burst) []string {
+	var args []string
+	if burst.CPU > res.Cpu {
+		args = append(args, "--cpus", fmt.Sprintf("%d", burst.CPU), "--cpu-shares", fmt.Sprintf("%d", res.Cpu*1024))
+	} else {
+		args = append(args, "--cpus", fmt.Sprintf("%d", res.Cpu))
+	}
+	if burst.RAM > res.Ram {
+		args = append(args, "--memory", fmt.Sprintf("%dg", burst.RAM), "--memory-reservation", fmt.Sprintf("%dg", res.Ram))
+	} else {
+		args = append(args, "--memory", fmt.Sprintf("%dg", res.Ram))
+	}
+	return args
+}
+
+// burstResources returns the accounted burst part of the definition
+func (d *Driver) burstResources(res types.Resources, burst Burst) (out types.Resources) {
+	out.Cpu = burstAccount(res.Cpu, burst.CPU, d.cfg.CPUBurstOvercommit)
+	out.Ram = burstAccount(res.Ram, burst.RAM, d.cfg.RAMBurstOvercommit)
+	return out
 }
 
 // In order to recover after restart we need to find the current docker usage
 // There is some evristics to find the modifiers like Multitenancy and the others
-func (d *Driver) getInitialUsage() (types.Resources, error) {
-	var out types.Resources
+// The burst part of the containers is returned too to account it for the local docker
+func (d *Driver) getInitialUsage() (out, burst types.Resources, err error) {
 	// The driver is configured as remote so collecting the current remote docker usage
 	// Listing the existing containers ID's to use in inpect command later
 	stdout, _, err := util.RunAndLog("DOCKER", 5*time.Second, nil, d.cfg.DockerPath, "ps", "--format", "{{ .ID }}")
 	if err != nil {
-		return out, fmt.Errorf("Docker: Unable to list the running containers: %v", err)
+		return out, burst, fmt.Errorf("Docker: Unable to list the running containers: %v", err)
 	}
 
 	idsList := strings.Split(strings.TrimSpace(stdout), "\n")
 	if len(idsList) == 1 && idsList[0] == "" {
 		// It's actually empty so skip it
-		return out, nil
+		return out, burst, nil
 	}
 
-	out, err = d.getContainersResources(idsList)
+	out, burst, err = d.getContainersResources(idsList)
 	if err != nil {
-		return out, err
+		return out, burst, err
 	}
 
 	if out.IsEmpty() || len(idsList) == 1 {
 		// There is no or one container is allocated - so for safety use false for modifiers
-		return out, nil
+		return out, burst, nil
 	}
 
 	// Let's try to find the modificators that is used
@@ -103,7 +148,7 @@ func (d *Driver) getInitialUsage() (types.Resources, error) {
 		out.RamOverbook = true
 	}
 
-	return out, nil
+	return out, burst, nil
 }
 
 // Collects the available resource with alteration
@@ -0,0 +1,50 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package docker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func Test_burst_args(t *testing.T) {
+	res := types.Resources{Cpu: 2, Ram: 4}
+
+	if args := burstArgs(res, Burst{}); !slices.Equal(args, []string{"--cpus", "2", "--memory", "4g"}) {
+		t.Fatalf("Wrong args without burst: %q", args)
+	}
+	args := burstArgs(res, Burst{CPU: 8, RAM: 16})
+	expected := []string{"--cpus", "8", "--cpu-shares", "2048", "--memory", "16g", "--memory-reservation", "4g"}
+	if !slices.Equal(args, expected) {
+		t.Fatalf("Wrong args with burst: %q", args)
+	}
+}
+
+func Test_burst_account(t *testing.T) {
+	d := &Driver{cfg: Config{CPUBurstOvercommit: 4, RAMBurstOvercommit: 1}}
+
+	// CPU burst part 6-2 is overcommitted 4 times, RAM burst part is accounted fully
+	burst := d.burstResources(types.Resources{Cpu: 2, Ram: 4}, Burst{CPU: 6, RAM: 8})
+	if burst.Cpu != 1 || burst.Ram != 4 {
+		t.Fatalf("Wrong accounted burst: %+v", burst)
+	}
+	// The partial CPU is rounded up
+	if got := burstAccount(2, 5, 4); got != 1 {
+		t.Fatalf("Wrong rounding of accounted burst: %d", got)
+	}
+	if got := burstAccount(4, 2, 1); got != 0 {
+		t.Fatalf("Burst below guaranteed should not be accounted: %d", got)
+	}
+}
@@ -14,6 +14,8 @@
 package drivers
 
 import (
+	"time"
+
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 )
 
@@ -23,6 +25,16 @@ const (
 	StatusAllocated = "ALLOCATED"
 )
 
+// MetadataSeed is the Resource metadata key with the Application random seed
+const MetadataSeed = "FISH_SEED"
+
+// MetadataNode and MetadataApplication are the Resource metadata keys with the node and Application
+// UIDs, the drivers able to list the resources tag them to be reconciled after the node crash
+const (
+	MetadataNode        = "FISH_NODE"
+	MetadataApplication = "FISH_APPLICATION"
+)
+
 // FactoryList is a list of available drivers factories
 var FactoryList []ResourceDriverFactory
 
@@ -48,6 +60,10 @@ type ResourceDriver interface {
 	// -> config - driver configuration in json format
 	Prepare(config []byte) error
 
+	// Which Resources the driver could provide, used to validate the definition
+	// <- caps - supported disks, networks and tenancy of the driver
+	Capabilities() types.DriverCapabilities
+
 	// Make sure the allocate definition is appropriate for the driver
 	// -> def - describes the driver options to allocate the required resource
 	ValidateDefinition(def types.LabelDefinition) error
@@ -61,9 +77,13 @@ type ResourceDriver interface {
 	// Allocate the resource by definition and returns hw address
 	// -> def - describes the driver options to allocate the required resource
 	// -> metadata - user metadata to use during resource allocation
-	// <- res - initial resource information to store driver instance state
+	// <- res - initial resource information to store driver instance state and driver info
 	Allocate(def types.LabelDefinition, metadata map[string]any) (res *types.Resource, err error)
 
+	// Schema of the driver-specific info the driver fills in the allocated resource
+	// <- schema - fields of the resource driver info, empty if the driver has no info to provide
+	InfoSchema() types.DriverInfoSchema
+
 	// Get the status of the resource with given hw address
 	// -> res - resource information with stored driver instance state
 	// <- status - current status of the resource
@@ -78,3 +98,54 @@ type ResourceDriver interface {
 	// -> res - resource information with stored driver instance state
 	Deallocate(res *types.Resource) error
 }
+
+// ResourceDriverHealthCheck could be implemented by the driver to run the Label definition health
+// check script inside of the allocated resource
+type ResourceDriverHealthCheck interface {
+	// Execute the script in the resource and wait for it to complete
+	// -> res - resource information with stored driver instance state
+	// -> script - the health check script to run
+	// -> timeout - how long the script could run
+	// <- err - the script failed, returned non-zero exit code or the resource is not reachable
+	HealthCheck(res *types.Resource, script string, timeout time.Duration) error
+}
+
+// ResourceDriverSelfCheck could be implemented by the driver to report if the tools it depends on
+// are still working, it's executed periodically by the node telemetry
+type ResourceDriverSelfCheck interface {
+	// Check the driver is able to manage the resources
+	// <- err - the driver tools are not responding or failing
+	SelfCheck() error
+}
+
+// ResourceDriverList could be implemented by the driver to list the resources it created, it's
+// used on the node startup to reconcile the database with the actually existing resources
+type ResourceDriverList interface {
+	// List the resources created by the driver
+	// <- list - existing resources with Identifier in the same format as Resource.Identifier, the
+	//    NodeUID and ApplicationUID are set from the resource tags or nil if it's not tagged
+	// <- err - the driver was not able to get the list
+	List() (list []types.Resource, err error)
+}
+
+// ResourceDriverForecast could be implemented by the driver which capacity is freed up with delay,
+// so the node could wait for it instead of failing the Application
+type ResourceDriverForecast interface {
+	// Report the state of the driver pools and when their capacity will free up
+	Forecast() types.DriverForecast
+
+	// Estimate when the capacity for the definition will appear
+	// -> def - the label definition to allocate
+	// <- at - the estimated time, zero if there is no capacity expected
+	CapacityForecast(def types.LabelDefinition) (at time.Time)
+}
+
+// ResourceDriverPricing could be implemented by the driver which knows the price of its
+// Resources, so the node could annotate them with the cost and check the budget caps
+type ResourceDriverPricing interface {
+	// Estimate the cost of the Resource per hour
+	// -> def - the label definition to price
+	// <- cost - hourly price in the currency of the driver price source
+	// <- err - the driver is not able to price the definition
+	HourlyCost(def types.LabelDefinition) (cost float64, err error)
+}
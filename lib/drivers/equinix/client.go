@@ -0,0 +1,84 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// errNotFound is returned when the API responded with 404
+var errNotFound = fmt.Errorf("EQUINIX: Not found")
+
+// call sends request to the API and decodes the response into out
+func (d *Driver) call(method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("EQUINIX: Unable to encode %s %s request: %v", method, path, err)
+		}
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(d.cfg.APIURL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("EQUINIX: Unable to create %s %s request: %v", method, path, err)
+	}
+	req.Header.Set("X-Auth-Token", d.cfg.AuthToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: time.Duration(d.cfg.Timeout)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("EQUINIX: %s %s request failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("EQUINIX: Unable to read %s %s response: %v", method, path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// The API returns {"errors": ["..."]} or {"error": "..."}
+		var msg struct {
+			Errors []string `json:"errors"`
+			Error  string   `json:"error"`
+		}
+		json.Unmarshal(data, &msg)
+		if msg.Error != "" {
+			msg.Errors = append(msg.Errors, msg.Error)
+		}
+		if len(msg.Errors) == 0 {
+			msg.Errors = []string{"unknown error"}
+		}
+		return fmt.Errorf("EQUINIX: %s %s responded with status %d: %s", method, path, resp.StatusCode, strings.Join(msg.Errors, ", "))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("EQUINIX: Unable to decode %s %s response: %v", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package equinix implements driver for the Equinix Metal bare-metal cloud
+//
+// The driver provisions the on-demand or reserved servers by plan through the Metal REST API,
+// other bare-metal clouds with the compatible API could be used by setting the api_url.
+package equinix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - node driver configuration
+//
+// Example:
+//
+//	auth_token: <token>
+//	project_id: 9a8b7c6d-...
+//	metro: sv
+//	on_demand_limit: 5
+type Config struct {
+	AuthToken string `json:"auth_token"` // API token of the User or project
+	ProjectID string `json:"project_id"` // UUID of the project to run the servers in
+	Metro     string `json:"metro"`      // Metro to run the servers in when Label does not set it
+
+	// Optional
+	APIURL        string   `json:"api_url"`         // Base URL of the API, "https://api.equinix.com/metal/v1" by default
+	OnDemandLimit int64    `json:"on_demand_limit"` // Maximum number of on-demand servers provisioned by the node, default: 10
+	DeviceTags    []string `json:"device_tags"`     // Tags to set on the servers this node provision
+
+	// Various options to not hardcode the important numbers
+	Timeout          util.Duration `json:"timeout"`            // Timeout of the API request, default: 30s
+	DeviceCreateWait util.Duration `json:"device_create_wait"` // Maximum wait time for server to become active, default: 30m
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("EQUINIX: Unable to apply the driver config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.AuthToken == "" {
+		return fmt.Errorf("EQUINIX: Auth token is required")
+	}
+	if c.ProjectID == "" {
+		return fmt.Errorf("EQUINIX: Project ID is required")
+	}
+
+	if c.APIURL == "" {
+		c.APIURL = "https://api.equinix.com/metal/v1"
+	}
+	if _, err := url.ParseRequestURI(c.APIURL); err != nil {
+		return fmt.Errorf("EQUINIX: Unable to parse API URL: %v", err)
+	}
+	if c.OnDemandLimit == 0 {
+		c.OnDemandLimit = 10
+	}
+	if c.OnDemandLimit < 0 {
+		return fmt.Errorf("EQUINIX: On-demand limit can't be negative")
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = util.Duration(30 * time.Second)
+	}
+	if c.DeviceCreateWait <= 0 {
+		c.DeviceCreateWait = util.Duration(30 * time.Minute)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package equinix
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// How often to check the server status while waiting for it
+var devicePollInterval = 10 * time.Second
+
+// Factory implements drivers.ResourceDriverFactory interface
+type Factory struct{}
+
+// Name shows name of the driver factory
+func (*Factory) Name() string {
+	return "equinix"
+}
+
+// NewResourceDriver creates new resource driver
+func (*Factory) NewResourceDriver() drivers.ResourceDriver {
+	return &Driver{}
+}
+
+func init() {
+	drivers.FactoryList = append(drivers.FactoryList, &Factory{})
+}
+
+// Driver implements drivers.ResourceDriver interface
+type Driver struct {
+	cfg Config
+}
+
+// Name returns name of the driver
+func (*Driver) Name() string {
+	return "equinix"
+}
+
+// IsRemote needed to detect the out-of-node resources managed by this driver
+func (*Driver) IsRemote() bool {
+	return true
+}
+
+// Prepare initializes the driver
+func (d *Driver) Prepare(config []byte) error {
+	if err := d.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := d.cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Checking the token has access to the project
+	if err := d.call("GET", "/projects/"+d.cfg.ProjectID, nil, nil); err != nil {
+		return fmt.Errorf("EQUINIX: Unable to access the project: %v", err)
+	}
+	return nil
+}
+
+// Capabilities of the driver, the plan defines the server disks and network
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		DiskTypes: []string{},
+		Networks:  []string{""},
+	}
+}
+
+// ValidateDefinition checks LabelDefinition is ok
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return err
+	}
+	if opts.Metro == "" && d.cfg.Metro == "" {
+		return fmt.Errorf("EQUINIX: No metro is specified in options or driver config")
+	}
+
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return fmt.Errorf("EQUINIX: Resources validation failed: %s", err)
+	}
+
+	return nil
+}
+
+// AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
+//
+// The capacity is the provisionable reservations of the plan plus the on-demand servers left in
+// the driver limit if the metro has the plan available
+func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.LabelDefinition) int64 {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		log.Error("EQUINIX: Unable to apply options:", err)
+		return -1
+	}
+	metro := d.metro(&opts)
+
+	var capacity int64
+	if opts.Reservation != ReservationNone {
+		reservations, err := d.getReservations(opts.Plan, metro)
+		if err != nil {
+			log.Error("EQUINIX: Unable to get hardware reservations:", err)
+			return -1
+		}
+		for _, r := range reservations {
+			if opts.Reservation == ReservationPrefer || opts.Reservation == ReservationOnly || opts.Reservation == r.ID {
+				capacity++
+			}
+		}
+	}
+
+	if opts.Reservation == ReservationNone || opts.Reservation == ReservationPrefer {
+		available, err := d.metroHasCapacity(opts.Plan, metro)
+		if err != nil {
+			log.Error("EQUINIX: Unable to get metro capacity:", err)
+			return -1
+		}
+		if available {
+			used, err := d.countOnDemand()
+			if err != nil {
+				log.Error("EQUINIX: Unable to count on-demand servers:", err)
+				return -1
+			}
+			capacity += max(d.cfg.OnDemandLimit-used, 0)
+		}
+	}
+
+	log.Debugf("EQUINIX: AvailableCapacity for plan %q in %q: %d", opts.Plan, metro, capacity)
+
+	return capacity
+}
+
+// metro returns the metro to run the server in
+func (d *Driver) metro(opts *Options) string {
+	if opts.Metro != "" {
+		return opts.Metro
+	}
+	return d.cfg.Metro
+}
+
+// Allocate server with provided plan and OS
+//
+// It selects the hardware reservation according to the options and provisions the server. Uses
+// metadata to fill the server userdata
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	// Generate fish name
+	buf := crypt.RandBytes(6)
+	hName := fmt.Sprintf("fish-%02x%02x%02x%02x%02x%02x", buf[0], buf[1], buf[2], buf[3], buf[4], buf[5])
+
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return nil, fmt.Errorf("EQUINIX: %s: Unable to apply options: %v", hName, err)
+	}
+	metro := d.metro(&opts)
+
+	input := map[string]any{
+		"hostname":         hName,
+		"plan":             opts.Plan,
+		"metro":            metro,
+		"operating_system": opts.OperatingSystem,
+		"billing_cycle":    "hourly",
+		"tags":             append(append([]string{deviceTag}, opts.Tags...), d.cfg.DeviceTags...),
+	}
+	if opts.IPXEScriptURL != "" {
+		input["ipxe_script_url"] = opts.IPXEScriptURL
+	}
+
+	switch opts.Reservation {
+	case ReservationNone:
+	case ReservationPrefer, ReservationOnly:
+		reservations, err := d.getReservations(opts.Plan, metro)
+		if err != nil {
+			return nil, fmt.Errorf("EQUINIX: %s: Unable to get hardware reservations: %v", hName, err)
+		}
+		if len(reservations) > 0 {
+			input["hardware_reservation_id"] = reservations[0].ID
+		} else if opts.Reservation == ReservationOnly {
+			return nil, fmt.Errorf("EQUINIX: %s: No provisionable reservation of plan %q in %q", hName, opts.Plan, metro)
+		}
+	default:
+		input["hardware_reservation_id"] = opts.Reservation
+	}
+	if id, ok := input["hardware_reservation_id"]; ok {
+		log.Infof("EQUINIX: %s: Selected hardware reservation: %q", hName, id)
+	}
+
+	if opts.UserDataFormat != "" {
+		// Set UserData field
+		userdata, err := util.SerializeMetadata(opts.UserDataFormat, opts.UserDataPrefix, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("EQUINIX: %s: Unable to serialize metadata to userdata: %v", hName, err)
+		}
+		input["userdata"] = string(userdata)
+	}
+
+	var dev device
+	if err := d.call("POST", "/projects/"+d.cfg.ProjectID+"/devices", input, &dev); err != nil {
+		return nil, log.Errorf("EQUINIX: %s: Unable to create server: %v", hName, err)
+	}
+	log.Infof("EQUINIX: %s: Provisioning server %q", hName, dev.ID)
+
+	// Returning identifier so Fish could deallocate what was created
+	res := &types.Resource{Identifier: dev.ID}
+
+	active, err := d.waitDeviceActive(dev.ID)
+	if err != nil {
+		return res, log.Errorf("EQUINIX: %s: %v", hName, err)
+	}
+	if res.IpAddr = deviceIP(active, opts.PublicIP); res.IpAddr == "" {
+		return res, log.Errorf("EQUINIX: %s: Unable to locate the server IP: %q", hName, dev.ID)
+	}
+	res.DriverInfo = deviceInfo(active)
+	log.Infof("EQUINIX: %s: Allocate of server completed: %q, %q", hName, dev.ID, res.IpAddr)
+
+	return res, nil
+}
+
+// InfoSchema describes the server info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "plan", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Plan of the server"},
+		{Name: "metro", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Metro the server is running in"},
+		{Name: "reservation_id", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Hardware reservation of the server if any"},
+	}
+}
+
+// Status shows status of the resource
+func (d *Driver) Status(res *types.Resource) (string, error) {
+	if res == nil || res.Identifier == "" {
+		return "", fmt.Errorf("EQUINIX: Invalid resource: %v", res)
+	}
+	dev, err := d.getDevice(res.Identifier)
+	if err != nil {
+		return "", fmt.Errorf("EQUINIX: Error during status check for %s: %v", res.Identifier, err)
+	}
+	if dev != nil && dev.State != "deleted" {
+		return drivers.StatusAllocated, nil
+	}
+	return drivers.StatusNone, nil
+}
+
+// GetTask returns task struct by name, the driver has no tasks yet
+func (*Driver) GetTask(_, _ string) drivers.ResourceDriverTask {
+	return nil
+}
+
+// Deallocate the resource, the reserved hardware returns to the reservation pool
+func (d *Driver) Deallocate(res *types.Resource) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("EQUINIX: Invalid resource: %v", res)
+	}
+
+	if err := d.call("DELETE", "/devices/"+res.Identifier, nil, nil); err != nil && err != errNotFound {
+		return fmt.Errorf("EQUINIX: Error during deleting the server %s: %v", res.Identifier, err)
+	}
+
+	log.Infof("EQUINIX: %s: Deallocate of server completed", res.Identifier)
+
+	return nil
+}
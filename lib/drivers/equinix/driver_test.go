@@ -0,0 +1,176 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package equinix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// mockMetal serves the parts of Metal API used by the driver with one provisionable reservation
+// and one on-demand server already running
+type mockMetal struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	create   map[string]any // The last server create request
+	reserved bool
+}
+
+func newMockMetal() *mockMetal {
+	m := &mockMetal{}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockMetal) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if r.Header.Get("X-Auth-Token") != "test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Invalid authentication token"}`))
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /projects/prj-1":
+		w.Write([]byte(`{"id":"prj-1"}`))
+	case "GET /projects/prj-1/hardware-reservations":
+		if m.reserved {
+			w.Write([]byte(`{"hardware_reservations":[]}`))
+			return
+		}
+		w.Write([]byte(`{"hardware_reservations":[
+			{"id":"res-other","provisionable":true,"plan":{"slug":"m3.large.x86"},"facility":{"metro":{"code":"sv"}}},
+			{"id":"res-1","provisionable":true,"plan":{"slug":"c3.small.x86"},"facility":{"metro":{"code":"sv"}}}]}`))
+	case "GET /capacity/metros":
+		w.Write([]byte(`{"capacity":{"sv":{"c3.small.x86":{"level":"limited"}},"da":{"c3.small.x86":{"level":"unavailable"}}}}`))
+	case "GET /projects/prj-1/devices":
+		w.Write([]byte(`{"devices":[
+			{"id":"dev-ondemand","tags":["aquarium-fish"]},
+			{"id":"dev-other","tags":["manual"]},
+			{"id":"dev-reserved","tags":["aquarium-fish"],"hardware_reservation":{"href":"/hardware-reservations/res-2"}}]}`))
+	case "POST /projects/prj-1/devices":
+		json.Unmarshal(body, &m.create)
+		m.reserved = true
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"dev-1","state":"queued"}`))
+	case "GET /devices/dev-1":
+		if !m.reserved {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":["Not found"]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"dev-1","state":"active","plan":{"slug":"c3.small.x86"},"metro":{"code":"sv"},
+			"hardware_reservation":{"href":"/hardware-reservations/res-1"},
+			"ip_addresses":[
+				{"address":"2604:1380::1","public":true,"address_family":6},
+				{"address":"147.75.0.5","public":true,"address_family":4},
+				{"address":"10.0.0.5","public":false,"address_family":4}]}`))
+	case "DELETE /devices/dev-1":
+		m.reserved = false
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":["Unknown path"]}`))
+	}
+}
+
+func Test_equinix_driver_lifecycle(t *testing.T) {
+	metal := newMockMetal()
+	defer metal.Close()
+
+	d := &Driver{}
+	if err := d.Prepare([]byte(`{"api_url":"` + metal.URL + `","auth_token":"test-token","project_id":"prj-1","metro":"sv","on_demand_limit":3}`)); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+
+	def := types.LabelDefinition{
+		Driver:    "equinix",
+		Options:   `{"plan":"c3.small.x86","operating_system":"ubuntu_22_04","reservation":"prefer","userdata_format":"env"}`,
+		Resources: types.Resources{Cpu: 8, Ram: 32},
+	}
+	if err := d.ValidateDefinition(def); err != nil {
+		t.Fatalf("Unable to validate definition: %v", err)
+	}
+
+	// One reservation of the plan and 3-1 on-demand servers left in the limit
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 3 {
+		t.Fatalf("Wrong capacity: %d", capacity)
+	}
+
+	res, err := d.Allocate(def, map[string]any{"key": "value"})
+	if err != nil {
+		t.Fatalf("Unable to allocate: %v", err)
+	}
+	if res.Identifier != "dev-1" || res.IpAddr != "10.0.0.5" {
+		t.Fatalf("Wrong allocated resource: %+v", res)
+	}
+	if err := drivers.ValidateInfo(d.InfoSchema(), res.DriverInfo); err != nil {
+		t.Fatalf("Wrong driver info %s: %v", res.DriverInfo, err)
+	}
+	if metal.create["hardware_reservation_id"] != "res-1" || metal.create["userdata"] == nil {
+		t.Fatalf("Wrong server create request: %v", metal.create)
+	}
+
+	if status, err := d.Status(res); err != nil || status != drivers.StatusAllocated {
+		t.Fatalf("Wrong status: %q, %v", status, err)
+	}
+	if err := d.Deallocate(res); err != nil {
+		t.Fatalf("Unable to deallocate: %v", err)
+	}
+	if status, err := d.Status(res); err != nil || status != drivers.StatusNone {
+		t.Fatalf("Wrong status after deallocate: %q, %v", status, err)
+	}
+}
+
+func Test_equinix_capacity_reservation_only(t *testing.T) {
+	metal := newMockMetal()
+	defer metal.Close()
+
+	d := &Driver{}
+	if err := d.Prepare([]byte(`{"api_url":"` + metal.URL + `","auth_token":"test-token","project_id":"prj-1","metro":"da"}`)); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+
+	// No reservations in da and the plan is unavailable there
+	def := types.LabelDefinition{Options: `{"plan":"c3.small.x86","operating_system":"ubuntu_22_04","reservation":"prefer"}`}
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 0 {
+		t.Fatalf("Wrong capacity in da: %d", capacity)
+	}
+	def.Options = `{"plan":"c3.small.x86","operating_system":"ubuntu_22_04","reservation":"only","metro":"sv"}`
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 1 {
+		t.Fatalf("Wrong reservation only capacity in sv: %d", capacity)
+	}
+}
+
+func Test_equinix_options_validate(t *testing.T) {
+	if err := (&Options{Plan: "c3.small.x86", OperatingSystem: "ubuntu_22_04", Reservation: "sometimes"}).Validate(); err == nil {
+		t.Fatalf("Wrong reservation should not be valid")
+	}
+	if err := (&Options{Plan: "c3.small.x86", OperatingSystem: "custom_ipxe"}).Validate(); err == nil {
+		t.Fatalf("Custom iPXE without script URL should not be valid")
+	}
+	if err := (&Options{Plan: "c3.small.x86", OperatingSystem: "ubuntu_22_04", Reservation: "6f5b3c1a-2c4d-4e5f-8a9b-0c1d2e3f4a5b"}).Validate(); err != nil {
+		t.Fatalf("Reservation UUID should be valid: %v", err)
+	}
+}
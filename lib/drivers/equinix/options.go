@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package equinix
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Placement of the server on the hardware reservations
+const (
+	ReservationNone   = ""       // Only on-demand servers
+	ReservationPrefer = "prefer" // Use the provisionable reservation of the plan if any, on-demand otherwise
+	ReservationOnly   = "only"   // Use only the reservations of the plan
+)
+
+// Options for label definition
+//
+// Example:
+//
+//	plan: c3.small.x86
+//	operating_system: ubuntu_22_04
+//	metro: da                     # Optional, the driver config one by default
+//	reservation: prefer           # Optional, "", "prefer", "only" or UUID of the reservation
+//	tags: [ci]
+type Options struct {
+	Plan            string   `json:"plan"`             // Slug of the server plan
+	OperatingSystem string   `json:"operating_system"` // Slug of the OS to install
+	Metro           string   `json:"metro"`            // Metro to run the server in, the driver config one by default
+	Reservation     string   `json:"reservation"`      // How to use the hardware reservations
+	PublicIP        bool     `json:"public_ip"`        // Use the public IPv4 of the server, private one otherwise
+	IPXEScriptURL   string   `json:"ipxe_script_url"`  // Script URL for the "custom_ipxe" operating system
+	Tags            []string `json:"tags"`             // Tags to add during server creation
+
+	UserDataFormat string `json:"userdata_format"` // If not empty - will store the resource metadata to userdata in defined format
+	UserDataPrefix string `json:"userdata_prefix"` // Optional if need to add custom prefix to the metadata key during formatting
+}
+
+// Apply takes json and applies it to the options structure
+func (o *Options) Apply(options util.UnparsedJSON) error {
+	if err := json.Unmarshal([]byte(options), o); err != nil {
+		return log.Error("EQUINIX: Unable to apply the driver options", err)
+	}
+
+	return o.Validate()
+}
+
+// Validate makes sure the options have the required defaults & that the required fields are set
+func (o *Options) Validate() error {
+	if o.Plan == "" {
+		return fmt.Errorf("EQUINIX: No plan is specified")
+	}
+	if o.OperatingSystem == "" {
+		return fmt.Errorf("EQUINIX: No operating system is specified")
+	}
+	if o.OperatingSystem == "custom_ipxe" && o.IPXEScriptURL == "" {
+		return fmt.Errorf("EQUINIX: iPXE script URL is required for custom_ipxe operating system")
+	}
+
+	if !util.Contains([]string{ReservationNone, ReservationPrefer, ReservationOnly}, o.Reservation) {
+		if _, err := uuid.Parse(o.Reservation); err != nil {
+			return fmt.Errorf("EQUINIX: Reservation should be one of %q or reservation UUID", []string{ReservationNone, ReservationPrefer, ReservationOnly})
+		}
+	}
+
+	if !util.Contains([]string{"", "json", "env", "ps1"}, o.UserDataFormat) {
+		return fmt.Errorf("EQUINIX: Unsupported userdata format: %s", o.UserDataFormat)
+	}
+
+	return nil
+}
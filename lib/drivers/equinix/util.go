@@ -0,0 +1,167 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package equinix
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Tag to mark the servers provisioned by the driver, used to count the on-demand servers
+const deviceTag = "aquarium-fish"
+
+// href is the reference to the other API object
+type href struct {
+	Href string `json:"href"`
+}
+
+// device is the part of Metal device we need
+type device struct {
+	ID          string   `json:"id"`
+	State       string   `json:"state"`
+	Tags        []string `json:"tags"`
+	IPAddresses []struct {
+		Address       string `json:"address"`
+		Public        bool   `json:"public"`
+		AddressFamily int    `json:"address_family"`
+	} `json:"ip_addresses"`
+	Plan struct {
+		Slug string `json:"slug"`
+	} `json:"plan"`
+	Metro struct {
+		Code string `json:"code"`
+	} `json:"metro"`
+	HardwareReservation *href `json:"hardware_reservation"`
+}
+
+// reservation is the part of Metal hardware reservation we need
+type reservation struct {
+	ID            string `json:"id"`
+	Provisionable bool   `json:"provisionable"`
+	Plan          struct {
+		Slug string `json:"slug"`
+	} `json:"plan"`
+	Facility struct {
+		Metro struct {
+			Code string `json:"code"`
+		} `json:"metro"`
+	} `json:"facility"`
+}
+
+// getReservations returns the provisionable hardware reservations of the plan in metro
+func (d *Driver) getReservations(plan, metro string) ([]reservation, error) {
+	var resp struct {
+		Reservations []reservation `json:"hardware_reservations"`
+	}
+	if err := d.call("GET", "/projects/"+d.cfg.ProjectID+"/hardware-reservations?provisionable=only&per_page=1000", nil, &resp); err != nil {
+		return nil, err
+	}
+	var out []reservation
+	for _, r := range resp.Reservations {
+		if r.Provisionable && r.Plan.Slug == plan && r.Facility.Metro.Code == metro {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// countOnDemand returns the number of on-demand servers provisioned by the driver in the project
+func (d *Driver) countOnDemand() (int64, error) {
+	var resp struct {
+		Devices []device `json:"devices"`
+	}
+	if err := d.call("GET", "/projects/"+d.cfg.ProjectID+"/devices?per_page=1000", nil, &resp); err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, dev := range resp.Devices {
+		if dev.HardwareReservation == nil && util.Contains(dev.Tags, deviceTag) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// metroHasCapacity checks the metro capacity level of the plan to have servers available
+func (d *Driver) metroHasCapacity(plan, metro string) (bool, error) {
+	var resp struct {
+		Capacity map[string]map[string]struct {
+			Level string `json:"level"`
+		} `json:"capacity"`
+	}
+	if err := d.call("GET", "/capacity/metros", nil, &resp); err != nil {
+		return false, err
+	}
+	level := resp.Capacity[metro][plan].Level
+	return level != "" && level != "unavailable", nil
+}
+
+// getDevice returns the server by ID, nil if it's not exists
+func (d *Driver) getDevice(id string) (*device, error) {
+	var dev device
+	if err := d.call("GET", "/devices/"+id, nil, &dev); err == errNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &dev, nil
+}
+
+// waitDeviceActive waits for the server to be provisioned
+func (d *Driver) waitDeviceActive(id string) (*device, error) {
+	deadline := time.Now().Add(time.Duration(d.cfg.DeviceCreateWait))
+	for {
+		dev, err := d.getDevice(id)
+		if err != nil {
+			log.Warnf("EQUINIX: Error during getting server %s while waiting for it to become active: %v", id, err)
+		} else if dev == nil {
+			return nil, fmt.Errorf("EQUINIX: Server %s disappeared", id)
+		} else if dev.State == "active" {
+			return dev, nil
+		} else if dev.State == "failed" {
+			return nil, fmt.Errorf("EQUINIX: Server %s failed to provision", id)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("EQUINIX: Server %s is not active after %s", id, time.Duration(d.cfg.DeviceCreateWait))
+		}
+		time.Sleep(devicePollInterval)
+	}
+}
+
+// deviceIP returns the public or private IPv4 address of the server
+func deviceIP(dev *device, public bool) string {
+	for _, ip := range dev.IPAddresses {
+		if ip.AddressFamily == 4 && ip.Public == public {
+			return ip.Address
+		}
+	}
+	return ""
+}
+
+// deviceInfo collects the driver info of the Resource described by InfoSchema
+func deviceInfo(dev *device) util.UnparsedJSON {
+	info := map[string]any{
+		"plan":  dev.Plan.Slug,
+		"metro": dev.Metro.Code,
+	}
+	if dev.HardwareReservation != nil {
+		info["reservation_id"] = path.Base(dev.HardwareReservation.Href)
+	}
+	return drivers.InfoJSON(info)
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package hyperv implements driver
+package hyperv
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/adobe/aquarium-fish/lib/inventory"
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// Config - node driver configuration
+type Config struct {
+	PowerShellPath string `json:"powershell_path"` // Path to powershell.exe or pwsh.exe with Hyper-V module available
+
+	ImagesPath    string `json:"images_path"`    // Where to look/store VM images
+	WorkspacePath string `json:"workspace_path"` // Where to place the VMs and their disks
+
+	SwitchName string `json:"switch_name"` // Virtual switch to connect the VMs, "Default Switch" by default
+	Generation uint   `json:"generation"`  // Generation of the VMs, 2 by default (UEFI)
+
+	// Alter allows you to control how much resources will be used:
+	// * Negative (<0) value will alter the total resource count before provisioning so you will be
+	//   able to save some resources for the host system (recommended -2 for CPU and -10 for RAM)
+	// * Positive (>0) value could also be available, but be careful here - Hyper-V will fail to
+	//   start the VM if there is not enough memory
+	CPUAlter int `json:"cpu_alter"` // 0 do nothing, <0 reduces number available CPUs, >0 increases it (dangerous)
+	RAMAlter int `json:"ram_alter"` // 0 do nothing, <0 reduces amount of available RAM (GB), >0 increases it (dangerous)
+
+	// Overbook options allows tenants to reuse the resources, the same way as in vmx driver
+	CPUOverbook uint `json:"cpu_overbook"` // How much CPUs could be reused by multiple tenants
+	RAMOverbook uint `json:"ram_overbook"` // How much RAM (GB) could be reused by multiple tenants
+
+	DownloadUser     string `json:"download_user"`     // The user will be used in download operations
+	DownloadPassword string `json:"download_password"` // The password will be used in download operations
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	// Parse json
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("HyperV: Unable to apply the driver config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() (err error) {
+	// Check that values of the config is filled at least with defaults
+	if c.PowerShellPath == "" {
+		// Look in the PATH, the Windows PowerShell has Hyper-V module out of the box
+		if c.PowerShellPath, err = exec.LookPath("powershell.exe"); err != nil {
+			if c.PowerShellPath, err = exec.LookPath("pwsh"); err != nil {
+				return log.Error("HyperV: Unable to locate `powershell.exe` or `pwsh` path:", err)
+			}
+		}
+	}
+	if c.ImagesPath == "" {
+		c.ImagesPath = "fish_hyperv_images"
+	}
+	if c.WorkspacePath == "" {
+		c.WorkspacePath = "fish_hyperv_workspace"
+	}
+	if c.SwitchName == "" {
+		c.SwitchName = "Default Switch"
+	}
+	if c.Generation == 0 {
+		c.Generation = 2
+	}
+	if c.Generation > 2 {
+		return log.Errorf("HyperV: Generation could be only 1 or 2: %d", c.Generation)
+	}
+
+	// Making paths absolute
+	if c.ImagesPath, err = filepath.Abs(c.ImagesPath); err != nil {
+		return err
+	}
+	if c.WorkspacePath, err = filepath.Abs(c.WorkspacePath); err != nil {
+		return err
+	}
+
+	log.Debug("HyperV: Creating working directories:", c.ImagesPath, c.WorkspacePath)
+	if err := os.MkdirAll(c.ImagesPath, 0o750); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.WorkspacePath, 0o750); err != nil {
+		return err
+	}
+
+	// Validating CpuAlter & RamAlter to not be less then the current cpu/ram count
+	inv := inventory.Get()
+	cpuStat := int(inv.CpuThreads)
+
+	if c.CPUAlter < 0 && cpuStat <= -c.CPUAlter {
+		return log.Errorf("HyperV: |CpuAlter| can't be more or equal the available Host CPUs: |%d| > %d", c.CPUAlter, cpuStat)
+	}
+
+	ramStat := inv.RamTotal
+
+	if c.RAMAlter < 0 && int(ramStat) <= -c.RAMAlter {
+		return log.Errorf("HyperV: |RamAlter| can't be more or equal the available Host RAM: |%d| > %d", c.RAMAlter, ramStat)
+	}
+
+	return nil
+}
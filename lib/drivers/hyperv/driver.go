@@ -0,0 +1,295 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package hyperv
+
+// Microsoft Hyper-V driver to manage the Windows host VMs & images
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/inventory"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Prefix of the VM names created by the driver to list them
+const vmPrefix = "fish-"
+
+// Factory implements drivers.ResourceDriverFactory interface
+type Factory struct{}
+
+// Name shows name of the driver factory
+func (*Factory) Name() string {
+	return "hyperv"
+}
+
+// NewResourceDriver creates new resource driver
+func (*Factory) NewResourceDriver() drivers.ResourceDriver {
+	return &Driver{}
+}
+
+func init() {
+	drivers.FactoryList = append(drivers.FactoryList, &Factory{})
+}
+
+// Driver implements drivers.ResourceDriver interface
+type Driver struct {
+	cfg Config
+
+	totalCPU uint // In logical threads
+	totalRAM uint // In RAM GB
+}
+
+// Name returns name of the driver
+func (*Driver) Name() string {
+	return "hyperv"
+}
+
+// IsRemote needed to detect the out-of-node resources managed by this driver
+func (*Driver) IsRemote() bool {
+	return false
+}
+
+// Prepare initializes the driver
+func (d *Driver) Prepare(config []byte) error {
+	if err := d.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := d.cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Make sure the Hyper-V module is here and the user is able to use it
+	if _, err := d.ps(30*time.Second, "Get-VMHost | Out-Null"); err != nil {
+		return log.Error("HyperV: Unable to access Hyper-V, check the feature is enabled and the user is in Hyper-V Administrators:", err)
+	}
+
+	// Collect node resources status
+	inv := inventory.Get()
+	if inv.CpuThreads == 0 || inv.RamTotal == 0 {
+		return fmt.Errorf("HyperV: Unable to detect the node CPU & RAM: %d, %d", inv.CpuThreads, inv.RamTotal)
+	}
+	d.totalCPU = inv.CpuThreads
+	d.totalRAM = inv.RamTotal
+
+	return nil
+}
+
+// Capabilities of the driver, the disks are the VHDX files formatted to the filesystem
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"ntfs", "refs", "exfat", "fat32"},
+		DiskReuse: true,
+		Networks:  []string{""},
+		Tenancy:   true,
+	}
+}
+
+// ValidateDefinition checks LabelDefinition is ok
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return log.Error("HyperV: Resources validation failed:", err)
+	}
+
+	// Check options
+	var opts Options
+	return opts.Apply(def.Options)
+}
+
+// AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
+func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDefinition) int64 {
+	var outCount int64
+
+	availCPU, availRAM := d.getAvailResources()
+
+	// Check if the node has the required resources - otherwise we can't run it anyhow
+	if req.Resources.Cpu > availCPU {
+		return 0
+	}
+	if req.Resources.Ram > availRAM {
+		return 0
+	}
+
+	// Since we have the required resources - let's check if tenancy allows us to expand them to
+	// run more tenants here
+	if nodeUsage.IsEmpty() {
+		// In case we dealing with the first one - we need to set usage modificators, otherwise
+		// those values will mess up the next calculations
+		nodeUsage.Multitenancy = req.Resources.Multitenancy
+		nodeUsage.CpuOverbook = req.Resources.CpuOverbook
+		nodeUsage.RamOverbook = req.Resources.RamOverbook
+	}
+	if nodeUsage.Multitenancy && req.Resources.Multitenancy {
+		// Ok we can run more tenants, let's calculate how much
+		if nodeUsage.CpuOverbook && req.Resources.CpuOverbook {
+			availCPU += d.cfg.CPUOverbook
+		}
+		if nodeUsage.RamOverbook && req.Resources.RamOverbook {
+			availRAM += d.cfg.RAMOverbook
+		}
+	}
+
+	// Calculate how much of those definitions we could run
+	outCount = int64((availCPU - nodeUsage.Cpu) / req.Resources.Cpu)
+	ramCount := int64((availRAM - nodeUsage.Ram) / req.Resources.Ram)
+	if outCount > ramCount {
+		outCount = ramCount
+	}
+
+	return outCount
+}
+
+// Allocate VM with provided images
+//
+// It downloads the required images, creates the differencing disk from the last one and runs
+// the VM. Not using metadata because there is no good interfaces to pass it to VM.
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return nil, log.Error("HyperV: Unable to apply options:", err)
+	}
+
+	// Generate unique id from the hw address
+	buf := crypt.RandBytes(6)
+	buf[0] = (buf[0] | 2) & 0xfe // Set local bit, ensure unicast address
+	vmHwaddr := fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", buf[0], buf[1], buf[2], buf[3], buf[4], buf[5])
+	vmName := vmPrefix + strings.ReplaceAll(vmHwaddr, ":", "")
+	vmDir := filepath.Join(d.cfg.WorkspacePath, vmName)
+
+	// Load the required images
+	imgPath, err := d.loadImages(&opts)
+	if err != nil {
+		return nil, log.Error("HyperV: Unable to load the required images:", err)
+	}
+
+	// Create the VM on top of the image, so the image is not modified
+	secureBoot := "On"
+	if opts.SecureBoot != nil && !*opts.SecureBoot {
+		secureBoot = "Off"
+	}
+	diskPath := filepath.Join(vmDir, vmName+".vhdx")
+	script := fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+New-Item -ItemType Directory -Force -Path %[1]s | Out-Null
+New-VHD -Path %[2]s -ParentPath %[3]s -Differencing | Out-Null
+New-VM -Name %[4]s -Path %[1]s -Generation %[5]d -MemoryStartupBytes %[6]dGB -VHDPath %[2]s -SwitchName %[7]s | Out-Null
+Set-VMMemory -VMName %[4]s -DynamicMemoryEnabled $false
+Set-VMProcessor -VMName %[4]s -Count %[8]d
+Set-VMNetworkAdapter -VMName %[4]s -StaticMacAddress %[9]s
+Set-VM -Name %[4]s -Notes %[10]s`,
+		psQuote(vmDir), psQuote(diskPath), psQuote(imgPath), psQuote(vmName), d.cfg.Generation,
+		def.Resources.Ram, psQuote(d.cfg.SwitchName), def.Resources.Cpu, psQuote(psMac(vmHwaddr)),
+		psQuote(vmNotes(drivers.ListTags(metadata))),
+	)
+	if d.cfg.Generation == 2 {
+		script += fmt.Sprintf("\nSet-VMFirmware -VMName %s -EnableSecureBoot %s", psQuote(vmName), secureBoot)
+	}
+	if _, err := d.ps(5*time.Minute, script); err != nil {
+		d.cleanupVM(vmName)
+		return nil, log.Error("HyperV: Unable to create VM:", vmName, err)
+	}
+
+	// Create and connect disks to VM
+	if err := d.disksCreate(vmName, vmDir, def.Resources.Disks); err != nil {
+		d.cleanupVM(vmName)
+		return nil, log.Error("HyperV: Unable create disks for VM:", vmName, err)
+	}
+
+	// Run the VM
+	if _, err := d.ps(5*time.Minute, "Start-VM -Name "+psQuote(vmName)); err != nil {
+		d.cleanupVM(vmName)
+		return nil, log.Error("HyperV: Unable to run VM:", vmName, err)
+	}
+
+	log.Info("HyperV: Allocate of VM completed:", vmName)
+	return &types.Resource{
+		Identifier:     vmName,
+		HwAddr:         vmHwaddr,
+		Authentication: def.Authentication,
+		DriverInfo:     drivers.InfoJSON(map[string]any{"image": imgPath, "switch": d.cfg.SwitchName}),
+	}, nil
+}
+
+// InfoSchema describes the VM info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "image", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Path to the image disk the VM disk differs from"},
+		{Name: "switch", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Virtual switch the VM is connected to"},
+	}
+}
+
+// Status shows status of the resource
+func (d *Driver) Status(res *types.Resource) (string, error) {
+	if res == nil || res.Identifier == "" {
+		return "", fmt.Errorf("HyperV: Invalid resource: %v", res)
+	}
+	vms, err := d.List()
+	if err != nil {
+		return "", err
+	}
+	for _, vm := range vms {
+		if vm.Identifier == res.Identifier {
+			return drivers.StatusAllocated, nil
+		}
+	}
+	return drivers.StatusNone, nil
+}
+
+// List returns the VMs created by the driver, the owner is stored in the VM notes
+func (d *Driver) List() ([]types.Resource, error) {
+	stdout, err := d.ps(30*time.Second, fmt.Sprintf("Get-VM -Name %s | ForEach-Object { $_.Name + \"`t\" + $_.Notes }", psQuote(vmPrefix+"*")))
+	if err != nil {
+		return nil, fmt.Errorf("HyperV: Unable to list VMs: %v", err)
+	}
+	out := []types.Resource{}
+	for _, line := range strings.Split(stdout, "\n") {
+		name, notes, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		if name != "" {
+			node, app := parseVMNotes(notes)
+			out = append(out, drivers.ListResource(name, node, app))
+		}
+	}
+	return out, nil
+}
+
+// GetTask returns task struct by name
+func (*Driver) GetTask(_, _ string) drivers.ResourceDriverTask {
+	return nil
+}
+
+// Deallocate the resource
+func (d *Driver) Deallocate(res *types.Resource) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("HyperV: Invalid resource: %v", res)
+	}
+	vmName := res.Identifier
+
+	// Turning off is the same as pulling the power plug, the VM will be removed anyway
+	script := fmt.Sprintf("$ErrorActionPreference = 'Stop'\nStop-VM -Name %[1]s -TurnOff -Force\nRemove-VM -Name %[1]s -Force", psQuote(vmName))
+	if _, err := d.psRetry(3, 2*time.Minute, script); err != nil {
+		return log.Error("HyperV: Unable to deallocate VM:", vmName, err)
+	}
+
+	// Cleaning the VM disks too
+	d.cleanupVM(vmName)
+
+	log.Info("HyperV: Deallocate of VM completed:", vmName)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package hyperv
+
+import (
+	"encoding/json"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Options for label definition
+//
+// Example:
+//
+//	images:
+//	  - url: https://artifact-storage/aquarium/image/hyperv/win2022-VERSION/win2022-VERSION.tar.xz
+//	    sum: sha256:1234567890abcdef1234567890abcdef1
+//	  - url: https://artifact-storage/aquarium/image/hyperv/win2022-vs2022-VERSION/win2022-vs2022-VERSION.tar.xz
+//	    sum: sha256:1234567890abcdef1234567890abcdef2
+type Options struct {
+	Images []drivers.Image `json:"images"` // List of image dependencies, last one is running one
+
+	SecureBoot *bool `json:"secure_boot"` // Enable UEFI secure boot of generation 2 VM, enabled by default
+}
+
+// Apply takes json and applies it to the options structure
+func (o *Options) Apply(options util.UnparsedJSON) error {
+	if err := json.Unmarshal([]byte(options), o); err != nil {
+		return log.Error("HyperV: Unable to apply the driver options", err)
+	}
+
+	return o.Validate()
+}
+
+// Validate makes sure the options have the required defaults & that the required fields are set
+func (o *Options) Validate() error {
+	if len(o.Images) == 0 {
+		return log.Error("HyperV: No images are specified")
+	}
+
+	// Check images
+	var imgErr error
+	for index := range o.Images {
+		if err := o.Images[index].Validate(); err != nil {
+			imgErr = log.Error("HyperV: Error during image validation:", err)
+		}
+	}
+	if imgErr != nil {
+		return imgErr
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package hyperv
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Returns the total resources available for the node after alteration
+func (d *Driver) getAvailResources() (availCPU, availRAM uint) {
+	if d.cfg.CPUAlter < 0 {
+		availCPU = d.totalCPU - uint(-d.cfg.CPUAlter)
+	} else {
+		availCPU = d.totalCPU + uint(d.cfg.CPUAlter)
+	}
+
+	if d.cfg.RAMAlter < 0 {
+		availRAM = d.totalRAM - uint(-d.cfg.RAMAlter)
+	} else {
+		availRAM = d.totalRAM + uint(d.cfg.RAMAlter)
+	}
+
+	return
+}
+
+// Load images and returns the last image disk path to use as parent of the VM disk, the image
+// disks could be differencing too, so all of them are unpacked to the images directory
+func (d *Driver) loadImages(opts *Options) (string, error) {
+	var wg sync.WaitGroup
+	errs := make([]error, len(opts.Images))
+	for imageIndex, image := range opts.Images {
+		log.Info("HyperV: Loading the required image:", image.Name, image.Version, image.URL)
+
+		wg.Add(1)
+		go func(image drivers.Image, index int) {
+			defer wg.Done()
+			if err := image.DownloadUnpack(d.cfg.ImagesPath, d.cfg.DownloadUser, d.cfg.DownloadPassword); err != nil {
+				errs[index] = fmt.Errorf("HyperV: Unable to download and unpack the image %s %s: %v", image.Name, image.URL, err)
+			}
+		}(image, imageIndex)
+	}
+
+	log.Debug("HyperV: Wait for all the background image processes to be done...")
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return "", err
+		}
+	}
+	log.Info("HyperV: The images are processed.")
+
+	// Looking for the disk of the last image, preferring the one named as image
+	image := opts.Images[len(opts.Images)-1]
+	imageUnpacked := filepath.Join(d.cfg.ImagesPath, image.Name+"-"+image.Version)
+	var found []string
+	err := filepath.WalkDir(imageUnpacked, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(path), ".vhdx") {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("HyperV: Unable to read the unpacked directory %s: %v", imageUnpacked, err)
+	}
+	for _, path := range found {
+		if strings.HasPrefix(filepath.Base(path), image.Name) {
+			return path, nil
+		}
+	}
+	if len(found) == 0 {
+		return "", fmt.Errorf("HyperV: Unpacked image %s has no vhdx disk", imageUnpacked)
+	}
+
+	return found[0], nil
+}
+
+// Creates VHDX disks described by the disks map and attaches them to the VM
+func (d *Driver) disksCreate(vmName, vmDir string, disks map[string]types.ResourcesDisk) error {
+	for dName, disk := range disks {
+		diskPath := filepath.Join(vmDir, dName+".vhdx")
+		if disk.Reuse {
+			diskPath = filepath.Join(d.cfg.WorkspacePath, "disk-"+dName, dName+".vhdx")
+			if err := os.MkdirAll(filepath.Dir(diskPath), 0o750); err != nil {
+				return err
+			}
+		}
+
+		if _, err := os.Stat(diskPath); os.IsNotExist(err) {
+			var fsType string
+			switch disk.Type {
+			case "refs":
+				fsType = "ReFS"
+			case "exfat":
+				fsType = "exFAT"
+			case "fat32":
+				fsType = "FAT32"
+			default:
+				fsType = "NTFS"
+			}
+			label := dName
+			if disk.Label != "" {
+				label = disk.Label
+			}
+
+			// Mounting the disk on the host to format it and dismount even if format failed
+			script := fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+New-VHD -Path %[1]s -SizeBytes %[2]dGB -Dynamic | Out-Null
+try {
+  $disk = Mount-VHD -Path %[1]s -Passthru | Initialize-Disk -PartitionStyle GPT -Passthru
+  $disk | New-Partition -UseMaximumSize | Format-Volume -FileSystem %[3]s -NewFileSystemLabel %[4]s -Confirm:$false | Out-Null
+} finally {
+  Dismount-VHD -Path %[1]s
+}`, psQuote(diskPath), disk.Size, fsType, psQuote(label))
+			if _, err := d.ps(10*time.Minute, script); err != nil {
+				os.Remove(diskPath)
+				return fmt.Errorf("HyperV: Unable to create disk %s: %v", diskPath, err)
+			}
+		}
+
+		if _, err := d.ps(time.Minute, fmt.Sprintf("Add-VMHardDiskDrive -VMName %s -Path %s", psQuote(vmName), psQuote(diskPath))); err != nil {
+			return fmt.Errorf("HyperV: Unable to attach disk %s: %v", diskPath, err)
+		}
+	}
+
+	return nil
+}
+
+// ps runs the PowerShell script and returns its output
+func (d *Driver) ps(timeout time.Duration, script string) (string, error) {
+	stdout, _, err := util.RunAndLog("HYPERV", timeout, nil, d.cfg.PowerShellPath, "-NoProfile", "-NonInteractive", "-Command", script)
+	return stdout, err
+}
+
+// psRetry runs the PowerShell script and retries if it fails
+func (d *Driver) psRetry(retry int, timeout time.Duration, script string) (string, error) {
+	stdout, _, err := util.RunAndLogRetry("HYPERV", retry, timeout, nil, d.cfg.PowerShellPath, "-NoProfile", "-NonInteractive", "-Command", script)
+	return stdout, err
+}
+
+// psQuote makes PowerShell single-quoted string literal, the only special char there is the quote
+func psQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// vmNotes makes the VM notes to find the owner of the VM, they are not used by Hyper-V itself
+func vmNotes(node, app string) string {
+	return "FishNode=" + node + ";FishApplication=" + app
+}
+
+// parseVMNotes returns the owner of the VM from the notes, empty if the notes are not set by Fish
+func parseVMNotes(notes string) (node, app string) {
+	for _, field := range strings.Split(notes, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "FishNode":
+			node = value
+		case "FishApplication":
+			app = value
+		}
+	}
+	return node, app
+}
+
+// psMac converts the hw address to the format Hyper-V uses for the network adapter
+func psMac(hwaddr string) string {
+	return strings.ToUpper(strings.ReplaceAll(hwaddr, ":", ""))
+}
+
+// Removes the VM if it's left and the VM directory for clean up purposes
+func (d *Driver) cleanupVM(vmName string) error {
+	if _, err := d.ps(2*time.Minute, fmt.Sprintf("Remove-VM -Name %s -Force -ErrorAction SilentlyContinue", psQuote(vmName))); err != nil {
+		log.Warn("HyperV: Unable to remove the VM:", vmName, err)
+	}
+	vmDir := filepath.Join(d.cfg.WorkspacePath, vmName)
+	if err := os.RemoveAll(vmDir); err != nil {
+		log.Warn("HyperV: Unable to clean up the vm directory:", vmDir, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package hyperv
+
+import (
+	"testing"
+)
+
+func Test_ps_quote(t *testing.T) {
+	for in, out := range map[string]string{
+		`fish-0123`:             `'fish-0123'`,
+		`C:\fish\images\a.vhdx`: `'C:\fish\images\a.vhdx'`,
+		`it's $(evil)`:          `'it''s $(evil)'`,
+	} {
+		if got := psQuote(in); got != out {
+			t.Fatalf("psQuote(%q) = %q, expected %q", in, got, out)
+		}
+	}
+}
+
+func Test_ps_mac(t *testing.T) {
+	if got := psMac("02:15:5d:0a:bc:01"); got != "02155D0ABC01" {
+		t.Fatalf("Wrong Hyper-V MAC address: %q", got)
+	}
+}
+
+func Test_vm_notes(t *testing.T) {
+	node, app := parseVMNotes(vmNotes("node-uid", "app-uid"))
+	if node != "node-uid" || app != "app-uid" {
+		t.Fatalf("Wrong VM owner from notes: %q, %q", node, app)
+	}
+	if node, app = parseVMNotes("Created manually"); node != "" || app != "" {
+		t.Fatalf("Manual VM notes should not have owner: %q, %q", node, app)
+	}
+}
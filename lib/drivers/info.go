@@ -0,0 +1,88 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// InfoJSON makes the driver info of the Resource, nil values are skipped to not fill the
+// optional fields of schema with nulls
+func InfoJSON(info map[string]any) util.UnparsedJSON {
+	out := make(map[string]any, len(info))
+	for k, v := range info {
+		if v != nil {
+			out[k] = v
+		}
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return util.UnparsedJSON("{}")
+	}
+	return util.UnparsedJSON(data)
+}
+
+// ValidateInfo checks the driver info of the Resource against the schema published by the
+// driver, so the tooling could rely on the types and presence of the fields
+func ValidateInfo(schema types.DriverInfoSchema, info util.UnparsedJSON) error {
+	data := map[string]any{}
+	if info != "" {
+		if err := json.Unmarshal([]byte(info), &data); err != nil {
+			return fmt.Errorf("Unable to parse driver info: %v", err)
+		}
+	}
+
+	fields := make(map[string]types.DriverInfoField, len(schema))
+	for _, field := range schema {
+		fields[field.Name] = field
+		if _, ok := data[field.Name]; !ok && field.Required {
+			return fmt.Errorf("Driver info required field %q is not set", field.Name)
+		}
+	}
+
+	for key, value := range data {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("Driver info field %q is not described by schema", key)
+		}
+		if !infoTypeCheck(field.Type, value) {
+			return fmt.Errorf("Driver info field %q has wrong type, expected %s: %v", key, field.Type, value)
+		}
+	}
+
+	return nil
+}
+
+// infoTypeCheck makes sure the parsed json value has the described type
+func infoTypeCheck(typ types.DriverInfoFieldType, value any) bool {
+	switch typ {
+	case types.DriverInfoFieldTypeString:
+		_, ok := value.(string)
+		return ok
+	case types.DriverInfoFieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case types.DriverInfoFieldTypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case types.DriverInfoFieldTypeInteger:
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v)
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+func Test_validate_info(t *testing.T) {
+	schema := types.DriverInfoSchema{
+		{Name: "type", Type: types.DriverInfoFieldTypeString, Required: true},
+		{Name: "cpu", Type: types.DriverInfoFieldTypeInteger, Required: true},
+		{Name: "spot", Type: types.DriverInfoFieldTypeBoolean},
+		{Name: "price", Type: types.DriverInfoFieldTypeNumber},
+	}
+
+	tests := []struct {
+		name  string
+		info  util.UnparsedJSON
+		valid bool
+	}{
+		{"full", `{"type":"c6a.4xlarge","cpu":16,"spot":false,"price":0.5}`, true},
+		{"only_required", `{"type":"c6a.4xlarge","cpu":16}`, true},
+		{"missing_required", `{"type":"c6a.4xlarge"}`, false},
+		{"unknown_field", `{"type":"c6a.4xlarge","cpu":16,"host":"h-1"}`, false},
+		{"wrong_type", `{"type":16,"cpu":16}`, false},
+		{"float_integer", `{"type":"c6a.4xlarge","cpu":16.5}`, false},
+		{"not_json", `type`, false},
+		{"empty", ``, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInfo(schema, tt.info)
+			if tt.valid && err != nil {
+				t.Fatalf("ValidateInfo(%s) unexpected error: %v", tt.info, err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatalf("ValidateInfo(%s) expected error", tt.info)
+			}
+		})
+	}
+
+	if err := ValidateInfo(nil, InfoJSON(map[string]any{"host": nil})); err != nil {
+		t.Fatalf("Nil values should be skipped by InfoJSON: %v", err)
+	}
+}
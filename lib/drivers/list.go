@@ -0,0 +1,46 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ListTags returns the node and Application UIDs from the Resource metadata to tag the resource
+// with, empty if the metadata doesn't contain them
+func ListTags(metadata map[string]any) (node, app string) {
+	if v, ok := metadata[MetadataNode]; ok {
+		node = fmt.Sprint(v)
+	}
+	if v, ok := metadata[MetadataApplication]; ok {
+		app = fmt.Sprint(v)
+	}
+	return node, app
+}
+
+// ListResource makes the listed resource out of the identifier and tags, the invalid or empty tags
+// are left nil so the resource is treated as not owned by any node
+func ListResource(id, node, app string) types.Resource {
+	res := types.Resource{Identifier: id}
+	if uid, err := uuid.Parse(node); err == nil {
+		res.NodeUID = uid
+	}
+	if uid, err := uuid.Parse(app); err == nil {
+		res.ApplicationUID = uid
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_list_resource(t *testing.T) {
+	nodeUID, appUID := uuid.New(), uuid.New()
+	node, app := ListTags(map[string]any{MetadataNode: nodeUID.String(), MetadataApplication: appUID.String()})
+
+	res := ListResource("fish-1", node, app)
+	if res.Identifier != "fish-1" || res.NodeUID != nodeUID || res.ApplicationUID != appUID {
+		t.Fatalf("Wrong listed resource: %+v", res)
+	}
+
+	// Not tagged resource should not be owned by any node
+	node, app = ListTags(map[string]any{})
+	if res = ListResource("fish-2", node, app); res.NodeUID != uuid.Nil || res.ApplicationUID != uuid.Nil {
+		t.Fatalf("Not tagged resource should not have owner: %+v", res)
+	}
+	if res = ListResource("fish-3", "manual", ""); res.NodeUID != uuid.Nil {
+		t.Fatalf("Invalid tag should be skipped: %+v", res)
+	}
+}
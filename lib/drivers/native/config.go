@@ -20,9 +20,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-
+	"github.com/adobe/aquarium-fish/lib/inventory"
 	"github.com/adobe/aquarium-fish/lib/log"
 )
 
@@ -313,20 +311,14 @@ func (c *Config) Validate() (err error) {
 	// the user, but will require much less changes in the system.
 
 	// Validating CpuAlter & RamAlter to not be less then the current cpu/ram count
-	cpuStat, err := cpu.Counts(true)
-	if err != nil {
-		return err
-	}
+	inv := inventory.Get()
+	cpuStat := int(inv.CpuThreads)
 
 	if c.CPUAlter < 0 && cpuStat <= -c.CPUAlter {
 		return log.Errorf("Native: |CpuAlter| can't be more or equal the available Host CPUs: |%d| > %d", c.CPUAlter, cpuStat)
 	}
 
-	memStat, err := mem.VirtualMemory()
-	if err != nil {
-		return err
-	}
-	ramStat := memStat.Total / 1073741824 // Getting GB from Bytes
+	ramStat := inv.RamTotal
 
 	if c.RAMAlter < 0 && int(ramStat) <= -c.RAMAlter {
 		return log.Errorf("Native: |RamAlter| can't be more or equal the available Host RAM: |%d| > %d", c.RAMAlter, ramStat)
@@ -18,10 +18,8 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-
 	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/inventory"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 )
@@ -78,25 +76,35 @@ func (d *Driver) Prepare(config []byte) error {
 	}
 
 	// Collect node resources status
-	cpuStat, err := cpu.Counts(true)
-	if err != nil {
-		return err
-	}
-	d.totalCPU = uint(cpuStat)
-
-	memStat, err := mem.VirtualMemory()
-	if err != nil {
-		return err
+	inv := inventory.Get()
+	if inv.CpuThreads == 0 || inv.RamTotal == 0 {
+		return fmt.Errorf("Native: Unable to detect the node CPU & RAM: %d, %d", inv.CpuThreads, inv.RamTotal)
 	}
-	d.totalRAM = uint(memStat.Total / 1073741824) // Getting GB from Bytes
+	d.totalCPU = inv.CpuThreads
+	d.totalRAM = inv.RamTotal
 
 	// TODO: Cleanup the image directory in case the images are not good
 
 	return nil
 }
 
+// Capabilities of the driver, the disks are the directories or the mounted images
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"dir", "hfs+", "exfat", "fat32"},
+		Networks:  []string{""},
+		Tenancy:   true,
+	}
+}
+
 // ValidateDefinition checks LabelDefinition is ok
-func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return log.Error("Native: Resources validation failed:", err)
+	}
+
 	// Check options
 	var opts Options
 	if err := opts.Apply(def.Options); err != nil {
@@ -215,7 +223,14 @@ func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*
 
 	log.Infof("Native: Started environment for user %q", user)
 
-	return &types.Resource{Identifier: user}, nil
+	return &types.Resource{Identifier: user, DriverInfo: drivers.InfoJSON(map[string]any{"home_dir": homedir})}, nil
+}
+
+// InfoSchema describes the environment info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "home_dir", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Home directory of the workload user"},
+	}
 }
 
 // Status shows status of the resource
@@ -0,0 +1,149 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package oci
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version of the OCI APIs used by the driver
+const apiVersion = "/20160918"
+
+// apiError is the OCI error response, returned as error by call
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("OCI: API responded with status %d: %s: %s", e.Status, e.Code, e.Message)
+}
+
+// signingString returns the string to sign and the list of the signed headers
+func signingString(req *http.Request) (string, string) {
+	headers := []string{"(request-target)", "date", "host"}
+	if req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodPatch {
+		headers = append(headers, "x-content-sha256", "content-type", "content-length")
+	}
+
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, h+": "+strings.ToLower(req.Method)+" "+req.URL.RequestURI())
+		case "host":
+			lines = append(lines, h+": "+req.Host)
+		default:
+			lines = append(lines, h+": "+req.Header.Get(h))
+		}
+	}
+	return strings.Join(lines, "\n"), strings.Join(headers, " ")
+}
+
+// sign adds the OCI signature to the request
+func (c *Config) sign(req *http.Request, body []byte) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	if req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodPatch {
+		sum := sha256.Sum256(body)
+		req.Header.Set("X-Content-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	toSign, headers := signingString(req)
+	hash := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return fmt.Errorf("OCI: Unable to sign the request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature version="1",keyId="%s/%s/%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		c.Tenancy, c.User, c.Fingerprint, headers, base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// call sends signed request to the OCI service endpoint and decodes the response into out
+func (d *Driver) call(method, endpoint, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("OCI: Unable to encode %s %s request: %v", method, path, err)
+		}
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(endpoint, "/")+apiVersion+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("OCI: Unable to create %s %s request: %v", method, path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if err = d.cfg.sign(req, body); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: time.Duration(d.cfg.Timeout)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OCI: %s %s request failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("OCI: Unable to read %s %s response: %v", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &apiError{Status: resp.StatusCode}
+		if err := json.Unmarshal(data, apiErr); err != nil {
+			apiErr.Message = "unknown error"
+		}
+		return apiErr
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("OCI: Unable to decode %s %s response: %v", method, path, err)
+	}
+	return nil
+}
+
+// isNotFound checks if the error is 404 response
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.Status == http.StatusNotFound
+}
+
+// isCapacityError checks if the error is caused by the lack of capacity or limits in the
+// availability domain, so the instance could be tried in another one
+func isCapacityError(err error) bool {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		return false
+	}
+	return apiErr.Code == "LimitExceeded" || apiErr.Code == "QuotaExceeded" ||
+		strings.Contains(strings.ToLower(apiErr.Message), "out of host capacity")
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package oci implements driver for the Oracle Cloud Infrastructure
+//
+// The driver talks to the OCI Core Services REST API directly and signs the requests with the API
+// signing key of the User as described in the OCI request signatures documentation.
+package oci
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - node driver configuration
+//
+// Example:
+//
+//	region: us-ashburn-1
+//	tenancy: ocid1.tenancy.oc1..aaaa
+//	user: ocid1.user.oc1..aaaa
+//	fingerprint: 12:34:56:78:90:ab:cd:ef:12:34:56:78:90:ab:cd:ef
+//	key_file: /etc/fish/oci_api_key.pem
+//	compartment: ocid1.compartment.oc1..aaaa
+type Config struct {
+	Region      string `json:"region"`      // OCI Region to connect to
+	Tenancy     string `json:"tenancy"`     // OCID of the tenancy
+	User        string `json:"user"`        // OCID of the User which owns the API key
+	Fingerprint string `json:"fingerprint"` // Fingerprint of the API signing key
+	Key         string `json:"key"`         // PEM encoded RSA private API signing key
+	KeyFile     string `json:"key_file"`    // Path to the private key file, used if key is not set
+	Compartment string `json:"compartment"` // OCID of the compartment to run the instances in, tenancy by default
+
+	// Optional
+	InstanceTags map[string]string `json:"instance_tags"` // Freeform tags to set on the instances this node provision
+
+	// Endpoints of the services, derived from the region by default
+	ComputeEndpoint  string `json:"compute_endpoint"`  // Core Services API endpoint
+	IdentityEndpoint string `json:"identity_endpoint"` // Identity API endpoint to list the availability domains
+
+	// Various options to not hardcode the important numbers
+	Timeout            util.Duration `json:"timeout"`              // Timeout of the API request, default: 30s
+	InstanceCreateWait util.Duration `json:"instance_create_wait"` // Maximum wait time for instance to become running, default: 10m
+
+	privateKey *rsa.PrivateKey
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("OCI: Unable to apply the driver config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.Region == "" {
+		return fmt.Errorf("OCI: Region is required")
+	}
+	if c.Tenancy == "" || c.User == "" || c.Fingerprint == "" {
+		return fmt.Errorf("OCI: Tenancy, User and Fingerprint are required to sign the requests")
+	}
+
+	if c.Key == "" && c.KeyFile != "" {
+		data, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return fmt.Errorf("OCI: Unable to read the private key file: %v", err)
+		}
+		c.Key = string(data)
+	}
+	if c.Key == "" {
+		return fmt.Errorf("OCI: Private key or key file is required")
+	}
+	key, err := parsePrivateKey([]byte(c.Key))
+	if err != nil {
+		return err
+	}
+	c.privateKey = key
+
+	if c.Compartment == "" {
+		c.Compartment = c.Tenancy
+	}
+	if c.ComputeEndpoint == "" {
+		c.ComputeEndpoint = fmt.Sprintf("https://iaas.%s.oraclecloud.com", c.Region)
+	}
+	if c.IdentityEndpoint == "" {
+		c.IdentityEndpoint = fmt.Sprintf("https://identity.%s.oraclecloud.com", c.Region)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = util.Duration(30 * time.Second)
+	}
+	if c.InstanceCreateWait <= 0 {
+		c.InstanceCreateWait = util.Duration(10 * time.Minute)
+	}
+
+	return nil
+}
+
+// parsePrivateKey reads the RSA key in PKCS1 or PKCS8 PEM format
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("OCI: Unable to decode the private key PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("OCI: Unable to parse the private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("OCI: Private key is not RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,323 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package oci
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// How often to check the instance status while waiting for it
+var instancePollInterval = 5 * time.Second
+
+// Factory implements drivers.ResourceDriverFactory interface
+type Factory struct{}
+
+// Name shows name of the driver factory
+func (*Factory) Name() string {
+	return "oci"
+}
+
+// NewResourceDriver creates new resource driver
+func (*Factory) NewResourceDriver() drivers.ResourceDriver {
+	return &Driver{}
+}
+
+func init() {
+	drivers.FactoryList = append(drivers.FactoryList, &Factory{})
+}
+
+// Driver implements drivers.ResourceDriver interface
+type Driver struct {
+	cfg Config
+
+	// Contains the region availability domains
+	domains      []string
+	domainsMutex sync.Mutex
+}
+
+// Name returns name of the driver
+func (*Driver) Name() string {
+	return "oci"
+}
+
+// IsRemote needed to detect the out-of-node resources managed by this driver
+func (*Driver) IsRemote() bool {
+	return true
+}
+
+// Prepare initializes the driver
+func (d *Driver) Prepare(config []byte) error {
+	if err := d.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := d.cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Checking the credentials are working
+	if _, err := d.getAvailabilityDomains(&Options{}); err != nil {
+		return fmt.Errorf("OCI: Unable to list availability domains: %v", err)
+	}
+	return nil
+}
+
+// Capabilities of the driver, no additional disks and network is the subnet OCID
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		DiskTypes: []string{},
+		Networks:  []string{"?*"},
+	}
+}
+
+// ValidateDefinition checks LabelDefinition is ok
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return err
+	}
+
+	if len(def.Resources.Disks) > 0 {
+		return fmt.Errorf("OCI: Additional disks are not supported, use boot_volume_size option")
+	}
+	if def.Resources.Network == "" {
+		return fmt.Errorf("OCI: Subnet OCID is required in Resources network")
+	}
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return fmt.Errorf("OCI: Resources validation failed: %s", err)
+	}
+
+	return nil
+}
+
+// AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
+//
+// Uses the compute capacity reports of the availability domains to find how many instances of
+// the shape could be created right now
+func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.LabelDefinition) int64 {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		log.Error("OCI: Unable to apply options:", err)
+		return -1
+	}
+	compartment := opts.Compartment
+	if compartment == "" {
+		compartment = d.cfg.Compartment
+	}
+
+	domains, err := d.getAvailabilityDomains(&opts)
+	if err != nil {
+		log.Error("OCI: Unable to get availability domains:", err)
+		return -1
+	}
+
+	var capacity int64
+	for _, ad := range domains {
+		shape := map[string]any{"instanceShape": opts.Shape}
+		if cfg := getShapeConfig(opts.Shape, def.Resources); cfg != nil {
+			shape["instanceShapeConfig"] = cfg
+		}
+		req := map[string]any{
+			"compartmentId":       compartment,
+			"availabilityDomain":  ad,
+			"shapeAvailabilities": []any{shape},
+		}
+		var resp struct {
+			ShapeAvailabilities []struct {
+				AvailableCount int64 `json:"availableCount"`
+			} `json:"shapeAvailabilities"`
+		}
+		if err = d.call("POST", d.cfg.ComputeEndpoint, "/computeCapacityReports", req, &resp); err != nil {
+			log.Errorf("OCI: Unable to get capacity report for %s: %v", ad, err)
+			return -1
+		}
+		for _, a := range resp.ShapeAvailabilities {
+			capacity += a.AvailableCount
+		}
+	}
+
+	log.Debugf("OCI: AvailableCapacity for shape %q: %d", opts.Shape, capacity)
+
+	return capacity
+}
+
+// Allocate Instance with provided image
+//
+// It tries the availability domains in order and moves to the next one when the current is out
+// of capacity. Uses metadata to fill the instance userdata
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	// Generate fish name
+	buf := crypt.RandBytes(6)
+	iName := fmt.Sprintf("fish-%02x%02x%02x%02x%02x%02x", buf[0], buf[1], buf[2], buf[3], buf[4], buf[5])
+
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return nil, fmt.Errorf("OCI: %s: Unable to apply options: %v", iName, err)
+	}
+	compartment := opts.Compartment
+	if compartment == "" {
+		compartment = d.cfg.Compartment
+	}
+
+	imageID, err := d.getImageID(compartment, opts.Image)
+	if err != nil {
+		return nil, fmt.Errorf("OCI: %s: Unable to get image: %v", iName, err)
+	}
+	log.Infof("OCI: %s: Selected image: %q", iName, imageID)
+
+	domains, err := d.getAvailabilityDomains(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("OCI: %s: Unable to get availability domains: %v", iName, err)
+	}
+
+	source := map[string]any{"sourceType": "image", "imageId": imageID}
+	if opts.BootVolumeSize > 0 {
+		source["bootVolumeSizeInGBs"] = opts.BootVolumeSize
+	}
+	input := map[string]any{
+		"compartmentId": compartment,
+		"displayName":   iName,
+		"shape":         opts.Shape,
+		"sourceDetails": source,
+		"createVnicDetails": map[string]any{
+			"subnetId":       def.Resources.Network,
+			"assignPublicIp": opts.AssignPublicIP,
+		},
+	}
+	if cfg := getShapeConfig(opts.Shape, def.Resources); cfg != nil {
+		input["shapeConfig"] = cfg
+		log.Infof("OCI: %s: Flexible shape config: %.0f OCPUs, %.0fGB RAM", iName, cfg.OCPUs, cfg.MemoryInGBs)
+	}
+
+	instMeta := map[string]string{}
+	if opts.SSHAuthorizedKeys != "" {
+		instMeta["ssh_authorized_keys"] = opts.SSHAuthorizedKeys
+	}
+	if opts.UserDataFormat != "" {
+		// Set UserData field
+		userdata, err := util.SerializeMetadata(opts.UserDataFormat, opts.UserDataPrefix, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("OCI: %s: Unable to serialize metadata to userdata: %v", iName, err)
+		}
+		instMeta["user_data"] = base64.StdEncoding.EncodeToString(userdata)
+	}
+	if len(instMeta) > 0 {
+		input["metadata"] = instMeta
+	}
+
+	tags := map[string]string{}
+	// Append tags to the map - from opts (low priority) and from cfg (high priority)
+	for k, v := range opts.Tags {
+		tags[k] = v
+	}
+	for k, v := range d.cfg.InstanceTags {
+		tags[k] = v
+	}
+	if len(tags) > 0 {
+		input["freeformTags"] = tags
+	}
+
+	// Run the instance in the first availability domain which has the capacity
+	var inst instance
+	for _, ad := range domains {
+		input["availabilityDomain"] = ad
+		if err = d.call("POST", d.cfg.ComputeEndpoint, "/instances", input, &inst); err == nil {
+			log.Infof("OCI: %s: Launched instance %q in %s", iName, inst.ID, ad)
+			break
+		}
+		if !isCapacityError(err) {
+			return nil, log.Errorf("OCI: %s: Unable to launch instance: %v", iName, err)
+		}
+		log.Warnf("OCI: %s: No capacity in %s, trying the next availability domain: %v", iName, ad, err)
+	}
+	if inst.ID == "" {
+		return nil, log.Errorf("OCI: %s: Unable to launch instance in any of %q: %v", iName, domains, err)
+	}
+
+	// Returning identifier so Fish could deallocate what was created
+	res := &types.Resource{Identifier: inst.ID}
+
+	running, err := d.waitInstanceRunning(inst.ID)
+	if err != nil {
+		return res, log.Errorf("OCI: %s: %v", iName, err)
+	}
+	v, err := d.getInstanceVnic(compartment, inst.ID)
+	if err != nil {
+		return res, log.Errorf("OCI: %s: Unable to get instance VNIC: %v", iName, err)
+	}
+
+	res.IpAddr = v.PrivateIP
+	if v.PublicIP != "" {
+		res.IpAddr = v.PublicIP
+	}
+	res.DriverInfo = instanceInfo(running, v)
+	log.Infof("OCI: %s: Allocate of instance completed: %q, %q", iName, inst.ID, res.IpAddr)
+
+	return res, nil
+}
+
+// InfoSchema describes the instance info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "shape", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Shape of the instance"},
+		{Name: "availability_domain", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Availability domain of the instance"},
+		{Name: "image_id", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Image the instance was started from"},
+		{Name: "public_ip", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Public IP of the instance if assigned"},
+	}
+}
+
+// Status shows status of the resource
+func (d *Driver) Status(res *types.Resource) (string, error) {
+	if res == nil || res.Identifier == "" {
+		return "", fmt.Errorf("OCI: Invalid resource: %v", res)
+	}
+	inst, err := d.getInstance(res.Identifier)
+	if err != nil {
+		return "", fmt.Errorf("OCI: Error during status check for %s: %v", res.Identifier, err)
+	}
+	if inst != nil && inst.LifecycleState != "TERMINATED" {
+		return drivers.StatusAllocated, nil
+	}
+	return drivers.StatusNone, nil
+}
+
+// GetTask returns task struct by name, the driver has no tasks yet
+func (*Driver) GetTask(_, _ string) drivers.ResourceDriverTask {
+	return nil
+}
+
+// Deallocate the resource
+func (d *Driver) Deallocate(res *types.Resource) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("OCI: Invalid resource: %v", res)
+	}
+
+	err := d.call("DELETE", d.cfg.ComputeEndpoint, "/instances/"+url.PathEscape(res.Identifier)+"?preserveBootVolume=false", nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("OCI: Error during terminating the instance %s: %v", res.Identifier, err)
+	}
+
+	log.Infof("OCI: %s: Deallocate of instance completed", res.Identifier)
+
+	return nil
+}
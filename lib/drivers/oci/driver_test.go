@@ -0,0 +1,193 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package oci
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+var signatureRegexp = regexp.MustCompile(`signature="([^"]+)"`)
+
+// mockCloud verifies the request signatures and serves the parts of OCI API used by the driver,
+// the first availability domain is always out of capacity
+type mockCloud struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	key      *rsa.PublicKey
+	launch   map[string]any // The last successful instance launch request
+	launched bool
+}
+
+func newMockCloud(key *rsa.PublicKey) *mockCloud {
+	m := &mockCloud{key: key}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockCloud) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	toSign, _ := signingString(r)
+	hash := sha256.Sum256([]byte(toSign))
+	sig := signatureRegexp.FindStringSubmatch(r.Header.Get("Authorization"))
+	if len(sig) < 2 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	sigData, _ := base64.StdEncoding.DecodeString(sig[1])
+	if err := rsa.VerifyPKCS1v15(m.key, crypto.SHA256, hash[:], sigData); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"NotAuthenticated","message":"wrong signature"}`))
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /20160918/availabilityDomains":
+		w.Write([]byte(`[{"name":"AD-1"},{"name":"AD-2"}]`))
+	case "GET /20160918/images":
+		w.Write([]byte(`[{"id":"ocid1.image.new"},{"id":"ocid1.image.old"}]`))
+	case "POST /20160918/computeCapacityReports":
+		var req struct {
+			AvailabilityDomain string `json:"availabilityDomain"`
+		}
+		json.Unmarshal(body, &req)
+		if req.AvailabilityDomain == "AD-1" {
+			w.Write([]byte(`{"shapeAvailabilities":[{"availableCount":0,"availabilityStatus":"OUT_OF_HOST_CAPACITY"}]}`))
+		} else {
+			w.Write([]byte(`{"shapeAvailabilities":[{"availableCount":3,"availabilityStatus":"AVAILABLE"}]}`))
+		}
+	case "POST /20160918/instances":
+		var req map[string]any
+		json.Unmarshal(body, &req)
+		if req["availabilityDomain"] == "AD-1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code":"InternalError","message":"Out of host capacity."}`))
+			return
+		}
+		m.launch = req
+		m.launched = true
+		w.Write([]byte(`{"id":"ocid1.instance.1","lifecycleState":"PROVISIONING"}`))
+	case "GET /20160918/instances/ocid1.instance.1":
+		state := "RUNNING"
+		if !m.launched {
+			state = "TERMINATED"
+		}
+		w.Write([]byte(`{"id":"ocid1.instance.1","lifecycleState":"` + state + `","availabilityDomain":"AD-2",
+			"shape":"VM.Standard.A1.Flex","imageId":"ocid1.image.new"}`))
+	case "GET /20160918/vnicAttachments":
+		w.Write([]byte(`[{"vnicId":"ocid1.vnic.1","lifecycleState":"ATTACHED"}]`))
+	case "GET /20160918/vnics/ocid1.vnic.1":
+		w.Write([]byte(`{"privateIp":"10.0.0.5"}`))
+	case "DELETE /20160918/instances/ocid1.instance.1":
+		m.launched = false
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"Unknown path"}`))
+	}
+}
+
+func Test_oci_driver_lifecycle(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	cloud := newMockCloud(&key.PublicKey)
+	defer cloud.Close()
+
+	cfg, _ := json.Marshal(map[string]any{
+		"region":            "test-region-1",
+		"tenancy":           "ocid1.tenancy.test",
+		"user":              "ocid1.user.test",
+		"fingerprint":       "00:11:22",
+		"key":               string(keyPEM),
+		"compute_endpoint":  cloud.URL,
+		"identity_endpoint": cloud.URL,
+	})
+	d := &Driver{}
+	if err := d.Prepare(cfg); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+
+	def := types.LabelDefinition{
+		Driver:    "oci",
+		Options:   `{"image":"ubuntu-arm","shape":"VM.Standard.A1.Flex","userdata_format":"json"}`,
+		Resources: types.Resources{Cpu: 4, Ram: 16, Network: "ocid1.subnet.test"},
+	}
+	if err := d.ValidateDefinition(def); err != nil {
+		t.Fatalf("Unable to validate definition: %v", err)
+	}
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 3 {
+		t.Fatalf("Wrong capacity: %d", capacity)
+	}
+
+	res, err := d.Allocate(def, map[string]any{"key": "value"})
+	if err != nil {
+		t.Fatalf("Unable to allocate: %v", err)
+	}
+	if res.Identifier != "ocid1.instance.1" || res.IpAddr != "10.0.0.5" {
+		t.Fatalf("Wrong allocated resource: %+v", res)
+	}
+	if err := drivers.ValidateInfo(d.InfoSchema(), res.DriverInfo); err != nil {
+		t.Fatalf("Wrong driver info %s: %v", res.DriverInfo, err)
+	}
+	if cloud.launch["availabilityDomain"] != "AD-2" {
+		t.Fatalf("Instance should fallback to AD-2: %v", cloud.launch["availabilityDomain"])
+	}
+	if sc, _ := cloud.launch["shapeConfig"].(map[string]any); sc["ocpus"] != float64(4) || sc["memoryInGBs"] != float64(16) {
+		t.Fatalf("Wrong shape config: %v", cloud.launch["shapeConfig"])
+	}
+
+	if status, err := d.Status(res); err != nil || status != drivers.StatusAllocated {
+		t.Fatalf("Wrong status: %q, %v", status, err)
+	}
+	if err := d.Deallocate(res); err != nil {
+		t.Fatalf("Unable to deallocate: %v", err)
+	}
+	if status, err := d.Status(res); err != nil || status != drivers.StatusNone {
+		t.Fatalf("Wrong status after deallocate: %q, %v", status, err)
+	}
+}
+
+func Test_oci_shape_config(t *testing.T) {
+	if cfg := getShapeConfig("VM.Standard.E4.Flex", types.Resources{Cpu: 3, Ram: 8}); cfg == nil || cfg.OCPUs != 2 || cfg.MemoryInGBs != 8 {
+		t.Fatalf("Wrong x86 shape config: %+v", cfg)
+	}
+	if cfg := getShapeConfig("VM.Standard.A1.Flex", types.Resources{Cpu: 3, Ram: 8}); cfg == nil || cfg.OCPUs != 3 {
+		t.Fatalf("Wrong Ampere shape config: %+v", cfg)
+	}
+	if cfg := getShapeConfig("VM.Standard2.1", types.Resources{Cpu: 2, Ram: 15}); cfg != nil {
+		t.Fatalf("Fixed shape should not have config: %+v", cfg)
+	}
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Options for label definition
+//
+// The subnet OCID is taken from the Label Resources `network`. For the flexible shapes the OCPUs
+// and memory are derived from the Resources: on Ampere (A1, A2) shapes 1 OCPU is 1 vCPU and on
+// the x86 shapes 1 OCPU is 2 vCPUs.
+//
+// Example:
+//
+//	image: Canonical-Ubuntu-22.04-aarch64    # OCID or display name
+//	shape: VM.Standard.A1.Flex
+//	availability_domains: [Uocm:US-ASHBURN-AD-1, Uocm:US-ASHBURN-AD-2]
+//	tags:
+//	  somekey: somevalue
+type Options struct {
+	Image               string            `json:"image"`                // OCID/Display name of the image (the latest one with the name is used)
+	Shape               string            `json:"shape"`                // Shape of the instance
+	Compartment         string            `json:"compartment"`          // OCID of the compartment, the driver config one by default
+	AvailabilityDomains []string          `json:"availability_domains"` // Where to try to run the instance in order, all the region ones by default
+	AssignPublicIP      bool              `json:"assign_public_ip"`     // Assign the public IP to the instance VNIC
+	BootVolumeSize      uint              `json:"boot_volume_size"`     // Size of the boot volume in GB, image default if 0
+	SSHAuthorizedKeys   string            `json:"ssh_authorized_keys"`  // Public keys to put into the instance metadata
+	Tags                map[string]string `json:"tags"`                 // Freeform tags to add during instance creation
+
+	UserDataFormat string `json:"userdata_format"` // If not empty - will store the resource metadata to userdata in defined format
+	UserDataPrefix string `json:"userdata_prefix"` // Optional if need to add custom prefix to the metadata key during formatting
+}
+
+// Apply takes json and applies it to the options structure
+func (o *Options) Apply(options util.UnparsedJSON) error {
+	if err := json.Unmarshal([]byte(options), o); err != nil {
+		return log.Error("OCI: Unable to apply the driver options", err)
+	}
+
+	return o.Validate()
+}
+
+// Validate makes sure the options have the required defaults & that the required fields are set
+func (o *Options) Validate() error {
+	if o.Image == "" {
+		return fmt.Errorf("OCI: No image is specified")
+	}
+	if o.Shape == "" {
+		return fmt.Errorf("OCI: No shape is specified")
+	}
+
+	if !util.Contains([]string{"", "json", "env", "ps1"}, o.UserDataFormat) {
+		return fmt.Errorf("OCI: Unsupported userdata format: %s", o.UserDataFormat)
+	}
+
+	return nil
+}
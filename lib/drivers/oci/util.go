@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package oci
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// instance is the part of OCI instance we need
+type instance struct {
+	ID                 string `json:"id"`
+	LifecycleState     string `json:"lifecycleState"`
+	AvailabilityDomain string `json:"availabilityDomain"`
+	Shape              string `json:"shape"`
+	ImageID            string `json:"imageId"`
+}
+
+// vnic is the part of OCI VNIC we need
+type vnic struct {
+	PrivateIP string `json:"privateIp"`
+	PublicIP  string `json:"publicIp"`
+}
+
+// shapeConfig is the OCPUs and memory of the flexible shape instance
+type shapeConfig struct {
+	OCPUs       float64 `json:"ocpus"`
+	MemoryInGBs float64 `json:"memoryInGBs"`
+}
+
+// getShapeConfig returns the flexible shape config derived from the Resources, nil for the fixed
+// shapes. On Ampere shapes 1 OCPU is 1 vCPU and on the x86 ones 1 OCPU is 2 vCPUs (cores with HT)
+func getShapeConfig(shape string, res types.Resources) *shapeConfig {
+	if !strings.HasSuffix(shape, ".Flex") {
+		return nil
+	}
+	ocpus := float64((res.Cpu + 1) / 2)
+	if strings.Contains(shape, ".A1.") || strings.Contains(shape, ".A2.") {
+		ocpus = float64(res.Cpu)
+	}
+	return &shapeConfig{OCPUs: ocpus, MemoryInGBs: float64(res.Ram)}
+}
+
+// getAvailabilityDomains returns the domains to run the instance in, all the region domains are
+// requested once and cached since they are not changing
+func (d *Driver) getAvailabilityDomains(opts *Options) ([]string, error) {
+	if len(opts.AvailabilityDomains) > 0 {
+		return opts.AvailabilityDomains, nil
+	}
+
+	d.domainsMutex.Lock()
+	defer d.domainsMutex.Unlock()
+	if len(d.domains) > 0 {
+		return d.domains, nil
+	}
+
+	var resp []struct {
+		Name string `json:"name"`
+	}
+	if err := d.call("GET", d.cfg.IdentityEndpoint, "/availabilityDomains?compartmentId="+url.QueryEscape(d.cfg.Tenancy), nil, &resp); err != nil {
+		return nil, err
+	}
+	for _, ad := range resp {
+		d.domains = append(d.domains, ad.Name)
+	}
+	if len(d.domains) == 0 {
+		return nil, fmt.Errorf("OCI: No availability domains found in region %s", d.cfg.Region)
+	}
+	return d.domains, nil
+}
+
+// getImageID returns OCID of the image by OCID or display name, the latest one is used if there
+// are multiple images with the same name
+func (d *Driver) getImageID(compartment, name string) (string, error) {
+	if strings.HasPrefix(name, "ocid1.image.") {
+		return name, nil
+	}
+
+	var resp []struct {
+		ID string `json:"id"`
+	}
+	query := url.Values{
+		"compartmentId":  {compartment},
+		"displayName":    {name},
+		"lifecycleState": {"AVAILABLE"},
+		"sortBy":         {"TIMECREATED"},
+		"sortOrder":      {"DESC"},
+	}
+	if err := d.call("GET", d.cfg.ComputeEndpoint, "/images?"+query.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+	if len(resp) == 0 {
+		return "", fmt.Errorf("OCI: Unable to find image %q", name)
+	}
+	return resp[0].ID, nil
+}
+
+// getInstance returns the instance by OCID, nil if it's not exists
+func (d *Driver) getInstance(id string) (*instance, error) {
+	var inst instance
+	if err := d.call("GET", d.cfg.ComputeEndpoint, "/instances/"+url.PathEscape(id), nil, &inst); isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// waitInstanceRunning waits for the instance to become running
+func (d *Driver) waitInstanceRunning(id string) (*instance, error) {
+	deadline := time.Now().Add(time.Duration(d.cfg.InstanceCreateWait))
+	for {
+		inst, err := d.getInstance(id)
+		if err != nil {
+			log.Warnf("OCI: Error during getting instance %s while waiting for it to become running: %v", id, err)
+		} else if inst == nil {
+			return nil, fmt.Errorf("OCI: Instance %s disappeared", id)
+		} else if inst.LifecycleState == "RUNNING" {
+			return inst, nil
+		} else if inst.LifecycleState == "TERMINATING" || inst.LifecycleState == "TERMINATED" {
+			return nil, fmt.Errorf("OCI: Instance %s was terminated during start", id)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("OCI: Instance %s is not running after %s", id, time.Duration(d.cfg.InstanceCreateWait))
+		}
+		time.Sleep(instancePollInterval)
+	}
+}
+
+// getInstanceVnic returns the primary VNIC of the instance
+func (d *Driver) getInstanceVnic(compartment, id string) (*vnic, error) {
+	var attachments []struct {
+		VnicID         string `json:"vnicId"`
+		LifecycleState string `json:"lifecycleState"`
+	}
+	query := url.Values{"compartmentId": {compartment}, "instanceId": {id}}
+	if err := d.call("GET", d.cfg.ComputeEndpoint, "/vnicAttachments?"+query.Encode(), nil, &attachments); err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		if a.LifecycleState != "ATTACHED" || a.VnicID == "" {
+			continue
+		}
+		var v vnic
+		if err := d.call("GET", d.cfg.ComputeEndpoint, "/vnics/"+url.PathEscape(a.VnicID), nil, &v); err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+	return nil, fmt.Errorf("OCI: No attached VNIC found for instance %s", id)
+}
+
+// instanceInfo collects the driver info of the Resource described by InfoSchema
+func instanceInfo(inst *instance, v *vnic) util.UnparsedJSON {
+	info := map[string]any{
+		"shape":               inst.Shape,
+		"availability_domain": inst.AvailabilityDomain,
+		"image_id":            inst.ImageID,
+	}
+	if v.PublicIP != "" {
+		info["public_ip"] = v.PublicIP
+	}
+	return drivers.InfoJSON(info)
+}
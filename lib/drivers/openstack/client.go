@@ -0,0 +1,237 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Types of the services in Keystone catalog used by the driver
+const (
+	serviceCompute = "compute"
+	serviceNetwork = "network"
+	serviceImage   = "image"
+)
+
+// Nova microversion which allows to set volume type in block device mapping
+const computeMicroversion = "2.67"
+
+// errNotFound is returned when the API responded with 404
+var errNotFound = fmt.Errorf("OPENSTACK: Not found")
+
+// tokenResponse is the part of Keystone token we need
+type tokenResponse struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Catalog   []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// authRequest builds Keystone v3 token request body from the config
+func (c *Config) authRequest() map[string]any {
+	if c.ApplicationCredentialID != "" {
+		return map[string]any{"auth": map[string]any{
+			"identity": map[string]any{
+				"methods": []string{"application_credential"},
+				"application_credential": map[string]any{
+					"id":     c.ApplicationCredentialID,
+					"secret": c.ApplicationCredentialSecret,
+				},
+			},
+		}}
+	}
+
+	project := map[string]any{"id": c.ProjectID}
+	if c.ProjectID == "" {
+		project = map[string]any{"name": c.ProjectName, "domain": map[string]any{"name": c.ProjectDomainName}}
+	}
+	return map[string]any{"auth": map[string]any{
+		"identity": map[string]any{
+			"methods": []string{"password"},
+			"password": map[string]any{
+				"user": map[string]any{
+					"name":     c.Username,
+					"password": c.Password,
+					"domain":   map[string]any{"name": c.UserDomainName},
+				},
+			},
+		},
+		"scope": map[string]any{"project": project},
+	}}
+}
+
+// authenticate gets the new token and the service endpoints from Keystone
+func (d *Driver) authenticate() error {
+	body, err := json.Marshal(d.cfg.authRequest())
+	if err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to encode auth request: %v", err)
+	}
+
+	authURL := strings.TrimRight(d.cfg.AuthURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+	req, err := http.NewRequest(http.MethodPost, authURL+"/auth/tokens", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to create auth request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: time.Duration(d.cfg.Timeout)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OPENSTACK: Auth request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to read auth response: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("OPENSTACK: Auth responded with status %d: %s", resp.StatusCode, errorMessage(data))
+	}
+
+	var token tokenResponse
+	if err = json.Unmarshal(data, &token); err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to decode auth response: %v", err)
+	}
+
+	endpoints := make(map[string]string)
+	for _, service := range token.Token.Catalog {
+		for _, ep := range service.Endpoints {
+			if ep.Interface != d.cfg.Interface || (d.cfg.Region != "" && ep.Region != d.cfg.Region) {
+				continue
+			}
+			if _, ok := endpoints[service.Type]; !ok {
+				endpoints[service.Type] = strings.TrimRight(ep.URL, "/")
+			}
+		}
+	}
+	for _, service := range []string{serviceCompute, serviceNetwork, serviceImage} {
+		if _, ok := endpoints[service]; !ok {
+			return fmt.Errorf("OPENSTACK: Unable to find %s %s endpoint in region %q", d.cfg.Interface, service, d.cfg.Region)
+		}
+	}
+	// The network and image endpoints are usually registered without the API version
+	if !strings.HasSuffix(endpoints[serviceNetwork], "/v2.0") {
+		endpoints[serviceNetwork] += "/v2.0"
+	}
+	if !strings.HasSuffix(endpoints[serviceImage], "/v2") {
+		endpoints[serviceImage] += "/v2"
+	}
+
+	d.token = resp.Header.Get("X-Subject-Token")
+	d.tokenExpiresAt = token.Token.ExpiresAt
+	d.endpoints = endpoints
+
+	return nil
+}
+
+// call sends request to the service and decodes the response into out, the token is refreshed
+// when it's about to expire or was revoked
+func (d *Driver) call(method, service, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("OPENSTACK: Unable to encode %s %s request: %v", method, path, err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		d.tokenMutex.Lock()
+		if d.token == "" || time.Until(d.tokenExpiresAt) < time.Minute {
+			if err := d.authenticate(); err != nil {
+				d.tokenMutex.Unlock()
+				return err
+			}
+		}
+		token, endpoint := d.token, d.endpoints[service]
+		d.tokenMutex.Unlock()
+
+		req, err := http.NewRequest(method, endpoint+path, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("OPENSTACK: Unable to create %s %s request: %v", method, path, err)
+		}
+		req.Header.Set("X-Auth-Token", token)
+		req.Header.Set("Accept", "application/json")
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if service == serviceCompute {
+			req.Header.Set("X-OpenStack-Nova-API-Version", computeMicroversion)
+		}
+
+		client := &http.Client{Timeout: time.Duration(d.cfg.Timeout)}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("OPENSTACK: %s %s request failed: %v", method, path, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("OPENSTACK: Unable to read %s %s response: %v", method, path, err)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			// Token was revoked, trying again with the new one
+			d.tokenMutex.Lock()
+			d.token = ""
+			d.tokenMutex.Unlock()
+			continue
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return errNotFound
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("OPENSTACK: %s %s responded with status %d: %s", method, path, resp.StatusCode, errorMessage(data))
+		}
+		if out == nil || len(data) == 0 {
+			return nil
+		}
+		if err = json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("OPENSTACK: Unable to decode %s %s response: %v", method, path, err)
+		}
+		return nil
+	}
+}
+
+// errorMessage finds the error description in the OpenStack error response, the services are
+// using different formats like {"badRequest": {"message": "..."}} or {"NeutronError": {...}}
+func errorMessage(data []byte) string {
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(data, &resp); err == nil {
+		for _, raw := range resp {
+			var msg struct {
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(raw, &msg) == nil && msg.Message != "" {
+				return msg.Message
+			}
+		}
+	}
+	return "unknown error"
+}
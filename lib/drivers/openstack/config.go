@@ -0,0 +1,116 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package openstack implements driver for the private OpenStack clouds
+//
+// The driver talks to the OpenStack REST APIs directly: Keystone v3 to authenticate and find the
+// service endpoints, Nova to run the servers, Neutron to create the ports and floating IPs and
+// Glance to find the images. The Label disks are created as Cinder volumes attached to the server
+// on boot and removed with it.
+package openstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - node driver configuration
+//
+// Example:
+//
+//	auth_url: https://keystone.example.com:5000/v3
+//	region: RegionOne
+//	username: fish
+//	password: secret
+//	project_name: ci
+//	network: ci-net
+type Config struct {
+	AuthURL   string `json:"auth_url"`  // Keystone v3 endpoint to authenticate
+	Region    string `json:"region"`    // Region of the service endpoints, the first one is used if empty
+	Interface string `json:"interface"` // Interface of the service endpoints, "public" by default
+
+	// Password authentication
+	Username          string `json:"username"`            // Name of the User to authenticate
+	Password          string `json:"password"`            // Password of the User
+	UserDomainName    string `json:"user_domain_name"`    // Domain of the User, "Default" by default
+	ProjectName       string `json:"project_name"`        // Name of the project to scope the token to
+	ProjectID         string `json:"project_id"`          // ID of the project, used instead of the name if set
+	ProjectDomainName string `json:"project_domain_name"` // Domain of the project, "Default" by default
+
+	// Application credential authentication, used instead of password if set
+	ApplicationCredentialID     string `json:"application_credential_id"`     // ID of the application credential
+	ApplicationCredentialSecret string `json:"application_credential_secret"` // Secret of the application credential
+
+	// Optional
+	Network        string            `json:"network"`         // Name/ID of the network to use when Label does not set it
+	ServerMetadata map[string]string `json:"server_metadata"` // Metadata to set on the servers this node provision
+
+	// Various options to not hardcode the important numbers
+	Timeout          util.Duration `json:"timeout"`            // Timeout of the API request, default: 30s
+	ServerCreateWait util.Duration `json:"server_create_wait"` // Maximum wait time for server to become active, default: 10m
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("OPENSTACK: Unable to apply the driver config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.AuthURL == "" {
+		return fmt.Errorf("OPENSTACK: Keystone auth URL is required")
+	}
+	if _, err := url.ParseRequestURI(c.AuthURL); err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to parse Keystone auth URL: %v", err)
+	}
+
+	if c.ApplicationCredentialID != "" || c.ApplicationCredentialSecret != "" {
+		if c.ApplicationCredentialID == "" || c.ApplicationCredentialSecret == "" {
+			return fmt.Errorf("OPENSTACK: Application credential requires both ID and secret")
+		}
+	} else {
+		if c.Username == "" || c.Password == "" {
+			return fmt.Errorf("OPENSTACK: Username and password or application credential are required")
+		}
+		if c.ProjectName == "" && c.ProjectID == "" {
+			return fmt.Errorf("OPENSTACK: Project name or ID is required for password authentication")
+		}
+	}
+
+	if c.Interface == "" {
+		c.Interface = "public"
+	}
+	if c.UserDomainName == "" {
+		c.UserDomainName = "Default"
+	}
+	if c.ProjectDomainName == "" {
+		c.ProjectDomainName = "Default"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = util.Duration(30 * time.Second)
+	}
+	if c.ServerCreateWait <= 0 {
+		c.ServerCreateWait = util.Duration(10 * time.Minute)
+	}
+
+	return nil
+}
@@ -0,0 +1,416 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openstack
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// How often to check the server status while waiting for it
+var serverPollInterval = 5 * time.Second
+
+// Factory implements drivers.ResourceDriverFactory interface
+type Factory struct{}
+
+// Name shows name of the driver factory
+func (*Factory) Name() string {
+	return "openstack"
+}
+
+// NewResourceDriver creates new resource driver
+func (*Factory) NewResourceDriver() drivers.ResourceDriver {
+	return &Driver{}
+}
+
+func init() {
+	drivers.FactoryList = append(drivers.FactoryList, &Factory{})
+}
+
+// Driver implements drivers.ResourceDriver interface
+type Driver struct {
+	cfg Config
+
+	// Keystone token and the service endpoints from its catalog
+	token          string
+	tokenExpiresAt time.Time
+	endpoints      map[string]string
+	tokenMutex     sync.Mutex
+
+	// Contains flavors cache to not load them for every sneeze
+	flavors           []flavor
+	flavorsMutex      sync.Mutex
+	flavorsNextUpdate time.Time
+}
+
+// Name returns name of the driver
+func (*Driver) Name() string {
+	return "openstack"
+}
+
+// IsRemote needed to detect the out-of-node resources managed by this driver
+func (*Driver) IsRemote() bool {
+	return true
+}
+
+// Prepare initializes the driver
+func (d *Driver) Prepare(config []byte) error {
+	if err := d.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := d.cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Checking the credentials are working
+	d.tokenMutex.Lock()
+	defer d.tokenMutex.Unlock()
+	return d.authenticate()
+}
+
+// Capabilities of the driver, disk type is the Cinder volume type and network is the Neutron one
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"*"},
+		DiskClone: true,
+		Networks:  []string{"*"},
+	}
+}
+
+// ValidateDefinition checks LabelDefinition is ok
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return err
+	}
+
+	// Check resources
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return fmt.Errorf("OPENSTACK: Resources validation failed: %s", err)
+	}
+
+	return nil
+}
+
+// AvailableCapacity allows Fish to ask the driver about it's capacity (free slots) of a specific definition
+func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, def types.LabelDefinition) int64 {
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		log.Error("OPENSTACK: Unable to apply options:", err)
+		return -1
+	}
+
+	flavors, err := d.getFlavors()
+	if err != nil {
+		log.Error("OPENSTACK: Unable to get flavors:", err)
+		return -1
+	}
+	f, err := selectFlavor(flavors, opts.Flavor, def.Resources)
+	if err != nil {
+		log.Error("OPENSTACK: Unable to select flavor:", err)
+		return -1
+	}
+
+	var resp struct {
+		Limits struct {
+			Absolute absoluteLimits `json:"absolute"`
+		} `json:"limits"`
+	}
+	if err = d.call("GET", serviceCompute, "/limits", nil, &resp); err != nil {
+		log.Error("OPENSTACK: Unable to get project limits:", err)
+		return -1
+	}
+	l := resp.Limits.Absolute
+
+	// Return the most limiting value of the project quotas
+	capacity := int64(math.MaxInt32)
+	if l.MaxTotalInstances >= 0 {
+		capacity = min(capacity, l.MaxTotalInstances-l.TotalInstancesUsed)
+	}
+	if l.MaxTotalCores >= 0 && f.VCPUs > 0 {
+		capacity = min(capacity, (l.MaxTotalCores-l.TotalCoresUsed)/int64(f.VCPUs))
+	}
+	if l.MaxTotalRAMSize >= 0 && f.RAM > 0 {
+		capacity = min(capacity, (l.MaxTotalRAMSize-l.TotalRAMUsed)/int64(f.RAM))
+	}
+
+	log.Debugf("OPENSTACK: AvailableCapacity for flavor %q: %d", f.Name, capacity)
+
+	return max(capacity, 0)
+}
+
+// Allocate server with provided image
+//
+// It creates the port in the network, runs the server with the volumes attached and assigns the
+// floating IP if it's requested. Uses metadata to fill the server userdata
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	// Generate fish name
+	buf := crypt.RandBytes(6)
+	sName := fmt.Sprintf("fish-%02x%02x%02x%02x%02x%02x", buf[0], buf[1], buf[2], buf[3], buf[4], buf[5])
+
+	var opts Options
+	if err := opts.Apply(def.Options); err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: Unable to apply options: %v", sName, err)
+	}
+
+	flavors, err := d.getFlavors()
+	if err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: Unable to get flavors: %v", sName, err)
+	}
+	f, err := selectFlavor(flavors, opts.Flavor, def.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: %v", sName, err)
+	}
+	log.Infof("OPENSTACK: %s: Selected flavor: %q", sName, f.Name)
+
+	imageID, err := d.getImageID(opts.Image)
+	if err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: Unable to get image: %v", sName, err)
+	}
+	log.Infof("OPENSTACK: %s: Selected image: %q", sName, imageID)
+
+	network := def.Resources.Network
+	if network == "" {
+		network = d.cfg.Network
+	}
+	if network == "" {
+		return nil, fmt.Errorf("OPENSTACK: %s: No network is specified in Label Resources or driver config", sName)
+	}
+	networkID, err := d.getNetworkingID("networks", network)
+	if err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: Unable to get network: %v", sName, err)
+	}
+	log.Infof("OPENSTACK: %s: Selected network: %q", sName, networkID)
+
+	secGroups := []string{}
+	for _, name := range opts.SecurityGroups {
+		id, err := d.getNetworkingID("security-groups", name)
+		if err != nil {
+			return nil, fmt.Errorf("OPENSTACK: %s: Unable to get security group: %v", sName, err)
+		}
+		secGroups = append(secGroups, id)
+	}
+
+	var floatingNetworkID string
+	if opts.FloatingNetwork != "" {
+		if floatingNetworkID, err = d.getNetworkingID("networks", opts.FloatingNetwork); err != nil {
+			return nil, fmt.Errorf("OPENSTACK: %s: Unable to get floating network: %v", sName, err)
+		}
+	}
+
+	// Preparing the server request before creating anything to not leak the port on error
+	srvReq := map[string]any{
+		"name":      sName,
+		"imageRef":  imageID,
+		"flavorRef": f.ID,
+	}
+	if opts.KeyName != "" {
+		srvReq["key_name"] = opts.KeyName
+	}
+	if opts.AvailabilityZone != "" {
+		srvReq["availability_zone"] = opts.AvailabilityZone
+	}
+
+	srvMeta := map[string]string{}
+	// Append metadata to the map - from opts (low priority) and from cfg (high priority)
+	for k, v := range opts.Metadata {
+		srvMeta[k] = v
+	}
+	for k, v := range d.cfg.ServerMetadata {
+		srvMeta[k] = v
+	}
+	if len(srvMeta) > 0 {
+		srvReq["metadata"] = srvMeta
+	}
+
+	if opts.UserDataFormat != "" {
+		// Set UserData field
+		userdata, err := util.SerializeMetadata(opts.UserDataFormat, opts.UserDataPrefix, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("OPENSTACK: %s: Unable to serialize metadata to userdata: %v", sName, err)
+		}
+		srvReq["user_data"] = base64.StdEncoding.EncodeToString(userdata)
+	}
+
+	// Prepare the volumes mapping, the image is booted from the flavor local disk
+	if len(def.Resources.Disks) > 0 {
+		mapping := []map[string]any{{
+			"boot_index":            0,
+			"uuid":                  imageID,
+			"source_type":           "image",
+			"destination_type":      "local",
+			"delete_on_termination": true,
+		}}
+		// Sorting the disks to attach them in the same order every time
+		names := make([]string, 0, len(def.Resources.Disks))
+		for name := range def.Resources.Disks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			disk := def.Resources.Disks[name]
+			volume := map[string]any{
+				"boot_index":            -1,
+				"source_type":           "blank",
+				"destination_type":      "volume",
+				"volume_size":           disk.Size,
+				"delete_on_termination": true,
+			}
+			if disk.Clone != "" {
+				volume["source_type"] = "snapshot"
+				volume["uuid"] = disk.Clone
+			}
+			if disk.Type != "" {
+				volume["volume_type"] = disk.Type
+			}
+			mapping = append(mapping, volume)
+		}
+		srvReq["block_device_mapping_v2"] = mapping
+	}
+
+	// Creating the port to know the IP and to manage the floating IP
+	var portResp struct {
+		Port port `json:"port"`
+	}
+	portReq := map[string]any{"port": map[string]any{
+		"name":            sName,
+		"network_id":      networkID,
+		"security_groups": secGroups,
+	}}
+	if err = d.call("POST", serviceNetwork, "/ports", portReq, &portResp); err != nil {
+		return nil, fmt.Errorf("OPENSTACK: %s: Unable to create port: %v", sName, err)
+	}
+	p := &portResp.Port
+	log.Infof("OPENSTACK: %s: Created port: %q", sName, p.ID)
+	if len(p.FixedIPs) == 0 {
+		d.cleanupPort(sName, p.ID)
+		return nil, fmt.Errorf("OPENSTACK: %s: Port %s has no IP address", sName, p.ID)
+	}
+	srvReq["networks"] = []map[string]any{{"port": p.ID}}
+
+	// Run the server
+	var srvResp struct {
+		Server server `json:"server"`
+	}
+	if err = d.call("POST", serviceCompute, "/servers", map[string]any{"server": srvReq}, &srvResp); err != nil {
+		d.cleanupPort(sName, p.ID)
+		return nil, log.Errorf("OPENSTACK: %s: Unable to create server: %v", sName, err)
+	}
+	srvID := srvResp.Server.ID
+
+	res := &types.Resource{Identifier: srvID, IpAddr: p.FixedIPs[0].IPAddress}
+
+	srv, err := d.waitServerActive(srvID)
+	if err != nil {
+		// Returning identifier so Fish could deallocate what was created
+		return res, log.Errorf("OPENSTACK: %s: %v", sName, err)
+	}
+
+	var fip *floatingIP
+	if floatingNetworkID != "" {
+		var fipResp struct {
+			FloatingIP floatingIP `json:"floatingip"`
+		}
+		fipReq := map[string]any{"floatingip": map[string]any{
+			"floating_network_id": floatingNetworkID,
+			"port_id":             p.ID,
+		}}
+		if err = d.call("POST", serviceNetwork, "/floatingips", fipReq, &fipResp); err != nil {
+			return res, log.Errorf("OPENSTACK: %s: Unable to create floating IP: %v", sName, err)
+		}
+		fip = &fipResp.FloatingIP
+		res.IpAddr = fip.FloatingIPAddress
+	}
+
+	res.DriverInfo = serverInfo(srv, f, imageID, p, fip)
+	log.Infof("OPENSTACK: %s: Allocate of server completed: %q, %q", sName, srvID, res.IpAddr)
+
+	return res, nil
+}
+
+// cleanupPort removes the port if the server was not created
+func (d *Driver) cleanupPort(sName, id string) {
+	if err := d.deletePort(id); err != nil {
+		log.Errorf("OPENSTACK: %s: Unable to cleanup port %s: %v", sName, id, err)
+	}
+}
+
+// InfoSchema describes the server info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "flavor", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Nova flavor of the server"},
+		{Name: "image_id", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Image the server was booted from"},
+		{Name: "port_id", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Neutron port of the server"},
+		{Name: "availability_zone", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Availability zone of the server"},
+		{Name: "floating_ip", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Floating IP assigned to the server if any"},
+	}
+}
+
+// Status shows status of the resource
+func (d *Driver) Status(res *types.Resource) (string, error) {
+	if res == nil || res.Identifier == "" {
+		return "", fmt.Errorf("OPENSTACK: Invalid resource: %v", res)
+	}
+	srv, err := d.getServer(res.Identifier)
+	if err != nil {
+		return "", fmt.Errorf("OPENSTACK: Error during status check for %s: %v", res.Identifier, err)
+	}
+	if srv != nil && srv.Status != "DELETED" && srv.Status != "SOFT_DELETED" {
+		return drivers.StatusAllocated, nil
+	}
+	return drivers.StatusNone, nil
+}
+
+// GetTask returns task struct by name, the driver has no tasks yet
+func (*Driver) GetTask(_, _ string) drivers.ResourceDriverTask {
+	return nil
+}
+
+// Deallocate the resource
+//
+// Removes the floating IPs, the server with its volumes and the port created for it
+func (d *Driver) Deallocate(res *types.Resource) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("OPENSTACK: Invalid resource: %v", res)
+	}
+
+	ports, err := d.getServerPorts(res.Identifier)
+	if err != nil {
+		return fmt.Errorf("OPENSTACK: Unable to get ports of server %s: %v", res.Identifier, err)
+	}
+
+	if err = d.call("DELETE", serviceCompute, "/servers/"+res.Identifier, nil, nil); err != nil && err != errNotFound {
+		return fmt.Errorf("OPENSTACK: Error during deleting the server %s: %v", res.Identifier, err)
+	}
+
+	for _, p := range ports {
+		if err = d.deletePort(p.ID); err != nil {
+			return fmt.Errorf("OPENSTACK: Unable to delete port %s of server %s: %v", p.ID, res.Identifier, err)
+		}
+	}
+
+	log.Infof("OPENSTACK: %s: Deallocate of server completed", res.Identifier)
+
+	return nil
+}
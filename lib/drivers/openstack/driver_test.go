@@ -0,0 +1,226 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openstack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// mockCloud serves the parts of Keystone, Nova, Neutron and Glance APIs used by the driver
+type mockCloud struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	server  map[string]any // The last server create request
+	servers map[string]bool
+	ports   map[string]bool
+	fips    map[string]bool
+}
+
+func newMockCloud() *mockCloud {
+	m := &mockCloud{servers: map[string]bool{}, ports: map[string]bool{}, fips: map[string]bool{}}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockCloud) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if r.URL.Path != "/identity/v3/auth/tokens" && r.Header.Get("X-Auth-Token") != "test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "POST /identity/v3/auth/tokens":
+		w.Header().Set("X-Subject-Token", "test-token")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token": {"expires_at": "2100-01-01T00:00:00Z", "catalog": [
+			{"type": "compute", "endpoints": [
+				{"interface": "internal", "region": "RegionOne", "url": "http://internal.example.com"},
+				{"interface": "public", "region": "RegionOne", "url": "` + m.URL + `/compute/v2.1"}]},
+			{"type": "network", "endpoints": [{"interface": "public", "region": "RegionOne", "url": "` + m.URL + `/network"}]},
+			{"type": "image", "endpoints": [{"interface": "public", "region": "RegionOne", "url": "` + m.URL + `/image/"}]}
+		]}}`))
+	case "GET /compute/v2.1/flavors/detail":
+		w.Write([]byte(`{"flavors": [
+			{"id": "3", "name": "m1.large", "vcpus": 4, "ram": 8192, "disk": 40},
+			{"id": "2", "name": "m1.medium", "vcpus": 2, "ram": 4096, "disk": 20},
+			{"id": "1", "name": "m1.small", "vcpus": 1, "ram": 2048, "disk": 10}]}`))
+	case "GET /compute/v2.1/limits":
+		w.Write([]byte(`{"limits": {"absolute": {"maxTotalCores": 20, "totalCoresUsed": 4,
+			"maxTotalRAMSize": 51200, "totalRAMUsed": 8192, "maxTotalInstances": -1, "totalInstancesUsed": 2}}}`))
+	case "GET /image/v2/images":
+		w.Write([]byte(`{"images": [
+			{"id": "img-old", "created_at": "2024-01-01T00:00:00Z"},
+			{"id": "img-new", "created_at": "2024-02-01T00:00:00Z"}]}`))
+	case "GET /network/v2.0/networks":
+		w.Write([]byte(`{"networks": [{"id": "net-` + r.URL.Query().Get("name") + `"}], "networks_links": [{"href": "next"}]}`))
+	case "GET /network/v2.0/security-groups":
+		w.Write([]byte(`{"security_groups": [{"id": "sg-` + r.URL.Query().Get("name") + `"}]}`))
+	case "POST /network/v2.0/ports":
+		m.ports["port-1"] = true
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"port": {"id": "port-1", "fixed_ips": [{"ip_address": "10.0.0.5"}]}}`))
+	case "GET /network/v2.0/ports":
+		if r.URL.Query().Get("device_id") == "srv-1" && m.ports["port-1"] {
+			w.Write([]byte(`{"ports": [{"id": "port-1"}]}`))
+		} else {
+			w.Write([]byte(`{"ports": []}`))
+		}
+	case "DELETE /network/v2.0/ports/port-1":
+		delete(m.ports, "port-1")
+		w.WriteHeader(http.StatusNoContent)
+	case "POST /network/v2.0/floatingips":
+		m.fips["fip-1"] = true
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"floatingip": {"id": "fip-1", "floating_ip_address": "203.0.113.5"}}`))
+	case "GET /network/v2.0/floatingips":
+		if r.URL.Query().Get("port_id") == "port-1" && m.fips["fip-1"] {
+			w.Write([]byte(`{"floatingips": [{"id": "fip-1"}]}`))
+		} else {
+			w.Write([]byte(`{"floatingips": []}`))
+		}
+	case "DELETE /network/v2.0/floatingips/fip-1":
+		delete(m.fips, "fip-1")
+		w.WriteHeader(http.StatusNoContent)
+	case "POST /compute/v2.1/servers":
+		var req struct {
+			Server map[string]any `json:"server"`
+		}
+		json.Unmarshal(body, &req)
+		m.server = req.Server
+		m.servers["srv-1"] = true
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"server": {"id": "srv-1"}}`))
+	case "GET /compute/v2.1/servers/srv-1":
+		if !m.servers["srv-1"] {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"itemNotFound": {"message": "Instance srv-1 could not be found."}}`))
+			return
+		}
+		w.Write([]byte(`{"server": {"id": "srv-1", "status": "ACTIVE", "OS-EXT-AZ:availability_zone": "nova"}}`))
+	case "DELETE /compute/v2.1/servers/srv-1":
+		delete(m.servers, "srv-1")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"itemNotFound": {"message": "Unknown path"}}`))
+	}
+}
+
+func Test_openstack_driver_lifecycle(t *testing.T) {
+	cloud := newMockCloud()
+	defer cloud.Close()
+
+	d := &Driver{}
+	if err := d.Prepare([]byte(`{"auth_url":"` + cloud.URL + `/identity","username":"fish","password":"secret","project_name":"ci","network":"ci-net"}`)); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+
+	def := types.LabelDefinition{
+		Driver:  "openstack",
+		Options: `{"image":"ubuntu","security_groups":["ssh"],"floating_network":"public","userdata_format":"env"}`,
+		Resources: types.Resources{
+			Cpu:   2,
+			Ram:   4,
+			Disks: map[string]types.ResourcesDisk{"data": {Size: 10, Type: "ssd"}},
+		},
+	}
+	if err := d.ValidateDefinition(def); err != nil {
+		t.Fatalf("Unable to validate definition: %v", err)
+	}
+
+	// m1.medium fits: cores (20-4)/2 = 8, ram (51200-8192)/4096 = 10, instances are unlimited
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 8 {
+		t.Fatalf("Wrong capacity: %d", capacity)
+	}
+
+	res, err := d.Allocate(def, map[string]any{"key": "value"})
+	if err != nil {
+		t.Fatalf("Unable to allocate: %v", err)
+	}
+	if res.Identifier != "srv-1" || res.IpAddr != "203.0.113.5" {
+		t.Fatalf("Wrong allocated resource: %+v", res)
+	}
+	if err := drivers.ValidateInfo(d.InfoSchema(), res.DriverInfo); err != nil {
+		t.Fatalf("Wrong driver info %s: %v", res.DriverInfo, err)
+	}
+
+	if cloud.server["flavorRef"] != "2" || cloud.server["imageRef"] != "img-new" {
+		t.Fatalf("Wrong flavor or image in server request: %v", cloud.server)
+	}
+	if cloud.server["user_data"] == nil {
+		t.Fatalf("No userdata in server request: %v", cloud.server)
+	}
+	bdm, _ := cloud.server["block_device_mapping_v2"].([]any)
+	if len(bdm) != 2 {
+		t.Fatalf("Wrong block device mapping: %v", cloud.server["block_device_mapping_v2"])
+	}
+	if vol, _ := bdm[1].(map[string]any); vol["volume_type"] != "ssd" || vol["volume_size"] != float64(10) {
+		t.Fatalf("Wrong volume mapping: %v", bdm[1])
+	}
+
+	if status, err := d.Status(res); err != nil || status != drivers.StatusAllocated {
+		t.Fatalf("Wrong status: %q, %v", status, err)
+	}
+	if err := d.Deallocate(res); err != nil {
+		t.Fatalf("Unable to deallocate: %v", err)
+	}
+	if status, err := d.Status(res); err != nil || status != drivers.StatusNone {
+		t.Fatalf("Wrong status after deallocate: %q, %v", status, err)
+	}
+	if len(cloud.ports) != 0 || len(cloud.fips) != 0 {
+		t.Fatalf("Port or floating IP left after deallocate: %v, %v", cloud.ports, cloud.fips)
+	}
+}
+
+func Test_openstack_select_flavor(t *testing.T) {
+	flavors := []flavor{
+		{ID: "3", Name: "m1.large", VCPUs: 4, RAM: 8192},
+		{ID: "4", Name: "c1.large", VCPUs: 4, RAM: 4096},
+		{ID: "1", Name: "m1.small", VCPUs: 1, RAM: 2048},
+	}
+
+	if f, err := selectFlavor(flavors, "", types.Resources{Cpu: 2, Ram: 4}); err != nil || f.Name != "c1.large" {
+		t.Fatalf("Wrong flavor selected: %v, %v", f, err)
+	}
+	if f, err := selectFlavor(flavors, "3", types.Resources{Cpu: 1, Ram: 1}); err != nil || f.Name != "m1.large" {
+		t.Fatalf("Wrong requested flavor: %v, %v", f, err)
+	}
+	if _, err := selectFlavor(flavors, "", types.Resources{Cpu: 8, Ram: 4}); err == nil {
+		t.Fatalf("Flavor should not be found for 8 vCPUs")
+	}
+}
+
+func Test_openstack_config_validate(t *testing.T) {
+	if err := (&Config{AuthURL: "http://127.0.0.1/v3", Username: "fish", Password: "secret"}).Validate(); err == nil {
+		t.Fatalf("Password auth without project should not be valid")
+	}
+	if err := (&Config{AuthURL: "http://127.0.0.1/v3", ApplicationCredentialID: "id"}).Validate(); err == nil {
+		t.Fatalf("Application credential without secret should not be valid")
+	}
+	if err := (&Config{Username: "fish", Password: "secret", ProjectName: "ci"}).Validate(); err == nil {
+		t.Fatalf("Config without auth URL should not be valid")
+	}
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openstack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Options for label definition
+//
+// The network is taken from the Label Resources `network` or the driver config. The disks of the
+// Resources are created as volumes, disk `type` is the Cinder volume type and `clone` is the ID of
+// the volume snapshot to create the volume from.
+//
+// Example:
+//
+//	image: ubuntu-22.04
+//	flavor: m1.large               # Optional, the smallest flavor fitting the Resources otherwise
+//	security_groups: [ci-ssh]
+//	floating_network: public       # Optional, assigns the floating IP from the external network
+//	metadata:
+//	  somekey: somevalue
+type Options struct {
+	Image            string            `json:"image"`             // ID/Name of the Glance image to boot the server from
+	Flavor           string            `json:"flavor"`            // ID/Name of the flavor, selected by the Label Resources if empty
+	SecurityGroups   []string          `json:"security_groups"`   // ID/Name of the security groups of the server port
+	KeyName          string            `json:"key_name"`          // Name of the Nova keypair to inject into the server
+	AvailabilityZone string            `json:"availability_zone"` // Availability zone to run the server in
+	FloatingNetwork  string            `json:"floating_network"`  // ID/Name of the external network to get the floating IP from
+	Metadata         map[string]string `json:"metadata"`          // Metadata to set on the server
+
+	UserDataFormat string `json:"userdata_format"` // If not empty - will store the resource metadata to userdata in defined format
+	UserDataPrefix string `json:"userdata_prefix"` // Optional if need to add custom prefix to the metadata key during formatting
+}
+
+// Apply takes json and applies it to the options structure
+func (o *Options) Apply(options util.UnparsedJSON) error {
+	if err := json.Unmarshal([]byte(options), o); err != nil {
+		return log.Error("OPENSTACK: Unable to apply the driver options", err)
+	}
+
+	return o.Validate()
+}
+
+// Validate makes sure the options have the required defaults & that the required fields are set
+func (o *Options) Validate() error {
+	if o.Image == "" {
+		return fmt.Errorf("OPENSTACK: No image is specified")
+	}
+
+	if !util.Contains([]string{"", "json", "env", "ps1"}, o.UserDataFormat) {
+		return fmt.Errorf("OPENSTACK: Unsupported userdata format: %s", o.UserDataFormat)
+	}
+
+	return nil
+}
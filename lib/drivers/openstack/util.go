@@ -0,0 +1,265 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openstack
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// flavor is Nova flavor, ram is in MB and disk is in GB
+type flavor struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	VCPUs uint   `json:"vcpus"`
+	RAM   uint   `json:"ram"`
+	Disk  uint   `json:"disk"`
+}
+
+// server is the part of Nova server we need
+type server struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	AvailabilityZone string `json:"OS-EXT-AZ:availability_zone"`
+	Fault            struct {
+		Message string `json:"message"`
+	} `json:"fault"`
+}
+
+// port is the part of Neutron port we need
+type port struct {
+	ID       string `json:"id"`
+	FixedIPs []struct {
+		IPAddress string `json:"ip_address"`
+	} `json:"fixed_ips"`
+}
+
+// floatingIP is the part of Neutron floating IP we need
+type floatingIP struct {
+	ID                string `json:"id"`
+	FloatingIPAddress string `json:"floating_ip_address"`
+}
+
+// absoluteLimits is the part of Nova limits we need, -1 means unlimited
+type absoluteLimits struct {
+	MaxTotalCores      int64 `json:"maxTotalCores"`
+	TotalCoresUsed     int64 `json:"totalCoresUsed"`
+	MaxTotalRAMSize    int64 `json:"maxTotalRAMSize"`
+	TotalRAMUsed       int64 `json:"totalRAMUsed"`
+	MaxTotalInstances  int64 `json:"maxTotalInstances"`
+	TotalInstancesUsed int64 `json:"totalInstancesUsed"`
+}
+
+// getFlavors returns the available flavors, they are rarely changing so cached for a while
+func (d *Driver) getFlavors() ([]flavor, error) {
+	d.flavorsMutex.Lock()
+	defer d.flavorsMutex.Unlock()
+
+	if d.flavors != nil && time.Now().Before(d.flavorsNextUpdate) {
+		return d.flavors, nil
+	}
+
+	var resp struct {
+		Flavors []flavor `json:"flavors"`
+	}
+	if err := d.call("GET", serviceCompute, "/flavors/detail", nil, &resp); err != nil {
+		return nil, err
+	}
+	d.flavors = resp.Flavors
+	d.flavorsNextUpdate = time.Now().Add(30 * time.Minute)
+
+	return d.flavors, nil
+}
+
+// selectFlavor returns the requested flavor or the smallest one fitting the Resources
+func selectFlavor(flavors []flavor, name string, res types.Resources) (*flavor, error) {
+	if name != "" {
+		for i, f := range flavors {
+			if f.ID == name || f.Name == name {
+				return &flavors[i], nil
+			}
+		}
+		return nil, fmt.Errorf("OPENSTACK: Unable to find flavor %q", name)
+	}
+
+	var fit []flavor
+	for _, f := range flavors {
+		if f.VCPUs >= res.Cpu && f.RAM >= res.Ram*1024 {
+			fit = append(fit, f)
+		}
+	}
+	if len(fit) == 0 {
+		return nil, fmt.Errorf("OPENSTACK: Unable to find flavor with %d vCPUs and %dGB RAM", res.Cpu, res.Ram)
+	}
+	sort.Slice(fit, func(i, j int) bool {
+		if fit[i].VCPUs != fit[j].VCPUs {
+			return fit[i].VCPUs < fit[j].VCPUs
+		}
+		if fit[i].RAM != fit[j].RAM {
+			return fit[i].RAM < fit[j].RAM
+		}
+		if fit[i].Disk != fit[j].Disk {
+			return fit[i].Disk < fit[j].Disk
+		}
+		return fit[i].Name < fit[j].Name
+	})
+	return &fit[0], nil
+}
+
+// getImageID returns ID of the active image by ID or name, the latest one is used if there are
+// multiple images with the same name
+func (d *Driver) getImageID(name string) (string, error) {
+	if _, err := uuid.Parse(name); err == nil {
+		var img struct {
+			ID string `json:"id"`
+		}
+		if err = d.call("GET", serviceImage, "/images/"+name, nil, &img); err == nil {
+			return img.ID, nil
+		} else if err != errNotFound {
+			return "", err
+		}
+	}
+
+	var resp struct {
+		Images []struct {
+			ID        string    `json:"id"`
+			CreatedAt time.Time `json:"created_at"`
+		} `json:"images"`
+	}
+	if err := d.call("GET", serviceImage, "/images?status=active&name="+url.QueryEscape(name), nil, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Images) == 0 {
+		return "", fmt.Errorf("OPENSTACK: Unable to find image %q", name)
+	}
+	sort.Slice(resp.Images, func(i, j int) bool { return resp.Images[i].CreatedAt.After(resp.Images[j].CreatedAt) })
+	return resp.Images[0].ID, nil
+}
+
+// getNetworkingID returns ID of the Neutron object (network, security group) by ID or name
+func (d *Driver) getNetworkingID(kind, name string) (string, error) {
+	var resp map[string][]struct {
+		ID string `json:"id"`
+	}
+	query := "name=" + url.QueryEscape(name)
+	if _, err := uuid.Parse(name); err == nil {
+		query = "id=" + url.QueryEscape(name)
+	}
+	if err := d.call("GET", serviceNetwork, "/"+kind+"?"+query, nil, &resp); err != nil {
+		return "", err
+	}
+	// The list key differs from the path (security-groups -> security_groups) and the response
+	// could also contain the pagination links, so looking for the items with ID in any list
+	var found []string
+	for _, list := range resp {
+		for _, item := range list {
+			if item.ID != "" {
+				found = append(found, item.ID)
+			}
+		}
+	}
+	if len(found) != 1 {
+		return "", fmt.Errorf("OPENSTACK: Found %d %s with %q, expected one", len(found), kind, name)
+	}
+	return found[0], nil
+}
+
+// getServer returns the server by ID, nil if it's not exists
+func (d *Driver) getServer(id string) (*server, error) {
+	var resp struct {
+		Server server `json:"server"`
+	}
+	if err := d.call("GET", serviceCompute, "/servers/"+id, nil, &resp); err == errNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &resp.Server, nil
+}
+
+// waitServerActive waits for the server to become active
+func (d *Driver) waitServerActive(id string) (*server, error) {
+	deadline := time.Now().Add(time.Duration(d.cfg.ServerCreateWait))
+	for {
+		srv, err := d.getServer(id)
+		if err != nil {
+			log.Warnf("OPENSTACK: Error during getting server %s while waiting for it to become active: %v", id, err)
+		} else if srv == nil {
+			return nil, fmt.Errorf("OPENSTACK: Server %s disappeared", id)
+		} else if srv.Status == "ACTIVE" {
+			return srv, nil
+		} else if srv.Status == "ERROR" {
+			return nil, fmt.Errorf("OPENSTACK: Server %s failed to start: %s", id, srv.Fault.Message)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("OPENSTACK: Server %s is not active after %s", id, time.Duration(d.cfg.ServerCreateWait))
+		}
+		time.Sleep(serverPollInterval)
+	}
+}
+
+// getServerPorts returns the ports of the server
+func (d *Driver) getServerPorts(id string) ([]port, error) {
+	var resp struct {
+		Ports []port `json:"ports"`
+	}
+	if err := d.call("GET", serviceNetwork, "/ports?device_id="+url.QueryEscape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Ports, nil
+}
+
+// deletePort removes the floating IPs of the port and the port itself
+func (d *Driver) deletePort(id string) error {
+	var resp struct {
+		FloatingIPs []floatingIP `json:"floatingips"`
+	}
+	if err := d.call("GET", serviceNetwork, "/floatingips?port_id="+url.QueryEscape(id), nil, &resp); err != nil {
+		return err
+	}
+	for _, fip := range resp.FloatingIPs {
+		if err := d.call("DELETE", serviceNetwork, "/floatingips/"+fip.ID, nil, nil); err != nil && err != errNotFound {
+			return err
+		}
+	}
+	if err := d.call("DELETE", serviceNetwork, "/ports/"+id, nil, nil); err != nil && err != errNotFound {
+		return err
+	}
+	return nil
+}
+
+// serverInfo collects the driver info of the Resource described by InfoSchema
+func serverInfo(srv *server, f *flavor, imageID string, p *port, fip *floatingIP) util.UnparsedJSON {
+	info := map[string]any{
+		"flavor":   f.Name,
+		"image_id": imageID,
+		"port_id":  p.ID,
+	}
+	if srv.AvailabilityZone != "" {
+		info["availability_zone"] = srv.AvailabilityZone
+	}
+	if fip != nil {
+		info["floating_ip"] = fip.FloatingIPAddress
+	}
+	return drivers.InfoJSON(info)
+}
@@ -0,0 +1,463 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/build"
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// How long to wait for the plugin to respond on the handshake
+const helloTimeout = 10 * time.Second
+
+// Discover loads the executables in the directory as the driver plugins, the ones which failed
+// to load are returned as errors to not block the others
+func Discover(dir string) (out []drivers.ResourceDriverFactory, errs []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, []error{fmt.Errorf("Plugin: Unable to read plugins directory %q: %v", dir, err)}
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		f, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, errs
+}
+
+// Factory implements drivers.ResourceDriverFactory interface for the plugin executable, it keeps
+// the plugin process running and starts it again if it exits
+type Factory struct {
+	path  string
+	hello HelloReply
+
+	mutex  sync.Mutex
+	cmd    *exec.Cmd
+	client *rpc.Client
+	gen    uint64 // Increased on each plugin start to let the drivers recreate their instances
+}
+
+// Load starts the plugin executable and negotiates the protocol version
+func Load(path string) (*Factory, error) {
+	f := &Factory{path: path}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	hello, err := f.start()
+	if err != nil {
+		return nil, err
+	}
+	f.hello = hello
+	log.Infof("Plugin: Loaded driver %s %s from %q, protocol v%d, interfaces: %v", f.hello.Name, f.hello.Version, path, f.hello.Protocol, f.hello.Interfaces)
+	return f, nil
+}
+
+// Name of the plugin driver
+func (f *Factory) Name() string {
+	return f.hello.Name
+}
+
+// NewResourceDriver creates the proxy driver which implements the same optional interfaces as the
+// plugin driver, since Fish checks them by type
+func (f *Factory) NewResourceDriver() drivers.ResourceDriver {
+	d := &Driver{factory: f}
+	hc := slices.Contains(f.hello.Interfaces, InterfaceHealthCheck)
+	list := slices.Contains(f.hello.Interfaces, InterfaceList)
+	switch {
+	case hc && list:
+		return &driverHealthCheckList{d}
+	case hc:
+		return &driverHealthCheck{d}
+	case list:
+		return &driverList{d}
+	}
+	return d
+}
+
+// connect returns the client of the running plugin, starting it if needed
+func (f *Factory) connect() (*rpc.Client, uint64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.client == nil {
+		hello, err := f.start()
+		if err != nil {
+			return nil, 0, err
+		}
+		// The handshake info is not changing on restart, so the executable should not be replaced
+		if hello.Name != f.hello.Name {
+			f.stop()
+			return nil, 0, fmt.Errorf("Plugin: Driver %s was replaced by %s in %q", f.hello.Name, hello.Name, f.path)
+		}
+		log.Warnf("Plugin: Driver %s process was restarted", f.hello.Name)
+	}
+	return f.client, f.gen, nil
+}
+
+// start runs the plugin process and makes the handshake, should be called under lock
+func (f *Factory) start() (hello HelloReply, err error) {
+	cmd := exec.Command(f.path)
+	cmd.Env = append(os.Environ(), EnvMagicCookie+"="+MagicCookie)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return hello, fmt.Errorf("Plugin: Unable to create stdin of %q: %v", f.path, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return hello, fmt.Errorf("Plugin: Unable to create stdout of %q: %v", f.path, err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return hello, fmt.Errorf("Plugin: Unable to create stderr of %q: %v", f.path, err)
+	}
+	if err = cmd.Start(); err != nil {
+		return hello, fmt.Errorf("Plugin: Unable to start %q: %v", f.path, err)
+	}
+	client := jsonrpc.NewClient(&stdioConn{Reader: stdout, Writer: stdin, closer: stdin})
+
+	// Plugin output is not a part of the protocol, so just passing it to the log
+	name := filepath.Base(f.path)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Infof("Plugin %s: %s", name, scanner.Text())
+		}
+	}()
+	go func() {
+		err := cmd.Wait()
+		log.Debugf("Plugin: Process of %q exited: %v", f.path, err)
+		client.Close()
+		f.mutex.Lock()
+		if f.cmd == cmd {
+			f.cmd, f.client = nil, nil
+		}
+		f.mutex.Unlock()
+	}()
+
+	call := client.Go("Plugin.Hello", HelloArgs{Protocols: []int{ProtocolVersion}, FishVersion: build.Version}, &hello, nil)
+	select {
+	case <-call.Done:
+		err = call.Error
+	case <-time.After(helloTimeout):
+		err = fmt.Errorf("no response in %s", helloTimeout)
+	}
+	if err == nil && hello.Protocol != ProtocolVersion {
+		err = fmt.Errorf("unsupported protocol version %d", hello.Protocol)
+	}
+	if err == nil && hello.Name == "" {
+		err = fmt.Errorf("empty driver name")
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		return hello, fmt.Errorf("Plugin: Handshake with %q failed: %v", f.path, err)
+	}
+
+	f.cmd, f.client = cmd, client
+	f.gen++
+	return hello, nil
+}
+
+// stop kills the plugin process, should be called under lock
+func (f *Factory) stop() {
+	if f.cmd != nil {
+		f.cmd.Process.Kill()
+	}
+	f.cmd, f.client = nil, nil
+}
+
+// Driver implements drivers.ResourceDriver interface by calling the plugin
+type Driver struct {
+	factory *Factory
+
+	mutex    sync.Mutex
+	id       uint64 // Instance in the plugin process
+	gen      uint64 // Plugin process the instance belongs to
+	config   []byte
+	prepared bool
+}
+
+// instance returns the plugin client and the driver instance in it, the instance is created again
+// with the same config if the plugin was restarted
+func (d *Driver) instance() (*rpc.Client, uint64, error) {
+	client, gen, err := d.factory.connect()
+	if err != nil {
+		return nil, 0, err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.id != 0 && d.gen == gen {
+		return client, d.id, nil
+	}
+	var id uint64
+	if err := client.Call("Plugin.New", Empty{}, &id); err != nil {
+		return nil, 0, fmt.Errorf("Plugin: Unable to create driver %s instance: %v", d.Name(), err)
+	}
+	if d.prepared {
+		if err := client.Call("Plugin.Prepare", PrepareArgs{Instance: id, Config: d.config}, &Empty{}); err != nil {
+			return nil, 0, fmt.Errorf("Plugin: Unable to prepare driver %s after restart: %v", d.Name(), err)
+		}
+	}
+	d.id, d.gen = id, gen
+	return client, id, nil
+}
+
+func (d *Driver) call(method string, args func(id uint64) any, reply any) error {
+	client, id, err := d.instance()
+	if err != nil {
+		return err
+	}
+	return client.Call("Plugin."+method, args(id), reply)
+}
+
+// Name returns name of the plugin driver
+func (d *Driver) Name() string {
+	return d.factory.hello.Name
+}
+
+// IsRemote returns what the plugin reported on handshake
+func (d *Driver) IsRemote() bool {
+	return d.factory.hello.Remote
+}
+
+// Prepare initializes the driver in the plugin, the config is kept to prepare it again on restart
+func (d *Driver) Prepare(config []byte) error {
+	d.mutex.Lock()
+	d.config, d.prepared = config, false
+	d.mutex.Unlock()
+
+	err := d.call("Prepare", func(id uint64) any { return PrepareArgs{Instance: id, Config: config} }, &Empty{})
+	if err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	d.prepared = true
+	d.mutex.Unlock()
+	return nil
+}
+
+// Capabilities returns the plugin driver capabilities, empty if plugin is not available
+func (d *Driver) Capabilities() (caps types.DriverCapabilities) {
+	if err := d.call("Capabilities", func(id uint64) any { return InstanceArgs{Instance: id} }, &caps); err != nil {
+		log.Errorf("Plugin: Driver %s is unable to report capabilities: %v", d.Name(), err)
+	}
+	return caps
+}
+
+// ValidateDefinition checks the definition by the plugin driver
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	return d.call("ValidateDefinition", func(id uint64) any { return DefinitionArgs{Instance: id, Definition: def} }, &Empty{})
+}
+
+// AvailableCapacity returns -1 if the plugin is not available
+func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDefinition) (capacity int64) {
+	err := d.call("AvailableCapacity", func(id uint64) any {
+		return CapacityArgs{Instance: id, NodeUsage: nodeUsage, Definition: req}
+	}, &capacity)
+	if err != nil {
+		log.Errorf("Plugin: Driver %s is unable to report capacity: %v", d.Name(), err)
+		return -1
+	}
+	return capacity
+}
+
+// Allocate the Resource by the plugin driver
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	var res types.Resource
+	err := d.call("Allocate", func(id uint64) any {
+		return AllocateArgs{Instance: id, Definition: def, Metadata: metadata}
+	}, &res)
+	if err != nil {
+		// The driver could return the partially allocated Resource to clean it up
+		if res.Identifier != "" {
+			return &res, err
+		}
+		return nil, err
+	}
+	return &res, nil
+}
+
+// InfoSchema returns empty schema if the plugin is not available
+func (d *Driver) InfoSchema() (schema types.DriverInfoSchema) {
+	if err := d.call("InfoSchema", func(id uint64) any { return InstanceArgs{Instance: id} }, &schema); err != nil {
+		log.Errorf("Plugin: Driver %s is unable to report info schema: %v", d.Name(), err)
+	}
+	return schema
+}
+
+// Status of the Resource from the plugin driver
+func (d *Driver) Status(res *types.Resource) (status string, err error) {
+	err = d.call("Status", func(id uint64) any { return ResourceArgs{Instance: id, Resource: *res} }, &status)
+	return status, err
+}
+
+// GetTask returns the task proxy if the plugin driver is able to execute it
+func (d *Driver) GetTask(name, options string) drivers.ResourceDriverTask {
+	var ok bool
+	err := d.call("HasTask", func(id uint64) any { return TaskArgs{Instance: id, Name: name, Options: options} }, &ok)
+	if err != nil {
+		log.Errorf("Plugin: Driver %s is unable to get task %q: %v", d.Name(), name, err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return &Task{driver: d, name: name, options: options}
+}
+
+// Deallocate the Resource by the plugin driver
+func (d *Driver) Deallocate(res *types.Resource) error {
+	return d.call("Deallocate", func(id uint64) any { return ResourceArgs{Instance: id, Resource: *res} }, &Empty{})
+}
+
+// SelfCheck returns error if the plugin is not available or the driver check failed
+func (d *Driver) SelfCheck() error {
+	return d.call("SelfCheck", func(id uint64) any { return InstanceArgs{Instance: id} }, &Empty{})
+}
+
+// HourlyCost asks the plugin driver to price the definition
+func (d *Driver) HourlyCost(def types.LabelDefinition) (cost float64, err error) {
+	if !slices.Contains(d.factory.hello.Interfaces, InterfacePricing) {
+		return 0, fmt.Errorf("Plugin: Driver %s is not able to price the Resources", d.Name())
+	}
+	err = d.call("HourlyCost", func(id uint64) any { return DefinitionArgs{Instance: id, Definition: def} }, &cost)
+	return cost, err
+}
+
+// Forecast asks the plugin driver for the state of its pools, empty if it's not able to forecast
+func (d *Driver) Forecast() (out types.DriverForecast) {
+	out.Pools = []types.DriverPoolForecast{}
+	if !slices.Contains(d.factory.hello.Interfaces, InterfaceForecast) {
+		return out
+	}
+	if err := d.call("Forecast", func(id uint64) any { return InstanceArgs{Instance: id} }, &out); err != nil {
+		log.Errorf("Plugin: Driver %s is unable to forecast: %v", d.Name(), err)
+	}
+	return out
+}
+
+// CapacityForecast asks the plugin driver when the capacity for the definition will appear, zero
+// time is returned if it's not able to forecast
+func (d *Driver) CapacityForecast(def types.LabelDefinition) (at time.Time) {
+	if !slices.Contains(d.factory.hello.Interfaces, InterfaceForecast) {
+		return at
+	}
+	if err := d.call("CapacityForecast", func(id uint64) any { return DefinitionArgs{Instance: id, Definition: def} }, &at); err != nil {
+		log.Errorf("Plugin: Driver %s is unable to forecast the capacity: %v", d.Name(), err)
+		return time.Time{}
+	}
+	return at
+}
+
+func (d *Driver) healthCheck(res *types.Resource, script string, timeout time.Duration) error {
+	return d.call("HealthCheck", func(id uint64) any {
+		return HealthCheckArgs{Instance: id, Resource: *res, Script: script, Timeout: timeout}
+	}, &Empty{})
+}
+
+func (d *Driver) list() (list []types.Resource, err error) {
+	err = d.call("List", func(id uint64) any { return InstanceArgs{Instance: id} }, &list)
+	return list, err
+}
+
+// driverHealthCheck implements drivers.ResourceDriverHealthCheck
+type driverHealthCheck struct{ *Driver }
+
+func (d *driverHealthCheck) HealthCheck(res *types.Resource, script string, timeout time.Duration) error {
+	return d.healthCheck(res, script, timeout)
+}
+
+// driverList implements drivers.ResourceDriverList
+type driverList struct{ *Driver }
+
+func (d *driverList) List() ([]types.Resource, error) {
+	return d.list()
+}
+
+// driverHealthCheckList implements both drivers.ResourceDriverHealthCheck and ResourceDriverList
+type driverHealthCheckList struct{ *Driver }
+
+func (d *driverHealthCheckList) HealthCheck(res *types.Resource, script string, timeout time.Duration) error {
+	return d.healthCheck(res, script, timeout)
+}
+
+func (d *driverHealthCheckList) List() ([]types.Resource, error) {
+	return d.list()
+}
+
+// Task implements drivers.ResourceDriverTask by executing it in the plugin, the task output is
+// sent after the execution completes
+type Task struct {
+	drivers.TaskOutput
+
+	driver  *Driver
+	name    string
+	options string
+
+	task *types.ApplicationTask
+	def  *types.LabelDefinition
+	res  *types.Resource
+}
+
+// Name of the task
+func (t *Task) Name() string {
+	return t.name
+}
+
+// Clone the task structure
+func (t *Task) Clone() drivers.ResourceDriverTask {
+	n := *t
+	return &n
+}
+
+// SetInfo keeps the task information to send it to the plugin
+func (t *Task) SetInfo(task *types.ApplicationTask, def *types.LabelDefinition, res *types.Resource) {
+	t.task, t.def, t.res = task, def, res
+}
+
+// Execute runs the task in the plugin driver
+func (t *Task) Execute() ([]byte, error) {
+	if t.task == nil || t.def == nil || t.res == nil {
+		return []byte(`{"error":"internal: invalid task info"}`), log.Errorf("Plugin: Invalid task %s info", t.name)
+	}
+	var reply TaskExecuteReply
+	err := t.driver.call("TaskExecute", func(id uint64) any {
+		return TaskExecuteArgs{Instance: id, Name: t.name, Options: t.options, Task: *t.task, Definition: *t.def, Resource: *t.res}
+	}, &reply)
+	for _, line := range reply.Output {
+		t.Output("%s", line)
+	}
+	return reply.Result, err
+}
@@ -0,0 +1,220 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// The test binary serves the test driver when it's started as plugin
+func TestMain(m *testing.M) {
+	if os.Getenv(EnvMagicCookie) == MagicCookie {
+		if err := Serve(&testFactory{}, "v1.2.3"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type testFactory struct{}
+
+func (*testFactory) Name() string                              { return "plugtest" }
+func (*testFactory) NewResourceDriver() drivers.ResourceDriver { return &testDriver{} }
+
+type testDriver struct {
+	prefix string
+}
+
+func (*testDriver) Name() string   { return "plugtest" }
+func (*testDriver) IsRemote() bool { return true }
+func (d *testDriver) Prepare(config []byte) error {
+	var cfg struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return err
+	}
+	if cfg.Prefix == "" {
+		return fmt.Errorf("prefix is required")
+	}
+	d.prefix = cfg.Prefix
+	return nil
+}
+func (*testDriver) Capabilities() types.DriverCapabilities { return types.DriverCapabilities{} }
+func (*testDriver) ValidateDefinition(def types.LabelDefinition) error {
+	if def.Driver != "plugtest" {
+		return fmt.Errorf("wrong driver %q", def.Driver)
+	}
+	return nil
+}
+func (*testDriver) AvailableCapacity(_ types.Resources, _ types.LabelDefinition) int64 { return 3 }
+func (d *testDriver) Allocate(_ types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	return &types.Resource{Identifier: fmt.Sprintf("%s-%v", d.prefix, metadata["n"])}, nil
+}
+func (*testDriver) InfoSchema() types.DriverInfoSchema { return types.DriverInfoSchema{} }
+func (*testDriver) Status(res *types.Resource) (string, error) {
+	if strings.HasPrefix(res.Identifier, "gone") {
+		return drivers.StatusNone, nil
+	}
+	return drivers.StatusAllocated, nil
+}
+func (*testDriver) GetTask(name, _ string) drivers.ResourceDriverTask {
+	if name != drivers.TaskReboot {
+		return nil
+	}
+	return &testTask{}
+}
+func (*testDriver) Deallocate(_ *types.Resource) error { return nil }
+func (d *testDriver) List() ([]types.Resource, error) {
+	return []types.Resource{drivers.ListResource(d.prefix+"-1", "b6f7ae43-8a8e-4c7c-9ad1-0c3b33b8a5a6", "")}, nil
+}
+func (*testDriver) Forecast() types.DriverForecast {
+	return types.DriverForecast{Pools: []types.DriverPoolForecast{{Name: "pool"}}}
+}
+func (*testDriver) CapacityForecast(_ types.LabelDefinition) time.Time {
+	return time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+type testTask struct {
+	drivers.TaskOutput
+	res *types.Resource
+}
+
+func (*testTask) Name() string                        { return drivers.TaskReboot }
+func (t *testTask) Clone() drivers.ResourceDriverTask { n := *t; return &n }
+func (t *testTask) SetInfo(_ *types.ApplicationTask, _ *types.LabelDefinition, res *types.Resource) {
+	t.res = res
+}
+func (t *testTask) Execute() ([]byte, error) {
+	t.Output("rebooting %s", t.res.Identifier)
+	return []byte(`{"status":"running"}`), nil
+}
+
+func Test_plugin_driver_calls(t *testing.T) {
+	f, err := Load(os.Args[0])
+	if err != nil {
+		t.Fatalf("Unable to load plugin: %v", err)
+	}
+	if f.Name() != "plugtest" || f.hello.Version != "v1.2.3" {
+		t.Fatalf("Wrong plugin info: %+v", f.hello)
+	}
+
+	drv := f.NewResourceDriver()
+	if !drv.IsRemote() {
+		t.Fatalf("Plugin driver should be remote")
+	}
+	if _, ok := drv.(drivers.ResourceDriverHealthCheck); ok {
+		t.Fatalf("Plugin driver should not implement health check")
+	}
+	if err := drv.Prepare([]byte(`{}`)); err == nil || !strings.Contains(err.Error(), "prefix is required") {
+		t.Fatalf("Expected prepare error, got: %v", err)
+	}
+	if err := drv.Prepare([]byte(`{"prefix":"one"}`)); err != nil {
+		t.Fatalf("Unable to prepare: %v", err)
+	}
+	if err := drv.ValidateDefinition(types.LabelDefinition{Driver: "other"}); err == nil {
+		t.Fatalf("Expected validation error")
+	}
+	if c := drv.AvailableCapacity(types.Resources{}, types.LabelDefinition{}); c != 3 {
+		t.Fatalf("Wrong capacity: %d", c)
+	}
+	res, err := drv.Allocate(types.LabelDefinition{Driver: "plugtest"}, map[string]any{"n": 5})
+	if err != nil || res.Identifier != "one-5" {
+		t.Fatalf("Wrong allocate result: %v, %v", res, err)
+	}
+	if status, err := drv.Status(res); err != nil || status != drivers.StatusAllocated {
+		t.Fatalf("Wrong status: %q, %v", status, err)
+	}
+
+	l, ok := drv.(drivers.ResourceDriverList)
+	if !ok {
+		t.Fatalf("Plugin driver should implement list")
+	}
+	if list, err := l.List(); err != nil || len(list) != 1 || list[0].Identifier != "one-1" || list[0].NodeUID.String() != "b6f7ae43-8a8e-4c7c-9ad1-0c3b33b8a5a6" {
+		t.Fatalf("Wrong list: %v, %v", list, err)
+	}
+
+	fc, ok := drv.(drivers.ResourceDriverForecast)
+	if !ok {
+		t.Fatalf("Plugin driver should implement forecast")
+	}
+	if out := fc.Forecast(); len(out.Pools) != 1 || out.Pools[0].Name != "pool" {
+		t.Fatalf("Wrong forecast: %+v", out)
+	}
+	if at := fc.CapacityForecast(types.LabelDefinition{}); !at.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("Wrong capacity forecast: %v", at)
+	}
+
+	if drv.GetTask(drivers.TaskSnapshot, "") != nil {
+		t.Fatalf("Plugin driver should not have snapshot task")
+	}
+	task := drv.GetTask(drivers.TaskReboot, "").Clone()
+	var lines []string
+	task.(drivers.ResourceDriverTaskOutput).SetOutput(func(line string) { lines = append(lines, line) })
+	task.SetInfo(&types.ApplicationTask{}, &types.LabelDefinition{}, res)
+	out, err := task.Execute()
+	if err != nil || string(out) != `{"status":"running"}` {
+		t.Fatalf("Wrong task result: %s, %v", out, err)
+	}
+	if len(lines) != 1 || lines[0] != "rebooting one-5" {
+		t.Fatalf("Wrong task output: %v", lines)
+	}
+}
+
+func Test_plugin_restart_prepares_instance(t *testing.T) {
+	f, err := Load(os.Args[0])
+	if err != nil {
+		t.Fatalf("Unable to load plugin: %v", err)
+	}
+	drv := f.NewResourceDriver()
+	if err := drv.Prepare([]byte(`{"prefix":"two"}`)); err != nil {
+		t.Fatalf("Unable to prepare: %v", err)
+	}
+
+	// Killing the plugin process and waiting for the factory to notice it
+	f.mutex.Lock()
+	cmd := f.cmd
+	f.mutex.Unlock()
+	cmd.Process.Kill()
+	for {
+		f.mutex.Lock()
+		gone := f.cmd == nil
+		f.mutex.Unlock()
+		if gone {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	res, err := drv.Allocate(types.LabelDefinition{Driver: "plugtest"}, map[string]any{"n": 1})
+	if err != nil || res.Identifier != "two-1" {
+		t.Fatalf("Wrong allocate result after restart: %v, %v", res, err)
+	}
+}
+
+func Test_plugin_refuses_without_fish(t *testing.T) {
+	t.Setenv(EnvMagicCookie, "")
+	if err := Serve(&testFactory{}, "v0"); err == nil {
+		t.Fatalf("Plugin should refuse to serve without handshake cookie")
+	}
+}
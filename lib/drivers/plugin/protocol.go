@@ -0,0 +1,127 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package plugin allows to run the resource drivers built and distributed separately from Fish.
+// The plugin is an executable which serves the driver through JSON-RPC on its stdin/stdout, Fish
+// starts it from the plugins directory and negotiates the protocol version and the capabilities.
+package plugin
+
+import (
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Handshake environment variables, the plugin refuses to serve if it's not started by Fish
+const (
+	EnvMagicCookie = "AQUARIUM_FISH_PLUGIN"
+	MagicCookie    = "d3a1c0a7-fish-driver-plugin"
+)
+
+// ProtocolVersion is the latest version of the plugin protocol, it's increased on each breaking
+// change of the calls below and Fish and the plugin agree on the highest one they both support
+const ProtocolVersion = 1
+
+// Optional interfaces of the driver, reported by the plugin to let Fish know what it could call
+const (
+	InterfaceHealthCheck = "health_check"
+	InterfaceSelfCheck   = "self_check"
+	InterfaceList        = "list"
+	InterfacePricing     = "pricing"
+	InterfaceForecast    = "forecast"
+)
+
+// HelloArgs is sent by Fish to start the session
+type HelloArgs struct {
+	Protocols   []int  // Versions of the protocol Fish supports
+	FishVersion string // To let the plugin know which Fish it's working with
+}
+
+// HelloReply describes the plugin driver
+type HelloReply struct {
+	Protocol   int      // Chosen version of the protocol
+	Name       string   // Name of the driver to use in the config and the Label definitions
+	Version    string   // Version of the plugin to report in the log
+	Remote     bool     // Same as ResourceDriver.IsRemote()
+	Interfaces []string // Optional interfaces implemented by the driver
+}
+
+// Empty is used as the call args or reply when nothing needs to be transferred
+type Empty struct{}
+
+// InstanceArgs identifies the driver instance in the plugin
+type InstanceArgs struct {
+	Instance uint64
+}
+
+// PrepareArgs passes the driver config
+type PrepareArgs struct {
+	Instance uint64
+	Config   []byte
+}
+
+// DefinitionArgs passes the Label definition
+type DefinitionArgs struct {
+	Instance   uint64
+	Definition types.LabelDefinition
+}
+
+// CapacityArgs passes the AvailableCapacity request
+type CapacityArgs struct {
+	Instance   uint64
+	NodeUsage  types.Resources
+	Definition types.LabelDefinition
+}
+
+// AllocateArgs passes the Allocate request
+type AllocateArgs struct {
+	Instance   uint64
+	Definition types.LabelDefinition
+	Metadata   map[string]any
+}
+
+// ResourceArgs passes the Resource to operate
+type ResourceArgs struct {
+	Instance uint64
+	Resource types.Resource
+}
+
+// HealthCheckArgs passes the HealthCheck request
+type HealthCheckArgs struct {
+	Instance uint64
+	Resource types.Resource
+	Script   string
+	Timeout  time.Duration
+}
+
+// TaskArgs identifies the driver task
+type TaskArgs struct {
+	Instance uint64
+	Name     string
+	Options  string
+}
+
+// TaskExecuteArgs passes the task with the information it needs to execute
+type TaskExecuteArgs struct {
+	Instance   uint64
+	Name       string
+	Options    string
+	Task       types.ApplicationTask
+	Definition types.LabelDefinition
+	Resource   types.Resource
+}
+
+// TaskExecuteReply returns the task result and the output lines it produced during execution
+type TaskExecuteReply struct {
+	Result []byte
+	Output []string
+}
@@ -0,0 +1,320 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Serve runs the driver plugin, it's called from the main() of the plugin executable and returns
+// when Fish closes the connection. The stdout is used by the protocol, so the driver output is
+// redirected to stderr which Fish writes to its log.
+func Serve(factory drivers.ResourceDriverFactory, version string) error {
+	if os.Getenv(EnvMagicCookie) != MagicCookie {
+		return fmt.Errorf("Plugin: This executable is an Aquarium Fish driver plugin and should be started by Fish")
+	}
+	stdin, stdout := os.Stdin, os.Stdout
+	os.Stdout = os.Stderr
+
+	return ServeConn(factory, version, &stdioConn{Reader: stdin, Writer: stdout, closer: stdin})
+}
+
+// ServeConn serves the driver plugin on the provided connection
+func ServeConn(factory drivers.ResourceDriverFactory, version string, conn io.ReadWriteCloser) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Plugin", &Server{factory: factory, version: version}); err != nil {
+		return fmt.Errorf("Plugin: Unable to register the plugin server: %v", err)
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// Server executes the Fish calls on the driver instances
+type Server struct {
+	factory drivers.ResourceDriverFactory
+	version string
+
+	mutex     sync.Mutex
+	lastID    uint64
+	instances map[uint64]drivers.ResourceDriver
+}
+
+func (s *Server) get(id uint64) (drivers.ResourceDriver, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	drv, ok := s.instances[id]
+	if !ok {
+		return nil, fmt.Errorf("Plugin: Driver instance %d not found", id)
+	}
+	return drv, nil
+}
+
+// Hello negotiates the protocol version and reports the driver info
+func (s *Server) Hello(args HelloArgs, reply *HelloReply) error {
+	if !slices.Contains(args.Protocols, ProtocolVersion) {
+		return fmt.Errorf("Plugin: Protocol version %d is not supported by Fish %s: %v", ProtocolVersion, args.FishVersion, args.Protocols)
+	}
+	drv := s.factory.NewResourceDriver()
+	reply.Protocol = ProtocolVersion
+	reply.Name = s.factory.Name()
+	reply.Version = s.version
+	reply.Remote = drv.IsRemote()
+	reply.Interfaces = Interfaces(drv)
+	return nil
+}
+
+// New creates the driver instance, the same plugin could be used by a number of the driver configs
+func (s *Server) New(_ Empty, id *uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.instances == nil {
+		s.instances = make(map[uint64]drivers.ResourceDriver)
+	}
+	s.lastID++
+	s.instances[s.lastID] = s.factory.NewResourceDriver()
+	*id = s.lastID
+	return nil
+}
+
+// Prepare calls ResourceDriver.Prepare
+func (s *Server) Prepare(args PrepareArgs, _ *Empty) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	return drv.Prepare(args.Config)
+}
+
+// Capabilities calls ResourceDriver.Capabilities
+func (s *Server) Capabilities(args InstanceArgs, reply *types.DriverCapabilities) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	*reply = drv.Capabilities()
+	return nil
+}
+
+// ValidateDefinition calls ResourceDriver.ValidateDefinition
+func (s *Server) ValidateDefinition(args DefinitionArgs, _ *Empty) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	return drv.ValidateDefinition(args.Definition)
+}
+
+// AvailableCapacity calls ResourceDriver.AvailableCapacity
+func (s *Server) AvailableCapacity(args CapacityArgs, reply *int64) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	*reply = drv.AvailableCapacity(args.NodeUsage, args.Definition)
+	return nil
+}
+
+// Allocate calls ResourceDriver.Allocate
+func (s *Server) Allocate(args AllocateArgs, reply *types.Resource) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	res, err := drv.Allocate(args.Definition, args.Metadata)
+	if res != nil {
+		*reply = *res
+	}
+	return err
+}
+
+// InfoSchema calls ResourceDriver.InfoSchema
+func (s *Server) InfoSchema(args InstanceArgs, reply *types.DriverInfoSchema) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	*reply = drv.InfoSchema()
+	return nil
+}
+
+// Status calls ResourceDriver.Status
+func (s *Server) Status(args ResourceArgs, reply *string) (err error) {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	*reply, err = drv.Status(&args.Resource)
+	return err
+}
+
+// Deallocate calls ResourceDriver.Deallocate
+func (s *Server) Deallocate(args ResourceArgs, _ *Empty) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	return drv.Deallocate(&args.Resource)
+}
+
+// HasTask checks the driver is able to execute the task
+func (s *Server) HasTask(args TaskArgs, reply *bool) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	*reply = drv.GetTask(args.Name, args.Options) != nil
+	return nil
+}
+
+// TaskExecute gets the driver task and executes it, the output lines are collected to the reply
+func (s *Server) TaskExecute(args TaskExecuteArgs, reply *TaskExecuteReply) (err error) {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	t := drv.GetTask(args.Name, args.Options)
+	if t == nil {
+		return fmt.Errorf("Plugin: Driver %s is not able to execute task %q", s.factory.Name(), args.Name)
+	}
+	t.SetInfo(&args.Task, &args.Definition, &args.Resource)
+	if to, ok := t.(drivers.ResourceDriverTaskOutput); ok {
+		to.SetOutput(func(line string) {
+			reply.Output = append(reply.Output, line)
+		})
+	}
+	reply.Result, err = t.Execute()
+	return err
+}
+
+// HealthCheck calls ResourceDriverHealthCheck.HealthCheck
+func (s *Server) HealthCheck(args HealthCheckArgs, _ *Empty) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	hc, ok := drv.(drivers.ResourceDriverHealthCheck)
+	if !ok {
+		return fmt.Errorf("Plugin: Driver %s is not able to run the health check script", s.factory.Name())
+	}
+	return hc.HealthCheck(&args.Resource, args.Script, args.Timeout)
+}
+
+// SelfCheck calls ResourceDriverSelfCheck.SelfCheck
+func (s *Server) SelfCheck(args InstanceArgs, _ *Empty) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	if sc, ok := drv.(drivers.ResourceDriverSelfCheck); ok {
+		return sc.SelfCheck()
+	}
+	return nil
+}
+
+// List calls ResourceDriverList.List
+func (s *Server) List(args InstanceArgs, reply *[]types.Resource) (err error) {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	l, ok := drv.(drivers.ResourceDriverList)
+	if !ok {
+		return fmt.Errorf("Plugin: Driver %s is not able to list the resources", s.factory.Name())
+	}
+	*reply, err = l.List()
+	return err
+}
+
+// HourlyCost calls ResourceDriverPricing.HourlyCost
+func (s *Server) HourlyCost(args DefinitionArgs, reply *float64) (err error) {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	p, ok := drv.(drivers.ResourceDriverPricing)
+	if !ok {
+		return fmt.Errorf("Plugin: Driver %s is not able to price the Resources", s.factory.Name())
+	}
+	*reply, err = p.HourlyCost(args.Definition)
+	return err
+}
+
+// Forecast calls ResourceDriverForecast.Forecast
+func (s *Server) Forecast(args InstanceArgs, reply *types.DriverForecast) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	f, ok := drv.(drivers.ResourceDriverForecast)
+	if !ok {
+		return fmt.Errorf("Plugin: Driver %s is not able to forecast the capacity", s.factory.Name())
+	}
+	*reply = f.Forecast()
+	return nil
+}
+
+// CapacityForecast calls ResourceDriverForecast.CapacityForecast
+func (s *Server) CapacityForecast(args DefinitionArgs, reply *time.Time) error {
+	drv, err := s.get(args.Instance)
+	if err != nil {
+		return err
+	}
+	f, ok := drv.(drivers.ResourceDriverForecast)
+	if !ok {
+		return fmt.Errorf("Plugin: Driver %s is not able to forecast the capacity", s.factory.Name())
+	}
+	*reply = f.CapacityForecast(args.Definition)
+	return nil
+}
+
+// Interfaces returns the optional interfaces the driver implements
+func Interfaces(drv drivers.ResourceDriver) []string {
+	out := []string{}
+	if _, ok := drv.(drivers.ResourceDriverHealthCheck); ok {
+		out = append(out, InterfaceHealthCheck)
+	}
+	if _, ok := drv.(drivers.ResourceDriverSelfCheck); ok {
+		out = append(out, InterfaceSelfCheck)
+	}
+	if _, ok := drv.(drivers.ResourceDriverList); ok {
+		out = append(out, InterfaceList)
+	}
+	if _, ok := drv.(drivers.ResourceDriverPricing); ok {
+		out = append(out, InterfacePricing)
+	}
+	if _, ok := drv.(drivers.ResourceDriverForecast); ok {
+		out = append(out, InterfaceForecast)
+	}
+	return out
+}
+
+// stdioConn joins the process stdin and stdout into connection
+type stdioConn struct {
+	io.Reader
+	io.Writer
+	closer io.Closer
+}
+
+func (c *stdioConn) Close() error {
+	return c.closer.Close()
+}
@@ -0,0 +1,53 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// StaticPricing is the price table for the drivers which have no price source, like the on-prem
+// ones. The price of the Resource is the base price plus the price of its resources.
+//
+// Example:
+//
+//	pricing:
+//	  hour: 0.05  # Base price of any Resource
+//	  cpu: 0.02   # Price of one vCPU
+//	  ram: 0.005  # Price of one GiB of RAM
+//	  disk: 0.0001
+type StaticPricing struct {
+	Hour float64 `json:"hour"` // Base price of the Resource per hour
+	Cpu  float64 `json:"cpu"`  // Price of one vCPU per hour
+	Ram  float64 `json:"ram"`  // Price of one GiB of RAM per hour
+	Disk float64 `json:"disk"` // Price of one GiB of the disks per hour
+}
+
+// Validate makes sure the prices are not negative
+func (p *StaticPricing) Validate() error {
+	if p.Hour < 0 || p.Cpu < 0 || p.Ram < 0 || p.Disk < 0 {
+		return fmt.Errorf("Static pricing can't be negative: %+v", *p)
+	}
+	return nil
+}
+
+// HourlyCost returns the price of the Resource with the definition
+func (p *StaticPricing) HourlyCost(def types.LabelDefinition) float64 {
+	cost := p.Hour + p.Cpu*float64(def.Resources.Cpu) + p.Ram*float64(def.Resources.Ram)
+	for _, disk := range def.Resources.Disks {
+		cost += p.Disk * float64(disk.Size)
+	}
+	return cost
+}
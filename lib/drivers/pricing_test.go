@@ -0,0 +1,45 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package drivers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func Test_static_pricing(t *testing.T) {
+	pricing := StaticPricing{Hour: 0.1, Cpu: 0.02, Ram: 0.01, Disk: 0.001}
+	if err := pricing.Validate(); err != nil {
+		t.Fatalf("Valid pricing returned error: %v", err)
+	}
+
+	def := types.LabelDefinition{Resources: types.Resources{
+		Cpu: 4,
+		Ram: 8,
+		Disks: map[string]types.ResourcesDisk{
+			"ws":    {Size: 100},
+			"cache": {Size: 50},
+		},
+	}}
+	// 0.1 + 4*0.02 + 8*0.01 + 150*0.001
+	if cost := pricing.HourlyCost(def); math.Abs(cost-0.41) > 1e-9 {
+		t.Fatalf("Incorrect hourly cost: %f", cost)
+	}
+
+	pricing.Cpu = -1
+	if err := pricing.Validate(); err == nil {
+		t.Fatalf("Negative pricing should not pass validation")
+	}
+}
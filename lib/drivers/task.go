@@ -13,9 +13,41 @@
 package drivers
 
 import (
+	"fmt"
+
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 )
 
+// Generic tasks, the driver could implement any of them with the same name, options and result,
+// so the clients could use them without knowing which driver allocated the Resource
+const (
+	// TaskSnapshot creates the snapshots of the Resource disks, option "full" includes the OS
+	// disk, result: {"snapshots":["<id>", ...]}
+	TaskSnapshot = "snapshot"
+	// TaskImage creates the image of the Resource to allocate it again, result: {"image":"<id>"}
+	TaskImage = "image"
+	// TaskSuspend stops the Resource keeping its disks, result: {"status":"suspended"}
+	TaskSuspend = "suspend"
+	// TaskResume starts the suspended Resource, result: {"status":"running"}
+	TaskResume = "resume"
+	// TaskReboot restarts the Resource, result: {"status":"running"}
+	TaskReboot = "reboot"
+)
+
+// Tasks lists the generic tasks the drivers could implement
+var Tasks = []string{TaskSnapshot, TaskImage, TaskSuspend, TaskResume, TaskReboot}
+
+// SupportedTasks returns the generic tasks implemented by the driver
+func SupportedTasks(drv ResourceDriver) []string {
+	out := []string{}
+	for _, name := range Tasks {
+		if drv.GetTask(name, "") != nil {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 // ResourceDriverTask is interface for driver tasks execution
 type ResourceDriverTask interface {
 	// Name of the task
@@ -32,3 +64,27 @@ type ResourceDriverTask interface {
 	// <- result - json data with results of operation
 	Execute() (result []byte, err error)
 }
+
+// ResourceDriverTaskOutput could be implemented by the task to report the progress while it's
+// executing, Fish streams the output lines to the Application events subscribers
+type ResourceDriverTaskOutput interface {
+	// Fish provides the function to send the output line
+	SetOutput(out func(line string))
+}
+
+// TaskOutput is embedded into the task to implement ResourceDriverTaskOutput
+type TaskOutput struct {
+	out func(line string)
+}
+
+// SetOutput sets the function to send the output lines
+func (o *TaskOutput) SetOutput(out func(line string)) {
+	o.out = out
+}
+
+// Output sends the formatted line if Fish is listening for the task output
+func (o *TaskOutput) Output(format string, args ...any) {
+	if o.out != nil {
+		o.out(fmt.Sprintf(format, args...))
+	}
+}
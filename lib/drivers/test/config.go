@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
 )
 
 // Config - node driver configuration
@@ -38,6 +39,13 @@ type Config struct {
 	FailStatus         uint8 `json:"fail_status"`          // Fail on Status (0 - not, 1-254 random, 255-yes)
 	FailSnapshot       uint8 `json:"fail_snapshot"`        // Fail on Snapshot (0 - not, 1-254 random, 255-yes)
 	FailDeallocate     uint8 `json:"fail_deallocate"`      // Fail on Deallocate (0 - not, 1-254 random, 255-yes)
+	FailHealthCheck    uint8 `json:"fail_health_check"`    // Fail on HealthCheck (0 - not, 1-254 random, 255-yes)
+	FailSelfCheck      uint8 `json:"fail_self_check"`      // Fail on SelfCheck (0 - not, 1-254 random, 255-yes)
+
+	CapacityForecast util.Duration `json:"capacity_forecast"` // Pretend the capacity will free up in this time, 0 - not forecasting
+	AllocateDelay    util.Duration `json:"allocate_delay"`    // Pretend the allocated resource takes time to start, 0 - no delay
+
+	CPUPrice float64 `json:"cpu_price"` // Pretend the price of one CPU per hour, 0 - not pricing
 }
 
 // Apply takes json and applies it to the config structure
@@ -49,7 +57,7 @@ func (c *Config) Apply(config []byte) error {
 		}
 	}
 
-	return randomFail("ConfigApply", c.FailConfigApply)
+	return randomFail("ConfigApply", c.FailConfigApply, nil)
 }
 
 // Validate makes sure the config have the required defaults & that the required fields are set
@@ -64,5 +72,5 @@ func (c *Config) Validate() (err error) {
 	if err := os.MkdirAll(c.WorkspacePath, 0o750); err != nil {
 		return err
 	}
-	return randomFail("ConfigValidate", c.FailConfigValidate)
+	return randomFail("ConfigValidate", c.FailConfigValidate, nil)
 }
@@ -20,11 +20,16 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/drivers"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
 )
 
 // Factory implements drivers.ResourceDriverFactory interface
@@ -49,6 +54,10 @@ type Driver struct {
 	cfg Config
 	// Contains the available tasks of the driver
 	tasksList []drivers.ResourceDriverTask
+
+	// Random generators of the Resources seeded by the Application seed
+	rands      map[string]*rand.Rand
+	randsMutex sync.Mutex
 }
 
 // Name returns name of the driver
@@ -71,13 +80,34 @@ func (d *Driver) Prepare(config []byte) error {
 	}
 
 	// Fill up the available tasks
-	d.tasksList = append(d.tasksList, &TaskSnapshot{driver: d})
+	d.tasksList = append(d.tasksList,
+		&TaskSnapshot{driver: d},
+		&TaskPower{driver: d, action: drivers.TaskSuspend},
+		&TaskPower{driver: d, action: drivers.TaskResume},
+		&TaskPower{driver: d, action: drivers.TaskReboot},
+	)
 
 	return nil
 }
 
+// Capabilities of the driver, everything is allowed for the tests
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"*"},
+		DiskClone: true,
+		DiskReuse: true,
+		Networks:  []string{"*"},
+		Tenancy:   true,
+	}
+}
+
 // ValidateDefinition checks LabelDefinition is ok
-func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return fmt.Errorf("TEST: Resources validation failed: %s", err)
+	}
+
 	var opts Options
 	return opts.Apply(def.Options)
 }
@@ -92,7 +122,7 @@ func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDef
 		return -1
 	}
 
-	if err := randomFail("AvailableCapacity", opts.FailAvailableCapacity); err != nil {
+	if err := randomFail("AvailableCapacity", opts.FailAvailableCapacity, nil); err != nil {
 		log.Error("TEST: RandomFail:", err)
 		return -1
 	}
@@ -145,13 +175,19 @@ func (d *Driver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDef
 }
 
 // Allocate - pretends to Allocate (actually not) the Resource
-func (d *Driver) Allocate(def types.LabelDefinition, _ /*metadata*/ map[string]any) (*types.Resource, error) {
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
 	var opts Options
 	if err := opts.Apply(def.Options); err != nil {
 		return nil, log.Error("TEST: Unable to apply options:", err)
 	}
 
-	if err := randomFail("Allocate", opts.FailAllocate); err != nil {
+	// The Application seed makes the Resource identifier and failures reproducible
+	var r *rand.Rand
+	if seed, err := strconv.ParseInt(fmt.Sprint(metadata[drivers.MetadataSeed]), 10, 64); err == nil {
+		r = util.SeededRand(seed, "test")
+	}
+
+	if err := randomFail("Allocate", opts.FailAllocate, r); err != nil {
 		return nil, log.Error("TEST: RandomFail:", err)
 	}
 
@@ -159,32 +195,53 @@ func (d *Driver) Allocate(def types.LabelDefinition, _ /*metadata*/ map[string]a
 	res := &types.Resource{
 		IpAddr:         "127.0.0.1",
 		Authentication: def.Authentication,
+		DriverInfo:     drivers.InfoJSON(map[string]any{"workspace": d.cfg.WorkspacePath, "cpu": def.Resources.Cpu, "image": opts.Image}),
 	}
 	var resFile string
 	for {
-		res.Identifier = "test-" + crypt.RandString(6)
+		res.Identifier = "test-" + randString(r, 6)
 		resFile = filepath.Join(d.cfg.WorkspacePath, res.Identifier)
 		if _, err := os.Stat(resFile); os.IsNotExist(err) {
 			break
 		}
 	}
+	if r != nil {
+		d.randsMutex.Lock()
+		if d.rands == nil {
+			d.rands = make(map[string]*rand.Rand)
+		}
+		d.rands[res.Identifier] = r
+		d.randsMutex.Unlock()
+	}
 
-	// Write identifier file
-	fh, err := os.Create(resFile)
-	if err != nil {
-		return nil, fmt.Errorf("TEST: Unable to open file %q to store identifier: %v", resFile, err)
+	// Write identifier file with the tags to find the owner of the resource
+	node, app := drivers.ListTags(metadata)
+	tags, _ := json.Marshal(testResourceTags{Node: node, Application: app})
+	if err := os.WriteFile(resFile, tags, 0o640); err != nil {
+		return nil, fmt.Errorf("TEST: Unable to write file %q to store identifier: %v", resFile, err)
 	}
-	defer fh.Close()
+
+	// The resource is already created, but not reported to the node yet
+	time.Sleep(time.Duration(d.cfg.AllocateDelay))
 
 	return res, nil
 }
 
+// InfoSchema describes the test resource info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "workspace", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Workspace path of the test driver"},
+		{Name: "cpu", Type: types.DriverInfoFieldTypeInteger, Required: true, Description: "Amount of CPU requested by definition"},
+		{Name: "image", Type: types.DriverInfoFieldTypeString, Required: false, Description: "Image identifier from the options"},
+	}
+}
+
 // Status shows status of the resource
 func (d *Driver) Status(res *types.Resource) (string, error) {
 	if res == nil || res.Identifier == "" {
 		return "", fmt.Errorf("TEST: Invalid resource: %v", res)
 	}
-	if err := randomFail(fmt.Sprintf("Status %s", res.Identifier), d.cfg.FailStatus); err != nil {
+	if err := d.resourceFail(res, "Status", d.cfg.FailStatus); err != nil {
 		return "", fmt.Errorf("TEST: RandomFail: %v", err)
 	}
 
@@ -216,12 +273,91 @@ func (d *Driver) GetTask(name, options string) drivers.ResourceDriverTask {
 	return t
 }
 
+// HealthCheck pretends to run the script in the resource, fails if the resource is gone
+func (d *Driver) HealthCheck(res *types.Resource, _ string, _ time.Duration) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("TEST: Invalid resource: %v", res)
+	}
+	if err := d.resourceFail(res, "HealthCheck", d.cfg.FailHealthCheck); err != nil {
+		return fmt.Errorf("TEST: RandomFail: %v", err)
+	}
+
+	resFile := filepath.Join(d.cfg.WorkspacePath, res.Identifier)
+	if _, err := os.Stat(resFile); err != nil {
+		return fmt.Errorf("TEST: Resource '%s' is not available: %v", res.Identifier, err)
+	}
+	return nil
+}
+
+// SelfCheck makes sure the workspace is still available
+func (d *Driver) SelfCheck() error {
+	if err := randomFail("SelfCheck", d.cfg.FailSelfCheck, nil); err != nil {
+		return err
+	}
+	if _, err := os.Stat(d.cfg.WorkspacePath); err != nil {
+		return fmt.Errorf("TEST: Workspace is not available: %v", err)
+	}
+	return nil
+}
+
+// List returns the resources which files are in the workspace
+func (d *Driver) List() ([]types.Resource, error) {
+	entries, err := os.ReadDir(d.cfg.WorkspacePath)
+	if err != nil {
+		return nil, fmt.Errorf("TEST: Unable to read the workspace: %v", err)
+	}
+	out := []types.Resource{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "test-") {
+			continue
+		}
+		// The file could be empty or created manually, so the tags are optional
+		var tags testResourceTags
+		if data, err := os.ReadFile(filepath.Join(d.cfg.WorkspacePath, entry.Name())); err == nil && len(data) > 0 {
+			json.Unmarshal(data, &tags)
+		}
+		out = append(out, drivers.ListResource(entry.Name(), tags.Node, tags.Application))
+	}
+	return out, nil
+}
+
+// testResourceTags is stored in the resource file to list the resources owned by the node
+type testResourceTags struct {
+	Node        string `json:"node"`
+	Application string `json:"application"`
+}
+
+// Forecast pretends there is a pool which capacity will free up if it's configured
+func (d *Driver) Forecast() types.DriverForecast {
+	out := types.DriverForecast{Pools: []types.DriverPoolForecast{}}
+	if at := d.CapacityForecast(types.LabelDefinition{}); !at.IsZero() {
+		out.Pools = append(out.Pools, types.DriverPoolForecast{Name: "test", InstanceType: "test", Scrubbing: 1, NextCapacityAt: &at})
+	}
+	return out
+}
+
+// CapacityForecast returns the configured time when the capacity will free up
+func (d *Driver) CapacityForecast(_ /*def*/ types.LabelDefinition) time.Time {
+	if d.cfg.CapacityForecast <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(d.cfg.CapacityForecast))
+}
+
+// HourlyCost returns the price of the definition CPUs
+func (d *Driver) HourlyCost(def types.LabelDefinition) (float64, error) {
+	if d.cfg.CPUPrice <= 0 {
+		return 0, fmt.Errorf("TEST: Pricing is not configured")
+	}
+	return d.cfg.CPUPrice * float64(def.Resources.Cpu), nil
+}
+
 // Deallocate the resource
 func (d *Driver) Deallocate(res *types.Resource) error {
 	if res == nil || res.Identifier == "" {
 		return log.Error("TEST: Invalid resource:", res)
 	}
-	if err := randomFail(fmt.Sprintf("Deallocate %s", res.Identifier), d.cfg.FailDeallocate); err != nil {
+	if err := d.resourceFail(res, "Deallocate", d.cfg.FailDeallocate); err != nil {
 		return log.Error("TEST: RandomFail:", err)
 	}
 
@@ -233,10 +369,58 @@ func (d *Driver) Deallocate(res *types.Resource) error {
 		return fmt.Errorf("TEST: Unable to deallocate the resource '%s': %v", res.Identifier, err)
 	}
 
+	d.randsMutex.Lock()
+	delete(d.rands, res.Identifier)
+	d.randsMutex.Unlock()
+
 	return nil
 }
 
-func randomFail(name string, probability uint8) error {
+// resourceFail fails the Resource operation with the probability using the Resource seeded
+// random generator, which is restored from the Resource metadata if the driver was restarted
+func (d *Driver) resourceFail(res *types.Resource, name string, probability uint8) error {
+	// The generators are not thread-safe, so the lock is held while it's used
+	d.randsMutex.Lock()
+	defer d.randsMutex.Unlock()
+	return randomFail(fmt.Sprintf("%s %s", name, res.Identifier), probability, d.resourceRand(res))
+}
+
+// resourceRand returns the seeded random generator of the Resource or nil if the Resource has no
+// seed, the caller should hold randsMutex
+func (d *Driver) resourceRand(res *types.Resource) *rand.Rand {
+	if r, ok := d.rands[res.Identifier]; ok {
+		return r
+	}
+
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(res.Metadata), &metadata); err != nil {
+		return nil
+	}
+	seed, err := strconv.ParseInt(fmt.Sprint(metadata[drivers.MetadataSeed]), 10, 64)
+	if err != nil {
+		return nil
+	}
+	if d.rands == nil {
+		d.rands = make(map[string]*rand.Rand)
+	}
+	d.rands[res.Identifier] = util.SeededRand(seed, "test", res.Identifier)
+	return d.rands[res.Identifier]
+}
+
+// randString generates the base58 string with the seeded generator or crypto random if it's nil
+func randString(r *rand.Rand, size int) string {
+	if r == nil {
+		return crypt.RandString(size)
+	}
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = crypt.RandStringCharsetB58[r.Intn(len(crypt.RandStringCharsetB58))]
+	}
+	return string(data)
+}
+
+// randomFail fails with the probability, r is the seeded generator or nil to use the global one
+func randomFail(name string, probability uint8, r *rand.Rand) error {
 	// Do not fail on 0
 	if probability == 0 {
 		return nil
@@ -248,7 +432,11 @@ func randomFail(name string, probability uint8) error {
 	}
 
 	// Fail on probability 1 - low, 254 - high (but still can not fail)
-	if uint8(rand.Intn(254)) < probability { //nolint:gosec // G402,G404 -- fine for test driver
+	intn := rand.Intn //nolint:gosec // G402,G404 -- fine for test driver
+	if r != nil {
+		intn = r.Intn
+	}
+	if uint8(intn(254)) < probability {
 		return fmt.Errorf("TEST: %s failed (%d)", name, probability)
 	}
 
@@ -25,6 +25,8 @@ type Options struct {
 	FailOptionsValidate   uint8 `json:"fail_options_validate"`   // Fail on options Validate (0 - not, 1-254 random, 255-yes)
 	FailAvailableCapacity uint8 `json:"fail_available_capacity"` // Fail on executing AvailableCapacity (0 - not, 1-254 random, 255-yes)
 	FailAllocate          uint8 `json:"fail_allocate"`           // Fail on Allocate (0 - not, 1-254 random, 255-yes)
+
+	Image string `json:"image"` // Image identifier, is reported in the Resource driver info
 }
 
 // Apply takes json and applies it to the options structure
@@ -37,10 +39,10 @@ func (o *Options) Apply(options util.UnparsedJSON) error {
 		return err
 	}
 
-	return randomFail("OptionsApply", o.FailOptionsApply)
+	return randomFail("OptionsApply", o.FailOptionsApply, nil)
 }
 
 // Validate makes sure the options have the required defaults & that the required fields are set
 func (o *Options) Validate() error {
-	return randomFail("OptionsValidate", o.FailOptionsValidate)
+	return randomFail("OptionsValidate", o.FailOptionsValidate, nil)
 }
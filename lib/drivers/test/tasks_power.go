@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// TaskPower implements test suspend, resume and reboot tasks
+type TaskPower struct {
+	driver *Driver
+	action string
+
+	*types.ApplicationTask `json:"-"` // Info about the requested task
+	*types.LabelDefinition `json:"-"` // Info about the used label definition
+	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
+}
+
+// Name shows name of the task
+func (t *TaskPower) Name() string {
+	return t.action
+}
+
+// Clone copies task to use it
+func (t *TaskPower) Clone() drivers.ResourceDriverTask {
+	n := *t
+	return &n
+}
+
+// SetInfo defines the task environment
+func (t *TaskPower) SetInfo(task *types.ApplicationTask, def *types.LabelDefinition, res *types.Resource) {
+	t.ApplicationTask = task
+	t.LabelDefinition = def
+	t.Resource = res
+}
+
+// Execute runs the task
+func (t *TaskPower) Execute() (result []byte, err error) {
+	if t.ApplicationTask == nil {
+		return []byte(`{"error":"internal: invalid application task"}`), log.Error("TEST: Invalid application task:", t.ApplicationTask)
+	}
+	if t.Resource == nil || t.Resource.Identifier == "" {
+		return []byte(`{"error":"internal: invalid resource"}`), log.Error("TEST: Invalid resource:", t.Resource)
+	}
+
+	resFile := filepath.Join(t.driver.cfg.WorkspacePath, t.Resource.Identifier)
+	if _, err := os.Stat(resFile); os.IsNotExist(err) {
+		return []byte(`{}`), fmt.Errorf("TEST: Unable to %s unavailable resource '%s'", t.action, t.Resource.Identifier)
+	}
+
+	status := "running"
+	if t.action == drivers.TaskSuspend {
+		status = "suspended"
+	}
+	t.Output("Resource %s is %s", t.Resource.Identifier, status)
+
+	return json.Marshal(map[string]any{"status": status})
+}
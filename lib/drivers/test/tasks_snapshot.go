@@ -30,6 +30,7 @@ type TaskSnapshot struct {
 	*types.ApplicationTask `json:"-"` // Info about the requested task
 	*types.LabelDefinition `json:"-"` // Info about the used label definition
 	*types.Resource        `json:"-"` // Info about the processed resource
+	drivers.TaskOutput     `json:"-"` // Progress reported to the Application events
 
 	Full bool `json:"full"` // Make full (all disks including OS image), or just the additional disks snapshot
 }
@@ -63,7 +64,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 	if t.Resource == nil || t.Resource.Identifier == "" {
 		return []byte(`{"error":"internal: invalid resource"}`), log.Error("TEST: Invalid resource:", t.Resource)
 	}
-	if err := randomFail(fmt.Sprintf("Snapshot %s", t.Resource.Identifier), t.driver.cfg.FailSnapshot); err != nil {
+	if err := t.driver.resourceFail(t.Resource, "Snapshot", t.driver.cfg.FailSnapshot); err != nil {
 		return []byte(`{}`), log.Error("TEST: RandomFail:", err)
 	}
 
@@ -71,6 +72,7 @@ func (t *TaskSnapshot) Execute() (result []byte, err error) {
 	if _, err := os.Stat(resFile); os.IsNotExist(err) {
 		return []byte(`{}`), fmt.Errorf("TEST: Unable to snapshot unavailable resource '%s'", t.Resource.Identifier)
 	}
+	t.Output("Created snapshot of resource %s", t.Resource.Identifier)
 
 	return json.Marshal(map[string]any{"snapshots": []string{"test-snapshot"}, "when": t.ApplicationTask.When})
 }
@@ -20,11 +20,9 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-
 	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/inventory"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 	"github.com/adobe/aquarium-fish/lib/util"
@@ -77,27 +75,33 @@ func (d *Driver) Prepare(config []byte) error {
 	}
 
 	// Collect node resources status
-	cpuStat, err := cpu.Counts(true)
-	if err != nil {
-		return err
-	}
-	d.totalCPU = uint(cpuStat)
-
-	memStat, err := mem.VirtualMemory()
-	if err != nil {
-		return err
+	inv := inventory.Get()
+	if inv.CpuThreads == 0 || inv.RamTotal == 0 {
+		return fmt.Errorf("VMX: Unable to detect the node CPU & RAM: %d, %d", inv.CpuThreads, inv.RamTotal)
 	}
-	d.totalRAM = uint(memStat.Total / 1073741824) // Getting GB from Bytes
+	d.totalCPU = inv.CpuThreads
+	d.totalRAM = inv.RamTotal
 
 	// TODO: Cleanup the image directory in case the images are not good
 
 	return nil
 }
 
+// Capabilities of the driver, the disks are the VMDK files formatted to the filesystem
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"hfs+", "exfat", "fat32"},
+		DiskReuse: true,
+		Networks:  []string{"", "nat"},
+		Tenancy:   true,
+	}
+}
+
 // ValidateDefinition checks LabelDefinition is ok
-func (*Driver) ValidateDefinition(def types.LabelDefinition) error {
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
 	// Check resources
-	if err := def.Resources.Validate([]string{"hfs+", "exfat", "fat32"}, true); err != nil {
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
 		return log.Error("VMX: Resources validation failed:", err)
 	}
 
@@ -216,7 +220,7 @@ func (d *Driver) Allocate(def types.LabelDefinition, _ /*metadata*/ map[string]a
 
 	// Run the background monitoring of the vmware log
 	if d.cfg.LogMonitor {
-		go d.logMonitor(vmID, vmxPath)
+		util.GoSupervised("VMX log monitor "+vmID, func() { d.logMonitor(vmID, vmxPath) })
 	}
 
 	// Run the VM
@@ -231,9 +235,18 @@ func (d *Driver) Allocate(def types.LabelDefinition, _ /*metadata*/ map[string]a
 		Identifier:     vmxPath,
 		HwAddr:         vmHwaddr,
 		Authentication: def.Authentication,
+		DriverInfo:     drivers.InfoJSON(map[string]any{"image": imgPath, "network": vmNetwork}),
 	}, nil
 }
 
+// InfoSchema describes the VM info stored in the Resource
+func (*Driver) InfoSchema() types.DriverInfoSchema {
+	return types.DriverInfoSchema{
+		{Name: "image", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Path to the image the VM was cloned from"},
+		{Name: "network", Type: types.DriverInfoFieldTypeString, Required: true, Description: "Network type of the VM"},
+	}
+}
+
 // Status shows status of the resource
 func (d *Driver) Status(res *types.Resource) (string, error) {
 	if res == nil || res.Identifier == "" {
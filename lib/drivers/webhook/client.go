@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the signed provider requests
+const (
+	headerTimestamp = "X-Fish-Timestamp"
+	headerSignature = "X-Fish-Signature"
+)
+
+// sign returns signature of the body with timestamp, so the signed request could not be replayed
+// later: "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+func sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// call sends signed request to the provider service endpoint and decodes the response into out
+func (d *Driver) call(endpoint string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to encode %s request: %v", endpoint, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(d.cfg.URL, "/")+"/"+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to create %s request: %v", endpoint, err)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(headerSignature, sign(d.cfg.Secret, ts, body))
+
+	client := &http.Client{Timeout: time.Duration(d.cfg.Timeout)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Provider %s request failed: %v", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	// Limiting the response to not be flooded by the broken service
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to read provider %s response: %v", endpoint, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			msg.Message = "unknown error"
+		}
+		return fmt.Errorf("WEBHOOK: Provider %s responded with status %d: %s", endpoint, resp.StatusCode, msg.Message)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to decode provider %s response: %v", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package webhook implements driver which delegates the resources management to external service
+//
+// The service receives signed POST requests with JSON body and timeout set by the driver config:
+//   - /capacity   {"definition": {...}} -> {"capacity": <number of such resources could be allocated>}
+//   - /allocate   {"definition": {...}, "metadata": {...}} -> {"identifier": "...", "ip_addr": "...",
+//     "hw_addr": "...", "authentication": {...}, "info": {...}}
+//   - /status     {"identifier": "..."} -> {"status": "ALLOCATED" or "NONE"}
+//   - /deallocate {"identifier": "..."} -> any 2xx response
+//
+// Each request carries headers "X-Fish-Timestamp" with unix time and "X-Fish-Signature" with
+// "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>")), so the service can verify the
+// request is coming from Fish and is not replayed. Non-2xx response is treated as error and the
+// optional "message" field of the response is used as error description.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - node driver configuration
+type Config struct {
+	URL     string        `json:"url"`     // Base URL of the external provider service
+	Secret  string        `json:"secret"`  // Secret to sign the requests by HMAC, required
+	Timeout util.Duration `json:"timeout"` // Timeout of the request to the service, 30s by default
+
+	InfoSchema types.DriverInfoSchema `json:"info_schema"` // Fields of the driver info the service returns on allocate
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("WEBHOOK: Unable to apply the driver config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("WEBHOOK: Provider service URL is required")
+	}
+	if _, err := url.ParseRequestURI(c.URL); err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to parse provider service URL: %v", err)
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("WEBHOOK: Secret is required to sign the provider requests")
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = util.Duration(30 * time.Second)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Factory implements drivers.ResourceDriverFactory interface
+type Factory struct{}
+
+// Name shows name of the driver factory
+func (*Factory) Name() string {
+	return "webhook"
+}
+
+// NewResourceDriver creates new resource driver
+func (*Factory) NewResourceDriver() drivers.ResourceDriver {
+	return &Driver{}
+}
+
+func init() {
+	drivers.FactoryList = append(drivers.FactoryList, &Factory{})
+}
+
+// Driver implements drivers.ResourceDriver interface
+type Driver struct {
+	cfg Config
+}
+
+// capacityResponse is the provider answer on capacity request
+type capacityResponse struct {
+	Capacity int64 `json:"capacity"`
+}
+
+// allocateRequest is sent to the provider to allocate the resource
+type allocateRequest struct {
+	Definition types.LabelDefinition `json:"definition"`
+	Metadata   map[string]any        `json:"metadata"`
+}
+
+// allocateResponse is the provider answer on allocate request
+type allocateResponse struct {
+	Identifier     string                `json:"identifier"`
+	IPAddr         string                `json:"ip_addr"`
+	HwAddr         string                `json:"hw_addr"`
+	Authentication *types.Authentication `json:"authentication"`
+	Info           map[string]any        `json:"info"`
+}
+
+// resourceRequest identifies the allocated resource in status and deallocate requests
+type resourceRequest struct {
+	Identifier string `json:"identifier"`
+}
+
+// statusResponse is the provider answer on status request
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+// Name returns name of the driver
+func (*Driver) Name() string {
+	return "webhook"
+}
+
+// IsRemote needed to detect the out-of-node resources managed by this driver
+func (*Driver) IsRemote() bool {
+	return true
+}
+
+// Prepare initializes the driver
+func (d *Driver) Prepare(config []byte) error {
+	if err := d.cfg.Apply(config); err != nil {
+		return err
+	}
+	return d.cfg.Validate()
+}
+
+// Capabilities of the driver, the provider decides what it could serve
+func (*Driver) Capabilities() types.DriverCapabilities {
+	return types.DriverCapabilities{
+		Disks:     true,
+		DiskTypes: []string{"*"},
+		DiskClone: true,
+		DiskReuse: true,
+		Networks:  []string{"*"},
+		Tenancy:   true,
+	}
+}
+
+// ValidateDefinition checks LabelDefinition is ok, the options are passed to the provider as is
+func (d *Driver) ValidateDefinition(def types.LabelDefinition) error {
+	if err := def.Resources.Validate(d.Capabilities()); err != nil {
+		return fmt.Errorf("WEBHOOK: Resources validation failed: %s", err)
+	}
+	if def.Options == "" {
+		return nil
+	}
+	var opts map[string]any
+	if err := json.Unmarshal([]byte(def.Options), &opts); err != nil {
+		return fmt.Errorf("WEBHOOK: Options should be a json object: %v", err)
+	}
+	return nil
+}
+
+// AvailableCapacity asks the provider how many resources of the definition it could allocate
+func (d *Driver) AvailableCapacity(_ /*nodeUsage*/ types.Resources, req types.LabelDefinition) int64 {
+	var resp capacityResponse
+	if err := d.call("capacity", allocateRequest{Definition: req}, &resp); err != nil {
+		log.Error("WEBHOOK: Unable to get capacity:", err)
+		return -1
+	}
+	return resp.Capacity
+}
+
+// Allocate asks the provider to allocate the resource
+func (d *Driver) Allocate(def types.LabelDefinition, metadata map[string]any) (*types.Resource, error) {
+	var resp allocateResponse
+	if err := d.call("allocate", allocateRequest{Definition: def, Metadata: metadata}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Identifier == "" {
+		return nil, fmt.Errorf("WEBHOOK: Provider returned empty resource identifier")
+	}
+
+	res := &types.Resource{
+		Identifier:     resp.Identifier,
+		IpAddr:         resp.IPAddr,
+		HwAddr:         resp.HwAddr,
+		Authentication: def.Authentication,
+		DriverInfo:     drivers.InfoJSON(resp.Info),
+	}
+	if resp.Authentication != nil {
+		res.Authentication = resp.Authentication
+	}
+	return res, nil
+}
+
+// InfoSchema describes the resource info returned by the provider, set in the driver config
+func (d *Driver) InfoSchema() types.DriverInfoSchema {
+	return d.cfg.InfoSchema
+}
+
+// Status asks the provider about status of the resource
+func (d *Driver) Status(res *types.Resource) (string, error) {
+	if res == nil || res.Identifier == "" {
+		return "", fmt.Errorf("WEBHOOK: Invalid resource: %v", res)
+	}
+	var resp statusResponse
+	if err := d.call("status", resourceRequest{Identifier: res.Identifier}, &resp); err != nil {
+		return "", err
+	}
+	if resp.Status != drivers.StatusAllocated && resp.Status != drivers.StatusNone {
+		return "", fmt.Errorf("WEBHOOK: Provider returned unknown status %q", resp.Status)
+	}
+	return resp.Status, nil
+}
+
+// GetTask returns task struct by name, the provider tasks are not supported
+func (*Driver) GetTask(_, _ string) drivers.ResourceDriverTask {
+	return nil
+}
+
+// Deallocate asks the provider to deallocate the resource
+func (d *Driver) Deallocate(res *types.Resource) error {
+	if res == nil || res.Identifier == "" {
+		return fmt.Errorf("WEBHOOK: Invalid resource: %v", res)
+	}
+	return d.call("deallocate", resourceRequest{Identifier: res.Identifier}, nil)
+}
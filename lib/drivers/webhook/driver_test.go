@@ -0,0 +1,120 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// mockProvider verifies the request signature and serves one resource
+func mockProvider(secret string) *httptest.Server {
+	allocated := false
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(headerTimestamp), 10, 64)
+		if r.Header.Get(headerSignature) != sign(secret, ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"wrong signature"}`))
+			return
+		}
+		var req resourceRequest
+		json.Unmarshal(body, &req)
+		switch r.URL.Path {
+		case "/capacity":
+			w.Write([]byte(`{"capacity":1}`))
+		case "/allocate":
+			allocated = true
+			w.Write([]byte(`{"identifier":"mainframe-1","ip_addr":"10.0.0.1","info":{"lpar":"LP01"}}`))
+		case "/status":
+			if allocated && req.Identifier == "mainframe-1" {
+				w.Write([]byte(`{"status":"ALLOCATED"}`))
+			} else {
+				w.Write([]byte(`{"status":"NONE"}`))
+			}
+		case "/deallocate":
+			allocated = false
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func Test_webhook_driver_lifecycle(t *testing.T) {
+	srv := mockProvider("test-secret")
+	defer srv.Close()
+
+	d := &Driver{}
+	if err := d.Prepare([]byte(`{"url":"` + srv.URL + `","secret":"test-secret","info_schema":[{"name":"lpar","type":"string","required":true}]}`)); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+
+	def := types.LabelDefinition{Driver: "webhook", Options: `{"pool":"z15"}`, Resources: types.Resources{Cpu: 1, Ram: 1}}
+	if err := d.ValidateDefinition(def); err != nil {
+		t.Fatalf("Unable to validate definition: %v", err)
+	}
+	if capacity := d.AvailableCapacity(types.Resources{}, def); capacity != 1 {
+		t.Fatalf("Wrong capacity: %d", capacity)
+	}
+
+	res, err := d.Allocate(def, map[string]any{"key": "value"})
+	if err != nil {
+		t.Fatalf("Unable to allocate: %v", err)
+	}
+	if res.Identifier != "mainframe-1" || res.IpAddr != "10.0.0.1" {
+		t.Fatalf("Wrong allocated resource: %+v", res)
+	}
+	if err := drivers.ValidateInfo(d.InfoSchema(), res.DriverInfo); err != nil {
+		t.Fatalf("Wrong driver info %s: %v", res.DriverInfo, err)
+	}
+
+	if status, err := d.Status(res); err != nil || status != drivers.StatusAllocated {
+		t.Fatalf("Wrong status: %q, %v", status, err)
+	}
+	if err := d.Deallocate(res); err != nil {
+		t.Fatalf("Unable to deallocate: %v", err)
+	}
+	if status, err := d.Status(res); err != nil || status != drivers.StatusNone {
+		t.Fatalf("Wrong status after deallocate: %q, %v", status, err)
+	}
+}
+
+func Test_webhook_driver_wrong_secret(t *testing.T) {
+	srv := mockProvider("test-secret")
+	defer srv.Close()
+
+	d := &Driver{}
+	if err := d.Prepare([]byte(`{"url":"` + srv.URL + `","secret":"wrong-secret"}`)); err != nil {
+		t.Fatalf("Unable to prepare driver: %v", err)
+	}
+	_, err := d.Allocate(types.LabelDefinition{Driver: "webhook", Options: "{}"}, nil)
+	if err == nil || err.Error() != "WEBHOOK: Provider allocate responded with status 401: wrong signature" {
+		t.Fatalf("Wrong error: %v", err)
+	}
+}
+
+func Test_webhook_driver_config_validate(t *testing.T) {
+	if err := (&Driver{}).Prepare([]byte(`{"url":"http://127.0.0.1"}`)); err == nil {
+		t.Fatalf("Driver without secret should not be prepared")
+	}
+	if err := (&Driver{}).Prepare([]byte(`{"secret":"test"}`)); err == nil {
+		t.Fatalf("Driver without URL should not be prepared")
+	}
+}
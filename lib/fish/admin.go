@@ -0,0 +1,64 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Used to rollback the inspection transaction
+var errDBQueryRollback = errors.New("rollback")
+
+// DBQuery executes the read-only SQL query for the database inspection by admin
+// The query is executed in transaction which is always rolled back, so no changes will be made
+func (f *Fish) DBQuery(query string) (columns []string, rows [][]any, err error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if !strings.HasPrefix(q, "select ") && !strings.HasPrefix(q, "explain ") {
+		return nil, nil, fmt.Errorf("Fish: Only SELECT and EXPLAIN queries are allowed")
+	}
+
+	err = f.db.Transaction(func(tx *gorm.DB) error {
+		result, err := tx.Raw(query).Rows()
+		if err != nil {
+			return err
+		}
+		defer result.Close()
+
+		if columns, err = result.Columns(); err != nil {
+			return err
+		}
+		for result.Next() {
+			row := make([]any, len(columns))
+			ptrs := make([]any, len(columns))
+			for i := range row {
+				ptrs[i] = &row[i]
+			}
+			if err := result.Scan(ptrs...); err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+		if err := result.Err(); err != nil {
+			return err
+		}
+		return errDBQueryRollback
+	})
+	if errors.Is(err, errDBQueryRollback) {
+		err = nil
+	}
+	return columns, rows, err
+}
@@ -14,18 +14,35 @@
 package fish
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// ApplicationFind lists Applications by filter
-func (f *Fish) ApplicationFind(filter *string) (as []types.Application, err error) {
-	db := f.db
+// Application short ID is used by humans so the charset is not containing similar looking symbols
+const (
+	ApplicationShortIDPrefix  = "fish-"
+	applicationShortIDCharset = "23456789abcdefghjkmnpqrstuvwxyz"
+	applicationShortIDLen     = 4
+)
+
+// ApplicationFind lists Applications by filter, report uses the replica
+func (f *Fish) ApplicationFind(filter *string, report bool) (as []types.Application, err error) {
+	return f.ApplicationSelect(filter, nil, nil, report)
+}
+
+// ApplicationSelect lists Applications by filter, selector and page, report uses the replica
+func (f *Fish) ApplicationSelect(filter *string, sel *types.ApplicationSelector, page *ListPage, report bool) (as []types.Application, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -35,28 +52,134 @@ func (f *Fish) ApplicationFind(filter *string) (as []types.Application, err erro
 		}
 		db = db.Where(securedFilter)
 	}
-	err = db.Find(&as).Error
+	if sel != nil {
+		if sel.OwnerName != nil && *sel.OwnerName != "" {
+			db = db.Where("owner_name = ?", *sel.OwnerName)
+		}
+		if sel.LabelUID != nil {
+			db = db.Where("label_uid = ?", *sel.LabelUID)
+		}
+		if sel.OlderThan != nil && *sel.OlderThan != "" {
+			olderThan, err := time.ParseDuration(*sel.OlderThan)
+			if err != nil {
+				return as, fmt.Errorf("Fish: Unable to parse older_than duration: %v", err)
+			}
+			db = db.Where("created_at < ?", time.Now().Add(-olderThan))
+		}
+		if sel.NodeUID != nil {
+			db = db.Where("uid IN (SELECT application_uid FROM resources WHERE node_uid = ?)", *sel.NodeUID)
+		}
+		if sel.Project != nil {
+			db = db.Where("project = ?", *sel.Project)
+		}
+		if sel.Status != nil && *sel.Status != "" {
+			db = db.Where("? = (SELECT s.status FROM application_states s WHERE s.application_uid = applications.uid ORDER BY s.created_at DESC LIMIT 1)", *sel.Status)
+		}
+	}
+	if db, err = page.query(db, []string{"created_at", "owner_name", "label_uid"}, "uid"); err != nil {
+		return as, err
+	}
+	if err = db.Find(&as).Error; err != nil {
+		return as, err
+	}
+	return cutPage(page, as)
+}
+
+// ApplicationListByUIDs returns the Applications with the provided UIDs
+func (f *Fish) ApplicationListByUIDs(uids []types.ApplicationUID) (as []types.Application, err error) {
+	if len(uids) == 0 {
+		return as, nil
+	}
+	err = f.db.Where("uid IN ?", uids).Find(&as).Error
 	return as, err
 }
 
-// ApplicationCreate makes new Applciation
-func (f *Fish) ApplicationCreate(a *types.Application) error {
-	if a.LabelUID == uuid.Nil {
-		return fmt.Errorf("Fish: LabelUID can't be unset")
+// ApplicationCreate makes new Applciation, ctx carries the span of the caller to link the
+// Application trace with
+func (f *Fish) ApplicationCreate(ctx context.Context, a *types.Application) error {
+	// Batch is set only by the batch create
+	a.BatchUID = uuid.Nil
+	if err := f.applicationPrepare(a); err != nil {
+		return err
 	}
+	if err := f.quotaCheck(a, 1); err != nil {
+		return err
+	}
+	return f.applicationInsert(ctx, a)
+}
+
+// applicationPrepare validates the Application and fills the defaults
+func (f *Fish) applicationPrepare(a *types.Application) error {
 	if a.Metadata == "" {
 		a.Metadata = "{}"
 	}
+	if a.Template != "" {
+		if err := f.templateApply(a); err != nil {
+			return err
+		}
+	}
+	if a.LabelUID == uuid.Nil {
+		return fmt.Errorf("Fish: LabelUID can't be unset")
+	}
+	// The Application is always in the Project of it's Label
+	label, err := f.LabelGet(a.LabelUID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find Label %s: %v", a.LabelUID, err)
+	}
+	a.Project = label.Project
+	if a.Project != "" && f.ProjectRole(a.Project, a.OwnerName) == "" && !f.UserHasRole(a.OwnerName, RoleOperator) {
+		return fmt.Errorf("Fish: User %q is not a member of Project %q", a.OwnerName, a.Project)
+	}
+	if err := f.roleLabelCheck(a.OwnerName, label); err != nil {
+		return err
+	}
+	if err := f.limitMetadata(a.Metadata); err != nil {
+		return err
+	}
+	// Bad metadata fails deep in the Resource boot scripts, so checking it upfront
+	if err := metadataSchemaValidate(label, a.Metadata); err != nil {
+		return err
+	}
+	if err := f.applicationDependsValidate(a); err != nil {
+		return err
+	}
+	return f.applicationPriorityResolve(a)
+}
 
+// applicationInsert stores the prepared Application and it's initial NEW state
+func (f *Fish) applicationInsert(ctx context.Context, a *types.Application) error {
 	a.UID = f.NewUID()
-	err := f.db.Create(a).Error
+	if a.Seed == 0 {
+		a.Seed = rand.Int63() // #nosec G404
+	}
+	span := f.appTraceRoot(ctx, a.UID, "fish.application.create")
+	defer span.Finish()
+
+	var err error
+	// The short ID could be taken by the concurrent Application creation, so retrying with new one
+	for i := 0; i < 5; i++ {
+		a.ShortId = f.applicationNewShortID()
+		if err = f.db.Create(a).Error; err == nil || !isShortIDConflict(err) {
+			break
+		}
+	}
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	f.syncLog(syncKindApplication, a.UID.String(), "")
 
-	// Create ApplicationState NEW too
-	f.ApplicationStateCreate(&types.ApplicationState{
+	// Create ApplicationState NEW too, or PENDING_APPROVAL if the Label is expensive
+	state := &types.ApplicationState{
 		ApplicationUID: a.UID, Status: types.ApplicationStatusNEW,
-		Description: "Just created by Fish " + f.node.Name,
-	})
-	return err
+		Description: fmt.Sprintf("Just created by Fish %s with seed %d", f.node.Name, a.Seed),
+	}
+	if label, err := f.LabelGet(a.LabelUID); err == nil && label.RequiresApproval {
+		state.Status = types.ApplicationStatusPENDINGAPPROVAL
+		state.Description += ", waiting for approval"
+	}
+	f.ApplicationStateCreate(state)
+	return nil
 }
 
 // Intentionally disabled, application can't be updated
@@ -71,27 +194,92 @@ func (f *Fish) ApplicationGet(uid types.ApplicationUID) (a *types.Application, e
 	return a, err
 }
 
+// ApplicationGetByShortID returns Application by human-friendly short ID
+func (f *Fish) ApplicationGetByShortID(shortID string) (a *types.Application, err error) {
+	a = &types.Application{}
+	err = f.db.Where("short_id = ?", strings.ToLower(shortID)).First(a).Error
+	return a, err
+}
+
+// ApplicationResolveUID returns Application UID from the UID or short ID string
+func (f *Fish) ApplicationResolveUID(id string) (types.ApplicationUID, error) {
+	if uid, err := uuid.Parse(id); err == nil {
+		return uid, nil
+	}
+	app, err := f.ApplicationGetByShortID(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Fish: Unable to find Application %q: %v", id, err)
+	}
+	return app.UID, nil
+}
+
+// applicationNewShortID generates the new unique short ID, in the rare case of collision it's
+// getting longer to not spend much time on retries when there are lots of Applications
+func (f *Fish) applicationNewShortID() string {
+	size := applicationShortIDLen
+	for {
+		for i := 0; i < 3; i++ {
+			id := ApplicationShortIDPrefix + crypt.RandStringCharset(size, applicationShortIDCharset)
+			if _, err := f.ApplicationGetByShortID(id); err != nil {
+				return id
+			}
+		}
+		size++
+	}
+}
+
+// applicationsFillShortID sets short ID for the Applications created before it was introduced
+func (f *Fish) applicationsFillShortID() error {
+	var apps []types.Application
+	if err := f.db.Where("short_id IS NULL OR short_id = ''").Find(&apps).Error; err != nil {
+		return err
+	}
+	for _, app := range apps {
+		var err error
+		for i := 0; i < 5; i++ {
+			if err = f.db.Model(&app).Update("short_id", f.applicationNewShortID()).Error; err == nil || !isShortIDConflict(err) {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isShortIDConflict checks if the DB error is caused by the duplicated short ID
+func isShortIDConflict(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "short_id")
+}
+
 // ApplicationListGetStatusNew returns new Applications
 func (f *Fish) ApplicationListGetStatusNew() (as []types.Application, err error) {
 	// SELECT * FROM applications WHERE UID in (
 	//    SELECT application_uid FROM (
 	//        SELECT application_uid, status, max(created_at) FROM application_states GROUP BY application_uid
 	//    ) WHERE status = "NEW"
+	// ) AND UID NOT IN (
+	//    SELECT uid FROM sync_foreigns
 	// ) ORDER BY created_at
+	// The Applications received by sync are executed by the node they were created on
 	err = f.db.Order("created_at").Where("UID in (?)",
 		f.db.Select("application_uid").Table("(?)",
 			f.db.Model(&types.ApplicationState{}).Select("application_uid, status, max(created_at)").Group("application_uid"),
 		).Where("Status = ?", types.ApplicationStatusNEW),
+	).Where("UID NOT IN (?)",
+		f.db.Model(&syncForeign{}).Select("uid"),
 	).Find(&as).Error
 	return as, err
 }
 
 // ApplicationIsAllocated returns if specific Application is allocated
+// The Application waiting for deallocate approval is still allocated
 func (f *Fish) ApplicationIsAllocated(appUID types.ApplicationUID) (err error) {
 	state, err := f.ApplicationStateGetByApplication(appUID)
 	if err != nil {
 		return err
-	} else if state.Status != types.ApplicationStatusALLOCATED {
+	} else if state.Status != types.ApplicationStatusALLOCATED && state.Status != types.ApplicationStatusHOLD {
 		return fmt.Errorf("Fish: The Application is not allocated")
 	}
 	return nil
@@ -0,0 +1,83 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplicationAnnotationTextMaxLen limits the human-readable status, it's not a log storage
+const ApplicationAnnotationTextMaxLen = 1024
+
+// ApplicationAnnotationListByApplication returns the annotations of the Application
+func (f *Fish) ApplicationAnnotationListByApplication(appUID types.ApplicationUID) (aas []types.ApplicationAnnotation, err error) {
+	err = f.db.Where("application_uid = ?", appUID).Order("source, key").Find(&aas).Error
+	return aas, err
+}
+
+// ApplicationAnnotationSet creates or replaces the annotation with the same source and key and
+// sends it to the Application events subscribers if it was changed
+func (f *Fish) ApplicationAnnotationSet(a *types.ApplicationAnnotation) error {
+	if a.ApplicationUID == uuid.Nil {
+		return fmt.Errorf("Fish: ApplicationUID can't be unset")
+	}
+	if a.Source == "" || a.Key == "" {
+		return fmt.Errorf("Fish: Source and Key can't be empty")
+	}
+	if len(a.Text) > ApplicationAnnotationTextMaxLen {
+		return fmt.Errorf("Fish: Text is too long: %d > %d", len(a.Text), ApplicationAnnotationTextMaxLen)
+	}
+	if a.Data == "" {
+		a.Data = "{}"
+	}
+	if err := f.limitMetadata(a.Data); err != nil {
+		return err
+	}
+
+	f.appEvents.Lock()
+	defer f.appEvents.Unlock()
+
+	state, err := f.ApplicationStateGetByApplication(a.ApplicationUID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find the Application %s state: %v", a.ApplicationUID, err)
+	}
+
+	existing := &types.ApplicationAnnotation{}
+	err = f.db.Where("application_uid = ? AND source = ? AND key = ?", a.ApplicationUID, a.Source, a.Key).First(existing).Error
+	if err == nil {
+		if existing.Text == a.Text && existing.Data == a.Data {
+			// Nothing changed, so no need to bother the subscribers
+			*a = *existing
+			return nil
+		}
+		a.UID = existing.UID
+		a.CreatedAt = existing.CreatedAt
+		a.UpdatedAt = time.Now()
+		err = f.db.Model(existing).Updates(map[string]any{"text": a.Text, "data": a.Data, "updated_at": a.UpdatedAt}).Error
+	} else {
+		a.UID = f.NewUID()
+		err = f.db.Create(a).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	annotation := *a
+	f.applicationEventPublish(types.ApplicationStateEvent{State: *state, Annotation: &annotation})
+	return nil
+}
@@ -0,0 +1,291 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApprovalWebhookUser is used as approver name when deallocate was confirmed by the webhook
+const ApprovalWebhookUser = "webhook"
+
+// RoleApprover allows the User to approve or reject allocation of the Applications which Label
+// requires approval
+const RoleApprover = "approver"
+
+// deallocateRequest stores who requested the deallocate of the Application in HOLD state, so the
+// same user will not be able to approve it
+type deallocateRequest struct {
+	ApplicationUID types.ApplicationUID `gorm:"primaryKey"`
+	Requester      string
+	CreatedAt      time.Time
+}
+
+// ApplicationNeedsApproval returns true if the Application deallocate need to be approved
+func (f *Fish) ApplicationNeedsApproval(app *types.Application) bool {
+	if f.cfg.DeallocateApproval.Tag == "" {
+		return false
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(app.Metadata), &metadata); err != nil {
+		return false
+	}
+	switch v := metadata[f.cfg.DeallocateApproval.Tag].(type) {
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false" && v != "0"
+	case nil:
+		return false
+	}
+	return true
+}
+
+// ApplicationDeallocate requests deallocation of the Application, the regulated Applications are
+// moved to HOLD state to wait for approval instead
+func (f *Fish) ApplicationDeallocate(app *types.Application, requester string) (*types.ApplicationState, error) {
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find status for the Application %s: %v", app.UID, err)
+	}
+	if !f.ApplicationStateIsActive(state.Status) {
+		return nil, fmt.Errorf("Fish: Unable to deallocate the Application with status: %s", state.Status)
+	}
+	if state.Status == types.ApplicationStatusHOLD {
+		return nil, fmt.Errorf("Fish: The Application deallocate is already waiting for approval")
+	}
+
+	as := &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusDEALLOCATE,
+		Description: fmt.Sprintf("Requested by user %s", requester),
+	}
+	if state.Status != types.ApplicationStatusALLOCATED {
+		// The Application was not yet Allocated so just mark it as Recalled
+		as.Status = types.ApplicationStatusRECALLED
+	} else if f.ApplicationNeedsApproval(app) {
+		as.Status = types.ApplicationStatusHOLD
+		as.Description = fmt.Sprintf("Deallocate requested by user %s, waiting for approval", requester)
+		if err := f.db.Save(&deallocateRequest{ApplicationUID: app.UID, Requester: requester}).Error; err != nil {
+			return nil, fmt.Errorf("Fish: Unable to store the Application %s deallocate request: %v", app.UID, err)
+		}
+	}
+
+	if err := f.ApplicationStateCreate(as); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to deallocate the Application %s: %v", app.UID, err)
+	}
+	return as, nil
+}
+
+// ApplicationDeallocateBatch requests deallocation of the active Applications matching the
+// selector, the not active ones are skipped and the per-Application errors are not stopping it
+func (f *Fish) ApplicationDeallocateBatch(sel *types.ApplicationSelector, dryRun bool, requester string) (*types.ApplicationDeallocateBatchResult, []*types.ApplicationState, error) {
+	apps, err := f.ApplicationSelect(nil, sel, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &types.ApplicationDeallocateBatchResult{Deallocated: []types.ApplicationUID{}, Failed: map[string]string{}}
+	var states []*types.ApplicationState
+	for i := range apps {
+		state, err := f.ApplicationStateGetByApplication(apps[i].UID)
+		if err != nil || !f.ApplicationStateIsActive(state.Status) {
+			continue
+		}
+		if dryRun {
+			out.Deallocated = append(out.Deallocated, apps[i].UID)
+			continue
+		}
+		as, err := f.ApplicationDeallocate(&apps[i], requester)
+		if err != nil {
+			out.Failed[apps[i].UID.String()] = err.Error()
+			continue
+		}
+		out.Deallocated = append(out.Deallocated, apps[i].UID)
+		states = append(states, as)
+	}
+	log.Infof("Fish: Batch deallocate by %s (dry run: %v): %d deallocated, %d failed", requester, dryRun, len(out.Deallocated), len(out.Failed))
+	return out, states, nil
+}
+
+// ApplicationDeallocateApprove confirms the deallocation of the Application in HOLD state
+func (f *Fish) ApplicationDeallocateApprove(app *types.Application, approver string) (*types.ApplicationState, error) {
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find status for the Application %s: %v", app.UID, err)
+	}
+	if state.Status != types.ApplicationStatusHOLD {
+		return nil, fmt.Errorf("Fish: The Application is not waiting for approval: %s", state.Status)
+	}
+	var req deallocateRequest
+	if err := f.db.First(&req, "application_uid = ?", app.UID).Error; err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find the Application %s deallocate request: %v", app.UID, err)
+	}
+	if approver == req.Requester {
+		return nil, fmt.Errorf("Fish: The deallocate requester can't approve it")
+	}
+
+	as := &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusDEALLOCATE,
+		Description: fmt.Sprintf("Deallocate approved by %s", approver),
+	}
+	if err := f.ApplicationStateCreate(as); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to approve the Application %s deallocate: %v", app.UID, err)
+	}
+	log.Infof("Fish: Deallocate of the Application %s requested by %s approved by %s", app.UID, req.Requester, approver)
+	if err := f.db.Delete(&req).Error; err != nil {
+		log.Error("Fish: Unable to remove the Application deallocate request:", app.UID, err)
+	}
+	return as, nil
+}
+
+// ApplicationApprove allows the Application in PENDING_APPROVAL state to be allocated
+func (f *Fish) ApplicationApprove(app *types.Application, approver string) (*types.ApplicationState, error) {
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find status for the Application %s: %v", app.UID, err)
+	}
+	if state.Status != types.ApplicationStatusPENDINGAPPROVAL {
+		return nil, fmt.Errorf("Fish: The Application is not waiting for approval: %s", state.Status)
+	}
+
+	as := &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusNEW,
+		Description: fmt.Sprintf("Allocation approved by %s", approver),
+	}
+	if err := f.ApplicationStateCreate(as); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to approve the Application %s: %v", app.UID, err)
+	}
+	log.Infof("Fish: Allocation of the Application %s owned by %s approved by %s", app.UID, app.OwnerName, approver)
+	return as, nil
+}
+
+// ApplicationReject recalls the Application in PENDING_APPROVAL state, so it will not be allocated
+func (f *Fish) ApplicationReject(app *types.Application, approver, reason string) (*types.ApplicationState, error) {
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find status for the Application %s: %v", app.UID, err)
+	}
+	if state.Status != types.ApplicationStatusPENDINGAPPROVAL {
+		return nil, fmt.Errorf("Fish: The Application is not waiting for approval: %s", state.Status)
+	}
+
+	as := &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusRECALLED,
+		Description: fmt.Sprintf("Allocation rejected by %s", approver),
+	}
+	if reason != "" {
+		as.Description += ": " + reason
+	}
+	if err := f.ApplicationStateCreate(as); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to reject the Application %s: %v", app.UID, err)
+	}
+	log.Infof("Fish: Allocation of the Application %s owned by %s rejected by %s", app.UID, app.OwnerName, approver)
+	return as, nil
+}
+
+// approvalHold is used by the executing node to process the Application in HOLD state
+type approvalHold struct {
+	lastWebhook time.Time
+	escalated   bool
+}
+
+// processApprovalHold calls the approval webhook and escalates if the hold is taking too long
+func (f *Fish) processApprovalHold(hold *approvalHold, app *types.Application, state *types.ApplicationState) {
+	cfg := f.cfg.DeallocateApproval
+
+	// Asking the external system for confirmation, it could answer later so repeating periodically
+	if cfg.Webhook != "" && time.Since(hold.lastWebhook) > time.Minute {
+		hold.lastWebhook = time.Now()
+		approved, err := approvalWebhookCall(cfg.Webhook, "approve", f.approvalWebhookRequest(app, state))
+		if err != nil {
+			log.Warn("Fish: Approval webhook call failed for Application:", app.UID, err)
+		} else if approved {
+			if _, err := f.ApplicationDeallocateApprove(app, ApprovalWebhookUser); err != nil {
+				log.Error("Fish: Unable to approve Application deallocate:", app.UID, err)
+			}
+			return
+		}
+	}
+
+	escalateAfter := time.Duration(cfg.EscalateAfter)
+	if !hold.escalated && escalateAfter > 0 && time.Since(state.CreatedAt) > escalateAfter {
+		hold.escalated = true
+		log.Warnf("Fish: Application %s deallocate is waiting for approval longer than %s, escalating", app.UID, escalateAfter)
+		if cfg.EscalateWebhook != "" {
+			if _, err := approvalWebhookCall(cfg.EscalateWebhook, "escalate", f.approvalWebhookRequest(app, state)); err != nil {
+				log.Error("Fish: Escalation webhook call failed for Application:", app.UID, err)
+			}
+		}
+	}
+}
+
+// approvalRequest is the webhook request body, it contains only the identifying info since the
+// Application metadata could contain secrets
+type approvalRequest struct {
+	Action         string               `json:"action"`
+	ApplicationUID types.ApplicationUID `json:"application_uid"`
+	ShortID        string               `json:"short_id"`
+	LabelUID       types.LabelUID       `json:"label_uid"`
+	Owner          string               `json:"owner"`
+	Requester      string               `json:"requester"`
+	Description    string               `json:"description"`
+	HoldSince      time.Time            `json:"hold_since"`
+}
+
+// approvalWebhookRequest fills the webhook request for the Application in HOLD state
+func (f *Fish) approvalWebhookRequest(app *types.Application, state *types.ApplicationState) *approvalRequest {
+	req := &approvalRequest{
+		ApplicationUID: app.UID,
+		ShortID:        app.ShortId,
+		LabelUID:       app.LabelUID,
+		Owner:          app.OwnerName,
+		Description:    state.Description,
+		HoldSince:      state.CreatedAt,
+	}
+	var dr deallocateRequest
+	if err := f.db.First(&dr, "application_uid = ?", app.UID).Error; err == nil {
+		req.Requester = dr.Requester
+	}
+	return req
+}
+
+// approvalWebhookCall sends the Application info to the webhook and returns true if it approved
+func approvalWebhookCall(url, action string, req *approvalRequest) (bool, error) {
+	req.Action = action
+	body, err := json.Marshal(req)
+	if err != nil {
+		return false, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("Fish: Webhook responded with status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Approved bool `json:"approved"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		// Response body is not required for escalation
+		return false, nil
+	}
+	return out.Approved, nil
+}
@@ -0,0 +1,213 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// GangRequester is used as the deallocate requester when the gang batch is released
+const GangRequester = "fish-gang"
+
+// ApplicationBatchFind returns list of Application batches that fits the filter
+func (f *Fish) ApplicationBatchFind(filter *string) (bs []types.ApplicationBatch, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return bs, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Find(&bs).Error
+	return bs, err
+}
+
+// ApplicationBatchGet returns Application batch by UID
+func (f *Fish) ApplicationBatchGet(uid types.ApplicationBatchUID) (b *types.ApplicationBatch, err error) {
+	b = &types.ApplicationBatch{}
+	err = f.db.First(b, uid).Error
+	return b, err
+}
+
+// ApplicationBatchCreate makes the batch of identical Applications, all the checks are done
+// before creating the Applications so the batch is not created partially
+func (f *Fish) ApplicationBatchCreate(ctx context.Context, b *types.ApplicationBatch) error {
+	if err := f.limitBatchCount(b.Count); err != nil {
+		return err
+	}
+	if b.GangTimeout < 0 {
+		return fmt.Errorf("Fish: GangTimeout can't be negative")
+	}
+
+	proto := types.Application{
+		OwnerName: b.OwnerName,
+		LabelUID:  b.LabelUID,
+		Template:  b.Template,
+		Metadata:  b.Metadata,
+		Priority:  b.Priority,
+	}
+	if err := f.applicationPrepare(&proto); err != nil {
+		return err
+	}
+	if err := f.quotaCheck(&proto, b.Count); err != nil {
+		return err
+	}
+
+	b.UID = f.NewUID()
+	b.NodeUID = f.node.UID
+	b.LabelUID = proto.LabelUID
+	b.Metadata = proto.Metadata
+	b.Priority = proto.Priority
+	if err := f.db.Create(b).Error; err != nil {
+		return err
+	}
+
+	var created []types.Application
+	for i := 0; i < b.Count; i++ {
+		app := proto
+		app.BatchUID = b.UID
+		if err := f.applicationInsert(ctx, &app); err != nil {
+			// Recall the already created ones to not leave the batch half-created
+			for j := range created {
+				if _, rerr := f.ApplicationDeallocate(&created[j], GangRequester); rerr != nil {
+					log.Error("Fish: Unable to recall the batch Application:", created[j].UID, rerr)
+				}
+			}
+			return fmt.Errorf("Fish: Unable to create the batch Application: %v", err)
+		}
+		created = append(created, app)
+	}
+	return nil
+}
+
+// ApplicationListByBatch returns the Applications created by the batch
+func (f *Fish) ApplicationListByBatch(uid types.ApplicationBatchUID) (as []types.Application, err error) {
+	err = f.db.Where("batch_uid = ?", uid).Order("created_at").Find(&as).Error
+	return as, err
+}
+
+// ApplicationBatchStateGet returns the summary of the batch Applications states
+func (f *Fish) ApplicationBatchStateGet(uid types.ApplicationBatchUID) (*types.ApplicationBatchState, error) {
+	apps, err := f.ApplicationListByBatch(uid)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to get the batch Applications: %v", err)
+	}
+	out := &types.ApplicationBatchState{
+		BatchUID: uid,
+		Total:    len(apps),
+		Counts:   make(map[string]int),
+		States:   []types.ApplicationState{},
+	}
+	for _, app := range apps {
+		state, err := f.ApplicationStateGetByApplication(app.UID)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to get the Application %s state: %v", app.UID, err)
+		}
+		out.Counts[string(state.Status)]++
+		out.States = append(out.States, *state)
+	}
+	return out, nil
+}
+
+// batchGangProcess watches the gang batches created by this node and releases them if they can't
+// be allocated completely
+func (f *Fish) batchGangProcess() {
+	// The batches which are completely allocated or released are not needed to be checked again
+	done := make(map[types.ApplicationBatchUID]bool)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		var batches []types.ApplicationBatch
+		if err := f.db.Where("gang = ? AND node_uid = ?", true, f.node.UID).Find(&batches).Error; err != nil {
+			log.Error("Fish: Unable to find gang batches:", err)
+			continue
+		}
+		for i := range batches {
+			if done[batches[i].UID] {
+				continue
+			}
+			finished, err := f.batchGangCheck(&batches[i])
+			if err != nil {
+				log.Error("Fish: Unable to check gang batch:", batches[i].UID, err)
+				continue
+			}
+			done[batches[i].UID] = finished
+		}
+	}
+}
+
+// batchGangCheck releases the gang batch if one of the Applications failed before the whole batch
+// was allocated or the batch was not allocated in time, returns true if the batch is finished
+func (f *Fish) batchGangCheck(b *types.ApplicationBatch) (bool, error) {
+	apps, err := f.ApplicationListByBatch(b.UID)
+	if err != nil {
+		return false, err
+	}
+
+	// The Application which was ALLOCATED at least once is counted as allocated, so later user
+	// deallocate of the part of completely allocated batch will not release the rest
+	var allocated int64
+	if err := f.db.Model(&types.ApplicationState{}).Where("application_uid IN (?) AND status = ?",
+		f.db.Model(&types.Application{}).Select("uid").Where("batch_uid = ?", b.UID),
+		types.ApplicationStatusALLOCATED,
+	).Distinct("application_uid").Count(&allocated).Error; err != nil {
+		return false, err
+	}
+	if int(allocated) == len(apps) {
+		return true, nil
+	}
+
+	var active []types.Application
+	failed := false
+	for _, app := range apps {
+		state, err := f.ApplicationStateGetByApplication(app.UID)
+		if err != nil {
+			return false, err
+		}
+		if f.ApplicationStateIsActive(state.Status) {
+			active = append(active, app)
+		} else {
+			failed = true
+		}
+	}
+	if len(active) == 0 {
+		return true, nil
+	}
+
+	timeout := time.Duration(b.GangTimeout)
+	expired := timeout > 0 && time.Since(b.CreatedAt) > timeout
+	if !failed && !expired {
+		return false, nil
+	}
+
+	log.Infof("Fish: Releasing the gang batch %s: failed: %v, expired: %v", b.UID, failed, expired)
+	for i := range active {
+		if _, err := f.ApplicationDeallocate(&active[i], GangRequester); err != nil {
+			log.Error("Fish: Unable to deallocate the gang Application:", active[i].UID, err)
+		}
+	}
+	return true, nil
+}
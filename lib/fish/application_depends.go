@@ -0,0 +1,100 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// applicationDependsValidate checks the Application dependencies exist, active and accessible by
+// the Application owner
+func (f *Fish) applicationDependsValidate(a *types.Application) error {
+	seen := make(map[types.ApplicationUID]bool, len(a.DependsOn))
+	for _, uid := range a.DependsOn {
+		if seen[uid] {
+			return fmt.Errorf("Fish: Duplicated dependency %s", uid)
+		}
+		seen[uid] = true
+
+		dep, err := f.ApplicationGet(uid)
+		if err != nil {
+			return fmt.Errorf("Fish: Unable to find the dependency Application %s: %v", uid, err)
+		}
+		if dep.OwnerName != a.OwnerName && !f.UserHasRole(a.OwnerName, RoleOperator) {
+			return fmt.Errorf("Fish: The dependency Application %s is owned by another User", uid)
+		}
+		state, err := f.ApplicationStateGetByApplication(uid)
+		if err != nil {
+			return fmt.Errorf("Fish: Unable to get the dependency Application %s state: %v", uid, err)
+		}
+		if !f.ApplicationStateIsActive(state.Status) {
+			return fmt.Errorf("Fish: The dependency Application %s is not active: %s", uid, state.Status)
+		}
+	}
+	return nil
+}
+
+// applicationDependsReady returns true if all the dependencies are ALLOCATED, the error means one
+// of the dependencies will never be allocated so the Application can't be served
+func (f *Fish) applicationDependsReady(app *types.Application) (bool, error) {
+	ready := true
+	for _, uid := range app.DependsOn {
+		state, err := f.ApplicationStateGetByApplication(uid)
+		if err != nil {
+			return false, fmt.Errorf("Unable to get the dependency %s state: %v", uid, err)
+		}
+		if !f.ApplicationStateIsActive(state.Status) {
+			return false, fmt.Errorf("The dependency %s is not active: %s", uid, state.Status)
+		}
+		if state.Status != types.ApplicationStatusALLOCATED && state.Status != types.ApplicationStatusHOLD {
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// applicationDependsCheck returns true if the NEW Application could be elected, the Application
+// with failed dependency is switched to ERROR
+func (f *Fish) applicationDependsCheck(app *types.Application) bool {
+	if len(app.DependsOn) == 0 {
+		return true
+	}
+	ready, err := f.applicationDependsReady(app)
+	if err != nil {
+		log.Warn("Fish: The Application dependency failed:", app.UID, err)
+		f.ApplicationStateCreate(&types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusERROR,
+			Description: fmt.Sprint("Dependency failed: ", err),
+		})
+		return false
+	}
+	return ready
+}
+
+// applicationDependsInject adds the dependencies Resources addresses to the metadata
+func (f *Fish) applicationDependsInject(app *types.Application, metadata map[string]any) {
+	for i, uid := range app.DependsOn {
+		prefix := fmt.Sprintf("FISH_DEPENDENCY_%d_", i)
+		if dep, err := f.ApplicationGet(uid); err == nil {
+			metadata[prefix+"ID"] = dep.ShortId
+		}
+		res, err := f.ResourceGetByApplication(uid)
+		if err != nil {
+			log.Warn("Fish: Unable to find the dependency Resource:", app.UID, uid, err)
+			continue
+		}
+		metadata[prefix+"IP"] = res.IpAddr
+	}
+}
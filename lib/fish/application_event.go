@@ -0,0 +1,190 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplicationEventQueueLimit is the max amount of events waiting for the slow subscriber, when
+// it's reached the subscription is closed instead of skipping events to not break the order
+const ApplicationEventQueueLimit = 10000
+
+// ApplicationEventHistoryLimit is how many of the last events are kept to resume the subscriptions
+const ApplicationEventHistoryLimit = 10000
+
+// ErrApplicationEventResume is returned when the events to resume from are not available anymore,
+// so the subscriber need to re-read the current state
+var ErrApplicationEventResume = errors.New("Fish: Application events to resume from are not available")
+
+// applicationEvents keeps the sequence numbers and the subscribers of the Application state,
+// annotation and task events
+type applicationEvents struct {
+	// Held while the state is stored and published, so the events order is the same as in DB
+	sync.Mutex
+	seq         uint64
+	history     []types.ApplicationStateEvent // The last events to resume the subscriptions
+	subscribers map[*ApplicationEventSubscription]struct{}
+}
+
+// ApplicationEventSubscription receives the Application state events in the order they were
+// stored, the events of one Application are never reordered or skipped
+type ApplicationEventSubscription struct {
+	mutex  sync.Mutex
+	queue  []types.ApplicationStateEvent
+	notify chan struct{}
+	closed bool
+}
+
+// Notify returns the channel which receives signal when the new events are available
+func (s *ApplicationEventSubscription) Notify() <-chan struct{} {
+	return s.notify
+}
+
+// Next returns the queued events and false if the subscription was closed as too slow
+func (s *ApplicationEventSubscription) Next() ([]types.ApplicationStateEvent, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	out := s.queue
+	s.queue = nil
+	return out, !s.closed
+}
+
+// push adds the event to the subscriber queue, returns false if the subscriber is too slow
+func (s *ApplicationEventSubscription) push(event types.ApplicationStateEvent) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.queue) >= ApplicationEventQueueLimit {
+		s.closed = true
+	} else {
+		s.queue = append(s.queue, event)
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// The subscriber was already notified
+	}
+	return !s.closed
+}
+
+// applicationStateStore stores the state and publishes it to the subscribers in the same order
+func (f *Fish) applicationStateStore(as *types.ApplicationState) error {
+	f.appEvents.Lock()
+	defer f.appEvents.Unlock()
+
+	if err := f.db.Create(as).Error; err != nil {
+		return err
+	}
+
+	f.applicationEventPublish(types.ApplicationStateEvent{State: *as})
+	return nil
+}
+
+// applicationEventPublish numbers the event and sends it to the subscribers, appEvents lock
+// should be held
+func (f *Fish) applicationEventPublish(event types.ApplicationStateEvent) {
+	// The Application sequence is the number of its states, so it survives the node restart
+	var objectSeq int64
+	if err := f.db.Model(&types.ApplicationState{}).Where("application_uid = ?", event.State.ApplicationUID).Count(&objectSeq).Error; err != nil {
+		log.Warn("Fish: Unable to count the Application states:", event.State.ApplicationUID, err)
+	}
+	f.appEvents.seq++
+	event.Seq = f.appEvents.seq
+	event.ObjectSeq = uint64(objectSeq)
+
+	if len(f.appEvents.history) >= ApplicationEventHistoryLimit {
+		// Shifting in place to not grow the underlying array
+		copy(f.appEvents.history, f.appEvents.history[1:])
+		f.appEvents.history = f.appEvents.history[:len(f.appEvents.history)-1]
+	}
+	f.appEvents.history = append(f.appEvents.history, event)
+
+	for sub := range f.appEvents.subscribers {
+		if !sub.push(event) {
+			log.Warn("Fish: Application events subscriber is too slow, closing the subscription")
+			delete(f.appEvents.subscribers, sub)
+		}
+	}
+}
+
+// applicationEventCurrent publishes the event which is not changing the Application state, like
+// the task progress, the current state of the Application is attached to it
+func (f *Fish) applicationEventCurrent(appUID types.ApplicationUID, event types.ApplicationStateEvent) {
+	f.appEvents.Lock()
+	defer f.appEvents.Unlock()
+
+	state, err := f.ApplicationStateGetByApplication(appUID)
+	if err != nil {
+		log.Warn("Fish: Unable to find the Application state to send the event:", appUID, err)
+		return
+	}
+	event.State = *state
+	f.applicationEventPublish(event)
+}
+
+// ApplicationEventSubscribe returns the subscription to the Application state events, cancel need
+// to be called when the events are not needed anymore
+func (f *Fish) ApplicationEventSubscribe() (sub *ApplicationEventSubscription, cancel func()) {
+	sub = &ApplicationEventSubscription{notify: make(chan struct{}, 1)}
+	f.appEvents.Lock()
+	if f.appEvents.subscribers == nil {
+		f.appEvents.subscribers = make(map[*ApplicationEventSubscription]struct{})
+	}
+	f.appEvents.subscribers[sub] = struct{}{}
+	f.appEvents.Unlock()
+
+	return sub, func() {
+		f.appEvents.Lock()
+		delete(f.appEvents.subscribers, sub)
+		f.appEvents.Unlock()
+	}
+}
+
+// ApplicationEventSubscribeFrom returns the subscription which first receives the kept events
+// after the seq, ErrApplicationEventResume is returned if some of them are not kept anymore or
+// the seq is from the other node run
+func (f *Fish) ApplicationEventSubscribeFrom(seq uint64) (sub *ApplicationEventSubscription, cancel func(), err error) {
+	f.appEvents.Lock()
+	defer f.appEvents.Unlock()
+
+	if seq > f.appEvents.seq {
+		return nil, nil, ErrApplicationEventResume
+	}
+	var missed []types.ApplicationStateEvent
+	if seq < f.appEvents.seq {
+		history := f.appEvents.history
+		if len(history) == 0 || seq < history[0].Seq-1 {
+			return nil, nil, ErrApplicationEventResume
+		}
+		missed = append(missed, history[seq-(history[0].Seq-1):]...)
+	}
+
+	sub = &ApplicationEventSubscription{notify: make(chan struct{}, 1), queue: missed}
+	if len(missed) > 0 {
+		sub.notify <- struct{}{}
+	}
+	if f.appEvents.subscribers == nil {
+		f.appEvents.subscribers = make(map[*ApplicationEventSubscription]struct{})
+	}
+	f.appEvents.subscribers[sub] = struct{}{}
+
+	return sub, func() {
+		f.appEvents.Lock()
+		delete(f.appEvents.subscribers, sub)
+		f.appEvents.Unlock()
+	}, nil
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplicationFullList joins the Applications with the current state, Resource and Label in a few
+// queries to save the clients from requesting them one by one, report uses the replica
+func (f *Fish) ApplicationFullList(apps []types.Application, report bool) ([]types.ApplicationFull, error) {
+	out := make([]types.ApplicationFull, 0, len(apps))
+	if len(apps) == 0 {
+		return out, nil
+	}
+	db := f.dbFind(report)
+
+	appUIDs := make([]types.ApplicationUID, 0, len(apps))
+	labelUIDs := make([]types.LabelUID, 0, len(apps))
+	for _, app := range apps {
+		appUIDs = append(appUIDs, app.UID)
+		labelUIDs = append(labelUIDs, app.LabelUID)
+	}
+
+	var states []types.ApplicationState
+	if err := db.Where("application_uid IN ?", appUIDs).
+		Where("created_at = (SELECT max(s.created_at) FROM application_states s WHERE s.application_uid = application_states.application_uid)").
+		Find(&states).Error; err != nil {
+		return nil, err
+	}
+	stateByApp := make(map[types.ApplicationUID]types.ApplicationState, len(states))
+	for _, s := range states {
+		stateByApp[s.ApplicationUID] = s
+	}
+
+	var resources []types.Resource
+	if err := db.Where("application_uid IN ?", appUIDs).Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	resByApp := make(map[types.ApplicationUID]*types.Resource, len(resources))
+	for i := range resources {
+		resByApp[resources[i].ApplicationUID] = &resources[i]
+	}
+
+	var labels []types.Label
+	if err := db.Select("uid", "name", "version").Where("uid IN ?", labelUIDs).Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	labelByUID := make(map[types.LabelUID]types.Label, len(labels))
+	for _, l := range labels {
+		labelByUID[l.UID] = l
+	}
+
+	for _, app := range apps {
+		label := labelByUID[app.LabelUID]
+		out = append(out, types.ApplicationFull{
+			Application:  app,
+			State:        stateByApp[app.UID],
+			Resource:     resByApp[app.UID],
+			LabelName:    label.Name,
+			LabelVersion: label.Version,
+		})
+	}
+	return out, nil
+}
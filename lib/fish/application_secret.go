@@ -0,0 +1,104 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplicationSecretMaxSize limits the size of the relayed ciphertext
+const ApplicationSecretMaxSize = 32 * 1024
+
+// ApplicationSecretCreate stores the encrypted secret to relay it to the Application Resource
+// The plaintext is never available to Fish, so only ciphertext format is checked here
+func (f *Fish) ApplicationSecretCreate(s *types.ApplicationSecret) error {
+	if s.ApplicationUID == uuid.Nil {
+		return fmt.Errorf("Fish: ApplicationUID can't be unset")
+	}
+	if s.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if len(s.Ciphertext) > ApplicationSecretMaxSize {
+		return fmt.Errorf("Fish: Ciphertext is too big: %d > %d", len(s.Ciphertext), ApplicationSecretMaxSize)
+	}
+	if _, err := base64.StdEncoding.DecodeString(s.Ciphertext); err != nil {
+		return fmt.Errorf("Fish: Ciphertext should be base64-encoded: %v", err)
+	}
+
+	res, err := f.ResourceGetByApplication(s.ApplicationUID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find Resource of the Application %s: %v", s.ApplicationUID, err)
+	}
+	if res.SecretKey == "" {
+		return fmt.Errorf("Fish: The Resource secret key is not registered yet")
+	}
+
+	s.UID = f.NewUID()
+	if err := f.db.Create(s).Error; err != nil {
+		return err
+	}
+	log.Infof("Fish: Secret %q queued for Application %s", s.Name, s.ApplicationUID)
+	return nil
+}
+
+// ApplicationSecretListPop returns the pending secrets of the Application and removes them
+func (f *Fish) ApplicationSecretListPop(appUID types.ApplicationUID) (ss []types.ApplicationSecret, err error) {
+	err = f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("application_uid = ?", appUID).Order("created_at").Find(&ss).Error; err != nil {
+			return err
+		}
+		if len(ss) == 0 {
+			return nil
+		}
+		uids := make([]types.ApplicationSecretUID, len(ss))
+		for i := range ss {
+			uids[i] = ss[i].UID
+		}
+		return tx.Delete(&types.ApplicationSecret{}, uids).Error
+	})
+	return ss, err
+}
+
+// ApplicationSecretDeleteByApplication removes the not delivered secrets of the Application
+func (f *Fish) ApplicationSecretDeleteByApplication(appUID types.ApplicationUID) error {
+	return f.db.Where("application_uid = ?", appUID).Delete(&types.ApplicationSecret{}).Error
+}
+
+// ResourceSecretKeySet registers the Resource public key, it could be set only once to not allow
+// to replace the key the clients already used. The recycled Resource can't register the key since
+// the process left by the previous Application could do that to receive the next one secrets.
+func (f *Fish) ResourceSecretKeySet(res *types.Resource, publicKey string) error {
+	if res.Reused > 0 {
+		return fmt.Errorf("Fish: The secrets relay is not available for the recycled Resource")
+	}
+	if err := crypt.SecretKeyValidate(publicKey); err != nil {
+		return err
+	}
+	// Checking the key is not set in the same query to not allow the concurrent requests to race
+	result := f.db.Model(res).Where("secret_key = ''").Update("secret_key", publicKey)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("Fish: The Resource secret key is already registered")
+	}
+	return nil
+}
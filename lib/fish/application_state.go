@@ -18,8 +18,12 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/tracing"
 )
 
+// applicationElectedPrefix is the description prefix of the ELECTED state followed by the Node name
+const applicationElectedPrefix = "Elected node: "
+
 // ApplicationStateList returns list of ApplicationStates
 func (f *Fish) ApplicationStateList() (ass []types.ApplicationState, err error) {
 	err = f.db.Find(&ass).Error
@@ -36,7 +40,15 @@ func (f *Fish) ApplicationStateCreate(as *types.ApplicationState) error {
 	}
 
 	as.UID = f.NewUID()
-	return f.db.Create(as).Error
+	if err := f.applicationStateStore(as); err != nil {
+		return err
+	}
+	f.syncLog(syncKindApplicationState, as.UID.String(), "")
+
+	// State changes are recorded as instant spans to see the Application lifecycle in the trace
+	f.appTrace(as.ApplicationUID, "fish.state", tracing.Attr("status", string(as.Status)),
+		tracing.Attr("description", as.Description)).Finish()
+	return nil
 }
 
 // Intentionally disabled, application state can't be updated
@@ -58,6 +70,19 @@ func (f *Fish) ApplicationStateGetByApplication(appUID types.ApplicationUID) (as
 	return as, err
 }
 
+// ApplicationStateListActive returns the latest states of the active Applications in one query
+func (f *Fish) ApplicationStateListActive() (ass []types.ApplicationState, err error) {
+	var latest []types.ApplicationState
+	err = f.db.Where("created_at = (SELECT max(s.created_at) FROM application_states s WHERE s.application_uid = application_states.application_uid)").
+		Find(&latest).Error
+	for _, as := range latest {
+		if f.ApplicationStateIsActive(as.Status) {
+			ass = append(ass, as)
+		}
+	}
+	return ass, err
+}
+
 // ApplicationStateIsActive returns false if Status in ERROR, DEALLOCATE or DEALLOCATED state
 func (*Fish) ApplicationStateIsActive(status types.ApplicationStatus) bool {
 	if status == types.ApplicationStatusERROR {
@@ -14,17 +14,20 @@ package fish
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/adobe/aquarium-fish/lib/drivers"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// ApplicationTaskFindByApplication allows to find all the ApplicationTasks by ApplciationUID
-func (f *Fish) ApplicationTaskFindByApplication(uid types.ApplicationUID, filter *string) (at []types.ApplicationTask, err error) {
-	db := f.db.Where("application_uid = ?", uid)
+// ApplicationTaskFindByApplication allows to find all the ApplicationTasks by ApplciationUID, report uses the replica
+func (f *Fish) ApplicationTaskFindByApplication(uid types.ApplicationUID, filter *string, report bool) (at []types.ApplicationTask, err error) {
+	db := f.dbFind(report).Where("application_uid = ?", uid)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -47,6 +50,24 @@ func (f *Fish) ApplicationTaskCreate(at *types.ApplicationTask) error {
 	if at.Task == "" {
 		return fmt.Errorf("Fish: Task can't be empty")
 	}
+	// The allocated Resource knows which generic tasks its driver implements
+	if slices.Contains(drivers.Tasks, at.Task) {
+		if res, err := f.ResourceGetByApplication(at.ApplicationUID); err == nil && res.Tasks != nil && !slices.Contains(res.Tasks, at.Task) {
+			return fmt.Errorf("Fish: Task %q is not supported by the Resource driver, available: %v", at.Task, res.Tasks)
+		}
+	}
+	if at.Interval != nil && *at.Interval != "" {
+		interval, err := time.ParseDuration(*at.Interval)
+		if err != nil || interval < time.Minute {
+			return fmt.Errorf("Fish: Interval should be a duration not less than 1m: %q", *at.Interval)
+		}
+		if at.When != types.ApplicationStatusALLOCATED {
+			return fmt.Errorf("Fish: Only ALLOCATED task could be repeated with Interval")
+		}
+	}
+	if at.Retries != nil && (*at.Retries < 0 || *at.Retries > 10) {
+		return fmt.Errorf("Fish: Retries should be in range 0-10")
+	}
 	if at.Options == "" {
 		at.Options = util.UnparsedJSON("{}")
 	}
@@ -55,7 +76,12 @@ func (f *Fish) ApplicationTaskCreate(at *types.ApplicationTask) error {
 	}
 
 	at.UID = f.NewUID()
-	return f.db.Create(at).Error
+	if err := f.db.Create(at).Error; err != nil {
+		return err
+	}
+	task := *at
+	f.applicationEventCurrent(at.ApplicationUID, types.ApplicationStateEvent{Task: &task})
+	return nil
 }
 
 // ApplicationTaskSave stores the ApplicationTask
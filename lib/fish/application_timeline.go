@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"sort"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplicationTimeline returns the Application states and tasks ordered by time with durations
+func (f *Fish) ApplicationTimeline(appUID types.ApplicationUID) (out []types.ApplicationTimelineItem, err error) {
+	var states []types.ApplicationState
+	if err = f.db.Where("application_uid = ?", appUID).Order("created_at").Find(&states).Error; err != nil {
+		return out, err
+	}
+	var tasks []types.ApplicationTask
+	if err = f.db.Where("application_uid = ?", appUID).Order("created_at").Find(&tasks).Error; err != nil {
+		return out, err
+	}
+
+	now := time.Now()
+	out = make([]types.ApplicationTimelineItem, 0, len(states)+len(tasks))
+	for i, s := range states {
+		item := types.ApplicationTimelineItem{
+			Kind:        "state",
+			Name:        string(s.Status),
+			Description: s.Description,
+			StartedAt:   s.CreatedAt,
+		}
+		// The state lasts until the next one, the final states have no duration
+		if i+1 < len(states) {
+			item.FinishedAt = &states[i+1].CreatedAt
+		} else if s.Status == types.ApplicationStatusDEALLOCATED || s.Status == types.ApplicationStatusERROR {
+			item.FinishedAt = &states[i].CreatedAt
+		}
+		out = append(out, timelineDuration(item, now))
+	}
+	for i, t := range tasks {
+		item := types.ApplicationTimelineItem{
+			Kind:        "task",
+			Name:        t.Task,
+			Description: string(t.Result),
+			StartedAt:   t.CreatedAt,
+		}
+		// Executor fills the result when the task is completed
+		if t.Result != "{}" {
+			item.FinishedAt = &tasks[i].UpdatedAt
+		}
+		out = append(out, timelineDuration(item, now))
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out, nil
+}
+
+// timelineDuration fills the duration of the item, the not finished one lasts until now
+func timelineDuration(item types.ApplicationTimelineItem, now time.Time) types.ApplicationTimelineItem {
+	end := now
+	if item.FinishedAt != nil {
+		end = *item.FinishedAt
+	}
+	item.Duration = end.Sub(item.StartedAt).Seconds()
+	return item
+}
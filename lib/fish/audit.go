@@ -0,0 +1,197 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// RoleAuditor allows the User to read the audit log, which contains the actions of all the users
+const RoleAuditor = "auditor"
+
+// Auth methods of the audited actions
+const (
+	AuditAuthBasic      = "basic"       // API user basic auth
+	AuditAuthToken      = "token"       // API user basic auth with UserToken
+	AuditAuthSSHCert    = "ssh_cert"    // Admin SSH certificate
+	AuditAuthResourceIP = "resource_ip" // Meta API request from the Resource address
+)
+
+// auditRedacted fields are not stored in the audit log changes, only the fact they were changed
+var auditRedacted = []string{"hash", "password", "authentication", "ciphertext", "metadata", "secret_key", "token"}
+
+// AuditRecordCreate appends the record to the audit log, the records are never updated and removed
+// only by the retention process
+func (f *Fish) AuditRecordCreate(r *types.AuditRecord) error {
+	if r.Action == "" {
+		return fmt.Errorf("Fish: Action can't be empty")
+	}
+	if r.AuthMethod == "" {
+		return fmt.Errorf("Fish: AuthMethod can't be empty")
+	}
+	if r.Changes == "" {
+		r.Changes = util.UnparsedJSON("{}")
+	}
+
+	r.UID = f.NewUID()
+	r.NodeUID = f.node.UID
+	if err := f.db.Create(r).Error; err != nil {
+		return err
+	}
+
+	f.auditSubscribersMutex.Lock()
+	for ch := range f.auditSubscribers {
+		select {
+		case ch <- *r:
+		default:
+			// The slow subscriber should not block the API, it could get the missed records by list
+			log.Warn("Fish: Audit subscriber is too slow, skipping record:", r.UID)
+		}
+	}
+	f.auditSubscribersMutex.Unlock()
+
+	return nil
+}
+
+// AuditRecordFind returns the audit records by filter ordered by time
+func (f *Fish) AuditRecordFind(filter *string) (rs []types.AuditRecord, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return rs, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Order("created_at").Find(&rs).Error
+	return rs, err
+}
+
+// AuditSubscribe returns the channel receiving the new audit records, cancel need to be called
+// when the records are not needed anymore
+func (f *Fish) AuditSubscribe() (records <-chan types.AuditRecord, cancel func()) {
+	ch := make(chan types.AuditRecord, 100)
+	f.auditSubscribersMutex.Lock()
+	if f.auditSubscribers == nil {
+		f.auditSubscribers = make(map[chan types.AuditRecord]struct{})
+	}
+	f.auditSubscribers[ch] = struct{}{}
+	f.auditSubscribersMutex.Unlock()
+
+	return ch, func() {
+		f.auditSubscribersMutex.Lock()
+		delete(f.auditSubscribers, ch)
+		f.auditSubscribersMutex.Unlock()
+	}
+}
+
+// AuditChanges returns the changed fields of the object as {"field": {"before": ..., "after": ...}}
+// The before or after could be nil in case the object was created or removed
+func AuditChanges(before, after any) util.UnparsedJSON {
+	b, errB := auditFields(before)
+	a, errA := auditFields(after)
+	if errB != nil || errA != nil {
+		log.Errorf("Fish: Unable to prepare audit changes: %v, %v", errB, errA)
+		return util.UnparsedJSON("{}")
+	}
+
+	changes := map[string]map[string]any{}
+	for key, value := range b {
+		if !reflect.DeepEqual(value, a[key]) {
+			changes[key] = map[string]any{"before": value, "after": a[key]}
+		}
+	}
+	for key, value := range a {
+		if _, ok := b[key]; !ok {
+			changes[key] = map[string]any{"before": nil, "after": value}
+		}
+	}
+	for key, change := range changes {
+		if slices.Contains(auditRedacted, key) {
+			change["before"], change["after"] = "<redacted>", "<redacted>"
+		}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		log.Error("Fish: Unable to serialize audit changes:", err)
+		return util.UnparsedJSON("{}")
+	}
+	return util.UnparsedJSON(data)
+}
+
+// auditFields converts the object to the map of its json fields
+func auditFields(obj any) (map[string]any, error) {
+	fields := map[string]any{}
+	if obj == nil || (reflect.ValueOf(obj).Kind() == reflect.Pointer && reflect.ValueOf(obj).IsNil()) {
+		return fields, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	// The top level redacted fields are processed by AuditChanges to record the fact of change
+	for _, value := range fields {
+		auditRedactNested(value)
+	}
+	return fields, nil
+}
+
+// auditRedactNested hides the redacted fields of the nested objects, like Label definitions
+// Authentication
+func auditRedactNested(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if slices.Contains(auditRedacted, key) {
+				v[key] = "<redacted>"
+				continue
+			}
+			auditRedactNested(item)
+		}
+	case []any:
+		for _, item := range v {
+			auditRedactNested(item)
+		}
+	}
+}
+
+// auditRetentionProcess periodically removes the audit records older than retention
+func (f *Fish) auditRetentionProcess() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		result := f.db.Where("created_at < ?", time.Now().Add(-time.Duration(f.cfg.AuditRetention))).Delete(&types.AuditRecord{})
+		if result.Error != nil {
+			log.Error("Fish: Unable to remove outdated audit records:", result.Error)
+		} else if result.RowsAffected > 0 {
+			log.Infof("Fish: Removed %d outdated audit records", result.RowsAffected)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// The backup file name contains the snapshot time to find the one for the point in time restore
+const (
+	backupPrefix     = "fish-backup-"
+	backupSuffix     = ".db"
+	backupTimeFormat = "20060102T150405.000Z"
+)
+
+var backupMutex sync.Mutex
+
+// BackupDirectory returns where the node database backups are stored
+func BackupDirectory(cfg *Config) string {
+	if cfg.Backup.Directory == "" {
+		return filepath.Join(NodeDirectory(cfg), "backup")
+	}
+	if filepath.IsAbs(cfg.Backup.Directory) {
+		return cfg.Backup.Directory
+	}
+	return filepath.Join(cfg.Directory, cfg.Backup.Directory)
+}
+
+// BackupName returns the backup file name with the snapshot time
+func BackupName(t time.Time) string {
+	return backupPrefix + t.UTC().Format(backupTimeFormat) + backupSuffix
+}
+
+// BackupCreate makes the consistent snapshot of the running node database
+func (f *Fish) BackupCreate() (*types.Backup, error) {
+	backupMutex.Lock()
+	defer backupMutex.Unlock()
+
+	dir := BackupDirectory(f.cfg)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to create backup directory: %v", err)
+	}
+	now := time.Now().UTC()
+	name := BackupName(now)
+	path := filepath.Join(dir, name)
+	if err := f.dbSnapshot(path); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to stat the backup: %v", err)
+	}
+	log.Info("Fish: Database backup created:", path)
+
+	if len(f.cfg.Backup.UploadCommand) > 0 {
+		if err := backupUpload(f.cfg.Backup.UploadCommand, path); err != nil {
+			log.Error("Fish: Unable to upload the backup:", path, err)
+		}
+	}
+
+	return &types.Backup{Name: name, CreatedAt: now, Size: info.Size()}, nil
+}
+
+// BackupList returns the local backups of the node database sorted by time
+func (f *Fish) BackupList() ([]types.Backup, error) {
+	return BackupListDir(BackupDirectory(f.cfg))
+}
+
+// BackupPath returns the path of the local backup by name
+func (f *Fish) BackupPath(name string) (string, error) {
+	if _, err := backupTime(name); err != nil || filepath.Base(name) != name {
+		return "", fmt.Errorf("Fish: Wrong backup name: %q", name)
+	}
+	path := filepath.Join(BackupDirectory(f.cfg), name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("Fish: Unable to find the backup: %v", err)
+	}
+	return path, nil
+}
+
+// BackupListDir returns the backups stored in the directory sorted by time
+func BackupListDir(dir string) (out []types.Backup, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []types.Backup{}, nil
+		}
+		return nil, fmt.Errorf("Fish: Unable to read backup directory: %v", err)
+	}
+	out = []types.Backup{}
+	for _, e := range entries {
+		created, err := backupTime(e.Name())
+		if err != nil || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, types.Backup{Name: e.Name(), CreatedAt: created, Size: info.Size()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// BackupRestore replaces the database of the stopped node with the latest backup made not later
+// than the provided time, returns the name of the restored backup
+func BackupRestore(cfg *Config, from string, at time.Time) (string, error) {
+	if from == "" {
+		from = BackupDirectory(cfg)
+	}
+	backups, err := BackupListDir(from)
+	if err != nil {
+		return "", err
+	}
+	var found *types.Backup
+	for i := range backups {
+		if backups[i].CreatedAt.After(at) {
+			break
+		}
+		found = &backups[i]
+	}
+	if found == nil {
+		return "", fmt.Errorf("Fish: No backup found in %s made before %s", from, at.Format(time.RFC3339))
+	}
+
+	dbPath := filepath.Join(NodeDirectory(cfg), "sqlite.db")
+	data, err := os.ReadFile(filepath.Join(from, found.Name))
+	if err != nil {
+		return "", fmt.Errorf("Fish: Unable to read the backup: %v", err)
+	}
+	// Keeping the current database in case the wrong backup was picked
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := os.Rename(dbPath, dbPath+".before-restore"); err != nil {
+			return "", fmt.Errorf("Fish: Unable to move the current database: %v", err)
+		}
+	}
+	// The WAL of the previous database should not be applied to the restored one
+	os.Remove(dbPath + "-wal")
+	os.Remove(dbPath + "-shm")
+	if err := os.WriteFile(dbPath, data, 0o600); err != nil {
+		return "", fmt.Errorf("Fish: Unable to write the restored database: %v", err)
+	}
+	return found.Name, nil
+}
+
+// backupProcess periodically makes the database backups and removes the old ones
+func (f *Fish) backupProcess() {
+	ticker := time.NewTicker(time.Duration(f.cfg.Backup.Interval))
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		if !f.running {
+			break
+		}
+		if _, err := f.BackupCreate(); err != nil {
+			log.Error("Fish: Unable to backup the database:", err)
+			continue
+		}
+		backups, err := f.BackupList()
+		if err != nil {
+			log.Error("Fish: Unable to list the backups:", err)
+			continue
+		}
+		for i := 0; i < len(backups)-f.cfg.Backup.Keep; i++ {
+			if err := os.Remove(filepath.Join(BackupDirectory(f.cfg), backups[i].Name)); err != nil {
+				log.Warn("Fish: Unable to remove old backup:", backups[i].Name, err)
+			}
+		}
+	}
+}
+
+// backupUpload runs the configured command to copy the backup to the external storage
+func backupUpload(command []string, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	args := append(append([]string{}, command[1:]...), path)
+	out, err := exec.CommandContext(ctx, command[0], args...).CombinedOutput() // #nosec G204
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// backupTime parses the snapshot time from the backup file name
+func backupTime(name string) (time.Time, error) {
+	ts, ok := strings.CutPrefix(name, backupPrefix)
+	if !ok {
+		return time.Time{}, fmt.Errorf("Fish: Not a backup file: %s", name)
+	}
+	ts, ok = strings.CutSuffix(ts, backupSuffix)
+	if !ok {
+		return time.Time{}, fmt.Errorf("Fish: Not a backup file: %s", name)
+	}
+	return time.Parse(backupTimeFormat, ts)
+}
@@ -0,0 +1,211 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/build"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// The config keys containing those words are redacted in the bundle
+var bundleRedacted = []string{"password", "token", "secret", "key", "credential"}
+
+// The tables to count the records of in the bundle DB stats
+var bundleTables = map[string]any{
+	"applications":       &types.Application{},
+	"application_states": &types.ApplicationState{},
+	"application_tasks":  &types.ApplicationTask{},
+	"labels":             &types.Label{},
+	"nodes":              &types.Node{},
+	"resources":          &types.Resource{},
+	"users":              &types.User{},
+	"votes":              &types.Vote{},
+	"audit_records":      &types.AuditRecord{},
+}
+
+// bundleDriver is the state of the driver in the bundle
+type bundleDriver struct {
+	Name       string    `json:"name"`
+	Remote     bool      `json:"remote"`
+	Restarting bool      `json:"restarting"`
+	Panics     int       `json:"panics"`
+	LastPanic  time.Time `json:"last_panic"`
+}
+
+// BundleWrite writes the diagnostic bundle of the node as tar.gz archive: recent logs, config with
+// redacted secrets, database stats, drivers state and goroutines dump
+func (f *Fish) BundleWrite(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, obj any) error {
+		// Not escaping the HTML to keep the bundle readable
+		var data bytes.Buffer
+		enc := json.NewEncoder(&data)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(obj); err != nil {
+			return fmt.Errorf("Fish: Unable to serialize %s: %v", name, err)
+		}
+		return add(name, data.Bytes())
+	}
+
+	info := map[string]any{
+		"node_name":  f.node.Name,
+		"node_uid":   f.node.UID,
+		"version":    build.Version,
+		"build_time": build.Time,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"started_at": f.startedAt,
+		"created_at": now,
+		"goroutines": runtime.NumGoroutine(),
+	}
+	if err := addJSON("info.json", info); err != nil {
+		return err
+	}
+
+	config, err := f.bundleConfig()
+	if err != nil {
+		return err
+	}
+	if err = addJSON("config.json", config); err != nil {
+		return err
+	}
+
+	if err = add("logs.txt", []byte(strings.Join(log.Tail(-1), "\n")+"\n")); err != nil {
+		return err
+	}
+
+	if err = addJSON("db.json", f.bundleDBStats()); err != nil {
+		return err
+	}
+
+	if err = addJSON("drivers.json", bundleDrivers()); err != nil {
+		return err
+	}
+
+	var goroutines bytes.Buffer
+	if err = pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return fmt.Errorf("Fish: Unable to dump goroutines: %v", err)
+	}
+	if err = add("goroutines.txt", goroutines.Bytes()); err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// bundleConfig returns the node config with redacted secrets
+func (f *Fish) bundleConfig() (map[string]any, error) {
+	data, err := json.Marshal(f.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to serialize config: %v", err)
+	}
+	var out map[string]any
+	if err = json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to parse config: %v", err)
+	}
+	bundleRedact(out)
+	return out, nil
+}
+
+// bundleRedact replaces the secret values of the config tree
+func bundleRedact(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			lower := strings.ToLower(key)
+			redacted := false
+			for _, word := range bundleRedacted {
+				if strings.Contains(lower, word) {
+					redacted = true
+					break
+				}
+			}
+			// Keeping the empty values to see they are not set
+			if redacted && item != nil && item != "" {
+				v[key] = "<redacted>"
+				continue
+			}
+			bundleRedact(item)
+		}
+	case []any:
+		for _, item := range v {
+			bundleRedact(item)
+		}
+	}
+}
+
+// bundleDBStats returns the connection pool stats and the records count of the main tables
+func (f *Fish) bundleDBStats() map[string]any {
+	out := map[string]any{}
+	if db, err := f.db.DB(); err == nil {
+		out["pool"] = db.Stats()
+	}
+	counts := map[string]any{}
+	for name, model := range bundleTables {
+		var count int64
+		if err := f.db.Model(model).Count(&count).Error; err != nil {
+			counts[name] = err.Error()
+			continue
+		}
+		counts[name] = count
+	}
+	out["tables"] = counts
+	return out
+}
+
+// bundleDrivers returns the state of the active drivers
+func bundleDrivers() []bundleDriver {
+	out := []bundleDriver{}
+	for name, drv := range driversInstances {
+		state := bundleDriver{Name: name, Remote: drv.IsRemote()}
+		if s, ok := drv.(*supervisedDriver); ok {
+			s.mutex.RLock()
+			state.Restarting = s.restarting
+			state.Panics = s.panics
+			state.LastPanic = s.lastPanic
+			s.mutex.RUnlock()
+		}
+		out = append(out, state)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,216 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"sort"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/inventory"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// NodeCapacityGet calculates the current capacity of this node by asking the drivers about the
+// compatible definitions of the latest Label versions
+func (f *Fish) NodeCapacityGet() (*types.NodeCapacity, error) {
+	labels, err := f.LabelFind(nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &types.NodeCapacity{
+		NodeUID:   f.node.UID,
+		NodeName:  f.node.Name,
+		UpdatedAt: time.Now(),
+		Drivers:   []types.DriverCapacity{},
+		Labels:    []types.LabelCapacity{},
+	}
+	inv := inventory.Get()
+	out.CpuTotal = inv.CpuThreads
+	out.RamTotal = inv.RamTotal
+
+	f.nodeUsageMutex.Lock()
+	nodeUsage := f.nodeUsage
+	f.nodeUsageMutex.Unlock()
+	out.CpuUsed = nodeUsage.Cpu
+	out.RamUsed = nodeUsage.Ram
+
+	var names []string
+	for name := range driversInstances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Drivers = append(out.Drivers, types.DriverCapacity{Name: name, Remote: driversInstances[name].IsRemote()})
+	}
+
+	// Only the latest version of the Label is interesting for the new Applications
+	latest := make(map[string]*types.Label)
+	for i := range labels {
+		if l, ok := latest[labels[i].Name]; !ok || l.Version < labels[i].Version {
+			latest[labels[i].Name] = &labels[i]
+		}
+	}
+	names = names[:0]
+	for name := range latest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		label := latest[name]
+		lc := types.LabelCapacity{
+			LabelUID:    label.UID,
+			Name:        label.Name,
+			Version:     label.Version,
+			Definitions: []types.DefinitionCapacity{},
+		}
+		for index, ok := range f.labelCompatGet(label) {
+			if !ok {
+				continue
+			}
+			def := label.Definitions[index]
+			drv := f.driverGet(def.Driver)
+			dc := types.DefinitionCapacity{
+				Index:  index,
+				Driver: def.Driver,
+				Remote: drv.IsRemote(),
+				Slots:  -1,
+			}
+			// Node in maintenance is not taking the new Applications
+			if f.maintenance {
+				dc.Slots = 0
+			} else {
+				dc.Slots = drv.AvailableCapacity(nodeUsage, def)
+			}
+			lc.Definitions = append(lc.Definitions, dc)
+		}
+		if len(lc.Definitions) > 0 {
+			lc.Slots = labelCapacitySlots(lc.Definitions)
+			out.Labels = append(out.Labels, lc)
+		}
+	}
+
+	return out, nil
+}
+
+// ClusterCapacityGet aggregates the capacity published by the active nodes
+func (f *Fish) ClusterCapacityGet() (*types.ClusterCapacity, error) {
+	nodes, err := f.NodeActiveList()
+	if err != nil {
+		return nil, err
+	}
+
+	out := &types.ClusterCapacity{
+		Nodes:  []types.NodeCapacity{},
+		Labels: []types.LabelCapacity{},
+	}
+	labels := make(map[types.LabelUID]*types.LabelCapacity)
+	var order []types.LabelUID
+	for _, node := range nodes {
+		if node.Capacity == nil {
+			continue
+		}
+		out.Nodes = append(out.Nodes, *node.Capacity)
+
+		for _, lc := range node.Capacity.Labels {
+			sum, ok := labels[lc.LabelUID]
+			if !ok {
+				sum = &types.LabelCapacity{
+					LabelUID:    lc.LabelUID,
+					Name:        lc.Name,
+					Version:     lc.Version,
+					Definitions: []types.DefinitionCapacity{},
+				}
+				labels[lc.LabelUID] = sum
+				order = append(order, lc.LabelUID)
+			}
+			for _, dc := range lc.Definitions {
+				sum.Definitions = definitionCapacityAdd(sum.Definitions, dc)
+			}
+		}
+	}
+
+	for _, uid := range order {
+		lc := labels[uid]
+		sort.Slice(lc.Definitions, func(i, j int) bool { return lc.Definitions[i].Index < lc.Definitions[j].Index })
+		lc.Slots = labelCapacitySlots(lc.Definitions)
+		out.Labels = append(out.Labels, *lc)
+	}
+	sort.Slice(out.Labels, func(i, j int) bool { return out.Labels[i].Name < out.Labels[j].Name })
+
+	return out, nil
+}
+
+// definitionCapacityAdd merges the node definition capacity into the cluster one. The local
+// drivers have their own node resources so they are summed, the remote drivers of the nodes
+// usually share the same cloud account so they are not
+func definitionCapacityAdd(defs []types.DefinitionCapacity, dc types.DefinitionCapacity) []types.DefinitionCapacity {
+	if dc.Slots < 0 {
+		// The driver failed on the node so nothing to add
+		dc.Slots = 0
+	}
+	for i := range defs {
+		if defs[i].Index != dc.Index {
+			continue
+		}
+		if dc.Remote {
+			defs[i].Slots = max(defs[i].Slots, dc.Slots)
+		} else {
+			defs[i].Slots += dc.Slots
+		}
+		return defs
+	}
+	return append(defs, dc)
+}
+
+// labelCapacitySlots returns how many Applications of the Label could be allocated. Definitions
+// of the local drivers compete for the same node resources and the remote ones for the same cloud
+// quotas, so the max of each group is taken.
+func labelCapacitySlots(defs []types.DefinitionCapacity) int64 {
+	var local, remote int64
+	for _, dc := range defs {
+		if dc.Remote {
+			remote = max(remote, dc.Slots)
+		} else {
+			local = max(local, dc.Slots)
+		}
+	}
+	return local + remote
+}
+
+// capacityProcess publishes the node capacity for the cluster capacity API
+func (f *Fish) capacityProcess() {
+	ticker := time.NewTicker(time.Duration(f.cfg.CapacityInterval))
+	defer ticker.Stop()
+	for {
+		if !f.running {
+			break
+		}
+		if err := f.capacityPublish(); err != nil {
+			log.Error("Fish: Unable to publish the node capacity:", err)
+		}
+		<-ticker.C
+	}
+}
+
+// capacityPublish stores the current capacity of this node
+func (f *Fish) capacityPublish() error {
+	capacity, err := f.NodeCapacityGet()
+	if err != nil {
+		return err
+	}
+	f.node.Capacity = capacity
+	return f.db.Model(f.node).Update("capacity", capacity).Error
+}
@@ -13,10 +13,19 @@
 package fish
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/secrets"
+	"github.com/adobe/aquarium-fish/lib/tracing"
 	"github.com/adobe/aquarium-fish/lib/util"
 	"github.com/ghodss/yaml"
 )
@@ -43,18 +52,241 @@ type Config struct {
 
 	NodeSSHKey string `json:"ssh_key"` // The SSH RSA identity private key for the fish node (if relative - to directory)
 
+	ProxySSHRecordPath      string        `json:"proxy_ssh_record_path"`      // Where to store SSH proxy session recordings, empty disables recording (if relative - to directory)
+	ProxySSHRecordRetention util.Duration `json:"proxy_ssh_record_retention"` // How long to keep the session recordings, 0 keeps them forever
+	ProxySSHRecordInput     bool          `json:"proxy_ssh_record_input"`     // Record the user input too, disabled by default since it contains the typed passwords
+
+	ProxySSHPolicy types.ProxySSHPolicy `json:"proxy_ssh_policy"` // Default SSH proxy access policy if Label Definition not sets its own
+
+	// Emergency admin SSH endpoint to manage the node when API is not healthy, the users are
+	// authenticated only by SSH certificates signed by the cluster CA and all the actions are audited
+	AdminSSHAddress    string   `json:"admin_ssh_address"`    // Where to serve admin SSH, empty disables it
+	AdminSSHHostKey    string   `json:"admin_ssh_host_key"`   // Admin SSH host private key, generated if not exists (if relative - to directory)
+	AdminSSHCAKey      string   `json:"admin_ssh_ca_key"`     // CA public key to verify user certificates (if relative - to directory)
+	AdminSSHRevoked    string   `json:"admin_ssh_revoked"`    // File with revoked certificates serials, one per line, re-read on login (if relative - to directory)
+	AdminSSHPrincipals []string `json:"admin_ssh_principals"` // Login users allowed by the certificate principals, "admin" by default
+	AdminSSHAuditPath  string   `json:"admin_ssh_audit_path"` // Where to write audit log of admin SSH actions (if relative - to directory)
+
+	AuditRetention util.Duration `json:"audit_retention"` // How long to keep the audit log of the user actions, 0 keeps them forever
+
+	HistoryRetention ConfigHistoryRetention `json:"history_retention"` // How long to keep the objects history by type
+
+	MetricsAuth bool `json:"metrics_auth"` // Require admin, operator or auditor basic auth to get the Prometheus metrics from /metrics endpoint, enabled by default
+
+	APIClientsDirectory string `json:"api_clients_directory"` // Where the generated API clients are to serve them on /api/clients/ (if relative - to directory), empty disables
+
+	Tracing tracing.Config `json:"tracing"` // OpenTelemetry tracing exporter, the Application spans are joined cluster-wide by Application UID
+
 	DefaultResourceLifetime string `json:"default_resource_lifetime"` // Sets the lifetime of the resource which will be used if label definition one is not set
 
+	LifetimeExtend ConfigLifetimeExtend `json:"lifetime_extend"` // Extends the lifetime of the Resource still in use to not cut off the user
+
+	DBReplicaSyncInterval util.Duration `json:"db_replica_sync_interval"` // How often to sync read-only DB replica used by reporting list API calls, 0 disables replica
+
+	Backup ConfigBackup `json:"backup"` // Online snapshots of the node database to restore it to the point in time
+
+	CapacityInterval util.Duration `json:"capacity_interval"` // How often to publish the Node capacity for the cluster capacity API, 1m by default, 0 disables
+
+	InventoryInterval util.Duration `json:"inventory_interval"` // How often to detect the Node hardware inventory, 1h by default, 0 detects only on startup
+
+	Telemetry ConfigTelemetry `json:"telemetry"` // Host usage published in the Node with the thresholds switching it to WARNING
+
+	DeallocateApproval ConfigDeallocateApproval `json:"deallocate_approval"` // Two-phase deallocation for the regulated Applications
+
+	Priority  ConfigPriority `json:"priority"`  // Application scheduling priorities and preemption of the idle Resources
+	Scheduler string         `json:"scheduler"` // How the same priority Applications are ordered: "fifo" (default) or "fair_share" by owner usage
+
+	Limits ConfigLimits `json:"limits"` // Size limits of the API requests and the objects
+
+	RateLimit ConfigRateLimit `json:"rate_limit"` // Per User rate of the API requests by service or operation
+
+	CapacityWait util.Duration `json:"capacity_wait"` // How long since creation the Application could wait for the capacity forecasted by driver instead of failing on allocate, 2h by default, 0 disables
+
+	ReconcileTerminate bool `json:"reconcile_terminate"` // Deallocate the driver resources unknown to the database on startup, only reported by default
+
+	IdempotencyWindow util.Duration `json:"idempotency_window"` // How long to keep the responses of the requests with Idempotency-Key, 24h by default, 0 disables
+
+	WorkloadIdentity ConfigWorkloadIdentity `json:"workload_identity"` // OIDC tokens for the allocated Resources to access the external systems
+
+	Idle ConfigIdle `json:"idle"` // Deallocation of the unused Resources with Label `idle_timeout`
+
+	SyncCentral ConfigSyncCentral `json:"sync_central"` // Makes the node an edge one which syncs with central cluster when online
+	SyncEdges   bool              `json:"sync_edges"`   // Makes the node a central one which keeps the changes log for the edge nodes
+
+	// External secrets providers to resolve the `secret://` references used anywhere in the config
+	// and in the Label Authentication, the drivers and gates configs are resolved on start and
+	// re-read every `refresh_interval` to pick up the rotated secrets
+	Secrets secrets.Config `json:"secrets"`
+
+	MasterKey ConfigMasterKey `json:"master_key"` // Encryption at rest of the Label & Resource Authentication secrets
+
+	LabelBundle ConfigLabelBundle `json:"label_bundle"` // Signing of the exported Labels and the keys trusted on import
+
+	Notifications notify.Config `json:"notifications"` // Sinks of the lifecycle events notifications the users could subscribe to
+
+	ImageBuild ConfigImageBuild `json:"image_build"` // Image build templates the operators could run to publish the catalog Images
+
+	// Metadata keys of the bootstrap secrets (like agent tokens), they are moved to the vault during
+	// allocation and could be received by the Resource only once through META-API
+	VaultMetadata []string `json:"vault_metadata"`
+
+	// Directory with the driver plugins executables built separately from Fish, each plugin adds its
+	// driver to the built-in ones and could be used in `drivers` the same way (if relative - to directory)
+	PluginsDirectory string `json:"plugins_directory"`
+
 	// Configuration for the node drivers, if defined - only the listed plugins will be loaded
 	// Each configuration could instantinate the same driver multiple times by adding instance name
 	// separated from driver by slash symbol (like "<driver>/prod" - will create "prod" instance).
 	Drivers []ConfigDriver `json:"drivers"`
+
+	// Configuration for the gates - the way for external systems to request Resources from Fish
+	// Uses the same format as drivers (like "github/org" - will create "org" instance of github gate)
+	Gates []ConfigDriver `json:"gates"`
 }
 
 // ConfigDriver helper to store driver config without parsing it right away
 type ConfigDriver struct {
 	Name string            `json:"name"`
 	Cfg  util.UnparsedJSON `json:"cfg"`
+
+	// Price table of the driver Resources if the driver is not able to price them by itself
+	Pricing *drivers.StaticPricing `json:"pricing"`
+}
+
+// ConfigImageBuild describes the Image build templates, only the node with the template config runs
+// the build, so the commands are not coming from the API users
+type ConfigImageBuild struct {
+	Templates    map[string]ConfigImageBuildTemplate `json:"templates"`     // Build templates by name
+	SmokeTimeout util.Duration                       `json:"smoke_timeout"` // How long to wait for the smoke test Application allocation, 30m by default
+}
+
+// ConfigImageBuildTemplate is the command to build the Image, it gets the build variables as the
+// PKR_VAR_<name> env vars and should print the packer machine-readable artifact id lines
+type ConfigImageBuildTemplate struct {
+	Command []string      `json:"command"` // Command with arguments (like ["packer", "build", "-machine-readable", "macos.pkr.hcl"])
+	Dir     string        `json:"dir"`     // Working directory of the command (if relative - to directory)
+	Driver  string        `json:"driver"`  // Driver name to prefix the artifact regions of the identifiers (like "aws" for "aws/us-west-2")
+	Timeout util.Duration `json:"timeout"` // Max build duration, 2h by default
+}
+
+// ConfigMasterKey describes the node master key to encrypt the Authentication secrets in database,
+// all the nodes sharing the database need to use the same key
+type ConfigMasterKey struct {
+	File     string `json:"file"`       // Key file, generated if not exists (if relative - to directory), "master.key" by default, empty disables encryption
+	KMSKeyID string `json:"kms_key_id"` // AWS KMS key ID or alias to use instead of the file, requires `secrets.aws` config
+}
+
+// ConfigLabelBundle describes the keys of the Labels export and import, the own key is always
+// trusted so the bundle could be imported back to the same cluster
+type ConfigLabelBundle struct {
+	Key         string   `json:"key"`          // Ed25519 sign key file, generated if not exists (if relative - to directory), "label_bundle.key" by default
+	TrustedKeys []string `json:"trusted_keys"` // Base64 public keys of the other clusters to import their bundles
+}
+
+// ConfigSyncCentral describes how the edge node connects to the central cluster
+type ConfigSyncCentral struct {
+	Address  string        `json:"address"`  // Central node API URL (like "https://central:8001"), empty disables sync
+	Username string        `json:"username"` // Central user allowed to sync, usually "admin"
+	Password string        `json:"password"` // Password of the central user
+	CACert   string        `json:"ca_cert"`  // CA certificate to verify central (if relative - to directory), node CA by default
+	Interval util.Duration `json:"interval"` // How often to sync, 30s by default
+}
+
+// ConfigDeallocateApproval describes when the Application deallocate need to be approved
+type ConfigDeallocateApproval struct {
+	Tag             string        `json:"tag"`              // Application metadata key which marks it as regulated (like "evidence"), empty disables
+	Webhook         string        `json:"webhook"`          // URL to POST the approval request, response `{"approved": true}` confirms deallocate
+	EscalateAfter   util.Duration `json:"escalate_after"`   // How long to wait for approval before escalation, 0 disables
+	EscalateWebhook string        `json:"escalate_webhook"` // URL to POST the escalation notification
+}
+
+// ConfigPriority describes how the Application priority is set and when the Resources are preempted
+type ConfigPriority struct {
+	UserMax     int            `json:"user_max"`     // Max priority the regular User can request, admin and operator are not limited
+	Roles       map[string]int `json:"roles"`        // Default priority of the Applications created by the User with role, the highest one wins
+	PreemptIdle util.Duration  `json:"preempt_idle"` // Deallocate lower priority Resource idle that long if higher priority can't be placed, 0 disables
+}
+
+// ConfigLifetimeExtend describes how the lifetime of the Resource in use is extended on expiry
+type ConfigLifetimeExtend struct {
+	Grace   util.Duration `json:"grace"`   // How long to extend the lifetime of the Resource in use on expiry, 0 disables
+	Max     util.Duration `json:"max"`     // Max total extension of the Resource lifetime, one grace by default
+	Active  util.Duration `json:"active"`  // Resource used that long before expiry is considered in use, 10m by default
+	Webhook string        `json:"webhook"` // URL to POST the Application owner notification about the extension
+}
+
+// ConfigLimits describes the max sizes of the requests and objects to protect the node resources
+type ConfigLimits struct {
+	BodySize         util.HumanSize `json:"body_size"`         // Max size of the API request body, 64KB by default
+	Metadata         util.HumanSize `json:"metadata"`          // Max size of the Label, Application and template metadata, 16KB by default
+	LabelDefinitions int            `json:"label_definitions"` // Max number of definitions in one Label, 16 by default
+	BatchCount       int            `json:"batch_count"`       // Max number of Applications in one batch, 1000 by default
+	Preference       util.HumanSize `json:"preference"`        // Max size of the User preference value, 16KB by default
+	Preferences      int            `json:"preferences"`       // Max number of the preferences of one User, 100 by default
+}
+
+// ConfigRateLimit describes the User API requests rate, the operation rule has priority over the
+// service one and the rest of the operations share the default rule
+type ConfigRateLimit struct {
+	Default ConfigRate            `json:"default"` // Rate of the operations without the own rule, zero disables the limit
+	Rules   map[string]ConfigRate `json:"rules"`   // Rate by the API service (like "Application") or operation ID (like "ApplicationCreatePost")
+	Exempt  []string              `json:"exempt"`  // Users or roles not limited by the rate, "admin" is always exempt
+}
+
+// ConfigRate is the sustained rate with the burst on top of it
+type ConfigRate struct {
+	Rate  float64 `json:"rate"`  // Sustained requests per second, zero disables the limit
+	Burst int     `json:"burst"` // How many requests could be made at once, rate rounded up by default
+}
+
+// ConfigWorkloadIdentity describes the OIDC tokens issued to the Resources through Meta API
+type ConfigWorkloadIdentity struct {
+	Issuer    string        `json:"issuer"`    // External URL of the Fish API to serve OIDC discovery (like "https://fish.example.com:8001"), empty disables
+	Key       string        `json:"key"`       // ECDSA P-256 PEM key to sign the tokens, generated if not exists (if relative - to directory)
+	Audiences []string      `json:"audiences"` // Allowed token audiences, the first one is used by default
+	TTL       util.Duration `json:"ttl"`       // Lifetime of the token, 15m by default
+}
+
+// ConfigIdle describes how the idle Resources are detected and released
+type ConfigIdle struct {
+	Warning util.Duration `json:"warning"` // How long before the idle deallocation to warn the owner, 15m by default
+	CPU     float32       `json:"cpu"`     // Reported CPU usage (percent) above which the Resource is in use, 5 by default
+	Webhook string        `json:"webhook"` // URL to POST the Application owner notification about the idle Resource
+}
+
+// ConfigTelemetry describes how often to collect the Node host usage and when to warn about it
+type ConfigTelemetry struct {
+	Interval       util.Duration `json:"interval"`        // How often to collect the telemetry, 1m by default, 0 disables
+	CPU            float32       `json:"cpu"`             // Host CPU usage (percent) to warn about, 95 by default
+	RAM            float32       `json:"ram"`             // Host RAM usage (percent) to warn about, 90 by default
+	Disk           float32       `json:"disk"`            // Node directory disk usage (percent) to warn about, 90 by default
+	ClockSkew      util.Duration `json:"clock_skew"`      // Clock difference with the reference to warn about, 5s by default
+	ClockReference string        `json:"clock_reference"` // URL to compare the node clock with by the response Date header, empty disables
+}
+
+// ConfigHistoryRetention is how long to keep the history of the objects, 0 keeps it forever
+type ConfigHistoryRetention struct {
+	Application util.Duration `json:"application"` // Intermediate states and tasks of the finished Applications, the final state is kept
+	Node        util.Duration `json:"node"`        // Node history events like maintenance changes and allocated Resources
+}
+
+// ConfigBackup describes the scheduled database backups, the upload command allows to copy them to
+// the external storage (like `["aws", "s3", "cp"]` with S3-compatible endpoint)
+type ConfigBackup struct {
+	Interval      util.Duration `json:"interval"`       // How often to backup the database, 0 disables scheduled backups
+	Keep          int           `json:"keep"`           // How many local backups to keep, 24 by default
+	Directory     string        `json:"directory"`      // Where to store the backups, "backup" in the node directory by default (if relative - to directory)
+	UploadCommand []string      `json:"upload_command"` // Command to run with the backup file path appended after it's created
+}
+
+// NodeDirectory returns where the node database and files are stored, the node address port
+// separator is not allowed in the Windows paths so it's replaced there
+func NodeDirectory(cfg *Config) string {
+	name := cfg.NodeAddress
+	if runtime.GOOS == "windows" {
+		name = strings.ReplaceAll(name, ":", "_")
+	}
+	return filepath.Join(cfg.Directory, name)
 }
 
 // ReadConfigFile needed to read the config file
@@ -71,6 +303,9 @@ func (c *Config) ReadConfigFile(cfgPath string) error {
 		if err := yaml.Unmarshal(data, c); err != nil {
 			return err
 		}
+		if err := c.resolveSecrets(data); err != nil {
+			return err
+		}
 	}
 
 	if c.TLSKey == "" {
@@ -84,6 +319,91 @@ func (c *Config) ReadConfigFile(cfgPath string) error {
 		c.NodeSSHKey = c.NodeName + "_id_ecdsa"
 	}
 
+	if c.AdminSSHHostKey == "" {
+		c.AdminSSHHostKey = c.NodeName + "_admin_id_ecdsa"
+	}
+	if c.AdminSSHAddress != "" && c.AdminSSHCAKey == "" {
+		return fmt.Errorf("Fish: Admin SSH requires CA key to verify the user certificates")
+	}
+	if c.SyncCentral.Address != "" && c.SyncCentral.Interval <= 0 {
+		c.SyncCentral.Interval = util.Duration(30 * time.Second)
+	}
+
+	if c.LifetimeExtend.Grace > 0 {
+		if c.LifetimeExtend.Max <= 0 {
+			c.LifetimeExtend.Max = c.LifetimeExtend.Grace
+		}
+		if c.LifetimeExtend.Active <= 0 {
+			c.LifetimeExtend.Active = util.Duration(10 * time.Minute)
+		}
+	}
+
+	if c.Scheduler == "" {
+		c.Scheduler = SchedulerFIFO
+	}
+	if c.Scheduler != SchedulerFIFO && c.Scheduler != SchedulerFairShare {
+		return fmt.Errorf("Fish: Unknown scheduler %q, available: %q, %q", c.Scheduler, SchedulerFIFO, SchedulerFairShare)
+	}
+
+	if c.Limits.BodySize < util.KB {
+		return fmt.Errorf("Fish: Limits body_size can't be less than 1KB")
+	}
+	if c.Limits.Metadata == 0 || c.Limits.LabelDefinitions < 1 || c.Limits.BatchCount < 1 {
+		return fmt.Errorf("Fish: Limits metadata, label_definitions and batch_count should be positive")
+	}
+	if c.Limits.Preference == 0 || c.Limits.Preferences < 1 {
+		return fmt.Errorf("Fish: Limits preference and preferences should be positive")
+	}
+
+	if c.WorkloadIdentity.Issuer != "" {
+		if c.WorkloadIdentity.Key == "" {
+			c.WorkloadIdentity.Key = c.NodeName + "_workload_identity.key"
+		}
+		if len(c.WorkloadIdentity.Audiences) == 0 {
+			return fmt.Errorf("Fish: Workload identity requires at least one audience")
+		}
+		if c.WorkloadIdentity.TTL <= 0 {
+			c.WorkloadIdentity.TTL = util.Duration(15 * time.Minute)
+		}
+	}
+
+	if c.Idle.Warning <= 0 {
+		c.Idle.Warning = util.Duration(15 * time.Minute)
+	}
+	if c.Idle.CPU <= 0 {
+		c.Idle.CPU = 5
+	}
+
+	for _, drv := range c.Drivers {
+		if drv.Pricing == nil {
+			continue
+		}
+		if err := drv.Pricing.Validate(); err != nil {
+			return fmt.Errorf("Fish: Driver %q: %v", drv.Name, err)
+		}
+	}
+
+	for name, tpl := range c.ImageBuild.Templates {
+		if len(tpl.Command) == 0 || tpl.Driver == "" {
+			return fmt.Errorf("Fish: Image build template %q requires command and driver", name)
+		}
+		if tpl.Timeout <= 0 {
+			tpl.Timeout = util.Duration(2 * time.Hour)
+			c.ImageBuild.Templates[name] = tpl
+		}
+	}
+	if c.ImageBuild.SmokeTimeout <= 0 {
+		c.ImageBuild.SmokeTimeout = util.Duration(30 * time.Minute)
+	}
+
+	if c.Backup.Keep <= 0 {
+		c.Backup.Keep = 24
+	}
+
+	if len(c.AdminSSHPrincipals) == 0 {
+		c.AdminSSHPrincipals = []string{"admin"}
+	}
+
 	_, err := time.ParseDuration(c.DefaultResourceLifetime)
 	if c.DefaultResourceLifetime != "" && err != nil {
 		return fmt.Errorf("Fish: Default Resource Lifetime parse error: %v", err)
@@ -92,6 +412,35 @@ func (c *Config) ReadConfigFile(cfgPath string) error {
 	return nil
 }
 
+// resolveSecrets replaces the secret references in the config with the values. Drivers and gates
+// configs are kept as is to be resolved during their init, so the rotated secrets could be re-read.
+func (c *Config) resolveSecrets(data []byte) error {
+	if err := secrets.Init(c.Secrets); err != nil {
+		return err
+	}
+	if !secrets.HasRefs(data) {
+		return nil
+	}
+
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+	var tree map[string]any
+	if err = json.Unmarshal(jsonData, &tree); err != nil {
+		return err
+	}
+	delete(tree, "drivers")
+	delete(tree, "gates")
+	if _, err = secrets.ResolveTree(tree); err != nil {
+		return fmt.Errorf("Fish: Unable to resolve config secrets: %v", err)
+	}
+	if jsonData, err = json.Marshal(tree); err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonData, c)
+}
+
 func (c *Config) initDefaults() {
 	c.Directory = "fish_data"
 	c.APIAddress = "0.0.0.0:8001"
@@ -101,5 +450,26 @@ func (c *Config) initDefaults() {
 	c.TLSKey = "" // Will be set after read config file from NodeName
 	c.TLSCrt = "" // ...
 	c.TLSCaCrt = "ca.crt"
+	c.AdminSSHAuditPath = "admin_ssh_audit.log"
+	c.MetricsAuth = true
+	c.CapacityInterval = util.Duration(time.Minute)
+	c.InventoryInterval = util.Duration(time.Hour)
+	c.Telemetry.Interval = util.Duration(time.Minute)
+	c.Telemetry.CPU = 95
+	c.Telemetry.RAM = 90
+	c.Telemetry.Disk = 90
+	c.Telemetry.ClockSkew = util.Duration(5 * time.Second)
+	c.Notifications.ExpiryNotice = util.Duration(time.Hour)
+	c.Notifications.NodeDownAge = util.Duration(time.Minute)
+	c.MasterKey.File = "master.key"
+	c.LabelBundle.Key = "label_bundle.key"
+	c.Limits.BodySize = 64 * util.KB
+	c.Limits.Metadata = 16 * util.KB
+	c.Limits.LabelDefinitions = 16
+	c.Limits.BatchCount = 1000
+	c.Limits.Preference = 16 * util.KB
+	c.Limits.Preferences = 100
+	c.IdempotencyWindow = util.Duration(24 * time.Hour)
+	c.CapacityWait = util.Duration(2 * time.Hour)
 	c.NodeName, _ = os.Hostname()
 }
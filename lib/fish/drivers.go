@@ -14,16 +14,25 @@ package fish
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/drivers/plugin"
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/secrets"
 
 	// Load all the drivers
 	_ "github.com/adobe/aquarium-fish/lib/drivers/aws"
 	_ "github.com/adobe/aquarium-fish/lib/drivers/docker"
+	_ "github.com/adobe/aquarium-fish/lib/drivers/equinix"
+	_ "github.com/adobe/aquarium-fish/lib/drivers/hyperv"
 	_ "github.com/adobe/aquarium-fish/lib/drivers/native"
+	_ "github.com/adobe/aquarium-fish/lib/drivers/oci"
+	_ "github.com/adobe/aquarium-fish/lib/drivers/openstack"
 	_ "github.com/adobe/aquarium-fish/lib/drivers/vmx"
+	_ "github.com/adobe/aquarium-fish/lib/drivers/webhook"
 
 	// Importing test driver
 	_ "github.com/adobe/aquarium-fish/lib/drivers/test"
@@ -41,14 +50,42 @@ func (*Fish) driverGet(name string) drivers.ResourceDriver {
 	return drv
 }
 
+// driversPluginsLoad adds the drivers from the plugins directory to the available ones, the plugin
+// could not replace the built-in driver
+func (f *Fish) driversPluginsLoad() error {
+	if f.cfg.PluginsDirectory == "" {
+		return nil
+	}
+	dir := f.cfg.PluginsDirectory
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(f.cfg.Directory, dir)
+	}
+	factories, errs := plugin.Discover(dir)
+	for _, err := range errs {
+		log.Error("Fish: Unable to load driver plugin:", err)
+	}
+	for _, fbr := range factories {
+		for _, existing := range drivers.FactoryList {
+			if existing.Name() == fbr.Name() {
+				return fmt.Errorf("Driver plugin %s conflicts with the existing driver", fbr.Name())
+			}
+		}
+		drivers.FactoryList = append(drivers.FactoryList, fbr)
+	}
+	return nil
+}
+
 // driversSet making the drivers instances map with specified names
 func (f *Fish) driversSet() error {
+	if err := f.driversPluginsLoad(); err != nil {
+		return err
+	}
 	instances := make(map[string]drivers.ResourceDriver)
 
 	if len(f.cfg.Drivers) == 0 {
 		// If no drivers instances are specified in the config - load all the drivers
 		for _, fbr := range drivers.FactoryList {
-			instances[fbr.Name()] = fbr.NewResourceDriver()
+			instances[fbr.Name()] = newSupervisedDriver(fbr.Name(), fbr)
 			log.Info("Fish: Resource driver enabled:", fbr.Name())
 		}
 	} else {
@@ -57,14 +94,14 @@ func (f *Fish) driversSet() error {
 			for _, cfg := range f.cfg.Drivers {
 				log.Debug("Fish: Processing driver config:", cfg.Name, "vs", fbr.Name())
 				if cfg.Name == fbr.Name() || strings.HasPrefix(cfg.Name, fbr.Name()+"/") {
-					instances[cfg.Name] = fbr.NewResourceDriver()
+					instances[cfg.Name] = newSupervisedDriver(cfg.Name, fbr)
 					log.Info("Fish: Resource driver enabled:", fbr.Name(), "as", cfg.Name)
 				}
 			}
 		}
 
 		if len(f.cfg.Drivers) > len(instances) {
-			return fmt.Errorf("Unable to enable all the required drivers %s", f.cfg.Drivers)
+			return fmt.Errorf("Unable to enable all the required drivers %v", f.cfg.Drivers)
 		}
 	}
 
@@ -85,6 +122,12 @@ func (*Fish) driversPrepare(configs []ConfigDriver) (errs []error) {
 				break
 			}
 		}
+		jsonCfg, err := secrets.ResolveJSON(jsonCfg)
+		if err != nil {
+			errs = append(errs, err)
+			log.Warn("Fish: Resource driver config secrets resolve failed:", drv.Name(), err)
+			continue
+		}
 
 		if err := drv.Prepare(jsonCfg); err != nil {
 			errs = append(errs, err)
@@ -99,3 +142,33 @@ func (*Fish) driversPrepare(configs []ConfigDriver) (errs []error) {
 
 	return errs
 }
+
+// driversSecretsProcess re-reads the drivers config secrets and restarts the drivers which
+// secrets were rotated
+func (f *Fish) driversSecretsProcess() {
+	ticker := time.NewTicker(secrets.RefreshInterval())
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		if !f.running {
+			break
+		}
+		for _, cfg := range f.cfg.Drivers {
+			if !secrets.HasRefs([]byte(cfg.Cfg)) {
+				continue
+			}
+			drv, ok := driversInstances[cfg.Name].(*supervisedDriver)
+			if !ok {
+				continue
+			}
+			jsonCfg, err := secrets.ResolveJSON([]byte(cfg.Cfg))
+			if err != nil {
+				log.Error("Fish: Unable to resolve the resource driver config secrets:", cfg.Name, err)
+				continue
+			}
+			if err = drv.reconfigure(jsonCfg); err != nil {
+				log.Error("Fish: Unable to reconfigure the resource driver with rotated secrets:", cfg.Name, err)
+			}
+		}
+	}
+}
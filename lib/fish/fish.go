@@ -13,13 +13,14 @@
 package fish
 
 import (
+	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
-	"path"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -28,9 +29,15 @@ import (
 	"github.com/mostlygeek/arp"
 	"gorm.io/gorm"
 
+	"github.com/adobe/aquarium-fish/lib/build"
+	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/inventory"
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/secrets"
+	"github.com/adobe/aquarium-fish/lib/tracing"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
@@ -43,6 +50,15 @@ type Fish struct {
 	cfg  *Config
 	node *types.Node
 
+	// Fingerprint of the sshproxy host key for the users to pin it
+	proxySSHFingerprint string
+
+	// Signs the OIDC tokens of the Resources, nil if workload identity is disabled
+	workloadKey *ecdsa.PrivateKey
+
+	// Signs the exported Label bundles, nil if the bundle key is not set
+	labelBundleKey *crypt.SignKey
+
 	// Signal to stop the fish
 	Quit chan os.Signal
 
@@ -52,20 +68,62 @@ type Fish struct {
 	shutdownCancel chan bool
 	shutdownDelay  time.Duration
 
+	// Serializes the node maintenance sync with the requests
+	nodeMaintenanceMutex sync.Mutex
+
 	activeVotesMutex sync.Mutex
 	activeVotes      []*types.Vote
 
 	// Stores the currently executing Applications
 	applicationsMutex sync.Mutex
 	applications      []types.ApplicationUID
+	handovers         map[types.ApplicationUID]types.Node // Target nodes of the Resources on shutdown
 
-	// Used to temporary store the won Votes by Application create time
+	// Used to temporary store the won Votes to execute them in priority order
 	wonVotesMutex sync.Mutex
-	wonVotes      map[int64]types.Vote
+	wonVotes      map[types.ApplicationUID]wonVote
+
+	// Last time the Resources were used to find the idle ones for preemption
+	startedAt             time.Time
+	resourceActivityMutex sync.Mutex
+	resourceActivity      map[types.ResourceUID]time.Time
+	resourceSessions      map[types.ResourceUID]map[io.Closer]struct{}
+	resourceUsage         map[types.ResourceUID]time.Time
+
+	// Gates providing the additional ways to access the Resources
+	accessGatesMutex sync.Mutex
+	accessGates      map[string]string
+
+	// Which Label definitions could be served by the node drivers and identifiers
+	labelCompatMutex sync.Mutex
+	labelCompat      map[types.LabelUID][]bool
 
 	// Stores the current usage of the node resources
 	nodeUsageMutex sync.Mutex // Is needed to protect node resources from concurrent allocations
 	nodeUsage      types.Resources
+
+	// Read-only snapshot of the database to run reporting queries
+	replicaMutex sync.RWMutex
+	replica      *gorm.DB
+	replicaPath  string
+
+	// Stores the warm Resources to be reused by the next Applications
+	recyclePoolMutex sync.Mutex
+	recyclePool      []*recycledResource
+
+	// Receive the new audit records to stream them
+	auditSubscribersMutex sync.Mutex
+	auditSubscribers      map[chan types.AuditRecord]struct{}
+
+	// Sequence and subscribers of the Application state events
+	appEvents applicationEvents
+
+	// Enabled notification sinks by name
+	notifySinks map[string]notify.Sink
+
+	// API requests rate buckets by User and rule
+	rateLimitsMutex sync.Mutex
+	rateLimits      map[string]*util.RateBucket
 }
 
 // New creates new Fish node
@@ -88,20 +146,78 @@ func (f *Fish) Init() error {
 		&types.User{},
 		&types.Node{},
 		&types.Label{},
+		&types.Image{},
+		&types.ImageBuild{},
 		&types.Application{},
+		&types.ApplicationBatch{},
 		&types.ApplicationState{},
 		&types.ApplicationTask{},
+		&types.ApplicationSecret{},
+		&types.ApplicationAnnotation{},
+		&deallocateRequest{},
+		&IdempotencyRecord{},
+		&vaultItem{},
 		&types.Resource{},
 		&types.ResourceAccess{},
 		&types.Vote{},
 		&types.Location{},
+		&types.Project{},
 		&types.ServiceMapping{},
+		&types.Role{},
+		&types.RoleGrant{},
+		&types.UserToken{},
+		&types.Subscription{},
+		&types.AuditRecord{},
+		&types.HistoryEvent{},
+		&types.Quota{},
+		&types.Preference{},
+		&types.Template{},
+		&types.Schedule{},
+		&types.ScheduleRun{},
+		&types.Upgrade{},
+		&userOTP{},
+		&recycledResource{},
+		&syncChange{},
+		&syncCursor{},
+		&syncForeign{},
 	); err != nil {
 		return fmt.Errorf("Fish: Unable to apply DB schema: %v", err)
 	}
 
+	if err := f.masterKeyInit(); err != nil {
+		return err
+	}
+
+	if err := f.labelBundleKeyInit(); err != nil {
+		return err
+	}
+
+	if err := f.metricsInit(); err != nil {
+		return fmt.Errorf("Fish: Unable to init metrics: %v", err)
+	}
+
+	if f.cfg.WorkloadIdentity.Issuer != "" {
+		if err := f.workloadIdentityInit(); err != nil {
+			return err
+		}
+	}
+
+	if err := f.applicationsFillShortID(); err != nil {
+		return fmt.Errorf("Fish: Unable to fill Applications short ID: %v", err)
+	}
+
 	// Init variables
-	f.wonVotes = make(map[int64]types.Vote, 5)
+	f.wonVotes = make(map[types.ApplicationUID]wonVote, 5)
+	f.startedAt = time.Now()
+	// Application events sequence keeps increasing after the node restart
+	f.appEvents.seq = uint64(f.startedAt.UnixMicro())
+	f.resourceActivity = make(map[types.ResourceUID]time.Time)
+	f.resourceSessions = make(map[types.ResourceUID]map[io.Closer]struct{})
+	f.resourceUsage = make(map[types.ResourceUID]time.Time)
+	f.accessGates = make(map[string]string)
+	f.labelCompat = make(map[types.LabelUID][]bool)
+	f.rateLimits = make(map[string]*util.RateBucket)
+	f.handovers = make(map[types.ApplicationUID]types.Node)
 
 	// Create admin user and ignore errors if it's existing
 	_, err := f.UserGet("admin")
@@ -148,6 +264,18 @@ func (f *Fish) Init() error {
 		return fmt.Errorf("Fish: Unable to init node: %v", err)
 	}
 
+	node.Version = build.Version
+	inv := inventory.Detect()
+	node.Definition.Inventory = &inv
+
+	// The maintenance mode is not kept over the restart
+	node.Maintenance = false
+	node.Shutdown = false
+	node.Handover = false
+	node.Drain = false
+	node.DrainTimeout = 0
+	node.DrainStartedAt = time.Time{}
+
 	f.node = node
 	if createNode {
 		if err = f.NodeCreate(f.node); err != nil {
@@ -158,6 +286,7 @@ func (f *Fish) Init() error {
 			return fmt.Errorf("Fish: Unable to save node: %v", err)
 		}
 	}
+	f.nodeHistoryEvent(HistoryNodeStarted, "Version: "+build.Version)
 
 	// Fill the node identifiers with defaults
 	if len(f.cfg.NodeIdentifiers) == 0 {
@@ -183,6 +312,16 @@ func (f *Fish) Init() error {
 		log.Error("Fish: Unable to prepare some resource drivers:", errs)
 	}
 
+	// Restore the warm Resources pool, they are consuming the node resources
+	if err := f.recyclePoolRestore(); err != nil {
+		return log.Error("Fish: Unable to restore recycle pool:", err)
+	}
+
+	// Clean up what was left by the crash before serving the Resources
+	if err := f.reconcileDrivers(); err != nil {
+		log.Error("Fish: Unable to reconcile the drivers resources:", err)
+	}
+
 	// Continue to execute the assigned applications
 	resources, err := f.ResourceListNode(f.node.UID)
 	if err != nil {
@@ -211,12 +350,89 @@ func (f *Fish) Init() error {
 		}
 	}
 
+	// The Image builds were running as the node child processes, so they are lost
+	if err := f.imageBuildInterrupted(); err != nil {
+		log.Error("Fish: Unable to fail the interrupted Image builds:", err)
+	}
+
 	// Run node ping timer
 	go f.pingProcess()
 
 	// Run application vote process
 	go f.checkNewApplicationProcess()
 
+	// Run gang batches watcher process
+	go f.batchGangProcess()
+	go f.scheduleProcess()
+	go f.upgradeProcess()
+
+	// Run notifications processes
+	f.notifySinks = notify.NewSinks(&f.cfg.Notifications)
+	go f.notificationProcess()
+	go f.nodeDownProcess()
+
+	// Run expired role grants revoke process
+	go f.roleGrantProcess()
+
+	// Run recycle pool cleanup process
+	go f.recyclePoolProcess()
+
+	// Run audit log cleanup process if the records are not kept forever
+	if f.cfg.AuditRetention > 0 {
+		go f.auditRetentionProcess()
+	}
+
+	// Run history cleanup process if any of the history is not kept forever
+	if f.cfg.HistoryRetention.Node > 0 || f.cfg.HistoryRetention.Application > 0 {
+		go f.historyRetentionProcess()
+	}
+
+	// Run drivers secrets rotation process if needed
+	if secrets.RefreshInterval() > 0 {
+		go f.driversSecretsProcess()
+	}
+
+	// Run node inventory detection process if needed
+	if f.cfg.InventoryInterval > 0 {
+		go f.inventoryProcess()
+	}
+
+	// Run node capacity publishing process if needed
+	if f.cfg.CapacityInterval > 0 {
+		go f.capacityProcess()
+	}
+
+	// Run API rate buckets cleanup process
+	go f.rateLimitProcess()
+
+	// Run the process to serve the Resources passed by the other nodes on their shutdown
+	go f.handoverProcess()
+
+	// Run idempotency records cleanup process if the keys are enabled
+	if f.cfg.IdempotencyWindow > 0 {
+		go f.idempotencyProcess()
+	}
+
+	// Run node telemetry publishing process if needed
+	if f.cfg.Telemetry.Interval > 0 {
+		go f.telemetryProcess()
+	}
+
+	// Run DB replica sync process if needed
+	if f.cfg.DBReplicaSyncInterval > 0 {
+		go f.replicaProcess()
+	}
+
+	// Run scheduled database backups if needed
+	if f.cfg.Backup.Interval > 0 {
+		go f.backupProcess()
+	}
+
+	// Run differential sync with the central cluster if it's the edge node
+	if f.cfg.SyncCentral.Address != "" {
+		go f.syncEdgeProcess()
+	}
+
 	// Run ARP autoupdate process to ensure the addresses will be ok
 	arp.AutoRefresh(30 * time.Second)
 
@@ -225,6 +441,7 @@ func (f *Fish) Init() error {
 
 // Close tells the node that the Fish execution need to be stopped
 func (f *Fish) Close() {
+	f.nodeHistoryEvent(HistoryNodeStopped, "")
 	f.running = false
 }
 
@@ -243,6 +460,28 @@ func (f *Fish) GetProxySSHEndpoint() string {
 	return f.cfg.ProxySSHAddress
 }
 
+// GetProxySSHFingerprint returns fingerprint of the sshproxy host key
+func (f *Fish) GetProxySSHFingerprint() string {
+	return f.proxySSHFingerprint
+}
+
+// SetProxySSHFingerprint stores fingerprint of the sshproxy host key to give it to the users
+func (f *Fish) SetProxySSHFingerprint(fingerprint string) {
+	f.proxySSHFingerprint = fingerprint
+}
+
+// GetTLSCertKeyPaths returns the absolute paths to the node certificate and key to serve TLS
+func (f *Fish) GetTLSCertKeyPaths() (certPath, keyPath string) {
+	certPath, keyPath = f.cfg.TLSCrt, f.cfg.TLSKey
+	if !filepath.IsAbs(certPath) {
+		certPath = filepath.Join(f.cfg.Directory, certPath)
+	}
+	if !filepath.IsAbs(keyPath) {
+		keyPath = filepath.Join(f.cfg.Directory, keyPath)
+	}
+	return certPath, keyPath
+}
+
 // NewUID Creates new UID with 6 starting bytes of Node UID as prefix
 func (f *Fish) NewUID() uuid.UUID {
 	uid := uuid.New()
@@ -275,6 +514,10 @@ func (f *Fish) checkNewApplicationProcess() {
 				if f.voteActive(app.UID) {
 					continue
 				}
+				// Election starts only when all the Application dependencies are allocated
+				if !f.applicationDependsCheck(&app) {
+					continue
+				}
 				log.Info("Fish: NEW Application with no vote:", app.UID, app.CreatedAt)
 
 				// Vote not exists in the active votes - running the process
@@ -294,22 +537,16 @@ func (f *Fish) checkNewApplicationProcess() {
 			}
 
 			// Check the Applications ready to be allocated
-			// It's needed to be single-threaded to have some order in allocation - FIFO principle,
-			// who requested first should be processed first.
+			// It's needed to be single-threaded to have some order in allocation - higher priority
+			// first, then the fair share of the owners if enabled and FIFO principle, who requested
+			// first should be processed first.
 			f.wonVotesMutex.Lock()
 			{
-				// We need to sort the won_votes by key which is time they was created
-				keys := make([]int64, 0, len(f.wonVotes))
-				for k := range f.wonVotes {
-					keys = append(keys, k)
-				}
-				sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
-
-				for _, k := range keys {
-					if err := f.executeApplication(f.wonVotes[k]); err != nil {
-						log.Errorf("Fish: Can't execute Application %s: %v", f.wonVotes[k].ApplicationUID, err)
+				for _, won := range wonVotesOrdered(f.wonVotes, f.schedulerUsage()) {
+					if err := f.executeApplication(won.Vote); err != nil {
+						log.Errorf("Fish: Can't execute Application %s: %v", won.Vote.ApplicationUID, err)
 					}
-					delete(f.wonVotes, k)
+					delete(f.wonVotes, won.Vote.ApplicationUID)
 				}
 			}
 			f.wonVotesMutex.Unlock()
@@ -331,22 +568,40 @@ func (f *Fish) voteProcessRound(vote *types.Vote) error {
 		return log.Error("Fish: Vote Fatal: Unable to find Label:", vote.UID, app.LabelUID, err)
 	}
 
+	span := f.appTrace(vote.ApplicationUID, "fish.election", tracing.Attr("label", label.Name))
+	defer span.Finish()
+
 	for {
 		startTime := time.Now()
 		log.Infof("Fish: Starting Application %s election round %d", vote.ApplicationUID, vote.Round)
 
+		// The owner roles could restrict the locations to allocate in and the quotas could be used
+		// up by the allocated Resources, checked before locking the node usage to not hold it
+		// while waiting for the DB
+		locationAllowed := f.roleLocationAllowed(app.OwnerName)
+		quotaAllowed := f.quotaElectionAllowed(app, label)
+
 		// Determine answer for this round, it will try find the first possible definition to serve
 		// We can't run multiple resources check at a time or together with
 		// allocating application so using mutex here
 		f.nodeUsageMutex.Lock()
 		vote.Available = -1 // Set "nope" answer by default in case all the definitions are not fit
-		for i, def := range label.Definitions {
-			if f.isNodeAvailableForDefinition(def) {
+		for i := range label.Definitions {
+			// Node with warm Resource in the recycle pool can serve the Application right away
+			if !f.maintenance && f.recyclePoolHas(label.UID, i) || f.isNodeAvailableForDefinition(label, i) {
 				vote.Available = i
 				break
 			}
 		}
+		// Leave the capacity for the higher priority or less served owner Application waiting for it
+		if vote.Available >= 0 && f.isPrecedingWaiting(app) {
+			vote.Available = -1
+		}
+		if vote.Available >= 0 && (!locationAllowed || !quotaAllowed) {
+			vote.Available = -1
+		}
 		f.nodeUsageMutex.Unlock()
+		span.AddEvent("round", tracing.Attr("round", vote.Round), tracing.Attr("available", vote.Available))
 
 		// Create vote if it's required
 		if vote.UID == uuid.Nil {
@@ -385,16 +640,21 @@ func (f *Fish) voteProcessRound(vote *types.Vote) error {
 					}
 					if vote.NodeUID == f.node.UID {
 						log.Info("Fish: I won the election for Application", vote.ApplicationUID)
+						span.AddEvent("won", tracing.Attr("round", vote.Round))
 						app, err := f.ApplicationGet(vote.ApplicationUID)
 						if err != nil {
 							return log.Error("Fish: Unable to get the Application:", vote.ApplicationUID, err)
 						}
 						f.wonVotesMutex.Lock()
-						f.wonVotes[app.CreatedAt.UnixMicro()] = *vote
+						f.wonVotes[app.UID] = wonVote{Vote: *vote, Priority: app.Priority, Owner: app.OwnerName, CreatedAt: app.CreatedAt}
 						f.wonVotesMutex.Unlock()
 					} else {
 						log.Infof("Fish: I lose the election for Application %s to Node %s", vote.ApplicationUID, vote.NodeUID)
+						span.AddEvent("lost", tracing.Attr("round", vote.Round), tracing.Attr("winner", vote.NodeUID.String()))
 					}
+				} else {
+					// No one can serve the Application, maybe it's worth to free some capacity
+					f.preemptIdleResource(app, label, votes)
 				}
 
 				// Wait till the next round for ELECTION_ROUND_TIME since round start
@@ -428,48 +688,46 @@ func (f *Fish) voteProcessRound(vote *types.Vote) error {
 	}
 }
 
-func (f *Fish) isNodeAvailableForDefinition(def types.LabelDefinition) bool {
+// isNodeAvailableForDefinition checks the node could allocate the Label definition right now
+func (f *Fish) isNodeAvailableForDefinition(label *types.Label, index int) bool {
 	// When node is in maintenance mode - it should not accept any Applications
 	if f.maintenance {
 		return false
 	}
 
-	// Is node supports the required label driver
-	driver := f.driverGet(def.Driver)
-	if driver == nil {
+	// The drivers and node filters are checked once per Label by compatibility index
+	if !f.labelCompatGet(label)[index] {
 		return false
 	}
 
-	// Verify node filters because some workload can't be running on all the physical nodes
-	// The node becomes fitting only when all the needed node filter patterns are matched
-	if len(def.Resources.NodeFilter) > 0 {
-		neededIdents := def.Resources.NodeFilter
-		currentIdents := f.cfg.NodeIdentifiers
-		for _, needed := range neededIdents {
-			found := false
-			for _, value := range currentIdents {
-				// We're validating the pattern on error during label creation, so they should be ok
-				if found, _ = path.Match(needed, value); found {
-					break
-				}
-			}
-			if !found {
-				// One of the required node identifiers did not matched the node ones
-				return false
-			}
-		}
-	}
-	// Here all the node filters matched the node identifiers
-
 	// Check with the driver if it's possible to allocate the Application resource
+	def := label.Definitions[index]
 	nodeUsage := f.nodeUsage
-	if capacity := driver.AvailableCapacity(nodeUsage, def); capacity < 1 {
+	if capacity := f.driverGet(def.Driver).AvailableCapacity(nodeUsage, def); capacity < 1 {
 		return false
 	}
 
 	return true
 }
 
+// capacityForecasted checks the driver expects the capacity for the definition soon enough, so
+// the Application could return to the election instead of failing
+func (f *Fish) capacityForecasted(app *types.Application, driver drivers.ResourceDriver, def types.LabelDefinition) bool {
+	if f.cfg.CapacityWait <= 0 {
+		return false
+	}
+	fc, ok := driver.(drivers.ResourceDriverForecast)
+	if !ok {
+		return false
+	}
+	at := fc.CapacityForecast(def)
+	if at.IsZero() || at.After(app.CreatedAt.Add(time.Duration(f.cfg.CapacityWait))) {
+		return false
+	}
+	log.Infof("Fish: Driver %s forecasts the capacity for Application %s at %s", driver.Name(), app.UID, at)
+	return true
+}
+
 func (f *Fish) executeApplication(vote types.Vote) error {
 	// Check the application is executed already
 	f.applicationsMutex.Lock()
@@ -519,11 +777,23 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 	}
 	labelDef := label.Definitions[vote.Available]
 
-	// The already running applications will not consume the additional resources
+	// Replacing the Image catalog references with the driver identifiers of the current versions
+	if labelDef.Options, err = f.imageResolve(labelDef.Driver, labelDef.Options, nil); err != nil {
+		f.nodeUsageMutex.Unlock()
+		return fmt.Errorf("Fish: Unable to resolve Images of Label %s for Application %s: %v", app.LabelUID, app.UID, err)
+	}
+
+	// Trying to get the warm Resource from the recycle pool, it already consumes the node resources
+	var recycled *recycledResource
 	if appState.Status == types.ApplicationStatusNEW {
+		recycled = f.recyclePoolTake(label.UID, vote.Available)
+	}
+
+	// The already running applications will not consume the additional resources
+	if appState.Status == types.ApplicationStatusNEW && recycled == nil {
 		// In case there is multiple Applications won the election process on the same node it could
 		// just have not enough resources, so skip it for now to allow the other Nodes to try again.
-		if !f.isNodeAvailableForDefinition(labelDef) {
+		if !f.isNodeAvailableForDefinition(label, vote.Available) {
 			log.Warn("Fish: Not enough resources to execute the Application", app.UID)
 			f.nodeUsageMutex.Unlock()
 			return nil
@@ -534,11 +804,17 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 	driver := f.driverGet(labelDef.Driver)
 	if driver == nil {
 		f.nodeUsageMutex.Unlock()
+		if recycled != nil {
+			// Can't happen in normal conditions, but anyway need to cleanup
+			if err := f.recyclePoolDestroy(recycled); err != nil {
+				log.Error("Fish: Unable to destroy the recycle pool Resource:", recycled.Identifier, err)
+			}
+		}
 		return fmt.Errorf("Fish: Unable to locate driver for the Application %s: %s", app.UID, labelDef.Driver)
 	}
 
 	// If the driver is not using the remote resources - we need to increase the counter
-	if !driver.IsRemote() {
+	if !driver.IsRemote() && recycled == nil {
 		f.nodeUsage.Add(labelDef.Resources)
 	}
 
@@ -558,11 +834,16 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 		if appState.Status == types.ApplicationStatusNEW {
 			// Set Application state as ELECTED
 			appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusELECTED,
-				Description: "Elected node: " + f.node.Name,
+				Description: applicationElectedPrefix + f.node.Name,
 			}
 			err := f.ApplicationStateCreate(appState)
 			if err != nil {
 				log.Error("Fish: Unable to set Application state:", app.UID, err)
+				if recycled != nil {
+					if err := f.recyclePoolDestroy(recycled); err != nil {
+						log.Error("Fish: Unable to destroy the recycle pool Resource:", recycled.Identifier, err)
+					}
+				}
 				f.applicationsMutex.Lock()
 				f.removeFromExecutingApplincations(app.UID)
 				f.applicationsMutex.Unlock()
@@ -570,6 +851,22 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 			}
 		}
 
+		// Set when the node usage of the Application is already released
+		usageReleased := false
+		// The pool Resource is not tracked anywhere else, so it's destroyed if the Application
+		// can't use it. Destroy releases the pool Resource node usage, nothing was added for the
+		// Application itself.
+		recycledDestroy := func() {
+			if recycled == nil {
+				return
+			}
+			if err := f.recyclePoolDestroy(recycled); err != nil {
+				log.Error("Fish: Unable to destroy the recycle pool Resource:", recycled.Identifier, err)
+			}
+			usageReleased = true
+			recycled = nil
+		}
+
 		// Merge application and label metadata, in this exact order
 		var mergedMetadata []byte
 		var metadata map[string]any
@@ -579,6 +876,7 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 				Description: fmt.Sprint("Unable to parse the app metadata:", err),
 			}
 			f.ApplicationStateCreate(appState)
+			recycledDestroy()
 		}
 		if err := json.Unmarshal([]byte(label.Metadata), &metadata); err != nil {
 			log.Error("Fish: Unable to parse the Label metadata:", label.UID, err)
@@ -586,6 +884,25 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 				Description: fmt.Sprint("Unable to parse the label metadata:", err),
 			}
 			f.ApplicationStateCreate(appState)
+			recycledDestroy()
+		}
+		if app.DependsInject {
+			f.applicationDependsInject(app, metadata)
+		}
+		if metadata != nil {
+			metadata[drivers.MetadataSeed] = strconv.FormatInt(app.Seed, 10)
+			metadata[drivers.MetadataNode] = f.node.UID.String()
+			metadata[drivers.MetadataApplication] = app.UID.String()
+		}
+		if appState.Status == types.ApplicationStatusELECTED {
+			if err := f.vaultExtract(app, metadata); err != nil {
+				log.Error("Fish: Unable to move the bootstrap secrets to vault:", app.UID, err)
+				appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusERROR,
+					Description: fmt.Sprint("Unable to move bootstrap secrets to vault:", err),
+				}
+				f.ApplicationStateCreate(appState)
+				recycledDestroy()
+			}
 		}
 		if mergedMetadata, err = json.Marshal(metadata); err != nil {
 			log.Error("Fish: Unable to merge metadata:", label.UID, err)
@@ -593,6 +910,7 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 				Description: fmt.Sprint("Unable to merge metadata:", err),
 			}
 			f.ApplicationStateCreate(appState)
+			recycledDestroy()
 		}
 
 		// Get or create the new resource object
@@ -601,7 +919,7 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 			NodeUID:        f.node.UID,
 			Metadata:       util.UnparsedJSON(mergedMetadata),
 		}
-		if appState.Status == types.ApplicationStatusALLOCATED {
+		if appState.Status == types.ApplicationStatusALLOCATED || appState.Status == types.ApplicationStatusHOLD {
 			res, err = f.ResourceGetByApplication(app.UID)
 			if err != nil {
 				log.Error("Fish: Unable to get the allocated Resource for Application:", app.UID, err)
@@ -612,16 +930,77 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 			}
 		}
 
+		// Estimate the Resource cost to check the owner budget before allocation
+		if appState.Status == types.ApplicationStatusELECTED {
+			res.HourlyCost = f.definitionHourlyCost(labelDef)
+			if err := f.quotaBudgetCheck(app, res.HourlyCost); err != nil {
+				log.Warn("Fish: Unable to allocate resource for the Application:", app.UID, err)
+				appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusERROR,
+					Description: fmt.Sprint("Budget check error:", err),
+				}
+				f.ApplicationStateCreate(appState)
+				recycledDestroy()
+				if err := f.vaultDeleteByApplication(app.UID); err != nil {
+					log.Error("Fish: Unable to wipe the vault secrets of the Application:", app.UID, err)
+				}
+			}
+		}
+
 		// Allocate the resource
 		if appState.Status == types.ApplicationStatusELECTED {
-			// Run the allocation
-			log.Infof("Fish: Allocate the Application %s resource using driver: %s", app.UID, driver.Name())
-			drvRes, err := driver.Allocate(labelDef, metadata)
-			if err != nil {
+			var drvRes *types.Resource
+			var err error
+			if recycled != nil {
+				log.Infof("Fish: Reusing the recycled Resource %s for the Application %s", recycled.Identifier, app.UID)
+				drvRes = &types.Resource{
+					ApplicationUID: app.UID,
+					Identifier:     recycled.Identifier,
+					HwAddr:         recycled.HwAddr,
+					IpAddr:         recycled.IpAddr,
+					Authentication: recycled.Authentication,
+					DriverInfo:     recycled.DriverInfo,
+					Metadata:       res.Metadata,
+					Reused:         recycled.Reused,
+				}
+				// Delivering the new Application metadata to the Resource
+				if err := f.recycleReuse(recycled, drvRes); err != nil {
+					log.Errorf("Fish: Unable to reuse the Resource for the Application %s, allocating new one: %v", app.UID, err)
+					if err := f.recyclePoolDestroy(recycled); err != nil {
+						log.Error("Fish: Unable to destroy the recycle pool Resource:", recycled.Identifier, err)
+					}
+					// Pool Resource was consuming the node resources, so the new one takes them
+					if !driver.IsRemote() {
+						f.nodeUsageMutex.Lock()
+						f.nodeUsage.Add(labelDef.Resources)
+						f.nodeUsageMutex.Unlock()
+					}
+					recycled = nil
+				}
+			}
+			if recycled == nil {
+				// Run the allocation
+				log.Infof("Fish: Allocate the Application %s resource using driver: %s", app.UID, driver.Name())
+				span := f.appTrace(app.UID, "driver.Allocate", tracing.Attr("driver", driver.Name()))
+				drvRes, err = driver.Allocate(labelDef, metadata)
+				span.SetError(err)
+				span.Finish()
+				if err == nil {
+					recycled = &recycledResource{AllocatedAt: time.Now()}
+				}
+			}
+			if err != nil && f.capacityForecasted(app, driver, labelDef) {
+				log.Warn("Fish: Unable to allocate resource for the Application, waiting for capacity:", app.UID, err)
+				appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusNEW,
+					Description: fmt.Sprint("Waiting for the driver capacity after allocate error:", err),
+				}
+			} else if err != nil {
 				log.Error("Fish: Unable to allocate resource for the Application:", app.UID, err)
 				appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusERROR,
 					Description: fmt.Sprint("Driver allocate resource error:", err),
 				}
+				if err := f.vaultDeleteByApplication(app.UID); err != nil {
+					log.Error("Fish: Unable to wipe the vault secrets of the Application:", app.UID, err)
+				}
 			} else {
 				res.Identifier = drvRes.Identifier
 				res.HwAddr = drvRes.HwAddr
@@ -629,6 +1008,14 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 				res.LabelUID = label.UID
 				res.DefinitionIndex = vote.Available
 				res.Authentication = drvRes.Authentication
+				res.DriverInfo = drvRes.DriverInfo
+				if err := drivers.ValidateInfo(driver.InfoSchema(), res.DriverInfo); err != nil {
+					// The Resource is already allocated so not failing, just not trusting the info
+					log.Errorf("Fish: Driver %s returned invalid info for the Application %s: %v", driver.Name(), app.UID, err)
+					res.DriverInfo = util.UnparsedJSON("{}")
+				}
+				res.Reused = drvRes.Reused
+				res.Tasks = drivers.SupportedTasks(driver)
 				err := f.ResourceCreate(res)
 				if err != nil {
 					log.Error("Fish: Unable to store Resource for Application:", app.UID, err)
@@ -637,36 +1024,59 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 					Description: "Driver allocated the resource",
 				}
 				log.Infof("Fish: Allocated Resource %q for the Application %s", app.UID, res.Identifier)
+				f.nodeHistoryEvent(HistoryNodeResourceAllocated, fmt.Sprintf("Application %s Resource %s by %s", app.UID, res.Identifier, driver.Name()))
+				metricAllocateLatency.Observe(time.Since(app.CreatedAt).Seconds(), driver.Name())
 			}
 			f.ApplicationStateCreate(appState)
 		}
 
 		// Getting the resource lifetime to know how much time it will live
-		resourceLifetime, err := time.ParseDuration(labelDef.Resources.Lifetime)
-		if labelDef.Resources.Lifetime != "" && err != nil {
+		resourceLifetime, err := f.resourceLifetimeGet(&labelDef)
+		if err != nil {
 			log.Error("Fish: Can't parse the Lifetime from Label Definition:", label.UID, res.DefinitionIndex)
 		}
+		resourceTimeout := res.CreatedAt.Add(resourceLifetime)
+		idle, err := newResourceIdle(&labelDef)
 		if err != nil {
-			// Try to get default value from fish config
-			resourceLifetime, err = time.ParseDuration(f.cfg.DefaultResourceLifetime)
-			if err != nil {
-				// Not an error - in worst case the resource will just sit there but at least will
-				// not ruin the workload execution
-				log.Warn("Fish: Default Resource Lifetime is not set in fish config")
-			}
+			log.Error("Fish: Can't parse the IdleTimeout from Label Definition:", label.UID, res.DefinitionIndex, err)
 		}
-		resourceTimeout := res.CreatedAt.Add(resourceLifetime)
-		if appState.Status == types.ApplicationStatusALLOCATED {
+		health, err := newResourceHealth(&labelDef, res)
+		if err != nil {
+			log.Error("Fish: Can't parse the HealthCheck from Label Definition:", label.UID, res.DefinitionIndex, err)
+		}
+		if appState.Status == types.ApplicationStatusALLOCATED || appState.Status == types.ApplicationStatusHOLD {
 			if resourceLifetime > 0 {
 				log.Infof("Fish: Resource of Application %s will be deallocated by timeout in %s (%s)", app.UID, resourceLifetime, resourceTimeout)
 			} else {
 				log.Warn("Fish: Resource have no lifetime set and will live until deallocated by user:", app.UID)
 			}
+			// Receiving the Resource SSH host key in background to publish it to the users
+			if res.HostKeyFingerprint == "" {
+				go f.resourceProbeHostKeyProcess(res.UID)
+			}
 		}
 
+		// Filling the recycle info to be able to return the Resource to the pool
+		if recycled == nil {
+			// The Resource was allocated before the node restart so don't know when exactly
+			recycled = &recycledResource{AllocatedAt: res.CreatedAt}
+		}
+		recycled.Driver = driver
+		recycled.Definition = labelDef
+		recycled.LabelUID = label.UID
+		recycled.DefinitionIndex = res.DefinitionIndex
+		recycled.Identifier = res.Identifier
+		recycled.HwAddr = res.HwAddr
+		recycled.IpAddr = res.IpAddr
+		recycled.Authentication = res.Authentication
+		recycled.DriverInfo = res.DriverInfo
+		isRecycled := false
+
 		// Run the loop to wait for deallocate request
 		var deallocateRetry uint8 = 1
-		for appState.Status == types.ApplicationStatusALLOCATED {
+		var hold *approvalHold
+		expiryNotified := false
+		for appState.Status == types.ApplicationStatusALLOCATED || appState.Status == types.ApplicationStatusHOLD {
 			if !f.running {
 				log.Info("Fish: Stopping the Application execution:", app.UID)
 				return
@@ -676,18 +1086,74 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 				log.Error("Fish: Unable to get Status for Application:", app.UID, err)
 			}
 
+			// The node is shutting down and the other one of the location continues to serve the
+			// Resource, the driver is remote so the node usage is not affected
+			if appState.Status == types.ApplicationStatusALLOCATED && f.resourceHandover(app.UID, res) {
+				log.Info("Fish: Done executing Application, it was passed to the other node", app.UID)
+				return
+			}
+
+			// The owner requested to extend the lifetime, the latest state contains the new deadline
+			if appState.ExpiresAt != nil && appState.ExpiresAt.After(resourceTimeout) {
+				log.Infof("Fish: Resource lifetime of Application %s is extended till %s", app.UID, appState.ExpiresAt)
+				resourceTimeout = *appState.ExpiresAt
+				expiryNotified = false
+			}
+
 			// Check if it's life timeout for the resource
-			if resourceLifetime > 0 {
+			if resourceLifetime > 0 && appState.Status == types.ApplicationStatusALLOCATED {
+				// Letting the owner know to save the work or to request another Resource in advance
+				if !expiryNotified && time.Until(resourceTimeout) < time.Duration(f.cfg.Notifications.ExpiryNotice) {
+					expiryNotified = true
+					f.notifyApplication(notify.EventLifetimeExpiring, app,
+						fmt.Sprintf("Resource lifetime expires at %s", resourceTimeout.Format(time.RFC3339)))
+				}
 				// The time limit is set - so let's use resource create time and find out timeout
+				if resourceTimeout.Before(time.Now()) {
+					// Not cutting off the user in the middle of work, if it's possible
+					resourceTimeout = f.resourceLifetimeExtend(app, res, resourceTimeout, resourceLifetime)
+				}
 				if resourceTimeout.Before(time.Now()) {
 					// Seems the timeout has come, so fish asks for application deallocate
-					appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusDEALLOCATE,
-						Description: fmt.Sprint("Resource lifetime timeout reached:", resourceLifetime),
+					if f.ApplicationNeedsApproval(app) {
+						if newState, err := f.ApplicationDeallocate(app, "fish"); err != nil {
+							log.Error("Fish: Unable to deallocate Application by timeout:", app.UID, err)
+						} else {
+							appState = newState
+						}
+					} else {
+						appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusDEALLOCATE,
+							Description: fmt.Sprint("Resource lifetime timeout reached:", resourceLifetime),
+						}
+						f.ApplicationStateCreate(appState)
 					}
-					f.ApplicationStateCreate(appState)
 				}
 			}
 
+			// Releasing the forgotten Resource no one uses
+			if idle != nil && appState.Status == types.ApplicationStatusALLOCATED {
+				if newState := idle.check(f, app, res); newState != nil {
+					appState = newState
+				}
+			}
+
+			// Noticing the dead Resource and replacing it if the Label wants to
+			if health != nil && appState.Status == types.ApplicationStatusALLOCATED {
+				if newState := health.run(f, driver, app, res); newState != nil {
+					appState = newState
+				}
+			}
+
+			// Deallocate of the regulated Application waits for approval
+			if appState.Status == types.ApplicationStatusHOLD {
+				if hold == nil {
+					hold = &approvalHold{}
+				}
+				f.processApprovalHold(hold, app, appState)
+			} else {
+				hold = nil
+			}
+
 			// Execute the existing ApplicationTasks. It will be executed during ALLOCATED or prior
 			// to executing deallocation by DEALLOCATE & RECALLED which right now is useful for
 			// `snapshot` and `image` tasks.
@@ -695,8 +1161,15 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 
 			if appState.Status == types.ApplicationStatusDEALLOCATE || appState.Status == types.ApplicationStatusRECALLED {
 				log.Info("Fish: Running Deallocate of the Application and Resource:", app.UID, res.Identifier)
-				// Deallocating and destroy the resource
-				if err := driver.Deallocate(res); err != nil {
+				// User should not be able to reach the Resource during cleanup or destroy
+				f.resourceSessionsRevoke(res.UID)
+				// Returning the resource to the recycle pool if the Label allows or destroying it
+				if labelDef.Recycle != nil && f.recycleResource(recycled, res) {
+					isRecycled = true
+					appState = &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusDEALLOCATED,
+						Description: "Resource returned to the recycle pool",
+					}
+				} else if err := f.driverDeallocateTraced(driver, res); err != nil {
 					log.Errorf("Fish: Unable to deallocate the Resource of Application: %s (try: %d): %v", app.UID, deallocateRetry, err)
 					// Let's retry to deallocate the resource 10 times before give up
 					if deallocateRetry <= 10 {
@@ -713,6 +1186,7 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 						Description: "Driver deallocated the resource",
 					}
 				}
+				f.nodeHistoryEvent(HistoryNodeResourceDeallocated, fmt.Sprintf("Application %s Resource %s: %s", app.UID, res.Identifier, appState.Description))
 				// Destroying the resource anyway to not bloat the table - otherwise it will stuck there and
 				// will block the access to IP of the other VM's that will reuse this IP
 				if err := f.ResourceDelete(res.UID); err != nil {
@@ -726,8 +1200,8 @@ func (f *Fish) executeApplication(vote types.Vote) error {
 
 		f.applicationsMutex.Lock()
 		{
-			// Decrease the amout of running local apps
-			if !driver.IsRemote() {
+			// Decrease the amout of running local apps, recycled Resource still consumes them
+			if !driver.IsRemote() && !isRecycled && !usageReleased {
 				f.nodeUsageMutex.Lock()
 				f.nodeUsage.Subtract(labelDef.Resources)
 				f.nodeUsageMutex.Unlock()
@@ -751,32 +1225,81 @@ func (f *Fish) executeApplicationTasks(drv drivers.ResourceDriver, def *types.La
 		return log.Error("Fish: Unable to get ApplicationTasks:", res.ApplicationUID, err)
 	}
 	for _, task := range tasks {
-		// Skipping already executed task
-		if task.Result != "{}" {
+		periodic := task.Interval != nil && *task.Interval != ""
+		// Skipping already executed task, the periodic one is executed until the Resource is gone
+		if task.Result != "{}" && !periodic {
+			continue
+		}
+		// The teardown tasks are not waiting for the schedule since the Resource will be gone
+		if appStatus == types.ApplicationStatusALLOCATED && task.At != nil && time.Now().Before(*task.At) {
 			continue
 		}
 		t := drv.GetTask(task.Task, string(task.Options))
 		if t == nil {
 			log.Error("Fish: Unable to get associated driver task type for Application:", res.ApplicationUID, task.Task)
 			task.Result = util.UnparsedJSON(`{"error":"task not available in driver"}`)
+			// No reason to repeat the task the driver doesn't have
+			task.Interval = nil
 		} else {
 			// Executing the task
 			t.SetInfo(&task, def, res)
-			result, err := t.Execute()
-			if err != nil {
-				// We're not crashing here because even with error task could have a result
-				log.Error("Fish: Error happened during executing the task:", task.UID, err)
+			if to, ok := t.(drivers.ResourceDriverTaskOutput); ok {
+				info := task
+				to.SetOutput(func(line string) {
+					f.applicationEventCurrent(info.ApplicationUID, types.ApplicationStateEvent{Task: &info, TaskOutput: &line})
+				})
 			}
-			task.Result = util.UnparsedJSON(result)
+			task.Result = util.UnparsedJSON(f.executeApplicationTaskRetry(t, &task, res, appStatus))
+		}
+		if task.Interval != nil && *task.Interval != "" {
+			interval, _ := time.ParseDuration(*task.Interval)
+			next := time.Now().Add(interval)
+			task.At = &next
 		}
 		if err := f.ApplicationTaskSave(&task); err != nil {
 			log.Error("Fish: Error during update the task with result:", task.UID, err)
 		}
+		done := task
+		f.applicationEventCurrent(task.ApplicationUID, types.ApplicationStateEvent{Task: &done})
 	}
 
 	return nil
 }
 
+// executeApplicationTaskRetry runs the driver task and repeats it on failure as much as the
+// ApplicationTask allows, the result of the last attempt is returned
+func (f *Fish) executeApplicationTaskRetry(t drivers.ResourceDriverTask, task *types.ApplicationTask, res *types.Resource, appStatus types.ApplicationStatus) []byte {
+	retries := 0
+	if task.Retries != nil {
+		retries = *task.Retries
+	}
+	for attempt := 0; ; attempt++ {
+		span := f.appTrace(res.ApplicationUID, "driver.Task", tracing.Attr("task", task.Task), tracing.Attr("when", string(appStatus)))
+		result, err := t.Execute()
+		span.SetError(err)
+		span.Finish()
+		if err == nil {
+			return result
+		}
+		if attempt >= retries {
+			// We're not crashing here because even with error task could have a result
+			log.Error("Fish: Error happened during executing the task:", task.UID, err)
+			return result
+		}
+		log.Warnf("Fish: Error happened during executing the task %s, retrying (%d/%d): %v", task.UID, attempt+1, retries, err)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// driverDeallocateTraced runs the driver Deallocate in the Application trace
+func (f *Fish) driverDeallocateTraced(driver drivers.ResourceDriver, res *types.Resource) error {
+	span := f.appTrace(res.ApplicationUID, "driver.Deallocate", tracing.Attr("driver", driver.Name()))
+	defer span.Finish()
+	err := driver.Deallocate(res)
+	span.SetError(err)
+	return err
+}
+
 func (f *Fish) removeFromExecutingApplincations(appUID types.ApplicationUID) {
 	for i, uid := range f.applications {
 		if uid != appUID {
@@ -826,6 +1349,25 @@ func (f *Fish) MaintenanceSet(value bool) {
 	}
 
 	f.maintenance = value
+
+	// Keep the node record in sync to show the state to the cluster
+	if f.node != nil && f.node.Maintenance != value {
+		if value {
+			f.nodeHistoryEvent(HistoryNodeMaintenanceEnabled, fmt.Sprintf("Drain: %v", f.node.Drain))
+		} else {
+			f.nodeHistoryEvent(HistoryNodeMaintenanceDisabled, "")
+		}
+		f.node.Maintenance = value
+		f.node.DrainStartedAt = time.Time{}
+		if value {
+			f.node.DrainStartedAt = time.Now()
+		} else {
+			f.node.Drain = false
+		}
+		if err := f.nodeMaintenanceSave(f.node); err != nil {
+			log.Error("Fish:", err)
+		}
+	}
 }
 
 // ShutdownSet tells node it need to execute graceful shutdown operation
@@ -836,10 +1378,25 @@ func (f *Fish) ShutdownSet(value bool) {
 		} else {
 			log.Info("Fish: Disabled shutdown mode")
 			f.shutdownCancel <- true
+			// The Resources not passed yet stay on this node
+			f.applicationsMutex.Lock()
+			clear(f.handovers)
+			f.applicationsMutex.Unlock()
 		}
 	}
 
 	f.shutdown = value
+
+	// Keep the node record in sync to show the state to the cluster
+	if f.node != nil && f.node.Shutdown != value {
+		if value {
+			f.nodeHistoryEvent(HistoryNodeShutdown, fmt.Sprintf("Delay: %v", f.shutdownDelay))
+		}
+		f.node.Shutdown = value
+		if err := f.nodeMaintenanceSave(f.node); err != nil {
+			log.Error("Fish:", err)
+		}
+	}
 }
 
 // ShutdownDelaySet set of how much time to wait before executing the node shutdown operation
@@ -903,6 +1460,11 @@ func (f *Fish) activateShutdown() {
 			tickerReport := time.NewTicker(30 * time.Second)
 			defer tickerReport.Stop()
 
+			// The Resources of the remote drivers could be served by the other nodes
+			if f.node.Handover {
+				f.resourcesHandover()
+			}
+
 			for {
 				select {
 				case <-f.shutdownCancel:
@@ -916,6 +1478,10 @@ func (f *Fish) activateShutdown() {
 					}
 				case <-tickerReport.C:
 					log.Info("Fish: Shutdown: waiting for running Applications:", len(f.applications))
+					// The target nodes could become available later
+					if f.node.Handover {
+						f.resourcesHandover()
+					}
 				}
 			}
 		}()
@@ -0,0 +1,148 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// HandoverSet enables the handover of the remote drivers Resources to the other Nodes of the same
+// location during shutdown instead of waiting for their lifetime
+func (f *Fish) HandoverSet(value bool) {
+	if f.node == nil || f.node.Handover == value {
+		return
+	}
+	log.Info("Fish: Resources handover on shutdown is set to:", value)
+	f.node.Handover = value
+	if err := f.nodeMaintenanceSave(f.node); err != nil {
+		log.Error("Fish:", err)
+	}
+}
+
+// handoverTargets returns the active Nodes of the same location which are able to continue
+// serving the Resources of the driver
+func (f *Fish) handoverTargets(driver string) []types.Node {
+	nodes, err := f.NodeActiveList()
+	if err != nil {
+		log.Error("Fish: Handover: Unable to list the active nodes:", err)
+		return nil
+	}
+	var out []types.Node
+	for _, node := range nodes {
+		if node.UID == f.node.UID || node.LocationName != f.node.LocationName || node.Maintenance || node.Shutdown {
+			continue
+		}
+		// The target Node reports the health of its drivers in telemetry
+		if node.Telemetry == nil || !slices.ContainsFunc(node.Telemetry.Drivers, func(d types.DriverTelemetry) bool {
+			return d.Name == driver && d.Healthy
+		}) {
+			continue
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// resourcesHandover chooses the target Nodes for the allocated Resources of the remote drivers,
+// the executing Applications are passing the Resources on the next check. The Resources of the
+// local drivers are not touched and stay till the end of their lifetime.
+func (f *Fish) resourcesHandover() {
+	rs, err := f.ResourceListNode(f.node.UID)
+	if err != nil {
+		log.Error("Fish: Handover: Unable to list the node Resources:", err)
+		return
+	}
+	targets := map[string][]types.Node{}
+	assigned := 0
+	for _, res := range rs {
+		if f.ApplicationIsAllocated(res.ApplicationUID) != nil {
+			continue
+		}
+		label, err := f.LabelGet(res.LabelUID)
+		if err != nil || len(label.Definitions) <= res.DefinitionIndex {
+			continue
+		}
+		name := label.Definitions[res.DefinitionIndex].Driver
+		if drv := f.driverGet(name); drv == nil || !drv.IsRemote() {
+			continue
+		}
+		if _, ok := targets[name]; !ok {
+			targets[name] = f.handoverTargets(name)
+		}
+		if len(targets[name]) == 0 {
+			continue
+		}
+		// Spreading the Resources across the available Nodes
+		node := targets[name][assigned%len(targets[name])]
+		f.applicationsMutex.Lock()
+		f.handovers[res.ApplicationUID] = node
+		f.applicationsMutex.Unlock()
+		assigned++
+	}
+	if assigned > 0 {
+		log.Infof("Fish: Handover: Passing %d Resources to the other nodes", assigned)
+	}
+}
+
+// resourceHandover passes the Resource to the chosen Node if the handover was requested, returns
+// true if the Resource is not served by this Node anymore
+func (f *Fish) resourceHandover(appUID types.ApplicationUID, res *types.Resource) bool {
+	f.applicationsMutex.Lock()
+	defer f.applicationsMutex.Unlock()
+	node, ok := f.handovers[appUID]
+	if !ok {
+		return false
+	}
+	delete(f.handovers, appUID)
+
+	if err := f.db.Model(res).Update("node_uid", node.UID).Error; err != nil {
+		log.Errorf("Fish: Handover: Unable to pass the Resource of Application %s to node %s: %v", appUID, node.Name, err)
+		return false
+	}
+	log.Infof("Fish: Handover: Resource of Application %s is passed to node %s", appUID, node.Name)
+	f.nodeHistoryEvent(HistoryNodeResourceHandover, fmt.Sprintf("Application %s Resource %s to node %s", appUID, res.Identifier, node.Name))
+	f.removeFromExecutingApplincations(appUID)
+	return true
+}
+
+// handoverProcess picks up the Resources passed to this node by the other ones to continue serving
+// them, the Resources found on startup are executed by Init
+func (f *Fish) handoverProcess() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		rs, err := f.ResourceListNode(f.node.UID)
+		if err != nil {
+			log.Error("Fish: Handover: Unable to list the node Resources:", err)
+			continue
+		}
+		for _, res := range rs {
+			if !res.UpdatedAt.After(f.startedAt) || f.ApplicationIsAllocated(res.ApplicationUID) != nil {
+				continue
+			}
+			// Executing Applications are skipped by executeApplication, so only the new ones are started
+			vote := types.Vote{ApplicationUID: res.ApplicationUID, NodeUID: f.node.UID, Available: res.DefinitionIndex}
+			if err := f.executeApplication(vote); err != nil {
+				log.Errorf("Fish: Handover: Can't execute Application %s: %v", res.ApplicationUID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// HealthRequester is used as the deallocate requester when the unhealthy Resource is recreated
+const HealthRequester = "fish-health"
+
+// Types of the Label definition health check
+const (
+	HealthCheckTCP    = "tcp"
+	HealthCheckSSH    = "ssh"
+	HealthCheckScript = "script"
+)
+
+// resourceHealth keeps the health check state of the executing Application
+type resourceHealth struct {
+	check    *types.HealthCheck
+	interval time.Duration
+	timeout  time.Duration
+	failures int
+
+	nextAt time.Time
+	failed int
+}
+
+// healthCheckValidate makes sure the health check could be executed for the driver
+func (f *Fish) healthCheckValidate(driverName string, hc *types.HealthCheck) error {
+	switch hc.Type {
+	case HealthCheckTCP:
+		if hc.Port < 1 || hc.Port > 65535 {
+			return fmt.Errorf("Port is out of range: %d", hc.Port)
+		}
+	case HealthCheckSSH:
+		if hc.Port < 0 || hc.Port > 65535 {
+			return fmt.Errorf("Port is out of range: %d", hc.Port)
+		}
+	case HealthCheckScript:
+		if hc.Script == "" {
+			return fmt.Errorf("Script can't be empty")
+		}
+		// Only the node with the driver could check it's able to run the script
+		if drv := f.driverGet(driverName); drv != nil {
+			if s, ok := drv.(*supervisedDriver); ok {
+				drv = s.unwrap()
+			}
+			if _, ok := drv.(drivers.ResourceDriverHealthCheck); !ok {
+				return fmt.Errorf("Driver %s is not able to run the script", driverName)
+			}
+		}
+	default:
+		return fmt.Errorf("Unknown type: %q", hc.Type)
+	}
+	for name, value := range map[string]string{"Interval": hc.Interval, "Timeout": hc.Timeout, "StartPeriod": hc.StartPeriod} {
+		if _, err := time.ParseDuration(value); value != "" && err != nil {
+			return fmt.Errorf("%s parse error: %v", name, err)
+		}
+	}
+	if hc.Failures < 0 {
+		return fmt.Errorf("Failures can't be negative")
+	}
+	return nil
+}
+
+// newResourceHealth prepares the Label definition health check, nil is returned if it's not set
+func newResourceHealth(def *types.LabelDefinition, res *types.Resource) (*resourceHealth, error) {
+	if def.HealthCheck == nil {
+		return nil, nil
+	}
+	rh := &resourceHealth{
+		check:    def.HealthCheck,
+		interval: 30 * time.Second,
+		timeout:  10 * time.Second,
+		failures: 3,
+	}
+	var err error
+	if def.HealthCheck.Interval != "" {
+		if rh.interval, err = time.ParseDuration(def.HealthCheck.Interval); err != nil {
+			return nil, fmt.Errorf("Fish: Can't parse the HealthCheck Interval: %v", err)
+		}
+	}
+	if def.HealthCheck.Timeout != "" {
+		if rh.timeout, err = time.ParseDuration(def.HealthCheck.Timeout); err != nil {
+			return nil, fmt.Errorf("Fish: Can't parse the HealthCheck Timeout: %v", err)
+		}
+	}
+	startPeriod := rh.interval
+	if def.HealthCheck.StartPeriod != "" {
+		if startPeriod, err = time.ParseDuration(def.HealthCheck.StartPeriod); err != nil {
+			return nil, fmt.Errorf("Fish: Can't parse the HealthCheck StartPeriod: %v", err)
+		}
+	}
+	if def.HealthCheck.Failures > 0 {
+		rh.failures = def.HealthCheck.Failures
+	}
+	rh.nextAt = res.CreatedAt.Add(startPeriod)
+	return rh, nil
+}
+
+// run executes the health check when it's time, marks the Resource unhealthy after the configured
+// failures in a row and returns the new Application state if it was deallocated to recreate
+func (rh *resourceHealth) run(f *Fish, drv drivers.ResourceDriver, app *types.Application, res *types.Resource) *types.ApplicationState {
+	if time.Now().Before(rh.nextAt) {
+		return nil
+	}
+	rh.nextAt = time.Now().Add(rh.interval)
+
+	// The Resource IP could be updated after allocation, so using the current one
+	cur, err := f.ResourceGet(res.UID)
+	if err != nil {
+		log.Error("Fish: Unable to get the Resource to check health:", app.UID, err)
+		return nil
+	}
+	if err = rh.probe(drv, cur); err == nil {
+		rh.failed = 0
+		if cur.Unhealthy {
+			log.Info("Fish: Resource of Application is healthy again:", app.UID)
+			cur.Unhealthy = false
+			if err := f.ResourceSave(cur); err != nil {
+				log.Error("Fish: Unable to save the Resource health:", app.UID, err)
+			}
+		}
+		return nil
+	}
+
+	rh.failed++
+	log.Debugf("Fish: Health check of Application %s failed (%d/%d): %v", app.UID, rh.failed, rh.failures, err)
+	if rh.failed < rh.failures || cur.Unhealthy {
+		return nil
+	}
+
+	log.Warnf("Fish: Resource of Application %s is unhealthy: %v", app.UID, err)
+	cur.Unhealthy = true
+	if err := f.ResourceSave(cur); err != nil {
+		log.Error("Fish: Unable to save the Resource health:", app.UID, err)
+	}
+	f.notifyApplication(notify.EventResourceUnhealthy, app,
+		fmt.Sprintf("Resource health check failed %d times in a row: %v", rh.failed, err))
+	if !rh.check.Recreate {
+		return nil
+	}
+
+	log.Infof("Fish: AUDIT: Deallocating unhealthy Resource of Application %s to recreate it", app.UID)
+	state, err := f.ApplicationDeallocate(app, HealthRequester)
+	if err != nil {
+		log.Errorf("Fish: Unable to deallocate unhealthy Application %s: %v", app.UID, err)
+		return nil
+	}
+	f.applicationRecreate(app)
+	return state
+}
+
+// probe executes the health check against the Resource
+func (rh *resourceHealth) probe(drv drivers.ResourceDriver, res *types.Resource) error {
+	if rh.check.Type == HealthCheckScript {
+		hc, ok := drv.(drivers.ResourceDriverHealthCheck)
+		if !ok {
+			return fmt.Errorf("Fish: Driver %s is not able to run the health check script", drv.Name())
+		}
+		return hc.HealthCheck(res, rh.check.Script, rh.timeout)
+	}
+
+	if res.IpAddr == "" {
+		return fmt.Errorf("Fish: Resource IP address is unknown")
+	}
+	port := rh.check.Port
+	if port == 0 {
+		port = 22
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(res.IpAddr, strconv.Itoa(port)), rh.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if rh.check.Type == HealthCheckTCP {
+		return nil
+	}
+
+	// SSH server sends the identification string right after the connection is established
+	if err = conn.SetReadDeadline(time.Now().Add(rh.timeout)); err != nil {
+		return err
+	}
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to read SSH banner: %v", err)
+	}
+	if !strings.HasPrefix(banner, "SSH-") {
+		return fmt.Errorf("Fish: Received banner is not SSH: %.32q", banner)
+	}
+	return nil
+}
+
+// applicationRecreate creates the new Application with the same Label, metadata and priority to
+// replace the unhealthy one
+func (f *Fish) applicationRecreate(app *types.Application) {
+	newApp := &types.Application{
+		OwnerName: app.OwnerName,
+		LabelUID:  app.LabelUID,
+		Metadata:  app.Metadata,
+		Priority:  app.Priority,
+	}
+	if err := f.ApplicationCreate(context.Background(), newApp); err != nil {
+		log.Errorf("Fish: Unable to recreate unhealthy Application %s: %v", app.UID, err)
+		return
+	}
+	log.Infof("Fish: AUDIT: Unhealthy Application %s is recreated as %s", app.UID, newApp.UID)
+}
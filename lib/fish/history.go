@@ -0,0 +1,114 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// HistoryObjectNode is the object type of the Node history events
+const HistoryObjectNode = "Node"
+
+// Events of the Node history
+const (
+	HistoryNodeStarted             = "started"
+	HistoryNodeStopped             = "stopped"
+	HistoryNodeMaintenanceEnabled  = "maintenance_enabled"
+	HistoryNodeMaintenanceDisabled = "maintenance_disabled"
+	HistoryNodeShutdown            = "shutdown"
+	HistoryNodeResourceAllocated   = "resource_allocated"
+	HistoryNodeResourceDeallocated = "resource_deallocated"
+	HistoryNodeResourceHandover    = "resource_handover"
+	HistoryNodeReconciled          = "reconciled"
+	HistoryNodeWarning             = "warning"
+	HistoryNodeRecovered           = "recovered"
+)
+
+// historyEvent records the event of the object history, the failure is not critical for the
+// operation so it's only logged
+func (f *Fish) historyEvent(objectType, objectID, event, description string) {
+	e := &types.HistoryEvent{
+		UID:         f.NewUID(),
+		ObjectType:  objectType,
+		ObjectId:    objectID,
+		Event:       event,
+		Description: description,
+	}
+	if err := f.db.Create(e).Error; err != nil {
+		log.Errorf("Fish: Unable to record %s %s history event %s: %v", objectType, objectID, event, err)
+	}
+}
+
+// nodeHistoryEvent records the event of this Node history
+func (f *Fish) nodeHistoryEvent(event, description string) {
+	if f.node == nil {
+		return
+	}
+	f.historyEvent(HistoryObjectNode, f.node.UID.String(), event, description)
+}
+
+// HistoryEventFind returns the history events of the object ordered by time
+func (f *Fish) HistoryEventFind(objectType, objectID string) (es []types.HistoryEvent, err error) {
+	err = f.db.Where("object_type = ? AND object_id = ?", objectType, objectID).Order("created_at").Find(&es).Error
+	return es, err
+}
+
+// historyRetentionProcess periodically removes the history older than the per-type retention
+func (f *Fish) historyRetentionProcess() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		if f.cfg.HistoryRetention.Node > 0 {
+			cutoff := time.Now().Add(-time.Duration(f.cfg.HistoryRetention.Node))
+			result := f.db.Where("object_type = ? AND created_at < ?", HistoryObjectNode, cutoff).Delete(&types.HistoryEvent{})
+			if result.Error != nil {
+				log.Error("Fish: Unable to remove outdated Node history:", result.Error)
+			} else if result.RowsAffected > 0 {
+				log.Infof("Fish: Removed %d outdated Node history events", result.RowsAffected)
+			}
+		}
+		if f.cfg.HistoryRetention.Application > 0 {
+			f.historyApplicationPrune(time.Now().Add(-time.Duration(f.cfg.HistoryRetention.Application)))
+		}
+	}
+}
+
+// historyApplicationPrune removes the intermediate states and the tasks of the Applications
+// finished before cutoff, the final state is kept to not change the Application status
+func (f *Fish) historyApplicationPrune(cutoff time.Time) {
+	const latest = "created_at = (SELECT max(s.created_at) FROM application_states s WHERE s.application_uid = application_states.application_uid)"
+	finished := f.db.Model(&types.ApplicationState{}).Select("application_uid").
+		Where(latest).
+		Where("status IN ?", []types.ApplicationStatus{types.ApplicationStatusDEALLOCATED, types.ApplicationStatusERROR}).
+		Where("created_at < ?", cutoff)
+
+	result := f.db.Where("application_uid IN (?)", finished).Delete(&types.ApplicationTask{})
+	if result.Error != nil {
+		log.Error("Fish: Unable to remove outdated ApplicationTasks:", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Infof("Fish: Removed %d outdated ApplicationTasks", result.RowsAffected)
+	}
+
+	result = f.db.Where("application_uid IN (?)", finished).Where("NOT " + latest).Delete(&types.ApplicationState{})
+	if result.Error != nil {
+		log.Error("Fish: Unable to remove outdated ApplicationStates:", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Infof("Fish: Removed %d outdated ApplicationStates", result.RowsAffected)
+	}
+}
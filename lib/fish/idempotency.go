@@ -0,0 +1,130 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// ErrIdempotencyInProgress is returned when the request with the same key is still processed
+var ErrIdempotencyInProgress = errors.New("Fish: Request with the idempotency key is in progress")
+
+// idempotencyInProgressTimeout is how long the request could keep the key reserved, the record
+// stays in progress if the node was stopped during the request, so the retries are allowed after
+const idempotencyInProgressTimeout = 5 * time.Minute
+
+// ErrIdempotencyMismatch is returned when the key was already used for the other request
+var ErrIdempotencyMismatch = errors.New("Fish: Idempotency key was used for the other request")
+
+// IdempotencyRecord keeps the response of the mutating request with the idempotency key to reply
+// the same on the client retry instead of processing the request again
+type IdempotencyRecord struct {
+	Key         string `gorm:"primaryKey"` // User name and the client key
+	Request     string // Method and route of the request to detect the key reuse
+	BodyHash    string // Hash of the request body to detect the key reuse with the other payload
+	Status      int    // Response status, 0 while the request is in progress
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+// IdempotencyStart reserves the User key for the request, returns the stored record if the request
+// was already processed, nil if it need to be processed now or error if the key can't be used
+func (f *Fish) IdempotencyStart(user, key, request, bodyHash string) (*IdempotencyRecord, error) {
+	if f.cfg.IdempotencyWindow <= 0 {
+		return nil, nil
+	}
+	rec := &IdempotencyRecord{Key: user + "/" + key, Request: request, BodyHash: bodyHash}
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		var existing IdempotencyRecord
+		err := tx.First(&existing, "key = ?", rec.Key).Error
+		if err == nil {
+			valid := existing.CreatedAt.After(time.Now().Add(-time.Duration(f.cfg.IdempotencyWindow)))
+			if existing.Status == 0 {
+				valid = valid && existing.CreatedAt.After(time.Now().Add(-idempotencyInProgressTimeout))
+			}
+			if valid {
+				rec = &existing
+				return nil
+			}
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		// Not existing or outdated record, so the request is processed again
+		if err = tx.Delete(&IdempotencyRecord{}, "key = ?", rec.Key).Error; err != nil {
+			return err
+		}
+		if err = tx.Create(rec).Error; err != nil {
+			return err
+		}
+		rec = nil
+		return nil
+	})
+	if err != nil {
+		// The concurrent request with the same key created the record first
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, ErrIdempotencyInProgress
+		}
+		return nil, err
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	if rec.Request != request || rec.BodyHash != bodyHash {
+		return nil, ErrIdempotencyMismatch
+	}
+	if rec.Status == 0 {
+		return nil, ErrIdempotencyInProgress
+	}
+	return rec, nil
+}
+
+// IdempotencyFinish stores the response of the request, the server errors are not stored to allow
+// the client to retry the request
+func (f *Fish) IdempotencyFinish(user, key string, status int, contentType string, body []byte) error {
+	if f.cfg.IdempotencyWindow <= 0 {
+		return nil
+	}
+	if status == 0 || status >= 500 {
+		return f.db.Delete(&IdempotencyRecord{}, "key = ?", user+"/"+key).Error
+	}
+	return f.db.Model(&IdempotencyRecord{}).Where("key = ?", user+"/"+key).Updates(map[string]any{
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}).Error
+}
+
+// idempotencyProcess periodically removes the records older than the idempotency window
+func (f *Fish) idempotencyProcess() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		result := f.db.Where("created_at < ?", time.Now().Add(-time.Duration(f.cfg.IdempotencyWindow))).Delete(&IdempotencyRecord{})
+		if result.Error != nil {
+			log.Error("Fish: Unable to remove outdated idempotency records:", result.Error)
+		} else if result.RowsAffected > 0 {
+			log.Debugf("Fish: Removed %d outdated idempotency records", result.RowsAffected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// IdleRequester is used as the deallocate requester when the idle Resource is released
+const IdleRequester = "fish-idle"
+
+// idleNotification is the webhook request body to warn the Application owner
+type idleNotification struct {
+	Action         string               `json:"action"`
+	ApplicationUID types.ApplicationUID `json:"application_uid"`
+	ShortID        string               `json:"short_id"`
+	Owner          string               `json:"owner"`
+	IdleSince      time.Time            `json:"idle_since"`
+	DeallocateAt   time.Time            `json:"deallocate_at"`
+}
+
+// resourceIdle keeps the idle detection state of the executing Application
+type resourceIdle struct {
+	timeout time.Duration
+	warned  bool
+}
+
+// ResourceActivityReport stores the Resource agent report, the Resource is used if the agent
+// detected activity or the CPU usage is above the threshold
+func (f *Fish) ResourceActivityReport(res *types.Resource, activity *types.ResourceActivity) error {
+	if activity.Cpu < 0 || activity.Cpu > 100*1024 {
+		return fmt.Errorf("Fish: CPU usage is out of range: %f", activity.Cpu)
+	}
+	if activity.Active || activity.Cpu >= f.cfg.Idle.CPU {
+		f.resourceActivityMutex.Lock()
+		f.resourceUsage[res.UID] = time.Now()
+		f.resourceActivityMutex.Unlock()
+	}
+	return nil
+}
+
+// resourceUsedSince returns the last time the Resource was used by the user, the meta API
+// requests are not counted since the agents are polling it all the time
+func (f *Fish) resourceUsedSince(res *types.Resource) time.Time {
+	since := f.startedAt
+	if res.CreatedAt.After(since) {
+		since = res.CreatedAt
+	}
+	f.resourceActivityMutex.Lock()
+	defer f.resourceActivityMutex.Unlock()
+	if len(f.resourceSessions[res.UID]) > 0 {
+		return time.Now()
+	}
+	if last, ok := f.resourceUsage[res.UID]; ok && last.After(since) {
+		since = last
+	}
+	return since
+}
+
+// newResourceIdle parses the Label definition idle timeout, nil is returned if it's disabled
+func newResourceIdle(def *types.LabelDefinition) (*resourceIdle, error) {
+	if def.Resources.IdleTimeout == "" {
+		return nil, nil
+	}
+	timeout, err := time.ParseDuration(def.Resources.IdleTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Can't parse the IdleTimeout: %v", err)
+	}
+	if timeout <= 0 {
+		return nil, nil
+	}
+	return &resourceIdle{timeout: timeout}, nil
+}
+
+// check warns the owner when the Resource is idle for too long and deallocates it on timeout,
+// returns the new Application state if it was deallocated
+func (ri *resourceIdle) check(f *Fish, app *types.Application, res *types.Resource) *types.ApplicationState {
+	since := f.resourceUsedSince(res)
+	deadline := since.Add(ri.timeout)
+	now := time.Now()
+
+	if now.Before(deadline) {
+		warnAt := deadline.Add(-time.Duration(f.cfg.Idle.Warning))
+		if !now.Before(warnAt) && !ri.warned {
+			ri.warned = true
+			log.Infof("Fish: AUDIT: Resource of Application %s is idle since %s and will be deallocated at %s", app.UID, since, deadline)
+			f.idleNotify(app, "idle_warning", since, deadline)
+		} else if now.Before(warnAt) {
+			// The Resource was used after the warning
+			ri.warned = false
+		}
+		return nil
+	}
+
+	log.Infof("Fish: AUDIT: Deallocating idle Resource of Application %s, not used since %s", app.UID, since)
+	state, err := f.ApplicationDeallocate(app, IdleRequester)
+	if err != nil {
+		log.Errorf("Fish: Unable to deallocate idle Application %s: %v", app.UID, err)
+		return nil
+	}
+	f.idleNotify(app, "idle_deallocate", since, now)
+	return state
+}
+
+// idleNotify sends the idle notification to the configured webhook in background
+func (f *Fish) idleNotify(app *types.Application, action string, since, deallocateAt time.Time) {
+	if f.cfg.Idle.Webhook == "" {
+		return
+	}
+	n := &idleNotification{
+		Action:         action,
+		ApplicationUID: app.UID,
+		ShortID:        app.ShortId,
+		Owner:          app.OwnerName,
+		IdleSince:      since,
+		DeallocateAt:   deallocateAt,
+	}
+	go func() {
+		if err := webhookNotify(f.cfg.Idle.Webhook, n); err != nil {
+			log.Warn("Fish: Unable to notify owner about idle Application:", app.UID, err)
+		}
+	}()
+}
@@ -0,0 +1,213 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// imageRefRegexp finds the Image catalog references in the Label definition options:
+// ${image:NAME}, ${image:NAME:VERSION}, ${image_checksum:NAME} or ${image_checksum:NAME:VERSION}
+var imageRefRegexp = regexp.MustCompile(`\$\{(image|image_checksum):([^}:]+)(?::([0-9]+))?\}`)
+
+// ImageFind returns list of Images that fits filter
+func (f *Fish) ImageFind(filter *string) (images []types.Image, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return images, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Order("name, version").Find(&images).Error
+	return images, err
+}
+
+// ImageCreate adds new Image version to the catalog
+func (f *Fish) ImageCreate(i *types.Image) error {
+	if i.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if strings.ContainsAny(i.Name, ":}") {
+		return fmt.Errorf("Fish: Name can't contain ':' or '}'")
+	}
+	if i.Version < 1 {
+		return fmt.Errorf("Fish: Version can't be less than 1")
+	}
+	if len(i.Identifiers) == 0 {
+		return fmt.Errorf("Fish: Identifiers can't be empty")
+	}
+	for drv, id := range i.Identifiers {
+		if drv == "" || id == "" {
+			return fmt.Errorf("Fish: Identifier and it's driver can't be empty")
+		}
+	}
+
+	i.UID = f.NewUID()
+	if err := f.db.Create(i).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindImage, i.UID.String(), "")
+
+	// The deprecated version is not available yet, like the built one waiting for smoke test
+	if !i.Deprecated {
+		f.imageNotifyPublished(i)
+	}
+	return nil
+}
+
+// imagePublish makes the deprecated Image version available for the Label references by name
+func (f *Fish) imagePublish(i *types.Image) error {
+	i.Deprecated = false
+	if err := f.db.Save(i).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindImage, i.UID.String(), "")
+	f.imageNotifyPublished(i)
+	return nil
+}
+
+// imageNotifyPublished notifies the subscribers about the new available Image version
+func (f *Fish) imageNotifyPublished(i *types.Image) {
+	text := fmt.Sprintf("New version %d of Image %s is available", i.Version, i.Name)
+	if i.Description != nil && *i.Description != "" {
+		text += ": " + *i.Description
+	}
+	f.notify("", &notify.Notification{
+		Event:   notify.EventImagePublished,
+		Subject: fmt.Sprintf("Aquarium: Image %s:%d is published", i.Name, i.Version),
+		Text:    text,
+		Data:    map[string]any{"image_uid": i.UID, "name": i.Name, "version": i.Version},
+	})
+}
+
+// ImageGet returns Image by UID
+func (f *Fish) ImageGet(uid types.ImageUID) (i *types.Image, err error) {
+	i = &types.Image{}
+	err = f.db.First(i, uid).Error
+	return i, err
+}
+
+// ImageDeprecate marks the Image version as deprecated
+func (f *Fish) ImageDeprecate(uid types.ImageUID) (*types.Image, error) {
+	i, err := f.ImageGet(uid)
+	if err != nil {
+		return nil, err
+	}
+	if i.Deprecated {
+		return i, nil
+	}
+	i.Deprecated = true
+	if err = f.db.Save(i).Error; err != nil {
+		return nil, err
+	}
+	f.syncLog(syncKindImage, i.UID.String(), "")
+
+	f.notify("", &notify.Notification{
+		Event:   notify.EventImageDeprecated,
+		Subject: fmt.Sprintf("Aquarium: Image %s:%d is deprecated", i.Name, i.Version),
+		Text:    fmt.Sprintf("Version %d of Image %s is deprecated, the Labels referencing it by name will not use it", i.Version, i.Name),
+		Data:    map[string]any{"image_uid": i.UID, "name": i.Name, "version": i.Version},
+	})
+	return i, nil
+}
+
+// ImageDelete removes the Image version by UID
+func (f *Fish) ImageDelete(uid types.ImageUID) error {
+	return f.db.Delete(&types.Image{}, uid).Error
+}
+
+// imageGetByRef returns the Image version or the latest not deprecated one if version is 0
+func (f *Fish) imageGetByRef(name string, version int) (i *types.Image, err error) {
+	i = &types.Image{}
+	if version > 0 {
+		err = f.db.Where("name = ? AND version = ?", name, version).First(i).Error
+		if err == nil && i.Deprecated {
+			log.Warnf("Fish: Deprecated Image %s:%d is used", name, version)
+		}
+		return i, err
+	}
+	err = f.db.Where("name = ? AND deprecated = ?", name, false).Order("version desc").First(i).Error
+	return i, err
+}
+
+// imagePendingRef returns the Image version or the latest not deprecated one if version is 0 from
+// the Images which are not created yet, nil if not found
+func imagePendingRef(pending []types.Image, name string, version int) (found *types.Image) {
+	for i := range pending {
+		img := &pending[i]
+		if img.Name != name {
+			continue
+		}
+		if version > 0 && img.Version == version {
+			return img
+		}
+		if version == 0 && !img.Deprecated && (found == nil || img.Version > found.Version) {
+			found = img
+		}
+	}
+	return found
+}
+
+// imageResolve replaces the Image catalog references in the definition options with the Image
+// identifier of the driver instance (like "aws/us-west-2") or the driver name (like "aws"), the
+// pending Images are used if the reference is not found in the catalog
+func (f *Fish) imageResolve(driverName string, options util.UnparsedJSON, pending []types.Image) (util.UnparsedJSON, error) {
+	if !strings.Contains(string(options), "${image") {
+		return options, nil
+	}
+
+	var resolveErr error
+	out := imageRefRegexp.ReplaceAllStringFunc(string(options), func(ref string) string {
+		m := imageRefRegexp.FindStringSubmatch(ref)
+		version := 0
+		if m[3] != "" {
+			version, _ = strconv.Atoi(m[3])
+		}
+		img, err := f.imageGetByRef(m[2], version)
+		if p := imagePendingRef(pending, m[2], version); err != nil && p != nil {
+			img, err = p, nil
+		}
+		if err != nil {
+			resolveErr = fmt.Errorf("Fish: Unable to find Image for %s: %v", ref, err)
+			return ref
+		}
+		value := img.Checksum
+		if m[1] == "image" {
+			var ok bool
+			if value, ok = img.Identifiers[driverName]; !ok {
+				base, _, _ := strings.Cut(driverName, "/")
+				if value, ok = img.Identifiers[base]; !ok {
+					resolveErr = fmt.Errorf("Fish: Image %s:%d has no identifier for driver %s", img.Name, img.Version, driverName)
+					return ref
+				}
+			}
+		}
+		// The options are json, so the value needs to be escaped as json string content
+		escaped, _ := json.Marshal(value)
+		return string(escaped[1 : len(escaped)-1])
+	})
+	return util.UnparsedJSON(out), resolveErr
+}
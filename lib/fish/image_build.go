@@ -0,0 +1,371 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Statuses of the Image build
+const (
+	ImageBuildStatusBuilding  = "BUILDING"
+	ImageBuildStatusTesting   = "TESTING"
+	ImageBuildStatusFailed    = "FAILED"
+	ImageBuildStatusCompleted = "COMPLETED"
+)
+
+// How much of the build command output to keep in the Image build log
+const imageBuildLogSize = 16 * 1024
+
+// ImageBuildFind returns list of Image builds that fits the filter
+func (f *Fish) ImageBuildFind(filter *string) (bs []types.ImageBuild, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return bs, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Order("created_at").Find(&bs).Error
+	return bs, err
+}
+
+// ImageBuildGet returns Image build by UID
+func (f *Fish) ImageBuildGet(uid types.ImageBuildUID) (b *types.ImageBuild, err error) {
+	b = &types.ImageBuild{}
+	err = f.db.First(b, uid).Error
+	return b, err
+}
+
+// ImageBuildCreate validates the Image build and runs it on background
+func (f *Fish) ImageBuildCreate(b *types.ImageBuild) error {
+	if b.OwnerName == "" {
+		return fmt.Errorf("Fish: OwnerName can't be empty")
+	}
+	if _, ok := f.cfg.ImageBuild.Templates[b.Template]; !ok {
+		return fmt.Errorf("Fish: Image build template %q is not configured on the node", b.Template)
+	}
+	if b.Name == "" || strings.ContainsAny(b.Name, ":}") {
+		return fmt.Errorf("Fish: Name can't be empty or contain ':' or '}'")
+	}
+	if b.Version < 1 {
+		return fmt.Errorf("Fish: Version can't be less than 1")
+	}
+	if exists, err := f.syncExists(&types.Image{}, "name = ? AND version = ?", b.Name, b.Version); err != nil || exists {
+		return fmt.Errorf("Fish: Image %s:%d already exists", b.Name, b.Version)
+	}
+	if b.SmokeLabel != "" {
+		if _, err := f.LabelGetLatest(b.SmokeLabel); err != nil {
+			return fmt.Errorf("Fish: Unable to find smoke test Label %s: %v", b.SmokeLabel, err)
+		}
+	}
+	for _, name := range b.RollLabels {
+		if _, err := f.LabelGetLatest(name); err != nil {
+			return fmt.Errorf("Fish: Unable to find roll Label %s: %v", name, err)
+		}
+	}
+	if b.Variables == nil {
+		b.Variables = map[string]string{}
+	}
+	if b.RollLabels == nil {
+		b.RollLabels = []string{}
+	}
+
+	b.UID = f.NewUID()
+	b.NodeName = f.node.Name
+	b.Status = ImageBuildStatusBuilding
+	b.ImageUID = uuid.Nil
+	b.Error = ""
+	b.Log = ""
+	if err := f.db.Create(b).Error; err != nil {
+		return err
+	}
+
+	go f.imageBuildRun(*b)
+	return nil
+}
+
+// imageBuildInterrupted fails the builds of the node which were running before restart
+func (f *Fish) imageBuildInterrupted() error {
+	return f.db.Model(&types.ImageBuild{}).
+		Where("node_name = ? AND status IN ?", f.node.Name, []string{ImageBuildStatusBuilding, ImageBuildStatusTesting}).
+		Updates(map[string]any{"status": ImageBuildStatusFailed, "error": "Interrupted by the node restart"}).Error
+}
+
+// imageBuildFail stores the build failure
+func (f *Fish) imageBuildFail(b *types.ImageBuild, err error) {
+	log.Errorf("Fish: Image build %s of %s:%d failed: %v", b.UID, b.Name, b.Version, err)
+	b.Status = ImageBuildStatusFailed
+	b.Error = err.Error()
+	if err := f.db.Save(b).Error; err != nil {
+		log.Error("Fish: Unable to save the Image build:", b.UID, err)
+	}
+}
+
+// imageBuildRun builds, registers, tests and publishes the Image and rolls the pinned Labels
+func (f *Fish) imageBuildRun(b types.ImageBuild) {
+	tpl := f.cfg.ImageBuild.Templates[b.Template]
+	log.Infof("Fish: Starting Image build %s of %s:%d with template %s", b.UID, b.Name, b.Version, b.Template)
+
+	output, identifiers, err := f.imageBuildExec(&b, tpl)
+	b.Log = output
+	if err != nil {
+		f.imageBuildFail(&b, err)
+		return
+	}
+	if len(identifiers) == 0 {
+		f.imageBuildFail(&b, fmt.Errorf("Build command printed no artifact id"))
+		return
+	}
+
+	// The Image is not available by name until the smoke test is completed
+	description := fmt.Sprintf("Built by %s from template %s", b.UID, b.Template)
+	img := &types.Image{
+		Name:        b.Name,
+		Version:     b.Version,
+		Identifiers: identifiers,
+		Description: &description,
+		Deprecated:  true,
+	}
+	if err = f.ImageCreate(img); err != nil {
+		f.imageBuildFail(&b, fmt.Errorf("Unable to register the Image: %v", err))
+		return
+	}
+	b.ImageUID = img.UID
+
+	if b.SmokeLabel != "" {
+		b.Status = ImageBuildStatusTesting
+		if err = f.db.Save(&b).Error; err != nil {
+			log.Error("Fish: Unable to save the Image build:", b.UID, err)
+		}
+		if err = f.imageBuildSmokeTest(&b); err != nil {
+			f.imageBuildFail(&b, fmt.Errorf("Smoke test failed: %v", err))
+			return
+		}
+	}
+
+	if err = f.imagePublish(img); err != nil {
+		f.imageBuildFail(&b, fmt.Errorf("Unable to publish the Image: %v", err))
+		return
+	}
+	for _, name := range b.RollLabels {
+		if err = f.imageBuildRoll(&b, name); err != nil {
+			f.imageBuildFail(&b, fmt.Errorf("Unable to roll Label %s: %v", name, err))
+			return
+		}
+	}
+
+	b.Status = ImageBuildStatusCompleted
+	if err = f.db.Save(&b).Error; err != nil {
+		log.Error("Fish: Unable to save the Image build:", b.UID, err)
+	}
+	log.Infof("Fish: Image build %s of %s:%d is completed", b.UID, b.Name, b.Version)
+}
+
+// imageBuildExec runs the template command and returns the tail of its output and the artifacts
+func (f *Fish) imageBuildExec(b *types.ImageBuild, tpl ConfigImageBuildTemplate) (string, map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(tpl.Timeout))
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, tpl.Command[0], tpl.Command[1:]...) // #nosec G204
+	cmd.Dir = tpl.Dir
+	if cmd.Dir != "" && !filepath.IsAbs(cmd.Dir) {
+		cmd.Dir = filepath.Join(f.cfg.Directory, cmd.Dir)
+	}
+	cmd.Env = os.Environ()
+	for key, val := range b.Variables {
+		cmd.Env = append(cmd.Env, "PKR_VAR_"+key+"="+val)
+	}
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	// Keeping just the tail of the output, the builds could be quite verbose
+	identifiers := map[string]string{}
+	done := make(chan string)
+	go func() {
+		var tail []string
+		size := 0
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			imageBuildArtifact(line, tpl.Driver, identifiers)
+			tail = append(tail, line)
+			size += len(line) + 1
+			for size > imageBuildLogSize && len(tail) > 1 {
+				size -= len(tail[0]) + 1
+				tail = tail[1:]
+			}
+		}
+		io.Copy(io.Discard, reader)
+		done <- strings.Join(tail, "\n")
+	}()
+
+	err := cmd.Run()
+	writer.Close()
+	output := <-done
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, nil, fmt.Errorf("Build command timed out")
+	}
+	if err != nil {
+		return output, nil, fmt.Errorf("Build command failed: %v", err)
+	}
+	return output, identifiers, nil
+}
+
+// imageBuildArtifact parses the packer machine-readable artifact id line of the output like
+// "1700000000,amazon-ebs.macos,artifact,0,id,us-west-2:ami-0123,eu-central-1:ami-0456" into the
+// identifiers: the ones with region go to "<driver>/<region>" and the others to "<driver>"
+func imageBuildArtifact(line, driver string, identifiers map[string]string) {
+	parts := strings.Split(strings.TrimSpace(line), ",")
+	if len(parts) < 6 || parts[2] != "artifact" || parts[4] != "id" {
+		return
+	}
+	// Packer escapes the commas of the data, but the artifact ids list is printed as is
+	for _, data := range parts[5:] {
+		for _, id := range strings.Split(strings.ReplaceAll(data, "%!(PACKER_COMMA)", ","), ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			if region, value, ok := strings.Cut(id, ":"); ok {
+				identifiers[driver+"/"+region] = value
+			} else {
+				identifiers[driver] = id
+			}
+		}
+	}
+}
+
+// imageBuildSmokeTest allocates the Application of the temporary smoke Label copy pinned to the
+// built Image version and cleans it up after
+func (f *Fish) imageBuildSmokeTest(b *types.ImageBuild) error {
+	label, err := f.LabelGetLatest(b.SmokeLabel)
+	if err != nil {
+		return err
+	}
+	smoke := *label
+	smoke.Name = fmt.Sprintf("%s-smoke-%s", label.Name, b.UID.String()[:8])
+	smoke.Version = 1
+	smoke.Extends = nil
+	// The smoke test is requested by the operator, so no need to wait for approval
+	smoke.RequiresApproval = false
+	smoke.Definitions = imagePinDefinitions(label.Definitions, b.Name, b.Version)
+	if err = f.LabelCreate(&smoke); err != nil {
+		return fmt.Errorf("Unable to create smoke Label: %v", err)
+	}
+
+	app := &types.Application{
+		LabelUID:  smoke.UID,
+		OwnerName: b.OwnerName,
+		Metadata:  util.UnparsedJSON(fmt.Sprintf(`{"IMAGE_BUILD":%q}`, b.UID.String())),
+	}
+	if err = f.ApplicationCreate(context.Background(), app); err != nil {
+		f.LabelDelete(smoke.UID)
+		return fmt.Errorf("Unable to create smoke Application: %v", err)
+	}
+
+	timeout := time.Duration(f.cfg.ImageBuild.SmokeTimeout)
+	status := f.imageBuildAwait(app.UID, timeout, types.ApplicationStatusALLOCATED, types.ApplicationStatusERROR, types.ApplicationStatusDEALLOCATED)
+	if f.ApplicationStateIsActive(status) {
+		if _, err := f.ApplicationDeallocate(app, b.OwnerName); err != nil {
+			log.Error("Fish: Unable to deallocate the smoke test Application:", app.UID, err)
+		}
+		f.imageBuildAwait(app.UID, timeout, types.ApplicationStatusDEALLOCATED, types.ApplicationStatusERROR)
+	}
+	if err := f.LabelDelete(smoke.UID); err != nil {
+		log.Error("Fish: Unable to delete the smoke test Label:", smoke.UID, err)
+	}
+
+	if status != types.ApplicationStatusALLOCATED {
+		return fmt.Errorf("Application %s was not allocated: %s", app.UID, status)
+	}
+	return nil
+}
+
+// imageBuildAwait waits for the Application to get one of the statuses and returns the last one
+func (f *Fish) imageBuildAwait(uid types.ApplicationUID, timeout time.Duration, statuses ...types.ApplicationStatus) types.ApplicationStatus {
+	var status types.ApplicationStatus
+	deadline := time.Now().Add(timeout)
+	for f.running && time.Now().Before(deadline) {
+		if state, err := f.ApplicationStateGetByApplication(uid); err == nil {
+			status = state.Status
+			for _, s := range statuses {
+				if status == s {
+					return status
+				}
+			}
+		}
+		time.Sleep(time.Second)
+	}
+	return status
+}
+
+// imageBuildRoll creates the next version of the Label pinned to the built Image version
+func (f *Fish) imageBuildRoll(b *types.ImageBuild, name string) error {
+	label, err := f.LabelGetLatest(name)
+	if err != nil {
+		return err
+	}
+	rolled := *label
+	rolled.Version = label.Version + 1
+	rolled.Definitions = imagePinDefinitions(label.Definitions, b.Name, b.Version)
+	changed := false
+	for i := range rolled.Definitions {
+		changed = changed || rolled.Definitions[i].Options != label.Definitions[i].Options
+	}
+	if !changed {
+		log.Warnf("Fish: Label %s is not referencing Image %s, skipping roll", name, b.Name)
+		return nil
+	}
+	if err = f.LabelCreate(&rolled); err != nil {
+		return err
+	}
+	log.Infof("Fish: Label %s:%d is rolled to Image %s:%d", rolled.Name, rolled.Version, b.Name, b.Version)
+	return nil
+}
+
+// imagePinDefinitions returns the copy of definitions with the Image references pinned to version
+func imagePinDefinitions(defs types.LabelDefinitions, name string, version int) types.LabelDefinitions {
+	out := make(types.LabelDefinitions, len(defs))
+	for i, def := range defs {
+		def.Options = util.UnparsedJSON(imageRefRegexp.ReplaceAllStringFunc(string(def.Options), func(ref string) string {
+			m := imageRefRegexp.FindStringSubmatch(ref)
+			if m[2] != name {
+				return ref
+			}
+			return "${" + m[1] + ":" + name + ":" + strconv.Itoa(version) + "}"
+		}))
+		out[i] = def
+	}
+	return out
+}
@@ -21,9 +21,18 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// LabelFind returns list of Labels that fits filter
-func (f *Fish) LabelFind(filter *string) (labels []types.Label, err error) {
-	db := f.db
+// LabelFind returns list of Labels that fits filter and page, report uses the replica
+func (f *Fish) LabelFind(filter *string, page *ListPage, report bool) (labels []types.Label, err error) {
+	return f.LabelFindVisible(filter, nil, page, report)
+}
+
+// LabelFindVisible is LabelFind limited to the shared Labels and the Labels of the projects, nil
+// projects means no limit
+func (f *Fish) LabelFindVisible(filter *string, projects []string, page *ListPage, report bool) (labels []types.Label, err error) {
+	db := f.dbFind(report)
+	if projects != nil {
+		db = db.Where("project = '' OR project IN ?", projects)
+	}
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -33,15 +42,40 @@ func (f *Fish) LabelFind(filter *string) (labels []types.Label, err error) {
 		}
 		db = db.Where(securedFilter)
 	}
-	err = db.Find(&labels).Error
-	return labels, err
+	if db, err = page.query(db, []string{"name", "version"}, "uid"); err != nil {
+		return labels, err
+	}
+	if err = db.Find(&labels).Error; err != nil {
+		return labels, err
+	}
+	return cutPage(page, labels)
 }
 
 // LabelCreate makes new Label
 func (f *Fish) LabelCreate(l *types.Label) error {
+	if err := f.labelValidate(l, nil, nil); err != nil {
+		return err
+	}
+	return f.labelInsert(l)
+}
+
+// labelValidate resolves the parents, checks the Label and fills the defaults, the Image
+// references could point to the not created yet images
+func (f *Fish) labelValidate(l *types.Label, pending map[string]*types.Label, images []types.Image) error {
 	if l.Name == "" {
 		return fmt.Errorf("Fish: Name can't be empty")
 	}
+	if l.Project != "" {
+		if _, err := f.ProjectGet(l.Project); err != nil {
+			return fmt.Errorf("Fish: Unable to find Project %q: %v", l.Project, err)
+		}
+	}
+	if err := f.labelResolve(l, pending); err != nil {
+		return err
+	}
+	if err := f.limitLabelDefinitions(len(l.Definitions)); err != nil {
+		return err
+	}
 	for i, def := range l.Definitions {
 		if def.Driver == "" {
 			return fmt.Errorf("Fish: Driver can't be empty in Label Definition %d", i)
@@ -56,16 +90,72 @@ func (f *Fish) LabelCreate(l *types.Label) error {
 		if def.Resources.Lifetime != "" && err != nil {
 			return fmt.Errorf("Fish: Resources Lifetime parse error in Label Definition %d: %v", i, err)
 		}
+		if _, err := time.ParseDuration(def.Resources.IdleTimeout); def.Resources.IdleTimeout != "" && err != nil {
+			return fmt.Errorf("Fish: Resources IdleTimeout parse error in Label Definition %d: %v", i, err)
+		}
+		if _, err := time.ParseDuration(def.Resources.MaxExtension); def.Resources.MaxExtension != "" && err != nil {
+			return fmt.Errorf("Fish: Resources MaxExtension parse error in Label Definition %d: %v", i, err)
+		}
+		if _, err := time.ParseDuration(def.Resources.MaxLifetime); def.Resources.MaxLifetime != "" && err != nil {
+			return fmt.Errorf("Fish: Resources MaxLifetime parse error in Label Definition %d: %v", i, err)
+		}
 		if def.Options == "" {
 			l.Definitions[i].Options = "{}"
 		}
+		if def.Recycle != nil {
+			if def.Recycle.MaxReuse < 0 {
+				return fmt.Errorf("Fish: Recycle MaxReuse can't be negative in Label Definition %d", i)
+			}
+			if _, err := time.ParseDuration(def.Recycle.MaxAge); def.Recycle.MaxAge != "" && err != nil {
+				return fmt.Errorf("Fish: Recycle MaxAge parse error in Label Definition %d: %v", i, err)
+			}
+			if _, err := time.ParseDuration(def.Recycle.IdleTimeout); def.Recycle.IdleTimeout != "" && err != nil {
+				return fmt.Errorf("Fish: Recycle IdleTimeout parse error in Label Definition %d: %v", i, err)
+			}
+			if def.Recycle.Options == "" {
+				l.Definitions[i].Recycle.Options = "{}"
+			}
+			if def.Recycle.ReuseOptions == "" {
+				l.Definitions[i].Recycle.ReuseOptions = "{}"
+			}
+		}
+		if def.HealthCheck != nil {
+			if err := f.healthCheckValidate(def.Driver, def.HealthCheck); err != nil {
+				return fmt.Errorf("Fish: HealthCheck is invalid in Label Definition %d: %v", i, err)
+			}
+		}
+		// The Image catalog references have to exist, the driver validates the resolved options
+		resolved := l.Definitions[i]
+		if resolved.Options, err = f.imageResolve(def.Driver, resolved.Options, images); err != nil {
+			return fmt.Errorf("Fish: Label Definition %d options: %v", i, err)
+		}
+		// Only the node with the driver could check the definition is possible to allocate
+		if drv := f.driverGet(def.Driver); drv != nil {
+			if err := drv.ValidateDefinition(resolved); err != nil {
+				return fmt.Errorf("Fish: Label Definition %d is not supported by driver %s: %v", i, def.Driver, err)
+			}
+		}
 	}
 	if l.Metadata == "" {
 		l.Metadata = "{}"
 	}
+	if l.MetadataSchema == "" {
+		l.MetadataSchema = "{}"
+	}
+	if _, err := labelMetadataSchema(l.MetadataSchema); err != nil {
+		return err
+	}
+	return f.limitMetadata(l.Metadata)
+}
 
+// labelInsert stores the validated Label
+func (f *Fish) labelInsert(l *types.Label) error {
 	l.UID = f.NewUID()
-	return f.db.Create(l).Error
+	if err := f.db.Create(l).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindLabel, l.UID.String(), "")
+	return nil
 }
 
 // Intentionally disabled - labels can be created once and can't be updated
@@ -81,7 +171,15 @@ func (f *Fish) LabelGet(uid types.LabelUID) (label *types.Label, err error) {
 	return label, err
 }
 
+// LabelGetLatest returns the latest version of the Label by name
+func (f *Fish) LabelGetLatest(name string) (label *types.Label, err error) {
+	label = &types.Label{}
+	err = f.db.Where("name = ?", name).Order("version desc").First(label).Error
+	return label, err
+}
+
 // LabelDelete deletes the Label by UID
 func (f *Fish) LabelDelete(uid types.LabelUID) error {
+	f.labelCompatRemove(uid)
 	return f.db.Delete(&types.Label{}, uid).Error
 }
@@ -0,0 +1,265 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ApplyChange is the object changed by the declarative apply, nil Before means it was created and
+// nil After means it was removed
+type ApplyChange[T any] struct {
+	Before *T
+	After  *T
+}
+
+// LabelApplyChanges are the objects changed by LabelApply to audit them
+type LabelApplyChanges struct {
+	Labels []ApplyChange[types.Label]
+	Roles  []ApplyChange[types.Role]
+}
+
+// LabelApply makes the cluster Labels and optionally the custom Roles match the provided set. The
+// Label versions are immutable, so the existing version with different content is reported as
+// drifted and nothing is applied, the changed Roles are updated. Prune removes the Labels missing
+// in the set unless they are used by active Applications and the Roles unless they are granted.
+// Dry run only reports what will be changed. The set is applied in one transaction.
+func (f *Fish) LabelApply(req types.LabelApply) (res types.LabelApplyResult, changes LabelApplyChanges, err error) {
+	res = types.LabelApplyResult{Created: []string{}, Unchanged: []string{}, Drifted: []string{}, Removed: []string{}, InUse: []string{}}
+	prune := req.Prune != nil && *req.Prune
+
+	var existing []types.Label
+	if err = f.db.Find(&existing).Error; err != nil {
+		return res, changes, err
+	}
+	current := make(map[string]types.Label, len(existing))
+	for _, l := range existing {
+		current[labelKey(l.Name, l.Version)] = l
+	}
+
+	// Checking the whole set before changing anything, the parents have to go before the children
+	var created, removed []types.Label
+	wanted := make(map[string]bool, len(req.Labels))
+	pending := make(map[string]*types.Label, len(req.Labels))
+	for i := range req.Labels {
+		l := &req.Labels[i]
+		key := labelKey(l.Name, l.Version)
+		if wanted[key] {
+			return res, changes, fmt.Errorf("Fish: Label %s is duplicated in the set", key)
+		}
+		wanted[key] = true
+		if err = f.labelValidate(l, pending, nil); err != nil {
+			return res, changes, fmt.Errorf("Fish: Label %s is invalid: %w", key, err)
+		}
+		pending[key] = l
+		if cur, ok := current[key]; !ok {
+			res.Created = append(res.Created, key)
+			created = append(created, *l)
+		} else if labelEqual(cur, *l) {
+			res.Unchanged = append(res.Unchanged, key)
+		} else {
+			res.Drifted = append(res.Drifted, key)
+		}
+	}
+	if prune {
+		stats, err := f.LabelStatsGet("", false)
+		if err != nil {
+			return res, changes, err
+		}
+		running := make(map[types.LabelUID]bool, len(stats))
+		for _, s := range stats {
+			running[s.LabelUID] = s.Running > 0
+		}
+		for key, l := range current {
+			if wanted[key] {
+				continue
+			}
+			if running[l.UID] {
+				res.InUse = append(res.InUse, key)
+			} else {
+				res.Removed = append(res.Removed, key)
+				removed = append(removed, l)
+			}
+		}
+		sort.Strings(res.Removed)
+		sort.Strings(res.InUse)
+	}
+
+	var roles []ApplyChange[types.Role]
+	if req.Roles != nil {
+		var rolesRes types.RoleApplyResult
+		if rolesRes, roles, err = f.roleApplyPlan(*req.Roles, prune); err != nil {
+			return res, changes, err
+		}
+		res.Roles = &rolesRes
+	}
+
+	if req.DryRun != nil && *req.DryRun {
+		return res, changes, nil
+	}
+	if len(res.Drifted) > 0 {
+		return res, changes, fmt.Errorf("Fish: Label versions are immutable, create new versions instead of changing: %v", res.Drifted)
+	}
+
+	err = f.db.Transaction(func(tx *gorm.DB) error {
+		for i := range created {
+			created[i].UID = f.NewUID()
+			if err := tx.Create(&created[i]).Error; err != nil {
+				return fmt.Errorf("Fish: Unable to create Label %s: %w", labelKey(created[i].Name, created[i].Version), err)
+			}
+		}
+		for i := range removed {
+			if err := tx.Delete(&types.Label{}, removed[i].UID).Error; err != nil {
+				return fmt.Errorf("Fish: Unable to remove Label %s: %w", labelKey(removed[i].Name, removed[i].Version), err)
+			}
+		}
+		for _, rc := range roles {
+			if rc.After != nil {
+				if err := tx.Save(rc.After).Error; err != nil {
+					return fmt.Errorf("Fish: Unable to save Role %q: %w", rc.After.Name, err)
+				}
+				continue
+			}
+			// The Role could be granted since the plan was made
+			var count int64
+			if err := tx.Model(&types.RoleGrant{}).Where("role = ? AND revoked_at IS NULL", rc.Before.Name).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("Fish: Role %q still has %d active grants", rc.Before.Name, count)
+			}
+			if err := tx.Delete(&types.Role{}, "name = ?", rc.Before.Name).Error; err != nil {
+				return fmt.Errorf("Fish: Unable to remove Role %q: %w", rc.Before.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return res, changes, err
+	}
+
+	for i := range created {
+		f.syncLog(syncKindLabel, created[i].UID.String(), "")
+		changes.Labels = append(changes.Labels, ApplyChange[types.Label]{After: &created[i]})
+	}
+	for i := range removed {
+		f.labelCompatRemove(removed[i].UID)
+		changes.Labels = append(changes.Labels, ApplyChange[types.Label]{Before: &removed[i]})
+	}
+	changes.Roles = roles
+	return res, changes, nil
+}
+
+// roleApplyPlan compares the custom Roles with the provided set and returns the changes to apply
+func (f *Fish) roleApplyPlan(wanted []types.Role, prune bool) (res types.RoleApplyResult, changes []ApplyChange[types.Role], err error) {
+	res = types.RoleApplyResult{Created: []string{}, Updated: []string{}, Unchanged: []string{}, Removed: []string{}, InUse: []string{}}
+
+	existing, err := f.RoleFind()
+	if err != nil {
+		return res, nil, err
+	}
+	current := make(map[string]*types.Role, len(existing))
+	for i := range existing {
+		current[existing[i].Name] = &existing[i]
+	}
+
+	names := make(map[string]bool, len(wanted))
+	for i := range wanted {
+		r := &wanted[i]
+		if names[r.Name] {
+			return res, nil, fmt.Errorf("Fish: Role %q is duplicated in the set", r.Name)
+		}
+		names[r.Name] = true
+		if err := roleValidate(r); err != nil {
+			return res, nil, fmt.Errorf("Fish: Role %q is invalid: %w", r.Name, err)
+		}
+		cur, ok := current[r.Name]
+		switch {
+		case !ok:
+			res.Created = append(res.Created, r.Name)
+			changes = append(changes, ApplyChange[types.Role]{After: r})
+		case roleEqual(*cur, *r):
+			res.Unchanged = append(res.Unchanged, r.Name)
+		default:
+			r.CreatedAt = cur.CreatedAt
+			res.Updated = append(res.Updated, r.Name)
+			changes = append(changes, ApplyChange[types.Role]{Before: cur, After: r})
+		}
+	}
+	if !prune {
+		return res, changes, nil
+	}
+	for i := range existing {
+		r := &existing[i]
+		if names[r.Name] {
+			continue
+		}
+		var count int64
+		if err := f.db.Model(&types.RoleGrant{}).Where("role = ? AND revoked_at IS NULL", r.Name).Count(&count).Error; err != nil {
+			return res, nil, err
+		}
+		if count > 0 {
+			res.InUse = append(res.InUse, r.Name)
+		} else {
+			res.Removed = append(res.Removed, r.Name)
+			changes = append(changes, ApplyChange[types.Role]{Before: r})
+		}
+	}
+	return res, changes, nil
+}
+
+// roleEqual compares the content of the Roles ignoring the timestamps set by Fish
+func roleEqual(a, b types.Role) bool {
+	a.CreatedAt, b.CreatedAt = time.Time{}, time.Time{}
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aj, bj)
+}
+
+// labelKey is the unique identifier of the Label version
+func labelKey(name string, version int) string {
+	return fmt.Sprintf("%s:%d", name, version)
+}
+
+// labelEqual compares the content of the Labels ignoring the identifiers set by Fish
+func labelEqual(a, b types.Label) bool {
+	a.UID, b.UID = uuid.Nil, uuid.Nil
+	a.CreatedAt, b.CreatedAt = time.Time{}, time.Time{}
+	a.Definitions, b.Definitions = labelDefinitionsStored(a.Definitions), labelDefinitionsStored(b.Definitions)
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aj, bj)
+}
+
+// labelDefinitionsStored returns the copy of the definitions as they are stored in the database, so
+// the node filter is always a list
+func labelDefinitionsStored(defs types.LabelDefinitions) types.LabelDefinitions {
+	out := make(types.LabelDefinitions, len(defs))
+	copy(out, defs)
+	for i := range out {
+		if out[i].Resources.NodeFilter == nil {
+			out[i].Resources.NodeFilter = []string{}
+		}
+	}
+	return out
+}
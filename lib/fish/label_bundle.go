@@ -0,0 +1,322 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Version of the Label bundle format, the bundles of other formats are not imported
+const labelBundleFormat = 1
+
+// Conflict resolution of the Label import
+const (
+	LabelImportNewVersion = "new_version"
+	LabelImportOverwrite  = "overwrite"
+)
+
+// LabelImportChanges are the objects changed by the import to audit them
+type LabelImportChanges struct {
+	Created []types.Label
+	Removed []types.Label
+	Images  []types.Image
+}
+
+// labelBundleKeyInit loads or generates the key to sign the exported Label bundles
+func (f *Fish) labelBundleKeyInit() error {
+	if f.cfg.LabelBundle.Key == "" {
+		return nil
+	}
+	keyPath := f.cfg.LabelBundle.Key
+	if !filepath.IsAbs(keyPath) {
+		keyPath = filepath.Join(f.cfg.Directory, keyPath)
+	}
+	key, err := crypt.NewFileSignKey(keyPath)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to init label bundle key: %v", err)
+	}
+	f.labelBundleKey = key
+	return nil
+}
+
+// LabelExport returns the signed bundle of the Labels by "name" (the latest version) or
+// "name:version" with the catalog Images they reference
+func (f *Fish) LabelExport(refs []string) (*types.LabelBundle, error) {
+	if f.labelBundleKey == nil {
+		return nil, fmt.Errorf("Fish: Label bundle key is not set")
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("Fish: At least one Label is required")
+	}
+
+	bundle := &types.LabelBundle{
+		Format:    labelBundleFormat,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Source:    f.node.Name,
+		Labels:    []types.Label{},
+		Images:    []types.Image{},
+		PublicKey: f.labelBundleKey.Public(),
+	}
+	images := make(map[string]bool)
+	for _, ref := range refs {
+		label, err := f.labelGetByRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		labelBundleClean(label)
+		bundle.Labels = append(bundle.Labels, *label)
+
+		for _, def := range label.Definitions {
+			for _, m := range imageRefRegexp.FindAllStringSubmatch(string(def.Options), -1) {
+				version := 0
+				if m[3] != "" {
+					version, _ = strconv.Atoi(m[3])
+				}
+				img, err := f.imageGetByRef(m[2], version)
+				if err != nil {
+					return nil, fmt.Errorf("Fish: Unable to find Image %s referenced by Label %s: %v", m[0], ref, err)
+				}
+				if key := labelKey(img.Name, img.Version); !images[key] {
+					images[key] = true
+					bundle.Images = append(bundle.Images, *img)
+				}
+			}
+		}
+	}
+
+	payload, err := labelBundlePayload(bundle)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Signature = f.labelBundleKey.Sign(payload)
+	return bundle, nil
+}
+
+// LabelImport verifies the bundle and creates the Labels and the missing Images. The existing
+// Label version with different content is imported as the new version or overwritten unless it's
+// used by active Applications. Dry run only reports what will be changed.
+func (f *Fish) LabelImport(bundle types.LabelBundle, onConflict string, dryRun bool) (res types.LabelImportResult, changes LabelImportChanges, err error) {
+	res = types.LabelImportResult{
+		Created: []string{}, Unchanged: []string{}, Versioned: []string{}, Overwritten: []string{},
+		InUse: []string{}, ImagesCreated: []string{}, ImagesUnchanged: []string{},
+	}
+	if onConflict == "" {
+		onConflict = LabelImportNewVersion
+	}
+	if onConflict != LabelImportNewVersion && onConflict != LabelImportOverwrite {
+		return res, changes, fmt.Errorf("Fish: Unknown conflict resolution: %q", onConflict)
+	}
+	if err = f.labelBundleVerify(&bundle); err != nil {
+		return res, changes, err
+	}
+
+	// The Images are immutable, so the different checksum can't be resolved
+	for _, img := range bundle.Images {
+		key := labelKey(img.Name, img.Version)
+		cur := &types.Image{}
+		if err := f.db.Where("name = ? AND version = ?", img.Name, img.Version).First(cur).Error; err != nil {
+			img.UID = uuid.Nil
+			changes.Images = append(changes.Images, img)
+			res.ImagesCreated = append(res.ImagesCreated, key)
+		} else if cur.Checksum != img.Checksum {
+			return res, changes, fmt.Errorf("Fish: Image %s checksum %q differs from the bundle one %q", key, cur.Checksum, img.Checksum)
+		} else {
+			res.ImagesUnchanged = append(res.ImagesUnchanged, key)
+		}
+	}
+
+	stats, err := f.LabelStatsGet("", false)
+	if err != nil {
+		return res, changes, err
+	}
+	running := make(map[types.LabelUID]bool, len(stats))
+	for _, s := range stats {
+		running[s.LabelUID] = s.Running > 0
+	}
+
+	// Next versions of the Labels imported as new versions
+	nextVersion := make(map[string]int)
+	seen := make(map[string]bool, len(bundle.Labels))
+	for _, l := range bundle.Labels {
+		key := labelKey(l.Name, l.Version)
+		if seen[key] {
+			return res, changes, fmt.Errorf("Fish: Label %s is duplicated in the bundle", key)
+		}
+		seen[key] = true
+		labelBundleClean(&l)
+		if err = f.labelValidate(&l, nil, changes.Images); err != nil {
+			return res, changes, fmt.Errorf("Fish: Label %s is invalid: %w", key, err)
+		}
+
+		var versions []types.Label
+		if err = f.db.Where("name = ?", l.Name).Order("version").Find(&versions).Error; err != nil {
+			return res, changes, err
+		}
+		idx := slices.IndexFunc(versions, func(v types.Label) bool { return v.Version == l.Version })
+		if idx < 0 {
+			res.Created = append(res.Created, key)
+			changes.Created = append(changes.Created, l)
+			continue
+		}
+		cur := versions[idx]
+		labelBundleClean(&cur)
+		if labelEqual(cur, l) {
+			res.Unchanged = append(res.Unchanged, key)
+			continue
+		}
+
+		if onConflict == LabelImportOverwrite {
+			if running[versions[idx].UID] {
+				res.InUse = append(res.InUse, key)
+				continue
+			}
+			res.Overwritten = append(res.Overwritten, key)
+			changes.Removed = append(changes.Removed, versions[idx])
+			changes.Created = append(changes.Created, l)
+			continue
+		}
+
+		// The same content could be already imported as another version
+		if i := slices.IndexFunc(versions, func(v types.Label) bool {
+			labelBundleClean(&v)
+			l.Version = v.Version
+			return labelEqual(v, l)
+		}); i >= 0 {
+			res.Unchanged = append(res.Unchanged, labelKey(l.Name, versions[i].Version))
+			continue
+		}
+		if nextVersion[l.Name] == 0 {
+			nextVersion[l.Name] = versions[len(versions)-1].Version + 1
+		}
+		l.Version = nextVersion[l.Name]
+		nextVersion[l.Name]++
+		res.Versioned = append(res.Versioned, fmt.Sprintf("%s -> %s", key, labelKey(l.Name, l.Version)))
+		changes.Created = append(changes.Created, l)
+	}
+
+	if dryRun {
+		return res, LabelImportChanges{}, nil
+	}
+	if len(res.InUse) > 0 {
+		return res, LabelImportChanges{}, fmt.Errorf("Fish: Label versions used by active Applications can't be overwritten: %v", res.InUse)
+	}
+
+	done := LabelImportChanges{}
+	for i := range changes.Images {
+		if err = f.ImageCreate(&changes.Images[i]); err != nil {
+			return res, done, fmt.Errorf("Fish: Unable to create Image: %v", err)
+		}
+		done.Images = append(done.Images, changes.Images[i])
+	}
+	for i := range changes.Removed {
+		if err = f.LabelDelete(changes.Removed[i].UID); err != nil {
+			return res, done, err
+		}
+		done.Removed = append(done.Removed, changes.Removed[i])
+	}
+	for i := range changes.Created {
+		if err = f.labelInsert(&changes.Created[i]); err != nil {
+			return res, done, err
+		}
+		done.Created = append(done.Created, changes.Created[i])
+	}
+	return res, done, nil
+}
+
+// labelBundleVerify checks the bundle is signed by the own or trusted key
+func (f *Fish) labelBundleVerify(bundle *types.LabelBundle) error {
+	if bundle.Format != labelBundleFormat {
+		return fmt.Errorf("Fish: Unsupported label bundle format: %d", bundle.Format)
+	}
+	trusted := slices.Contains(f.cfg.LabelBundle.TrustedKeys, bundle.PublicKey)
+	if f.labelBundleKey != nil && f.labelBundleKey.Public() == bundle.PublicKey {
+		trusted = true
+	}
+	if !trusted {
+		return fmt.Errorf("Fish: Label bundle key is not trusted: %s", bundle.PublicKey)
+	}
+	payload, err := labelBundlePayload(bundle)
+	if err != nil {
+		return err
+	}
+	if err = crypt.SignVerify(bundle.PublicKey, payload, bundle.Signature); err != nil {
+		return fmt.Errorf("Fish: Label bundle verification failed: %v", err)
+	}
+	return nil
+}
+
+// labelGetByRef returns the Label by "name:version" or by "name" as the latest version
+func (f *Fish) labelGetByRef(ref string) (*types.Label, error) {
+	name, ver, hasVer := strings.Cut(ref, ":")
+	if !hasVer {
+		l, err := f.LabelGetLatest(name)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to find Label %s: %v", ref, err)
+		}
+		return l, nil
+	}
+	version, err := strconv.Atoi(ver)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Invalid version of Label %s: %v", ref, err)
+	}
+	l := &types.Label{}
+	if err = f.db.Where("name = ? AND version = ?", name, version).First(l).Error; err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find Label %s: %v", ref, err)
+	}
+	return l, nil
+}
+
+// labelBundleClean removes the node-specific fields and the secrets from the Label, the parents
+// are already merged into it so not needed on the other cluster
+func labelBundleClean(l *types.Label) {
+	l.UID = uuid.Nil
+	l.CreatedAt = time.Time{}
+	l.Extends = nil
+	for i, def := range l.Definitions {
+		if def.Authentication != nil {
+			auth := *def.Authentication
+			auth.Password, auth.Key = "", ""
+			l.Definitions[i].Authentication = &auth
+		}
+	}
+}
+
+// labelBundlePayload returns the canonical json of the bundle without signature, so the YAML or
+// reformatted bundle has the same payload
+func labelBundlePayload(bundle *types.LabelBundle) ([]byte, error) {
+	unsigned := *bundle
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to serialize label bundle: %v", err)
+	}
+	var canonical any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&canonical); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to serialize label bundle: %v", err)
+	}
+	return json.Marshal(canonical)
+}
@@ -0,0 +1,101 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"path"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// labelCompatGet returns which definitions of the Label could be served by this node drivers and
+// identifiers. Labels are immutable and the drivers and identifiers are set on the node start, so
+// the result is cached till the Label is removed.
+func (f *Fish) labelCompatGet(label *types.Label) []bool {
+	f.labelCompatMutex.Lock()
+	defer f.labelCompatMutex.Unlock()
+
+	if compat, ok := f.labelCompat[label.UID]; ok {
+		return compat
+	}
+	compat := make([]bool, len(label.Definitions))
+	for i, def := range label.Definitions {
+		compat[i] = f.isNodeCompatibleWithDefinition(def)
+	}
+	f.labelCompat[label.UID] = compat
+	return compat
+}
+
+// labelCompatRemove drops the removed Label from the index
+func (f *Fish) labelCompatRemove(uid types.LabelUID) {
+	f.labelCompatMutex.Lock()
+	defer f.labelCompatMutex.Unlock()
+	delete(f.labelCompat, uid)
+}
+
+// LabelCompatibilityList returns the Labels this node could serve with the compatible definitions
+// and their estimated hourly cost
+func (f *Fish) LabelCompatibilityList() ([]types.LabelCompatibility, error) {
+	labels, err := f.LabelFind(nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	out := []types.LabelCompatibility{}
+	for i := range labels {
+		var defs []int
+		var costs []float64
+		for index, ok := range f.labelCompatGet(&labels[i]) {
+			if ok {
+				defs = append(defs, index)
+				costs = append(costs, f.definitionHourlyCost(labels[i].Definitions[index]))
+			}
+		}
+		if len(defs) > 0 {
+			out = append(out, types.LabelCompatibility{
+				LabelUID:    labels[i].UID,
+				Name:        labels[i].Name,
+				Version:     labels[i].Version,
+				Definitions: defs,
+				HourlyCosts: costs,
+			})
+		}
+	}
+	return out, nil
+}
+
+// isNodeCompatibleWithDefinition checks the node has the driver and identifiers to serve the
+// definition, the capacity is not checked here
+func (f *Fish) isNodeCompatibleWithDefinition(def types.LabelDefinition) bool {
+	// Is node supports the required label driver
+	if f.driverGet(def.Driver) == nil {
+		return false
+	}
+
+	// Verify node filters because some workload can't be running on all the physical nodes
+	// The node becomes fitting only when all the needed node filter patterns are matched
+	for _, needed := range def.Resources.NodeFilter {
+		found := false
+		for _, value := range f.cfg.NodeIdentifiers {
+			// We're validating the pattern on error during label creation, so they should be ok
+			if found, _ = path.Match(needed, value); found {
+				break
+			}
+		}
+		if !found {
+			// One of the required node identifiers did not matched the node ones
+			return false
+		}
+	}
+	// Here all the node filters matched the node identifiers
+	return true
+}
@@ -0,0 +1,167 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// labelResolve merges the parent Labels listed in extends into the Label, so the stored version
+// contains the concrete definitions and the Applications are not affected by the parents changes.
+// The parents are looked up in pending first (to extend the Labels of the same apply set) and then
+// in the database, the references are stored as exact "name:version" to keep the provenance.
+func (f *Fish) labelResolve(l *types.Label, pending map[string]*types.Label) error {
+	if len(l.Extends) == 0 {
+		return nil
+	}
+
+	merged := &types.Label{Metadata: "{}"}
+	refs := make([]string, 0, len(l.Extends))
+	for _, ref := range l.Extends {
+		parent, err := f.labelParent(ref, pending)
+		if err != nil {
+			return err
+		}
+		if parent.Name == l.Name {
+			return fmt.Errorf("Fish: Label can't extend the version of itself: %s", ref)
+		}
+		refs = append(refs, labelKey(parent.Name, parent.Version))
+		if err = labelMerge(merged, parent); err != nil {
+			return fmt.Errorf("Fish: Unable to merge parent Label %s: %v", ref, err)
+		}
+	}
+	if err := labelMerge(merged, l); err != nil {
+		return fmt.Errorf("Fish: Unable to merge Label with parents: %v", err)
+	}
+
+	l.Extends = refs
+	l.Definitions = merged.Definitions
+	l.Metadata = merged.Metadata
+	l.MetadataSchema = merged.MetadataSchema
+	l.Priority = merged.Priority
+	l.AccessOtp = merged.AccessOtp
+	l.RequiresApproval = merged.RequiresApproval
+	return nil
+}
+
+// labelParent finds the parent Label by "name:version" or by "name" as the latest version
+func (f *Fish) labelParent(ref string, pending map[string]*types.Label) (*types.Label, error) {
+	name, ver, hasVer := strings.Cut(ref, ":")
+	if !hasVer {
+		var latest *types.Label
+		if l, err := f.LabelGetLatest(name); err == nil {
+			latest = l
+		}
+		for _, l := range pending {
+			if l.Name == name && (latest == nil || l.Version > latest.Version) {
+				latest = l
+			}
+		}
+		if latest == nil {
+			return nil, fmt.Errorf("Fish: Unable to find parent Label %s", ref)
+		}
+		return latest, nil
+	}
+
+	version, err := strconv.Atoi(ver)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Invalid version of parent Label %s: %v", ref, err)
+	}
+	if l, ok := pending[labelKey(name, version)]; ok {
+		return l, nil
+	}
+	l := &types.Label{}
+	if err = f.db.Where("name = ? AND version = ?", name, version).First(l).Error; err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find parent Label %s", ref)
+	}
+	return l, nil
+}
+
+// labelMerge overrides the base Label with the set fields of the other one. Definitions are merged
+// by index: driver and resources are replaced by the set (not zero) values, options are merged
+// deeply and authentication, recycle, proxy ssh policy and health check are replaced as a whole.
+// The additional definitions are appended, the metadata schema is replaced as a whole. Label is
+// sensitive or requires approval if any of the parents does.
+func labelMerge(base, over *types.Label) error {
+	for i, def := range over.Definitions {
+		if i >= len(base.Definitions) {
+			base.Definitions = append(base.Definitions, def)
+			continue
+		}
+		bdef := &base.Definitions[i]
+		if def.Driver != "" {
+			bdef.Driver = def.Driver
+		}
+
+		bres, err := json.Marshal(bdef.Resources)
+		if err != nil {
+			return err
+		}
+		ores, err := json.Marshal(def.Resources)
+		if err != nil {
+			return err
+		}
+		mres, err := util.MergeJSON(bres, ores, true)
+		if err != nil {
+			return err
+		}
+		var res types.Resources
+		if err = json.Unmarshal(mres, &res); err != nil {
+			return err
+		}
+		bdef.Resources = res
+
+		if def.Options != "" {
+			opts, err := util.MergeJSON([]byte(bdef.Options), []byte(def.Options), false)
+			if err != nil {
+				return fmt.Errorf("options of definition %d: %v", i, err)
+			}
+			bdef.Options = util.UnparsedJSON(opts)
+		}
+		if def.Authentication != nil {
+			bdef.Authentication = def.Authentication
+		}
+		if def.Recycle != nil {
+			bdef.Recycle = def.Recycle
+		}
+		if def.ProxySshPolicy != nil {
+			bdef.ProxySshPolicy = def.ProxySshPolicy
+		}
+		if def.HealthCheck != nil {
+			bdef.HealthCheck = def.HealthCheck
+		}
+	}
+
+	if over.Metadata != "" {
+		metadata, err := util.MergeJSON([]byte(base.Metadata), []byte(over.Metadata), false)
+		if err != nil {
+			return fmt.Errorf("metadata: %v", err)
+		}
+		base.Metadata = util.UnparsedJSON(metadata)
+	}
+	if over.MetadataSchema != "" && over.MetadataSchema != "{}" {
+		base.MetadataSchema = over.MetadataSchema
+	}
+	if over.Priority != 0 {
+		base.Priority = over.Priority
+	}
+	base.AccessOtp = base.AccessOtp || over.AccessOtp
+	base.RequiresApproval = base.RequiresApproval || over.RequiresApproval
+	return nil
+}
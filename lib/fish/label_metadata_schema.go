@@ -0,0 +1,75 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// labelMetadataSchema parses the Label metadata schema, nil if the schema is empty
+func labelMetadataSchema(raw util.UnparsedJSON) (*openapi3.Schema, error) {
+	if strings.TrimSpace(string(raw)) == "" || strings.TrimSpace(string(raw)) == "{}" {
+		return nil, nil
+	}
+	schema := &openapi3.Schema{}
+	if err := json.Unmarshal([]byte(raw), schema); err != nil {
+		return nil, &ValidationError{Field: "metadata_schema", Message: fmt.Sprintf("Unable to parse metadata schema: %v", err)}
+	}
+	if err := schema.Validate(context.Background()); err != nil {
+		return nil, &ValidationError{Field: "metadata_schema", Message: fmt.Sprintf("Metadata schema is invalid: %v", err)}
+	}
+	return schema, nil
+}
+
+// metadataSchemaValidate checks the Application metadata matches the Label metadata schema and
+// returns all the found issues at once to fix them in one go
+func metadataSchemaValidate(label *types.Label, metadata util.UnparsedJSON) error {
+	schema, err := labelMetadataSchema(label.MetadataSchema)
+	if err != nil || schema == nil {
+		return err
+	}
+	var value any
+	if err = json.Unmarshal([]byte(metadata), &value); err != nil {
+		return &ValidationError{Field: "metadata", Message: fmt.Sprintf("Metadata should be a valid JSON: %v", err)}
+	}
+	if err = schema.VisitJSON(value, openapi3.MultiErrors()); err == nil {
+		return nil
+	}
+
+	var issues []string
+	var merr openapi3.MultiError
+	if !errors.As(err, &merr) {
+		merr = openapi3.MultiError{err}
+	}
+	for _, e := range merr {
+		var serr *openapi3.SchemaError
+		if !errors.As(e, &serr) {
+			issues = append(issues, e.Error())
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("/%s: %s", strings.Join(serr.JSONPointer(), "/"), serr.Reason))
+	}
+	return &ValidationError{
+		Field:   "metadata",
+		Message: fmt.Sprintf("Metadata doesn't match the Label %s:%d schema: %s", label.Name, label.Version, strings.Join(issues, "; ")),
+	}
+}
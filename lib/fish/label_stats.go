@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// labelStatsState is the Application state row used to calculate the Label stats
+type labelStatsState struct {
+	LabelUID       types.LabelUID
+	ApplicationUID types.ApplicationUID
+	Status         types.ApplicationStatus
+	CreatedAt      time.Time
+}
+
+// LabelStatsGet returns usage statistics of the Label versions, name filters the Labels if set
+func (f *Fish) LabelStatsGet(name string, report bool) ([]types.LabelStats, error) {
+	db := f.dbFind(report)
+
+	var labels []types.Label
+	ldb := db.Order("name, version")
+	if name != "" {
+		ldb = ldb.Where("name = ?", name)
+	}
+	if err := ldb.Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return []types.LabelStats{}, nil
+	}
+	uids := make([]types.LabelUID, len(labels))
+	for i := range labels {
+		uids[i] = labels[i].UID
+	}
+
+	// All the states are needed to find allocation & deallocation time of the Applications
+	var rows []labelStatsState
+	err := db.Table("application_states s").
+		Select("a.label_uid, s.application_uid, s.status, s.created_at").
+		Joins("JOIN applications a ON a.uid = s.application_uid").
+		Where("a.label_uid IN ?", uids).
+		Order("s.created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type appStats struct {
+		label       types.LabelUID
+		status      types.ApplicationStatus
+		failed      bool
+		allocatedAt time.Time
+		finishedAt  time.Time
+	}
+	apps := make(map[types.ApplicationUID]*appStats)
+	for _, row := range rows {
+		app, ok := apps[row.ApplicationUID]
+		if !ok {
+			app = &appStats{label: row.LabelUID}
+			apps[row.ApplicationUID] = app
+		}
+		// Rows are ordered so the last one is the current status
+		app.status = row.Status
+		switch row.Status {
+		case types.ApplicationStatusERROR:
+			app.failed = true
+		case types.ApplicationStatusALLOCATED:
+			if app.allocatedAt.IsZero() {
+				app.allocatedAt = row.CreatedAt
+			}
+		case types.ApplicationStatusDEALLOCATED:
+			app.finishedAt = row.CreatedAt
+		}
+	}
+
+	type labelSums struct {
+		stats    types.LabelStats
+		finished int
+		lived    int
+		lifetime time.Duration
+	}
+	sums := make(map[types.LabelUID]*labelSums, len(labels))
+	for i := range labels {
+		sums[labels[i].UID] = &labelSums{stats: types.LabelStats{
+			LabelUID: labels[i].UID,
+			Name:     labels[i].Name,
+			Version:  labels[i].Version,
+		}}
+	}
+	for _, app := range apps {
+		sum := sums[app.label]
+		sum.stats.Total++
+		if f.ApplicationStateIsActive(app.status) {
+			sum.stats.Running++
+			continue
+		}
+		sum.finished++
+		if app.failed {
+			sum.stats.Failed++
+		}
+		if !app.allocatedAt.IsZero() && app.finishedAt.After(app.allocatedAt) {
+			sum.lived++
+			sum.lifetime += app.finishedAt.Sub(app.allocatedAt)
+		}
+	}
+
+	out := make([]types.LabelStats, 0, len(labels))
+	for i := range labels {
+		sum := sums[labels[i].UID]
+		if sum.finished > 0 {
+			sum.stats.FailureRate = float32(sum.stats.Failed) / float32(sum.finished)
+		}
+		if sum.lived > 0 {
+			sum.stats.AvgLifetime = util.Duration(sum.lifetime / time.Duration(sum.lived))
+		}
+		out = append(out, sum.stats)
+	}
+	return out, nil
+}
@@ -0,0 +1,187 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// lifetimeExtendNotification is the webhook request body to notify the Application owner
+type lifetimeExtendNotification struct {
+	Action         string               `json:"action"`
+	ApplicationUID types.ApplicationUID `json:"application_uid"`
+	ShortID        string               `json:"short_id"`
+	Owner          string               `json:"owner"`
+	ExtendedTill   time.Time            `json:"extended_till"`
+	Final          bool                 `json:"final"`
+}
+
+// ApplicationExtendPrefix starts the description of the ALLOCATED state created by the lifetime
+// extension request, such states are not the new allocations
+const ApplicationExtendPrefix = "Lifetime extended"
+
+// ApplicationExtend moves the Resource lifetime deadline of the allocated Application within the
+// Label definition policy and records it as the new state, which the executing node picks up
+func (f *Fish) ApplicationExtend(app *types.Application, req *types.ApplicationExtend, requester string) (*types.ApplicationState, error) {
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to parse the Duration: %v", err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("Fish: Duration should be positive: %s", duration)
+	}
+
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find status for the Application %s: %v", app.UID, err)
+	}
+	if state.Status != types.ApplicationStatusALLOCATED {
+		return nil, fmt.Errorf("Fish: Unable to extend lifetime of the Application with status: %s", state.Status)
+	}
+	res, err := f.ResourceGetByApplication(app.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find the Resource of Application %s: %v", app.UID, err)
+	}
+	label, err := f.LabelGet(res.LabelUID)
+	if err != nil || res.DefinitionIndex >= len(label.Definitions) {
+		return nil, fmt.Errorf("Fish: Unable to find the Label definition of Application %s: %v", app.UID, err)
+	}
+	def := &label.Definitions[res.DefinitionIndex]
+
+	lifetime, _ := f.resourceLifetimeGet(def)
+	if lifetime <= 0 {
+		return nil, fmt.Errorf("Fish: The Resource has no lifetime limit")
+	}
+	maxExtension, _ := time.ParseDuration(def.Resources.MaxExtension)
+	if maxExtension <= 0 {
+		return nil, fmt.Errorf("Fish: The Label doesn't allow to extend the Resource lifetime")
+	}
+	if duration > maxExtension {
+		return nil, fmt.Errorf("Fish: The Label allows to extend the Resource lifetime by %s max", maxExtension)
+	}
+
+	deadline := res.CreatedAt.Add(lifetime)
+	if state.ExpiresAt != nil && state.ExpiresAt.After(deadline) {
+		deadline = *state.ExpiresAt
+	}
+	// The Resource could be kept after the deadline by the automatic extension
+	if deadline.Before(time.Now()) {
+		deadline = time.Now()
+	}
+	expires := deadline.Add(duration)
+	if maxLifetime, _ := time.ParseDuration(def.Resources.MaxLifetime); maxLifetime > 0 {
+		if limit := res.CreatedAt.Add(maxLifetime); expires.After(limit) {
+			return nil, fmt.Errorf("Fish: The Resource max lifetime %s will be exceeded, it could be extended till %s", maxLifetime, limit.Format(time.RFC3339))
+		}
+	}
+
+	as := &types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusALLOCATED,
+		Description: fmt.Sprintf("%s by user %s for %s till %s", ApplicationExtendPrefix, requester, duration, expires.Format(time.RFC3339)),
+		ExpiresAt:   &expires,
+	}
+	if req.Reason != nil && *req.Reason != "" {
+		as.Description += ": " + *req.Reason
+	}
+	if err := f.ApplicationStateCreate(as); err != nil {
+		return nil, fmt.Errorf("Fish: Unable to extend lifetime of the Application %s: %v", app.UID, err)
+	}
+	log.Infof("Fish: AUDIT: Resource lifetime of Application %s is extended by %s till %s by user %s", app.UID, duration, expires, requester)
+	return as, nil
+}
+
+// resourceLifetimeGet returns the Resource lifetime of the Label definition or the node default,
+// the error is returned if the definition lifetime can't be parsed
+func (f *Fish) resourceLifetimeGet(def *types.LabelDefinition) (time.Duration, error) {
+	lifetime, err := time.ParseDuration(def.Resources.Lifetime)
+	if err == nil {
+		return lifetime, nil
+	}
+	if def.Resources.Lifetime == "" {
+		err = nil
+	}
+	// Try to get default value from fish config
+	lifetime, defErr := time.ParseDuration(f.cfg.DefaultResourceLifetime)
+	if defErr != nil {
+		// Not an error - in worst case the resource will just sit there but at least will
+		// not ruin the workload execution
+		log.Warn("Fish: Default Resource Lifetime is not set in fish config")
+	}
+	return lifetime, err
+}
+
+// resourceLifetimeExtend returns the new timeout of the Resource which is still in use on expiry,
+// the total extension is bounded so the Resource will not live forever
+func (f *Fish) resourceLifetimeExtend(app *types.Application, res *types.Resource, timeout time.Time, lifetime time.Duration) time.Time {
+	cfg := f.cfg.LifetimeExtend
+	if cfg.Grace <= 0 {
+		return timeout
+	}
+	last, ok := f.resourceLastActivity(res.UID)
+	if !ok || time.Since(last) > time.Duration(cfg.Active) {
+		return timeout
+	}
+
+	deadline := res.CreatedAt.Add(lifetime + time.Duration(cfg.Max))
+	extended := timeout.Add(time.Duration(cfg.Grace))
+	if extended.After(deadline) {
+		extended = deadline
+	}
+	if !extended.After(time.Now()) {
+		return timeout
+	}
+
+	log.Infof("Fish: AUDIT: Resource lifetime of Application %s is extended till %s since it's still in use", app.UID, extended)
+	if cfg.Webhook != "" {
+		n := &lifetimeExtendNotification{
+			Action:         "lifetime_extend",
+			ApplicationUID: app.UID,
+			ShortID:        app.ShortId,
+			Owner:          app.OwnerName,
+			ExtendedTill:   extended,
+			Final:          !extended.Before(deadline),
+		}
+		go func() {
+			if err := webhookNotify(cfg.Webhook, n); err != nil {
+				log.Warn("Fish: Unable to notify owner about lifetime extension of Application:", app.UID, err)
+			}
+		}()
+	}
+	return extended
+}
+
+// webhookNotify sends the notification to the webhook
+func webhookNotify(url string, n any) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Fish: Webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// ValidationError describes which field of the object is not passing the check, so the API could
+// return the structured error to the client
+type ValidationError struct {
+	Field   string // The object field name as in API
+	Limit   string // The limit which was exceeded, empty if it's not about the limit
+	Message string
+}
+
+// Error returns the validation error message
+func (e *ValidationError) Error() string {
+	return "Fish: " + e.Message
+}
+
+// LimitsGet returns the configured size limits
+func (f *Fish) LimitsGet() ConfigLimits {
+	return f.cfg.Limits
+}
+
+// limitMetadata checks the metadata is not exceeding the configured size
+func (f *Fish) limitMetadata(metadata util.UnparsedJSON) error {
+	if size := util.HumanSize(len(metadata)); size > f.cfg.Limits.Metadata {
+		return &ValidationError{
+			Field:   "metadata",
+			Limit:   f.cfg.Limits.Metadata.String(),
+			Message: fmt.Sprintf("Metadata size %s exceeds the limit %s", size, f.cfg.Limits.Metadata),
+		}
+	}
+	return nil
+}
+
+// limitLabelDefinitions checks the number of the Label definitions
+func (f *Fish) limitLabelDefinitions(count int) error {
+	if count > f.cfg.Limits.LabelDefinitions {
+		return &ValidationError{
+			Field:   "definitions",
+			Limit:   fmt.Sprint(f.cfg.Limits.LabelDefinitions),
+			Message: fmt.Sprintf("Number of definitions %d exceeds the limit %d", count, f.cfg.Limits.LabelDefinitions),
+		}
+	}
+	return nil
+}
+
+// limitBatchCount checks the number of the Applications in batch
+func (f *Fish) limitBatchCount(count int) error {
+	if count < 1 || count > f.cfg.Limits.BatchCount {
+		return &ValidationError{
+			Field:   "count",
+			Limit:   fmt.Sprint(f.cfg.Limits.BatchCount),
+			Message: fmt.Sprintf("Count should be in range 1-%d", f.cfg.Limits.BatchCount),
+		}
+	}
+	return nil
+}
+
+// limitPreference checks the size of the User preference value
+func (f *Fish) limitPreference(value util.UnparsedJSON) error {
+	if size := util.HumanSize(len(value)); size > f.cfg.Limits.Preference {
+		return &ValidationError{
+			Field:   "value",
+			Limit:   f.cfg.Limits.Preference.String(),
+			Message: fmt.Sprintf("Preference size %s exceeds the limit %s", size, f.cfg.Limits.Preference),
+		}
+	}
+	return nil
+}
+
+// limitPreferences checks the number of the User preferences
+func (f *Fish) limitPreferences(count int) error {
+	if count > f.cfg.Limits.Preferences {
+		return &ValidationError{
+			Field:   "key",
+			Limit:   fmt.Sprint(f.cfg.Limits.Preferences),
+			Message: fmt.Sprintf("Number of preferences %d exceeds the limit %d", count, f.cfg.Limits.Preferences),
+		}
+	}
+	return nil
+}
@@ -20,9 +20,9 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// LocationFind returns list of Locations fits filter
-func (f *Fish) LocationFind(filter *string) (ls []types.Location, err error) {
-	db := f.db
+// LocationFind returns list of Locations fits filter, report uses the replica
+func (f *Fish) LocationFind(filter *string, report bool) (ls []types.Location, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
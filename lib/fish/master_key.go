@@ -0,0 +1,85 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/secrets"
+)
+
+// masterKeyInit enables the envelope encryption of the Authentication and User OTP secrets stored
+// in database and encrypts the ones stored in plaintext before
+func (f *Fish) masterKeyInit() error {
+	var master crypt.MasterKey
+	var err error
+	switch {
+	case f.cfg.MasterKey.KMSKeyID != "":
+		master, err = secrets.NewKMSMasterKey(f.cfg.MasterKey.KMSKeyID)
+	case f.cfg.MasterKey.File != "":
+		keyPath := f.cfg.MasterKey.File
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(f.cfg.Directory, keyPath)
+		}
+		master, err = crypt.NewFileMasterKey(keyPath)
+	default:
+		log.Warn("Fish: Master key is not set, Authentication secrets are stored in plaintext")
+	}
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to init master key: %v", err)
+	}
+	if err = crypt.EnvelopeInit(master); err != nil {
+		return fmt.Errorf("Fish: Unable to init master key: %v", err)
+	}
+	if master == nil {
+		return nil
+	}
+
+	// Saving the objects with plaintext secrets will encrypt them
+	var labels []types.Label
+	if err = f.db.Where("definitions LIKE ? AND definitions NOT LIKE ?", `%"authentication":{%`, "%$enc1$%").Find(&labels).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to find Labels to encrypt: %v", err)
+	}
+	for i := range labels {
+		if err = f.db.Save(&labels[i]).Error; err != nil {
+			return fmt.Errorf("Fish: Unable to encrypt Label %s: %v", labels[i].UID, err)
+		}
+	}
+	var resources []types.Resource
+	if err = f.db.Where("authentication IS NOT NULL AND authentication NOT LIKE ?", "%$enc1$%").Find(&resources).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to find Resources to encrypt: %v", err)
+	}
+	for i := range resources {
+		if err = f.ResourceSave(&resources[i]); err != nil {
+			return fmt.Errorf("Fish: Unable to encrypt Resource %s: %v", resources[i].UID, err)
+		}
+	}
+	var otps []userOTP
+	if err = f.db.Where("secret NOT LIKE ?", "$enc1$%").Find(&otps).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to find User OTP secrets to encrypt: %v", err)
+	}
+	for i := range otps {
+		if err = f.db.Save(&otps[i]).Error; err != nil {
+			return fmt.Errorf("Fish: Unable to encrypt User %q OTP secret: %v", otps[i].UserName, err)
+		}
+	}
+	if len(labels) > 0 || len(resources) > 0 || len(otps) > 0 {
+		log.Infof("Fish: Encrypted secrets of %d Labels, %d Resources and %d User OTPs", len(labels), len(resources), len(otps))
+	}
+
+	return nil
+}
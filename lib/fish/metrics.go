@@ -0,0 +1,123 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/metrics"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+var (
+	metricAllocateLatency = metrics.NewHistogram("fish_application_allocate_latency_seconds",
+		"Time from the Application creation till the Resource is allocated", nil, "driver")
+	metricDriverDuration = metrics.NewHistogram("fish_driver_call_duration_seconds",
+		"Duration of the driver allocate and deallocate calls", nil, "driver", "operation")
+	metricDriverErrors = metrics.NewCounter("fish_driver_errors_total",
+		"Amount of failed driver allocate and deallocate calls", "driver", "operation")
+	metricDBDuration = metrics.NewHistogram("fish_db_operation_duration_seconds",
+		"Latency of the database operations", []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5}, "operation", "table")
+
+	// The node to collect the Applications metrics from
+	metricsFish atomic.Pointer[Fish]
+)
+
+func init() {
+	metrics.NewGaugeFunc("fish_applications", "Amount of Applications by the current state", func() []metrics.Sample {
+		f := metricsFish.Load()
+		if f == nil {
+			return nil
+		}
+		return f.metricApplications()
+	}, "status")
+}
+
+// metricApplications counts the Applications by the latest state
+func (f *Fish) metricApplications() (samples []metrics.Sample) {
+	var rows []struct {
+		Status types.ApplicationStatus
+		Count  int64
+	}
+	// SELECT status, count(*) FROM (
+	//     SELECT application_uid, status, max(created_at) FROM application_states GROUP BY application_uid
+	// ) GROUP BY status
+	db := f.db
+	err := db.Table("(?)",
+		db.Model(&types.ApplicationState{}).Select("application_uid, status, max(created_at)").Group("application_uid"),
+	).Select("status, count(*) as count").Group("status").Scan(&rows).Error
+	if err != nil {
+		log.Warn("Fish: Unable to collect Applications metrics:", err)
+		return nil
+	}
+	for _, r := range rows {
+		samples = append(samples, metrics.Sample{Labels: []string{string(r.Status)}, Value: float64(r.Count)})
+	}
+	return samples
+}
+
+// metricsInit starts collecting the node metrics
+func (f *Fish) metricsInit() error {
+	metricsFish.Store(f)
+	return metricsRegisterDBCallbacks(f.db)
+}
+
+// metricDriverCall records duration and the result of the driver call
+func metricDriverCall(driver, operation string, start time.Time, err *error) {
+	metricDriverDuration.Observe(time.Since(start).Seconds(), driver, operation)
+	if *err != nil {
+		metricDriverErrors.Inc(driver, operation)
+	}
+}
+
+// metricsRegisterDBCallbacks adds gorm callbacks to measure the database operations latency
+func metricsRegisterDBCallbacks(db *gorm.DB) error {
+	const startKey = "metrics:start"
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			if v, ok := tx.InstanceGet(startKey); ok {
+				if start, ok := v.(time.Time); ok {
+					metricDBDuration.Observe(time.Since(start).Seconds(), operation, tx.Statement.Table)
+				}
+			}
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,14 +19,18 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/adobe/aquarium-fish/lib/inventory"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// NodeFind returns list of Nodes that fits filter
-func (f *Fish) NodeFind(filter *string) (ns []types.Node, err error) {
-	db := f.db
+// DrainRequester is used as the deallocate requester when the Node drain timeout is reached
+const DrainRequester = "fish-drain"
+
+// NodeFind returns list of Nodes that fits filter and page, report uses the replica
+func (f *Fish) NodeFind(filter *string, page *ListPage, report bool) (ns []types.Node, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -36,8 +40,13 @@ func (f *Fish) NodeFind(filter *string) (ns []types.Node, err error) {
 		}
 		db = db.Where(securedFilter)
 	}
-	err = db.Find(&ns).Error
-	return ns, err
+	if db, err = page.query(db, []string{"name"}, "uid"); err != nil {
+		return ns, err
+	}
+	if err = db.Find(&ns).Error; err != nil {
+		return ns, err
+	}
+	return cutPage(page, ns)
 }
 
 // NodeGet returns Node by it's unique name
@@ -47,6 +56,13 @@ func (f *Fish) NodeGet(name string) (node *types.Node, err error) {
 	return node, err
 }
 
+// NodeGetUID returns Node by it's UID
+func (f *Fish) NodeGetUID(uid types.NodeUID) (node *types.Node, err error) {
+	node = &types.Node{}
+	err = f.db.First(node, uid).Error
+	return node, err
+}
+
 // NodeActiveList lists all the nodes in the cluster
 func (f *Fish) NodeActiveList() (ns []types.Node, err error) {
 	// Only the nodes that pinged at least twice the delay time
@@ -93,5 +109,143 @@ func (f *Fish) pingProcess() {
 		<-pingTicker.C
 		log.Debug("Fish Node: ping")
 		f.NodePing(f.node)
+		f.nodeMaintenanceSync()
+	}
+}
+
+// inventoryProcess re-detects the Node hardware inventory to catch the host changes
+func (f *Fish) inventoryProcess() {
+	ticker := time.NewTicker(time.Duration(f.cfg.InventoryInterval))
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		if !f.running {
+			break
+		}
+		f.node.Definition.Update()
+		inv := inventory.Detect()
+		f.node.Definition.Inventory = &inv
+		if err := f.db.Model(f.node).Update("definition", f.node.Definition).Error; err != nil {
+			log.Error("Fish Node: Unable to update the node inventory:", err)
+		}
+	}
+}
+
+// NodeMaintenanceRequest switches the maintenance mode of any Node in the cluster, the Node
+// picks it up on the next ping. With drain the executing Applications will be deallocated
+// when the timeout is reached, otherwise the Node waits for them to complete.
+func (f *Fish) NodeMaintenanceRequest(uid types.NodeUID, enable, drain bool, timeout time.Duration) (*types.Node, error) {
+	if timeout < 0 {
+		return nil, fmt.Errorf("Fish: Drain timeout can't be negative: %v", timeout)
+	}
+	node, err := f.NodeGetUID(uid)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find Node %s: %v", uid, err)
+	}
+
+	if enable && !node.Maintenance {
+		node.DrainStartedAt = time.Now()
+	} else if !enable {
+		// Returning the Node to service cancels the requested shutdown too
+		node.DrainStartedAt = time.Time{}
+		node.Shutdown = false
+		node.Handover = false
+	}
+	node.Maintenance = enable
+	node.Drain = enable && drain
+	node.DrainTimeout = util.Duration(timeout)
+	if err = f.nodeMaintenanceSave(node); err != nil {
+		return nil, err
+	}
+
+	// This node can apply the change right away
+	if node.UID == f.node.UID {
+		f.nodeMaintenanceSync()
+	}
+	return node, nil
+}
+
+// NodeDrainGet reports the maintenance progress of the Node
+func (f *Fish) NodeDrainGet(uid types.NodeUID) (*types.NodeDrain, error) {
+	node, err := f.NodeGetUID(uid)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find Node %s: %v", uid, err)
+	}
+	rs, err := f.ResourceListNode(node.UID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to list Resources of Node %s: %v", uid, err)
+	}
+
+	drain := &types.NodeDrain{
+		NodeUID:      node.UID,
+		Maintenance:  node.Maintenance,
+		Drain:        node.Drain,
+		StartedAt:    node.DrainStartedAt,
+		Remaining:    len(rs),
+		Applications: []types.ApplicationUID{},
+		Drained:      node.Maintenance && len(rs) == 0,
+		Handover:     node.Handover,
+	}
+	if node.Drain {
+		drain.Deadline = node.DrainStartedAt.Add(time.Duration(node.DrainTimeout))
+	}
+	for _, res := range rs {
+		drain.Applications = append(drain.Applications, res.ApplicationUID)
+	}
+	return drain, nil
+}
+
+// nodeMaintenanceSave stores just the maintenance fields of the Node to not override the others
+func (f *Fish) nodeMaintenanceSave(node *types.Node) error {
+	err := f.db.Model(node).Select("maintenance", "drain", "drain_timeout", "drain_started_at", "shutdown", "handover").Updates(node).Error
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to save Node %s maintenance: %v", node.UID, err)
+	}
+	return nil
+}
+
+// nodeMaintenanceSync applies the requested maintenance mode of this node and deallocates the
+// Applications left when the drain timeout is reached
+func (f *Fish) nodeMaintenanceSync() {
+	f.nodeMaintenanceMutex.Lock()
+	defer f.nodeMaintenanceMutex.Unlock()
+
+	node, err := f.NodeGetUID(f.node.UID)
+	if err != nil {
+		log.Error("Fish: Unable to get the node maintenance state:", err)
+		return
+	}
+	f.node.Maintenance = node.Maintenance
+	f.node.Drain = node.Drain
+	f.node.DrainTimeout = node.DrainTimeout
+	f.node.DrainStartedAt = node.DrainStartedAt
+	f.node.Shutdown = node.Shutdown
+	f.node.Handover = node.Handover
+	f.MaintenanceSet(node.Maintenance)
+	// Shutdown goes after maintenance to wait for the Applications to complete
+	f.ShutdownSet(node.Shutdown)
+
+	if !node.Drain || time.Now().Before(node.DrainStartedAt.Add(time.Duration(node.DrainTimeout))) {
+		return
+	}
+	rs, err := f.ResourceListNode(node.UID)
+	if err != nil {
+		log.Error("Fish: Unable to list the node Resources to drain:", err)
+		return
+	}
+	for _, res := range rs {
+		app, err := f.ApplicationGet(res.ApplicationUID)
+		if err != nil {
+			log.Errorf("Fish: Unable to find the Application %s to drain: %v", res.ApplicationUID, err)
+			continue
+		}
+		// The Application could be already deallocating or waiting for approval
+		if state, err := f.ApplicationStateGetByApplication(app.UID); err != nil || state.Status != types.ApplicationStatusALLOCATED {
+			continue
+		}
+		log.Infof("Fish: AUDIT: Deallocating Application %s due to node drain timeout", app.UID)
+		if _, err := f.ApplicationDeallocate(app, DrainRequester); err != nil {
+			log.Errorf("Fish: Unable to deallocate the Application %s on drain: %v", app.UID, err)
+		}
 	}
 }
@@ -0,0 +1,225 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// SubscriptionCreate subscribes the User to the event notifications
+func (f *Fish) SubscriptionCreate(s *types.Subscription) error {
+	if s.UserName == "" {
+		return fmt.Errorf("Fish: UserName can't be empty")
+	}
+	if !slices.Contains(notify.Events, s.Event) {
+		return fmt.Errorf("Fish: Unknown event %q, available: %v", s.Event, notify.Events)
+	}
+	sink, ok := f.notifySinks[s.Sink]
+	if !ok {
+		return fmt.Errorf("Fish: Sink %q is not enabled on the node", s.Sink)
+	}
+	if err := sink.Validate(s.Address); err != nil {
+		return err
+	}
+	// The cluster state is not something the regular users should know about
+	if (s.Event == notify.EventNodeDown || s.Event == notify.EventNodeWarning) && !f.UserHasRole(s.UserName, RoleOperator) {
+		return fmt.Errorf("Fish: Only admin or operator could subscribe to %s", s.Event)
+	}
+
+	s.UID = f.NewUID()
+	if err := f.db.Create(s).Error; err != nil {
+		return err
+	}
+	log.Infof("Fish: User %q subscribed to %s by %s", s.UserName, s.Event, s.Sink)
+	return nil
+}
+
+// SubscriptionGet returns the subscription by UID
+func (f *Fish) SubscriptionGet(uid types.SubscriptionUID) (s *types.Subscription, err error) {
+	s = &types.Subscription{}
+	err = f.db.First(s, uid).Error
+	return s, err
+}
+
+// SubscriptionListUser returns all the subscriptions of the User
+func (f *Fish) SubscriptionListUser(name string) (ss []types.Subscription, err error) {
+	err = f.db.Where("user_name = ?", name).Order("created_at").Find(&ss).Error
+	return ss, err
+}
+
+// SubscriptionDelete removes the subscription
+func (f *Fish) SubscriptionDelete(uid types.SubscriptionUID) error {
+	return f.db.Delete(&types.Subscription{}, uid).Error
+}
+
+// subscriptionDeleteUser removes all the subscriptions of the User
+func (f *Fish) subscriptionDeleteUser(name string) error {
+	return f.db.Where("user_name = ?", name).Delete(&types.Subscription{}).Error
+}
+
+// notify sends the notification to the subscribers of the event in background, empty user sends
+// it to all the event subscribers
+func (f *Fish) notify(user string, n *notify.Notification) {
+	n.Time = time.Now()
+	query := f.db.Where("event = ?", n.Event)
+	if user != "" {
+		query = query.Where("user_name = ?", user)
+	}
+	var subs []types.Subscription
+	if err := query.Find(&subs).Error; err != nil {
+		log.Error("Fish: Unable to find the notification subscriptions:", n.Event, err)
+		return
+	}
+
+	for _, s := range subs {
+		sink, ok := f.notifySinks[s.Sink]
+		if !ok {
+			log.Warnf("Fish: Sink %q of the User %q subscription is not enabled anymore", s.Sink, s.UserName)
+			continue
+		}
+		go func(s types.Subscription) {
+			if err := sink.Send(s.Address, n); err != nil {
+				log.Warnf("Fish: Unable to notify User %q about %s by %s: %v", s.UserName, n.Event, s.Sink, err)
+			}
+		}(s)
+	}
+}
+
+// notifyApplication sends the Application event notification to the owner
+func (f *Fish) notifyApplication(event string, app *types.Application, text string) {
+	f.notify(app.OwnerName, &notify.Notification{
+		Event:   event,
+		Subject: fmt.Sprintf("Aquarium: Application %s %s", app.ShortId, notificationEventName(event)),
+		Text:    text,
+		Data: map[string]any{
+			"application_uid": app.UID,
+			"short_id":        app.ShortId,
+			"label_uid":       app.LabelUID,
+		},
+	})
+}
+
+// notificationEventName returns human readable event name for the notification subject
+func notificationEventName(event string) string {
+	switch event {
+	case notify.EventApplicationAllocated:
+		return "is allocated"
+	case notify.EventApplicationError:
+		return "failed"
+	case notify.EventApplicationDeallocated:
+		return "is deallocated"
+	case notify.EventLifetimeExpiring:
+		return "lifetime is expiring"
+	case notify.EventResourceUnhealthy:
+		return "is unhealthy"
+	}
+	return event
+}
+
+// notificationProcess notifies the Application owners about the state changes
+func (f *Fish) notificationProcess() {
+	sub, cancel := f.ApplicationEventSubscribe()
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for f.running {
+		select {
+		case <-sub.Notify():
+		case <-ticker.C:
+			continue
+		}
+		events, active := sub.Next()
+		for _, event := range events {
+			if event.Annotation != nil || event.Task != nil {
+				// The annotations and tasks are not changing the Application state
+				continue
+			}
+			var name string
+			switch event.State.Status {
+			case types.ApplicationStatusALLOCATED:
+				if strings.HasPrefix(event.State.Description, ApplicationExtendPrefix) {
+					// Lifetime extension is not the new allocation
+					continue
+				}
+				name = notify.EventApplicationAllocated
+			case types.ApplicationStatusERROR:
+				name = notify.EventApplicationError
+			case types.ApplicationStatusDEALLOCATED:
+				name = notify.EventApplicationDeallocated
+			default:
+				continue
+			}
+			// The synced Applications are reported by the node executing them
+			if exists, _ := f.syncExists(&syncForeign{}, "uid = ?", event.State.ApplicationUID.String()); exists {
+				continue
+			}
+			app, err := f.ApplicationGet(event.State.ApplicationUID)
+			if err != nil {
+				log.Warn("Fish: Unable to find the Application to notify about:", event.State.ApplicationUID, err)
+				continue
+			}
+			f.notifyApplication(name, app, event.State.Description)
+		}
+		if !active {
+			log.Warn("Fish: Notifications are behind the Application events, resubscribing")
+			cancel()
+			sub, cancel = f.ApplicationEventSubscribe()
+		}
+	}
+}
+
+// nodeDownProcess notifies the subscribers when the cluster Node stops to ping and is back
+func (f *Fish) nodeDownProcess() {
+	down := map[string]bool{}
+	ticker := time.NewTicker(types.NodePingDelay * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		var nodes []types.Node
+		if err := f.db.Find(&nodes).Error; err != nil {
+			log.Error("Fish: Unable to list the Nodes to check they are alive:", err)
+			continue
+		}
+		for _, node := range nodes {
+			if node.UID == f.node.UID {
+				continue
+			}
+			isDown := time.Since(node.UpdatedAt) > time.Duration(f.cfg.Notifications.NodeDownAge)
+			if isDown == down[node.Name] {
+				continue
+			}
+			down[node.Name] = isDown
+			if !isDown {
+				log.Info("Fish: Node is back online:", node.Name)
+				continue
+			}
+			log.Warn("Fish: Node is down:", node.Name)
+			f.notify("", &notify.Notification{
+				Event:   notify.EventNodeDown,
+				Subject: fmt.Sprintf("Aquarium: Node %s is down", node.Name),
+				Text:    fmt.Sprintf("Node %s in %s did not respond since %s", node.Name, node.LocationName, node.UpdatedAt.Format(time.RFC3339)),
+				Data:    map[string]any{"node_name": node.Name, "node_uid": node.UID},
+			})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ListPageMaxSize limits the amount of the objects returned by one list page
+const ListPageMaxSize = 1000
+
+// ListPage is the window and order of the objects list, the empty page returns all the objects
+type ListPage struct {
+	Size  int    // Amount of the objects on the page
+	Token string // Opaque token of the page returned by the previous page request
+	Sort  string // Comma-separated list of columns, "-" prefix means descending order
+
+	Next string // Token of the next page, empty if it's the last one
+}
+
+// NewListPage creates the page from the optional list API parameters
+func NewListPage(size *int, token, sort *string) *ListPage {
+	p := &ListPage{}
+	if size != nil {
+		p.Size = *size
+	}
+	if token != nil {
+		p.Token = *token
+	}
+	if sort != nil {
+		p.Sort = *sort
+	}
+	return p
+}
+
+// query applies the order and window of the page to the list query. Only the listed columns could
+// be used to sort to keep the query on the indexes, the unique key is always added to the end of
+// the order to make the pages stable.
+func (p *ListPage) query(db *gorm.DB, sortable []string, key string) (*gorm.DB, error) {
+	if p == nil {
+		return db, nil
+	}
+	if p.Sort != "" {
+		for _, field := range strings.Split(p.Sort, ",") {
+			field = strings.TrimSpace(field)
+			column, desc := strings.CutPrefix(field, "-")
+			if !slices.Contains(sortable, column) {
+				return db, fmt.Errorf("Fish: Unable to sort by %q, available: %v", column, sortable)
+			}
+			if desc {
+				column += " desc"
+			}
+			db = db.Order(column)
+		}
+	}
+	db = db.Order(key)
+
+	if p.Size == 0 && p.Token == "" {
+		return db, nil
+	}
+	if p.Size < 1 || p.Size > ListPageMaxSize {
+		p.Size = ListPageMaxSize
+	}
+	offset, err := p.offset()
+	if err != nil {
+		return db, err
+	}
+	// Requesting one more object to know if there is the next page
+	return db.Offset(offset).Limit(p.Size + 1), nil
+}
+
+// cutPage removes the extra object from the page and fills the next page token
+func cutPage[T any](p *ListPage, items []T) ([]T, error) {
+	if p == nil || p.Size == 0 || len(items) <= p.Size {
+		return items, nil
+	}
+	offset, err := p.offset()
+	if err != nil {
+		return items, err
+	}
+	p.Next = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset + p.Size)))
+	return items[:p.Size], nil
+}
+
+// offset decodes the position of the page from the token
+func (p *ListPage) offset() (int, error) {
+	if p.Token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(p.Token)
+	if err != nil {
+		return 0, fmt.Errorf("Fish: Wrong page token")
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("Fish: Wrong page token")
+	}
+	return offset, nil
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// preferenceKeyRegex limits the preference keys to the simple path-like names
+var preferenceKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9._/-]{1,128}$`)
+
+// PreferenceList returns all the preferences of the User
+func (f *Fish) PreferenceList(userName string) (ps []types.Preference, err error) {
+	err = f.db.Where("user_name = ?", userName).Order("key").Find(&ps).Error
+	return ps, err
+}
+
+// PreferenceGet returns the preference of the User by key
+func (f *Fish) PreferenceGet(userName, key string) (p *types.Preference, err error) {
+	p = &types.Preference{}
+	err = f.db.Where("user_name = ? AND key = ?", userName, key).First(p).Error
+	return p, err
+}
+
+// PreferenceSet creates or updates the preference of the User
+func (f *Fish) PreferenceSet(p *types.Preference) error {
+	if p.UserName == "" {
+		return fmt.Errorf("Fish: UserName can't be empty")
+	}
+	if !preferenceKeyRegex.MatchString(p.Key) {
+		return &ValidationError{Field: "key", Message: fmt.Sprintf("Wrong preference key %q", p.Key)}
+	}
+	if !json.Valid([]byte(p.Value)) {
+		return &ValidationError{Field: "value", Message: "Preference value should be a valid JSON"}
+	}
+	if err := f.limitPreference(p.Value); err != nil {
+		return err
+	}
+
+	// The number of preferences is checked only when the new one is added
+	var count int64
+	if err := f.db.Model(&types.Preference{}).Where("user_name = ? AND key != ?", p.UserName, p.Key).Count(&count).Error; err != nil {
+		return err
+	}
+	if err := f.limitPreferences(int(count) + 1); err != nil {
+		return err
+	}
+	return f.db.Save(p).Error
+}
+
+// PreferenceDelete removes the preference of the User by key
+func (f *Fish) PreferenceDelete(userName, key string) error {
+	return f.db.Where("user_name = ? AND key = ?", userName, key).Delete(&types.Preference{}).Error
+}
+
+// preferenceDeleteUser removes all the preferences of the User
+func (f *Fish) preferenceDeleteUser(userName string) error {
+	return f.db.Where("user_name = ?", userName).Delete(&types.Preference{}).Error
+}
@@ -0,0 +1,47 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// definitionHourlyCost estimates the hourly cost of the definition Resource by the driver pricing
+// or the static price table of the driver config, 0 if the price is unknown
+func (f *Fish) definitionHourlyCost(def types.LabelDefinition) float64 {
+	if p, ok := f.driverGet(def.Driver).(drivers.ResourceDriverPricing); ok {
+		cost, err := p.HourlyCost(def)
+		if err == nil {
+			return cost
+		}
+		log.Debug("Fish: Driver is not able to price the definition, using the static pricing:", def.Driver, err)
+	}
+	for _, cfg := range f.cfg.Drivers {
+		if cfg.Name == def.Driver && cfg.Pricing != nil {
+			return cfg.Pricing.HourlyCost(def)
+		}
+	}
+	return 0
+}
+
+// ownerHourlyCost returns the hourly cost of the User active Resources
+func (f *Fish) ownerHourlyCost(name string) (total float64, err error) {
+	err = f.db.Table("resources r").
+		Select("COALESCE(SUM(r.hourly_cost), 0)").
+		Joins("JOIN applications a ON a.uid = r.application_uid").
+		Where("a.owner_name = ?", name).
+		Scan(&total).Error
+	return total, err
+}
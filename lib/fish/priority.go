@@ -0,0 +1,243 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// PreemptRequester is used as requester name when the idle Resource is preempted
+const PreemptRequester = "fish-preempt"
+
+// wonVote stores the won Vote with the Application ordering info
+type wonVote struct {
+	Vote      types.Vote
+	Priority  int
+	Owner     string
+	CreatedAt time.Time
+}
+
+// wonVotesOrdered returns won votes in order of execution - the higher priority goes first, then
+// the owner with less usage in fair share mode (usage is nil otherwise) and the Application
+// requested first should be processed first
+func wonVotesOrdered(votes map[types.ApplicationUID]wonVote, usage map[string]int) []wonVote {
+	out := make([]wonVote, 0, len(votes))
+	for _, v := range votes {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Priority != out[j].Priority {
+			return out[i].Priority > out[j].Priority
+		}
+		if usage[out[i].Owner] != usage[out[j].Owner] {
+			return usage[out[i].Owner] < usage[out[j].Owner]
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+// applicationPrecedes returns true if Application a should be served before b, the same priority
+// and share Applications are served in the order of election
+func applicationPrecedes(a, b *types.Application, usage map[string]int) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return usage[a.OwnerName] < usage[b.OwnerName]
+}
+
+// applicationPriorityResolve sets the default priority of the Application and checks the owner
+// is allowed to request it
+func (f *Fish) applicationPriorityResolve(a *types.Application) error {
+	if a.Priority < 0 {
+		return fmt.Errorf("Fish: Priority can't be negative")
+	}
+	privileged := f.UserHasRole(a.OwnerName, RoleOperator)
+	if a.Priority > f.cfg.Priority.UserMax && !privileged {
+		return fmt.Errorf("Fish: Priority %d is higher than allowed for the User: %d", a.Priority, f.cfg.Priority.UserMax)
+	}
+	if a.Priority > 0 {
+		return nil
+	}
+
+	// The Label could be not found here, it will be checked later during the election
+	if label, err := f.LabelGet(a.LabelUID); err == nil {
+		a.Priority = label.Priority
+	}
+	for role, priority := range f.cfg.Priority.Roles {
+		if priority > a.Priority && f.UserHasRole(a.OwnerName, role) {
+			a.Priority = priority
+		}
+	}
+	return nil
+}
+
+// isPrecedingWaiting checks if there is NEW Application with higher priority or owner share this
+// node can serve, so the node should not vote for the current one to leave the capacity for it
+// Should be called under nodeUsageMutex
+func (f *Fish) isPrecedingWaiting(app *types.Application) bool {
+	newApps, err := f.ApplicationListGetStatusNew()
+	if err != nil {
+		log.Error("Fish: Unable to get NEW Applications to check priority:", err)
+		return false
+	}
+	usage := f.schedulerUsage()
+	for i := range newApps {
+		other := &newApps[i]
+		if !applicationPrecedes(other, app, usage) {
+			continue
+		}
+		label, err := f.LabelGet(other.LabelUID)
+		if err != nil {
+			continue
+		}
+		for i := range label.Definitions {
+			if !f.maintenance && f.recyclePoolHas(label.UID, i) || f.isNodeAvailableForDefinition(label, i) {
+				log.Debugf("Fish: Application %s yields to preceding Application %s", app.UID, other.UID)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResourceActivityTouch marks the Resource as used right now to not preempt it as idle
+func (f *Fish) ResourceActivityTouch(uid types.ResourceUID) {
+	f.resourceActivityMutex.Lock()
+	defer f.resourceActivityMutex.Unlock()
+	f.resourceActivity[uid] = time.Now()
+}
+
+// ResourceSessionBegin marks the Resource as used by the user session till it ends, the session
+// connection will be closed when the Resource is deallocated
+func (f *Fish) ResourceSessionBegin(uid types.ResourceUID, conn io.Closer) {
+	f.resourceActivityMutex.Lock()
+	defer f.resourceActivityMutex.Unlock()
+	f.resourceActivity[uid] = time.Now()
+	f.resourceUsage[uid] = time.Now()
+	if f.resourceSessions[uid] == nil {
+		f.resourceSessions[uid] = make(map[io.Closer]struct{})
+	}
+	f.resourceSessions[uid][conn] = struct{}{}
+}
+
+// ResourceSessionEnd marks the user session of the Resource as ended
+func (f *Fish) ResourceSessionEnd(uid types.ResourceUID, conn io.Closer) {
+	f.resourceActivityMutex.Lock()
+	defer f.resourceActivityMutex.Unlock()
+	f.resourceActivity[uid] = time.Now()
+	f.resourceUsage[uid] = time.Now()
+	delete(f.resourceSessions[uid], conn)
+	if len(f.resourceSessions[uid]) == 0 {
+		delete(f.resourceSessions, uid)
+	}
+}
+
+// resourceSessionsRevoke closes all the active user sessions of the Resource
+func (f *Fish) resourceSessionsRevoke(uid types.ResourceUID) {
+	f.resourceActivityMutex.Lock()
+	sessions := f.resourceSessions[uid]
+	delete(f.resourceSessions, uid)
+	f.resourceActivityMutex.Unlock()
+	for conn := range sessions {
+		if err := conn.Close(); err != nil {
+			log.Debugf("Fish: Unable to close the session of Resource %s: %v", uid, err)
+		}
+	}
+}
+
+// resourceLastActivity returns the last time the Resource was used, now if it has active sessions
+func (f *Fish) resourceLastActivity(uid types.ResourceUID) (time.Time, bool) {
+	f.resourceActivityMutex.Lock()
+	defer f.resourceActivityMutex.Unlock()
+	if len(f.resourceSessions[uid]) > 0 {
+		return time.Now(), true
+	}
+	last, ok := f.resourceActivity[uid]
+	return last, ok
+}
+
+// resourceIdleSince returns the last time Resource was used by the meta API or proxies, the node
+// does not know about the activity before it was started so it's the earliest possible value
+func (f *Fish) resourceIdleSince(res *types.Resource) time.Time {
+	since := f.startedAt
+	if res.CreatedAt.After(since) {
+		since = res.CreatedAt
+	}
+	if last, ok := f.resourceLastActivity(res.UID); ok && last.After(since) {
+		since = last
+	}
+	return since
+}
+
+// preemptIdleResource deallocates one idle Resource of lower priority Application to free the
+// capacity for the Application no one node can serve. To not preempt multiple Resources for the
+// same Application only the node with the lowest vote Rand in the round is doing that.
+func (f *Fish) preemptIdleResource(app *types.Application, label *types.Label, votes []types.Vote) {
+	if f.cfg.Priority.PreemptIdle <= 0 || f.maintenance || len(votes) == 0 {
+		return
+	}
+	preemptor := votes[0]
+	for _, v := range votes[1:] {
+		if v.Rand < preemptor.Rand {
+			preemptor = v
+		}
+	}
+	if preemptor.NodeUID != f.node.UID {
+		return
+	}
+
+	drivers := make(map[string]bool, len(label.Definitions))
+	for _, def := range label.Definitions {
+		drivers[def.Driver] = true
+	}
+
+	resources, err := f.ResourceListNode(f.node.UID)
+	if err != nil {
+		log.Error("Fish: Unable to list the node Resources to preempt:", err)
+		return
+	}
+	idleAfter := time.Duration(f.cfg.Priority.PreemptIdle)
+	for i := range resources {
+		res := &resources[i]
+		if time.Since(f.resourceIdleSince(res)) < idleAfter {
+			continue
+		}
+		victim, err := f.ApplicationGet(res.ApplicationUID)
+		if err != nil || victim.Priority >= app.Priority {
+			continue
+		}
+		state, err := f.ApplicationStateGetByApplication(victim.UID)
+		if err != nil || state.Status != types.ApplicationStatusALLOCATED {
+			continue
+		}
+		resLabel, err := f.LabelGet(res.LabelUID)
+		if err != nil || res.DefinitionIndex >= len(resLabel.Definitions) || !drivers[resLabel.Definitions[res.DefinitionIndex].Driver] {
+			continue
+		}
+
+		log.Infof("Fish: AUDIT: Preempting idle Resource of Application %s (priority %d) for Application %s (priority %d)",
+			victim.UID, victim.Priority, app.UID, app.Priority)
+		if _, err := f.ApplicationDeallocate(victim, PreemptRequester); err != nil {
+			log.Errorf("Fish: Unable to preempt Application %s: %v", victim.UID, err)
+			continue
+		}
+		return
+	}
+}
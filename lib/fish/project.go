@@ -0,0 +1,106 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// ProjectFind returns all the Projects
+func (f *Fish) ProjectFind() (ps []types.Project, err error) {
+	err = f.db.Order("name").Find(&ps).Error
+	return ps, err
+}
+
+// ProjectSave creates or updates the Project
+func (f *Fish) ProjectSave(p *types.Project) error {
+	if p.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if p.MaxApplications < 0 || p.MaxCpu < 0 || p.MaxRam < 0 {
+		return fmt.Errorf("Fish: Project limits can't be negative")
+	}
+	seen := make(map[string]bool, len(p.Members))
+	for _, m := range p.Members {
+		if seen[m.UserName] {
+			return fmt.Errorf("Fish: User %q is listed twice in the Project members", m.UserName)
+		}
+		seen[m.UserName] = true
+		if m.Role != types.ProjectMemberRoleMember && m.Role != types.ProjectMemberRoleMaintainer {
+			return fmt.Errorf("Fish: Wrong Project member %q role: %q", m.UserName, m.Role)
+		}
+		if _, err := f.UserGet(m.UserName); err != nil {
+			return fmt.Errorf("Fish: Unable to find User %q: %v", m.UserName, err)
+		}
+	}
+	if p.Members == nil {
+		p.Members = []types.ProjectMember{}
+	}
+	return f.db.Save(p).Error
+}
+
+// ProjectGet returns Project by it's unique name
+func (f *Fish) ProjectGet(name string) (p *types.Project, err error) {
+	p = &types.Project{}
+	err = f.db.First(p, "name = ?", name).Error
+	return p, err
+}
+
+// ProjectDelete removes the Project which has no Labels
+func (f *Fish) ProjectDelete(name string) error {
+	var count int64
+	if err := f.db.Model(&types.Label{}).Where("project = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("Fish: Project %q still has %d Labels", name, count)
+	}
+	return f.db.Delete(&types.Project{}, "name = ?", name).Error
+}
+
+// ProjectRole returns the role of the User in the Project, empty if the User is not a member
+func (f *Fish) ProjectRole(project, userName string) types.ProjectMemberRole {
+	if project == "" {
+		return ""
+	}
+	p, err := f.ProjectGet(project)
+	if err != nil {
+		return ""
+	}
+	for _, m := range p.Members {
+		if m.UserName == userName {
+			return m.Role
+		}
+	}
+	return ""
+}
+
+// UserProjects returns the names of the Projects the User is member of
+func (f *Fish) UserProjects(userName string) ([]string, error) {
+	ps, err := f.ProjectFind()
+	if err != nil {
+		return nil, err
+	}
+	out := []string{}
+	for _, p := range ps {
+		for _, m := range p.Members {
+			if m.UserName == userName {
+				out = append(out, p.Name)
+				break
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,380 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// QuotaGet returns the quota of the User, empty quota (unlimited) if it's not set
+func (f *Fish) QuotaGet(name string) (q *types.Quota, err error) {
+	q = &types.Quota{}
+	err = f.db.Where("user_name = ?", name).Limit(1).Find(q).Error
+	q.UserName = name
+	return q, err
+}
+
+// QuotaSet creates or updates the quota of the User
+func (f *Fish) QuotaSet(q *types.Quota) error {
+	if q.UserName == "" {
+		return fmt.Errorf("Fish: UserName can't be empty")
+	}
+	if q.MaxApplications < 0 || q.MaxCpu < 0 || q.MaxRam < 0 || q.MaxHours < 0 || q.MaxHourlyCost < 0 {
+		return fmt.Errorf("Fish: Quota limits can't be negative")
+	}
+	if _, err := f.UserGet(q.UserName); err != nil {
+		return fmt.Errorf("Fish: Unable to find User %q: %v", q.UserName, err)
+	}
+	return f.db.Save(q).Error
+}
+
+// QuotaDelete removes the quota of the User
+func (f *Fish) QuotaDelete(name string) error {
+	return f.db.Where("user_name = ?", name).Delete(&types.Quota{}).Error
+}
+
+// quotaWindow is the period of the allocation hours limit
+const quotaWindow = 24 * time.Hour
+
+// quotaStatusInactive are the Application statuses not counted in the quota usage, the same as
+// ApplicationStateIsActive is using
+var quotaStatusInactive = []types.ApplicationStatus{
+	types.ApplicationStatusERROR,
+	types.ApplicationStatusDEALLOCATE,
+	types.ApplicationStatusRECALLED,
+	types.ApplicationStatusDEALLOCATED,
+}
+
+// quotaLimits are the limits of the User, Project or Role group, where is the condition on the
+// `a` Applications table to select the Applications counted in the usage
+type quotaLimits struct {
+	name  string // Quota name for the error message
+	where string
+	args  []any
+
+	apps  int
+	cpu   int
+	ram   int
+	hours int
+}
+
+// quotaUsage is the usage of the quota limits, hours are counted only if limited
+type quotaUsage struct {
+	Applications int
+	Cpu          int
+	Ram          int
+	Hours        float32
+}
+
+// empty returns true if nothing is limited
+func (l *quotaLimits) empty() bool {
+	return l.apps == 0 && l.cpu == 0 && l.ram == 0 && l.hours == 0
+}
+
+// exceeded returns the error message if the usage with the requested Applications is over the
+// limits, empty if it fits
+func (l *quotaLimits) exceeded(u *quotaUsage, count, cpu, ram int) string {
+	if l.apps > 0 && u.Applications+count > l.apps {
+		return fmt.Sprintf("Fish: %s exceeded: max %d active Applications, used %d", l.name, l.apps, u.Applications)
+	}
+	if l.hours > 0 && u.Hours >= float32(l.hours) {
+		return fmt.Sprintf("Fish: %s exceeded: max %d allocation hours per day, used %.1f", l.name, l.hours, u.Hours)
+	}
+	if l.cpu > 0 && u.Cpu+cpu > l.cpu {
+		return fmt.Sprintf("Fish: %s exceeded: max %d CPU, used %d, requested %d", l.name, l.cpu, u.Cpu, cpu)
+	}
+	if l.ram > 0 && u.Ram+ram > l.ram {
+		return fmt.Sprintf("Fish: %s exceeded: max %d GB RAM, used %d, requested %d", l.name, l.ram, u.Ram, ram)
+	}
+	return ""
+}
+
+// quotaUserLimits returns the limits of the User quota
+func quotaUserLimits(q *types.Quota) quotaLimits {
+	return quotaLimits{
+		name: "Quota", where: "a.owner_name = ?", args: []any{q.UserName},
+		apps: q.MaxApplications, cpu: q.MaxCpu, ram: q.MaxRam, hours: q.MaxHours,
+	}
+}
+
+// quotaRoleLimits returns the limits of the Role group quota, the group is the Users with active
+// grant of the role
+func (f *Fish) quotaRoleLimits(r *types.Role) quotaLimits {
+	members := f.db.Model(&types.RoleGrant{}).Select("user_name").
+		Where("role = ? AND revoked_at IS NULL AND expires_at > ?", r.Name, time.Now())
+	return quotaLimits{
+		name: fmt.Sprintf("Role %q quota", r.Name), where: "a.owner_name IN (?)", args: []any{members},
+		apps: r.MaxApplications, cpu: r.MaxCpu, ram: r.MaxRam, hours: r.MaxHours,
+	}
+}
+
+// quotaLimitsGet returns the not empty limits applied to the Application: the Project, owner and
+// the owner Role groups quotas
+func (f *Fish) quotaLimitsGet(a *types.Application) (out []quotaLimits, err error) {
+	if a.Project != "" {
+		p, err := f.ProjectGet(a.Project)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to find Project %q: %v", a.Project, err)
+		}
+		l := quotaLimits{
+			name: "Project quota", where: "a.project = ?", args: []any{p.Name},
+			apps: p.MaxApplications, cpu: p.MaxCpu, ram: p.MaxRam,
+		}
+		if !l.empty() {
+			out = append(out, l)
+		}
+	}
+
+	q, err := f.QuotaGet(a.OwnerName)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to get the User quota: %v", err)
+	}
+	if l := quotaUserLimits(q); !l.empty() {
+		out = append(out, l)
+	}
+
+	roles, err := f.userRoles(a.OwnerName)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to get the User roles: %v", err)
+	}
+	for i := range roles {
+		if l := f.quotaRoleLimits(&roles[i]); !l.empty() {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// quotaUsageGet returns the usage of the limits, the allocated flag counts only the Applications
+// with Resources, withHours forces to count the hours even if they are not limited
+func (f *Fish) quotaUsageGet(l *quotaLimits, allocated, withHours bool) (u quotaUsage, err error) {
+	// The Resources amount is taken from the Label definitions JSON in the same query to not load
+	// every Label and Resource of the active Applications one by one
+	def := "COALESCE(SUM(json_extract(CAST(l.definitions AS TEXT), '$[' || COALESCE(r.definition_index, 0) || '].resources.%s')), 0)"
+	q := f.db.Table("applications a").
+		Select("COUNT(*) AS applications, "+fmt.Sprintf(def, "cpu")+" AS cpu, "+fmt.Sprintf(def, "ram")+" AS ram").
+		Joins("JOIN application_states s ON s.application_uid = a.uid AND s.created_at = "+
+			"(SELECT max(ls.created_at) FROM application_states ls WHERE ls.application_uid = a.uid)").
+		Joins("LEFT JOIN labels l ON l.uid = a.label_uid").
+		Joins("LEFT JOIN resources r ON r.application_uid = a.uid").
+		Where("s.status NOT IN ?", quotaStatusInactive).
+		Where(l.where, l.args...)
+	if allocated {
+		q = q.Where("r.uid IS NOT NULL")
+	}
+	if err = q.Scan(&u).Error; err != nil {
+		return u, err
+	}
+	if l.hours > 0 || withHours {
+		u.Hours, err = f.quotaAllocationHours(l, time.Now().Add(-quotaWindow))
+	}
+	return u, err
+}
+
+// quotaAllocationHours returns how many hours the Resources of the limits Applications were
+// allocated since the time till now, only the allocations ended in the period and the current
+// ones are read to not walk through the whole history
+func (f *Fish) quotaAllocationHours(l *quotaLimits, from time.Time) (float32, error) {
+	now := time.Now()
+	var total time.Duration
+	overlap := func(start, end time.Time) {
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+
+	// The Resources are removed on deallocation, so the existing ones are still allocated
+	var current []types.Resource
+	err := f.db.Table("resources r").Select("r.created_at").
+		Joins("JOIN applications a ON a.uid = r.application_uid").
+		Where(l.where, l.args...).Find(&current).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range current {
+		overlap(r.CreatedAt, now)
+	}
+
+	var ended []types.ApplicationState
+	err = f.db.Table("application_states s").Select("s.application_uid, s.created_at").
+		Joins("JOIN applications a ON a.uid = s.application_uid").
+		Where("s.status = ? AND s.created_at >= ?", types.ApplicationStatusDEALLOCATED, from).
+		Where(l.where, l.args...).Find(&ended).Error
+	if err != nil || len(ended) == 0 {
+		return float32(total.Hours()), err
+	}
+	uids := make([]types.ApplicationUID, 0, len(ended))
+	for _, s := range ended {
+		uids = append(uids, s.ApplicationUID)
+	}
+	var allocated []types.ApplicationState
+	err = f.db.Where("application_uid IN ? AND status = ?", uids, types.ApplicationStatusALLOCATED).
+		Order("created_at").Find(&allocated).Error
+	if err != nil {
+		return 0, err
+	}
+	started := make(map[types.ApplicationUID]time.Time, len(allocated))
+	for _, s := range allocated {
+		if _, ok := started[s.ApplicationUID]; !ok {
+			started[s.ApplicationUID] = s.CreatedAt
+		}
+	}
+	for _, s := range ended {
+		// Not allocated Applications could be deallocated too
+		if start, ok := started[s.ApplicationUID]; ok {
+			overlap(start, s.CreatedAt)
+		}
+	}
+	return float32(total.Hours()), nil
+}
+
+// QuotaUsageGet returns the quota of the User with the current usage of it and the User roles
+// group quotas
+func (f *Fish) QuotaUsageGet(name string) (*types.QuotaUsage, error) {
+	q, err := f.QuotaGet(name)
+	if err != nil {
+		return nil, err
+	}
+	usage := &types.QuotaUsage{Quota: *q, Roles: []types.RoleQuotaUsage{}}
+
+	l := quotaUserLimits(q)
+	u, err := f.quotaUsageGet(&l, false, true)
+	if err != nil {
+		return nil, err
+	}
+	usage.Applications, usage.Cpu, usage.Ram, usage.Hours = u.Applications, u.Cpu, u.Ram, u.Hours
+
+	if usage.HourlyCost, err = f.ownerHourlyCost(name); err != nil {
+		return nil, err
+	}
+
+	roles, err := f.userRoles(name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		l := f.quotaRoleLimits(&roles[i])
+		u, err := f.quotaUsageGet(&l, false, true)
+		if err != nil {
+			return nil, err
+		}
+		usage.Roles = append(usage.Roles, types.RoleQuotaUsage{
+			Role: roles[i], Applications: u.Applications, Cpu: u.Cpu, Ram: u.Ram, Hours: u.Hours,
+		})
+	}
+	return usage, nil
+}
+
+// quotaRequested returns the Resources amount of the new Applications by the first definition
+// of the Label, zero if the Label is wrong because it will fail the Application anyway
+func quotaRequested(label *types.Label, count int) (cpu, ram int) {
+	if label == nil || len(label.Definitions) == 0 {
+		return 0, 0
+	}
+	def := label.Definitions[0]
+	return int(def.Resources.Cpu) * count, int(def.Resources.Ram) * count
+}
+
+// quotaCheck returns error if the count of new Applications exceeds the Project, owner or owner
+// Role groups quota
+func (f *Fish) quotaCheck(a *types.Application, count int) error {
+	limits, err := f.quotaLimitsGet(a)
+	if err != nil || len(limits) == 0 {
+		return err
+	}
+
+	var label *types.Label
+	if label, err = f.LabelGet(a.LabelUID); err != nil {
+		log.Warn("Fish: Unable to find Label to check the quota:", a.LabelUID, err)
+		label = nil
+	}
+	cpu, ram := quotaRequested(label, count)
+	for i := range limits {
+		usage, err := f.quotaUsageGet(&limits[i], false, false)
+		if err != nil {
+			return fmt.Errorf("Fish: Unable to get the %s usage: %v", limits[i].name, err)
+		}
+		if msg := limits[i].exceeded(&usage, count, cpu, ram); msg != "" {
+			return f.quotaExceeded(a, "%s", msg)
+		}
+	}
+	return nil
+}
+
+// quotaElectionAllowed checks the quotas allow to allocate the Application right now, only the
+// allocated Resources are counted, so the Application waits for the other ones to be released.
+// The error is not restricting, so the node will not stop voting because of the transient DB
+// failure - the quota was already checked on create.
+func (f *Fish) quotaElectionAllowed(app *types.Application, label *types.Label) bool {
+	limits, err := f.quotaLimitsGet(app)
+	if err != nil {
+		log.Warn("Fish: Unable to get the quota to check on election:", app.UID, err)
+		return true
+	}
+	cpu, ram := quotaRequested(label, 1)
+	for i := range limits {
+		usage, err := f.quotaUsageGet(&limits[i], true, false)
+		if err != nil {
+			log.Warnf("Fish: Unable to get the %s usage to check on election: %s: %v", limits[i].name, app.UID, err)
+			continue
+		}
+		if msg := limits[i].exceeded(&usage, 1, cpu, ram); msg != "" {
+			log.Infof("Fish: Application %s waits for the quota: %s", app.UID, msg)
+			return false
+		}
+	}
+	return true
+}
+
+// quotaBudgetCheck returns error if the Resource cost exceeds the owner budget cap, it's checked
+// by the node before allocation because the cost depends on the driver and definition
+func (f *Fish) quotaBudgetCheck(a *types.Application, cost float64) error {
+	if cost <= 0 {
+		return nil
+	}
+	q, err := f.QuotaGet(a.OwnerName)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to get the User quota: %v", err)
+	}
+	if q.MaxHourlyCost <= 0 {
+		return nil
+	}
+	used, err := f.ownerHourlyCost(a.OwnerName)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to get the User Resources cost: %v", err)
+	}
+	if used+cost > q.MaxHourlyCost {
+		return f.quotaExceeded(a, "Fish: Budget exceeded: max %.2f per hour, used %.2f, requested %.2f", q.MaxHourlyCost, used, cost)
+	}
+	return nil
+}
+
+// quotaExceeded notifies the owner about the rejected Application and returns the error
+func (f *Fish) quotaExceeded(a *types.Application, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	f.notify(a.OwnerName, &notify.Notification{
+		Event:   notify.EventQuotaExceeded,
+		Subject: "Aquarium: Quota exceeded",
+		Text:    err.Error(),
+		Data:    map[string]any{"label_uid": a.LabelUID},
+	})
+	return err
+}
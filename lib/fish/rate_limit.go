@@ -0,0 +1,128 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// RateLimitDefault is the rule name of the operations without the own rule
+const RateLimitDefault = "default"
+
+// rateLimitRule finds the rule of the API operation, the operation rule has priority over the
+// service one
+func (f *Fish) rateLimitRule(operation string, services []string) (string, ConfigRate) {
+	if r, ok := f.cfg.RateLimit.Rules[operation]; ok {
+		return operation, r
+	}
+	for _, service := range services {
+		if r, ok := f.cfg.RateLimit.Rules[service]; ok {
+			return service, r
+		}
+	}
+	return RateLimitDefault, f.cfg.RateLimit.Default
+}
+
+// rateLimitExempt checks if the User is not limited by the rate
+func (f *Fish) rateLimitExempt(user string) bool {
+	if user == "admin" || slices.Contains(f.cfg.RateLimit.Exempt, user) {
+		return true
+	}
+	for _, role := range f.cfg.RateLimit.Exempt {
+		if slices.Contains(Roles, role) && f.UserHasRole(user, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitBucket returns the bucket of the User rule, creates it if needed
+func (f *Fish) rateLimitBucket(user, rule string, r ConfigRate) *util.RateBucket {
+	key := user + "/" + rule
+	b, ok := f.rateLimits[key]
+	if !ok || b.Rate != r.Rate {
+		b = util.NewRateBucket(r.Rate, r.Burst)
+		f.rateLimits[key] = b
+	}
+	return b
+}
+
+// RateLimitTake consumes the User request to the API operation, returns the state of the limit or
+// nil if the request is not limited and how long to wait if it's rejected
+func (f *Fish) RateLimitTake(user, operation string, services []string) (*types.RateLimit, time.Duration) {
+	rule, r := f.rateLimitRule(operation, services)
+	if r.Rate <= 0 || f.rateLimitExempt(user) {
+		return nil, 0
+	}
+
+	now := time.Now()
+	f.rateLimitsMutex.Lock()
+	defer f.rateLimitsMutex.Unlock()
+	b := f.rateLimitBucket(user, rule, r)
+	_, wait := b.Take(now)
+	return &types.RateLimit{Rule: rule, Rate: r.Rate, Burst: b.Burst, Remaining: b.Remaining(now)}, wait
+}
+
+// RateLimitListUser returns the rate limits applied to the User
+func (f *Fish) RateLimitListUser(user string) []types.RateLimit {
+	out := []types.RateLimit{}
+	if f.rateLimitExempt(user) {
+		return out
+	}
+	rules := map[string]ConfigRate{}
+	for rule, r := range f.cfg.RateLimit.Rules {
+		rules[rule] = r
+	}
+	rules[RateLimitDefault] = f.cfg.RateLimit.Default
+
+	var names []string
+	for rule, r := range rules {
+		if r.Rate > 0 {
+			names = append(names, rule)
+		}
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	f.rateLimitsMutex.Lock()
+	defer f.rateLimitsMutex.Unlock()
+	for _, rule := range names {
+		b := f.rateLimitBucket(user, rule, rules[rule])
+		out = append(out, types.RateLimit{Rule: rule, Rate: b.Rate, Burst: b.Burst, Remaining: b.Remaining(now)})
+	}
+	return out
+}
+
+// rateLimitProcess forgets the refilled buckets to not keep the inactive Users in memory
+func (f *Fish) rateLimitProcess() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		now := time.Now()
+		f.rateLimitsMutex.Lock()
+		for key, b := range f.rateLimits {
+			if b.Full(now) {
+				delete(f.rateLimits, key)
+			}
+		}
+		f.rateLimitsMutex.Unlock()
+	}
+}
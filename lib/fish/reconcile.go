@@ -0,0 +1,227 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// reconcileDrivers compares the resources existing in the drivers with the database on startup, so
+// the state left by the node crash is cleaned up. The Resources of this node not existing in the
+// driver anymore are marked ERROR. The driver resources unknown to the database and tagged by this
+// node are adopted by their ELECTED Application or reported and deallocated if `reconcile_terminate`
+// is enabled, the resources of the other nodes are only reported.
+func (f *Fish) reconcileDrivers() error {
+	// All the known identifiers, the remote drivers could share the account with the other nodes
+	known := map[string]bool{}
+	var ids []string
+	if err := f.db.Model(&types.Resource{}).Pluck("identifier", &ids).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to list the Resources identifiers: %v", err)
+	}
+	for _, id := range ids {
+		known[id] = true
+	}
+	ids = nil
+	if err := f.db.Model(&recycledResource{}).Pluck("identifier", &ids).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to list the recycle pool identifiers: %v", err)
+	}
+	for _, id := range ids {
+		known[id] = true
+	}
+
+	rs, err := f.ResourceListNode(f.node.UID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to get the node resources: %v", err)
+	}
+
+	var names []string
+	for name := range driversInstances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		drv := driversInstances[name]
+		if s, ok := drv.(*supervisedDriver); ok {
+			if _, ok := s.unwrap().(drivers.ResourceDriverList); !ok {
+				continue
+			}
+		}
+		l, ok := drv.(drivers.ResourceDriverList)
+		if !ok {
+			continue
+		}
+		existing, err := l.List()
+		if err != nil {
+			// Not able to say what is missing, so leaving everything as is
+			log.Errorf("Fish: Reconcile: Unable to list the resources of driver %s: %v", name, err)
+			continue
+		}
+		exists := map[string]bool{}
+		for _, lr := range existing {
+			exists[lr.Identifier] = true
+		}
+
+		ghosts := 0
+		for _, res := range rs {
+			if exists[res.Identifier] || f.resourceDriverName(&res) != name {
+				continue
+			}
+			log.Warnf("Fish: Reconcile: Resource %s of Application %s is not found by driver %s", res.Identifier, res.ApplicationUID, name)
+			if err := f.ResourceDelete(res.UID); err != nil {
+				log.Error("Fish: Reconcile: Unable to delete Resource of Application:", res.ApplicationUID, err)
+			}
+			f.ApplicationStateCreate(&types.ApplicationState{ApplicationUID: res.ApplicationUID, Status: types.ApplicationStatusERROR,
+				Description: fmt.Sprintf("Resource %s is not found by driver on node startup", res.Identifier),
+			})
+			ghosts++
+		}
+
+		untracked, adopted, terminated := 0, 0, 0
+		for _, lr := range existing {
+			if known[lr.Identifier] {
+				continue
+			}
+			untracked++
+			// The other nodes could use the same driver account, so touching only the own resources
+			if lr.NodeUID != f.node.UID {
+				log.Warnf("Fish: Reconcile: Resource %s of driver %s is not known and not owned by this node, keeping it", lr.Identifier, name)
+				continue
+			}
+			if lr.ApplicationUID != uuid.Nil {
+				err := f.reconcileAdopt(name, &lr)
+				if err == nil {
+					log.Infof("Fish: Reconcile: Resource %s of driver %s is adopted by Application %s", lr.Identifier, name, lr.ApplicationUID)
+					adopted++
+					continue
+				}
+				log.Warnf("Fish: Reconcile: Resource %s of driver %s can't be adopted: %v", lr.Identifier, name, err)
+			}
+			if !f.cfg.ReconcileTerminate {
+				log.Warnf("Fish: Reconcile: Resource %s of driver %s is not known, keeping it", lr.Identifier, name)
+				continue
+			}
+			// The database could be changed since the snapshot was taken, so checking it again
+			if ok, err := f.reconcileKnown(lr.Identifier); err != nil || ok {
+				log.Warnf("Fish: Reconcile: Resource %s of driver %s is known now or unable to check (%v), keeping it", lr.Identifier, name, err)
+				continue
+			}
+			log.Warnf("Fish: Reconcile: Resource %s of driver %s is not known, deallocating it", lr.Identifier, name)
+			if err := drv.Deallocate(&types.Resource{Identifier: lr.Identifier}); err != nil {
+				log.Errorf("Fish: Reconcile: Unable to deallocate Resource %s of driver %s: %v", lr.Identifier, name, err)
+				continue
+			}
+			terminated++
+		}
+
+		if ghosts > 0 || untracked > 0 {
+			f.nodeHistoryEvent(HistoryNodeReconciled, fmt.Sprintf("Driver %s: %d missing, %d unknown (%d adopted, %d deallocated)", name, ghosts, untracked, adopted, terminated))
+		}
+	}
+	return nil
+}
+
+// reconcileKnown checks if the driver resource identifier is used by the Resource or recycle pool
+func (f *Fish) reconcileKnown(identifier string) (bool, error) {
+	var count int64
+	if err := f.db.Model(&types.Resource{}).Where("identifier = ?", identifier).Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
+	}
+	err := f.db.Model(&recycledResource{}).Where("identifier = ?", identifier).Count(&count).Error
+	return count > 0, err
+}
+
+// reconcileAdopt creates the Resource for the driver resource which was allocated by this node for
+// the Application, but the node crashed before storing it. The Application is resumed as usual
+// allocated one after the reconcile.
+func (f *Fish) reconcileAdopt(drvName string, lr *types.Resource) error {
+	app, err := f.ApplicationGet(lr.ApplicationUID)
+	if err != nil {
+		return fmt.Errorf("Unable to find Application: %v", err)
+	}
+	state, err := f.ApplicationStateGetByApplication(app.UID)
+	if err != nil {
+		return fmt.Errorf("Unable to get Application state: %v", err)
+	}
+	if state.Status != types.ApplicationStatusELECTED {
+		return fmt.Errorf("Application is %s, not waiting for the Resource", state.Status)
+	}
+	vote, err := f.VoteGetNodeApplication(f.node.UID, app.UID)
+	if err != nil {
+		return fmt.Errorf("Unable to find the node vote: %v", err)
+	}
+	label, err := f.LabelGet(app.LabelUID)
+	if err != nil {
+		return fmt.Errorf("Unable to find Label: %v", err)
+	}
+	if vote.Available < 0 || len(label.Definitions) <= vote.Available || label.Definitions[vote.Available].Driver != drvName {
+		return fmt.Errorf("Voted definition %d of Label %s is not using the driver", vote.Available, label.UID)
+	}
+	def := label.Definitions[vote.Available]
+
+	// Restoring the metadata the same way as on allocation, the driver got it already
+	var metadata map[string]any
+	json.Unmarshal([]byte(app.Metadata), &metadata)
+	json.Unmarshal([]byte(label.Metadata), &metadata)
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata[drivers.MetadataSeed] = strconv.FormatInt(app.Seed, 10)
+	metadata[drivers.MetadataNode] = f.node.UID.String()
+	metadata[drivers.MetadataApplication] = app.UID.String()
+	mergedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("Unable to merge metadata: %v", err)
+	}
+
+	res := &types.Resource{
+		ApplicationUID:  app.UID,
+		NodeUID:         f.node.UID,
+		LabelUID:        label.UID,
+		DefinitionIndex: vote.Available,
+		Identifier:      lr.Identifier,
+		HwAddr:          lr.HwAddr,
+		IpAddr:          lr.IpAddr,
+		Authentication:  def.Authentication,
+		DriverInfo:      util.UnparsedJSON("{}"),
+		Metadata:        util.UnparsedJSON(mergedMetadata),
+		HourlyCost:      f.definitionHourlyCost(def),
+	}
+	if drv := f.driverGet(drvName); drv != nil {
+		res.Tasks = drivers.SupportedTasks(drv)
+	}
+	if err := f.ResourceCreate(res); err != nil {
+		return fmt.Errorf("Unable to store Resource: %v", err)
+	}
+	return f.ApplicationStateCreate(&types.ApplicationState{ApplicationUID: app.UID, Status: types.ApplicationStatusALLOCATED,
+		Description: fmt.Sprintf("Resource %s is adopted from driver on node startup", lr.Identifier),
+	})
+}
+
+// resourceDriverName returns the driver instance name of the Resource Label definition
+func (f *Fish) resourceDriverName(res *types.Resource) string {
+	label, err := f.LabelGet(res.LabelUID)
+	if err != nil || len(label.Definitions) <= res.DefinitionIndex {
+		return ""
+	}
+	return label.Definitions[res.DefinitionIndex].Driver
+}
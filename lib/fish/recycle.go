@@ -0,0 +1,279 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// recycledResource is the warm Resource waiting in the pool for the next Application
+//
+// The pool is stored in DB to not lose track of the warm Resources in case of node restart
+type recycledResource struct {
+	UID             uuid.UUID `gorm:"primaryKey"`
+	NodeUID         uuid.UUID `gorm:"index"`
+	LabelUID        types.LabelUID
+	DefinitionIndex int
+
+	// Driver-provided resource info, the DB Resource is recreated for each Application
+	Identifier     string
+	HwAddr         string
+	IpAddr         string
+	Authentication *types.Authentication
+	DriverInfo     util.UnparsedJSON
+
+	Reused      int       // How many times the Resource was already used by Applications
+	AllocatedAt time.Time // When the Resource was allocated by driver
+	PooledAt    time.Time // When the Resource was put to the pool
+	Destroying  bool      // Deallocate failed, it's retried on the next node start
+
+	// Restored from the Label Definition, so not stored
+	Driver     drivers.ResourceDriver `gorm:"-"`
+	Definition types.LabelDefinition  `gorm:"-"`
+}
+
+// recyclePoolRestore loads the node pool from DB, the Resources which can't be served anymore
+// are forgotten since there is no driver to destroy them and the ones failed to destroy before
+// are destroyed again
+func (f *Fish) recyclePoolRestore() error {
+	var pool []*recycledResource
+	if err := f.db.Where("node_uid = ?", f.node.UID).Find(&pool).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to get recycle pool: %v", err)
+	}
+
+	f.recyclePoolMutex.Lock()
+	defer f.recyclePoolMutex.Unlock()
+
+	for _, r := range pool {
+		label, err := f.LabelGet(r.LabelUID)
+		if err == nil && r.DefinitionIndex < len(label.Definitions) {
+			r.Definition = label.Definitions[r.DefinitionIndex]
+			r.Driver = f.driverGet(r.Definition.Driver)
+		}
+		if r.Driver == nil || r.Definition.Recycle == nil {
+			log.Errorf("Fish: Unable to restore the recycle pool Resource %s: no Label Definition or driver", r.Identifier)
+			f.db.Delete(r)
+			continue
+		}
+		// The pooled local Resources are consuming the node resources
+		if !r.Driver.IsRemote() {
+			f.nodeUsageMutex.Lock()
+			f.nodeUsage.Add(r.Definition.Resources)
+			f.nodeUsageMutex.Unlock()
+		}
+		if r.Destroying {
+			if err := f.recyclePoolDestroy(r); err != nil {
+				log.Error("Fish: Unable to destroy the recycle pool Resource:", r.Identifier, err)
+			}
+			continue
+		}
+		log.Info("Fish: Restored the recycle pool Resource:", r.Identifier)
+		f.recyclePool = append(f.recyclePool, r)
+	}
+
+	return nil
+}
+
+// recyclePoolHas checks if the pool contains Resource for the Label Definition
+func (f *Fish) recyclePoolHas(labelUID types.LabelUID, defIndex int) bool {
+	f.recyclePoolMutex.Lock()
+	defer f.recyclePoolMutex.Unlock()
+
+	for _, r := range f.recyclePool {
+		if r.LabelUID == labelUID && r.DefinitionIndex == defIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// recyclePoolTake returns the Resource from the pool for the Label Definition or nil if not found
+func (f *Fish) recyclePoolTake(labelUID types.LabelUID, defIndex int) *recycledResource {
+	f.recyclePoolMutex.Lock()
+	defer f.recyclePoolMutex.Unlock()
+
+	for i, r := range f.recyclePool {
+		if r.LabelUID != labelUID || r.DefinitionIndex != defIndex {
+			continue
+		}
+		f.recyclePool = append(f.recyclePool[:i], f.recyclePool[i+1:]...)
+		// Now the Resource will be tracked as the Application Resource
+		if err := f.db.Delete(r).Error; err != nil {
+			log.Error("Fish: Unable to remove the recycle pool Resource from DB:", r.Identifier, err)
+		}
+		return r
+	}
+	return nil
+}
+
+// recycleReuse prepares the pool Resource to serve the new Application, the reuse task of the
+// policy delivers the Application metadata to the running Resource
+func (f *Fish) recycleReuse(r *recycledResource, res *types.Resource) error {
+	policy := r.Definition.Recycle
+	if policy == nil || policy.ReuseTask == "" {
+		return nil
+	}
+
+	t := r.Driver.GetTask(policy.ReuseTask, string(policy.ReuseOptions))
+	if t == nil {
+		return fmt.Errorf("Fish: Unable to get reuse task %q for Resource %s", policy.ReuseTask, r.Identifier)
+	}
+	task := &types.ApplicationTask{
+		ApplicationUID: res.ApplicationUID,
+		Task:           policy.ReuseTask,
+		When:           types.ApplicationStatusALLOCATED,
+		Options:        policy.ReuseOptions,
+		Result:         util.UnparsedJSON("{}"),
+	}
+	t.SetInfo(task, &r.Definition, res)
+	if _, err := t.Execute(); err != nil {
+		return fmt.Errorf("Fish: Reuse task %q failed for Resource %s: %v", policy.ReuseTask, r.Identifier, err)
+	}
+
+	return nil
+}
+
+// recycleResource tries to cleanup the Resource and return it to the pool instead of deallocation
+// Returns true if the Resource was recycled and should not be deallocated
+func (f *Fish) recycleResource(r *recycledResource, res *types.Resource) bool {
+	policy := r.Definition.Recycle
+	if policy == nil || !f.running || f.maintenance || f.shutdown {
+		return false
+	}
+
+	if policy.MaxReuse > 0 && r.Reused >= policy.MaxReuse {
+		log.Infof("Fish: Resource %s reached max reuse count %d, destroying", r.Identifier, policy.MaxReuse)
+		return false
+	}
+	if maxAge, err := time.ParseDuration(policy.MaxAge); err == nil && maxAge > 0 && time.Since(r.AllocatedAt) > maxAge {
+		log.Infof("Fish: Resource %s reached max age %s, destroying", r.Identifier, maxAge)
+		return false
+	}
+
+	// Running cleanup task to bring the Resource to the clean state
+	if policy.Task != "" {
+		t := r.Driver.GetTask(policy.Task, string(policy.Options))
+		if t == nil {
+			log.Errorf("Fish: Unable to get recycle task %q for Resource %s, destroying", policy.Task, r.Identifier)
+			return false
+		}
+		task := &types.ApplicationTask{
+			ApplicationUID: res.ApplicationUID,
+			Task:           policy.Task,
+			When:           types.ApplicationStatusDEALLOCATE,
+			Options:        policy.Options,
+			Result:         util.UnparsedJSON("{}"),
+		}
+		t.SetInfo(task, &r.Definition, res)
+		if _, err := t.Execute(); err != nil {
+			log.Errorf("Fish: Recycle task %q failed for Resource %s, destroying: %v", policy.Task, r.Identifier, err)
+			return false
+		}
+	}
+
+	r.Reused++
+	r.PooledAt = time.Now()
+	r.UID = f.NewUID()
+	r.NodeUID = f.node.UID
+	if err := f.db.Create(r).Error; err != nil {
+		log.Errorf("Fish: Unable to store the recycle pool Resource %s, destroying: %v", r.Identifier, err)
+		return false
+	}
+
+	f.recyclePoolMutex.Lock()
+	f.recyclePool = append(f.recyclePool, r)
+	f.recyclePoolMutex.Unlock()
+
+	log.Infof("Fish: Resource %s returned to the recycle pool (used %d times)", r.Identifier, r.Reused)
+
+	return true
+}
+
+// recyclePoolProcess periodically destroys the pool Resources which are idle or too old
+func (f *Fish) recyclePoolProcess() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		var expired []*recycledResource
+		f.recyclePoolMutex.Lock()
+		pool := f.recyclePool[:0]
+		for _, r := range f.recyclePool {
+			idle, _ := time.ParseDuration(r.Definition.Recycle.IdleTimeout)
+			maxAge, _ := time.ParseDuration(r.Definition.Recycle.MaxAge)
+			if idle > 0 && time.Since(r.PooledAt) > idle || maxAge > 0 && time.Since(r.AllocatedAt) > maxAge || f.maintenance {
+				expired = append(expired, r)
+				continue
+			}
+			pool = append(pool, r)
+		}
+		f.recyclePool = pool
+		f.recyclePoolMutex.Unlock()
+
+		for _, r := range expired {
+			if err := f.recyclePoolDestroy(r); err != nil {
+				log.Error("Fish: Unable to destroy the recycle pool Resource:", r.Identifier, err)
+			}
+		}
+	}
+}
+
+// recyclePoolDestroy deallocates the pool Resource and releases the node resources, in case of
+// error the Resource is stored in DB to try again on the next node start and keeps consuming the
+// node resources
+func (f *Fish) recyclePoolDestroy(r *recycledResource) error {
+	log.Info("Fish: Destroying the recycle pool Resource:", r.Identifier)
+	res := &types.Resource{
+		LabelUID:        r.LabelUID,
+		DefinitionIndex: r.DefinitionIndex,
+		Identifier:      r.Identifier,
+		HwAddr:          r.HwAddr,
+		IpAddr:          r.IpAddr,
+		Authentication:  r.Authentication,
+	}
+	if err := r.Driver.Deallocate(res); err != nil {
+		// The taken Resource is already removed from DB, so storing it back to not lose track
+		r.Destroying = true
+		if r.UID == uuid.Nil {
+			r.UID = f.NewUID()
+		}
+		r.NodeUID = f.node.UID
+		if dbErr := f.db.Save(r).Error; dbErr != nil {
+			log.Error("Fish: Unable to store the recycle pool Resource to destroy later:", r.Identifier, dbErr)
+		}
+		return fmt.Errorf("Fish: Unable to deallocate Resource %s: %v", r.Identifier, err)
+	}
+	if r.UID != uuid.Nil {
+		if err := f.db.Delete(r).Error; err != nil {
+			log.Error("Fish: Unable to remove the recycle pool Resource from DB:", r.Identifier, err)
+		}
+	}
+
+	// The pooled local Resources are still consuming the node resources
+	if !r.Driver.IsRemote() {
+		f.nodeUsageMutex.Lock()
+		f.nodeUsage.Subtract(r.Definition.Resources)
+		f.nodeUsageMutex.Unlock()
+	}
+	return nil
+}
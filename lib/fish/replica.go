@@ -0,0 +1,157 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// dbFind returns the database to run the list query, the reporting queries are explicitly
+// served by the replica (if enabled and synced) to not affect the live database. The replica
+// could be stale up to db_replica_sync_interval, so it should not be used for the decisions.
+func (f *Fish) dbFind(report bool) *gorm.DB {
+	if !report {
+		return f.db
+	}
+	f.replicaMutex.RLock()
+	defer f.replicaMutex.RUnlock()
+
+	if f.replica != nil {
+		return f.replica
+	}
+	return f.db
+}
+
+// replicaProcess periodically creates the snapshot of the live database and switches the
+// reporting queries to it
+func (f *Fish) replicaProcess() {
+	interval := time.Duration(f.cfg.DBReplicaSyncInterval)
+	dir := filepath.Join(NodeDirectory(f.cfg), "replica")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		log.Error("Fish: Unable to create replica directory, replica is disabled:", err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f.replicaSync(dir); err != nil {
+			log.Error("Fish: Unable to sync DB replica:", err)
+		}
+		<-ticker.C
+		if !f.running {
+			break
+		}
+	}
+
+	// Switching back to the live db
+	f.replicaMutex.Lock()
+	old, oldPath := f.replica, f.replicaPath
+	f.replica, f.replicaPath = nil, ""
+	f.replicaMutex.Unlock()
+	replicaClose(old, oldPath)
+}
+
+// replicaSync makes a new snapshot of the database and replaces the current replica with it
+func (f *Fish) replicaSync(dir string) error {
+	path := filepath.Join(dir, fmt.Sprintf("sqlite-%d.db", time.Now().UnixNano()))
+
+	if err := f.dbSnapshot(path); err != nil {
+		return err
+	}
+
+	replica, err := gorm.Open(sqlite.Open("file:"+path+"?mode=ro"), &gorm.Config{Logger: f.db.Logger})
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("Fish: Unable to open the database replica: %v", err)
+	}
+
+	f.replicaMutex.Lock()
+	old, oldPath := f.replica, f.replicaPath
+	f.replica, f.replicaPath = replica, path
+	f.replicaMutex.Unlock()
+
+	// Giving the running queries on the old replica some time to complete
+	if old != nil {
+		go func() {
+			time.Sleep(time.Minute)
+			replicaClose(old, oldPath)
+		}()
+	}
+	log.Debug("Fish: DB replica synced:", path)
+
+	return nil
+}
+
+func replicaClose(db *gorm.DB, path string) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+	if err := os.Remove(path); err != nil {
+		log.Warn("Fish: Unable to remove old DB replica:", path, err)
+	}
+}
+
+// dbSnapshot writes the consistent copy of the live database to the path. The live database is
+// using one connection, so the snapshot is made through the separated one to not block the other
+// queries. In WAL mode VACUUM INTO reads the consistent snapshot of the database while the
+// writers continue to work through the main connection.
+func (f *Fish) dbSnapshot(path string) error {
+	var dbList []struct {
+		Name string
+		File string
+	}
+	if err := f.db.Raw("PRAGMA database_list").Scan(&dbList).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to locate the database file: %v", err)
+	}
+	var dbPath string
+	for _, d := range dbList {
+		if d.Name == "main" {
+			dbPath = d.File
+		}
+	}
+	if dbPath == "" {
+		return fmt.Errorf("Fish: Unable to snapshot in-memory database")
+	}
+	return DBSnapshot(dbPath, path, f.db.Logger)
+}
+
+// DBSnapshot writes the consistent copy of the database file to the path, could be used while
+// the node is running
+func DBSnapshot(dbPath, path string, l logger.Interface) error {
+	src, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{Logger: l})
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to open the database to snapshot: %v", err)
+	}
+	err = src.Exec("VACUUM INTO ?", path).Error
+	if sqlDB, e := src.DB(); e == nil {
+		sqlDB.Close()
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("Fish: Unable to snapshot the database: %v", err)
+	}
+	return nil
+}
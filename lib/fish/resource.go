@@ -15,20 +15,23 @@ package fish
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mostlygeek/arp"
 	"gorm.io/gorm"
 
+	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// ResourceFind lists Resources that fits filter
-func (f *Fish) ResourceFind(filter *string) (rs []types.Resource, err error) {
-	db := f.db
+// ResourceFind lists Resources that fits filter, report uses the replica
+func (f *Fish) ResourceFind(filter *string, report bool) (rs []types.Resource, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -66,6 +69,11 @@ func (f *Fish) ResourceCreate(r *types.Resource) error {
 	if len(r.Metadata) < 2 {
 		return fmt.Errorf("Fish: Metadata can't be empty")
 	}
+	if r.DriverInfo == "" {
+		r.DriverInfo = util.UnparsedJSON("{}")
+	}
+
+	r.AddressFamily = util.AddressFamily(r.IpAddr)
 
 	r.UID = f.NewUID()
 	return f.db.Create(r).Error
@@ -78,6 +86,21 @@ func (f *Fish) ResourceDelete(uid types.ResourceUID) error {
 	if err != nil {
 		log.Errorf("Unable to delete ResourceAccess associated with Resource UID=%v: %v", uid, err)
 	}
+	// The secrets which were not delivered to the Resource are not needed anymore
+	if res, err := f.ResourceGet(uid); err == nil {
+		if err := f.ApplicationSecretDeleteByApplication(res.ApplicationUID); err != nil {
+			log.Errorf("Unable to delete ApplicationSecrets associated with Resource UID=%v: %v", uid, err)
+		}
+		if err := f.vaultDeleteByApplication(res.ApplicationUID); err != nil {
+			log.Errorf("Unable to delete vault secrets associated with Resource UID=%v: %v", uid, err)
+		}
+	}
+	// All the access methods are revoked with the Resource
+	f.resourceSessionsRevoke(uid)
+	f.resourceActivityMutex.Lock()
+	delete(f.resourceActivity, uid)
+	delete(f.resourceUsage, uid)
+	f.resourceActivityMutex.Unlock()
 	// Now purge the resource.
 	return f.db.Delete(&types.Resource{}, uid).Error
 }
@@ -94,6 +117,50 @@ func (f *Fish) ResourceGet(uid types.ResourceUID) (res *types.Resource, err erro
 	return res, err
 }
 
+// ResourceSetHostKeyFingerprint stores the Resource SSH host key fingerprint
+func (f *Fish) ResourceSetHostKeyFingerprint(uid types.ResourceUID, fingerprint string) error {
+	return f.db.Model(&types.Resource{}).Where("uid = ?", uid).Update("host_key_fingerprint", fingerprint).Error
+}
+
+// resourceProbeHostKeyProcess receives the Resource SSH host key fingerprint once it's booted
+// The Resource could still boot, so it's not an error if it's not available - will be tried later
+func (f *Fish) resourceProbeHostKeyProcess(uid types.ResourceUID) {
+	deadline := time.Now().Add(30 * time.Minute)
+	for f.running && time.Now().Before(deadline) {
+		res, err := f.ResourceGet(uid)
+		if err != nil || res.HostKeyFingerprint != "" || res.IpAddr == "" {
+			// Resource is gone or already have the fingerprint
+			return
+		}
+		port := 22
+		if res.Authentication != nil && res.Authentication.Port > 0 {
+			port = res.Authentication.Port
+		}
+		fingerprint, err := crypt.GetSSHHostKeyFingerprint(net.JoinHostPort(res.IpAddr, strconv.Itoa(port)), 5*time.Second)
+		if err == nil {
+			if err := f.ResourceSetHostKeyFingerprint(uid, fingerprint); err != nil {
+				log.Errorf("Fish: Unable to save SSH host key fingerprint of Resource %s: %v", uid, err)
+			}
+			return
+		}
+		log.Debugf("Fish: Unable to get SSH host key of Resource %s: %v", uid, err)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// ResourceGetProxySSHPolicy returns the SSH proxy access policy for the Resource
+func (f *Fish) ResourceGetProxySSHPolicy(res *types.Resource) types.ProxySSHPolicy {
+	label, err := f.LabelGet(res.LabelUID)
+	if err != nil {
+		log.Warnf("Fish: Unable to find Label %s of Resource %s, using default policy: %v", res.LabelUID, res.UID, err)
+		return f.cfg.ProxySSHPolicy
+	}
+	if res.DefinitionIndex < len(label.Definitions) && label.Definitions[res.DefinitionIndex].ProxySshPolicy != nil {
+		return *label.Definitions[res.DefinitionIndex].ProxySshPolicy
+	}
+	return f.cfg.ProxySSHPolicy
+}
+
 func fixHwAddr(hwaddr string) string {
 	split := strings.Split(hwaddr, ":")
 	if len(split) == 6 {
@@ -160,6 +227,7 @@ func (f *Fish) ResourceGetByIP(ip string) (res *types.Resource, err error) {
 		if f.ApplicationIsAllocated(res.ApplicationUID) != nil {
 			return nil, fmt.Errorf("Fish: Prohibited to access the Resource of not allocated Application")
 		}
+		f.ResourceActivityTouch(res.UID)
 
 		return res, nil
 	}
@@ -186,6 +254,8 @@ func (f *Fish) ResourceGetByIP(ip string) (res *types.Resource, err error) {
 		return nil, fmt.Errorf("Fish: Prohibited to access the Resource of not allocated Application")
 	}
 
+	f.ResourceActivityTouch(res.UID)
+
 	log.Debug("Fish: Update IP address for the Resource of Application", res.ApplicationUID, ip)
 	res.IpAddr = ip
 	err = f.ResourceSave(res)
@@ -14,6 +14,7 @@ package fish
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/google/uuid"
 
@@ -77,3 +78,58 @@ func (f *Fish) ResourceAccessSingleUseKey(username string, key string) (ra *type
 	}
 	return ra, err
 }
+
+// AccessGateRegister adds the gate access method to the Resources access methods list, the
+// endpoint is the URL prefix which will be completed by the Application UID
+func (f *Fish) AccessGateRegister(name, endpoint string) {
+	f.accessGatesMutex.Lock()
+	defer f.accessGatesMutex.Unlock()
+	f.accessGates[name] = endpoint
+}
+
+// AccessGateUnregister removes the gate access method when the gate is stopped
+func (f *Fish) AccessGateUnregister(name string) {
+	f.accessGatesMutex.Lock()
+	defer f.accessGatesMutex.Unlock()
+	delete(f.accessGates, name)
+}
+
+// ResourceAccessMethods returns the list of ways the user could reach the Resource right now
+func (f *Fish) ResourceAccessMethods(res *types.Resource) []types.AccessMethod {
+	var methods []types.AccessMethod
+	if endpoint := f.GetProxySSHEndpoint(); endpoint != "" {
+		fingerprint := f.GetProxySSHFingerprint()
+		methods = append(methods, types.AccessMethod{
+			Type:               "ssh",
+			Endpoint:           endpoint,
+			HostKeyFingerprint: fingerprint,
+			Credentials:        "resource_access",
+		})
+		policy := f.ResourceGetProxySSHPolicy(res)
+		if !policy.DenyPortForward && !policy.ShellOnly {
+			methods = append(methods, types.AccessMethod{
+				Type:               "port_forward",
+				Endpoint:           endpoint,
+				HostKeyFingerprint: fingerprint,
+				Credentials:        "resource_access",
+			})
+		}
+	}
+
+	f.accessGatesMutex.Lock()
+	names := make([]string, 0, len(f.accessGates))
+	for name := range f.accessGates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		methods = append(methods, types.AccessMethod{
+			Type:        name,
+			Endpoint:    f.accessGates[name] + res.ApplicationUID.String(),
+			Credentials: "token",
+		})
+	}
+	f.accessGatesMutex.Unlock()
+
+	return methods
+}
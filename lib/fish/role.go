@@ -0,0 +1,138 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// RoleFind returns all the custom roles
+func (f *Fish) RoleFind() (rs []types.Role, err error) {
+	err = f.db.Order("name").Find(&rs).Error
+	return rs, err
+}
+
+// RoleSave creates or updates the custom role
+func (f *Fish) RoleSave(r *types.Role) error {
+	if err := roleValidate(r); err != nil {
+		return err
+	}
+	return f.db.Save(r).Error
+}
+
+// roleValidate checks the custom role and fills the empty lists
+func roleValidate(r *types.Role) error {
+	if r.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if r.Name == "admin" || slices.Contains(Roles, r.Name) {
+		return fmt.Errorf("Fish: Role %q is built-in", r.Name)
+	}
+	if r.MaxApplications < 0 || r.MaxCpu < 0 || r.MaxRam < 0 || r.MaxHours < 0 {
+		return fmt.Errorf("Fish: Role quota limits can't be negative")
+	}
+	for _, pattern := range append(slices.Clone(r.Labels), r.Locations...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Fish: Wrong Role pattern %q: %v", pattern, err)
+		}
+	}
+	if r.Labels == nil {
+		r.Labels = []string{}
+	}
+	if r.Locations == nil {
+		r.Locations = []string{}
+	}
+	return nil
+}
+
+// RoleGet returns the custom role by it's unique name
+func (f *Fish) RoleGet(name string) (r *types.Role, err error) {
+	r = &types.Role{}
+	err = f.db.First(r, "name = ?", name).Error
+	return r, err
+}
+
+// RoleDelete removes the custom role which is not granted to anyone
+func (f *Fish) RoleDelete(name string) error {
+	var count int64
+	if err := f.db.Model(&types.RoleGrant{}).Where("role = ? AND revoked_at IS NULL", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("Fish: Role %q still has %d active grants", name, count)
+	}
+	return f.db.Delete(&types.Role{}, "name = ?", name).Error
+}
+
+// userRoles returns the custom roles actively granted to the User
+func (f *Fish) userRoles(name string) (rs []types.Role, err error) {
+	err = f.db.Where("name IN (?)", f.db.Model(&types.RoleGrant{}).Select("role").
+		Where("user_name = ? AND revoked_at IS NULL AND expires_at > ?", name, time.Now())).
+		Order("name").Find(&rs).Error
+	return rs, err
+}
+
+// roleScope returns the union of the patterns of the roles, nil means not restricted: the User
+// has no custom roles or one of them allows any
+func roleScope(rs []types.Role, patterns func(*types.Role) []string) (out []string) {
+	for i := range rs {
+		p := patterns(&rs[i])
+		if len(p) == 0 {
+			return nil
+		}
+		out = append(out, p...)
+	}
+	return out
+}
+
+// roleLabelCheck returns error if the custom roles of the owner are not allowing the Label
+func (f *Fish) roleLabelCheck(owner string, label *types.Label) error {
+	rs, err := f.userRoles(owner)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to get the User roles: %v", err)
+	}
+	labels := roleScope(rs, func(r *types.Role) []string { return r.Labels })
+	if labels != nil && !roleMatchAny(labels, label.Name) {
+		return fmt.Errorf("Fish: Roles restrict Label %q, allowed: %v", label.Name, labels)
+	}
+	return nil
+}
+
+// roleLocationAllowed checks the custom roles of the owner allow to allocate the Applications in
+// this node location. The error is not restricting, so the node will not silently stop voting for
+// the owner Applications because of the transient DB failure.
+func (f *Fish) roleLocationAllowed(owner string) bool {
+	rs, err := f.userRoles(owner)
+	if err != nil {
+		log.Warn("Fish: Unable to get the User roles to check location:", owner, err)
+		return true
+	}
+	locations := roleScope(rs, func(r *types.Role) []string { return r.Locations })
+	return locations == nil || roleMatchAny(locations, f.node.LocationName)
+}
+
+// roleMatchAny checks the value matches at least one of the patterns
+func roleMatchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// RoleOperator allows the User to act as admin for the operational calls, but not to manage the
+// Users and their grants, to access the Resources, approve deallocation or read profiling info
+const RoleOperator = "operator"
+
+// RoleGrantRevoker is used as revoker name when the grant is expired
+const RoleGrantRevoker = "fish"
+
+// Roles which could be granted to the User
+var Roles = []string{RoleOperator, RoleAuditor, RoleApprover}
+
+// RoleGrantCreate grants the role to the User till the grant expires
+func (f *Fish) RoleGrantCreate(g *types.RoleGrant) error {
+	if !slices.Contains(Roles, g.Role) {
+		// The custom roles created by admin could be granted too
+		if _, err := f.RoleGet(g.Role); err != nil {
+			return fmt.Errorf("Fish: Unknown role %q, available: %v and the custom roles", g.Role, Roles)
+		}
+	}
+	if g.UserName == "" {
+		return fmt.Errorf("Fish: UserName can't be empty")
+	}
+	if g.GrantedBy == "" {
+		return fmt.Errorf("Fish: GrantedBy can't be empty")
+	}
+	if !g.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("Fish: ExpiresAt should be in the future")
+	}
+	if _, err := f.UserGet(g.UserName); err != nil {
+		return fmt.Errorf("Fish: Unable to find User %q: %v", g.UserName, err)
+	}
+
+	g.UID = f.NewUID()
+	g.RevokedAt = nil
+	g.RevokedBy = ""
+	if err := f.db.Create(g).Error; err != nil {
+		return err
+	}
+	log.Infof("Fish: AUDIT: Role %q granted to User %q by %q till %s", g.Role, g.UserName, g.GrantedBy, g.ExpiresAt)
+	return nil
+}
+
+// RoleGrantGet returns the grant by UID
+func (f *Fish) RoleGrantGet(uid types.RoleGrantUID) (g *types.RoleGrant, err error) {
+	g = &types.RoleGrant{}
+	err = f.db.First(g, uid).Error
+	return g, err
+}
+
+// RoleGrantListUser returns all the grants of the User
+func (f *Fish) RoleGrantListUser(name string) (gs []types.RoleGrant, err error) {
+	err = f.db.Where("user_name = ?", name).Order("created_at").Find(&gs).Error
+	return gs, err
+}
+
+// RoleGrantRevoke marks the grant as revoked, the grant record is kept for audit
+func (f *Fish) RoleGrantRevoke(g *types.RoleGrant, revoker string) error {
+	if g.RevokedAt != nil {
+		return fmt.Errorf("Fish: The grant is already revoked by %q", g.RevokedBy)
+	}
+	now := time.Now()
+	g.RevokedAt = &now
+	g.RevokedBy = revoker
+	if err := f.db.Model(g).Select("revoked_at", "revoked_by").Updates(g).Error; err != nil {
+		return err
+	}
+	log.Infof("Fish: AUDIT: Role %q of User %q revoked by %q", g.Role, g.UserName, revoker)
+	return nil
+}
+
+// roleGrantRevokeUser revokes all the active grants of the User
+func (f *Fish) roleGrantRevokeUser(name, revoker string) error {
+	var active []types.RoleGrant
+	if err := f.db.Where("user_name = ? AND revoked_at IS NULL", name).Find(&active).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to find the User %q grants: %v", name, err)
+	}
+	for i := range active {
+		if err := f.RoleGrantRevoke(&active[i], revoker); err != nil {
+			return fmt.Errorf("Fish: Unable to revoke the User %q grant %s: %v", name, active[i].UID, err)
+		}
+	}
+	return nil
+}
+
+// UserHasRole checks if the User has active grant of the role, admin has all the roles
+func (f *Fish) UserHasRole(name, role string) bool {
+	if name == "admin" {
+		return true
+	}
+	var count int64
+	f.db.Model(&types.RoleGrant{}).Where("user_name = ? AND role = ? AND revoked_at IS NULL AND expires_at > ?",
+		name, role, time.Now()).Count(&count)
+	return count > 0
+}
+
+// roleGrantProcess periodically revokes the expired grants
+func (f *Fish) roleGrantProcess() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		var expired []types.RoleGrant
+		if err := f.db.Where("revoked_at IS NULL AND expires_at <= ?", time.Now()).Find(&expired).Error; err != nil {
+			log.Error("Fish: Unable to find expired role grants:", err)
+			continue
+		}
+		for i := range expired {
+			if err := f.RoleGrantRevoke(&expired[i], RoleGrantRevoker); err != nil {
+				log.Error("Fish: Unable to revoke expired role grant:", expired[i].UID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// ScheduleRequester is used as the deallocate requester when the Schedule releases Applications
+const ScheduleRequester = "fish-schedule"
+
+// ScheduleFind returns list of Schedules that fits the filter
+func (f *Fish) ScheduleFind(filter *string) (ss []types.Schedule, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return ss, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Find(&ss).Error
+	return ss, err
+}
+
+// ScheduleGet returns Schedule by UID
+func (f *Fish) ScheduleGet(uid types.ScheduleUID) (s *types.Schedule, err error) {
+	s = &types.Schedule{}
+	err = f.db.First(s, uid).Error
+	return s, err
+}
+
+// ScheduleCreate makes new Schedule, it's executed by the node which created it
+func (f *Fish) ScheduleCreate(s *types.Schedule) error {
+	if s.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if _, _, err := scheduleCrons(s); err != nil {
+		return err
+	}
+	if err := f.limitBatchCount(s.Count); err != nil {
+		return err
+	}
+
+	// Checking the Applications could be created at all to not fail on each run
+	proto := types.Application{
+		OwnerName: s.OwnerName,
+		LabelUID:  s.LabelUID,
+		Template:  s.Template,
+		Metadata:  s.Metadata,
+		Priority:  s.Priority,
+	}
+	if err := f.applicationPrepare(&proto); err != nil {
+		return err
+	}
+
+	s.UID = f.NewUID()
+	s.NodeUID = f.node.UID
+	s.Metadata = proto.Metadata
+	// The runs are calculated from the creation time
+	s.LastRunAt = time.Now()
+	s.LastDeallocateAt = s.LastRunAt
+	return f.db.Create(s).Error
+}
+
+// ScheduleDelete removes the Schedule and it's history
+func (f *Fish) ScheduleDelete(uid types.ScheduleUID) error {
+	if err := f.db.Where("schedule_uid = ?", uid).Delete(&types.ScheduleRun{}).Error; err != nil {
+		return err
+	}
+	return f.db.Delete(&types.Schedule{}, uid).Error
+}
+
+// ScheduleEnable enables or disables the Schedule, the runs missed while the Schedule was
+// disabled are not executed
+func (f *Fish) ScheduleEnable(s *types.Schedule, enabled bool) error {
+	if enabled && !s.Enabled {
+		s.LastRunAt = time.Now()
+		s.LastDeallocateAt = s.LastRunAt
+	}
+	s.Enabled = enabled
+	return f.db.Save(s).Error
+}
+
+// ScheduleNext returns the next count times of the Schedule runs
+func (f *Fish) ScheduleNext(s *types.Schedule, count int) (*types.ScheduleNext, error) {
+	allocate, deallocate, err := scheduleCrons(s)
+	if err != nil {
+		return nil, err
+	}
+	out := &types.ScheduleNext{Allocate: []time.Time{}, Deallocate: []time.Time{}}
+	now := time.Now()
+	for t := allocate.next(now); !t.IsZero() && len(out.Allocate) < count; t = allocate.next(t) {
+		out.Allocate = append(out.Allocate, t)
+	}
+	for t := deallocate.next(now); !t.IsZero() && len(out.Deallocate) < count; t = deallocate.next(t) {
+		out.Deallocate = append(out.Deallocate, t)
+	}
+	return out, nil
+}
+
+// ScheduleHistory returns the runs of the Schedule, latest first
+func (f *Fish) ScheduleHistory(uid types.ScheduleUID) (rs []types.ScheduleRun, err error) {
+	err = f.db.Where("schedule_uid = ?", uid).Order("created_at DESC").Find(&rs).Error
+	return rs, err
+}
+
+// scheduleCron is the parsed cron expression in the Schedule time zone
+type scheduleCron struct {
+	*util.Cron
+	loc *time.Location
+}
+
+// next returns the first run time after t, zero time if cron is not set
+func (c scheduleCron) next(t time.Time) time.Time {
+	if c.Cron == nil {
+		return time.Time{}
+	}
+	return c.Next(t.In(c.loc))
+}
+
+// scheduleCrons parses the allocate & deallocate cron expressions of the Schedule
+func scheduleCrons(s *types.Schedule) (allocate, deallocate scheduleCron, err error) {
+	loc := time.UTC
+	if s.Timezone != "" {
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return allocate, deallocate, fmt.Errorf("Fish: Unknown timezone %q: %v", s.Timezone, err)
+		}
+	}
+	allocate.loc, deallocate.loc = loc, loc
+	if allocate.Cron, err = util.ParseCron(s.Cron); err != nil {
+		return allocate, deallocate, fmt.Errorf("Fish: Invalid cron: %v", err)
+	}
+	if s.DeallocateCron != "" {
+		if deallocate.Cron, err = util.ParseCron(s.DeallocateCron); err != nil {
+			return allocate, deallocate, fmt.Errorf("Fish: Invalid deallocate cron: %v", err)
+		}
+	}
+	return allocate, deallocate, nil
+}
+
+// scheduleProcess executes the enabled Schedules created by this node
+func (f *Fish) scheduleProcess() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		if f.maintenance || f.shutdown {
+			continue
+		}
+		var schedules []types.Schedule
+		if err := f.db.Where("enabled = ? AND node_uid = ?", true, f.node.UID).Find(&schedules).Error; err != nil {
+			log.Error("Fish: Unable to find Schedules:", err)
+			continue
+		}
+		for i := range schedules {
+			f.scheduleCheck(&schedules[i], time.Now())
+		}
+	}
+}
+
+// scheduleCheck runs the Schedule actions which time has come, the missed runs are merged in one
+func (f *Fish) scheduleCheck(s *types.Schedule, now time.Time) {
+	allocate, deallocate, err := scheduleCrons(s)
+	if err != nil {
+		log.Errorf("Fish: Unable to parse Schedule %s: %v", s.UID, err)
+		return
+	}
+
+	// Deallocation goes first to not release the just created Applications if both are due
+	if t := deallocate.next(s.LastDeallocateAt); !t.IsZero() && !t.After(now) {
+		// Updating just the run time to not override the concurrent Schedule changes
+		s.LastDeallocateAt = now
+		if err := f.db.Model(s).Update("last_deallocate_at", now).Error; err != nil {
+			log.Errorf("Fish: Unable to save Schedule %s: %v", s.UID, err)
+			return
+		}
+		f.scheduleDeallocate(s)
+	}
+	if t := allocate.next(s.LastRunAt); !t.IsZero() && !t.After(now) {
+		s.LastRunAt = now
+		if err := f.db.Model(s).Update("last_run_at", now).Error; err != nil {
+			log.Errorf("Fish: Unable to save Schedule %s: %v", s.UID, err)
+			return
+		}
+		f.scheduleAllocate(s)
+	}
+}
+
+// scheduleAllocate creates the batch of Applications for the Schedule
+func (f *Fish) scheduleAllocate(s *types.Schedule) {
+	log.Infof("Fish: Schedule %s creates %d Applications", s.UID, s.Count)
+	run := &types.ScheduleRun{ScheduleUID: s.UID, Action: "allocate"}
+	b := &types.ApplicationBatch{
+		OwnerName: s.OwnerName,
+		LabelUID:  s.LabelUID,
+		Template:  s.Template,
+		Metadata:  s.Metadata,
+		Priority:  s.Priority,
+		Count:     s.Count,
+	}
+	if err := f.ApplicationBatchCreate(context.Background(), b); err != nil {
+		log.Errorf("Fish: Schedule %s is unable to create Applications: %v", s.UID, err)
+		run.Error = err.Error()
+	} else {
+		run.BatchUID = b.UID
+		run.Count = b.Count
+	}
+	f.scheduleRunSave(run)
+}
+
+// scheduleDeallocate releases the active Applications created by the Schedule
+func (f *Fish) scheduleDeallocate(s *types.Schedule) {
+	run := &types.ScheduleRun{ScheduleUID: s.UID, Action: "deallocate"}
+	var apps []types.Application
+	err := f.db.Where("batch_uid IN (?)",
+		f.db.Model(&types.ScheduleRun{}).Select("batch_uid").Where("schedule_uid = ? AND action = ?", s.UID, "allocate"),
+	).Find(&apps).Error
+	if err != nil {
+		log.Errorf("Fish: Schedule %s is unable to find Applications: %v", s.UID, err)
+		run.Error = err.Error()
+	}
+	for i := range apps {
+		state, err := f.ApplicationStateGetByApplication(apps[i].UID)
+		if err != nil || !f.ApplicationStateIsActive(state.Status) || state.Status == types.ApplicationStatusHOLD {
+			continue
+		}
+		if _, err := f.ApplicationDeallocate(&apps[i], ScheduleRequester); err != nil {
+			log.Errorf("Fish: Schedule %s is unable to deallocate Application %s: %v", s.UID, apps[i].UID, err)
+			run.Error = err.Error()
+			continue
+		}
+		run.Count++
+	}
+	log.Infof("Fish: Schedule %s deallocated %d Applications", s.UID, run.Count)
+	f.scheduleRunSave(run)
+}
+
+// scheduleRunSave stores the Schedule run in history
+func (f *Fish) scheduleRunSave(run *types.ScheduleRun) {
+	run.UID = f.NewUID()
+	if err := f.db.Create(run).Error; err != nil {
+		log.Errorf("Fish: Unable to store Schedule %s run: %v", run.ScheduleUID, err)
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"sort"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+const (
+	// SchedulerFIFO serves the same priority Applications in order of request
+	SchedulerFIFO = "fifo"
+	// SchedulerFairShare serves first the same priority Applications of the owner using less
+	SchedulerFairShare = "fair_share"
+)
+
+// SchedulerShares returns the current usage of the cluster by the Application owners
+func (f *Fish) SchedulerShares() ([]types.SchedulerShare, error) {
+	states, err := f.ApplicationStateListActive()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[types.ApplicationUID]types.ApplicationStatus, len(states))
+	uids := make([]types.ApplicationUID, 0, len(states))
+	for _, s := range states {
+		statuses[s.ApplicationUID] = s.Status
+		uids = append(uids, s.ApplicationUID)
+	}
+	apps, err := f.ApplicationListByUIDs(uids)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]*types.SchedulerShare)
+	total := 0
+	for _, app := range apps {
+		share, ok := owners[app.OwnerName]
+		if !ok {
+			share = &types.SchedulerShare{OwnerName: app.OwnerName}
+			owners[app.OwnerName] = share
+		}
+		if statuses[app.UID] == types.ApplicationStatusNEW {
+			share.Waiting++
+		} else {
+			share.Allocated++
+			total++
+		}
+	}
+
+	out := make([]types.SchedulerShare, 0, len(owners))
+	for _, share := range owners {
+		if total > 0 {
+			share.Share = float32(share.Allocated) / float32(total)
+		}
+		out = append(out, *share)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OwnerName < out[j].OwnerName })
+	return out, nil
+}
+
+// schedulerUsage returns the number of allocated Applications per owner in fair share mode and
+// nil otherwise, so all the owners are equal
+func (f *Fish) schedulerUsage() map[string]int {
+	if f.cfg.Scheduler != SchedulerFairShare {
+		return nil
+	}
+	shares, err := f.SchedulerShares()
+	if err != nil {
+		log.Error("Fish: Unable to get the owners usage for fair share:", err)
+		return nil
+	}
+	usage := make(map[string]int, len(shares))
+	for _, share := range shares {
+		usage[share.OwnerName] = share.Allocated
+	}
+	return usage
+}
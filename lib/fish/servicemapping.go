@@ -20,9 +20,9 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// ServiceMappingFind returns list of ServiceMappings that fits the filter
-func (f *Fish) ServiceMappingFind(filter *string) (sms []types.ServiceMapping, err error) {
-	db := f.db
+// ServiceMappingFind returns list of ServiceMappings that fits the filter, report uses the replica
+func (f *Fish) ServiceMappingFind(filter *string, report bool) (sms []types.ServiceMapping, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
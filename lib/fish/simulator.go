@@ -0,0 +1,163 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers/aws"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// simulatorReplayState is the Application state row used to replay the recorded workload
+type simulatorReplayState struct {
+	ApplicationUID types.ApplicationUID
+	Status         types.ApplicationStatus
+	CreatedAt      time.Time
+}
+
+// SimulatorDedicated runs the workload through the dedicated pool model for each pool settings
+func (f *Fish) SimulatorDedicated(req types.SimulatorRequest) ([]types.SimulatorResult, error) {
+	if len(req.Pools) == 0 {
+		return nil, fmt.Errorf("Fish: At least one pool settings is required")
+	}
+
+	var workload []aws.SimulatorWorkload
+	if req.Workload != nil {
+		for _, wl := range *req.Workload {
+			workload = append(workload, aws.SimulatorWorkload{Start: wl.Start, Duration: time.Duration(wl.Duration)})
+		}
+	}
+	if req.ReplayWeeks != nil && *req.ReplayWeeks > 0 {
+		label := ""
+		if req.LabelName != nil {
+			label = *req.LabelName
+		}
+		replay, err := f.simulatorReplay(time.Now().AddDate(0, 0, -7*int(*req.ReplayWeeks)), label)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to get the recorded Applications: %v", err)
+		}
+		workload = append(workload, replay...)
+	}
+	if len(workload) == 0 {
+		return nil, fmt.Errorf("Fish: No workload to simulate, set the workload or replay_weeks")
+	}
+
+	out := make([]types.SimulatorResult, 0, len(req.Pools))
+	for _, pool := range req.Pools {
+		res := aws.Simulate(workload, simulatorSettings(pool))
+		result := types.SimulatorResult{
+			Name:         pool.Name,
+			HostsMax:     res.HostsMax,
+			InstancesMax: res.InstancesMax,
+			QueueMax:     res.QueueMax,
+			QueueWaitMax: util.Duration(res.QueueWaitMax),
+			Unserved:     res.Unserved,
+			Cost:         res.Cost,
+			Months:       make([]types.SimulatorMonth, 0, len(res.Months)),
+		}
+		for _, m := range res.Months {
+			result.Months = append(result.Months, types.SimulatorMonth{
+				Month:         m.Month,
+				HostsMax:      m.HostsMax,
+				HostHours:     m.HostHours,
+				InstanceHours: m.InstanceHours,
+				QueueHours:    m.QueueHours,
+				Cost:          m.Cost,
+			})
+		}
+		out = append(out, result)
+	}
+
+	return out, nil
+}
+
+func simulatorSettings(pool types.SimulatorPool) (s aws.SimulatorSettings) {
+	s.Max = pool.Max
+	if pool.ScrubbingDelay != nil {
+		s.ScrubbingDelay = time.Duration(*pool.ScrubbingDelay)
+	}
+	if pool.ScrubbingDuration != nil {
+		s.ScrubbingDuration = time.Duration(*pool.ScrubbingDuration)
+	}
+	if pool.MinUsage != nil {
+		s.MinUsage = time.Duration(*pool.MinUsage)
+	}
+	if pool.Initialize != nil {
+		s.Initialize = time.Duration(*pool.Initialize)
+	}
+	if pool.HostHourlyPrice != nil {
+		s.HostHourlyPrice = *pool.HostHourlyPrice
+	}
+	return s
+}
+
+// simulatorReplay converts the Applications created since the time to the workload, the still
+// running ones are counted till now
+func (f *Fish) simulatorReplay(since time.Time, labelName string) ([]aws.SimulatorWorkload, error) {
+	db := f.dbFind(true).Table("application_states s").
+		Select("s.application_uid, s.status, s.created_at").
+		Joins("JOIN applications a ON a.uid = s.application_uid").
+		Where("a.created_at >= ?", since)
+	if labelName != "" {
+		db = db.Joins("JOIN labels l ON l.uid = a.label_uid").Where("l.name = ?", labelName)
+	}
+	var rows []simulatorReplayState
+	if err := db.Order("s.created_at").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	type appTimes struct {
+		requestedAt time.Time
+		allocatedAt time.Time
+		finishedAt  time.Time
+	}
+	apps := make(map[types.ApplicationUID]*appTimes)
+	for _, row := range rows {
+		app, ok := apps[row.ApplicationUID]
+		if !ok {
+			// Rows are ordered so the first one is the request time
+			app = &appTimes{requestedAt: row.CreatedAt}
+			apps[row.ApplicationUID] = app
+		}
+		switch row.Status {
+		case types.ApplicationStatusALLOCATED:
+			if app.allocatedAt.IsZero() {
+				app.allocatedAt = row.CreatedAt
+			}
+		case types.ApplicationStatusDEALLOCATED, types.ApplicationStatusERROR:
+			if !app.allocatedAt.IsZero() && app.finishedAt.IsZero() {
+				app.finishedAt = row.CreatedAt
+			}
+		}
+	}
+
+	now := time.Now()
+	out := make([]aws.SimulatorWorkload, 0, len(apps))
+	for _, app := range apps {
+		// The Applications which were never allocated are not consuming the hosts
+		if app.allocatedAt.IsZero() {
+			continue
+		}
+		if app.finishedAt.IsZero() {
+			app.finishedAt = now
+		}
+		out = append(out, aws.SimulatorWorkload{Start: app.requestedAt, Duration: app.finishedAt.Sub(app.allocatedAt)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+
+	return out, nil
+}
@@ -0,0 +1,414 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// If the driver did not panic for this time - the restart backoff is reset
+const supervisorStablePeriod = 10 * time.Minute
+
+// supervisedDriver wraps the driver to isolate its panics from the rest of the node
+// In case of panic the call returns error and the driver instance is recreated with backoff
+type supervisedDriver struct {
+	name    string // Instance name like "aws/prod"
+	factory drivers.ResourceDriverFactory
+
+	mutex      sync.RWMutex
+	drv        drivers.ResourceDriver
+	config     []byte
+	restarting bool
+	panics     int       // Panics in a row used to calculate backoff
+	lastPanic  time.Time // When the last panic happened
+}
+
+func newSupervisedDriver(name string, factory drivers.ResourceDriverFactory) *supervisedDriver {
+	return &supervisedDriver{name: name, factory: factory, drv: factory.NewResourceDriver()}
+}
+
+// get returns the current driver instance or error if it's restarting
+func (s *supervisedDriver) get() (drivers.ResourceDriver, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.restarting {
+		return nil, fmt.Errorf("Fish: Resource driver %s is restarting", s.name)
+	}
+	return s.drv, nil
+}
+
+// recover catches the panic of the driver call, reports it and schedules the driver restart
+func (s *supervisedDriver) recover(call string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Errorf("Fish: Resource driver %s panic in %s: %v\n%s", s.name, call, r, debug.Stack())
+	if err != nil {
+		*err = fmt.Errorf("Fish: Resource driver %s panic in %s: %v", s.name, call, r)
+	}
+
+	s.mutex.Lock()
+	if time.Since(s.lastPanic) > supervisorStablePeriod {
+		s.panics = 0
+	}
+	s.lastPanic = time.Now()
+	delay := util.Backoff(s.panics, time.Second, 5*time.Minute)
+	s.panics++
+	if s.restarting {
+		s.mutex.Unlock()
+		return
+	}
+	s.restarting = true
+	s.mutex.Unlock()
+
+	go s.restartProcess(delay)
+}
+
+// restartProcess recreates the driver instance after delay and retries until it prepares properly
+func (s *supervisedDriver) restartProcess(delay time.Duration) {
+	for attempt := 0; ; attempt++ {
+		log.Warnf("Fish: Restarting resource driver %s in %s", s.name, delay)
+		time.Sleep(delay)
+		err := s.restart()
+		if err == nil {
+			return
+		}
+		log.Error("Fish: Unable to restart resource driver:", s.name, err)
+		delay = util.Backoff(attempt+1, time.Second, 5*time.Minute)
+	}
+}
+
+// restart creates new driver instance and prepares it with the stored config
+func (s *supervisedDriver) restart() (err error) {
+	drv := s.factory.NewResourceDriver()
+	s.mutex.RLock()
+	config := s.config
+	s.mutex.RUnlock()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("Fish: Resource driver %s panic in Prepare: %v\n%s", s.name, r, debug.Stack())
+				err = fmt.Errorf("Fish: Resource driver %s panic in Prepare: %v", s.name, r)
+			}
+		}()
+		err = drv.Prepare(config)
+	}()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.drv = drv
+	s.restarting = false
+	s.mutex.Unlock()
+	log.Info("Fish: Resource driver restarted:", s.name)
+	return nil
+}
+
+// reconfigure restarts the driver with the new config if it was changed
+func (s *supervisedDriver) reconfigure(config []byte) error {
+	s.mutex.Lock()
+	if bytes.Equal(s.config, config) {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.config = config
+	s.mutex.Unlock()
+
+	log.Info("Fish: Resource driver config was changed, restarting:", s.name)
+	return s.restart()
+}
+
+// Name of the driver
+func (s *supervisedDriver) Name() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.drv.Name()
+}
+
+// IsRemote returns if the driver uses remote resources
+func (s *supervisedDriver) IsRemote() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.drv.IsRemote()
+}
+
+// Capabilities of the driver, they are static so the current instance is ok even when restarting
+func (s *supervisedDriver) Capabilities() types.DriverCapabilities {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.drv.Capabilities()
+}
+
+// Prepare stores the config to use during restart and prepares the driver
+func (s *supervisedDriver) Prepare(config []byte) (err error) {
+	s.mutex.Lock()
+	s.config = config
+	drv := s.drv
+	s.mutex.Unlock()
+
+	defer s.recover("Prepare", &err)
+	return drv.Prepare(config)
+}
+
+// ValidateDefinition checks the definition with the driver
+func (s *supervisedDriver) ValidateDefinition(def types.LabelDefinition) (err error) {
+	drv, err := s.get()
+	if err != nil {
+		return err
+	}
+	defer s.recover("ValidateDefinition", &err)
+	return drv.ValidateDefinition(def)
+}
+
+// AvailableCapacity returns -1 if the driver is restarting or panicked
+func (s *supervisedDriver) AvailableCapacity(nodeUsage types.Resources, req types.LabelDefinition) (capacity int64) {
+	drv, err := s.get()
+	if err != nil {
+		return -1
+	}
+	capacity = -1
+	defer s.recover("AvailableCapacity", nil)
+	return drv.AvailableCapacity(nodeUsage, req)
+}
+
+// Allocate the resource with the driver
+func (s *supervisedDriver) Allocate(def types.LabelDefinition, metadata map[string]any) (res *types.Resource, err error) {
+	defer metricDriverCall(s.name, "allocate", time.Now(), &err)
+	drv, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	defer s.recover("Allocate", &err)
+	return drv.Allocate(def, metadata)
+}
+
+// InfoSchema of the driver info, empty if the driver is restarting
+func (s *supervisedDriver) InfoSchema() (schema types.DriverInfoSchema) {
+	drv, err := s.get()
+	if err != nil {
+		return nil
+	}
+	defer s.recover("InfoSchema", nil)
+	return drv.InfoSchema()
+}
+
+// Status of the resource from the driver
+func (s *supervisedDriver) Status(res *types.Resource) (status string, err error) {
+	drv, err := s.get()
+	if err != nil {
+		return "", err
+	}
+	defer s.recover("Status", &err)
+	return drv.Status(res)
+}
+
+// GetTask returns the driver task wrapped to isolate its panics too
+func (s *supervisedDriver) GetTask(task, options string) (t drivers.ResourceDriverTask) {
+	drv, err := s.get()
+	if err != nil {
+		log.Error("Fish: Unable to get task:", task, err)
+		return nil
+	}
+	defer s.recover("GetTask", nil)
+	if t = drv.GetTask(task, options); t == nil {
+		return nil
+	}
+	return &supervisedTask{ResourceDriverTask: t, driver: s}
+}
+
+// Deallocate the resource with the driver
+func (s *supervisedDriver) Deallocate(res *types.Resource) (err error) {
+	defer metricDriverCall(s.name, "deallocate", time.Now(), &err)
+	drv, err := s.get()
+	if err != nil {
+		return err
+	}
+	defer s.recover("Deallocate", &err)
+	return drv.Deallocate(res)
+}
+
+// HealthCheck runs the health check script if the driver supports it
+func (s *supervisedDriver) HealthCheck(res *types.Resource, script string, timeout time.Duration) (err error) {
+	drv, err := s.get()
+	if err != nil {
+		return err
+	}
+	hc, ok := drv.(drivers.ResourceDriverHealthCheck)
+	if !ok {
+		return fmt.Errorf("Fish: Resource driver %s is not able to run the health check script", s.name)
+	}
+	defer s.recover("HealthCheck", &err)
+	return hc.HealthCheck(res, script, timeout)
+}
+
+// SelfCheck reports the restarting driver as failed and runs the driver self check if supported
+func (s *supervisedDriver) SelfCheck() (err error) {
+	drv, err := s.get()
+	if err != nil {
+		return err
+	}
+	sc, ok := drv.(drivers.ResourceDriverSelfCheck)
+	if !ok {
+		return nil
+	}
+	defer s.recover("SelfCheck", &err)
+	return sc.SelfCheck()
+}
+
+// List returns the resources created by the driver, fails if the driver is not able to list them
+func (s *supervisedDriver) List() (list []types.Resource, err error) {
+	drv, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	l, ok := drv.(drivers.ResourceDriverList)
+	if !ok {
+		return nil, fmt.Errorf("Fish: Resource driver %s is not able to list the resources", s.name)
+	}
+	defer s.recover("List", &err)
+	return l.List()
+}
+
+// Forecast returns the driver pools forecast, empty if the driver is not able to forecast
+func (s *supervisedDriver) Forecast() (out types.DriverForecast) {
+	out.Pools = []types.DriverPoolForecast{}
+	drv, err := s.get()
+	if err != nil {
+		return out
+	}
+	f, ok := drv.(drivers.ResourceDriverForecast)
+	if !ok {
+		return out
+	}
+	defer s.recover("Forecast", nil)
+	return f.Forecast()
+}
+
+// CapacityForecast returns zero time if the driver is restarting or not able to forecast
+func (s *supervisedDriver) CapacityForecast(def types.LabelDefinition) (at time.Time) {
+	drv, err := s.get()
+	if err != nil {
+		return at
+	}
+	f, ok := drv.(drivers.ResourceDriverForecast)
+	if !ok {
+		return at
+	}
+	defer s.recover("CapacityForecast", nil)
+	return f.CapacityForecast(def)
+}
+
+// HourlyCost returns error if the driver is restarting or not able to price the definition
+func (s *supervisedDriver) HourlyCost(def types.LabelDefinition) (cost float64, err error) {
+	drv, err := s.get()
+	if err != nil {
+		return 0, err
+	}
+	p, ok := drv.(drivers.ResourceDriverPricing)
+	if !ok {
+		return 0, fmt.Errorf("Fish: Resource driver %s is not able to price the Resources", s.name)
+	}
+	defer s.recover("HourlyCost", &err)
+	return p.HourlyCost(def)
+}
+
+// unwrap returns the current driver instance to check the optional interfaces it implements, the
+// instance could be restarting so it should not be used to execute anything
+func (s *supervisedDriver) unwrap() drivers.ResourceDriver {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.drv
+}
+
+// supervisedTask catches the panics of the driver task execution
+type supervisedTask struct {
+	drivers.ResourceDriverTask
+	driver *supervisedDriver
+}
+
+// Clone the task keeping the supervision
+func (t *supervisedTask) Clone() drivers.ResourceDriverTask {
+	return &supervisedTask{ResourceDriverTask: t.ResourceDriverTask.Clone(), driver: t.driver}
+}
+
+// SetOutput passes the output function to the task if it reports the progress
+func (t *supervisedTask) SetOutput(out func(line string)) {
+	if to, ok := t.ResourceDriverTask.(drivers.ResourceDriverTaskOutput); ok {
+		to.SetOutput(out)
+	}
+}
+
+// Execute runs the task and reports panic to the driver supervisor
+func (t *supervisedTask) Execute() (result []byte, err error) {
+	defer t.driver.recover("Task "+t.Name(), &err)
+	return t.ResourceDriverTask.Execute()
+}
+
+// DriverInfoSchema returns the schema of the Resource info published by the driver instance
+func (*Fish) DriverInfoSchema(name string) (types.DriverInfoSchema, error) {
+	drv, ok := driversInstances[name]
+	if !ok {
+		return nil, fmt.Errorf("Fish: Unable to find active resource driver %q", name)
+	}
+	schema := drv.InfoSchema()
+	if schema == nil {
+		schema = types.DriverInfoSchema{}
+	}
+	return schema, nil
+}
+
+// DriverCapabilities returns the Resources the driver instance could provide
+func (*Fish) DriverCapabilities(name string) (*types.DriverCapabilities, error) {
+	drv, ok := driversInstances[name]
+	if !ok {
+		return nil, fmt.Errorf("Fish: Unable to find active resource driver %q", name)
+	}
+	caps := drv.Capabilities()
+	caps.Tasks = drivers.SupportedTasks(drv)
+	return &caps, nil
+}
+
+// DriverForecast returns the capacity forecast of the driver instance pools
+func (*Fish) DriverForecast(name string) (*types.DriverForecast, error) {
+	drv, ok := driversInstances[name].(*supervisedDriver)
+	if !ok {
+		return nil, fmt.Errorf("Fish: Unable to find active resource driver %q", name)
+	}
+	if _, ok := drv.unwrap().(drivers.ResourceDriverForecast); !ok {
+		return nil, fmt.Errorf("Fish: Resource driver %q is not able to forecast the capacity", name)
+	}
+	out := drv.Forecast()
+	return &out, nil
+}
+
+// DriverRestart recreates the driver instance by name, used by admin to recover the misbehaving driver
+func (*Fish) DriverRestart(name string) error {
+	drv, ok := driversInstances[name].(*supervisedDriver)
+	if !ok {
+		return fmt.Errorf("Fish: Unable to find active resource driver %q", name)
+	}
+	log.Info("Fish: Manual restart of the resource driver requested:", name)
+	return drv.restart()
+}
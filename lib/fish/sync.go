@@ -0,0 +1,345 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Kinds of the synced objects
+const (
+	syncKindLabel            = "label"
+	syncKindUser             = "user"
+	syncKindApplication      = "application"
+	syncKindApplicationState = "application_state"
+	syncKindImage            = "image"
+)
+
+// How many changes to send in one sync batch
+const syncBatchSize = 1000
+
+// syncChange is the log of the local changes to sync them differentially, Seq is growing in the
+// order changes arrive to the node so the late changes of the edge nodes will not be missed
+type syncChange struct {
+	Seq    int64  `gorm:"primaryKey;autoIncrement"`
+	Kind   string `gorm:"index:idx_sync_change_kind_uid"`
+	UID    string `gorm:"index:idx_sync_change_kind_uid"`
+	Origin string // Name of the node the change came from, empty for the local changes
+}
+
+// syncCursor stores the sync progress of the edge node
+type syncCursor struct {
+	Name string `gorm:"primaryKey"`
+	Seq  int64
+}
+
+// Prefix of the central node cursors with the last central change received by the edge node
+const syncCursorEdgePrefix = "edge/"
+
+// syncForeign marks the Applications received by sync, they are executed by the node they were
+// created on
+type syncForeign struct {
+	UID    string `gorm:"primaryKey"`
+	Origin string
+}
+
+// syncEnabled returns true if the node syncs with central or serves the edge nodes sync
+func (f *Fish) syncEnabled() bool {
+	return f.cfg.SyncCentral.Address != "" || f.cfg.SyncEdges
+}
+
+// SyncEdgesEnabled returns true if the node is central one and accepts the edge nodes sync
+func (f *Fish) SyncEdgesEnabled() bool {
+	return f.cfg.SyncEdges
+}
+
+// syncLog records the change of the object to sync it later, does nothing if sync is disabled
+func (f *Fish) syncLog(kind, uid, origin string) {
+	if !f.syncEnabled() {
+		return
+	}
+	if err := f.db.Create(&syncChange{Kind: kind, UID: uid, Origin: origin}).Error; err != nil {
+		log.Errorf("Fish: Unable to log sync change of %s %s: %v", kind, uid, err)
+	}
+}
+
+// SyncCollect returns the changes since the provided sequence number excluding the ones came
+// from the skipOrigin node, until is the sequence number of the last collected change
+func (f *Fish) SyncCollect(since int64, skipOrigin string) (data types.SyncData, until int64, err error) {
+	data = types.SyncData{
+		Labels:            []types.Label{},
+		Users:             []types.User{},
+		Applications:      []types.Application{},
+		ApplicationStates: []types.ApplicationState{},
+		DeletedUsers:      []string{},
+		Images:            []types.Image{},
+	}
+	until = since
+
+	var changes []syncChange
+	if err = f.db.Where("seq > ? AND origin != ?", since, skipOrigin).Order("seq").Limit(syncBatchSize).Find(&changes).Error; err != nil {
+		return data, until, fmt.Errorf("Fish: Unable to get sync changes: %v", err)
+	}
+
+	// The object could be changed a number of times, but only the current state is sent
+	seen := make(map[string]bool)
+	for _, ch := range changes {
+		if seen[ch.Kind+"/"+ch.UID] {
+			until = ch.Seq
+			continue
+		}
+		seen[ch.Kind+"/"+ch.UID] = true
+
+		var err error
+		switch ch.Kind {
+		case syncKindLabel:
+			var label types.Label
+			if err = f.db.First(&label, "uid = ?", ch.UID).Error; err == nil {
+				data.Labels = append(data.Labels, label)
+			}
+		case syncKindUser:
+			var user types.User
+			if err = f.db.First(&user, "name = ?", ch.UID).Error; err == nil {
+				data.Users = append(data.Users, user)
+			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				// The changed User is not here anymore so it was deleted
+				data.DeletedUsers = append(data.DeletedUsers, ch.UID)
+				err = nil
+			}
+		case syncKindApplication:
+			var app types.Application
+			if err = f.db.First(&app, "uid = ?", ch.UID).Error; err == nil {
+				data.Applications = append(data.Applications, app)
+			}
+		case syncKindApplicationState:
+			var state types.ApplicationState
+			if err = f.db.First(&state, "uid = ?", ch.UID).Error; err == nil {
+				data.ApplicationStates = append(data.ApplicationStates, state)
+			}
+		case syncKindImage:
+			var image types.Image
+			if err = f.db.First(&image, "uid = ?", ch.UID).Error; err == nil {
+				data.Images = append(data.Images, image)
+			}
+		}
+		// Stopping on the DB error to not skip the change, it will be collected next time
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Errorf("Fish: Unable to get sync change %s %s: %v", ch.Kind, ch.UID, err)
+			break
+		}
+		until = ch.Seq
+	}
+	return data, until, nil
+}
+
+// SyncApply stores the changes received from the origin node resolving the conflicts:
+// * Labels are immutable - the existing Label with the same name and version wins
+// * Users - the latest update wins, the User delete wins over any update
+// * Applications are created once - the existing one wins, short ID is regenerated on collision
+// * Application states are appended, but the active state can't override the terminal one
+// * Images are immutable like Labels, but the deprecation is delivered
+func (f *Fish) SyncApply(data *types.SyncData, origin string) (conflicts []string) {
+	for i := range data.Labels {
+		l := &data.Labels[i]
+		var existing types.Label
+		err := f.db.First(&existing, "name = ? AND version = ?", l.Name, l.Version).Error
+		if err == nil {
+			if existing.UID != l.UID {
+				conflicts = append(conflicts, fmt.Sprintf("Label %s:%d already exists with UID %s", l.Name, l.Version, existing.UID))
+			}
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			conflicts = append(conflicts, fmt.Sprintf("Label %s: %v", l.UID, err))
+			continue
+		}
+		if err := f.db.Create(l).Error; err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("Label %s: %v", l.UID, err))
+			continue
+		}
+		f.syncLog(syncKindLabel, l.UID.String(), origin)
+	}
+
+	for i := range data.Images {
+		img := &data.Images[i]
+		var existing types.Image
+		err := f.db.First(&existing, "name = ? AND version = ?", img.Name, img.Version).Error
+		if err == nil {
+			if existing.UID != img.UID {
+				conflicts = append(conflicts, fmt.Sprintf("Image %s:%d already exists with UID %s", img.Name, img.Version, existing.UID))
+				continue
+			}
+			if existing.Deprecated || !img.Deprecated {
+				continue
+			}
+			err = f.db.Save(img).Error
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = f.db.Create(img).Error
+		}
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("Image %s: %v", img.UID, err))
+			continue
+		}
+		f.syncLog(syncKindImage, img.UID.String(), origin)
+	}
+
+	for i := range data.Users {
+		u := &data.Users[i]
+		var existing types.User
+		err := f.db.First(&existing, "name = ?", u.Name).Error
+		if err == nil {
+			if !u.UpdatedAt.After(existing.UpdatedAt) {
+				continue
+			}
+			// Keeping the edge UpdatedAt, otherwise the next sync will not be able to compare
+			err = f.db.Model(u).Select("*").UpdateColumns(u).Error
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = f.db.Create(u).Error
+		}
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("User %s: %v", u.Name, err))
+			continue
+		}
+		f.syncLog(syncKindUser, u.Name, origin)
+	}
+
+	for _, name := range data.DeletedUsers {
+		if err := f.userDelete(name, origin); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("User %s delete: %v", name, err))
+		}
+	}
+
+	for i := range data.Applications {
+		a := &data.Applications[i]
+		exists, err := f.syncExists(&types.Application{}, "uid = ?", a.UID)
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("Application %s: %v", a.UID, err))
+			continue
+		}
+		if exists {
+			continue
+		}
+		if exists, err = f.syncExists(&types.Application{}, "short_id = ?", a.ShortId); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("Application %s: %v", a.UID, err))
+			continue
+		}
+		if exists {
+			newID := f.applicationNewShortID()
+			conflicts = append(conflicts, fmt.Sprintf("Application %s short ID %s is taken, changed to %s", a.UID, a.ShortId, newID))
+			a.ShortId = newID
+		}
+		err = f.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(a).Error; err != nil {
+				return err
+			}
+			return tx.Create(&syncForeign{UID: a.UID.String(), Origin: origin}).Error
+		})
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("Application %s: %v", a.UID, err))
+			continue
+		}
+		f.syncLog(syncKindApplication, a.UID.String(), origin)
+	}
+
+	for i := range data.ApplicationStates {
+		as := &data.ApplicationStates[i]
+		exists, err := f.syncExists(&types.ApplicationState{}, "uid = ?", as.UID)
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("ApplicationState %s: %v", as.UID, err))
+			continue
+		}
+		if exists {
+			continue
+		}
+		if exists, err = f.syncExists(&types.Application{}, "uid = ?", as.ApplicationUID); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("ApplicationState %s: %v", as.UID, err))
+			continue
+		}
+		if !exists {
+			conflicts = append(conflicts, fmt.Sprintf("ApplicationState %s: Application %s not found", as.UID, as.ApplicationUID))
+			continue
+		}
+		if current, err := f.ApplicationStateGetByApplication(as.ApplicationUID); err == nil &&
+			!f.ApplicationStateIsActive(current.Status) && f.ApplicationStateIsActive(as.Status) {
+			conflicts = append(conflicts, fmt.Sprintf("ApplicationState %s: Application %s is already %s, ignoring %s",
+				as.UID, as.ApplicationUID, current.Status, as.Status))
+			continue
+		}
+		if err := f.applicationStateStore(as); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("ApplicationState %s: %v", as.UID, err))
+			continue
+		}
+		f.syncLog(syncKindApplicationState, as.UID.String(), origin)
+	}
+
+	for _, c := range conflicts {
+		log.Warnf("Fish: Sync conflict with %s: %s", origin, c)
+	}
+	return conflicts
+}
+
+// syncExists checks if the object matching the query is stored in DB
+func (f *Fish) syncExists(model any, query string, args ...any) (bool, error) {
+	var count int64
+	err := f.db.Model(model).Where(query, args...).Count(&count).Error
+	return count > 0, err
+}
+
+// SyncAck stores the last central change received by the edge node and prunes the sync log
+func (f *Fish) SyncAck(nodeName string, seq int64) error {
+	if err := f.syncCursorSet(syncCursorEdgePrefix+nodeName, seq); err != nil {
+		return fmt.Errorf("Fish: Unable to store sync cursor of %s: %v", nodeName, err)
+	}
+	return f.syncPrune()
+}
+
+// syncPrune removes the sync log changes received by every node syncing with this one: central
+// by the local cursor of the edge node and the edge nodes by their cursors on the central node,
+// so the edge node which stopped to sync keeps the central log until its cursor is removed
+func (f *Fish) syncPrune() error {
+	var cursors []syncCursor
+	if err := f.db.Where("name = ? OR name LIKE ?", syncCursorLocal, syncCursorEdgePrefix+"%").Find(&cursors).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to get sync cursors: %v", err)
+	}
+	if len(cursors) == 0 {
+		return nil
+	}
+	acked := cursors[0].Seq
+	for _, c := range cursors[1:] {
+		acked = min(acked, c.Seq)
+	}
+	if err := f.db.Where("seq <= ?", acked).Delete(&syncChange{}).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to prune sync log: %v", err)
+	}
+	return nil
+}
+
+// syncCursorGet returns the stored sync progress
+func (f *Fish) syncCursorGet(name string) int64 {
+	var cursor syncCursor
+	if f.db.First(&cursor, "name = ?", name).Error != nil {
+		return 0
+	}
+	return cursor.Seq
+}
+
+// syncCursorSet stores the sync progress
+func (f *Fish) syncCursorSet(name string, seq int64) error {
+	return f.db.Save(&syncCursor{Name: name, Seq: seq}).Error
+}
@@ -0,0 +1,165 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Names of the edge node sync cursors
+const (
+	syncCursorLocal   = "local"   // Last local change pushed to central
+	syncCursorCentral = "central" // Last central change received
+)
+
+// Origin of the changes received from central
+const syncOriginCentral = "central"
+
+// syncEdgeProcess periodically syncs the edge node with the central cluster, if the central is
+// not reachable the local changes are just waiting in the sync log for the next try
+func (f *Fish) syncEdgeProcess() {
+	client, err := f.syncEdgeClient()
+	if err != nil {
+		log.Error("Fish: Unable to init sync with central, sync disabled:", err)
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(f.cfg.SyncCentral.Interval))
+	defer ticker.Stop()
+	online := true
+	for {
+		// Pushing the changes in batches until everything is synced
+		for {
+			more, err := f.syncEdge(client)
+			if err != nil {
+				if online {
+					log.Warn("Fish: Central is not reachable, working offline:", err)
+				}
+				online = false
+				break
+			}
+			if !online {
+				log.Info("Fish: Central is reachable again, synced the offline changes")
+			}
+			online = true
+			if !more {
+				break
+			}
+		}
+
+		<-ticker.C
+		if !f.running {
+			return
+		}
+	}
+}
+
+// syncEdgeClient prepares the http client to connect to central
+func (f *Fish) syncEdgeClient() (*http.Client, error) {
+	caPath := f.cfg.SyncCentral.CACert
+	if caPath == "" {
+		caPath = f.cfg.TLSCaCrt
+	}
+	if !filepath.IsAbs(caPath) {
+		caPath = filepath.Join(f.cfg.Directory, caPath)
+	}
+	caPem, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to read central CA certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf("Fish: Unable to parse central CA certificate %q", caPath)
+	}
+
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}, nil
+}
+
+// syncEdge makes one sync round and returns true if there are more changes to sync
+func (f *Fish) syncEdge(client *http.Client) (more bool, err error) {
+	localSeq := f.syncCursorGet(syncCursorLocal)
+	centralSeq := f.syncCursorGet(syncCursorCentral)
+
+	// Pushing only the local changes, the central ones are already there
+	changes, pushedSeq, err := f.SyncCollect(localSeq, syncOriginCentral)
+	if err != nil {
+		return false, err
+	}
+
+	body, err := json.Marshal(types.SyncRequest{
+		NodeName: f.node.Name,
+		Since:    centralSeq,
+		Changes:  changes,
+	})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(f.cfg.SyncCentral.Address, "/")+"/api/v1/sync/", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(f.cfg.SyncCentral.Username, f.cfg.SyncCentral.Password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("Fish: Central responded with status %d: %s", resp.StatusCode, data)
+	}
+
+	var out types.SyncResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, fmt.Errorf("Fish: Unable to parse central sync response: %v", err)
+	}
+	for _, c := range out.Conflicts {
+		log.Warn("Fish: Central rejected the local change:", c)
+	}
+
+	f.SyncApply(&out.Changes, syncOriginCentral)
+
+	if err := f.syncCursorSet(syncCursorLocal, pushedSeq); err != nil {
+		return false, err
+	}
+	if err := f.syncCursorSet(syncCursorCentral, out.Until); err != nil {
+		return false, err
+	}
+	if err := f.syncPrune(); err != nil {
+		log.Warn("Fish: Unable to prune the synced changes:", err)
+	}
+
+	log.Debugf("Fish: Synced with central: pushed till %d, received till %d", pushedSeq, out.Until)
+
+	return pushedSeq > localSeq || out.Until > centralSeq, nil
+}
@@ -0,0 +1,164 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/adobe/aquarium-fish/lib/drivers"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// NodeTelemetryGet collects the current usage of this node host and checks it against the
+// configured thresholds
+func (f *Fish) NodeTelemetryGet() *types.NodeTelemetry {
+	cfg := f.cfg.Telemetry
+	out := &types.NodeTelemetry{
+		UpdatedAt: time.Now(),
+		Status:    types.NodeTelemetryStatusOK,
+		Drivers:   []types.DriverTelemetry{},
+		Warnings:  []string{},
+	}
+	warn := func(format string, args ...any) {
+		out.Warnings = append(out.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	if percents, err := cpu.Percent(time.Second, false); err != nil || len(percents) < 1 {
+		warn("Unable to get CPU usage: %v", err)
+	} else {
+		out.CpuUsage = float32(percents[0])
+	}
+	if vm, err := mem.VirtualMemory(); err != nil {
+		warn("Unable to get RAM usage: %v", err)
+	} else {
+		out.RamUsage = float32(vm.UsedPercent)
+	}
+	if du, err := disk.Usage(f.cfg.Directory); err != nil {
+		warn("Unable to get disk usage: %v", err)
+	} else {
+		out.DiskUsage = float32(du.UsedPercent)
+	}
+	if cfg.ClockReference != "" {
+		if skew, err := telemetryClockSkew(cfg.ClockReference); err != nil {
+			warn("Unable to get clock skew: %v", err)
+		} else {
+			out.ClockSkew = util.Duration(skew)
+		}
+	}
+
+	if cfg.CPU > 0 && out.CpuUsage >= cfg.CPU {
+		warn("CPU usage %.1f%% is over %.1f%%", out.CpuUsage, cfg.CPU)
+	}
+	if cfg.RAM > 0 && out.RamUsage >= cfg.RAM {
+		warn("RAM usage %.1f%% is over %.1f%%", out.RamUsage, cfg.RAM)
+	}
+	if cfg.Disk > 0 && out.DiskUsage >= cfg.Disk {
+		warn("Disk usage %.1f%% is over %.1f%%", out.DiskUsage, cfg.Disk)
+	}
+	skew := time.Duration(out.ClockSkew)
+	if skew < 0 {
+		skew = -skew
+	}
+	if cfg.ClockSkew > 0 && skew >= time.Duration(cfg.ClockSkew) {
+		warn("Clock skew %v is over %v", time.Duration(out.ClockSkew), time.Duration(cfg.ClockSkew))
+	}
+
+	var names []string
+	for name := range driversInstances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dt := types.DriverTelemetry{Name: name, Healthy: true}
+		if drv, ok := driversInstances[name].(drivers.ResourceDriverSelfCheck); ok {
+			if err := drv.SelfCheck(); err != nil {
+				msg := err.Error()
+				dt.Healthy = false
+				dt.Error = &msg
+				warn("Driver %s self check failed: %v", name, err)
+			}
+		}
+		out.Drivers = append(out.Drivers, dt)
+	}
+
+	if len(out.Warnings) > 0 {
+		out.Status = types.NodeTelemetryStatusWARNING
+	}
+
+	return out
+}
+
+// telemetryClockSkew compares the local clock with the Date header of the reference URL, the
+// header has seconds precision so the request time is used to not count the network delay
+func telemetryClockSkew(url string) (time.Duration, error) {
+	cli := &http.Client{Timeout: 5 * time.Second}
+	started := time.Now()
+	resp, err := cli.Head(url)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse Date header: %v", err)
+	}
+	local := started.Add(time.Since(started) / 2)
+	return local.Sub(date).Truncate(time.Second), nil
+}
+
+// telemetryProcess publishes the node host usage and notifies the operators when it's over the
+// thresholds
+func (f *Fish) telemetryProcess() {
+	ticker := time.NewTicker(time.Duration(f.cfg.Telemetry.Interval))
+	defer ticker.Stop()
+	status := types.NodeTelemetryStatusOK
+	for {
+		if !f.running {
+			break
+		}
+		telemetry := f.NodeTelemetryGet()
+		f.node.Telemetry = telemetry
+		if err := f.db.Model(f.node).Update("telemetry", telemetry).Error; err != nil {
+			log.Error("Fish: Unable to publish the node telemetry:", err)
+		}
+		if telemetry.Status != status {
+			status = telemetry.Status
+			if status == types.NodeTelemetryStatusWARNING {
+				text := strings.Join(telemetry.Warnings, "\n")
+				log.Warn("Fish: Node telemetry is over the thresholds:", text)
+				f.nodeHistoryEvent(HistoryNodeWarning, text)
+				f.notify("", &notify.Notification{
+					Event:   notify.EventNodeWarning,
+					Subject: fmt.Sprintf("Aquarium: Node %s needs attention", f.node.Name),
+					Text:    text,
+					Data:    map[string]any{"node_name": f.node.Name, "node_uid": f.node.UID},
+				})
+			} else {
+				log.Info("Fish: Node telemetry is back to normal")
+				f.nodeHistoryEvent(HistoryNodeRecovered, "")
+			}
+		}
+		<-ticker.C
+	}
+}
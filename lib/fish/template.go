@@ -0,0 +1,128 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// TemplateFind returns list of Application templates that fits filter
+func (f *Fish) TemplateFind(filter *string) (ts []types.Template, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return ts, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Order("name").Find(&ts).Error
+	return ts, err
+}
+
+// TemplateGet returns Application template by name
+func (f *Fish) TemplateGet(name string) (t *types.Template, err error) {
+	t = &types.Template{}
+	err = f.db.Where("name = ?", name).First(t).Error
+	return t, err
+}
+
+// TemplateSave creates or updates the Application template
+func (f *Fish) TemplateSave(t *types.Template) error {
+	if t.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if t.Priority < 0 {
+		return fmt.Errorf("Fish: Priority can't be negative")
+	}
+	if t.Metadata == "" {
+		t.Metadata = "{}"
+	}
+	if err := f.limitMetadata(t.Metadata); err != nil {
+		return err
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(t.Metadata), &metadata); err != nil {
+		return fmt.Errorf("Fish: Metadata should be a json object: %v", err)
+	}
+	if _, err := f.templateLabel(t); err != nil {
+		return err
+	}
+	return f.db.Save(t).Error
+}
+
+// TemplateDelete removes the Application template by name
+func (f *Fish) TemplateDelete(name string) error {
+	return f.db.Where("name = ?", name).Delete(&types.Template{}).Error
+}
+
+// templateLabel returns the Label the template is pointing to
+func (f *Fish) templateLabel(t *types.Template) (*types.Label, error) {
+	if t.LabelVersion == 0 {
+		label, err := f.LabelGetLatest(t.LabelName)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to find Label %q: %v", t.LabelName, err)
+		}
+		return label, nil
+	}
+	label := &types.Label{}
+	if err := f.db.Where("name = ? AND version = ?", t.LabelName, t.LabelVersion).First(label).Error; err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find Label %q version %d: %v", t.LabelName, t.LabelVersion, err)
+	}
+	return label, nil
+}
+
+// templateApply fills the Application with the template defaults
+func (f *Fish) templateApply(a *types.Application) error {
+	t, err := f.TemplateGet(a.Template)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find template %q: %v", a.Template, err)
+	}
+	if a.LabelUID == uuid.Nil {
+		label, err := f.templateLabel(t)
+		if err != nil {
+			return err
+		}
+		a.LabelUID = label.UID
+	}
+	if a.Priority == 0 {
+		a.Priority = t.Priority
+	}
+
+	// The Application metadata overrides the template defaults
+	var metadata, override map[string]any
+	if err := json.Unmarshal([]byte(t.Metadata), &metadata); err != nil || metadata == nil {
+		metadata = make(map[string]any)
+	}
+	if err := json.Unmarshal([]byte(a.Metadata), &override); err != nil {
+		return fmt.Errorf("Fish: Metadata should be a json object: %v", err)
+	}
+	for k, v := range override {
+		metadata[k] = v
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to encode the Application metadata: %v", err)
+	}
+	a.Metadata = util.UnparsedJSON(data)
+	return nil
+}
@@ -0,0 +1,50 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"context"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/tracing"
+)
+
+// appTrace starts the span in the Application trace - the trace ID is the Application UID, so
+// the spans of all the cluster nodes processing the Application are joined in one trace
+func (f *Fish) appTrace(appUID types.ApplicationUID, name string, attrs ...tracing.Attribute) *tracing.Span {
+	if !tracing.Enabled() {
+		return nil
+	}
+	attrs = append(attrs, tracing.Attr("application.uid", appUID.String()))
+	if f.node != nil {
+		attrs = append(attrs, tracing.Attr("fish.node", f.node.Name))
+	}
+	_, span := tracing.StartTrace(context.Background(), tracing.TraceID(appUID), name, attrs...)
+	return span
+}
+
+// appTraceRoot starts the root span of the Application trace, the span of ctx (like the API
+// request one) is linked to it so the request could be found from the Application trace
+func (f *Fish) appTraceRoot(ctx context.Context, appUID types.ApplicationUID, name string) *tracing.Span {
+	if !tracing.Enabled() {
+		return nil
+	}
+	// Marking the request span to find the Application trace from it too
+	tracing.SpanFromContext(ctx).SetAttributes(tracing.Attr("application.uid", appUID.String()))
+	attrs := []tracing.Attribute{tracing.Attr("application.uid", appUID.String())}
+	if f.node != nil {
+		attrs = append(attrs, tracing.Attr("fish.node", f.node.Name))
+	}
+	_, span := tracing.StartTraceRoot(ctx, tracing.TraceID(appUID), name, attrs...)
+	return span
+}
@@ -0,0 +1,371 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Statuses of the rolling Upgrade
+const (
+	UpgradeStatusRunning   = "RUNNING"
+	UpgradeStatusPaused    = "PAUSED"
+	UpgradeStatusFailed    = "FAILED"
+	UpgradeStatusAborted   = "ABORTED"
+	UpgradeStatusCompleted = "COMPLETED"
+)
+
+// Default timeouts of the Upgrade step
+const (
+	upgradeDrainTimeout  = time.Hour
+	upgradeHealthTimeout = 10 * time.Minute
+)
+
+// upgradeFailureRateMinApps is how many Applications should be allocated on the upgraded Nodes to
+// check the failure rate, otherwise one failed Application will stop the Upgrade
+const upgradeFailureRateMinApps = 5
+
+// UpgradeFind returns list of Upgrades that fits the filter
+func (f *Fish) UpgradeFind(filter *string) (us []types.Upgrade, err error) {
+	db := f.db
+	if filter != nil {
+		securedFilter, err := util.ExpressionSQLFilter(*filter)
+		if err != nil {
+			log.Warn("Fish: SECURITY: weird SQL filter received:", err)
+			// We do not fail here because we should not give attacker more information
+			return us, nil
+		}
+		db = db.Where(securedFilter)
+	}
+	err = db.Find(&us).Error
+	return us, err
+}
+
+// UpgradeGet returns Upgrade by UID
+func (f *Fish) UpgradeGet(uid types.UpgradeUID) (u *types.Upgrade, err error) {
+	u = &types.Upgrade{}
+	err = f.db.First(u, uid).Error
+	return u, err
+}
+
+// UpgradeCreate validates and starts the new rolling Upgrade, only one could run at a time
+func (f *Fish) UpgradeCreate(u *types.Upgrade) error {
+	if u.Version == "" {
+		return fmt.Errorf("Fish: Version can't be empty")
+	}
+	if u.DrainTimeout < 0 || u.HealthTimeout < 0 {
+		return fmt.Errorf("Fish: Timeouts can't be negative")
+	}
+	if u.Parallel < 0 {
+		return fmt.Errorf("Fish: Parallel can't be negative")
+	}
+	if u.MaxFailureRate < 0 || u.MaxFailureRate > 1 {
+		return fmt.Errorf("Fish: Max failure rate should be in range 0-1: %v", u.MaxFailureRate)
+	}
+	if u.Parallel == 0 {
+		u.Parallel = 1
+	}
+	if u.DrainTimeout == 0 {
+		u.DrainTimeout = util.Duration(upgradeDrainTimeout)
+	}
+	if u.HealthTimeout == 0 {
+		u.HealthTimeout = util.Duration(upgradeHealthTimeout)
+	}
+
+	var active int64
+	if err := f.db.Model(&types.Upgrade{}).Where("status IN ?", []string{UpgradeStatusRunning, UpgradeStatusPaused, UpgradeStatusFailed}).Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return fmt.Errorf("Fish: Another Upgrade is in progress, complete or abort it first")
+	}
+
+	if len(u.Nodes) == 0 {
+		nodes, err := f.NodeActiveList()
+		if err != nil {
+			return fmt.Errorf("Fish: Unable to list the active Nodes: %v", err)
+		}
+		for _, n := range nodes {
+			u.Nodes = append(u.Nodes, n.Name)
+		}
+		sort.Strings(u.Nodes)
+	}
+	seen := make(map[string]bool, len(u.Nodes))
+	for _, name := range u.Nodes {
+		if seen[name] {
+			return fmt.Errorf("Fish: Node %q is listed twice", name)
+		}
+		seen[name] = true
+		if _, err := f.NodeGet(name); err != nil {
+			return fmt.Errorf("Fish: Unable to find Node %q: %v", name, err)
+		}
+	}
+
+	u.UID = f.NewUID()
+	u.Status = UpgradeStatusRunning
+	u.Current = 0
+	u.Steps = types.UpgradeSteps{}
+	u.ResumedAt = time.Time{}
+	u.Error = ""
+	return f.db.Create(u).Error
+}
+
+// UpgradePause stops the Upgrade from proceeding to the next Node
+func (f *Fish) UpgradePause(u *types.Upgrade) error {
+	if u.Status != UpgradeStatusRunning {
+		return fmt.Errorf("Fish: Unable to pause the Upgrade with status %s", u.Status)
+	}
+	return f.upgradeUpdate(u, u.Status, map[string]any{"status": UpgradeStatusPaused})
+}
+
+// UpgradeResume continues the paused or failed Upgrade, the current Nodes steps are started over
+// and the failure rate is counted from now
+func (f *Fish) UpgradeResume(u *types.Upgrade) error {
+	if u.Status != UpgradeStatusPaused && u.Status != UpgradeStatusFailed {
+		return fmt.Errorf("Fish: Unable to resume the Upgrade with status %s", u.Status)
+	}
+	return f.upgradeUpdate(u, u.Status, map[string]any{
+		"status":     UpgradeStatusRunning,
+		"steps":      types.UpgradeSteps{},
+		"resumed_at": time.Now(),
+		"error":      "",
+	})
+}
+
+// UpgradeAbort stops the Upgrade and releases the current Nodes if they are not restarted yet
+func (f *Fish) UpgradeAbort(u *types.Upgrade) error {
+	if u.Status == UpgradeStatusAborted || u.Status == UpgradeStatusCompleted {
+		return fmt.Errorf("Fish: Unable to abort the Upgrade with status %s", u.Status)
+	}
+	if err := f.upgradeUpdate(u, u.Status, map[string]any{"status": UpgradeStatusAborted}); err != nil {
+		return err
+	}
+	return f.upgradeRelease(u)
+}
+
+// upgradeRelease returns the Nodes which were drained by the Upgrade, but not restarted yet, back
+// to service
+func (f *Fish) upgradeRelease(u *types.Upgrade) error {
+	names := make([]string, 0, len(u.Steps))
+	for name := range u.Steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		node, err := f.NodeGet(name)
+		if err != nil || !node.Shutdown {
+			continue
+		}
+		log.Infof("Fish: Upgrade %s: releasing Node %s from maintenance", u.UID, node.Name)
+		if _, err := f.NodeMaintenanceRequest(node.UID, false, false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeUpdate changes the Upgrade fields if it still has the expected status, so the nodes
+// processing the Upgrade concurrently will not step over each other
+func (f *Fish) upgradeUpdate(u *types.Upgrade, status string, values map[string]any) error {
+	res := f.db.Model(&types.Upgrade{}).Where("uid = ? AND status = ? AND current = ?", u.UID, status, u.Current).Updates(values)
+	if res.Error != nil {
+		return fmt.Errorf("Fish: Unable to update Upgrade %s: %v", u.UID, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("Fish: Upgrade %s was changed concurrently", u.UID)
+	}
+	updated, err := f.UpgradeGet(u.UID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to get Upgrade %s: %v", u.UID, err)
+	}
+	*u = *updated
+	return nil
+}
+
+// upgradeProcess drives the running Upgrades, every node is doing that so the Upgrade proceeds
+// even when the node which created it is restarting
+func (f *Fish) upgradeProcess() {
+	ticker := time.NewTicker(types.NodePingDelay * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !f.running {
+			return
+		}
+		var upgrades []types.Upgrade
+		if err := f.db.Where("status = ?", UpgradeStatusRunning).Find(&upgrades).Error; err != nil {
+			log.Error("Fish: Unable to find Upgrades:", err)
+			continue
+		}
+		for i := range upgrades {
+			if err := f.upgradeStep(&upgrades[i], time.Now()); err != nil {
+				log.Debugf("Fish: Upgrade %s: %v", upgrades[i].UID, err)
+			}
+		}
+	}
+}
+
+// upgradeStep moves the running Upgrade forward: the Nodes which are back with the new version are
+// completed and the next ones are drained, up to the parallel limit at the same time
+func (f *Fish) upgradeStep(u *types.Upgrade, now time.Time) error {
+	if reason := f.upgradeFailureRate(u); reason != "" {
+		if err := f.upgradeFail(u, reason); err != nil {
+			return err
+		}
+		return f.upgradeRelease(u)
+	}
+
+	current := u.Current
+	steps := make(types.UpgradeSteps, len(u.Steps))
+	for name, started := range u.Steps {
+		steps[name] = started
+	}
+	var drain []*types.Node
+	for i := u.Current; i < len(u.Nodes) && i < u.Current+max(u.Parallel, 1); i++ {
+		name := u.Nodes[i]
+		node, err := f.NodeGet(name)
+		if err != nil {
+			return f.upgradeFail(u, fmt.Sprintf("Unable to find Node %q: %v", name, err))
+		}
+
+		// The Node is up with the new version and not going down
+		healthy := node.Version == u.Version && !node.Maintenance && !node.Shutdown &&
+			now.Sub(node.UpdatedAt) < types.NodePingDelay*2*time.Second
+
+		if healthy {
+			if _, ok := steps[name]; ok || i == current {
+				log.Infof("Fish: Upgrade %s: Node %s is running version %s", u.UID, name, node.Version)
+			}
+			delete(steps, name)
+			// The Nodes are completed in order, so the next step will not wait for them again
+			if i == current {
+				current++
+			}
+			continue
+		}
+
+		started, ok := steps[name]
+		if !ok {
+			steps[name] = now
+			drain = append(drain, node)
+			continue
+		}
+		deadline := started.Add(time.Duration(u.DrainTimeout) + time.Duration(u.HealthTimeout))
+		if now.After(deadline) {
+			return f.upgradeFail(u, fmt.Sprintf("Node %q is not running version %s after %s, it reports %q",
+				name, u.Version, deadline.Sub(started), node.Version))
+		}
+	}
+
+	if current >= len(u.Nodes) {
+		log.Infof("Fish: Upgrade %s: completed", u.UID)
+		return f.upgradeUpdate(u, UpgradeStatusRunning, map[string]any{
+			"status":  UpgradeStatusCompleted,
+			"current": current,
+			"steps":   types.UpgradeSteps{},
+		})
+	}
+	if current == u.Current && len(steps) == len(u.Steps) && len(drain) == 0 {
+		return nil
+	}
+	if err := f.upgradeUpdate(u, UpgradeStatusRunning, map[string]any{
+		"current": current,
+		"steps":   steps,
+	}); err != nil {
+		return err
+	}
+
+	for _, node := range drain {
+		log.Infof("Fish: Upgrade %s: draining Node %s", u.UID, node.Name)
+		if _, err := f.NodeMaintenanceRequest(node.UID, true, true, time.Duration(u.DrainTimeout)); err != nil {
+			return f.upgradeFail(u, err.Error())
+		}
+		if err := f.nodeShutdownRequest(node.UID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeFailureRate returns the reason to stop the Upgrade if too many Applications failed on the
+// upgraded Nodes since the Upgrade start or resume, empty string if it could continue
+func (f *Fish) upgradeFailureRate(u *types.Upgrade) string {
+	if u.MaxFailureRate <= 0 || u.Current == 0 {
+		return ""
+	}
+	since := u.CreatedAt
+	if u.ResumedAt.After(since) {
+		since = u.ResumedAt
+	}
+	elected := make([]string, 0, u.Current)
+	for _, name := range u.Nodes[:u.Current] {
+		elected = append(elected, applicationElectedPrefix+name)
+	}
+	apps := func() *gorm.DB {
+		return f.db.Model(&types.ApplicationState{}).Select("application_uid").
+			Where("status = ? AND description IN ? AND created_at >= ?", types.ApplicationStatusELECTED, elected, since)
+	}
+
+	var total, failed int64
+	if err := apps().Count(&total).Error; err != nil {
+		log.Errorf("Fish: Upgrade %s: unable to count the Applications on the upgraded Nodes: %v", u.UID, err)
+		return ""
+	}
+	if total < upgradeFailureRateMinApps {
+		return ""
+	}
+	err := f.db.Model(&types.ApplicationState{}).Distinct("application_uid").
+		Where("status = ? AND application_uid IN (?)", types.ApplicationStatusERROR, apps()).Count(&failed).Error
+	if err != nil {
+		log.Errorf("Fish: Upgrade %s: unable to count the failed Applications on the upgraded Nodes: %v", u.UID, err)
+		return ""
+	}
+	if rate := float64(failed) / float64(total); rate > u.MaxFailureRate {
+		return fmt.Sprintf("%d of %d Applications failed on the upgraded Nodes, rate %.2f is over %.2f",
+			failed, total, rate, u.MaxFailureRate)
+	}
+	return ""
+}
+
+// upgradeFail stops the Upgrade until the operator will resume or abort it
+func (f *Fish) upgradeFail(u *types.Upgrade, reason string) error {
+	log.Errorf("Fish: Upgrade %s failed: %s", u.UID, reason)
+	return f.upgradeUpdate(u, UpgradeStatusRunning, map[string]any{
+		"status": UpgradeStatusFailed,
+		"error":  reason,
+	})
+}
+
+// nodeShutdownRequest asks the Node to shutdown when the Applications are completed, the
+// service manager is expected to start it again with the new binary
+func (f *Fish) nodeShutdownRequest(uid types.NodeUID) error {
+	node, err := f.NodeGetUID(uid)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find Node %s: %v", uid, err)
+	}
+	node.Shutdown = true
+	if err = f.nodeMaintenanceSave(node); err != nil {
+		return err
+	}
+	if node.UID == f.node.UID {
+		f.nodeMaintenanceSync()
+	}
+	return nil
+}
@@ -21,9 +21,9 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// UserFind returns list of users that fits the filter
-func (f *Fish) UserFind(filter *string) (us []types.User, err error) {
-	db := f.db
+// UserFind returns list of users that fits the filter and page, report uses the replica
+func (f *Fish) UserFind(filter *string, page *ListPage, report bool) (us []types.User, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -33,8 +33,13 @@ func (f *Fish) UserFind(filter *string) (us []types.User, err error) {
 		}
 		db = db.Where(securedFilter)
 	}
-	err = db.Find(&us).Error
-	return us, err
+	if db, err = page.query(db, []string{"name"}, "name"); err != nil {
+		return us, err
+	}
+	if err = db.Find(&us).Error; err != nil {
+		return us, err
+	}
+	return cutPage(page, us)
 }
 
 // UserCreate makes new User
@@ -46,12 +51,20 @@ func (f *Fish) UserCreate(u *types.User) error {
 		return fmt.Errorf("Fish: Hash can't be empty")
 	}
 
-	return f.db.Create(u).Error
+	if err := f.db.Create(u).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindUser, u.Name, "")
+	return nil
 }
 
 // UserSave stores User
 func (f *Fish) UserSave(u *types.User) error {
-	return f.db.Save(u).Error
+	if err := f.db.Save(u).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindUser, u.Name, "")
+	return nil
 }
 
 // UserGet returns User by unique name
@@ -102,5 +115,33 @@ func (f *Fish) UserNew(name string, password string) (string, *types.User, error
 
 // UserDelete removes User
 func (f *Fish) UserDelete(name string) error {
-	return f.db.Where("name = ?", name).Delete(&types.User{}).Error
+	return f.userDelete(name, "")
+}
+
+// userDelete removes the User and logs the change to sync it, the User grants are revoked so the
+// User created later with the same name will not get them, same for the API tokens
+func (f *Fish) userDelete(name, origin string) error {
+	if err := f.roleGrantRevokeUser(name, RoleGrantRevoker); err != nil {
+		return err
+	}
+	if err := f.userTokenRevokeUser(name, RoleGrantRevoker); err != nil {
+		return err
+	}
+	if err := f.QuotaDelete(name); err != nil {
+		return err
+	}
+	if err := f.UserOTPReset(name); err != nil {
+		return err
+	}
+	if err := f.preferenceDeleteUser(name); err != nil {
+		return err
+	}
+	if err := f.subscriptionDeleteUser(name); err != nil {
+		return err
+	}
+	if err := f.db.Where("name = ?", name).Delete(&types.User{}).Error; err != nil {
+		return err
+	}
+	f.syncLog(syncKindUser, name, origin)
+	return nil
 }
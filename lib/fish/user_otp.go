@@ -0,0 +1,126 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// UserOTPIssuer is shown in the authenticator app for the enrolled secrets
+const UserOTPIssuer = "Aquarium Fish"
+
+// userOTP keeps the User TOTP secret for the second factor on the sensitive Resources access.
+// It's not a part of User object to never expose the secret in the User API and sync.
+type userOTP struct {
+	UserName    string `gorm:"primaryKey"`
+	Secret      otpSecret
+	LastCounter int64 // Used code period to deny the code reuse
+	CreatedAt   time.Time
+}
+
+// otpSecret is stored in database encrypted by the node master key
+type otpSecret string
+
+// Scan decrypts the secret stored in database
+func (s *otpSecret) Scan(value any) error {
+	var stored string
+	switch v := value.(type) {
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("Fish: Failed to scan OTP secret: %T", value)
+	}
+	plain, err := crypt.EnvelopeDecrypt(stored)
+	if err != nil {
+		return err
+	}
+	*s = otpSecret(plain)
+	return nil
+}
+
+// Value encrypts the secret to store it in database, the plaintext stays if the node master key
+// is not set
+func (s otpSecret) Value() (driver.Value, error) {
+	return crypt.EnvelopeEncrypt(string(s))
+}
+
+// UserOTPEnabled returns true if the User has enrolled the OTP secret
+func (f *Fish) UserOTPEnabled(name string) bool {
+	var count int64
+	f.db.Model(&userOTP{}).Where("user_name = ?", name).Count(&count)
+	return count > 0
+}
+
+// UserOTPEnroll generates the User OTP secret and returns otpauth URI to add it to the
+// authenticator app. The secret could be enrolled just once, admin need to reset it to re-enroll.
+func (f *Fish) UserOTPEnroll(name string) (string, error) {
+	if f.UserOTPEnabled(name) {
+		return "", fmt.Errorf("Fish: OTP is already enrolled for the User, ask admin to reset it")
+	}
+	secret, err := crypt.TOTPSecretGenerate()
+	if err != nil {
+		return "", fmt.Errorf("Fish: Unable to generate OTP secret: %v", err)
+	}
+	if err := f.db.Create(&userOTP{UserName: name, Secret: otpSecret(secret)}).Error; err != nil {
+		return "", err
+	}
+	return crypt.TOTPURI(UserOTPIssuer, name, secret), nil
+}
+
+// UserOTPReset removes the User OTP secret
+func (f *Fish) UserOTPReset(name string) error {
+	return f.db.Where("user_name = ?", name).Delete(&userOTP{}).Error
+}
+
+// ApplicationAccessOTP returns true if the Application Label requires the OTP second factor to
+// access the Resource
+func (f *Fish) ApplicationAccessOTP(appUID types.ApplicationUID) (bool, error) {
+	app, err := f.ApplicationGet(appUID)
+	if err != nil {
+		return false, fmt.Errorf("Fish: Unable to find the Application %s: %v", appUID, err)
+	}
+	label, err := f.LabelGet(app.LabelUID)
+	if err != nil {
+		return false, fmt.Errorf("Fish: Unable to find the Label %s: %v", app.LabelUID, err)
+	}
+	return label.AccessOtp, nil
+}
+
+// UserOTPCheck validates the User OTP code, each code could be used just once
+func (f *Fish) UserOTPCheck(name, code string) error {
+	otp := &userOTP{}
+	if err := f.db.Where("user_name = ?", name).First(otp).Error; err != nil {
+		return fmt.Errorf("Fish: OTP is not enrolled for the User")
+	}
+	counter, ok := crypt.TOTPValidate(string(otp.Secret), code, time.Now())
+	if !ok {
+		return fmt.Errorf("Fish: Invalid OTP code")
+	}
+	// Conditional update makes sure the concurrent requests can't use the same code twice
+	res := f.db.Model(&userOTP{}).Where("user_name = ? AND last_counter < ?", name, counter).
+		Update("last_counter", counter)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("Fish: OTP code was already used")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// UserTokenPrefix marks the password as the User API token: fat_<token UID>_<secret>
+const UserTokenPrefix = "fat_"
+
+// The token last usage is not updated on every request to not write to DB that often
+const userTokenLastUsedPeriod = time.Minute
+
+// UserTokenCreate generates the new API token of the User, the secret is returned only once in
+// the Token field and just its hash is stored
+func (f *Fish) UserTokenCreate(t *types.UserToken) error {
+	if t.Name == "" {
+		return fmt.Errorf("Fish: Name can't be empty")
+	}
+	if t.UserName == "" {
+		return fmt.Errorf("Fish: UserName can't be empty")
+	}
+	if t.CreatedBy == "" {
+		return fmt.Errorf("Fish: CreatedBy can't be empty")
+	}
+	if t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("Fish: ExpiresAt should be in the future")
+	}
+	if _, err := f.UserGet(t.UserName); err != nil {
+		return fmt.Errorf("Fish: Unable to find User %q: %v", t.UserName, err)
+	}
+
+	secret := crypt.RandString(40)
+	t.UID = f.NewUID()
+	t.Hash = crypt.NewHash(secret, nil)
+	t.LastUsedAt = nil
+	t.RevokedAt = nil
+	t.RevokedBy = ""
+	if t.Scopes == nil {
+		t.Scopes = []string{}
+	}
+	if err := f.db.Create(t).Error; err != nil {
+		return err
+	}
+
+	token := UserTokenPrefix + strings.ReplaceAll(t.UID.String(), "-", "") + "_" + secret
+	t.Token = &token
+	log.Infof("Fish: AUDIT: Token %q of User %q created by %q", t.Name, t.UserName, t.CreatedBy)
+	return nil
+}
+
+// UserTokenGet returns the token by UID
+func (f *Fish) UserTokenGet(uid types.UserTokenUID) (t *types.UserToken, err error) {
+	t = &types.UserToken{}
+	err = f.db.First(t, uid).Error
+	return t, err
+}
+
+// UserTokenListUser returns all the tokens of the User
+func (f *Fish) UserTokenListUser(name string) (ts []types.UserToken, err error) {
+	err = f.db.Where("user_name = ?", name).Order("created_at").Find(&ts).Error
+	return ts, err
+}
+
+// UserTokenRevoke marks the token as revoked, the token record is kept for audit
+func (f *Fish) UserTokenRevoke(t *types.UserToken, revoker string) error {
+	if t.RevokedAt != nil {
+		return fmt.Errorf("Fish: The token is already revoked by %q", t.RevokedBy)
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	t.RevokedBy = revoker
+	if err := f.db.Model(t).Select("revoked_at", "revoked_by").Updates(t).Error; err != nil {
+		return err
+	}
+	log.Infof("Fish: AUDIT: Token %q of User %q revoked by %q", t.Name, t.UserName, revoker)
+	return nil
+}
+
+// userTokenRevokeUser revokes all the active tokens of the User
+func (f *Fish) userTokenRevokeUser(name, revoker string) error {
+	var active []types.UserToken
+	if err := f.db.Where("user_name = ? AND revoked_at IS NULL", name).Find(&active).Error; err != nil {
+		return fmt.Errorf("Fish: Unable to find the User %q tokens: %v", name, err)
+	}
+	for i := range active {
+		if err := f.UserTokenRevoke(&active[i], revoker); err != nil {
+			return fmt.Errorf("Fish: Unable to revoke the User %q token %s: %v", name, active[i].UID, err)
+		}
+	}
+	return nil
+}
+
+// UserTokenAuth returns User and the token if the token is valid and belongs to the User
+func (f *Fish) UserTokenAuth(name, token string) (*types.User, *types.UserToken) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, UserTokenPrefix), "_")
+	uid, err := uuid.Parse(id)
+	if !ok || err != nil {
+		log.Warn("Fish: Wrong format of the User token:", name)
+		return nil, nil
+	}
+	t, err := f.UserTokenGet(uid)
+	if err != nil || t.UserName != name {
+		log.Warn("Fish: User token not exists:", name, uid)
+		return nil, nil
+	}
+	if !t.Hash.IsEqual(secret) {
+		log.Warn("Fish: Incorrect User token:", name, uid)
+		return nil, nil
+	}
+	now := time.Now()
+	if t.RevokedAt != nil || t.ExpiresAt != nil && !t.ExpiresAt.After(now) {
+		log.Warn("Fish: User token is revoked or expired:", name, uid)
+		return nil, nil
+	}
+	user, err := f.UserGet(name)
+	if err != nil {
+		log.Warn("Fish: User not exists:", name)
+		return nil, nil
+	}
+
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) > userTokenLastUsedPeriod {
+		t.LastUsedAt = &now
+		if err = f.db.Model(t).Update("last_used_at", now).Error; err != nil {
+			log.Warn("Fish: Unable to update User token last usage:", uid, err)
+		}
+	}
+
+	return user, t
+}
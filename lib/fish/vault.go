@@ -0,0 +1,125 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// vaultItem is the bootstrap secret of the Application, which is removed from the metadata during
+// allocation and could be received by the Resource only once
+type vaultItem struct {
+	UID            types.ApplicationSecretUID `gorm:"primaryKey"`
+	ApplicationUID types.ApplicationUID       `gorm:"index"`
+	Name           string
+	Value          string // Enveloped by the master key if it's set
+	CreatedAt      time.Time
+}
+
+// vaultExtract moves the configured bootstrap secrets from the merged metadata to the vault and
+// wipes them from the stored Application metadata, so they will not be served by the metadata API
+// or passed to the driver
+func (f *Fish) vaultExtract(app *types.Application, metadata map[string]any) error {
+	if len(f.cfg.VaultMetadata) == 0 || metadata == nil {
+		return nil
+	}
+
+	var items []vaultItem
+	for _, name := range f.cfg.VaultMetadata {
+		value, ok := metadata[name]
+		if !ok {
+			continue
+		}
+		delete(metadata, name)
+		enc, err := crypt.EnvelopeEncrypt(fmt.Sprint(value))
+		if err != nil {
+			return fmt.Errorf("Fish: Unable to encrypt vault secret %q: %v", name, err)
+		}
+		items = append(items, vaultItem{UID: f.NewUID(), ApplicationUID: app.UID, Name: name, Value: enc})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var appMetadata map[string]any
+	if err := json.Unmarshal([]byte(app.Metadata), &appMetadata); err != nil {
+		return fmt.Errorf("Fish: Unable to parse the Application metadata: %v", err)
+	}
+	for name := range appMetadata {
+		if slices.Contains(f.cfg.VaultMetadata, name) {
+			delete(appMetadata, name)
+		}
+	}
+	data, err := json.Marshal(appMetadata)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to serialize the Application metadata: %v", err)
+	}
+	app.Metadata = util.UnparsedJSON(data)
+
+	err = f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+		return tx.Model(app).Update("metadata", app.Metadata).Error
+	})
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to store vault secrets: %v", err)
+	}
+	log.Infof("Fish: Moved %d bootstrap secrets of the Application %s to vault", len(items), app.UID)
+	return nil
+}
+
+// VaultPop returns the vault secrets of the Application and wipes them
+func (f *Fish) VaultPop(appUID types.ApplicationUID) (map[string]any, error) {
+	var items []vaultItem
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("application_uid = ?", appUID).Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Where("application_uid = ?", appUID).Delete(&vaultItem{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]any, len(items))
+	for _, item := range items {
+		value, err := crypt.EnvelopeDecrypt(item.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Fish: Unable to decrypt vault secret %q: %v", item.Name, err)
+		}
+		out[item.Name] = value
+	}
+	if len(items) > 0 {
+		log.Infof("Fish: Vault secrets of the Application %s were received by the Resource", appUID)
+	}
+	return out, nil
+}
+
+// vaultDeleteByApplication wipes the vault secrets which were not received by the Resource
+func (f *Fish) vaultDeleteByApplication(appUID types.ApplicationUID) error {
+	return f.db.Where("application_uid = ?", appUID).Delete(&vaultItem{}).Error
+}
@@ -14,7 +14,7 @@ package fish
 
 import (
 	"fmt"
-	"math/rand"
+	"strconv"
 
 	"github.com/google/uuid"
 
@@ -23,9 +23,9 @@ import (
 	"github.com/adobe/aquarium-fish/lib/util"
 )
 
-// VoteFind returns list of Votes that fits filter
-func (f *Fish) VoteFind(filter *string) (vs []types.Vote, err error) {
-	db := f.db
+// VoteFind returns list of Votes that fits filter, report uses the replica
+func (f *Fish) VoteFind(filter *string, report bool) (vs []types.Vote, err error) {
+	db := f.dbFind(report)
 	if filter != nil {
 		securedFilter, err := util.ExpressionSQLFilter(*filter)
 		if err != nil {
@@ -47,8 +47,12 @@ func (f *Fish) VoteCreate(v *types.Vote) error {
 	if v.NodeUID == uuid.Nil {
 		return fmt.Errorf("Fish: NodeUID can't be unset")
 	}
-	// Update Vote Rand to be actual rand
-	v.Rand = rand.Uint32() // #nosec G404
+	// The Vote Rand is generated from the Application seed to make the election reproducible
+	app, err := f.ApplicationGet(v.ApplicationUID)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to find the Vote Application: %v", err)
+	}
+	v.Rand = util.SeededRand(app.Seed, "vote", f.node.Name, strconv.Itoa(int(v.Round))).Uint32()
 	v.UID = f.NewUID()
 	return f.db.Create(v).Error
 }
@@ -0,0 +1,180 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package fish
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// workloadClaims are asserting the Resource identity to the external systems, the subject is the
+// Application UID
+type workloadClaims struct {
+	jwt.StandardClaims
+	ApplicationShortID string `json:"application_short_id"`
+	Owner              string `json:"owner"`
+	LabelUID           string `json:"label_uid"`
+	LabelName          string `json:"label_name"`
+	LabelVersion       int    `json:"label_version"`
+	Node               string `json:"node"`
+}
+
+// workloadIdentityInit loads or generates the key to sign the workload tokens
+func (f *Fish) workloadIdentityInit() error {
+	keyPath := f.cfg.WorkloadIdentity.Key
+	if !filepath.IsAbs(keyPath) {
+		keyPath = filepath.Join(f.cfg.Directory, keyPath)
+	}
+
+	data, err := os.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		log.Info("Fish: Generating workload identity key:", keyPath)
+		if data, err = crypt.GenerateSSHKey(); err != nil {
+			return fmt.Errorf("Fish: Unable to generate workload identity key: %v", err)
+		}
+		if err = os.WriteFile(keyPath, data, 0o600); err != nil {
+			return fmt.Errorf("Fish: Unable to write workload identity key: %v", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("Fish: Unable to read workload identity key: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("Fish: Unable to parse workload identity key PEM")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("Fish: Unable to parse workload identity key: %v", err)
+	}
+	if key.Curve != elliptic.P256() {
+		return fmt.Errorf("Fish: Workload identity key should be ECDSA P-256")
+	}
+	f.workloadKey = key
+	return nil
+}
+
+// workloadKeyID returns the stable ID of the signing key to find it in JWKS
+func workloadKeyID(key *ecdsa.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(key)
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// WorkloadIdentityEnabled returns true if the node issues the workload tokens
+func (f *Fish) WorkloadIdentityEnabled() bool {
+	return f.workloadKey != nil
+}
+
+// WorkloadTokenIssue creates the short-lived token asserting the Resource identity for the audience
+func (f *Fish) WorkloadTokenIssue(res *types.Resource, audience string) (*types.WorkloadToken, error) {
+	if !f.WorkloadIdentityEnabled() {
+		return nil, fmt.Errorf("Fish: Workload identity is not enabled")
+	}
+	if audience == "" {
+		audience = f.cfg.WorkloadIdentity.Audiences[0]
+	}
+	if !slices.Contains(f.cfg.WorkloadIdentity.Audiences, audience) {
+		return nil, fmt.Errorf("Fish: Audience %q is not allowed", audience)
+	}
+	if err := f.ApplicationIsAllocated(res.ApplicationUID); err != nil {
+		return nil, err
+	}
+	app, err := f.ApplicationGet(res.ApplicationUID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find the Application %s: %v", res.ApplicationUID, err)
+	}
+	label, err := f.LabelGet(app.LabelUID)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to find the Label %s: %v", app.LabelUID, err)
+	}
+
+	now := time.Now()
+	expires := now.Add(time.Duration(f.cfg.WorkloadIdentity.TTL))
+	claims := workloadClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    f.cfg.WorkloadIdentity.Issuer,
+			Subject:   app.UID.String(),
+			Audience:  audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: expires.Unix(),
+			Id:        f.NewUID().String(),
+		},
+		ApplicationShortID: app.ShortId,
+		Owner:              app.OwnerName,
+		LabelUID:           label.UID.String(),
+		LabelName:          label.Name,
+		LabelVersion:       label.Version,
+		Node:               f.node.Name,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = workloadKeyID(&f.workloadKey.PublicKey)
+	signed, err := token.SignedString(f.workloadKey)
+	if err != nil {
+		return nil, fmt.Errorf("Fish: Unable to sign workload token: %v", err)
+	}
+	return &types.WorkloadToken{Token: signed, ExpiresAt: expires}, nil
+}
+
+// WorkloadOIDCConfiguration returns OIDC discovery document for the workload tokens verifiers
+func (f *Fish) WorkloadOIDCConfiguration() map[string]any {
+	issuer := strings.TrimSuffix(f.cfg.WorkloadIdentity.Issuer, "/")
+	return map[string]any{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"id_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{jwt.SigningMethodES256.Alg()},
+		"claims_supported": []string{"iss", "sub", "aud", "exp", "iat", "nbf", "jti",
+			"application_short_id", "owner", "label_uid", "label_name", "label_version", "node"},
+	}
+}
+
+// WorkloadJWKS returns the public key set to verify the workload tokens
+func (f *Fish) WorkloadJWKS() map[string]any {
+	pub := f.workloadKey.PublicKey
+	coord := func(v []byte) string {
+		// The coordinates should be padded to the curve size
+		out := make([]byte, 32)
+		copy(out[32-len(v):], v)
+		return base64.RawURLEncoding.EncodeToString(out)
+	}
+	return map[string]any{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   coord(pub.X.Bytes()),
+			"y":   coord(pub.Y.Bytes()),
+			"kid": workloadKeyID(&pub),
+			"use": "sig",
+			"alg": jwt.SigningMethodES256.Alg(),
+		}},
+	}
+}
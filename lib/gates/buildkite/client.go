@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package buildkite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// queueMetrics is the part of agent metrics for the queue
+type queueMetrics struct {
+	Scheduled int `json:"scheduled"` // Jobs waiting for agent
+	Running   int `json:"running"`
+	Waiting   int `json:"waiting"` // Jobs waiting for dependencies
+}
+
+// agentInfo is the part of Buildkite agent info we need
+type agentInfo struct {
+	Name              string          `json:"name"`
+	ConnectionState   string          `json:"connection_state"` // connected, disconnected, lost, never_connected
+	Job               json.RawMessage `json:"job"`              // Current job, empty if agent is idle
+	CreatedAt         time.Time       `json:"created_at"`
+	LastJobFinishedAt *time.Time      `json:"last_job_finished_at"`
+}
+
+// IsIdle returns true if the agent does not run any job
+func (a *agentInfo) IsIdle() bool {
+	return len(a.Job) == 0 || string(a.Job) == "null"
+}
+
+// IdleSince returns when the agent became idle
+func (a *agentInfo) IdleSince() time.Time {
+	if a.LastJobFinishedAt != nil && a.LastJobFinishedAt.After(a.CreatedAt) {
+		return *a.LastJobFinishedAt
+	}
+	return a.CreatedAt
+}
+
+// request executes Buildkite API request and parses the response json into out
+func (g *Gate) request(url, auth string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("BUILDKITE: API %s returned %d: %s", url, resp.StatusCode, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getMetrics returns the jobs metrics per queue
+func (g *Gate) getMetrics() (map[string]queueMetrics, error) {
+	var out struct {
+		Jobs struct {
+			Queues map[string]queueMetrics `json:"queues"`
+		} `json:"jobs"`
+	}
+	if err := g.request(g.cfg.MetricsURL, "Token "+g.cfg.AgentToken, &out); err != nil {
+		return nil, err
+	}
+	return out.Jobs.Queues, nil
+}
+
+// listAgents returns the organization agents by name
+func (g *Gate) listAgents() (map[string]agentInfo, error) {
+	agents := make(map[string]agentInfo)
+	for page := 1; ; page++ {
+		var list []agentInfo
+		url := fmt.Sprintf("%s/organizations/%s/agents?per_page=100&page=%d", g.cfg.APIURL, g.cfg.Organization, page)
+		if err := g.request(url, "Bearer "+g.cfg.APIToken, &list); err != nil {
+			return nil, err
+		}
+		for _, a := range list {
+			agents[a.Name] = a
+		}
+		if len(list) < 100 {
+			return agents, nil
+		}
+	}
+}
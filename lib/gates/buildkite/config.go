@@ -0,0 +1,115 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package buildkite implements gate which scales Buildkite agents on Fish Resources
+package buildkite
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - gate configuration
+type Config struct {
+	APIURL     string `json:"api_url"`     // Buildkite REST API endpoint, "https://api.buildkite.com/v2" by default
+	MetricsURL string `json:"metrics_url"` // Buildkite agent metrics endpoint, "https://agent.buildkite.com/v3/metrics" by default
+
+	Organization string `json:"organization"` // Buildkite organization slug
+	APIToken     string `json:"api_token"`    // REST API token with read_agents scope, if empty - BUILDKITE_API_TOKEN env var is used
+	AgentToken   string `json:"agent_token"`  // Agent registration token sent by secrets relay, if empty - BUILDKITE_AGENT_TOKEN env var is used
+
+	Queues []QueueConfig `json:"queues"` // Which queues to serve and how
+
+	PollInterval   util.Duration `json:"poll_interval"`   // How often to check the queues, 30s by default
+	Cooldown       util.Duration `json:"cooldown"`        // Minimal time between the scale actions of the queue, 1m by default
+	IdleTimeout    util.Duration `json:"idle_timeout"`    // How long agent could stay idle before scale down, 5m by default
+	ConnectTimeout util.Duration `json:"connect_timeout"` // How long to wait for the new agent to connect, 30m by default
+
+	ApplicationOwner string `json:"application_owner"` // Fish user to own the created Applications, "admin" by default
+}
+
+// QueueConfig maps Buildkite queue to Fish Label
+type QueueConfig struct {
+	Name  string `json:"name"`  // Buildkite queue name
+	Label string `json:"label"` // Fish Label name to allocate agents with, latest version is used
+	Min   int    `json:"min"`   // Minimal amount of agents to keep running
+	Max   int    `json:"max"`   // Maximum amount of agents, 0 means no limit
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("BUILDKITE: Unable to apply the gate config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.APIURL == "" {
+		c.APIURL = "https://api.buildkite.com/v2"
+	}
+	c.APIURL = strings.TrimSuffix(c.APIURL, "/")
+	if c.MetricsURL == "" {
+		c.MetricsURL = "https://agent.buildkite.com/v3/metrics"
+	}
+	if c.Organization == "" {
+		return fmt.Errorf("BUILDKITE: Organization is not set")
+	}
+	if c.APIToken == "" {
+		c.APIToken = os.Getenv("BUILDKITE_API_TOKEN")
+	}
+	if c.APIToken == "" {
+		return fmt.Errorf("BUILDKITE: API token is not set")
+	}
+	if c.AgentToken == "" {
+		c.AgentToken = os.Getenv("BUILDKITE_AGENT_TOKEN")
+	}
+	if c.AgentToken == "" {
+		return fmt.Errorf("BUILDKITE: Agent token is not set")
+	}
+	if len(c.Queues) == 0 {
+		return fmt.Errorf("BUILDKITE: No queues to serve")
+	}
+	for _, q := range c.Queues {
+		if q.Name == "" || q.Label == "" {
+			return fmt.Errorf("BUILDKITE: Queue name and label are required")
+		}
+		if q.Min < 0 || q.Max < 0 || q.Max > 0 && q.Min > q.Max {
+			return fmt.Errorf("BUILDKITE: Queue %q has wrong min/max bounds: %d/%d", q.Name, q.Min, q.Max)
+		}
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = util.Duration(30 * time.Second)
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = util.Duration(time.Minute)
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = util.Duration(5 * time.Minute)
+	}
+	if c.ConnectTimeout <= 0 {
+		c.ConnectTimeout = util.Duration(30 * time.Minute)
+	}
+	if c.ApplicationOwner == "" {
+		c.ApplicationOwner = "admin"
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Factory implements gates.GateFactory interface
+type Factory struct{}
+
+// Name shows name of the gate factory
+func (*Factory) Name() string {
+	return "buildkite"
+}
+
+// NewGate creates new gate
+func (*Factory) NewGate() gates.Gate {
+	return &Gate{}
+}
+
+func init() {
+	gates.FactoryList = append(gates.FactoryList, &Factory{})
+}
+
+// Gate implements gates.Gate interface
+type Gate struct {
+	cfg    Config
+	fish   *fish.Fish
+	client *http.Client
+
+	// State of the served queues, key is queue name. The state is not stored separately, but
+	// recovered from the active Applications metadata on start.
+	queuesMutex sync.Mutex
+	queues      map[string]*queueState
+
+	stop chan struct{}
+}
+
+// queueState keeps the agents Applications created for the queue
+type queueState struct {
+	Agents    map[string]*agentRecord // Agent name -> Application
+	LastScale time.Time
+}
+
+// agentRecord stores the Application of the agent
+type agentRecord struct {
+	AppUID    types.ApplicationUID
+	CreatedAt time.Time
+	TokenSent bool // Agent token was sent to the Resource through the secrets relay
+}
+
+// queueAgent is the agent info used to make scale decision
+type queueAgent struct {
+	Name         string
+	Pending      bool      // Application is allocating or agent is not yet connected
+	PendingSince time.Time // When the agent Application was created
+	Idle         bool
+	IdleSince    time.Time
+}
+
+// agentMetadata is passed to the Application, the driver puts it to the Resource userdata. The
+// agent token is not the part of it and delivered by the secrets relay with agentTokenSecret name.
+type agentMetadata struct {
+	Name string `json:"BUILDKITE_AGENT_NAME"`
+	Tags string `json:"BUILDKITE_AGENT_TAGS"`
+}
+
+// Name of the secret to deliver the agent token to the Resource
+const agentTokenSecret = "BUILDKITE_AGENT_TOKEN"
+
+// Name returns name of the gate
+func (*Gate) Name() string {
+	return "buildkite"
+}
+
+// Init validates config and starts the queues processing
+func (g *Gate) Init(f *fish.Fish, config []byte, sv *gates.Supervisor) error {
+	if err := g.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := g.cfg.Validate(); err != nil {
+		return err
+	}
+
+	g.fish = f
+	g.client = &http.Client{Timeout: 30 * time.Second}
+	g.queues = make(map[string]*queueState)
+	for _, q := range g.cfg.Queues {
+		g.queues[q.Name] = &queueState{Agents: make(map[string]*agentRecord)}
+	}
+	g.stop = make(chan struct{})
+
+	if err := g.restoreAgents(); err != nil {
+		return err
+	}
+
+	sv.Go(g.pollProcess)
+
+	return nil
+}
+
+// Close stops the gate processes
+func (g *Gate) Close() {
+	close(g.stop)
+}
+
+// restoreAgents recovers the queues agents from the active Applications after restart, so the
+// agents will not be lost and the scale decisions will count them
+func (g *Gate) restoreAgents() error {
+	apps, err := g.fish.ApplicationFind(nil, false)
+	if err != nil {
+		return fmt.Errorf("BUILDKITE: Unable to list Applications to restore the agents: %v", err)
+	}
+	for _, app := range apps {
+		if app.OwnerName != g.cfg.ApplicationOwner {
+			continue
+		}
+		var md agentMetadata
+		if err := json.Unmarshal([]byte(app.Metadata), &md); err != nil || md.Name == "" {
+			continue
+		}
+		state, ok := g.queues[strings.TrimPrefix(md.Tags, "queue=")]
+		if !ok {
+			continue
+		}
+		appState, err := g.fish.ApplicationStateGetByApplication(app.UID)
+		if err != nil || !g.fish.ApplicationStateIsActive(appState.Status) {
+			continue
+		}
+		state.Agents[md.Name] = &agentRecord{AppUID: app.UID, CreatedAt: app.CreatedAt}
+		log.Debugf("BUILDKITE: Restored agent %s of Application %s", md.Name, app.UID)
+	}
+	return nil
+}
+
+// pollProcess periodically checks the queues and scales the agents
+func (g *Gate) pollProcess() {
+	ticker := time.NewTicker(time.Duration(g.cfg.PollInterval))
+	defer ticker.Stop()
+	for {
+		g.poll()
+
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *Gate) poll() {
+	metrics, err := g.getMetrics()
+	if err != nil {
+		log.Error("BUILDKITE: Unable to get agent metrics:", err)
+		return
+	}
+	agents, err := g.listAgents()
+	if err != nil {
+		log.Error("BUILDKITE: Unable to list agents:", err)
+		return
+	}
+
+	g.queuesMutex.Lock()
+	defer g.queuesMutex.Unlock()
+	for _, q := range g.cfg.Queues {
+		g.processQueue(q, g.queues[q.Name], metrics[q.Name], agents)
+	}
+}
+
+// processQueue cleans up the gone agents and scales the queue agents
+func (g *Gate) processQueue(q QueueConfig, state *queueState, metrics queueMetrics, agents map[string]agentInfo) {
+	var list []queueAgent
+	for name, rec := range state.Agents {
+		appState, err := g.fish.ApplicationStateGetByApplication(rec.AppUID)
+		if err != nil {
+			log.Error("BUILDKITE: Unable to get Application state:", rec.AppUID, err)
+			continue
+		}
+		if !g.fish.ApplicationStateIsActive(appState.Status) {
+			log.Debugf("BUILDKITE: Application %s of agent %s is not active anymore: %s", rec.AppUID, name, appState.Status)
+			delete(state.Agents, name)
+			continue
+		}
+
+		agent, ok := agents[name]
+		if ok {
+			// Agent was registered in Buildkite, so it received the token
+			rec.TokenSent = true
+		} else if !rec.TokenSent {
+			rec.TokenSent = g.sendToken(name, rec.AppUID)
+		}
+		switch {
+		case !ok || agent.ConnectionState == "never_connected":
+			list = append(list, queueAgent{Name: name, Pending: true, PendingSince: rec.CreatedAt})
+		case agent.ConnectionState == "connected":
+			list = append(list, queueAgent{Name: name, Idle: agent.IsIdle(), IdleSince: agent.IdleSince()})
+		default:
+			// Agent was connected, but now it's gone so the Resource is not needed anymore
+			log.Infof("BUILDKITE: Agent %s is %s, deallocating Application %s", name, agent.ConnectionState, rec.AppUID)
+			if g.deallocate(rec.AppUID) {
+				delete(state.Agents, name)
+			}
+		}
+	}
+
+	// The agents which were not able to connect are broken and should not block the scale up
+	list, expired := expiredAgents(list, time.Duration(g.cfg.ConnectTimeout), time.Now())
+	for _, name := range expired {
+		log.Warnf("BUILDKITE: Agent %s was not connected in %s, deallocating Application %s", name, time.Duration(g.cfg.ConnectTimeout), state.Agents[name].AppUID)
+		if g.deallocate(state.Agents[name].AppUID) {
+			delete(state.Agents, name)
+		}
+	}
+
+	if time.Since(state.LastScale) < time.Duration(g.cfg.Cooldown) {
+		return
+	}
+
+	up, down := scaleDecision(q, metrics.Scheduled, list, time.Duration(g.cfg.IdleTimeout), time.Now())
+	if up > 0 {
+		log.Infof("BUILDKITE: Scaling up queue %s by %d agents (scheduled jobs: %d, agents: %d)", q.Name, up, metrics.Scheduled, len(list))
+		state.LastScale = time.Now()
+		for i := 0; i < up; i++ {
+			name, appUID, err := g.createAgent(q)
+			if err != nil {
+				log.Error("BUILDKITE: Unable to create agent:", err)
+				break
+			}
+			state.Agents[name] = &agentRecord{AppUID: appUID, CreatedAt: time.Now()}
+		}
+	}
+	if len(down) > 0 {
+		log.Infof("BUILDKITE: Scaling down queue %s by %d idle agents", q.Name, len(down))
+		state.LastScale = time.Now()
+		for _, name := range down {
+			if g.deallocate(state.Agents[name].AppUID) {
+				delete(state.Agents, name)
+			}
+		}
+	}
+}
+
+// expiredAgents splits out the pending agents which were not connected during the timeout
+func expiredAgents(agents []queueAgent, connectTimeout time.Duration, now time.Time) (active []queueAgent, expired []string) {
+	for _, a := range agents {
+		if a.Pending && now.Sub(a.PendingSince) > connectTimeout {
+			expired = append(expired, a.Name)
+			continue
+		}
+		active = append(active, a)
+	}
+	return active, expired
+}
+
+// sendToken delivers the agent token encrypted with the Resource key through the secrets relay,
+// returns false if the Resource is not ready to receive it yet
+func (g *Gate) sendToken(name string, appUID types.ApplicationUID) bool {
+	res, err := g.fish.ResourceGetByApplication(appUID)
+	if err != nil || res.SecretKey == "" {
+		// Resource is still allocating or not registered the key yet
+		return false
+	}
+	ciphertext, err := crypt.SecretSeal(res.SecretKey, []byte(g.cfg.AgentToken))
+	if err != nil {
+		log.Errorf("BUILDKITE: Unable to encrypt token for agent %s: %v", name, err)
+		return false
+	}
+	secret := &types.ApplicationSecret{ApplicationUID: appUID, Name: agentTokenSecret, Ciphertext: ciphertext}
+	if err := g.fish.ApplicationSecretCreate(secret); err != nil {
+		log.Errorf("BUILDKITE: Unable to send token to agent %s: %v", name, err)
+		return false
+	}
+	log.Debugf("BUILDKITE: Token sent to agent %s of Application %s", name, appUID)
+	return true
+}
+
+// scaleDecision returns how many agents need to be added or which idle agents could be removed
+func scaleDecision(q QueueConfig, scheduled int, agents []queueAgent, idleTimeout time.Duration, now time.Time) (up int, down []string) {
+	total := len(agents)
+	available := 0
+	for _, a := range agents {
+		if a.Pending || a.Idle {
+			available++
+		}
+	}
+
+	up = scheduled - available
+	if total+up < q.Min {
+		up = q.Min - total
+	}
+	if q.Max > 0 && total+up > q.Max {
+		up = q.Max - total
+	}
+	if up > 0 {
+		return up, nil
+	}
+	if scheduled > 0 {
+		// The idle agents will pick up the scheduled jobs soon
+		return 0, nil
+	}
+
+	for _, a := range agents {
+		if total-len(down) <= q.Min {
+			break
+		}
+		if a.Idle && now.Sub(a.IdleSince) > idleTimeout {
+			down = append(down, a.Name)
+		}
+	}
+	return 0, down
+}
+
+// createAgent creates Application which will start the agent for the queue
+func (g *Gate) createAgent(q QueueConfig) (string, types.ApplicationUID, error) {
+	label, err := g.fish.LabelGetLatest(q.Label)
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("BUILDKITE: Unable to find Label %q: %v", q.Label, err)
+	}
+
+	name := fmt.Sprintf("fish-%s-%s", q.Name, crypt.RandStringCharset(6, "0123456789abcdefghijklmnopqrstuvwxyz"))
+	// The token is not passed in metadata to not expose it, it's sent when Resource is ready
+	metadata, err := json.Marshal(agentMetadata{Name: name, Tags: "queue=" + q.Name})
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("BUILDKITE: Unable to prepare Application metadata: %v", err)
+	}
+
+	app := &types.Application{
+		LabelUID:  label.UID,
+		OwnerName: g.cfg.ApplicationOwner,
+		Metadata:  util.UnparsedJSON(metadata),
+	}
+	if err := g.fish.ApplicationCreate(context.Background(), app); err != nil {
+		return "", uuid.Nil, fmt.Errorf("BUILDKITE: Unable to create Application for agent %s: %v", name, err)
+	}
+	log.Infof("BUILDKITE: Created Application %s with Label %s:%d for agent %s", app.UID, label.Name, label.Version, name)
+	return name, app.UID, nil
+}
+
+// deallocate requests Application deallocation and returns true if it was requested
+func (g *Gate) deallocate(appUID types.ApplicationUID) bool {
+	app, err := g.fish.ApplicationGet(appUID)
+	if err != nil {
+		log.Error("BUILDKITE: Unable to find Application:", appUID, err)
+		return false
+	}
+	if _, err := g.fish.ApplicationDeallocate(app, "buildkite"); err != nil {
+		log.Error("BUILDKITE: Unable to deallocate Application:", appUID, err)
+		return false
+	}
+	return true
+}
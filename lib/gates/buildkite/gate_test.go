@@ -0,0 +1,83 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package buildkite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Verify the queue scaling respects the jobs, idle agents and min/max bounds
+func Test_scale_decision(t *testing.T) {
+	now := time.Now()
+	idleOld := queueAgent{Name: "idle-old", Idle: true, IdleSince: now.Add(-time.Hour)}
+	idleNew := queueAgent{Name: "idle-new", Idle: true, IdleSince: now}
+	busy := queueAgent{Name: "busy"}
+	pending := queueAgent{Name: "pending", Pending: true}
+
+	testcases := []struct {
+		name      string
+		queue     QueueConfig
+		scheduled int
+		agents    []queueAgent
+		up        int
+		down      string
+	}{
+		{"no jobs no agents", QueueConfig{}, 0, nil, 0, "[]"},
+		{"jobs waiting", QueueConfig{}, 3, []queueAgent{busy}, 3, "[]"},
+		{"pending agents will take jobs", QueueConfig{}, 2, []queueAgent{pending, idleNew}, 0, "[]"},
+		{"max limit", QueueConfig{Max: 3}, 5, []queueAgent{busy}, 2, "[]"},
+		{"max reached", QueueConfig{Max: 1}, 5, []queueAgent{busy}, 0, "[]"},
+		{"keep min", QueueConfig{Min: 2}, 0, nil, 2, "[]"},
+		{"scale down idle", QueueConfig{}, 0, []queueAgent{idleOld, idleNew, busy}, 0, "[idle-old]"},
+		{"no scale down below min", QueueConfig{Min: 2}, 0, []queueAgent{idleOld, busy}, 0, "[]"},
+		{"no scale down with jobs", QueueConfig{}, 1, []queueAgent{idleOld, pending}, 0, "[]"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			up, down := scaleDecision(tc.queue, tc.scheduled, tc.agents, 5*time.Minute, now)
+			if up != tc.up || fmt.Sprint(down) != tc.down {
+				t.Fatalf("scaleDecision() = %d, %v; want: %d, %s", up, down, tc.up, tc.down)
+			}
+		})
+	}
+}
+
+// Verify the never connected agents are expired after the connect timeout
+func Test_expired_agents(t *testing.T) {
+	now := time.Now()
+	agents := []queueAgent{
+		{Name: "pending-old", Pending: true, PendingSince: now.Add(-time.Hour)},
+		{Name: "pending-new", Pending: true, PendingSince: now.Add(-time.Minute)},
+		{Name: "connected", Idle: true, IdleSince: now.Add(-time.Hour)},
+	}
+	active, expired := expiredAgents(agents, 30*time.Minute, now)
+	if fmt.Sprint(expired) != "[pending-old]" {
+		t.Fatalf("Expected only pending-old to expire, got: %v", expired)
+	}
+	if len(active) != 2 || active[0].Name != "pending-new" || active[1].Name != "connected" {
+		t.Fatalf("Unexpected active agents: %v", active)
+	}
+
+	// The expired agent should not block the scale up anymore
+	up, _ := scaleDecision(QueueConfig{Max: 2}, 1, agents[:1], 5*time.Minute, now)
+	if up != 0 {
+		t.Fatalf("Pending agent expected to take the job, got scale up: %d", up)
+	}
+	active, expired = expiredAgents(agents[:1], 30*time.Minute, now)
+	up, _ = scaleDecision(QueueConfig{Max: 2}, 1, active, 5*time.Minute, now)
+	if len(expired) != 1 || up != 1 {
+		t.Fatalf("Expected scale up after the agent expired, got: %d", up)
+	}
+}
@@ -0,0 +1,308 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package gates implements interface for each gate (the way for external systems to use Fish)
+package gates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/secrets"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// FactoryList is a list of available gates factories
+var FactoryList []GateFactory
+
+// GateFactory allows to generate new instances of the gates
+type GateFactory interface {
+	// Name of the gate
+	Name() string
+
+	// Generates new gate
+	NewGate() Gate
+}
+
+// Gate interface of the functions that connects the external system to Fish
+type Gate interface {
+	// Name of the gate
+	Name() string
+
+	// Give gate configs and start its background processes, which should be started with
+	// sv.Go() to be supervised
+	// -> f - fish node to operate
+	// -> config - gate configuration in json format
+	// -> sv - supervisor of the gate instance
+	Init(f *fish.Fish, config []byte, sv *Supervisor) error
+
+	// Stops the gate background processes
+	Close()
+}
+
+// If the gate did not panic for this time - the restart backoff is reset
+const supervisorStablePeriod = 10 * time.Minute
+
+// Supervisor keeps the gate instance info to restart it in case of panic, so the failing gate
+// will not take down the whole node. It implements Gate interface to be used as the instance.
+type Supervisor struct {
+	name    string
+	factory GateFactory
+	fish    *fish.Fish
+	config  []byte
+	ref     []byte // Config with the secret references to re-read the rotated secrets
+
+	mutex      sync.Mutex
+	gate       Gate
+	closed     bool
+	restarting bool
+	panics     int       // Panics in a row used to calculate backoff
+	lastPanic  time.Time // When the last panic happened
+}
+
+// Init creates and starts the gates listed in configs, the config name could contain instance
+// suffix separated by slash like the drivers ("github/prod")
+func Init(f *fish.Fish, configs []fish.ConfigDriver) (instances map[string]Gate, err error) {
+	instances = make(map[string]Gate)
+	for _, cfg := range configs {
+		var factory GateFactory
+		for _, fbr := range FactoryList {
+			if cfg.Name == fbr.Name() || strings.HasPrefix(cfg.Name, fbr.Name()+"/") {
+				factory = fbr
+				break
+			}
+		}
+		if factory == nil {
+			Close(instances)
+			return nil, fmt.Errorf("Gates: Unable to find gate for config %q", cfg.Name)
+		}
+		sv := &Supervisor{name: cfg.Name, factory: factory, ref: []byte(cfg.Cfg)}
+		config, err := secrets.ResolveJSON(sv.ref)
+		if err != nil {
+			Close(instances)
+			return nil, fmt.Errorf("Gates: Unable to resolve gate %q config secrets: %v", cfg.Name, err)
+		}
+		if err := sv.Init(f, config, nil); err != nil {
+			Close(instances)
+			return nil, fmt.Errorf("Gates: Unable to init gate %q: %v", cfg.Name, err)
+		}
+		log.Info("Gates: Gate enabled:", cfg.Name)
+		instances[cfg.Name] = sv
+		if secrets.HasRefs(sv.ref) && secrets.RefreshInterval() > 0 {
+			go sv.secretsProcess(secrets.RefreshInterval())
+		}
+	}
+	return instances, nil
+}
+
+// Close stops all the provided gates
+func Close(instances map[string]Gate) {
+	for name, gate := range instances {
+		log.Debug("Gates: Stopping gate:", name)
+		gate.Close()
+	}
+}
+
+// Name returns the gate instance name
+func (s *Supervisor) Name() string {
+	return s.name
+}
+
+// Init creates the gate instance and starts it, the supervisor is not used here
+func (s *Supervisor) Init(f *fish.Fish, config []byte, _ *Supervisor) error {
+	s.fish = f
+	s.config = config
+	return s.start()
+}
+
+// Close stops the gate and prevents it's restart
+func (s *Supervisor) Close() {
+	s.mutex.Lock()
+	s.closed = true
+	gate := s.gate
+	restarting := s.restarting
+	s.mutex.Unlock()
+
+	// Restarting gate is already stopped
+	if gate != nil && !restarting {
+		s.stop(gate)
+	}
+}
+
+// Go runs the gate background process and restarts the gate with backoff if the process panics
+func (s *Supervisor) Go(fn func()) {
+	s.mutex.Lock()
+	gate := s.gate
+	s.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.panicked(gate, r)
+			}
+		}()
+		fn()
+	}()
+}
+
+// Annotate sets the business-level status of the Application served by the gate, the gate
+// instance name is used as the annotation source. Data is optional structured details.
+func (s *Supervisor) Annotate(appUID types.ApplicationUID, key, text string, data any) {
+	annotation := &types.ApplicationAnnotation{
+		ApplicationUID: appUID,
+		Source:         s.name,
+		Key:            key,
+		Text:           text,
+	}
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			log.Warnf("Gates: Gate %s unable to encode annotation %q data: %v", s.name, key, err)
+			return
+		}
+		annotation.Data = util.UnparsedJSON(encoded)
+	}
+	if err := s.fish.ApplicationAnnotationSet(annotation); err != nil {
+		log.Warnf("Gates: Gate %s unable to annotate Application %s: %v", s.name, appUID, err)
+	}
+}
+
+// start creates the new gate instance and inits it
+func (s *Supervisor) start() (err error) {
+	gate := s.factory.NewGate()
+	// Setting the gate before init to allow it's background processes to report the panic
+	s.mutex.Lock()
+	s.gate = gate
+	s.mutex.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Gates: Gate %s panic in Init: %v\n%s", s.name, r, debug.Stack())
+			err = fmt.Errorf("Gates: Gate %s panic in Init: %v", s.name, r)
+		}
+	}()
+	return gate.Init(s.fish, s.config, s)
+}
+
+// stop closes the gate instance
+func (s *Supervisor) stop(gate Gate) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Gates: Gate %s panic in Close: %v", s.name, r)
+		}
+	}()
+	gate.Close()
+}
+
+// panicked reports the panic of the gate process and schedules the gate restart
+func (s *Supervisor) panicked(gate Gate, r any) {
+	log.Errorf("Gates: Gate %s panic: %v\n%s", s.name, r, debug.Stack())
+
+	s.mutex.Lock()
+	if s.closed || s.restarting || s.gate != gate {
+		// The panicked instance is already replaced or stopped
+		s.mutex.Unlock()
+		return
+	}
+	if time.Since(s.lastPanic) > supervisorStablePeriod {
+		s.panics = 0
+	}
+	s.lastPanic = time.Now()
+	s.restarting = true
+	s.mutex.Unlock()
+
+	s.stop(gate)
+	go s.restartProcess()
+}
+
+// restartProcess recreates the gate instance with backoff until it inits properly
+func (s *Supervisor) restartProcess() {
+	for {
+		s.mutex.Lock()
+		delay := util.Backoff(s.panics, time.Second, 5*time.Minute)
+		s.panics++
+		s.mutex.Unlock()
+
+		log.Warnf("Gates: Restarting gate %s in %s", s.name, delay)
+		time.Sleep(delay)
+
+		s.mutex.Lock()
+		closed := s.closed
+		s.mutex.Unlock()
+		if closed {
+			// Gates were closed during the delay
+			return
+		}
+
+		err := s.start()
+		s.mutex.Lock()
+		if err == nil {
+			s.restarting = false
+		}
+		closed = s.closed
+		gate := s.gate
+		s.mutex.Unlock()
+
+		if err != nil {
+			log.Error("Gates: Unable to restart gate:", s.name, err)
+			continue
+		}
+		if closed {
+			// Gates were closed during the start
+			s.stop(gate)
+			return
+		}
+		log.Info("Gates: Gate restarted:", s.name)
+		return
+	}
+}
+
+// secretsProcess re-reads the config secrets and restarts the gate if they were rotated
+func (s *Supervisor) secretsProcess(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		config, err := secrets.ResolveJSON(s.ref)
+		if err != nil {
+			log.Error("Gates: Unable to resolve the gate config secrets:", s.name, err)
+			continue
+		}
+
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			return
+		}
+		if s.restarting || bytes.Equal(s.config, config) {
+			// Restarting gate will pick up the new config on the next round
+			s.mutex.Unlock()
+			continue
+		}
+		s.config = config
+		s.restarting = true
+		gate := s.gate
+		s.mutex.Unlock()
+
+		log.Info("Gates: Gate config secrets were rotated, restarting:", s.name)
+		s.stop(gate)
+		go s.restartProcess()
+	}
+}
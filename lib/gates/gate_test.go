@@ -0,0 +1,92 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package gates
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+)
+
+type panicFactory struct {
+	inits atomic.Int32
+}
+
+func (*panicFactory) Name() string {
+	return "panic"
+}
+
+func (f *panicFactory) NewGate() Gate {
+	return &panicGate{factory: f}
+}
+
+// panicGate panics in the background process on the first init only
+type panicGate struct {
+	factory *panicFactory
+}
+
+func (*panicGate) Name() string {
+	return "panic"
+}
+
+func (g *panicGate) Init(_ *fish.Fish, _ []byte, sv *Supervisor) error {
+	if g.factory.inits.Add(1) == 1 {
+		sv.Go(func() { panic("test panic") })
+	}
+	return nil
+}
+
+func (*panicGate) Close() {}
+
+// Verify the gate is restarted after panic in its background process
+func Test_gate_panic_restart(t *testing.T) {
+	factory := &panicFactory{}
+	FactoryList = append(FactoryList, factory)
+	defer func() { FactoryList = FactoryList[:len(FactoryList)-1] }()
+
+	instances, err := Init(nil, []fish.ConfigDriver{{Name: "panic/test"}})
+	if err != nil {
+		t.Fatalf("Unable to init gate: %v", err)
+	}
+	defer Close(instances)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for factory.inits.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Gate was not restarted after panic")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Verify the closed gate is not restarted after panic
+func Test_gate_close_during_restart(t *testing.T) {
+	factory := &panicFactory{}
+	FactoryList = append(FactoryList, factory)
+	defer func() { FactoryList = FactoryList[:len(FactoryList)-1] }()
+
+	instances, err := Init(nil, []fish.ConfigDriver{{Name: "panic/close"}})
+	if err != nil {
+		t.Fatalf("Unable to init gate: %v", err)
+	}
+	// Giving the process time to panic and closing the gates during the restart delay
+	time.Sleep(100 * time.Millisecond)
+	Close(instances)
+
+	time.Sleep(1500 * time.Millisecond)
+	if inits := factory.inits.Load(); inits != 1 {
+		t.Fatalf("Closed gate was restarted: %d inits", inits)
+	}
+}
@@ -0,0 +1,135 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// workflowJob is the part of GitHub workflow job we need
+type workflowJob struct {
+	ID     int64    `json:"id"`
+	RunID  int64    `json:"run_id"`
+	Name   string   `json:"name"`
+	Status string   `json:"status"` // queued, in_progress, completed
+	Labels []string `json:"labels"`
+
+	RunnerName string `json:"runner_name"` // Runner which picked up the job
+}
+
+// selfHostedRunner is the part of GitHub self-hosted runner we need
+type selfHostedRunner struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // online, offline
+	Busy   bool   `json:"busy"`
+}
+
+// request executes GitHub API request and parses the response json into out
+func (g *Gate) request(method, path string, out any) error {
+	req, err := http.NewRequest(method, g.cfg.APIURL+path, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.cfg.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GITHUB: API %s %s returned %d: %s", method, path, resp.StatusCode, body)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// listQueuedJobs returns the queued jobs of the repository
+func (g *Gate) listQueuedJobs(repo string) (jobs []workflowJob, err error) {
+	var runs struct {
+		WorkflowRuns []struct {
+			ID int64 `json:"id"`
+		} `json:"workflow_runs"`
+	}
+	if err = g.request(http.MethodGet, "/repos/"+repo+"/actions/runs?status=queued&per_page=100", &runs); err != nil {
+		return nil, err
+	}
+	for _, run := range runs.WorkflowRuns {
+		var runJobs struct {
+			Jobs []workflowJob `json:"jobs"`
+		}
+		if err = g.request(http.MethodGet, fmt.Sprintf("/repos/%s/actions/runs/%d/jobs?filter=latest&per_page=100", repo, run.ID), &runJobs); err != nil {
+			return nil, err
+		}
+		for _, job := range runJobs.Jobs {
+			if job.Status == "queued" {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+// getJob returns the current state of the job
+func (g *Gate) getJob(repo string, id int64) (job workflowJob, err error) {
+	err = g.request(http.MethodGet, fmt.Sprintf("/repos/%s/actions/jobs/%d", repo, id), &job)
+	return job, err
+}
+
+// getRunner returns the registered runner with the name or nil if it's not registered
+func (g *Gate) getRunner(repo, name string) (*selfHostedRunner, error) {
+	var out struct {
+		Runners []selfHostedRunner `json:"runners"`
+	}
+	if err := g.request(http.MethodGet, fmt.Sprintf("/repos/%s/actions/runners?name=%s", repo, url.QueryEscape(name)), &out); err != nil {
+		return nil, err
+	}
+	for i := range out.Runners {
+		if out.Runners[i].Name == name {
+			return &out.Runners[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// registrationToken creates the new runner registration token for repository
+func (g *Gate) registrationToken(repo string) (string, error) {
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := g.request(http.MethodPost, "/repos/"+repo+"/actions/runners/registration-token", &out); err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// repoURL returns the web URL of the repository the runner need to register to
+func (g *Gate) repoURL(repo string) string {
+	if g.cfg.APIURL == "https://api.github.com" {
+		return "https://github.com/" + repo
+	}
+	// GitHub Enterprise Server API is located at https://<host>/api/v3
+	return strings.TrimSuffix(g.cfg.APIURL, "/api/v3") + "/" + repo
+}
@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package github implements gate which provides ephemeral GitHub Actions self-hosted runners
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - gate configuration
+type Config struct {
+	APIURL string `json:"api_url"` // GitHub API endpoint, "https://api.github.com" by default
+	Token  string `json:"token"`   // Token with permissions to manage self-hosted runners, if empty - GITHUB_TOKEN env var is used
+
+	Repositories []string `json:"repositories"` // List of "owner/repo" to serve the workflow jobs
+
+	PollInterval util.Duration `json:"poll_interval"` // How often to check for the queued jobs, 30s by default, negative disables polling
+
+	WebhookAddress string `json:"webhook_address"` // Where to listen for `workflow_job` webhook events, empty disables webhook
+	WebhookSecret  string `json:"webhook_secret"`  // Secret to verify the webhook events signature
+
+	// The job labels should contain the LabelPrefix + Fish Label name (like "fish:ubuntu2204")
+	// in addition to "self-hosted" to be served by the gate. If prefix is empty - any job label
+	// which is equal to the existing Fish Label name will match.
+	LabelPrefix string `json:"label_prefix"`
+
+	ApplicationOwner string `json:"application_owner"` // Fish user to own the created Applications, "admin" by default
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("GITHUB: Unable to apply the gate config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.APIURL == "" {
+		c.APIURL = "https://api.github.com"
+	}
+	c.APIURL = strings.TrimSuffix(c.APIURL, "/")
+	if c.Token == "" {
+		c.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("GITHUB: Token is not set")
+	}
+	if len(c.Repositories) == 0 {
+		return fmt.Errorf("GITHUB: No repositories to serve")
+	}
+	for _, repo := range c.Repositories {
+		if strings.Count(repo, "/") != 1 {
+			return fmt.Errorf("GITHUB: Repository %q should be in format owner/repo", repo)
+		}
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = util.Duration(30 * time.Second)
+	}
+	if c.WebhookAddress != "" && c.WebhookSecret == "" {
+		return fmt.Errorf("GITHUB: Webhook secret is required to verify the events")
+	}
+	if c.ApplicationOwner == "" {
+		c.ApplicationOwner = "admin"
+	}
+	return nil
+}
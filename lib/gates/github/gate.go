@@ -0,0 +1,448 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Factory implements gates.GateFactory interface
+type Factory struct{}
+
+// Name shows name of the gate factory
+func (*Factory) Name() string {
+	return "github"
+}
+
+// NewGate creates new gate
+func (*Factory) NewGate() gates.Gate {
+	return &Gate{}
+}
+
+func init() {
+	gates.FactoryList = append(gates.FactoryList, &Factory{})
+}
+
+// Gate implements gates.Gate interface
+type Gate struct {
+	cfg    Config
+	fish   *fish.Fish
+	sv     *gates.Supervisor
+	client *http.Client
+
+	// The jobs Applications were created for, key is job ID. The state is not stored separately,
+	// but recovered from the active Applications metadata on start.
+	jobsMutex sync.Mutex
+	jobs      map[int64]*jobRecord
+
+	stop    chan struct{}
+	webhook *http.Server
+}
+
+// jobRecord stores the Application created for the job. The ephemeral runner could pick any
+// queued job with the matching labels, so the Application is tracked by the runner name.
+type jobRecord struct {
+	Repo       string
+	RunnerName string
+	AppUID     types.ApplicationUID // Empty while the Application is creating
+	Registered bool                 // The runner was seen registered in GitHub
+}
+
+// runnerMetadata is passed to the Application, the runner in the Resource is using Meta API to
+// get the info and register itself
+type runnerMetadata struct {
+	URL        string `json:"GITHUB_RUNNER_URL"`
+	Token      string `json:"GITHUB_RUNNER_TOKEN"`
+	Name       string `json:"GITHUB_RUNNER_NAME"`
+	Labels     string `json:"GITHUB_RUNNER_LABELS"`
+	Ephemeral  string `json:"GITHUB_RUNNER_EPHEMERAL"`
+	Repository string `json:"GITHUB_REPOSITORY"`
+	JobID      string `json:"GITHUB_JOB_ID"`
+}
+
+// Name returns name of the gate
+func (*Gate) Name() string {
+	return "github"
+}
+
+// Init validates config and starts the jobs processing
+func (g *Gate) Init(f *fish.Fish, config []byte, sv *gates.Supervisor) error {
+	if err := g.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := g.cfg.Validate(); err != nil {
+		return err
+	}
+
+	g.fish = f
+	g.sv = sv
+	g.client = &http.Client{Timeout: 30 * time.Second}
+	g.jobs = make(map[int64]*jobRecord)
+	g.stop = make(chan struct{})
+
+	if err := g.restoreJobs(); err != nil {
+		return err
+	}
+
+	if g.cfg.WebhookAddress != "" {
+		listener, err := net.Listen("tcp", g.cfg.WebhookAddress)
+		if err != nil {
+			return fmt.Errorf("GITHUB: Unable to bind webhook to address %q: %v", g.cfg.WebhookAddress, err)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", g.webhookHandler)
+		g.webhook = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go g.webhook.Serve(listener)
+		log.Info("GITHUB: Webhook listening on:", listener.Addr())
+	}
+
+	sv.Go(g.pollProcess)
+
+	return nil
+}
+
+// Close stops the gate processes
+func (g *Gate) Close() {
+	close(g.stop)
+	if g.webhook != nil {
+		g.webhook.Close()
+	}
+}
+
+// restoreJobs recovers the served jobs from the active Applications after restart, so the
+// Applications will not be created twice for the same job or left without deallocation
+func (g *Gate) restoreJobs() error {
+	apps, err := g.fish.ApplicationFind(nil, false)
+	if err != nil {
+		return fmt.Errorf("GITHUB: Unable to list Applications to restore the jobs: %v", err)
+	}
+	for _, app := range apps {
+		if app.OwnerName != g.cfg.ApplicationOwner {
+			continue
+		}
+		var md runnerMetadata
+		if err := json.Unmarshal([]byte(app.Metadata), &md); err != nil || md.Name == "" || md.JobID == "" {
+			continue
+		}
+		if !util.Contains(g.cfg.Repositories, md.Repository) {
+			continue
+		}
+		jobID, err := strconv.ParseInt(md.JobID, 10, 64)
+		if err != nil {
+			continue
+		}
+		state, err := g.fish.ApplicationStateGetByApplication(app.UID)
+		if err != nil || !g.fish.ApplicationStateIsActive(state.Status) {
+			continue
+		}
+		g.jobs[jobID] = &jobRecord{Repo: md.Repository, RunnerName: md.Name, AppUID: app.UID}
+		log.Debugf("GITHUB: Restored Application %s of %s job %d", app.UID, md.Repository, jobID)
+	}
+	return nil
+}
+
+// pollProcess checks the queued jobs and the status of the served ones
+func (g *Gate) pollProcess() {
+	interval := time.Duration(g.cfg.PollInterval)
+	if interval < 0 {
+		// Polling of the new jobs is disabled, but still need to check the served ones
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if g.cfg.PollInterval > 0 {
+			for _, repo := range g.cfg.Repositories {
+				jobs, err := g.listQueuedJobs(repo)
+				if err != nil {
+					log.Errorf("GITHUB: Unable to get queued jobs for %s: %v", repo, err)
+					continue
+				}
+				for _, job := range jobs {
+					g.jobQueued(repo, job)
+				}
+			}
+		}
+		g.checkServedJobs()
+
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// matchLabel returns Fish Label for the job or nil if the job should not be served by the gate
+func (g *Gate) matchLabel(job workflowJob) *types.Label {
+	if !util.Contains(job.Labels, "self-hosted") {
+		return nil
+	}
+	for _, l := range job.Labels {
+		if l == "self-hosted" {
+			continue
+		}
+		name := l
+		if g.cfg.LabelPrefix != "" {
+			if !strings.HasPrefix(l, g.cfg.LabelPrefix) {
+				continue
+			}
+			name = strings.TrimPrefix(l, g.cfg.LabelPrefix)
+		}
+		if label, err := g.fish.LabelGetLatest(name); err == nil {
+			return label
+		}
+	}
+	return nil
+}
+
+// jobQueued creates Application for the queued job if it matches the Fish Label
+func (g *Gate) jobQueued(repo string, job workflowJob) {
+	// Reserving the job to not process it twice, the lock is not held during the requests
+	g.jobsMutex.Lock()
+	if _, ok := g.jobs[job.ID]; ok {
+		g.jobsMutex.Unlock()
+		return
+	}
+	rec := &jobRecord{Repo: repo, RunnerName: fmt.Sprintf("fish-%d", job.ID)}
+	g.jobs[job.ID] = rec
+	g.jobsMutex.Unlock()
+
+	appUID, err := g.createApplication(repo, rec.RunnerName, job)
+
+	g.jobsMutex.Lock()
+	defer g.jobsMutex.Unlock()
+	if err != nil || appUID == uuid.Nil {
+		if err != nil {
+			log.Errorf("GITHUB: Unable to serve %s job %d: %v", repo, job.ID, err)
+		}
+		delete(g.jobs, job.ID)
+		return
+	}
+	rec.AppUID = appUID
+	g.sv.Annotate(appUID, "job", fmt.Sprintf("GitHub %s job %d queued", repo, job.ID),
+		map[string]any{"repository": repo, "run_id": job.RunID, "job_id": job.ID, "job_name": job.Name})
+}
+
+// createApplication requests the Resource for the job runner, returns empty UID if the job
+// should not be served by the gate
+func (g *Gate) createApplication(repo, runnerName string, job workflowJob) (types.ApplicationUID, error) {
+	label := g.matchLabel(job)
+	if label == nil {
+		return uuid.Nil, nil
+	}
+
+	token, err := g.registrationToken(repo)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("unable to get runner registration token: %v", err)
+	}
+
+	metadata, err := json.Marshal(runnerMetadata{
+		URL:        g.repoURL(repo),
+		Token:      token,
+		Name:       runnerName,
+		Labels:     strings.Join(job.Labels, ","),
+		Ephemeral:  "true",
+		Repository: repo,
+		JobID:      fmt.Sprint(job.ID),
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("unable to prepare Application metadata: %v", err)
+	}
+
+	app := &types.Application{
+		LabelUID:  label.UID,
+		OwnerName: g.cfg.ApplicationOwner,
+		Metadata:  util.UnparsedJSON(metadata),
+	}
+	if err := g.fish.ApplicationCreate(context.Background(), app); err != nil {
+		return uuid.Nil, fmt.Errorf("unable to create Application: %v", err)
+	}
+	log.Infof("GITHUB: Created Application %s with Label %s:%d for %s job %d", app.UID, label.Name, label.Version, repo, job.ID)
+	return app.UID, nil
+}
+
+// runnerCompleted deallocates the Application of the runner which is done
+func (g *Gate) runnerCompleted(jobID int64, reason string) {
+	g.jobsMutex.Lock()
+	rec, ok := g.jobs[jobID]
+	if !ok || rec.AppUID == uuid.Nil {
+		g.jobsMutex.Unlock()
+		return
+	}
+	delete(g.jobs, jobID)
+	g.jobsMutex.Unlock()
+
+	app, err := g.fish.ApplicationGet(rec.AppUID)
+	if err != nil {
+		log.Error("GITHUB: Unable to find Application:", rec.AppUID, err)
+		return
+	}
+	if _, err := g.fish.ApplicationDeallocate(app, "github"); err != nil {
+		log.Error("GITHUB: Unable to deallocate Application:", rec.AppUID, err)
+		return
+	}
+	log.Infof("GITHUB: Runner %s %s, deallocating Application %s", rec.RunnerName, reason, rec.AppUID)
+}
+
+// runnerJobCompleted deallocates the Application of the runner which completed the job
+func (g *Gate) runnerJobCompleted(runnerName string) {
+	g.jobsMutex.Lock()
+	var jobID int64
+	for id, rec := range g.jobs {
+		if rec.RunnerName == runnerName {
+			jobID = id
+			break
+		}
+	}
+	g.jobsMutex.Unlock()
+	if jobID != 0 {
+		g.runnerCompleted(jobID, "completed the job")
+	}
+}
+
+// runnerDone decides if the ephemeral runner has nothing to do anymore: it completed a job and
+// deregistered itself or the job it was created for was taken by another runner
+func runnerDone(registered bool, runner *selfHostedRunner, job workflowJob) bool {
+	if runner == nil {
+		// Not registered yet runner is still booting, so waiting while its job is queued
+		return registered || job.Status != "queued"
+	}
+	return !runner.Busy && job.Status != "queued"
+}
+
+// checkServedJobs looks for the completed runners and the Applications which are not active anymore
+func (g *Gate) checkServedJobs() {
+	g.jobsMutex.Lock()
+	jobs := make(map[int64]jobRecord, len(g.jobs))
+	for id, rec := range g.jobs {
+		if rec.AppUID != uuid.Nil {
+			jobs[id] = *rec
+		}
+	}
+	g.jobsMutex.Unlock()
+
+	for id, rec := range jobs {
+		state, err := g.fish.ApplicationStateGetByApplication(rec.AppUID)
+		if err == nil && !g.fish.ApplicationStateIsActive(state.Status) {
+			log.Debugf("GITHUB: Application %s of job %d is not active anymore: %s", rec.AppUID, id, state.Status)
+			g.jobsMutex.Lock()
+			delete(g.jobs, id)
+			g.jobsMutex.Unlock()
+			continue
+		}
+		runner, err := g.getRunner(rec.Repo, rec.RunnerName)
+		if err != nil {
+			log.Errorf("GITHUB: Unable to get %s runner %s: %v", rec.Repo, rec.RunnerName, err)
+			continue
+		}
+		if runner != nil && !rec.Registered {
+			g.jobsMutex.Lock()
+			if r, ok := g.jobs[id]; ok {
+				r.Registered = true
+			}
+			g.jobsMutex.Unlock()
+		}
+		if runner != nil && runner.Busy {
+			g.sv.Annotate(rec.AppUID, "runner", fmt.Sprintf("GitHub runner %s is running a job", rec.RunnerName), nil)
+			continue
+		}
+		if runner != nil {
+			g.sv.Annotate(rec.AppUID, "runner", fmt.Sprintf("GitHub runner %s is %s and idle", rec.RunnerName, runner.Status), nil)
+		}
+		job, err := g.getJob(rec.Repo, id)
+		if err != nil {
+			log.Errorf("GITHUB: Unable to get %s job %d: %v", rec.Repo, id, err)
+			continue
+		}
+		if runnerDone(rec.Registered || runner != nil, runner, job) {
+			g.runnerCompleted(id, "has nothing to do")
+		}
+	}
+}
+
+// webhookHandler processes the `workflow_job` events
+func (g *Gate) webhookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Verifying the payload signature
+	mac := hmac.New(sha256.New, []byte(g.cfg.WebhookSecret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Hub-Signature-256"))) {
+		log.Warn("GITHUB: Webhook signature mismatch from:", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.Header.Get("X-GitHub-Event") != "workflow_job" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var event struct {
+		Action      string      `json:"action"`
+		WorkflowJob workflowJob `json:"workflow_job"`
+		Repository  struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !util.Contains(g.cfg.Repositories, event.Repository.FullName) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch event.Action {
+	case "queued":
+		g.jobQueued(event.Repository.FullName, event.WorkflowJob)
+	case "completed":
+		// The ephemeral runner could pick any matching job, so looking for it by name
+		if event.WorkflowJob.RunnerName != "" {
+			g.runnerJobCompleted(event.WorkflowJob.RunnerName)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Verify the runner is found by name and the missing one is reported as not registered
+func Test_get_runner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/actions/runners" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("name") == "fish-1" {
+			w.Write([]byte(`{"total_count":1,"runners":[{"id":10,"name":"fish-1","status":"online","busy":true}]}`))
+			return
+		}
+		w.Write([]byte(`{"total_count":0,"runners":[]}`))
+	}))
+	defer srv.Close()
+
+	g := &Gate{cfg: Config{APIURL: srv.URL, Token: "token"}, client: srv.Client()}
+
+	runner, err := g.getRunner("owner/repo", "fish-1")
+	if err != nil || runner == nil || !runner.Busy {
+		t.Fatalf("getRunner() should return busy runner: %+v, %v", runner, err)
+	}
+	runner, err = g.getRunner("owner/repo", "fish-2")
+	if err != nil || runner != nil {
+		t.Fatalf("getRunner() should return no runner: %+v, %v", runner, err)
+	}
+	if _, err = g.getRunner("other/repo", "fish-1"); err == nil {
+		t.Fatalf("getRunner() should fail on API error")
+	}
+}
+
+// Verify the runner Application is released only when the runner has nothing to do
+func Test_runner_done(t *testing.T) {
+	queued := workflowJob{ID: 1, Status: "queued"}
+	taken := workflowJob{ID: 1, Status: "in_progress", RunnerName: "other"}
+	idle := &selfHostedRunner{Name: "fish-1"}
+	busy := &selfHostedRunner{Name: "fish-1", Busy: true}
+
+	tests := []struct {
+		name       string
+		registered bool
+		runner     *selfHostedRunner
+		job        workflowJob
+		want       bool
+	}{
+		{"booting runner with queued job", false, nil, queued, false},
+		{"booting runner with taken job", false, nil, taken, true},
+		{"idle runner with queued job", true, idle, queued, false},
+		{"idle runner with taken job", true, idle, taken, true},
+		{"busy runner with taken job", true, busy, taken, false},
+		{"deregistered runner", true, nil, queued, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runnerDone(tt.registered, tt.runner, tt.job); got != tt.want {
+				t.Fatalf("runnerDone() = %v; want: %v", got, tt.want)
+			}
+		})
+	}
+}
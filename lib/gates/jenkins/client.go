@@ -0,0 +1,141 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package jenkins
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errNotFound is returned when Jenkins responded with 404, for example the agent was removed
+var errNotFound = errors.New("JENKINS: Not found")
+
+// agentInfo is the part of Jenkins computer info we need
+type agentInfo struct {
+	Idle                  bool  `json:"idle"`
+	Offline               bool  `json:"offline"`
+	IdleStartMilliseconds int64 `json:"idleStartMilliseconds"`
+}
+
+// request executes Jenkins API request with the form body and returns the response body
+func (g *Gate) request(method, path string, form url.Values) ([]byte, error) {
+	var body io.Reader = http.NoBody
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, g.cfg.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(g.cfg.Username, g.cfg.APIToken)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		if len(data) > 1024 {
+			data = data[:1024]
+		}
+		return nil, fmt.Errorf("JENKINS: API %s %s returned %d: %s", method, path, resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// createAgent creates the permanent JNLP agent node in Jenkins
+func (g *Gate) createAgent(name, labels string) error {
+	node, err := json.Marshal(map[string]any{
+		"name":            name,
+		"nodeDescription": "Aquarium Fish agent",
+		"numExecutors":    fmt.Sprint(g.cfg.AgentExecutors),
+		"remoteFS":        g.cfg.AgentWorkdir,
+		"labelString":     labels,
+		"mode":            "EXCLUSIVE",
+		"type":            "hudson.slaves.DumbSlave",
+		"launcher": map[string]any{
+			"stapler-class": "hudson.slaves.JNLPLauncher",
+			"$class":        "hudson.slaves.JNLPLauncher",
+		},
+		"retentionStrategy": map[string]any{
+			"stapler-class": "hudson.slaves.RetentionStrategy$Always",
+			"$class":        "hudson.slaves.RetentionStrategy$Always",
+		},
+		"nodeProperties": map[string]any{"stapler-class-bag": "true"},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = g.request(http.MethodPost, "/computer/doCreateItem", url.Values{
+		"name": {name},
+		"type": {"hudson.slaves.DumbSlave"},
+		"json": {string(node)},
+	})
+	return err
+}
+
+// agentSecret returns the JNLP secret the agent need to connect to Jenkins
+func (g *Gate) agentSecret(name string) (string, error) {
+	data, err := g.request(http.MethodGet, "/computer/"+url.PathEscape(name)+"/jenkins-agent.jnlp", nil)
+	if err != nil {
+		return "", err
+	}
+	return parseJNLPSecret(data)
+}
+
+// parseJNLPSecret gets the secret from the agent JNLP file, it's the first application argument
+func parseJNLPSecret(data []byte) (string, error) {
+	var jnlp struct {
+		Arguments []string `xml:"application-desc>argument"`
+	}
+	if err := xml.Unmarshal(data, &jnlp); err != nil {
+		return "", fmt.Errorf("JENKINS: Unable to parse agent JNLP: %v", err)
+	}
+	if len(jnlp.Arguments) == 0 || jnlp.Arguments[0] == "" {
+		return "", fmt.Errorf("JENKINS: Agent JNLP does not contain secret")
+	}
+	return jnlp.Arguments[0], nil
+}
+
+// getAgent returns the current state of the agent
+func (g *Gate) getAgent(name string) (info agentInfo, err error) {
+	data, err := g.request(http.MethodGet, "/computer/"+url.PathEscape(name)+"/api/json?tree=idle,offline,idleStartMilliseconds", nil)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// deleteAgent removes the agent node from Jenkins
+func (g *Gate) deleteAgent(name string) error {
+	_, err := g.request(http.MethodPost, "/computer/"+url.PathEscape(name)+"/doDelete", url.Values{})
+	return err
+}
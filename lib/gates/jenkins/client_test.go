@@ -0,0 +1,42 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package jenkins
+
+import (
+	"testing"
+)
+
+// Verify the secret is taken from the agent JNLP file
+func Test_parse_jnlp_secret(t *testing.T) {
+	jnlp := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<jnlp codebase="https://jenkins.example.com/computer/fish-test/" spec="1.0+">
+  <information><title>Agent for fish-test</title></information>
+  <application-desc main-class="hudson.remoting.jnlp.Main">
+    <argument>0123456789abcdef</argument>
+    <argument>fish-test</argument>
+    <argument>-workDir</argument>
+    <argument>/home/jenkins</argument>
+  </application-desc>
+</jnlp>`)
+	secret, err := parseJNLPSecret(jnlp)
+	if err != nil {
+		t.Fatalf("Unable to parse JNLP: %v", err)
+	}
+	if secret != "0123456789abcdef" {
+		t.Fatalf("parseJNLPSecret() = `%s`; want: `0123456789abcdef`", secret)
+	}
+
+	if _, err := parseJNLPSecret([]byte(`<jnlp><application-desc/></jnlp>`)); err == nil {
+		t.Fatalf("parseJNLPSecret() of JNLP without arguments should fail")
+	}
+}
@@ -0,0 +1,97 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package jenkins implements gate which provisions Jenkins agents on Fish Resources
+package jenkins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - gate configuration
+type Config struct {
+	URL      string `json:"url"`       // Jenkins controller URL (like "https://jenkins.example.com")
+	Username string `json:"username"`  // Jenkins user with permissions to create and delete agents
+	APIToken string `json:"api_token"` // Jenkins user API token, if empty - JENKINS_API_TOKEN env var is used
+
+	// Where to listen for the provisioning requests from Jenkins and the token Jenkins should use
+	// to authenticate as "Authorization: Bearer <token>"
+	Address string `json:"address"`
+	Token   string `json:"token"`
+
+	IdleTimeout   util.Duration `json:"idle_timeout"`   // How long agent could stay idle before deallocation, 10m by default
+	CheckInterval util.Duration `json:"check_interval"` // How often to check the agents state, 30s by default
+
+	AgentWorkdir   string `json:"agent_workdir"`   // Remote root directory of the agent, "/home/jenkins" by default
+	AgentExecutors int    `json:"agent_executors"` // Number of executors on the agent, 1 by default
+
+	ApplicationOwner string `json:"application_owner"` // Fish user to own the created Applications, "admin" by default
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("JENKINS: Unable to apply the gate config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	c.URL = strings.TrimSuffix(c.URL, "/")
+	if c.URL == "" {
+		return fmt.Errorf("JENKINS: URL is not set")
+	}
+	if c.Username == "" {
+		return fmt.Errorf("JENKINS: Username is not set")
+	}
+	if c.APIToken == "" {
+		c.APIToken = os.Getenv("JENKINS_API_TOKEN")
+	}
+	if c.APIToken == "" {
+		return fmt.Errorf("JENKINS: API token is not set")
+	}
+	if c.Address == "" {
+		return fmt.Errorf("JENKINS: Address to listen for provisioning requests is not set")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("JENKINS: Token to authenticate the provisioning requests is not set")
+	}
+	if c.IdleTimeout < 0 {
+		return fmt.Errorf("JENKINS: Idle timeout can't be negative: %s", time.Duration(c.IdleTimeout))
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = util.Duration(10 * time.Minute)
+	}
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = util.Duration(30 * time.Second)
+	}
+	if c.AgentWorkdir == "" {
+		c.AgentWorkdir = "/home/jenkins"
+	}
+	if c.AgentExecutors < 1 {
+		c.AgentExecutors = 1
+	}
+	if c.ApplicationOwner == "" {
+		c.ApplicationOwner = "admin"
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package jenkins
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Max amount of agents Jenkins could request at once
+const provisionMaxCount = 20
+
+// Factory implements gates.GateFactory interface
+type Factory struct{}
+
+// Name shows name of the gate factory
+func (*Factory) Name() string {
+	return "jenkins"
+}
+
+// NewGate creates new gate
+func (*Factory) NewGate() gates.Gate {
+	return &Gate{}
+}
+
+func init() {
+	gates.FactoryList = append(gates.FactoryList, &Factory{})
+}
+
+// Gate implements gates.Gate interface
+type Gate struct {
+	cfg    Config
+	fish   *fish.Fish
+	sv     *gates.Supervisor
+	client *http.Client
+
+	// The agents Applications were created for, key is agent name. The state is not stored
+	// separately, but recovered from the active Applications metadata on start.
+	agentsMutex sync.Mutex
+	agents      map[string]types.ApplicationUID
+
+	stop   chan struct{}
+	server *http.Server
+}
+
+// agentMetadata is passed to the Application, the driver puts it to the Resource userdata
+type agentMetadata struct {
+	URL     string `json:"JENKINS_URL"`
+	Name    string `json:"JENKINS_AGENT_NAME"`
+	Secret  string `json:"JENKINS_AGENT_SECRET"`
+	Workdir string `json:"JENKINS_AGENT_WORKDIR"`
+}
+
+// provisionRequest is sent by Jenkins to get new agents
+type provisionRequest struct {
+	Label string `json:"label"` // Fish Label name, will be used as Jenkins agent label too
+	Count int    `json:"count"` // How many agents to provision, 1 by default
+}
+
+// provisionedAgent is returned to Jenkins to track the provisioned agents
+type provisionedAgent struct {
+	Name           string               `json:"name"`
+	ApplicationUID types.ApplicationUID `json:"application_uid"`
+}
+
+// Name returns name of the gate
+func (*Gate) Name() string {
+	return "jenkins"
+}
+
+// Init validates config and starts the provisioning endpoint
+func (g *Gate) Init(f *fish.Fish, config []byte, sv *gates.Supervisor) error {
+	if err := g.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := g.cfg.Validate(); err != nil {
+		return err
+	}
+
+	g.fish = f
+	g.sv = sv
+	g.client = &http.Client{Timeout: 30 * time.Second}
+	g.agents = make(map[string]types.ApplicationUID)
+	g.stop = make(chan struct{})
+
+	if err := g.restoreAgents(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", g.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("JENKINS: Unable to bind to address %q: %v", g.cfg.Address, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/provision", g.provisionHandler)
+	g.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go g.server.Serve(listener)
+	log.Info("JENKINS: Provisioning endpoint listening on:", listener.Addr())
+
+	sv.Go(g.checkProcess)
+
+	return nil
+}
+
+// Close stops the gate processes
+func (g *Gate) Close() {
+	close(g.stop)
+	g.server.Close()
+}
+
+// restoreAgents recovers the provisioned agents from the active Applications after restart, so
+// the idle agents will be deallocated and removed from Jenkins
+func (g *Gate) restoreAgents() error {
+	apps, err := g.fish.ApplicationFind(nil, false)
+	if err != nil {
+		return fmt.Errorf("JENKINS: Unable to list Applications to restore the agents: %v", err)
+	}
+	for _, app := range apps {
+		if app.OwnerName != g.cfg.ApplicationOwner {
+			continue
+		}
+		var md agentMetadata
+		if err := json.Unmarshal([]byte(app.Metadata), &md); err != nil || md.Name == "" || md.URL != g.cfg.URL {
+			continue
+		}
+		state, err := g.fish.ApplicationStateGetByApplication(app.UID)
+		if err != nil || !g.fish.ApplicationStateIsActive(state.Status) {
+			continue
+		}
+		g.agents[md.Name] = app.UID
+		log.Debugf("JENKINS: Restored agent %s of Application %s", md.Name, app.UID)
+	}
+	return nil
+}
+
+// isAuthorized checks the Authorization header contains the bearer token
+func isAuthorized(header, token string) bool {
+	value, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(token)) == 1
+}
+
+// provisionHandler creates Jenkins agents and Applications to run them
+func (g *Gate) provisionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAuthorized(r.Header.Get("Authorization"), g.cfg.Token) {
+		log.Warn("JENKINS: Provisioning request with wrong token from:", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req provisionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1024*1024)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Wrong request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Count < 1 {
+		req.Count = 1
+	}
+	if req.Count > provisionMaxCount {
+		http.Error(w, fmt.Sprintf("Too many agents requested, max is %d", provisionMaxCount), http.StatusBadRequest)
+		return
+	}
+	label, err := g.fish.LabelGetLatest(req.Label)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to find Label %q", req.Label), http.StatusBadRequest)
+		return
+	}
+
+	var out []provisionedAgent
+	for i := 0; i < req.Count; i++ {
+		agent, err := g.provisionAgent(label)
+		if err != nil {
+			log.Error("JENKINS: Unable to provision agent:", err)
+			break
+		}
+		out = append(out, agent)
+	}
+	if len(out) == 0 {
+		http.Error(w, "Unable to provision agents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"agents": out})
+}
+
+// provisionAgent creates the agent in Jenkins and Application which will connect to it
+func (g *Gate) provisionAgent(label *types.Label) (agent provisionedAgent, err error) {
+	agent.Name = "fish-" + crypt.RandStringCharset(8, "0123456789abcdefghijklmnopqrstuvwxyz")
+	if err := g.createAgent(agent.Name, label.Name); err != nil {
+		return agent, fmt.Errorf("JENKINS: Unable to create agent %s: %v", agent.Name, err)
+	}
+	defer func() {
+		if err != nil {
+			if err := g.deleteAgent(agent.Name); err != nil {
+				log.Error("JENKINS: Unable to cleanup agent:", agent.Name, err)
+			}
+		}
+	}()
+
+	secret, err := g.agentSecret(agent.Name)
+	if err != nil {
+		return agent, fmt.Errorf("JENKINS: Unable to get agent %s secret: %v", agent.Name, err)
+	}
+
+	// The secret is passed to the Resource as metadata, so driver puts it to the userdata
+	metadata, err := json.Marshal(agentMetadata{
+		URL:     g.cfg.URL,
+		Name:    agent.Name,
+		Secret:  secret,
+		Workdir: g.cfg.AgentWorkdir,
+	})
+	if err != nil {
+		return agent, fmt.Errorf("JENKINS: Unable to prepare Application metadata: %v", err)
+	}
+
+	app := &types.Application{
+		LabelUID:  label.UID,
+		OwnerName: g.cfg.ApplicationOwner,
+		Metadata:  util.UnparsedJSON(metadata),
+	}
+	if err = g.fish.ApplicationCreate(context.Background(), app); err != nil {
+		return agent, fmt.Errorf("JENKINS: Unable to create Application for agent %s: %v", agent.Name, err)
+	}
+	agent.ApplicationUID = app.UID
+
+	g.agentsMutex.Lock()
+	g.agents[agent.Name] = app.UID
+	g.agentsMutex.Unlock()
+	log.Infof("JENKINS: Created Application %s with Label %s:%d for agent %s", app.UID, label.Name, label.Version, agent.Name)
+
+	return agent, nil
+}
+
+// checkProcess periodically looks for the idle agents to deallocate
+func (g *Gate) checkProcess() {
+	ticker := time.NewTicker(time.Duration(g.cfg.CheckInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+		}
+
+		g.agentsMutex.Lock()
+		agents := make(map[string]types.ApplicationUID, len(g.agents))
+		for name, appUID := range g.agents {
+			agents[name] = appUID
+		}
+		g.agentsMutex.Unlock()
+
+		for name, appUID := range agents {
+			g.checkAgent(name, appUID)
+		}
+	}
+}
+
+// checkAgent deallocates the Application if agent is idle for too long and removes the agent
+// from Jenkins when Application is not active anymore
+func (g *Gate) checkAgent(name string, appUID types.ApplicationUID) {
+	state, err := g.fish.ApplicationStateGetByApplication(appUID)
+	if err != nil {
+		log.Error("JENKINS: Unable to get Application state:", appUID, err)
+		return
+	}
+	if !g.fish.ApplicationStateIsActive(state.Status) {
+		log.Debugf("JENKINS: Application %s of agent %s is not active anymore: %s", appUID, name, state.Status)
+		g.removeAgent(name)
+		return
+	}
+
+	info, err := g.getAgent(name)
+	if errors.Is(err, errNotFound) {
+		log.Infof("JENKINS: Agent %s was removed from Jenkins, deallocating Application %s", name, appUID)
+		if g.deallocate(appUID) {
+			g.removeAgent(name)
+		}
+		return
+	}
+	if err != nil {
+		log.Error("JENKINS: Unable to get agent info:", name, err)
+		return
+	}
+
+	// Agent is offline until the Resource will be allocated and connected to Jenkins
+	if info.Offline {
+		g.sv.Annotate(appUID, "agent", fmt.Sprintf("Jenkins agent %s is offline", name), nil)
+		return
+	}
+	if !info.Idle {
+		g.sv.Annotate(appUID, "agent", fmt.Sprintf("Jenkins agent %s is running a job", name), nil)
+		return
+	}
+	idleSince := time.UnixMilli(info.IdleStartMilliseconds)
+	g.sv.Annotate(appUID, "agent", fmt.Sprintf("Jenkins agent %s idle since %s", name, idleSince.Format("15:04")),
+		map[string]any{"idle_since": idleSince})
+	idle := time.Since(idleSince)
+	if idle < time.Duration(g.cfg.IdleTimeout) {
+		return
+	}
+	log.Infof("JENKINS: Agent %s is idle for %s, deallocating Application %s", name, idle.Round(time.Second), appUID)
+	// Keeping the agent tracked to retry on the next check if deallocation failed
+	if g.deallocate(appUID) {
+		g.removeAgent(name)
+	}
+}
+
+// deallocate requests Application deallocation and returns true if it was requested
+func (g *Gate) deallocate(appUID types.ApplicationUID) bool {
+	app, err := g.fish.ApplicationGet(appUID)
+	if err != nil {
+		log.Error("JENKINS: Unable to find Application:", appUID, err)
+		return false
+	}
+	if _, err := g.fish.ApplicationDeallocate(app, "jenkins"); err != nil {
+		log.Error("JENKINS: Unable to deallocate Application:", appUID, err)
+		return false
+	}
+	return true
+}
+
+// removeAgent deletes the agent from Jenkins and stops tracking it
+func (g *Gate) removeAgent(name string) {
+	if err := g.deleteAgent(name); err != nil && !errors.Is(err, errNotFound) {
+		log.Error("JENKINS: Unable to delete agent:", name, err)
+		return
+	}
+	g.agentsMutex.Lock()
+	delete(g.agents, name)
+	g.agentsMutex.Unlock()
+}
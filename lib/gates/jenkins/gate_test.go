@@ -0,0 +1,47 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package jenkins
+
+import (
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Verify only the bearer token is accepted
+func Test_is_authorized(t *testing.T) {
+	if !isAuthorized("Bearer secret", "secret") {
+		t.Fatalf("isAuthorized() should accept the bearer token")
+	}
+	if isAuthorized("secret", "secret") {
+		t.Fatalf("isAuthorized() should not accept the raw token")
+	}
+	if isAuthorized("Bearer wrong", "secret") {
+		t.Fatalf("isAuthorized() should not accept the wrong token")
+	}
+	if isAuthorized("", "secret") {
+		t.Fatalf("isAuthorized() should not accept the empty header")
+	}
+}
+
+// Verify the negative idle timeout is rejected
+func Test_config_idle_timeout(t *testing.T) {
+	cfg := Config{URL: "https://jenkins", Username: "fish", APIToken: "token", Address: ":0", Token: "token"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() of the valid config failed: %v", err)
+	}
+	cfg.IdleTimeout = util.Duration(-1)
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("Validate() should reject negative idle timeout")
+	}
+}
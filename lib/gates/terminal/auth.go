@@ -0,0 +1,68 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package terminal
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// tokenClaims are the claims of the Web UI token, the subject is the Fish user name
+type tokenClaims struct {
+	jwt.StandardClaims
+}
+
+// requestToken returns the token from the Authorization header or from the query parameter,
+// since the browser websocket API can't set the headers
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if strings.HasPrefix(auth, "Bearer ") {
+			return strings.TrimPrefix(auth, "Bearer ")
+		}
+		return ""
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// verifyToken checks the token signature & claims and returns the user name
+func verifyToken(secret, issuer, token string, now time.Time) (string, error) {
+	if token == "" {
+		return "", fmt.Errorf("Token is not provided")
+	}
+	var claims tokenClaims
+	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Alg()}, SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}); err != nil {
+		return "", fmt.Errorf("Token is not valid: %v", err)
+	}
+
+	// Validating the claims here to use the provided time and require expiration
+	if !claims.VerifyExpiresAt(now.Unix(), true) {
+		return "", fmt.Errorf("Token is expired or has no expiration")
+	}
+	if !claims.VerifyNotBefore(now.Unix(), false) {
+		return "", fmt.Errorf("Token is not valid yet")
+	}
+	if issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return "", fmt.Errorf("Token issuer %q is not allowed", claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return "", fmt.Errorf("Token subject is not set")
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,83 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package terminal
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func testToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.StandardClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("Unable to sign token: %v", err)
+	}
+	return token
+}
+
+// Verify only the properly signed unexpired token of the right issuer is accepted
+func Test_verify_token(t *testing.T) {
+	now := time.Now()
+	valid := jwt.StandardClaims{Subject: "user", Issuer: "webui", ExpiresAt: now.Add(time.Minute).Unix()}
+
+	name, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodHS256, []byte(testSecret), valid), now)
+	if err != nil || name != "user" {
+		t.Fatalf("verifyToken() of the valid token failed: %q, %v", name, err)
+	}
+
+	expired := valid
+	expired.ExpiresAt = now.Add(-time.Minute).Unix()
+	if _, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodHS256, []byte(testSecret), expired), now); err == nil {
+		t.Fatalf("verifyToken() of expired token should fail")
+	}
+	noExp := valid
+	noExp.ExpiresAt = 0
+	if _, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodHS256, []byte(testSecret), noExp), now); err == nil {
+		t.Fatalf("verifyToken() of token without expiration should fail")
+	}
+	if _, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodHS256, []byte("wrong-secret-wrong-secret-wrong!"), valid), now); err == nil {
+		t.Fatalf("verifyToken() of token with wrong secret should fail")
+	}
+	if _, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid), now); err == nil {
+		t.Fatalf("verifyToken() of unsigned token should fail")
+	}
+	if _, err := verifyToken(testSecret, "other", testToken(t, jwt.SigningMethodHS256, []byte(testSecret), valid), now); err == nil {
+		t.Fatalf("verifyToken() of token with wrong issuer should fail")
+	}
+	noSub := valid
+	noSub.Subject = ""
+	if _, err := verifyToken(testSecret, "webui", testToken(t, jwt.SigningMethodHS256, []byte(testSecret), noSub), now); err == nil {
+		t.Fatalf("verifyToken() of token without subject should fail")
+	}
+}
+
+// Verify the token is taken only from the Bearer header or query parameter
+func Test_request_token(t *testing.T) {
+	r := httptest.NewRequest("GET", "/terminal/app?access_token=query", nil)
+	if token := requestToken(r); token != "query" {
+		t.Fatalf("requestToken() should return query token: %q", token)
+	}
+	r.Header.Set("Authorization", "Bearer header")
+	if token := requestToken(r); token != "header" {
+		t.Fatalf("requestToken() should prefer header token: %q", token)
+	}
+	r.Header.Set("Authorization", "header")
+	if token := requestToken(r); token != "" {
+		t.Fatalf("requestToken() should not accept raw token in header: %q", token)
+	}
+}
@@ -0,0 +1,60 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package terminal implements gate which provides the web terminal to the allocated Resources
+// for the users authenticated by the JWT issued by the Web UI
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - gate configuration
+type Config struct {
+	Address string `json:"address"` // Where to listen for the websocket connections
+
+	JWTSecret string `json:"jwt_secret"` // HS256 secret shared with the Web UI to verify the tokens
+	JWTIssuer string `json:"jwt_issuer"` // Expected issuer of the token, not checked if empty
+
+	RecordPath      string        `json:"record_path"`      // Where to store the terminal sessions recordings, empty disables recording
+	RecordRetention util.Duration `json:"record_retention"` // How long to keep the recordings, 0 keeps them forever
+	RecordInput     bool          `json:"record_input"`     // Record the user input too, it could contain secrets
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("TERMINAL: Unable to apply the gate config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("TERMINAL: Address to listen is not set")
+	}
+	if len(c.JWTSecret) < 32 {
+		return fmt.Errorf("TERMINAL: JWT secret should be at least 32 characters long")
+	}
+	if c.RecordRetention < 0 {
+		return fmt.Errorf("TERMINAL: Record retention can't be negative: %s", time.Duration(c.RecordRetention))
+	}
+	return nil
+}
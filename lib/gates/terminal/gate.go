@@ -0,0 +1,172 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package terminal
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/proxyssh"
+)
+
+// Factory implements gates.GateFactory interface
+type Factory struct{}
+
+// Name shows name of the gate factory
+func (*Factory) Name() string {
+	return "terminal"
+}
+
+// NewGate creates new gate
+func (*Factory) NewGate() gates.Gate {
+	return &Gate{}
+}
+
+func init() {
+	gates.FactoryList = append(gates.FactoryList, &Factory{})
+}
+
+// Gate implements gates.Gate interface
+type Gate struct {
+	cfg      Config
+	fish     *fish.Fish
+	terminal *proxyssh.Terminal
+
+	server *http.Server
+}
+
+// Name returns name of the gate
+func (*Gate) Name() string {
+	return "terminal"
+}
+
+// Init validates config and starts the terminal endpoint
+func (g *Gate) Init(f *fish.Fish, config []byte, _ *gates.Supervisor) error {
+	if err := g.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := g.cfg.Validate(); err != nil {
+		return err
+	}
+
+	g.fish = f
+	var err error
+	if g.terminal, err = proxyssh.NewTerminal(f, g.cfg.RecordPath, time.Duration(g.cfg.RecordRetention), g.cfg.RecordInput); err != nil {
+		return fmt.Errorf("TERMINAL: Unable to init terminal: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", g.cfg.Address)
+	if err != nil {
+		g.terminal.Close()
+		return fmt.Errorf("TERMINAL: Unable to bind to address %q: %v", g.cfg.Address, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/terminal/", g.terminalHandler)
+	g.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	certPath, keyPath := f.GetTLSCertKeyPaths()
+	go func() {
+		if err := g.server.ServeTLS(listener, certPath, keyPath); err != http.ErrServerClosed {
+			log.Error("TERMINAL: Unable to serve:", err)
+		}
+	}()
+	log.Info("TERMINAL: Listening on:", listener.Addr())
+
+	// Users will see the terminal in the Resource access methods list
+	f.AccessGateRegister(g.Name(), fmt.Sprintf("wss://%s/terminal/", listener.Addr()))
+
+	return nil
+}
+
+// Close stops the gate processes
+func (g *Gate) Close() {
+	g.fish.AccessGateUnregister(g.Name())
+	g.server.Close()
+	g.terminal.Close()
+}
+
+// terminalHandler authenticates the user and connects it to the Application Resource shell,
+// the path is "/terminal/<Application UID or short ID>"
+func (g *Gate) terminalHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	username, err := verifyToken(g.cfg.JWTSecret, g.cfg.JWTIssuer, requestToken(r), time.Now())
+	if err != nil {
+		log.Warnf("TERMINAL: Unauthorized request from %s: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if _, err := g.fish.UserGet(username); err != nil {
+		log.Warnf("TERMINAL: Unknown user %q from %s", username, r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	appUID, err := g.fish.ApplicationResolveUID(strings.TrimPrefix(r.URL.Path, "/terminal/"))
+	if err != nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	app, err := g.fish.ApplicationGet(appUID)
+	if err != nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	// Only the owner of the Application (or admin) can access the Resource terminal, operator
+	// role allows to manage the Resources, but not to access them
+	if app.OwnerName != username && username != "admin" {
+		http.Error(w, "Only the owner and admin can access the Application terminal", http.StatusForbidden)
+		return
+	}
+	if err := g.fish.ApplicationIsAllocated(app.UID); err != nil {
+		http.Error(w, "The Application is not allocated", http.StatusBadRequest)
+		return
+	}
+	// Sensitive Labels require the second factor in the handshake, the code is passed as query
+	// parameter since the browser websocket API can't set the headers
+	otp, err := g.fish.ApplicationAccessOTP(app.UID)
+	if err != nil {
+		log.Errorf("TERMINAL: Unable to check the Application %s access policy: %v", app.UID, err)
+		http.Error(w, "Unable to check the access policy", http.StatusInternalServerError)
+		return
+	}
+	if otp {
+		code := r.URL.Query().Get("otp")
+		if code == "" {
+			http.Error(w, "OTP code is required to access the Resource", http.StatusUnauthorized)
+			return
+		}
+		if err := g.fish.UserOTPCheck(username, code); err != nil {
+			log.Warnf("TERMINAL: User %q from %s failed OTP check: %v", username, r.RemoteAddr, err)
+			http.Error(w, "Unable to verify OTP", http.StatusUnauthorized)
+			return
+		}
+	}
+	res, err := g.fish.ResourceGetByApplication(app.UID)
+	if err != nil {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
+
+	// Web terminal provides just the shell, so it's allowed by any proxyssh policy
+	log.Infof("TERMINAL: User %q from %s opens terminal to Application %s", username, r.RemoteAddr, app.UID)
+	g.terminal.Serve(w, r, res, username)
+}
@@ -0,0 +1,82 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package webhook implements gate which allows external systems to request Resources by the
+// signed webhook events and reports the allocation state back to the callback URL
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config - gate configuration
+type Config struct {
+	Address string `json:"address"` // Where to listen for the webhook events
+
+	Clients []ClientConfig `json:"clients"` // The external systems allowed to send events
+
+	MaxCount      int           `json:"max_count"`      // Max amount of Resources in one event, 10 by default
+	CheckInterval util.Duration `json:"check_interval"` // How often to check the Applications state for callbacks, 10s by default
+	MaxClockSkew  util.Duration `json:"max_clock_skew"` // How old the event timestamp could be to protect from replay, 5m by default
+}
+
+// ClientConfig describes the external system and the service user it acts on behalf of
+type ClientConfig struct {
+	Name   string `json:"name"`   // Identifier of the client in X-Fish-Client header
+	Secret string `json:"secret"` // Key to sign the events and callbacks with HMAC-SHA256
+	User   string `json:"user"`   // Fish user which will own the created Applications
+}
+
+// Apply takes json and applies it to the config structure
+func (c *Config) Apply(config []byte) error {
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, c); err != nil {
+			return log.Error("WEBHOOK: Unable to apply the gate config:", err)
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the config have the required defaults & that the required fields are set
+func (c *Config) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("WEBHOOK: Address to listen is not set")
+	}
+	if len(c.Clients) == 0 {
+		return fmt.Errorf("WEBHOOK: No clients are configured")
+	}
+	names := make(map[string]bool)
+	for i, cl := range c.Clients {
+		if cl.Name == "" || cl.Secret == "" || cl.User == "" {
+			return fmt.Errorf("WEBHOOK: Client %d should have name, secret and user", i)
+		}
+		if names[cl.Name] {
+			return fmt.Errorf("WEBHOOK: Client %q is defined twice", cl.Name)
+		}
+		names[cl.Name] = true
+	}
+	if c.MaxCount < 1 {
+		c.MaxCount = 10
+	}
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = util.Duration(10 * time.Second)
+	}
+	if c.MaxClockSkew <= 0 {
+		c.MaxClockSkew = util.Duration(5 * time.Minute)
+	}
+	return nil
+}
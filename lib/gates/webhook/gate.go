@@ -0,0 +1,437 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/gates"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Factory implements gates.GateFactory interface
+type Factory struct{}
+
+// Name shows name of the gate factory
+func (*Factory) Name() string {
+	return "webhook"
+}
+
+// NewGate creates new gate
+func (*Factory) NewGate() gates.Gate {
+	return &Gate{}
+}
+
+func init() {
+	gates.FactoryList = append(gates.FactoryList, &Factory{})
+}
+
+// Gate implements gates.Gate interface
+type Gate struct {
+	cfg    Config
+	fish   *fish.Fish
+	client *http.Client
+
+	// The Applications to report the state to callback, recovered from the Applications
+	// metadata on start
+	trackedMutex sync.Mutex
+	tracked      map[types.ApplicationUID]*trackedApp
+
+	// The received events signatures to reject replays
+	replays replayCache
+
+	stop   chan struct{}
+	server *http.Server
+}
+
+// trackedApp stores the callback info of the Application
+type trackedApp struct {
+	Client      *ClientConfig
+	CallbackURL string
+	Reference   string
+	LastStatus  types.ApplicationStatus
+}
+
+// callbackMetadata is added to the Application metadata to recover the tracked Applications
+type callbackMetadata struct {
+	Client      string `json:"WEBHOOK_CLIENT"`
+	CallbackURL string `json:"WEBHOOK_CALLBACK_URL"`
+	Reference   string `json:"WEBHOOK_REFERENCE"`
+}
+
+// allocateEvent is sent by the external system to request the Resources
+type allocateEvent struct {
+	Label       string            `json:"label"`        // Label name, the latest version is used
+	LabelUID    types.LabelUID    `json:"label_uid"`    // Or the exact Label UID
+	Count       int               `json:"count"`        // How many Resources are needed, 1 by default
+	Metadata    util.UnparsedJSON `json:"metadata"`     // Application metadata
+	CallbackURL string            `json:"callback_url"` // Where to POST the Applications state changes
+	Reference   string            `json:"reference"`    // External identifier to return in callbacks
+}
+
+// deallocateEvent is sent by the external system to release the Resources
+type deallocateEvent struct {
+	Applications []types.ApplicationUID `json:"applications"`
+}
+
+// callbackEvent is sent to the callback URL when the Application state is changed
+type callbackEvent struct {
+	Reference      string                  `json:"reference"`
+	ApplicationUID types.ApplicationUID    `json:"application_uid"`
+	ShortID        string                  `json:"short_id"`
+	Status         types.ApplicationStatus `json:"status"`
+	Description    string                  `json:"description"`
+}
+
+// Name returns name of the gate
+func (*Gate) Name() string {
+	return "webhook"
+}
+
+// Init validates config and starts the webhook endpoint
+func (g *Gate) Init(f *fish.Fish, config []byte, sv *gates.Supervisor) error {
+	if err := g.cfg.Apply(config); err != nil {
+		return err
+	}
+	if err := g.cfg.Validate(); err != nil {
+		return err
+	}
+
+	g.fish = f
+	g.client = &http.Client{Timeout: 30 * time.Second}
+	g.tracked = make(map[types.ApplicationUID]*trackedApp)
+	g.stop = make(chan struct{})
+
+	for _, cl := range g.cfg.Clients {
+		if _, err := f.UserGet(cl.User); err != nil {
+			return fmt.Errorf("WEBHOOK: User %q of client %q is not found: %v", cl.User, cl.Name, err)
+		}
+	}
+	if err := g.restoreTracked(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", g.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to bind to address %q: %v", g.cfg.Address, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/allocate", g.allocateHandler)
+	mux.HandleFunc("/deallocate", g.deallocateHandler)
+	g.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	certPath, keyPath := f.GetTLSCertKeyPaths()
+	go func() {
+		if err := g.server.ServeTLS(listener, certPath, keyPath); err != http.ErrServerClosed {
+			log.Error("WEBHOOK: Unable to serve:", err)
+		}
+	}()
+	log.Info("WEBHOOK: Listening on:", listener.Addr())
+
+	sv.Go(g.callbackProcess)
+
+	return nil
+}
+
+// Close stops the gate processes
+func (g *Gate) Close() {
+	close(g.stop)
+	g.server.Close()
+}
+
+// restoreTracked recovers the tracked Applications after restart, the current state will be sent
+// to the callback again since it's not known if it was delivered
+func (g *Gate) restoreTracked() error {
+	apps, err := g.fish.ApplicationFind(nil, false)
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Unable to list Applications to restore the tracked ones: %v", err)
+	}
+	for _, app := range apps {
+		var md callbackMetadata
+		if err := json.Unmarshal([]byte(app.Metadata), &md); err != nil || md.CallbackURL == "" {
+			continue
+		}
+		client := g.getClient(md.Client)
+		if client == nil || app.OwnerName != client.User {
+			continue
+		}
+		state, err := g.fish.ApplicationStateGetByApplication(app.UID)
+		if err != nil || !g.fish.ApplicationStateIsActive(state.Status) {
+			continue
+		}
+		g.tracked[app.UID] = &trackedApp{
+			Client:      client,
+			CallbackURL: md.CallbackURL,
+			Reference:   md.Reference,
+		}
+		log.Debugf("WEBHOOK: Restored tracking of Application %s for client %q", app.UID, client.Name)
+	}
+	return nil
+}
+
+// getClient returns the client config by name
+func (g *Gate) getClient(name string) *ClientConfig {
+	for i := range g.cfg.Clients {
+		if g.cfg.Clients[i].Name == name {
+			return &g.cfg.Clients[i]
+		}
+	}
+	return nil
+}
+
+// authenticate verifies the event signature and returns the client and the body
+func (g *Gate) authenticate(w http.ResponseWriter, r *http.Request) (*ClientConfig, []byte) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, nil
+	}
+
+	client := g.getClient(r.Header.Get(headerClient))
+	if client == nil {
+		log.Warn("WEBHOOK: Event from unknown client:", r.RemoteAddr, r.Header.Get(headerClient))
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil, nil
+	}
+	if err := verify(client.Secret, r.Header.Get(headerTimestamp), r.Header.Get(headerSignature), body,
+		time.Duration(g.cfg.MaxClockSkew), time.Now()); err != nil {
+		log.Warnf("WEBHOOK: Event of client %q from %s is not valid: %v", client.Name, r.RemoteAddr, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil, nil
+	}
+	// The signature is valid for the clock skew range on both sides, so remembering it for twice long
+	if err := g.replays.check(client.Name+":"+r.Header.Get(headerSignature), time.Now(), 2*time.Duration(g.cfg.MaxClockSkew)); err != nil {
+		log.Warnf("WEBHOOK: Event of client %q from %s is rejected: %v", client.Name, r.RemoteAddr, err)
+		w.WriteHeader(http.StatusConflict)
+		return nil, nil
+	}
+	return client, body
+}
+
+// allocateHandler creates the Applications on behalf of the client user
+func (g *Gate) allocateHandler(w http.ResponseWriter, r *http.Request) {
+	client, body := g.authenticate(w, r)
+	if client == nil {
+		return
+	}
+
+	var event allocateEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, fmt.Sprintf("Wrong event: %v", err), http.StatusBadRequest)
+		return
+	}
+	if event.Count < 1 {
+		event.Count = 1
+	}
+	if event.Count > g.cfg.MaxCount {
+		http.Error(w, fmt.Sprintf("Too many resources requested, max is %d", g.cfg.MaxCount), http.StatusBadRequest)
+		return
+	}
+	if event.Metadata == "" {
+		event.Metadata = "{}"
+	}
+	if event.CallbackURL != "" {
+		// Storing the callback in the Application to continue tracking after restart
+		var metadata map[string]any
+		if err := json.Unmarshal([]byte(event.Metadata), &metadata); err != nil {
+			http.Error(w, fmt.Sprintf("Wrong metadata: %v", err), http.StatusBadRequest)
+			return
+		}
+		md, _ := json.Marshal(callbackMetadata{Client: client.Name, CallbackURL: event.CallbackURL, Reference: event.Reference})
+		json.Unmarshal(md, &metadata)
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Wrong metadata: %v", err), http.StatusBadRequest)
+			return
+		}
+		event.Metadata = util.UnparsedJSON(data)
+	}
+
+	var label *types.Label
+	var err error
+	if event.LabelUID != uuid.Nil {
+		label, err = g.fish.LabelGet(event.LabelUID)
+	} else {
+		label, err = g.fish.LabelGetLatest(event.Label)
+	}
+	if err != nil {
+		http.Error(w, "Unable to find Label", http.StatusBadRequest)
+		return
+	}
+
+	var apps []callbackEvent
+	for i := 0; i < event.Count; i++ {
+		app := &types.Application{
+			LabelUID:  label.UID,
+			OwnerName: client.User,
+			Metadata:  event.Metadata,
+		}
+		if err := g.fish.ApplicationCreate(context.Background(), app); err != nil {
+			log.Errorf("WEBHOOK: Unable to create Application for client %q: %v", client.Name, err)
+			break
+		}
+		log.Infof("WEBHOOK: Created Application %s with Label %s:%d for client %q", app.UID, label.Name, label.Version, client.Name)
+		apps = append(apps, callbackEvent{
+			Reference:      event.Reference,
+			ApplicationUID: app.UID,
+			ShortID:        app.ShortId,
+			Status:         types.ApplicationStatusNEW,
+		})
+		if event.CallbackURL != "" {
+			g.trackedMutex.Lock()
+			g.tracked[app.UID] = &trackedApp{
+				Client:      client,
+				CallbackURL: event.CallbackURL,
+				Reference:   event.Reference,
+				LastStatus:  types.ApplicationStatusNEW,
+			}
+			g.trackedMutex.Unlock()
+		}
+	}
+	if len(apps) == 0 {
+		http.Error(w, "Unable to create Applications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"applications": apps})
+}
+
+// deallocateHandler releases the client user Applications
+func (g *Gate) deallocateHandler(w http.ResponseWriter, r *http.Request) {
+	client, body := g.authenticate(w, r)
+	if client == nil {
+		return
+	}
+
+	var event deallocateEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, fmt.Sprintf("Wrong event: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result := make(map[types.ApplicationUID]string)
+	for _, appUID := range event.Applications {
+		app, err := g.fish.ApplicationGet(appUID)
+		if err != nil || app.OwnerName != client.User {
+			result[appUID] = "not found"
+			continue
+		}
+		if _, err := g.fish.ApplicationDeallocate(app, client.User); err != nil {
+			result[appUID] = err.Error()
+			continue
+		}
+		result[appUID] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"applications": result})
+}
+
+// callbackProcess reports the tracked Applications state changes
+func (g *Gate) callbackProcess() {
+	ticker := time.NewTicker(time.Duration(g.cfg.CheckInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+		}
+
+		g.trackedMutex.Lock()
+		tracked := make(map[types.ApplicationUID]*trackedApp, len(g.tracked))
+		for uid, t := range g.tracked {
+			tracked[uid] = t
+		}
+		g.trackedMutex.Unlock()
+
+		for appUID, t := range tracked {
+			g.checkApplication(appUID, t)
+		}
+	}
+}
+
+// checkApplication sends callback if the Application state is changed
+func (g *Gate) checkApplication(appUID types.ApplicationUID, t *trackedApp) {
+	state, err := g.fish.ApplicationStateGetByApplication(appUID)
+	if err != nil || state.Status == t.LastStatus {
+		return
+	}
+	app, err := g.fish.ApplicationGet(appUID)
+	if err != nil {
+		return
+	}
+
+	if err := g.callback(t, callbackEvent{
+		Reference:      t.Reference,
+		ApplicationUID: appUID,
+		ShortID:        app.ShortId,
+		Status:         state.Status,
+		Description:    state.Description,
+	}); err != nil {
+		// Will retry on the next check
+		log.Warnf("WEBHOOK: Unable to send callback for Application %s to client %q: %v", appUID, t.Client.Name, err)
+		return
+	}
+	t.LastStatus = state.Status
+
+	if !g.fish.ApplicationStateIsActive(state.Status) {
+		g.trackedMutex.Lock()
+		delete(g.tracked, appUID)
+		g.trackedMutex.Unlock()
+	}
+}
+
+// callback sends the signed event to the client callback URL
+func (g *Gate) callback(t *trackedApp, event callbackEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerClient, t.Client.Name)
+	req.Header.Set(headerTimestamp, fmt.Sprint(ts))
+	req.Header.Set(headerSignature, sign(t.Client.Secret, ts, body))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("WEBHOOK: Callback responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of the signed events and callbacks
+const (
+	headerClient    = "X-Fish-Client"
+	headerTimestamp = "X-Fish-Timestamp"
+	headerSignature = "X-Fish-Signature"
+)
+
+// sign returns signature of the body with timestamp, so the signed message could not be replayed
+// later: "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+func sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature and that timestamp is not too old
+func verify(secret, timestamp, signature string, body []byte, maxSkew time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("WEBHOOK: Wrong timestamp %q", timestamp)
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("WEBHOOK: Timestamp is out of allowed range")
+	}
+	if !hmac.Equal([]byte(sign(secret, ts, body)), []byte(signature)) {
+		return fmt.Errorf("WEBHOOK: Signature mismatch")
+	}
+	return nil
+}
+
+// replayCache remembers the signatures of the received events to reject the same event sent again
+// while its timestamp is still in the allowed range
+type replayCache struct {
+	mutex sync.Mutex
+	seen  map[string]time.Time // Signature -> when it could be forgotten
+}
+
+// check returns error if the signature was already received, otherwise remembers it for ttl
+func (c *replayCache) check(signature string, now time.Time, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.seen == nil {
+		c.seen = make(map[string]time.Time)
+	}
+	for sig, expires := range c.seen {
+		if now.After(expires) {
+			delete(c.seen, sig)
+		}
+	}
+	if _, ok := c.seen[signature]; ok {
+		return fmt.Errorf("WEBHOOK: Event was already received")
+	}
+	c.seen[signature] = now.Add(ttl)
+	return nil
+}
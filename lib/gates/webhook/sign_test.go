@@ -0,0 +1,65 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Verify the signed body passes only with the right secret, body and fresh timestamp
+func Test_sign_verify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"label":"test","count":1}`)
+	sig := sign("secret", now.Unix(), body)
+	ts := fmt.Sprint(now.Unix())
+
+	if err := verify("secret", ts, sig, body, time.Minute, now); err != nil {
+		t.Fatalf("verify() of the valid signature failed: %v", err)
+	}
+	if err := verify("wrong", ts, sig, body, time.Minute, now); err == nil {
+		t.Fatalf("verify() with wrong secret should fail")
+	}
+	if err := verify("secret", ts, sig, []byte(`{"label":"test","count":9}`), time.Minute, now); err == nil {
+		t.Fatalf("verify() of modified body should fail")
+	}
+	if err := verify("secret", ts, sig, body, time.Minute, now.Add(2*time.Minute)); err == nil {
+		t.Fatalf("verify() of replayed event should fail")
+	}
+	if err := verify("secret", "nope", sig, body, time.Minute, now); err == nil {
+		t.Fatalf("verify() with wrong timestamp should fail")
+	}
+}
+
+// Verify the same signed event is accepted only once until it's expired
+func Test_replay_cache(t *testing.T) {
+	now := time.Now()
+	var c replayCache
+
+	if err := c.check("sig1", now, time.Minute); err != nil {
+		t.Fatalf("check() of the new signature failed: %v", err)
+	}
+	if err := c.check("sig1", now.Add(30*time.Second), time.Minute); err == nil {
+		t.Fatalf("check() of the replayed signature should fail")
+	}
+	if err := c.check("sig2", now.Add(30*time.Second), time.Minute); err != nil {
+		t.Fatalf("check() of another signature failed: %v", err)
+	}
+	if err := c.check("sig1", now.Add(2*time.Minute), time.Minute); err != nil {
+		t.Fatalf("check() of the expired signature failed: %v", err)
+	}
+	if len(c.seen) != 1 {
+		t.Fatalf("Expired signatures should be removed: %v", c.seen)
+	}
+}
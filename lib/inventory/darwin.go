@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// systemProfiler is the part of system_profiler json output we need
+type systemProfiler struct {
+	Displays []struct {
+		Model  string `json:"sppci_model"`
+		Vendor string `json:"spdisplays_vendor"`
+	} `json:"SPDisplaysDataType"`
+	NVMe []systemProfilerController `json:"SPNVMeDataType"`
+	SATA []systemProfilerController `json:"SPSerialATADataType"`
+}
+
+type systemProfilerController struct {
+	Items []struct {
+		BSDName    string `json:"bsd_name"`
+		Model      string `json:"device_model"`
+		Size       uint64 `json:"size_in_bytes"`
+		MediumType string `json:"spsata_medium_type"`
+	} `json:"_items"`
+}
+
+// detectDarwin fills the disks, GPUs and Hypervisor.framework support
+func detectDarwin(inv *types.NodeInventory) error {
+	out, err := exec.Command("/usr/sbin/system_profiler", "-json", "SPDisplaysDataType", "SPNVMeDataType", "SPSerialATADataType").Output()
+	if err != nil {
+		return fmt.Errorf("Unable to run system_profiler: %v", err)
+	}
+	if err = parseSystemProfiler(inv, out); err != nil {
+		return err
+	}
+
+	// Apple Silicon CPUs have no flags, so asking the kernel
+	if out, err = exec.Command("/usr/sbin/sysctl", "-n", "kern.hv_support").Output(); err == nil && strings.TrimSpace(string(out)) == "1" {
+		inv.Virtualization.Hardware = true
+	}
+
+	return nil
+}
+
+// parseSystemProfiler fills the disks and GPUs from system_profiler json output
+func parseSystemProfiler(inv *types.NodeInventory, data []byte) error {
+	var sp systemProfiler
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return fmt.Errorf("Unable to parse system_profiler output: %v", err)
+	}
+
+	for _, display := range sp.Displays {
+		inv.Gpus = append(inv.Gpus, types.InventoryGPU{
+			// Vendor could look like "sppci_vendor_Apple"
+			Vendor: strings.TrimPrefix(display.Vendor, "sppci_vendor_"),
+			Model:  display.Model,
+		})
+	}
+	for _, controller := range append(sp.NVMe, sp.SATA...) {
+		for _, item := range controller.Items {
+			inv.Disks = append(inv.Disks, types.InventoryDisk{
+				Name:       item.BSDName,
+				Model:      item.Model,
+				Size:       uint(item.Size / 1073741824), // Getting GB from Bytes
+				Rotational: item.MediumType == "Rotational",
+			})
+		}
+	}
+
+	return nil
+}
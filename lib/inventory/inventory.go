@@ -0,0 +1,100 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package inventory detects the hardware of the Node host
+package inventory
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// The CPU flags showing the hardware virtualization support
+var virtFlags = []string{"vmx", "svm"}
+
+var (
+	last      *types.NodeInventory
+	lastMutex sync.Mutex
+)
+
+// Get returns the last detected inventory, detects it on the first call
+func Get() types.NodeInventory {
+	lastMutex.Lock()
+	inv := last
+	lastMutex.Unlock()
+	if inv == nil {
+		return Detect()
+	}
+	return *inv
+}
+
+// Detect collects the current inventory of the host and stores it for Get
+func Detect() types.NodeInventory {
+	inv := types.NodeInventory{
+		UpdatedAt: time.Now(),
+		CpuFlags:  []string{},
+		Disks:     []types.InventoryDisk{},
+		Gpus:      []types.InventoryGPU{},
+	}
+
+	if infos, err := cpu.Info(); err != nil {
+		log.Warn("Inventory: Unable to get CPU info:", err)
+	} else if len(infos) > 0 {
+		inv.CpuModel = infos[0].ModelName
+		inv.CpuVendor = infos[0].VendorID
+		inv.CpuFlags = append(inv.CpuFlags, infos[0].Flags...)
+		sort.Strings(inv.CpuFlags)
+	}
+	if cores, err := cpu.Counts(false); err == nil {
+		inv.CpuCores = uint(cores)
+	}
+	if threads, err := cpu.Counts(true); err == nil {
+		inv.CpuThreads = uint(threads)
+	}
+	if memStat, err := mem.VirtualMemory(); err == nil {
+		inv.RamTotal = uint(memStat.Total / 1073741824) // Getting GB from Bytes
+	}
+
+	inv.Virtualization.System, inv.Virtualization.Role, _ = host.Virtualization()
+	for _, flag := range virtFlags {
+		if util.Contains(inv.CpuFlags, flag) {
+			inv.Virtualization.Hardware = true
+		}
+	}
+
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		err = detectLinux(&inv, "/")
+	case "darwin":
+		err = detectDarwin(&inv)
+	}
+	if err != nil {
+		log.Warn("Inventory: Unable to detect some of the host hardware:", err)
+	}
+
+	lastMutex.Lock()
+	last = &inv
+	lastMutex.Unlock()
+
+	return inv
+}
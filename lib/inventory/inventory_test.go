@@ -0,0 +1,115 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, data := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_detect_linux(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"sys/block/nvme0n1/size":                           "3907029168\n",
+		"sys/block/nvme0n1/device/model":                   "Samsung SSD 990 PRO 2TB   \n",
+		"sys/block/nvme0n1/queue/rotational":               "0\n",
+		"sys/block/sda/size":                               "7814037168\n",
+		"sys/block/sda/device/model":                       "ST4000NM000A\n",
+		"sys/block/sda/queue/rotational":                   "1\n",
+		"sys/block/loop0/size":                             "1024\n",
+		"sys/bus/pci/devices/0000:01:00.0/class":           "0x030000\n",
+		"sys/bus/pci/devices/0000:01:00.0/vendor":          "0x10de\n",
+		"sys/bus/pci/devices/0000:01:00.0/device":          "0x2684\n",
+		"sys/bus/pci/devices/0000:02:00.0/class":           "0x030000\n",
+		"sys/bus/pci/devices/0000:02:00.0/vendor":          "0xabcd\n",
+		"sys/bus/pci/devices/0000:02:00.0/device":          "0x0001\n",
+		"sys/bus/pci/devices/0000:00:1f.0/class":           "0x060100\n",
+		"proc/driver/nvidia/gpus/0000:01:00.0/information": "Model: \t\t NVIDIA GeForce RTX 4090\nIRQ:   \t\t 180\n",
+		"dev/kvm": "",
+	})
+
+	inv := types.NodeInventory{}
+	if err := detectLinux(&inv, root); err != nil {
+		t.Fatalf("Unable to detect: %v", err)
+	}
+
+	if len(inv.Disks) != 2 {
+		t.Fatalf("Wrong disks: %+v", inv.Disks)
+	}
+	if inv.Disks[0] != (types.InventoryDisk{Name: "nvme0n1", Model: "Samsung SSD 990 PRO 2TB", Size: 1863, Rotational: false}) {
+		t.Fatalf("Wrong nvme disk: %+v", inv.Disks[0])
+	}
+	if inv.Disks[1] != (types.InventoryDisk{Name: "sda", Model: "ST4000NM000A", Size: 3726, Rotational: true}) {
+		t.Fatalf("Wrong sata disk: %+v", inv.Disks[1])
+	}
+
+	if len(inv.Gpus) != 2 {
+		t.Fatalf("Wrong GPUs: %+v", inv.Gpus)
+	}
+	if inv.Gpus[0] != (types.InventoryGPU{Vendor: "NVIDIA", Model: "NVIDIA GeForce RTX 4090"}) {
+		t.Fatalf("Wrong NVIDIA GPU: %+v", inv.Gpus[0])
+	}
+	if inv.Gpus[1] != (types.InventoryGPU{Vendor: "0xabcd", Model: "0x0001"}) {
+		t.Fatalf("Wrong unknown GPU: %+v", inv.Gpus[1])
+	}
+
+	if !inv.Virtualization.Hardware {
+		t.Fatalf("KVM is not detected")
+	}
+}
+
+func Test_parse_system_profiler(t *testing.T) {
+	inv := types.NodeInventory{}
+	err := parseSystemProfiler(&inv, []byte(`{
+		"SPDisplaysDataType": [{"_name": "Apple M2 Pro", "sppci_model": "Apple M2 Pro", "spdisplays_vendor": "sppci_vendor_Apple"}],
+		"SPNVMeDataType": [{"_name": "Apple SSD Controller", "_items": [
+			{"_name": "APPLE SSD AP1024Z", "bsd_name": "disk0", "device_model": "APPLE SSD AP1024Z", "size_in_bytes": 1000555581440}]}],
+		"SPSerialATADataType": []
+	}`))
+	if err != nil {
+		t.Fatalf("Unable to parse: %v", err)
+	}
+
+	if len(inv.Gpus) != 1 || inv.Gpus[0] != (types.InventoryGPU{Vendor: "Apple", Model: "Apple M2 Pro"}) {
+		t.Fatalf("Wrong GPUs: %+v", inv.Gpus)
+	}
+	if len(inv.Disks) != 1 || inv.Disks[0] != (types.InventoryDisk{Name: "disk0", Model: "APPLE SSD AP1024Z", Size: 931}) {
+		t.Fatalf("Wrong disks: %+v", inv.Disks)
+	}
+}
+
+func Test_get_caches_detect(t *testing.T) {
+	inv := Detect()
+	if inv.CpuThreads == 0 || inv.RamTotal == 0 {
+		t.Fatalf("CPU or RAM is not detected: %+v", inv)
+	}
+	if got := Get(); !got.UpdatedAt.Equal(inv.UpdatedAt) {
+		t.Fatalf("Get returned not the last detected inventory: %v != %v", got.UpdatedAt, inv.UpdatedAt)
+	}
+}
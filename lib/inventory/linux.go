@@ -0,0 +1,111 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Known PCI vendors of the GPUs
+var pciVendors = map[string]string{
+	"0x10de": "NVIDIA",
+	"0x1002": "AMD",
+	"0x8086": "Intel",
+	"0x1a03": "ASPEED",
+	"0x102b": "Matrox",
+	"0x15ad": "VMware",
+	"0x1234": "QEMU",
+	"0x1af4": "Red Hat",
+}
+
+// detectLinux fills the disks, GPUs and KVM support from sysfs, root is "/" except for tests
+func detectLinux(inv *types.NodeInventory, root string) error {
+	// Only the block devices backed by the physical device, skips loop, ram, dm and so on
+	blocks, err := os.ReadDir(filepath.Join(root, "sys", "block"))
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		dir := filepath.Join(root, "sys", "block", block.Name())
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+		sectors, _ := strconv.ParseUint(readFile(filepath.Join(dir, "size")), 10, 64)
+		inv.Disks = append(inv.Disks, types.InventoryDisk{
+			Name:       block.Name(),
+			Model:      readFile(filepath.Join(dir, "device", "model")),
+			Size:       uint(sectors * 512 / 1073741824), // Size is always in 512 bytes sectors
+			Rotational: readFile(filepath.Join(dir, "queue", "rotational")) == "1",
+		})
+	}
+
+	// PCI display controllers class is 0x03xxxx
+	devices, _ := os.ReadDir(filepath.Join(root, "sys", "bus", "pci", "devices"))
+	for _, device := range devices {
+		dir := filepath.Join(root, "sys", "bus", "pci", "devices", device.Name())
+		if !strings.HasPrefix(readFile(filepath.Join(dir, "class")), "0x03") {
+			continue
+		}
+		vendorID := readFile(filepath.Join(dir, "vendor"))
+		gpu := types.InventoryGPU{
+			Vendor: pciVendors[vendorID],
+			Model:  readFile(filepath.Join(dir, "device")),
+		}
+		if gpu.Vendor == "" {
+			gpu.Vendor = vendorID
+		}
+		// The NVIDIA driver knows the model name
+		if model := nvidiaModel(filepath.Join(root, "proc", "driver", "nvidia", "gpus", device.Name(), "information")); model != "" {
+			gpu.Model = model
+		}
+		inv.Gpus = append(inv.Gpus, gpu)
+	}
+
+	// KVM device is available only if the hardware virtualization is enabled
+	if _, err := os.Stat(filepath.Join(root, "dev", "kvm")); err == nil {
+		inv.Virtualization.Hardware = true
+	}
+
+	return nil
+}
+
+// nvidiaModel returns the GPU model from NVIDIA driver information file
+func nvidiaModel(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), ":"); ok && strings.TrimSpace(key) == "Model" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// readFile returns the trimmed content of the sysfs file or empty string
+func readFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -15,6 +15,7 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 )
@@ -76,10 +77,13 @@ func InitLoggers() error {
 		flags |= log.Lshortfile
 	}
 
-	debugLogger = log.New(os.Stdout, "DEBUG:\t", flags)
-	infoLogger = log.New(os.Stdout, "INFO:\t", flags)
-	warnLogger = log.New(os.Stdout, "WARN:\t", flags)
-	errorLogger = log.New(os.Stdout, "ERROR:\t", flags)
+	// Keeping the last lines in memory to be able to tail them
+	out := io.MultiWriter(os.Stdout, tail)
+
+	debugLogger = log.New(out, "DEBUG:\t", flags)
+	infoLogger = log.New(out, "INFO:\t", flags)
+	warnLogger = log.New(out, "WARN:\t", flags)
+	errorLogger = log.New(out, "ERROR:\t", flags)
 
 	return nil
 }
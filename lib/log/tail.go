@@ -0,0 +1,85 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// How many log lines to keep in memory
+const tailSize = 1000
+
+// tailBuffer keeps the last log lines in memory to show them to admin when the node logs are
+// not accessible otherwise
+type tailBuffer struct {
+	mutex sync.Mutex
+	lines []string
+	pos   int
+	subs  map[chan string]struct{}
+}
+
+var tail = &tailBuffer{
+	lines: make([]string, 0, tailSize),
+	subs:  make(map[chan string]struct{}),
+}
+
+// Write stores the log line and sends it to subscribers, logger writes one message per call
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.lines) < tailSize {
+		t.lines = append(t.lines, line)
+	} else {
+		t.lines[t.pos] = line
+		t.pos = (t.pos + 1) % tailSize
+	}
+	for ch := range t.subs {
+		// Slow subscribers are just missing the lines to not block the logging
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Tail returns up to n last log lines
+func Tail(n int) []string {
+	tail.mutex.Lock()
+	defer tail.mutex.Unlock()
+
+	out := make([]string, 0, len(tail.lines))
+	out = append(out, tail.lines[tail.pos:]...)
+	out = append(out, tail.lines[:tail.pos]...)
+	if n >= 0 && n < len(out) {
+		out = out[len(out)-n:]
+	}
+	return out
+}
+
+// TailSubscribe returns channel to receive the new log lines, cancel should be called when done
+func TailSubscribe() (lines <-chan string, cancel func()) {
+	ch := make(chan string, 100)
+	tail.mutex.Lock()
+	tail.subs[ch] = struct{}{}
+	tail.mutex.Unlock()
+
+	return ch, func() {
+		tail.mutex.Lock()
+		delete(tail.subs, ch)
+		tail.mutex.Unlock()
+	}
+}
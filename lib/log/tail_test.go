@@ -0,0 +1,42 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package log
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Verify tail keeps only the last lines in the right order
+func Test_tail_keeps_last_lines(t *testing.T) {
+	buf := &tailBuffer{subs: make(map[chan string]struct{})}
+	tail, buf = buf, tail
+	defer func() { tail = buf }()
+
+	for i := 0; i < tailSize+5; i++ {
+		tail.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+
+	out := Tail(3)
+	want := []string{
+		fmt.Sprintf("line %d", tailSize+2),
+		fmt.Sprintf("line %d", tailSize+3),
+		fmt.Sprintf("line %d", tailSize+4),
+	}
+	if fmt.Sprint(out) != fmt.Sprint(want) {
+		t.Fatalf("Tail(3) = %q; want: %q", out, want)
+	}
+	if all := Tail(-1); len(all) != tailSize || all[0] != "line 5" {
+		t.Fatalf("Tail(-1) returned %d lines starting with %q; want: %d starting with `line 5`", len(all), all[0], tailSize)
+	}
+}
@@ -0,0 +1,353 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package metrics is the tiny internal registry of the node metrics, exported in Prometheus
+// text format to be able to chart the fleet behaviour
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are used by the histograms in seconds if nothing else is specified
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 1800}
+
+// Default registry used by the node to collect all the metrics
+var Default = &Registry{}
+
+// Sample is the metric value with the label values in the same order as metric labels
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// collector is implemented by all the metric types to be exported by the registry
+type collector interface {
+	describe() *desc
+	write(w io.Writer)
+}
+
+// Registry keeps the list of the metrics to export
+type Registry struct {
+	mu      sync.RWMutex
+	metrics []collector
+}
+
+// register adds the metric to the registry, the metric names should be unique
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.metrics {
+		if m.describe().name == c.describe().name {
+			panic(fmt.Sprintf("Metrics: Metric %q is already registered", c.describe().name))
+		}
+	}
+	r.metrics = append(r.metrics, c)
+}
+
+// WriteText exports all the registered metrics in Prometheus text format
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.RLock()
+	metrics := make([]collector, len(r.metrics))
+	copy(metrics, r.metrics)
+	r.mu.RUnlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].describe().name < metrics[j].describe().name
+	})
+	for _, m := range metrics {
+		d := m.describe()
+		fmt.Fprintf(w, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+		fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.typ)
+		m.write(w)
+	}
+}
+
+// desc describes the metric
+type desc struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+}
+
+func (d *desc) describe() *desc {
+	return d
+}
+
+// labelsString formats the label pairs, extra pair is used by histogram for "le" label
+func (d *desc) labelsString(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(d.labels)+1)
+	for i, name := range d.labels {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, name+`="`+escapeLabel(value)+`"`)
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, extra[0]+`="`+escapeLabel(extra[1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// vec stores the values of the metric per label values combination
+type vec[T any] struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*T
+	keys   map[string][]string
+}
+
+// get returns the value for the label values, creates a new one if it's not exists
+func (v *vec[T]) get(labels []string, create func() *T) *T {
+	if len(labels) != len(v.labels) {
+		panic(fmt.Sprintf("Metrics: Metric %q expects %d label values, got %d", v.name, len(v.labels), len(labels)))
+	}
+	key := strings.Join(labels, "\xff")
+	val, ok := v.values[key]
+	if !ok {
+		val = create()
+		v.values[key] = val
+		v.keys[key] = append([]string(nil), labels...)
+	}
+	return val
+}
+
+// sorted returns the stored keys in stable order
+func (v *vec[T]) sorted() []string {
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Counter is the monotonically increasing value
+type Counter struct {
+	vec[float64]
+}
+
+// NewCounter creates and registers the counter in the default registry
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewCounter creates and registers the counter in the registry
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{vec[float64]{desc: desc{name, help, "counter", labels}, values: map[string]*float64{}, keys: map[string][]string{}}}
+	r.register(c)
+	return c
+}
+
+// Inc increases the counter by 1
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add increases the counter by value, negative values are ignored
+func (c *Counter) Add(value float64, labels ...string) {
+	if value < 0 {
+		return
+	}
+	c.mu.Lock()
+	*c.get(labels, newFloat) += value
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range c.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelsString(c.keys[k]), formatFloat(*c.values[k]))
+	}
+}
+
+// Gauge is the value which could go up and down
+type Gauge struct {
+	vec[float64]
+}
+
+// NewGauge creates and registers the gauge in the default registry
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+// NewGauge creates and registers the gauge in the registry
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{vec[float64]{desc: desc{name, help, "gauge", labels}, values: map[string]*float64{}, keys: map[string][]string{}}}
+	r.register(g)
+	return g
+}
+
+// Set the gauge value
+func (g *Gauge) Set(value float64, labels ...string) {
+	g.mu.Lock()
+	*g.get(labels, newFloat) = value
+	g.mu.Unlock()
+}
+
+// Add value to the gauge, use negative value to decrease it
+func (g *Gauge) Add(value float64, labels ...string) {
+	g.mu.Lock()
+	*g.get(labels, newFloat) += value
+	g.mu.Unlock()
+}
+
+// Inc increases the gauge by 1
+func (g *Gauge) Inc(labels ...string) {
+	g.Add(1, labels...)
+}
+
+// Dec decreases the gauge by 1
+func (g *Gauge) Dec(labels ...string) {
+	g.Add(-1, labels...)
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, k := range g.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelsString(g.keys[k]), formatFloat(*g.values[k]))
+	}
+}
+
+// GaugeFunc is the gauge which values are collected on export, useful when the values are
+// already stored somewhere else (like in database) and there is no need to track them
+type GaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+// NewGaugeFunc creates and registers the collected gauge in the default registry
+func NewGaugeFunc(name, help string, collect func() []Sample, labels ...string) *GaugeFunc {
+	return Default.NewGaugeFunc(name, help, collect, labels...)
+}
+
+// NewGaugeFunc creates and registers the collected gauge in the registry
+func (r *Registry) NewGaugeFunc(name, help string, collect func() []Sample, labels ...string) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{name, help, "gauge", labels}, collect: collect}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	samples := g.collect()
+	sort.SliceStable(samples, func(i, j int) bool {
+		return strings.Join(samples[i].Labels, "\xff") < strings.Join(samples[j].Labels, "\xff")
+	})
+	for _, s := range samples {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelsString(s.Labels), formatFloat(s.Value))
+	}
+}
+
+// histogramValue stores the observations of one label values combination
+type histogramValue struct {
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts the observations in the configurable buckets
+type Histogram struct {
+	vec[histogramValue]
+	buckets []float64
+}
+
+// NewHistogram creates and registers the histogram in the default registry, if buckets are
+// not provided the DefaultBuckets are used
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// NewHistogram creates and registers the histogram in the registry
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{
+		vec:     vec[histogramValue]{desc: desc{name, help, "histogram", labels}, values: map[string]*histogramValue{}, keys: map[string][]string{}},
+		buckets: buckets,
+	}
+	r.register(h)
+	return h
+}
+
+// Observe adds the value to the histogram
+func (h *Histogram) Observe(value float64, labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v := h.get(labels, func() *histogramValue {
+		return &histogramValue{counts: make([]uint64, len(h.buckets))}
+	})
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		v.counts[i]++
+	}
+	v.count++
+	v.sum += value
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, k := range h.sorted() {
+		v := h.values[k]
+		var cumulative uint64
+		for i, b := range h.buckets {
+			cumulative += v.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelsString(h.keys[k], "le", formatFloat(b)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelsString(h.keys[k], "le", "+Inf"), v.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelsString(h.keys[k]), formatFloat(v.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelsString(h.keys[k]), v.count)
+	}
+}
+
+func newFloat() *float64 {
+	return new(float64)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+var labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_write_text(t *testing.T) {
+	r := &Registry{}
+	c := r.NewCounter("test_errors_total", "Test errors", "driver")
+	c.Inc("aws")
+	c.Add(2, "aws")
+	c.Inc("docker")
+
+	g := r.NewGauge("test_connections", "Test connections")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+
+	r.NewGaugeFunc("test_apps", "Test \"apps\"\nby status", func() []Sample {
+		return []Sample{{[]string{"NEW"}, 3}, {[]string{`A"B`}, 1}}
+	}, "status")
+
+	h := r.NewHistogram("test_latency_seconds", "Test latency", []float64{1, 0.1})
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.Observe(0.5)
+	h.Observe(3)
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	out := buf.String()
+
+	for _, line := range []string{
+		`# HELP test_apps Test "apps"\nby status`,
+		`# TYPE test_apps gauge`,
+		`test_apps{status="A\"B"} 1`,
+		`test_apps{status="NEW"} 3`,
+		`# TYPE test_connections gauge`,
+		`test_connections 1`,
+		`# TYPE test_errors_total counter`,
+		`test_errors_total{driver="aws"} 3`,
+		`test_errors_total{driver="docker"} 1`,
+		`# TYPE test_latency_seconds histogram`,
+		`test_latency_seconds_bucket{le="0.1"} 2`,
+		`test_latency_seconds_bucket{le="1"} 3`,
+		`test_latency_seconds_bucket{le="+Inf"} 4`,
+		`test_latency_seconds_sum 3.65`,
+		`test_latency_seconds_count 4`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in the output:\n%s", line, out)
+		}
+	}
+
+	// Metrics are sorted by name
+	if strings.Index(out, "test_apps") > strings.Index(out, "test_latency_seconds") {
+		t.Errorf("Metrics are not sorted:\n%s", out)
+	}
+}
@@ -0,0 +1,105 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package notify delivers the lifecycle notifications to the users through the pluggable sinks:
+// email by SMTP, Slack incoming webhook and generic JSON webhook
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Events the users could subscribe to
+const (
+	EventApplicationAllocated   = "application_allocated"
+	EventApplicationError       = "application_error"
+	EventApplicationDeallocated = "application_deallocated"
+	EventLifetimeExpiring       = "lifetime_expiring"
+	EventResourceUnhealthy      = "resource_unhealthy"
+	EventQuotaExceeded          = "quota_exceeded"
+	EventNodeDown               = "node_down"
+	EventNodeWarning            = "node_warning"
+	EventImagePublished         = "image_published"
+	EventImageDeprecated        = "image_deprecated"
+)
+
+// Events lists all the supported events
+var Events = []string{
+	EventApplicationAllocated,
+	EventApplicationError,
+	EventApplicationDeallocated,
+	EventLifetimeExpiring,
+	EventResourceUnhealthy,
+	EventQuotaExceeded,
+	EventNodeDown,
+	EventNodeWarning,
+	EventImagePublished,
+	EventImageDeprecated,
+}
+
+// Config defines the notification sinks, SMTP is enabled when address is set and the webhooks are
+// limited to the allowed URL prefixes, since the users could set any address
+type Config struct {
+	SMTP         ConfigSMTP    `json:"smtp"`          // Email sink
+	SlackAllow   []string      `json:"slack_allow"`   // Slack webhook URL prefixes, "https://hooks.slack.com/" by default
+	WebhookAllow []string      `json:"webhook_allow"` // Generic webhook URL prefixes, empty disables the webhook sink
+	ExpiryNotice util.Duration `json:"expiry_notice"` // How long before the Resource lifetime expiry to notify the owner, 1h by default
+	NodeDownAge  util.Duration `json:"node_down_age"` // Node not pinged that long is considered down, 1m by default
+}
+
+// Notification is the event message sent to the user
+type Notification struct {
+	Event   string         `json:"event"`
+	Time    time.Time      `json:"time"`
+	Subject string         `json:"subject"`
+	Text    string         `json:"text"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Sink delivers the notification to the address in the sink format
+type Sink interface {
+	// Validate checks the address is acceptable by the sink before the user subscribes with it
+	Validate(address string) error
+	// Send delivers the notification to the address
+	Send(address string, n *Notification) error
+}
+
+// NewSinks creates the sinks enabled by config, the key is the sink name used in subscriptions
+func NewSinks(cfg *Config) map[string]Sink {
+	sinks := map[string]Sink{}
+	if cfg.SMTP.Address != "" {
+		sinks["smtp"] = &smtpSink{cfg: cfg.SMTP}
+	}
+	slackAllow := cfg.SlackAllow
+	if len(slackAllow) == 0 {
+		slackAllow = []string{"https://hooks.slack.com/"}
+	}
+	sinks["slack"] = &slackSink{allow: slackAllow}
+	if len(cfg.WebhookAllow) > 0 {
+		sinks["webhook"] = &webhookSink{allow: cfg.WebhookAllow}
+	}
+	return sinks
+}
+
+// urlAllowed checks the address starts with one of the allowed prefixes
+func urlAllowed(allow []string, address string) error {
+	for _, prefix := range allow {
+		if strings.HasPrefix(address, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Notify: URL is not allowed, should start with one of: %v", allow)
+}
@@ -0,0 +1,108 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_sinks_enabled_by_config(t *testing.T) {
+	sinks := NewSinks(&Config{})
+	if _, ok := sinks["slack"]; !ok {
+		t.Fatalf("Slack sink should be enabled by default")
+	}
+	if _, ok := sinks["smtp"]; ok {
+		t.Fatalf("SMTP sink should be disabled without address")
+	}
+	if _, ok := sinks["webhook"]; ok {
+		t.Fatalf("Webhook sink should be disabled without allowed prefixes")
+	}
+
+	sinks = NewSinks(&Config{SMTP: ConfigSMTP{Address: "localhost:25"}, WebhookAllow: []string{"https://hooks.example.com/"}})
+	if len(sinks) != 3 {
+		t.Fatalf("All the sinks should be enabled: %v", sinks)
+	}
+}
+
+func Test_webhook_sink_allowed_urls(t *testing.T) {
+	sink := &webhookSink{allow: []string{"https://hooks.example.com/"}}
+	if err := sink.Validate("https://hooks.example.com/fish"); err != nil {
+		t.Fatalf("URL should be allowed: %v", err)
+	}
+	for _, url := range []string{"http://hooks.example.com/fish", "https://hooks.example.com.evil.org/", "http://169.254.169.254/"} {
+		if err := sink.Validate(url); err == nil {
+			t.Fatalf("URL should not be allowed: %s", url)
+		}
+	}
+}
+
+func Test_webhook_sink_send(t *testing.T) {
+	var received Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	sink := &webhookSink{allow: []string{srv.URL}}
+	n := &Notification{Event: EventApplicationAllocated, Time: time.Now(), Subject: "Allocated", Text: "Ready"}
+	if err := sink.Send(srv.URL+"/hook", n); err != nil {
+		t.Fatalf("Unable to send: %v", err)
+	}
+	if received.Event != EventApplicationAllocated || received.Subject != "Allocated" {
+		t.Fatalf("Received notification is incorrect: %v", received)
+	}
+}
+
+func Test_slack_sink_send(t *testing.T) {
+	var received map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	sink := &slackSink{allow: []string{srv.URL}}
+	err := sink.Send(srv.URL, &Notification{Subject: "Node down", Text: "node-1"})
+	if err == nil {
+		t.Fatalf("Error status should fail the send")
+	}
+	if received["text"] != "*Node down*\nnode-1" {
+		t.Fatalf("Slack message is incorrect: %q", received["text"])
+	}
+}
+
+func Test_smtp_message_header_injection(t *testing.T) {
+	sink := &smtpSink{}
+	if err := sink.Validate("user@example.com"); err != nil {
+		t.Fatalf("Address should be valid: %v", err)
+	}
+	if err := sink.Validate("User <user@example.com>"); err == nil {
+		t.Fatalf("Only plain address should be valid")
+	}
+
+	msg := string(smtpMessage("fish@example.com", "user@example.com", &Notification{
+		Subject: "Allocated\r\nBcc: evil@example.com",
+		Text:    "Line 1\nLine 2",
+	}))
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Fatalf("Subject should not inject headers: %q", msg)
+	}
+	if !strings.Contains(msg, "\r\n\r\nLine 1\r\nLine 2\r\n") {
+		t.Fatalf("Body is incorrect: %q", msg)
+	}
+}
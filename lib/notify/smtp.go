@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// ConfigSMTP defines the mail server to send the email notifications
+type ConfigSMTP struct {
+	Address  string `json:"address"`  // Mail server host:port, empty disables the email sink
+	From     string `json:"from"`     // Sender address of the notifications
+	Username string `json:"username"` // Optional PLAIN auth username
+	Password string `json:"password"` // Optional PLAIN auth password, could be the secret reference
+}
+
+// smtpSink sends the notifications as plain text emails
+type smtpSink struct {
+	cfg ConfigSMTP
+}
+
+// Validate checks the address is a single email address
+func (*smtpSink) Validate(address string) error {
+	addr, err := mail.ParseAddress(address)
+	if err != nil || addr.Address != address {
+		return fmt.Errorf("Notify: Wrong email address %q", address)
+	}
+	return nil
+}
+
+// Send delivers the notification email
+func (s *smtpSink) Send(address string, n *Notification) error {
+	if err := s.Validate(address); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		host, _, _ := net.SplitHostPort(s.cfg.Address)
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)
+	}
+
+	return smtp.SendMail(s.cfg.Address, auth, s.cfg.From, []string{address}, smtpMessage(s.cfg.From, address, n))
+}
+
+// smtpMessage formats the notification as email message, the subject is stripped of the line
+// breaks to not allow header injection
+func smtpMessage(from, to string, n *Notification) []byte {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Subject)
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + subject + "\r\n")
+	b.WriteString("Date: " + n.Time.Format("Mon, 02 Jan 2006 15:04:05 -0700") + "\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Text, "\n", "\r\n") + "\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,77 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookSink POSTs the notification as is in JSON
+type webhookSink struct {
+	allow []string
+}
+
+// Validate checks the URL is allowed by config
+func (s *webhookSink) Validate(address string) error {
+	return urlAllowed(s.allow, address)
+}
+
+// Send posts the notification to the URL
+func (s *webhookSink) Send(address string, n *Notification) error {
+	if err := s.Validate(address); err != nil {
+		return err
+	}
+	return postJSON(address, n)
+}
+
+// slackSink posts the notification to the Slack incoming webhook
+type slackSink struct {
+	allow []string
+}
+
+// Validate checks the URL is allowed by config
+func (s *slackSink) Validate(address string) error {
+	return urlAllowed(s.allow, address)
+}
+
+// Send posts the notification text in the Slack message format
+func (s *slackSink) Send(address string, n *Notification) error {
+	if err := s.Validate(address); err != nil {
+		return err
+	}
+	return postJSON(address, map[string]string{"text": "*" + n.Subject + "*\n" + n.Text})
+}
+
+// postJSON sends the data to the URL and checks the response status
+func postJSON(url string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Notify: Webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
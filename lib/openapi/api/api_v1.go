@@ -14,9 +14,19 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/http/pprof"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,34 +37,202 @@ import (
 	"github.com/adobe/aquarium-fish/lib/fish"
 	"github.com/adobe/aquarium-fish/lib/log"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/util"
 )
 
 // H is a shortcut for map[string]any
 type H map[string]any
 
+// validationH returns the error response, the validation error gets the field and limit details
+// so the client could show what exactly need to be fixed
+func validationH(message string, err error) H {
+	out := H{"message": fmt.Sprintf("%s: %v", message, err)}
+	var verr *fish.ValidationError
+	if errors.As(err, &verr) {
+		out["field"] = verr.Field
+		if verr.Limit != "" {
+			out["limit"] = verr.Limit
+		}
+	}
+	return out
+}
+
 // Processor doing processing of the API request
 type Processor struct {
-	fish *fish.Fish
+	fish       *fish.Fish
+	operations map[string]Operation
 }
 
-// NewV1Router creates router for APIv1
-func NewV1Router(e *echo.Echo, f *fish.Fish) {
-	proc := &Processor{fish: f}
+// Operation describes the API operation to check the UserToken scopes
+type Operation struct {
+	ID   string
+	Tags []string
+}
+
+// NewV1Router creates router for APIv1, operations are mapped by the echo route "METHOD /path"
+func NewV1Router(e *echo.Echo, f *fish.Fish, operations map[string]Operation) {
+	proc := &Processor{fish: f, operations: operations}
 	router := e.Group("")
 	router.Use(
 		// Regular basic auth
 		echomw.BasicAuth(proc.BasicAuth),
-		// Limiting body size for better security, as usual "64KB ought to be enough for anybody"
-		echomw.BodyLimit("64KB"),
+		// Limits the UserToken requests to the token scopes
+		proc.TokenScope,
+		// Limits the User requests rate by the API service or operation
+		proc.RateLimit,
+		// Limiting body size for better security, as usual "64KB ought to be enough for anybody",
+		// goes before the middlewares reading the body to not let them to buffer unlimited payload
+		echomw.BodyLimit(f.LimitsGet().BodySize.String()),
+		// Records the mutating requests to the audit log
+		proc.Audit,
+		// Replays the response of the retried mutating requests with the same Idempotency-Key
+		proc.Idempotency,
+		// Allows to use Application short ID instead of UID
+		proc.ApplicationShortID,
 	)
 	RegisterHandlers(router, proc)
 }
 
+// ApplicationShortID middleware replaces Application short ID with the actual UID in the path
+// of Application requests and in the `application_UID` field of the JSON requests body
+func (e *Processor) ApplicationShortID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if strings.HasPrefix(c.Path(), "/api/v1/application/") {
+			values := c.ParamValues()
+			for i, name := range c.ParamNames() {
+				if name != "uid" || i >= len(values) || !isApplicationShortID(values[i]) {
+					continue
+				}
+				uid, err := e.fish.ApplicationResolveUID(values[i])
+				if err != nil {
+					c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the application: %s", values[i])})
+					return fmt.Errorf("Unable to find the Application: %s, %w", values[i], err)
+				}
+				values[i] = uid.String()
+			}
+			c.SetParamValues(values...)
+		}
+
+		req := c.Request()
+		if req.Body == nil || !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+			return next(c)
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			// Most probably it's the body limit error
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if !bytes.Contains(body, []byte(`"application_UID"`)) {
+			return next(c)
+		}
+		var data map[string]any
+		if json.Unmarshal(body, &data) != nil {
+			// Let the handler report the format error
+			return next(c)
+		}
+		if id, ok := data["application_UID"].(string); ok && isApplicationShortID(id) {
+			uid, err := e.fish.ApplicationResolveUID(id)
+			if err != nil {
+				c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the application: %s", id)})
+				return fmt.Errorf("Unable to find the Application: %s, %w", id, err)
+			}
+			data["application_UID"] = uid.String()
+			if body, err = json.Marshal(data); err == nil {
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.ContentLength = int64(len(body))
+			}
+		}
+		return next(c)
+	}
+}
+
+// isApplicationShortID checks if the identifier looks like Application short ID
+func isApplicationShortID(id string) bool {
+	return strings.HasPrefix(strings.ToLower(id), fish.ApplicationShortIDPrefix)
+}
+
+// auditGetRoutes are the GET routes which are changing the state, so need to be audited too
+var auditGetRoutes = []string{
+	"/api/v1/application/:uid/deallocate",
+	"/api/v1/application/:uid/deallocate/approve",
+	"/api/v1/application/:uid/approve",
+	"/api/v1/application/:uid/reject",
+	"/api/v1/node/:uid/maintenance",
+	"/api/v1/node/this/maintenance",
+	"/api/v1/node/this/driver/restart",
+	"/api/v1/node/this/bundle",
+	"/api/v1/schedule/:uid/enable",
+	"/api/v1/schedule/:uid/disable",
+	"/api/v1/upgrade/:uid/pause",
+	"/api/v1/upgrade/:uid/resume",
+	"/api/v1/upgrade/:uid/abort",
+}
+
+// auditObject describes the object changed by request to put it in the audit log
+type auditObject struct {
+	Type   string
+	ID     string
+	Before any
+	After  any
+}
+
+// audit marks the object changed by the request, before or after could be nil if the object was
+// created or removed
+func audit(c echo.Context, objType, objID string, before, after any) {
+	c.Set("audit", &auditObject{Type: objType, ID: objID, Before: before, After: after})
+}
+
+// Audit middleware records the mutating requests to the audit log, including the rejected ones
+func (e *Processor) Audit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+
+		obj, _ := c.Get("audit").(*auditObject)
+		if obj == nil && c.Request().Method == http.MethodGet && !slices.Contains(auditGetRoutes, c.Path()) {
+			return err
+		}
+
+		rec := &types.AuditRecord{
+			AuthMethod: fish.AuditAuthBasic,
+			SourceIp:   c.RealIP(),
+			Action:     c.Request().Method + " " + c.Path(),
+			Status:     c.Response().Status,
+		}
+		if _, ok := c.Get("token").(*types.UserToken); ok {
+			rec.AuthMethod = fish.AuditAuthToken
+		}
+		if user, ok := c.Get("user").(*types.User); ok {
+			rec.UserName = user.Name
+		}
+		if obj != nil {
+			rec.ObjectType = obj.Type
+			rec.ObjectId = obj.ID
+			rec.Changes = fish.AuditChanges(obj.Before, obj.After)
+		}
+		if aerr := e.fish.AuditRecordCreate(rec); aerr != nil {
+			log.Error("API: Unable to write audit record:", rec.Action, aerr)
+		}
+
+		return err
+	}
+}
+
 // BasicAuth middleware to ensure API will not be used by crocodile
 func (e *Processor) BasicAuth(username, password string, c echo.Context) (bool, error) {
 	c.Set("uid", crypt.RandString(8))
 	log.Debugf("API: %s: New request received: %s %s", username, c.Get("uid"), c.Path())
-	user := e.fish.UserAuth(username, password)
+	var user *types.User
+	if strings.HasPrefix(password, fish.UserTokenPrefix) {
+		var token *types.UserToken
+		if user, token = e.fish.UserTokenAuth(username, password); token != nil {
+			c.Set("token", token)
+		}
+	}
+	// The regular password could look like token too, so checking it if token was not found
+	if user == nil {
+		user = e.fish.UserAuth(username, password)
+	}
 
 	// Clean Auth header and set the user
 	c.Response().Header().Del("Authorization")
@@ -64,6 +242,156 @@ func (e *Processor) BasicAuth(username, password string, c echo.Context) (bool,
 	return user != nil, nil
 }
 
+// TokenScope middleware denies the UserToken requests to the operations out of the token scopes
+func (e *Processor) TokenScope(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := c.Get("token").(*types.UserToken)
+		if !ok || len(token.Scopes) == 0 {
+			return next(c)
+		}
+		op := e.operations[c.Request().Method+" "+c.Path()]
+		for _, scope := range token.Scopes {
+			if scope == op.ID || slices.Contains(op.Tags, scope) {
+				return next(c)
+			}
+		}
+		c.JSON(http.StatusForbidden, H{"message": fmt.Sprintf("The token scopes are not allowing operation %s", op.ID)})
+		return fmt.Errorf("The token scopes are not allowing operation %s", op.ID)
+	}
+}
+
+// RateLimit middleware rejects the User requests over the configured rate and reports the limit
+// consumption in the headers, so the clients could adapt
+func (e *Processor) RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := c.Get("user").(*types.User)
+		if !ok {
+			return next(c)
+		}
+		op := e.operations[c.Request().Method+" "+c.Path()]
+		limit, wait := e.fish.RateLimitTake(user.Name, op.ID, op.Tags)
+		if limit == nil {
+			return next(c)
+		}
+		header := c.Response().Header()
+		header.Set("X-RateLimit-Rule", limit.Rule)
+		header.Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+		if wait > 0 {
+			header.Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, H{"message": fmt.Sprintf("Rate limit of %s is exceeded, retry in %s", limit.Rule, wait.Round(time.Millisecond))})
+			return fmt.Errorf("Rate limit of %s is exceeded for User %s", limit.Rule, user.Name)
+		}
+		return next(c)
+	}
+}
+
+// IdempotencyKeyHeader is set by the client to safely retry the mutating requests
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// bodyRecorder copies the response body to store it for the idempotent requests
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap allows the response controller to flush the underlying writer
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Idempotency middleware stores the response of the mutating request with Idempotency-Key and
+// replies with it when the client retries the request with the same key, so the network failures
+// will not cause the duplicated Applications or the double deallocation
+func (e *Processor) Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(IdempotencyKeyHeader)
+		user, ok := c.Get("user").(*types.User)
+		if key == "" || !ok || (c.Request().Method == http.MethodGet && !slices.Contains(auditGetRoutes, c.Path())) {
+			return next(c)
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, H{"message": "Idempotency key is longer than 255 symbols"})
+			return fmt.Errorf("Idempotency key is longer than 255 symbols")
+		}
+
+		// The body is hashed to make sure the key is not reused for the request with other payload
+		body, err := io.ReadAll(c.Request().Body)
+		if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+			return err
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to read the request body: %v", err)})
+			return fmt.Errorf("Unable to read the request body: %w", err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		bodyHash := sha256.Sum256(body)
+
+		rec, err := e.fish.IdempotencyStart(user.Name, key, c.Request().Method+" "+c.Request().URL.Path, hex.EncodeToString(bodyHash[:]))
+		if errors.Is(err, fish.ErrIdempotencyInProgress) {
+			c.JSON(http.StatusConflict, H{"message": "Request with the same idempotency key is in progress"})
+			return err
+		}
+		if errors.Is(err, fish.ErrIdempotencyMismatch) {
+			c.JSON(http.StatusUnprocessableEntity, H{"message": "Idempotency key was already used for the other request"})
+			return err
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to check the idempotency key: %v", err)})
+			return fmt.Errorf("Unable to check the idempotency key: %w", err)
+		}
+		if rec != nil {
+			c.Response().Header().Set("Idempotent-Replayed", "true")
+			return c.Blob(rec.Status, rec.ContentType, rec.Body)
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		err = next(c)
+		status := 0
+		if c.Response().Committed {
+			status = c.Response().Status
+		}
+		if ferr := e.fish.IdempotencyFinish(user.Name, key, status, c.Response().Header().Get(echo.HeaderContentType), recorder.body.Bytes()); ferr != nil {
+			log.Error("API: Unable to store the idempotency key response:", ferr)
+		}
+		return err
+	}
+}
+
+// tokenScopesValid checks the scopes are the known operations or services and not wider than the
+// scopes of the request token if it was used
+func (e *Processor) tokenScopesValid(c echo.Context, scopes []string) error {
+	known := map[string]bool{}
+	for _, op := range e.operations {
+		known[op.ID] = true
+		for _, tag := range op.Tags {
+			known[tag] = true
+		}
+	}
+	for _, scope := range scopes {
+		if !known[scope] {
+			return fmt.Errorf("Unknown scope %q, should be API service or operation", scope)
+		}
+	}
+	if token, ok := c.Get("token").(*types.UserToken); ok && len(token.Scopes) > 0 {
+		if len(scopes) == 0 {
+			return fmt.Errorf("Scoped token can't create the token without scopes")
+		}
+		for _, scope := range scopes {
+			if !slices.Contains(token.Scopes, scope) {
+				return fmt.Errorf("Scoped token can't create the token with wider scope %q", scope)
+			}
+		}
+	}
+	return nil
+}
+
 // UserMeGet API call processor
 func (*Processor) UserMeGet(c echo.Context) error {
 	user, ok := c.Get("user").(*types.User)
@@ -76,6 +404,102 @@ func (*Processor) UserMeGet(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
+// UserMePermissionsGet API call processor
+func (e *Processor) UserMePermissionsGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	return c.JSON(http.StatusOK, e.operationPermissions(user))
+}
+
+// UserMeRateLimitGet API call processor
+func (e *Processor) UserMeRateLimitGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	return c.JSON(http.StatusOK, e.fish.RateLimitListUser(user.Name))
+}
+
+// PreferenceListGet API call processor
+func (e *Processor) PreferenceListGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	out, err := e.fish.PreferenceList(user.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the preferences: %v", err)})
+		return fmt.Errorf("Unable to get the preferences: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// PreferenceGet API call processor
+func (e *Processor) PreferenceGet(c echo.Context, key string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	out, err := e.fish.PreferenceGet(user.Name, key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Preference not found: %v", err)})
+		return fmt.Errorf("Preference not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// PreferencePut API call processor
+func (e *Processor) PreferencePut(c echo.Context, key string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	var data types.Preference
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	data.UserName = user.Name
+	data.Key = key
+
+	if err := e.fish.PreferenceSet(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to set preference", err))
+		return fmt.Errorf("Unable to set preference: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// PreferenceDelete API call processor
+func (e *Processor) PreferenceDelete(c echo.Context, key string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	if err := e.fish.PreferenceDelete(user.Name, key); err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to remove preference: %v", err)})
+		return fmt.Errorf("Unable to remove preference: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, H{"message": "Preference removed"})
+}
+
 // UserListGet API call processor
 func (e *Processor) UserListGet(c echo.Context, params types.UserListGetParams) error {
 	// Only admin can list users
@@ -89,11 +513,13 @@ func (e *Processor) UserListGet(c echo.Context, params types.UserListGetParams)
 		return fmt.Errorf("Only 'admin' user can list users")
 	}
 
-	out, err := e.fish.UserFind(params.Filter)
+	page := fish.NewListPage(params.PageSize, params.PageToken, params.Sort)
+	out, err := e.fish.UserFind(params.Filter, page, params.Report != nil && *params.Report)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the user list: %v", err)})
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the user list: %v", err)})
 		return fmt.Errorf("Unable to get the user list: %w", err)
 	}
+	listPageHeader(c, page)
 
 	return c.JSON(http.StatusOK, out)
 }
@@ -146,8 +572,10 @@ func (e *Processor) UserCreateUpdatePost(c echo.Context) error {
 	modUser, err := e.fish.UserGet(data.Name)
 	if err == nil {
 		// Updating existing user
+		before := *modUser
 		modUser.Hash = crypt.NewHash(password, nil)
 		e.fish.UserSave(modUser)
+		audit(c, "User", modUser.Name, &before, modUser)
 	} else {
 		// Creating new user
 		password, modUser, err = e.fish.UserNew(data.Name, password)
@@ -155,6 +583,7 @@ func (e *Processor) UserCreateUpdatePost(c echo.Context) error {
 			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create user: %v", err)})
 			return fmt.Errorf("Unable to create user: %w", err)
 		}
+		audit(c, "User", modUser.Name, nil, modUser)
 	}
 
 	// Fill the output values
@@ -182,509 +611,2440 @@ func (e *Processor) UserDelete(c echo.Context, name string) error {
 		return fmt.Errorf("Only 'admin' user can delete user")
 	}
 
+	before, _ := e.fish.UserGet(name)
 	if err := e.fish.UserDelete(name); err != nil {
 		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("User delete failed with error: %v", err)})
 		return fmt.Errorf("User delete failed with error: %w", err)
 	}
+	audit(c, "User", name, before, nil)
 
 	return c.JSON(http.StatusOK, H{"message": "User removed"})
 }
 
-// ResourceListGet API call processor
-func (e *Processor) ResourceListGet(c echo.Context, params types.ResourceListGetParams) error {
-	// Only admin can list the resources
+// UserGrantListGet API call processor
+func (e *Processor) UserGrantListGet(c echo.Context, name string) error {
+	// Only admin or the user itself can see the grants
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can list resource"})
-		return fmt.Errorf("Only 'admin' user can list resource")
+	if user.Name != "admin" && user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or user itself can list the user grants"})
+		return fmt.Errorf("Only 'admin' user or user itself can list the user grants")
 	}
 
-	out, err := e.fish.ResourceFind(params.Filter)
+	out, err := e.fish.RoleGrantListUser(name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the resource list: %v", err)})
-		return fmt.Errorf("Unable to get the resource list: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the user grants list: %v", err)})
+		return fmt.Errorf("Unable to get the user grants list: %w", err)
 	}
 
 	return c.JSON(http.StatusOK, out)
 }
 
-// ResourceGet API call processor
-func (e *Processor) ResourceGet(c echo.Context, uid types.ResourceUID) error {
-	// Only admin can get the resource directly
+// UserGrantCreatePost API call processor
+func (e *Processor) UserGrantCreatePost(c echo.Context, name string) error {
+	// Only admin can grant roles, the temporary granted roles are not allowing that
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
 	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can get resource"})
-		return fmt.Errorf("Only 'admin' user can get resource")
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can grant roles"})
+		return fmt.Errorf("Only 'admin' user can grant roles")
 	}
 
-	out, err := e.fish.ResourceGet(uid)
-	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
-		return fmt.Errorf("Resource not found: %w", err)
+	var data types.RoleGrant
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
 	}
+	data.UserName = name
+	data.GrantedBy = user.Name
 
-	return c.JSON(http.StatusOK, out)
+	if err := e.fish.RoleGrantCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to grant the role: %v", err)})
+		return fmt.Errorf("Unable to grant the role: %w", err)
+	}
+	audit(c, "RoleGrant", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
 }
 
-// ResourceAccessPut API call processor
-func (e *Processor) ResourceAccessPut(c echo.Context, uid types.ResourceUID) error {
+// UserTokenListGet API call processor
+func (e *Processor) UserTokenListGet(c echo.Context, name string) error {
+	// Only admin or the user itself can see the tokens
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-
-	res, err := e.fish.ResourceGet(uid)
-	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
-		return fmt.Errorf("Resource not found: %w", err)
+	if user.Name != "admin" && user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or user itself can list the user tokens"})
+		return fmt.Errorf("Only 'admin' user or user itself can list the user tokens")
 	}
 
-	// Only the owner and admin can create access for application resource
-	app, err := e.fish.ApplicationGet(res.ApplicationUID)
+	out, err := e.fish.UserTokenListUser(name)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", res.ApplicationUID)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", res.ApplicationUID, err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the user tokens list: %v", err)})
+		return fmt.Errorf("Unable to get the user tokens list: %w", err)
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner & admin can assign service mapping to the Application"})
-		return fmt.Errorf("Only the owner & admin can assign service mapping to the Application")
+	for i := range out {
+		out[i].Hash = crypt.Hash{}
 	}
 
-	pwd := crypt.RandString(64)
-	// The proxy password is temporary (for the lifetime of the Resource) and one-time
-	// so lack of salt will not be a big deal - the params will contribute to salt majorily.
-	pwdHash := crypt.NewHash(pwd, []byte{}).Hash
-	key, err := crypt.GenerateSSHKey()
-	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": "Unable to generate SSH key"})
-		return fmt.Errorf("Unable to generate SSH key: %w", err)
+	return c.JSON(http.StatusOK, out)
+}
+
+// UserTokenCreatePost API call processor
+func (e *Processor) UserTokenCreatePost(c echo.Context, name string) error {
+	// Only admin or the user itself can create the tokens, admin uses it for service accounts
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
 	}
-	pubkey, err := crypt.GetSSHPubKeyFromPem(key)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": "Unable to generate SSH public key"})
-		return fmt.Errorf("Unable to generate SSH public key: %w", err)
+	if user.Name != "admin" && user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or user itself can create the user tokens"})
+		return fmt.Errorf("Only 'admin' user or user itself can create the user tokens")
 	}
-	rAccess := types.ResourceAccess{
-		ResourceUID: res.UID,
-		// Storing address of the proxy to give the user idea of where to connect to.
-		// Later when cluster will be here - it could contain a different node IP instead, because
-		// this particular one could not be able to serve the connection.
-		Address:  e.fish.GetProxySSHEndpoint(),
-		Username: user.Name,
-		// We should not store clear password, so convert it to salted hash
-		Password: string(pwdHash),
-		// Key need to be stored as public key
-		Key: string(pubkey),
+
+	var data types.UserToken
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
 	}
-	e.fish.ResourceAccessCreate(&rAccess)
+	if err := e.tokenScopesValid(c, data.Scopes); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create the token: %v", err)})
+		return fmt.Errorf("Unable to create the token: %w", err)
+	}
+	data.UserName = name
+	data.CreatedBy = user.Name
 
-	// Now database has had the hashed credentials stored, we store the original
-	// values to return so user have access to the actual credentials.
-	rAccess.Password = pwd
-	rAccess.Key = string(key)
+	if err := e.fish.UserTokenCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create the token: %v", err)})
+		return fmt.Errorf("Unable to create the token: %w", err)
+	}
+	data.Hash = crypt.Hash{}
+	audit(c, "UserToken", data.UID.String(), nil, &data)
 
-	return c.JSON(http.StatusOK, rAccess)
+	return c.JSON(http.StatusOK, data)
 }
 
-// ApplicationListGet API call processor
-func (e *Processor) ApplicationListGet(c echo.Context, params types.ApplicationListGetParams) error {
-	out, err := e.fish.ApplicationFind(params.Filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the application list: %v", err)})
-		return fmt.Errorf("Unable to get the application list: %w", err)
-	}
-
-	// Filter the output by owner
+// UserSubscriptionListGet API call processor
+func (e *Processor) UserSubscriptionListGet(c echo.Context, name string) error {
+	// Only admin or the user itself can see the subscriptions
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		var ownerOut []types.Application
-		for _, app := range out {
-			if app.OwnerName == user.Name {
-				ownerOut = append(ownerOut, app)
-			}
-		}
-		out = ownerOut
+	if user.Name != "admin" && user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or user itself can list the user subscriptions"})
+		return fmt.Errorf("Only 'admin' user or user itself can list the user subscriptions")
 	}
 
-	return c.JSON(http.StatusOK, out)
-}
-
-// ApplicationGet API call processor
-func (e *Processor) ApplicationGet(c echo.Context, uid types.ApplicationUID) error {
-	app, err := e.fish.ApplicationGet(uid)
+	out, err := e.fish.SubscriptionListUser(name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application not found: %v", err)})
-		return fmt.Errorf("Application not found: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the user subscriptions list: %v", err)})
+		return fmt.Errorf("Unable to get the user subscriptions list: %w", err)
 	}
 
-	// Only the owner of the application (or admin) can request it
+	return c.JSON(http.StatusOK, out)
+}
+
+// UserSubscriptionCreatePost API call processor
+func (e *Processor) UserSubscriptionCreatePost(c echo.Context, name string) error {
+	// Only admin or the user itself can subscribe the user
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner and admin can request the Application"})
-		return fmt.Errorf("Only the owner and admin can request the Application")
+	if user.Name != "admin" && user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or user itself can create the user subscriptions"})
+		return fmt.Errorf("Only 'admin' user or user itself can create the user subscriptions")
 	}
 
-	return c.JSON(http.StatusOK, app)
-}
-
-// ApplicationCreatePost API call processor
+	var data types.Subscription
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	data.UserName = name
+
+	if err := e.fish.SubscriptionCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create the subscription: %v", err)})
+		return fmt.Errorf("Unable to create the subscription: %w", err)
+	}
+	audit(c, "Subscription", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// UserSubscriptionDelete API call processor
+func (e *Processor) UserSubscriptionDelete(c echo.Context, uid types.SubscriptionUID) error {
+	// Only admin or the subscription owner can remove it
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	sub, err := e.fish.SubscriptionGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the subscription: %s", uid)})
+		return fmt.Errorf("Unable to find the subscription: %s, %w", uid, err)
+	}
+	if user.Name != "admin" && user.Name != sub.UserName {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or subscription owner can remove the subscription"})
+		return fmt.Errorf("Only 'admin' user or subscription owner can remove the subscription")
+	}
+	if err := e.fish.SubscriptionDelete(uid); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to remove the subscription: %v", err)})
+		return fmt.Errorf("Unable to remove the subscription: %w", err)
+	}
+	audit(c, "Subscription", sub.UID.String(), sub, nil)
+
+	return c.JSON(http.StatusOK, sub)
+}
+
+// UserOTPPut API call processor
+func (e *Processor) UserOTPPut(c echo.Context, name string) error {
+	// Only the User itself can get the secret, otherwise it's not a second factor anymore
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != name {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the User can enroll the own OTP"})
+		return fmt.Errorf("Only the User can enroll the own OTP")
+	}
+
+	uri, err := e.fish.UserOTPEnroll(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to enroll OTP: %v", err)})
+		return fmt.Errorf("Unable to enroll OTP: %w", err)
+	}
+	audit(c, "UserOTP", name, nil, nil)
+
+	return c.JSON(http.StatusOK, types.UserOTP{Uri: uri})
+}
+
+// UserOTPDelete API call processor
+func (e *Processor) UserOTPDelete(c echo.Context, name string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can reset OTP"})
+		return fmt.Errorf("Only 'admin' user can reset OTP")
+	}
+
+	if err := e.fish.UserOTPReset(name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to reset OTP: %v", err)})
+		return fmt.Errorf("Unable to reset OTP: %w", err)
+	}
+	audit(c, "UserOTP", name, nil, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "OTP reset"})
+}
+
+// UserQuotaGet API call processor
+func (e *Processor) UserQuotaGet(c echo.Context, name string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the User, admin and operator can get the User quota"})
+		return fmt.Errorf("Only the User, admin and operator can get the User quota")
+	}
+
+	out, err := e.fish.QuotaUsageGet(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the User quota: %v", err)})
+		return fmt.Errorf("Unable to get the User quota: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// UserQuotaPut API call processor
+func (e *Processor) UserQuotaPut(c echo.Context, name string) error {
+	// Only admin can set quotas, otherwise operator could lift the own limits
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can set quotas"})
+		return fmt.Errorf("Only 'admin' user can set quotas")
+	}
+
+	var data types.Quota
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	data.UserName = name
+
+	before, _ := e.fish.QuotaGet(name)
+	if err := e.fish.QuotaSet(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to set the quota: %v", err)})
+		return fmt.Errorf("Unable to set the quota: %w", err)
+	}
+	audit(c, "Quota", name, before, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// UserQuotaDelete API call processor
+func (e *Processor) UserQuotaDelete(c echo.Context, name string) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can remove quotas"})
+		return fmt.Errorf("Only 'admin' user can remove quotas")
+	}
+
+	before, _ := e.fish.QuotaGet(name)
+	if err := e.fish.QuotaDelete(name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to remove the quota: %v", err)})
+		return fmt.Errorf("Unable to remove the quota: %w", err)
+	}
+	audit(c, "Quota", name, before, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Quota removed"})
+}
+
+// GrantRevokeDelete API call processor
+func (e *Processor) GrantRevokeDelete(c echo.Context, uid types.RoleGrantUID) error {
+	// Only admin can revoke the grants
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can revoke grants"})
+		return fmt.Errorf("Only 'admin' user can revoke grants")
+	}
+
+	grant, err := e.fish.RoleGrantGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the grant: %s", uid)})
+		return fmt.Errorf("Unable to find the grant: %s, %w", uid, err)
+	}
+	before := *grant
+	if err := e.fish.RoleGrantRevoke(grant, user.Name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to revoke the grant: %v", err)})
+		return fmt.Errorf("Unable to revoke the grant: %w", err)
+	}
+	audit(c, "RoleGrant", grant.UID.String(), &before, grant)
+
+	return c.JSON(http.StatusOK, grant)
+}
+
+// RoleListGet API call processor
+func (e *Processor) RoleListGet(c echo.Context) error {
+	out, err := e.fish.RoleFind()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the role list: %v", err)})
+		return fmt.Errorf("Unable to get the role list: %w", err)
+	}
+	if out == nil {
+		out = []types.Role{}
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// RoleCreateUpdatePost API call processor
+func (e *Processor) RoleCreateUpdatePost(c echo.Context) error {
+	// Only admin can manage the roles, same as the grants
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can manage roles"})
+		return fmt.Errorf("Only 'admin' user can manage roles")
+	}
+
+	var data types.Role
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	before, err := e.fish.RoleGet(data.Name)
+	if err == nil {
+		data.CreatedAt = before.CreatedAt
+	} else {
+		before = nil
+	}
+	if err := e.fish.RoleSave(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to save role: %v", err)})
+		return fmt.Errorf("Unable to save role: %w", err)
+	}
+	audit(c, "Role", data.Name, before, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// RoleGet API call processor
+func (e *Processor) RoleGet(c echo.Context, name types.RoleName) error {
+	out, err := e.fish.RoleGet(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Role not found: %v", err)})
+		return fmt.Errorf("Role not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// RoleDelete API call processor
+func (e *Processor) RoleDelete(c echo.Context, name types.RoleName) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can delete roles"})
+		return fmt.Errorf("Only 'admin' user can delete roles")
+	}
+
+	before, _ := e.fish.RoleGet(name)
+	if err := e.fish.RoleDelete(name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Role delete failed with error: %v", err)})
+		return fmt.Errorf("Role delete failed with error: %w", err)
+	}
+	audit(c, "Role", name, before, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Role removed"})
+}
+
+// UserTokenRevokeDelete API call processor
+func (e *Processor) UserTokenRevokeDelete(c echo.Context, uid types.UserTokenUID) error {
+	// Only admin or the token owner can revoke the token
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	token, err := e.fish.UserTokenGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the token: %s", uid)})
+		return fmt.Errorf("Unable to find the token: %s, %w", uid, err)
+	}
+	if user.Name != "admin" && user.Name != token.UserName {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user or token owner can revoke the token"})
+		return fmt.Errorf("Only 'admin' user or token owner can revoke the token")
+	}
+	token.Hash = crypt.Hash{}
+	before := *token
+	if err := e.fish.UserTokenRevoke(token, user.Name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to revoke the token: %v", err)})
+		return fmt.Errorf("Unable to revoke the token: %w", err)
+	}
+	audit(c, "UserToken", token.UID.String(), &before, token)
+
+	return c.JSON(http.StatusOK, token)
+}
+
+// resourceHideSecrets removes the Resource credentials and metadata (which usually contains the
+// agent secrets) if the user is not admin or the Application owner, so the operator role allows
+// to manage the Resources, but not to access them
+func resourceHideSecrets(user *types.User, owner string, res *types.Resource) {
+	if user.Name == "admin" || user.Name == owner {
+		return
+	}
+	res.Authentication = nil
+	res.Metadata = util.UnparsedJSON("{}")
+}
+
+// ResourceListGet API call processor
+func (e *Processor) ResourceListGet(c echo.Context, params types.ResourceListGetParams) error {
+	// Only admin can list the resources
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can list resource"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can list resource")
+	}
+
+	out, err := e.fish.ResourceFind(params.Filter, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the resource list: %v", err)})
+		return fmt.Errorf("Unable to get the resource list: %w", err)
+	}
+	for i := range out {
+		resourceHideSecrets(user, "", &out[i])
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ResourceGet API call processor
+func (e *Processor) ResourceGet(c echo.Context, uid types.ResourceUID) error {
+	// Only admin can get the resource directly
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get resource"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get resource")
+	}
+
+	out, err := e.fish.ResourceGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
+		return fmt.Errorf("Resource not found: %w", err)
+	}
+	resourceHideSecrets(user, "", out)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ResourceAccessPut API call processor
+func (e *Processor) ResourceAccessPut(c echo.Context, uid types.ResourceUID, params types.ResourceAccessPutParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	res, err := e.fish.ResourceGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
+		return fmt.Errorf("Resource not found: %w", err)
+	}
+
+	// Only the owner and admin can create access for application resource, operator role allows
+	// to manage the Resources but not to access them
+	app, err := e.fish.ApplicationGet(res.ApplicationUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", res.ApplicationUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", res.ApplicationUID, err)
+	}
+	if app.OwnerName != user.Name && user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner and admin can access the Application resource"})
+		return fmt.Errorf("Only the owner and admin can access the Application resource")
+	}
+
+	// Sensitive Labels require the second factor from the one who will use the access
+	label, err := e.fish.LabelGet(app.LabelUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Label: %s", app.LabelUID)})
+		return fmt.Errorf("Unable to find the Label: %s, %w", app.LabelUID, err)
+	}
+	if label.AccessOtp {
+		if params.Otp == nil || *params.Otp == "" {
+			c.JSON(http.StatusBadRequest, H{"message": "OTP code is required to access the Resource"})
+			return fmt.Errorf("OTP code is required to access the Resource")
+		}
+		if err := e.fish.UserOTPCheck(user.Name, *params.Otp); err != nil {
+			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to verify OTP: %v", err)})
+			return fmt.Errorf("Unable to verify OTP: %w", err)
+		}
+	}
+
+	pwd := crypt.RandString(64)
+	// The proxy password is temporary (for the lifetime of the Resource) and one-time
+	// so lack of salt will not be a big deal - the params will contribute to salt majorily.
+	pwdHash := crypt.NewHash(pwd, []byte{}).Hash
+	key, err := crypt.GenerateSSHKey()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": "Unable to generate SSH key"})
+		return fmt.Errorf("Unable to generate SSH key: %w", err)
+	}
+	pubkey, err := crypt.GetSSHPubKeyFromPem(key)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": "Unable to generate SSH public key"})
+		return fmt.Errorf("Unable to generate SSH public key: %w", err)
+	}
+	rAccess := types.ResourceAccess{
+		ResourceUID: res.UID,
+		// Storing address of the proxy to give the user idea of where to connect to.
+		// Later when cluster will be here - it could contain a different node IP instead, because
+		// this particular one could not be able to serve the connection.
+		Address:  e.fish.GetProxySSHEndpoint(),
+		Username: user.Name,
+		// We should not store clear password, so convert it to salted hash
+		Password: string(pwdHash),
+		// Key need to be stored as public key
+		Key: string(pubkey),
+		// Allows the user to pin the proxy host key instead of disabling StrictHostKeyChecking
+		HostKeyFingerprint: e.fish.GetProxySSHFingerprint(),
+		AddressFamily:      util.AddressFamily(e.fish.GetProxySSHEndpoint()),
+	}
+	e.fish.ResourceAccessCreate(&rAccess)
+	audit(c, "ResourceAccess", rAccess.UID.String(), nil, &rAccess)
+
+	// Now database has had the hashed credentials stored, we store the original
+	// values to return so user have access to the actual credentials.
+	rAccess.Password = pwd
+	rAccess.Key = string(key)
+
+	return c.JSON(http.StatusOK, rAccess)
+}
+
+// ResourceAccessMethodsGet API call processor
+func (e *Processor) ResourceAccessMethodsGet(c echo.Context, uid types.ResourceUID) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	res, err := e.fish.ResourceGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
+		return fmt.Errorf("Resource not found: %w", err)
+	}
+
+	// Same as the access itself - only owner and admin can see the ways to reach the Resource
+	app, err := e.fish.ApplicationGet(res.ApplicationUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", res.ApplicationUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", res.ApplicationUID, err)
+	}
+	if app.OwnerName != user.Name && user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner and admin can access the Application resource"})
+		return fmt.Errorf("Only the owner and admin can access the Application resource")
+	}
+
+	return c.JSON(http.StatusOK, e.fish.ResourceAccessMethods(res))
+}
+
+// ApplicationListGet API call processor
+func (e *Processor) ApplicationListGet(c echo.Context, params types.ApplicationListGetParams) error {
+	out, err := e.applicationList(c, params)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationFullListGet API call processor
+func (e *Processor) ApplicationFullListGet(c echo.Context, params types.ApplicationFullListGetParams) error {
+	apps, err := e.applicationList(c, types.ApplicationListGetParams(params))
+	if err != nil {
+		return err
+	}
+	out, err := e.fish.ApplicationFullList(apps, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the application related objects: %v", err)})
+		return fmt.Errorf("Unable to get the application related objects: %w", err)
+	}
+	user := c.Get("user").(*types.User)
+	for i := range out {
+		if out[i].Resource != nil {
+			resourceHideSecrets(user, out[i].Application.OwnerName, out[i].Resource)
+		}
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// applicationAccess returns true if the User is the owner of the Application, admin, operator or
+// maintainer of the Application Project
+func (e *Processor) applicationAccess(user *types.User, app *types.Application) bool {
+	if app.OwnerName == user.Name || e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		return true
+	}
+	return e.fish.ProjectRole(app.Project, user.Name) == types.ProjectMemberRoleMaintainer
+}
+
+// labelAccess returns true if the User could see the Label: it's shared, the User is member of
+// the Label Project, admin or operator
+func (e *Processor) labelAccess(user *types.User, label *types.Label) bool {
+	if label.Project == "" || e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		return true
+	}
+	return e.fish.ProjectRole(label.Project, user.Name) != ""
+}
+
+// labelManage returns true if the User could create and delete the Labels of the Project, the
+// shared Labels are managed only by admin and operator
+func (e *Processor) labelManage(user *types.User, project string) bool {
+	if e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		return true
+	}
+	return e.fish.ProjectRole(project, user.Name) == types.ProjectMemberRoleMaintainer
+}
+
+// applicationList selects the Applications visible to the User and sets the next page header,
+// the error response is already sent when error is returned
+func (e *Processor) applicationList(c echo.Context, params types.ApplicationListGetParams) ([]types.Application, error) {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return nil, fmt.Errorf("Not authentified")
+	}
+
+	sel := &types.ApplicationSelector{
+		OwnerName: params.OwnerName,
+		LabelUID:  params.LabelUid,
+		Status:    params.Status,
+		OlderThan: params.OlderThan,
+		NodeUID:   params.NodeUid,
+		Project:   params.Project,
+	}
+	// Filter the output by owner in the query to keep the pages full, the Project maintainer
+	// could see all the Applications of the Project
+	maintainer := sel.Project != nil && e.fish.ProjectRole(*sel.Project, user.Name) == types.ProjectMemberRoleMaintainer
+	if !maintainer && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		if sel.OwnerName != nil && *sel.OwnerName != user.Name {
+			return []types.Application{}, nil
+		}
+		sel.OwnerName = &user.Name
+	}
+	page := fish.NewListPage(params.PageSize, params.PageToken, params.Sort)
+	out, err := e.fish.ApplicationSelect(params.Filter, sel, page, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the application list: %v", err)})
+		return nil, fmt.Errorf("Unable to get the application list: %w", err)
+	}
+	listPageHeader(c, page)
+
+	return out, nil
+}
+
+// ApplicationBatchListGet API call processor
+func (e *Processor) ApplicationBatchListGet(c echo.Context, params types.ApplicationBatchListGetParams) error {
+	out, err := e.fish.ApplicationBatchFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the batch list: %v", err)})
+		return fmt.Errorf("Unable to get the batch list: %w", err)
+	}
+
+	// Filter the output by owner
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		var ownerOut []types.ApplicationBatch
+		for _, b := range out {
+			if b.OwnerName == user.Name {
+				ownerOut = append(ownerOut, b)
+			}
+		}
+		out = ownerOut
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationBatchCreatePost API call processor
+func (e *Processor) ApplicationBatchCreatePost(c echo.Context) error {
+	var data types.ApplicationBatch
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// Set the User field out of the authorized user
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	data.OwnerName = user.Name
+
+	if err := e.fish.ApplicationBatchCreate(c.Request().Context(), &data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to create batch", err))
+		return fmt.Errorf("Unable to create batch: %w", err)
+	}
+	audit(c, "ApplicationBatch", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ApplicationBatchGet API call processor
+func (e *Processor) ApplicationBatchGet(c echo.Context, uid types.ApplicationBatchUID) error {
+	b, err := e.fish.ApplicationBatchGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application batch not found: %v", err)})
+		return fmt.Errorf("Application batch not found: %w", err)
+	}
+
+	// Only the owner of the batch (or admin and operator) can request it
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if b.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application batch"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application batch")
+	}
+
+	return c.JSON(http.StatusOK, b)
+}
+
+// ApplicationBatchStateGet API call processor
+func (e *Processor) ApplicationBatchStateGet(c echo.Context, uid types.ApplicationBatchUID) error {
+	b, err := e.fish.ApplicationBatchGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application batch not found: %v", err)})
+		return fmt.Errorf("Application batch not found: %w", err)
+	}
+
+	// Only the owner of the batch (or admin and operator) can request the state
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if b.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application batch state"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application batch state")
+	}
+
+	out, err := e.fish.ApplicationBatchStateGet(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the batch state: %v", err)})
+		return fmt.Errorf("Unable to get the batch state: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationGet API call processor
+func (e *Processor) ApplicationGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application not found: %v", err)})
+		return fmt.Errorf("Application not found: %w", err)
+	}
+
+	// Only the owner of the application (or admin and operator) can request it
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application")
+	}
+
+	return c.JSON(http.StatusOK, app)
+}
+
+// ApplicationCreatePost API call processor
 func (e *Processor) ApplicationCreatePost(c echo.Context) error {
 	var data types.Application
 	if err := c.Bind(&data); err != nil {
-		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
+		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// Set the User field out of the authorized user
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	data.OwnerName = user.Name
+
+	if err := e.fish.ApplicationCreate(c.Request().Context(), &data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to create application", err))
+		return fmt.Errorf("Unable to create application: %w", err)
+	}
+	audit(c, "Application", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ApplicationResourceGet API call processor
+func (e *Processor) ApplicationResourceGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) can request the resource
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application resource"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application resource")
+	}
+
+	out, err := e.fish.ResourceGetByApplication(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
+		return fmt.Errorf("Resource not found: %w", err)
+	}
+	resourceHideSecrets(user, app.OwnerName, out)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationFullGet API call processor
+func (e *Processor) ApplicationFullGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) can request it
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application")
+	}
+
+	out, err := e.fish.ApplicationFullList([]types.Application{*app}, false)
+	if err != nil || len(out) != 1 {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the application related objects: %v", err)})
+		return fmt.Errorf("Unable to get the application related objects: %w", err)
+	}
+	if out[0].Resource != nil {
+		resourceHideSecrets(user, app.OwnerName, out[0].Resource)
+	}
+
+	return c.JSON(http.StatusOK, out[0])
+}
+
+// ApplicationSecretCreatePost API call processor
+func (e *Processor) ApplicationSecretCreatePost(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) can send secrets to the resource
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if app.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can send secrets to the Application"})
+		return fmt.Errorf("Only the owner, admin and operator can send secrets to the Application")
+	}
+
+	if err := e.fish.ApplicationIsAllocated(uid); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": "The Application is not allocated"})
+		return fmt.Errorf("The Application is not allocated: %s, %w", uid, err)
+	}
+
+	var data types.ApplicationSecret
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	data.ApplicationUID = app.UID
+
+	if err := e.fish.ApplicationSecretCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to send the secret: %v", err)})
+		return fmt.Errorf("Unable to send the secret: %w", err)
+	}
+	audit(c, "ApplicationSecret", data.UID.String(), nil, &data)
+
+	// No need to return the ciphertext back
+	data.Ciphertext = ""
+	return c.JSON(http.StatusOK, data)
+}
+
+// ApplicationStateGet API call processor
+func (e *Processor) ApplicationStateGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) can request the status
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can request the Application status"})
+		return fmt.Errorf("Only the owner, admin and operator can request the Application status")
+	}
+
+	out, err := e.fish.ApplicationStateGetByApplication(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application status not found: %v", err)})
+		return fmt.Errorf("Application status not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationStateStreamGet API call processor
+func (e *Processor) ApplicationStateStreamGet(c echo.Context, params types.ApplicationStateStreamGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	// Only the owner of the application (or admin, operator and Project maintainer) can receive
+	// its states
+	all := e.fish.UserHasRole(user.Name, fish.RoleOperator)
+	// The Applications are needed to check the owner and the filters by Application properties
+	needApp := !all || params.LabelUid != nil || params.OwnerName != nil || params.Project != nil
+	apps := map[types.ApplicationUID]*types.Application{}
+
+	var sub *fish.ApplicationEventSubscription
+	var cancel func()
+	if params.ResumeFrom != nil {
+		var err error
+		if sub, cancel, err = e.fish.ApplicationEventSubscribeFrom(uint64(*params.ResumeFrom)); err != nil {
+			c.JSON(http.StatusGone, H{"message": fmt.Sprintf("Unable to resume the stream: %v", err)})
+			return fmt.Errorf("Unable to resume the stream: %w", err)
+		}
+	} else {
+		sub, cancel = e.fish.ApplicationEventSubscribe()
+	}
+	defer cancel()
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	enc := json.NewEncoder(c.Response())
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-sub.Notify():
+		}
+		events, active := sub.Next()
+		for _, event := range events {
+			appUID := event.State.ApplicationUID
+			if params.ApplicationUid != nil && !slices.Contains(*params.ApplicationUid, appUID) {
+				continue
+			}
+			if params.Status != nil && !slices.Contains(*params.Status, event.State.Status) {
+				continue
+			}
+			if needApp {
+				app, ok := apps[appUID]
+				if !ok {
+					// Not found Applications are cached as nil to skip them without DB request
+					var err error
+					if app, err = e.fish.ApplicationGet(appUID); err != nil {
+						app = nil
+					}
+					apps[appUID] = app
+				}
+				if app == nil || (!all && !e.applicationAccess(user, app)) {
+					continue
+				}
+				if params.Project != nil && !slices.Contains(*params.Project, app.Project) {
+					continue
+				}
+				if params.LabelUid != nil && !slices.Contains(*params.LabelUid, app.LabelUID) {
+					continue
+				}
+				if params.OwnerName != nil && !slices.Contains(*params.OwnerName, app.OwnerName) {
+					continue
+				}
+			}
+			if err := enc.Encode(event); err != nil {
+				return fmt.Errorf("Unable to send Application state: %w", err)
+			}
+		}
+		c.Response().Flush()
+		if !active {
+			return fmt.Errorf("Application state stream is too slow, closing")
+		}
+	}
+}
+
+// ApplicationAnnotationListGet API call processor
+func (e *Processor) ApplicationAnnotationListGet(c echo.Context, appUID types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(appUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could get the annotations
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application, admin and operator can get the Application annotations"})
+		return fmt.Errorf("Only the owner of Application, admin and operator can get the Application annotations")
+	}
+
+	out, err := e.fish.ApplicationAnnotationListByApplication(appUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the Application annotations: %v", err)})
+		return fmt.Errorf("Unable to get the Application annotations: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationTimelineGet API call processor
+func (e *Processor) ApplicationTimelineGet(c echo.Context, appUID types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(appUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could get the timeline
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application, admin and operator can get the Application timeline"})
+		return fmt.Errorf("Only the owner of Application, admin and operator can get the Application timeline")
+	}
+
+	out, err := e.fish.ApplicationTimeline(appUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the Application timeline: %v", err)})
+		return fmt.Errorf("Unable to get the Application timeline: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationTaskListGet API call processor
+func (e *Processor) ApplicationTaskListGet(c echo.Context, appUID types.ApplicationUID, params types.ApplicationTaskListGetParams) error {
+	app, err := e.fish.ApplicationGet(appUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could get the tasks
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application, admin and operator can get the Application Tasks"})
+		return fmt.Errorf("Only the owner of Application, admin and operator can get the Application Tasks")
+	}
+
+	out, err := e.fish.ApplicationTaskFindByApplication(appUID, params.Filter, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the Application Tasks list: %v", err)})
+		return fmt.Errorf("Unable to get the Application Tasks list: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationTaskCreatePost API call processor
+func (e *Processor) ApplicationTaskCreatePost(c echo.Context, appUID types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(appUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could create the tasks
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if app.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application, admin and operator can create the Application Tasks"})
+		return fmt.Errorf("Only the owner of Application, admin and operator can create the Application Tasks")
+	}
+
+	var data types.ApplicationTask
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// Set Application UID for the task forcefully to not allow creating tasks for the other Apps
+	data.ApplicationUID = appUID
+
+	if err := e.fish.ApplicationTaskCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create ApplicationTask: %v", err)})
+		return fmt.Errorf("Unable to create ApplicationTask: %w", err)
+	}
+	audit(c, "ApplicationTask", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ApplicationTaskGet API call processor
+func (e *Processor) ApplicationTaskGet(c echo.Context, taskUID types.ApplicationTaskUID) error {
+	task, err := e.fish.ApplicationTaskGet(taskUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", taskUID)})
+		return fmt.Errorf("Unable to find the ApplicationTask: %s, %w", taskUID, err)
+	}
+
+	app, err := e.fish.ApplicationGet(task.ApplicationUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", task.ApplicationUID)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", task.ApplicationUID, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could get the attached task
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application, admin and operator can get the ApplicationTask"})
+		return fmt.Errorf("Only the owner of Application, admin and operator can get the ApplicationTask")
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+// ApplicationDeallocateGet API call processor
+func (e *Processor) ApplicationDeallocateGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could deallocate it
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can deallocate the Application resource"})
+		return fmt.Errorf("Only the owner, admin and operator can deallocate the Application resource")
+	}
+
+	as, err := e.fish.ApplicationDeallocate(app, user.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to deallocate the Application: %v", err)})
+		return fmt.Errorf("Unable to deallocate the Application: %s, %w", uid, err)
+	}
+	audit(c, "ApplicationState", as.UID.String(), nil, as)
+
+	return c.JSON(http.StatusOK, as)
+}
+
+// ApplicationDeallocateBatchPost API call processor
+func (e *Processor) ApplicationDeallocateBatchPost(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	var data types.ApplicationDeallocateBatch
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// Only admin and operator could deallocate the Applications of the other users
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		data.Selector.OwnerName = &user.Name
+	}
+
+	out, states, err := e.fish.ApplicationDeallocateBatch(&data.Selector, data.DryRun != nil && *data.DryRun, user.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to deallocate the Applications: %v", err)})
+		return fmt.Errorf("Unable to deallocate the Applications: %w", err)
+	}
+	for _, as := range states {
+		audit(c, "ApplicationState", as.UID.String(), nil, as)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApplicationExtendPost API call processor
+func (e *Processor) ApplicationExtendPost(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only the owner of the application (or admin and operator) could extend it's lifetime
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.applicationAccess(user, app) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can extend the Application resource lifetime"})
+		return fmt.Errorf("Only the owner, admin and operator can extend the Application resource lifetime")
+	}
+
+	var data types.ApplicationExtend
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	as, err := e.fish.ApplicationExtend(app, &data, user.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to extend the Application lifetime: %v", err)})
+		return fmt.Errorf("Unable to extend the Application lifetime: %s, %w", uid, err)
+	}
+	audit(c, "ApplicationState", as.UID.String(), nil, as)
+
+	return c.JSON(http.StatusOK, as)
+}
+
+// ApplicationDeallocateApproveGet API call processor
+func (e *Processor) ApplicationDeallocateApproveGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	// Only admin can approve the deallocate and it should be not the user requested it, operator
+	// role is not enough since it's the second person check of the regulated Applications
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can approve the Application deallocate"})
+		return fmt.Errorf("Only 'admin' user can approve the Application deallocate")
+	}
+
+	as, err := e.fish.ApplicationDeallocateApprove(app, user.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to approve the Application deallocate: %v", err)})
+		return fmt.Errorf("Unable to approve the Application deallocate: %s, %w", uid, err)
+	}
+	audit(c, "ApplicationState", as.UID.String(), nil, as)
+
+	return c.JSON(http.StatusOK, as)
+}
+
+// ApplicationApproveGet API call processor
+func (e *Processor) ApplicationApproveGet(c echo.Context, uid types.ApplicationUID) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleApprover) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'approver' user can approve the Application allocation"})
+		return fmt.Errorf("Only 'admin' or 'approver' user can approve the Application allocation")
+	}
+
+	as, err := e.fish.ApplicationApprove(app, user.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to approve the Application allocation: %v", err)})
+		return fmt.Errorf("Unable to approve the Application allocation: %s, %w", uid, err)
+	}
+	audit(c, "ApplicationState", as.UID.String(), nil, as)
+
+	return c.JSON(http.StatusOK, as)
+}
+
+// ApplicationRejectGet API call processor
+func (e *Processor) ApplicationRejectGet(c echo.Context, uid types.ApplicationUID, params types.ApplicationRejectGetParams) error {
+	app, err := e.fish.ApplicationGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
+		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+	}
+
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleApprover) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'approver' user can reject the Application allocation"})
+		return fmt.Errorf("Only 'admin' or 'approver' user can reject the Application allocation")
+	}
+
+	reason := ""
+	if params.Reason != nil {
+		reason = *params.Reason
+	}
+	as, err := e.fish.ApplicationReject(app, user.Name, reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to reject the Application allocation: %v", err)})
+		return fmt.Errorf("Unable to reject the Application allocation: %s, %w", uid, err)
+	}
+	audit(c, "ApplicationState", as.UID.String(), nil, as)
+
+	return c.JSON(http.StatusOK, as)
+}
+
+// labelHideSecrets removes the Label definitions Authentication password & key if the user is not
+// admin or operator, they are decrypted from database only for the ones who manage the Labels
+func (e *Processor) labelHideSecrets(c echo.Context, labels ...types.Label) {
+	user, ok := c.Get("user").(*types.User)
+	if ok && e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		return
+	}
+	for _, label := range labels {
+		for i, def := range label.Definitions {
+			if def.Authentication != nil {
+				auth := *def.Authentication
+				auth.Password, auth.Key = "", ""
+				label.Definitions[i].Authentication = &auth
+			}
+		}
+	}
+}
+
+// LabelListGet API call processor
+func (e *Processor) LabelListGet(c echo.Context, params types.LabelListGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	// The Project Labels are visible only to the Project members
+	var projects []string
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		var err error
+		if projects, err = e.fish.UserProjects(user.Name); err != nil {
+			c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the user projects: %v", err)})
+			return fmt.Errorf("Unable to get the user projects: %w", err)
+		}
+	}
+
+	page := fish.NewListPage(params.PageSize, params.PageToken, params.Sort)
+	out, err := e.fish.LabelFindVisible(params.Filter, projects, page, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the label list: %v", err)})
+		return fmt.Errorf("Unable to get the label list: %w", err)
+	}
+	listPageHeader(c, page)
+	e.labelHideSecrets(c, out...)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// LabelStatsGet API call processor
+func (e *Processor) LabelStatsGet(c echo.Context, params types.LabelStatsGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get label stats"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get label stats")
+	}
+
+	name := ""
+	if params.Name != nil {
+		name = *params.Name
+	}
+	out, err := e.fish.LabelStatsGet(name, params.Report != nil && *params.Report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the label stats: %v", err)})
+		return fmt.Errorf("Unable to get the label stats: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// LabelGet API call processor
+func (e *Processor) LabelGet(c echo.Context, uid types.LabelUID) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	out, err := e.fish.LabelGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Label not found: %v", err)})
+		return fmt.Errorf("Label not found: %w", err)
+	}
+	// The Label of the other Project is not found for the User
+	if !e.labelAccess(user, out) {
+		c.JSON(http.StatusNotFound, H{"message": "Label not found"})
+		return fmt.Errorf("Label not found: %s is in the other Project", uid)
+	}
+	e.labelHideSecrets(c, *out)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// LabelCreatePost API call processor
+func (e *Processor) LabelCreatePost(c echo.Context) error {
+	// Only admin, operator or the Project maintainer can create label
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	var data types.Label
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	if !e.labelManage(user, data.Project) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin', 'operator' or the Project maintainer user can create label"})
+		return fmt.Errorf("Only 'admin', 'operator' or the Project maintainer user can create label")
+	}
+	if err := e.fish.LabelCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to create label", err))
+		return fmt.Errorf("Unable to create label: %w", err)
+	}
+	audit(c, "Label", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// LabelApplyPost API call processor
+func (e *Processor) LabelApplyPost(c echo.Context) error {
+	// Only admin or operator can manage the labels
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can apply labels"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can apply labels")
+	}
+
+	var data types.LabelApply
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	// Only admin can manage the roles
+	if data.Roles != nil && user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can apply roles"})
+		return fmt.Errorf("Only 'admin' user can apply roles")
+	}
+	res, changes, err := e.fish.LabelApply(data)
+	for _, lc := range changes.Labels {
+		l := lc.After
+		if l == nil {
+			l = lc.Before
+		}
+		audit(c, "Label", l.UID.String(), lc.Before, lc.After)
+	}
+	for _, rc := range changes.Roles {
+		r := rc.After
+		if r == nil {
+			r = rc.Before
+		}
+		audit(c, "Role", r.Name, rc.Before, rc.After)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to apply labels: %v", err), "result": res})
+		return fmt.Errorf("Unable to apply labels: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// LabelExportGet API call processor
+func (e *Processor) LabelExportGet(c echo.Context, params types.LabelExportGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can export labels"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can export labels")
+	}
+
+	out, err := e.fish.LabelExport(params.Label)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to export labels: %v", err)})
+		return fmt.Errorf("Unable to export labels: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// LabelImportPost API call processor
+func (e *Processor) LabelImportPost(c echo.Context, params types.LabelImportPostParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can import labels"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can import labels")
+	}
+
+	var data types.LabelBundle
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	onConflict := ""
+	if params.OnConflict != nil {
+		onConflict = string(*params.OnConflict)
+	}
+	res, changes, err := e.fish.LabelImport(data, onConflict, params.DryRun != nil && *params.DryRun)
+	for i := range changes.Images {
+		audit(c, "Image", changes.Images[i].UID.String(), nil, &changes.Images[i])
+	}
+	for i := range changes.Removed {
+		audit(c, "Label", changes.Removed[i].UID.String(), &changes.Removed[i], nil)
+	}
+	for i := range changes.Created {
+		audit(c, "Label", changes.Created[i].UID.String(), nil, &changes.Created[i])
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to import labels: %v", err), "result": res})
+		return fmt.Errorf("Unable to import labels: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// LabelDelete API call processor
+func (e *Processor) LabelDelete(c echo.Context, uid types.LabelUID) error {
+	// Only admin, operator or the Project maintainer can delete label
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	before, _ := e.fish.LabelGet(uid)
+	if !e.labelManage(user, before.Project) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin', 'operator' or the Project maintainer user can delete Label"})
+		return fmt.Errorf("Only 'admin', 'operator' or the Project maintainer user can delete label")
+	}
+
+	err := e.fish.LabelDelete(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Label delete failed with error: %v", err)})
+		return fmt.Errorf("Label delete failed with error: %w", err)
+	}
+	audit(c, "Label", uid.String(), before, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Label removed"})
+}
+
+// ImageListGet API call processor
+func (e *Processor) ImageListGet(c echo.Context, params types.ImageListGetParams) error {
+	out, err := e.fish.ImageFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the image list: %v", err)})
+		return fmt.Errorf("Unable to get the image list: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ImageCreatePost API call processor
+func (e *Processor) ImageCreatePost(c echo.Context) error {
+	// Only admin or operator can manage the image catalog
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can create image"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can create image")
+	}
+
+	var data types.Image
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	if err := e.fish.ImageCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to create image", err))
+		return fmt.Errorf("Unable to create image: %w", err)
+	}
+	audit(c, "Image", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ImageBuildListGet API call processor
+func (e *Processor) ImageBuildListGet(c echo.Context, params types.ImageBuildListGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can list image builds"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can list image builds")
+	}
+
+	out, err := e.fish.ImageBuildFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the image build list: %v", err)})
+		return fmt.Errorf("Unable to get the image build list: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ImageBuildCreatePost API call processor
+func (e *Processor) ImageBuildCreatePost(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can build image"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can build image")
+	}
+
+	var data types.ImageBuild
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
 		return fmt.Errorf("Wrong request body: %w", err)
 	}
+	data.OwnerName = user.Name
+	if err := e.fish.ImageBuildCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to start image build", err))
+		return fmt.Errorf("Unable to start image build: %w", err)
+	}
+	audit(c, "ImageBuild", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ImageBuildGet API call processor
+func (e *Processor) ImageBuildGet(c echo.Context, uid types.ImageBuildUID) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get image build"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get image build")
+	}
+
+	out, err := e.fish.ImageBuildGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Image build not found: %v", err)})
+		return fmt.Errorf("Image build not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ImageGet API call processor
+func (e *Processor) ImageGet(c echo.Context, uid types.ImageUID) error {
+	out, err := e.fish.ImageGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Image not found: %v", err)})
+		return fmt.Errorf("Image not found: %w", err)
+	}
 
-	// Set the User field out of the authorized user
+	return c.JSON(http.StatusOK, out)
+}
+
+// ImageDeprecateGet API call processor
+func (e *Processor) ImageDeprecateGet(c echo.Context, uid types.ImageUID) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	data.OwnerName = user.Name
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can deprecate image"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can deprecate image")
+	}
 
-	if err := e.fish.ApplicationCreate(&data); err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create application: %v", err)})
-		return fmt.Errorf("Unable to create application: %w", err)
+	before, err := e.fish.ImageGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Image not found: %v", err)})
+		return fmt.Errorf("Image not found: %w", err)
+	}
+	out, err := e.fish.ImageDeprecate(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to deprecate image: %v", err)})
+		return fmt.Errorf("Unable to deprecate image: %w", err)
 	}
+	audit(c, "Image", uid.String(), before, out)
 
-	return c.JSON(http.StatusOK, data)
+	return c.JSON(http.StatusOK, out)
 }
 
-// ApplicationResourceGet API call processor
-func (e *Processor) ApplicationResourceGet(c echo.Context, uid types.ApplicationUID) error {
-	app, err := e.fish.ApplicationGet(uid)
+// ImageDelete API call processor
+func (e *Processor) ImageDelete(c echo.Context, uid types.ImageUID) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can delete image"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can delete image")
+	}
+
+	before, err := e.fish.ImageGet(uid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Image not found: %v", err)})
+		return fmt.Errorf("Image not found: %w", err)
+	}
+	if err = e.fish.ImageDelete(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Image delete failed with error: %v", err)})
+		return fmt.Errorf("Image delete failed with error: %w", err)
+	}
+	audit(c, "Image", uid.String(), before, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Image removed"})
+}
+
+// TemplateListGet API call processor
+func (e *Processor) TemplateListGet(c echo.Context, params types.TemplateListGetParams) error {
+	out, err := e.fish.TemplateFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the template list: %v", err)})
+		return fmt.Errorf("Unable to get the template list: %w", err)
 	}
 
-	// Only the owner of the application (or admin) can request the resource
+	return c.JSON(http.StatusOK, out)
+}
+
+// TemplateCreateUpdatePost API call processor
+func (e *Processor) TemplateCreateUpdatePost(c echo.Context) error {
+	// Only admin or operator can manage templates
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner and admin can request the Application resource"})
-		return fmt.Errorf("Only the owner and admin can request the Application resource")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can create or update template"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can create or update template")
 	}
 
-	out, err := e.fish.ResourceGetByApplication(uid)
-	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Resource not found: %v", err)})
-		return fmt.Errorf("Resource not found: %w", err)
+	var data types.Template
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
 	}
 
-	return c.JSON(http.StatusOK, out)
+	var before *types.Template
+	if t, err := e.fish.TemplateGet(data.Name); err == nil {
+		before = t
+		data.CreatedAt = t.CreatedAt
+	}
+	if err := e.fish.TemplateSave(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to save template", err))
+		return fmt.Errorf("Unable to save template: %w", err)
+	}
+	audit(c, "Template", data.Name, before, &data)
+
+	return c.JSON(http.StatusOK, data)
 }
 
-// ApplicationStateGet API call processor
-func (e *Processor) ApplicationStateGet(c echo.Context, uid types.ApplicationUID) error {
-	app, err := e.fish.ApplicationGet(uid)
+// TemplateGet API call processor
+func (e *Processor) TemplateGet(c echo.Context, name string) error {
+	out, err := e.fish.TemplateGet(name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Unable to find the Application: %s", uid)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Template not found: %v", err)})
+		return fmt.Errorf("Template not found: %w", err)
 	}
 
-	// Only the owner of the application (or admin) can request the status
+	return c.JSON(http.StatusOK, out)
+}
+
+// TemplateDelete API call processor
+func (e *Processor) TemplateDelete(c echo.Context, name string) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner and admin can request the Application status"})
-		return fmt.Errorf("Only the owner and admin can request the Application status")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can delete template"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can delete template")
 	}
 
-	out, err := e.fish.ApplicationStateGetByApplication(uid)
+	before, err := e.fish.TemplateGet(name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Application status not found: %v", err)})
-		return fmt.Errorf("Application status not found: %w", err)
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Template not found: %v", err)})
+		return fmt.Errorf("Template not found: %w", err)
+	}
+	if err := e.fish.TemplateDelete(name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Template delete failed with error: %v", err)})
+		return fmt.Errorf("Template delete failed with error: %w", err)
 	}
+	audit(c, "Template", name, before, nil)
 
-	return c.JSON(http.StatusOK, out)
+	return c.JSON(http.StatusOK, H{"message": "Template removed"})
 }
 
-// ApplicationTaskListGet API call processor
-func (e *Processor) ApplicationTaskListGet(c echo.Context, appUID types.ApplicationUID, params types.ApplicationTaskListGetParams) error {
-	app, err := e.fish.ApplicationGet(appUID)
+// ScheduleListGet API call processor
+func (e *Processor) ScheduleListGet(c echo.Context, params types.ScheduleListGetParams) error {
+	out, err := e.fish.ScheduleFind(params.Filter)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the schedule list: %v", err)})
+		return fmt.Errorf("Unable to get the schedule list: %w", err)
 	}
 
-	// Only the owner of the application (or admin) could get the tasks
+	// Filter the output by owner
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application & admin can get the Application Tasks"})
-		return fmt.Errorf("Only the owner of Application & admin can get the Application Tasks")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		var ownerOut []types.Schedule
+		for _, s := range out {
+			if s.OwnerName == user.Name {
+				ownerOut = append(ownerOut, s)
+			}
+		}
+		out = ownerOut
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ScheduleCreatePost API call processor
+func (e *Processor) ScheduleCreatePost(c echo.Context) error {
+	var data types.Schedule
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// Set the User field out of the authorized user
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
 	}
+	data.OwnerName = user.Name
+
+	if err := e.fish.ScheduleCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, validationH("Unable to create schedule", err))
+		return fmt.Errorf("Unable to create schedule: %w", err)
+	}
+	audit(c, "Schedule", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
 
-	out, err := e.fish.ApplicationTaskFindByApplication(appUID, params.Filter)
+// ScheduleGet API call processor
+func (e *Processor) ScheduleGet(c echo.Context, uid types.ScheduleUID) error {
+	s, err := e.scheduleGetOwned(c, uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the Application Tasks list: %v", err)})
-		return fmt.Errorf("Unable to get the Application Tasks list: %w", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, s)
+}
+
+// ScheduleDelete API call processor
+func (e *Processor) ScheduleDelete(c echo.Context, uid types.ScheduleUID) error {
+	s, err := e.scheduleGetOwned(c, uid)
+	if err != nil {
+		return err
+	}
+	if err := e.fish.ScheduleDelete(uid); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Schedule delete failed with error: %v", err)})
+		return fmt.Errorf("Schedule delete failed with error: %w", err)
+	}
+	audit(c, "Schedule", uid.String(), s, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Schedule removed"})
+}
+
+// ScheduleEnableGet API call processor
+func (e *Processor) ScheduleEnableGet(c echo.Context, uid types.ScheduleUID) error {
+	return e.scheduleEnable(c, uid, true)
+}
+
+// ScheduleDisableGet API call processor
+func (e *Processor) ScheduleDisableGet(c echo.Context, uid types.ScheduleUID) error {
+	return e.scheduleEnable(c, uid, false)
+}
+
+// ScheduleNextGet API call processor
+func (e *Processor) ScheduleNextGet(c echo.Context, uid types.ScheduleUID, params types.ScheduleNextGetParams) error {
+	s, err := e.scheduleGetOwned(c, uid)
+	if err != nil {
+		return err
+	}
+
+	count := 5
+	if params.Count != nil {
+		count = *params.Count
+	}
+	if count < 1 || count > 100 {
+		c.JSON(http.StatusBadRequest, H{"message": "Count should be in range 1-100"})
+		return fmt.Errorf("Count should be in range 1-100")
+	}
+	out, err := e.fish.ScheduleNext(s, count)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to calculate the next runs: %v", err)})
+		return fmt.Errorf("Unable to calculate the next runs: %w", err)
 	}
 
 	return c.JSON(http.StatusOK, out)
 }
 
-// ApplicationTaskCreatePost API call processor
-func (e *Processor) ApplicationTaskCreatePost(c echo.Context, appUID types.ApplicationUID) error {
-	app, err := e.fish.ApplicationGet(appUID)
+// ScheduleHistoryGet API call processor
+func (e *Processor) ScheduleHistoryGet(c echo.Context, uid types.ScheduleUID) error {
+	if _, err := e.scheduleGetOwned(c, uid); err != nil {
+		return err
+	}
+
+	out, err := e.fish.ScheduleHistory(uid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", appUID)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", appUID, err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the schedule history: %v", err)})
+		return fmt.Errorf("Unable to get the schedule history: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// scheduleEnable switches the Schedule on or off
+func (e *Processor) scheduleEnable(c echo.Context, uid types.ScheduleUID, enabled bool) error {
+	s, err := e.scheduleGetOwned(c, uid)
+	if err != nil {
+		return err
+	}
+	before := *s
+	if err := e.fish.ScheduleEnable(s, enabled); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to update schedule: %v", err)})
+		return fmt.Errorf("Unable to update schedule: %w", err)
+	}
+	audit(c, "Schedule", uid.String(), &before, s)
+
+	return c.JSON(http.StatusOK, s)
+}
+
+// scheduleGetOwned returns the Schedule if the user is the owner, admin or operator
+func (e *Processor) scheduleGetOwned(c echo.Context, uid types.ScheduleUID) (*types.Schedule, error) {
+	s, err := e.fish.ScheduleGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Schedule not found: %v", err)})
+		return nil, fmt.Errorf("Schedule not found: %w", err)
+	}
+
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return nil, fmt.Errorf("Not authentified")
 	}
+	if s.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can manage the Schedule"})
+		return nil, fmt.Errorf("Only the owner, admin and operator can manage the Schedule")
+	}
+	return s, nil
+}
 
-	// Only the owner of the application (or admin) could create the tasks
+// SyncPost API call processor
+func (e *Processor) SyncPost(c echo.Context) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application & admin can create the Application Tasks"})
-		return fmt.Errorf("Only the owner of Application & admin can create the Application Tasks")
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can sync the nodes"})
+		return fmt.Errorf("Only 'admin' user can sync the nodes")
+	}
+	if !e.fish.SyncEdgesEnabled() {
+		c.JSON(http.StatusBadRequest, H{"message": "Sync of the edge nodes is not enabled on the node"})
+		return fmt.Errorf("Sync of the edge nodes is not enabled on the node")
 	}
 
-	var data types.ApplicationTask
+	var data types.SyncRequest
 	if err := c.Bind(&data); err != nil {
 		c.JSON(http.StatusBadRequest, H{"error": fmt.Sprintf("Wrong request body: %v", err)})
 		return fmt.Errorf("Wrong request body: %w", err)
 	}
+	if data.NodeName == "" {
+		c.JSON(http.StatusBadRequest, H{"message": "Node name can't be empty"})
+		return fmt.Errorf("Node name can't be empty")
+	}
 
-	// Set Application UID for the task forcefully to not allow creating tasks for the other Apps
-	data.ApplicationUID = appUID
+	// The edge node received the changes till since, so they could be pruned
+	if err := e.fish.SyncAck(data.NodeName, data.Since); err != nil {
+		log.Warn("API: Unable to ack sync:", err)
+	}
 
-	if err := e.fish.ApplicationTaskCreate(&data); err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create ApplicationTask: %v", err)})
-		return fmt.Errorf("Unable to create ApplicationTask: %w", err)
+	out := types.SyncResponse{}
+	out.Conflicts = e.fish.SyncApply(&data.Changes, data.NodeName)
+	audit(c, "Node", data.NodeName, nil, nil)
+	if out.Conflicts == nil {
+		out.Conflicts = []string{}
 	}
 
-	return c.JSON(http.StatusOK, data)
+	var err error
+	if out.Changes, out.Until, err = e.fish.SyncCollect(data.Since, data.NodeName); err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to collect changes: %v", err)})
+		return fmt.Errorf("Unable to collect changes: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
 }
 
-// ApplicationTaskGet API call processor
-func (e *Processor) ApplicationTaskGet(c echo.Context, taskUID types.ApplicationTaskUID) error {
-	task, err := e.fish.ApplicationTaskGet(taskUID)
+// NodeListGet API call processor
+func (e *Processor) NodeListGet(c echo.Context, params types.NodeListGetParams) error {
+	page := fish.NewListPage(params.PageSize, params.PageToken, params.Sort)
+	out, err := e.fish.NodeFind(params.Filter, page, params.Report != nil && *params.Report)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", taskUID)})
-		return fmt.Errorf("Unable to find the ApplicationTask: %s, %w", taskUID, err)
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the node list: %v", err)})
+		return fmt.Errorf("Unable to get the node list: %w", err)
 	}
+	listPageHeader(c, page)
 
-	app, err := e.fish.ApplicationGet(task.ApplicationUID)
+	return c.JSON(http.StatusOK, out)
+}
+
+// NodeCapacityGet API call processor
+func (e *Processor) NodeCapacityGet(c echo.Context) error {
+	out, err := e.fish.ClusterCapacityGet()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", task.ApplicationUID)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", task.ApplicationUID, err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the cluster capacity: %v", err)})
+		return fmt.Errorf("Unable to get the cluster capacity: %w", err)
 	}
 
-	// Only the owner of the application (or admin) could get the attached task
+	return c.JSON(http.StatusOK, out)
+}
+
+// NodeMaintenanceGet API call processor
+func (e *Processor) NodeMaintenanceGet(c echo.Context, uid types.NodeUID, params types.NodeMaintenanceGetParams) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner of Application & admin can get the ApplicationTask"})
-		return fmt.Errorf("Only the owner of Application & admin can get the ApplicationTask")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can set node maintenance"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can set node maintenance")
 	}
 
-	return c.JSON(http.StatusOK, task)
+	before, err := e.fish.NodeGetUID(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Node not found: %v", err)})
+		return fmt.Errorf("Node not found: %w", err)
+	}
+
+	var timeout time.Duration
+	if params.DrainTimeout != nil {
+		if timeout, err = time.ParseDuration(*params.DrainTimeout); err != nil {
+			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong duration format: %v", err)})
+			return fmt.Errorf("Wrong duration format: %v", err)
+		}
+	}
+
+	node, err := e.fish.NodeMaintenanceRequest(uid, params.Enable == nil || *params.Enable,
+		params.Drain != nil && *params.Drain, timeout)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to set node maintenance: %v", err)})
+		return fmt.Errorf("Unable to set node maintenance: %w", err)
+	}
+	audit(c, "Node", node.Name, before, node)
+
+	return c.JSON(http.StatusOK, node)
 }
 
-// ApplicationDeallocateGet API call processor
-func (e *Processor) ApplicationDeallocateGet(c echo.Context, uid types.ApplicationUID) error {
-	app, err := e.fish.ApplicationGet(uid)
+// NodeDrainGet API call processor
+func (e *Processor) NodeDrainGet(c echo.Context, uid types.NodeUID) error {
+	out, err := e.fish.NodeDrainGet(uid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the application: %s", uid)})
-		return fmt.Errorf("Unable to find the Application: %s, %w", uid, err)
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Node not found: %v", err)})
+		return fmt.Errorf("Node not found: %w", err)
 	}
 
-	// Only the owner of the application (or admin) could deallocate it
+	return c.JSON(http.StatusOK, out)
+}
+
+// NodeTimelineGet API call processor
+func (e *Processor) NodeTimelineGet(c echo.Context, uid types.NodeUID) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if app.OwnerName != user.Name && user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only the owner & admin can deallocate the Application resource"})
-		return fmt.Errorf("Only the owner & admin can deallocate the Application resource")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can get the node timeline"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get the node timeline")
+	}
+
+	out, err := e.fish.HistoryEventFind(fish.HistoryObjectNode, uid.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the node timeline: %v", err)})
+		return fmt.Errorf("Unable to get the node timeline: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// NodeThisGet API call processor
+func (e *Processor) NodeThisGet(c echo.Context) error {
+	node := e.fish.GetNode()
+
+	return c.JSON(http.StatusOK, node)
+}
+
+// NodeThisMaintenanceGet API call processor
+func (e *Processor) NodeThisMaintenanceGet(c echo.Context, params types.NodeThisMaintenanceGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can set node maintenance"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can set node maintenance")
+	}
+
+	// Set shutdown delay first
+	if params.ShutdownDelay != nil {
+		dur, err := time.ParseDuration(*params.ShutdownDelay)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong duration format: %v", err)})
+			return fmt.Errorf("Wrong duration format: %v", err)
+		}
+		e.fish.ShutdownDelaySet(dur)
+	}
+
+	// Then set maintenance mode
+	if params.Enable == nil {
+		e.fish.MaintenanceSet(true)
+	} else {
+		e.fish.MaintenanceSet(*params.Enable)
+	}
+
+	// Handover is used by shutdown, so need to be set before it
+	if params.Handover != nil {
+		e.fish.HandoverSet(*params.Handover)
+	}
+
+	// Shutdown last, technically will work immediately if maintenance enable is false
+	if params.Shutdown != nil {
+		e.fish.ShutdownSet(*params.Shutdown)
+	}
+	audit(c, "Node", e.fish.GetNode().Name, nil, &params)
+
+	return c.JSON(http.StatusOK, params)
+}
+
+// NodeThisDriverRestartGet API call processor
+func (e *Processor) NodeThisDriverRestartGet(c echo.Context, params types.NodeThisDriverRestartGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
 	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can restart the driver"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can restart the driver")
+	}
+
+	if err := e.fish.DriverRestart(params.Name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to restart the driver: %v", err)})
+		return fmt.Errorf("Unable to restart the driver: %v", err)
+	}
+	audit(c, "Driver", params.Name, nil, nil)
+
+	return c.JSON(http.StatusOK, H{"message": fmt.Sprintf("Driver %s restarted", params.Name)})
+}
 
-	out, err := e.fish.ApplicationStateGetByApplication(uid)
+// NodeThisDriverInfoSchemaGet API call processor
+func (e *Processor) NodeThisDriverInfoSchemaGet(c echo.Context, params types.NodeThisDriverInfoSchemaGetParams) error {
+	schema, err := e.fish.DriverInfoSchema(params.Name)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find status for the Application: %s", uid)})
-		return fmt.Errorf("Unable to find status for the Application: %s, %w", uid, err)
-	}
-	if !e.fish.ApplicationStateIsActive(out.Status) {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to deallocate the Application with status: %s", out.Status)})
-		return fmt.Errorf("Unable to deallocate the Application with status: %s", out.Status)
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the driver info schema: %v", err)})
+		return fmt.Errorf("Unable to get the driver info schema: %v", err)
 	}
 
-	newStatus := types.ApplicationStatusDEALLOCATE
-	if out.Status != types.ApplicationStatusALLOCATED {
-		// The Application was not yet Allocated so just mark it as Recalled
-		newStatus = types.ApplicationStatusRECALLED
-	}
-	as := &types.ApplicationState{ApplicationUID: uid, Status: newStatus,
-		Description: fmt.Sprintf("Requested by user %s", user.Name),
+	return c.JSON(http.StatusOK, schema)
+}
+
+// NodeThisDriverCapabilitiesGet API call processor
+func (e *Processor) NodeThisDriverCapabilitiesGet(c echo.Context, params types.NodeThisDriverCapabilitiesGetParams) error {
+	caps, err := e.fish.DriverCapabilities(params.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the driver capabilities: %v", err)})
+		return fmt.Errorf("Unable to get the driver capabilities: %v", err)
 	}
-	err = e.fish.ApplicationStateCreate(as)
+
+	return c.JSON(http.StatusOK, caps)
+}
+
+// NodeThisDriverForecastGet API call processor
+func (e *Processor) NodeThisDriverForecastGet(c echo.Context, params types.NodeThisDriverForecastGetParams) error {
+	forecast, err := e.fish.DriverForecast(params.Name)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to deallocate the Application: %s", uid)})
-		return fmt.Errorf("Unable to deallocate the Application: %s, %w", uid, err)
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to get the driver forecast: %v", err)})
+		return fmt.Errorf("Unable to get the driver forecast: %v", err)
 	}
 
-	return c.JSON(http.StatusOK, as)
+	return c.JSON(http.StatusOK, forecast)
 }
 
-// LabelListGet API call processor
-func (e *Processor) LabelListGet(c echo.Context, params types.LabelListGetParams) error {
-	out, err := e.fish.LabelFind(params.Filter)
+// NodeThisLabelCompatibilityGet API call processor
+func (e *Processor) NodeThisLabelCompatibilityGet(c echo.Context) error {
+	out, err := e.fish.LabelCompatibilityList()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the label list: %v", err)})
-		return fmt.Errorf("Unable to get the label list: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the Label compatibility: %v", err)})
+		return fmt.Errorf("Unable to get the Label compatibility: %w", err)
 	}
 
 	return c.JSON(http.StatusOK, out)
 }
 
-// LabelGet API call processor
-func (e *Processor) LabelGet(c echo.Context, uid types.LabelUID) error {
-	out, err := e.fish.LabelGet(uid)
+// NodeThisCapacityGet API call processor
+func (e *Processor) NodeThisCapacityGet(c echo.Context) error {
+	out, err := e.fish.NodeCapacityGet()
 	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Label not found: %v", err)})
-		return fmt.Errorf("Label not found: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the node capacity: %v", err)})
+		return fmt.Errorf("Unable to get the node capacity: %w", err)
 	}
 
 	return c.JSON(http.StatusOK, out)
 }
 
-// LabelCreatePost API call processor
-func (e *Processor) LabelCreatePost(c echo.Context) error {
-	// Only admin can create label
+// NodeThisBundleGet API call processor
+func (e *Processor) NodeThisBundleGet(c echo.Context) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
+	// The bundle contains the logs and internals of the node, so operator role is not enough
 	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can create label"})
-		return fmt.Errorf("Only 'admin' user can create label")
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can get the diagnostic bundle"})
+		return fmt.Errorf("Only 'admin' can get the diagnostic bundle")
 	}
 
-	var data types.Label
-	if err := c.Bind(&data); err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
-		return fmt.Errorf("Wrong request body: %w", err)
-	}
-	if err := e.fish.LabelCreate(&data); err != nil {
-		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create label: %v", err)})
-		return fmt.Errorf("Unable to create label: %w", err)
+	// Preparing the bundle in memory to be able to return error
+	var buf bytes.Buffer
+	if err := e.fish.BundleWrite(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to create the bundle: %v", err)})
+		return fmt.Errorf("Unable to create the bundle: %w", err)
 	}
 
-	return c.JSON(http.StatusOK, data)
+	name := fmt.Sprintf("fish-bundle-%s-%s.tar.gz", e.fish.GetNode().Name, time.Now().UTC().Format("20060102-150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	return c.Blob(http.StatusOK, "application/gzip", buf.Bytes())
 }
 
-// LabelDelete API call processor
-func (e *Processor) LabelDelete(c echo.Context, uid types.LabelUID) error {
-	// Only admin can delete label
+// NodeThisBackupListGet API call processor
+func (e *Processor) NodeThisBackupListGet(c echo.Context) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
+	// The backup contains all the data of the cluster, so operator role is not enough
 	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can delete Label"})
-		return fmt.Errorf("Only 'admin' user can delete label")
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can list the backups"})
+		return fmt.Errorf("Only 'admin' can list the backups")
 	}
 
-	err := e.fish.LabelDelete(uid)
+	out, err := e.fish.BackupList()
 	if err != nil {
-		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Label delete failed with error: %v", err)})
-		return fmt.Errorf("Label delete failed with error: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the backup list: %v", err)})
+		return fmt.Errorf("Unable to get the backup list: %w", err)
 	}
 
-	return c.JSON(http.StatusOK, H{"message": "Label removed"})
+	return c.JSON(http.StatusOK, out)
 }
 
-// NodeListGet API call processor
-func (e *Processor) NodeListGet(c echo.Context, params types.NodeListGetParams) error {
-	out, err := e.fish.NodeFind(params.Filter)
+// NodeThisBackupCreatePost API call processor
+func (e *Processor) NodeThisBackupCreatePost(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can create the backup"})
+		return fmt.Errorf("Only 'admin' can create the backup")
+	}
+
+	out, err := e.fish.BackupCreate()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the node list: %v", err)})
-		return fmt.Errorf("Unable to get the node list: %w", err)
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to create the backup: %v", err)})
+		return fmt.Errorf("Unable to create the backup: %w", err)
 	}
+	audit(c, "Backup", out.Name, nil, out)
 
 	return c.JSON(http.StatusOK, out)
 }
 
-// NodeThisGet API call processor
-func (e *Processor) NodeThisGet(c echo.Context) error {
-	node := e.fish.GetNode()
-
-	return c.JSON(http.StatusOK, node)
-}
-
-// NodeThisMaintenanceGet API call processor
-func (e *Processor) NodeThisMaintenanceGet(c echo.Context, params types.NodeThisMaintenanceGetParams) error {
+// NodeThisBackupGet API call processor
+func (e *Processor) NodeThisBackupGet(c echo.Context, name string) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
 	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can set node maintenance"})
-		return fmt.Errorf("Only 'admin' user can set node maintenance")
-	}
-
-	// Set shutdown delay first
-	if params.ShutdownDelay != nil {
-		dur, err := time.ParseDuration(*params.ShutdownDelay)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong duration format: %v", err)})
-			return fmt.Errorf("Wrong duration format: %v", err)
-		}
-		e.fish.ShutdownDelaySet(dur)
-	}
-
-	// Then set maintenance mode
-	if params.Enable == nil {
-		e.fish.MaintenanceSet(true)
-	} else {
-		e.fish.MaintenanceSet(*params.Enable)
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can get the backup"})
+		return fmt.Errorf("Only 'admin' can get the backup")
 	}
 
-	// Shutdown last, technically will work immediately if maintenance enable is false
-	if params.Shutdown != nil {
-		e.fish.ShutdownSet(*params.Shutdown)
+	path, err := e.fish.BackupPath(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the backup: %v", err)})
+		return fmt.Errorf("Unable to find the backup: %w", err)
 	}
 
-	return c.JSON(http.StatusOK, params)
+	return c.Attachment(path, name)
 }
 
 // NodeThisProfilingIndexGet API call processor
@@ -693,12 +3053,13 @@ func (e *Processor) NodeThisProfilingIndexGet(c echo.Context) error {
 }
 
 // NodeThisProfilingGet API call processor
-func (*Processor) NodeThisProfilingGet(c echo.Context, handler string) error {
+func (e *Processor) NodeThisProfilingGet(c echo.Context, handler string) error {
 	user, ok := c.Get("user").(*types.User)
 	if !ok {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
+	// The profiles could contain the memory with secrets, so operator role is not enough
 	if user.Name != "admin" {
 		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' can see profiling info"})
 		return fmt.Errorf("Only 'admin' can see profiling info")
@@ -734,12 +3095,12 @@ func (e *Processor) VoteListGet(c echo.Context, params types.VoteListGetParams)
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can get votes"})
-		return fmt.Errorf("Only 'admin' user can get votes")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get votes"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get votes")
 	}
 
-	out, err := e.fish.VoteFind(params.Filter)
+	out, err := e.fish.VoteFind(params.Filter, params.Report != nil && *params.Report)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the vote list: %v", err)})
 		return fmt.Errorf("Unable to get the vote list: %w", err)
@@ -748,6 +3109,205 @@ func (e *Processor) VoteListGet(c echo.Context, params types.VoteListGetParams)
 	return c.JSON(http.StatusOK, out)
 }
 
+// UpgradeListGet API call processor
+func (e *Processor) UpgradeListGet(c echo.Context, params types.UpgradeListGetParams) error {
+	out, err := e.fish.UpgradeFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the upgrade list: %v", err)})
+		return fmt.Errorf("Unable to get the upgrade list: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// UpgradeCreatePost API call processor
+func (e *Processor) UpgradeCreatePost(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can upgrade the cluster"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can upgrade the cluster")
+	}
+
+	var data types.Upgrade
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	data.OwnerName = user.Name
+
+	if err := e.fish.UpgradeCreate(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create upgrade: %v", err)})
+		return fmt.Errorf("Unable to create upgrade: %w", err)
+	}
+	audit(c, "Upgrade", data.UID.String(), nil, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// UpgradeGet API call processor
+func (e *Processor) UpgradeGet(c echo.Context, uid types.UpgradeUID) error {
+	out, err := e.fish.UpgradeGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Upgrade not found: %v", err)})
+		return fmt.Errorf("Upgrade not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// UpgradePauseGet API call processor
+func (e *Processor) UpgradePauseGet(c echo.Context, uid types.UpgradeUID) error {
+	return e.upgradeControl(c, uid, e.fish.UpgradePause)
+}
+
+// UpgradeResumeGet API call processor
+func (e *Processor) UpgradeResumeGet(c echo.Context, uid types.UpgradeUID) error {
+	return e.upgradeControl(c, uid, e.fish.UpgradeResume)
+}
+
+// UpgradeAbortGet API call processor
+func (e *Processor) UpgradeAbortGet(c echo.Context, uid types.UpgradeUID) error {
+	return e.upgradeControl(c, uid, e.fish.UpgradeAbort)
+}
+
+// upgradeControl changes the Upgrade status with the provided action, admin or operator only
+func (e *Processor) upgradeControl(c echo.Context, uid types.UpgradeUID, action func(*types.Upgrade) error) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' can control the upgrade"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can control the upgrade")
+	}
+
+	u, err := e.fish.UpgradeGet(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Upgrade not found: %v", err)})
+		return fmt.Errorf("Upgrade not found: %w", err)
+	}
+	before := *u
+	if err := action(u); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to update upgrade: %v", err)})
+		return fmt.Errorf("Unable to update upgrade: %w", err)
+	}
+	audit(c, "Upgrade", uid.String(), &before, u)
+
+	return c.JSON(http.StatusOK, u)
+}
+
+// ProjectListGet API call processor
+func (e *Processor) ProjectListGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+
+	out, err := e.fish.ProjectFind()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the project list: %v", err)})
+		return fmt.Errorf("Unable to get the project list: %w", err)
+	}
+	// Filter the output by membership
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		var owned []types.Project
+		for _, p := range out {
+			if e.fish.ProjectRole(p.Name, user.Name) != "" {
+				owned = append(owned, p)
+			}
+		}
+		out = owned
+	}
+	if out == nil {
+		out = []types.Project{}
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ProjectCreateUpdatePost API call processor
+func (e *Processor) ProjectCreateUpdatePost(c echo.Context) error {
+	// Only admin can manage the projects, otherwise operator could lift the project limits
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can manage projects"})
+		return fmt.Errorf("Only 'admin' user can manage projects")
+	}
+
+	var data types.Project
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	before, err := e.fish.ProjectGet(data.Name)
+	if err == nil {
+		data.CreatedAt = before.CreatedAt
+	} else {
+		before = nil
+	}
+	if err := e.fish.ProjectSave(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to save project: %v", err)})
+		return fmt.Errorf("Unable to save project: %w", err)
+	}
+	audit(c, "Project", data.Name, before, &data)
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// ProjectGet API call processor
+func (e *Processor) ProjectGet(c echo.Context, name types.ProjectName) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if e.fish.ProjectRole(name, user.Name) == "" && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only the members, 'admin' or 'operator' user can get the project"})
+		return fmt.Errorf("Only the members, 'admin' or 'operator' user can get the project")
+	}
+
+	out, err := e.fish.ProjectGet(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("Project not found: %v", err)})
+		return fmt.Errorf("Project not found: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ProjectDelete API call processor
+func (e *Processor) ProjectDelete(c echo.Context, name types.ProjectName) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if user.Name != "admin" {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can delete projects"})
+		return fmt.Errorf("Only 'admin' user can delete projects")
+	}
+
+	before, _ := e.fish.ProjectGet(name)
+	if err := e.fish.ProjectDelete(name); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Project delete failed with error: %v", err)})
+		return fmt.Errorf("Project delete failed with error: %w", err)
+	}
+	audit(c, "Project", name, before, nil)
+
+	return c.JSON(http.StatusOK, H{"message": "Project removed"})
+}
+
 // LocationListGet API call processor
 func (e *Processor) LocationListGet(c echo.Context, params types.LocationListGetParams) error {
 	user, ok := c.Get("user").(*types.User)
@@ -755,12 +3315,12 @@ func (e *Processor) LocationListGet(c echo.Context, params types.LocationListGet
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can get locations"})
-		return fmt.Errorf("Only 'admin' user can get locations")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get locations"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get locations")
 	}
 
-	out, err := e.fish.LocationFind(params.Filter)
+	out, err := e.fish.LocationFind(params.Filter, params.Report != nil && *params.Report)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the location list: %v", err)})
 		return fmt.Errorf("Unable to get the location list: %w", err)
@@ -776,9 +3336,9 @@ func (e *Processor) LocationCreatePost(c echo.Context) error {
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can create location"})
-		return fmt.Errorf("Only 'admin' user can create location")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can create location"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can create location")
 	}
 
 	var data types.Location
@@ -791,6 +3351,7 @@ func (e *Processor) LocationCreatePost(c echo.Context) error {
 		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create location: %v", err)})
 		return fmt.Errorf("Unable to create location: %w", err)
 	}
+	audit(c, "Location", data.Name, nil, &data)
 
 	return c.JSON(http.StatusOK, data)
 }
@@ -802,9 +3363,9 @@ func (e *Processor) ServiceMappingGet(c echo.Context, uid types.ServiceMappingUI
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can get service mapping"})
-		return fmt.Errorf("Only 'admin' user can get service mapping")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get service mapping"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get service mapping")
 	}
 
 	out, err := e.fish.ServiceMappingGet(uid)
@@ -823,12 +3384,12 @@ func (e *Processor) ServiceMappingListGet(c echo.Context, params types.ServiceMa
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can get service mappings"})
-		return fmt.Errorf("Only 'admin' user can get service mappings")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get service mappings"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get service mappings")
 	}
 
-	out, err := e.fish.ServiceMappingFind(params.Filter)
+	out, err := e.fish.ServiceMappingFind(params.Filter, params.Report != nil && *params.Report)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the servicemappings list: %v", err)})
 		return fmt.Errorf("Unable to get the servicemappings list: %w", err)
@@ -851,26 +3412,27 @@ func (e *Processor) ServiceMappingCreatePost(c echo.Context) error {
 		return fmt.Errorf("Not authentified")
 	}
 	if data.ApplicationUID != uuid.Nil {
-		// Only the owner and admin can create servicemapping for his application
+		// Only the owner, admin and operator can create servicemapping for his application
 		app, err := e.fish.ApplicationGet(data.ApplicationUID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to find the Application: %s", data.ApplicationUID)})
 			return fmt.Errorf("Unable to find the Application: %s, %w", data.ApplicationUID, err)
 		}
 
-		if app.OwnerName != user.Name && user.Name != "admin" {
-			c.JSON(http.StatusBadRequest, H{"message": "Only the owner & admin can assign service mapping to the Application"})
-			return fmt.Errorf("Only the owner & admin can assign service mapping to the Application")
+		if app.OwnerName != user.Name && !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+			c.JSON(http.StatusBadRequest, H{"message": "Only the owner, admin and operator can assign service mapping to the Application"})
+			return fmt.Errorf("Only the owner, admin and operator can assign service mapping to the Application")
 		}
-	} else if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can create service mapping with undefined Application"})
-		return fmt.Errorf("Only 'admin' user can create service mapping with undefined Application")
+	} else if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can create service mapping with undefined Application"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can create service mapping with undefined Application")
 	}
 
 	if err := e.fish.ServiceMappingCreate(&data); err != nil {
 		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to create service mapping: %v", err)})
 		return fmt.Errorf("Unable to create service mapping: %w", err)
 	}
+	audit(c, "ServiceMapping", data.UID.String(), nil, &data)
 
 	return c.JSON(http.StatusOK, data)
 }
@@ -883,15 +3445,126 @@ func (e *Processor) ServiceMappingDelete(c echo.Context, uid types.ServiceMappin
 		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
 		return fmt.Errorf("Not authentified")
 	}
-	if user.Name != "admin" {
-		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' user can delete service mapping"})
-		return fmt.Errorf("Only 'admin' user can delete service mapping")
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can delete service mapping"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can delete service mapping")
 	}
 
+	before, _ := e.fish.ServiceMappingGet(uid)
 	if err := e.fish.ServiceMappingDelete(uid); err != nil {
 		c.JSON(http.StatusNotFound, H{"message": fmt.Sprintf("ServiceMapping %s delete failed with error: %v", uid, err)})
 		return fmt.Errorf("ServiceMapping %s delete failed with error: %w", uid, err)
 	}
+	audit(c, "ServiceMapping", uid.String(), before, nil)
 
 	return c.JSON(http.StatusOK, H{"message": "ServiceMapping removed"})
 }
+
+// AuditRecordListGet API call processor
+func (e *Processor) AuditRecordListGet(c echo.Context, params types.AuditRecordListGetParams) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleAuditor) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'auditor' user can get audit records"})
+		return fmt.Errorf("Only 'admin' or 'auditor' user can get audit records")
+	}
+
+	out, err := e.fish.AuditRecordFind(params.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the audit records: %v", err)})
+		return fmt.Errorf("Unable to get the audit records: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// AuditRecordStreamGet API call processor
+func (e *Processor) AuditRecordStreamGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleAuditor) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'auditor' user can stream audit records"})
+		return fmt.Errorf("Only 'admin' or 'auditor' user can stream audit records")
+	}
+
+	records, cancel := e.fish.AuditSubscribe()
+	defer cancel()
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	enc := json.NewEncoder(c.Response())
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case rec := <-records:
+			if err := enc.Encode(rec); err != nil {
+				return fmt.Errorf("Unable to send audit record: %w", err)
+			}
+			c.Response().Flush()
+		}
+	}
+}
+
+// SchedulerSharesGet API call processor
+func (e *Processor) SchedulerSharesGet(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can get the scheduler shares"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can get the scheduler shares")
+	}
+
+	out, err := e.fish.SchedulerShares()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": fmt.Sprintf("Unable to get the scheduler shares: %v", err)})
+		return fmt.Errorf("Unable to get the scheduler shares: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// SimulatorDedicatedPost API call processor
+func (e *Processor) SimulatorDedicatedPost(c echo.Context) error {
+	user, ok := c.Get("user").(*types.User)
+	if !ok {
+		c.JSON(http.StatusBadRequest, H{"message": "Not authentified"})
+		return fmt.Errorf("Not authentified")
+	}
+	if !e.fish.UserHasRole(user.Name, fish.RoleOperator) {
+		c.JSON(http.StatusBadRequest, H{"message": "Only 'admin' or 'operator' user can run the simulator"})
+		return fmt.Errorf("Only 'admin' or 'operator' user can run the simulator")
+	}
+
+	var data types.SimulatorRequest
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	out, err := e.fish.SimulatorDedicated(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to run the simulation: %v", err)})
+		return fmt.Errorf("Unable to run the simulation: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// listPageHeader returns the token of the next list page to the client
+func listPageHeader(c echo.Context, page *fish.ListPage) {
+	if page.Next != "" {
+		c.Response().Header().Set("X-Next-Page-Token", page.Next)
+	}
+}
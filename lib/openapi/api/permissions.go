@@ -0,0 +1,219 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"sort"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// operationAccess describes who is allowed to call the API operation, the checks itself are
+// done by the operation processors and the RBAC matrix test makes sure they are matching
+type operationAccess struct {
+	all   bool     // Any authenticated User
+	own   bool     // The owner of the object or the User itself
+	admin bool     // The admin User
+	roles []string // Users with any of the roles
+}
+
+var (
+	accessAll      = operationAccess{all: true}
+	accessAdmin    = operationAccess{admin: true}
+	accessOperator = operationAccess{admin: true, roles: []string{fish.RoleOperator}}
+	accessAuditor  = operationAccess{admin: true, roles: []string{fish.RoleAuditor}}
+	accessApprover = operationAccess{admin: true, roles: []string{fish.RoleApprover}}
+	accessOwner    = operationAccess{own: true, admin: true, roles: []string{fish.RoleOperator}}
+	accessSelf     = operationAccess{own: true, admin: true}
+	accessOwnOnly  = operationAccess{own: true}
+)
+
+// operationsAccess is the permission matrix of the API operations, every new operation should be
+// added here, otherwise the RBAC matrix test will fail
+var operationsAccess = map[string]operationAccess{
+	"UserListGet":          accessAdmin,
+	"UserCreateUpdatePost": accessSelf,
+	"UserMeGet":            accessAll,
+	"UserMePermissionsGet": accessAll,
+	"UserMeRateLimitGet":   accessAll,
+	"PreferenceListGet":    accessAll,
+	"PreferenceGet":        accessAll,
+	"PreferencePut":        accessAll,
+	"PreferenceDelete":     accessAll,
+	"UserGet":              accessAdmin,
+	"UserDelete":           accessAdmin,
+	"UserGrantListGet":     accessSelf,
+	"UserGrantCreatePost":  accessAdmin,
+	"UserOTPPut":           accessOwnOnly,
+	"UserOTPDelete":        accessAdmin,
+	"UserQuotaGet":         accessOwner,
+	"UserQuotaPut":         accessAdmin,
+	"UserQuotaDelete":      accessAdmin,
+	"GrantRevokeDelete":    accessAdmin,
+
+	"RoleListGet":          accessAll,
+	"RoleCreateUpdatePost": accessAdmin,
+	"RoleGet":              accessAll,
+	"RoleDelete":           accessAdmin,
+
+	"UserTokenListGet":      accessSelf,
+	"UserTokenCreatePost":   accessSelf,
+	"UserTokenRevokeDelete": accessSelf,
+
+	"UserSubscriptionListGet":    accessSelf,
+	"UserSubscriptionCreatePost": accessSelf,
+	"UserSubscriptionDelete":     accessSelf,
+
+	"LabelListGet":    accessAll,
+	"LabelCreatePost": accessOperator,
+	"LabelStatsGet":   accessOperator,
+	"LabelGet":        accessAll,
+	"LabelDelete":     accessOperator,
+
+	"LabelApplyPost":  accessOperator,
+	"LabelExportGet":  accessOperator,
+	"LabelImportPost": accessOperator,
+
+	"ImageListGet":      accessAll,
+	"ImageCreatePost":   accessOperator,
+	"ImageGet":          accessAll,
+	"ImageDeprecateGet": accessOperator,
+	"ImageDelete":       accessOperator,
+
+	"ImageBuildListGet":    accessOperator,
+	"ImageBuildCreatePost": accessOperator,
+	"ImageBuildGet":        accessOperator,
+
+	"TemplateListGet":          accessAll,
+	"TemplateCreateUpdatePost": accessOperator,
+	"TemplateGet":              accessAll,
+	"TemplateDelete":           accessOperator,
+
+	"ScheduleListGet":    accessAll,
+	"ScheduleCreatePost": accessAll,
+	"ScheduleGet":        accessOwner,
+	"ScheduleDelete":     accessOwner,
+	"ScheduleEnableGet":  accessOwner,
+	"ScheduleDisableGet": accessOwner,
+	"ScheduleNextGet":    accessOwner,
+	"ScheduleHistoryGet": accessOwner,
+
+	"ResourceListGet":          accessOperator,
+	"ResourceGet":              accessOperator,
+	"ResourceAccessPut":        accessSelf,
+	"ResourceAccessMethodsGet": accessSelf,
+
+	"ApplicationListGet":              accessAll,
+	"ApplicationCreatePost":           accessAll,
+	"ApplicationBatchListGet":         accessAll,
+	"ApplicationBatchCreatePost":      accessAll,
+	"ApplicationBatchGet":             accessOwner,
+	"ApplicationBatchStateGet":        accessOwner,
+	"ApplicationGet":                  accessOwner,
+	"ApplicationStateGet":             accessOwner,
+	"ApplicationResourceGet":          accessOwner,
+	"ApplicationSecretCreatePost":     accessOwner,
+	"ApplicationTaskListGet":          accessOwner,
+	"ApplicationTaskCreatePost":       accessOwner,
+	"ApplicationTaskGet":              accessOwner,
+	"ApplicationDeallocateGet":        accessOwner,
+	"ApplicationDeallocateApproveGet": accessAdmin,
+
+	"ApplicationFullListGet": accessAll,
+	"ApplicationFullGet":     accessOwner,
+
+	"ApplicationExtendPost": accessOwner,
+
+	"ApplicationDeallocateBatchPost": accessAll,
+
+	"ApplicationStateStreamGet": accessAll,
+	"ApplicationApproveGet":     accessApprover,
+	"ApplicationRejectGet":      accessApprover,
+
+	"ApplicationAnnotationListGet": accessOwner,
+	"ApplicationTimelineGet":       accessOwner,
+
+	"SyncPost": accessAdmin,
+
+	"NodeListGet":                   accessAll,
+	"NodeCapacityGet":               accessAll,
+	"NodeMaintenanceGet":            accessOperator,
+	"NodeDrainGet":                  accessAll,
+	"NodeTimelineGet":               accessOperator,
+	"NodeThisGet":                   accessAll,
+	"NodeThisMaintenanceGet":        accessOperator,
+	"NodeThisDriverRestartGet":      accessOperator,
+	"NodeThisDriverInfoSchemaGet":   accessAll,
+	"NodeThisDriverCapabilitiesGet": accessAll,
+	"NodeThisDriverForecastGet":     accessAll,
+	"NodeThisLabelCompatibilityGet": accessAll,
+	"NodeThisCapacityGet":           accessAll,
+	"NodeThisBundleGet":             accessAdmin,
+	"NodeThisProfilingIndexGet":     accessAdmin,
+	"NodeThisProfilingGet":          accessAdmin,
+
+	"NodeThisBackupListGet":    accessAdmin,
+	"NodeThisBackupCreatePost": accessAdmin,
+	"NodeThisBackupGet":        accessAdmin,
+
+	"UpgradeListGet":    accessAll,
+	"UpgradeCreatePost": accessOperator,
+	"UpgradeGet":        accessAll,
+	"UpgradePauseGet":   accessOperator,
+	"UpgradeResumeGet":  accessOperator,
+	"UpgradeAbortGet":   accessOperator,
+
+	"ProjectListGet":          accessAll,
+	"ProjectCreateUpdatePost": accessAdmin,
+	"ProjectGet":              accessOwner,
+	"ProjectDelete":           accessAdmin,
+
+	"LocationListGet":    accessOperator,
+	"LocationCreatePost": accessOperator,
+
+	"ServiceMappingListGet":    accessOperator,
+	"ServiceMappingCreatePost": accessOwner,
+	"ServiceMappingGet":        accessOperator,
+	"ServiceMappingDelete":     accessOperator,
+
+	"AuditRecordListGet":   accessAuditor,
+	"AuditRecordStreamGet": accessAuditor,
+
+	"SchedulerSharesGet": accessOperator,
+
+	"SimulatorDedicatedPost": accessOperator,
+}
+
+// operationPermissions returns the permissions of the User for all the API operations
+func (e *Processor) operationPermissions(user *types.User) []types.OperationPermission {
+	roles := make(map[string]bool, len(fish.Roles))
+	for _, role := range fish.Roles {
+		roles[role] = e.fish.UserHasRole(user.Name, role)
+	}
+
+	out := make([]types.OperationPermission, 0, len(operationsAccess))
+	for op, access := range operationsAccess {
+		allowed := access.all || access.admin && user.Name == "admin"
+		for _, role := range access.roles {
+			allowed = allowed || roles[role]
+		}
+		out = append(out, types.OperationPermission{
+			Operation: op,
+			Allowed:   allowed,
+			Own:       allowed || access.own,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Operation < out[j].Operation })
+	return out
+}
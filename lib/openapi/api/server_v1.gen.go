@@ -0,0 +1,3002 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get list of Applications
+	// (GET /api/v1/application/)
+	ApplicationListGet(ctx echo.Context, params ApplicationListGetParams) error
+	// Create new Application
+	// (POST /api/v1/application/)
+	ApplicationCreatePost(ctx echo.Context) error
+	// Triggers deallocate of the selected Applications
+	// (POST /api/v1/application/deallocate)
+	ApplicationDeallocateBatchPost(ctx echo.Context) error
+	// Get list of Applications with the related objects
+	// (GET /api/v1/application/full)
+	ApplicationFullListGet(ctx echo.Context, params ApplicationFullListGetParams) error
+	// Stream the Application state changes
+	// (GET /api/v1/application/stream)
+	ApplicationStateStreamGet(ctx echo.Context, params ApplicationStateStreamGetParams) error
+	// Get Application by UID
+	// (GET /api/v1/application/{uid})
+	ApplicationGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the ApplicationAnnotations
+	// (GET /api/v1/application/{uid}/annotation/)
+	ApplicationAnnotationListGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Approve the Application allocation
+	// (GET /api/v1/application/{uid}/approve)
+	ApplicationApproveGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Triggers Application deallocate
+	// (GET /api/v1/application/{uid}/deallocate)
+	ApplicationDeallocateGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Approves Application deallocate
+	// (GET /api/v1/application/{uid}/deallocate/approve)
+	ApplicationDeallocateApproveGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Extends the Resource lifetime
+	// (POST /api/v1/application/{uid}/extend)
+	ApplicationExtendPost(ctx echo.Context, uid openapi_types.UUID) error
+	// Get Application with the related objects
+	// (GET /api/v1/application/{uid}/full)
+	ApplicationFullGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Reject the Application allocation
+	// (GET /api/v1/application/{uid}/reject)
+	ApplicationRejectGet(ctx echo.Context, uid openapi_types.UUID, params ApplicationRejectGetParams) error
+	// Get Resource by Application UID
+	// (GET /api/v1/application/{uid}/resource)
+	ApplicationResourceGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Send the secret to the Application Resource
+	// (POST /api/v1/application/{uid}/secret/)
+	ApplicationSecretCreatePost(ctx echo.Context, uid openapi_types.UUID) error
+	// Get ApplicationState of the Application
+	// (GET /api/v1/application/{uid}/state)
+	ApplicationStateGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the ApplicationTasks
+	// (GET /api/v1/application/{uid}/task/)
+	ApplicationTaskListGet(ctx echo.Context, uid openapi_types.UUID, params ApplicationTaskListGetParams) error
+	// Create new ApplicationTask
+	// (POST /api/v1/application/{uid}/task/)
+	ApplicationTaskCreatePost(ctx echo.Context, uid openapi_types.UUID) error
+	// Get the Application timeline
+	// (GET /api/v1/application/{uid}/timeline)
+	ApplicationTimelineGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the audit records
+	// (GET /api/v1/audit/)
+	AuditRecordListGet(ctx echo.Context, params AuditRecordListGetParams) error
+	// Stream the new audit records
+	// (GET /api/v1/audit/stream)
+	AuditRecordStreamGet(ctx echo.Context) error
+	// Get list of Application batches
+	// (GET /api/v1/batch/)
+	ApplicationBatchListGet(ctx echo.Context, params ApplicationBatchListGetParams) error
+	// Create new Application batch
+	// (POST /api/v1/batch/)
+	ApplicationBatchCreatePost(ctx echo.Context) error
+	// Get Application batch by UID
+	// (GET /api/v1/batch/{uid})
+	ApplicationBatchGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get the state of Application batch
+	// (GET /api/v1/batch/{uid}/state)
+	ApplicationBatchStateGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Revoke the role grant
+	// (DELETE /api/v1/grant/{uid})
+	GrantRevokeDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of Images
+	// (GET /api/v1/image/)
+	ImageListGet(ctx echo.Context, params ImageListGetParams) error
+	// Add new Image version to catalog
+	// (POST /api/v1/image/)
+	ImageCreatePost(ctx echo.Context) error
+	// Get list of Image builds
+	// (GET /api/v1/image/build/)
+	ImageBuildListGet(ctx echo.Context, params ImageBuildListGetParams) error
+	// Start the Image build
+	// (POST /api/v1/image/build/)
+	ImageBuildCreatePost(ctx echo.Context) error
+	// Get Image build by UID
+	// (GET /api/v1/image/build/{uid})
+	ImageBuildGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Delete Image by UID
+	// (DELETE /api/v1/image/{uid})
+	ImageDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get Image by UID
+	// (GET /api/v1/image/{uid})
+	ImageGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Deprecate the Image version
+	// (GET /api/v1/image/{uid}/deprecate)
+	ImageDeprecateGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of Labels
+	// (GET /api/v1/label/)
+	LabelListGet(ctx echo.Context, params LabelListGetParams) error
+	// Create new Label
+	// (POST /api/v1/label/)
+	LabelCreatePost(ctx echo.Context) error
+	// Apply the declarative set of Labels
+	// (POST /api/v1/label/apply)
+	LabelApplyPost(ctx echo.Context) error
+	// Export the Labels to the signed bundle
+	// (GET /api/v1/label/export)
+	LabelExportGet(ctx echo.Context, params LabelExportGetParams) error
+	// Import the Labels from the signed bundle
+	// (POST /api/v1/label/import)
+	LabelImportPost(ctx echo.Context, params LabelImportPostParams) error
+	// Get usage statistics of the Label versions
+	// (GET /api/v1/label/stats)
+	LabelStatsGet(ctx echo.Context, params LabelStatsGetParams) error
+	// Delete Label by UID
+	// (DELETE /api/v1/label/{uid})
+	LabelDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get Label by UID
+	// (GET /api/v1/label/{uid})
+	LabelGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of locations
+	// (GET /api/v1/location/)
+	LocationListGet(ctx echo.Context, params LocationListGetParams) error
+	// Create new Location
+	// (POST /api/v1/location/)
+	LocationCreatePost(ctx echo.Context) error
+	// Get list of Nodes
+	// (GET /api/v1/node/)
+	NodeListGet(ctx echo.Context, params NodeListGetParams) error
+	// Get the cluster capacity
+	// (GET /api/v1/node/capacity)
+	NodeCapacityGet(ctx echo.Context) error
+	// Get this Node info
+	// (GET /api/v1/node/this/)
+	NodeThisGet(ctx echo.Context) error
+	// Get list of this Node database backups
+	// (GET /api/v1/node/this/backup/)
+	NodeThisBackupListGet(ctx echo.Context) error
+	// Create backup of this Node database
+	// (POST /api/v1/node/this/backup/)
+	NodeThisBackupCreatePost(ctx echo.Context) error
+	// Download the Node database backup
+	// (GET /api/v1/node/this/backup/{name})
+	NodeThisBackupGet(ctx echo.Context, name string) error
+	// Get diagnostic bundle of the Node
+	// (GET /api/v1/node/this/bundle)
+	NodeThisBundleGet(ctx echo.Context) error
+	// Get the capacity of this Node
+	// (GET /api/v1/node/this/capacity)
+	NodeThisCapacityGet(ctx echo.Context) error
+	// Get the capabilities of the driver
+	// (GET /api/v1/node/this/driver/capabilities)
+	NodeThisDriverCapabilitiesGet(ctx echo.Context, params NodeThisDriverCapabilitiesGetParams) error
+	// Get the capacity forecast of the driver
+	// (GET /api/v1/node/this/driver/forecast)
+	NodeThisDriverForecastGet(ctx echo.Context, params NodeThisDriverForecastGetParams) error
+	// Get the schema of the driver info
+	// (GET /api/v1/node/this/driver/info_schema)
+	NodeThisDriverInfoSchemaGet(ctx echo.Context, params NodeThisDriverInfoSchemaGetParams) error
+	// Restarts the resource driver of this Node
+	// (GET /api/v1/node/this/driver/restart)
+	NodeThisDriverRestartGet(ctx echo.Context, params NodeThisDriverRestartGetParams) error
+	// Get the Labels this Node could serve
+	// (GET /api/v1/node/this/label_compatibility)
+	NodeThisLabelCompatibilityGet(ctx echo.Context) error
+	// Triggers this Node maintenance mode
+	// (GET /api/v1/node/this/maintenance)
+	NodeThisMaintenanceGet(ctx echo.Context, params NodeThisMaintenanceGetParams) error
+	// Shows pprof index page
+	// (GET /api/v1/node/this/profiling/)
+	NodeThisProfilingIndexGet(ctx echo.Context) error
+	// Gives profiling data from pprof
+	// (GET /api/v1/node/this/profiling/{handler})
+	NodeThisProfilingGet(ctx echo.Context, handler string) error
+	// Get the Node drain progress
+	// (GET /api/v1/node/{uid}/drain)
+	NodeDrainGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Switch the Node maintenance mode
+	// (GET /api/v1/node/{uid}/maintenance)
+	NodeMaintenanceGet(ctx echo.Context, uid openapi_types.UUID, params NodeMaintenanceGetParams) error
+	// Get the Node history
+	// (GET /api/v1/node/{uid}/timeline)
+	NodeTimelineGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the Projects
+	// (GET /api/v1/project/)
+	ProjectListGet(ctx echo.Context) error
+	// Create or update the Project
+	// (POST /api/v1/project/)
+	ProjectCreateUpdatePost(ctx echo.Context) error
+	// Delete the Project
+	// (DELETE /api/v1/project/{name})
+	ProjectDelete(ctx echo.Context, name ProjectName) error
+	// Get the Project
+	// (GET /api/v1/project/{name})
+	ProjectGet(ctx echo.Context, name ProjectName) error
+	// Get list of Resources
+	// (GET /api/v1/resource/)
+	ResourceListGet(ctx echo.Context, params ResourceListGetParams) error
+	// Get Resource by UID
+	// (GET /api/v1/resource/{uid})
+	ResourceGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get SSH access credentials by Resource UID
+	// (GET /api/v1/resource/{uid}/access)
+	ResourceAccessPut(ctx echo.Context, uid openapi_types.UUID, params ResourceAccessPutParams) error
+	// Get the available access methods of the Resource
+	// (GET /api/v1/resource/{uid}/access_methods)
+	ResourceAccessMethodsGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the custom roles
+	// (GET /api/v1/role/)
+	RoleListGet(ctx echo.Context) error
+	// Create or update the custom role
+	// (POST /api/v1/role/)
+	RoleCreateUpdatePost(ctx echo.Context) error
+	// Delete the custom role
+	// (DELETE /api/v1/role/{name})
+	RoleDelete(ctx echo.Context, name RoleName) error
+	// Get the custom role
+	// (GET /api/v1/role/{name})
+	RoleGet(ctx echo.Context, name RoleName) error
+	// Get list of Schedules
+	// (GET /api/v1/schedule/)
+	ScheduleListGet(ctx echo.Context, params ScheduleListGetParams) error
+	// Create new Schedule
+	// (POST /api/v1/schedule/)
+	ScheduleCreatePost(ctx echo.Context) error
+	// Delete the Schedule
+	// (DELETE /api/v1/schedule/{uid})
+	ScheduleDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get Schedule by UID
+	// (GET /api/v1/schedule/{uid})
+	ScheduleGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Disable the Schedule
+	// (GET /api/v1/schedule/{uid}/disable)
+	ScheduleDisableGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Enable the Schedule
+	// (GET /api/v1/schedule/{uid}/enable)
+	ScheduleEnableGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get the history of the Schedule runs
+	// (GET /api/v1/schedule/{uid}/history)
+	ScheduleHistoryGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Preview the next runs of the Schedule
+	// (GET /api/v1/schedule/{uid}/next)
+	ScheduleNextGet(ctx echo.Context, uid openapi_types.UUID, params ScheduleNextGetParams) error
+	// Get the cluster usage shares of the Application owners
+	// (GET /api/v1/scheduler/shares/)
+	SchedulerSharesGet(ctx echo.Context) error
+	// Get list of service mappings
+	// (GET /api/v1/servicemapping/)
+	ServiceMappingListGet(ctx echo.Context, params ServiceMappingListGetParams) error
+	// Create new ServiceMapping
+	// (POST /api/v1/servicemapping/)
+	ServiceMappingCreatePost(ctx echo.Context) error
+	// Delete the ServiceMapping by UID
+	// (DELETE /api/v1/servicemapping/{uid})
+	ServiceMappingDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get ServiceMapping by UID
+	// (GET /api/v1/servicemapping/{uid})
+	ServiceMappingGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Simulate the dedicated hosts pool usage
+	// (POST /api/v1/simulator/dedicated)
+	SimulatorDedicatedPost(ctx echo.Context) error
+	// Unsubscribe from the notifications
+	// (DELETE /api/v1/subscription/{uid})
+	UserSubscriptionDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Differential sync with the edge node
+	// (POST /api/v1/sync/)
+	SyncPost(ctx echo.Context) error
+	// Get ApplicationTask data
+	// (GET /api/v1/task/{task_uid})
+	ApplicationTaskGet(ctx echo.Context, taskUid openapi_types.UUID) error
+	// Get list of the Application templates
+	// (GET /api/v1/template/)
+	TemplateListGet(ctx echo.Context, params TemplateListGetParams) error
+	// Create or update the Application template
+	// (POST /api/v1/template/)
+	TemplateCreateUpdatePost(ctx echo.Context) error
+	// Delete the Application template by name
+	// (DELETE /api/v1/template/{name})
+	TemplateDelete(ctx echo.Context, name string) error
+	// Get the Application template by name
+	// (GET /api/v1/template/{name})
+	TemplateGet(ctx echo.Context, name string) error
+	// Revoke the User API token
+	// (DELETE /api/v1/token/{uid})
+	UserTokenRevokeDelete(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of the rolling Upgrades
+	// (GET /api/v1/upgrade/)
+	UpgradeListGet(ctx echo.Context, params UpgradeListGetParams) error
+	// Start the rolling Upgrade of the cluster
+	// (POST /api/v1/upgrade/)
+	UpgradeCreatePost(ctx echo.Context) error
+	// Get rolling Upgrade by UID
+	// (GET /api/v1/upgrade/{uid})
+	UpgradeGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Abort the rolling Upgrade
+	// (GET /api/v1/upgrade/{uid}/abort)
+	UpgradeAbortGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Pause the rolling Upgrade
+	// (GET /api/v1/upgrade/{uid}/pause)
+	UpgradePauseGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Resume the rolling Upgrade
+	// (GET /api/v1/upgrade/{uid}/resume)
+	UpgradeResumeGet(ctx echo.Context, uid openapi_types.UUID) error
+	// Get list of Users
+	// (GET /api/v1/user/)
+	UserListGet(ctx echo.Context, params UserListGetParams) error
+	// Create or update User
+	// (POST /api/v1/user/)
+	UserCreateUpdatePost(ctx echo.Context) error
+	// Get the current User
+	// (GET /api/v1/user/me/)
+	UserMeGet(ctx echo.Context) error
+	// Get the current User permissions
+	// (GET /api/v1/user/me/permissions)
+	UserMePermissionsGet(ctx echo.Context) error
+	// Get the current User preferences
+	// (GET /api/v1/user/me/preferences/)
+	PreferenceListGet(ctx echo.Context) error
+	// Remove the current User preference
+	// (DELETE /api/v1/user/me/preferences/{key})
+	PreferenceDelete(ctx echo.Context, key string) error
+	// Get the current User preference
+	// (GET /api/v1/user/me/preferences/{key})
+	PreferenceGet(ctx echo.Context, key string) error
+	// Set the current User preference
+	// (PUT /api/v1/user/me/preferences/{key})
+	PreferencePut(ctx echo.Context, key string) error
+	// Get the current User rate limits
+	// (GET /api/v1/user/me/ratelimit)
+	UserMeRateLimitGet(ctx echo.Context) error
+	// Delete the User by name
+	// (DELETE /api/v1/user/{name})
+	UserDelete(ctx echo.Context, name string) error
+	// Get User by name
+	// (GET /api/v1/user/{name})
+	UserGet(ctx echo.Context, name string) error
+	// Get list of the User role grants
+	// (GET /api/v1/user/{name}/grant/)
+	UserGrantListGet(ctx echo.Context, name string) error
+	// Grant the role to User
+	// (POST /api/v1/user/{name}/grant/)
+	UserGrantCreatePost(ctx echo.Context, name string) error
+	// Reset the User OTP secret
+	// (DELETE /api/v1/user/{name}/otp)
+	UserOTPDelete(ctx echo.Context, name string) error
+	// Enroll the User OTP secret
+	// (PUT /api/v1/user/{name}/otp)
+	UserOTPPut(ctx echo.Context, name string) error
+	// Remove the User quota
+	// (DELETE /api/v1/user/{name}/quota)
+	UserQuotaDelete(ctx echo.Context, name string) error
+	// Get the User quota and usage
+	// (GET /api/v1/user/{name}/quota)
+	UserQuotaGet(ctx echo.Context, name string) error
+	// Set the User quota
+	// (PUT /api/v1/user/{name}/quota)
+	UserQuotaPut(ctx echo.Context, name string) error
+	// Get list of the User notification subscriptions
+	// (GET /api/v1/user/{name}/subscription/)
+	UserSubscriptionListGet(ctx echo.Context, name string) error
+	// Subscribe the User to the notifications
+	// (POST /api/v1/user/{name}/subscription/)
+	UserSubscriptionCreatePost(ctx echo.Context, name string) error
+	// Get list of the User API tokens
+	// (GET /api/v1/user/{name}/token/)
+	UserTokenListGet(ctx echo.Context, name string) error
+	// Create the User API token
+	// (POST /api/v1/user/{name}/token/)
+	UserTokenCreatePost(ctx echo.Context, name string) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// ApplicationListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "owner_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner_name", ctx.QueryParams(), &params.OwnerName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter owner_name: %s", err))
+	}
+
+	// ------------- Optional query parameter "label_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_uid", ctx.QueryParams(), &params.LabelUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "older_than" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "older_than", ctx.QueryParams(), &params.OlderThan)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter older_than: %s", err))
+	}
+
+	// ------------- Optional query parameter "node_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "node_uid", ctx.QueryParams(), &params.NodeUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter node_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "project" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "project", ctx.QueryParams(), &params.Project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", ctx.QueryParams(), &params.PageToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationListGet(ctx, params)
+	return err
+}
+
+// ApplicationCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationCreatePost(ctx)
+	return err
+}
+
+// ApplicationDeallocateBatchPost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationDeallocateBatchPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationDeallocateBatchPost(ctx)
+	return err
+}
+
+// ApplicationFullListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationFullListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationFullListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "owner_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner_name", ctx.QueryParams(), &params.OwnerName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter owner_name: %s", err))
+	}
+
+	// ------------- Optional query parameter "label_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_uid", ctx.QueryParams(), &params.LabelUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "older_than" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "older_than", ctx.QueryParams(), &params.OlderThan)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter older_than: %s", err))
+	}
+
+	// ------------- Optional query parameter "node_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "node_uid", ctx.QueryParams(), &params.NodeUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter node_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "project" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "project", ctx.QueryParams(), &params.Project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", ctx.QueryParams(), &params.PageToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationFullListGet(ctx, params)
+	return err
+}
+
+// ApplicationStateStreamGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationStateStreamGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationStateStreamGetParams
+	// ------------- Optional query parameter "application_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "application_uid", ctx.QueryParams(), &params.ApplicationUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "label_uid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_uid", ctx.QueryParams(), &params.LabelUid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label_uid: %s", err))
+	}
+
+	// ------------- Optional query parameter "owner_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner_name", ctx.QueryParams(), &params.OwnerName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter owner_name: %s", err))
+	}
+
+	// ------------- Optional query parameter "project" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "project", ctx.QueryParams(), &params.Project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "resume_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resume_from", ctx.QueryParams(), &params.ResumeFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter resume_from: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationStateStreamGet(ctx, params)
+	return err
+}
+
+// ApplicationGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationGet(ctx, uid)
+	return err
+}
+
+// ApplicationAnnotationListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationAnnotationListGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationAnnotationListGet(ctx, uid)
+	return err
+}
+
+// ApplicationApproveGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationApproveGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationApproveGet(ctx, uid)
+	return err
+}
+
+// ApplicationDeallocateGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationDeallocateGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationDeallocateGet(ctx, uid)
+	return err
+}
+
+// ApplicationDeallocateApproveGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationDeallocateApproveGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationDeallocateApproveGet(ctx, uid)
+	return err
+}
+
+// ApplicationExtendPost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationExtendPost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationExtendPost(ctx, uid)
+	return err
+}
+
+// ApplicationFullGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationFullGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationFullGet(ctx, uid)
+	return err
+}
+
+// ApplicationRejectGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationRejectGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationRejectGetParams
+	// ------------- Optional query parameter "reason" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reason", ctx.QueryParams(), &params.Reason)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reason: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationRejectGet(ctx, uid, params)
+	return err
+}
+
+// ApplicationResourceGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationResourceGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationResourceGet(ctx, uid)
+	return err
+}
+
+// ApplicationSecretCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationSecretCreatePost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationSecretCreatePost(ctx, uid)
+	return err
+}
+
+// ApplicationStateGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationStateGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationStateGet(ctx, uid)
+	return err
+}
+
+// ApplicationTaskListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationTaskListGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationTaskListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationTaskListGet(ctx, uid, params)
+	return err
+}
+
+// ApplicationTaskCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationTaskCreatePost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationTaskCreatePost(ctx, uid)
+	return err
+}
+
+// ApplicationTimelineGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationTimelineGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationTimelineGet(ctx, uid)
+	return err
+}
+
+// AuditRecordListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) AuditRecordListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AuditRecordListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuditRecordListGet(ctx, params)
+	return err
+}
+
+// AuditRecordStreamGet converts echo context to params.
+func (w *ServerInterfaceWrapper) AuditRecordStreamGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuditRecordStreamGet(ctx)
+	return err
+}
+
+// ApplicationBatchListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationBatchListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplicationBatchListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationBatchListGet(ctx, params)
+	return err
+}
+
+// ApplicationBatchCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationBatchCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationBatchCreatePost(ctx)
+	return err
+}
+
+// ApplicationBatchGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationBatchGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationBatchGet(ctx, uid)
+	return err
+}
+
+// ApplicationBatchStateGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationBatchStateGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationBatchStateGet(ctx, uid)
+	return err
+}
+
+// GrantRevokeDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) GrantRevokeDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GrantRevokeDelete(ctx, uid)
+	return err
+}
+
+// ImageListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImageListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageListGet(ctx, params)
+	return err
+}
+
+// ImageCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageCreatePost(ctx)
+	return err
+}
+
+// ImageBuildListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageBuildListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImageBuildListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageBuildListGet(ctx, params)
+	return err
+}
+
+// ImageBuildCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageBuildCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageBuildCreatePost(ctx)
+	return err
+}
+
+// ImageBuildGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageBuildGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageBuildGet(ctx, uid)
+	return err
+}
+
+// ImageDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageDelete(ctx, uid)
+	return err
+}
+
+// ImageGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageGet(ctx, uid)
+	return err
+}
+
+// ImageDeprecateGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ImageDeprecateGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImageDeprecateGet(ctx, uid)
+	return err
+}
+
+// LabelListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LabelListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", ctx.QueryParams(), &params.PageToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelListGet(ctx, params)
+	return err
+}
+
+// LabelCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelCreatePost(ctx)
+	return err
+}
+
+// LabelApplyPost converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelApplyPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelApplyPost(ctx)
+	return err
+}
+
+// LabelExportGet converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelExportGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LabelExportGetParams
+	// ------------- Required query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelExportGet(ctx, params)
+	return err
+}
+
+// LabelImportPost converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelImportPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LabelImportPostParams
+	// ------------- Optional query parameter "on_conflict" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "on_conflict", ctx.QueryParams(), &params.OnConflict)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter on_conflict: %s", err))
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelImportPost(ctx, params)
+	return err
+}
+
+// LabelStatsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelStatsGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LabelStatsGetParams
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelStatsGet(ctx, params)
+	return err
+}
+
+// LabelDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelDelete(ctx, uid)
+	return err
+}
+
+// LabelGet converts echo context to params.
+func (w *ServerInterfaceWrapper) LabelGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LabelGet(ctx, uid)
+	return err
+}
+
+// LocationListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) LocationListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LocationListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LocationListGet(ctx, params)
+	return err
+}
+
+// LocationCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) LocationCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LocationCreatePost(ctx)
+	return err
+}
+
+// NodeListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", ctx.QueryParams(), &params.PageToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeListGet(ctx, params)
+	return err
+}
+
+// NodeCapacityGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeCapacityGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeCapacityGet(ctx)
+	return err
+}
+
+// NodeThisGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisGet(ctx)
+	return err
+}
+
+// NodeThisBackupListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisBackupListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisBackupListGet(ctx)
+	return err
+}
+
+// NodeThisBackupCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisBackupCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisBackupCreatePost(ctx)
+	return err
+}
+
+// NodeThisBackupGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisBackupGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisBackupGet(ctx, name)
+	return err
+}
+
+// NodeThisBundleGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisBundleGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisBundleGet(ctx)
+	return err
+}
+
+// NodeThisCapacityGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisCapacityGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisCapacityGet(ctx)
+	return err
+}
+
+// NodeThisDriverCapabilitiesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisDriverCapabilitiesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeThisDriverCapabilitiesGetParams
+	// ------------- Required query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisDriverCapabilitiesGet(ctx, params)
+	return err
+}
+
+// NodeThisDriverForecastGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisDriverForecastGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeThisDriverForecastGetParams
+	// ------------- Required query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisDriverForecastGet(ctx, params)
+	return err
+}
+
+// NodeThisDriverInfoSchemaGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisDriverInfoSchemaGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeThisDriverInfoSchemaGetParams
+	// ------------- Required query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisDriverInfoSchemaGet(ctx, params)
+	return err
+}
+
+// NodeThisDriverRestartGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisDriverRestartGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeThisDriverRestartGetParams
+	// ------------- Required query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisDriverRestartGet(ctx, params)
+	return err
+}
+
+// NodeThisLabelCompatibilityGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisLabelCompatibilityGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisLabelCompatibilityGet(ctx)
+	return err
+}
+
+// NodeThisMaintenanceGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisMaintenanceGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeThisMaintenanceGetParams
+	// ------------- Optional query parameter "enable" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "enable", ctx.QueryParams(), &params.Enable)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter enable: %s", err))
+	}
+
+	// ------------- Optional query parameter "shutdown" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shutdown", ctx.QueryParams(), &params.Shutdown)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shutdown: %s", err))
+	}
+
+	// ------------- Optional query parameter "handover" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "handover", ctx.QueryParams(), &params.Handover)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter handover: %s", err))
+	}
+
+	// ------------- Optional query parameter "shutdown_delay" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shutdown_delay", ctx.QueryParams(), &params.ShutdownDelay)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shutdown_delay: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisMaintenanceGet(ctx, params)
+	return err
+}
+
+// NodeThisProfilingIndexGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisProfilingIndexGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisProfilingIndexGet(ctx)
+	return err
+}
+
+// NodeThisProfilingGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeThisProfilingGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "handler" -------------
+	var handler string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "handler", ctx.Param("handler"), &handler, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter handler: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeThisProfilingGet(ctx, handler)
+	return err
+}
+
+// NodeDrainGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeDrainGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeDrainGet(ctx, uid)
+	return err
+}
+
+// NodeMaintenanceGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeMaintenanceGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params NodeMaintenanceGetParams
+	// ------------- Optional query parameter "enable" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "enable", ctx.QueryParams(), &params.Enable)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter enable: %s", err))
+	}
+
+	// ------------- Optional query parameter "drain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "drain", ctx.QueryParams(), &params.Drain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter drain: %s", err))
+	}
+
+	// ------------- Optional query parameter "drain_timeout" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "drain_timeout", ctx.QueryParams(), &params.DrainTimeout)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter drain_timeout: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeMaintenanceGet(ctx, uid, params)
+	return err
+}
+
+// NodeTimelineGet converts echo context to params.
+func (w *ServerInterfaceWrapper) NodeTimelineGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.NodeTimelineGet(ctx, uid)
+	return err
+}
+
+// ProjectListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ProjectListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ProjectListGet(ctx)
+	return err
+}
+
+// ProjectCreateUpdatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ProjectCreateUpdatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ProjectCreateUpdatePost(ctx)
+	return err
+}
+
+// ProjectDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) ProjectDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name ProjectName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ProjectDelete(ctx, name)
+	return err
+}
+
+// ProjectGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ProjectGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name ProjectName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ProjectGet(ctx, name)
+	return err
+}
+
+// ResourceListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ResourceListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ResourceListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResourceListGet(ctx, params)
+	return err
+}
+
+// ResourceGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ResourceGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResourceGet(ctx, uid)
+	return err
+}
+
+// ResourceAccessPut converts echo context to params.
+func (w *ServerInterfaceWrapper) ResourceAccessPut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ResourceAccessPutParams
+	// ------------- Optional query parameter "otp" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "otp", ctx.QueryParams(), &params.Otp)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter otp: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResourceAccessPut(ctx, uid, params)
+	return err
+}
+
+// ResourceAccessMethodsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ResourceAccessMethodsGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResourceAccessMethodsGet(ctx, uid)
+	return err
+}
+
+// RoleListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleListGet(ctx)
+	return err
+}
+
+// RoleCreateUpdatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleCreateUpdatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleCreateUpdatePost(ctx)
+	return err
+}
+
+// RoleDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name RoleName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleDelete(ctx, name)
+	return err
+}
+
+// RoleGet converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name RoleName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleGet(ctx, name)
+	return err
+}
+
+// ScheduleListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScheduleListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleListGet(ctx, params)
+	return err
+}
+
+// ScheduleCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleCreatePost(ctx)
+	return err
+}
+
+// ScheduleDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleDelete(ctx, uid)
+	return err
+}
+
+// ScheduleGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleGet(ctx, uid)
+	return err
+}
+
+// ScheduleDisableGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleDisableGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleDisableGet(ctx, uid)
+	return err
+}
+
+// ScheduleEnableGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleEnableGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleEnableGet(ctx, uid)
+	return err
+}
+
+// ScheduleHistoryGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleHistoryGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleHistoryGet(ctx, uid)
+	return err
+}
+
+// ScheduleNextGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleNextGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScheduleNextGetParams
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", ctx.QueryParams(), &params.Count)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter count: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ScheduleNextGet(ctx, uid, params)
+	return err
+}
+
+// SchedulerSharesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SchedulerSharesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SchedulerSharesGet(ctx)
+	return err
+}
+
+// ServiceMappingListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ServiceMappingListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ServiceMappingListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ServiceMappingListGet(ctx, params)
+	return err
+}
+
+// ServiceMappingCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) ServiceMappingCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ServiceMappingCreatePost(ctx)
+	return err
+}
+
+// ServiceMappingDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) ServiceMappingDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ServiceMappingDelete(ctx, uid)
+	return err
+}
+
+// ServiceMappingGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ServiceMappingGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ServiceMappingGet(ctx, uid)
+	return err
+}
+
+// SimulatorDedicatedPost converts echo context to params.
+func (w *ServerInterfaceWrapper) SimulatorDedicatedPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SimulatorDedicatedPost(ctx)
+	return err
+}
+
+// UserSubscriptionDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) UserSubscriptionDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserSubscriptionDelete(ctx, uid)
+	return err
+}
+
+// SyncPost converts echo context to params.
+func (w *ServerInterfaceWrapper) SyncPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SyncPost(ctx)
+	return err
+}
+
+// ApplicationTaskGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ApplicationTaskGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "task_uid" -------------
+	var taskUid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "task_uid", ctx.Param("task_uid"), &taskUid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter task_uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ApplicationTaskGet(ctx, taskUid)
+	return err
+}
+
+// TemplateListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) TemplateListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TemplateListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.TemplateListGet(ctx, params)
+	return err
+}
+
+// TemplateCreateUpdatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) TemplateCreateUpdatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.TemplateCreateUpdatePost(ctx)
+	return err
+}
+
+// TemplateDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) TemplateDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.TemplateDelete(ctx, name)
+	return err
+}
+
+// TemplateGet converts echo context to params.
+func (w *ServerInterfaceWrapper) TemplateGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.TemplateGet(ctx, name)
+	return err
+}
+
+// UserTokenRevokeDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) UserTokenRevokeDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserTokenRevokeDelete(ctx, uid)
+	return err
+}
+
+// UpgradeListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradeListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpgradeListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradeListGet(ctx, params)
+	return err
+}
+
+// UpgradeCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradeCreatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradeCreatePost(ctx)
+	return err
+}
+
+// UpgradeGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradeGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradeGet(ctx, uid)
+	return err
+}
+
+// UpgradeAbortGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradeAbortGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradeAbortGet(ctx, uid)
+	return err
+}
+
+// UpgradePauseGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradePauseGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradePauseGet(ctx, uid)
+	return err
+}
+
+// UpgradeResumeGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UpgradeResumeGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", ctx.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uid: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpgradeResumeGet(ctx, uid)
+	return err
+}
+
+// UserListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UserListGetParams
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "report" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "report", ctx.QueryParams(), &params.Report)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter report: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", ctx.QueryParams(), &params.PageToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserListGet(ctx, params)
+	return err
+}
+
+// UserCreateUpdatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) UserCreateUpdatePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserCreateUpdatePost(ctx)
+	return err
+}
+
+// UserMeGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserMeGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserMeGet(ctx)
+	return err
+}
+
+// UserMePermissionsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserMePermissionsGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserMePermissionsGet(ctx)
+	return err
+}
+
+// PreferenceListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) PreferenceListGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PreferenceListGet(ctx)
+	return err
+}
+
+// PreferenceDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) PreferenceDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", ctx.Param("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PreferenceDelete(ctx, key)
+	return err
+}
+
+// PreferenceGet converts echo context to params.
+func (w *ServerInterfaceWrapper) PreferenceGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", ctx.Param("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PreferenceGet(ctx, key)
+	return err
+}
+
+// PreferencePut converts echo context to params.
+func (w *ServerInterfaceWrapper) PreferencePut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", ctx.Param("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PreferencePut(ctx, key)
+	return err
+}
+
+// UserMeRateLimitGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserMeRateLimitGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserMeRateLimitGet(ctx)
+	return err
+}
+
+// UserDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) UserDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserDelete(ctx, name)
+	return err
+}
+
+// UserGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserGet(ctx, name)
+	return err
+}
+
+// UserGrantListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserGrantListGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserGrantListGet(ctx, name)
+	return err
+}
+
+// UserGrantCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) UserGrantCreatePost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserGrantCreatePost(ctx, name)
+	return err
+}
+
+// UserOTPDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) UserOTPDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserOTPDelete(ctx, name)
+	return err
+}
+
+// UserOTPPut converts echo context to params.
+func (w *ServerInterfaceWrapper) UserOTPPut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserOTPPut(ctx, name)
+	return err
+}
+
+// UserQuotaDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) UserQuotaDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserQuotaDelete(ctx, name)
+	return err
+}
+
+// UserQuotaGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserQuotaGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserQuotaGet(ctx, name)
+	return err
+}
+
+// UserQuotaPut converts echo context to params.
+func (w *ServerInterfaceWrapper) UserQuotaPut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserQuotaPut(ctx, name)
+	return err
+}
+
+// UserSubscriptionListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserSubscriptionListGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserSubscriptionListGet(ctx, name)
+	return err
+}
+
+// UserSubscriptionCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) UserSubscriptionCreatePost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserSubscriptionCreatePost(ctx, name)
+	return err
+}
+
+// UserTokenListGet converts echo context to params.
+func (w *ServerInterfaceWrapper) UserTokenListGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserTokenListGet(ctx, name)
+	return err
+}
+
+// UserTokenCreatePost converts echo context to params.
+func (w *ServerInterfaceWrapper) UserTokenCreatePost(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(Basic_authScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserTokenCreatePost(ctx, name)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.GET(baseURL+"/api/v1/application/", wrapper.ApplicationListGet)
+	router.POST(baseURL+"/api/v1/application/", wrapper.ApplicationCreatePost)
+	router.POST(baseURL+"/api/v1/application/deallocate", wrapper.ApplicationDeallocateBatchPost)
+	router.GET(baseURL+"/api/v1/application/full", wrapper.ApplicationFullListGet)
+	router.GET(baseURL+"/api/v1/application/stream", wrapper.ApplicationStateStreamGet)
+	router.GET(baseURL+"/api/v1/application/:uid", wrapper.ApplicationGet)
+	router.GET(baseURL+"/api/v1/application/:uid/annotation/", wrapper.ApplicationAnnotationListGet)
+	router.GET(baseURL+"/api/v1/application/:uid/approve", wrapper.ApplicationApproveGet)
+	router.GET(baseURL+"/api/v1/application/:uid/deallocate", wrapper.ApplicationDeallocateGet)
+	router.GET(baseURL+"/api/v1/application/:uid/deallocate/approve", wrapper.ApplicationDeallocateApproveGet)
+	router.POST(baseURL+"/api/v1/application/:uid/extend", wrapper.ApplicationExtendPost)
+	router.GET(baseURL+"/api/v1/application/:uid/full", wrapper.ApplicationFullGet)
+	router.GET(baseURL+"/api/v1/application/:uid/reject", wrapper.ApplicationRejectGet)
+	router.GET(baseURL+"/api/v1/application/:uid/resource", wrapper.ApplicationResourceGet)
+	router.POST(baseURL+"/api/v1/application/:uid/secret/", wrapper.ApplicationSecretCreatePost)
+	router.GET(baseURL+"/api/v1/application/:uid/state", wrapper.ApplicationStateGet)
+	router.GET(baseURL+"/api/v1/application/:uid/task/", wrapper.ApplicationTaskListGet)
+	router.POST(baseURL+"/api/v1/application/:uid/task/", wrapper.ApplicationTaskCreatePost)
+	router.GET(baseURL+"/api/v1/application/:uid/timeline", wrapper.ApplicationTimelineGet)
+	router.GET(baseURL+"/api/v1/audit/", wrapper.AuditRecordListGet)
+	router.GET(baseURL+"/api/v1/audit/stream", wrapper.AuditRecordStreamGet)
+	router.GET(baseURL+"/api/v1/batch/", wrapper.ApplicationBatchListGet)
+	router.POST(baseURL+"/api/v1/batch/", wrapper.ApplicationBatchCreatePost)
+	router.GET(baseURL+"/api/v1/batch/:uid", wrapper.ApplicationBatchGet)
+	router.GET(baseURL+"/api/v1/batch/:uid/state", wrapper.ApplicationBatchStateGet)
+	router.DELETE(baseURL+"/api/v1/grant/:uid", wrapper.GrantRevokeDelete)
+	router.GET(baseURL+"/api/v1/image/", wrapper.ImageListGet)
+	router.POST(baseURL+"/api/v1/image/", wrapper.ImageCreatePost)
+	router.GET(baseURL+"/api/v1/image/build/", wrapper.ImageBuildListGet)
+	router.POST(baseURL+"/api/v1/image/build/", wrapper.ImageBuildCreatePost)
+	router.GET(baseURL+"/api/v1/image/build/:uid", wrapper.ImageBuildGet)
+	router.DELETE(baseURL+"/api/v1/image/:uid", wrapper.ImageDelete)
+	router.GET(baseURL+"/api/v1/image/:uid", wrapper.ImageGet)
+	router.GET(baseURL+"/api/v1/image/:uid/deprecate", wrapper.ImageDeprecateGet)
+	router.GET(baseURL+"/api/v1/label/", wrapper.LabelListGet)
+	router.POST(baseURL+"/api/v1/label/", wrapper.LabelCreatePost)
+	router.POST(baseURL+"/api/v1/label/apply", wrapper.LabelApplyPost)
+	router.GET(baseURL+"/api/v1/label/export", wrapper.LabelExportGet)
+	router.POST(baseURL+"/api/v1/label/import", wrapper.LabelImportPost)
+	router.GET(baseURL+"/api/v1/label/stats", wrapper.LabelStatsGet)
+	router.DELETE(baseURL+"/api/v1/label/:uid", wrapper.LabelDelete)
+	router.GET(baseURL+"/api/v1/label/:uid", wrapper.LabelGet)
+	router.GET(baseURL+"/api/v1/location/", wrapper.LocationListGet)
+	router.POST(baseURL+"/api/v1/location/", wrapper.LocationCreatePost)
+	router.GET(baseURL+"/api/v1/node/", wrapper.NodeListGet)
+	router.GET(baseURL+"/api/v1/node/capacity", wrapper.NodeCapacityGet)
+	router.GET(baseURL+"/api/v1/node/this/", wrapper.NodeThisGet)
+	router.GET(baseURL+"/api/v1/node/this/backup/", wrapper.NodeThisBackupListGet)
+	router.POST(baseURL+"/api/v1/node/this/backup/", wrapper.NodeThisBackupCreatePost)
+	router.GET(baseURL+"/api/v1/node/this/backup/:name", wrapper.NodeThisBackupGet)
+	router.GET(baseURL+"/api/v1/node/this/bundle", wrapper.NodeThisBundleGet)
+	router.GET(baseURL+"/api/v1/node/this/capacity", wrapper.NodeThisCapacityGet)
+	router.GET(baseURL+"/api/v1/node/this/driver/capabilities", wrapper.NodeThisDriverCapabilitiesGet)
+	router.GET(baseURL+"/api/v1/node/this/driver/forecast", wrapper.NodeThisDriverForecastGet)
+	router.GET(baseURL+"/api/v1/node/this/driver/info_schema", wrapper.NodeThisDriverInfoSchemaGet)
+	router.GET(baseURL+"/api/v1/node/this/driver/restart", wrapper.NodeThisDriverRestartGet)
+	router.GET(baseURL+"/api/v1/node/this/label_compatibility", wrapper.NodeThisLabelCompatibilityGet)
+	router.GET(baseURL+"/api/v1/node/this/maintenance", wrapper.NodeThisMaintenanceGet)
+	router.GET(baseURL+"/api/v1/node/this/profiling/", wrapper.NodeThisProfilingIndexGet)
+	router.GET(baseURL+"/api/v1/node/this/profiling/:handler", wrapper.NodeThisProfilingGet)
+	router.GET(baseURL+"/api/v1/node/:uid/drain", wrapper.NodeDrainGet)
+	router.GET(baseURL+"/api/v1/node/:uid/maintenance", wrapper.NodeMaintenanceGet)
+	router.GET(baseURL+"/api/v1/node/:uid/timeline", wrapper.NodeTimelineGet)
+	router.GET(baseURL+"/api/v1/project/", wrapper.ProjectListGet)
+	router.POST(baseURL+"/api/v1/project/", wrapper.ProjectCreateUpdatePost)
+	router.DELETE(baseURL+"/api/v1/project/:name", wrapper.ProjectDelete)
+	router.GET(baseURL+"/api/v1/project/:name", wrapper.ProjectGet)
+	router.GET(baseURL+"/api/v1/resource/", wrapper.ResourceListGet)
+	router.GET(baseURL+"/api/v1/resource/:uid", wrapper.ResourceGet)
+	router.GET(baseURL+"/api/v1/resource/:uid/access", wrapper.ResourceAccessPut)
+	router.GET(baseURL+"/api/v1/resource/:uid/access_methods", wrapper.ResourceAccessMethodsGet)
+	router.GET(baseURL+"/api/v1/role/", wrapper.RoleListGet)
+	router.POST(baseURL+"/api/v1/role/", wrapper.RoleCreateUpdatePost)
+	router.DELETE(baseURL+"/api/v1/role/:name", wrapper.RoleDelete)
+	router.GET(baseURL+"/api/v1/role/:name", wrapper.RoleGet)
+	router.GET(baseURL+"/api/v1/schedule/", wrapper.ScheduleListGet)
+	router.POST(baseURL+"/api/v1/schedule/", wrapper.ScheduleCreatePost)
+	router.DELETE(baseURL+"/api/v1/schedule/:uid", wrapper.ScheduleDelete)
+	router.GET(baseURL+"/api/v1/schedule/:uid", wrapper.ScheduleGet)
+	router.GET(baseURL+"/api/v1/schedule/:uid/disable", wrapper.ScheduleDisableGet)
+	router.GET(baseURL+"/api/v1/schedule/:uid/enable", wrapper.ScheduleEnableGet)
+	router.GET(baseURL+"/api/v1/schedule/:uid/history", wrapper.ScheduleHistoryGet)
+	router.GET(baseURL+"/api/v1/schedule/:uid/next", wrapper.ScheduleNextGet)
+	router.GET(baseURL+"/api/v1/scheduler/shares/", wrapper.SchedulerSharesGet)
+	router.GET(baseURL+"/api/v1/servicemapping/", wrapper.ServiceMappingListGet)
+	router.POST(baseURL+"/api/v1/servicemapping/", wrapper.ServiceMappingCreatePost)
+	router.DELETE(baseURL+"/api/v1/servicemapping/:uid", wrapper.ServiceMappingDelete)
+	router.GET(baseURL+"/api/v1/servicemapping/:uid", wrapper.ServiceMappingGet)
+	router.POST(baseURL+"/api/v1/simulator/dedicated", wrapper.SimulatorDedicatedPost)
+	router.DELETE(baseURL+"/api/v1/subscription/:uid", wrapper.UserSubscriptionDelete)
+	router.POST(baseURL+"/api/v1/sync/", wrapper.SyncPost)
+	router.GET(baseURL+"/api/v1/task/:task_uid", wrapper.ApplicationTaskGet)
+	router.GET(baseURL+"/api/v1/template/", wrapper.TemplateListGet)
+	router.POST(baseURL+"/api/v1/template/", wrapper.TemplateCreateUpdatePost)
+	router.DELETE(baseURL+"/api/v1/template/:name", wrapper.TemplateDelete)
+	router.GET(baseURL+"/api/v1/template/:name", wrapper.TemplateGet)
+	router.DELETE(baseURL+"/api/v1/token/:uid", wrapper.UserTokenRevokeDelete)
+	router.GET(baseURL+"/api/v1/upgrade/", wrapper.UpgradeListGet)
+	router.POST(baseURL+"/api/v1/upgrade/", wrapper.UpgradeCreatePost)
+	router.GET(baseURL+"/api/v1/upgrade/:uid", wrapper.UpgradeGet)
+	router.GET(baseURL+"/api/v1/upgrade/:uid/abort", wrapper.UpgradeAbortGet)
+	router.GET(baseURL+"/api/v1/upgrade/:uid/pause", wrapper.UpgradePauseGet)
+	router.GET(baseURL+"/api/v1/upgrade/:uid/resume", wrapper.UpgradeResumeGet)
+	router.GET(baseURL+"/api/v1/user/", wrapper.UserListGet)
+	router.POST(baseURL+"/api/v1/user/", wrapper.UserCreateUpdatePost)
+	router.GET(baseURL+"/api/v1/user/me/", wrapper.UserMeGet)
+	router.GET(baseURL+"/api/v1/user/me/permissions", wrapper.UserMePermissionsGet)
+	router.GET(baseURL+"/api/v1/user/me/preferences/", wrapper.PreferenceListGet)
+	router.DELETE(baseURL+"/api/v1/user/me/preferences/:key", wrapper.PreferenceDelete)
+	router.GET(baseURL+"/api/v1/user/me/preferences/:key", wrapper.PreferenceGet)
+	router.PUT(baseURL+"/api/v1/user/me/preferences/:key", wrapper.PreferencePut)
+	router.GET(baseURL+"/api/v1/user/me/ratelimit", wrapper.UserMeRateLimitGet)
+	router.DELETE(baseURL+"/api/v1/user/:name", wrapper.UserDelete)
+	router.GET(baseURL+"/api/v1/user/:name", wrapper.UserGet)
+	router.GET(baseURL+"/api/v1/user/:name/grant/", wrapper.UserGrantListGet)
+	router.POST(baseURL+"/api/v1/user/:name/grant/", wrapper.UserGrantCreatePost)
+	router.DELETE(baseURL+"/api/v1/user/:name/otp", wrapper.UserOTPDelete)
+	router.PUT(baseURL+"/api/v1/user/:name/otp", wrapper.UserOTPPut)
+	router.DELETE(baseURL+"/api/v1/user/:name/quota", wrapper.UserQuotaDelete)
+	router.GET(baseURL+"/api/v1/user/:name/quota", wrapper.UserQuotaGet)
+	router.PUT(baseURL+"/api/v1/user/:name/quota", wrapper.UserQuotaPut)
+	router.GET(baseURL+"/api/v1/user/:name/subscription/", wrapper.UserSubscriptionListGet)
+	router.POST(baseURL+"/api/v1/user/:name/subscription/", wrapper.UserSubscriptionCreatePost)
+	router.GET(baseURL+"/api/v1/user/:name/token/", wrapper.UserTokenListGet)
+	router.POST(baseURL+"/api/v1/user/:name/token/", wrapper.UserTokenCreatePost)
+
+}
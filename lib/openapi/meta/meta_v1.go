@@ -109,3 +109,121 @@ func (e *Processor) DataGet(c echo.Context, _ /*keyPath*/ string, _ /*params*/ t
 	e.Return(c, http.StatusNotFound, H{"message": "TODO: Not implemented"})
 	return fmt.Errorf("TODO: Not implemented")
 }
+
+// SecretGetList returns the pending secrets of the Resource, each secret is returned only once
+func (e *Processor) SecretGetList(c echo.Context) error {
+	res, ok := c.Get("resource").(*types.Resource)
+	if !ok {
+		c.JSON(http.StatusNotFound, H{"message": "No data found"})
+		return fmt.Errorf("Unable to get resource from context")
+	}
+
+	out, err := e.fish.ApplicationSecretListPop(res.ApplicationUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, H{"message": "Unable to get the secrets"})
+		return fmt.Errorf("Unable to get secrets of Resource %s: %w", res.UID, err)
+	}
+	if out == nil {
+		out = []types.ApplicationSecret{}
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// VaultGetList returns the bootstrap secrets of the Resource, they could be received only once
+func (e *Processor) VaultGetList(c echo.Context, _ /*params*/ types.VaultGetListParams) error {
+	res, ok := c.Get("resource").(*types.Resource)
+	if !ok {
+		e.Return(c, http.StatusNotFound, H{"message": "No data found"})
+		return fmt.Errorf("Unable to get resource from context")
+	}
+
+	out, err := e.fish.VaultPop(res.ApplicationUID)
+	if err != nil {
+		e.Return(c, http.StatusInternalServerError, H{"message": "Unable to get the vault secrets"})
+		return fmt.Errorf("Unable to get vault secrets of Resource %s: %w", res.UID, err)
+	}
+
+	return e.Return(c, http.StatusOK, out)
+}
+
+// SecretKeyPut registers the Resource public key to receive the secrets
+func (e *Processor) SecretKeyPut(c echo.Context) error {
+	res, ok := c.Get("resource").(*types.Resource)
+	if !ok {
+		c.JSON(http.StatusNotFound, H{"message": "No data found"})
+		return fmt.Errorf("Unable to get resource from context")
+	}
+
+	var data types.SecretKey
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+
+	// The key allows to receive the secrets, so the registration attempts are audited
+	err := e.fish.ResourceSecretKeySet(res, data.PublicKey)
+	rec := &types.AuditRecord{
+		AuthMethod: fish.AuditAuthResourceIP,
+		SourceIp:   c.RealIP(),
+		Action:     c.Request().Method + " " + c.Path(),
+		ObjectType: "Resource",
+		ObjectId:   res.UID.String(),
+		Status:     http.StatusOK,
+		Changes:    fish.AuditChanges(nil, &data),
+	}
+	if err != nil {
+		rec.Status = http.StatusBadRequest
+	}
+	if aerr := e.fish.AuditRecordCreate(rec); aerr != nil {
+		log.Error("API META: Unable to write audit record:", aerr)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to register the key: %v", err)})
+		return fmt.Errorf("Unable to register secret key of Resource %s: %w", res.UID, err)
+	}
+
+	return c.JSON(http.StatusOK, H{"message": "Secret key registered"})
+}
+
+// IdentityTokenGet issues the OIDC token asserting the Resource identity
+func (e *Processor) IdentityTokenGet(c echo.Context, params types.IdentityTokenGetParams) error {
+	res, ok := c.Get("resource").(*types.Resource)
+	if !ok {
+		c.JSON(http.StatusNotFound, H{"message": "No data found"})
+		return fmt.Errorf("Unable to get resource from context")
+	}
+
+	audience := ""
+	if params.Audience != nil {
+		audience = *params.Audience
+	}
+	out, err := e.fish.WorkloadTokenIssue(res, audience)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to issue the token: %v", err)})
+		return fmt.Errorf("Unable to issue identity token for Resource %s: %w", res.UID, err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ActivityPost stores the Resource activity reported by the agent to detect the idle Resources
+func (e *Processor) ActivityPost(c echo.Context) error {
+	res, ok := c.Get("resource").(*types.Resource)
+	if !ok {
+		c.JSON(http.StatusNotFound, H{"message": "No data found"})
+		return fmt.Errorf("Unable to get resource from context")
+	}
+
+	var data types.ResourceActivity
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Wrong request body: %v", err)})
+		return fmt.Errorf("Wrong request body: %w", err)
+	}
+	if err := e.fish.ResourceActivityReport(res, &data); err != nil {
+		c.JSON(http.StatusBadRequest, H{"message": fmt.Sprintf("Unable to store the activity: %v", err)})
+		return fmt.Errorf("Unable to store the activity: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, H{"message": "Activity stored"})
+}
@@ -0,0 +1,208 @@
+// Package meta provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package meta
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+)
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Report the Resource activity
+	// (POST /meta/v1/activity)
+	ActivityPost(ctx echo.Context) error
+	// Get the Resource metadata
+	// (GET /meta/v1/data/)
+	DataGetList(ctx echo.Context, params DataGetListParams) error
+	// TODO Get value by key path
+	// (GET /meta/v1/data/{key_path})
+	DataGet(ctx echo.Context, keyPath string, params DataGetParams) error
+	// Get the Resource identity token
+	// (GET /meta/v1/identity/token)
+	IdentityTokenGet(ctx echo.Context, params IdentityTokenGetParams) error
+	// Receive the secrets for the Resource
+	// (GET /meta/v1/secret/)
+	SecretGetList(ctx echo.Context) error
+	// Register the Resource secret public key
+	// (PUT /meta/v1/secret/key)
+	SecretKeyPut(ctx echo.Context) error
+	// Receive the bootstrap secrets of the Resource
+	// (GET /meta/v1/vault/)
+	VaultGetList(ctx echo.Context, params VaultGetListParams) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// ActivityPost converts echo context to params.
+func (w *ServerInterfaceWrapper) ActivityPost(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ActivityPost(ctx)
+	return err
+}
+
+// DataGetList converts echo context to params.
+func (w *ServerInterfaceWrapper) DataGetList(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DataGetListParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "prefix", ctx.QueryParams(), &params.Prefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter prefix: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DataGetList(ctx, params)
+	return err
+}
+
+// DataGet converts echo context to params.
+func (w *ServerInterfaceWrapper) DataGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key_path" -------------
+	var keyPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key_path", ctx.Param("key_path"), &keyPath, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key_path: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DataGetParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "prefix", ctx.QueryParams(), &params.Prefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter prefix: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DataGet(ctx, keyPath, params)
+	return err
+}
+
+// IdentityTokenGet converts echo context to params.
+func (w *ServerInterfaceWrapper) IdentityTokenGet(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params IdentityTokenGetParams
+	// ------------- Optional query parameter "audience" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "audience", ctx.QueryParams(), &params.Audience)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter audience: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.IdentityTokenGet(ctx, params)
+	return err
+}
+
+// SecretGetList converts echo context to params.
+func (w *ServerInterfaceWrapper) SecretGetList(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SecretGetList(ctx)
+	return err
+}
+
+// SecretKeyPut converts echo context to params.
+func (w *ServerInterfaceWrapper) SecretKeyPut(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SecretKeyPut(ctx)
+	return err
+}
+
+// VaultGetList converts echo context to params.
+func (w *ServerInterfaceWrapper) VaultGetList(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params VaultGetListParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "prefix", ctx.QueryParams(), &params.Prefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter prefix: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.VaultGetList(ctx, params)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.POST(baseURL+"/meta/v1/activity", wrapper.ActivityPost)
+	router.GET(baseURL+"/meta/v1/data/", wrapper.DataGetList)
+	router.GET(baseURL+"/meta/v1/data/:key_path", wrapper.DataGet)
+	router.GET(baseURL+"/meta/v1/identity/token", wrapper.IdentityTokenGet)
+	router.GET(baseURL+"/meta/v1/secret/", wrapper.SecretGetList)
+	router.PUT(baseURL+"/meta/v1/secret/key", wrapper.SecretKeyPut)
+	router.GET(baseURL+"/meta/v1/vault/", wrapper.VaultGetList)
+
+}
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	echomw "github.com/labstack/echo/v4/middleware"
 	_ "github.com/oapi-codegen/oapi-codegen/v2/pkg/util" // We need util here otherwise it will not load the needed imports and fail go.mod vetting
@@ -35,6 +36,7 @@ import (
 
 	"github.com/adobe/aquarium-fish/lib/fish"
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/metrics"
 	"github.com/adobe/aquarium-fish/lib/openapi/api"
 	"github.com/adobe/aquarium-fish/lib/openapi/meta"
 )
@@ -66,8 +68,23 @@ func (*YamlBinder) Bind(i any, c echo.Context) (err error) {
 	return
 }
 
+// apiOperations returns the API operations by the echo route "METHOD /path"
+func apiOperations(swagger *openapi3.T) map[string]api.Operation {
+	out := make(map[string]api.Operation)
+	for path, item := range swagger.Paths.Map() {
+		// Echo uses ":param" instead of "{param}" in the routes
+		route := strings.NewReplacer("{", ":", "}", "").Replace(path)
+		for method, op := range item.Operations() {
+			out[method+" "+route] = api.Operation{ID: op.OperationID, Tags: op.Tags}
+		}
+	}
+	return out
+}
+
 // Init startups the API server to listen for incoming requests
-func Init(f *fish.Fish, apiAddress, caPath, certPath, keyPath string) (*http.Server, error) {
+// If metricsAuth is true - the /metrics endpoint requires basic auth of admin, operator or auditor
+// If clientsPath is set - the generated API clients are served from it on /api/clients/
+func Init(f *fish.Fish, apiAddress, caPath, certPath, keyPath string, metricsAuth bool, clientsPath string) (*http.Server, error) {
 	swagger, err := GetSwagger()
 	if err != nil {
 		return nil, fmt.Errorf("Fish OpenAPI: Error loading swagger spec: %w", err)
@@ -82,6 +99,7 @@ func Init(f *fish.Fish, apiAddress, caPath, certPath, keyPath string) (*http.Ser
 	router.Binder = &YamlBinder{}
 
 	router.Use(echomw.Logger())
+	router.Use(tracingMiddleware)
 	// TODO: Make sure openapi schema validation is possible
 	//router.Use(oapimw.OapiRequestValidator(swagger))
 	router.HideBanner = true
@@ -89,7 +107,59 @@ func Init(f *fish.Fish, apiAddress, caPath, certPath, keyPath string) (*http.Ser
 	// TODO: Probably it will be a feature an ability to separate those
 	// routers to independence ports if needed
 	meta.NewV1Router(router, f)
-	api.NewV1Router(router, f)
+	api.NewV1Router(router, f, apiOperations(swagger))
+
+	// Prometheus metrics are served in text format, so not a part of the OpenAPI spec
+	var metricsMw []echo.MiddlewareFunc
+	if metricsAuth {
+		metricsMw = append(metricsMw, echomw.BasicAuth(func(username, password string, _ echo.Context) (bool, error) {
+			user := f.UserAuth(username, password)
+			if user == nil {
+				return false, nil
+			}
+			// The metrics contain the cluster usage and the owners data, so not for the regular users
+			if user.Name != "admin" && !f.UserHasRole(user.Name, fish.RoleOperator) && !f.UserHasRole(user.Name, fish.RoleAuditor) {
+				return false, echo.NewHTTPError(http.StatusForbidden, "Only admin, operator or auditor can get the metrics")
+			}
+			return true, nil
+		}))
+	}
+	router.GET("/metrics", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		metrics.Default.WriteText(c.Response())
+		return nil
+	}, metricsMw...)
+	// OIDC discovery of the workload identity is public to let the external systems verify tokens
+	if f.WorkloadIdentityEnabled() {
+		router.GET("/.well-known/openid-configuration", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, f.WorkloadOIDCConfiguration())
+		})
+		router.GET("/.well-known/jwks.json", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, f.WorkloadJWKS())
+		})
+	}
+	// The spec is public to allow the non-Go tools to generate the clients
+	specJSON, err := swagger.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("Fish OpenAPI: Unable to encode spec: %w", err)
+	}
+	var specTree any
+	if err = yaml.Unmarshal(specJSON, &specTree); err != nil {
+		return nil, fmt.Errorf("Fish OpenAPI: Unable to parse spec: %w", err)
+	}
+	specYAML, err := yaml.Marshal(specTree)
+	if err != nil {
+		return nil, fmt.Errorf("Fish OpenAPI: Unable to encode spec: %w", err)
+	}
+	router.GET("/api/openapi.json", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, specJSON)
+	})
+	router.GET("/api/openapi.yaml", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "application/yaml", specYAML)
+	})
+	if clientsPath != "" {
+		router.Group("/api/clients", echomw.StaticWithConfig(echomw.StaticConfig{Root: clientsPath, Browse: true}))
+	}
 	// TODO: web UI router
 
 	caPool := x509.NewCertPool()
@@ -0,0 +1,667 @@
+// Package openapi provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+z9C5PbNpY3jH8VlN73X2Pvqi927MTpp7b+5dhO0jOJ3etux/M863laEAlJSFMEA4Dd",
+	"1qTy3d865wAgSIES1RdfEu9WTdwiievBwbn+zu+jTC0rVYrSmtHR7yMtTKVKI/CPNyWv7UJp+W+Rv9Ba",
+	"afgxFybTsrJSlaOj0dPaLkRpZcbhBybLmdJL92/DltIYWc6Z0kyWl7yQ+Wg8WgieC40dvH379jxqQcBv",
+	"JluIJYd/2VUlRkcjY7Us56M/4P/G7jF+/TTLhDE/C7tQ+frIzhaCXfEVswvBaiM0y1Rd5EwLni3wR14U",
+	"CjrN2WthVK0zMcbf/V/ug6mgb/i0EGy6YpyV9XIqNFMztsS+DTNyWReWl0LVpljtvytH41GlVSW0lbSU",
+	"mRY5zJIXZn2oP6orZhWbC4sDiN5lM6XxN+rpiE20G905x9lP2B7T4rdaGNsePD0es4lVF6KE1+DxnFtc",
+	"DcOkNUxdlYy3NhBHLt7zZVWI0dGo09lo3N2T8UiUeaVkaddn9XYhtIB5ZaosRWbhn7URtPbYnpsVu8fz",
+	"XMPfSrM3r3+63xrDTJrFvvtzP1PLo4eHDx+mRrJQxp5fiNX5TJZzoSudHNXpj08fPv6aRe/ARsKY/EwY",
+	"NMQuxAoGXMmSSTtmYlnZFZMzVirLeFUVMgOC6KwXNX706JvnL/67+vXJj9+d8v88ODtevv3Px39/Jur/",
+	"Fq8vlo9f/lz9/e3/+eGrxWn9/ZvUROiHNXpeVcIPtbV8R2xizAJ2WJZWaJ5ZeSmYWYiiYHahVT0nejdm",
+	"wSqt3q/GbFIpbc9nSl9xncOX4RmDJ8w9keV8zCbv6sPDrzKknJIvBf4p4CM3ikqrS5mLHA5HoLF7hbwQ",
+	"bGKFXsqSF5NAyFdiyvyv9zvLZ8xifT3+GI+AwKUW+ejof+hpRHY9+z5unbh/hVbV9FeRWVjlp7SJtLrd",
+	"xQ7HyJ+tTAtumzkCQ9lnL4CVlCoX7FJZ0RxWfsllwaeykBaJyHMafKjDCS1z/CEramOBPy2UMoJdLWS2",
+	"YKoU1PKVLArGM1vzolixXNEiKn2R4jJvjp/Df/5fLWajo9H/c9Dw9gPHNw+iacPbf4xHU26zxbn7tL0M",
+	"b46fe5LDt/BfUQvsiptoaaJj4n/khSrFaDyia2F0NKprvAS04PmrsliNjqyuRXfTx6P3e4pXci9TuZiL",
+	"ck+8t5rvWT7Hac6VXo6ORrLMxfvReLTiy2J0FM0DaMYN4JwjD3i/N1d7rg8rl2L/TC7FTt1Am7moRJmb",
+	"c1kiEa1fhnlucInoRVFmUpjAkg1zbE4YIIoWt14Ky3NuOeOGTb4/Pv3x/PmLkxcvn794+ex/n9MBxGHg",
+	"P8X58ckEyWfIq88n7J5ZwKluNjOMbxXv5v0xu0Kejd/D/Q3vVspId7eziV8BVU5YIY1FGnTrOlWqELwc",
+	"RQulyh3I8Xm0aNBIwaei8HTZpZ9boJemfdhbvwPJXcUF4EWzTbJkfz999ZLRsNi9jJd/s0xdCq1lLthP",
+	"0HR4u3Wf/T76+4uX/zh+eXr+9IcXL8/OXz79+cXoaGSFsXtw4EfjzgunL569fnE2OhodfvXkq28Fn2az",
+	"bx89nj4Q3zw5nIons28fPXmSP/z6UfYoe/zNdPbVkyePH32d82/5k0ePnzz56tHjx48fzx5+Oz2MWn7z",
+	"+qfR0WhhbXV0cPDgm4f7D77ef7D/4OjJ4ZPHB7D28XmprSz235QV10bkMGt4QV2VQp/DfbAuqe16rCot",
+	"lZZ2lRQhSiRCWBnDMl7xDHiqNCBUWCCXvMuTDLuSdsEWcr4Qmvm2GdeCiUJkVuRsJrWx++yQJCE6EDNe",
+	"F7Z53R0U2kjH1t8YoZlWhRgzVRYrxvOlLPEcAg/mVmmW8ZIZEbXjhmEXvMRr4IrukIl/YR9ukvMlfz8h",
+	"dp+pcibn++2L8cFhIHhZWjEXeoTLptJs6IQe+DnE/Brn49m0v67MgmuR0zNzq7wZBXYhEtL5a17masng",
+	"YWKcY9iZcNkCr8jrQmjaQKlo0eERHBuWa3kJa6zYkl+ItTlXWmWC9BAtKq3yOpPTQsD+z0UJG+eIQNOY",
+	"VCnGuInYdzTAGZeFyNmzY6brEpnqFXLFly/eMmO5FfdhDFpUBV8xaRlc+kWx2mdnMaufC2vgqSwd+z59",
+	"8eL5JDCLztY/+fqbR19//fjhw68Onzx88PjR4eFhdJnK0n79aJQiDuT45zKx8j/WS17uzbQUZV6sWF3K",
+	"32rBJMpKMym0n21rO4IqhPsiS/b05JjJ0ljBcXlAUJDl+tJzuzB0U/HmZ+C5EzaToghL68WsqcpX++wH",
+	"ty2BAr6Xxglaqmx1gLe8VGVn1VBp2Hs0++a3WyJnWqXjQNRWLKvCKazt5X3Jl0FM92+hEgQjXSfOmVbL",
+	"cftlAwTSMB85Y5NwVU2A9ZXKBqF73GJfjRhR5oEHdZZGKrM3rWWRbxW14WqMKKklU7VugPiujm7SiLNH",
+	"KxYLnC1JYU2+cryj4XRbxPinZalsj0D/XW1kKYzZK8SlKPC41ibFIeHgx4qMYy6kz7wbPTtmv6ope/Dw",
+	"q0fABUpZzt+N7o+ZtH8zjDgCiLxzI3PabFnONDdW15mttcCOBXEEHoYLmxoOYMP22mrChViNmVEwQAu8",
+	"TFrG51yWxHAyx8MqLS6lqg275EUtbq4jNGvqtIXOOb4t2Sw6YEcyf38ed9MsVCO8dYcxVOQH4TQp5aE4",
+	"R2RFAgRugd+3nOXCclkEkiECGg2QlC5EQrA5XmO3ETVA97KMaKB1fn9V07tdY7y0qd+N3A2PB2mr/rZs",
+	"7VQz5Lm0i/oDjNqK97bvwoNbAE14Yeea8SUOdcouU1f5dvpK8tAW44yaWT9OYemJcNycHNEO5n+3eDAr",
+	"LZdcr/4hVqM/2t19B2w8ZTqhq9xRSWMsJQ6X8aIlraOoN6uLII0+O2aVrEQB7NqRVylEzsDCKquCxNXc",
+	"MG6ZKjPHS+e8nDsThTQgaO8pvVcqOEdzMIzN0KCyzu4NCnXGS/hXC1WIph209QUrMQhs0M857Laq7WQM",
+	"D12jS1QwchFevznrxfV1XDdTdcqY+TIs7tq8gsgRU/rDpCIxkG/C3BPacVGsd24WXl5slk9pVjabsEza",
+	"DeLlTVhA5RJFqSsubSCXaOetgh5hUQthRbFqOh8zPrOkhtnImiVNa8fYC1SL8OZkS8FLE7oSl0J3Zcyv",
+	"Dpcp/v+81o4j3aYJY0ebxc/uSYo0hlxapcpF2iQIp+0laqrOwheWc8ykZVfwL2E6W3PbBkC3GmGUf2yx",
+	"SGw0Mpx0dP7OWq2fl9sS/g1K/wPF8H7ZO6xCWwyPBO60RE48xZ3rztnbctMgazq1yTUAAjH1Ei6Ntu24",
+	"zSHgY7PGIVu26M0HxnNF/I4HG91Jq731zRvMQCuhE7LC76OnP/306tnTsxfPR0cPnoxHL1+8HR09/COx",
+	"Xm6Kawv0rNZalJaWALpGj2TUt9ek/dGRVizNDhcHbUwzJK41X+HfyvJip1ukM5LucnYoNVbsqK+wR2E5",
+	"hlDWBxFfngfOv0WQsSq6JdaXaAlfg5gBTwxaqJReI+xcr851nVBMgQcyLWytaalDc2smTVXbZiCuw/Q1",
+	"GkaxA834T7p7GtrasnGd5XwtTF3YpGMNDBUpWiMu0Sz1+hqGR3nSVWWSzTrTksg7G3lPaVpskTNVslyv",
+	"QPa/f43z5mS07mkjc+EA7tQwtIFTopY78wlKqxbcqHK0tl2djY1XMwx26yZHLpq1LfhJmtTOMpxH90dn",
+	"64Gl31t76GRvb6+vSyuLNWE7MGI0kZAc3xL0l74d5xLn5WqptNhnb0jjaC7mrJCitAdGaDQk06mvVKHm",
+	"UpiO3Pc/o4fim/yBeDDb+zY7zPceTZ+IvSezR2LvMHvIv+YPZ1/n36KuFuho60XWJZ1oLV68t6LMN3In",
+	"ga+0HYqFnAkrl4lD5MXTtHjtpVd2T7zfZ+9GDxfvRmjW5nnuvZaZu8DWOgNyzEFxGzNyiE0FgxUn90dj",
+	"0szFTJbkVJws+ftznICRqpy0l3r0cJFaLUfgCW8RGc5Cc7j/QqCZ1FilSYnz5hvB4s/jbn9S5dwbApgW",
+	"heBGsGE207C8W07S93VRpGWn6CX2q5KlyOOjXZAuhU2ui0+8HdMwkH81mkqv9EyPL4U2rul1wcrHNWzr",
+	"2FNNEJF2l2w6ax7P2rfZmlF3/Fv25lRkWtgeyRafMVFmelVFHgriIM1OhbNR1dNCZmS9RTeGogu/4Ct0",
+	"A3UjAYCXabFUl2j9ECVZlrXIhLy8DasCTe5ujbkdR3vSViurhdBpg9133IivH+2JEjrJ2Uv+rGBGcLjy",
+	"pup9sMDSLqXUgmH2DE/s/Sqc22yr2EIUVXufrPIm+xWT9jpK3LrpzxFrtDaDKPWDiMunkUzZiajDJyYh",
+	"0LmjoZZLcsM6oiWfFfmgVSkM3uZyXgKDXqPuOzah9Bs60MhB9sfGiLU2xxDcMxrfyujaJo0iF/ocLs+h",
+	"t/UjvK6NLLOEpuKdpe0r9lHyjm1bU/qPSKsD/CjV2jVCFZLBpk4f3+3CqA0u5sZztMGU4bX0zvjAqmzZ",
+	"XAkTgiznmlcLeBW/MeitswtpvFsJRRDjvYKK2Hu32UwtfXyYdGaS4AIsuLEo39Iwp8K05LG+od7CpQEt",
+	"f4A7Y83Bg1M6B6HH2LG3XR09uLkf8PYH9dCFCEYEtCGSLA6UD3E4TnJZI3vxvpJaGDehdRotxdVGYdwI",
+	"S2KH48jhlSApkxeFpkQalAtl8+FR1BTLeJHVJIOC7TIt0/vmie5679+bHOadr9ZgyYtX718DuMKLS1Ha",
+	"rZI6vsqyBS/nghk4jN6Jb7Xgy3VBvRWqsLMvHjk0DvnciN9SV/JvtSiz2Ptm27xMmnaAj7FcWzJIsweN",
+	"GBtCm5AHyRJW2QgXYgdOkRUrxXvHeuJgUK8YdHwNFLAEm7/DsAVsAVMlDRtuyHF6KCbeBTUDhb/FW2EO",
+	"mSqtLGthGgKGFmkBGJ4KWbKlzLQyIlNgoiDfkRb4yk6TvJ56Mx5Zbi52+O4MXnefnavaVnUPxVZazTHR",
+	"Ag+zFpXSkQ4j3ousJssiNLhNpIUtbJGhn/CQY/VhZNbAYURZL2HMECh9/PKH86cnJ69f/fL0pxEZ8Mej",
+	"Fz+9eAY2/XFk3x+Pfnz1E/zn+Qv/42g8ev3i2dOffnrR/h3+evH69avXo391J9Ee0pnb2U44iLdTkDkH",
+	"tkEwo5bwPxqlI25WZYaGLFXSUWhf8ex7pZmT6pgpeWUWyrq4VDzgU5Fxn30TkhrQSzsXls4CL1cY/w0G",
+	"Mko34FWFtEAiOprSWMVpRMbyMuc6b0dP4QkkQYhOm/MDw7duYjlY6VqZEOiHNVarFWq4ZCOmPmeyKLoU",
+	"qgJngLE5jRnHGxkVr8DVeyFLDDEM6wBdhWGkP7SLEPSFIh5NwdQVHJamV3Rn2/135bFtZhlliQDvQRVi",
+	"WlvmQj78NFTpNYur9a30x/A25DagtjsS2zbIXykx5aWyTHBdSB8Mjbwc2S3OP9hycXEx6WUqwlb5CEmu",
+	"c4iwa5Mk+OiBaYEYJW2L0INQ5Bucq1JslkoGGg8w1eoy5U17LSrBrUlPC69Vic5FqXIXVUh/yCyc2/vO",
+	"4p0wi0dW+7E/ROBQgWuNy9Iw28hxgZA6VtWv0xpfRWrX9si4xp3B/EcDQgp0j0coGXinHQtQM1q5MJVh",
+	"PVktRU+e5RIOppVOyYJXV82hljOgIPKFjOG0+sBaSJ5Y8vdyWS8pHH8pS/ojGVFj01x+LegP3qNtxEh0",
+	"mTnSvjfxpDAZs4lc8rmAf5jaAJuCf8ICLYXLAtJiqpSd3GcLfkmEb/iSQlj8weBFEXE2wyTQwlKUdN0v",
+	"xHLsbR1aYGZPYysPh2iCg5sEdZTawqwMzHWTwnQT+twsrh1NNx6BknwnOsKWMDwnAmH3zeEIZLxFxvGM",
+	"985FHFgmEOaOrVhu9yc4BUC32JFwUq9lVqmLhNd1g0b7vPmro2TomDtF5J4ihX6f1KkTwafCXglROikd",
+	"/X2yRGu6dryyVFd0fP9GPj96LnK2Eja2zeWqnhaRlk2qBvpr3RdJNbvJRArqDiY/omqtGw4Cv/kItHzM",
+	"6hJecGnDvoNN1w9ILJtzf6UVS7bHJjiGCXSO53LSPnh9poS0Re+0Nxzeza2MA5toF5vOYiE4ZbLTdtui",
+	"bllPOrb9y9Y57LiGwZredvJFwxkPdtbd4Umuc2lfi0zppIsXfvfLzuFVVqg541NVkyS6rC2FgjiJ3plx",
+	"1ByjYCu4LPbA8ONFBWiPBCdyMeVkFkJD5AQ7ONfCihIGMPEdR3lp0CyCIVzfE9VM2AumWfroE64DCemq",
+	"bgyb2scXa5eAd3r6I/oYeJm3yPLk1ekZO+CVPLh8cIBW/4MUgQIAwvmyB0QCkRmaToVGgowwE0QOZ3HK",
+	"jcwoxx0So2ojdAS90PqZuC6kEp7B0zEm759nQlt6sZmTu9odAoOsmhz6cCNDmCc27cZn3pWpKZJFJBWK",
+	"hg9yysUK5z8WxjAYFvjvTGkyAvlI2rAoRFxGlEZimIVrzMm23JgroOIFJ24dkmKRDF3GjPEoA1rkPLMi",
+	"dyADbXHi91HkgMZhjI4ejkc0ttHRgz+GJK7eRV74ICeSS0MUebx2d+I1IouMzHvy+XWLm2eOBOirpGJA",
+	"7W1HpWg3FYEBxMcWzo8Wv2JgTzvoAk5o8gbxByBpUEdzVtxBBpNLB9bGNue1sDSEvWE/np2d+OwwWPIO",
+	"10nGA2MKb/pifbtQbMlz0T4v7fTbLtaEGd0s5Xa76BtFDDdjb3PCeNkDj27TQkxpkYHdM5vkndrm/nd1",
+	"p8aINjtBFlnFRIlZQg6zBrUj5QIzIS7T5Zs4HkeGH6d7swnFCxwdHBAzczmaGv8S/kduF/TD//w/9MuF",
+	"WNEP/5owLWZCizJrICLIOI0NB6gVTS70lbvJjSouG+PSS0qTLVYd6J2Y1GgSgTcDW79wzTWRLU79A2Y9",
+	"xQCoqP0lx8sQPypzF79K37iUeTcAus6UDinyKbkhmSIHV2Cl5SW3woPx1EbEScdh/FaBzCNhL7sTTV+I",
+	"/sseK/n121U6ZXl/dkKwOtGG4OWKkgsGDu2/K3tZS5qznDkUGni660A7HCJ0Ei2MTz/DGaWO8nc8u6gT",
+	"LPmZKo00Fv3RwQw9ayjHk1MKIYv3KQoY7OBa8a06jACrQK/yaSgwpsBYCc0JyBg+1wL975uUr/RKfy+L",
+	"tgJE3aznm9Pvew8PHz56cPjg67MHhw8eHx7uHx4e/p/9fJq8juS/U9qY/HenLzCDo19qurLtUJM+BIDO",
+	"Frvtbd0C2Hlqa58RINEzh7YBA0zE5KQCgOF3ZgplDaaAiJxx8KOZsP1maHg1NhVGkAiRRUSQBPm5Tyju",
+	"Dg0Ajm+5IOCdRgEv9w+iu8auZbc6qZV9Hpzk/YtLVrVkFCbd8Ot2Rfi5jVjSeOOTbAW0PxtHesbJA7B9",
+	"Gxa26zWk8Y5ZKeYcV7jtQxGIG7g7xfoYQrccYcx+gMnlxXefRfbIPovVNOSWNuhM0aDpXne37djFTBhr",
+	"mqservH9nyF9YMIqbq3QZZMbTr4ikTvtKXXl5dJcnGcIjrU+RGkuIp8SvBSFW6zbVKPNw2a1qM3WZi9E",
+	"Zd3dHWOtoHnLr0l/F/BzYnFPw8zhLYZv0fJ5W7Q0bo33TCUyOZMZuwfMzayMFUumNLtURQ1X2qoSrWSM",
+	"rUHz0KXZGHSDbzRLwK1FU0ZymqWwgLK2cZLuHbfNOw0WTenrbf/g3AAdU3KLOIPp3ow77tGXhB8VpSpE",
+	"xEJOglZeQiqyPpWU2YxalLzMEsLaz5Cc7Z4iVUH80FSpC7ZUOewyiH5kebL8QjhBhWc+B7G7/t2oetza",
+	"Fu2N4yPUIvxo75oR+xXfzDbSHLk3Kr5ho+uymdusJS/53DEXVds9NUOUr+D7Ntsn7+5u11n/BL5XWmTc",
+	"2A28e+ZeCThwNMhKqWI9mYB+Pfp92FVJYzhRqgjj2HZhUgf9EzouZ+p7ULQ2cXFVijaUUFCn/XEpZ2o3",
+	"p0YHMCJf93Fgh8PN6/8Qq9aXTJad0TWSJOg3vMyE17ETROdXMEV2rn3DeHHFV2RPb13USWa33bZD7SKj",
+	"G41DOE1Q05tIKOdPGYfm/7VN/XD07eYbnmwP1Wuo5DRgBvdnpDU2zmjp41MAvDRkvK6DA+8PFRq7xJvg",
+	"/onDkvTJiMRBRXhaE5ywwljprBchnjjg5GHYw0wLweqK7mH3sUZ049KiByALuj0C4zUpqOSWcSuidC50",
+	"ezETco2DPi1EypYOXQenIw7Lj3RITB366LQQ59skVHzOwkiYlvOFJQdhmRV17pN1aS1IU1SFkwui7NMB",
+	"MGvt07rZb+dejXqkAVC/ui6HM5Q4Ah9bkmVbfAVnTbI58d6e+0VPqtoviJ5ETlrzVcvvmcVL3KGuUlnm",
+	"nJ0WUUQxfAueuv0ogKOuXODUwgmD4n1FOZ5W0ct1tTk+pxIlbOC5S8tLRVkUfms9cGCOJgH3BQzAZLqe",
+	"TlswP5vprvmgh6zdBmTA8urKG/sjZ4nfflqohjjJe41u65xEmUEDApm9byw+czG+DRHiRgvMcM29hWQ5",
+	"rLc0v+5eVM3Jd6OLV21931pnuZ+7nwmQda1OiGUijUjvs0DdWTCimLFsIbILF9eThA4XvLCL1UZJLmoI",
+	"rGR9akNaVkwvoe82Nf0f8dkz6G+T+LNwDkpy0sLbpuf+IqFAXop99jRQZRNoDYtTa4GUzJl2zcYfL7m+",
+	"EDnjhtWlG3m4hTDzx6VbI4hdSuf1XfQEZtXZIjEKgPQ07bGE7sfs0OH0fDXaFpLVH7CXzKR6sIREKh92",
+	"Yhd+cT1IEHX7bvTVoXk32h9uCz5x8bzdXEJsvZnPw4d4RgnYHZ+1YJwebputFg78KUE6LSiLZitbGaj0",
+	"NSaWtOC+AzAeXzpT0zgNOhnb6X+tjSXCWHm2hPTSAx5No00IRPjvOELaSS0TemviF7FU5d6/hYb3MH43",
+	"92hOjrrakTJoeMhswaTZczbCX0V5IUuzx+eitL1BLecUtzmQoB4v25l5zXLTdjj3U8C2otRgUNm9870R",
+	"0HCebUoMm8IN86Te46TYjK+1Nm4D41aEq5Do+N3oQe8RGOIyFtnFEZvYrJp430hwfVWJszJ2ZwJkCZo1",
+	"OFGmvAT+E2xk+CXGT3jS0LVfJPwByNMjdIadaOlJ7J5sid7OqGeYtDcoWwBNjAKJR1yp2ZYOdY0bthkd",
+	"6+SNIY1VetWTsIQ/dyI8FvSFs4QS9ybDoCzj96SFy2/MMKIj8gHKkswr3vVvyK+I+TrtMKMgRk5cn3Gc",
+	"0Y2DiuKZe8C8O4H/3z23r4nVwlVJnRKR3rC3C27ZAoO4YgiIEKfR0F+0Eefkvs43xHHIfEhkck84yK5p",
+	"k363cY7nERLN4KASerFZQaIWSkG0bdOJM3/eyYgHYLL1hkb4nd9mz+iS8d2ERxxDNPn6mv9CoVUiZxhu",
+	"Hhg0t7xQ83HDI0KIQjA7ryWAumhpjOv6f3/H9o6gzsEfE3Yvyk8AbpOLSgsSVF1w130MaY0/O/rlxevT",
+	"41cv/2hCz0kYq5dxF+f+R9fXPntVtm7XhWgGTzNB4OQwj3UM9GhO3s1lFJvWy8oLMrRYbujQKCbPhnSj",
+	"OPQel8mwGpHopb0mr8Pt80zOTTgV2EdP/ERomFbBSAmxwnHziVlwKFlEoSgLXzZkcgNcjWZLU9Jnd7v9",
+	"tVOb+JogkoqjYRaC4dRhs2KLYafuyAYODQz1b0FTxxVJzbKhAnMD7C4aa9MWwgkGwyNpzjgPXymJX5mD",
+	"2uxdCWP3Hk7wELjXO29N7jdnsbVoWtBaxr36UAjfZTegEnoV9V4mSqt5sfdgdDTiS7l3OBN5NuXfPvnm",
+	"68ePvnr4ANJfWgP07wFk8uOvv3nyLZ9muZjBe5fL9662iDk6OODayhnP7B6wNj4XB/y3mmtZLw9wAw4u",
+	"l+8PljxT5sGjw2/23gMje/D1g8RPe44J7Fuu99//e7SG6DCMGRqhJS/kv4U++tU42OjtNjZPLPGd2x3g",
+	"LcFbEyuDX3FsA7NkosDYJFtvTWTsTpL7yDRgXSGCE2W6EHafsBHdcGI7pue4A+9n2T6kER9sMZ/UFYsT",
+	"/w6RvBIlAmTjRWqtUHCOR5HwAd6VTuYJzy6Evk8CMECFWeZJn8kQcz+XxooIhszdrnCLNQNv9x3dvE1I",
+	"S+CTZqkuXBWUVtZGA0GrNNcrlqkKXVAT/OAcQ1QmrJJlKQKsGyj7EVOOcJHJXuS69zefu85Q55poVRTU",
+	"qplEiWTtDsR7GKG0XfLzdQ/jAUSZgOEOaLdGi6xKcZOLFPd8B5Xhj/E2s+MUqSiYGjfDHMN1g8ciGTz+",
+	"uqGXY8eAdkNRBuA2lTBZn3FZhAAzHK/Ln2AOPGBAw9uZ5rGXOTxy3k7c04fV9/TTxNUHzd53s3XobWyj",
+	"ra9HtJ2esGnFXnXptEPsVjFocI2Kh0ePXP+iiw7/5q3DieBQ6018xkeye8YUi2nkdONXfNXaebffu8Tm",
+	"vyJAz+/eHP8E2A1jdvbi9Az/8f3T459eYEbDs1c/n/z0glLQtm7oMPxuOhj+Xc+x8QqgjNxzfGPSeNs6",
+	"9H2DpNdLriXo8TeRQM/8yENjzm/hSc+feVChTv7x+vyXp69d/cC4zKcoL6GFDgg2bmODBwnl425RIBsq",
+	"zaxxmC0xhdvEjHVYdf/vrvwRgas3e9U+YG3GESVINBzfXybEpjfLK3dsELjD5kuwZyi9gvjDdS/eUuUp",
+	"bvQz/BzUcEm5+RelurqmlxybyMWlzNJBNQ5YifeAstIIDPvx+fO0z2JrIDe1ULIf5Hc38brScrkOW+NO",
+	"ko9f+x9O3lxv6X84eTNmJ8+O3dpBDVFnF4cBoQ5a9m7LpSjzlKz0C/4edbHVfO5a8vPfONdfpIZqufLf",
+	"If+nPW0oH3PFdU/cHhZ/DsZ+/+4eN4ZAES5brbN7v5ztvR+zpz8/3/sFLiK4HQX78Zfv7yfpRKtUZMxr",
+	"VYhWkoS7bDp9uRjZPfYOax6/G0GH70bzWsAfySsVv0hsQLLhpvsmhoCSd9xfcebalGsqW1ts3byw4GFA",
+	"biFS2/hTWjo5W0gf6xdHvPrayZhmNO7C+JRC5Gbfi2Uh7heteqSE5XKG6oUNwBjewtg4Mg0ziEtmJS/2",
+	"mb+C2hUR81pHadjYSIQeImNYlv13JQ6I7QVzIe38EihPgsZmeWkDzhI8omVrQJykjfziIZS0Vd7S573s",
+	"sybpwLRhYmL7koNGm8l57RGjjMJyplOHjSZyV2+Ol+Ffhrz2BKdyRQselsjgGFmljMEBWeVwkNxi4KZA",
+	"Y5iXgz+qIidEWFmyjBvh22yJojBcUV5Krcol7NyDwxVpoRDhRRHtTnZzLWYUcHspjTfJ+nLybisiC4iT",
+	"B8B2TI5ayopz6OF7zS++uP2FEIQM7J1pauZHR0HaOKS1lYBgCidiNk51iogXOS1Oo4yXLviOChxx14Tf",
+	"bSQHoC29z54WRvmldZsXwsVFTp746YqoTjcltRzx77NTtRS0mxiRVAegfHMlXYklXipLlXUpFzHsVav8",
+	"YMD1bUc7KR3XZ4Kjc03tHfctIAhkwphzZRPpaKchLd2blJEvmaag76uzE6IQbjzos8J6AplVuOTePNF4",
+	"7LE/FhWYxz1GoxnC3cuyKZC34GVuFvxCHLWL8DMM63eefiSBC7GaKq7zvbiMf6XV0iVqdKvnYyQ9bBRK",
+	"8MpWE/ZbLfQKOAdfCusCLILUPuOFEalLabBdP/CRQbsT8R0CMbXAmxMhMRwZsDuJHEIKgCUdOfL31xwq",
+	"JaNg0XY+pI6VjC4lPRd5c2pC+IQ0ITm1KRzbYo/R59MVlUE/Qgqme88xTOIxnuk6hATnTKJTQDOCMzBu",
+	"nGFN27kQlQfloMPUrK2PU+5pD9aHuzp1yIij7JbORAj/A3hZqAYmTXsI2ATMD8Nl4ivPdZ+TQdOxP1q9",
+	"1h0Sso9bvZc5sNjM+pF764eDRA4zIm4bMYrZzCVde/9YlMuCSvUe5I4efbU5jeWWzCX95dW+40ZmTeCT",
+	"Vahad7OsN5V9f/vq9T9OT54+ewFOsaN37wYVXfcdnpueeHd4kdFDdu9VJUrABvmK5ZIXIrP3UwA/vs3I",
+	"zozmdKqK033bHxuYKkIagSURaCkdUKOUdQE3IXDO+fOxFrpBlMRQ+RpJrV06P6JY7LRKTUGVxA50IC+3",
+	"BHmUJYIwN9yufYwyIu4g3Wf7XRdZ+2pqVdD/feRS/0ZHo//rPV5bxeG4iZQEvJkMhpGzkzyP/vb7H3/b",
+	"4OM6ReGA+RzyUNAg2AATtruH+w9vydVFSPzB1dVfoe95T3X+VJ2nlnsiHv71auiHpthUYF1nZpUr/w+P",
+	"/GtLAXG0xtX/F9FnUTTl2pDx2pfWH41kPf5IeLKKcI6Dz6PZGiv4ck+qm0KHBEI1AHiuVTJe9sX7SpSm",
+	"EanIAZaL3OEhUey90qDYN1kP99uiV3wEnViLHTrwVI8aURuhaZkm7rmegP1cBESiEN8xZlZSpEXJJEK0",
+	"rVCP6EL+RljV20WjXkvkcSSTr2s1My2Eu10Nv+yIxrRqOIqw5RU3ltQBs9+HMH3DAzYAFqZr5QwZY7Ho",
+	"11dOMpLCU2TUnLX1e6wRDnstAkAvPfH5XqEg7LhZODcgklckGxUrltXGqiUDUwuye44N7lqmL2AAu4DH",
+	"McsVaI7YWn9BvsZ7NBz8IJU3Vuk6lTD+GqMsI6YTvE2NVAy3tS9+JWyvccoMW+N4NSMsGujExxA40EPq",
+	"bNC0obWtSaMb0BUaQumrPnjW7FzHZ4cbOKZSnDBYJgMmWeMNcVFSXjEJkVJJIJPWbm9PXtdyZvuSOsV7",
+	"SRdbZ19BEkfbFVqxIkT7zqJv7V2W50nIAOg89lwi/UVEhbHC3ijlYQSc5tqp5TJ8MC5meLf1C7S7jcBi",
+	"+gCHXOnodZfuOiTZBMo0rTU72kworHMv8X5Xl3mRlNPmpQCdKg6Jd5RrFQkM8NuynbFCxlHjAPrkvOS2",
+	"xsxP2NO44Dl2y8T7DPaz/TLFfieMNAOtBt6jtMWV54bg3o6u5gfJZB5wWfWxKhdTg26t3nDVJhFV6hBN",
+	"OhgXBttOkeGtMXqsGneeBMai8mjsRf7w8eMH30YF5lqAnXRIDRFOs8JpFCK32Vv7Cm+2t60ftW+zUw4H",
+	"SkS9ZZCdExfIpA1o5LXu4HN1dNJaz3i+vQcxBoPowRBvV76KjmQEMBKS8EIK8rrE0baqDcswX4cR6qPF",
+	"wbW0e0EuerKtz1rh0WEFYMJFcKYExkR+pcZoxsFdUBTe5krYFmTgar7JClXn7LdaWU6WI7RJExbf+3aH",
+	"VVGvPaFGkXMNyOTeUNsyJYG0K/Y1IjOtVlti7qcytay4lQhWtOqD06KDnLecOI6+XDZvG/1lI3klgKM6",
+	"olCrp7Bz1CX2l7jLo2XsEuFC1bpYnWegE25KNqf3GLy3lh6JHhsQzNyDSTTECeRMylnLd4mWRKcwV1qi",
+	"g+RdGY97ABr4HZ2mWyKztkLWWuReamu4xqaEYnz1b4YhekhjFmO82Q9XFsVZgWNkPJ8k4WqWRgml6zUj",
+	"Uo4fvgbduRlLuvX2H+MIO21DJAi+E6WR9ieEU0bJ1uyzKFN7Ux2N5x3cK/diNBIcX+TaHFLsAp1J54Ah",
+	"XalCZqttoz2B909PfzyhtylXeJUVA+r24mvxd46bD634u16WIQJ38680CziAkDcUIS8SzIzbyCQcuhzH",
+	"fh2kXE+haJAmM08rCoDtBT9G5DKxKkrRjZuMqhJExrwFj4A14nH6WjXoHW25QBpwQMyyaNEapSgcja5k",
+	"SWXMHx4++Hbv0uB/MjmKhebWOzsma7S+bf/VTdEYJwdzk/5cE8kfh/UOS3HzAexlsu/3tTyV7knJqhoC",
+	"JiO8Or8uv48cch/FrVJM2YPDxnAt3s84RYY5hLPR0ahE0Vfz5ejo4eEff4wjGuFXJk0jYZZb5rCG8nE0",
+	"yr7m+4/eF1xjOLoRWQ3kfz7Xqq5GRyOfOQ+DE3rvSpYOYxrNfeHarMyD0fZ1eX+ZT+G/YSHaMwemfoQ2",
+	"073LKvOL8NXDP/741y4go9G1mLj28ZVjDMrZ3ahEwTwf0apER/78Jt9ey0Cy2aIEcUaFzJIWLR+HAyaK",
+	"Ky2tFeWtG5iitneb1DXX4tKn1u6wHEQ6BLfic2MonoASAdeI6AHbo3+xtUdfTXZYnkG2rWZG7dUM+75G",
+	"egl66r3fAfkscbO/MXxOpWKksTLrqC2NbNwFJJuf+4K0620+vRTA9wnrioogtuA4chH9HbDPXGWhDgE2",
+	"3rgHi68OlynhzYNroGGMUoTWZdVW7dO2vxDPyFxZGBqWm+zWDI40MQcbca6TmQ4nUXRhckKus5DI1PhR",
+	"9w8fRxr1rFDcphSo6ylMW3JSUp+64NRtK+mqZBd9/GN9Ba2yvNjWbGvNBKKvEck3cX1dAt1ofNhoJe2s",
+	"wbVUR79cfn6BEjskM24fnd7TeneZAz+pvhIOsfeVjjvU8JTQ2dTrUQjH7fHH/Rl2cY1TYS197SrmSXQV",
+	"u4irqE0tcql9HACISjIuzSC49vWyeZYpnUflIqD70O3+9e3nNwMd8QPYdOQ2SkjueziIg0Dlt2FctBrc",
+	"eN57h34tWkLj2Lr9CQQz3KkQZUwuXqQSVwYGo9lKU5NVMmTZ53V08OEdoBuhYRdaoekkCjNRCCre66EB",
+	"PY3tvyufJerHBhoiBwz20Ckji5HQ8M41Y2hhTXwILRXSSQV7WKGBvtwbtEawbMHihtemqq2RedKWkkW2",
+	"9F3A9ncOSx3SQVvazzWXZVoq88ZEfAXO9V6nRnaL8XMt4tKsTJVsgl+eO1inSX9iYoxfgZ8MKpkXoy8t",
+	"0eHDQwxUMvO4fwHjcaYdHhD51EInW7v5sLSJixFwoTmN7ORKjG4Z1JqEBKHTKmnPe1Uys6gtFQNuo1zG",
+	"tv8mqUOLTvIjhd2+VHnzTZDzIui6uLbwpjm/KxOTW99f30VvtSgRu0uku7fScE4RAWymYFmuE4uP+7X8",
+	"wu1OF+vPDJpQeh5vMA6pZRm+ATYTRTVRiGA9TbpEzzyzjJyhDdiNKl1QPyEhUGEnz7WDM7BVVmW6srcw",
+	"XE+jG/YHjX3+vQbXYY25eELLB22LjVFTt7HEBmJ1LUE5kYMVAMZRG+eY7T4Pgbf+0oOUQNOiZIIe3YTs",
+	"2ysER1EoMTmxqSy5Xm3P+L4e2EirhEr74Db3Zcv153c74lztY+ovnC7PTTD+lOzUuiD74a+jJRrv4pO9",
+	"WshC9HHPQi6ljfPuGtdsSrat6vMe7enpUtVluyTU5bOTN2Yo8DE0nQY/blrGBllc1qS9BK/Xiy9s7tR9",
+	"mOiTVEnfcJvZ7YAWv9WPn6y2FGfAdG2P21y3Ny6+NFy1j2E81p5qvtyBVF4//XmnJGpqfxu9NK3eJtFs",
+	"YqVv16H6uWEZL7K62KnWcVTKMQYsaDG05jRGxyde+WiVGlrfWMiqI0qnqsC5HCGRLUqZ9SqoZSpoAV0B",
+	"kYU1Xoqsqvex5IPldmMVoPijJa/+h0juX/8BL+yjLdE3sVBmTcWA35p+8OYHBghx+i5cHI3cLuV8yDUb",
+	"8tMpk2fpPmsNUyz3XWr2z/jCps5LYU3vKpUCRm+FnvFMpJeqa+it6tE4FNrBxns3vl9rqrSao5oYH9pY",
+	"+vQXYMdIG8ud26rYg2IOUlOjiqkydJaM8ujjTGu0I3heyFJsOK9aLJ0+2MnkIJ9tpAOOKanOhUF7NXK0",
+	"TREbMP1EZ7AEYfS9mmWfB6JHUwDxeMHR2BH334RiGT5Dc5mxqmIyHVTer8Sd3bbihgObhB2asBzhkg3F",
+	"m4Ug9UpidpsrNLE+4psoVskGd7otw/B3MjxvPhHrBufbMTHsfEWlheF2Tf6GipulGLc5REPOEX31cavj",
+	"mEl3TBsOmKLFrYDzp+Rmh7tgVXNNt29wSjihKrcmUj/hpVkhBOJALNRSzUUpVN0nN2cqWSKhvfnVYmXw",
+	"Vn128obRFzvIz7OCzxNdfC9IEY4KGDpJ6NnJm5ixbmWk0MlGdJnYNPAshf9CbdgF4rxvW41CzcNiuE/G",
+	"jYW0LqX1HCBiOJOsqie7rNowNJue2fQUJzzx+4jPr6M/tOGVEnsxr1Kgaj+cvLlZd4Dak+jtrsX5QUJ1",
+	"kMuQY+XC+mrz/baHNcygQYvQgRpaK6zclcI9elNETuPo0LeJPj6rbWndS2m4tWuD72OErVI6Kdd6lwvG",
+	"IXLhAQGBkBPs7dPXL49f/uAdJWi9gtB415BDRpAGoznwp0mwTE0CkOpCC7NQRZ7ih4XKLs7NhbhKlTOd",
+	"eSzvOOmFdF74jgpAhA73qbGQDzJxAtpVI9pF2OAudW2tFtWakZoUqiSkOlRjQp6Ez9HBKHQGlDQa4kDH",
+	"kpY9Tbc2DF5svM24AOS1hBOwc699pg4Kc/V98pbhYzdjR8vgmGIgm9YTWMb11rPBl/TlC1/9YzQeOSJO",
+	"1CkcyG0ChZEOr4piK7u54hrkGtNTe5KF2pMgvka2nfaBu35UT4sxhcYaUo63oUWI4/hIxraCMKM+7nN3",
+	"YQKvPNr+idBLadI25OaZX0sE/yFpjvD5ASsjIPevq6gAWNGnQGFbZGgLzXWKAJSrtZoakYrQ9JtW/+Kh",
+	"sePncTzOKFIEfhDJCiO9Lojt4/bFmnI3esPcj/gp+aa3l7CNl9UvJA0rRS4ngROn8JwsKTjRJjp0GlWG",
+	"KA34UeYemxtS8HMAjrNoo9Us52aBUEus4CtV2/vBf/FWTNmbY7w5nv10zAz1ZthMwaibLqOy9kueLWQp",
+	"UuJ80lcVFYOtwkTHfhdcffFCWMrczOVcOgyxyf5kzCbn8D978D8Hk62uj2udpzbTG+xGrs1gUOVrDQul",
+	"iYT0DT+vLyjcDoiGs55R+vuISGF0FAG+sv9i70YIO/FuNACLp8tPTYMbSzmGLQ5LQ0+Teg/+COJKVzwL",
+	"U7OCLwOJE0CIc7ZRmoJrKeSza8EA8A4uEUIrIQJ3QCXjhDebu+JOvqWQTd4AmcSP0flDfiETiwTdvEjP",
+	"V1zPNNoJWgK4LAHPwz3xR4DAUqC7XGA8gU1Mr8wDzop/1LZRobRJLU8osZCax8WoTYSW4IaEwiBNygmu",
+	"S8FLVpfO9bU9hmt4xMWNYrpOAo5GIibg/flmW+rP/H1U9HHDtiV7iixI0JWz06/3wIOqB+LvzTvCyP7N",
+	"HYFceO+H7+7ftDeixcGpuK6tn0Uyb/DmNUkG9E2xedfk2wNC+lqsLN6CZrUSlNdQSLOFGzigW8D1TaYe",
+	"FrJqXfkOyaTZyfa59DC7XtCncXoDJLKdtLwf32Gbowhilt8LZhvv0JAomWY+twbt1LlFO+l/ScedVoUP",
+	"/4biimDgxBRl6ZD6miQ2LyHWJEdiZE1jqg8Jo5eSh1KNmK+4z75r0HER0yoUhHbiYYrb5qJcnVdKW0gk",
+	"uuI6VV9KGhwYmW7vkRq8Z7NKVvcdbiP6F+7hT3uunftUMNL9hfEs7UrhdSnfM6OyC2FN9Bq7Z6wWfIm9",
+	"3e9xJ+CozcxW/aM9/f7shJl66nCYCT4RTfmkrsDXe06uxerVlQfv9zmK6BTxvzlvQBuhGPZE89LMnCVm",
+	"JhG6xyh4zMxCFMW5FxS0ABLDwo/7PbNqPkjcYCgrY2MxlCl+wowgLWyP5TR7g3Nl9/BKz6r742YlzHh9",
+	"Y7AmKy7RPx88iJ4kx9lNN10joHh7WpNKneb/BuEglXGKIlDMnlq3zjY4RZJ1fHww5LOj/OHqqzbyB2s7",
+	"zOjqoxMJslvZFdgiTNFuv3F5H7IcKR0nrzZfzqhce/T6SlisrOcH3IDhrdec9rFrdYkOUaPWRgIBlibw",
+	"ERK/XJR1E4UJeQm4+RF2c2O3o4/A9gKcAzVBai3nslghsgCNA89BXaVYyy0JTncnKfW2HOX+p0pa5XOB",
+	"Zft902IT4oLr93Vj9vW0GwekryHvdSmTrg6HvBAKBDQAYUQO+21kjn4kBj/Lnk1Bo7jb5Q5yhIkGGQEH",
+	"G8sePqIv7ljiTDY/sAbMBlnkGjf/BumlJd8NkuPiPVmnwl7W+SZt1n1GiVnOqhuzUTzZW4NF1lfYnbb1",
+	"BxsPy4tdjsYw0u0h2x+vTazbrdy/+Utqk+ZAN9kfLmU6tVI9WIAtvwMmfdMmmSbLDoEB8XM217yM5EHY",
+	"012wACOi2WbQ9pTSIV0iWyJZv4QxDfh5pkj2NbcCr/YE3iK3LiI2BGOcHPv0MLh9Ghuqr6nQ4aETza04",
+	"xyYmZKtwcRWma55umWSBmeZCN75tXRciiujFBhPX27TWxm7AsgqdB5lxyXPBuEVQ+yQXS6eVntYG1au8",
+	"abIS2oXfDzszGwJgto03RtlKjLguRL953e3euGVnx1LETk+ZhENJSX+zztb02eQh+k4kDfMdGsbxuYUd",
+	"uy2LlyNJpC1Qlg2wPgt11eI2zcoh5kRDneJ925K2z46jShB+CTyGHt70CPONGdS5WsoSEd3hJch8KxSP",
+	"lRl4a8ymNdUvYT6pjmUgr9cVs9xcuPg27ZWvuNaHVc3rDkJ3j7SfK4nxOX8zjDMPS8E0xHomzoPMC9Gf",
+	"8QQ3MPM+XnZPvN9n70YPDpfvRvdTa4hJUc4gUSnVLFJ3LQMEOpQzOHw3ChK+lQWT1hXBNwzv4DkyEmQW",
+	"PrGgpYt1jH5zMXAeXy9gGka68ibNZKLbh2O9T9pcad1+CJYLY7VaiTyWzQk9SJbz3tm17KfJ0TvYk+Rx",
+	"b+rLbCJf0Jo6/Y3Go6Us5RIsQEns716gplf0IOxjTJxDIJlwSOeDm8fXd2wc3x4EcwUHKkKYUg2dRnqa",
+	"l+nn8pKKLzQpzu87FR+RTjMhL7s7EgIQOolkrTIJuShwWHhirOqe7312TKPHYYPaZ9geNhh1Q8TYKJuO",
+	"GD39ERm0hiaxxmyAhg8Dmq6cB5PZhVb1fBEew5XQQ7DXXnqrGkpaiLW118LWumyWH7Zo7KHtWuvRWfbO",
+	"4WyvQxk6TU6ncwU1h7FhK+M2t3QL0ByfFkl2iT99Z/Vhg7psmgAcF1swWqWPovz8pngUXQeuElecjFa6",
+	"PCfLtXUlkgJ6HW97WyI3ix9F2+qAls4QA5uqnrFuHQ3zials8rMjs0kqATzO9kb7Siw8UldXCbtOuGiv",
+	"mRHut6adFX4+40uZMvSRo16LPGSH05tI6mQNXrMF35vI6vLRBCUrWV1+PbmfOmSRHH9+G5EjK74sRkdr",
+	"zQL93xRzcOc09XOqtpBUu4h1nAMpbgcQTLsHm5XG4AcP4MWgM1h1xOFhuZwLY129cldyiJLYSWIMAqFj",
+	"Zng4aBGQOC95IfMuVkGw8FmlQCRw4PgeWwEvMiL+pn77ZK3gijtACIp6/m9V4lVo9q6EsXsPeSigKnPY",
+	"0KXcOxQP+TfiQf519lgc5t9MH2yBLburEisLZSwgDJ/PZDkXutKyTAiXpz8+ffj4axa9s4Z3Cm4SaAzT",
+	"mO9BcS9jFnsXYjUXJdsrJswsILtW2vuwqjxEpmSFBFrFpXbyqG9mn72mS7uDLVGyKc8uQJEvcyf2xRU1",
+	"jHKVrxDDEG2spQoCANqCXeVA9NwjsJO/sojONCyg0EEngCmpMrI1g/LQIQG3RkePvnn+4r+rX5/8+N0p",
+	"/8+Ds+Pl2/98/Pdnov5v8fpi+fjlz9Xf3/6fH75anNbfJ0OuBxp7kpCzldBoc+mcArBpAl0r3awhXDsy",
+	"w0eCWWD8ESRt1wjaY/PsCZeJDElX58BiEybJp8+YDy9DJxAd8xlzOHmBM7tLA6QFRzHRNdoq/IOz8leV",
+	"byY4clSfGtIc6V5XJ1yInp+Et5vMHy/ReCS42Hc1kwjO6eoc9oyg6lmkY6yZJviFB4EK0zo+CQvUGcaY",
+	"1abGyiFzDgsLJ4ebxo0zcVsy6RnMDvBXw+6upsHNtcmeqeUULTCxYACHtFNZ6x6yCGkISek+FaWEC6ON",
+	"Ukpk0Egr/kL367TGvtt1zl4+/fnF6GhkgXm7XM/2C6cvnr1+cTY6Gh1+9eSrbwWfZrNvHz2ePhDfPDmc",
+	"iiezbx89eZI//PpR9ih7/M109tWTJ48ffZ3zb/mTR4+fPPnq0ePHjx/PHn47PRyNN5RYO/hFFfVSmANX",
+	"w+rhaNyqw0WQoEcHBw++ebgP1cz3Hxw9OXzy+GBQXbbhCV7Dtjq094dX+PKhQPR0XhLqhWPfHU8hae/C",
+	"KRuHEZ9v9CsSY6VpmzLOmq8jPZLwG7WYS5S8jci0oAvoXRr5jN7YVOhgT5SwWDn751q9A99Pc6u97ora",
+	"nm5RESszvfIFLrBfw4yzKjVVuGTnKqALL3QkLXpA+3XDVLKNKIWWWbyAZ/CmE9EmpuSVWShLUrEWcCdO",
+	"7scXT/d+CkVdTQS9Qbe/y/f3Br/mLBP3Nt2yir7z0XhkalOJkiBGTE2gb/0R5j2X1k0jn+qSwLdXyeDf",
+	"jiLkXMBrDm9qgtyXDsOQFqkxx0fOXCp4WzLOtLpq0MuFbXqgljAV1VD144GQP4O8fTsilHTVmHGcYxmD",
+	"/60pHa1rurkxGwGjVd0rKdGuqYWtQxz4VVuXibe164KhQ7PJUvAUS4sl7AWlq8WrNBOl1auOmSCINXD9",
+	"FUpdsLqKSn7fayAhHSji/Sblaf9d+cbJHqKkKB2IXuLG7DneckMdmya1DX/tKT1g92AvjiqlSehfiKIi",
+	"W0Rb1SYPCMZYGbPo4VK3qNb7nsKbw9T7gTrzTTWqMLqgTTnVqDHYw1PinIhZVJ7Dq+ZuFJLkJQdUBSNr",
+	"R2tFBnYguSulkRKJ2qOS8D077L/pAWmIWqyNY6OFms+p4H/AsTeLCF0wwf6Jjm9T8Gm1+YcLgkhjjJ25",
+	"sENfu/S6E9nOeluDiqGfwuCiBffx/8NY53amd3dpU00/Vl4mEaX8Ew+K2RVCMBRvPTwDvurZMPwiJOQ2",
+	"oaPc93QPdg/pHx+YsefM7Fc1Nfv7+/eT0YjJ8CqP7dxkYLp0wcj7hfEVNL1W0bGH+wMwjjuk4yZOo9m0",
+	"s3e/p6laP86LS/L8Q1bVulJGmDGsdaLyjwqmwBCeIPFKCPFPUTWnEnfsezhoBAfi3brhgsWedRNYZpTj",
+	"eDwna7eppxYMDK0b+GeVg6WTEBVCCB9MoOKaF4UoSBCk7Z2uost9LRuEwD9crtisFe/Y1ZRrE72CZhW3",
+	"IFR635tIEMhidQDJzVMQLqwqhOYl6sRPXdZLjB7RRiQ4IgQCrBwCAm0QRwm97J4HNQiABhQ7p4W5P4b4",
+	"keUkpOQQeMAE6iRMcJYOzOreg8OHj/7vV2y6ssLc32dvqZQUvD32JhDaPTcXtuwueAMFIQ3LRVZwvWYb",
+	"bgeIuzGtieRYVP5XOvhN6CspnAH233ArzUx2u9hPY2Vsx4C7F0NDoIfyxzMnz+VkDEMA6Ido8IPFvb/Z",
+	"bdyPDuGpIMH4kCzIAYiEA2PxrwNrw2vLeQnhUp4LtOThcMmoJ8pLkw4u97ASjR/rpLVKg+rydKAjGo61",
+	"OU7iecAf6iinLWNxdJikYcmYhIcQk7DPKLhMmndlEN5QPnMh4w1t8fbFlFD/6aLpGgH235VnLp8VK/pA",
+	"0jJ8TBYSJDcRh7V2AhpCwDq8CUvjbrJ+m2h/4YEzjFr1z9dsoLJMrxRWFvjq0OB6fSfmsjRwmPy835Xu",
+	"WGGjmAWQN5s0XdFtqzTjtVWdR26b0QWPGrBozR43pQxJGxSijnakIF95RIdWAaPQXCnmHN27bip7D2AW",
+	"rtm2ARi+hzgBIBvhkh4oSiZIDCECrJnEhgATLC+dTgyHmN5+ogz5zz5NGlvqWNjCLk7BtyHelW5wPREx",
+	"dhF9EjgfNUyk7L/vn1A/YTUh0aGP9PQe9QUCIQn5rAHfyLsyrKFxgxbvMyHy/kkGpHQ/nKXsnVJdWOnu",
+	"oM0MVNcl4whHHeJNGhYZ8mM9MHoajstXEFpHdIDr0T0OpqJQCwSucC8UtIu0pSEvfZZxShAupGkj4TRm",
+	"GeOnGc4DeuLoHjas4hbdOHnGdW7aprxXp0c511R06dWpqx0Bwux/jMajpzpbHL1/8vX514822/USiBzD",
+	"wDOvc3UCzsSwqxP6uZVbMwl8SDHCEfwhkkh7Jzt02rn3O3OJmH+XB3WOcOea3aQ50E2duIhnshSGgGCs",
+	"Ytxani0OskKVYn9/PwWrkwI5fAY/0wFyNmHY31B8HFuPDBPEJyL0cFWKXk9YInEfPTKU/uhvjLm8FKW3",
+	"LUF/Y3cL4QtehsaBkJqdsEokQwmfK2fHD4XysRHKCHTIbq2wzXD4dN2TACf/neinORbYPmXwX/900J9r",
+	"p8K5eEMaH6UNWkVbsr18Mj71G+Om4pdu7KgjSYaqSOZvhJD/uIAFgimgOpipSvSlvkXR7k2+AJ5h8MgE",
+	"jJLA7Fv5BS3AgDVAA4ybahfhIRFyLtAHtGQxfGY7xsp4+2BTOMYlpuLUGowrHIinUuTq+B50tOqMX9W2",
+	"PXzpoyko29Kn+MFDytBznh3Ks4hX6qibfxHjMPQBMCTW2M8gsM9xnK147UzFTwUpAabWy5FMD+hGAkc7",
+	"hsmhdejgsdrFwBvaJ3PvwY1cT+vS1g8fHu5yIV/fsRZIOZHCqjoQCXQGXCQGTryl6q0lufZOn0g6HCm6",
+	"mKND5XICL1WrzMZa+/tdr6UPCPuPD7J0t4yrgQf37lE1NnezW0oltvXpJFZun+IQAA24zz4ieoZjQ/HJ",
+	"vEYCZt9FDcu12qFmqbuaXuPVBCNYdQqXopIIl4Wb5m3UJ91QJZQGglFqdemjwoBfXIjKhpqgiRTD3YqB",
+	"kkiYf5BCoI4W0vMNcjZN3PPe0qLLBO3apgncMcLeSlFPN6J2eU+/JmGD+mjsB1j9xHTEslKa6xVtTywr",
+	"xRs1dkcYXgF5p7YKrvAMo+60uFQghzg1r4Kx02WCHzRXB4FE+ehV+gx6uRCCEi14nUvLrOayuK7L3k/V",
+	"eesHuq9p1GYzeiRNP2S1pNZgE5Kko//z6WpzTgpJoQvVHJgN8pHrd9DAERIcXx83scIEQBIcYr2j9x2l",
+	"Rv92oQIRhP7GbALmxkkoqu4oIzmLpLby2hGhb84jdk+84I5udsu4cRpM8AP5TE9eIFiiYfcioIS2MESy",
+	"49iXPyj5HIO17lPqY0kFXOn3SDSHcr0Pv3b0Pe74+0Nf7F6mRU6F6EmfCSGdcCQWMs9FOWalchZ01wbM",
+	"Ar1XvLhPbVeV9sqwFnOqO9K+ZFvVb12JQ6c8uXJbWs0QmgMF8H02wbPWXky4SqNzWKgA3hhcv5hmOnED",
+	"au+EG6TSznmU1HDijFRQayaO2Zlz+p4XE2Idbe2xq6uGEIzmUlEwCMr0ToO7kM4oNWm5PebNFpTDgDPa",
+	"SrBLHtJhoizFfP0xJLysi2bV4l4tNtM6tRsvhjv0c3vBbQioVrTpdwis1QEsGIZyEQ2tpc1PV0Fr97To",
+	"JZtbxcL4wNgUG9EmhlzC6znztKlbYR9SdHqaLUSexAR4hkeDjt+U2wxRrzu2JZZpTLirNHkn22mCKLaK",
+	"vSuul52FjJyNYGJGwypfOUtkITjZOJfe/iQuRYnJ1cC+lhIroei6NOxe6StyYOVApZmfj/dT5veDjBTM",
+	"5FH6xM5SkG/fC0Ggru1UpCQYKGH5ULUg42oTdpPM0x4obcF+9Ikrqs9IOGaPffZbZz/Zu9FSlrUVSN+w",
+	"R3tqtrdUpV0w+l/305UQF+AejScyOmRP2H+w/2AP9h4nq0EEp+X55mHnbRd7azk7xYL99ngxLPjhVuRL",
+	"RZGMrt2aFy4+o3HvdYb/8HDT+F0ERVqR8eTXEKQ0LSpM2tPvOF8GmNV5tOwp0fYnYGjkOY/Ws1VmqbsJ",
+	"u9TZxSHoutylb7/HN+m3P1XoZ/ckdVqHYBGkRZoOhH18Fft5tYgNmGRd7S151utKdWTRU4opXqTmFMhA",
+	"cE6OqsvWLeVI60ZI1al8nQZTejsQNoTqaql0MvzyxD3p2Z11RmnFsiqSsDyxlOnf2sAV0cyQ2gygq38n",
+	"fYfHT18+JfqF576vDk81Y/bm7Bnobsii2og5S6Flxg9+Uub8aTkXhTDXCdZ1Mmy0Ca3kCM+43HWxzomj",
+	"ObZTKcLitjIkwu75+7B9zJN8Z5Ms8lK877HWkVeybhSe6CytVyrIkmTwNlle2NseGqvQegW9nFux57zV",
+	"Q8roXXMIzZc3HkY3ONePqDW8TVvxuu4pmrCQBiuraJEpnXe3o8FguImM9boufWZIlnZ+vV1w6/kay2XO",
+	"9tjEz2vicKvC362TFi3F2hqitNvPbtPCcEcQ8e076e427vJmWH9cS/L0I1S6Q2LXljaF1qlaYG8Xq7Ap",
+	"lHbmxTFVMlNTHlNi3Y3b9/Nb1Jl9xpdbw1YXQ2wCrQ8CIcY00jA9Wo4tx+nu7AGxanK3PejTBdfbCjJ5",
+	"4Jnpap0U3St4QfWy7nwbdW9wiWHLkbukVBYhymjq6wTfllfWKTM93xOubXe6YfQdE5mAeD0TwAzdzAND",
+	"Otx/+HiI9cBPYsvSvHzxdtO6UCOkrLtAhsSydE5HS5yI+UezsLROyROA2ZH/SCV/NXgTTVJzOlF5jVTo",
+	"i+slTm8VrKLG0zNCrMSfeVUlN8QnTgZ8CUwDEOF2gDAa8d4KDbZ0B7xIpuxKGapZogVhtweYtCW750EI",
+	"0SVUCpELlxS3gLNAnbGpsrYQpcgu9mZaCPb8tQO5MVbXma21iMzLCHjwWy20CxQClImAEkYV8ApxyUsf",
+	"7xwQIHgnY6SBTJEly5wRJwTABsQeV1hkr3VsXEi1aacAXtdI09oaL0OsoymlIOk44Y15uB6wxWuZi3iw",
+	"e1cyjzZnNL4VXluj0fYY7qsjmb8/96t57mgDzPjn8FJzm6WQnAZe3aH1m4ArDx8ynS1asETN1f9+/pIp",
+	"3QEiuULiQedJLr3Xw7Xso8OTYgS9MrSbCHdW5L79O9/DIbJHe4+aiUVLuU7W21nVHcoHcgkeNKV/Bttg",
+	"SplXLvep5QDIRS7pykTkUGf0zXghypxrMjSuh1o4RKNBANTG9kX6fCeLwoFdB2qA933t3EzX06mrylEq",
+	"y6b4/lDga2PN+ZK/T8b6QBBsFO8TOm6BOjomTAswKFo2gH6FCQ8Y6TK9X7iNjaOSgBAbHerh4cNHe4cP",
+	"Umfwt1rUom/NT+ulbzQY+zxMrkHhJMo8XBA+wtZJdEEc3Zo1e9Cig7V1ag95POoFcQ80fqJU0VuaL9CS",
+	"oddF3qHyNXoOgytW5wiYlQI1iqDYA5E07UITAbSrLVx+/XgQxWKaIsb9paF2MOeEigt7QD1+QQFKU6Xs",
+	"OGRGScO+WW6rGrvDyWhP0sRIw0MPxhLDeJJuyZ9lKZe8aKgRV7JVHyTgk6J3qjXRh48W22a63e19ybXk",
+	"hMLjUb5kyAiri/RF55nTeS4KnhCBj/PCHbHI4ebn5kF/QisQytzK7lOVlUtfUDk++I+3bm00tLqvpmeL",
+	"oPyosMxKg9aD4UwxwbVWHhMBNw8lHZwIlLfxcL924sW6bcPxqZCGiVdWKJEJ60ltiDETEgN9J563eWSj",
+	"quCrc/CYmUmQM6ciVFpu8wUyv6YJ6DU21dSe6Q0J8YHusMA8bTiEeZhUZUmky9Dc2nRB/OZ6cOn0NvtM",
+	"hiI2y5NUqtZnSjbI5r5En/xLhk34kvd+D2I6fjSQc4RvUwIN5J74xfEv+uQ1n4kX7gBa5F2XytPcVvsu",
+	"Nb+FsNOxsMNEs5AvESjA8a1e8SyV8BuDTSI7vyNxquHevpedJKid+vIIHeHjwdcSCCrDSy12ROzEAdp+",
+	"17TPcLODPVLcDqvQluLiZAYnxg1aEuoWWkj3/ZMq5yL2FIdzl5YdN95UdQlqVcrY+LaZDMGGCmdI9Mha",
+	"PgoQumEck56GTTF9KcWiapsG451YW55oCk5sDUS1kRO87eVqr8p2ltombrZ28Afe951CbP74rF/48LQR",
+	"S1v8ezTg/sfK7toO88HhSLwpYDQ04YE6GDczTy57Pd2QxBVS5Ez0mrcVQ3gKAVSKS4RtVVbO/OXnzHqy",
+	"vKBgcA/r0aqz0/pi4pK6j9jELG01YUaUuWFiyWXhu3TmkTGbmIJnFxNWodTtnp7Cb0yWmVpiDrKYLpS6",
+	"YG9e/0Qlut0P4SscRzMCKkntQ+Bf/3RdI1+0VNsA5F7g5NxjpjSONdiXfD2G7kBbtLawtjJHBwcwM7OP",
+	"y7KfqeWBt9wenB0eHh58B//zz3/+85/bAd8Gh67grqdpBh8RpApB/EHW3xFGEQejULgFJ+P2A3RXdX+M",
+	"PIPwyIdGnWMgrCznE3YvDfuA3mt8SzCjVIn4PO6t84B72P28BVEpjS9yCB9jEOg54SuI3H3YrppI6oJb",
+	"VpbXrcQKiiKFljD0AQIEJ/4PwDxpJvOSgIwKsRRWr2AYypODXWhhFqrIEW+IMNfRL2AWwsnz9GMuKi1o",
+	"2ajRY/iZZdxyCPZ2aUb3OyFmyX1KqnqyvEhz1KH0ixR763HZLc5yS5X4rxewTafErVTDCrbx4js0hq7K",
+	"7Hky4qzJO8sZjYoUx1WZsamwV8LdTJgwuina+RyrfQwXHrsFuVLiYzeYeteG07EwmJ0EWVR6W8axe/ca",
+	"SWx4EIePGQ9oqp0mMXpQO6hgp9oJsx3UDMx3q4IXkiVrtzqdmO8EbXRXP6xT8mSsyiwyfXRUOuJhW+9l",
+	"T/c+bnE7bxH5vAtV02J9KWPoKQyzzEQn4Rh1/0yUVoOiiSNuKjVMV2vdhYMvS/v1o+1O8GZGfmTjsDD9",
+	"K2oqVRpxK0sKwlshM2t6JAKYm2t2TW8B6mguyqal8U5Zn1YWO24HbYNENB9cCY8kxI3DXsLAPhfTtuuW",
+	"0IiabYjXKLUlZ70Rot/VZe6Nn2Q381a+WO7wrupkKeeoJJt3Kf5am4DYHeJOgYTaFeSqCEgmuOmjvDas",
+	"EI9rtB1a4npQEi/eVwXWg7ryYXVhuBJDEPpLPGw/47SeVvmFaQknriDB/sP+Hi4JyClhoaQHff2AVbs2",
+	"blNhLsa2MXri1PzeIPHntOfNbqwH2I27P0QvuyACw6TdJaS8WSCpzN60lsUtySb98dZ+olV/3PX16znv",
+	"ihQQ0VaXDPoCkOPZpA7/m2queZ5OicUcTlcirRvW9RLBO0JBwCvmBkL6Nz3lGgNwMmEIvZqKioxZXcGH",
+	"E4/WOmHcNghtsDxHEQSaYVWNvp8llyWhbLmygrnmkgJtoBamS3m6SoYSU8F5oB0sOR/QBX30BGXdaoZT",
+	"NVTeMipcOJUl1yuAShVla2hQJ6l5VbyvyGTs1oL0BFpgWgiRm1YRRVWKUM3Pt4ledTIosAnO0aOATdh/",
+	"sglpiM1PcR9UCxBWpF1B36czRzOg8sAeL4pqIXST2eBRTU3nbkvnwjptcAJgE/BhrcW5xvji9aFYpRo8",
+	"Wk01gF9GKDI+FNERmS8eFQofki/I7dI17SJuRLtl6TuM4oQLEZSxsG4IFPzSFc1CVdpMmqJ/MLOwfith",
+	"+/XBVrG3aMc3GQ0VbnLYYxzFWmado/kxe7BAJGTiZttMhFvimOM9FtvTZcBV0SLaXWflTMt4KBqDKC7U",
+	"mD04XO4wsy7RJgQ3PBj3Dvce3E9Gjbuzkj4eDXCmXyPHPDWj4imOONzpaN5z9UU8zhZyMW4ZONcte9zJ",
+	"ixA69uVgTOGarxpbaltXDvcfDvIrkZo97FThtEfXSHByvH9DBaPmDgmL3L0nxuxBe+/DXB+mjhZtwRYE",
+	"C79xhGGBH5DUTh3+W2jVxjLeJesOdNDapD0MgHH05uXL45c/jNnJ0zenL56P2fdPj3+C/z797tXrsxdY",
+	"efzZq59Pfnpx9uL5kHNnrKiGbuUpvjtUdBmPegVQEADiG9AdjQVqAq5qgMdl8b6CsHGjy8P9b/cPr5Pl",
+	"1RKVWsHZjZAUTEie/roMd41XJXhG2MfmnvBL3SKyTRkQrfOz2QL0MoYedGfBi1IeEMCF/tOr05VTpxIp",
+	"daSx7z1wa7H3cDfY1xatbEAYH6ZwjVoCVSwUhCNfqitieAkpcEpMwodw9JyGtTW/OxMn2qwSYWqurLCD",
+	"YwSBaMGpKkKh5nCJ0XYZRvGy4ILIl7KcRKb8UL+YZA4vhtP1Ai1iMRHjykQbmwutUzWEHVAl7H2rnnD0",
+	"+w3UaZhW9wXMWtj/EZ4MW1SxnIo8Fzku6RDENlj2NGLbLeldOK3kKTZCPz05PumtrHNSa1GsAGZ+D6OS",
+	"MOifFw5yHdvxth+3xwQr6RARPLoQ24ONbVIUjLDtAhzYXijho7S38TmMnRg/ypdeBRnmDvf6GnvXX6Do",
+	"WSG43rOgMsV1iowgSRF0NA9iH8UhwLxbs026e+6CYMJM+ojm5Q2yDBJM59XZyQYn+quzE190UZRaFcVS",
+	"lHaM1QBLCpZTZLht00GtZbpRZSuoj83evMYKijzPo6Sk4DJvamIrDabW1i3vmjg6OLDKVgdPf6u5lvXy",
+	"//fwEOrFHMFm/v+pvf/6+3enb//3V89PXvx48o+vTv55slU0gHH3rfuZuhBl+r7NXUXIC1G2fHzueGJV",
+	"ZCMznFhsqbRR8aw23oss2bNjMohQs8EY6jBxvRUUYaLoYkCPMZ15dCPDb6BKYsUDB8F3l6h2YZGS+vJg",
+	"Qdd/NhiBLoZrwMUaIt4OwtKjpXevruGguMfSUPFyAkQZALHnud+mFbn2zfe//D+g6J58fwSdnTeIJbXZ",
+	"psTQpEBBqU1bf/H3fYk47hbf5yUyAMYZgdvsotFsN3TTWKwC/RQM+rU0iyicu5Ad23cm9ypZiUKW14ch",
+	"bOa/FYZw8Ex3gSUcTMF08hMhfQ1yd1g/v1qOU4UUyXtARYyKxkaGgsl9uA6DCBHqyqJD4Cdp7A/CTu6n",
+	"UMrRs2IXXuLEMyobrHPYStWFXm7bw+M+PggWs03z9Yb10mUybuyK4d5r6k7dUrzGXihSuEsYibqKGdJH",
+	"DR1x/3G02WLmrWOwRTAO18jdKFu/qF4okdjpNBWZAmW6SaKYxYX6pfVGf6vYpbIiih5rpbEjfry0oSFZ",
+	"SLvaZzAKslKhdIAS9rvRSph3I6p+U6p3I7zF6fKH/CDGI6+wVnWZG5aLCsMOVdnq1kGgxwj7TNeFMNe8",
+	"3mG4/fm/d5G2iwECkOaJEx2QsBs2KnFwYMeqglNiuLki6QzWC+YVNjba/r+ZuIDhOC6qp0WlhYHVifTq",
+	"hkpkbOhv2vAGXBkCOfO4nHZTp5Adu5xvNOxO9h5MyO2EH7iK6HUZV42IBtpXGH2ganZrpeevs6ftOvW8",
+	"7AGEc0U2jdIuyWtea8HgkPltvJJlKfR+ImL8q4d4Ias61fhr+NmvaVOWwmODU2HB3nPYHgo0ATyYvfKq",
+	"cncoD76+xTUcwqg3lvmmJYnPkNuCFI/2vOBu2LMPp+/RuE4XStu9AsOQXh0/f9ZWvHw87BqLa0v8vQeg",
+	"Rxw4lXO4+v/+9mx7NRx3C0cdrq8hJtdnNbjaAQpmSWPEi+Ac1ET4CzmvQLgiIyMwu4W1FW24B714rjKn",
+	"dxdRfPVc2kU9xdBqnqupOOBOV94DzOuDK3khg/pM2TozRQlHpeUELkBSCGS9z4VkJ1ybhbgcjXfqaN12",
+	"CzKo267vfbG/A3dvYaGrTLgYL9f/04pnC8Ee7h/iFklLEG+uF2wkstUfjR7sH9KrqhIlr+ToaPQV/jQe",
+	"VdzlCB3wSh5cPjiITsUB0qRIqAevUfYzjIdKawFevwOjF6Tm4xwHHh56mZY8CEthMaTxf9YI7b9/YpO3",
+	"P754/WLCqE5Y44sno5+L1JDwNmKNeLnraBQKi9GlnRCb/xivO5CK7bBCqc5afpLb6vAnV0Mq1SGFrRCb",
+	"afrbzIAG9x9VhCJg5eCoSQ0lPGzGsUO0cG2Gj8tbNwTXhUT/NSc5z+fIMD5X7SKMfftV5EKfw/e3sV8k",
+	"zDQ1WlsZ3qvgmekZC148d7STjpJcGuY4/oN8QFyWQlM8ineC+eethiJLHQYe+gOBBmHzruyZW0Vt7bbI",
+	"gCvisfMrpZGzYLtNiQ7Q6sgV8Pw7eAsGyu7JmU9Uuu/0D8ZnM4/xArckMowpptrbBf3VmBON5YXwYVX5",
+	"9Ny1ew4R9OeytEID2n3vXEOp87WpRuUR+4vaNVwtqC8Vnwt2b8nfsweHh4f3x03EEz4JNregjUP4zD/3",
+	"AOhy74TPxR5KDRO2EDwXep/5wtm+m1aEkJzhghlh93unCN2eu5J2zSyXlLQJGNOHh1ElvgepGNv13N1I",
+	"YMFp4Sb3TyO8q8WlVLXBjzaN18sgO1DgM7Vc8j0j4HZCaO+opChqNCL30NZWMRS+p6sxm+xNYFgz+d5Z",
+	"RqFVQbBX6GkO9XkbWRTr9PZx1h56CgP/13jkY5/xHn94eOiFFhd2Fd/oaOY5+j1q7+ZZGH+sCTSnhMQI",
+	"YGLh/qd4gNwlLqxt7ehoI1UEih97Cm3j5DsC6F8mGOSjwwd9Ew1LePCmBHlTaUgJfYFBB7FwihJKLJb+",
+	"z79gB0y9BIl9dDT6QdhAKl1EYZTy2wa+fyE+grH9IPmuagmdUYf6K7oogJsELWrohNJ3Xejydypf7UQl",
+	"g4mjbQ45IE32ei219Aiw3P1xQ1LfqesBFI0kdZjCB8xDLPoHIjvaZHTftskiTXR/jJPyfhtZOE2XTVV6",
+	"042ZaUkLS26zYPg2aD1QGkOXS0TgKsZkaM/gWoT36qpXdvHeOorAV5oZW2cXyAGWBD7DS8ahYsA+e421",
+	"djR6sel68y5CMnBJDUJLu/ohe65XLhG9WLmjRkYpnEX7rDmTUu95axboO/j4wxy8Tqe3dw7XG/5Yx7Iz",
+	"Egc3chtnNSigH+q0nmk5nwN5NgfOkzqdlA7F7XqOZ3VRbNXd1w7brwqjyr3mJ3VL9xPjRrvhpT+LLtko",
+	"HGovcztyJ2RRVfra/fvsjFC9fOxpo/sznhgS3KRbjtv3dVF8sSV8sSV8sSV8sSV8sSV8sSV8sSV8PrYE",
+	"uLu/2BOuZU9oLlUtCDPLncJdZUVjteDLXmnxH0JULg9KlaWLm1CVcOmiGGnhkzs7V7HwMt2K8aoSXI9R",
+	"EgSycYNFSNlColyIuen4kZrhe1FryFl4ccVXDlzURbzRIcAeMLPBWKW9BCtLOBQGzsqEejs34rdJSK2f",
+	"8wqakTaKYMQBYLAblQLcZ8c+V1dit5SHaQpCx4FbY+w+hEWE51mhKEU33G/mQiI4dtOD2WdPsSIqpg2g",
+	"nkp1VZtiuViyMo48b9bG36vtOntY+R+g7ykaaxapuqoUDsyrU5K8tcJlqawjLRiW5ebCsHuyzIo6YPZX",
+	"Ws0J56q2VR1YmCvJU87pK6qLGO8Tivq0RjQQLRw1idyvLV2bFXf1eCewWS2sh4C1QdBU3CAKVL0U58Dh",
+	"J3G4Ou0evWgoHoX6JSHexFgKlYuWVa3ldp+qWRxWQuUF3FWPFcNhIL5Nlxk9bZSopy+fb1Fe8Jic4sIM",
+	"0F+CTNaMLlG4LMX541CHrmi4XgYqLSOuA8hcY3ztcsI7KSMfZZyoiJndtLThVeNvMDAnVZteGbwFptW0",
+	"t0kq30nsvr1Zhpss5kekBfrT1nCnwJz811w3VrrpKqFmJlaxd6IJ3fM68Fe1GbIOm4Bt2sxu3ATIXYT7",
+	"GN8DEfnQLwXyMMyNBo7oL69VwAaI0K/LFRpO78UBnC5Pl+AD7kPBs0cPDicthcAjEDie7SGmtdgLZcjj",
+	"xRcuXBNZPe4zhX7ub1B6AkdP668euSdoBYmatjvKse/3yvzaVknk4C9g/XczRV5PchyPHj043ADY2KWB",
+	"/q3fTQqlKyp5SINUsavg+Xst8z8GRBiBCFe0+52uKBaUQvV6b9cBV+qb4+dtZdnTZcXtoiFLX8s0Nnvv",
+	"Yl7516foubo2CR4+Wt+veHuA4GYYP7mzqtPZZdrg3cnqoLkwDgYZwqe1kaUwZq8Ql6LwN0dLKLaWZ4vg",
+	"RuqOlFQK6y+stqS9dAqMUSQWrxeuc0eJZOTNMuPTMKiBZu+Ixtu+wU+Q0He9bZvFuLYd4ZM9C7GVKDll",
+	"c82zUVVaXYrec/Gz8mJjPBFZspMXL58fv/zh/OnJyetXvzz9yRGtVVAUEBVraUOt2WCy3mdPw+WDyikY",
+	"WnhS/3Ujc/rvFt3pKb38JzsAu4Gs3tD5iZI4bQVlFuPqY1R/DlyO20/zdLitXzdiFL6w2fVORjv+YYfD",
+	"4e6E5y+e/vTTq2dPz17Q0RgWJvB5UPBt+diB0jJVEtiJ+TTpK/jn2+CccWXnmxDXNTkwCvQ/vvrpOZBb",
+	"l9T2WXSY4QC7PoLKFp/uv1ng0BZLrwntnfUu47YZ5+BAl8+KFf+VCNltzO0SMmTZlHl/hFhDvetY/rng",
+	"eSHLEO7iifh5m9JrjfWtQCqQZWMwjLMHETURh2IgRZvqQ8Bvvi8oElHIbEWW3/Amk1FZJx45L8JInBcg",
+	"hq0MwwaPaJPINNlyQl7gSrkIsE/rXNxpNBrN+/aC0EJ7Hyv27HbErTX2QSWTgEhZroQBvozQAMzGFPtp",
+	"shbak55zfj3Gcp2wNefss6Y3WA3COIIycr8Tu7Y9tuwvZ0Qip/yfyZB0W65yolItfnWlpnvoFOIgd9Cf",
+	"W4ozoRN9CPX5NU7jExPZxn1Aud3qPLQJIvfDWHMjcKPKO411+aKoDziJRGS3rqd7bIgB3oNwDXifQTyO",
+	"Lf4D/+1fyMTkp/wRL4CwYzfg/vGur2/4NSiOQI8O+pWe11An2cTofaJE1LIoor4Z1oTeOb8QULfsJX9W",
+	"MCM4VtdT7+97c5J/fd/BAuDdYIRTrrCiBgA5Oo+DrBZCw9+k4Th8O8OMghPXEdRCdICE06lVPV+wn4Xl",
+	"iIeldBL8H9qNirhtC23BGbaSz/5CGhDN/vY0oNDeR9OAogHcTmqcV4ACTbpzcyFWHvJfi7k0VmgH+n+3",
+	"V5zS7Kas51SUKQDPuJcIj+U6bMhyu/3Wi8MwuqJImiNuDlD74mL5cyhCRADJbboGLUIQ1jDneo8jk6JF",
+	"N1HfGTcXn6CTe/zRE8v+xCktHzqCAGjsLxM7cOZO3PWgCBKNIZ0PBCKA9/+q8iBR2W1Jg661jyULNt3/",
+	"GUAScDbXuwHlksCVd7VZu2SOJs8CE1lcADOVvLaLViaqGbus0IUoKoetWOa+0PhCRCYVZipREj/HtpRm",
+	"c2UJMmGzynbm5vMXjynzy3BsxfLPdzOsCWGeiIccgTqXdpjMh69CURZPJsvaUhVSd+jXiH4cBSlvM3RD",
+	"4z5NKkXT8Pw1Onk/EWiAD0PDzbRvRrfbLdC0AbdlgL6JdEOERh79lmgDv6fI9xYTHlt975js6L9yuWqu",
+	"Zk1IjItT1No1pnHy7hDt4hyKz8z+5kMTZ6TdbSJFTLF/FgqNkiXWiGQzgU65zRY3RV1l2IoPRH9jvM1Y",
+	"+bQrnxO6SRRAiJ2/FO/szP1jXPy3kBfuN/9GOt4kU3VpJ0zmVHao6JQAtq7ISZm3sLKwa59oLLILV0nj",
+	"t1pZTgkQrkq8M4teLVThvnI+8L81mCy+3iokM2uUd/wTjkEyW7wPuIcfEvnulmG3PjbYVtT/n0HHIyob",
+	"IuISC75Jbho0MDRDDZf5LxdhdA3iujNNirbrtjLW/N4P9DNHxHYN104DCRG4b4dRe2+UG/A2QryZsyec",
+	"sc+cMD8lv8+NqbNBNUnJCUNodK55aRuGmAu4lVMECsWMiECtWFZKc8DUhBseW2BTMVNaMGl9Abk1cvwB",
+	"3qN2nlMvw+nwtSoEfv8ZhtyEod+iBnS3kWFhyNePC6OCiwsRkUhEjaC1tMlQLvlc7KIaQdvH8JFhuB6W",
+	"F2q+RnP4xl9Jz8EJf07KDe1hRBv4w67A2aahB19R3SE31VP4eCo03qQTpLPzqp4W0ixEPvEICnImRZ7Q",
+	"ObDJO1c03K7dSLvwbXxQlSLq9PPSI57mOSoRLZJhVkWcpEuQa9xqWssivxbPYvipSVPbd/DsL8ezcNZ3",
+	"bNam9z++XbtDA0M536krv7hw33qLMRXKU+VMzlE2KzDrwAfXNR9Y17FLuXOUTjXxHUPEgFEqmgyvKOya",
+	"F8ws8T4XxvbxSNy/D8MoHancnFv6hj48y4x6/rz4JtJgl5ENZ5bDDC+d5ht3ecBtIcXXwRRaLosNRLmb",
+	"xtue1mema9wibd0t5+xRPOJNv4FWHDezZq3ppdCtOjBprb2iJmEeMi0QODIDt41P+5pxidD5Prge7eG5",
+	"qLSAv+A1BLHs4607q8s0x88k6/4jUto1aYz2w5NZH4GNB1qX2yLoJssyvnkNdvaZMrKPaKS7EXlELGg3",
+	"5nMQWEI/dAjXFwkOxLhp+EkeQJATLCm641ZU3sNjCNbGsSwHIehAm5MKdNNXW4Pe72dh7oMv5PunZoz+",
+	"Sluj0M2HACGBrxUZEZCG20SHP3+qdWO+VKz4UrHiS8WKT6ZiBfKKL3UqBpuvAsv1DB1/uFGtS1+7KsHE",
+	"79ym5Lb/RuYk38YHtSRFnX62QTx+47uktCYbwDKtNoCShbpzrrC+l30RO97xU3Upc5HDKfPpFgjHhDxu",
+	"yUu8chqpmRuWqVywe2dCaw7i5MGrSpRnalb7trQZsx+kfVUZVskK4+zNfZKbl9KgPO3EH1c/wpG7N2AF",
+	"KaYJxVOloHcvROWqWuB42g3JJcTbTwsRRP3QlHuPjGZNGQtHcXSbwj1O6AG5ljM/olJZrOgpjcM3ztke",
+	"y5q98i274if77C1GHle6LsWEKmj4MfrZ+wIhwrq7mGAL6rIQxjTo9bVp4p8TxUb32duFKNkEfMmGFI6w",
+	"mRQxY6xaYpCAaUci+uIkV3w1ZtPaRuDNebPUdZWHbcHZRDD86Lp2L9N2UFwjTEma0JOrkWg1Lw3HoHLc",
+	"mcSSqpIJODL7ELvLJrleneu6bNU3qQpOAd65sF64w43aIR68XWtmHKIoouIofuzY0tSpGikNDgkQNmR1",
+	"12wYO0mxNFh4HzOKax/fQx+Y5eIgb6k+6dkikJIsL3kh8zEeVS7L5nA6huQEA9hlj6CA/ItCbpFBMvnh",
+	"PKjYH5KmyAoO87xc35qtfF28R6ViGIh7mRdR8pzn02Xe8me5qBBkLt7yIZiR81K0XWY4AN/qhVh1zSbh",
+	"oGRquZTWlRGao4UkZ3LpuKhrEis0+dvHMKtr4sfw7EI4mMqK61DVhBeg5K3YUug5chDrOblruDO/p7Vd",
+	"0C1BaYCIQWGC+cZ/dm0e0XvyX2DLAxRpfJtNQMz7KkP4PfiXQN5Gw3OSrRXGhutE6fVPjugH94prZlM5",
+	"oY2moMG1bP5116zjOyS2G3MNWmhpGjtNijn47f1QHOFFs8WOdB1F+7NHkx/AFOhs9Ut7vwgN5sYWW4BO",
+	"uK218AcST6DI4fDRIcqiHIjO2fLySpuHeGjZNSEtIZGBuDb2eWWzQmb4SRAxtDCqcFleE1We+5cmR2xS",
+	"iqtz19aE3cvFjNeFvd8aLuqJ4cBENbYIF1ddCn2lpRUTtBXxzH22JhQ6wQs9DFuFrrMmxG6LzNm2RXcl",
+	"T0wWqZfoBaNx0f5ukYBun5EdY7dD0Al+9BUAcdfak21tfo+Y3cOqoo1vafWiBHPS/4wiSoAZ+F0d/Wto",
+	"RV8S7dnVgjeVGpzg2TMkt/oDQDvuSOQLLPHG+nfMXD+wSEiUdYsyoWNqjpM7VjZuyYhIRnH1bce7NtwE",
+	"7z5YiCctSMxqg2l759vAWG7NVgkRuIPg2aJzPu0iLsCm67KEMwwMZCGNVXot/w3y1IXm8wbtGN8G5gWX",
+	"i0b3fQesIReZhAprHlVPFXkYQJToa4Eib5+tQUaDGSCevQRNuHV5RBw3XGa42uNQSNDtXmM87+Ejuxez",
+	"/wJydAtma9z7P3nYZG3gNAJdwg2ctYuMBsIdwkl2CvGh5jfFY+AbOwfneJPnpxecc6uOZFq+m0XYhC1o",
+	"R1E0/oZhETYDd3K30IQPv40fy2nw6ZAFcIMtNBGfeYeYdL24AtWUXO7QinvyJbrgT3qvquyjVRy8rku2",
+	"iKg1nAg1GBVig2NW9QD/+Qd3754N23EzDVHFNVQ/oHqorlO79dPz064D+kf0FTHdUuXiWgz3pcoTScPw",
+	"6xdG+yWM60sY15cwri2cFljFlyiuwSKD57eencPfCVae8Ypn2PYQ6BL3csgn7DoYsFMmLoFJTvzbDc+K",
+	"IU3aWr4pFDCCTCvTCvNxQer41H0AslDBci3BMoC8AxYArKfEkZfKivC4SS0D4xjQM6tNzYtixcyCO/DV",
+	"rFB1zniGuFne+QHszpf/loZZfiGSwRMw5WdupjtD7u0mazyjNfG9fYoCa7R1gVq2E6FdSDMMl7SpdYpN",
+	"pTbjbCHNHW8EsaJPcfWlwYVhspypges+5dlFXQ1bfjp79EU4kNihF2jwFmpQYdfq2iJ7gkO137t532Hz",
+	"jVR491cL9XjrZtY7qjd7fbhVaTqb5XZynVLG2+MwVWmkgU1gpuSVWaggmngvTLsvYMWqtsxYVVUhFSmH",
+	"t0OLMzmvtcjZhAa2X1eF4vk5BOjwMp+sUZOLXpS2jzM39NRRZO+IM3hK+jQpZzx6nOrtDSoriI5B60mA",
+	"ZG4muyuU9Gma5HZjSr/DjT0sodx1OpM0E2MDXJUVuoQbf416wDcm7TZGtKPnza1b0nCM/9lkOb6ZYK0y",
+	"K+xeA5CcsEhPZUlQcmsi6ufO6Z6rqxKYReJKClsyiPLIb72V5Ljen/+bcZ0tQPC1inFrwT3tYqKm9dyZ",
+	"DcwRYkCXCKfuYH1jSA8UUbXIeWZF7sP+xs3oncPWS7QG4eCAcc6VVrWVpTAsr5dVH3lv4ow4151v2fm/",
+	"ZfWnoK+d+GETx7LT7ZtLPi+VsTJzTcRi00Ca3ElJQ/uS/6LFhL2GRCXM2hFiHa2qzDuRFS1kSjWjUAxY",
+	"Z27lNPjgXCQ7tkH2LA+JwLScLywr1ZXHT2/paU3QurkQuVevpXa6WSGXEg6B082gRK/7BPQyxiFgIx1B",
+	"4Un9A6losTb4yepnKdoYSIm0X0iQU1lIK8X2yB0KpnkdyM5ZrIDGLnlRC9MiS0cSrcExU1fISiMkDpaL",
+	"mSyJB6AVACOnKM9jzmXp1AymvJTYf88/xx6fRXPa8c53Q4ZOeZkJb17jV+ag0ip/N7q/OarmrgSC3Wh3",
+	"fRn+LBqQp3o/r/a+7Ub6M6VFxs2wlAYTl+xzVFIpVfhDEY6iNGymhcjB7E8hp1CQlt1DCPanb09ZLnKJ",
+	"gAxsoYxFUEyT6Xo6leWcquKH6DQ0V4aWTaEw/US8r7DkNrFfPFW1cWfPz4lZhalRiZrdkkKkKTUMZgQB",
+	"c06La0pCC5f7tIERE5V973r8Cx80vwR/tkOGVBco6gYnDWxY582Kbj1s3hOy7TKZyaIwPnVxQm+cQ2cT",
+	"P1pPzXlza42d02Wm4DAl7xpGY/USFfS17c45LmfqFL/6Cx+EZhH+TEfBEUN3vwabZd0h0MJYvjGBLs4j",
+	"6ZIFZbyKimuCfmy7Q0gBpQsh9qe4PoG6a6uWHJNiIPkQ9EqDNFjxUmaUcmvUEsOojVM9S0GJdC6SlC15",
+	"iQ6XbWfhNfX6uR2Ez5ta3aJv55oDqRbjcM+9XiiLoUqrC0MP9BkL9w3nXnAXkOEGGNK7uM0wDxsG4vK4",
+	"ZhLIeab0uH0z+ZQLjkELpVXe8RcJRk16OHrZPa111V3yul/Iam3IEgSw8m+WGYwhcdXB4C1RUE2yTSIS",
+	"4WLEa/ihHCHrPX8OUXIRATW0QuYBXP+BtLvkEtYS2EgvzR6XLHqNLaGnYNAzFlKyOJzpIPg4ZyixSkd4",
+	"lKGy8lVuKH1WZLUV++w48qkhuQN7ZVdKX4Bx07gXoYW9AD8J0jmTiZHVpZUFZUqHbDGXZJ/3s+Ofm2YG",
+	"MOMXZCxTmuXS4D+7w+hhwBQi1YqbcLmRnhdvD1s65TMoMWWscucVhS52PGxhfA6M03S8JeFdmatSeOZS",
+	"QgibxY0hq7KoCrUidA2O9CUzwfboVb/KzQ0qITRBcqiENX5XYhL5lURvWFGwX2tD/rB99iwko8Moi4Is",
+	"c/Dsv2a8MKI/GMosapurq3LHiK/nxJP816ziLvKiMdR4d17bVEc6KdnlfvnZa7KoNnrHn3f1+R25763i",
+	"OP92sIiaNQJJqIHbllMWghd2sXIjID69NspEWIj1NEAWxf4lXPAyV5dC77iEkEW6rLOFs1EtcAt7ac5V",
+	"UAFq8lLKg8WDw+VXh6ZfSvHbc472gFEyy8AXGR6N/xoyy5mW87nQMbNPsJwhHL/SaoZWjP7oh9OFujIs",
+	"F+DPkSWtOVAonwKBLwSvxpEjZszMajlVhWHCZvvsF6FXmEUIaytLhu5tOCD+cCzFUmmQSUztC0orVpe5",
+	"0MZ64UaW5MAMlP69NI7k3GWgyn5mfuKneAySTL8c8XmTBO1SBfvpRDYXk7cbFfwOjKAQ+o8/PzkMuNnf",
+	"ImOnRXULg+MxJKaIZWVXbI9uMrNQV7Tyac+3+/6LwvWDvBSGBZKj0G8MNcaF3k6yDklYc1kO0qxi1tgy",
+	"RyPJBPCZ2L1HF2cjacKlnY61ew7D2C2v7mUkEX4+aXVhsh8xtQ437IbF5LANJB6gwbkWxgwluSHq0emV",
+	"xPq169KPmqHK44IT26pRQ42wP2SpUpEvA6LF9lmf6oVfOg2AdCtfurmd9k859zj3iUP88AQeYFxy0Zh+",
+	"qUwKfXAOBi5V28mYNSJ86BxEPuN91kv4h0A59RaBAKCf3fSyD3Lkxp+UNvg87F5nf9tlNXFfW9vai50C",
+	"B353naBQQISKVAFHFu0xWNWg3QVd4N3o/pgdRjRoiJ4ocoJf8dWmcUZzuRUd4SMFSn/I4J9b57LE/hrG",
+	"sLtaQqwWtrKQ5bB6soRzsoKsi9K2g7Ix2acJxj5iaJVA9gMMM3533BosvuEtAwQw1AQFNap3xCwRzbL5",
+	"W5UQekb4WpcBEg9AtPBATNygz7UAIgORFaYfHHBRfFyvodSt0Z9F+hhkn/2Rlu0FLOmfNVy9MX/SZDce",
+	"mkoryCEclr1wQi/TH28cftJSuBC3cXQ5+wsZYyjBTOgygRK06Br9kOkKrsvPKYE9Xv5oQ91PA1LYowaA",
+	"NAMMnrR+C4lB/VYry/tFr/3+HaSe3lR5nCZw+/nuYfNulO7etPJBs91b3d5Osjvsprr1ysfXyFpQ2gFF",
+	"x7SWpNUE/2kSFYZAD3lCdjBj3LBSNZCVrjrPNUh4GFZR7D5vpnmddIUBpAKd3Zkt55MhIVr4rYQzHnxH",
+	"MTlr3VINmxuvA3f1kcOOoRQfmRY+KQaFqRTOJDvFOgBpuDQj2rt++/qIJ4gbGn4G8jMfAnItiI+gGKwR",
+	"pH/yBerjz4mp5Pf3cxJJY2r1Z8L/1nMohpW3Dbh0vrWN0HT+pd30WDoGn58dvSGUj2ZGD7tyA44a72wb",
+	"p24IBR1wnGgvIT2XpoLofzCYX5Rggjk9/ZHRRy2vo2eKodM+4nqK357UnwqJjVOYyCjrvDo7wbI8Y+Zb",
+	"96JQWHNK/wGpeUJrcq5sNfF8uIdVKluNPlaYc3sXbiyb/ALB52GhCHjJLZVVnkxaZPFpHqKIqDMtMGgT",
+	"3NrTVbPV6bPl1nH7CTtfCrtQef9JA3nEhDiwK75qL1wM7syzCO4GwbdDRiO7Z8yCVVq9X40Z3MhwMK+4",
+	"BsClMbsSU2aFXsqSF2zOrTD7+/uuilQ8b1/oQ5ZZUedYnKk2oj0et1xe72nwnZcNqpabs4vkvlQXIsoN",
+	"Ci1JE9trUzple7V/plb/PLfUILkmnvvHkG3u8PTB/vBgYnCE5WlHzbr3yqAzqAoxzBLrKnppLFjlUuH8",
+	"UfMVuVzIINwKCYVCFeJDmlyhv8/N3hovcrSDsKADDa5RC2tGV5OpakcrFSziB7Ky0n7dyMTqmvig9tWm",
+	"zz+1cTWiq3XC7DKU3UyrMckSY3EJHxFb4eWKAryvYWmFHdrdzOpmett2NRjMX87AupF4goV1fdd2tIZ+",
+	"tC37SIzkdgUUOH43FE6GMgmYcl4XO5ksu/ntp64Nl+YLnTAjhCGqh59AC1dlwrTpP/1ETJsfRCz2c/6c",
+	"JKKwxREx+d8GikP+9ai4cAjTYlMsRLyGlFOyTCvIIaq0MFgwiOIL4DEm1a6Fe+ErlCijSpG4gvww7hya",
+	"vdnmGwlSUTMfVJhq9/vZQrOHaSTpNsUIt5bBeY11mttETZzPFy319QHakZNaRB6OdqmrwMSuysZHuXuc",
+	"qx/NzlV3PoKp4Tbci63luoNaTD33c+BkN6vfE9NPH1sd5ioJA9rkKvEv/TVcJddjX58UmaCVN9rZtjl3",
+	"CBc7cGHkm3wl8PzGvKyfE1EHu9FcdCj+ElT3Z2RxtO8DeFw/8YpyI+2+KPtIFzGRl9IYtN3LApFsr7jx",
+	"WRV5VBYcgQPyDXfpi/ILAf8FCfhFeWP69RHYQ0z6nhCJdjtkNPZF4WdSo7qSplQX3v5nItWd9OnXdfkJ",
+	"eZlu4/aPE1Q6+4WEsitFQj7gIHKEFxnBUrVVd4Ro8Wli7bSVtTytXkKF+jGfEpWuxVO8DMC9YTHwXFrl",
+	"Ki2N2WOQyHyOXdrehLhIKXNTU0jpQ7ByWO1PRYm/o7NyAlWkxFVnt9ZpZ9hh0QdY3GZATRVI21/ycuUx",
+	"WVoyMmI84+3HFtyMA170ZMalPscuJiz0CbSF6FbE5NcOk58NNmhYDZofK4Qxt5oo6xdGn+IKfCj/cLvb",
+	"P3nB5rjIjyvejIudsqzTbicot2vFJyClJcfaINcKPz6lJn6mJhLMu/X8SyjynzMUub3Ln5OHwh0BtmwI",
+	"OBya9qRuUvG109TmQ3L3LobObt3M0dBt7MO6GxK9f75Ohy6V9FJiPw/f6oZ4C7K4Rl8EyTmttunKN5XI",
+	"AMszT1uEW1/8FZwGtxqB0SfMtvfhFnwE7QbXLcHr7G2g22Ct4X7nQevVv4gL4QYs6ROkKHQn7EpKMX+S",
+	"y7rgVumDUE0Bhpq+SV/7snQee5XZhVb13OMTt8sxVEoVCPFRsHsBt9IuXO0GQivGcgteePyZZ/iROWrq",
+	"ODhoGlS7WhDeDx8tGJYz5gUJ3PexHXzRkSgOwAgL8iJlwuvIGuLSlMNwqdIpvLVUpV0UK5Yp4/A6wnyD",
+	"aFhpdSlzquscFeEMIaGAhhoJp5nSecfdcrsqnt/G534T7lJA8Z299nfxzUSU9eZuXUgZJiY3AzFgALoL",
+	"MJF09OBHVmDdxEX/McYTFrMUv1QdblJPw4R3C7koFcCTOwU5bmYcReR7RhE/d8YYODK4lmtH440R+jT6",
+	"YGeJKP74M7zv4tHfrs2lZyvuWBCLO7zmpfmmdMOeioZJxxRoNkdXrsrsoP+WfBOVkBP5nJCbHCtHo8FS",
+	"WthXlqmyFGBjlHbFrGJVbRauxBshN8coU3Nh35XQpP/RZzSI0mpeBOOTkXBDhjrsMFZA0i5nhcxsqGWh",
+	"iksapIbwv6N3pUfa0ALguWuL582VlgvWJFWCs6D0BRvFldAUF+ri2OEZDhZ/pGP/rsRf1WV4bdwyhoU0",
+	"MALipBIBU4EfaJnnouzUU1elwEv5XVkiPmKWicoanCixVDljE/jrHBbfTLDQExmB4LKW1mxA0Tpdldld",
+	"3pyrMmvdch3nt6eWNXyxrNZGBd8F7qwWmZCwi4EC8JvRB9XvcT7U+Oeo3T+Xs5nQlG1IFBRQ1sPJjW+9",
+	"VZm1WYHl5uLgd/jf80F58F1T8Bk3F2tEGL0Az3fTy1yLiVvKj/KTvao68/6IullnJDdRzrpNOdO4J6no",
+	"cYeyxLICmewmIfy+jXW7/5l78leK0Pdz/txyFvt21JNQmNd2E3hIOetYw01vR7H4vZOWmrhc/UA/UL5j",
+	"s983Uk+jZj6o7bzd72dpNW9nOKbIK03GKTa4PeUxVidvlZR7CXn3pMdo3ndYC/+T9ar3XLd+PW/BrJ7a",
+	"eFAg3NImeeYwu3pfy2hdd82nyWTHLMsPQyMfkUd9UrTjgzd2J5yYS6kLMcjgBdAjjkOdHDP8LGXgwgeD",
+	"LFtn8Ca1u7NhK3z/+Vm1mqHfvkkrXvy75XthFtclXtr4sJkNUW20X9XVXPN8Z83CW5i0KrBMyxtqZl25",
+	"cA/+SrqFm/LnploktjKQDf00QKkg6PymYmq7TZcYrOOiqp6SXLW7EhmtKm9V2XDd33m0Ttj5GykZTSsf",
+	"VMdodft5qRinQE5JgmuzqyRJJ5jhjjiW3U43hVm4d/4a8RWfGEn13bxu224gNCYooB12sZ3cDvhUbahj",
+	"fhpKuPo+ujEMWa21KK2r4a/VslWxRM58ihxMs6m/uhL22sEHfcT9FGbyhcL/NBSO+5lir7sReMVrI3Yg",
+	"cKThSqtMCECK9IB3mP5BJXnWyN7Rtce0Qg+rrqsOFsOtEPkJzOYLkf9piBz38+ZEroWpl/1U/kyVVpa1",
+	"0/vxRORMaTbjEnzSLdLvUvetk/BrHOsXGv7T0DBt6LWI2Ah9raSiNPwo/PolgeiDJxCtzfXpEgNZW2fX",
+	"+NqhFZ8Ldm/J37MHh4eH98fN7YpPyPSF6Nmw+xQxM/nnHmSb7p3wudhDc9WELQTPhW4Aln03FF7kv53h",
+	"ghlh+8vMQ7fnRv67XeZyyd/LZb0cHcEoxyOM88U/x+vZtuu46TgJN3+cFm5y/zTCu5BwqmrjC2X3jteb",
+	"2HagwGdqueR7RsC5ABk8tsJgjWiIF5aiyDEf2Sht2XQ1ZpO9CQxrJt+zpeClYdCqKFE2wkqGvlTmnstl",
+	"Ouf23eh+73KbHnr6wNYyI/S1TWXjEe0a9rK2pwkHQUwOgdTHnjQD58GgKrfz/evzx0ew1Hl+uyNacZ+z",
+	"P5iLx6geQsLRXJSwvHBcjLlSOpxdH+ae5PYfyI8PXT09OT5xQ7uppW2ttQ9rcUt1/5k795EeNzocQNJY",
+	"DsZBJ/G3UPM5XUBU8Kup8ZEkxp/FLeS1b2daH83PiYtwY/hYWtrBG1YJvZQI0WmGhTaeHDeLYFj0eQPA",
+	"3gyB8SxTOtbzPSA14rOPGzmqjkKqs0LCIsInBqAaAhxtjNyP/b/ro5STZlwfCgzhlR9G0/fn4Cxa27KY",
+	"IgZRkBYYYZsNQd7wRUeij5ixSkeb36bgbok//9mHrTzre/08N7RZ65039PcLsRocktV8mOQF0xW7EKsN",
+	"ezosvOEfYhWJ8+7TtOmCuvtgwVZ3v7+vm7Txni3eAA7fz9RvunEDDAIfftduqbJmc/Q/mmDQDOI2xYMt",
+	"RFPVwxSP7fRDJohLXtRRaSlertjfT1+9pOxbeghGgpAPUwYsr/iu4BpsMktpmwsjqppf0xakawX7Rk7q",
+	"j06sd1FfO6LTm5XYbhP8h6yyfb2jlmDRb7Uq58Czxo6ylCaqYeI9+J1E/sFiGHY9eIn7WHMrcPSDBHR4",
+	"myZrGG5GU1SqNQg4Z4iDVod0+iWTmDkH5uZ87HMDvTROR5fnvKIpVWSL3mcvLsHM6k+lXzrGC6OoXuE/",
+	"915jIspS2j3838m4/eNrAU5tWc4nOKz2s7oQ3oxnxoE9aOFS+yljEdwjjx5+O8Ecw9pQv6+F1au9pzMr",
+	"dLBn9moMoccPVlzLd/hZCpURmW0n4u2R/V1QHOyjA4WTjMCGF3cP0nfKxScfoH/3wam3EJDvZcROHPUw",
+	"8TOEW/lW+oPt4Y0dA+3vfp+/GJ7sFgJIM4MDNAQNsxe6tHC8sRBVV2a8wBB+pbleUdkzbM90Nj5BQPDa",
+	"QCfmJ0pJg0s34lzvGJUzMABpjShmHwucM3a20f3U0MRwv8pZICn6MpREi8tyjqmu7ES8r6QW5pzbCflT",
+	"qVZuMHKS3wVUndoqMGtnWPnIFaftkUNwy1oRzR+NQu+mPKWjyRvXqPTtfPBClVHHn5dTBwfeouhhTgLH",
+	"r5WthtoBz6BKtxGZFq0z2R/7nzwKr85OPkXBbjAhVRrmZCV9vRQGAZYSHQX+7KKwbpFBfwRu/FoYYcNe",
+	"sIYUBhuZfnCu6o3EBPRbG8E4vWVEpsqczXhmle4pCB+EA4eEQ1pjoph+MiSve9GBNPJrbSxTZSZ8nYxS",
+	"kLatwyK4kVvFRAnRW0xaxuc8XWbVkf0AC9VnKuS+Oju5vQoluBsRhTNF5CZNKIJEa/7hzD0vaI8HUX8P",
+	"n/2tVpYP5bT4srf2XJ/V/jc080WLvhtHDRIC7ep1fDN9WxwDR6FZBgH8UqnGEdcaXzvXzlPJn1MBx6m9",
+	"wQv6VtnT+NMqX9CQoqOANuTjtXw/t8mCPvLFd/saD87qhtqOb+ODajpRp58tDz5do/rBl3AL5XRYIbBL",
+	"4Q0GxG+Ng5sUOeNTVRPu8NVCaLEVv/QvYJ5qY5b+JS1UvXi4OxisTj3IqWkpRoEgW50YlgvIZ4iCrYws",
+	"L/wHPM811iUCh/ykVLk4z9VV6Vxy+PcV1+Slc9TOtbhuPn+f+hMTxp/VDtYB7L0RsvYa9u8HhAa9Tdzh",
+	"j4Ev4A/P2tkZDhQcXxoEEbSrR8PZgxtgl44U1eDVkCLZFPGVZVbUfZH7mBPxF7hIIpigv+Qt0pDN7ukj",
+	"SOp8GROfw3Jph2XL0ljB81a/TRoJZZvgwBkMnC6QmGRb6WZ4SygEL6AvJ5YytTA3ap89JXERB0AZT80R",
+	"oEISvFwFRwwMlheFujLeIOhHSNcOCF4XpboqmwFzE2pO8QxLQph4yL5i1BUGH2SqErl/gmOCk7fglxB0",
+	"kgtNb5i+2wwp8896jcUIXTfN14mQvj5sps7tQIx9vBydHYHBLpUV18oI/kWlQIbh1y8ZwX/OkoKwt58T",
+	"2pmnUE/88LcjfgcUBQfAVWTor+3wVkxPVXYhLIN124Ndzhl577D0krBXQpQtkDOs/rB2Np7R02euwxtu",
+	"2VYXIdxgbnJSYYa3MFDjQZqF938kGNdxmSmtgbZb7OvbJMYFFpgYN0HXkaPF9RzFmtLsaTsbpkWvdV9q",
+	"Ns0tmtu3pbAcNo27Ehr9u3YW+fkYn4vSskpoqfIQdQGnyoABxi6EbvkFYR6yBOEhxLzKvGieOwUWvYZs",
+	"Ao/OrVwKVdtJc+q1KASQCokS601jzQqch8BwESPQXzXJqhr/5lPvL8CltQstzEIVeUqyeOqW4w4zcv0E",
+	"fFc7XM03jtt282FTmFDDSDqiEipAQHeWXHysFBaKeMGqhuXHjTuunua5ht9/vMJ/dULdIqdN5SGRGlry",
+	"C9DQ6M/C8ufc8g6Rwi0wTPtbcBMqmIWOoBlogt3L1HIqQVQmigMlMYau9S/eX6MLGNQPAqPbtt7Gzjbq",
+	"8sUdhkvP5esfNqTjC6gfjZCsxiNR1ktYm/Dn5ehfAwq2P81zCf/khcdA8DLBhVgxEHvx3NAI/kuUl5Oe",
+	"MdLXd4p70OXA20Te9feteG8PqoLL8n+xbMG1Efa/ajvbe5L8MAx81xv3Tg5KT+joP/w29Z0p73pao/OB",
+	"BwoSEM+hhyHIjZ4/rffW0BMmhPQdnSGJQdiKO72hQ99qOkPo3P0yXGUbfzmwXw7sRzmwZ6+ev2JwapGk",
+	"Xe4ncwS85cTKXJRW2hWZQnsP7LExHivNLJS2ewVWuXp1/PyZM7Xce3H68PHX7O9vz+4zbowg7a0ttLm+",
+	"jnyROtQh5XpxCIA5C7ESBZdLsqDC+lLS0doXOCh2/HxMEOHj6CKGVwm17QzLplmhSywsZUClcuLgpdBy",
+	"toost6Hk1IVYGYdVBIAtX2W+7Oi5n86+hMXR+FQc7F+JothDA9aBqkQp871WpuMkSKxhXXIlDBRYo1C0",
+	"BeqjVmYs0yKnMlimHR+3TImYx244aB4ZwBif1rmM8z8d7r0q8Rc/5qaYahmQHmdSYxAdkppnU2mewV0n",
+	"o48VJvLW7dbtGI18a4GUgXpzaaiQnWOrfs4e/BKNna2AtruXi9fu8DDgrsGphy+QGXiYcFw57CtRZnpV",
+	"WZEHv4cRZdAcG/kcXSghIm5Jp+t7aRZ4NLBYMDUQ62qurl6wRScLBeJXjTx99xaXiAdR57dgfvkQWhOu",
+	"ZhTt2tR79k0OJpELQUp+vU3Hn4cg4X8+fPz4wbfulpLaUcRcGiu0I6l6WsiM8oGTSa2O1tamIF1xaGg7",
+	"oh5qu+XL6E4YRwErNl21f7ccLoS4bvQqK6iQdT8R/kOsKDTqTrzRvosPqePDikaCY2RJapb3A1Mxddve",
+	"Lcc5GgLaTsiXcIENY3VTpayxmleB6LCKeqyzGDRvtu9NNsE+zv17E3e/3qcQI1mlWaFdtKl4jQeys4Uy",
+	"TkbxPuYmukOW7aXxvaeo9hcY3xdLxIdTbD5nRaX3Mlk/Hh2bWc9hhAaFvkyT289clugrS8dJU60TaA1f",
+	"6rJ1MxqPal2MjkYLaytzdHBwuI//f/Tk8PDBweiPf/3x/w0AtIlnMT0RAwA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,47 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package openapi
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/adobe/aquarium-fish/lib/tracing"
+)
+
+// tracingMiddleware creates server span for each API request, the W3C traceparent header is used
+// to continue the client trace
+func tracingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !tracing.Enabled() {
+			return next(c)
+		}
+		req := c.Request()
+		ctx := req.Context()
+		if parent, err := tracing.ParseTraceparent(req.Header.Get("traceparent")); err == nil {
+			ctx = tracing.ContextWithSpan(ctx, parent)
+		}
+		ctx, span := tracing.Start(ctx, req.Method+" "+c.Path(),
+			tracing.Attr("http.method", req.Method),
+			tracing.Attr("http.route", c.Path()),
+			tracing.Attr("net.peer.ip", c.RealIP()),
+		)
+		span.SetKind(tracing.KindServer)
+		defer span.Finish()
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+		span.SetAttributes(tracing.Attr("http.status_code", c.Response().Status))
+		span.SetError(err)
+		return err
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
 )
 
 // GormDataType describes how to store Authentication in database
@@ -31,11 +33,35 @@ func (auth *Authentication) Scan(value any) error {
 		return fmt.Errorf("Failed to unmarshal JSONB value: %s", value)
 	}
 
-	err := json.Unmarshal(bytes, auth)
-	return err
+	if err := json.Unmarshal(bytes, auth); err != nil {
+		return err
+	}
+	return auth.decrypt()
 }
 
 // Value converts json bytes to Authentication
 func (auth Authentication) Value() (driver.Value, error) {
+	if err := auth.encrypt(); err != nil {
+		return nil, err
+	}
 	return json.Marshal(auth)
 }
+
+// encrypt the sensitive fields to store them in database, the plaintext stays if the node
+// master key is not set
+func (auth *Authentication) encrypt() (err error) {
+	if auth.Password, err = crypt.EnvelopeEncrypt(auth.Password); err != nil {
+		return err
+	}
+	auth.Key, err = crypt.EnvelopeEncrypt(auth.Key)
+	return err
+}
+
+// decrypt the sensitive fields stored in database
+func (auth *Authentication) decrypt() (err error) {
+	if auth.Password, err = crypt.EnvelopeDecrypt(auth.Password); err != nil {
+		return err
+	}
+	auth.Key, err = crypt.EnvelopeDecrypt(auth.Key)
+	return err
+}
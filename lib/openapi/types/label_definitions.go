@@ -36,6 +36,9 @@ func (ld *LabelDefinitions) Scan(value any) error {
 		if r.Resources.NodeFilter == nil {
 			(*ld)[i].Resources.NodeFilter = []string{}
 		}
+		if r.Authentication != nil && err == nil {
+			err = r.Authentication.decrypt()
+		}
 	}
 	return err
 }
@@ -48,5 +51,17 @@ func (ld LabelDefinitions) Value() (driver.Value, error) {
 			ld[i].Resources.NodeFilter = []string{}
 		}
 	}
-	return json.Marshal(ld)
+	// Copy to not touch the definitions of the caller with encrypted Authentication
+	out := make(LabelDefinitions, len(ld))
+	copy(out, ld)
+	for i, r := range out {
+		if r.Authentication != nil {
+			auth := *r.Authentication
+			if err := auth.encrypt(); err != nil {
+				return nil, err
+			}
+			out[i].Authentication = &auth
+		}
+	}
+	return json.Marshal(out)
 }
@@ -17,8 +17,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
-
-	"github.com/adobe/aquarium-fish/lib/util"
 )
 
 // GormDataType describes how to store Resources in database
@@ -52,24 +50,37 @@ func (r Resources) Value() (driver.Value, error) {
 	return json.Marshal(r)
 }
 
-// Validate makes sure the Resources are defined correctly
-func (r *Resources) Validate(diskTypes []string, checkNet bool) error {
+// Validate makes sure the Resources are defined correctly and could be provided by the driver
+// with the capabilities
+func (r *Resources) Validate(caps DriverCapabilities) error {
 	// Check resources
 	if r.Cpu < 1 {
-		return fmt.Errorf("Resources: Number of CPU cores is less then 1")
+		return fmt.Errorf("Resources: Number of CPU threads is less then 1")
 	}
 	if r.Ram < 1 {
-		return fmt.Errorf("Resources: Amount of RAM is less then 1GB")
+		return fmt.Errorf("Resources: Amount of RAM is less then 1GiB")
+	}
+	if len(r.Disks) > 0 && !caps.Disks {
+		return fmt.Errorf("Resources: Additional disks are not supported by the driver")
 	}
 	for name, disk := range r.Disks {
 		if name == "" {
 			return fmt.Errorf("Resources: Disk name can't be empty")
 		}
-		if len(diskTypes) > 0 && !util.Contains(diskTypes, disk.Type) {
-			return fmt.Errorf("Resources: Type of disk must be one of: %+q", diskTypes)
+		if !matchAny(caps.DiskTypes, disk.Type) {
+			return fmt.Errorf("Resources: Type of disk %q must be one of: %+q", name, caps.DiskTypes)
 		}
 		if disk.Size < 1 {
-			return fmt.Errorf("Resources: Size of the disk can't be less than 1GB")
+			return fmt.Errorf("Resources: Size of the disk %q can't be less than 1GiB", name)
+		}
+		if disk.Clone != "" && !caps.DiskClone {
+			return fmt.Errorf("Resources: Clone of the disk %q is not supported by the driver", name)
+		}
+		if disk.Reuse && !caps.DiskReuse {
+			return fmt.Errorf("Resources: Reuse of the disk %q is not supported by the driver", name)
+		}
+		if disk.Clone != "" && disk.Reuse {
+			return fmt.Errorf("Resources: Disk %q can't be cloned and reused at the same time", name)
 		}
 	}
 	if len(r.NodeFilter) > 0 {
@@ -81,13 +92,29 @@ func (r *Resources) Validate(diskTypes []string, checkNet bool) error {
 			}
 		}
 	}
-	if checkNet && r.Network != "" && r.Network != "nat" {
-		return fmt.Errorf("Resources: The network configuration must be either '' (empty for hostonly) or 'nat'")
+	if !matchAny(caps.Networks, r.Network) {
+		return fmt.Errorf("Resources: The network configuration must be one of: %+q", caps.Networks)
+	}
+	if (r.CpuOverbook || r.RamOverbook) && !r.Multitenancy {
+		return fmt.Errorf("Resources: Overbook is used only along with multitenancy")
+	}
+	if r.Multitenancy && !caps.Tenancy {
+		return fmt.Errorf("Resources: Multitenancy is not supported by the driver")
 	}
 
 	return nil
 }
 
+// matchAny checks the value matches at least one of the patterns
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Add increases the Resources utilization by provided Resources
 func (r *Resources) Add(res Resources) error {
 	if r.Cpu == 0 && r.Ram == 0 {
@@ -0,0 +1,2364 @@
+// Package types provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package types
+
+import (
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/util"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+const (
+	Basic_authScopes = "basic_auth.Scopes"
+)
+
+// Defines values for ApplicationStatus.
+const (
+	ApplicationStatusALLOCATED       ApplicationStatus = "ALLOCATED"
+	ApplicationStatusDEALLOCATE      ApplicationStatus = "DEALLOCATE"
+	ApplicationStatusDEALLOCATED     ApplicationStatus = "DEALLOCATED"
+	ApplicationStatusELECTED         ApplicationStatus = "ELECTED"
+	ApplicationStatusERROR           ApplicationStatus = "ERROR"
+	ApplicationStatusHOLD            ApplicationStatus = "HOLD"
+	ApplicationStatusNEW             ApplicationStatus = "NEW"
+	ApplicationStatusPENDINGAPPROVAL ApplicationStatus = "PENDING_APPROVAL"
+	ApplicationStatusRECALLED        ApplicationStatus = "RECALLED"
+)
+
+// Defines values for DriverInfoFieldType.
+const (
+	DriverInfoFieldTypeBoolean DriverInfoFieldType = "boolean"
+	DriverInfoFieldTypeInteger DriverInfoFieldType = "integer"
+	DriverInfoFieldTypeNumber  DriverInfoFieldType = "number"
+	DriverInfoFieldTypeString  DriverInfoFieldType = "string"
+)
+
+// Defines values for NodeTelemetryStatus.
+const (
+	NodeTelemetryStatusOK      NodeTelemetryStatus = "OK"
+	NodeTelemetryStatusWARNING NodeTelemetryStatus = "WARNING"
+)
+
+// Defines values for ProjectMemberRole.
+const (
+	ProjectMemberRoleMaintainer ProjectMemberRole = "maintainer"
+	ProjectMemberRoleMember     ProjectMemberRole = "member"
+)
+
+// Defines values for LabelImportPostParamsOnConflict.
+const (
+	LabelImportPostParamsOnConflictNewVersion LabelImportPostParamsOnConflict = "new_version"
+	LabelImportPostParamsOnConflictOverwrite  LabelImportPostParamsOnConflict = "overwrite"
+)
+
+// Defines values for DataGetListParamsFormat.
+const (
+	DataGetListParamsFormatEnv  DataGetListParamsFormat = "env"
+	DataGetListParamsFormatJson DataGetListParamsFormat = "json"
+)
+
+// Defines values for DataGetParamsFormat.
+const (
+	DataGetParamsFormatEnv  DataGetParamsFormat = "env"
+	DataGetParamsFormatJson DataGetParamsFormat = "json"
+)
+
+// Defines values for VaultGetListParamsFormat.
+const (
+	VaultGetListParamsFormatEnv  VaultGetListParamsFormat = "env"
+	VaultGetListParamsFormatJson VaultGetListParamsFormat = "json"
+)
+
+// AccessMethod The way the user could reach the allocated Resource, the Resource could be reachable by a number of methods simultaneously.
+type AccessMethod struct {
+	// Credentials How to get the credentials for the method: `resource_access` - request the Resource access, `token` - the gate uses its own authentication
+	Credentials string `json:"credentials"`
+
+	// Endpoint Where to connect to use the access method (address or URL)
+	Endpoint string `json:"endpoint"`
+
+	// HostKeyFingerprint SHA256 fingerprint of the endpoint host key to pin it, empty if not applicable
+	HostKeyFingerprint string `json:"host_key_fingerprint"`
+
+	// Type Type of the access method: `ssh` - interactive shell through the ssh proxy, `port_forward` - ssh proxy port forwarding, `<gate name>` - access provided by the gate (like `terminal` for the web terminal)
+	Type string `json:"type"`
+}
+
+// Application Resource request created by the user. Each node votes for the availability to allocate the resource and the cluster choose which one node will actually do the work.
+type Application struct {
+	UID ApplicationUID `gorm:"primaryKey" json:"UID"`
+
+	// BatchUID UID of the batch the Application was created by, empty if created alone
+	BatchUID  openapi_types.UUID `gorm:"index" json:"batch_UID" yaml:"batch_UID"`
+	CreatedAt time.Time          `gorm:"index" json:"created_at"`
+
+	// DependsInject Adds the dependencies Resources addresses to the Resource metadata as `FISH_DEPENDENCY_<index>_IP` and `FISH_DEPENDENCY_<index>_ID` (short ID of the dependency Application), where index is the position in `depends_on` list
+	DependsInject bool `json:"depends_inject"`
+
+	// DependsOn List of the Application UIDs the Application depends on - the Application is not elected until all of them are ALLOCATED and fails if one of them is not active anymore. Useful to create client/server test topologies.
+	DependsOn ApplicationDependencies `json:"depends_on"`
+	LabelUID  openapi_types.UUID      `gorm:"index" json:"label_UID" yaml:"label_UID"`
+
+	// Metadata Additional metadata in JSON format (can't override Label metadata)
+	Metadata  util.UnparsedJSON `json:"metadata"`
+	OwnerName string            `gorm:"index" json:"owner_name"`
+
+	// Priority When the nodes capacity is contended the Applications with higher priority are elected first. 0 uses the default priority of the Label or the User role, only admin and operator can set priority higher than allowed by `priority.user_max` node config.
+	Priority int `json:"priority"`
+
+	// Project Project of the Application Label, empty for the shared Labels
+	Project string `gorm:"index" json:"project"`
+
+	// Seed Random seed of the Application, used by the scheduler election and the test driver to make the Application processing reproducible. 0 generates the random one, set the seed of the failed CI run (shown in NEW state) to replay it locally. The Resource gets it in `FISH_SEED` metadata.
+	Seed int64 `json:"seed"`
+
+	// ShortId Human-friendly unique identifier of the Application, could be used in API instead of UID in the Application paths and `application_UID` field of the request body. Generated by the Fish node on Application creation.
+	ShortId string `gorm:"uniqueIndex" json:"short_id"`
+
+	// Template Name of the template to create the Application from, the template sets the Label if `label_UID` is not provided, the default metadata and priority
+	Template string `json:"template"`
+}
+
+// ApplicationAnnotation Business-level status of the Application set by the gate driver (like "CI job 1234 running"), it's shown alongside the infrastructure state. The annotation is identified by the source and the key, so setting it again replaces the previous value.
+type ApplicationAnnotation struct {
+	UID            ApplicationAnnotationUID `gorm:"primaryKey" json:"UID"`
+	ApplicationUID openapi_types.UUID       `gorm:"uniqueIndex:idx_application_annotation" json:"application_UID" yaml:"application_UID"`
+	CreatedAt      time.Time                `json:"created_at"`
+
+	// Data JSON object with the structured details of the status
+	Data util.UnparsedJSON `json:"data"`
+
+	// Key Identifier of the annotation within the source
+	Key string `gorm:"uniqueIndex:idx_application_annotation" json:"key"`
+
+	// Source Name of the gate which set the annotation
+	Source string `gorm:"uniqueIndex:idx_application_annotation" json:"source"`
+
+	// Text Human-readable status
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ApplicationAnnotationUID defines model for ApplicationAnnotationUID.
+type ApplicationAnnotationUID = openapi_types.UUID
+
+// ApplicationBatch Request of the number of identical Applications, useful for the CI pipelines which need multiple shards at once. The gang batch is all-or-nothing - if one of the Applications fails or the whole batch is not allocated in `gang_timeout`, all of them are deallocated.
+type ApplicationBatch struct {
+	UID ApplicationBatchUID `gorm:"primaryKey" json:"UID"`
+
+	// Count Number of the Applications to create
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Gang All the Applications should be allocated or none of them
+	Gang bool `json:"gang"`
+
+	// GangTimeout Time to wait for the gang batch to be completely allocated, after that the batch is deallocated. Empty value means wait forever.
+	GangTimeout util.Duration      `json:"gang_timeout"`
+	LabelUID    openapi_types.UUID `json:"label_UID" yaml:"label_UID"`
+
+	// Metadata Metadata of the Applications
+	Metadata util.UnparsedJSON `json:"metadata"`
+
+	// NodeUID The Node created the batch, it watches the gang batch
+	NodeUID   openapi_types.UUID `json:"node_UID" yaml:"node_UID"`
+	OwnerName string             `json:"owner_name"`
+
+	// Priority Priority of the Applications
+	Priority int `json:"priority"`
+
+	// Template Name of the template to create the Applications from
+	Template string `json:"template"`
+}
+
+// ApplicationBatchState The summary of the batch Applications states
+type ApplicationBatchState struct {
+	BatchUID openapi_types.UUID `json:"batch_UID"`
+
+	// Counts Number of the Applications per status
+	Counts map[string]int `json:"counts"`
+
+	// States Current state of each Application in the batch
+	States []ApplicationState `json:"states"`
+
+	// Total Number of the Applications in the batch
+	Total int `json:"total"`
+}
+
+// ApplicationBatchUID defines model for ApplicationBatchUID.
+type ApplicationBatchUID = openapi_types.UUID
+
+// ApplicationDeallocateBatch Request to deallocate the Applications matching the selector
+type ApplicationDeallocateBatch struct {
+	// DryRun Only return the matching Applications without deallocating them
+	DryRun *bool `json:"dry_run,omitempty"`
+
+	// Selector Selects the Applications by the common properties, the empty ones are ignored
+	Selector ApplicationSelector `json:"selector"`
+}
+
+// ApplicationDeallocateBatchResult Result of the Applications batch deallocate
+type ApplicationDeallocateBatchResult struct {
+	// Deallocated UIDs of the Applications requested to deallocate (or matched on dry run)
+	Deallocated []ApplicationUID `json:"deallocated"`
+
+	// Failed UIDs of the Applications failed to deallocate with the reason
+	Failed map[string]string `json:"failed"`
+}
+
+// ApplicationDependencies List of the Application UIDs the Application depends on - the Application is not elected until all of them are ALLOCATED and fails if one of them is not active anymore. Useful to create client/server test topologies.
+type ApplicationDependencies []openapi_types.UUID
+
+// ApplicationExtend Request to extend the Resource lifetime
+type ApplicationExtend struct {
+	// Duration Time Duration (ex. "2h") to add to the current Resource lifetime deadline, can't be more than the Label definition `max_extension`
+	Duration string `json:"duration"`
+
+	// Reason Why the extension is needed, stored in the state description
+	Reason *string `json:"reason,omitempty"`
+}
+
+// ApplicationFull The Application joined with the related objects
+type ApplicationFull struct {
+	// Application Resource request created by the user. Each node votes for the availability to allocate the resource and the cluster choose which one node will actually do the work.
+	Application  Application `json:"application"`
+	LabelName    string      `json:"label_name"`
+	LabelVersion int         `json:"label_version"`
+
+	// Resource Active resource definition to be able to properly restore the state during the cluster node restart. Also contains additional info about the resource, for example user requested metadata, which is available for the resource through the `Meta API`.
+	// Could be created and updated only by the node which won the Application execution.
+	Resource *Resource `json:"resource,omitempty"`
+
+	// State The state of the Application - it goes through graph of states, so this object stores the info when the Application comes to this state and the last one describes the current state of the Application.
+	State ApplicationState `json:"state"`
+}
+
+// ApplicationSecret The secret encrypted by the client with the Resource public key, Fish only relays it to the Resource and removes when it's received.
+type ApplicationSecret struct {
+	UID            ApplicationSecretUID `gorm:"primaryKey" json:"UID"`
+	ApplicationUID openapi_types.UUID   `gorm:"index" json:"application_UID" yaml:"application_UID"`
+
+	// Ciphertext Base64-encoded NaCl sealed box of the secret
+	Ciphertext string    `json:"ciphertext"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Name Name of the secret to help the Resource to identify it
+	Name string `json:"name"`
+}
+
+// ApplicationSecretUID defines model for ApplicationSecretUID.
+type ApplicationSecretUID = openapi_types.UUID
+
+// ApplicationSelector Selects the Applications by the common properties, the empty ones are ignored
+type ApplicationSelector struct {
+	LabelUID *openapi_types.UUID `json:"label_UID,omitempty" yaml:"label_UID"`
+
+	// NodeUID Node which allocated the Applications Resources
+	NodeUID *openapi_types.UUID `json:"node_UID,omitempty" yaml:"node_UID"`
+
+	// OlderThan Time Duration (ex. "24h") since the Applications creation
+	OlderThan *string `json:"older_than,omitempty"`
+
+	// OwnerName Name of the Applications owner
+	OwnerName *string `json:"owner_name,omitempty"`
+
+	// Project Project of the Applications
+	Project *string            `json:"project,omitempty"`
+	Status  *ApplicationStatus `json:"status,omitempty"`
+}
+
+// ApplicationState The state of the Application - it goes through graph of states, so this object stores the info when the Application comes to this state and the last one describes the current state of the Application.
+type ApplicationState struct {
+	UID            ApplicationStateUID `gorm:"primaryKey" json:"UID"`
+	ApplicationUID openapi_types.UUID  `gorm:"index:idx_application_state_latest,priority:1" json:"application_UID" yaml:"application_UID"`
+	CreatedAt      time.Time           `gorm:"index:idx_application_state_latest,priority:2" json:"created_at"`
+
+	// Description Additional information for the state
+	Description string `json:"description"`
+
+	// ExpiresAt The new Resource lifetime deadline, set only by the lifetime extension. The latest one overrides the deadline calculated from the Label definition lifetime.
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	Status    ApplicationStatus `json:"status"`
+}
+
+// ApplicationStateEvent The ApplicationState change sent by the stream
+type ApplicationStateEvent struct {
+	// Annotation Business-level status of the Application set by the gate driver (like "CI job 1234 running"), it's shown alongside the infrastructure state. The annotation is identified by the source and the key, so setting it again replaces the previous value.
+	Annotation *ApplicationAnnotation `json:"annotation,omitempty"`
+
+	// ObjectSeq Sequence number of the state of this Application, starts from 1 with the NEW state and increases with every next state
+	ObjectSeq uint64 `json:"object_seq"`
+
+	// Seq Sequence number of the event on this node, increases with every state change of any Application and continues from the node start time in microseconds after restart
+	Seq uint64 `json:"seq"`
+
+	// State The state of the Application - it goes through graph of states, so this object stores the info when the Application comes to this state and the last one describes the current state of the Application.
+	State ApplicationState `json:"state"`
+
+	// Task Is needed to execute some sort of async action on the Application. For example snapshot operation, because the request could get from anywhere. Usually app tasks are not a part of standard Application state graph and could be not executed if the resource is destroyed.
+	// Results are filled by the executor of the task and really depends on what kind of operation is executed and really depends on the driver - it could support the task or not.
+	// It could be created by any Node but updated by the one which won the Application execution.
+	Task *ApplicationTask `json:"task,omitempty"`
+
+	// TaskOutput The progress line reported by the executing task
+	TaskOutput *string `json:"task_output,omitempty"`
+}
+
+// ApplicationStateUID defines model for ApplicationStateUID.
+type ApplicationStateUID = openapi_types.UUID
+
+// ApplicationStatus defines model for ApplicationStatus.
+type ApplicationStatus string
+
+// ApplicationTask Is needed to execute some sort of async action on the Application. For example snapshot operation, because the request could get from anywhere. Usually app tasks are not a part of standard Application state graph and could be not executed if the resource is destroyed.
+// Results are filled by the executor of the task and really depends on what kind of operation is executed and really depends on the driver - it could support the task or not.
+// It could be created by any Node but updated by the one which won the Application execution.
+type ApplicationTask struct {
+	UID            ApplicationTaskUID `gorm:"primaryKey" json:"UID"`
+	ApplicationUID openapi_types.UUID `json:"application_UID" yaml:"application_UID"`
+
+	// At Not earlier than this time the ALLOCATED task will be executed, the teardown tasks are not waiting for it because the Resource will be gone
+	At        *time.Time `json:"at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Interval Repeats the ALLOCATED task with this period (like periodic snapshot) until the Application is deallocated, the result contains the latest execution
+	Interval *string `json:"interval,omitempty"`
+
+	// Options JSON object with additional options
+	Options util.UnparsedJSON `json:"options"`
+
+	// Result JSON object with the results of task execution
+	Result util.UnparsedJSON `json:"result"`
+
+	// Retries How many times to retry the task if it failed, by default 0
+	Retries *int `json:"retries,omitempty"`
+
+	// Task Identifier of the task, the generic tasks (`snapshot`, `image`, `suspend`, `resume` and `reboot`) have the same meaning for all the drivers implementing them, which are listed in the Resource `tasks` and the driver capabilities
+	Task      string            `json:"task"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	When      ApplicationStatus `json:"when"`
+}
+
+// ApplicationTaskUID defines model for ApplicationTaskUID.
+type ApplicationTaskUID = openapi_types.UUID
+
+// ApplicationTimelineItem The Application state or task with the time it took
+type ApplicationTimelineItem struct {
+	// Description Description of the state or the result of the task
+	Description string `json:"description"`
+
+	// Duration Seconds between start and finish or until now if it's not finished yet
+	Duration float64 `json:"duration"`
+
+	// FinishedAt When the next state was set or the task was completed, unset if not finished
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Kind Type of the item - `state` or `task`
+	Kind string `json:"kind"`
+
+	// Name Status of the Application or the name of the task
+	Name string `json:"name"`
+
+	// StartedAt When the state was set or the task was created
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ApplicationUID defines model for ApplicationUID.
+type ApplicationUID = openapi_types.UUID
+
+// AuditRecord Record of the audit log about the mutating request. The log is append-only, the records are removed only when `audit_retention` of the node config is reached.
+type AuditRecord struct {
+	UID AuditRecordUID `gorm:"primaryKey" json:"UID"`
+
+	// Action Method and route of the request or admin SSH command
+	Action string `json:"action"`
+
+	// AuthMethod How the requester was authenticated - `basic` for API users, `token` for API users with UserToken, `ssh_cert` for admin SSH and `resource_ip` for the Resource Meta API requests
+	AuthMethod string `json:"auth_method"`
+
+	// Changes Changed fields of the object with values before and after the request, the sensitive fields (like password hash or metadata) are shown as `<redacted>`
+	Changes   util.UnparsedJSON `json:"changes"`
+	CreatedAt time.Time         `gorm:"index" json:"created_at"`
+
+	// NodeUID Node which processed the request
+	NodeUID openapi_types.UUID `json:"node_UID" yaml:"node_UID"`
+
+	// ObjectId UID or name of the changed object
+	ObjectId string `json:"object_id"`
+
+	// ObjectType Type of the changed object, empty if the request was rejected
+	ObjectType string `json:"object_type"`
+
+	// SourceIp Address the request came from
+	SourceIp string `json:"source_ip"`
+
+	// Status Response HTTP status code of the request
+	Status int `json:"status"`
+
+	// UserName Who made the request, empty for the Resource requests
+	UserName string `gorm:"index" json:"user_name"`
+}
+
+// AuditRecordUID defines model for AuditRecordUID.
+type AuditRecordUID = openapi_types.UUID
+
+// Authentication Authentication information to enable connecting to the machine. The fields could contain `secret://<provider>/<path>[#<key>]` references to the node secrets providers, they are resolved by the Node only to connect to the Resource. The password and key are encrypted in the database by the Node master key and returned in Label only to admin or operator.
+type Authentication struct {
+	// Key SSH private key to use instead of password to login into the Resource.
+	Key string `json:"key"`
+
+	// Password The password to login into the Resource.
+	Password string `json:"password"`
+
+	// Port TCP port to connect with SSH client.
+	Port int `json:"port"`
+
+	// Username The username to login into the Resource.
+	Username string `json:"username"`
+}
+
+// Backup Consistent snapshot of the Node database
+type Backup struct {
+	// CreatedAt Time of the snapshot, used to find the backup for the point in time restore
+	CreatedAt time.Time `json:"created_at"`
+
+	// Name File name of the backup
+	Name string `json:"name"`
+
+	// Size Size of the backup file in bytes
+	Size int64 `json:"size"`
+}
+
+// ClusterCapacity defines model for ClusterCapacity.
+type ClusterCapacity struct {
+	// Labels Label slots summed across the Nodes
+	Labels []LabelCapacity `json:"labels"`
+
+	// Nodes Capacity published by the active Nodes
+	Nodes []NodeCapacity `json:"nodes"`
+}
+
+// DefinitionCapacity defines model for DefinitionCapacity.
+type DefinitionCapacity struct {
+	Driver string `json:"driver"`
+
+	// Index Index of the Label definition
+	Index  int  `json:"index"`
+	Remote bool `json:"remote"`
+
+	// Slots Capacity reported by the driver, negative on the driver error
+	Slots int64 `json:"slots"`
+}
+
+// DriverCapabilities Describes which Resources the driver could provide, the lists contain `path.Match` patterns of the supported values.
+type DriverCapabilities struct {
+	// DiskClone Disk could be cloned from the snapshot
+	DiskClone bool `json:"disk_clone"`
+
+	// DiskReuse Disk could be kept and reused by the next Resource
+	DiskReuse bool `json:"disk_reuse"`
+
+	// DiskTypes Supported disk types, the meaning is driver-specific (filesystem or volume type)
+	DiskTypes []string `json:"disk_types"`
+
+	// Disks Additional disks could be attached
+	Disks bool `json:"disks"`
+
+	// Networks Supported network values
+	Networks []string `json:"networks"`
+
+	// Tasks Generic ApplicationTasks the driver implements, filled by the Node
+	Tasks []string `json:"tasks"`
+
+	// Tenancy Multitenancy and overbook modificators are taken into account
+	Tenancy bool `json:"tenancy"`
+}
+
+// DriverCapacity defines model for DriverCapacity.
+type DriverCapacity struct {
+	Name string `json:"name"`
+
+	// Remote The driver manages the out-of-node resources
+	Remote bool `json:"remote"`
+}
+
+// DriverForecast Capacity forecast of the driver pools
+type DriverForecast struct {
+	Pools []DriverPoolForecast `json:"pools"`
+}
+
+// DriverInfoField Describes one field of the Resource driver info
+type DriverInfoField struct {
+	// Description Human-readable description of the field
+	Description string `json:"description"`
+
+	// Name Key of the field in driver info
+	Name string `json:"name"`
+
+	// Required The field is always set by the driver
+	Required bool `json:"required"`
+
+	// Type Type of the field value
+	Type DriverInfoFieldType `json:"type"`
+}
+
+// DriverInfoFieldType Type of the field value
+type DriverInfoFieldType string
+
+// DriverInfoSchema List of the fields of driver info the driver fills in the allocated Resource.
+type DriverInfoSchema = []DriverInfoField
+
+// DriverPoolForecast State of the driver pool hosts and the estimation when the capacity will free up, the hosts are counted once by the first matching state in the order of the fields
+type DriverPoolForecast struct {
+	// Available Hosts with the free capacity
+	Available uint `json:"available"`
+
+	// FreeSlots Capacity slots available right now including the hosts the pool could allocate
+	FreeSlots int64 `json:"free_slots"`
+
+	// InstanceType Type of the instances the pool hosts could run
+	InstanceType string `json:"instance_type"`
+
+	// Name Name of the pool in the driver config
+	Name string `json:"name"`
+
+	// NextCapacityAt Estimated time when the next capacity slot will free up, not set if there are free slots already or nothing is expected to free up
+	NextCapacityAt *time.Time `json:"next_capacity_at,omitempty"`
+
+	// PendingRelease Idle hosts scheduled for release or scrubbing
+	PendingRelease uint `json:"pending_release"`
+
+	// Scrubbing Hosts in the cleanup process after the instance, not available until it's done
+	Scrubbing uint `json:"scrubbing"`
+
+	// Used Hosts running the Resources or reserved for them
+	Used uint `json:"used"`
+}
+
+// DriverTelemetry defines model for DriverTelemetry.
+type DriverTelemetry struct {
+	// Error Why the driver self check failed
+	Error *string `json:"error,omitempty"`
+
+	// Healthy The driver self check passed
+	Healthy bool   `json:"healthy"`
+	Name    string `json:"name"`
+}
+
+// HealthCheck Describes how the node checks the allocated Resource is alive. After the number of failures in a row the Resource is marked as unhealthy and the owner is notified.
+type HealthCheck struct {
+	// Failures How much failures in a row makes the Resource unhealthy, 0 means 3
+	Failures int `json:"failures"`
+
+	// Interval Time Duration (ex. "1m") between the checks. Empty means "30s".
+	Interval string `json:"interval"`
+
+	// Port Port of the Resource to check, 0 means 22 for `ssh` check
+	Port int `json:"port"`
+
+	// Recreate Deallocate the unhealthy Resource and create new Application with the same Label, metadata and priority instead of just notifying the owner
+	Recreate bool `json:"recreate"`
+
+	// Script Script to execute by the `script` check, non-zero exit code means failure
+	Script string `json:"script"`
+
+	// StartPeriod Time Duration (ex. "5m") since the Resource allocation to wait for it to boot before the first check. Empty means the same as interval.
+	StartPeriod string `json:"start_period"`
+
+	// Timeout Time Duration (ex. "5s") of one check. Empty means "10s".
+	Timeout string `json:"timeout"`
+
+	// Type Type of the check: `tcp` connects to the port of the Resource, `ssh` reads the SSH banner from the port and `script` runs the script inside the Resource by the driver (if the driver supports it)
+	Type string `json:"type"`
+}
+
+// HistoryEvent Event of the object history which is not kept in the object itself, like the Node maintenance changes. The events are removed when the `history_retention` is reached.
+type HistoryEvent struct {
+	UID       HistoryEventUID `gorm:"primaryKey" json:"UID"`
+	CreatedAt time.Time       `gorm:"index" json:"created_at"`
+
+	// Description Additional information about the event
+	Description string `json:"description"`
+
+	// Event What happened with the object
+	Event string `json:"event"`
+
+	// ObjectId Identifier of the object
+	ObjectId string `gorm:"index:idx_history_event_object" json:"object_id"`
+
+	// ObjectType Type of the object the event is related to
+	ObjectType string `gorm:"index:idx_history_event_object" json:"object_type"`
+}
+
+// HistoryEventUID defines model for HistoryEventUID.
+type HistoryEventUID = openapi_types.UUID
+
+// Image Versioned image of the catalog, which is referenced by the Label definition options as `${image:NAME}` (the latest not deprecated version) or `${image:NAME:VERSION}` and the checksum as `${image_checksum:NAME}`. On allocation the reference is replaced by the identifier of the definition driver, so bumping the image version is one operation for all the Labels using it.
+type Image struct {
+	UID ImageUID `gorm:"primaryKey" json:"UID"`
+
+	// Checksum Checksum of the image to verify it, like `sha256:<hex>`
+	Checksum  string    `json:"checksum"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Deprecated Deprecated version is not used when the Label references the Image by name
+	Deprecated bool `json:"deprecated"`
+
+	// Description What's in the image
+	Description *string `json:"description,omitempty"`
+
+	// Identifiers Image identifier per driver instance name (like `aws/us-west-2`) or driver name (like `aws`) which is used when there is no identifier for the instance
+	Identifiers map[string]string `gorm:"serializer:json" json:"identifiers"`
+
+	// Name Name of the image
+	Name      string    `gorm:"uniqueIndex:idx_image_uniq" json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version Version of the image, Image versions can't be changed once created
+	Version int `gorm:"uniqueIndex:idx_image_uniq" json:"version"`
+}
+
+// ImageBuild Build of the Image version by the node config template (like Packer). The built artifact ids are registered in the catalog as deprecated Image version, which is published when the smoke test Application of the temporary copy of `smoke_label` pinned to the new version is allocated. After publishing the Labels from `roll_labels` which are pinned to the explicit Image versions get the new version with the references pinned to the built one.
+type ImageBuild struct {
+	UID       ImageBuildUID `gorm:"primaryKey" json:"UID"`
+	CreatedAt time.Time     `json:"created_at"`
+
+	// Error Why the build failed
+	Error string `json:"error"`
+
+	// ImageUID Registered Image
+	ImageUID openapi_types.UUID `json:"image_UID"`
+
+	// Log Tail of the build command output
+	Log string `json:"log"`
+
+	// Name Name of the Image to build
+	Name string `json:"name"`
+
+	// NodeName Node which runs the build
+	NodeName  string `json:"node_name"`
+	OwnerName string `json:"owner_name"`
+
+	// RollLabels Names of the Labels pinned to the Image versions to roll to the built one
+	RollLabels []string `gorm:"serializer:json" json:"roll_labels"`
+
+	// SmokeLabel Name of the Label to run the smoke test Application, empty publishes the Image right away
+	SmokeLabel string `json:"smoke_label"`
+
+	// Status One of BUILDING, TESTING, FAILED or COMPLETED
+	Status string `json:"status"`
+
+	// Template Name of the build template in the node `image_build` config
+	Template  string    `json:"template"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Variables Template variables passed to the command as `PKR_VAR_<name>` env vars
+	Variables map[string]string `gorm:"serializer:json" json:"variables"`
+
+	// Version Version of the Image to build
+	Version int `json:"version"`
+}
+
+// ImageBuildUID defines model for ImageBuildUID.
+type ImageBuildUID = openapi_types.UUID
+
+// ImageUID defines model for ImageUID.
+type ImageUID = openapi_types.UUID
+
+// InventoryDisk defines model for InventoryDisk.
+type InventoryDisk struct {
+	// Model Model of the disk if known
+	Model string `json:"model"`
+
+	// Name Name of the disk device
+	Name string `json:"name"`
+
+	// Rotational The disk is HDD
+	Rotational bool `json:"rotational"`
+
+	// Size Size of the disk in GiB
+	Size uint `json:"size"`
+}
+
+// InventoryGPU defines model for InventoryGPU.
+type InventoryGPU struct {
+	// Model Model of the GPU, PCI device ID if the name is unknown
+	Model string `json:"model"`
+
+	// Vendor Vendor of the GPU
+	Vendor string `json:"vendor"`
+}
+
+// InventoryVirtualization defines model for InventoryVirtualization.
+type InventoryVirtualization struct {
+	// Hardware The host supports hardware-assisted virtualization (VT-x, AMD-V or Apple HVF)
+	Hardware bool `json:"hardware"`
+
+	// Role Role of the Node in the virtualization system - "host" or "guest"
+	Role string `json:"role"`
+
+	// System Virtualization system the Node is running in or running, empty for bare metal
+	System string `json:"system"`
+}
+
+// Label This one filled by the cluster admin, depends on the needs. Labels could be defined in different drivers and the priority is sequential. Version could be used during request and by default is the latest.
+// Label - is one of the most important part of the system, because it makes the resources reproducible in time. Definitions contains the driver name and configuration, so can be started again and again as much times we need. Versions make possible to update the labels and store the old ones in case we need to run the same environment 10y from now and rebuild the old code revision for example.
+// Labels can't be updated. Once they are stored - they are here to keep the history of environements and make possible to mark build with the specified label version in order to be able to reproduce it later. Also labels can be implemented just by one or multiple drivers. Sometimes it's useful to switch to another provider in case the previous ones are not available or completely used.
+type Label struct {
+	UID LabelUID `gorm:"primaryKey" json:"UID"`
+
+	// AccessOtp Sensitive Label requires the User OTP code as the second factor to get the Resource access credentials and once more in the gate handshake: the ssh proxy asks for it with keyboard-interactive prompt and the web terminal takes it as `otp` query parameter
+	AccessOtp bool      `json:"access_otp"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Definitions List of label definitions that describes resources, driver and it's options. The order is sequential - so the priority is to the first driver and if it's not available than the next definitions will be used.
+	Definitions LabelDefinitions `json:"definitions"`
+
+	// Extends Parent Labels as "name:version" or "name" for the latest version, which are merged in order before this Label on creation. Definitions are merged by index: not empty driver and resources values replace the parent ones, options are merged deeply, the other definition fields replace the parent ones as a whole and additional definitions are appended. Metadata is merged deeply and not zero priority is replaced. The stored Label contains the resolved definitions and exact parent versions, so the parent updates are not affecting it.
+	Extends []string `gorm:"serializer:json" json:"extends"`
+
+	// Metadata Basic metadata to pass to the Resource
+	Metadata util.UnparsedJSON `json:"metadata"`
+
+	// MetadataSchema JSON schema (OpenAPI 3 dialect) of the Application metadata, which is checked on the Application creation to not fail deep inside the Resource boot scripts. Empty object allows any metadata. The Label metadata is merged on top of the Application one later, so the schema describes only what the Application needs to provide.
+	MetadataSchema util.UnparsedJSON `gorm:"default:'{}'" json:"metadata_schema"`
+
+	// Name Simple name to identify the Label
+	Name string `gorm:"uniqueIndex:idx_label_uniq" json:"name"`
+
+	// Priority Default priority of the Applications requesting the Label
+	Priority int `json:"priority"`
+
+	// Project Project the Label belongs to, only the Project members can see the Label and create the Applications with it. Empty for the shared Labels available to everyone.
+	Project string `gorm:"index" json:"project"`
+
+	// RequiresApproval Expensive Label (like dedicated hosts or GPU instances) requires the Application to be approved by admin or user with `approver` role before allocation, till then it stays in PENDING_APPROVAL state
+	RequiresApproval bool `json:"requires_approval"`
+
+	// Version In order to update the labels freely and save the previous Label state for the past builds.
+	Version int `gorm:"uniqueIndex:idx_label_uniq" json:"version"`
+}
+
+// LabelApply The complete set of Labels and optionally custom Roles to apply
+type LabelApply struct {
+	// DryRun Only report the changes, don't apply them
+	DryRun *bool   `json:"dry_run,omitempty"`
+	Labels []Label `json:"labels"`
+
+	// Prune Remove the Label versions which are not in the set
+	Prune *bool `json:"prune,omitempty"`
+
+	// Roles The complete set of custom Roles, they are not changed if not set
+	Roles *[]Role `json:"roles,omitempty"`
+}
+
+// LabelApplyResult The changes of the Labels apply, each item is `<name>:<version>`
+type LabelApplyResult struct {
+	Created []string `json:"created"`
+
+	// Drifted The existing versions which content differs from the set
+	Drifted []string `json:"drifted"`
+
+	// InUse The versions to prune which are kept because used by active Applications
+	InUse   []string `json:"in_use"`
+	Removed []string `json:"removed"`
+
+	// Roles The changes of the custom Roles apply, each item is the Role name
+	Roles     *RoleApplyResult `json:"roles,omitempty"`
+	Unchanged []string         `json:"unchanged"`
+}
+
+// LabelBundle Signed export of the Labels to share them between the clusters, the signature covers the whole bundle except the signature itself
+type LabelBundle struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Format Version of the bundle format
+	Format int `json:"format"`
+
+	// Images The catalog Images referenced by the Labels with their checksums
+	Images []Image `json:"images"`
+	Labels []Label `json:"labels"`
+
+	// PublicKey Base64 Ed25519 public key of the node which signed the bundle
+	PublicKey string `json:"public_key"`
+
+	// Signature Base64 Ed25519 signature of the bundle
+	Signature string `json:"signature"`
+
+	// Source Name of the node exported the bundle
+	Source string `json:"source"`
+}
+
+// LabelCapacity How many Applications of the Label could be allocated right now
+type LabelCapacity struct {
+	Definitions []DefinitionCapacity `json:"definitions"`
+	LabelUID    openapi_types.UUID   `json:"label_UID"`
+	Name        string               `json:"name"`
+
+	// Slots The definitions of the local drivers share the Node resources as well as the remote ones share the cloud quotas, so it's the max of the local plus the max of the remote
+	Slots   int64 `json:"slots"`
+	Version int   `json:"version"`
+}
+
+// LabelCompatibility Label which definitions could be served by the Node
+type LabelCompatibility struct {
+	// Definitions Indexes of the Label definitions the Node could serve
+	Definitions []int `json:"definitions"`
+
+	// HourlyCosts Estimated hourly cost of the Resource for each of the `definitions`, 0 if the Node is not able to price it
+	HourlyCosts []float64          `json:"hourly_costs"`
+	LabelUID    openapi_types.UUID `json:"label_UID"`
+	Name        string             `json:"name"`
+	Version     int                `json:"version"`
+}
+
+// LabelDefinition Describes Label's ways to provide a Resource - it contains name of the driver, it's resource and additional options.
+type LabelDefinition struct {
+	// Authentication Authentication information to enable connecting to the machine. The fields could contain `secret://<provider>/<path>[#<key>]` references to the node secrets providers, they are resolved by the Node only to connect to the Resource. The password and key are encrypted in the database by the Node master key and returned in Label only to admin or operator.
+	Authentication *Authentication `json:"authentication,omitempty"`
+
+	// Driver Name of the driver to execute
+	Driver string `json:"driver"`
+
+	// HealthCheck Describes how the node checks the allocated Resource is alive. After the number of failures in a row the Resource is marked as unhealthy and the owner is notified.
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+
+	// Options Driver-specific options to execute the environment
+	Options util.UnparsedJSON `json:"options"`
+
+	// ProxySshPolicy Controls which SSH capabilities are available for the user connected to the Resource via the SSH proxy. By default everything is allowed.
+	ProxySshPolicy *ProxySSHPolicy `json:"proxy_ssh_policy,omitempty"`
+
+	// Recycle Describes how the Resource could be reused by the next Applications. It's useful for the Labels which boot time dominates the workload execution time, but make sure the cleanup task will bring the environment to the clean state - otherwise it's a security risk.
+	Recycle *RecyclePolicy `json:"recycle,omitempty"`
+
+	// Resources It's used for 2 purposes, in Label definitions to describe the required amount of resources and in Fish to store the currently used resources, so could add and subtract resources.
+	// Modificators are used for parallel node usage by different Applications, they are stored for the first Application and used for the others to determine node tenancy/overbook tolerance.
+	// All the drivers use the same units: `cpu` is the number of vCPUs (logical threads, not cores), `ram` and the disks `size` are in GiB (1024^3 bytes). Which disks, networks and tenancy modificators are supported is declared by the driver capabilities and the Label definition is rejected on create if it can't be satisfied by the driver.
+	Resources Resources `json:"resources"`
+}
+
+// LabelDefinitions List of label definitions that describes resources, driver and it's options. The order is sequential - so the priority is to the first driver and if it's not available than the next definitions will be used.
+type LabelDefinitions []LabelDefinition
+
+// LabelImportResult The changes of the Labels import, each item is `<name>:<version>`
+type LabelImportResult struct {
+	Created         []string `json:"created"`
+	ImagesCreated   []string `json:"images_created"`
+	ImagesUnchanged []string `json:"images_unchanged"`
+
+	// InUse The conflicting versions which can't be overwritten because used by active Applications
+	InUse       []string `json:"in_use"`
+	Overwritten []string `json:"overwritten"`
+	Unchanged   []string `json:"unchanged"`
+
+	// Versioned The conflicting versions imported as the new ones, like `<name>:1 -> <name>:3`
+	Versioned []string `json:"versioned"`
+}
+
+// LabelStats Usage statistics of the Label version
+type LabelStats struct {
+	// AvgLifetime Average time from allocation to deallocation of the finished Applications
+	AvgLifetime util.Duration `json:"avg_lifetime"`
+
+	// Failed Number of the Applications which got to ERROR state
+	Failed int `json:"failed"`
+
+	// FailureRate Part of the finished Applications which failed
+	FailureRate float32            `json:"failure_rate"`
+	LabelUID    openapi_types.UUID `json:"label_UID"`
+
+	// Name Name of the Label
+	Name string `json:"name"`
+
+	// Running Number of the currently active Applications
+	Running int `json:"running"`
+
+	// Total Number of the Applications ever created with the Label version
+	Total int `json:"total"`
+
+	// Version Version of the Label
+	Version int `json:"version"`
+}
+
+// LabelUID defines model for LabelUID.
+type LabelUID = openapi_types.UUID
+
+// Location In order to properly distribute the nodes across locations it's better to have this in place to properly redirect the services to the nearest one according to the node location.
+type Location struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Description Additional information about the location
+	Description string `json:"description"`
+
+	// Name Name of the location
+	Name LocationName `gorm:"primaryKey" json:"name"`
+}
+
+// LocationName Name of the location
+type LocationName = string
+
+// Node Each node need to report it's status and ensure there is no duplications and to perform the cluster worker election process properly.
+// Could be created by the node itself and updated by the same node.
+type Node struct {
+	UID NodeUID `gorm:"primaryKey" json:"UID"`
+
+	// Address External address to reach the Node from outside
+	Address string `json:"address"`
+
+	// Capacity Capacity of the Node, the local drivers share the Node resources while the remote drivers are limited by the cloud quotas
+	Capacity  *NodeCapacity `json:"capacity,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+
+	// Definition Contains technical information about the node
+	Definition NodeDefinition `json:"definition"`
+
+	// Drain The Node is draining - the executing Applications are deallocated on `drain_timeout`
+	Drain bool `json:"drain"`
+
+	// DrainStartedAt When the maintenance mode was requested
+	DrainStartedAt time.Time `json:"drain_started_at"`
+
+	// DrainTimeout How long to wait for the Applications to complete before deallocating them
+	DrainTimeout util.Duration `json:"drain_timeout"`
+
+	// Handover On shutdown the Resources of the remote drivers are passed to the other Nodes of the location instead of waiting for the Applications to complete
+	Handover bool `json:"handover"`
+
+	// LocationName Where the Node is located
+	LocationName string `json:"location_name"`
+
+	// Maintenance The Node is in maintenance mode and not taking the new Applications
+	Maintenance bool `json:"maintenance"`
+
+	// Name Unique name of the Node
+	Name string `gorm:"unique" json:"name"`
+
+	// Pubkey The node public key to verify on secondary connections and signatures
+	Pubkey *[]byte `gorm:"unique" json:"pubkey,omitempty"`
+
+	// Shutdown The Node will shutdown when the Applications are completed
+	Shutdown bool `json:"shutdown"`
+
+	// Telemetry Usage of the Node host resources, the Node switches to WARNING status when any of the values is over the `telemetry` config threshold
+	Telemetry *NodeTelemetry `json:"telemetry,omitempty"`
+
+	// UpdatedAt This field is used as ping, so the cluster knows the Node is alive.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version The version of the Node binary
+	Version string `json:"version"`
+}
+
+// NodeCapacity Capacity of the Node, the local drivers share the Node resources while the remote drivers are limited by the cloud quotas
+type NodeCapacity struct {
+	// CpuTotal Amount of the Node vCPUs
+	CpuTotal uint `json:"cpu_total"`
+
+	// CpuUsed Amount of vCPUs used by the local drivers Resources
+	CpuUsed uint `json:"cpu_used"`
+
+	// Drivers Active drivers of the Node
+	Drivers []DriverCapacity `json:"drivers"`
+
+	// Labels Latest versions of the Labels the Node could serve
+	Labels   []LabelCapacity    `json:"labels"`
+	NodeUID  openapi_types.UUID `json:"node_UID"`
+	NodeName string             `json:"node_name"`
+
+	// RamTotal Amount of the Node RAM in GiB
+	RamTotal uint `json:"ram_total"`
+
+	// RamUsed Amount of RAM in GiB used by the local drivers Resources
+	RamUsed uint `json:"ram_used"`
+
+	// UpdatedAt When the capacity was calculated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NodeDefinition Contains technical information about the node
+type NodeDefinition struct {
+	Cpu   []cpu.InfoStat             `json:"cpu"`
+	Disks map[string]*disk.UsageStat `json:"disks"`
+	Host  *host.InfoStat             `json:"host"`
+
+	// Inventory Hardware of the Node host, the local drivers use it to calculate the capacity and operators to verify the fleet is homogeneous
+	Inventory *NodeInventory         `json:"inventory,omitempty"`
+	Memory    *mem.VirtualMemoryStat `json:"memory"`
+	Nets      []net.InterfaceStat    `json:"nets"`
+}
+
+// NodeDrain The progress of the Node maintenance drain
+type NodeDrain struct {
+	// Applications The Applications still executing on the Node
+	Applications []openapi_types.UUID `json:"applications"`
+
+	// Deadline When the remaining Applications will be deallocated, zero if not draining
+	Deadline time.Time `json:"deadline"`
+
+	// Drain The Applications will be deallocated on deadline
+	Drain bool `json:"drain"`
+
+	// Drained The Node is in maintenance and has no Applications, so it's safe to stop it
+	Drained bool `json:"drained"`
+
+	// Handover The Resources of the remote drivers are passed to the other Nodes of the location, so `remaining` decreases as they are picked up
+	Handover bool `json:"handover"`
+
+	// Maintenance The Node is in maintenance mode
+	Maintenance bool               `json:"maintenance"`
+	NodeUID     openapi_types.UUID `json:"node_UID"`
+
+	// Remaining Number of the Applications still executing on the Node
+	Remaining int `json:"remaining"`
+
+	// StartedAt When the maintenance mode was requested
+	StartedAt time.Time `json:"started_at"`
+}
+
+// NodeInventory Hardware of the Node host, the local drivers use it to calculate the capacity and operators to verify the fleet is homogeneous
+type NodeInventory struct {
+	// CpuCores Number of the physical CPU cores
+	CpuCores uint `json:"cpu_cores"`
+
+	// CpuFlags Features supported by the CPU
+	CpuFlags []string `json:"cpu_flags"`
+
+	// CpuModel Model name of the CPU
+	CpuModel string `json:"cpu_model"`
+
+	// CpuThreads Number of the logical CPU threads, the same units as the Resources `cpu`
+	CpuThreads uint `json:"cpu_threads"`
+
+	// CpuVendor Vendor of the CPU
+	CpuVendor string `json:"cpu_vendor"`
+
+	// Disks Physical disks of the Node
+	Disks []InventoryDisk `json:"disks"`
+
+	// Gpus GPUs of the Node
+	Gpus []InventoryGPU `json:"gpus"`
+
+	// RamTotal Amount of the Node RAM in GiB
+	RamTotal uint `json:"ram_total"`
+
+	// UpdatedAt When the inventory was detected
+	UpdatedAt      time.Time               `json:"updated_at"`
+	Virtualization InventoryVirtualization `json:"virtualization"`
+}
+
+// NodeTelemetry Usage of the Node host resources, the Node switches to WARNING status when any of the values is over the `telemetry` config threshold
+type NodeTelemetry struct {
+	// ClockSkew Difference between the Node clock and `telemetry.clock_reference`, zero when the reference is not set
+	ClockSkew util.Duration `json:"clock_skew"`
+
+	// CpuUsage Host CPU usage in percents
+	CpuUsage float32 `json:"cpu_usage"`
+
+	// DiskUsage Usage of the disk with the Node directory in percents
+	DiskUsage float32 `json:"disk_usage"`
+
+	// Drivers Health of the active drivers
+	Drivers []DriverTelemetry `json:"drivers"`
+
+	// RamUsage Host RAM usage in percents
+	RamUsage float32             `json:"ram_usage"`
+	Status   NodeTelemetryStatus `json:"status"`
+
+	// UpdatedAt When the telemetry was collected
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Warnings Human readable reasons of the WARNING status
+	Warnings []string `json:"warnings"`
+}
+
+// NodeTelemetryStatus defines model for NodeTelemetry.Status.
+type NodeTelemetryStatus string
+
+// NodeUID defines model for NodeUID.
+type NodeUID = openapi_types.UUID
+
+// OperationPermission Permission of the User to call the API operation
+type OperationPermission struct {
+	// Allowed The User could call the operation for any object
+	Allowed bool `json:"allowed"`
+
+	// Operation The API operation ID
+	Operation string `json:"operation"`
+
+	// Own The User could call the operation for the owned objects or the User itself
+	Own bool `json:"own"`
+}
+
+// Preference Setting of the User stored on the server side (like saved filters or dashboard layout), so the Web UI and CLI settings follow the User across the machines
+type Preference struct {
+	// Key Key of the preference, could contain letters, digits and `.`, `_`, `-`, `/`
+	Key       string    `gorm:"primaryKey" json:"key"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserName  string    `gorm:"primaryKey" json:"user_name"`
+
+	// Value Value of the preference in JSON format
+	Value util.UnparsedJSON `json:"value"`
+}
+
+// Project Namespace of the team on the shared cluster. The Project Labels are visible only to the members, the Applications take the Project from the Label and the Project quota limits the active Applications of all the members. The `maintainer` members could create and delete the Project Labels and see the Project Applications, the `member` ones could only use the Labels. The zero quota values mean unlimited.
+type Project struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Description Additional information about the Project
+	Description string `json:"description"`
+
+	// MaxApplications Max number of the active Applications of the Project
+	MaxApplications int `json:"max_applications"`
+
+	// MaxCpu Max amount of CPU of the active Applications of the Project
+	MaxCpu int `json:"max_cpu"`
+
+	// MaxRam Max amount of RAM (GB) of the active Applications of the Project
+	MaxRam  int             `json:"max_ram"`
+	Members []ProjectMember `gorm:"serializer:json" json:"members"`
+
+	// Name Unique name of the Project
+	Name      ProjectName `gorm:"primaryKey" json:"name"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// ProjectMember Membership of the User in the Project
+type ProjectMember struct {
+	Role     ProjectMemberRole `json:"role"`
+	UserName string            `json:"user_name"`
+}
+
+// ProjectMemberRole defines model for ProjectMember.Role.
+type ProjectMemberRole string
+
+// ProjectName Unique name of the Project
+type ProjectName = string
+
+// ProxySSHPolicy Controls which SSH capabilities are available for the user connected to the Resource via the SSH proxy. By default everything is allowed.
+type ProxySSHPolicy struct {
+	// DenyPortForward Disable local (direct-tcpip) and remote (tcpip-forward) port forwarding, including the unix sockets forwarding (streamlocal)
+	DenyPortForward bool `json:"deny_port_forward"`
+
+	// DenySftp Disable SFTP subsystem and execution of sftp-server or scp commands. The other commands still could be used to transfer the files, so use shell_only to restrict it.
+	DenySftp bool `json:"deny_sftp"`
+
+	// ShellOnly Allow only interactive shell session - disables exec (and scp), subsystems, port forwarding, agent and X11 forwarding
+	ShellOnly bool `json:"shell_only"`
+}
+
+// Quota Limits of the User Applications checked on the Application create and election, 0 value means unlimited. The Resources amounts are taken from the Label definition the Application is allocated with or the first definition for not allocated yet. On election only the allocated Resources are counted, so the Application waits for the quota there instead of failing, for example when the quota was lowered or the daily hours are used up.
+type Quota struct {
+	// MaxApplications Max number of the active Applications
+	MaxApplications int `json:"max_applications"`
+
+	// MaxCpu Max amount of CPU of the active Applications
+	MaxCpu int `json:"max_cpu"`
+
+	// MaxHourlyCost Budget cap of the estimated hourly cost of the active Resources, checked by the node before allocation. The Resources which price is unknown are not counted.
+	MaxHourlyCost float64 `json:"max_hourly_cost"`
+
+	// MaxHours Max total hours of the Resources allocation for the last 24 hours
+	MaxHours int `json:"max_hours"`
+
+	// MaxRam Max amount of RAM (GB) of the active Applications
+	MaxRam    int       `json:"max_ram"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserName  string    `gorm:"primaryKey" json:"user_name"`
+}
+
+// QuotaUsage Current usage of the User quota
+type QuotaUsage struct {
+	Applications int `json:"applications"`
+	Cpu          int `json:"cpu"`
+
+	// HourlyCost Estimated hourly cost of the active Resources
+	HourlyCost float64 `json:"hourly_cost"`
+
+	// Hours Hours of the Resources allocation for the last 24 hours
+	Hours float32 `json:"hours"`
+
+	// Quota Limits of the User Applications checked on the Application create and election, 0 value means unlimited. The Resources amounts are taken from the Label definition the Application is allocated with or the first definition for not allocated yet. On election only the allocated Resources are counted, so the Application waits for the quota there instead of failing, for example when the quota was lowered or the daily hours are used up.
+	Quota Quota `json:"quota"`
+	Ram   int   `json:"ram"`
+
+	// Roles Usage of the group quotas of the custom roles granted to the User
+	Roles []RoleQuotaUsage `json:"roles"`
+}
+
+// RateLimit Rate limit of the API service or operation configured by the node `rate_limit`, the requests of the User to the operations under the same rule share the limit
+type RateLimit struct {
+	// Burst How many requests could be made at once
+	Burst int `json:"burst"`
+
+	// Rate Sustained requests per second
+	Rate float64 `json:"rate"`
+
+	// Remaining How many requests could be made right now
+	Remaining int `json:"remaining"`
+
+	// Rule The API service, operation ID or `default` for the rest of the operations
+	Rule string `json:"rule"`
+}
+
+// RecyclePolicy Describes how the Resource could be reused by the next Applications. It's useful for the Labels which boot time dominates the workload execution time, but make sure the cleanup task will bring the environment to the clean state - otherwise it's a security risk.
+type RecyclePolicy struct {
+	// IdleTimeout Time Duration (ex. "10m") the Resource could wait in the pool for the next Application. Empty or "0" means until it reaches max_age or node shutdown.
+	IdleTimeout string `json:"idle_timeout"`
+
+	// MaxAge Time Duration (ex. "6h") since the Resource allocation after which it will be destroyed instead of recycling. Empty or "0" means unlimited.
+	MaxAge string `json:"max_age"`
+
+	// MaxReuse How much times the Resource could be reused, 0 means unlimited
+	MaxReuse int `json:"max_reuse"`
+
+	// Options Options for the cleanup task
+	Options util.UnparsedJSON `json:"options"`
+
+	// ReuseOptions Options for the reuse task
+	ReuseOptions util.UnparsedJSON `json:"reuse_options"`
+
+	// ReuseTask Name of the driver task to execute on the pooled Resource before giving it to the next Application, it receives the Resource with the new Application metadata to deliver it into the environment. If the task fails - new Resource will be allocated instead. Empty means the Resource is getting the metadata by itself through the meta API.
+	ReuseTask string `json:"reuse_task"`
+
+	// Task Name of the driver task to execute to cleanup the Resource before returning it to pool, if the task fails - the Resource will be destroyed. Empty means no cleanup.
+	Task string `json:"task"`
+}
+
+// Resource Active resource definition to be able to properly restore the state during the cluster node restart. Also contains additional info about the resource, for example user requested metadata, which is available for the resource through the `Meta API`.
+// Could be created and updated only by the node which won the Application execution.
+type Resource struct {
+	UID ResourceUID `gorm:"primaryKey" json:"UID"`
+
+	// AddressFamily Preferred address family to connect to the Resource (`ipv4` or `ipv6`)
+	AddressFamily  string             `json:"address_family"`
+	ApplicationUID openapi_types.UUID `json:"application_UID" yaml:"application_UID"`
+
+	// Authentication Authentication information to enable connecting to the machine. The fields could contain `secret://<provider>/<path>[#<key>]` references to the node secrets providers, they are resolved by the Node only to connect to the Resource. The password and key are encrypted in the database by the Node master key and returned in Label only to admin or operator.
+	Authentication  *Authentication `json:"authentication,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	DefinitionIndex int             `json:"definition_index"`
+
+	// DriverInfo Driver-specific information about the Resource (like instance type or image digest). The fields are described by the driver info schema and validated by the node, so the tooling don't need to parse the `identifier`.
+	DriverInfo util.UnparsedJSON `gorm:"default:'{}'" json:"driver_info"`
+
+	// HostKeyFingerprint SHA256 fingerprint of the Resource SSH host key (as `ssh-keygen -l` shows it) to allow the clients to pin the host key. Received by the node in background after allocation, so empty if it was not received yet and could change if the image regenerates the key on the first boot.
+	HostKeyFingerprint string `json:"host_key_fingerprint"`
+
+	// HourlyCost Estimated cost of the Resource per hour by the driver pricing or the node static price table, 0 if the price is unknown
+	HourlyCost float64 `json:"hourly_cost"`
+
+	// HwAddr MAC or any other type of network address which will allow to properly identify the node through network interaction.
+	HwAddr string `json:"hw_addr"`
+
+	// Identifier Unique for driver identifier of the resource which can be used to find it later.
+	Identifier string `json:"identifier"`
+
+	// IpAddr Is a weak current network IP address of the resource, usually gathered based on the `hw_addr`.
+	IpAddr   string             `json:"ip_addr"`
+	LabelUID openapi_types.UUID `json:"label_UID" yaml:"label_UID"`
+
+	// Metadata Combined Application and Label metadata (in this order) to make it available through `Meta API` to the resource.
+	Metadata util.UnparsedJSON  `json:"metadata"`
+	NodeUID  openapi_types.UUID `json:"node_UID" yaml:"node_UID"`
+
+	// Reused How many Applications used the Resource before it was taken from the recycle pool, 0 if it was allocated for this Application. The recycled Resource can't register secret key.
+	Reused int `json:"reused"`
+
+	// SecretKey Base64-encoded X25519 public key registered by the Resource through Meta API to encrypt the secrets sent to it. Empty if the Resource not registered it yet.
+	SecretKey string `json:"secret_key"`
+
+	// Tasks Generic ApplicationTasks (like `snapshot` or `reboot`) the driver of the Resource implements, so the clients could show the available actions
+	Tasks []string `gorm:"serializer:json" json:"tasks"`
+
+	// Unhealthy Set by the node when the Label definition health check failed the configured number of times in a row, it's reset when the check passes again
+	Unhealthy bool      `json:"unhealthy"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ResourceAccess An accessor entry to be able to identify and look up different (currently running) resources.
+// Used to enable SSH pass-through.
+type ResourceAccess struct {
+	UID ResourceAccessUID `gorm:"primaryKey" json:"UID"`
+
+	// Address Address (host:port) to help user to connect to right proxyssh.
+	Address string `json:"address"`
+
+	// AddressFamily Preferred address family to connect to the proxyssh address (`ipv4` or `ipv6`)
+	AddressFamily string    `json:"address_family"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// HostKeyFingerprint SHA256 fingerprint of the proxyssh host key to pin it in the ssh client known_hosts.
+	HostKeyFingerprint string `json:"host_key_fingerprint"`
+
+	// Key SSH key could be used instead of password to access the system.
+	Key string `json:"key"`
+
+	// Password The password to use when logging into the fish node.
+	Password    string             `json:"password"`
+	ResourceUID openapi_types.UUID `json:"resource_UID" yaml:"resource_UID"`
+
+	// Username The username to use when logging into the fish node.
+	Username string `json:"username"`
+}
+
+// ResourceAccessUID defines model for ResourceAccessUID.
+type ResourceAccessUID = openapi_types.UUID
+
+// ResourceActivity Activity report of the Resource agent
+type ResourceActivity struct {
+	// Active The agent detected the user activity (logged in users, running jobs...)
+	Active bool `json:"active"`
+
+	// Cpu Average CPU usage percent since the last report
+	Cpu float32 `json:"cpu"`
+}
+
+// ResourceUID defines model for ResourceUID.
+type ResourceUID = openapi_types.UUID
+
+// Resources It's used for 2 purposes, in Label definitions to describe the required amount of resources and in Fish to store the currently used resources, so could add and subtract resources.
+// Modificators are used for parallel node usage by different Applications, they are stored for the first Application and used for the others to determine node tenancy/overbook tolerance.
+// All the drivers use the same units: `cpu` is the number of vCPUs (logical threads, not cores), `ram` and the disks `size` are in GiB (1024^3 bytes). Which disks, networks and tenancy modificators are supported is declared by the driver capabilities and the Label definition is rejected on create if it can't be satisfied by the driver.
+type Resources struct {
+	// Cpu Amount of vCPUs (logical CPU with HT enabled will have 2 per core)
+	Cpu uint `json:"cpu"`
+
+	// CpuOverbook Tolerate to node CPU overbooking when executed together with other envs
+	CpuOverbook bool                     `json:"cpu_overbook"`
+	Disks       map[string]ResourcesDisk `json:"disks"`
+
+	// IdleTimeout Deallocate the Resource if it was not used for this Time Duration (ex. "2h"). Usage is
+	// the proxy ssh sessions and the activity reported by the Resource agent through Meta API.
+	// The owner is warned before the deallocation. Empty or "0" disables the idle detection.
+	IdleTimeout string `json:"idle_timeout"`
+
+	// Lifetime Total lifetime of the resource in Time Duration (ex. "1h30m30s"). Begins on Resource
+	// create time till deallocate by user or auto deallocate by timeout. If it's empty or "0"
+	// then default value from fish node config will be used. If it's negative (ex. "-1s")
+	// then the resource will live forever or until the user requests deallocate.
+	Lifetime string `json:"lifetime"`
+
+	// MaxExtension Max Time Duration (ex. "2h") the owner could extend the Resource lifetime by one
+	// request. Empty or "0" means the lifetime can't be extended by request.
+	MaxExtension string `json:"max_extension"`
+
+	// MaxLifetime Max total lifetime Time Duration (ex. "24h") since the Resource creation the lifetime
+	// extensions can't exceed. Empty or "0" means there is no total limit.
+	MaxLifetime string `json:"max_lifetime"`
+
+	// Multitenancy Tolerate to run along with the other envs on the same node
+	Multitenancy bool `json:"multitenancy"`
+
+	// Network Which network configuration to use for the environment
+	Network string `json:"network"`
+
+	// NodeFilter The list of the Node identifiers to run resource on, supports path wildcards
+	NodeFilter []string `json:"node_filter"`
+
+	// Ram Amount of RAM in GiB
+	Ram uint `json:"ram"`
+
+	// RamOverbook Tolerate to RAM overbooking when executed together with other envs
+	RamOverbook bool `json:"ram_overbook"`
+}
+
+// ResourcesDisk Defines disk to attach/clone...
+type ResourcesDisk struct {
+	// Clone Clone the snapshot of existing disk instead of creating the new one
+	Clone string `json:"clone"`
+
+	// Label Volume name will be given to the disk, empty will use the disk key
+	Label string `json:"label"`
+
+	// Reuse Do not remove the disk and reuse it for the next resource run
+	Reuse bool `json:"reuse"`
+
+	// Size Amount of disk space in GiB
+	Size uint `json:"size"`
+
+	// Type Type of the filesystem to create
+	Type string `json:"type"`
+}
+
+// Role Custom role created by admin to scope the Application create of the Users granted with it. The User with the custom roles could create the Applications only with the Labels and get them allocated only by the nodes in the locations allowed by any of the roles, empty list allows any. The User without custom roles is not restricted. The role is also the group of the Users: the group quota limits the Applications of all the Users granted with the role together, checked on Application create and election, 0 value means unlimited.
+type Role struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Description Additional information about the role
+	Description string `json:"description"`
+
+	// Labels Names of the Labels the User could create Applications with, supports path wildcards
+	Labels []string `gorm:"serializer:json" json:"labels"`
+
+	// Locations Locations of the nodes which could allocate the User Applications, supports path wildcards. The nodes in other locations are not voting for the User Applications.
+	Locations []string `gorm:"serializer:json" json:"locations"`
+
+	// MaxApplications Max number of the active Applications of the group
+	MaxApplications int `json:"max_applications"`
+
+	// MaxCpu Max amount of CPU of the active Applications of the group
+	MaxCpu int `json:"max_cpu"`
+
+	// MaxHours Max total hours of the group Resources allocation for the last 24 hours
+	MaxHours int `json:"max_hours"`
+
+	// MaxRam Max amount of RAM (GB) of the active Applications of the group
+	MaxRam int `json:"max_ram"`
+
+	// Name Unique name of the custom role
+	Name      RoleName  `gorm:"primaryKey" json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoleApplyResult The changes of the custom Roles apply, each item is the Role name
+type RoleApplyResult struct {
+	Created []string `json:"created"`
+
+	// InUse The Roles to prune which are kept because granted to the Users
+	InUse     []string `json:"in_use"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+
+	// Updated The existing Roles which content differs from the set
+	Updated []string `json:"updated"`
+}
+
+// RoleGrant Temporary grant of the role to the User, the grant is automatically revoked when expired. The grants are not deleted after revoke to keep the audit trail.
+type RoleGrant struct {
+	UID       RoleGrantUID `gorm:"primaryKey" json:"UID"`
+	CreatedAt time.Time    `json:"created_at"`
+
+	// ExpiresAt When the grant will be automatically revoked
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// GrantedBy Name of the User who granted the role
+	GrantedBy string `json:"granted_by"`
+
+	// RevokedAt When the grant was revoked, empty if still active
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+
+	// RevokedBy Who revoked the grant, `fish` if it's expired
+	RevokedBy string `json:"revoked_by"`
+
+	// Role Role to grant, `operator` allows to act as admin for the operational calls (Resources, Applications, Labels, Node management), but not to manage the Users & grants, to access the Resources (credentials and metadata are hidden, no proxy access or terminal), to approve the regulated Applications deallocation and to get the node profiling info. `auditor` allows to read the audit log of the user actions. `approver` allows to approve or reject the Applications of the Labels with `requires_approval`. The custom roles created by admin could be granted too to limit the User Applications by their scope.
+	Role string `json:"role"`
+
+	// UserName Name of the User who receives the role
+	UserName string `gorm:"index" json:"user_name"`
+}
+
+// RoleGrantUID defines model for RoleGrantUID.
+type RoleGrantUID = openapi_types.UUID
+
+// RoleName Unique name of the custom role
+type RoleName = string
+
+// RoleQuotaUsage Current usage of the custom role group quota by all the granted Users
+type RoleQuotaUsage struct {
+	Applications int `json:"applications"`
+	Cpu          int `json:"cpu"`
+
+	// Hours Hours of the Resources allocation for the last 24 hours
+	Hours float32 `json:"hours"`
+	Ram   int     `json:"ram"`
+
+	// Role Custom role created by admin to scope the Application create of the Users granted with it. The User with the custom roles could create the Applications only with the Labels and get them allocated only by the nodes in the locations allowed by any of the roles, empty list allows any. The User without custom roles is not restricted. The role is also the group of the Users: the group quota limits the Applications of all the Users granted with the role together, checked on Application create and election, 0 value means unlimited.
+	Role Role `json:"role"`
+}
+
+// Schedule Creates the batch of Applications on cron expression, for example to pre-warm the Resources before the working day and release them in the evening. The missed runs (node was down or Schedule disabled) are not executed later.
+type Schedule struct {
+	UID ScheduleUID `gorm:"primaryKey" json:"UID"`
+
+	// Count Number of the Applications to create on each run
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Cron When to create the Applications, 5 fields cron expression "minute hour day-of-month month day-of-week"
+	Cron string `json:"cron"`
+
+	// DeallocateCron When to deallocate the Applications created by the Schedule, empty means they live till the usual Label lifetime
+	DeallocateCron string `json:"deallocate_cron"`
+
+	// Enabled The disabled Schedule is not executed
+	Enabled  bool               `json:"enabled"`
+	LabelUID openapi_types.UUID `json:"label_UID" yaml:"label_UID"`
+
+	// LastDeallocateAt Last time the Schedule deallocated the Applications
+	LastDeallocateAt time.Time `json:"last_deallocate_at"`
+
+	// LastRunAt Last time the Schedule created the Applications
+	LastRunAt time.Time `json:"last_run_at"`
+
+	// Metadata Metadata of the Applications
+	Metadata util.UnparsedJSON `json:"metadata"`
+
+	// Name Human readable name of the Schedule
+	Name string `json:"name"`
+
+	// NodeUID The Node created the Schedule, it executes the runs
+	NodeUID   openapi_types.UUID `json:"node_UID" yaml:"node_UID"`
+	OwnerName string             `json:"owner_name"`
+
+	// Priority Priority of the Applications
+	Priority int `json:"priority"`
+
+	// Template Name of the template to create the Applications from
+	Template string `json:"template"`
+
+	// Timezone IANA time zone of the cron expressions, UTC if empty
+	Timezone string `json:"timezone"`
+}
+
+// ScheduleNext The next runs of the Schedule
+type ScheduleNext struct {
+	// Allocate When the Applications will be created
+	Allocate []time.Time `json:"allocate"`
+
+	// Deallocate When the Applications will be deallocated
+	Deallocate []time.Time `json:"deallocate"`
+}
+
+// ScheduleRun The history record of the Schedule execution
+type ScheduleRun struct {
+	UID ScheduleRunUID `gorm:"primaryKey" json:"UID"`
+
+	// Action What the run did - `allocate` or `deallocate`
+	Action string `json:"action"`
+
+	// BatchUID The batch of Applications created by the allocate run
+	BatchUID openapi_types.UUID `json:"batch_UID" yaml:"batch_UID"`
+
+	// Count Number of the Applications created or deallocated
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Error Why the run failed, empty on success
+	Error       string             `json:"error"`
+	ScheduleUID openapi_types.UUID `gorm:"index" json:"schedule_UID" yaml:"schedule_UID"`
+}
+
+// ScheduleRunUID defines model for ScheduleRunUID.
+type ScheduleRunUID = openapi_types.UUID
+
+// ScheduleUID defines model for ScheduleUID.
+type ScheduleUID = openapi_types.UUID
+
+// SchedulerShare Usage of the cluster by the Applications of the owner
+type SchedulerShare struct {
+	// Allocated Number of the active Applications of the owner which are not waiting
+	Allocated int    `json:"allocated"`
+	OwnerName string `json:"owner_name"`
+
+	// Share Part of the cluster allocated Applications belongs to the owner
+	Share float32 `json:"share"`
+
+	// Waiting Number of the NEW Applications of the owner waiting for election
+	Waiting int `json:"waiting"`
+}
+
+// SecretKey Resource public key to encrypt the secrets
+type SecretKey struct {
+	// PublicKey Base64-encoded X25519 public key
+	PublicKey string `json:"public_key"`
+}
+
+// ServiceMapping Used to properly define the allowed external services and possible redirects for them ( useful when needed to achive proper bottleneck-free DR). The structure allows to make query with finding the most relevant value based on application and location, in case there is no specific values - the cluster defaults could be used.
+type ServiceMapping struct {
+	UID ServiceMappingUID `gorm:"primaryKey" json:"UID"`
+
+	// ApplicationUID Optional, if need to override the cluster-wide redirects
+	ApplicationUID openapi_types.UUID `gorm:"uniqueIndex:idx_location_service_app_uniq" json:"application_UID" yaml:"application_UID"`
+	CreatedAt      time.Time          `json:"created_at"`
+	LocationName   string             `gorm:"uniqueIndex:idx_location_service_app_uniq" json:"location_name"`
+
+	// Redirect FQDN or IP address of where to rediect the service request
+	Redirect string `json:"redirect"`
+
+	// Service FQDN or IP address of the requested service
+	Service string `gorm:"uniqueIndex:idx_location_service_app_uniq" json:"service"`
+}
+
+// ServiceMappingUID defines model for ServiceMappingUID.
+type ServiceMappingUID = openapi_types.UUID
+
+// SimulatorMonth Projected usage of the dedicated pool in the calendar month
+type SimulatorMonth struct {
+	Cost float64 `json:"cost"`
+
+	// HostHours Billed hours of the hosts, the scrubbing is not billed
+	HostHours float64 `json:"host_hours"`
+
+	// HostsMax Maximum amount of the hosts allocated in the month
+	HostsMax      uint    `json:"hosts_max"`
+	InstanceHours float64 `json:"instance_hours"`
+
+	// Month Month of the usage
+	Month string `json:"month"`
+
+	// QueueHours Sum of the time the workloads waited for the host
+	QueueHours float64 `json:"queue_hours"`
+}
+
+// SimulatorPool Settings of the simulated dedicated pool
+type SimulatorPool struct {
+	// HostHourlyPrice Cost of the allocated dedicated host per hour
+	HostHourlyPrice *float64 `json:"host_hourly_price,omitempty"`
+
+	// Initialize How long the instance takes to boot, default is 7m
+	Initialize *util.Duration `json:"initialize,omitempty"`
+
+	// Max Maximum amount of the dedicated hosts in the pool
+	Max uint `json:"max"`
+
+	// MinUsage Minimal time the host is allocated before release, default is 24h
+	MinUsage *util.Duration `json:"min_usage,omitempty"`
+
+	// Name Name of the variant to find it in the result
+	Name string `json:"name"`
+
+	// ScrubbingDelay Idle time before the host is sent to scrubbing, 0 disables the optimization
+	ScrubbingDelay *util.Duration `json:"scrubbing_delay,omitempty"`
+
+	// ScrubbingDuration How long the host is not available after the instance, default is 1h30m
+	ScrubbingDuration *util.Duration `json:"scrubbing_duration,omitempty"`
+}
+
+// SimulatorRequest Workload and the pool settings to simulate, either `workload` or `replay_weeks` need to be set
+type SimulatorRequest struct {
+	// LabelName Replay only the Applications of the Label with this name
+	LabelName *string `json:"label_name,omitempty"`
+
+	// Pools Variants of the pool settings to compare
+	Pools []SimulatorPool `json:"pools"`
+
+	// ReplayWeeks Use the Applications recorded in the last N weeks as the workload
+	ReplayWeeks *uint `json:"replay_weeks,omitempty"`
+
+	// Workload Profile of the workload to run on the simulated pools
+	Workload *[]SimulatorWorkload `json:"workload,omitempty"`
+}
+
+// SimulatorResult Projected usage of the dedicated pool with the settings variant
+type SimulatorResult struct {
+	// Cost Total cost of the hosts
+	Cost float64 `json:"cost"`
+
+	// HostsMax Maximum amount of the allocated hosts
+	HostsMax uint `json:"hosts_max"`
+
+	// InstancesMax Maximum amount of the running instances
+	InstancesMax uint             `json:"instances_max"`
+	Months       []SimulatorMonth `json:"months"`
+
+	// Name Name of the pool settings variant
+	Name string `json:"name"`
+
+	// QueueMax Maximum amount of the workloads waiting for the host
+	QueueMax uint `json:"queue_max"`
+
+	// QueueWaitMax Longest time the workload waited for the host
+	QueueWaitMax util.Duration `json:"queue_wait_max"`
+
+	// Unserved Workloads which were not able to get the host at all
+	Unserved uint `json:"unserved"`
+}
+
+// SimulatorWorkload One Application to run on the simulated pool
+type SimulatorWorkload struct {
+	// Duration How long the Application is running after the instance is initialized
+	Duration util.Duration `json:"duration"`
+
+	// Start When the Application is requested
+	Start time.Time `json:"start"`
+}
+
+// Subscription The User subscription to the lifecycle event notifications. The sinks are enabled by the node `notifications` config: `smtp` sends email to the address, `slack` posts to the Slack incoming webhook URL and `webhook` posts the notification JSON to the URL.
+type Subscription struct {
+	UID SubscriptionUID `gorm:"primaryKey" json:"UID"`
+
+	// Address Email address or URL where to deliver the notification
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Event The event to notify about: `application_allocated`, `application_error`, `application_deallocated`, `lifetime_expiring` (the Resource lifetime will expire soon), `resource_unhealthy` (the Resource health check is failing), `quota_exceeded` (the Application was not created due to the User quota), `node_down`, `node_warning` (the Node telemetry is over the thresholds), `image_published` or `image_deprecated` (the Image catalog changes)
+	Event string `json:"event"`
+
+	// Sink How to deliver the notification
+	Sink string `json:"sink"`
+
+	// UserName Name of the User who receives the notifications
+	UserName string `gorm:"index" json:"user_name"`
+}
+
+// SubscriptionUID defines model for SubscriptionUID.
+type SubscriptionUID = openapi_types.UUID
+
+// SyncData The changed objects to sync between the nodes
+type SyncData struct {
+	ApplicationStates []ApplicationState `json:"application_states"`
+	Applications      []Application      `json:"applications"`
+
+	// DeletedUsers Names of the deleted Users
+	DeletedUsers []string `json:"deleted_users"`
+	Images       []Image  `json:"images"`
+	Labels       []Label  `json:"labels"`
+	Users        []User   `json:"users"`
+}
+
+// SyncRequest defines model for SyncRequest.
+type SyncRequest struct {
+	// Changes The changed objects to sync between the nodes
+	Changes SyncData `json:"changes"`
+
+	// NodeName Name of the edge node
+	NodeName string `json:"node_name"`
+
+	// Since Sequence number of the last central change received by the edge node
+	Since int64 `json:"since"`
+}
+
+// SyncResponse defines model for SyncResponse.
+type SyncResponse struct {
+	// Changes The changed objects to sync between the nodes
+	Changes SyncData `json:"changes"`
+
+	// Conflicts The edge changes which were not applied due to conflicts
+	Conflicts []string `json:"conflicts"`
+
+	// Until Sequence number of the last change in response to use as since next time
+	Until int64 `json:"until"`
+}
+
+// Template Bundles the Label and the Application defaults, so the Application could be requested just by the template name instead of repeating the structured metadata every time.
+type Template struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Description Explains what the template is for
+	Description string `json:"description"`
+
+	// LabelName Name of the Label to request
+	LabelName string `json:"label_name"`
+
+	// LabelVersion Version of the Label to request, 0 uses the latest one
+	LabelVersion int `json:"label_version"`
+
+	// Metadata Default metadata of the Application, the Application metadata overrides it
+	Metadata util.UnparsedJSON `json:"metadata"`
+	Name     string            `gorm:"primaryKey" json:"name"`
+
+	// Priority Default priority of the Application
+	Priority  int       `json:"priority"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Upgrade Rolling restart of the cluster Nodes to the new version. The Nodes are processed in order, up to `parallel` at the same time: the Node is put in maintenance with drain and shuts down when the Applications are completed, then the service manager starts it with the new binary. When the Node is back with the expected version the Upgrade proceeds to the next one, if the Node is not back in `drain_timeout` + `health_timeout` the Upgrade fails and waits for the operator. When the share of the failed Applications on the upgraded Nodes gets over `max_failure_rate` the Upgrade fails too and the drained Nodes which are not restarted yet are returned to service.
+type Upgrade struct {
+	UID       UpgradeUID `gorm:"primaryKey" json:"UID"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Current Index of the first Node in `nodes` which is not upgraded yet
+	Current int `json:"current"`
+
+	// DrainTimeout How long to wait for the Node Applications to complete, 1h by default
+	DrainTimeout util.Duration `json:"drain_timeout"`
+
+	// Error Why the Upgrade failed
+	Error string `json:"error"`
+
+	// HealthTimeout How long to wait for the Node to get back after the drain, 10m by default
+	HealthTimeout util.Duration `json:"health_timeout"`
+
+	// MaxFailureRate Share (0-1) of the Applications failed on the upgraded Nodes since the Upgrade start or resume which fails the Upgrade, it's checked when at least 5 Applications were allocated there, 0 disables the check
+	MaxFailureRate float64 `json:"max_failure_rate"`
+
+	// Nodes Names of the Nodes in the upgrade order, all the active Nodes by name if empty
+	Nodes     UpgradeNodes `json:"nodes"`
+	OwnerName string       `json:"owner_name"`
+
+	// Parallel How many Nodes are upgraded at the same time, 1 by default
+	Parallel int `json:"parallel"`
+
+	// ResumedAt When the Upgrade was resumed last time, zero if it was not
+	ResumedAt time.Time `json:"resumed_at"`
+
+	// Status One of RUNNING, PAUSED, FAILED, ABORTED or COMPLETED
+	Status string `json:"status"`
+
+	// Steps When the Nodes which are upgraded now were put in maintenance by Node name
+	Steps     UpgradeSteps `json:"steps"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	// Version The version the Nodes should report after restart
+	Version string `json:"version"`
+}
+
+// UpgradeNodes Names of the Nodes in the upgrade order, all the active Nodes by name if empty
+type UpgradeNodes []string
+
+// UpgradeSteps When the Nodes which are upgraded now were put in maintenance by Node name
+type UpgradeSteps map[string]time.Time
+
+// UpgradeUID defines model for UpgradeUID.
+type UpgradeUID = openapi_types.UUID
+
+// User Contains limits and hash to login, name is unique, `admin` created during the first cluster start and prints it to stderr.
+// Could be created by any node and updated by any node.
+type User struct {
+	CreatedAt time.Time  `json:"created_at"`
+	Hash      crypt.Hash `gorm:"embedded" json:"hash"`
+	Name      UserName   `gorm:"primaryKey" json:"name"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// UserAPIPassword Purely API-only special user object to use during User create operation - it allows to set the required user password or receive the automatically generated one.
+type UserAPIPassword struct {
+	CreatedAt time.Time   `json:"created_at"`
+	Hash      *crypt.Hash `json:"hash,omitempty"`
+	Name      UserName    `gorm:"primaryKey" json:"name"`
+
+	// Password Clear-text password to set for new user or to get the autogenerated one
+	Password  string    `json:"password"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserName defines model for UserName.
+type UserName = string
+
+// UserOTP The User OTP secret enrollment, shown only once
+type UserOTP struct {
+	// Uri The otpauth URI to add the secret to the authenticator app
+	Uri string `json:"uri"`
+}
+
+// UserToken Named API token of the User to use in basic auth instead of the password, for example in CI. The token could be limited by the scopes and expiration time and is never removed after revoke to keep the audit trail.
+type UserToken struct {
+	UID       UserTokenUID `gorm:"primaryKey" json:"UID"`
+	CreatedAt time.Time    `json:"created_at"`
+
+	// CreatedBy Name of the User who created the token
+	CreatedBy string `json:"created_by"`
+
+	// ExpiresAt When the token expires, empty means the token is valid till revoked
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Hash      crypt.Hash `gorm:"embedded;embeddedPrefix:hash_" json:"hash"`
+
+	// LastUsedAt When the token was used last time, updated not often than once a minute
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// Name Name of the token to distinguish it in the list
+	Name string `json:"name"`
+
+	// RevokedAt When the token was revoked, empty if still active
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+
+	// RevokedBy Who revoked the token
+	RevokedBy string `json:"revoked_by"`
+
+	// Scopes Limits the token to the listed API services (tags like `Application`) or operations (like `LabelListGet`), empty list allows everything the User is allowed to do
+	Scopes []string `gorm:"serializer:json" json:"scopes"`
+
+	// Token The token secret, returned only once on create
+	Token *string `gorm:"-" json:"token,omitempty"`
+
+	// UserName Name of the User who owns the token
+	UserName string `gorm:"index" json:"user_name"`
+}
+
+// UserTokenUID defines model for UserTokenUID.
+type UserTokenUID = openapi_types.UUID
+
+// Vote When Application becomes available for the node it starts to vote to notify the cluster about its availability. Votes are basically "yes" or "no" and could take a number of rounds depends on the cluster voting and election rules.
+type Vote struct {
+	UID            VoteUID            `gorm:"primaryKey" json:"UID"`
+	ApplicationUID openapi_types.UUID `gorm:"uniqueIndex:idx_node_app_round_uniq" json:"application_UID" yaml:"application_UID"`
+
+	// Available Node places answer to the Vote for the Application's definitions, the number represents the first available index of the definition which fits the node available resources. In case it's `-1` then node can't run any of the definitions.
+	Available int                `json:"available"`
+	CreatedAt time.Time          `json:"created_at"`
+	NodeUID   openapi_types.UUID `gorm:"uniqueIndex:idx_node_app_round_uniq" json:"node_UID" yaml:"node_UID"`
+
+	// Rand The last resort to figure out for the winner.
+	Rand uint32 `json:"rand"`
+
+	// Round Round of the election, because it can take a number of rounds to figure out the Only One.
+	Round uint16 `gorm:"uniqueIndex:idx_node_app_round_uniq" json:"round"`
+}
+
+// VoteUID defines model for VoteUID.
+type VoteUID = openapi_types.UUID
+
+// WorkloadToken Short-lived OIDC token of the Resource
+type WorkloadToken struct {
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Token Signed JWT
+	Token string `json:"token"`
+}
+
+// ApplicationListGetParams defines parameters for ApplicationListGet.
+type ApplicationListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// OwnerName Only the Applications of the owner
+	OwnerName *string `form:"owner_name,omitempty" json:"owner_name,omitempty"`
+
+	// LabelUid Only the Applications of the Label
+	LabelUid *openapi_types.UUID `form:"label_uid,omitempty" json:"label_uid,omitempty"`
+
+	// Status Only the Applications with the current status
+	Status *ApplicationStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// OlderThan Only the Applications created earlier than the duration ago (ex. "24h")
+	OlderThan *string `form:"older_than,omitempty" json:"older_than,omitempty"`
+
+	// NodeUid Only the Applications which Resource is allocated by the Node
+	NodeUid *openapi_types.UUID `form:"node_uid,omitempty" json:"node_uid,omitempty"`
+
+	// Project Only the Applications of the Project, the Project maintainer gets all the Project Applications instead of just the owned ones
+	Project *string `form:"project,omitempty" json:"project,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+
+	// PageSize Amount of the objects on the page (max 1000), the next page token is returned in `X-Next-Page-Token` header. All the objects are returned if not set.
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// PageToken Token of the page from `X-Next-Page-Token` header of the previous page
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Sort Comma-separated list of the indexed fields to sort by, `-` prefix means descending order (ex. "-created_at")
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// ApplicationFullListGetParams defines parameters for ApplicationFullListGet.
+type ApplicationFullListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// OwnerName Only the Applications of the owner
+	OwnerName *string `form:"owner_name,omitempty" json:"owner_name,omitempty"`
+
+	// LabelUid Only the Applications of the Label
+	LabelUid *openapi_types.UUID `form:"label_uid,omitempty" json:"label_uid,omitempty"`
+
+	// Status Only the Applications with the current status
+	Status *ApplicationStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// OlderThan Only the Applications created earlier than the duration ago (ex. "24h")
+	OlderThan *string `form:"older_than,omitempty" json:"older_than,omitempty"`
+
+	// NodeUid Only the Applications which Resource is allocated by the Node
+	NodeUid *openapi_types.UUID `form:"node_uid,omitempty" json:"node_uid,omitempty"`
+
+	// Project Only the Applications of the Project, the Project maintainer gets all the Project Applications instead of just the owned ones
+	Project *string `form:"project,omitempty" json:"project,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+
+	// PageSize Amount of the objects on the page (max 1000), the next page token is returned in `X-Next-Page-Token` header. All the objects are returned if not set.
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// PageToken Token of the page from `X-Next-Page-Token` header of the previous page
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Sort Comma-separated list of the indexed fields to sort by, `-` prefix means descending order (ex. "-created_at")
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// ApplicationStateStreamGetParams defines parameters for ApplicationStateStreamGet.
+type ApplicationStateStreamGetParams struct {
+	// ApplicationUid Only the events of the Applications
+	ApplicationUid *[]openapi_types.UUID `form:"application_uid,omitempty" json:"application_uid,omitempty"`
+
+	// LabelUid Only the events of the Applications of the Labels
+	LabelUid *[]openapi_types.UUID `form:"label_uid,omitempty" json:"label_uid,omitempty"`
+
+	// OwnerName Only the events of the Applications of the owners
+	OwnerName *[]string `form:"owner_name,omitempty" json:"owner_name,omitempty"`
+
+	// Project Only the events of the Applications of the Projects, the Project maintainer receives the events of all the Project Applications
+	Project *[]string `form:"project,omitempty" json:"project,omitempty"`
+
+	// Status Only the events with the Application statuses, the annotation and task events are matched by the current status of the Application
+	Status *[]ApplicationStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// ResumeFrom Sequence number of the last received event, the node keeps the last 10000 events to resume from. If they are not available anymore (or the node was restarted) - `410` is returned and the client need to re-read the current state and connect without it.
+	ResumeFrom *int64 `form:"resume_from,omitempty" json:"resume_from,omitempty"`
+}
+
+// ApplicationRejectGetParams defines parameters for ApplicationRejectGet.
+type ApplicationRejectGetParams struct {
+	// Reason Why the Application was rejected
+	Reason *string `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// ApplicationTaskListGetParams defines parameters for ApplicationTaskListGet.
+type ApplicationTaskListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// AuditRecordListGetParams defines parameters for AuditRecordListGet.
+type AuditRecordListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// ApplicationBatchListGetParams defines parameters for ApplicationBatchListGet.
+type ApplicationBatchListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// ImageListGetParams defines parameters for ImageListGet.
+type ImageListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// ImageBuildListGetParams defines parameters for ImageBuildListGet.
+type ImageBuildListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// LabelListGetParams defines parameters for LabelListGet.
+type LabelListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+
+	// PageSize Amount of the objects on the page (max 1000), the next page token is returned in `X-Next-Page-Token` header. All the objects are returned if not set.
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// PageToken Token of the page from `X-Next-Page-Token` header of the previous page
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Sort Comma-separated list of the indexed fields to sort by, `-` prefix means descending order (ex. "-created_at")
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// LabelExportGetParams defines parameters for LabelExportGet.
+type LabelExportGetParams struct {
+	// Label Label `<name>` to export the latest version or `<name>:<version>`
+	Label []string `form:"label" json:"label"`
+}
+
+// LabelImportPostParams defines parameters for LabelImportPost.
+type LabelImportPostParams struct {
+	// OnConflict How to resolve the existing Label version with different content
+	OnConflict *LabelImportPostParamsOnConflict `form:"on_conflict,omitempty" json:"on_conflict,omitempty"`
+
+	// DryRun Only report what will be changed
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// LabelImportPostParamsOnConflict defines parameters for LabelImportPost.
+type LabelImportPostParamsOnConflict string
+
+// LabelStatsGetParams defines parameters for LabelStatsGet.
+type LabelStatsGetParams struct {
+	// Name Name of the Label to get the versions stats, all the Labels if not set
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// LocationListGetParams defines parameters for LocationListGet.
+type LocationListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// NodeListGetParams defines parameters for NodeListGet.
+type NodeListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+
+	// PageSize Amount of the objects on the page (max 1000), the next page token is returned in `X-Next-Page-Token` header. All the objects are returned if not set.
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// PageToken Token of the page from `X-Next-Page-Token` header of the previous page
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Sort Comma-separated list of the indexed fields to sort by, `-` prefix means descending order (ex. "-created_at")
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// NodeThisDriverCapabilitiesGetParams defines parameters for NodeThisDriverCapabilitiesGet.
+type NodeThisDriverCapabilitiesGetParams struct {
+	// Name Name of the driver instance (ex. "aws/prod")
+	Name string `form:"name" json:"name"`
+}
+
+// NodeThisDriverForecastGetParams defines parameters for NodeThisDriverForecastGet.
+type NodeThisDriverForecastGetParams struct {
+	// Name Name of the driver instance (ex. "aws/prod")
+	Name string `form:"name" json:"name"`
+}
+
+// NodeThisDriverInfoSchemaGetParams defines parameters for NodeThisDriverInfoSchemaGet.
+type NodeThisDriverInfoSchemaGetParams struct {
+	// Name Name of the driver instance (ex. "aws/prod")
+	Name string `form:"name" json:"name"`
+}
+
+// NodeThisDriverRestartGetParams defines parameters for NodeThisDriverRestartGet.
+type NodeThisDriverRestartGetParams struct {
+	// Name Name of the driver instance (ex. "aws/prod")
+	Name string `form:"name" json:"name"`
+}
+
+// NodeThisMaintenanceGetParams defines parameters for NodeThisMaintenanceGet.
+type NodeThisMaintenanceGetParams struct {
+	// Enable Enable or disable maintenance mode
+	Enable *bool `form:"enable,omitempty" json:"enable,omitempty"`
+
+	// Shutdown Safely stops the Node. It will wait in maintenance mode until all the Applicaions are
+	// done and then exit. If Node deployed as a service - then will be restarted immediately,
+	// otherwise will just stop. Could be cancelled by stop=false.
+	Shutdown *bool `form:"shutdown,omitempty" json:"shutdown,omitempty"`
+
+	// Handover During shutdown pass the Resources of the remote drivers (like cloud VMs which keep running without the Node) to the other active Nodes of the same location with the same healthy driver. The Resources of the local drivers are still waited for.
+	Handover *bool `form:"handover,omitempty" json:"handover,omitempty"`
+
+	// ShutdownDelay How much Node should wait in maintenance mode before exit (ex. "1h10m30s")
+	ShutdownDelay *string `form:"shutdown_delay,omitempty" json:"shutdown_delay,omitempty"`
+}
+
+// NodeMaintenanceGetParams defines parameters for NodeMaintenanceGet.
+type NodeMaintenanceGetParams struct {
+	// Enable Enable or disable maintenance mode
+	Enable *bool `form:"enable,omitempty" json:"enable,omitempty"`
+
+	// Drain Deallocate the executing Applications after drain_timeout
+	Drain *bool `form:"drain,omitempty" json:"drain,omitempty"`
+
+	// DrainTimeout How long to wait for the Applications to complete (ex. "1h"), 0 deallocates them right away
+	DrainTimeout *string `form:"drain_timeout,omitempty" json:"drain_timeout,omitempty"`
+}
+
+// ResourceListGetParams defines parameters for ResourceListGet.
+type ResourceListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// ResourceAccessPutParams defines parameters for ResourceAccessPut.
+type ResourceAccessPutParams struct {
+	// Otp The User OTP code, required if the Resource Label has `access_otp` enabled
+	Otp *string `form:"otp,omitempty" json:"otp,omitempty"`
+}
+
+// ScheduleListGetParams defines parameters for ScheduleListGet.
+type ScheduleListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// ScheduleNextGetParams defines parameters for ScheduleNextGet.
+type ScheduleNextGetParams struct {
+	// Count Number of the next runs to return, 5 by default
+	Count *int `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// ServiceMappingListGetParams defines parameters for ServiceMappingListGet.
+type ServiceMappingListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// TemplateListGetParams defines parameters for TemplateListGet.
+type TemplateListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// UpgradeListGetParams defines parameters for UpgradeListGet.
+type UpgradeListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// UserListGetParams defines parameters for UserListGet.
+type UserListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+
+	// PageSize Amount of the objects on the page (max 1000), the next page token is returned in `X-Next-Page-Token` header. All the objects are returned if not set.
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// PageToken Token of the page from `X-Next-Page-Token` header of the previous page
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Sort Comma-separated list of the indexed fields to sort by, `-` prefix means descending order (ex. "-created_at")
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// VoteListGetParams defines parameters for VoteListGet.
+type VoteListGetParams struct {
+	// Filter SQL `WHERE` filter for the object data
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Report Serve the reporting query from the read-only DB replica (if enabled) to not affect the live database, the data could be stale up to `db_replica_sync_interval`
+	Report *bool `form:"report,omitempty" json:"report,omitempty"`
+}
+
+// DataGetListParams defines parameters for DataGetList.
+type DataGetListParams struct {
+	// Format Set the return format
+	Format *DataGetListParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Prefix Additional prefix for the key path if `format=env`
+	Prefix *string `form:"prefix,omitempty" json:"prefix,omitempty"`
+}
+
+// DataGetListParamsFormat defines parameters for DataGetList.
+type DataGetListParamsFormat string
+
+// DataGetParams defines parameters for DataGet.
+type DataGetParams struct {
+	// Format Set the return format
+	Format *DataGetParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Prefix Additional prefix for the key path if `format=env`
+	Prefix *string `form:"prefix,omitempty" json:"prefix,omitempty"`
+}
+
+// DataGetParamsFormat defines parameters for DataGet.
+type DataGetParamsFormat string
+
+// IdentityTokenGetParams defines parameters for IdentityTokenGet.
+type IdentityTokenGetParams struct {
+	// Audience Audience of the token, one of configured in the node, the first one by default
+	Audience *string `form:"audience,omitempty" json:"audience,omitempty"`
+}
+
+// VaultGetListParams defines parameters for VaultGetList.
+type VaultGetListParams struct {
+	// Format Set the return format
+	Format *VaultGetListParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Prefix Additional prefix for the key path if `format=env`
+	Prefix *string `form:"prefix,omitempty" json:"prefix,omitempty"`
+}
+
+// VaultGetListParamsFormat defines parameters for VaultGetList.
+type VaultGetListParamsFormat string
+
+// ApplicationCreatePostJSONRequestBody defines body for ApplicationCreatePost for application/json ContentType.
+type ApplicationCreatePostJSONRequestBody = Application
+
+// ApplicationDeallocateBatchPostJSONRequestBody defines body for ApplicationDeallocateBatchPost for application/json ContentType.
+type ApplicationDeallocateBatchPostJSONRequestBody = ApplicationDeallocateBatch
+
+// ApplicationExtendPostJSONRequestBody defines body for ApplicationExtendPost for application/json ContentType.
+type ApplicationExtendPostJSONRequestBody = ApplicationExtend
+
+// ApplicationSecretCreatePostJSONRequestBody defines body for ApplicationSecretCreatePost for application/json ContentType.
+type ApplicationSecretCreatePostJSONRequestBody = ApplicationSecret
+
+// ApplicationTaskCreatePostJSONRequestBody defines body for ApplicationTaskCreatePost for application/json ContentType.
+type ApplicationTaskCreatePostJSONRequestBody = ApplicationTask
+
+// ApplicationBatchCreatePostJSONRequestBody defines body for ApplicationBatchCreatePost for application/json ContentType.
+type ApplicationBatchCreatePostJSONRequestBody = ApplicationBatch
+
+// ImageCreatePostJSONRequestBody defines body for ImageCreatePost for application/json ContentType.
+type ImageCreatePostJSONRequestBody = Image
+
+// ImageBuildCreatePostJSONRequestBody defines body for ImageBuildCreatePost for application/json ContentType.
+type ImageBuildCreatePostJSONRequestBody = ImageBuild
+
+// LabelCreatePostJSONRequestBody defines body for LabelCreatePost for application/json ContentType.
+type LabelCreatePostJSONRequestBody = Label
+
+// LabelApplyPostJSONRequestBody defines body for LabelApplyPost for application/json ContentType.
+type LabelApplyPostJSONRequestBody = LabelApply
+
+// LabelImportPostJSONRequestBody defines body for LabelImportPost for application/json ContentType.
+type LabelImportPostJSONRequestBody = LabelBundle
+
+// LocationCreatePostJSONRequestBody defines body for LocationCreatePost for application/json ContentType.
+type LocationCreatePostJSONRequestBody = Location
+
+// ProjectCreateUpdatePostJSONRequestBody defines body for ProjectCreateUpdatePost for application/json ContentType.
+type ProjectCreateUpdatePostJSONRequestBody = Project
+
+// RoleCreateUpdatePostJSONRequestBody defines body for RoleCreateUpdatePost for application/json ContentType.
+type RoleCreateUpdatePostJSONRequestBody = Role
+
+// ScheduleCreatePostJSONRequestBody defines body for ScheduleCreatePost for application/json ContentType.
+type ScheduleCreatePostJSONRequestBody = Schedule
+
+// ServiceMappingCreatePostJSONRequestBody defines body for ServiceMappingCreatePost for application/json ContentType.
+type ServiceMappingCreatePostJSONRequestBody = ServiceMapping
+
+// SimulatorDedicatedPostJSONRequestBody defines body for SimulatorDedicatedPost for application/json ContentType.
+type SimulatorDedicatedPostJSONRequestBody = SimulatorRequest
+
+// SyncPostJSONRequestBody defines body for SyncPost for application/json ContentType.
+type SyncPostJSONRequestBody = SyncRequest
+
+// TemplateCreateUpdatePostJSONRequestBody defines body for TemplateCreateUpdatePost for application/json ContentType.
+type TemplateCreateUpdatePostJSONRequestBody = Template
+
+// UpgradeCreatePostJSONRequestBody defines body for UpgradeCreatePost for application/json ContentType.
+type UpgradeCreatePostJSONRequestBody = Upgrade
+
+// UserCreateUpdatePostJSONRequestBody defines body for UserCreateUpdatePost for application/json ContentType.
+type UserCreateUpdatePostJSONRequestBody = UserAPIPassword
+
+// PreferencePutJSONRequestBody defines body for PreferencePut for application/json ContentType.
+type PreferencePutJSONRequestBody = Preference
+
+// UserGrantCreatePostJSONRequestBody defines body for UserGrantCreatePost for application/json ContentType.
+type UserGrantCreatePostJSONRequestBody = RoleGrant
+
+// UserQuotaPutJSONRequestBody defines body for UserQuotaPut for application/json ContentType.
+type UserQuotaPutJSONRequestBody = Quota
+
+// UserSubscriptionCreatePostJSONRequestBody defines body for UserSubscriptionCreatePost for application/json ContentType.
+type UserSubscriptionCreatePostJSONRequestBody = Subscription
+
+// UserTokenCreatePostJSONRequestBody defines body for UserTokenCreatePost for application/json ContentType.
+type UserTokenCreatePostJSONRequestBody = UserToken
+
+// ActivityPostJSONRequestBody defines body for ActivityPost for application/json ContentType.
+type ActivityPostJSONRequestBody = ResourceActivity
+
+// SecretKeyPutJSONRequestBody defines body for SecretKeyPut for application/json ContentType.
+type SecretKeyPutJSONRequestBody = SecretKey
@@ -0,0 +1,65 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// GormDataType describes how to store UpgradeNodes in database
+func (UpgradeNodes) GormDataType() string {
+	return "blob"
+}
+
+// Scan converts the UpgradeNodes to json bytes
+func (un *UpgradeNodes) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Failed to unmarshal JSONB value: %s", value)
+	}
+
+	return json.Unmarshal(bytes, un)
+}
+
+// Value converts json bytes to UpgradeNodes
+func (un UpgradeNodes) Value() (driver.Value, error) {
+	if un == nil {
+		un = UpgradeNodes{}
+	}
+	return json.Marshal(un)
+}
+
+// GormDataType describes how to store UpgradeSteps in database
+func (UpgradeSteps) GormDataType() string {
+	return "blob"
+}
+
+// Scan converts the UpgradeSteps to json bytes
+func (us *UpgradeSteps) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Failed to unmarshal JSONB value: %s", value)
+	}
+
+	return json.Unmarshal(bytes, us)
+}
+
+// Value converts json bytes to UpgradeSteps
+func (us UpgradeSteps) Value() (driver.Value, error) {
+	if us == nil {
+		us = UpgradeSteps{}
+	}
+	return json.Marshal(us)
+}
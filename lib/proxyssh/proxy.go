@@ -17,17 +17,26 @@ import (
 	"io"
 	"net"
 	"os"
+	"path"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"golang.org/x/crypto/ssh"
 
 	"github.com/adobe/aquarium-fish/lib/crypt"
 	"github.com/adobe/aquarium-fish/lib/fish"
 	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/metrics"
 	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	"github.com/adobe/aquarium-fish/lib/secrets"
 )
 
+var metricConnections = metrics.NewGauge("fish_proxy_active_connections",
+	"Amount of the active streaming connections to the Resources", "type")
+
 // NOTE: This proxy was highly influenced by Remco Verhoef's ideas in
 // https://github.com/dutchcoders/sshproxy, but have a little to no similarity with its ancestor.
 
@@ -41,6 +50,9 @@ type proxySSH struct {
 
 	// Keeps session info for auth, key is src address, value is session
 	sessions sync.Map
+
+	// Sessions recorder, nil if recording is disabled
+	recorder *recorder
 }
 
 // session is stored in proxySSH::sessions.
@@ -48,6 +60,10 @@ type session struct {
 	ResourceAccessor *types.ResourceAccess
 	SrcAddr          net.Addr
 
+	fish     *fish.Fish
+	recorder *recorder
+	policy   types.ProxySSHPolicy
+
 	// This work group used to track the routines of the session
 	// to make sure everything shutdown properly
 	wg sync.WaitGroup
@@ -55,6 +71,8 @@ type session struct {
 
 func (p *proxySSH) serveConnection(clientConn net.Conn) error {
 	log.Infof("PROXYSSH: %s: Starting new session", clientConn.RemoteAddr())
+	metricConnections.Inc("ssh")
+	defer metricConnections.Dec("ssh")
 
 	// Establish SSH connection
 	srcConn, srcConnChannels, srcConnReqs, err := p.establishConnection(clientConn)
@@ -69,6 +87,8 @@ func (p *proxySSH) serveConnection(clientConn net.Conn) error {
 	if err != nil {
 		return log.Errorf("PROXYSSH: %s: Failed to get session: %v", clientConn.RemoteAddr(), err)
 	}
+	session.fish = p.fish
+	session.recorder = p.recorder
 
 	if session.ResourceAccessor == nil {
 		return log.Errorf("PROXYSSH: %s: No ResourceAccessor is set for the session", session.SrcAddr)
@@ -79,10 +99,18 @@ func (p *proxySSH) serveConnection(clientConn net.Conn) error {
 	if err != nil {
 		return log.Errorf("PROXYSSH: %s: Unable to retrieve Resource %s: %v", session.SrcAddr, session.ResourceAccessor.ResourceUID, err)
 	}
+	// Mark the Resource as used during the session to not preempt or expire it under the user,
+	// the session will be closed by the node when the Resource is deallocated
+	p.fish.ResourceSessionBegin(resource.UID, srcConn)
+	defer p.fish.ResourceSessionEnd(resource.UID, srcConn)
+
 	if resource.Authentication == nil || resource.Authentication.Username == "" && resource.Authentication.Password == "" {
 		return log.Errorf("PROXYSSH: %s: Resource Authentication not provided", session.SrcAddr)
 	}
 
+	// Restricting the user capabilities according to the Label or node policy
+	session.policy = p.fish.ResourceGetProxySSHPolicy(resource)
+
 	// Establish destination connection
 	dstConn, err := session.connectToDestination(resource)
 	if err != nil {
@@ -127,23 +155,46 @@ func (p *proxySSH) getSession(sessionID []byte) (*session, error) {
 }
 
 func (s *session) connectToDestination(res *types.Resource) (*ssh.Client, error) {
-	dstAddr := net.JoinHostPort(res.IpAddr, strconv.Itoa(res.Authentication.Port))
+	// The Label Authentication could contain secret references, they are resolved on every
+	// connection to not store the secrets in the database and to pick up the rotated ones
+	auth := *res.Authentication
+	if err := secrets.ResolveFields(&auth.Username, &auth.Password, &auth.Key); err != nil {
+		return nil, log.Errorf("PROXYSSH: %s: Unable to resolve Resource Authentication: %v", s.SrcAddr, err)
+	}
+
+	dstAddr := net.JoinHostPort(res.IpAddr, strconv.Itoa(auth.Port))
 	dstConfig := &ssh.ClientConfig{
-		User:            res.Authentication.Username,
+		User:            auth.Username,
 		Auth:            []ssh.AuthMethod{},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // #nosec G106 , remote always have new hostkey by design
 	}
 
+	// If we know the host key of the resource - it's published to the users, so checking it. The
+	// images could regenerate the host keys on the first boot, so the changed key is re-pinned
+	// instead of breaking the access and the users will see the new fingerprint.
+	if res.HostKeyFingerprint != "" {
+		dstConfig.HostKeyCallback = func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != res.HostKeyFingerprint {
+				log.Warnf("PROXYSSH: %s: Host key of Resource %s changed, re-pinning: %s != %s", s.SrcAddr, res.UID, fingerprint, res.HostKeyFingerprint)
+				if err := s.fish.ResourceSetHostKeyFingerprint(res.UID, fingerprint); err != nil {
+					return fmt.Errorf("Unable to re-pin the host key fingerprint: %w", err)
+				}
+				res.HostKeyFingerprint = fingerprint
+			}
+			return nil
+		}
+	}
+
 	// Use password auth if password is set for the Resource
-	if res.Authentication.Password != "" {
-		dstConfig.Auth = append(dstConfig.Auth, ssh.Password(res.Authentication.Password))
+	if auth.Password != "" {
+		dstConfig.Auth = append(dstConfig.Auth, ssh.Password(auth.Password))
 	}
 
 	// Use private key if it's set for the Resource
-	if res.Authentication.Key != "" {
-		signer, err := ssh.ParsePrivateKey([]byte(res.Authentication.Key))
+	if auth.Key != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(auth.Key))
 		if err != nil {
-			return nil, log.Errorf("PROXYSSH: %s: Unable to parse private key len %d: %v", s.SrcAddr, len(res.Authentication.Key), err)
+			return nil, log.Errorf("PROXYSSH: %s: Unable to parse private key len %d: %v", s.SrcAddr, len(auth.Key), err)
 		}
 		dstConfig.Auth = append(dstConfig.Auth, ssh.PublicKeys(signer))
 	}
@@ -169,6 +220,12 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 	defer s.wg.Done()
 	log.Debugf("PROXYSSH: %s: Handling new channel: %s", s.SrcAddr, ch.ChannelType())
 
+	if !s.isChannelAllowed(ch.ChannelType()) {
+		log.Warnf("PROXYSSH: %s: Channel %q is denied by policy", s.SrcAddr, ch.ChannelType())
+		ch.Reject(ssh.Prohibited, "Denied by policy")
+		return
+	}
+
 	dstChn, dstChnRequests, dstChnErr := dstConn.OpenChannel(ch.ChannelType(), ch.ExtraData())
 	if dstChnErr != nil {
 		log.Errorf("PROXYSSH: %s: Could not open channel to destination: %v", s.SrcAddr, dstChnErr)
@@ -184,17 +241,36 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 		return
 	}
 
+	// Recording the session channel if needed, will be added to audit when channel is complete
+	var srcReader, dstReader io.Reader = srcChn, dstChn
+	rec := s.recorder.newChannelRecord(s, ch.ChannelType())
+	if rec != nil {
+		defer rec.Close()
+		srcReader, dstReader = rec.TeeInput(srcChn), rec.TeeOutput(dstChn)
+	}
+
 	// Need this local channel work group to wait until all the channel routines completed
 	var chWg sync.WaitGroup
 
+	// The destination sends exit-status after the last data, but the requests are processed
+	// separately from the stream, so need to know when the output is completely delivered
+	dstCopied := make(chan struct{})
+
 	// Proxying the requests
 	chWg.Add(1)
 	go func() {
 		defer chWg.Done()
 
 		// End the communication between the source and destination when this function is complete.
-		defer srcChn.Close()
-		defer dstChn.Close()
+		var exitForwarded chan struct{}
+		defer func() {
+			// The exit-status is forwarded in background, so it should be sent before closing
+			if exitForwarded != nil {
+				<-exitForwarded
+			}
+			dstChn.Close()
+			srcChn.Close()
+		}()
 
 		log.Debugf("PROXYSSH: %s: Starting to listen for channel requests", s.SrcAddr)
 		for {
@@ -205,15 +281,48 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 			case request = <-srcChnRequests:
 				//log.Debugf("PROXYSSH: %s: Received src channel request: %v", s.SrcAddr, request)
 				targetChannel = dstChn
+				if request != nil && !s.isChannelRequestAllowed(request) {
+					log.Warnf("PROXYSSH: %s: Channel request %q is denied by policy", s.SrcAddr, request.Type)
+					if request.WantReply {
+						request.Reply(false, nil)
+					}
+					continue
+				}
+				rec.HandleRequest(request)
 			case request = <-dstChnRequests:
 				//log.Debugf("PROXYSSH: %s: Received dst channel request: %v", s.SrcAddr, request)
 				targetChannel = srcChn
+				if request != nil && request.Type == "exit-status" && exitForwarded == nil {
+					// Closing the channels right after exit-status will drop the not yet copied
+					// output, so waiting for it in background to not block the other requests
+					exitForwarded = make(chan struct{})
+					go func(request *ssh.Request, done chan struct{}) {
+						defer close(done)
+						select {
+						case <-dstCopied:
+						case <-time.After(5 * time.Second):
+							log.Warnf("PROXYSSH: %s: Timeout waiting for the dst->src stream to complete", s.SrcAddr)
+						}
+						requestValid, err := srcChn.SendRequest(request.Type, request.WantReply, request.Payload)
+						if err != nil {
+							log.Errorf("PROXYSSH: %s: SendRequest error: %v", s.SrcAddr, err)
+						} else if request.WantReply {
+							request.Reply(requestValid, nil)
+						}
+						log.Debugf("PROXYSSH: %s: Request: Type=%q, WantReply='%t'.", s.SrcAddr, request.Type, request.WantReply)
+						// Ending the channel requests processing
+						dstChn.Close()
+					}(request, exitForwarded)
+					continue
+				}
 			}
 
 			// In the event that an SSH request gets killed (not exited),
 			// the request will be nil. Do not continue, exit the loop.
 			if request == nil {
-				log.Warnf("PROXYSSH: %s: SSH connection terminated ungracefully...", s.SrcAddr)
+				if exitForwarded == nil {
+					log.Warnf("PROXYSSH: %s: SSH connection terminated ungracefully...", s.SrcAddr)
+				}
 				break
 			}
 
@@ -231,10 +340,6 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 			}
 
 			log.Debugf("PROXYSSH: %s: Request: Type=%q, WantReply='%t'.", s.SrcAddr, request.Type, request.WantReply)
-			if request.Type == "exit-status" {
-				// Ending the channel requests processing
-				break
-			}
 		}
 
 		log.Debugf("PROXYSSH: %s: Stopped to listen for the channel requests", s.SrcAddr)
@@ -246,11 +351,12 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 	go func() {
 		defer chWg.Done()
 		log.Debugf("PROXYSSH: %s: Starting dst->src stream copy", s.SrcAddr)
-		if _, err := io.Copy(srcChn, dstChn); err != nil && err != io.EOF {
+		if _, err := io.Copy(srcChn, dstReader); err != nil && err != io.EOF {
 			log.Errorf("PROXYSSH: %s: The dst->src channel was closed unexpectedly: %v", s.SrcAddr, err)
 		} else {
 			log.Debugf("PROXYSSH: %s: The dst->src channel was closed: %v", s.SrcAddr, err)
 		}
+		close(dstCopied)
 		// Properly closing the channel
 		if err := dstChn.CloseWrite(); err != nil {
 			log.Warnf("PROXYSSH: %s: The dst->src closing write for dst channel did not go well: %v", s.SrcAddr, err)
@@ -260,7 +366,7 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 		}
 	}()
 
-	if _, err := io.Copy(dstChn, srcChn); err != nil && err != io.EOF {
+	if _, err := io.Copy(dstChn, srcReader); err != nil && err != io.EOF {
 		log.Errorf("PROXYSSH: %s: The src->dst channel was closed unexpectedly: %v", s.SrcAddr, err)
 	} else {
 		log.Debugf("PROXYSSH: %s: The src->dst channel was closed", s.SrcAddr)
@@ -280,6 +386,15 @@ func (s *session) handleChannel(ch ssh.NewChannel, dstConn ssh.Conn) {
 func (s *session) handleRequest(r *ssh.Request, c *ssh.Client) {
 	log.Debugf("PROXYSSH: %s: Handling src request: %s", s.SrcAddr, r.Type)
 
+	// Remote port forwarding is requested via global requests
+	if !s.isGlobalRequestAllowed(r.Type) {
+		log.Warnf("PROXYSSH: %s: Request %q is denied by policy", s.SrcAddr, r.Type)
+		if r.WantReply {
+			r.Reply(false, nil)
+		}
+		return
+	}
+
 	// Proxy to destination
 	ok, data, err := c.SendRequest(r.Type, r.WantReply, r.Payload)
 	if nil != err {
@@ -296,6 +411,74 @@ func (s *session) handleRequest(r *ssh.Request, c *ssh.Client) {
 	}
 }
 
+// isGlobalRequestAllowed checks the connection global request against the session policy
+func (s *session) isGlobalRequestAllowed(reqType string) bool {
+	if !s.policy.DenyPortForward && !s.policy.ShellOnly {
+		return true
+	}
+	switch reqType {
+	case "tcpip-forward", "cancel-tcpip-forward",
+		"streamlocal-forward@openssh.com", "cancel-streamlocal-forward@openssh.com":
+		return false
+	}
+	return true
+}
+
+// isChannelAllowed checks the new channel type against the session policy
+func (s *session) isChannelAllowed(chType string) bool {
+	if s.policy.ShellOnly {
+		return chType == "session"
+	}
+	if s.policy.DenyPortForward && (chType == "direct-tcpip" || chType == "direct-streamlocal@openssh.com") {
+		return false
+	}
+	return true
+}
+
+// isChannelRequestAllowed checks the session channel request against the session policy
+func (s *session) isChannelRequestAllowed(r *ssh.Request) bool {
+	switch r.Type {
+	case "subsystem":
+		if s.policy.ShellOnly {
+			return false
+		}
+		var sub struct{ Name string }
+		if err := ssh.Unmarshal(r.Payload, &sub); err != nil {
+			return false
+		}
+		return !(s.policy.DenySftp && sub.Name == "sftp")
+	case "exec":
+		if s.policy.ShellOnly {
+			return false
+		}
+		if s.policy.DenySftp {
+			var exec struct{ Command string }
+			if err := ssh.Unmarshal(r.Payload, &exec); err != nil {
+				return false
+			}
+			return !isFileTransferCommand(exec.Command)
+		}
+	case "x11-req", "auth-agent-req@openssh.com":
+		return !s.policy.ShellOnly
+	}
+	return true
+}
+
+// isFileTransferCommand checks if the command runs the server side of sftp or scp, which could
+// be used to bypass the denied sftp subsystem
+func isFileTransferCommand(command string) bool {
+	fields := strings.FieldsFunc(command, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`;&|()'"`+"`", r)
+	})
+	for _, field := range fields {
+		switch path.Base(field) {
+		case "sftp-server", "internal-sftp", "scp":
+			return true
+		}
+	}
+	return false
+}
+
 func (p *proxySSH) passwordCallback(incomingConn ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
 	user := incomingConn.User()
 	log.Debugf("PROXYSSH: %s: Login attempt for user %q.", incomingConn.RemoteAddr(), user)
@@ -319,11 +502,7 @@ func (p *proxySSH) passwordCallback(incomingConn ssh.ConnMetadata, pass []byte)
 
 	// Only return non-error if the username and password match (double check just in case)
 	if ra.Username == user && ra.Password == passHashStr {
-		srcAddr := incomingConn.RemoteAddr()
-		// If the session is not already stored in our map, create it so that
-		// we have access to it when processing the incoming connections.
-		p.sessions.LoadOrStore(string(incomingConn.SessionID()), &session{SrcAddr: srcAddr, ResourceAccessor: ra})
-		return nil, nil
+		return p.authorize(incomingConn, ra)
 	}
 
 	// Otherwise, we have failed, return error to indicate as such.
@@ -350,19 +529,58 @@ func (p *proxySSH) publicKeyCallback(incomingConn ssh.ConnMetadata, key ssh.Publ
 
 	// Only return non-error if the username and key match (double check just in case)
 	if ra.Username == user && ra.Key == stringKey {
-		srcAddr := incomingConn.RemoteAddr()
-		// If the session is not already stored in our map, create it so that
-		// we have access to it when processing the incoming connections.
-		p.sessions.LoadOrStore(string(incomingConn.SessionID()), &session{SrcAddr: srcAddr, ResourceAccessor: ra})
-		return nil, nil
+		return p.authorize(incomingConn, ra)
 	}
 
 	// Otherwise, we have failed, return error to indicate as such.
 	return nil, fmt.Errorf("Invalid access")
 }
 
+// authorize completes the authentication with the valid ResourceAccess, the sensitive Labels
+// require the User OTP code as the second step with keyboard-interactive prompt
+func (p *proxySSH) authorize(incomingConn ssh.ConnMetadata, ra *types.ResourceAccess) (*ssh.Permissions, error) {
+	res, err := p.fish.ResourceGet(ra.ResourceUID)
+	if err != nil {
+		log.Errorf("PROXYSSH: %s: Unable to find Resource %s: %v", incomingConn.RemoteAddr(), ra.ResourceUID, err)
+		return nil, fmt.Errorf("Invalid access")
+	}
+	otp, err := p.fish.ApplicationAccessOTP(res.ApplicationUID)
+	if err != nil {
+		log.Errorf("PROXYSSH: %s: Unable to check the access policy: %v", incomingConn.RemoteAddr(), err)
+		return nil, fmt.Errorf("Invalid access")
+	}
+
+	// If the session is not already stored in our map, create it so that
+	// we have access to it when processing the incoming connections.
+	store := func() {
+		p.sessions.LoadOrStore(string(incomingConn.SessionID()), &session{SrcAddr: incomingConn.RemoteAddr(), ResourceAccessor: ra})
+	}
+	if !otp {
+		store()
+		return nil, nil
+	}
+
+	// The one-time credentials are already used, so the failed OTP requires the new access
+	return nil, &ssh.PartialSuccessError{Next: ssh.ServerAuthCallbacks{
+		KeyboardInteractiveCallback: func(conn ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge("", "The Resource requires the second factor", []string{"OTP code: "}, []bool{true})
+			if err != nil || len(answers) != 1 {
+				return nil, fmt.Errorf("Invalid access")
+			}
+			if err := p.fish.UserOTPCheck(ra.Username, strings.TrimSpace(answers[0])); err != nil {
+				log.Warnf("PROXYSSH: %s: User %q failed OTP check: %v", conn.RemoteAddr(), ra.Username, err)
+				return nil, fmt.Errorf("Invalid access")
+			}
+			store()
+			return nil, nil
+		},
+	}}
+}
+
 // Init starts SSH proxy and returns the actual listening address and error if happened
-func Init(f *fish.Fish, idRsaPath string, address string) (string, error) {
+// If recordPath is not empty - the user sessions will be recorded and kept for retention time,
+// the user input is recorded only if recordInput is set
+func Init(f *fish.Fish, idRsaPath string, address string, recordPath string, retention time.Duration, recordInput bool) (string, error) {
 	// First, try and read the file if it exists already. Otherwise, it is the
 	// first execution, generate the private / public keys. The SSH server
 	// requires at least one identity loaded to run.
@@ -390,12 +608,19 @@ func Init(f *fish.Fish, idRsaPath string, address string) (string, error) {
 	}
 
 	server := proxySSH{fish: f}
+	if recordPath != "" {
+		if server.recorder, err = newRecorder(recordPath, retention, recordInput); err != nil {
+			return "", err
+		}
+		log.Info("PROXYSSH: Sessions recording enabled:", recordPath)
+	}
 	server.serverConfig = &ssh.ServerConfig{
 		ServerVersion:     "SSH-2.0-AquariumFishProxy",
 		PasswordCallback:  server.passwordCallback,
 		PublicKeyCallback: server.publicKeyCallback,
 	}
 	server.serverConfig.AddHostKey(private)
+	f.SetProxySSHFingerprint(ssh.FingerprintSHA256(private.PublicKey()))
 
 	// Create the listener and let it wait for new connections in a separated goroutine
 	listener, err := net.Listen("tcp", address)
@@ -0,0 +1,91 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package proxyssh
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func Test_proxy_policy(t *testing.T) {
+	sftp := &ssh.Request{Type: "subsystem", Payload: ssh.Marshal(struct{ Name string }{"sftp"})}
+	exec := &ssh.Request{Type: "exec", Payload: ssh.Marshal(struct{ Command string }{"ls"})}
+	execSftp := &ssh.Request{Type: "exec", Payload: ssh.Marshal(struct{ Command string }{"/usr/lib/openssh/sftp-server -e"})}
+	execScp := &ssh.Request{Type: "exec", Payload: ssh.Marshal(struct{ Command string }{"cd /tmp && scp -t ."})}
+	shell := &ssh.Request{Type: "shell"}
+
+	tests := []struct {
+		name    string
+		policy  types.ProxySSHPolicy
+		forward bool
+		x11     bool
+		sftp    bool
+		exec    bool
+	}{
+		{"default", types.ProxySSHPolicy{}, true, true, true, true},
+		{"deny_port_forward", types.ProxySSHPolicy{DenyPortForward: true}, false, true, true, true},
+		{"deny_sftp", types.ProxySSHPolicy{DenySftp: true}, true, true, false, true},
+		{"shell_only", types.ProxySSHPolicy{ShellOnly: true}, false, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &session{policy: tt.policy}
+			if !s.isChannelAllowed("session") || !s.isChannelRequestAllowed(shell) {
+				t.Fatalf("Shell session is denied; want: allowed")
+			}
+			for _, chType := range []string{"direct-tcpip", "direct-streamlocal@openssh.com"} {
+				if got := s.isChannelAllowed(chType); got != tt.forward {
+					t.Fatalf("isChannelAllowed(%s) = %v; want: %v", chType, got, tt.forward)
+				}
+			}
+			for _, reqType := range []string{"tcpip-forward", "cancel-tcpip-forward", "streamlocal-forward@openssh.com", "cancel-streamlocal-forward@openssh.com"} {
+				if got := s.isGlobalRequestAllowed(reqType); got != tt.forward {
+					t.Fatalf("isGlobalRequestAllowed(%s) = %v; want: %v", reqType, got, tt.forward)
+				}
+			}
+			if !s.isGlobalRequestAllowed("keepalive@openssh.com") {
+				t.Fatalf("isGlobalRequestAllowed(keepalive@openssh.com) = false; want: true")
+			}
+			if got := s.isChannelAllowed("x11"); got != tt.x11 {
+				t.Fatalf("isChannelAllowed(x11) = %v; want: %v", got, tt.x11)
+			}
+			for _, req := range []*ssh.Request{sftp, execSftp, execScp} {
+				if got := s.isChannelRequestAllowed(req); got != tt.sftp {
+					t.Fatalf("isChannelRequestAllowed(%s %q) = %v; want: %v", req.Type, req.Payload[4:], got, tt.sftp)
+				}
+			}
+			if got := s.isChannelRequestAllowed(exec); got != tt.exec {
+				t.Fatalf("isChannelRequestAllowed(exec) = %v; want: %v", got, tt.exec)
+			}
+		})
+	}
+}
+
+func Test_proxy_file_transfer_command(t *testing.T) {
+	tests := map[string]bool{
+		"ls -la":                          false,
+		"scp -f /etc/hosts":               true,
+		"/usr/libexec/sftp-server":        true,
+		"sh -c 'internal-sftp'":           true,
+		"echo scpx; cat /tmp/description": false,
+		"bash -c \"cd /tmp;scp -t .\"":    true,
+	}
+	for command, want := range tests {
+		if got := isFileTransferCommand(command); got != want {
+			t.Fatalf("isFileTransferCommand(%q) = %v; want: %v", command, got, want)
+		}
+	}
+}
@@ -0,0 +1,601 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package proxyssh
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/metrics"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+var metricAuditBroken = metrics.NewCounter("fish_proxy_audit_chain_broken_total",
+	"Amount of the broken recordings audit chains found on the node start")
+
+// SFTP packet types we're interested in to build the transfer manifest
+// https://datatracker.ietf.org/doc/html/draft-ietf-secsh-filexfer-02
+const (
+	sftpPacketOpen   = 3
+	sftpPacketRemove = 13
+	sftpPacketMkdir  = 14
+	sftpPacketRmdir  = 15
+	sftpPacketRename = 18
+
+	sftpFlagRead  = 0x00000001
+	sftpFlagWrite = 0x00000002
+
+	// Max packet size OpenSSH sftp-server accepts, the bigger ones are treated as garbage
+	sftpMaxPacketSize = 256 * 1024
+)
+
+// recorder keeps the state of the session recordings storage
+//
+// The recordings are stored as `<path>/<ResourceUID>/<time>-<channel>.cast` in asciinema v2
+// format, SFTP sessions are stored as `.sftp.json` manifest. Each finished record is added to the
+// `<path>/audit.log` HMAC chain, so the modification of the records or audit log can be detected.
+// The chain key is stored in `<path>/audit.key` encrypted by the node master key, so the one who
+// can write the recordings can't recompute the chain without access to the master key.
+// The user input is recorded only when enabled, since it usually contains the typed passwords.
+type recorder struct {
+	path      string
+	retention time.Duration
+	input     bool
+	done      chan struct{}
+
+	auditMutex    sync.Mutex
+	auditKey      []byte
+	auditLastHash string
+}
+
+// auditRecord is a line of the audit log chain
+type auditRecord struct {
+	Time     time.Time         `json:"time"`
+	Resource types.ResourceUID `json:"resource_UID"`
+	User     string            `json:"user"`
+	SrcAddr  string            `json:"src_addr"`
+	File     string            `json:"file"`
+	FileHash string            `json:"file_hash,omitempty"`
+	Removed  bool              `json:"removed,omitempty"` // Marks the record files were removed by retention
+	Broken   string            `json:"broken,omitempty"`  // Reason why the previous chain (in File) was broken
+	PrevHash string            `json:"prev_hash"`
+	Hash     string            `json:"hash"`
+}
+
+// sftpManifestRecord describes one file operation happened during the SFTP session
+type sftpManifestRecord struct {
+	Time   time.Time `json:"time"`
+	Op     string    `json:"op"`
+	Path   string    `json:"path"`
+	Target string    `json:"target,omitempty"`
+}
+
+// channelRecord writes the typescript or sftp manifest of one proxied channel
+type channelRecord struct {
+	rec      *recorder
+	session  *session
+	mutex    sync.Mutex
+	filePath string
+	file     *os.File
+	start    time.Time
+
+	width  uint32
+	height uint32
+
+	isSftp     bool
+	sftpBuf    []byte
+	sftpBroken bool
+	sftpOps    []sftpManifestRecord
+}
+
+func newRecorder(path string, retention time.Duration, input bool) (*recorder, error) {
+	if err := os.MkdirAll(path, 0o750); err != nil {
+		return nil, fmt.Errorf("PROXYSSH: Unable to create recordings directory %q: %w", path, err)
+	}
+	r := &recorder{path: path, retention: retention, input: input, done: make(chan struct{})}
+
+	var err error
+	if r.auditKey, err = loadAuditKey(filepath.Join(path, "audit.key")); err != nil {
+		return nil, err
+	}
+
+	// Verifying the existing audit chain and restoring the last hash to continue it
+	auditPath := filepath.Join(path, "audit.log")
+	data, err := os.ReadFile(auditPath)
+	if err == nil {
+		var verifyErr error
+		if r.auditLastHash, verifyErr = verifyAuditChain(r.auditKey, data); verifyErr != nil {
+			// The broken chain should not stop the proxy, so moving it aside for investigation
+			// and starting the new one with the record about the break
+			metricAuditBroken.Inc()
+			brokenPath := fmt.Sprintf("%s.corrupted-%s", auditPath, time.Now().UTC().Format("20060102T150405"))
+			log.Errorf("PROXYSSH: Recordings audit log is corrupted, moving it to %q: %v", brokenPath, verifyErr)
+			if err := os.Rename(auditPath, brokenPath); err != nil {
+				return nil, fmt.Errorf("PROXYSSH: Unable to move corrupted audit log: %w", err)
+			}
+			r.auditLastHash = ""
+			if err := r.auditAppend(auditRecord{File: brokenPath, Broken: verifyErr.Error()}); err != nil {
+				return nil, fmt.Errorf("PROXYSSH: Unable to record the audit chain break: %w", err)
+			}
+		}
+	}
+
+	if r.retention > 0 {
+		go r.cleanupProcess()
+	}
+
+	return r, nil
+}
+
+// Close stops the recorder background processes
+func (r *recorder) Close() {
+	if r == nil {
+		return
+	}
+	close(r.done)
+}
+
+// newChannelRecord prepares the record of the session channel, file will be created on first write
+func (r *recorder) newChannelRecord(s *session, chType string) *channelRecord {
+	if r == nil || chType != "session" {
+		return nil
+	}
+	now := time.Now()
+	return &channelRecord{
+		rec:      r,
+		session:  s,
+		filePath: filepath.Join(r.path, s.ResourceAccessor.ResourceUID.String(), fmt.Sprintf("%s-%x", now.UTC().Format("20060102T150405.000000"), now.UnixNano()&0xffff)),
+		start:    now,
+		width:    80,
+		height:   24,
+	}
+}
+
+// HandleRequest observes the channel requests to get the information about session
+func (cr *channelRecord) HandleRequest(req *ssh.Request) {
+	if cr == nil || req == nil {
+		return
+	}
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	switch req.Type {
+	case "pty-req":
+		var pty struct {
+			Term    string
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+			Modes   string
+		}
+		if err := ssh.Unmarshal(req.Payload, &pty); err == nil {
+			cr.width, cr.height = pty.Columns, pty.Rows
+		}
+	case "window-change":
+		var win struct {
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+		}
+		if err := ssh.Unmarshal(req.Payload, &win); err == nil && cr.file != nil {
+			cr.writeEvent("r", fmt.Sprintf("%dx%d", win.Columns, win.Rows))
+		}
+	case "subsystem":
+		var sub struct{ Name string }
+		if err := ssh.Unmarshal(req.Payload, &sub); err == nil && sub.Name == "sftp" {
+			cr.isSftp = true
+		}
+	case "exec":
+		var exec struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &exec); err == nil {
+			cr.writeEvent("m", "exec: "+exec.Command)
+		}
+	}
+}
+
+// TeeInput returns reader which records the data sent by the user
+func (cr *channelRecord) TeeInput(r io.Reader) io.Reader {
+	if cr == nil {
+		return r
+	}
+	return io.TeeReader(r, recordWriter{cr, "i"})
+}
+
+// TeeOutput returns reader which records the data received from the resource
+func (cr *channelRecord) TeeOutput(r io.Reader) io.Reader {
+	if cr == nil {
+		return r
+	}
+	return io.TeeReader(r, recordWriter{cr, "o"})
+}
+
+// WrapInput returns writer which records the data sent by the user to w
+func (cr *channelRecord) WrapInput(w io.Writer) io.Writer {
+	if cr == nil {
+		return w
+	}
+	return io.MultiWriter(recordWriter{cr, "i"}, w)
+}
+
+type recordWriter struct {
+	cr    *channelRecord
+	event string
+}
+
+// Write never fails to not interrupt the proxied stream
+func (w recordWriter) Write(p []byte) (int, error) {
+	w.cr.mutex.Lock()
+	defer w.cr.mutex.Unlock()
+
+	if w.cr.isSftp {
+		// Only the client requests are needed to build the manifest
+		if w.event == "i" {
+			w.cr.parseSftp(p)
+		}
+		return len(p), nil
+	}
+	if w.event == "i" && !w.cr.rec.input {
+		return len(p), nil
+	}
+	w.cr.writeEvent(w.event, string(p))
+
+	return len(p), nil
+}
+
+// writeEvent stores asciinema v2 event, the mutex should be locked already
+func (cr *channelRecord) writeEvent(event, data string) {
+	if cr.file == nil {
+		if err := os.MkdirAll(filepath.Dir(cr.filePath), 0o750); err != nil {
+			log.Errorf("PROXYSSH: %s: Unable to create record directory: %v", cr.session.SrcAddr, err)
+			return
+		}
+		f, err := os.OpenFile(cr.filePath+".cast", os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o640)
+		if err != nil {
+			log.Errorf("PROXYSSH: %s: Unable to create record file: %v", cr.session.SrcAddr, err)
+			return
+		}
+		cr.file = f
+		header, _ := json.Marshal(map[string]any{
+			"version":   2,
+			"width":     cr.width,
+			"height":    cr.height,
+			"timestamp": cr.start.Unix(),
+			"env":       map[string]string{"FISH_RESOURCE": cr.session.ResourceAccessor.ResourceUID.String()},
+		})
+		cr.file.Write(append(header, '\n'))
+	}
+	line, _ := json.Marshal([]any{time.Since(cr.start).Seconds(), event, data})
+	cr.file.Write(append(line, '\n'))
+}
+
+// parseSftp collects the client SFTP packets and records the file operations
+func (cr *channelRecord) parseSftp(p []byte) {
+	if cr.sftpBroken {
+		return
+	}
+	cr.sftpBuf = append(cr.sftpBuf, p...)
+	for len(cr.sftpBuf) >= 5 {
+		length := binary.BigEndian.Uint32(cr.sftpBuf[0:4])
+		if length > sftpMaxPacketSize {
+			// Stream is not a valid sftp, no reason to keep the data in memory
+			log.Warnf("PROXYSSH: %s: SFTP packet is too big (%d), stopping the manifest recording", cr.session.SrcAddr, length)
+			cr.sftpBroken = true
+			cr.sftpBuf = nil
+			return
+		}
+		if uint64(len(cr.sftpBuf)) < 4+uint64(length) {
+			// Waiting for the rest of the packet
+			return
+		}
+		packet := cr.sftpBuf[4 : 4+length]
+		cr.sftpBuf = cr.sftpBuf[4+length:]
+		if op := parseSftpPacket(packet); op != nil {
+			cr.sftpOps = append(cr.sftpOps, *op)
+		}
+	}
+}
+
+// parseSftpPacket returns manifest record if the packet is modifying or transferring the file
+func parseSftpPacket(packet []byte) *sftpManifestRecord {
+	if len(packet) < 5 {
+		return nil
+	}
+	// Packet type and request id
+	data := packet[5:]
+	readString := func() (string, bool) {
+		if len(data) < 4 {
+			return "", false
+		}
+		l := binary.BigEndian.Uint32(data[0:4])
+		if uint64(len(data)) < 4+uint64(l) {
+			return "", false
+		}
+		s := string(data[4 : 4+l])
+		data = data[4+l:]
+		return s, true
+	}
+
+	rec := &sftpManifestRecord{Time: time.Now()}
+	var ok bool
+	switch packet[0] {
+	case sftpPacketOpen:
+		if rec.Path, ok = readString(); !ok || len(data) < 4 {
+			return nil
+		}
+		flags := binary.BigEndian.Uint32(data[0:4])
+		switch {
+		case flags&sftpFlagWrite != 0:
+			rec.Op = "upload"
+		case flags&sftpFlagRead != 0:
+			rec.Op = "download"
+		default:
+			rec.Op = "open"
+		}
+	case sftpPacketRemove:
+		rec.Op = "remove"
+		rec.Path, ok = readString()
+	case sftpPacketMkdir:
+		rec.Op = "mkdir"
+		rec.Path, ok = readString()
+	case sftpPacketRmdir:
+		rec.Op = "rmdir"
+		rec.Path, ok = readString()
+	case sftpPacketRename:
+		rec.Op = "rename"
+		if rec.Path, ok = readString(); ok {
+			rec.Target, ok = readString()
+		}
+	default:
+		return nil
+	}
+	if rec.Op != "upload" && rec.Op != "download" && rec.Op != "open" && !ok {
+		return nil
+	}
+
+	return rec
+}
+
+// Close finishes the record and adds it to the audit chain
+func (cr *channelRecord) Close() {
+	if cr == nil {
+		return
+	}
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	var path string
+	if cr.isSftp {
+		if len(cr.sftpOps) == 0 {
+			return
+		}
+		path = cr.filePath + ".sftp.json"
+		data, err := json.MarshalIndent(cr.sftpOps, "", "  ")
+		if err != nil {
+			log.Errorf("PROXYSSH: %s: Unable to serialize sftp manifest: %v", cr.session.SrcAddr, err)
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			log.Errorf("PROXYSSH: %s: Unable to create record directory: %v", cr.session.SrcAddr, err)
+			return
+		}
+		if err := os.WriteFile(path, data, 0o640); err != nil {
+			log.Errorf("PROXYSSH: %s: Unable to write sftp manifest: %v", cr.session.SrcAddr, err)
+			return
+		}
+	} else {
+		if cr.file == nil {
+			return
+		}
+		path = cr.file.Name()
+		cr.file.Close()
+		cr.file = nil
+	}
+
+	if err := cr.rec.audit(cr.session, path); err != nil {
+		log.Errorf("PROXYSSH: %s: Unable to add record to audit log: %v", cr.session.SrcAddr, err)
+	}
+}
+
+// audit appends the record file info to the audit chain
+func (r *recorder) audit(s *session, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fileHash := sha256.Sum256(data)
+	relPath, _ := filepath.Rel(r.path, path)
+
+	return r.auditAppend(auditRecord{
+		Resource: s.ResourceAccessor.ResourceUID,
+		User:     s.ResourceAccessor.Username,
+		SrcAddr:  s.SrcAddr.String(),
+		File:     relPath,
+		FileHash: hex.EncodeToString(fileHash[:]),
+	})
+}
+
+// auditRemoved appends the record to the audit chain to mark the resource recordings as removed
+func (r *recorder) auditRemoved(resUID types.ResourceUID) error {
+	return r.auditAppend(auditRecord{
+		Resource: resUID,
+		File:     resUID.String(),
+		Removed:  true,
+	})
+}
+
+// auditAppend chains the record to the last one and writes it to the audit log
+func (r *recorder) auditAppend(rec auditRecord) error {
+	r.auditMutex.Lock()
+	defer r.auditMutex.Unlock()
+
+	rec.Time = time.Now()
+	rec.PrevHash = r.auditLastHash
+	rec.Hash = rec.computeHash(r.auditKey)
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(r.path, "audit.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	r.auditLastHash = rec.Hash
+
+	return nil
+}
+
+// computeHash returns the HMAC of the record which includes the previous record hash
+func (a auditRecord) computeHash(key []byte) string {
+	a.Hash = ""
+	data, _ := json.Marshal(a)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyAuditChain checks the audit log chain is consistent and returns the last record hash
+func verifyAuditChain(key []byte, data []byte) (string, error) {
+	prev := ""
+	for i, line := range splitLines(data) {
+		var rec auditRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return "", fmt.Errorf("unable to parse audit record %d: %w", i, err)
+		}
+		if rec.PrevHash != prev {
+			return "", fmt.Errorf("audit record %d is not chained to the previous one", i)
+		}
+		if !hmac.Equal([]byte(rec.computeHash(key)), []byte(rec.Hash)) {
+			return "", fmt.Errorf("audit record %d hash mismatch", i)
+		}
+		prev = rec.Hash
+	}
+	return prev, nil
+}
+
+// loadAuditKey reads the audit chain key or generates the new one if it's not exists
+//
+// When the master key is set the plaintext key is not trusted, since anyone with write access to
+// the recordings could put it there - so the new key is generated and the existing chain breaks.
+func loadAuditKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("PROXYSSH: Unable to read audit key %q: %w", path, err)
+	}
+	if err == nil {
+		value := strings.TrimSpace(string(data))
+		if !crypt.EnvelopeEnabled() || crypt.IsEnveloped(value) {
+			value, err = crypt.EnvelopeDecrypt(value)
+			if err != nil {
+				return nil, fmt.Errorf("PROXYSSH: Unable to decrypt audit key %q: %w", path, err)
+			}
+			key, err := hex.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("PROXYSSH: Unable to decode audit key %q: %w", path, err)
+			}
+			return key, nil
+		}
+		log.Errorf("PROXYSSH: Audit key %q is not encrypted by the master key, generating the new one", path)
+	}
+
+	if !crypt.EnvelopeEnabled() {
+		log.Warn("PROXYSSH: Master key is not set, recordings audit key is stored in plaintext")
+	}
+	key := crypt.RandBytes(32)
+	value, err := crypt.EnvelopeEncrypt(hex.EncodeToString(key))
+	if err != nil {
+		return nil, fmt.Errorf("PROXYSSH: Unable to encrypt audit key: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+		return nil, fmt.Errorf("PROXYSSH: Unable to write audit key %q: %w", path, err)
+	}
+	return key, nil
+}
+
+// cleanupProcess removes the resource recordings which are older than retention period
+func (r *recorder) cleanupProcess() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		entries, err := os.ReadDir(r.path)
+		if err != nil {
+			log.Error("PROXYSSH: Unable to read recordings directory:", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < r.retention {
+				continue
+			}
+			resUID, err := uuid.Parse(entry.Name())
+			if err != nil {
+				// Not a resource recordings directory
+				continue
+			}
+			log.Debug("PROXYSSH: Removing outdated recordings:", entry.Name())
+			if err := os.RemoveAll(filepath.Join(r.path, entry.Name())); err != nil {
+				log.Errorf("PROXYSSH: Unable to remove outdated recordings %q: %v", entry.Name(), err)
+				continue
+			}
+			// The audit entries are chained, so instead of removing them the removal is recorded
+			if err := r.auditRemoved(resUID); err != nil {
+				log.Errorf("PROXYSSH: Unable to add recordings removal to audit log: %v", err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func splitLines(data []byte) (lines [][]byte) {
+	start := 0
+	for i, b := range data {
+		if b != '\n' {
+			continue
+		}
+		if i > start {
+			lines = append(lines, data[start:i])
+		}
+		start = i + 1
+	}
+	if start < len(data) {
+		lines = append(lines, data[start:])
+	}
+	return lines
+}
@@ -0,0 +1,253 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package proxyssh
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+func sftpTestPacket(ptype byte, fields ...any) []byte {
+	var body bytes.Buffer
+	body.WriteByte(ptype)
+	binary.Write(&body, binary.BigEndian, uint32(1)) // Request ID
+	for _, f := range fields {
+		switch v := f.(type) {
+		case string:
+			binary.Write(&body, binary.BigEndian, uint32(len(v)))
+			body.WriteString(v)
+		case uint32:
+			binary.Write(&body, binary.BigEndian, v)
+		}
+	}
+	out := binary.BigEndian.AppendUint32(nil, uint32(body.Len()))
+	return append(out, body.Bytes()...)
+}
+
+func Test_record_sftp_manifest(t *testing.T) {
+	cr := &channelRecord{isSftp: true}
+
+	stream := append(sftpTestPacket(sftpPacketOpen, "/tmp/upload.bin", uint32(sftpFlagWrite), uint32(0)),
+		sftpTestPacket(sftpPacketOpen, "/etc/hosts", uint32(sftpFlagRead), uint32(0))...)
+	stream = append(stream, sftpTestPacket(sftpPacketRename, "/tmp/a", "/tmp/b")...)
+	stream = append(stream, sftpTestPacket(sftpPacketRemove, "/tmp/b")...)
+
+	// Feeding the stream by small chunks to make sure the packets are properly assembled
+	for i := 0; i < len(stream); i += 3 {
+		cr.parseSftp(stream[i:min(i+3, len(stream))])
+	}
+
+	want := []sftpManifestRecord{
+		{Op: "upload", Path: "/tmp/upload.bin"},
+		{Op: "download", Path: "/etc/hosts"},
+		{Op: "rename", Path: "/tmp/a", Target: "/tmp/b"},
+		{Op: "remove", Path: "/tmp/b"},
+	}
+	if len(cr.sftpOps) != len(want) {
+		t.Fatalf("parseSftp() found %d operations; want: %d", len(cr.sftpOps), len(want))
+	}
+	for i, op := range cr.sftpOps {
+		if op.Op != want[i].Op || op.Path != want[i].Path || op.Target != want[i].Target {
+			t.Fatalf("parseSftp() op %d = %+v; want: %+v", i, op, want[i])
+		}
+	}
+}
+
+func Test_record_audit_chain_tamper(t *testing.T) {
+	dir := t.TempDir()
+	r, err := newRecorder(dir, 0, false)
+	if err != nil {
+		t.Fatalf("newRecorder() = %v", err)
+	}
+
+	s := &session{
+		ResourceAccessor: &types.ResourceAccess{ResourceUID: uuid.New(), Username: "user"},
+		SrcAddr:          &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22},
+	}
+	for _, name := range []string{"first.cast", "second.cast"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(name), 0o600)
+		if err := r.audit(s, path); err != nil {
+			t.Fatalf("audit(%q) = %v", name, err)
+		}
+	}
+
+	auditPath := filepath.Join(dir, "audit.log")
+	data, _ := os.ReadFile(auditPath)
+	if _, err := verifyAuditChain(r.auditKey, data); err != nil {
+		t.Fatalf("verifyAuditChain() = %v; want: nil", err)
+	}
+
+	// Recorder should continue the existing chain
+	if _, err := newRecorder(dir, 0, false); err != nil {
+		t.Fatalf("newRecorder() on existing chain = %v", err)
+	}
+
+	// Modifying the first record should break the chain
+	tampered := bytes.Replace(data, []byte(`"user"`), []byte(`"evil"`), 1)
+	if _, err := verifyAuditChain(r.auditKey, tampered); err == nil {
+		t.Fatalf("verifyAuditChain() on tampered log = nil; want: error")
+	}
+
+	// Recomputing the chain without the key should not help
+	var rec auditRecord
+	json.Unmarshal(splitLines(tampered)[0], &rec)
+	rec.Hash = ""
+	plain, _ := json.Marshal(rec)
+	sum := sha256.Sum256(plain)
+	rec.Hash = hex.EncodeToString(sum[:])
+	forged, _ := json.Marshal(rec)
+	if _, err := verifyAuditChain(r.auditKey, forged); err == nil {
+		t.Fatalf("verifyAuditChain() on forged log = nil; want: error")
+	}
+	if _, err := verifyAuditChain([]byte("other key"), data); err == nil {
+		t.Fatalf("verifyAuditChain() with other key = nil; want: error")
+	}
+}
+
+func Test_record_sftp_oversized_packet(t *testing.T) {
+	cr := &channelRecord{
+		isSftp:  true,
+		session: &session{SrcAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22}},
+	}
+
+	// Length prefix above the max sftp packet size should stop the buffering
+	cr.parseSftp(binary.BigEndian.AppendUint32(nil, sftpMaxPacketSize+1))
+	cr.parseSftp(bytes.Repeat([]byte{0}, 1024))
+	if !cr.sftpBroken || len(cr.sftpBuf) != 0 {
+		t.Fatalf("parseSftp() kept %d bytes buffered (broken: %v); want: 0 (broken: true)", len(cr.sftpBuf), cr.sftpBroken)
+	}
+}
+
+func Test_record_input_opt_in(t *testing.T) {
+	for _, input := range []bool{false, true} {
+		dir := t.TempDir()
+		r, _ := newRecorder(dir, 0, input)
+		s := &session{
+			ResourceAccessor: &types.ResourceAccess{ResourceUID: uuid.New(), Username: "user"},
+			SrcAddr:          &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22},
+		}
+		cr := r.newChannelRecord(s, "session")
+		cr.WrapInput(io.Discard).Write([]byte("secret-password"))
+		cr.TeeOutput(bytes.NewReader([]byte("output"))).Read(make([]byte, 16))
+		path := cr.filePath + ".cast"
+		cr.Close()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%q) = %v", path, err)
+		}
+		if got := bytes.Contains(data, []byte("secret-password")); got != input {
+			t.Fatalf("record with input=%v contains input: %v", input, got)
+		}
+		if !bytes.Contains(data, []byte("output")) {
+			t.Fatalf("record with input=%v does not contain output", input)
+		}
+	}
+}
+
+func Test_record_corrupted_audit_log(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "audit.log"), []byte("not a json\n"), 0o600)
+
+	r, err := newRecorder(dir, 0, false)
+	if err != nil {
+		t.Fatalf("newRecorder() on corrupted audit log = %v; want: nil", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "audit.log.corrupted-*"))
+	if len(matches) != 1 {
+		t.Fatalf("corrupted audit log was not moved aside: %v", matches)
+	}
+
+	// The new chain should start with the record about the break
+	data, _ := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if _, err := verifyAuditChain(r.auditKey, data); err != nil {
+		t.Fatalf("verifyAuditChain() of the new chain = %v; want: nil", err)
+	}
+	lines := splitLines(data)
+	var rec auditRecord
+	if len(lines) != 1 || json.Unmarshal(lines[0], &rec) != nil || rec.Broken == "" || rec.File != matches[0] {
+		t.Fatalf("new audit chain does not record the break: %s", data)
+	}
+}
+
+func Test_record_retention_audit(t *testing.T) {
+	dir := t.TempDir()
+	r, _ := newRecorder(dir, 0, false)
+
+	s := &session{
+		ResourceAccessor: &types.ResourceAccess{ResourceUID: uuid.New(), Username: "user"},
+		SrcAddr:          &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22},
+	}
+	path := filepath.Join(dir, s.ResourceAccessor.ResourceUID.String(), "record.cast")
+	os.MkdirAll(filepath.Dir(path), 0o750)
+	os.WriteFile(path, []byte("record"), 0o600)
+	if err := r.audit(s, path); err != nil {
+		t.Fatalf("audit() = %v", err)
+	}
+	if err := r.auditRemoved(s.ResourceAccessor.ResourceUID); err != nil {
+		t.Fatalf("auditRemoved() = %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if _, err := verifyAuditChain(r.auditKey, data); err != nil {
+		t.Fatalf("verifyAuditChain() after removal = %v; want: nil", err)
+	}
+	lines := splitLines(data)
+	if len(lines) != 2 || !bytes.Contains(lines[1], []byte(`"removed":true`)) {
+		t.Fatalf("audit log does not mark the removed recordings: %s", data)
+	}
+}
+
+func Test_record_audit_key_encrypted(t *testing.T) {
+	dir := t.TempDir()
+	master, err := crypt.NewFileMasterKey(filepath.Join(dir, "master.key"))
+	if err != nil {
+		t.Fatalf("NewFileMasterKey() = %v", err)
+	}
+	crypt.EnvelopeInit(master)
+	defer crypt.EnvelopeInit(nil)
+
+	recPath := filepath.Join(dir, "records")
+	r, err := newRecorder(recPath, 0, false)
+	if err != nil {
+		t.Fatalf("newRecorder() = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(recPath, "audit.key"))
+	if !crypt.IsEnveloped(string(data)) {
+		t.Fatalf("audit key is stored in plaintext: %s", data)
+	}
+
+	// Plaintext key could be placed by anyone, so it should not be trusted
+	os.WriteFile(filepath.Join(recPath, "audit.key"), []byte(hex.EncodeToString([]byte("forged key"))), 0o600)
+	r2, err := newRecorder(recPath, 0, false)
+	if err != nil {
+		t.Fatalf("newRecorder() with plaintext key = %v", err)
+	}
+	if bytes.Equal(r2.auditKey, []byte("forged key")) || bytes.Equal(r2.auditKey, r.auditKey) {
+		t.Fatalf("newRecorder() uses the plaintext key")
+	}
+}
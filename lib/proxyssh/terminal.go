@@ -0,0 +1,243 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package proxyssh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/websocket"
+
+	"github.com/adobe/aquarium-fish/lib/fish"
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+)
+
+// Terminal serves the web terminal sessions to the Resources shell
+//
+// The websocket protocol is compatible with xterm.js attach addon: the text frames are always the
+// terminal input and the output is sent as binary frames. The binary frames sent by the client
+// could contain control JSON messages like `{"type":"resize","cols":120,"rows":40}`, all the
+// other binary frames are the terminal input too.
+type Terminal struct {
+	fish     *fish.Fish
+	recorder *recorder
+}
+
+// terminalControl is the control message client sends as websocket binary frame
+type terminalControl struct {
+	Type string `json:"type"` // Only "resize" is supported for now
+	Cols uint32 `json:"cols"`
+	Rows uint32 `json:"rows"`
+}
+
+// terminalFrame is the received websocket message with its type
+type terminalFrame struct {
+	data   []byte
+	binary bool
+}
+
+// terminalCodec allows to know the type of the received frame which is not provided by Message
+var terminalCodec = websocket.Codec{
+	Marshal: func(v any) ([]byte, byte, error) {
+		data, ok := v.([]byte)
+		if !ok {
+			return nil, 0, websocket.ErrNotSupported
+		}
+		return data, websocket.BinaryFrame, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v any) error {
+		frame, ok := v.(*terminalFrame)
+		if !ok {
+			return websocket.ErrNotSupported
+		}
+		frame.data = data
+		frame.binary = payloadType == websocket.BinaryFrame
+		return nil
+	},
+}
+
+// NewTerminal creates the web terminal server, if recordPath is not empty - the sessions will
+// be recorded and kept for retention time. The path should not be shared with the ssh proxy one,
+// since each recorder maintains its own audit chain.
+func NewTerminal(f *fish.Fish, recordPath string, retention time.Duration, recordInput bool) (*Terminal, error) {
+	t := &Terminal{fish: f}
+	if recordPath != "" {
+		var err error
+		if t.recorder, err = newRecorder(recordPath, retention, recordInput); err != nil {
+			return nil, err
+		}
+		log.Info("PROXYSSH: Terminal sessions recording enabled:", recordPath)
+	}
+	return t, nil
+}
+
+// Close stops the terminal background processes
+func (t *Terminal) Close() {
+	t.recorder.Close()
+}
+
+// Serve upgrades the request to websocket and connects it to the Resource shell, the user
+// should be authorized to access the Resource already
+func (t *Terminal) Serve(w http.ResponseWriter, r *http.Request, res *types.Resource, username string) {
+	server := websocket.Server{
+		// Browsers are sending origin, so making sure the page is served from the same host
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			origin := req.Header.Get("Origin")
+			if origin == "" {
+				return nil
+			}
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != req.Host {
+				return fmt.Errorf("Origin %q is not allowed", origin)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			srcAddr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+			s := &session{
+				ResourceAccessor: &types.ResourceAccess{ResourceUID: res.UID, Username: username},
+				SrcAddr:          srcAddr,
+				fish:             t.fish,
+				recorder:         t.recorder,
+			}
+			// The terminal is closed by the node along with the other sessions on deallocation
+			t.fish.ResourceSessionBegin(res.UID, ws)
+			defer t.fish.ResourceSessionEnd(res.UID, ws)
+			if err := s.serveTerminal(ws, res); err != nil {
+				log.Errorf("PROXYSSH: %s: Terminal session error: %v", s.SrcAddr, err)
+				websocket.Message.Send(ws, fmt.Sprintf("\r\nTerminal error: %v\r\n", err))
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+func (s *session) serveTerminal(ws *websocket.Conn, res *types.Resource) error {
+	log.Infof("PROXYSSH: %s: Starting new terminal session to Resource %s", s.SrcAddr, res.UID)
+	metricConnections.Inc("terminal")
+	defer metricConnections.Dec("terminal")
+	if res.Authentication == nil || res.Authentication.Username == "" && res.Authentication.Password == "" {
+		return fmt.Errorf("Resource Authentication not provided")
+	}
+
+	dstConn, err := s.connectToDestination(res)
+	if err != nil {
+		return fmt.Errorf("Unable to connect to destination: %w", err)
+	}
+	defer dstConn.Close()
+
+	dstSession, err := dstConn.NewSession()
+	if err != nil {
+		return fmt.Errorf("Unable to open session: %w", err)
+	}
+	defer dstSession.Close()
+
+	// Recording the terminal same way as the regular ssh proxy session
+	rec := s.recorder.newChannelRecord(s, "session")
+	defer rec.Close()
+
+	stdin, err := dstSession.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("Unable to get session stdin: %w", err)
+	}
+	stdout, err := dstSession.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("Unable to get session stdout: %w", err)
+	}
+	// With pty requested the stderr is merged into stdout by the remote side
+
+	ptyReq := ssh.Marshal(struct {
+		Term    string
+		Columns uint32
+		Rows    uint32
+		Width   uint32
+		Height  uint32
+		Modes   string
+	}{"xterm-256color", 80, 24, 0, 0, ""})
+	rec.HandleRequest(&ssh.Request{Type: "pty-req", Payload: ptyReq})
+	if err := dstSession.RequestPty("xterm-256color", 24, 80, ssh.TerminalModes{ssh.ECHO: 1}); err != nil {
+		return fmt.Errorf("Unable to request pty: %w", err)
+	}
+	if err := dstSession.Shell(); err != nil {
+		return fmt.Errorf("Unable to start shell: %w", err)
+	}
+
+	// Streaming the resource output to the websocket as binary frames
+	go func() {
+		buf := make([]byte, 32*1024)
+		out := rec.TeeOutput(stdout)
+		for {
+			n, err := out.Read(buf)
+			if n > 0 {
+				if err := websocket.Message.Send(ws, buf[:n]); err != nil {
+					log.Debugf("PROXYSSH: %s: Unable to send terminal output: %v", s.SrcAddr, err)
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}()
+
+	// Processing the client input and control messages
+	in := rec.WrapInput(stdin)
+	for {
+		var frame terminalFrame
+		if err := terminalCodec.Receive(ws, &frame); err != nil {
+			if err != io.EOF {
+				log.Debugf("PROXYSSH: %s: Terminal websocket closed: %v", s.SrcAddr, err)
+			}
+			break
+		}
+		if ctrl, ok := parseTerminalControl(frame); ok {
+			if ctrl.Type == "resize" && ctrl.Cols > 0 && ctrl.Rows > 0 {
+				rec.HandleRequest(&ssh.Request{Type: "window-change", Payload: ssh.Marshal(struct {
+					Columns uint32
+					Rows    uint32
+					Width   uint32
+					Height  uint32
+				}{ctrl.Cols, ctrl.Rows, 0, 0})})
+				dstSession.WindowChange(int(ctrl.Rows), int(ctrl.Cols))
+			}
+			continue
+		}
+		if _, err := in.Write(frame.data); err != nil {
+			break
+		}
+	}
+
+	log.Infof("PROXYSSH: %s: Terminal session closed", s.SrcAddr)
+	return nil
+}
+
+// parseTerminalControl returns control message if the frame is one, the text frames are always
+// the input to not swallow anything user types
+func parseTerminalControl(frame terminalFrame) (ctrl terminalControl, ok bool) {
+	if !frame.binary || len(frame.data) < 2 || frame.data[0] != '{' {
+		return ctrl, false
+	}
+	if err := json.Unmarshal(frame.data, &ctrl); err != nil || ctrl.Type == "" {
+		return ctrl, false
+	}
+	return ctrl, true
+}
@@ -0,0 +1,39 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package proxyssh
+
+import (
+	"testing"
+)
+
+// Verify only the binary control frames are processed and the user input is passed as is
+func Test_parse_terminal_control(t *testing.T) {
+	resize := []byte(`{"type":"resize","cols":120,"rows":40}`)
+
+	ctrl, ok := parseTerminalControl(terminalFrame{data: resize, binary: true})
+	if !ok || ctrl.Type != "resize" || ctrl.Cols != 120 || ctrl.Rows != 40 {
+		t.Fatalf("parseTerminalControl() should parse binary resize frame: %v, %+v", ok, ctrl)
+	}
+	if _, ok := parseTerminalControl(terminalFrame{data: resize}); ok {
+		t.Fatalf("parseTerminalControl() should treat text frame as input")
+	}
+	if _, ok := parseTerminalControl(terminalFrame{data: []byte(`{"name":"value"}`), binary: true}); ok {
+		t.Fatalf("parseTerminalControl() should treat JSON without type as input")
+	}
+	if _, ok := parseTerminalControl(terminalFrame{data: []byte("{"), binary: true}); ok {
+		t.Fatalf("parseTerminalControl() should treat single brace as input")
+	}
+	if _, ok := parseTerminalControl(terminalFrame{data: []byte("ls -la\r"), binary: true}); ok {
+		t.Fatalf("parseTerminalControl() should treat regular binary frame as input")
+	}
+}
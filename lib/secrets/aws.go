@@ -0,0 +1,120 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// ConfigAWS defines AWS Secrets Manager access, the credentials are taken from the standard
+// AWS environment variables if not set
+type ConfigAWS struct {
+	Region       string `json:"region"`        // Region of the Secrets Manager
+	KeyID        string `json:"key_id"`        // AWS access key ID, could be the env or file reference
+	SecretKey    string `json:"secret_key"`    // AWS secret access key, could be the env or file reference
+	SessionToken string `json:"session_token"` // Optional session token of the temporary credentials
+	Endpoint     string `json:"endpoint"`      // Override of the Secrets Manager endpoint, for example VPC one
+	KMSEndpoint  string `json:"kms_endpoint"`  // Override of the KMS endpoint used by the master key
+}
+
+// awsProvider gets the secrets from AWS Secrets Manager
+type awsProvider struct {
+	cfg ConfigAWS
+}
+
+// Get returns the secret string or the key field of it, path is the secret name or ARN
+func (p *awsProvider) Get(path, key string) (string, error) {
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := p.call(p.cfg.Endpoint, "secretsmanager", "secretsmanager.GetSecretValue", map[string]string{"SecretId": path}, &secret); err != nil {
+		return "", err
+	}
+	if secret.SecretString == nil {
+		return "", fmt.Errorf("Binary secrets are not supported")
+	}
+	return field(*secret.SecretString, key)
+}
+
+// call sends the signed json request to the AWS service and decodes the response into out, the
+// regional service endpoint is used if endpoint is empty
+func (p *awsProvider) call(endpoint, service, target string, in, out any) error {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", service, p.cfg.Region)
+	}
+
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	hash := sha256.Sum256(body)
+	if err = v4.NewSigner().SignHTTP(context.Background(), p.credentials(), req, hex.EncodeToString(hash[:]), service, p.cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("Unable to sign the request: %v", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &msg)
+		return fmt.Errorf("%s responded with status %d: %s %s", target, resp.StatusCode, msg.Type, msg.Message)
+	}
+
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("Unable to parse %s response: %v", target, err)
+	}
+	return nil
+}
+
+// credentials returns the configured credentials or the ones from environment
+func (p *awsProvider) credentials() aws.Credentials {
+	if p.cfg.KeyID != "" {
+		return aws.Credentials{AccessKeyID: p.cfg.KeyID, SecretAccessKey: p.cfg.SecretKey, SessionToken: p.cfg.SessionToken}
+	}
+	return aws.Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
@@ -0,0 +1,61 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+)
+
+// kmsMasterKey wraps the envelope data keys with AWS KMS key
+type kmsMasterKey struct {
+	p     *awsProvider
+	keyID string
+}
+
+// NewKMSMasterKey returns the envelope master key stored in AWS KMS, the key is accessed with the
+// AWS provider region & credentials
+func NewKMSMasterKey(keyID string) (crypt.MasterKey, error) {
+	providersMutex.RLock()
+	p, ok := providers["aws"].(*awsProvider)
+	providersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Secrets: AWS provider should be configured to use KMS master key")
+	}
+	return &kmsMasterKey{p: p, keyID: keyID}, nil
+}
+
+// Wrap encrypts the data key with KMS key
+func (m *kmsMasterKey) Wrap(dataKey []byte) ([]byte, error) {
+	var out struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+	in := map[string]any{"KeyId": m.keyID, "Plaintext": dataKey}
+	if err := m.p.call(m.p.cfg.KMSEndpoint, "kms", "TrentService.Encrypt", in, &out); err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+// Unwrap decrypts the data key with KMS key
+func (m *kmsMasterKey) Unwrap(wrapped []byte) ([]byte, error) {
+	var out struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	in := map[string]any{"KeyId": m.keyID, "CiphertextBlob": wrapped}
+	if err := m.p.call(m.p.cfg.KMSEndpoint, "kms", "TrentService.Decrypt", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
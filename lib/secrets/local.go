@@ -0,0 +1,43 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envProvider gets the secret from the node environment variable
+type envProvider struct{}
+
+// Get returns the environment variable value, it should be set
+func (envProvider) Get(path, key string) (string, error) {
+	value, ok := os.LookupEnv(path)
+	if !ok {
+		return "", fmt.Errorf("Environment variable is not set")
+	}
+	return field(value, key)
+}
+
+// fileProvider reads the secret from the file, for example mounted by the orchestrator
+type fileProvider struct{}
+
+// Get returns the file content without the trailing newline
+func (fileProvider) Get(path, key string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return field(strings.TrimRight(string(data), "\r\n"), key)
+}
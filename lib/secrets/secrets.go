@@ -0,0 +1,201 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package secrets resolves the `secret://` references to the external secrets providers, so the
+// node config and Label Authentication could avoid keeping the credentials in plaintext.
+//
+// Reference format is `secret://<provider>/<path>[#<key>]`, where key selects the field of the
+// structured (JSON) secret:
+//   - `secret://env/AWS_SECRET_KEY` - environment variable
+//   - `secret://file//etc/fish/ssh_key` - file content, trailing newline is trimmed
+//   - `secret://vault/kv/fish/aws#secret_key` - HashiCorp Vault KV v2 secret field
+//   - `secret://aws/prod/fish/aws#secret_key` - AWS Secrets Manager secret (JSON field)
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Prefix of the secret reference
+const Prefix = "secret://"
+
+// Config of the secrets providers, env and file are always available
+type Config struct {
+	Vault ConfigVault `json:"vault"` // HashiCorp Vault provider, enabled when address is set
+	AWS   ConfigAWS   `json:"aws"`   // AWS Secrets Manager provider, enabled when region is set
+
+	RefreshInterval util.Duration `json:"refresh_interval"` // How often to re-read the drivers and gates secrets to pick up the rotation, 0 disables
+}
+
+// Provider gets the secret from the external storage
+type Provider interface {
+	// Get returns the secret value by path, key selects the field of the structured secret
+	Get(path, key string) (string, error)
+}
+
+var (
+	providersMutex  sync.RWMutex
+	providers       = map[string]Provider{"env": envProvider{}, "file": fileProvider{}}
+	refreshInterval time.Duration
+)
+
+// Init configures the providers, the secrets provider config itself could use env and file
+// references to not keep the provider credentials in plaintext
+func Init(cfg Config) error {
+	if cfg.Vault.Address != "" {
+		if err := ResolveFields(&cfg.Vault.Token); err != nil {
+			return fmt.Errorf("Secrets: Unable to resolve Vault token: %v", err)
+		}
+		setProvider("vault", &vaultProvider{cfg: cfg.Vault})
+	}
+	if cfg.AWS.Region != "" {
+		if err := ResolveFields(&cfg.AWS.KeyID, &cfg.AWS.SecretKey, &cfg.AWS.SessionToken); err != nil {
+			return fmt.Errorf("Secrets: Unable to resolve AWS credentials: %v", err)
+		}
+		setProvider("aws", &awsProvider{cfg: cfg.AWS})
+	}
+
+	providersMutex.Lock()
+	refreshInterval = time.Duration(cfg.RefreshInterval)
+	providersMutex.Unlock()
+	return nil
+}
+
+// RefreshInterval returns how often the secrets should be re-read, 0 if never
+func RefreshInterval() time.Duration {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+	return refreshInterval
+}
+
+func setProvider(name string, p Provider) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	providers[name] = p
+}
+
+// IsRef checks the value is a secret reference
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// HasRefs checks the data contains secret references, used to skip the needless processing
+func HasRefs(data []byte) bool {
+	return bytes.Contains(data, []byte(Prefix))
+}
+
+// Resolve returns the secret value of the reference, the other values are returned as is
+func Resolve(value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	name, path, found := strings.Cut(strings.TrimPrefix(value, Prefix), "/")
+	if !found || path == "" {
+		return "", fmt.Errorf("Secrets: Wrong reference format, should be %s<provider>/<path>[#<key>]", Prefix)
+	}
+	path, key, _ := strings.Cut(path, "#")
+
+	providersMutex.RLock()
+	p, ok := providers[name]
+	providersMutex.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("Secrets: Provider %q is not configured", name)
+	}
+
+	secret, err := p.Get(path, key)
+	if err != nil {
+		return "", fmt.Errorf("Secrets: Unable to get %s secret %q: %v", name, path, err)
+	}
+	return secret, nil
+}
+
+// ResolveFields replaces the references in the provided string fields with the secret values
+func ResolveFields(fields ...*string) error {
+	for _, field := range fields {
+		value, err := Resolve(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// ResolveJSON replaces the string values of JSON data which are references with the secrets
+func ResolveJSON(data []byte) ([]byte, error) {
+	if !HasRefs(data) {
+		return data, nil
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("Secrets: Unable to parse json: %v", err)
+	}
+	tree, err := ResolveTree(tree)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// ResolveTree walks through the parsed JSON tree and replaces the references with the secrets
+func ResolveTree(tree any) (any, error) {
+	var err error
+	switch v := tree.(type) {
+	case string:
+		return Resolve(v)
+	case map[string]any:
+		for key, item := range v {
+			if v[key], err = ResolveTree(item); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if v[i], err = ResolveTree(item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tree, nil
+}
+
+// field returns the key field of the JSON secret or the secret itself if the key is empty
+func field(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(secret), &data); err != nil {
+		return "", fmt.Errorf("Secret is not a json object to get key %q", key)
+	}
+	return fieldValue(data, key)
+}
+
+// fieldValue returns the string value of the key in the secret fields
+func fieldValue(data map[string]any, key string) (string, error) {
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("Key %q is not found in the secret", key)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Key %q of the secret is not a string", key)
+	}
+	return str, nil
+}
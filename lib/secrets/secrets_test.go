@@ -0,0 +1,189 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package secrets
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Make sure the local references are resolved and the plain values are kept
+func Test_secrets_resolve_local(t *testing.T) {
+	t.Setenv("FISH_TEST_SECRET", "env-value")
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(`{"user":"test","pass":"file-value"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("Unable to write secret file: %v", err)
+	}
+
+	for value, expected := range map[string]string{
+		"plain":                           "plain",
+		"secret://env/FISH_TEST_SECRET":   "env-value",
+		"secret://file/" + path:           `{"user":"test","pass":"file-value"}`,
+		"secret://file/" + path + "#pass": "file-value",
+	} {
+		out, err := Resolve(value)
+		if err != nil || out != expected {
+			t.Fatalf("Wrong resolve of %q: %q, %v", value, out, err)
+		}
+	}
+
+	for _, value := range []string{"secret://env", "secret://env/FISH_TEST_NOT_SET", "secret://unknown/path", "secret://file/" + path + "#none"} {
+		if _, err := Resolve(value); err == nil {
+			t.Fatalf("Resolve of %q should fail", value)
+		}
+	}
+}
+
+// Make sure the references are replaced in the json and the structure is kept
+func Test_secrets_resolve_json(t *testing.T) {
+	t.Setenv("FISH_TEST_SECRET", "env-value")
+
+	out, err := ResolveJSON([]byte(`{"key":"secret://env/FISH_TEST_SECRET","list":["a","secret://env/FISH_TEST_SECRET"],"num":1}`))
+	if err != nil {
+		t.Fatalf("Unable to resolve json: %v", err)
+	}
+	var data map[string]any
+	json.Unmarshal(out, &data)
+	if data["key"] != "env-value" || data["list"].([]any)[1] != "env-value" || data["num"] != 1.0 {
+		t.Fatalf("Wrong resolved json: %s", out)
+	}
+}
+
+func Test_secrets_vault(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/fish/aws":
+			w.Write([]byte(`{"data":{"data":{"key_id":"AKID","secret_key":"vault-value"}}}`))
+		case "/v1/kv/data/fish/token":
+			w.Write([]byte(`{"data":{"data":{"token":"single-value"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer vault.Close()
+
+	t.Setenv("FISH_TEST_VAULT_TOKEN", "test-token")
+	if err := Init(Config{Vault: ConfigVault{Address: vault.URL, Token: "secret://env/FISH_TEST_VAULT_TOKEN"}}); err != nil {
+		t.Fatalf("Unable to init secrets: %v", err)
+	}
+
+	if out, err := Resolve("secret://vault/kv/fish/aws#secret_key"); err != nil || out != "vault-value" {
+		t.Fatalf("Wrong Vault secret: %q, %v", out, err)
+	}
+	if out, err := Resolve("secret://vault/kv/fish/token"); err != nil || out != "single-value" {
+		t.Fatalf("Wrong Vault single field secret: %q, %v", out, err)
+	}
+	if _, err := Resolve("secret://vault/kv/fish/aws"); err == nil {
+		t.Fatalf("Vault secret with multiple fields requires the key")
+	}
+	if _, err := Resolve("secret://vault/kv/fish/none#key"); err == nil {
+		t.Fatalf("Not existing Vault secret should fail")
+	}
+}
+
+func Test_secrets_aws(t *testing.T) {
+	sm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-west-2/secretsmanager/aws4_request") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"InvalidSignatureException","message":"wrong signature"}`))
+			return
+		}
+		var req struct {
+			SecretId string //nolint:revive
+		}
+		json.Unmarshal(body, &req)
+		if req.SecretId != "prod/fish" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"Name":"prod/fish","SecretString":"{\"secret_key\":\"aws-value\"}"}`))
+	}))
+	defer sm.Close()
+
+	if err := Init(Config{AWS: ConfigAWS{Region: "us-west-2", KeyID: "AKIDTEST", SecretKey: "secret", Endpoint: sm.URL}}); err != nil {
+		t.Fatalf("Unable to init secrets: %v", err)
+	}
+
+	if out, err := Resolve("secret://aws/prod/fish#secret_key"); err != nil || out != "aws-value" {
+		t.Fatalf("Wrong AWS secret: %q, %v", out, err)
+	}
+	if _, err := Resolve("secret://aws/prod/other"); err == nil {
+		t.Fatalf("Not existing AWS secret should fail")
+	}
+}
+
+func Test_secrets_kms_master_key(t *testing.T) {
+	// Fake KMS "encrypts" by reversing the bytes
+	reverse := func(in []byte) []byte {
+		out := make([]byte, len(in))
+		for i := range in {
+			out[len(in)-1-i] = in[i]
+		}
+		return out
+	}
+	kms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			KeyId          string //nolint:revive
+			Plaintext      []byte
+			CiphertextBlob []byte
+		}
+		json.Unmarshal(body, &req)
+		if req.KeyId != "alias/fish" || !strings.Contains(r.Header.Get("Authorization"), "/us-west-2/kms/aws4_request") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"NotFoundException","message":"wrong key"}`))
+			return
+		}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			out, _ := json.Marshal(map[string][]byte{"CiphertextBlob": reverse(req.Plaintext)})
+			w.Write(out)
+		case "TrentService.Decrypt":
+			out, _ := json.Marshal(map[string][]byte{"Plaintext": reverse(req.CiphertextBlob)})
+			w.Write(out)
+		}
+	}))
+	defer kms.Close()
+
+	if err := Init(Config{AWS: ConfigAWS{Region: "us-west-2", KeyID: "AKIDTEST", SecretKey: "secret", KMSEndpoint: kms.URL}}); err != nil {
+		t.Fatalf("Unable to init secrets: %v", err)
+	}
+	master, err := NewKMSMasterKey("alias/fish")
+	if err != nil {
+		t.Fatalf("Unable to create KMS master key: %v", err)
+	}
+
+	wrapped, err := master.Wrap([]byte("data-key"))
+	if err != nil || string(wrapped) != "yek-atad" {
+		t.Fatalf("Wrong wrapped key: %q, %v", wrapped, err)
+	}
+	if key, err := master.Unwrap(wrapped); err != nil || string(key) != "data-key" {
+		t.Fatalf("Wrong unwrapped key: %q, %v", key, err)
+	}
+}
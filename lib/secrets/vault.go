@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConfigVault defines HashiCorp Vault access
+type ConfigVault struct {
+	Address   string `json:"address"`   // Vault address like "https://vault.example.com:8200"
+	Token     string `json:"token"`     // Access token, could be the env or file reference
+	Namespace string `json:"namespace"` // Optional Vault Enterprise namespace
+}
+
+// vaultProvider gets the secrets from the Vault KV v2 engine, the first element of the path is
+// the engine mount
+type vaultProvider struct {
+	cfg ConfigVault
+}
+
+// Get returns the key field of the Vault secret, the key could be omitted if the secret has one field
+func (p *vaultProvider) Get(path, key string) (string, error) {
+	mount, secretPath, found := strings.Cut(path, "/")
+	if !found || secretPath == "" {
+		return "", fmt.Errorf("Vault path should be <mount>/<path>")
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimRight(p.cfg.Address, "/")+"/v1/"+mount+"/data/"+secretPath, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Errors []string `json:"errors"`
+		}
+		json.Unmarshal(body, &msg)
+		return "", fmt.Errorf("Vault responded with status %d: %s", resp.StatusCode, strings.Join(msg.Errors, ", "))
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("Unable to parse Vault response: %v", err)
+	}
+	if key == "" {
+		if len(secret.Data.Data) != 1 {
+			return "", fmt.Errorf("Vault secret has %d fields, the key should be specified", len(secret.Data.Data))
+		}
+		for k := range secret.Data.Data {
+			key = k
+		}
+	}
+	return fieldValue(secret.Data.Data, key)
+}
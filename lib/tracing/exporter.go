@@ -0,0 +1,332 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+// Config of the OTLP exporter
+type Config struct {
+	Endpoint      string            `json:"endpoint"`       // OTLP/HTTP collector URL (like "http://otel:4318"), empty disables tracing
+	Headers       map[string]string `json:"headers"`        // Additional headers to send, like authentication
+	ServiceName   string            `json:"service_name"`   // Name of the service in the traces, "aquarium-fish" by default
+	FlushInterval util.Duration     `json:"flush_interval"` // How often to send the spans, 5s by default
+	BatchSize     int               `json:"batch_size"`     // Max amount of spans to send in one request, 512 by default
+}
+
+// Exporter collects the finished spans and sends them in batches
+type exporter struct {
+	cfg        Config
+	attributes []Attribute
+	client     *http.Client
+
+	mu    sync.Mutex
+	queue []*Span
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+var (
+	exp   *exporter
+	expMu sync.RWMutex
+)
+
+// Init starts the exporter, the node attributes are added to every exported span resource
+func Init(cfg Config, attrs ...Attribute) error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "aquarium-fish"
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = util.Duration(5 * time.Second)
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+
+	e := &exporter{
+		cfg:        cfg,
+		attributes: append([]Attribute{Attr("service.name", cfg.ServiceName)}, attrs...),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		flush:      make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	expMu.Lock()
+	old := exp
+	exp = e
+	expMu.Unlock()
+	if old != nil {
+		old.close()
+	}
+
+	go e.process()
+	log.Info("Tracing: Exporting spans to:", cfg.Endpoint)
+
+	return nil
+}
+
+// Close sends the remaining spans and stops the exporter
+func Close() {
+	expMu.Lock()
+	e := exp
+	exp = nil
+	expMu.Unlock()
+	if e != nil {
+		e.close()
+	}
+}
+
+// Enabled returns true if the spans are exported
+func Enabled() bool {
+	expMu.RLock()
+	defer expMu.RUnlock()
+	return exp != nil
+}
+
+func export(s *Span) {
+	expMu.RLock()
+	e := exp
+	expMu.RUnlock()
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	// Protecting the node memory in case the collector is not available for a long time
+	if len(e.queue) >= e.cfg.BatchSize*10 {
+		e.queue = e.queue[1:]
+	}
+	e.queue = append(e.queue, s)
+	full := len(e.queue) >= e.cfg.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *exporter) close() {
+	close(e.stop)
+	<-e.done
+}
+
+func (e *exporter) process() {
+	defer close(e.done)
+	ticker := time.NewTicker(time.Duration(e.cfg.FlushInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stop:
+			e.send()
+			return
+		case <-ticker.C:
+		case <-e.flush:
+		}
+		e.send()
+	}
+}
+
+// send exports the queued spans in batches
+func (e *exporter) send() {
+	for {
+		e.mu.Lock()
+		n := min(len(e.queue), e.cfg.BatchSize)
+		batch := e.queue[:n]
+		e.queue = e.queue[n:]
+		e.mu.Unlock()
+		if n == 0 {
+			return
+		}
+
+		if err := e.post(batch); err != nil {
+			log.Warnf("Tracing: Unable to export %d spans: %v", n, err)
+			return
+		}
+	}
+}
+
+func (e *exporter) post(spans []*Span) error {
+	body, err := json.Marshal(encodeOTLP(spans, e.attributes))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(e.cfg.Endpoint, "/")+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Tracing: Collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP JSON encoding structures, the ids are hex-encoded and the timestamps are strings
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Links             []otlpLink      `json:"links,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpLink struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"` // 0 - unset, 1 - ok, 2 - error
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+func encodeOTLP(spans []*Span, resource []Attribute) otlpTraces {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		o := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes),
+		}
+		if s.ParentID != (SpanID{}) {
+			o.ParentSpanID = hex.EncodeToString(s.ParentID[:])
+		}
+		for _, ev := range s.Events {
+			o.Events = append(o.Events, otlpEvent{
+				TimeUnixNano: strconv.FormatInt(ev.Time.UnixNano(), 10),
+				Name:         ev.Name,
+				Attributes:   encodeAttributes(ev.Attributes),
+			})
+		}
+		for _, l := range s.Links {
+			o.Links = append(o.Links, otlpLink{TraceID: l.TraceID.String(), SpanID: l.SpanID.String()})
+		}
+		if s.Error != "" {
+			o.Status = otlpStatus{Code: 2, Message: s.Error}
+		}
+		s.mu.Unlock()
+		out = append(out, o)
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttributes(resource)},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "github.com/adobe/aquarium-fish"}, Spans: out}},
+	}}}
+}
+
+func encodeAttributes(attrs []Attribute) []otlpAttribute {
+	out := make([]otlpAttribute, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]any
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(val)}
+		case int8, int16, int32, int64:
+			v = map[string]any{"intValue": strconv.FormatInt(reflect.ValueOf(val).Int(), 10)}
+		case uint, uint8, uint16, uint32, uint64:
+			// OTLP int value is signed 64 bit, so the bigger values are sent as string
+			if u := reflect.ValueOf(val).Uint(); u <= math.MaxInt64 {
+				v = map[string]any{"intValue": strconv.FormatUint(u, 10)}
+			} else {
+				v = map[string]any{"stringValue": strconv.FormatUint(u, 10)}
+			}
+		case float32:
+			v = map[string]any{"doubleValue": float64(val)}
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		case fmt.Stringer:
+			v = map[string]any{"stringValue": val.String()}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		out = append(out, otlpAttribute{Key: a.Key, Value: v})
+	}
+	return out
+}
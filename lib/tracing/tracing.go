@@ -0,0 +1,260 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+// Package tracing is the minimal OpenTelemetry compatible tracer which sends the spans to the
+// OTLP/HTTP collector in JSON encoding, so no additional dependencies are needed. If the exporter
+// endpoint is not configured all the spans are no-op.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span kinds as OTLP defines them
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// TraceID identifies the whole trace
+type TraceID [16]byte
+
+// SpanID identifies the span in the trace
+type SpanID [8]byte
+
+// String returns hex representation of the trace id
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// String returns hex representation of the span id
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Attribute is the key-value pair attached to the span or event
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr is a shortcut to create attribute
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Link points to the related span of another trace
+type Link struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// Event is the point in time annotation of the span
+type Event struct {
+	Time       time.Time
+	Name       string
+	Attributes []Attribute
+}
+
+// Span is the timed operation, all the methods are safe to use on nil span
+type Span struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	ParentID   SpanID
+	Name       string
+	Kind       int
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Events     []Event
+	Links      []Link
+	Error      string
+
+	mu    sync.Mutex
+	ended bool
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns the context with the span to use as parent for the next spans
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the current span from context or nil
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start creates the new span, child of the span in context if it's there
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !Enabled() {
+		return ctx, nil
+	}
+	span := &Span{Name: name, Kind: KindInternal, Start: time.Now(), Attributes: attrs}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		rand.Read(span.TraceID[:])
+	}
+	rand.Read(span.SpanID[:])
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartTrace creates the new span in the specified trace, useful when the trace id could be
+// derived from the object (like Application UID) to join the spans of the different nodes. If
+// there is no parent of the same trace in context - the span is the child of the trace root span.
+func StartTrace(ctx context.Context, traceID TraceID, name string, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if parent := SpanFromContext(ctx); parent != nil && parent.TraceID == traceID {
+		return Start(ctx, name, attrs...)
+	}
+	if !Enabled() {
+		return ctx, nil
+	}
+	span := &Span{TraceID: traceID, ParentID: RootSpanID(traceID), Name: name, Kind: KindInternal, Start: time.Now(), Attributes: attrs}
+	rand.Read(span.SpanID[:])
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartTraceRoot creates the root span of the specified trace, the span in context (like the API
+// request one) is the parent of the root span if it's in the same trace or linked otherwise
+func StartTraceRoot(ctx context.Context, traceID TraceID, name string, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !Enabled() {
+		return ctx, nil
+	}
+	span := &Span{TraceID: traceID, SpanID: RootSpanID(traceID), Name: name, Kind: KindInternal, Start: time.Now(), Attributes: attrs}
+	if parent := SpanFromContext(ctx); parent != nil {
+		if parent.TraceID == traceID {
+			span.ParentID = parent.SpanID
+		} else {
+			span.Links = append(span.Links, Link{TraceID: parent.TraceID, SpanID: parent.SpanID})
+		}
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// RootSpanID returns the root span id derived from the trace id, so the spans started by the
+// different nodes could be parented to the same root span without passing it around
+func RootSpanID(traceID TraceID) SpanID {
+	sum := sha256.Sum256(traceID[:])
+	var id SpanID
+	copy(id[:], sum[:len(id)])
+	return id
+}
+
+// SetKind changes the kind of the span
+func (s *Span) SetKind(kind int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Kind = kind
+	s.mu.Unlock()
+}
+
+// SetAttributes adds the attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Attributes = append(s.Attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent adds the point in time event to the span
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Events = append(s.Events, Event{Time: time.Now(), Name: name, Attributes: attrs})
+	s.mu.Unlock()
+}
+
+// SetError marks the span as failed if err is not nil
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Error = err.Error()
+	s.mu.Unlock()
+}
+
+// Finish ends the span and sends it to the exporter, the next calls are ignored
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = time.Now()
+	s.mu.Unlock()
+	export(s)
+}
+
+// Traceparent returns W3C trace context header value to propagate the span to another node
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", s.TraceID, s.SpanID)
+}
+
+// ParseTraceparent creates the remote parent span from W3C trace context header value
+func ParseTraceparent(value string) (*Span, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return nil, fmt.Errorf("Tracing: Wrong traceparent format: %q", value)
+	}
+	span := &Span{ended: true}
+	if _, err := hex.Decode(span.TraceID[:], []byte(parts[1])); err != nil {
+		return nil, fmt.Errorf("Tracing: Wrong trace id in traceparent: %v", err)
+	}
+	if _, err := hex.Decode(span.SpanID[:], []byte(parts[2])); err != nil {
+		return nil, fmt.Errorf("Tracing: Wrong span id in traceparent: %v", err)
+	}
+	if span.TraceID == (TraceID{}) || span.SpanID == (SpanID{}) {
+		return nil, fmt.Errorf("Tracing: Invalid zero ids in traceparent")
+	}
+	return span, nil
+}
@@ -0,0 +1,120 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/util"
+)
+
+func Test_traceparent(t *testing.T) {
+	span := &Span{TraceID: TraceID{1, 2, 3}, SpanID: SpanID{4, 5, 6}}
+	value := span.Traceparent()
+	if value != "00-01020300000000000000000000000000-0405060000000000-01" {
+		t.Fatalf("Wrong traceparent: %s", value)
+	}
+
+	parsed, err := ParseTraceparent(value)
+	if err != nil {
+		t.Fatalf("Unable to parse traceparent: %v", err)
+	}
+	if parsed.TraceID != span.TraceID || parsed.SpanID != span.SpanID {
+		t.Fatalf("Wrong parsed ids: %s %s", parsed.TraceID, parsed.SpanID)
+	}
+
+	for _, wrong := range []string{"", "00-0102-0405-01", "00-00000000000000000000000000000000-0405060000000000-01"} {
+		if _, err := ParseTraceparent(wrong); err == nil {
+			t.Fatalf("Traceparent %q should not be parsed", wrong)
+		}
+	}
+}
+
+func Test_export_otlp(t *testing.T) {
+	received := make(chan otlpTraces, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("X-Test") != "ok" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data otlpTraces
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &data)
+		received <- data
+	}))
+	defer srv.Close()
+
+	// Disabled tracer returns nil spans which are safe to use
+	ctx, span := Start(context.Background(), "disabled")
+	span.SetError(errors.New("test"))
+	span.Finish()
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatalf("Span should be nil when tracing is disabled")
+	}
+
+	Init(Config{Endpoint: srv.URL, Headers: map[string]string{"X-Test": "ok"}, FlushInterval: util.Duration(time.Hour)}, Attr("host.name", "node-1"))
+
+	reqCtx, req := Start(context.Background(), "request")
+	_, root := StartTraceRoot(reqCtx, TraceID{1}, "root")
+	ctx, parent := StartTrace(context.Background(), TraceID{1}, "parent", Attr("count", 2), Attr("round", uint32(3)), Attr("big", uint64(math.MaxUint64)))
+	_, child := Start(ctx, "child")
+	child.AddEvent("state", Attr("status", "NEW"))
+	child.SetError(errors.New("failed"))
+	child.Finish()
+	parent.Finish()
+	root.Finish()
+	req.Finish()
+
+	Close()
+
+	var data otlpTraces
+	select {
+	case data = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Spans were not exported")
+	}
+
+	rs := data.ResourceSpans[0]
+	if len(rs.Resource.Attributes) != 2 || rs.Resource.Attributes[0].Value["stringValue"] != "aquarium-fish" {
+		t.Fatalf("Wrong resource attributes: %v", rs.Resource.Attributes)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 4 {
+		t.Fatalf("Wrong amount of spans: %d", len(spans))
+	}
+	if spans[0].Name != "child" || spans[0].TraceID != "01000000000000000000000000000000" || spans[0].ParentSpanID != spans[1].SpanID {
+		t.Fatalf("Wrong child span: %+v", spans[0])
+	}
+	if spans[0].Status.Code != 2 || spans[0].Status.Message != "failed" || len(spans[0].Events) != 1 {
+		t.Fatalf("Wrong child span status or events: %+v", spans[0])
+	}
+	attrs := spans[1].Attributes
+	if attrs[0].Value["intValue"] != "2" || attrs[1].Value["intValue"] != "3" || attrs[2].Value["stringValue"] != "18446744073709551615" {
+		t.Fatalf("Wrong parent span attributes: %+v", attrs)
+	}
+	// Spans without parent in context are joined to the trace root one, which is linked to the request
+	if spans[1].ParentSpanID != spans[2].SpanID || spans[2].SpanID != RootSpanID(TraceID{1}).String() {
+		t.Fatalf("Wrong parent span of the trace: %+v", spans[1])
+	}
+	if spans[2].ParentSpanID != "" || len(spans[2].Links) != 1 || spans[2].Links[0].SpanID != spans[3].SpanID || spans[2].Links[0].TraceID != spans[3].TraceID {
+		t.Fatalf("Wrong root span links: %+v", spans[2])
+	}
+}
@@ -0,0 +1,35 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func Test_address_family(t *testing.T) {
+	tests := map[string]string{
+		"":                "ipv4",
+		"127.0.0.1":       "ipv4",
+		"0.0.0.0:2022":    "ipv4",
+		"localhost:2022":  "ipv4",
+		"::1":             "ipv6",
+		"[::]:2022":       "ipv6",
+		"[fe80::1]:22":    "ipv6",
+		"::ffff:10.0.0.1": "ipv4",
+	}
+	for addr, want := range tests {
+		if got := AddressFamily(addr); got != want {
+			t.Fatalf("AddressFamily(%q) = %q; want: %q", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Verify the delay grows exponentially and is limited by max
+func Test_backoff_grows_to_max(t *testing.T) {
+	expected := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		10 * time.Second, 10 * time.Second, 10 * time.Second,
+	}
+	for attempt, want := range expected {
+		t.Run(fmt.Sprintf("Testing attempt %d", attempt), func(t *testing.T) {
+			if out := Backoff(attempt, time.Second, 10*time.Second); out != want {
+				t.Fatalf("Backoff(%d) = %s; want: %s", attempt, out, want)
+			}
+		})
+	}
+	if out := Backoff(1000, time.Second, time.Minute); out != time.Minute {
+		t.Fatalf("Backoff(1000) = %s; want: %s", out, time.Minute)
+	}
+}
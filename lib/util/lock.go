@@ -17,6 +17,7 @@ import (
 	"math"
 	"math/bits"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
@@ -61,7 +62,7 @@ func WaitLock(lockPath string, clean func()) error {
 					os.Remove(lockPath)
 					break
 				}
-				if proc, err := os.FindProcess(int(pid)); err != nil || proc.Signal(syscall.Signal(0)) != nil {
+				if !processRunning(int(pid)) {
 					log.Warnf("Util: No process running for lock file '%s': %s", lockPath, lockInfo)
 					clean()
 					os.Remove(lockPath)
@@ -77,3 +78,17 @@ func WaitLock(lockPath string, clean func()) error {
 
 	return nil
 }
+
+// processRunning checks the process with pid exists, on Windows the signals are not supported but
+// FindProcess opens the process handle so fails if it's not running
+func processRunning(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		proc.Release()
+		return true
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
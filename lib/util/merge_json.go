@@ -0,0 +1,78 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"encoding/json"
+)
+
+// MergeJSON deeply merges the override json object into the base one: the nested objects are
+// merged, the other values of override are replacing the base ones. When skipZero is set the zero
+// values (0, "", false, null, empty objects and arrays) of override are not replacing the base.
+func MergeJSON(base, override []byte, skipZero bool) ([]byte, error) {
+	var b, o map[string]any
+	if len(base) > 0 {
+		if err := json.Unmarshal(base, &b); err != nil {
+			return nil, err
+		}
+	}
+	if len(override) > 0 {
+		if err := json.Unmarshal(override, &o); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(mergeMap(b, o, skipZero))
+}
+
+func mergeMap(base, override map[string]any, skipZero bool) map[string]any {
+	out := make(map[string]any, len(base)+len(override))
+	for key, val := range base {
+		out[key] = val
+	}
+	for key, val := range override {
+		if skipZero && isZeroJSON(val) {
+			continue
+		}
+		if om, ok := val.(map[string]any); ok {
+			if bm, ok := out[key].(map[string]any); ok {
+				out[key] = mergeMap(bm, om, skipZero)
+				continue
+			}
+		}
+		out[key] = val
+	}
+	return out
+}
+
+func isZeroJSON(val any) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		for _, item := range v {
+			if !isZeroJSON(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+var (
+	// base, override, skipZero result, full override result
+	TestMergeJSON = [][4]string{
+		{``, ``, `{}`, `{}`},
+		{`{"a":1}`, ``, `{"a":1}`, `{"a":1}`},
+		{``, `{"a":1}`, `{"a":1}`, `{"a":1}`},
+		{`{"a":1,"b":2}`, `{"b":3,"c":4}`, `{"a":1,"b":3,"c":4}`, `{"a":1,"b":3,"c":4}`},
+		{`{"a":1,"b":"x"}`, `{"a":0,"b":""}`, `{"a":1,"b":"x"}`, `{"a":0,"b":""}`},
+		{`{"a":true}`, `{"a":false}`, `{"a":true}`, `{"a":false}`},
+		{`{"a":{"b":1,"c":2}}`, `{"a":{"c":3}}`, `{"a":{"b":1,"c":3}}`, `{"a":{"b":1,"c":3}}`},
+		{`{"a":{"b":1}}`, `{"a":{"b":0}}`, `{"a":{"b":1}}`, `{"a":{"b":0}}`},
+		{`{"a":[1,2]}`, `{"a":[3]}`, `{"a":[3]}`, `{"a":[3]}`},
+		{`{"a":[1,2]}`, `{"a":[]}`, `{"a":[1,2]}`, `{"a":[]}`},
+		{`{"a":{"b":1}}`, `{"a":"x"}`, `{"a":"x"}`, `{"a":"x"}`},
+	}
+)
+
+// Verify the json objects are merged properly with and without skipping the zero values
+func Test_merge_json(t *testing.T) {
+	for _, testcase := range TestMergeJSON {
+		t.Run("Merging "+testcase[0]+" with "+testcase[1], func(t *testing.T) {
+			out, err := MergeJSON([]byte(testcase[0]), []byte(testcase[1]), true)
+			if err != nil || string(out) != testcase[2] {
+				t.Fatalf("MergeJSON(`%s`, `%s`, true) = `%s`, %v; want: `%s`", testcase[0], testcase[1], out, err, testcase[2])
+			}
+			out, err = MergeJSON([]byte(testcase[0]), []byte(testcase[1]), false)
+			if err != nil || string(out) != testcase[3] {
+				t.Fatalf("MergeJSON(`%s`, `%s`, false) = `%s`, %v; want: `%s`", testcase[0], testcase[1], out, err, testcase[3])
+			}
+		})
+	}
+}
+
+// Verify the invalid json is reported
+func Test_merge_json_invalid(t *testing.T) {
+	if _, err := MergeJSON([]byte(`{"a":`), []byte(`{}`), false); err == nil {
+		t.Fatalf("MergeJSON should fail on invalid base")
+	}
+	if _, err := MergeJSON([]byte(`{}`), []byte(`[1]`), false); err == nil {
+		t.Fatalf("MergeJSON should fail on not object override")
+	}
+}
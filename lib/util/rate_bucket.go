@@ -0,0 +1,72 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"math"
+	"time"
+)
+
+// RateBucket is the token bucket which allows the burst of requests and refills with the sustained
+// rate, it's not thread safe so the caller should protect it
+type RateBucket struct {
+	Rate  float64 // Tokens added per second
+	Burst int     // Max tokens in the bucket
+
+	tokens float64
+	last   time.Time
+}
+
+// NewRateBucket creates the full bucket
+func NewRateBucket(rate float64, burst int) *RateBucket {
+	if burst < 1 {
+		burst = int(math.Ceil(rate))
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &RateBucket{Rate: rate, Burst: burst, tokens: float64(burst)}
+}
+
+// refill adds the tokens for the time passed since the last call
+func (b *RateBucket) refill(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(float64(b.Burst), b.tokens+now.Sub(b.last).Seconds()*b.Rate)
+	}
+	b.last = now
+}
+
+// Take consumes one token if available, otherwise returns how long to wait for the next one
+func (b *RateBucket) Take(now time.Time) (ok bool, wait time.Duration) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if b.Rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	return false, time.Duration((1 - b.tokens) / b.Rate * float64(time.Second))
+}
+
+// Remaining returns the number of whole tokens available right now
+func (b *RateBucket) Remaining(now time.Time) int {
+	b.refill(now)
+	return int(b.tokens)
+}
+
+// Full returns true when the bucket is refilled completely, so could be forgotten
+func (b *RateBucket) Full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.Burst)
+}
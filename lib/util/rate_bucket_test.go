@@ -0,0 +1,58 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// Verify the burst is available at once and then the bucket refills with the rate
+func Test_rate_bucket_burst_and_refill(t *testing.T) {
+	now := time.Now()
+	b := NewRateBucket(2, 3)
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.Take(now); !ok {
+			t.Fatalf("Take %d of the burst failed", i)
+		}
+	}
+	ok, wait := b.Take(now)
+	if ok {
+		t.Fatalf("Take over the burst succeeded")
+	}
+	if wait != 500*time.Millisecond {
+		t.Fatalf("Wait = %s; want: %s", wait, 500*time.Millisecond)
+	}
+	if r := b.Remaining(now.Add(time.Second)); r != 2 {
+		t.Fatalf("Remaining after 1s = %d; want: 2", r)
+	}
+	if b.Full(now.Add(time.Second)) {
+		t.Fatalf("Bucket should not be full after 1s")
+	}
+	if !b.Full(now.Add(time.Hour)) {
+		t.Fatalf("Bucket should be full after 1h")
+	}
+	if r := b.Remaining(now.Add(2 * time.Hour)); r != 3 {
+		t.Fatalf("Remaining is over the burst: %d", r)
+	}
+}
+
+// Verify the burst defaults to the rate rounded up
+func Test_rate_bucket_default_burst(t *testing.T) {
+	if b := NewRateBucket(2.5, 0); b.Burst != 3 {
+		t.Fatalf("Burst = %d; want: 3", b.Burst)
+	}
+	if b := NewRateBucket(0.1, 0); b.Burst != 1 {
+		t.Fatalf("Burst = %d; want: 1", b.Burst)
+	}
+}
@@ -0,0 +1,29 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// SeededRand returns the deterministic random generator for the seed, the salts allow to get the
+// different sequences from the same seed for the different consumers
+func SeededRand(seed int64, salts ...string) *rand.Rand {
+	h := fnv.New64a()
+	for _, salt := range salts {
+		h.Write([]byte(salt))
+		h.Write([]byte{0})
+	}
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64()))) // #nosec G404
+}
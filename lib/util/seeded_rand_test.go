@@ -0,0 +1,40 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func Test_seeded_rand_repeats(t *testing.T) {
+	r1 := SeededRand(42, "test")
+	r2 := SeededRand(42, "test")
+	for i := 0; i < 10; i++ {
+		if v1, v2 := r1.Int63(), r2.Int63(); v1 != v2 {
+			t.Fatalf("Same seed gives different values on step %d: %d != %d", i, v1, v2)
+		}
+	}
+}
+
+func Test_seeded_rand_salts(t *testing.T) {
+	if SeededRand(42, "node-1").Int63() == SeededRand(42, "node-2").Int63() {
+		t.Fatalf("Different salts should give different sequences")
+	}
+	// Salts are separated, so the concatenation is not giving the same sequence
+	if SeededRand(42, "ab", "c").Int63() == SeededRand(42, "a", "bc").Int63() {
+		t.Fatalf("Salts boundaries should change the sequence")
+	}
+	if SeededRand(42).Int63() == SeededRand(43).Int63() {
+		t.Fatalf("Different seeds should give different sequences")
+	}
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/adobe/aquarium-fish/lib/log"
+)
+
+// If the process did not panic for this time - the restart backoff is reset
+const superviseStablePeriod = 10 * time.Minute
+
+// GoSupervised runs the long-living background process and restarts it with backoff in case of
+// panic, so the failing driver process will not take down the whole node
+func GoSupervised(name string, fn func()) {
+	go func() {
+		panics := 0
+		for {
+			started := time.Now()
+			if !runRecovered(name, fn) {
+				return
+			}
+			if time.Since(started) > superviseStablePeriod {
+				panics = 0
+			}
+			delay := Backoff(panics, time.Second, 5*time.Minute)
+			panics++
+			log.Warnf("Process %s will be restarted in %s", name, delay)
+			time.Sleep(delay)
+		}
+	}()
+}
+
+// runRecovered executes the function and returns true if it panicked
+func runRecovered(name string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Process %s panic: %v\n%s", name, r, debug.Stack())
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
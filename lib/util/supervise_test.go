@@ -0,0 +1,41 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Verify the panicked process is restarted and the completed one is not
+func Test_go_supervised(t *testing.T) {
+	var runs atomic.Int32
+	done := make(chan struct{})
+	GoSupervised("test", func() {
+		if runs.Add(1) == 1 {
+			panic("test panic")
+		}
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Process was not restarted after panic")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := runs.Load(); n != 2 {
+		t.Fatalf("Process should run 2 times, but run %d", n)
+	}
+}
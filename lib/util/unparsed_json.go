@@ -21,8 +21,11 @@ import (
 // UnparsedJSON is used to store json as is and not parse it until the right time
 type UnparsedJSON string
 
-// MarshalJSON represents UnparsedJson as bytes
+// MarshalJSON represents UnparsedJson as bytes, the empty value is represented as empty object
 func (r UnparsedJSON) MarshalJSON() ([]byte, error) {
+	if r == "" {
+		return []byte("{}"), nil
+	}
 	return []byte(r), nil
 }
 
@@ -0,0 +1,42 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package util
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnparsedJSONMarshalEmpty(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Options UnparsedJSON `json:"options"`
+	}{})
+	if err != nil {
+		t.Fatalf(`json.Marshal(empty) = %v, want: nil`, err)
+	}
+	if string(data) != `{"options":{}}` {
+		t.Fatalf(`json.Marshal(empty) = %s, want: {"options":{}}`, data)
+	}
+}
+
+func TestUnparsedJSONMarshalValue(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Options UnparsedJSON `json:"options"`
+	}{Options: `{"a":1}`})
+	if err != nil {
+		t.Fatalf(`json.Marshal(value) = %v, want: nil`, err)
+	}
+	if string(data) != `{"options":{"a":1}}` {
+		t.Fatalf(`json.Marshal(value) = %s, want: {"options":{"a":1}}`, data)
+	}
+}
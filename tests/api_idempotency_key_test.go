@@ -0,0 +1,277 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"gorm.io/gorm"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Idempotency-Key of the mutating requests:
+// * Retried Application create with the same key returns the same Application
+// * Only one Application is created
+// * The key can't be reused for the other request
+// * The key can't be reused for the same request with other body
+// * Request with the other key creates new Application
+// * Request with the key can't bypass the body size limit
+// * Concurrent requests with the same key create one Application and get no server error
+// * The key left in progress by the stopped node is released after timeout
+func Test_api_idempotency_key(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application with the key", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			Header("Idempotency-Key", "ci-job-1").
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			HeaderNotPresent("Idempotent-Replayed").
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Retry with the same key should return the same Application", func(t *testing.T) {
+		var retried types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			Header("Idempotency-Key", "ci-job-1").
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Idempotent-Replayed", "true").
+			End().
+			JSON(&retried)
+
+		if retried.UID != app.UID {
+			t.Fatalf("Retried Application UID is not the same: %v != %v", retried.UID, app.UID)
+		}
+	})
+
+	t.Run("Only one Application should be created", func(t *testing.T) {
+		var apps []types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+
+		if len(apps) != 1 {
+			t.Fatalf("Expected 1 Application, got: %d", len(apps))
+		}
+	})
+
+	t.Run("Key can't be reused for the other request", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			Header("Idempotency-Key", "ci-job-1").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("Key can't be reused for the request with other body", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			Header("Idempotency-Key", "ci-job-1").
+			JSON(`{"label_UID":"`+label.UID.String()+`", "metadata":{"JOB":"other"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("Other key should create new Application", func(t *testing.T) {
+		var other types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			Header("Idempotency-Key", "ci-job-2").
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&other)
+
+		if other.UID == uuid.Nil || other.UID == app.UID {
+			t.Fatalf("Other Application UID is incorrect: %v", other.UID)
+		}
+	})
+
+	t.Run("Key can't be used to bypass the body size limit", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			Header("Idempotency-Key", "ci-job-3").
+			JSON(`{"label_UID":"`+label.UID.String()+`", "metadata":{"JOB":"`+strings.Repeat("a", 128*1024)+`"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusRequestEntityTooLarge).
+			End()
+	})
+
+	t.Run("Concurrent requests with the same key should create one Application", func(t *testing.T) {
+		var wg sync.WaitGroup
+		statuses := make([]int, 5)
+		for i := range statuses {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				req, _ := http.NewRequest(http.MethodPost, afi.APIAddress("api/v1/application/"),
+					strings.NewReader(`{"label_UID":"`+label.UID.String()+`"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Idempotency-Key", "ci-job-4")
+				req.SetBasicAuth("admin", afi.AdminToken())
+				resp, err := cli.Do(req)
+				if err != nil {
+					t.Errorf("Unable to send request: %v", err)
+					return
+				}
+				resp.Body.Close()
+				statuses[i] = resp.StatusCode
+			}(i)
+		}
+		wg.Wait()
+
+		for _, status := range statuses {
+			if status != http.StatusOK && status != http.StatusConflict {
+				t.Fatalf("Unexpected concurrent request status: %v", statuses)
+			}
+		}
+
+		var apps []types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+
+		// The first one and the one with the other key are created before
+		if len(apps) != 3 {
+			t.Fatalf("Expected 3 Applications, got: %d", len(apps))
+		}
+	})
+
+	t.Run("Key left in progress by the stopped node is released after timeout", func(t *testing.T) {
+		body := `{"label_UID":"` + label.UID.String() + `"}`
+		bodyHash := sha256.Sum256([]byte(body))
+
+		// Simulating the requests interrupted by the node stop long ago and just now
+		afi.Stop(t)
+		files, _ := filepath.Glob(filepath.Join(afi.Workspace(), "fish_data", "*", "sqlite.db"))
+		if len(files) != 1 {
+			t.Fatalf("Unable to find the node database: %v", files)
+		}
+		db, err := gorm.Open(sqlite.Open(files[0]), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("Unable to open the node database: %v", err)
+		}
+		for key, createdAt := range map[string]time.Time{
+			"ci-job-5": time.Now().Add(-10 * time.Minute),
+			"ci-job-6": time.Now(),
+		} {
+			err = db.Exec("INSERT INTO idempotency_records (key, request, body_hash, status, created_at) VALUES (?, ?, ?, 0, ?)",
+				"admin/"+key, "POST /api/v1/application/", hex.EncodeToString(bodyHash[:]), createdAt).Error
+			if err != nil {
+				t.Fatalf("Unable to insert the idempotency record: %v", err)
+			}
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		afi.Start(t)
+
+		for key, status := range map[string]int{"ci-job-5": http.StatusOK, "ci-job-6": http.StatusConflict} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				Header("Idempotency-Key", key).
+				JSON(body).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(status).
+				End()
+		}
+	})
+}
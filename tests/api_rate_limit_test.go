@@ -0,0 +1,156 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the API rate limit:
+// * Operation rule allows the burst and rejects the next request with Retry-After
+// * Service rule is shared by the service operations
+// * Admin is not limited
+// * User could get the current consumption
+func Test_api_rate_limit(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+rate_limit:
+  rules:
+    UserMeGet:
+      rate: 0.01
+      burst: 2
+    Label:
+      rate: 0.01
+      burst: 1
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User could make the burst of requests", func(t *testing.T) {
+		for _, remaining := range []string{"1", "0"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/user/me/")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(t).
+				Status(http.StatusOK).
+				Header("X-RateLimit-Rule", "UserMeGet").
+				Header("X-RateLimit-Limit", "2").
+				Header("X-RateLimit-Remaining", remaining).
+				End()
+		}
+	})
+
+	t.Run("User request over the burst is rejected", func(t *testing.T) {
+		resp := apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusTooManyRequests).
+			End()
+
+		if resp.Response.Header.Get("Retry-After") == "" {
+			t.Fatalf("Retry-After header is not set")
+		}
+	})
+
+	t.Run("Service rule is shared by the service operations", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("X-RateLimit-Rule", "Label").
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/00000000-0000-0000-0000-000000000000")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusTooManyRequests).
+			End()
+	})
+
+	t.Run("Admin is not limited", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/user/me/")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				HeaderNotPresent("X-RateLimit-Rule").
+				End()
+		}
+	})
+
+	t.Run("User could get the rate limits consumption", func(t *testing.T) {
+		var limits []types.RateLimit
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/ratelimit")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&limits)
+
+		if len(limits) != 2 {
+			t.Fatalf("Rate limits are incorrect: %+v", limits)
+		}
+		for _, l := range limits {
+			if l.Remaining != 0 {
+				t.Fatalf("Rate limit %s remaining is incorrect: %d", l.Rule, l.Remaining)
+			}
+		}
+	})
+}
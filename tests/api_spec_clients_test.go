@@ -0,0 +1,110 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the node serves the API spec and the generated clients:
+// * OpenAPI spec is available without auth in json and yaml
+// * Generated client archive is served from the clients directory
+func Test_api_spec_clients(t *testing.T) {
+	t.Parallel()
+	clientsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(clientsDir, "aquarium-fish-client-python-v0.0.1.tar.gz"), []byte("test-archive"), 0o600); err != nil {
+		t.Fatalf("Unable to create client archive: %v", err)
+	}
+
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+api_clients_directory: `+clientsDir+`
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("OpenAPI spec is served in json", func(t *testing.T) {
+		resp := apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/openapi.json")).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		var spec struct {
+			OpenAPI string                    `json:"openapi"`
+			Paths   map[string]map[string]any `json:"paths"`
+		}
+		if err := json.NewDecoder(resp.Response.Body).Decode(&spec); err != nil {
+			t.Fatalf("Unable to parse the spec: %v", err)
+		}
+		if !strings.HasPrefix(spec.OpenAPI, "3.") || spec.Paths["/api/v1/application/"] == nil {
+			t.Fatalf("Spec is incorrect: %s, %d paths", spec.OpenAPI, len(spec.Paths))
+		}
+	})
+
+	t.Run("OpenAPI spec is served in yaml", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/openapi.yaml")).
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Content-Type", "application/yaml").
+			End()
+	})
+
+	t.Run("Generated client is served", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/clients/aquarium-fish-client-python-v0.0.1.tar.gz")).
+			Expect(t).
+			Status(http.StatusOK).
+			Body("test-archive").
+			End()
+	})
+
+	t.Run("Clients are listed", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/clients/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
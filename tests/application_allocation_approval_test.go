@@ -0,0 +1,196 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the allocation approval of the expensive Labels:
+// * Application of the Label with requires_approval is waiting in PENDING_APPROVAL
+// * Regular user can't approve it
+// * Approver approves one Application and it gets allocated
+// * Approver rejects another Application and it gets recalled
+func Test_application_allocation_approval(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create Users", func(t *testing.T) {
+		for _, name := range []string{"test-user", "test-approver"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"`+name+`-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-approver/grant/")).
+			JSON(map[string]any{"role": "approver", "expires_at": time.Now().Add(time.Hour)}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "requires_approval":true, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	apps := make([]types.Application, 2)
+	t.Run("Create Applications", func(t *testing.T) {
+		for i := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+				BasicAuth("test-user", "test-user-password").
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&apps[i])
+
+			if apps[i].UID == uuid.Nil {
+				t.Fatalf("Application UID is incorrect: %v", apps[i].UID)
+			}
+		}
+	})
+
+	t.Run("Applications should stay PENDING_APPROVAL", func(t *testing.T) {
+		time.Sleep(3 * time.Second)
+		for _, app := range apps {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusPENDINGAPPROVAL {
+				t.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		}
+	})
+
+	t.Run("Regular user can't approve the Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/approve")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Approver approves the first Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/approve")).
+			BasicAuth("test-approver", "test-approver-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Approver rejects the second Application", func(t *testing.T) {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[1].UID.String()+"/reject")).
+			Query("reason", "too expensive").
+			BasicAuth("test-approver", "test-approver-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusRECALLED {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Approved Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/state")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Rejected Application can't be approved", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[1].UID.String()+"/approve")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
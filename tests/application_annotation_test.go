@@ -0,0 +1,214 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the gate annotates the served Application with the business-level status:
+// * Jenkins gate provisions the agent and Application for it
+// * The agent idle status is attached to the Application as annotation
+// * Annotation is sent by the Application state stream and available by the API
+// * Other users can't see the Application annotations
+func Test_application_annotation(t *testing.T) {
+	t.Parallel()
+
+	idleSince := time.Date(2024, 5, 1, 10, 2, 0, 0, time.Local)
+	jenkins := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/jenkins-agent.jnlp"):
+			fmt.Fprint(w, `<jnlp><application-desc><argument>test-secret</argument></application-desc></jnlp>`)
+		case strings.HasSuffix(r.URL.Path, "/api/json"):
+			fmt.Fprintf(w, `{"idle":true, "offline":false, "idleStartMilliseconds":%d}`, idleSince.UnixMilli())
+		}
+	}))
+	defer jenkins.Close()
+
+	// Getting the free port for the gate provisioning endpoint
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to find free port: %v", err)
+	}
+	gateAddress := listener.Addr().String()
+	listener.Close()
+
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+
+gates:
+  - name: jenkins
+    cfg:
+      url: `+jenkins.URL+`
+      username: fish
+      api_token: test-api-token
+      address: `+gateAddress+`
+      token: test-gate-token
+      idle_timeout: 1h
+      check_interval: 1s`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Create Label", func(t *testing.T) {
+		var label types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	// The stream is opened before provisioning to receive the annotation event
+	req, _ := http.NewRequest(http.MethodGet, afi.APIAddress("api/v1/application/stream"), http.NoBody)
+	req.SetBasicAuth("admin", afi.AdminToken())
+	streamCli := &http.Client{Transport: tr}
+	resp, err := streamCli.Do(req)
+	if err != nil {
+		t.Fatalf("Unable to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	annotations := make(chan types.ApplicationAnnotation, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var event types.ApplicationStateEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil && event.Annotation != nil {
+				annotations <- *event.Annotation
+			}
+		}
+	}()
+
+	var appUID types.ApplicationUID
+	t.Run("Jenkins requests the agent", func(t *testing.T) {
+		var out struct {
+			Agents []struct {
+				Name           string               `json:"name"`
+				ApplicationUID types.ApplicationUID `json:"application_uid"`
+			} `json:"agents"`
+		}
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			req, _ := http.NewRequest(http.MethodPost, "http://"+gateAddress+"/provision", strings.NewReader(`{"label":"test-label"}`))
+			req.Header.Set("Authorization", "Bearer test-gate-token")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				r.Fatalf("Unable to request the agent: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				r.Fatalf("Provisioning failed with status %d", resp.StatusCode)
+			}
+			json.NewDecoder(resp.Body).Decode(&out)
+		})
+		if len(out.Agents) != 1 || out.Agents[0].ApplicationUID == uuid.Nil {
+			t.Fatalf("Provisioned agents are incorrect: %v", out.Agents)
+		}
+		appUID = out.Agents[0].ApplicationUID
+	})
+
+	expectedText := "idle since " + idleSince.Format("15:04")
+
+	t.Run("Annotation is received by the stream in 10 sec", func(t *testing.T) {
+		select {
+		case a := <-annotations:
+			if a.ApplicationUID != appUID || a.Source != "jenkins" || a.Key != "agent" || !strings.HasSuffix(a.Text, expectedText) {
+				t.Fatalf("Annotation is incorrect: %v", a)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Annotation was not received")
+		}
+	})
+
+	t.Run("Annotation is not duplicated while status is the same", func(t *testing.T) {
+		select {
+		case a := <-annotations:
+			t.Fatalf("Unexpected annotation event: %v", a)
+		case <-time.After(3 * time.Second):
+		}
+	})
+
+	t.Run("Admin gets the Application annotations", func(t *testing.T) {
+		var list []types.ApplicationAnnotation
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+appUID.String()+"/annotation/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&list)
+
+		if len(list) != 1 || !strings.HasSuffix(list[0].Text, expectedText) {
+			t.Fatalf("Annotations are incorrect: %v", list)
+		}
+	})
+
+	t.Run("Other user can't get the Application annotations", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+appUID.String()+"/annotation/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,190 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the batch of Applications is created and the gang batch is all-or-nothing:
+// * Gang batch of 3 Applications can't be allocated completely on the node with capacity of 2
+// * Gang batch is released after the gang timeout
+// * Regular batch of 2 Applications is allocated completely
+func Test_application_batch(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+    cfg:
+      cpu_limit: 4
+      ram_limit: 8`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":2,"ram":4}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Batch with wrong count is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/batch/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "count":0}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var gang types.ApplicationBatch
+	t.Run("Create gang batch", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/batch/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "count":3, "gang":true, "gang_timeout":"30s"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&gang)
+
+		if gang.UID == uuid.Nil {
+			t.Fatalf("Batch UID is incorrect: %v", gang.UID)
+		}
+	})
+
+	t.Run("Gang batch should be partially ALLOCATED in 20 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var state types.ApplicationBatchState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/batch/"+gang.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&state)
+
+			if state.Total != 3 || state.Counts["ALLOCATED"] != 2 || state.Counts["NEW"] != 1 {
+				r.Fatalf("Batch state is incorrect: %v", state.Counts)
+			}
+		})
+	})
+
+	t.Run("Gang batch should be released after timeout", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 2 * time.Second}, t, func(r *h.R) {
+			var state types.ApplicationBatchState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/batch/"+gang.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&state)
+
+			if state.Counts["DEALLOCATED"] != 2 || state.Counts["RECALLED"] != 1 {
+				r.Fatalf("Batch state is incorrect: %v", state.Counts)
+			}
+		})
+	})
+
+	var batch types.ApplicationBatch
+	t.Run("Create regular batch", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/batch/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "count":2}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&batch)
+
+		if batch.UID == uuid.Nil {
+			t.Fatalf("Batch UID is incorrect: %v", batch.UID)
+		}
+	})
+
+	t.Run("Regular batch should be ALLOCATED in 20 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var state types.ApplicationBatchState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/batch/"+batch.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&state)
+
+			if state.Total != 2 || state.Counts["ALLOCATED"] != 2 {
+				r.Fatalf("Batch state is incorrect: %v", state.Counts)
+			}
+		})
+	})
+
+	t.Run("Batch Applications are listed with the batch UID", func(t *testing.T) {
+		var apps []types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			Query("filter", "batch_uid = '"+batch.UID.String()+"'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+
+		if len(apps) != 2 {
+			t.Fatalf("Wrong number of batch Applications: %d", len(apps))
+		}
+	})
+}
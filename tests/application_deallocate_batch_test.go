@@ -0,0 +1,181 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the filtered Applications list and the batch deallocate:
+// * List is filtered by the Label, owner and status
+// * Dry run returns the matching Applications without deallocating them
+// * Regular user deallocates only own Applications with the batch
+// * Operator deallocates the Applications of all the users
+func Test_application_deallocate_batch(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var labelA, labelB types.Label
+	t.Run("Create Labels and User", func(t *testing.T) {
+		for name, label := range map[string]*types.Label{"label-a": &labelA, "label-b": &labelB} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(`{"name":"`+name+`", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(label)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	create := func(t *testing.T, label types.Label, user, password string) (app types.Application) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth(user, password).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+		return app
+	}
+	list := func(t apitest.TestingT, query map[string]string) (apps []types.Application) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			QueryParams(query).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+		return apps
+	}
+	batch := func(t *testing.T, body, user, password string) (res types.ApplicationDeallocateBatchResult) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/deallocate")).
+			JSON(body).
+			BasicAuth(user, password).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+		return res
+	}
+
+	t.Run("Allocate Applications", func(t *testing.T) {
+		create(t, labelA, "admin", afi.AdminToken())
+		create(t, labelA, "test-user", "test-user-password")
+		create(t, labelB, "test-user", "test-user-password")
+
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if apps := list(r, map[string]string{"status": string(types.ApplicationStatusALLOCATED)}); len(apps) != 3 {
+				r.Fatalf("Not all the Applications are allocated: %d", len(apps))
+			}
+		})
+	})
+
+	t.Run("List is filtered", func(t *testing.T) {
+		if apps := list(t, map[string]string{"label_uid": labelA.UID.String()}); len(apps) != 2 {
+			t.Fatalf("Wrong amount of the Label Applications: %d", len(apps))
+		}
+		if apps := list(t, map[string]string{"owner_name": "test-user", "label_uid": labelB.UID.String()}); len(apps) != 1 {
+			t.Fatalf("Wrong amount of the owner Label Applications: %d", len(apps))
+		}
+		if apps := list(t, map[string]string{"older_than": "1h"}); len(apps) != 0 {
+			t.Fatalf("Wrong amount of the old Applications: %d", len(apps))
+		}
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			Query("older_than", "wrong").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Dry run only returns the matched Applications", func(t *testing.T) {
+		res := batch(t, `{"selector":{"label_UID":"`+labelA.UID.String()+`"}, "dry_run":true}`, "admin", afi.AdminToken())
+		if len(res.Deallocated) != 2 {
+			t.Fatalf("Wrong amount of the matched Applications: %v", res)
+		}
+		if apps := list(t, map[string]string{"status": string(types.ApplicationStatusALLOCATED)}); len(apps) != 3 {
+			t.Fatalf("Dry run should not deallocate: %d", len(apps))
+		}
+	})
+
+	t.Run("User deallocates only own Applications", func(t *testing.T) {
+		res := batch(t, `{"selector":{"label_UID":"`+labelA.UID.String()+`"}}`, "test-user", "test-user-password")
+		if len(res.Deallocated) != 1 || len(res.Failed) != 0 {
+			t.Fatalf("Wrong batch result: %v", res)
+		}
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if apps := list(r, map[string]string{"status": string(types.ApplicationStatusDEALLOCATED)}); len(apps) != 1 {
+				r.Fatalf("Wrong amount of the deallocated Applications: %d", len(apps))
+			}
+		})
+	})
+
+	t.Run("Operator deallocates all the selected Applications", func(t *testing.T) {
+		res := batch(t, `{"selector":{"status":"ALLOCATED"}}`, "admin", afi.AdminToken())
+		if len(res.Deallocated) != 2 || len(res.Failed) != 0 {
+			t.Fatalf("Wrong batch result: %v", res)
+		}
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if apps := list(r, map[string]string{"status": string(types.ApplicationStatusDEALLOCATED)}); len(apps) != 3 {
+				r.Fatalf("Wrong amount of the deallocated Applications: %d", len(apps))
+			}
+		})
+	})
+}
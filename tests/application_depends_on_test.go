@@ -0,0 +1,187 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Application dependencies:
+// * Client Application waits in NEW while the server one can't be allocated
+// * Client Application fails when the server one is deallocated before allocation
+// * Client Application is allocated after the server one and gets the server address
+func Test_application_depends_on(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+    cfg:
+      cpu_limit: 4
+      ram_limit: 8`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	createLabel := func(t *testing.T, name string, cpu int) types.Label {
+		var label types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"`+name+`", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":`+fmt.Sprint(cpu)+`,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+		return label
+	}
+
+	createApp := func(t *testing.T, body string) types.Application {
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(body).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+		return app
+	}
+
+	getState := func(t apitest.TestingT, app types.Application) types.ApplicationState {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+		return appState
+	}
+
+	var label, bigLabel types.Label
+	t.Run("Create Labels", func(t *testing.T) {
+		label = createLabel(t, "test-label", 1)
+		bigLabel = createLabel(t, "test-big-label", 5)
+	})
+
+	t.Run("Dependency should exist", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "depends_on":["`+uuid.NewString()+`"]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var bigServer, failClient types.Application
+	t.Run("Client waits for the server which can't be allocated", func(t *testing.T) {
+		bigServer = createApp(t, `{"label_UID":"`+bigLabel.UID.String()+`"}`)
+		failClient = createApp(t, `{"label_UID":"`+label.UID.String()+`", "depends_on":["`+bigServer.UID.String()+`"]}`)
+
+		time.Sleep(15 * time.Second)
+		if s := getState(t, failClient); s.Status != types.ApplicationStatusNEW {
+			t.Fatalf("Application Status is incorrect: %v", s.Status)
+		}
+	})
+
+	t.Run("Client fails when the server is deallocated", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+bigServer.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		h.Retry(&h.Timer{Timeout: 15 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if s := getState(r, failClient); s.Status != types.ApplicationStatusERROR {
+				r.Fatalf("Application Status is incorrect: %v", s.Status)
+			}
+		})
+	})
+
+	var server, client types.Application
+	t.Run("Client is allocated after the server", func(t *testing.T) {
+		server = createApp(t, `{"label_UID":"`+label.UID.String()+`"}`)
+		client = createApp(t, `{"label_UID":"`+label.UID.String()+`", "depends_on":["`+server.UID.String()+`"], "depends_inject":true}`)
+
+		h.Retry(&h.Timer{Timeout: 30 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if s := getState(r, client); s.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", s.Status)
+			}
+		})
+		if s := getState(t, server); s.Status != types.ApplicationStatusALLOCATED {
+			t.Fatalf("Server Application Status is incorrect: %v", s.Status)
+		}
+	})
+
+	t.Run("Client Resource has the server address", func(t *testing.T) {
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+client.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		var metadata map[string]any
+		if err := json.Unmarshal([]byte(res.Metadata), &metadata); err != nil {
+			t.Fatalf("Unable to parse the Resource metadata: %v", err)
+		}
+		if metadata["FISH_DEPENDENCY_0_IP"] != "127.0.0.1" || metadata["FISH_DEPENDENCY_0_ID"] != server.ShortId {
+			t.Fatalf("Resource metadata has no dependency address: %v", metadata)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Resource lifetime extension by request:
+// * Label without max_extension doesn't allow to extend
+// * Extension can't be more than max_extension and can't exceed max_lifetime
+// * Extended Resource is not deallocated by the Label lifetime
+// * Other users can't extend the Application lifetime
+func Test_application_extend(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	// Creates the Label and waits for the Application to be allocated
+	allocate := func(t *testing.T, name, resources string) (app types.Application) {
+		var label types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"`+name+`", "version":1, "definitions": [{"driver":"test", "resources":`+resources+`}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+		return app
+	}
+
+	extend := func(t *testing.T, app types.Application, duration string, status int) (state types.ApplicationState) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/extend")).
+			JSON(`{"duration":"`+duration+`", "reason":"test"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(status).
+			End().
+			JSON(&state)
+		return state
+	}
+
+	t.Run("Label without max extension doesn't allow to extend", func(t *testing.T) {
+		app := allocate(t, "no-extend", `{"cpu":1,"ram":2,"lifetime":"1h"}`)
+		extend(t, app, "1h", http.StatusBadRequest)
+	})
+
+	t.Run("Extension is limited by the Label policy", func(t *testing.T) {
+		app := allocate(t, "policy", `{"cpu":1,"ram":2,"lifetime":"1h","max_extension":"2h","max_lifetime":"4h"}`)
+
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		extend(t, app, "3h", http.StatusBadRequest)
+		state := extend(t, app, "2h", http.StatusOK)
+		if state.Status != types.ApplicationStatusALLOCATED || state.ExpiresAt == nil ||
+			!state.ExpiresAt.Equal(res.CreatedAt.Add(3*time.Hour)) {
+			t.Fatalf("Extended state is incorrect: %v", state)
+		}
+		extend(t, app, "2h", http.StatusBadRequest)
+		state = extend(t, app, "1h", http.StatusOK)
+		if state.ExpiresAt == nil || !state.ExpiresAt.Equal(res.CreatedAt.Add(4*time.Hour)) {
+			t.Fatalf("Extended state is incorrect: %v", state)
+		}
+	})
+
+	t.Run("Extended Resource outlives the Label lifetime", func(t *testing.T) {
+		app := allocate(t, "short", `{"cpu":1,"ram":2,"lifetime":"20s","max_extension":"1h"}`)
+		extend(t, app, "1h", http.StatusOK)
+
+		time.Sleep(25 * time.Second)
+
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+		if appState.Status != types.ApplicationStatusALLOCATED {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Other user can't extend the Application lifetime", func(t *testing.T) {
+		app := allocate(t, "other", `{"cpu":1,"ram":2,"lifetime":"1h","max_extension":"1h"}`)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/extend")).
+			JSON(`{"duration":"1h"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
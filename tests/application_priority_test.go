@@ -0,0 +1,176 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the higher priority Application is allocated before the earlier requested one:
+// * User can't request priority higher than allowed
+// * First Application fills the node
+// * Low and high priority Applications are waiting
+// * Destroying first Application
+// * High priority Application is allocated, the low one is still waiting
+func Test_application_priority(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+priority:
+  user_max: 1
+
+drivers:
+  - name: test
+    cfg:
+      cpu_limit: 4
+      ram_limit: 8`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":4,"ram":8}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User can't request priority higher than allowed", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "priority": 5}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	createApp := func(t *testing.T, priority string) (app types.Application) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "priority": `+priority+`}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+		return app
+	}
+	appStatus := func(r apitest.TestingT, app types.Application) types.ApplicationStatus {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(r).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+		return appState.Status
+	}
+
+	var app1 types.Application
+	t.Run("Create Application 1", func(t *testing.T) {
+		app1 = createApp(t, "0")
+	})
+
+	t.Run("Application 1 should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if status := appStatus(r, app1); status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application 1 Status is incorrect: %v", status)
+			}
+		})
+	})
+
+	var appLow, appHigh types.Application
+	t.Run("Create low and high priority Applications", func(t *testing.T) {
+		appLow = createApp(t, "0")
+		appHigh = createApp(t, "10")
+		if appHigh.Priority != 10 {
+			t.Fatalf("Application priority is incorrect: %v", appHigh.Priority)
+		}
+	})
+
+	t.Run("Deallocate the Application 1", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app1.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("High priority Application should get ALLOCATED in 40 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 5 * time.Second}, t, func(r *h.R) {
+			if status := appStatus(r, appHigh); status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("High priority Application Status is incorrect: %v", status)
+			}
+		})
+	})
+
+	t.Run("Low priority Application should still be NEW", func(t *testing.T) {
+		if status := appStatus(t, appLow); status != types.ApplicationStatusNEW {
+			t.Fatalf("Low priority Application Status is incorrect: %v", status)
+		}
+	})
+}
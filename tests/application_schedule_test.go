@@ -0,0 +1,224 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Schedule creates the Applications on cron expression:
+// * Wrong cron expression is rejected
+// * Next runs preview follows the cron expression
+// * The Schedule creates the batch of Applications and records it in history
+// * Other user can't manage the Schedule
+// * Disabled Schedule does not create Applications
+func Test_application_schedule(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Schedule with wrong cron is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/schedule/")).
+			JSON(`{"name":"warmup", "enabled":true, "cron":"0 25 * * *", "label_UID":"`+label.UID.String()+`", "count":2}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var schedule types.Schedule
+	t.Run("Create Schedule", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/schedule/")).
+			JSON(`{"name":"warmup", "enabled":true, "cron":"* * * * *", "deallocate_cron":"0 20 * * 1-5", "label_UID":"`+label.UID.String()+`", "count":2}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&schedule)
+
+		if schedule.UID == uuid.Nil {
+			t.Fatalf("Schedule UID is incorrect: %v", schedule.UID)
+		}
+	})
+
+	t.Run("Next runs preview", func(t *testing.T) {
+		var next types.ScheduleNext
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/next")).
+			Query("count", "3").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&next)
+
+		if len(next.Allocate) != 3 || next.Allocate[1].Sub(next.Allocate[0]) != time.Minute {
+			t.Fatalf("Allocate runs are incorrect: %v", next.Allocate)
+		}
+		if len(next.Deallocate) != 3 || next.Deallocate[0].UTC().Hour() != 20 || next.Deallocate[0].UTC().Weekday() == time.Sunday {
+			t.Fatalf("Deallocate runs are incorrect: %v", next.Deallocate)
+		}
+	})
+
+	t.Run("Other user can't manage the Schedule", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/disable")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var history []types.ScheduleRun
+	t.Run("Schedule should create the Applications in 90 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 90 * time.Second, Wait: 5 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/history")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&history)
+
+			if len(history) == 0 {
+				r.Fatalf("Schedule was not executed yet")
+			}
+		})
+		if history[0].Action != "allocate" || history[0].Error != "" || history[0].Count != 2 {
+			t.Fatalf("Schedule run is incorrect: %v", history[0])
+		}
+	})
+
+	t.Run("Scheduled Applications should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var state types.ApplicationBatchState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/batch/"+history[0].BatchUID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&state)
+
+			if state.Counts[string(types.ApplicationStatusALLOCATED)] != 2 {
+				r.Fatalf("Batch Applications are not allocated: %v", state.Counts)
+			}
+		})
+	})
+
+	t.Run("Disable Schedule", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/disable")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&schedule)
+
+		if schedule.Enabled {
+			t.Fatalf("Schedule is still enabled")
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/history")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&history)
+	})
+
+	t.Run("Disabled Schedule is not executed", func(t *testing.T) {
+		// Waiting for the next minute run to pass
+		time.Sleep(70 * time.Second)
+
+		var after []types.ScheduleRun
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/schedule/"+schedule.UID.String()+"/history")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&after)
+
+		if len(after) != len(history) {
+			t.Fatalf("Disabled Schedule was executed: %v", after)
+		}
+	})
+}
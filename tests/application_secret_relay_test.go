@@ -0,0 +1,221 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the secrets relay end-to-end:
+// * Resource registers the public key through Meta API only once
+// * User sends the secret encrypted with the Resource key
+// * Resource receives and decrypts the secret only once
+func Test_application_secret_relay(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Secret can't be sent before the key is registered", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/secret/")).
+			JSON(map[string]any{"name": "token", "ciphertext": "c2VjcmV0"}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	pubKey, privKey, err := crypt.SecretKeyGenerate()
+	if err != nil {
+		t.Fatalf("Unable to generate secret key: %v", err)
+	}
+
+	// The test driver Resource has localhost address, so the test acts as the Resource
+	t.Run("Resource registers the secret key", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("meta/v1/secret/key")).
+			JSON(map[string]any{"public_key": pubKey}).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Resource secret key can't be replaced", func(t *testing.T) {
+		anotherKey, _, _ := crypt.SecretKeyGenerate()
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("meta/v1/secret/key")).
+			JSON(map[string]any{"public_key": anotherKey}).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("User sends the encrypted secret", func(t *testing.T) {
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if res.SecretKey != pubKey {
+			t.Fatalf("Resource secret key is incorrect: %q != %q", res.SecretKey, pubKey)
+		}
+		ciphertext, err := crypt.SecretSeal(res.SecretKey, []byte("test-secret-value"))
+		if err != nil {
+			t.Fatalf("Unable to encrypt the secret: %v", err)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/secret/")).
+			JSON(map[string]any{"name": "token", "ciphertext": ciphertext}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Resource receives the secret", func(t *testing.T) {
+		var secrets []types.ApplicationSecret
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/secret/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&secrets)
+
+		if len(secrets) != 1 || secrets[0].Name != "token" {
+			t.Fatalf("Expected one secret with name token: %v", secrets)
+		}
+		data, err := crypt.SecretOpen(pubKey, privKey, secrets[0].Ciphertext)
+		if err != nil {
+			t.Fatalf("Unable to decrypt the secret: %v", err)
+		}
+		if string(data) != "test-secret-value" {
+			t.Fatalf("Decrypted secret is incorrect: %q", data)
+		}
+	})
+
+	t.Run("Secret is received only once", func(t *testing.T) {
+		var secrets []types.ApplicationSecret
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/secret/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&secrets)
+
+		if len(secrets) != 0 {
+			t.Fatalf("Secret should be removed after the first receive: %v", secrets)
+		}
+	})
+
+	t.Run("Deallocate the Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
@@ -0,0 +1,166 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Ensure the Application seed makes the test driver behavior reproducible:
+// * Application without seed gets the random one
+// * Seed is passed to the Resource metadata
+// * Applications with the same seed are getting the same Resource identifier
+func Test_application_seed(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	// allocate creates the Application with seed and returns the allocated Resource
+	allocate := func(t *testing.T, seed int64) (types.Application, types.Resource) {
+		t.Helper()
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(map[string]any{"label_UID": label.UID, "seed": seed}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+
+		var res types.Resource
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&res)
+
+			if res.Identifier == "" {
+				r.Fatalf("Resource is not allocated")
+			}
+		})
+		return app, res
+	}
+
+	// deallocate destroys the Application Resource and waits for it
+	deallocate := func(t *testing.T, app types.Application) {
+		t.Helper()
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var state types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&state)
+
+			if state.Status != types.ApplicationStatusDEALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", state.Status)
+			}
+		})
+	}
+
+	t.Run("Application without seed gets random one", func(t *testing.T) {
+		app, _ := allocate(t, 0)
+		if app.Seed == 0 {
+			t.Fatalf("Application seed is not generated")
+		}
+		deallocate(t, app)
+	})
+
+	var res1 types.Resource
+	t.Run("Seed is passed to the Resource metadata", func(t *testing.T) {
+		var app types.Application
+		app, res1 = allocate(t, 12345)
+		if app.Seed != 12345 {
+			t.Fatalf("Application seed is incorrect: %v", app.Seed)
+		}
+		if !strings.Contains(string(res1.Metadata), `"FISH_SEED":"12345"`) {
+			t.Fatalf("Resource metadata has no seed: %s", res1.Metadata)
+		}
+
+		deallocate(t, app)
+	})
+
+	t.Run("Same seed gives the same Resource", func(t *testing.T) {
+		app, res2 := allocate(t, 12345)
+		if res2.Identifier != res1.Identifier {
+			t.Fatalf("Resource identifiers with the same seed are different: %s != %s", res1.Identifier, res2.Identifier)
+		}
+		deallocate(t, app)
+	})
+}
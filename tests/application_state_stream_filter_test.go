@@ -0,0 +1,181 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Makes sure the Application state stream sends only the events matching the filters:
+// * Create 2 Applications
+// * Subscribe to the stream of the first Application with DEALLOCATE and DEALLOCATED statuses
+// * Deallocate both Applications
+// * Check only the filtered statuses of the first Application were received
+func Test_application_state_stream_filter(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	apps := make([]types.Application, 2)
+	t.Run("Create Applications", func(t *testing.T) {
+		for i := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&apps[i])
+
+			if apps[i].UID == uuid.Nil {
+				t.Fatalf("Application UID is incorrect: %v", apps[i].UID)
+			}
+		}
+	})
+
+	// The stream connection is kept open, so it's not limited by client timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, afi.APIAddress("api/v1/application/stream"), http.NoBody)
+	query := req.URL.Query()
+	query.Add("application_uid", apps[0].UID.String())
+	query.Add("status", string(types.ApplicationStatusDEALLOCATE))
+	query.Add("status", string(types.ApplicationStatusDEALLOCATED))
+	req.URL.RawQuery = query.Encode()
+	req.SetBasicAuth("admin", afi.AdminToken())
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		t.Fatalf("Unable to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected stream status: %d", resp.StatusCode)
+	}
+
+	events := make(chan types.ApplicationStateEvent, 100)
+	go func() {
+		defer close(events)
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var event types.ApplicationStateEvent
+			if dec.Decode(&event) != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+
+	t.Run("Applications should get ALLOCATED in 10 sec", func(t *testing.T) {
+		for _, app := range apps {
+			h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+				var appState types.ApplicationState
+				apitest.New().
+					EnableNetworking(cli).
+					Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+					BasicAuth("admin", afi.AdminToken()).
+					Expect(r).
+					Status(http.StatusOK).
+					End().
+					JSON(&appState)
+
+				if appState.Status != types.ApplicationStatusALLOCATED {
+					r.Fatalf("Application Status is incorrect: %v", appState.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("Deallocate the Applications", func(t *testing.T) {
+		// The second one first to make sure its events are skipped before the expected ones
+		for i := len(apps) - 1; i >= 0; i-- {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+apps[i].UID.String()+"/deallocate")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Stream should deliver only the filtered events", func(t *testing.T) {
+		var received []types.ApplicationStatus
+		timeout := time.After(20 * time.Second)
+		for len(received) == 0 || received[len(received)-1] != types.ApplicationStatusDEALLOCATED {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					t.Fatalf("Stream was closed")
+				}
+				if event.State.ApplicationUID != apps[0].UID {
+					t.Errorf("Received event of the not filtered Application: %s", event.State.ApplicationUID)
+				}
+				received = append(received, event.State.Status)
+			case <-timeout:
+				t.Fatalf("Timeout waiting for the states, received: %v", received)
+			}
+		}
+
+		if len(received) != 2 || received[0] != types.ApplicationStatusDEALLOCATE {
+			t.Errorf("Received statuses are incorrect: %v", received)
+		}
+	})
+}
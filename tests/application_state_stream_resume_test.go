@@ -0,0 +1,200 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Makes sure the Application state stream could be resumed from the last received event:
+// * Subscribe to the stream and wait for the Application to become ALLOCATED
+// * Close the stream and deallocate the Application
+// * Resume the stream and check the missed states are coming first
+// * Check the stream could not be resumed from the too old or future event
+func Test_application_state_stream_resume(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	// Opens the stream and returns the channel of the received events
+	openStream := func(t *testing.T, query string) (chan types.ApplicationStateEvent, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, afi.APIAddress("api/v1/application/stream"+query), http.NoBody)
+		req.SetBasicAuth("admin", afi.AdminToken())
+		resp, err := (&http.Client{Transport: tr}).Do(req)
+		if err != nil {
+			cancel()
+			t.Fatalf("Unable to open the stream: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			cancel()
+			t.Fatalf("Unexpected stream status: %d", resp.StatusCode)
+		}
+
+		events := make(chan types.ApplicationStateEvent, 100)
+		go func() {
+			defer close(events)
+			defer resp.Body.Close()
+			dec := json.NewDecoder(resp.Body)
+			for {
+				var event types.ApplicationStateEvent
+				if dec.Decode(&event) != nil {
+					return
+				}
+				events <- event
+			}
+		}()
+		return events, cancel
+	}
+
+	// Waits for the Application state event with the required status
+	waitStatus := func(t *testing.T, events chan types.ApplicationStateEvent, status types.ApplicationStatus) types.ApplicationStateEvent {
+		timeout := time.After(20 * time.Second)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					t.Fatalf("Stream was closed")
+				}
+				if event.State.Status == status {
+					return event
+				}
+			case <-timeout:
+				t.Fatalf("Timeout waiting for the status %s", status)
+			}
+		}
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	events, cancel := openStream(t, "")
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	var lastSeq uint64
+	t.Run("Stream should deliver ALLOCATED state", func(t *testing.T) {
+		lastSeq = waitStatus(t, events, types.ApplicationStatusALLOCATED).Seq
+		cancel()
+	})
+
+	t.Run("Deallocate the Application while disconnected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusDEALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Resumed stream should deliver the missed states", func(t *testing.T) {
+		events, cancel := openStream(t, fmt.Sprintf("?resume_from=%d", lastSeq))
+		defer cancel()
+
+		event := waitStatus(t, events, types.ApplicationStatusDEALLOCATE)
+		if event.Seq <= lastSeq {
+			t.Errorf("Resumed event seq is not increasing: %d after %d", event.Seq, lastSeq)
+		}
+		waitStatus(t, events, types.ApplicationStatusDEALLOCATED)
+	})
+
+	t.Run("Stream could not be resumed from unknown events", func(t *testing.T) {
+		for _, seq := range []uint64{1, lastSeq + 1000000} {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/stream")).
+				Query("resume_from", fmt.Sprintf("%d", seq)).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusGone).
+				End()
+		}
+	})
+}
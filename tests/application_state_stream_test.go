@@ -0,0 +1,202 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Makes sure the Application state stream keeps the order of the states of each Application:
+// * Subscribe to the stream
+// * Allocate and deallocate a few Applications in parallel
+// * Check the states of every Application are coming in lifecycle order without gaps
+func Test_application_state_stream(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	// The stream connection is kept open, so it's not limited by client timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, afi.APIAddress("api/v1/application/stream"), http.NoBody)
+	req.SetBasicAuth("admin", afi.AdminToken())
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		t.Fatalf("Unable to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected stream status: %d", resp.StatusCode)
+	}
+
+	events := make(chan types.ApplicationStateEvent, 100)
+	go func() {
+		defer close(events)
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var event types.ApplicationStateEvent
+			if dec.Decode(&event) != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	apps := make([]types.Application, 3)
+	t.Run("Create Applications", func(t *testing.T) {
+		for i := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&apps[i])
+
+			if apps[i].UID == uuid.Nil {
+				t.Fatalf("Application UID is incorrect: %v", apps[i].UID)
+			}
+		}
+	})
+
+	t.Run("Applications should get ALLOCATED in 10 sec", func(t *testing.T) {
+		for _, app := range apps {
+			h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+				var appState types.ApplicationState
+				apitest.New().
+					EnableNetworking(cli).
+					Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+					BasicAuth("admin", afi.AdminToken()).
+					Expect(r).
+					Status(http.StatusOK).
+					End().
+					JSON(&appState)
+
+				if appState.Status != types.ApplicationStatusALLOCATED {
+					r.Fatalf("Application Status is incorrect: %v", appState.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("Deallocate the Applications", func(t *testing.T) {
+		for _, app := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Stream should deliver the states in order", func(t *testing.T) {
+		order := map[types.ApplicationStatus]int{
+			types.ApplicationStatusNEW:         0,
+			types.ApplicationStatusELECTED:     1,
+			types.ApplicationStatusALLOCATED:   2,
+			types.ApplicationStatusDEALLOCATE:  3,
+			types.ApplicationStatusDEALLOCATED: 4,
+		}
+		lastSeq := map[types.ApplicationUID]uint64{}
+		lastStatus := map[types.ApplicationUID]types.ApplicationStatus{}
+		var seq uint64
+		timeout := time.After(20 * time.Second)
+		for done := 0; done < len(apps); {
+			var event types.ApplicationStateEvent
+			select {
+			case e, ok := <-events:
+				if !ok {
+					t.Fatalf("Stream was closed")
+				}
+				event = e
+			case <-timeout:
+				t.Fatalf("Timeout waiting for the states, received: %v", lastStatus)
+			}
+
+			if event.Seq <= seq {
+				t.Errorf("Event seq is not increasing: %d after %d", event.Seq, seq)
+			}
+			seq = event.Seq
+
+			uid := event.State.ApplicationUID
+			if event.ObjectSeq != lastSeq[uid]+1 {
+				t.Errorf("Application %s object_seq has gap: %d after %d", uid, event.ObjectSeq, lastSeq[uid])
+			}
+			lastSeq[uid] = event.ObjectSeq
+
+			if prev, ok := lastStatus[uid]; ok && order[event.State.Status] <= order[prev] {
+				t.Errorf("Application %s state %s came after %s", uid, event.State.Status, prev)
+			}
+			lastStatus[uid] = event.State.Status
+			if event.State.Status == types.ApplicationStatusDEALLOCATED {
+				done++
+			}
+		}
+
+		for _, app := range apps {
+			if lastSeq[app.UID] != uint64(len(order)) {
+				t.Errorf("Application %s should have %d states, got: %d", app.UID, len(order), lastSeq[app.UID])
+			}
+		}
+	})
+}
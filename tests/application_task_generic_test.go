@@ -0,0 +1,173 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the generic ApplicationTasks are discoverable and executed:
+// * Driver capabilities and the Resource list the generic tasks the driver implements
+// * Generic task not implemented by the Resource driver is rejected
+// * Suspend and resume tasks are executed with the generic result
+func Test_application_task_generic(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	expected := []string{"snapshot", "suspend", "resume", "reboot"}
+
+	t.Run("Driver capabilities list the tasks", func(t *testing.T) {
+		var caps types.DriverCapabilities
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/driver/capabilities")).
+			Query("name", "test").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&caps)
+
+		if !slices.Equal(caps.Tasks, expected) {
+			t.Fatalf("Wrong test driver tasks: %v", caps.Tasks)
+		}
+	})
+
+	var app types.Application
+	t.Run("Allocate Application", func(t *testing.T) {
+		var label types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Resource lists the tasks", func(t *testing.T) {
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if !slices.Equal(res.Tasks, expected) {
+			t.Fatalf("Wrong Resource tasks: %v", res.Tasks)
+		}
+	})
+
+	t.Run("Not implemented generic task is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+			JSON(map[string]any{"task": "image", "when": types.ApplicationStatusALLOCATED}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	for _, task := range []struct{ name, result string }{
+		{"suspend", `{"status":"suspended"}`},
+		{"resume", `{"status":"running"}`},
+	} {
+		t.Run("Execute "+task.name, func(t *testing.T) {
+			var appTask types.ApplicationTask
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+				JSON(map[string]any{"task": task.name, "when": types.ApplicationStatusALLOCATED}).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&appTask)
+
+			h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+				apitest.New().
+					EnableNetworking(cli).
+					Get(afi.APIAddress("api/v1/task/"+appTask.UID.String())).
+					BasicAuth("admin", afi.AdminToken()).
+					Expect(r).
+					Status(http.StatusOK).
+					End().
+					JSON(&appTask)
+
+				if string(appTask.Result) != task.result {
+					r.Fatalf("ApplicationTask result is incorrect: %v", appTask.Result)
+				}
+			})
+		})
+	}
+}
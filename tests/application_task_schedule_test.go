@@ -0,0 +1,170 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the scheduled ApplicationTasks:
+// * Wrong interval or interval for not ALLOCATED task is rejected
+// * Task with `at` is not executed before the time
+// * Periodic task gets the next execution time after the run
+func Test_application_task_schedule(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var app types.Application
+	t.Run("Allocate Application", func(t *testing.T) {
+		var label types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Wrong schedule is rejected", func(t *testing.T) {
+		for _, body := range []map[string]any{
+			{"task": "snapshot", "when": types.ApplicationStatusALLOCATED, "interval": "10s"},
+			{"task": "snapshot", "when": types.ApplicationStatusDEALLOCATE, "interval": "1h"},
+			{"task": "snapshot", "when": types.ApplicationStatusALLOCATED, "retries": 100},
+		} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+				JSON(body).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusBadRequest).
+				End()
+		}
+	})
+
+	getTask := func(t apitest.TestingT, uid string) (task types.ApplicationTask) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/task/"+uid)).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&task)
+		return task
+	}
+
+	t.Run("Task is executed not earlier than at", func(t *testing.T) {
+		at := time.Now().Add(15 * time.Second)
+		var task types.ApplicationTask
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+			JSON(map[string]any{"task": "snapshot", "when": types.ApplicationStatusALLOCATED, "at": at}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&task)
+
+		time.Sleep(7 * time.Second)
+		if res := getTask(t, task.UID.String()); string(res.Result) != "{}" {
+			t.Fatalf("ApplicationTask executed before the time: %v", res.Result)
+		}
+
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if res := getTask(r, task.UID.String()); string(res.Result) == "{}" {
+				r.Fatalf("ApplicationTask is not executed yet")
+			}
+		})
+	})
+
+	t.Run("Periodic task is scheduled for the next run", func(t *testing.T) {
+		var task types.ApplicationTask
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+			JSON(map[string]any{"task": "snapshot", "when": types.ApplicationStatusALLOCATED, "interval": "1h"}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&task)
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			res := getTask(r, task.UID.String())
+			if string(res.Result) == "{}" {
+				r.Fatalf("ApplicationTask is not executed yet")
+			}
+			if res.At == nil || time.Until(*res.At) < 50*time.Minute {
+				r.Fatalf("ApplicationTask next run is incorrect: %v", res.At)
+			}
+		})
+	})
+}
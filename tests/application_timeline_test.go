@@ -0,0 +1,226 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the task progress is streamed and the Application timeline shows states and tasks:
+// * User opens the state stream and creates the snapshot task on allocated Application
+// * Task creation, output and completion are received by the stream
+// * Timeline contains the states and the completed task with durations
+// * Other users can't get the Application timeline
+func Test_application_timeline(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	for _, name := range []string{"test-user", "test-user2"} {
+		t.Run("Create User "+name, func(t *testing.T) {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"test-user-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		})
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, afi.APIAddress("api/v1/application/stream"), http.NoBody)
+	req.SetBasicAuth("test-user", "test-user-password")
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		t.Fatalf("Unable to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	events := make(chan types.ApplicationStateEvent, 100)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var event types.ApplicationStateEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil && event.Task != nil {
+				events <- event
+			}
+		}
+	}()
+
+	var task types.ApplicationTask
+	t.Run("Create snapshot ApplicationTask on ALLOCATED", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+			JSON(map[string]any{"task": "snapshot", "when": types.ApplicationStatusALLOCATED}).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&task)
+
+		if task.UID == uuid.Nil {
+			t.Fatalf("ApplicationTask UID is incorrect: %v", task.UID)
+		}
+	})
+
+	t.Run("Task creation, output and result are streamed in 10 sec", func(t *testing.T) {
+		var created, output, done bool
+		timeout := time.After(10 * time.Second)
+		for !done {
+			select {
+			case event := <-events:
+				if event.Task.UID != task.UID || event.State.Status != types.ApplicationStatusALLOCATED {
+					t.Fatalf("Task event is incorrect: %v", event)
+				}
+				switch {
+				case event.TaskOutput != nil && *event.TaskOutput != "":
+					if !strings.HasPrefix(*event.TaskOutput, "Created snapshot") {
+						t.Fatalf("Task output is incorrect: %q", *event.TaskOutput)
+					}
+					output = true
+				case event.Task.Result == "{}":
+					created = true
+				default:
+					done = true
+				}
+			case <-timeout:
+				t.Fatalf("Task events were not received: created %v, output %v", created, output)
+			}
+		}
+		if !created || !output {
+			t.Fatalf("Task events are incorrect: created %v, output %v", created, output)
+		}
+	})
+
+	t.Run("Timeline contains the states and the task", func(t *testing.T) {
+		var timeline []types.ApplicationTimelineItem
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/timeline")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&timeline)
+
+		var names []string
+		for _, item := range timeline {
+			names = append(names, item.Kind+":"+item.Name)
+			if item.Duration < 0 {
+				t.Errorf("Timeline item duration is incorrect: %v", item)
+			}
+			if item.Kind == "task" && item.FinishedAt == nil {
+				t.Errorf("Task should be finished: %v", item)
+			}
+		}
+		if strings.Join(names, ",") != "state:NEW,state:ELECTED,state:ALLOCATED,task:snapshot" {
+			t.Fatalf("Timeline is incorrect: %v", names)
+		}
+		if timeline[2].FinishedAt != nil {
+			t.Fatalf("Current state should not be finished: %v", timeline[2])
+		}
+	})
+
+	t.Run("Other user can't get the Application timeline", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/timeline")).
+			BasicAuth("test-user2", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,176 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the bootstrap secrets vault:
+// * Application metadata key listed in vault_metadata is removed during allocation
+// * Resource metadata doesn't contain it
+// * Resource receives it from the vault only once
+func Test_application_vault(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+vault_metadata:
+  - AGENT_TOKEN
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "metadata":{"AGENT_TOKEN":"test-agent-token", "AGENT_NAME":"test"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Application metadata should not contain the secret", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String())).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		var metadata map[string]any
+		json.Unmarshal([]byte(app.Metadata), &metadata)
+		if _, ok := metadata["AGENT_TOKEN"]; ok {
+			t.Fatalf("Application metadata still contains the secret: %s", app.Metadata)
+		}
+		if metadata["AGENT_NAME"] != "test" {
+			t.Fatalf("Application metadata lost the regular key: %s", app.Metadata)
+		}
+	})
+
+	// The test driver Resource has localhost address, so the test acts as the Resource
+	t.Run("Resource metadata should not contain the secret", func(t *testing.T) {
+		var metadata map[string]any
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/data/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&metadata)
+
+		if _, ok := metadata["AGENT_TOKEN"]; ok {
+			t.Fatalf("Resource metadata contains the secret: %v", metadata)
+		}
+	})
+
+	t.Run("Resource receives the secret from vault", func(t *testing.T) {
+		var vault map[string]any
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/vault/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&vault)
+
+		if vault["AGENT_TOKEN"] != "test-agent-token" {
+			t.Fatalf("Vault secret is incorrect: %v", vault)
+		}
+	})
+
+	t.Run("Secret is received only once", func(t *testing.T) {
+		var vault map[string]any
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/vault/")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&vault)
+
+		if len(vault) != 0 {
+			t.Fatalf("Vault should be empty: %v", vault)
+		}
+	})
+}
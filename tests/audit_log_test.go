@@ -0,0 +1,161 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the mutating requests are recorded in the audit log:
+// * Create User and Label
+// * Rejected Label create by User is recorded too
+// * User can't read the audit log without auditor role
+// * User with auditor role can read the audit log and the password hash is redacted
+func Test_audit_log(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User can't create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":2, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("User can't read audit log without role", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/audit/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Grant auditor role", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/grant/")).
+			JSON(map[string]any{"role": "auditor", "expires_at": time.Now().Add(time.Hour)}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Auditor can read the audit log", func(t *testing.T) {
+		var records []types.AuditRecord
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/audit/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&records)
+
+		if len(records) != 4 {
+			t.Fatalf("Wrong amount of audit records: %v", records)
+		}
+		if records[0].Action != "POST /api/v1/user/" || records[0].ObjectId != "test-user" || records[0].UserName != "admin" || records[0].AuthMethod != "basic" {
+			t.Fatalf("Wrong User create audit record: %+v", records[0])
+		}
+		var changes map[string]map[string]any
+		if err := json.Unmarshal([]byte(records[0].Changes), &changes); err != nil {
+			t.Fatalf("Unable to parse audit changes: %v", err)
+		}
+		if changes["hash"]["after"] != "<redacted>" || changes["name"]["after"] != "test-user" {
+			t.Fatalf("Wrong User create audit changes: %v", changes)
+		}
+		if records[1].ObjectType != "Label" || records[1].Status != http.StatusOK {
+			t.Fatalf("Wrong Label create audit record: %+v", records[1])
+		}
+		if records[2].UserName != "test-user" || records[2].ObjectType != "" || records[2].Status != http.StatusBadRequest {
+			t.Fatalf("Wrong rejected Label create audit record: %+v", records[2])
+		}
+		if records[3].ObjectType != "RoleGrant" {
+			t.Fatalf("Wrong RoleGrant audit record: %+v", records[3])
+		}
+	})
+
+	t.Run("Auditor can filter the audit log", func(t *testing.T) {
+		var records []types.AuditRecord
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/audit/")).
+			Query("filter", "user_name = 'test-user'").
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&records)
+
+		if len(records) != 1 || records[0].Status != http.StatusBadRequest {
+			t.Fatalf("Wrong filtered audit records: %v", records)
+		}
+	})
+}
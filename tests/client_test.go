@@ -0,0 +1,179 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adobe/aquarium-fish/lib/client"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the fishctl API client is able to run the basic user workflow:
+// * Wrong credentials are reported as API error
+// * Labels are created and the latest version is resolved by name
+// * Application is created, awaited, accessed and deallocated
+// * Application state is awaited by the stream
+// * Not found object is reported by the typed error
+// * Nodes are listed
+func Test_client_workflow(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	newClient := func(t *testing.T, password string) *client.Client {
+		cli, err := client.New(client.Config{URL: "https://" + afi.APIEndpoint(), Username: "admin", Password: password, Insecure: true})
+		if err != nil {
+			t.Fatalf("Unable to create client: %v", err)
+		}
+		return cli
+	}
+
+	t.Run("Wrong credentials are reported", func(t *testing.T) {
+		_, err := newClient(t, "wrong").UserMe()
+		var apiErr *client.Error
+		if !errors.As(err, &apiErr) || apiErr.Status != 401 {
+			t.Fatalf("Expected unauthorized API error, got: %v", err)
+		}
+	})
+
+	cli := newClient(t, afi.AdminToken())
+
+	t.Run("Login as admin", func(t *testing.T) {
+		user, err := cli.UserMe()
+		if err != nil || user.Name != "admin" {
+			t.Fatalf("Unable to get the current user %v: %v", user, err)
+		}
+	})
+
+	t.Run("Create Label versions", func(t *testing.T) {
+		for _, version := range []int{1, 2} {
+			label := &types.Label{
+				Name:        "test-label",
+				Version:     version,
+				Definitions: types.LabelDefinitions{{Driver: "test", Resources: types.Resources{Cpu: 1, Ram: 2}}},
+			}
+			if _, err := cli.LabelCreate(label); err != nil {
+				t.Fatalf("Unable to create Label version %d: %v", version, err)
+			}
+		}
+	})
+
+	var label *types.Label
+	t.Run("Latest Label version is resolved", func(t *testing.T) {
+		var err error
+		if label, err = cli.LabelGetLatest("test-label"); err != nil || label.Version != 2 {
+			t.Fatalf("Wrong Label resolved %v: %v", label, err)
+		}
+		if _, err = cli.LabelGetLatest("test-label' OR '1'='1"); err == nil {
+			t.Fatalf("Label should not be found by the injected name")
+		}
+	})
+
+	var app *types.Application
+	t.Run("Create Application and wait for allocation", func(t *testing.T) {
+		var err error
+		if app, err = cli.ApplicationCreate(&types.Application{LabelUID: label.UID}); err != nil {
+			t.Fatalf("Unable to create Application: %v", err)
+		}
+		state, err := cli.ApplicationAwait(app.UID, []types.ApplicationStatus{types.ApplicationStatusALLOCATED}, 10*time.Second, time.Second)
+		if err != nil {
+			t.Fatalf("Application was not allocated: %v", err)
+		}
+		if state.Status != types.ApplicationStatusALLOCATED {
+			t.Fatalf("Application Status is incorrect: %v", state.Status)
+		}
+	})
+
+	t.Run("Get Resource access", func(t *testing.T) {
+		res, err := cli.ApplicationResourceGet(app.UID)
+		if err != nil {
+			t.Fatalf("Unable to get Resource: %v", err)
+		}
+		acc, err := cli.ResourceAccess(res.UID, "")
+		if err != nil {
+			t.Fatalf("Unable to get Resource access: %v", err)
+		}
+		if acc.Username == "" || acc.Key == "" || acc.HostKeyFingerprint == "" {
+			t.Fatalf("Resource access is incorrect: %v", acc)
+		}
+	})
+
+	t.Run("Wait for Application by the stream", func(t *testing.T) {
+		app2, err := cli.ApplicationCreate(&types.Application{LabelUID: label.UID})
+		if err != nil {
+			t.Fatalf("Unable to create Application: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		state, err := cli.ApplicationWait(ctx, app2.UID, []types.ApplicationStatus{types.ApplicationStatusALLOCATED})
+		if err != nil {
+			t.Fatalf("Application was not allocated: %v", err)
+		}
+		if state.Status != types.ApplicationStatusALLOCATED || state.ApplicationUID != app2.UID {
+			t.Fatalf("Application state is incorrect: %v", state)
+		}
+
+		if _, err = cli.ApplicationDeallocate(app2.UID); err != nil {
+			t.Fatalf("Unable to deallocate Application: %v", err)
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err = cli.ApplicationWait(ctx, app2.UID, []types.ApplicationStatus{types.ApplicationStatusDEALLOCATED}); err != nil {
+			t.Fatalf("Application was not deallocated: %v", err)
+		}
+		// The completed Application will not get the other status anymore
+		if _, err = cli.ApplicationWait(ctx, app2.UID, []types.ApplicationStatus{types.ApplicationStatusALLOCATED}); err == nil {
+			t.Fatalf("Deallocated Application should not be awaited")
+		}
+	})
+
+	t.Run("Not found is reported by typed error", func(t *testing.T) {
+		_, err := cli.ApplicationStateGet(uuid.New())
+		if !client.IsNotFound(err) {
+			t.Fatalf("Expected not found API error, got: %v", err)
+		}
+	})
+
+	t.Run("List Nodes", func(t *testing.T) {
+		nodes, err := cli.NodeList()
+		if err != nil || len(nodes) != 1 || nodes[0].Name != "node-1" {
+			t.Fatalf("Nodes list is incorrect %v: %v", nodes, err)
+		}
+	})
+
+	t.Run("Deallocate Application", func(t *testing.T) {
+		if _, err := cli.ApplicationDeallocate(app.UID); err != nil {
+			t.Fatalf("Unable to deallocate Application: %v", err)
+		}
+		if _, err := cli.ApplicationAwait(app.UID, []types.ApplicationStatus{types.ApplicationStatusDEALLOCATED}, 10*time.Second, time.Second); err != nil {
+			t.Fatalf("Application was not deallocated: %v", err)
+		}
+	})
+}
@@ -0,0 +1,233 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the rolling Upgrade of the cluster:
+// * Upgrade with wrong parameters is rejected
+// * Upgrade drains and shuts down the Node
+// * Node restarted with the old version fails the Upgrade
+// * Failed Upgrade could be aborted
+// * Upgrade to the version the Node already runs is completed without restart
+func Test_cluster_upgrade(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var node types.Node
+	t.Run("Get this Node version", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&node)
+
+		if node.Version == "" {
+			t.Fatalf("Node version is empty")
+		}
+	})
+
+	t.Run("Upgrade of unknown Node is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/upgrade/")).
+			JSON(`{"version":"v99.0.0", "nodes":["node-2"]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Upgrade with wrong max failure rate is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/upgrade/")).
+			JSON(`{"version":"v99.0.0", "max_failure_rate":1.5}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var upgrade types.Upgrade
+	t.Run("Start the Upgrade", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/upgrade/")).
+			JSON(`{"version":"v99.0.0", "drain_timeout":"10s", "health_timeout":"20s"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&upgrade)
+
+		if upgrade.UID == uuid.Nil {
+			t.Fatalf("Upgrade UID is incorrect: %v", upgrade.UID)
+		}
+		if upgrade.Status != "RUNNING" || len(upgrade.Nodes) != 1 || upgrade.Nodes[0] != "node-1" {
+			t.Fatalf("Upgrade is incorrect: %v, %v", upgrade.Status, upgrade.Nodes)
+		}
+		if upgrade.Parallel != 1 {
+			t.Fatalf("Upgrade parallel should be 1 by default: %v", upgrade.Parallel)
+		}
+	})
+
+	t.Run("Second Upgrade is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/upgrade/")).
+			JSON(`{"version":"v99.0.0"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Fish should shutdown in 30 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 30 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if afi.IsRunning() {
+				r.Fatalf("Fish is still running, but should be stopped already")
+			}
+		})
+	})
+
+	t.Run("Start Fish with the same version", func(t *testing.T) {
+		afi.Start(t)
+	})
+
+	t.Run("Upgrade should get FAILED in 40 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/upgrade/"+upgrade.UID.String())).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&upgrade)
+
+			if upgrade.Status != "FAILED" {
+				r.Fatalf("Upgrade Status is incorrect: %v", upgrade.Status)
+			}
+		})
+		if !strings.Contains(upgrade.Error, "v99.0.0") {
+			t.Fatalf("Upgrade error is incorrect: %v", upgrade.Error)
+		}
+	})
+
+	t.Run("Restarted Node is not in maintenance", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&node)
+
+		if node.Maintenance || node.Shutdown {
+			t.Fatalf("Node should be active: %v, %v", node.Maintenance, node.Shutdown)
+		}
+	})
+
+	t.Run("Abort the failed Upgrade", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/upgrade/"+upgrade.UID.String()+"/abort")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&upgrade)
+
+		if upgrade.Status != "ABORTED" {
+			t.Fatalf("Upgrade Status is incorrect: %v", upgrade.Status)
+		}
+	})
+
+	t.Run("Aborted Upgrade can't be resumed", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/upgrade/"+upgrade.UID.String()+"/resume")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Upgrade to the current version", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/upgrade/")).
+			JSON(`{"version":"`+node.Version+`", "nodes":["node-1"]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&upgrade)
+	})
+
+	t.Run("Upgrade should get COMPLETED in 30 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 30 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/upgrade/"+upgrade.UID.String())).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&upgrade)
+
+			if upgrade.Status != "COMPLETED" {
+				r.Fatalf("Upgrade Status is incorrect: %v", upgrade.Status)
+			}
+		})
+		if !afi.IsRunning() {
+			t.Fatalf("Fish should not be restarted")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Testing the secret references in the node config
+// * Node location is taken from env secret
+// * Node is started with the resolved value
+func Test_config_secrets(t *testing.T) {
+	t.Parallel()
+
+	// Env is inherited by the fish process, so using the unique variable name
+	os.Setenv("FISH_TEST_CONFIG_SECRETS_LOCATION", "secret_loc")
+
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: secret://env/FISH_TEST_CONFIG_SECRETS_LOCATION
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Node location is resolved", func(t *testing.T) {
+		var node types.Node
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&node)
+
+		if node.LocationName != "secret_loc" {
+			t.Fatalf("Node location is not resolved: %q", node.LocationName)
+		}
+	})
+}
@@ -0,0 +1,380 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Resources cost estimation and the budget cap:
+// * Label definitions are priced by the driver and by the static price table
+// * Allocated Resource is annotated with the cost
+// * Application over the User budget cap fails on allocation
+func Test_driver_pricing(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test/cloud
+    cfg:
+      cpu_price: 0.5
+  - name: test/onprem
+    pricing:
+      hour: 0.1
+      cpu: 0.25`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [
+				{"driver":"test/cloud", "resources":{"cpu":2,"ram":4}},
+				{"driver":"test/onprem", "resources":{"cpu":2,"ram":4}}
+			]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Label definitions are priced", func(t *testing.T) {
+		var compat []types.LabelCompatibility
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/label_compatibility")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&compat)
+
+		if len(compat) != 1 || len(compat[0].HourlyCosts) != 2 {
+			t.Fatalf("Wrong Label compatibility: %+v", compat)
+		}
+		if math.Abs(compat[0].HourlyCosts[0]-1.0) > 1e-9 || math.Abs(compat[0].HourlyCosts[1]-0.6) > 1e-9 {
+			t.Fatalf("Wrong definitions hourly costs: %v", compat[0].HourlyCosts)
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Admin sets the User budget", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/quota")).
+			JSON(`{"max_hourly_cost":1.5}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	apps := make([]types.Application, 2)
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps[0])
+
+		if apps[0].UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", apps[0].UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Resource is annotated with the cost", func(t *testing.T) {
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if res.HourlyCost != 1.0 {
+			t.Fatalf("Resource hourly cost is incorrect: %v", res.HourlyCost)
+		}
+	})
+
+	t.Run("User can see the cost in quota usage", func(t *testing.T) {
+		var usage types.QuotaUsage
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/test-user/quota")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&usage)
+
+		if usage.HourlyCost != 1.0 || usage.Quota.MaxHourlyCost != 1.5 {
+			t.Fatalf("Wrong quota usage: %+v", usage)
+		}
+	})
+
+	t.Run("Create Application over the budget", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps[1])
+	})
+
+	t.Run("Application over the budget should get ERROR in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+apps[1].UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusERROR {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+			if !strings.Contains(appState.Description, "Budget exceeded") {
+				r.Fatalf("Application error is incorrect: %v", appState.Description)
+			}
+		})
+	})
+}
+
+// Checks the pooled Resource taken by the Application over the budget releases the node usage once:
+// * Allocate Application on the regular Label to consume the node resources
+// * Allocate and deallocate Application on the recycled Label to put the Resource to the pool
+// * Application over the budget takes the pooled Resource and fails
+// * Node usage still counts the running Application
+func Test_driver_pricing_budget_recycled(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+    cfg:
+      cpu_price: 1`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	createLabel := func(t *testing.T, name, recycle string) (label types.Label) {
+		t.Helper()
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"`+name+`", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}`+recycle+`}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+		return label
+	}
+	createApp := func(t *testing.T, label types.Label, user, token string) (app types.Application) {
+		t.Helper()
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth(user, token).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+		return app
+	}
+	waitState := func(t *testing.T, app types.Application, status types.ApplicationStatus) (appState types.ApplicationState) {
+		t.Helper()
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != status {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+		return appState
+	}
+
+	var label, recycleLabel types.Label
+	t.Run("Create Labels", func(t *testing.T) {
+		label = createLabel(t, "test-label", "")
+		recycleLabel = createLabel(t, "test-recycle", `, "recycle":{"max_reuse":0, "max_age":"", "idle_timeout":"", "task":"", "options":{}}`)
+	})
+
+	t.Run("Create User with budget", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/quota")).
+			JSON(`{"max_hourly_cost":0.5}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Application consumes the node resources", func(t *testing.T) {
+		waitState(t, createApp(t, label, "admin", afi.AdminToken()), types.ApplicationStatusALLOCATED)
+	})
+
+	t.Run("Deallocated Resource is returned to the pool", func(t *testing.T) {
+		app := createApp(t, recycleLabel, "admin", afi.AdminToken())
+		waitState(t, app, types.ApplicationStatusALLOCATED)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		appState := waitState(t, app, types.ApplicationStatusDEALLOCATED)
+		if !strings.Contains(appState.Description, "recycle pool") {
+			t.Fatalf("Application Resource was not recycled: %v", appState.Description)
+		}
+	})
+
+	t.Run("Application over the budget takes the pooled Resource and fails", func(t *testing.T) {
+		appState := waitState(t, createApp(t, recycleLabel, "test-user", "test-user-password"), types.ApplicationStatusERROR)
+		if !strings.Contains(appState.Description, "Budget exceeded") {
+			t.Fatalf("Application error is incorrect: %v", appState.Description)
+		}
+	})
+
+	t.Run("Node usage counts only the running Application", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var capacity types.NodeCapacity
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/node/this/capacity")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&capacity)
+
+			if capacity.CpuUsed != 1 || capacity.RamUsed != 2 {
+				r.Fatalf("Node usage is incorrect: cpu %d, ram %d", capacity.CpuUsed, capacity.RamUsed)
+			}
+		})
+	})
+}
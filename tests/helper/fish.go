@@ -159,8 +159,24 @@ func (afi *AFInstance) Stop(tb testing.TB) {
 		time.Sleep(50 * time.Millisecond)
 	}
 
-	// Hard killing the process
+	// Hard killing the process and waiting for it to exit
 	afi.fishKill()
+	for afi.running {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Command runs the fish executable subcommand with the node config and returns the output
+func (afi *AFInstance) Command(tb testing.TB, args ...string) string {
+	tb.Helper()
+	cmdArgs := append(args, "-c", filepath.Join(afi.workspace, "config.yml"))
+	cmd := exec.Command(fishPath, cmdArgs...)
+	cmd.Dir = afi.workspace
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		tb.Fatalf("ERROR: Fish command %v failed: %v\n%s", args, err, out)
+	}
+	return string(out)
 }
 
 // Start the fish node executable
@@ -180,7 +196,8 @@ func (afi *AFInstance) Start(tb testing.TB, args ...string) {
 	r, _ := afi.cmd.StdoutPipe()
 	afi.cmd.Stderr = afi.cmd.Stdout
 
-	initDone := make(chan string)
+	// Buffered to not block the process wait on exit after init is done
+	initDone := make(chan string, 2)
 	scanner := bufio.NewScanner(r)
 	// TODO: Add timeout for waiting of API available
 	go func() {
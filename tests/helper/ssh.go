@@ -14,6 +14,7 @@
 package helper
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net"
@@ -22,6 +23,7 @@ import (
 	"path/filepath"
 	"syscall"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/creack/pty"
@@ -170,6 +172,32 @@ func MockSSHPortServer(t *testing.T, user, pass, key string) (string, string) {
 	return MockSSHServer(t, sshSrv, user, pass, key)
 }
 
+// DialSSH checks the authentication with password and the OTP code as keyboard-interactive answer
+// if it's not empty
+func DialSSH(addr, username, password, otp string) error {
+	cfg := &ssh.ClientConfig{
+		User: username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(password),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // #nosec G106 , tests need to be simple
+	}
+	if otp != "" {
+		cfg.Auth = append(cfg.Auth, ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+			if len(questions) != 1 {
+				return nil, fmt.Errorf("DialSSH: Unexpected questions: %q", questions)
+			}
+			return []string{otp}, nil
+		}))
+	}
+
+	conn, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return fmt.Errorf("DialSSH: Unable to connect to %s: %v", addr, err)
+	}
+	return conn.Close()
+}
+
 func RunCmdPtySSH(addr, username, password, cmd string) ([]byte, error) {
 	cfg := &ssh.ClientConfig{
 		User: username,
@@ -217,16 +245,72 @@ func RunCmdPtySSH(addr, username, password, cmd string) ([]byte, error) {
 		return nil, fmt.Errorf("RunCmdPtySSH: Unable to request shell: %v", err)
 	}
 
-	// Send command
+	// Reading the output in background to be able to wait for the shell prompt
+	outCh := make(chan []byte)
+	go func() {
+		defer close(outCh)
+		buf := make([]byte, 1024)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				outCh <- bytes.Clone(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	var output []byte
+	timeout := time.After(30 * time.Second)
+	// Collects the output until it's closed or stop returns true
+	readOutput := func(stop func([]byte) bool) error {
+		for stop == nil || !stop(output) {
+			select {
+			case data, ok := <-outCh:
+				if !ok {
+					if stop == nil {
+						return nil
+					}
+					return fmt.Errorf("RunCmdPtySSH: Output closed before the shell prompt: %q", output)
+				}
+				output = append(output, data...)
+			case <-timeout:
+				return fmt.Errorf("RunCmdPtySSH: Timeout waiting for the shell output: %q", output)
+			}
+		}
+		return nil
+	}
+	// Waits for the shell prompt after the provided output position
+	promptAfter := func(pos int) func([]byte) bool {
+		return func(out []byte) bool {
+			return len(out) > pos && (bytes.HasSuffix(out, []byte("$ ")) || bytes.HasSuffix(out, []byte("# ")))
+		}
+	}
+
+	// Waiting for the shell to start, otherwise the typed ahead command could be echoed before
+	// the prompt and the responses will not be comparable
+	if err = readOutput(promptAfter(0)); err != nil {
+		return nil, err
+	}
+
+	// Send command and wait for it to complete, the exit could be echoed before the output otherwise
+	pos := len(output)
 	if _, err = io.WriteString(stdin, fmt.Sprintf("%s\n", cmd)); err != nil {
 		return nil, fmt.Errorf("RunCmdPtySSH: Unable to write to stdin: %v", err)
 	}
-	// Send exit to shell
+	if err = readOutput(promptAfter(pos)); err != nil {
+		return nil, err
+	}
+
+	// Send exit to shell and read the rest until the channel is closed
 	if _, err = io.WriteString(stdin, "exit\n"); err != nil {
 		return nil, fmt.Errorf("RunCmdPtySSH: Unable to write to stdin: %v", err)
 	}
+	if err = readOutput(nil); err != nil {
+		return nil, err
+	}
 
-	return io.ReadAll(stdout)
+	return output, nil
 }
 
 // SCP nowadays uses sftp subsystem with no need for scp binary on the target, so use it directly
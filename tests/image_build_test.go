@@ -0,0 +1,199 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Image build pipeline:
+// * Unknown build template is rejected
+// * Build registers the Image, runs the smoke test and publishes it
+// * Label pinned to the Image version is rolled to the built one
+// * Failed build is not registering the Image
+func Test_image_build(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+image_build:
+  templates:
+    test:
+      command: ["sh", "-c", "echo building $PKR_VAR_flavor; echo 1,test.build,artifact,0,id,image-$PKR_VAR_flavor"]
+      driver: test
+    fail:
+      command: ["sh", "-c", "echo broken; exit 1"]
+      driver: test
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create Image and Labels", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/image/")).
+			JSON(`{"name":"built", "version":1, "identifiers":{"test":"image-v1"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		for _, body := range []string{
+			`{"name":"smoke", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}, "options":{"image":"${image:built}"}}]}`,
+			`{"name":"pinned", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}, "options":{"image":"${image:built:1}"}}]}`,
+		} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(body).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Unknown build template is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/image/build/")).
+			JSON(`{"name":"built", "version":2, "template":"unknown"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	// Starts the build and waits for it to complete
+	build := func(t *testing.T, body string) (b types.ImageBuild) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/image/build/")).
+			JSON(body).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&b)
+
+		h.Retry(&h.Timer{Timeout: 30 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/image/build/"+b.UID.String())).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&b)
+
+			if b.Status != "COMPLETED" && b.Status != "FAILED" {
+				r.Fatalf("Image build is not finished: %v", b.Status)
+			}
+		})
+		return b
+	}
+
+	t.Run("Build is tested, published and rolled", func(t *testing.T) {
+		b := build(t, `{"name":"built", "version":2, "template":"test", "variables":{"flavor":"v2"},
+			"smoke_label":"smoke", "roll_labels":["pinned"]}`)
+		if b.Status != "COMPLETED" || !strings.Contains(b.Log, "building v2") {
+			t.Fatalf("Image build is incorrect: %v", b)
+		}
+
+		var images []types.Image
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/image/")).
+			Query("filter", "name = 'built' AND version = 2").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&images)
+		if len(images) != 1 || images[0].Deprecated || images[0].Identifiers["test"] != "image-v2" || images[0].UID != b.ImageUID {
+			t.Fatalf("Built Image is incorrect: %v", images)
+		}
+
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name = 'pinned' AND version = 2").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+		if len(labels) != 1 || !strings.Contains(string(labels[0].Definitions[0].Options), "${image:built:2}") {
+			t.Fatalf("Pinned Label is not rolled: %v", labels)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name LIKE 'smoke-smoke-%'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+		if len(labels) != 0 {
+			t.Fatalf("Smoke test Label is not removed: %v", labels)
+		}
+	})
+
+	t.Run("Failed build is not registering Image", func(t *testing.T) {
+		b := build(t, `{"name":"built", "version":3, "template":"fail"}`)
+		if b.Status != "FAILED" || b.Error == "" || !strings.Contains(b.Log, "broken") {
+			t.Fatalf("Image build is incorrect: %v", b)
+		}
+
+		var images []types.Image
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/image/")).
+			Query("filter", "name = 'built' AND version = 3").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&images)
+		if len(images) != 0 {
+			t.Fatalf("Image should not be registered: %v", images)
+		}
+	})
+}
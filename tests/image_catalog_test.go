@@ -0,0 +1,218 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Labels could reference the Image catalog:
+// * Label with unknown Image reference can't be created
+// * Reference by name is resolved to the latest Image version on allocation
+// * Deprecated Image version is not used by the name reference
+// * Regular user can list the Images, but can't change the catalog
+func Test_image_catalog(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	createImage := func(t *testing.T, version int, id string) (img types.Image) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/image/")).
+			JSON(map[string]any{"name": "test-image", "version": version, "identifiers": map[string]string{"test": id}}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&img)
+		if img.UID == uuid.Nil {
+			t.Fatalf("Image UID is incorrect: %v", img.UID)
+		}
+		return img
+	}
+
+	// Allocates the Application and returns the image reported by the Resource
+	allocatedImage := func(t *testing.T, label types.Label) string {
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		var info map[string]any
+		json.Unmarshal([]byte(res.DriverInfo), &info)
+		image, _ := info["image"].(string)
+		return image
+	}
+
+	t.Run("Create Image", func(t *testing.T) {
+		createImage(t, 1, "image-v1")
+	})
+
+	t.Run("Label with unknown Image can't be created", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"wrong-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}, "options":{"image":"${image:unknown}"}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Create Label referencing the Image", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}, "options":{"image":"${image:test-image}"}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Allocation uses the Image", func(t *testing.T) {
+		if image := allocatedImage(t, label); image != "image-v1" {
+			t.Fatalf("Resource image is incorrect: %q", image)
+		}
+	})
+
+	var img2 types.Image
+	t.Run("Allocation uses the new Image version", func(t *testing.T) {
+		img2 = createImage(t, 2, "image-v2")
+		if image := allocatedImage(t, label); image != "image-v2" {
+			t.Fatalf("Resource image is incorrect: %q", image)
+		}
+	})
+
+	t.Run("Allocation skips the deprecated Image version", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/image/"+img2.UID.String()+"/deprecate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		if image := allocatedImage(t, label); image != "image-v1" {
+			t.Fatalf("Resource image is incorrect: %q", image)
+		}
+	})
+
+	t.Run("Regular user can list, but can't change Images", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		var images []types.Image
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/image/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&images)
+		if len(images) != 2 || !images[1].Deprecated {
+			t.Fatalf("Images list is incorrect: %v", images)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/image/")).
+			JSON(`{"name":"user-image", "version":1, "identifiers":{"test":"user"}}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,279 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the declarative Labels apply:
+// * Missing Labels are created and the identical ones are kept on re-apply
+// * Changed content of the existing version is reported as drift and not applied
+// * Prune removes the Labels missing in the set, but keeps the ones used by active Applications
+// * Roles are created, updated and pruned except the granted ones
+// * Nothing is applied if the set contains invalid Role
+// * Operator can't apply the Roles
+// * Regular user can't apply the Labels
+func Test_label_apply(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	const labelA1 = `{"name":"label-a", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`
+	const labelA2 = `{"name":"label-a", "version":2, "definitions": [{"driver":"test", "resources":{"cpu":2,"ram":4}}]}`
+	const labelB1 = `{"name":"label-b", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`
+	const labelC1 = `{"name":"label-c", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`
+
+	apply := func(t *testing.T, body string, status int) (res types.LabelApplyResult) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/apply")).
+			JSON(body).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(status).
+			End().
+			JSON(&res)
+		return res
+	}
+
+	t.Run("Apply creates the Labels", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelB1+`,`+labelC1+`]}`, http.StatusOK)
+		if !slices.Equal(res.Created, []string{"label-a:1", "label-b:1", "label-c:1"}) || len(res.Unchanged) != 0 {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+	})
+
+	t.Run("Re-apply keeps the identical Labels", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelB1+`,`+labelC1+`]}`, http.StatusOK)
+		if len(res.Created) != 0 || len(res.Unchanged) != 3 {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+	})
+
+	const labelB1Changed = `{"name":"label-b", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":4,"ram":8}}]}`
+
+	t.Run("Dry run reports the drift", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelB1Changed+`], "dry_run":true}`, http.StatusOK)
+		if !slices.Equal(res.Drifted, []string{"label-b:1"}) {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+	})
+
+	t.Run("Drifted Label is not applied", func(t *testing.T) {
+		apply(t, `{"labels":[`+labelA1+`,`+labelA2+`,`+labelB1Changed+`]}`, http.StatusBadRequest)
+
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name = 'label-a'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+
+		if len(labels) != 1 {
+			t.Fatalf("Nothing should be applied with drift: %v", labels)
+		}
+	})
+
+	var labelC types.Label
+	t.Run("Application uses the Label", func(t *testing.T) {
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name = 'label-c'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+		if len(labels) != 1 {
+			t.Fatalf("Label not found: %v", labels)
+		}
+		labelC = labels[0]
+
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labelC.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Prune removes the Labels missing in the set except the used ones", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelA2+`], "prune":true}`, http.StatusOK)
+		if !slices.Equal(res.Created, []string{"label-a:2"}) || !slices.Equal(res.Removed, []string{"label-b:1"}) ||
+			!slices.Equal(res.InUse, []string{"label-c:1"}) {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/"+labelC.UID.String())).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Apply creates the Roles", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelA2+`], "roles":[{"name":"team-a", "labels":["label-a"]}, {"name":"team-b"}]}`, http.StatusOK)
+		if res.Roles == nil || !slices.Equal(res.Roles.Created, []string{"team-a", "team-b"}) || len(res.Unchanged) != 2 {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+	})
+
+	t.Run("Admin grants the Roles to User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"role-user", "password":"role-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		for _, role := range []string{"team-b", "operator"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/role-user/grant/")).
+				JSON(map[string]any{"role": role, "expires_at": time.Now().Add(time.Hour)}).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Apply updates the changed Role and prune keeps the granted one", func(t *testing.T) {
+		res := apply(t, `{"labels":[`+labelA1+`,`+labelA2+`], "roles":[{"name":"team-a", "labels":["label-a", "label-c"]}], "prune":true}`, http.StatusOK)
+		if res.Roles == nil || !slices.Equal(res.Roles.Updated, []string{"team-a"}) || !slices.Equal(res.Roles.InUse, []string{"team-b"}) ||
+			len(res.Roles.Removed) != 0 {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+
+		var role types.Role
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/role/team-a")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&role)
+
+		if !slices.Equal(role.Labels, []string{"label-a", "label-c"}) {
+			t.Fatalf("Role is not updated: %v", role)
+		}
+	})
+
+	t.Run("Nothing is applied with invalid Role", func(t *testing.T) {
+		const labelD1 = `{"name":"label-d", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`
+		apply(t, `{"labels":[`+labelA1+`,`+labelA2+`,`+labelD1+`], "roles":[{"name":"team-a"}, {"name":"operator"}]}`, http.StatusBadRequest)
+
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name = 'label-d'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+
+		if len(labels) != 0 {
+			t.Fatalf("Nothing should be applied with invalid Role: %v", labels)
+		}
+	})
+
+	t.Run("Operator can't apply Roles", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/apply")).
+			JSON(`{"labels":[`+labelA1+`], "roles":[], "dry_run":true}`).
+			BasicAuth("role-user", "role-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/apply")).
+			JSON(`{"labels":[`+labelA1+`], "dry_run":true}`).
+			BasicAuth("role-user", "role-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Regular user can't apply Labels", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/apply")).
+			JSON(`{"labels":[`+labelA1+`], "dry_run":true}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,134 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Testing the Label Authentication is encrypted at rest
+// * Create Label with Authentication password
+// * Admin gets the decrypted password, the regular user gets it removed
+// * The password is not stored in database in plaintext
+// * The password is decrypted after the node restart
+func Test_label_authentication_encryption(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	const password = "label-auth-plaintext-password"
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2},
+				"authentication":{"username":"tester", "password":"`+password+`", "key":"", "port":22}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+	})
+
+	t.Run("Create regular User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	checkPassword := func(t *testing.T, user, token, expected string) {
+		t.Helper()
+		var got types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/"+label.UID.String())).
+			BasicAuth(user, token).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&got)
+
+		auth := got.Definitions[0].Authentication
+		if auth == nil || auth.Username != "tester" || auth.Password != expected {
+			t.Fatalf("Wrong Label Authentication for %s: %+v", user, auth)
+		}
+	}
+
+	t.Run("Admin gets the password", func(t *testing.T) {
+		checkPassword(t, "admin", afi.AdminToken(), password)
+	})
+
+	t.Run("Regular User gets no password", func(t *testing.T) {
+		checkPassword(t, "test-user", "test-user-password", "")
+	})
+
+	t.Run("Password is not stored in plaintext", func(t *testing.T) {
+		files, _ := filepath.Glob(filepath.Join(afi.Workspace(), "fish_data", "*", "sqlite.db*"))
+		if len(files) == 0 {
+			t.Fatalf("Unable to find the database files")
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Unable to read database file %s: %v", path, err)
+			}
+			if bytes.Contains(data, []byte(password)) {
+				t.Fatalf("Password is stored in plaintext in %s", path)
+			}
+		}
+	})
+
+	t.Run("Restart the fish app node", func(t *testing.T) {
+		afi.Restart(t)
+	})
+
+	t.Run("Admin gets the password after restart", func(t *testing.T) {
+		checkPassword(t, "admin", afi.AdminToken(), password)
+	})
+}
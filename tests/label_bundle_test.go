@@ -0,0 +1,194 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Labels could be shared between the clusters by the signed bundles:
+// * Exported bundle contains the Label and the referenced catalog Image
+// * Cluster trusting the key imports the Label and the missing Image
+// * Changed bundle is not imported
+// * Conflicting Label version is imported as the new version or overwritten
+func Test_label_bundle(t *testing.T) {
+	t.Parallel()
+	afi1 := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi1.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Image and Label on node-1", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi1.APIAddress("api/v1/image/")).
+			JSON(`{"name":"bundle-image", "version":1, "identifiers":{"test":"image-v1"}, "checksum":"sha256:0123"}`).
+			BasicAuth("admin", afi1.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi1.APIAddress("api/v1/label/")).
+			JSON(`{"name":"bundle-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}, "options":{"image":"${image:bundle-image}"}}]}`).
+			BasicAuth("admin", afi1.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+	})
+
+	export := func(t *testing.T) (bundle types.LabelBundle) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi1.APIAddress("api/v1/label/export")).
+			Query("label", "bundle-label").
+			BasicAuth("admin", afi1.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&bundle)
+		return bundle
+	}
+
+	var bundle types.LabelBundle
+	t.Run("Export the Label from node-1", func(t *testing.T) {
+		bundle = export(t)
+		if len(bundle.Labels) != 1 || bundle.Labels[0].Name != "bundle-label" {
+			t.Fatalf("Bundle Labels are incorrect: %v", bundle.Labels)
+		}
+		if len(bundle.Images) != 1 || bundle.Images[0].Checksum != "sha256:0123" {
+			t.Fatalf("Bundle Images are incorrect: %v", bundle.Images)
+		}
+		if bundle.PublicKey == "" || bundle.Signature == "" {
+			t.Fatalf("Bundle is not signed")
+		}
+	})
+
+	afi2 := h.NewAquariumFish(t, "node-2", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+label_bundle:
+  trusted_keys:
+    - `+bundle.PublicKey+`
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi2.Cleanup(t)
+	})
+
+	importBundle := func(t *testing.T, b types.LabelBundle, query map[string]string, status int) (res types.LabelImportResult) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi2.APIAddress("api/v1/label/import")).
+			QueryParams(query).
+			JSON(b).
+			BasicAuth("admin", afi2.AdminToken()).
+			Expect(t).
+			Status(status).
+			End().
+			JSON(&res)
+		return res
+	}
+
+	t.Run("Changed bundle is not imported", func(t *testing.T) {
+		changed := bundle
+		changed.Labels = slices.Clone(bundle.Labels)
+		changed.Labels[0].Version = 2
+		importBundle(t, changed, nil, http.StatusBadRequest)
+	})
+
+	t.Run("Import the Label to node-2", func(t *testing.T) {
+		res := importBundle(t, bundle, nil, http.StatusOK)
+		if !slices.Equal(res.Created, []string{"bundle-label:1"}) || !slices.Equal(res.ImagesCreated, []string{"bundle-image:1"}) {
+			t.Fatalf("Import result is incorrect: %+v", res)
+		}
+
+		res = importBundle(t, bundle, nil, http.StatusOK)
+		if !slices.Equal(res.Unchanged, []string{"bundle-label:1"}) || !slices.Equal(res.ImagesUnchanged, []string{"bundle-image:1"}) {
+			t.Fatalf("Second import result is incorrect: %+v", res)
+		}
+	})
+
+	t.Run("Change the Label on node-1", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi1.APIAddress("api/v1/label/"+label.UID.String())).
+			BasicAuth("admin", afi1.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi1.APIAddress("api/v1/label/")).
+			JSON(`{"name":"bundle-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":2,"ram":4}, "options":{"image":"${image:bundle-image}"}}]}`).
+			BasicAuth("admin", afi1.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		bundle = export(t)
+	})
+
+	t.Run("Conflicting Label could be overwritten", func(t *testing.T) {
+		res := importBundle(t, bundle, map[string]string{"on_conflict": "overwrite", "dry_run": "true"}, http.StatusOK)
+		if !slices.Equal(res.Overwritten, []string{"bundle-label:1"}) {
+			t.Fatalf("Import result is incorrect: %+v", res)
+		}
+	})
+
+	t.Run("Conflicting Label is imported as new version", func(t *testing.T) {
+		res := importBundle(t, bundle, nil, http.StatusOK)
+		if !slices.Equal(res.Versioned, []string{"bundle-label:1 -> bundle-label:2"}) {
+			t.Fatalf("Import result is incorrect: %+v", res)
+		}
+
+		res = importBundle(t, bundle, nil, http.StatusOK)
+		if !slices.Equal(res.Unchanged, []string{"bundle-label:2"}) {
+			t.Fatalf("Second import result is incorrect: %+v", res)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the node reports only the Labels definitions it could serve:
+// * Label with the node driver is compatible
+// * Label with not enabled driver is not compatible
+// * Label definition with not matching node filter is skipped
+func Test_label_compatibility(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+node_identifiers:
+  - "example:test"
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	labels := []string{
+		`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`,
+		`{"name":"aws-label", "version":1, "definitions": [{"driver":"aws", "resources":{"cpu":1,"ram":2}}]}`,
+		`{"name":"filter-label", "version":1, "definitions": [
+			{"driver":"test", "resources":{"cpu":1,"ram":2,"node_filter":["example:other"]}},
+			{"driver":"test", "resources":{"cpu":1,"ram":2,"node_filter":["example:*"]}}
+		]}`,
+	}
+	for _, label := range labels {
+		t.Run("Create Label", func(t *testing.T) {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(label).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		})
+	}
+
+	t.Run("Node reports compatible Labels", func(t *testing.T) {
+		var compat []types.LabelCompatibility
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/label_compatibility")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&compat)
+
+		if len(compat) != 2 {
+			t.Fatalf("Wrong number of compatible Labels: %+v", compat)
+		}
+		for _, c := range compat {
+			switch c.Name {
+			case "test-label":
+				if len(c.Definitions) != 1 || c.Definitions[0] != 0 {
+					t.Fatalf("Wrong compatible definitions of test-label: %v", c.Definitions)
+				}
+			case "filter-label":
+				if len(c.Definitions) != 1 || c.Definitions[0] != 1 {
+					t.Fatalf("Wrong compatible definitions of filter-label: %v", c.Definitions)
+				}
+			default:
+				t.Fatalf("Label should not be compatible: %s", c.Name)
+			}
+		}
+	})
+}
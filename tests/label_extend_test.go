@@ -0,0 +1,148 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Label could extend the parent Labels:
+// * Child overrides resources, adds options and metadata of the parent
+// * Parent referenced by name is resolved to the latest version and stored as exact one
+// * Later parent versions are not affecting the existing child
+// * Parents and children could be applied in one set
+// * Missing parent is reported
+func Test_label_extend(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	createLabel := func(t *testing.T, body string, status int) (label types.Label) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(body).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(status).
+			End().
+			JSON(&label)
+		return label
+	}
+
+	t.Run("Create parent Label", func(t *testing.T) {
+		label := createLabel(t, `{"name":"base", "version":1, "metadata":{"A":"base","B":"base"},
+			"definitions": [{"driver":"test", "resources":{"cpu":2,"ram":4,"lifetime":"1h"}, "options":{"fail_allocate":0}}]}`, http.StatusOK)
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Child overrides the parent", func(t *testing.T) {
+		label := createLabel(t, `{"name":"child", "version":1, "extends":["base"], "metadata":{"B":"child"},
+			"definitions": [{"resources":{"ram":8}, "options":{"fail_options_apply":0}}]}`, http.StatusOK)
+
+		if !slices.Equal(label.Extends, []string{"base:1"}) {
+			t.Fatalf("Parent should be stored as exact version: %v", label.Extends)
+		}
+		if len(label.Definitions) != 1 {
+			t.Fatalf("Definitions are incorrect: %v", label.Definitions)
+		}
+		def := label.Definitions[0]
+		if def.Driver != "test" || def.Resources.Cpu != 2 || def.Resources.Ram != 8 || def.Resources.Lifetime != "1h" {
+			t.Fatalf("Definition is not merged: %v", def)
+		}
+		var opts, metadata map[string]any
+		json.Unmarshal([]byte(def.Options), &opts)
+		if len(opts) != 2 {
+			t.Fatalf("Options are not merged: %v", def.Options)
+		}
+		json.Unmarshal([]byte(label.Metadata), &metadata)
+		if metadata["A"] != "base" || metadata["B"] != "child" {
+			t.Fatalf("Metadata is not merged: %v", label.Metadata)
+		}
+	})
+
+	t.Run("New parent version is not affecting the child", func(t *testing.T) {
+		createLabel(t, `{"name":"base", "version":2, "definitions": [{"driver":"test", "resources":{"cpu":4,"ram":4}}]}`, http.StatusOK)
+
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("filter", "name = 'child'").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+
+		if len(labels) != 1 || labels[0].Definitions[0].Resources.Cpu != 2 {
+			t.Fatalf("Child Label should not be changed: %v", labels)
+		}
+	})
+
+	t.Run("Parent and child are applied in one set", func(t *testing.T) {
+		var res types.LabelApplyResult
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/apply")).
+			JSON(`{"labels":[
+				{"name":"set-base", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]},
+				{"name":"set-child", "version":1, "extends":["set-base:1"], "definitions": [{"resources":{"ram":16}}]}
+			]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if !slices.Equal(res.Created, []string{"set-base:1", "set-child:1"}) {
+			t.Fatalf("Apply result is incorrect: %v", res)
+		}
+	})
+
+	t.Run("Missing parent is reported", func(t *testing.T) {
+		createLabel(t, `{"name":"orphan", "version":1, "extends":["missing:1"], "definitions": [{"resources":{"ram":8}}]}`, http.StatusBadRequest)
+		createLabel(t, `{"name":"self", "version":2, "extends":["self"], "definitions": [{"resources":{"ram":8}}]}`, http.StatusBadRequest)
+	})
+}
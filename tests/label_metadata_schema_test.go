@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Label metadata schema is validating the Application metadata:
+// * Label with invalid schema can't be created
+// * Application with wrong metadata is rejected with all the issues listed
+// * Application with the right metadata is created
+func Test_label_metadata_schema(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Label with invalid schema can't be created", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"wrong-schema", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}],
+				"metadata_schema":{"type":"unknown"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Create Label with metadata schema", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}],
+				"metadata_schema":{
+					"type":"object",
+					"required":["JENKINS_URL", "JENKINS_AGENT_NAME"],
+					"properties":{
+						"JENKINS_URL":{"type":"string", "pattern":"^https://"},
+						"JENKINS_AGENT_NAME":{"type":"string"}
+					}
+				}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Application with wrong metadata is rejected", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, afi.APIAddress("api/v1/application/"),
+			strings.NewReader(`{"label_UID":"`+label.UID.String()+`", "metadata":{"JENKINS_URL":"http://jenkins"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth("admin", afi.AdminToken())
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("Unable to do request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+
+		var out struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &out)
+		if resp.StatusCode != http.StatusBadRequest || out.Field != "metadata" {
+			t.Fatalf("Wrong response %d: %s", resp.StatusCode, data)
+		}
+		// Both issues are reported at once
+		if !strings.Contains(out.Message, "JENKINS_AGENT_NAME") || !strings.Contains(out.Message, "/JENKINS_URL") {
+			t.Fatalf("Message is not listing all the issues: %s", out.Message)
+		}
+	})
+
+	t.Run("Application with the right metadata is created", func(t *testing.T) {
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "metadata":{"JENKINS_URL":"https://jenkins", "JENKINS_AGENT_NAME":"agent"}}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the pool Resource failed to destroy is destroyed again on the next node start:
+// * Allocate Application and deallocate it to put the Resource to the pool
+// * Application over the budget takes the pooled Resource and fails to destroy it
+// * Restart the node with the working deallocate
+// * The Resource is destroyed and the node usage is released
+func Test_label_recycle_destroy_retry(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+    cfg:
+      cpu_price: 1
+      fail_deallocate: 255`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	waitState := func(t *testing.T, app types.Application, status types.ApplicationStatus) (appState types.ApplicationState) {
+		t.Helper()
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != status {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+		return appState
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2},
+				"recycle":{"max_reuse":0, "max_age":"", "idle_timeout":"", "task":"", "options":{}}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Create User with budget", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/quota")).
+			JSON(`{"max_hourly_cost":0.5}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	var res types.Resource
+	t.Run("Deallocated Resource is returned to the pool", func(t *testing.T) {
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		waitState(t, app, types.ApplicationStatusALLOCATED)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		appState := waitState(t, app, types.ApplicationStatusDEALLOCATED)
+		if !strings.Contains(appState.Description, "recycle pool") {
+			t.Fatalf("Application Resource was not recycled: %v", appState.Description)
+		}
+	})
+
+	t.Run("Application over the budget fails to destroy the pooled Resource", func(t *testing.T) {
+		var app types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		appState := waitState(t, app, types.ApplicationStatusERROR)
+		if !strings.Contains(appState.Description, "Budget exceeded") {
+			t.Fatalf("Application error is incorrect: %v", appState.Description)
+		}
+	})
+
+	resFile := filepath.Join(afi.Workspace(), "fish_test_workspace", res.Identifier)
+	t.Run("Restart the node with the working deallocate", func(t *testing.T) {
+		if _, err := os.Stat(resFile); err != nil {
+			t.Fatalf("The Resource should still exist: %v", err)
+		}
+		afi.Stop(t)
+		cfgPath := filepath.Join(afi.Workspace(), "config.yml")
+		cfg, err := os.ReadFile(cfgPath)
+		if err != nil {
+			t.Fatalf("Unable to read the node config: %v", err)
+		}
+		cfg = bytes.Replace(cfg, []byte("fail_deallocate: 255"), []byte("fail_deallocate: 0"), 1)
+		if err := os.WriteFile(cfgPath, cfg, 0o600); err != nil {
+			t.Fatalf("Unable to write the node config: %v", err)
+		}
+		afi.Start(t)
+	})
+
+	t.Run("The Resource is destroyed on start", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if _, err := os.Stat(resFile); !os.IsNotExist(err) {
+				r.Fatalf("The Resource is not destroyed: %v", err)
+			}
+		})
+	})
+
+	t.Run("Node usage is released", func(t *testing.T) {
+		var capacity types.NodeCapacity
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/capacity")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&capacity)
+
+		if capacity.CpuUsed != 0 || capacity.RamUsed != 0 {
+			t.Fatalf("Node usage is incorrect: cpu %d, ram %d", capacity.CpuUsed, capacity.RamUsed)
+		}
+	})
+}
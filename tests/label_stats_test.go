@@ -0,0 +1,187 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Label versions usage stats:
+// * Finished Application is counted in total with lifetime of the old version
+// * Running Application is counted for the new version
+// * Regular user can't get the stats
+func Test_label_stats(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var labels [2]types.Label
+	t.Run("Create Label versions", func(t *testing.T) {
+		for i := range labels {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(`{"name":"test-label", "version":`+[]string{"1", "2"}[i]+`, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&labels[i])
+
+			if labels[i].UID == uuid.Nil {
+				t.Fatalf("Label UID is incorrect: %v", labels[i].UID)
+			}
+		}
+	})
+
+	var apps [2]types.Application
+	t.Run("Create Applications", func(t *testing.T) {
+		for i := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				JSON(`{"label_UID":"`+labels[i].UID.String()+`"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&apps[i])
+
+			if apps[i].UID == uuid.Nil {
+				t.Fatalf("Application UID is incorrect: %v", apps[i].UID)
+			}
+		}
+	})
+
+	t.Run("Applications should get ALLOCATED in 10 sec", func(t *testing.T) {
+		for i := range apps {
+			h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+				var appState types.ApplicationState
+				apitest.New().
+					EnableNetworking(cli).
+					Get(afi.APIAddress("api/v1/application/"+apps[i].UID.String()+"/state")).
+					BasicAuth("admin", afi.AdminToken()).
+					Expect(r).
+					Status(http.StatusOK).
+					End().
+					JSON(&appState)
+
+				if appState.Status != types.ApplicationStatusALLOCATED {
+					r.Fatalf("Application Status is incorrect: %v", appState.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("Deallocate the old version Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Application should get DEALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusDEALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Label versions stats", func(t *testing.T) {
+		var stats []types.LabelStats
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/stats")).
+			Query("name", "test-label").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&stats)
+
+		if len(stats) != 2 {
+			t.Fatalf("Stats should contain 2 versions: %v", stats)
+		}
+		if stats[0].Version != 1 || stats[0].Total != 1 || stats[0].Running != 0 || stats[0].Failed != 0 {
+			t.Fatalf("Old version stats are incorrect: %v", stats[0])
+		}
+		if stats[0].AvgLifetime <= 0 {
+			t.Fatalf("Old version lifetime is incorrect: %v", stats[0].AvgLifetime)
+		}
+		if stats[1].Version != 2 || stats[1].Total != 1 || stats[1].Running != 1 {
+			t.Fatalf("New version stats are incorrect: %v", stats[1])
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Regular user can't get the stats", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/stats")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
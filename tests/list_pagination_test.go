@@ -0,0 +1,128 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the list pagination and sorting:
+// * Pages are following each other by the token and the last one has no next token
+// * Sort order is applied and only the indexed fields could be used
+// * Wrong page token is rejected
+func Test_list_pagination(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create Labels", func(t *testing.T) {
+		for i := 1; i <= 5; i++ {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(fmt.Sprintf(`{"name":"label-%d", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`, i)).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Pages follow each other in the order", func(t *testing.T) {
+		var names []string
+		token := ""
+		for pages := 1; ; pages++ {
+			var labels []types.Label
+			query := map[string]string{"page_size": "2", "sort": "-name"}
+			if token != "" {
+				query["page_token"] = token
+			}
+			resp := apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/label/")).
+				QueryParams(query).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+			resp.JSON(&labels)
+
+			if len(labels) > 2 {
+				t.Fatalf("Page is bigger than requested: %d", len(labels))
+			}
+			for _, l := range labels {
+				names = append(names, l.Name)
+			}
+			token = resp.Response.Header.Get("X-Next-Page-Token")
+			if token == "" {
+				if pages != 3 {
+					t.Fatalf("Wrong amount of pages: %d", pages)
+				}
+				break
+			}
+		}
+		if !slices.Equal(names, []string{"label-5", "label-4", "label-3", "label-2", "label-1"}) {
+			t.Fatalf("Wrong Labels order: %v", names)
+		}
+	})
+
+	t.Run("Not indexed sort field is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("sort", "metadata").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Wrong page token is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			Query("page_token", "wrong!").
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Testing the node diagnostic bundle
+// * Regular user can't get the bundle
+// * Admin gets tar.gz with logs, config, db stats, drivers and goroutines
+// * The config secrets are redacted
+func Test_node_bundle(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+secrets:
+  vault:
+    address: https://vault.invalid:8200
+    token: bundle-secret-token
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 10,
+		Transport: tr,
+	}
+
+	t.Run("Admin gets the bundle", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, afi.APIAddress("api/v1/node/this/bundle"), nil)
+		req.SetBasicAuth("admin", afi.AdminToken())
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("Unable to request the bundle: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Wrong bundle status: %d", resp.StatusCode)
+		}
+		if !strings.Contains(resp.Header.Get("Content-Disposition"), "fish-bundle-node-1-") {
+			t.Fatalf("Wrong bundle file name: %q", resp.Header.Get("Content-Disposition"))
+		}
+
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatalf("Bundle is not gzip: %v", err)
+		}
+		files := map[string]string{}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unable to read bundle: %v", err)
+			}
+			data, _ := io.ReadAll(tr)
+			files[hdr.Name] = string(data)
+		}
+
+		for _, name := range []string{"info.json", "config.json", "logs.txt", "db.json", "drivers.json", "goroutines.txt"} {
+			if files[name] == "" {
+				t.Fatalf("Bundle file %s is missing or empty: %v", name, files)
+			}
+		}
+		if strings.Contains(files["config.json"], "bundle-secret-token") || !strings.Contains(files["config.json"], "<redacted>") {
+			t.Fatalf("Config secrets are not redacted: %s", files["config.json"])
+		}
+		if !strings.Contains(files["drivers.json"], `"name": "test"`) {
+			t.Fatalf("Test driver is not in the bundle: %s", files["drivers.json"])
+		}
+		if !strings.Contains(files["goroutines.txt"], "goroutine") {
+			t.Fatalf("Wrong goroutines dump: %s", files["goroutines.txt"])
+		}
+	})
+
+	t.Run("Regular user can't get the bundle", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, afi.APIAddress("api/v1/node/this/bundle"), nil)
+		req.SetBasicAuth("nobody", "nobody-password")
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("Unable to request the bundle: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Fatalf("Not existing user should not get the bundle")
+		}
+	})
+}
@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the node reports the capacity of the local and remote drivers:
+// * Label slots are the local plus the remote ones
+// * Allocated Application is taken into account by the node capacity
+// * Cluster capacity picks up the published node capacity
+func Test_node_capacity(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+capacity_interval: 1s
+
+drivers:
+  - name: test/local
+    cfg:
+      cpu_limit: 4
+      ram_limit: 8
+  - name: test/cloud
+    cfg:
+      is_remote: true
+      cpu_limit: 4
+      ram_limit: 8`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"capacity-label", "version":1, "definitions": [
+				{"driver":"test/local", "resources":{"cpu":2,"ram":4}},
+				{"driver":"test/cloud", "resources":{"cpu":2,"ram":4}}
+			]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Node reports the Label slots", func(t *testing.T) {
+		var capacity types.NodeCapacity
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/capacity")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&capacity)
+
+		if len(capacity.Drivers) != 2 || capacity.CpuTotal == 0 || capacity.CpuUsed != 0 {
+			t.Fatalf("Wrong node capacity: %+v", capacity)
+		}
+		if len(capacity.Labels) != 1 || len(capacity.Labels[0].Definitions) != 2 {
+			t.Fatalf("Wrong Labels capacity: %+v", capacity.Labels)
+		}
+		// 2 slots of the local driver and 2 of the remote one
+		if capacity.Labels[0].Slots != 4 {
+			t.Fatalf("Wrong Label slots: %+v", capacity.Labels[0])
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Node capacity takes the Application into account", func(t *testing.T) {
+		var capacity types.NodeCapacity
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/capacity")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&capacity)
+
+		if capacity.CpuUsed != 2 || capacity.RamUsed != 4 {
+			t.Fatalf("Wrong node resources used: %+v", capacity)
+		}
+		// The test driver checks the node usage even when it pretends to be remote
+		if len(capacity.Labels) != 1 || capacity.Labels[0].Slots != 2 {
+			t.Fatalf("Wrong Labels capacity: %+v", capacity.Labels)
+		}
+	})
+
+	t.Run("Cluster capacity gets the published node capacity in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var capacity types.ClusterCapacity
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/node/capacity")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&capacity)
+
+			if len(capacity.Nodes) != 1 || len(capacity.Labels) != 1 {
+				r.Fatalf("Wrong cluster capacity: %+v", capacity)
+			}
+			if capacity.Labels[0].Slots != 2 {
+				r.Fatalf("Wrong cluster Label slots: %+v", capacity.Labels[0])
+			}
+		})
+	})
+}
@@ -0,0 +1,337 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the startup reconcile of the driver resources with the database:
+// * Allocate 2 Applications
+// * Stop the node and emulate the crash: remove one Resource and add unknown ones to the driver
+// * Start the node
+// * Application with the missing Resource gets ERROR, the other one stays ALLOCATED
+// * Unknown Resource of this node is deallocated
+// * Unknown Resource of the other node is kept
+func Test_node_reconcile(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+reconcile_terminate: true
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	apps := make([]types.Application, 2)
+	t.Run("Create Applications", func(t *testing.T) {
+		for i := range apps {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/application/")).
+				JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&apps[i])
+
+			if apps[i].UID == uuid.Nil {
+				t.Fatalf("Application UID is incorrect: %v", apps[i].UID)
+			}
+		}
+	})
+
+	t.Run("Applications should get ALLOCATED in 10 sec", func(t *testing.T) {
+		for _, app := range apps {
+			h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+				var appState types.ApplicationState
+				apitest.New().
+					EnableNetworking(cli).
+					Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+					BasicAuth("admin", afi.AdminToken()).
+					Expect(r).
+					Status(http.StatusOK).
+					End().
+					JSON(&appState)
+
+				if appState.Status != types.ApplicationStatusALLOCATED {
+					r.Fatalf("Application Status is incorrect: %v", appState.Status)
+				}
+			})
+		}
+	})
+
+	var node types.Node
+	t.Run("Get this Node", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&node)
+	})
+
+	var res types.Resource
+	t.Run("Get Resource of the first Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if res.Identifier == "" {
+			t.Fatalf("Resource identifier is empty")
+		}
+	})
+
+	workspace := filepath.Join(afi.Workspace(), "fish_test_workspace")
+	orphan := filepath.Join(workspace, "test-orphan")
+	foreign := filepath.Join(workspace, "test-foreign")
+	t.Run("Emulate the node crash", func(t *testing.T) {
+		afi.Stop(t)
+		if err := os.Remove(filepath.Join(workspace, res.Identifier)); err != nil {
+			t.Fatalf("Unable to remove the Resource file: %v", err)
+		}
+		if err := os.WriteFile(orphan, []byte(`{"node":"`+node.UID.String()+`"}`), 0o640); err != nil {
+			t.Fatalf("Unable to create the unknown Resource file: %v", err)
+		}
+		if err := os.WriteFile(foreign, []byte(`{"node":"`+uuid.NewString()+`"}`), 0o640); err != nil {
+			t.Fatalf("Unable to create the other node Resource file: %v", err)
+		}
+		afi.Start(t)
+	})
+
+	t.Run("Application with missing Resource should get ERROR", func(t *testing.T) {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[0].UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusERROR {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Other Application should stay ALLOCATED", func(t *testing.T) {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+apps[1].UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusALLOCATED {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Unknown Resource should be deallocated", func(t *testing.T) {
+		if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+			t.Fatalf("Unknown Resource file still exists: %v", err)
+		}
+	})
+
+	t.Run("Unknown Resource of the other node should be kept", func(t *testing.T) {
+		if _, err := os.Stat(foreign); err != nil {
+			t.Fatalf("Other node Resource file is removed: %v", err)
+		}
+	})
+}
+
+// Checks the startup reconcile adopts the driver resource allocated before the node crash:
+// * Allocate the Application with slow driver
+// * Kill the node when the driver resource is created, but not reported yet
+// * Start the node
+// * Application gets ALLOCATED with the driver resource
+// * Deallocate the Application removes the driver resource
+func Test_node_reconcile_adopt(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+    cfg:
+      allocate_delay: 30s`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	workspace := filepath.Join(afi.Workspace(), "fish_test_workspace")
+	var identifier string
+	t.Run("Driver resource should be created in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			files, _ := filepath.Glob(filepath.Join(workspace, "test-*"))
+			if len(files) != 1 {
+				r.Fatalf("Wrong amount of the driver resources: %v", files)
+			}
+			identifier = filepath.Base(files[0])
+		})
+	})
+
+	t.Run("Emulate the node crash", func(t *testing.T) {
+		afi.Stop(t)
+		afi.Start(t)
+	})
+
+	t.Run("Application should get ALLOCATED with the driver resource", func(t *testing.T) {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusALLOCATED {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+
+		var res types.Resource
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if res.Identifier != identifier {
+			t.Fatalf("Resource identifier is incorrect: %q != %q", res.Identifier, identifier)
+		}
+	})
+
+	t.Run("Deallocate the Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Driver resource should be removed in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if _, err := os.Stat(filepath.Join(workspace, identifier)); !os.IsNotExist(err) {
+				r.Fatalf("Driver resource still exists: %v", err)
+			}
+		})
+	})
+}
@@ -0,0 +1,96 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the node publishes the host telemetry:
+// * Healthy node is in OK status with the usage filled
+// * Failing driver self check switches the node to WARNING
+func Test_node_telemetry(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+telemetry:
+  interval: 1s
+  cpu: 101
+  ram: 101
+  disk: 101
+
+drivers:
+  - name: test/good
+  - name: test/bad
+    cfg:
+      fail_self_check: 255`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Node telemetry should be published in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var node types.Node
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/node/this/")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&node)
+
+			if node.Telemetry == nil {
+				r.Fatalf("Node telemetry is not published yet")
+			}
+			if node.Telemetry.RamUsage <= 0 || node.Telemetry.DiskUsage <= 0 {
+				r.Fatalf("Node telemetry usage is incorrect: %+v", node.Telemetry)
+			}
+			if node.Telemetry.Status != types.NodeTelemetryStatusWARNING {
+				r.Fatalf("Node telemetry status is incorrect: %v", node.Telemetry.Status)
+			}
+			if len(node.Telemetry.Drivers) != 2 {
+				r.Fatalf("Node telemetry drivers are incorrect: %+v", node.Telemetry.Drivers)
+			}
+			for _, d := range node.Telemetry.Drivers {
+				if d.Healthy != (d.Name == "test/good") {
+					r.Fatalf("Driver %s health is incorrect: %v", d.Name, d.Healthy)
+				}
+			}
+			if len(node.Telemetry.Warnings) != 1 {
+				r.Fatalf("Node telemetry warnings are incorrect: %v", node.Telemetry.Warnings)
+			}
+		})
+	})
+}
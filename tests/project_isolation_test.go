@@ -0,0 +1,248 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Project isolates the Labels, Applications and quota of the team:
+// * Only the Project maintainer could create the Project Labels
+// * The Project Labels are not visible to the other Users
+// * Only the Project members could create the Applications with the Project Labels
+// * Project quota limits the Applications of all the members
+// * Project maintainer sees the Applications of the other members
+func Test_project_isolation(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create Users", func(t *testing.T) {
+		for _, name := range []string{"ios-lead", "ios-dev", "android-dev"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"`+name+`-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Admin creates the Project", func(t *testing.T) {
+		var project types.Project
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/project/")).
+			JSON(`{"name":"team-ios", "max_applications":1, "members":[
+				{"user_name":"ios-lead", "role":"maintainer"},
+				{"user_name":"ios-dev", "role":"member"}
+			]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&project)
+
+		if len(project.Members) != 2 {
+			t.Fatalf("Project members are incorrect: %v", project.Members)
+		}
+	})
+
+	t.Run("Member can't create the Project Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"ios-mac", "version":1, "project":"team-ios", "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("ios-dev", "ios-dev-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Maintainer creates the Project Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"ios-mac", "version":1, "project":"team-ios", "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("ios-lead", "ios-lead-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Project Label is not visible to the other User", func(t *testing.T) {
+		var labels []types.Label
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			BasicAuth("android-dev", "android-dev-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&labels)
+
+		if len(labels) != 0 {
+			t.Fatalf("Labels list is incorrect: %v", labels)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/"+label.UID.String())).
+			BasicAuth("android-dev", "android-dev-password").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("Other User can't create Application with the Project Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("android-dev", "android-dev-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			Body(`{"message":"Unable to create application: Fish: User \"android-dev\" is not a member of Project \"team-ios\""}`).
+			End()
+	})
+
+	var app types.Application
+	t.Run("Member creates Application in the Project", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("ios-dev", "ios-dev-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.Project != "team-ios" {
+			t.Fatalf("Application Project is incorrect: %q", app.Project)
+		}
+	})
+
+	t.Run("Project quota limits the other member", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("ios-lead", "ios-lead-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			Body(`{"message":"Unable to create application: Fish: Project quota exceeded: max 1 active Applications, used 1"}`).
+			End()
+	})
+
+	t.Run("Maintainer sees the Project Applications", func(t *testing.T) {
+		var apps []types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			Query("project", "team-ios").
+			BasicAuth("ios-lead", "ios-lead-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+
+		if len(apps) != 1 || apps[0].UID != app.UID {
+			t.Fatalf("Applications list is incorrect: %v", apps)
+		}
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String())).
+			BasicAuth("ios-lead", "ios-lead-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Other User doesn't see the Project Applications", func(t *testing.T) {
+		var apps []types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			Query("project", "team-ios").
+			BasicAuth("android-dev", "android-dev-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&apps)
+
+		if len(apps) != 0 {
+			t.Fatalf("Applications list is incorrect: %v", apps)
+		}
+
+		var projects []types.Project
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/project/")).
+			BasicAuth("android-dev", "android-dev-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&projects)
+
+		if len(projects) != 0 {
+			t.Fatalf("Projects list is incorrect: %v", projects)
+		}
+	})
+
+	t.Run("Project with Labels can't be deleted", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi.APIAddress("api/v1/project/team-ios")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,561 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"gopkg.in/yaml.v3"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// rbacOperation describes the API operation request and who is expected to be allowed to call it
+// on the objects of the other User:
+// * all - any User
+// * admin - only admin
+// * operator - admin and operator
+// * auditor - admin and auditor
+// * approver - admin and approver
+// * owner - the owner, admin and operator
+// * self - the owner and admin
+// * own - only the owner
+type rbacOperation struct {
+	method string
+	path   string
+	body   string
+	access string
+	safe   bool // The allowed Users could call it without changing the state
+}
+
+// rbacUser is the User with the role to check the matrix for
+type rbacUser struct {
+	name     string
+	password string
+	role     string
+}
+
+// allowed returns if the User with role is allowed to call the operation on the other User object
+func (o rbacOperation) allowed(role string) bool {
+	switch o.access {
+	case "all":
+		return true
+	case "admin", "self":
+		return role == "admin"
+	case "operator", "owner":
+		return role == "admin" || role == "operator"
+	case "auditor":
+		return role == "admin" || role == "auditor"
+	case "approver":
+		return role == "admin" || role == "approver"
+	}
+	return false
+}
+
+// rbacSkipTags are the API tags which are not served by the API server: MetaData is served to the
+// Resources by the meta server, Cluster and Vote are not implemented as API yet
+var rbacSkipTags = []string{"MetaData", "Cluster", "Vote"}
+
+// rbacServedOperations returns the operation IDs of the API served by the node and the ones which
+// are not routed because their tags are missing in the API config
+func rbacServedOperations(t *testing.T) (ops, unrouted []string) {
+	t.Helper()
+	var cfg struct {
+		OutputOptions struct {
+			IncludeTags []string `yaml:"include-tags"`
+		} `yaml:"output-options"`
+	}
+	data, err := os.ReadFile("../lib/openapi/api_v1.cfg.yaml")
+	if err != nil {
+		t.Fatalf("Unable to read API config: %v", err)
+	}
+	if err = yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unable to parse API config: %v", err)
+	}
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string   `yaml:"operationId"`
+			Tags        []string `yaml:"tags"`
+		} `yaml:"paths"`
+	}
+	if data, err = os.ReadFile("../docs/openapi.yaml"); err != nil {
+		t.Fatalf("Unable to read API spec: %v", err)
+	}
+	if err = yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Unable to parse API spec: %v", err)
+	}
+
+	for _, methods := range spec.Paths {
+		for _, op := range methods {
+			if slices.ContainsFunc(op.Tags, func(tag string) bool { return slices.Contains(cfg.OutputOptions.IncludeTags, tag) }) {
+				ops = append(ops, op.OperationID)
+			} else if !slices.ContainsFunc(op.Tags, func(tag string) bool { return slices.Contains(rbacSkipTags, tag) }) {
+				unrouted = append(unrouted, op.OperationID)
+			}
+		}
+	}
+	slices.Sort(ops)
+	slices.Sort(unrouted)
+	return ops, unrouted
+}
+
+// Checks every API operation against every role:
+// * All the API operations are routed by the API server
+// * Permission matrix covers all the served API operations
+// * Runtime permissions of the User are matching the matrix
+// * Users without permission are denied to call the operation on the other User objects
+// * Users with permission are allowed to call the read-only operations
+func Test_rbac_matrix(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	users := []rbacUser{
+		{"admin", afi.AdminToken(), "admin"},
+		{"rbac-operator", "rbac-operator-password", "operator"},
+		{"rbac-auditor", "rbac-auditor-password", "auditor"},
+		{"rbac-approver", "rbac-approver-password", "approver"},
+		{"rbac-user", "rbac-user-password", "user"},
+	}
+
+	var grant types.RoleGrant
+	var token types.UserToken
+	var subscription types.Subscription
+	t.Run("Create Users", func(t *testing.T) {
+		for _, u := range append(users[1:], rbacUser{"rbac-other", "rbac-other-password", "user"}) {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+u.name+`", "password":"`+u.password+`"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+			if u.role == "user" {
+				continue
+			}
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/"+u.name+"/grant/")).
+				JSON(map[string]any{"role": u.role, "expires_at": time.Now().Add(time.Hour)}).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&grant)
+		}
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/rbac-other/token/")).
+			JSON(`{"name":"rbac"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&token)
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/rbac-other/subscription/")).
+			JSON(`{"event":"application_allocated", "sink":"slack", "address":"https://hooks.slack.com/services/rbac"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&subscription)
+	})
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	var res types.Resource
+	t.Run("Resource should be allocated in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&res)
+
+			if res.UID == uuid.Nil {
+				r.Fatalf("Resource UID is incorrect: %v", res.UID)
+			}
+		})
+	})
+
+	var batch types.ApplicationBatch
+	var schedule types.Schedule
+	var task types.ApplicationTask
+	var mapping types.ServiceMapping
+	var node types.Node
+	t.Run("Create the objects", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/batch/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`", "count":1}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&batch)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/schedule/")).
+			JSON(`{"name":"rbac", "enabled":false, "cron":"0 0 1 1 *", "label_UID":"`+label.UID.String()+`", "count":1}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&schedule)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/"+app.UID.String()+"/task/")).
+			JSON(map[string]any{"task": "snapshot", "when": types.ApplicationStatusDEALLOCATE}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&task)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/servicemapping/")).
+			JSON(`{"application_UID":"`+app.UID.String()+`", "location_name":"test_loc", "service":"example.com", "redirect":"mirror.example.com"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&mapping)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/node/this/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&node)
+
+		if batch.UID == uuid.Nil || schedule.UID == uuid.Nil || task.UID == uuid.Nil || mapping.UID == uuid.Nil {
+			t.Fatalf("Objects are not created: %v, %v, %v, %v", batch.UID, schedule.UID, task.UID, mapping.UID)
+		}
+	})
+
+	appPath := "api/v1/application/" + app.UID.String()
+	schedulePath := "api/v1/schedule/" + schedule.UID.String()
+	upgradePath := "api/v1/upgrade/" + uuid.NewString()
+	imagePath := "api/v1/image/" + uuid.NewString()
+	labelBody := `{"name":"rbac-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`
+	operations := map[string]rbacOperation{
+		"UserListGet":          {"GET", "api/v1/user/", "", "admin", true},
+		"UserCreateUpdatePost": {"POST", "api/v1/user/", `{"name":"rbac-other", "password":"rbac-other-password"}`, "self", false},
+		"UserMeGet":            {"GET", "api/v1/user/me/", "", "all", true},
+		"UserMePermissionsGet": {"GET", "api/v1/user/me/permissions", "", "all", true},
+		"UserMeRateLimitGet":   {"GET", "api/v1/user/me/ratelimit", "", "all", true},
+		"PreferenceListGet":    {"GET", "api/v1/user/me/preferences/", "", "all", true},
+		"PreferenceGet":        {"GET", "api/v1/user/me/preferences/rbac", "", "all", true},
+		"PreferencePut":        {"PUT", "api/v1/user/me/preferences/rbac", `{"value":true}`, "all", false},
+		"PreferenceDelete":     {"DELETE", "api/v1/user/me/preferences/rbac", "", "all", false},
+		"UserGet":              {"GET", "api/v1/user/rbac-other", "", "admin", true},
+		"UserDelete":           {"DELETE", "api/v1/user/rbac-other", "", "admin", false},
+		"UserGrantListGet":     {"GET", "api/v1/user/rbac-other/grant/", "", "self", true},
+		"UserGrantCreatePost":  {"POST", "api/v1/user/rbac-other/grant/", `{"role":"operator", "expires_at":"2100-01-01T00:00:00Z"}`, "admin", false},
+		"UserOTPPut":           {"PUT", "api/v1/user/rbac-other/otp", "", "own", false},
+		"UserOTPDelete":        {"DELETE", "api/v1/user/rbac-other/otp", "", "admin", false},
+		"UserQuotaGet":         {"GET", "api/v1/user/rbac-other/quota", "", "owner", true},
+		"UserQuotaPut":         {"PUT", "api/v1/user/rbac-other/quota", `{"max_applications":10}`, "admin", false},
+		"UserQuotaDelete":      {"DELETE", "api/v1/user/rbac-other/quota", "", "admin", false},
+		"GrantRevokeDelete":    {"DELETE", "api/v1/grant/" + grant.UID.String(), "", "admin", false},
+
+		"RoleListGet":          {"GET", "api/v1/role/", "", "all", true},
+		"RoleCreateUpdatePost": {"POST", "api/v1/role/", `{"name":"rbac-role"}`, "admin", false},
+		"RoleGet":              {"GET", "api/v1/role/rbac-role", "", "all", true},
+		"RoleDelete":           {"DELETE", "api/v1/role/rbac-role", "", "admin", false},
+
+		"UserTokenListGet":      {"GET", "api/v1/user/rbac-other/token/", "", "self", true},
+		"UserTokenCreatePost":   {"POST", "api/v1/user/rbac-other/token/", `{"name":"rbac"}`, "self", false},
+		"UserTokenRevokeDelete": {"DELETE", "api/v1/token/" + token.UID.String(), "", "self", false},
+
+		"UserSubscriptionListGet":    {"GET", "api/v1/user/rbac-other/subscription/", "", "self", true},
+		"UserSubscriptionCreatePost": {"POST", "api/v1/user/rbac-other/subscription/", `{"event":"application_allocated", "sink":"slack", "address":"https://hooks.slack.com/services/rbac"}`, "self", false},
+		"UserSubscriptionDelete":     {"DELETE", "api/v1/subscription/" + subscription.UID.String(), "", "self", false},
+
+		"LabelListGet":    {"GET", "api/v1/label/", "", "all", true},
+		"LabelCreatePost": {"POST", "api/v1/label/", labelBody, "operator", false},
+		"LabelStatsGet":   {"GET", "api/v1/label/stats", "", "operator", true},
+		"LabelGet":        {"GET", "api/v1/label/" + label.UID.String(), "", "all", true},
+		"LabelDelete":     {"DELETE", "api/v1/label/" + label.UID.String(), "", "operator", false},
+
+		"LabelApplyPost":  {"POST", "api/v1/label/apply", `{"labels":[], "dry_run":true}`, "operator", true},
+		"LabelExportGet":  {"GET", "api/v1/label/export?label=" + label.Name, "", "operator", true},
+		"LabelImportPost": {"POST", "api/v1/label/import?dry_run=true", `{"format":1, "labels":[], "images":[]}`, "operator", true},
+
+		"ImageListGet":      {"GET", "api/v1/image/", "", "all", true},
+		"ImageCreatePost":   {"POST", "api/v1/image/", `{"name":"rbac-image", "version":1, "identifiers":{"test":"rbac"}}`, "operator", false},
+		"ImageGet":          {"GET", imagePath, "", "all", true},
+		"ImageDeprecateGet": {"GET", imagePath + "/deprecate", "", "operator", false},
+		"ImageDelete":       {"DELETE", imagePath, "", "operator", false},
+
+		"ImageBuildListGet":    {"GET", "api/v1/image/build/", "", "operator", true},
+		"ImageBuildCreatePost": {"POST", "api/v1/image/build/", `{"name":"rbac-image", "version":1, "template":"rbac"}`, "operator", false},
+		"ImageBuildGet":        {"GET", "api/v1/image/build/" + uuid.NewString(), "", "operator", true},
+
+		"TemplateListGet":          {"GET", "api/v1/template/", "", "all", true},
+		"TemplateCreateUpdatePost": {"POST", "api/v1/template/", `{"name":"rbac-template"}`, "operator", false},
+		"TemplateGet":              {"GET", "api/v1/template/rbac-template", "", "all", true},
+		"TemplateDelete":           {"DELETE", "api/v1/template/rbac-template", "", "operator", false},
+
+		"ScheduleListGet":    {"GET", "api/v1/schedule/", "", "all", true},
+		"ScheduleCreatePost": {"POST", "api/v1/schedule/", `{"name":"rbac", "cron":"0 0 1 1 *", "label_UID":"` + label.UID.String() + `", "count":1}`, "all", false},
+		"ScheduleGet":        {"GET", schedulePath, "", "owner", true},
+		"ScheduleDelete":     {"DELETE", schedulePath, "", "owner", false},
+		"ScheduleEnableGet":  {"GET", schedulePath + "/enable", "", "owner", false},
+		"ScheduleDisableGet": {"GET", schedulePath + "/disable", "", "owner", false},
+		"ScheduleNextGet":    {"GET", schedulePath + "/next", "", "owner", true},
+		"ScheduleHistoryGet": {"GET", schedulePath + "/history", "", "owner", true},
+
+		"ResourceListGet":          {"GET", "api/v1/resource/", "", "operator", true},
+		"ResourceGet":              {"GET", "api/v1/resource/" + res.UID.String(), "", "operator", true},
+		"ResourceAccessPut":        {"GET", "api/v1/resource/" + res.UID.String() + "/access", "", "self", false},
+		"ResourceAccessMethodsGet": {"GET", "api/v1/resource/" + res.UID.String() + "/access_methods", "", "self", true},
+
+		"ApplicationListGet":              {"GET", "api/v1/application/", "", "all", true},
+		"ApplicationCreatePost":           {"POST", "api/v1/application/", `{"label_UID":"` + label.UID.String() + `"}`, "all", false},
+		"ApplicationBatchListGet":         {"GET", "api/v1/batch/", "", "all", true},
+		"ApplicationBatchCreatePost":      {"POST", "api/v1/batch/", `{"label_UID":"` + label.UID.String() + `", "count":1}`, "all", false},
+		"ApplicationBatchGet":             {"GET", "api/v1/batch/" + batch.UID.String(), "", "owner", true},
+		"ApplicationBatchStateGet":        {"GET", "api/v1/batch/" + batch.UID.String() + "/state", "", "owner", true},
+		"ApplicationGet":                  {"GET", appPath, "", "owner", true},
+		"ApplicationStateGet":             {"GET", appPath + "/state", "", "owner", true},
+		"ApplicationResourceGet":          {"GET", appPath + "/resource", "", "owner", true},
+		"ApplicationFullListGet":          {"GET", "api/v1/application/full", "", "all", true},
+		"ApplicationFullGet":              {"GET", appPath + "/full", "", "owner", true},
+		"ApplicationSecretCreatePost":     {"POST", appPath + "/secret/", `{"name":"rbac", "ciphertext":"cmJhYw=="}`, "owner", false},
+		"ApplicationTaskListGet":          {"GET", appPath + "/task/", "", "owner", true},
+		"ApplicationTaskCreatePost":       {"POST", appPath + "/task/", `{"task":"snapshot", "when":"DEALLOCATE"}`, "owner", false},
+		"ApplicationTaskGet":              {"GET", "api/v1/task/" + task.UID.String(), "", "owner", true},
+		"ApplicationDeallocateGet":        {"GET", appPath + "/deallocate", "", "owner", false},
+		"ApplicationDeallocateApproveGet": {"GET", appPath + "/deallocate/approve", "", "admin", false},
+
+		"ApplicationExtendPost": {"POST", appPath + "/extend", `{"duration":"1h"}`, "owner", false},
+
+		"ApplicationDeallocateBatchPost": {"POST", "api/v1/application/deallocate", `{"selector":{"owner_name":"rbac-nobody"}, "dry_run":true}`, "all", false},
+
+		"ApplicationStateStreamGet": {"GET", "api/v1/application/stream", "", "all", false},
+		"ApplicationApproveGet":     {"GET", appPath + "/approve", "", "approver", false},
+		"ApplicationRejectGet":      {"GET", appPath + "/reject", "", "approver", false},
+
+		"ApplicationAnnotationListGet": {"GET", appPath + "/annotation/", "", "owner", true},
+		"ApplicationTimelineGet":       {"GET", appPath + "/timeline", "", "owner", true},
+
+		"SyncPost": {"POST", "api/v1/sync/", `{"since":0}`, "admin", false},
+
+		"NodeListGet":                   {"GET", "api/v1/node/", "", "all", true},
+		"NodeCapacityGet":               {"GET", "api/v1/node/capacity", "", "all", true},
+		"NodeMaintenanceGet":            {"GET", "api/v1/node/" + node.UID.String() + "/maintenance", "", "operator", false},
+		"NodeDrainGet":                  {"GET", "api/v1/node/" + node.UID.String() + "/drain", "", "all", true},
+		"NodeTimelineGet":               {"GET", "api/v1/node/" + node.UID.String() + "/timeline", "", "operator", true},
+		"NodeThisGet":                   {"GET", "api/v1/node/this/", "", "all", true},
+		"NodeThisMaintenanceGet":        {"GET", "api/v1/node/this/maintenance?enable=false", "", "operator", false},
+		"NodeThisDriverRestartGet":      {"GET", "api/v1/node/this/driver/restart?name=test", "", "operator", false},
+		"NodeThisDriverInfoSchemaGet":   {"GET", "api/v1/node/this/driver/info_schema?name=test", "", "all", true},
+		"NodeThisDriverCapabilitiesGet": {"GET", "api/v1/node/this/driver/capabilities?name=test", "", "all", true},
+		"NodeThisDriverForecastGet":     {"GET", "api/v1/node/this/driver/forecast?name=test", "", "all", true},
+		"NodeThisLabelCompatibilityGet": {"GET", "api/v1/node/this/label_compatibility", "", "all", true},
+		"NodeThisCapacityGet":           {"GET", "api/v1/node/this/capacity", "", "all", true},
+		"NodeThisBundleGet":             {"GET", "api/v1/node/this/bundle", "", "admin", true},
+		"NodeThisProfilingIndexGet":     {"GET", "api/v1/node/this/profiling/", "", "admin", true},
+		"NodeThisProfilingGet":          {"GET", "api/v1/node/this/profiling/heap", "", "admin", false},
+
+		"NodeThisBackupListGet":    {"GET", "api/v1/node/this/backup/", "", "admin", true},
+		"NodeThisBackupCreatePost": {"POST", "api/v1/node/this/backup/", "", "admin", false},
+		"NodeThisBackupGet":        {"GET", "api/v1/node/this/backup/fish-backup-20240101T000000.000Z.db", "", "admin", true},
+
+		"UpgradeListGet":    {"GET", "api/v1/upgrade/", "", "all", true},
+		"UpgradeCreatePost": {"POST", "api/v1/upgrade/", `{"version":"v99.0.0"}`, "operator", false},
+		"UpgradeGet":        {"GET", upgradePath, "", "all", true},
+		"UpgradePauseGet":   {"GET", upgradePath + "/pause", "", "operator", false},
+		"UpgradeResumeGet":  {"GET", upgradePath + "/resume", "", "operator", false},
+		"UpgradeAbortGet":   {"GET", upgradePath + "/abort", "", "operator", false},
+
+		"ProjectListGet":          {"GET", "api/v1/project/", "", "all", true},
+		"ProjectCreateUpdatePost": {"POST", "api/v1/project/", `{"name":"rbac-project"}`, "admin", false},
+		"ProjectGet":              {"GET", "api/v1/project/rbac-project", "", "owner", true},
+		"ProjectDelete":           {"DELETE", "api/v1/project/rbac-project", "", "admin", false},
+
+		"LocationListGet":    {"GET", "api/v1/location/", "", "operator", true},
+		"LocationCreatePost": {"POST", "api/v1/location/", `{"name":"rbac-loc"}`, "operator", false},
+
+		"ServiceMappingListGet":    {"GET", "api/v1/servicemapping/", "", "operator", true},
+		"ServiceMappingCreatePost": {"POST", "api/v1/servicemapping/", `{"application_UID":"` + app.UID.String() + `", "location_name":"test_loc", "service":"rbac.com", "redirect":"mirror.rbac.com"}`, "owner", false},
+		"ServiceMappingGet":        {"GET", "api/v1/servicemapping/" + mapping.UID.String(), "", "operator", true},
+		"ServiceMappingDelete":     {"DELETE", "api/v1/servicemapping/" + mapping.UID.String(), "", "operator", false},
+
+		"AuditRecordListGet":   {"GET", "api/v1/audit/", "", "auditor", true},
+		"AuditRecordStreamGet": {"GET", "api/v1/audit/stream", "", "auditor", false},
+
+		"SchedulerSharesGet": {"GET", "api/v1/scheduler/shares/", "", "operator", true},
+
+		"SimulatorDedicatedPost": {"POST", "api/v1/simulator/dedicated", `{"workload":[{"start":"2024-01-01T00:00:00Z", "duration":"1h"}], "pools":[{"name":"rbac", "max":1}]}`, "operator", true},
+	}
+
+	t.Run("Permission matrix covers all the API operations", func(t *testing.T) {
+		served, unrouted := rbacServedOperations(t)
+		for _, op := range unrouted {
+			t.Errorf("Operation %s tags are not in the API config include-tags", op)
+		}
+		for _, op := range served {
+			if _, ok := operations[op]; !ok {
+				t.Errorf("Operation %s is not in the RBAC matrix", op)
+			}
+		}
+		for op := range operations {
+			if !slices.Contains(served, op) {
+				t.Errorf("Operation %s is in the RBAC matrix, but not served", op)
+			}
+		}
+	})
+
+	t.Run("Runtime permissions are matching the matrix", func(t *testing.T) {
+		for _, u := range users {
+			var perms []types.OperationPermission
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/user/me/permissions")).
+				BasicAuth(u.name, u.password).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&perms)
+
+			if len(perms) != len(operations) {
+				t.Errorf("User %s got %d permissions, expected %d", u.name, len(perms), len(operations))
+			}
+			for _, p := range perms {
+				op, ok := operations[p.Operation]
+				if !ok {
+					t.Errorf("Unknown operation %s in the User %s permissions", p.Operation, u.name)
+					continue
+				}
+				own := op.allowed(u.role) || op.access == "owner" || op.access == "self" || op.access == "own"
+				if p.Allowed != op.allowed(u.role) || p.Own != own {
+					t.Errorf("User %s permission for %s is %v/%v, expected %v/%v", u.name, p.Operation, p.Allowed, p.Own, op.allowed(u.role), own)
+				}
+			}
+		}
+	})
+
+	call := func(t *testing.T, u rbacUser, op rbacOperation) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(op.method, afi.APIAddress(op.path), strings.NewReader(op.body))
+		if err != nil {
+			t.Fatalf("Unable to create request: %v", err)
+		}
+		req.SetBasicAuth(u.name, u.password)
+		if op.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("Unable to do request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		var out struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &out)
+		return resp.StatusCode, out.Message
+	}
+
+	for _, u := range users {
+		t.Run("Matrix for "+u.role, func(t *testing.T) {
+			for name, op := range operations {
+				allowed := op.allowed(u.role)
+				if allowed && !op.safe {
+					continue
+				}
+				status, message := call(t, u, op)
+				denied := status == http.StatusBadRequest && strings.HasPrefix(message, "Only")
+				if denied == allowed {
+					t.Errorf("User %s call %s %s: expected allowed %v, got %d: %s", u.name, name, op.path, allowed, status, message)
+				}
+			}
+		})
+	}
+}
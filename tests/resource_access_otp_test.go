@@ -0,0 +1,374 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"golang.org/x/net/websocket"
+
+	"github.com/adobe/aquarium-fish/lib/crypt"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Secret to sign the web terminal tokens
+const otpJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// Checks the Resource access of the sensitive Label requires OTP:
+// * Access without OTP is denied
+// * User enrolls OTP, the secret is encrypted at rest
+// * User gets the access with the valid code
+// * The same code can't be used twice
+// * Proxy and web terminal handshakes without OTP are denied
+// * User can't re-enroll OTP without admin reset
+// WARN: This test requires `sh` binary to be available in PATH
+func Test_resource_access_otp(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test
+
+gates:
+  - name: terminal
+    cfg:
+      address: 127.0.0.1:0
+      jwt_secret: `+otpJWTSecret)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	// Target for the proxy and the terminal
+	_, sshdPort := h.MockSSHPtyServer(t, "testuser", "testpass", "")
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "access_otp":true, "definitions": [{
+				"driver":"test",
+				"resources":{"cpu":1,"ram":2},
+				"authentication":{"username":"testuser","password":"testpass","port":`+sshdPort+`}
+			}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	var res types.Resource
+	t.Run("Resource should be created", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/resource")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&res)
+
+		if res.Identifier == "" {
+			t.Fatalf("Resource identifier is incorrect: %v", res.Identifier)
+		}
+	})
+
+	t.Run("Access without OTP is denied", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var secret string
+	t.Run("User enrolls OTP", func(t *testing.T) {
+		var otp types.UserOTP
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/otp")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&otp)
+
+		u, err := url.Parse(otp.Uri)
+		if err != nil {
+			t.Fatalf("Unable to parse OTP URI: %v", err)
+		}
+		secret = u.Query().Get("secret")
+		if secret == "" {
+			t.Fatalf("OTP URI has no secret: %s", otp.Uri)
+		}
+	})
+
+	t.Run("OTP secret is not stored in plaintext", func(t *testing.T) {
+		files, _ := filepath.Glob(filepath.Join(afi.Workspace(), "fish_data", "*", "sqlite.db*"))
+		if len(files) == 0 {
+			t.Fatalf("Unable to find the database files")
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Unable to read database file %s: %v", path, err)
+			}
+			if bytes.Contains(data, []byte(secret)) {
+				t.Fatalf("OTP secret is stored in plaintext in %s", path)
+			}
+		}
+	})
+
+	t.Run("User can't re-enroll OTP", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/otp")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Access with wrong OTP is denied", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access")).
+			Query("otp", "000000x").
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	// The codes of the previous, current and next periods are valid, so the test uses them in
+	// order to not wait for the next period
+	var counter int64
+	var code string
+	var acc types.ResourceAccess
+	t.Run("Access with valid OTP is allowed", func(t *testing.T) {
+		counter = crypt.TOTPCounter(time.Now())
+		code, _ = crypt.TOTPCode(secret, counter-1)
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access")).
+			Query("otp", code).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&acc)
+
+		if acc.Password == "" {
+			t.Fatalf("ResourceAccess password is empty")
+		}
+	})
+
+	t.Run("The same OTP can't be used twice", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access")).
+			Query("otp", code).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Proxy denies access without OTP", func(t *testing.T) {
+		if err := h.DialSSH(afi.ProxySSHEndpoint(), acc.Username, acc.Password, ""); err == nil {
+			t.Fatalf("PROXYSSH allowed access without OTP")
+		}
+	})
+
+	t.Run("Proxy allows access with OTP", func(t *testing.T) {
+		code, _ = crypt.TOTPCode(secret, counter)
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access")).
+			Query("otp", code).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&acc)
+
+		code, _ = crypt.TOTPCode(secret, counter+1)
+		if err := h.DialSSH(afi.ProxySSHEndpoint(), acc.Username, acc.Password, code); err != nil {
+			t.Fatalf("PROXYSSH denied access with OTP: %v", err)
+		}
+	})
+
+	var terminalURL string
+	t.Run("Terminal is in the access methods", func(t *testing.T) {
+		var methods []types.AccessMethod
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/resource/"+res.UID.String()+"/access_methods")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&methods)
+
+		for _, m := range methods {
+			if m.Type == "terminal" {
+				terminalURL = m.Endpoint
+			}
+		}
+		if terminalURL == "" {
+			t.Fatalf("Terminal access method is not found: %v", methods)
+		}
+	})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Subject:   "test-user",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(otpJWTSecret))
+	if err != nil {
+		t.Fatalf("Unable to sign the terminal token: %v", err)
+	}
+
+	t.Run("Terminal denies access without OTP", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(strings.Replace(terminalURL, "wss://", "https://", 1)).
+			Query("access_token", token).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("Terminal denies access with used OTP", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(strings.Replace(terminalURL, "wss://", "https://", 1)).
+			Query("access_token", token).
+			Query("otp", code).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("Terminal allows access with OTP", func(t *testing.T) {
+		// The rest of the valid codes are already used, so waiting for the next period
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if crypt.TOTPCounter(time.Now()) <= counter {
+				r.Fatalf("Waiting for the next OTP period")
+			}
+		})
+		code, _ = crypt.TOTPCode(secret, counter+2)
+		u, err := url.Parse(terminalURL)
+		if err != nil {
+			t.Fatalf("Unable to parse terminal URL: %v", err)
+		}
+		u.RawQuery = url.Values{"access_token": {token}, "otp": {code}}.Encode()
+		cfg, err := websocket.NewConfig(u.String(), "https://"+u.Host)
+		if err != nil {
+			t.Fatalf("Unable to create websocket config: %v", err)
+		}
+		cfg.TlsConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 , tests need to be simple
+		ws, err := websocket.DialConfig(cfg)
+		if err != nil {
+			t.Fatalf("Terminal denied access with OTP: %v", err)
+		}
+		ws.Close()
+	})
+
+	t.Run("Admin resets the User OTP", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi.APIAddress("api/v1/user/test-user/otp")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
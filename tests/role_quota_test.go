@@ -0,0 +1,287 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the Role group quota and the quota enforcement on election:
+// * Role group quota limits the Applications of all the granted Users together
+// * User can see the group quota usage
+// * Application waiting for approval is not allocated when the lowered User quota is used up
+// * Application is allocated when the quota is released
+func Test_role_quota(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	labels := map[string]types.Label{}
+	t.Run("Create Labels", func(t *testing.T) {
+		for name, approval := range map[string]bool{"test-label": false, "approval-label": true} {
+			var label types.Label
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(map[string]any{"name": name, "version": 1, "requires_approval": approval,
+					"definitions": []any{map[string]any{"driver": "test", "resources": map[string]any{"cpu": 2, "ram": 4}}}}).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&label)
+
+			if label.UID == uuid.Nil {
+				t.Fatalf("Label UID is incorrect: %v", label.UID)
+			}
+			labels[name] = label
+		}
+	})
+
+	t.Run("Create Users", func(t *testing.T) {
+		for _, name := range []string{"team-user-1", "team-user-2", "solo-user"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"`+name+`-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Admin creates the team role with group quota and grants it", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/role/")).
+			JSON(`{"name":"team", "max_applications":1}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		for _, name := range []string{"team-user-1", "team-user-2"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/"+name+"/grant/")).
+				JSON(map[string]any{"role": "team", "expires_at": time.Now().Add(time.Hour)}).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("First team User creates Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["test-label"].UID.String()+`"}`).
+			BasicAuth("team-user-1", "team-user-1-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Second team User can't create Application over group quota", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["test-label"].UID.String()+`"}`).
+			BasicAuth("team-user-2", "team-user-2-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			Body(`{"message":"Unable to create application: Fish: Role \"team\" quota exceeded: max 1 active Applications, used 1"}`).
+			End()
+	})
+
+	t.Run("Second team User can see the group quota usage", func(t *testing.T) {
+		var usage types.QuotaUsage
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/team-user-2/quota")).
+			BasicAuth("team-user-2", "team-user-2-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&usage)
+
+		if usage.Applications != 0 || len(usage.Roles) != 1 {
+			t.Fatalf("Wrong quota usage: %+v", usage)
+		}
+		if r := usage.Roles[0]; r.Role.Name != "team" || r.Applications != 1 || r.Cpu != 2 || r.Ram != 4 {
+			t.Fatalf("Wrong group quota usage: %+v", r)
+		}
+	})
+
+	var app types.Application
+	t.Run("Solo User creates Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["test-label"].UID.String()+`"}`).
+			BasicAuth("solo-user", "solo-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Solo User Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("solo-user", "solo-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	var waiting types.Application
+	t.Run("Solo User creates Application waiting for approval", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["approval-label"].UID.String()+`"}`).
+			BasicAuth("solo-user", "solo-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&waiting)
+
+		if waiting.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", waiting.UID)
+		}
+	})
+
+	t.Run("Admin lowers the User quota and approves the Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/solo-user/quota")).
+			JSON(`{"max_applications":1}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+waiting.UID.String()+"/approve")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Approved Application is not allocated over the quota", func(t *testing.T) {
+		time.Sleep(5 * time.Second)
+
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+waiting.UID.String()+"/state")).
+			BasicAuth("solo-user", "solo-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusNEW {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Deallocate the first Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("solo-user", "solo-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Approved Application should get ALLOCATED in 40 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+waiting.UID.String()+"/state")).
+				BasicAuth("solo-user", "solo-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("User quota usage counts the allocation hours", func(t *testing.T) {
+		var usage types.QuotaUsage
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/solo-user/quota")).
+			BasicAuth("solo-user", "solo-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&usage)
+
+		if usage.Applications != 1 || usage.Cpu != 2 || usage.Hours <= 0 {
+			t.Fatalf("Wrong quota usage: %+v", usage)
+		}
+	})
+}
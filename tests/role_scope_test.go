@@ -0,0 +1,215 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the custom role restricts the Labels and the node locations of the granted Users:
+// * User without custom roles is not restricted
+// * User can create Application only with the Labels allowed by the role
+// * Node out of the allowed locations is not allocating the User Application
+// * Application is allocated when the node location is allowed
+// * Granted role can't be deleted
+func Test_role_scope(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	labels := map[string]types.Label{}
+	t.Run("Create Labels", func(t *testing.T) {
+		for _, name := range []string{"team-ios-mac", "team-android-linux"} {
+			var label types.Label
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/label/")).
+				JSON(`{"name":"`+name+`", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&label)
+
+			if label.UID == uuid.Nil {
+				t.Fatalf("Label UID is incorrect: %v", label.UID)
+			}
+			labels[name] = label
+		}
+	})
+
+	t.Run("Create Users", func(t *testing.T) {
+		for _, name := range []string{"test-user", "other-user"} {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"`+name+`-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+
+	t.Run("Admin creates the role restricting Labels and locations", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/role/")).
+			JSON(`{"name":"team-ios", "labels":["team-ios-*"], "locations":["other_loc"]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Admin grants the role to User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/grant/")).
+			JSON(map[string]any{"role": "team-ios", "expires_at": time.Now().Add(time.Hour)}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User without the role is not restricted", func(t *testing.T) {
+		var other types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["team-android-linux"].UID.String()+`"}`).
+			BasicAuth("other-user", "other-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&other)
+
+		if other.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", other.UID)
+		}
+	})
+
+	t.Run("User can't create Application with not allowed Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["team-android-linux"].UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			Body(`{"message":"Unable to create application: Fish: Roles restrict Label \"team-android-linux\", allowed: [team-ios-*]"}`).
+			End()
+	})
+
+	var app types.Application
+	t.Run("User creates Application with allowed Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+labels["team-ios-mac"].UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application is not allocated out of allowed locations", func(t *testing.T) {
+		time.Sleep(5 * time.Second)
+
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+
+		if appState.Status != types.ApplicationStatusNEW {
+			t.Fatalf("Application Status is incorrect: %v", appState.Status)
+		}
+	})
+
+	t.Run("Admin allows the node location", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/role/")).
+			JSON(`{"name":"team-ios", "labels":["team-ios-*"], "locations":["test_*"]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Application should get ALLOCATED in 40 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("test-user", "test-user-password").
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Granted role can't be deleted", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi.APIAddress("api/v1/role/team-ios")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,196 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the fair share scheduler serves first the owner using less:
+// * User A fills the node with 2 Applications
+// * User A and then User B request one more Application
+// * Destroying one Application of User A
+// * User B Application is allocated, the User A one is still waiting
+// * Scheduler shares shows the usage of the owners
+func Test_scheduler_fair_share(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+scheduler: fair_share
+
+drivers:
+  - name: test
+    cfg:
+      cpu_limit: 8
+      ram_limit: 16`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":4,"ram":8}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	for _, name := range []string{"user-a", "user-b"} {
+		t.Run("Create "+name, func(t *testing.T) {
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/")).
+				JSON(`{"name":"`+name+`", "password":"`+name+`-password"}`).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		})
+	}
+
+	createApp := func(t *testing.T, user string) (app types.Application) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth(user, user+"-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+		return app
+	}
+	appStatus := func(r apitest.TestingT, app types.Application) types.ApplicationStatus {
+		var appState types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(r).
+			Status(http.StatusOK).
+			End().
+			JSON(&appState)
+		return appState.Status
+	}
+
+	var appA1, appA2 types.Application
+	t.Run("User A fills the node", func(t *testing.T) {
+		appA1 = createApp(t, "user-a")
+		appA2 = createApp(t, "user-a")
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if status := appStatus(r, appA1); status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application A1 Status is incorrect: %v", status)
+			}
+			if status := appStatus(r, appA2); status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application A2 Status is incorrect: %v", status)
+			}
+		})
+	})
+
+	var appA3, appB1 types.Application
+	t.Run("User A and User B request one more Application", func(t *testing.T) {
+		appA3 = createApp(t, "user-a")
+		appB1 = createApp(t, "user-b")
+	})
+
+	t.Run("Deallocate the Application A1", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+appA1.UID.String()+"/deallocate")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User B Application should get ALLOCATED in 40 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 40 * time.Second, Wait: 5 * time.Second}, t, func(r *h.R) {
+			if status := appStatus(r, appB1); status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application B1 Status is incorrect: %v", status)
+			}
+		})
+	})
+
+	t.Run("User A Application should still be NEW", func(t *testing.T) {
+		if status := appStatus(t, appA3); status != types.ApplicationStatusNEW {
+			t.Fatalf("Application A3 Status is incorrect: %v", status)
+		}
+	})
+
+	t.Run("User can't get scheduler shares", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/scheduler/shares/")).
+			BasicAuth("user-a", "user-a-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Scheduler shares show the owners usage", func(t *testing.T) {
+		var shares []types.SchedulerShare
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/scheduler/shares/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&shares)
+
+		if len(shares) != 2 {
+			t.Fatalf("Wrong number of shares: %+v", shares)
+		}
+		if shares[0].OwnerName != "user-a" || shares[0].Allocated != 1 || shares[0].Waiting != 1 {
+			t.Fatalf("Wrong User A share: %+v", shares[0])
+		}
+		if shares[1].OwnerName != "user-b" || shares[1].Allocated != 1 || shares[1].Share != 0.5 {
+			t.Fatalf("Wrong User B share: %+v", shares[1])
+		}
+	})
+}
@@ -0,0 +1,199 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the dedicated pool simulator:
+// * Simulate the provided workload profile with 2 pool settings
+// * Replay the recorded Application as the workload
+// * Regular user can't run the simulator
+func Test_simulator_dedicated(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Simulate the workload profile", func(t *testing.T) {
+		var results []types.SimulatorResult
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/simulator/dedicated")).
+			JSON(`{"workload":[
+				{"start":"2024-01-01T10:00:00Z", "duration":"2h"},
+				{"start":"2024-01-01T10:00:00Z", "duration":"2h"}
+			], "pools":[
+				{"name":"one", "max":1, "host_hourly_price":1},
+				{"name":"two", "max":2, "host_hourly_price":1}
+			]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&results)
+
+		if len(results) != 2 {
+			t.Fatalf("Expected 2 results: %v", results)
+		}
+		if results[0].Name != "one" || results[0].HostsMax != 1 || results[0].QueueWaitMax <= 0 {
+			t.Fatalf("Single host pool result is incorrect: %v", results[0])
+		}
+		if results[1].Name != "two" || results[1].HostsMax != 2 || results[1].QueueWaitMax != 0 {
+			t.Fatalf("Two hosts pool result is incorrect: %v", results[1])
+		}
+		if len(results[1].Months) != 1 || results[1].Months[0].Month != "2024-01" || results[1].Cost <= results[0].Cost {
+			t.Fatalf("Two hosts pool cost is incorrect: %v", results[1])
+		}
+	})
+
+	t.Run("Empty workload is not allowed", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/simulator/dedicated")).
+			JSON(`{"pools":[{"name":"one", "max":1}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	t.Run("Replay the recorded Applications", func(t *testing.T) {
+		var results []types.SimulatorResult
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/simulator/dedicated")).
+			JSON(`{"replay_weeks":1, "label_name":"test-label", "pools":[{"name":"one", "max":1}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&results)
+
+		if len(results) != 1 || results[0].HostsMax != 1 || results[0].InstancesMax != 1 {
+			t.Fatalf("Replay result is incorrect: %v", results)
+		}
+	})
+
+	t.Run("Replay of the other Label has no workload", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/simulator/dedicated")).
+			JSON(`{"replay_weeks":1, "label_name":"other-label", "pools":[{"name":"one", "max":1}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Regular user can't run the simulator", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/simulator/dedicated")).
+			JSON(`{"replay_weeks":1, "pools":[{"name":"one", "max":1}]}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
@@ -0,0 +1,298 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Ensure the central node resolves the edge nodes sync conflicts by the rules:
+// * Label with the same name and version is immutable
+// * The newer User update wins and the User delete is synced
+// * Application short ID collision is resolved by the new short ID
+// * Application terminal state can't be overridden by the active one
+// * Edge node receives the changes of the others, but not its own ones
+func Test_sync_edge_conflicts(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+sync_edges: true
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	emptyData := func() types.SyncData {
+		return types.SyncData{
+			Labels:            []types.Label{},
+			Users:             []types.User{},
+			Applications:      []types.Application{},
+			ApplicationStates: []types.ApplicationState{},
+			DeletedUsers:      []string{},
+		}
+	}
+	syncPost := func(t *testing.T, node string, since int64, data types.SyncData) (out types.SyncResponse) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/sync/")).
+			JSON(types.SyncRequest{NodeName: node, Since: since, Changes: data}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&out)
+		return out
+	}
+	hasConflict := func(conflicts []string, substr string) bool {
+		for _, c := range conflicts {
+			if strings.Contains(c, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	t.Run("Edge Label with the same name and version is rejected", func(t *testing.T) {
+		edgeLabel := label
+		edgeLabel.UID = uuid.New()
+		data := emptyData()
+		data.Labels = append(data.Labels, edgeLabel)
+		out := syncPost(t, "edge-1", 0, data)
+
+		if !hasConflict(out.Conflicts, "Label test-label:1 already exists") {
+			t.Fatalf("Expected Label conflict: %v", out.Conflicts)
+		}
+	})
+
+	var user types.User
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"sync-user", "password":"sync-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/sync-user")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&user)
+	})
+
+	t.Run("Older edge User update is ignored", func(t *testing.T) {
+		edgeUser := user
+		edgeUser.UpdatedAt = user.UpdatedAt.Add(-time.Hour)
+		data := emptyData()
+		data.Users = append(data.Users, edgeUser)
+		syncPost(t, "edge-1", 0, data)
+
+		var got types.User
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/sync-user")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&got)
+
+		if !got.UpdatedAt.Equal(user.UpdatedAt) {
+			t.Fatalf("User was updated by the older change: %v != %v", got.UpdatedAt, user.UpdatedAt)
+		}
+	})
+
+	t.Run("Newer edge User update wins", func(t *testing.T) {
+		edgeUser := user
+		edgeUser.UpdatedAt = user.UpdatedAt.Add(time.Hour)
+		data := emptyData()
+		data.Users = append(data.Users, edgeUser)
+		syncPost(t, "edge-1", 0, data)
+
+		var got types.User
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/sync-user")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&got)
+
+		if !got.UpdatedAt.Equal(edgeUser.UpdatedAt) {
+			t.Fatalf("User was not updated by the newer change: %v != %v", got.UpdatedAt, edgeUser.UpdatedAt)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	edgeApp := app
+	t.Run("Edge Application with taken short ID gets the new one", func(t *testing.T) {
+		edgeApp = app
+		edgeApp.UID = uuid.New()
+		edgeApp.CreatedAt = time.Now()
+		data := emptyData()
+		data.Applications = append(data.Applications, edgeApp)
+		data.ApplicationStates = append(data.ApplicationStates, types.ApplicationState{
+			UID: uuid.New(), CreatedAt: time.Now(), ApplicationUID: edgeApp.UID,
+			Status: types.ApplicationStatusDEALLOCATED, Description: "Deallocated on edge",
+		})
+		out := syncPost(t, "edge-1", 0, data)
+
+		if !hasConflict(out.Conflicts, "short ID "+app.ShortId+" is taken") {
+			t.Fatalf("Expected Application short ID conflict: %v", out.Conflicts)
+		}
+
+		var got types.Application
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+edgeApp.UID.String())).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&got)
+
+		if got.ShortId == "" || got.ShortId == app.ShortId {
+			t.Fatalf("Application short ID was not changed: %q", got.ShortId)
+		}
+	})
+
+	t.Run("Active state can't override the terminal one", func(t *testing.T) {
+		data := emptyData()
+		data.ApplicationStates = append(data.ApplicationStates, types.ApplicationState{
+			UID: uuid.New(), CreatedAt: time.Now(), ApplicationUID: edgeApp.UID,
+			Status: types.ApplicationStatusALLOCATED, Description: "Late allocate",
+		})
+		out := syncPost(t, "edge-1", 0, data)
+
+		if !hasConflict(out.Conflicts, "is already DEALLOCATED") {
+			t.Fatalf("Expected Application state conflict: %v", out.Conflicts)
+		}
+
+		var state types.ApplicationState
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+edgeApp.UID.String()+"/state")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&state)
+
+		if state.Status != types.ApplicationStatusDEALLOCATED {
+			t.Fatalf("Application state was overridden: %v", state.Status)
+		}
+	})
+
+	t.Run("Edge User delete is synced", func(t *testing.T) {
+		data := emptyData()
+		data.DeletedUsers = append(data.DeletedUsers, "sync-user")
+		syncPost(t, "edge-1", 0, data)
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/sync-user")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("Other edge receives the changes", func(t *testing.T) {
+		out := syncPost(t, "edge-2", 0, emptyData())
+
+		found := false
+		for _, a := range out.Changes.Applications {
+			found = found || a.UID == edgeApp.UID
+		}
+		if !found {
+			t.Fatalf("Edge Application was not received by the other edge: %v", out.Changes.Applications)
+		}
+		if len(out.Changes.DeletedUsers) != 1 || out.Changes.DeletedUsers[0] != "sync-user" {
+			t.Fatalf("User delete was not received by the other edge: %v", out.Changes.DeletedUsers)
+		}
+	})
+
+	t.Run("Edge doesn't receive its own changes", func(t *testing.T) {
+		out := syncPost(t, "edge-1", 0, emptyData())
+
+		for _, a := range out.Changes.Applications {
+			if a.UID == edgeApp.UID {
+				t.Fatalf("Edge received its own Application back: %v", a.UID)
+			}
+		}
+	})
+}
@@ -0,0 +1,222 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/notify"
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the User receives the lifecycle notifications by webhook:
+// * User can't subscribe to not allowed webhook or to node_down event
+// * User subscribes to the Application and quota events
+// * Notifications are received when the Application is allocated, deallocated and over quota
+func Test_user_notification(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var received []notify.Notification
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notify.Notification
+		json.NewDecoder(r.Body).Decode(&n)
+		mu.Lock()
+		received = append(received, n)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+	events := func() (out []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, n := range received {
+			out = append(out, n.Event)
+		}
+		return out
+	}
+
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+notifications:
+  webhook_allow:
+    - `+hook.URL+`/
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User can't subscribe to not allowed webhook", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/subscription/")).
+			JSON(`{"event":"application_allocated", "sink":"webhook", "address":"http://169.254.169.254/latest"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("User can't subscribe to node_down", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/subscription/")).
+			JSON(`{"event":"node_down", "sink":"webhook", "address":"`+hook.URL+`/"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("User subscribes to the events", func(t *testing.T) {
+		for _, event := range []string{notify.EventApplicationAllocated, notify.EventApplicationDeallocated, notify.EventQuotaExceeded} {
+			var sub types.Subscription
+			apitest.New().
+				EnableNetworking(cli).
+				Post(afi.APIAddress("api/v1/user/test-user/subscription/")).
+				JSON(`{"event":"`+event+`", "sink":"webhook", "address":"`+hook.URL+`/"}`).
+				BasicAuth("test-user", "test-user-password").
+				Expect(t).
+				Status(http.StatusOK).
+				End().
+				JSON(&sub)
+
+			if sub.UID == uuid.Nil || sub.UserName != "test-user" {
+				t.Fatalf("Subscription is incorrect: %v", sub)
+			}
+		}
+	})
+
+	t.Run("Admin sets the User quota", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/test-user/quota")).
+			JSON(`{"max_applications":1}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Notification about allocation should be received in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if got := events(); len(got) != 1 || got[0] != notify.EventApplicationAllocated {
+				r.Fatalf("Received notifications are incorrect: %v", got)
+			}
+		})
+	})
+
+	t.Run("Second Application is over quota", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+
+		h.Retry(&h.Timer{Timeout: 5 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if got := events(); len(got) != 2 || got[1] != notify.EventQuotaExceeded {
+				r.Fatalf("Received notifications are incorrect: %v", got)
+			}
+		})
+	})
+
+	t.Run("Deallocate the Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/deallocate")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Notification about deallocation should be received in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			if got := events(); len(got) != 3 || got[2] != notify.EventApplicationDeallocated {
+				r.Fatalf("Received notifications are incorrect: %v", got)
+			}
+		})
+	})
+}
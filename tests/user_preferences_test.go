@@ -0,0 +1,212 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the User preferences are stored per User:
+// * User stores the preference and gets it back
+// * Other User can't see the preference
+// * Wrong key, wrong value and values over the limits are rejected
+// * Removed preference is not available anymore
+func Test_user_preferences(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+limits:
+  preference: 1KB
+  preferences: 2
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User stores the preference", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/ui.filters")).
+			JSON(`{"value":{"application":"owner_name = 'test-user'"}}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User gets the preference", func(t *testing.T) {
+		var pref types.Preference
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/preferences/ui.filters")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&pref)
+
+		if pref.UserName != "test-user" || pref.Key != "ui.filters" {
+			t.Fatalf("Preference is incorrect: %v", pref)
+		}
+		if !strings.Contains(string(pref.Value), "owner_name") {
+			t.Fatalf("Preference value is incorrect: %s", pref.Value)
+		}
+	})
+
+	t.Run("Other User can't see the preference", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/preferences/ui.filters")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+
+		var prefs []types.Preference
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/preferences/")).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&prefs)
+
+		if len(prefs) != 0 {
+			t.Fatalf("Admin should not have preferences: %v", prefs)
+		}
+	})
+
+	t.Run("Wrong key is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/ui:filters")).
+			JSON(`{"value":true}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Empty value is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/ui.layout")).
+			JSON(`{}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Value over the limit is rejected", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/ui.layout")).
+			JSON(`{"value":"`+strings.Repeat("a", 2048)+`"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			Body(`{"field":"value", "limit":"1KB", "message":"Unable to set preference: Fish: Preference size 2050B exceeds the limit 1KB"}`).
+			End()
+	})
+
+	t.Run("Number of preferences is limited", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/cli.label")).
+			JSON(`{"value":"test-label"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/ui.layout")).
+			JSON(`{"value":["apps","nodes"]}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+
+		// Existing preference still could be updated
+		apitest.New().
+			EnableNetworking(cli).
+			Put(afi.APIAddress("api/v1/user/me/preferences/cli.label")).
+			JSON(`{"value":"other-label"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("User removes the preference", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi.APIAddress("api/v1/user/me/preferences/ui.filters")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		var prefs []types.Preference
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/preferences/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&prefs)
+
+		if len(prefs) != 1 || prefs[0].Key != "cli.label" {
+			t.Fatalf("Preferences are incorrect: %v", prefs)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Ensure the User API token works instead of password within the scopes till revoked or expired,
+// the regular password looking like token is still working
+func Test_user_token(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	t.Run("Create User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"test-user", "password":"test-user-password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	var token types.UserToken
+	var secret string
+	t.Run("User creates the token", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/token/")).
+			JSON(`{"name":"ci"}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&token)
+
+		if token.UID == uuid.Nil || token.Token == nil || *token.Token == "" {
+			t.Fatalf("UserToken is incorrect: %v", token)
+		}
+		secret = *token.Token
+		if token.CreatedBy != "test-user" || token.UserName != "test-user" {
+			t.Fatalf("UserToken owner is incorrect: %v, %v", token.UserName, token.CreatedBy)
+		}
+	})
+
+	t.Run("Token works instead of password", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/")).
+			BasicAuth("test-user", secret).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Token is not working for the other User", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/")).
+			BasicAuth("admin", secret).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	var tokens []types.UserToken
+	t.Run("Token list shows last usage without secrets", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/test-user/token/")).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&tokens)
+
+		if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+			t.Fatalf("UserToken list is incorrect: %v", tokens)
+		}
+		if tokens[0].Token != nil || len(tokens[0].Hash.Hash) != 0 {
+			t.Fatalf("UserToken list contains secrets: %v", tokens[0])
+		}
+	})
+
+	t.Run("Token with unknown scope can't be created", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/token/")).
+			JSON(`{"name":"wrong", "scopes":["NotExistingOperation"]}`).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	var scoped types.UserToken
+	t.Run("Admin creates scoped token for service account", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/token/")).
+			JSON(map[string]any{"name": "labels", "scopes": []string{"Label", "UserTokenCreatePost"}, "expires_at": time.Now().Add(10 * time.Second)}).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&scoped)
+
+		if scoped.CreatedBy != "admin" || scoped.UserName != "test-user" {
+			t.Fatalf("UserToken owner is incorrect: %v, %v", scoped.UserName, scoped.CreatedBy)
+		}
+	})
+
+	t.Run("Scoped token allows only the listed services", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/label/")).
+			BasicAuth("test-user", *scoped.Token).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/application/")).
+			BasicAuth("test-user", *scoped.Token).
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+
+	t.Run("Scoped token can't create the token with wider scopes", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/token/")).
+			JSON(`{"name":"wider"}`).
+			BasicAuth("test-user", *scoped.Token).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/test-user/token/")).
+			JSON(`{"name":"wider", "scopes":["Application"]}`).
+			BasicAuth("test-user", *scoped.Token).
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Scoped token is not working when expired", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 20 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/label/")).
+				BasicAuth("test-user", *scoped.Token).
+				Expect(r).
+				Status(http.StatusUnauthorized).
+				End()
+		})
+	})
+
+	t.Run("User revokes the token", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Delete(afi.APIAddress("api/v1/token/"+token.UID.String())).
+			BasicAuth("test-user", "test-user-password").
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&token)
+
+		if token.RevokedBy != "test-user" || token.RevokedAt == nil {
+			t.Fatalf("UserToken is not revoked: %v %v", token.RevokedBy, token.RevokedAt)
+		}
+	})
+
+	t.Run("Revoked token is not working", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/")).
+			BasicAuth("test-user", secret).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("Create User with password looking like token", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/user/")).
+			JSON(`{"name":"fat-user", "password":"fat_user_password"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("Password looking like token works", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("api/v1/user/me/")).
+			BasicAuth("fat-user", "fat_user_password").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
@@ -0,0 +1,175 @@
+/**
+ * Copyright 2024 Adobe. All rights reserved.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+ * OF ANY KIND, either express or implied. See the License for the specific language
+ * governing permissions and limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/tls"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+
+	"github.com/adobe/aquarium-fish/lib/openapi/types"
+	h "github.com/adobe/aquarium-fish/tests/helper"
+)
+
+// Checks the allocated Resource could get the identity token:
+// * Resource gets the token with default audience through Meta API
+// * Resource can't get the token for not allowed audience
+// * Token is verified with the key from the public JWKS and has the Application claims
+func Test_workload_identity_token(t *testing.T) {
+	t.Parallel()
+	afi := h.NewAquariumFish(t, "node-1", `---
+node_location: test_loc
+
+api_address: 127.0.0.1:0
+proxy_ssh_address: 127.0.0.1:0
+
+workload_identity:
+  issuer: https://fish.example.com
+  audiences:
+    - artifactory
+    - vault
+
+drivers:
+  - name: test`)
+
+	t.Cleanup(func() {
+		afi.Cleanup(t)
+	})
+
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	cli := &http.Client{
+		Timeout:   time.Second * 5,
+		Transport: tr,
+	}
+
+	var label types.Label
+	t.Run("Create Label", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/label/")).
+			JSON(`{"name":"test-label", "version":1, "definitions": [{"driver":"test", "resources":{"cpu":1,"ram":2}}]}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&label)
+
+		if label.UID == uuid.Nil {
+			t.Fatalf("Label UID is incorrect: %v", label.UID)
+		}
+	})
+
+	var app types.Application
+	t.Run("Create Application", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Post(afi.APIAddress("api/v1/application/")).
+			JSON(`{"label_UID":"`+label.UID.String()+`"}`).
+			BasicAuth("admin", afi.AdminToken()).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&app)
+
+		if app.UID == uuid.Nil {
+			t.Fatalf("Application UID is incorrect: %v", app.UID)
+		}
+	})
+
+	t.Run("Application should get ALLOCATED in 10 sec", func(t *testing.T) {
+		h.Retry(&h.Timer{Timeout: 10 * time.Second, Wait: 1 * time.Second}, t, func(r *h.R) {
+			var appState types.ApplicationState
+			apitest.New().
+				EnableNetworking(cli).
+				Get(afi.APIAddress("api/v1/application/"+app.UID.String()+"/state")).
+				BasicAuth("admin", afi.AdminToken()).
+				Expect(r).
+				Status(http.StatusOK).
+				End().
+				JSON(&appState)
+
+			if appState.Status != types.ApplicationStatusALLOCATED {
+				r.Fatalf("Application Status is incorrect: %v", appState.Status)
+			}
+		})
+	})
+
+	var token types.WorkloadToken
+	t.Run("Resource gets the identity token", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/identity/token")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&token)
+
+		if token.Token == "" || token.ExpiresAt.Before(time.Now()) {
+			t.Fatalf("Token is incorrect: %v", token)
+		}
+	})
+
+	t.Run("Resource can't get the token for not allowed audience", func(t *testing.T) {
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress("meta/v1/identity/token")).
+			Query("audience", "aws").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("Token is verified with the public JWKS", func(t *testing.T) {
+		var jwks struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		apitest.New().
+			EnableNetworking(cli).
+			Get(afi.APIAddress(".well-known/jwks.json")).
+			Expect(t).
+			Status(http.StatusOK).
+			End().
+			JSON(&jwks)
+
+		if len(jwks.Keys) != 1 {
+			t.Fatalf("Wrong number of keys in JWKS: %v", jwks)
+		}
+		x, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0]["x"])
+		y, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0]["y"])
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+
+		claims := jwt.MapClaims{}
+		parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodES256.Alg()}}
+		if _, err := parser.ParseWithClaims(token.Token, claims, func(*jwt.Token) (any, error) {
+			return pub, nil
+		}); err != nil {
+			t.Fatalf("Token is not valid: %v", err)
+		}
+		if claims["iss"] != "https://fish.example.com" || claims["aud"] != "artifactory" ||
+			claims["sub"] != app.UID.String() || claims["application_short_id"] != app.ShortId ||
+			claims["owner"] != "admin" || claims["label_name"] != "test-label" {
+			t.Fatalf("Token claims are incorrect: %v", claims)
+		}
+	})
+}